@@ -6,6 +6,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/grik-ai/ricochet-task/pkg/ai"
 	"github.com/grik-ai/ricochet-task/pkg/chain"
 )
 
@@ -72,24 +73,62 @@ type SessionCompleteParams struct {
 
 // AutoSelectModelsParams параметры для автоматического выбора моделей в цепочке
 type AutoSelectModelsParams struct {
-	ChainID string `json:"chain_id"` // ID цепочки
+	ChainID string `json:"chain_id"`          // ID цепочки
+	DryRun  bool   `json:"dry_run,omitempty"` // Только показать предлагаемые модели, не сохраняя цепочку
 }
 
 // AutoSelectModelsResponse ответ на автоматический выбор моделей
 type AutoSelectModelsResponse struct {
 	ChainID string                 `json:"chain_id"`
+	DryRun  bool                   `json:"dry_run,omitempty"`
 	Steps   []AutoSelectedStepInfo `json:"steps"`
+	Skipped []AutoSkippedStepInfo  `json:"skipped,omitempty"`
 	Success bool                   `json:"success"`
 	Message string                 `json:"message,omitempty"`
 }
 
-// AutoSelectedStepInfo информация о выбранной модели для шага
+// AutoSelectedStepInfo информация о выбранной модели для шага. Поля Previous*
+// содержат модель шага до выбора, Changed - отличается ли от нее выбранная
 type AutoSelectedStepInfo struct {
 	StepID           string `json:"step_id"`
 	StepName         string `json:"step_name"`
 	SelectedRole     string `json:"selected_role"`
 	SelectedModel    string `json:"selected_model"`
 	SelectedProvider string `json:"selected_provider"`
+	PreviousRole     string `json:"previous_role,omitempty"`
+	PreviousModel    string `json:"previous_model,omitempty"`
+	PreviousProvider string `json:"previous_provider,omitempty"`
+	Changed          bool   `json:"changed"`
+}
+
+// AutoSkippedStepInfo шаг, для которого модель не выбрана, и причина
+type AutoSkippedStepInfo struct {
+	StepID   string `json:"step_id"`
+	StepName string `json:"step_name"`
+	Role     string `json:"role"`
+	Reason   string `json:"reason"`
+}
+
+// SuggestPromptParams параметры для предложения промпта шага. С step_index
+// роль и описание берутся из существующего шага, model_role и description
+// их переопределяют; без step_index промпт предлагается для нового шага
+type SuggestPromptParams struct {
+	SessionID   string `json:"session_id"`
+	StepIndex   *int   `json:"step_index,omitempty"`
+	ModelRole   string `json:"model_role,omitempty"`
+	Description string `json:"description,omitempty"`
+	Apply       bool   `json:"apply,omitempty"` // Записать промпт в шаг step_index
+}
+
+// SuggestPromptResponse предложенный промпт шага
+type SuggestPromptResponse struct {
+	SessionID string `json:"session_id"`
+	StepIndex *int   `json:"step_index,omitempty"`
+	ModelRole string `json:"model_role"`
+	Prompt    string `json:"prompt"`
+	Source    string `json:"source"` // "ai" или "template", если AI недоступен
+	Applied   bool   `json:"applied"`
+	Message   string `json:"message"`
 }
 
 // activeSessions хранит активные сессии конструктора
@@ -162,6 +201,10 @@ func HandleChainBuilderAddStep(params json.RawMessage) (interface{}, error) {
 		return nil, fmt.Errorf("session_id является обязательным параметром")
 	}
 
+	if err := normalizeOutputSchema(stepParams.Parameters); err != nil {
+		return nil, err
+	}
+
 	// Получаем сессию
 	activeSessions.mutex.Lock()
 	defer activeSessions.mutex.Unlock()
@@ -238,6 +281,10 @@ func HandleChainBuilderEditStep(params json.RawMessage) (interface{}, error) {
 		return nil, fmt.Errorf("session_id является обязательным параметром")
 	}
 
+	if err := normalizeOutputSchema(stepParams.Parameters); err != nil {
+		return nil, err
+	}
+
 	// Получаем сессию
 	activeSessions.mutex.Lock()
 	defer activeSessions.mutex.Unlock()
@@ -476,8 +523,9 @@ func HandleAutoSelectModels(params json.RawMessage) (interface{}, error) {
 		return nil, err
 	}
 
-	// Информация о выбранных моделях
+	// Информация о выбранных и пропущенных шагах
 	selectedModels := make([]AutoSelectedStepInfo, 0, len(chain.Steps))
+	var skipped []AutoSkippedStepInfo
 
 	// Для каждого шага цепочки выбираем подходящую модель
 	for _, step := range chain.Steps {
@@ -487,39 +535,167 @@ func HandleAutoSelectModels(params json.RawMessage) (interface{}, error) {
 		// Получаем модель для этой роли
 		role := mm.GetModelForRole(roleID)
 
-		// Если модель не найдена, пропускаем шаг
+		// Если модель не найдена, сообщаем о пропуске шага
 		if role.ModelID == "" || role.Provider == "" {
+			skipped = append(skipped, AutoSkippedStepInfo{
+				StepID:   step.ID,
+				StepName: step.Name,
+				Role:     roleID,
+				Reason:   fmt.Sprintf("no model is configured for role %s", roleID),
+			})
 			continue
 		}
 
-		// Обновляем шаг с выбранной моделью
-		err = updateStepModel(p.ChainID, step.ID, role.Provider, role.ModelID, roleID)
-		if err != nil {
-			// Если не удалось обновить шаг, продолжаем с другими
-			continue
-		}
-
-		// Добавляем информацию о выбранной модели
-		selectedModels = append(selectedModels, AutoSelectedStepInfo{
+		info := AutoSelectedStepInfo{
 			StepID:           step.ID,
 			StepName:         step.Name,
 			SelectedRole:     roleID,
 			SelectedModel:    role.ModelID,
 			SelectedProvider: role.Provider,
-		})
+			PreviousRole:     step.RoleID,
+			PreviousModel:    step.ModelID,
+			PreviousProvider: step.ModelProvider,
+		}
+		info.Changed = info.SelectedModel != info.PreviousModel || info.SelectedProvider != info.PreviousProvider ||
+			info.SelectedRole != info.PreviousRole
+
+		// В режиме dry_run цепочка не изменяется
+		if !p.DryRun && info.Changed {
+			if err := updateStepModel(p.ChainID, step.ID, role.Provider, role.ModelID, roleID); err != nil {
+				skipped = append(skipped, AutoSkippedStepInfo{
+					StepID:   step.ID,
+					StepName: step.Name,
+					Role:     roleID,
+					Reason:   fmt.Sprintf("failed to update the step: %v", err),
+				})
+				continue
+			}
+		}
+
+		selectedModels = append(selectedModels, info)
 	}
 
 	// Формируем ответ
 	response := AutoSelectModelsResponse{
 		ChainID: p.ChainID,
+		DryRun:  p.DryRun,
 		Steps:   selectedModels,
+		Skipped: skipped,
 		Success: len(selectedModels) > 0,
 	}
 
-	if len(selectedModels) > 0 {
-		response.Message = fmt.Sprintf("Successfully selected models for %d steps", len(selectedModels))
-	} else {
+	changed := 0
+	for _, info := range selectedModels {
+		if info.Changed {
+			changed++
+		}
+	}
+
+	switch {
+	case len(selectedModels) == 0:
 		response.Message = "No models were selected for the chain steps"
+	case p.DryRun:
+		response.Message = fmt.Sprintf("Dry run: would change the models of %d of %d steps, nothing was saved", changed, len(chain.Steps))
+	default:
+		response.Message = fmt.Sprintf("Successfully selected models for %d steps, %d changed", len(selectedModels), changed)
+	}
+	if len(skipped) > 0 {
+		response.Message += fmt.Sprintf(", %d skipped", len(skipped))
+	}
+
+	return response, nil
+}
+
+// HandleChainBuilderSuggestPrompt предлагает промпт для шага по его роли,
+// описанию и назначению цепочки. Промпт можно принять с apply или передать
+// отредактированным в chain_builder_edit_step
+func HandleChainBuilderSuggestPrompt(params json.RawMessage) (interface{}, error) {
+	var suggestParams SuggestPromptParams
+	if err := json.Unmarshal(params, &suggestParams); err != nil {
+		return nil, fmt.Errorf("неверные параметры для предложения промпта: %v", err)
+	}
+
+	if suggestParams.SessionID == "" {
+		return nil, fmt.Errorf("session_id является обязательным параметром")
+	}
+	if suggestParams.Apply && suggestParams.StepIndex == nil {
+		return nil, fmt.Errorf("apply требует step_index существующего шага")
+	}
+
+	// Собираем контекст шага под блокировкой, запрос к AI идет без нее
+	activeSessions.mutex.RLock()
+	session, exists := activeSessions.sessions[suggestParams.SessionID]
+	if !exists {
+		activeSessions.mutex.RUnlock()
+		return nil, fmt.Errorf("сессия с ID %s не найдена", suggestParams.SessionID)
+	}
+
+	chainName, chainDesc := session.ChainName, session.ChainDesc
+	role, description := suggestParams.ModelRole, suggestParams.Description
+	var otherSteps []string
+	for i, step := range session.Steps {
+		if suggestParams.StepIndex != nil && i == *suggestParams.StepIndex {
+			if role == "" {
+				role = step.ModelRole
+			}
+			if description == "" {
+				description = step.Description
+			}
+			continue
+		}
+		otherSteps = append(otherSteps, fmt.Sprintf("%s: %s", step.ModelRole, step.Description))
+	}
+	stepCount := len(session.Steps)
+	activeSessions.mutex.RUnlock()
+
+	if suggestParams.StepIndex != nil && (*suggestParams.StepIndex < 0 || *suggestParams.StepIndex >= stepCount) {
+		return nil, fmt.Errorf("шаг с индексом %d не существует", *suggestParams.StepIndex)
+	}
+	if role == "" {
+		return nil, fmt.Errorf("model_role является обязательным параметром для нового шага")
+	}
+
+	response := SuggestPromptResponse{
+		SessionID: suggestParams.SessionID,
+		StepIndex: suggestParams.StepIndex,
+		ModelRole: role,
+		Source:    "ai",
+		Message:   "Промпт предложен AI",
+	}
+
+	// Без AI промпт собирается из шаблона роли
+	chains, err := GetAIChains()
+	if err == nil && !chains.Offline() {
+		response.Prompt, err = chains.SuggestStepPrompt(chainName, chainDesc, role, description, otherSteps)
+	} else if err == nil {
+		err = fmt.Errorf("нет рабочих API-ключей")
+	}
+	if err != nil {
+		response.Prompt, _ = ai.NewMockAIChains().SuggestStepPrompt(chainName, chainDesc, role, description, otherSteps)
+		response.Source = "template"
+		response.Message = fmt.Sprintf("AI недоступен (%v), промпт собран из шаблона роли", err)
+	}
+
+	if suggestParams.Apply {
+		activeSessions.mutex.Lock()
+		defer activeSessions.mutex.Unlock()
+
+		// Сессия могла измениться, пока ждали ответа AI
+		session, exists = activeSessions.sessions[suggestParams.SessionID]
+		if !exists {
+			return nil, fmt.Errorf("сессия с ID %s не найдена", suggestParams.SessionID)
+		}
+		if session.Status != "editing" {
+			return nil, fmt.Errorf("невозможно изменить промпт: сессия уже %s", session.Status)
+		}
+		if *suggestParams.StepIndex >= len(session.Steps) {
+			return nil, fmt.Errorf("шаг с индексом %d не существует", *suggestParams.StepIndex)
+		}
+
+		session.Steps[*suggestParams.StepIndex].Prompt = response.Prompt
+		session.UpdatedAt = time.Now()
+		response.Applied = true
+		response.Message += fmt.Sprintf(" и записан в шаг %d", *suggestParams.StepIndex)
 	}
 
 	return response, nil
@@ -586,6 +762,7 @@ func RegisterChainBuilderCommands(server *MCPServer) {
 	server.RegisterCommand("chain_builder_remove_step", HandleChainBuilderRemoveStep)
 	server.RegisterCommand("chain_builder_get_session", HandleChainBuilderGetSession)
 	server.RegisterCommand("chain_builder_complete", HandleChainBuilderComplete)
+	server.RegisterCommand("chain_builder_suggest_prompt", HandleChainBuilderSuggestPrompt)
 	server.RegisterCommand("auto_select_models", HandleAutoSelectModels)
 }
 
@@ -655,6 +832,22 @@ func loadTemplateIntoSession(session *ChainBuilderSession, templateID string) er
 	return nil
 }
 
+// normalizeOutputSchema проверяет JSON-схему результата в параметрах шага
+// (output_schema) и заменяет ее разобранной схемой, если она передана строкой
+func normalizeOutputSchema(parameters map[string]interface{}) error {
+	value, ok := parameters[chain.OutputSchemaParameter]
+	if !ok || value == nil {
+		return nil
+	}
+
+	schema, err := chain.ParseOutputSchema(value)
+	if err != nil {
+		return fmt.Errorf("неверный параметр %s: %v", chain.OutputSchemaParameter, err)
+	}
+	parameters[chain.OutputSchemaParameter] = schema
+	return nil
+}
+
 // createChainFromSession создает цепочку на основе сессии конструктора
 func createChainFromSession(session *ChainBuilderSession) (string, error) {
 	// TODO: Создать цепочку и сохранить ее в хранилище
@@ -680,6 +873,11 @@ func createChainFromSession(session *ChainBuilderSession) (string, error) {
 			}
 		}
 
+		// Схема результата уже проверена при добавлении шага
+		if schema, ok := step.Parameters[chain.OutputSchemaParameter].(map[string]interface{}); ok {
+			model.OutputSchema = schema
+		}
+
 		// Другие параметры можно добавить здесь
 
 		models = append(models, model)
@@ -2,9 +2,11 @@ package mcp
 
 import (
 	"fmt"
+	"log"
 	"sync"
 	"time"
 
+	"github.com/grik-ai/ricochet-task/pkg/ai"
 	"github.com/grik-ai/ricochet-task/pkg/chain"
 	"github.com/grik-ai/ricochet-task/pkg/orchestrator"
 )
@@ -13,6 +15,8 @@ import (
 type GlobalServices struct {
 	orchestrator orchestrator.Orchestrator
 	chainStore   chain.Store
+	aiKeys       *ai.UserAPIKeys
+	aiChains     *ai.AIChains
 	mutex        sync.RWMutex
 }
 
@@ -32,6 +36,15 @@ func SetChainStore(store chain.Store) {
 	globalServices.chainStore = store
 }
 
+// SetAIAPIKeys устанавливает API-ключи для AI цепочек. Сами цепочки создаются
+// при первом обращении, так как при создании проверяют ключи по сети
+func SetAIAPIKeys(keys *ai.UserAPIKeys) {
+	globalServices.mutex.Lock()
+	defer globalServices.mutex.Unlock()
+	globalServices.aiKeys = keys
+	globalServices.aiChains = nil
+}
+
 // GetOrchestratorService возвращает глобальный сервис оркестратора
 func GetOrchestratorService() (orchestrator.Orchestrator, error) {
 	globalServices.mutex.RLock()
@@ -56,6 +69,39 @@ func GetChainStore() (chain.Store, error) {
 	return globalServices.chainStore, nil
 }
 
+// GetAIChains возвращает AI цепочки, созданные с ключами из SetAIAPIKeys.
+// Без рабочих ключей цепочки работают офлайн, см. AIChains.Offline
+func GetAIChains() (*ai.AIChains, error) {
+	globalServices.mutex.Lock()
+	defer globalServices.mutex.Unlock()
+
+	if globalServices.aiChains == nil {
+		if globalServices.aiKeys == nil {
+			return nil, fmt.Errorf("AI chains not initialized")
+		}
+		globalServices.aiChains = ai.NewAIChains("", "", "", globalServices.aiKeys, aiLogger{})
+	}
+
+	return globalServices.aiChains, nil
+}
+
+// aiLogger пишет сообщения AI цепочек в стандартный лог
+type aiLogger struct{}
+
+func (aiLogger) Info(msg string, args ...interface{}) {
+	log.Println(append([]interface{}{msg}, args...)...)
+}
+
+func (aiLogger) Warn(msg string, args ...interface{}) {
+	log.Println(append([]interface{}{msg}, args...)...)
+}
+
+func (aiLogger) Debug(msg string, args ...interface{}) {}
+
+func (aiLogger) Error(msg string, err error, args ...interface{}) {
+	log.Println(append([]interface{}{msg, err}, args...)...)
+}
+
 // ChainInfo содержит информацию о цепочке
 type ChainInfo struct {
 	ID          string `json:"id"`
@@ -94,4 +140,4 @@ type CheckpointSummaryInfo struct {
 	CreatedAt   time.Time              `json:"created_at"`
 	ContentSize int                    `json:"content_size"`
 	Metadata    map[string]interface{} `json:"metadata,omitempty"`
-}
\ No newline at end of file
+}
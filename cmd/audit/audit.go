@@ -0,0 +1,151 @@
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	providerscmd "github.com/grik-ai/ricochet-task/cmd/providers"
+	"github.com/grik-ai/ricochet-task/internal/config"
+	"github.com/grik-ai/ricochet-task/pkg/mcp"
+	"github.com/grik-ai/ricochet-task/pkg/providers"
+)
+
+// AuditCmd represents the audit command
+var AuditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Show audit logs",
+	Long:  `Show the audit logs ricochet keeps of actions taken on behalf of others.`,
+}
+
+var mcpCmd = &cobra.Command{
+	Use:   "mcp",
+	Short: "Show the tool calls made through the MCP server",
+	Long: `Show the tool calls the MCP server recorded in its audit log, oldest first:
+the tool, its arguments with credentials redacted, the caller and the outcome.
+The caller is the name the client sent in the X-MCP-Client header and the
+address the request came from.
+
+The log is kept in mcp_audit.jsonl of the profile directory and rotated once
+it grows past maxSizeMB of the mcp.audit config section; rotated logs are
+searched too.
+
+Examples:
+  ricochet audit mcp
+  ricochet audit mcp --since 24h --errors
+  ricochet audit mcp --tool 'task_*' --caller triage-agent
+  ricochet audit mcp --since 2024-06-01 --limit 0 -o json`,
+	Args: cobra.NoArgs,
+	RunE: runAuditMCP,
+}
+
+func init() {
+	AuditCmd.AddCommand(mcpCmd)
+
+	mcpCmd.Flags().String("since", "", "Only calls since this time: a duration like 24h, YYYY-MM-DD or RFC3339")
+	mcpCmd.Flags().String("tool", "", "Only calls of this tool (name or pattern like ai_*)")
+	mcpCmd.Flags().String("caller", "", "Only calls whose caller name or address contains this")
+	mcpCmd.Flags().Bool("errors", false, "Only failed calls")
+	mcpCmd.Flags().Int("limit", 50, "Show only the newest calls (0 for all)")
+	mcpCmd.Flags().StringP("output", "o", "", "Output format (table, json, yaml); defaults to $RICOCHET_OUTPUT or defaultOutputFormat from the config")
+}
+
+func runAuditMCP(cmd *cobra.Command, args []string) error {
+	filter := mcp.AuditFilter{}
+	filter.Tool, _ = cmd.Flags().GetString("tool")
+	filter.Caller, _ = cmd.Flags().GetString("caller")
+	filter.ErrorsOnly, _ = cmd.Flags().GetBool("errors")
+	filter.Limit, _ = cmd.Flags().GetInt("limit")
+
+	if since, _ := cmd.Flags().GetString("since"); since != "" {
+		start, err := parseSince(since, time.Now())
+		if err != nil {
+			return err
+		}
+		filter.Since = start
+	}
+
+	log := mcp.NewAuditLog(config.ProfilePath(mcp.AuditLogFile), 0, 0, nil)
+	entries, err := log.Entries(filter)
+	if err != nil {
+		return err
+	}
+
+	format := providerscmd.OutputFormat(cmd)
+
+	switch format {
+	case providers.OutputFormatJSON:
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(entries)
+	case providers.OutputFormatYAML:
+		encoder := yaml.NewEncoder(os.Stdout)
+		defer encoder.Close()
+		return encoder.Encode(entries)
+	}
+
+	if len(entries) == 0 {
+		fmt.Println("No tool calls recorded")
+		return nil
+	}
+
+	fmt.Printf("%-19s %-24s %-30s %-17s %9s  %s\n", "TIME", "TOOL", "CALLER", "OUTCOME", "DURATION", "ARGUMENTS")
+	fmt.Printf("%-19s %-24s %-30s %-17s %9s  %s\n", "----", "----", "------", "-------", "--------", "---------")
+	for _, entry := range entries {
+		outcome := "✅ ok"
+		if entry.Outcome == mcp.AuditOutcomeError {
+			outcome = "❌ " + string(entry.ErrorCode)
+		}
+		fmt.Printf("%-19s %-24s %-30s %-17s %9s  %s\n",
+			entry.Time.Local().Format("2006-01-02 15:04:05"),
+			entry.Tool,
+			truncate(entry.Caller.String(), 30),
+			outcome,
+			(time.Duration(entry.DurationMs) * time.Millisecond).String(),
+			truncate(formatArguments(entry.Arguments), 60),
+		)
+		if entry.Error != "" {
+			fmt.Printf("%-19s %s\n", "", truncate(entry.Error, 120))
+		}
+	}
+	fmt.Printf("\n%d tool calls\n", len(entries))
+	return nil
+}
+
+// parseSince parses the start of the period to show: a duration back from now,
+// a date or an RFC3339 time
+func parseSince(value string, now time.Time) (time.Time, error) {
+	if duration, err := time.ParseDuration(value); err == nil && duration >= 0 {
+		return now.Add(-duration), nil
+	}
+	if start, err := time.ParseInLocation("2006-01-02", value, now.Location()); err == nil {
+		return start, nil
+	}
+	if start, err := time.Parse(time.RFC3339, value); err == nil {
+		return start, nil
+	}
+	return time.Time{}, providers.NewValidationError(fmt.Sprintf("invalid --since %q, use a duration like 24h, YYYY-MM-DD or RFC3339", value), nil)
+}
+
+func formatArguments(arguments map[string]interface{}) string {
+	if len(arguments) == 0 {
+		return ""
+	}
+	data, err := json.Marshal(arguments)
+	if err != nil {
+		return fmt.Sprint(arguments)
+	}
+	return string(data)
+}
+
+func truncate(text string, length int) string {
+	runes := []rune(text)
+	if len(runes) <= length {
+		return text
+	}
+	return string(runes[:length-3]) + "..."
+}
@@ -1,7 +1,6 @@
 package board
 
 import (
-	"context"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -12,6 +11,7 @@ import (
 	"github.com/spf13/viper"
 	"github.com/sirupsen/logrus"
 
+	providerscmd "github.com/grik-ai/ricochet-task/cmd/providers"
 	"github.com/grik-ai/ricochet-task/pkg/providers"
 )
 
@@ -108,6 +108,7 @@ func init() {
 	planCmd.Flags().Bool("auto-start", false, "Automatically start execution after planning")
 }
 
+// initializeBoard takes the shared provider registry, initializing it on first use
 func initializeBoard() error {
 	// Setup logger
 	logger = logrus.New()
@@ -117,24 +118,9 @@ func initializeBoard() error {
 		logger.SetLevel(logrus.InfoLevel)
 	}
 
-	// Load configuration
-	config := loadMultiProviderConfig()
-	if config == nil {
-		return fmt.Errorf("failed to load provider configuration")
-	}
-
-	// Initialize provider registry
-	registry = providers.NewProviderRegistry(config, logger)
-
-	// Initialize providers
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel()
-
-	if err := registry.Initialize(ctx); err != nil {
-		return fmt.Errorf("failed to initialize providers: %w", err)
-	}
-
-	return nil
+	var err error
+	registry, err = providerscmd.Registry()
+	return err
 }
 
 func runBoardInteractive(cmd *cobra.Command, args []string) error {
@@ -389,7 +375,35 @@ func getAllBoards() ([]*BoardInfo, error) {
 		},
 	}
 
-	return boards, nil
+	return boardsWithCapability(boards), nil
+}
+
+// boardsWithCapability skips boards of providers that don't support boards
+func boardsWithCapability(boards []*BoardInfo) []*BoardInfo {
+	names := make([]string, len(boards))
+	for i, board := range boards {
+		names[i] = board.ProviderName
+	}
+
+	_, unsupported := registry.SplitByCapability(names, providers.CapabilityBoards)
+	if len(unsupported) == 0 {
+		return boards
+	}
+
+	skip := make(map[string]bool, len(unsupported))
+	for _, name := range unsupported {
+		skip[name] = true
+	}
+
+	var supported []*BoardInfo
+	for _, board := range boards {
+		if skip[board.ProviderName] {
+			logger.Debugf("Skipping board %s: provider %s does not support boards", board.ID, board.ProviderName)
+			continue
+		}
+		supported = append(supported, board)
+	}
+	return supported
 }
 
 func saveWorkingContext(board *BoardInfo) error {
@@ -509,29 +523,3 @@ func executePlan(context *WorkingContext, plan *ProjectPlan, autoStart bool) err
 	
 	return nil
 }
-
-// Helper functions
-
-func loadMultiProviderConfig() *providers.MultiProviderConfig {
-	config := providers.DefaultMultiProviderConfig()
-
-	// Try to load from config file
-	configFile := viper.GetString("config")
-	if configFile == "" {
-		configFile = "ricochet.yaml"
-	}
-
-	if _, err := os.Stat(configFile); err == nil {
-		viper.SetConfigFile(configFile)
-		if err := viper.ReadInConfig(); err == nil {
-			viper.Unmarshal(config)
-			logger.Infof("Loaded configuration from %s", configFile)
-		} else {
-			logger.Warnf("Failed to read config file %s: %v", configFile, err)
-		}
-	} else {
-		logger.Infof("No config file found at %s, using defaults", configFile)
-	}
-
-	return config
-}
\ No newline at end of file
@@ -0,0 +1,67 @@
+package cache
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	providerscmd "github.com/grik-ai/ricochet-task/cmd/providers"
+	"github.com/grik-ai/ricochet-task/internal/config"
+	"github.com/grik-ai/ricochet-task/pkg/providers"
+)
+
+// CacheCmd represents the cache command
+var CacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Manage cached provider metadata",
+	Long: `Manage the cache of provider metadata such as users, projects and statuses.
+
+Metadata is cached between runs so that creating and updating tasks doesn't
+re-fetch it every time. Entries expire after the provider's metadataTtl
+(1h by default) and are dropped automatically when the provider's
+configuration changes.`,
+}
+
+var clearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Clear cached provider metadata",
+	Long: `Remove cached metadata for all providers or a single provider.
+
+Examples:
+  ricochet cache clear
+  ricochet cache clear --provider youtrack-prod`,
+	RunE: runClearCache,
+}
+
+func init() {
+	CacheCmd.AddCommand(clearCmd)
+
+	clearCmd.Flags().String("provider", "", "Only clear metadata of this provider")
+	clearCmd.RegisterFlagCompletionFunc("provider", providerscmd.CompleteProviderNames)
+}
+
+func runClearCache(cmd *cobra.Command, args []string) error {
+	providerName, _ := cmd.Flags().GetString("provider")
+
+	metadata, err := providers.NewMetadataCache(config.ProfilePath(providers.MetadataCacheFile), nil)
+	if err != nil {
+		return fmt.Errorf("failed to open metadata cache: %w", err)
+	}
+
+	var removed int
+	if providerName != "" {
+		removed, err = metadata.InvalidateProvider(providerName)
+	} else {
+		removed, err = metadata.Clear()
+	}
+	if err != nil {
+		return fmt.Errorf("failed to clear metadata cache: %w", err)
+	}
+
+	if providerName != "" {
+		fmt.Printf("✅ Removed %d cached entries for provider '%s'\n", removed, providerName)
+	} else {
+		fmt.Printf("✅ Removed %d cached entries\n", removed)
+	}
+	return nil
+}
@@ -5,6 +5,7 @@ import (
 	"os"
 	"strings"
 
+	providerscmd "github.com/grik-ai/ricochet-task/cmd/providers"
 	"github.com/grik-ai/ricochet-task/pkg/ai"
 	"github.com/grik-ai/ricochet-task/pkg/context"
 	"github.com/grik-ai/ricochet-task/pkg/providers"
@@ -60,6 +61,7 @@ func init() {
 	ContextCmd.PersistentFlags().StringVar(&boardID, "board-id", "", "ID доски")
 	ContextCmd.PersistentFlags().StringVar(&projectID, "project-id", "", "ID проекта")
 	ContextCmd.PersistentFlags().StringVar(&providerName, "provider", "", "Имя провайдера")
+	ContextCmd.RegisterFlagCompletionFunc("provider", providerscmd.CompleteProviderNames)
 	ContextCmd.PersistentFlags().StringVar(&defaultAssignee, "assignee", "", "Исполнитель по умолчанию")
 	ContextCmd.PersistentFlags().StringVar(&defaultPriority, "priority", "medium", "Приоритет по умолчанию")
 	ContextCmd.PersistentFlags().StringVar(&projectType, "type", "", "Тип проекта")
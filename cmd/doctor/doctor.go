@@ -0,0 +1,175 @@
+package doctor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	providerscmd "github.com/grik-ai/ricochet-task/cmd/providers"
+	"github.com/grik-ai/ricochet-task/internal/config"
+	"github.com/grik-ai/ricochet-task/pkg/chain"
+	"github.com/grik-ai/ricochet-task/pkg/doctor"
+	"github.com/grik-ai/ricochet-task/pkg/key"
+	"github.com/grik-ai/ricochet-task/pkg/providers"
+)
+
+// DoctorCmd represents the doctor command
+var DoctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check the ricochet setup",
+	Long: `Check that ricochet is set up correctly and print a checklist with a
+remediation hint for every problem found:
+
+  - the provider configuration file exists and is valid
+  - every enabled provider is reachable and accepts its credentials
+  - API keys are stored for the AI providers used by configured chains
+  - the config and cache directories are writable
+  - this build ships a plugin for every configured provider type
+
+The command exits with a non-zero code if any check fails; warnings
+don't affect the exit code.
+
+Examples:
+  ricochet doctor
+  ricochet doctor --timeout 30s
+  ricochet doctor --output json`,
+	RunE: runDoctor,
+}
+
+func init() {
+	DoctorCmd.Flags().Duration("timeout", 10*time.Second, "Timeout of each provider check")
+	DoctorCmd.Flags().StringP("output", "o", "", "Output format (table, json, yaml); defaults to $RICOCHET_OUTPUT or defaultOutputFormat from the config")
+}
+
+func runDoctor(cmd *cobra.Command, args []string) error {
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+
+	report := RunChecks(cmd.Context(), timeout)
+	if err := outputReport(cmd, report); err != nil {
+		return err
+	}
+
+	if report.Failed() {
+		cmd.SilenceUsage = true
+		return fmt.Errorf("%d of %d checks failed", report.Count(doctor.StatusFail), len(report.Checks))
+	}
+	return nil
+}
+
+// RunChecks runs all doctor checks. timeout bounds each provider check.
+func RunChecks(ctx context.Context, timeout time.Duration) *doctor.Report {
+	report := &doctor.Report{}
+
+	configFile, providerConfig, loadErr := providerscmd.LoadConfigFile()
+	report.Add(doctor.CheckConfig(configFile, providerConfig, loadErr))
+
+	report.Add(doctor.CheckProviders(ctx, providerConfig, func(ctx context.Context, config *providers.ProviderConfig) error {
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+		return providers.ProbeProvider(ctx, config)
+	})...)
+
+	appConfig, appCheck := loadAppConfig()
+	if appCheck != nil {
+		report.Add(*appCheck)
+	}
+	report.Add(checkChainKeys(appConfig.ConfigDir))
+	report.Add(doctor.CheckWritable("Config directory", appConfig.ConfigDir))
+	report.Add(doctor.CheckWritable("Cache directory", filepath.Dir(config.ProfilePath(providers.MetadataCacheFile))))
+	report.Add(doctor.CheckVersion(providerConfig))
+	return report
+}
+
+// loadAppConfig loads the application config holding the config directory.
+// A config that can't be read is reported as a failed check.
+func loadAppConfig() (config.Config, *doctor.Check) {
+	configPath, err := config.GetConfigPath()
+	if err != nil {
+		return config.DefaultConfig(), &doctor.Check{
+			Name:    "Application config",
+			Status:  doctor.StatusFail,
+			Message: err.Error(),
+			Hint:    "Set the HOME environment variable",
+		}
+	}
+
+	appConfig, err := config.LoadConfig(configPath)
+	if err != nil {
+		return appConfig, &doctor.Check{
+			Name:    "Application config",
+			Status:  doctor.StatusFail,
+			Message: err.Error(),
+			Hint:    fmt.Sprintf("Fix or remove %s", configPath),
+		}
+	}
+	return appConfig, nil
+}
+
+func checkChainKeys(configDir string) doctor.Check {
+	failed := func(err error) doctor.Check {
+		return doctor.Check{
+			Name:    "AI keys",
+			Status:  doctor.StatusFail,
+			Message: err.Error(),
+			Hint:    fmt.Sprintf("Check that the files in %s are readable and valid JSON", configDir),
+		}
+	}
+
+	chainStore, err := chain.NewFileChainStore(configDir)
+	if err != nil {
+		return failed(err)
+	}
+	chains, err := chainStore.List()
+	if err != nil {
+		return failed(err)
+	}
+
+	keyStore, err := key.NewFileKeyStore(configDir)
+	if err != nil {
+		return failed(err)
+	}
+	keys, err := keyStore.List()
+	if err != nil {
+		return failed(err)
+	}
+
+	return doctor.CheckChainKeys(chains, keys)
+}
+
+var statusIcons = map[doctor.Status]string{
+	doctor.StatusPass: "✅",
+	doctor.StatusWarn: "⚠️ ",
+	doctor.StatusFail: "❌",
+}
+
+func outputReport(cmd *cobra.Command, report *doctor.Report) error {
+	format := providerscmd.OutputFormat(cmd)
+
+	switch format {
+	case providers.OutputFormatJSON:
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(report)
+	case providers.OutputFormatYAML:
+		encoder := yaml.NewEncoder(os.Stdout)
+		defer encoder.Close()
+		return encoder.Encode(report)
+	}
+
+	for _, check := range report.Checks {
+		fmt.Printf("%s %s: %s\n", statusIcons[check.Status], check.Name, check.Message)
+		if check.Hint != "" {
+			fmt.Printf("   💡 %s\n", check.Hint)
+		}
+	}
+
+	fmt.Printf("\n%d passed, %d warnings, %d failed\n",
+		report.Count(doctor.StatusPass), report.Count(doctor.StatusWarn), report.Count(doctor.StatusFail))
+	return nil
+}
@@ -2,6 +2,7 @@ package mcp
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 	"os/signal"
@@ -11,9 +12,14 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 	"github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
 
+	doctorcmd "github.com/grik-ai/ricochet-task/cmd/doctor"
+	providerscmd "github.com/grik-ai/ricochet-task/cmd/providers"
+	appconfig "github.com/grik-ai/ricochet-task/internal/config"
 	"github.com/grik-ai/ricochet-task/pkg/mcp"
 	"github.com/grik-ai/ricochet-task/pkg/providers"
+	"github.com/grik-ai/ricochet-task/pkg/workflow"
 )
 
 var (
@@ -48,10 +54,33 @@ Supports both WebSocket and HTTP modes:
 - WebSocket mode: Full MCP protocol support for real-time communication
 - HTTP mode: REST API endpoints for tool listing and execution
 
+Each tool call is cancelled when the client disconnects or the tool's timeout
+passes, aborting the provider and AI requests in flight. --timeout bounds the
+provider tools, --ai-timeout the ai_* tools and --tool-timeout sets the
+timeout of single tools; 0 means no limit.
+
+The mcp section of the configuration limits the tools the server exposes:
+enabledTools lists the only tools to expose, disabledTools the tools to hide.
+Entries are tool names or patterns like ai_*. --enable-tools and
+--disable-tools replace the lists of the configuration. Calls of a hidden tool
+fail with the error code tool_disabled.
+
+Every tool call is recorded in the audit log with its arguments (credentials
+redacted), the caller and the outcome; see 'ricochet audit mcp'. Clients name
+themselves in the X-MCP-Client header.
+
+With --require-healthy the server runs the checks of 'ricochet doctor' first
+and refuses to start if any fails, e.g. when a provider can't be reached. Add
+--allow-degraded to start anyway: the failures are logged and /health reports
+the server as degraded.
+
 Examples:
   ricochet mcp start --port 8080
   ricochet mcp start --http-only --host 0.0.0.0 --port 3001
-  ricochet mcp start --websocket --debug`,
+  ricochet mcp start --websocket --debug
+  ricochet mcp start --ai-timeout 5m --tool-timeout ai_create_project_plan=10m
+  ricochet mcp start --require-healthy --health-timeout 30s
+  ricochet mcp start --disable-tools providers_add,task_update_universal,'ai_execute_*'`,
 	RunE: runMCPServer,
 }
 
@@ -66,7 +95,8 @@ the MCP server is running.
 Examples:
   ricochet mcp tools
   ricochet mcp tools --output json
-  ricochet mcp tools --verbose`,
+  ricochet mcp tools --verbose
+  ricochet mcp tools --all`,
 	RunE: runListTools,
 }
 
@@ -105,15 +135,24 @@ func init() {
 	// Server-specific flags
 	startCmd.Flags().Bool("websocket", true, "Enable WebSocket support")
 	startCmd.Flags().Bool("http-only", false, "HTTP-only mode (disables WebSocket)")
-	startCmd.Flags().Duration("timeout", 30*time.Second, "Request timeout")
+	startCmd.Flags().Duration("timeout", mcp.DefaultToolTimeout, "Timeout of the tools that only call providers (0 for no limit)")
+	startCmd.Flags().Duration("ai-timeout", mcp.DefaultAIToolTimeout, "Timeout of the ai_* tools (0 for no limit)")
+	startCmd.Flags().StringToString("tool-timeout", nil, "Timeouts of single tools, e.g. ai_create_project_plan=10m")
+	startCmd.Flags().StringSlice("enable-tools", nil, "Only expose these tools (names or patterns like ai_*), replacing enabledTools of the config")
+	startCmd.Flags().StringSlice("disable-tools", nil, "Hide these tools (names or patterns like ai_*), replacing disabledTools of the config")
+	startCmd.Flags().Bool("require-healthy", false, "Run the doctor checks before starting and refuse to start if any fails")
+	startCmd.Flags().Bool("allow-degraded", false, "With --require-healthy, start despite failed checks and report the server as degraded")
+	startCmd.Flags().Duration("health-timeout", 10*time.Second, "Timeout of the startup checks")
 	startCmd.Flags().Int("max-connections", 100, "Maximum concurrent connections")
 	startCmd.Flags().Bool("cors", true, "Enable CORS support")
 
 	// Tools command flags
-	toolsCmd.Flags().StringP("output", "o", "table", "Output format: table, json, yaml")
+	toolsCmd.Flags().Bool("all", false, "Also list the tools the configuration disables")
+	toolsCmd.Flags().StringP("output", "o", "table", "Output format: table, json, yaml (defaults to $RICOCHET_OUTPUT or defaultOutputFormat from the config)")
 
 	// Validate command flags
 	validateCmd.Flags().String("provider", "", "Validate specific provider only")
+	validateCmd.RegisterFlagCompletionFunc("provider", providerscmd.CompleteProviderNames)
 	validateCmd.Flags().Bool("fix", false, "Attempt to fix configuration issues")
 }
 
@@ -126,21 +165,12 @@ func initializeMCP() error {
 		logger.SetLevel(logrus.InfoLevel)
 	}
 
-	// Load configuration
-	config := loadMultiProviderConfig()
-	if config == nil {
-		return fmt.Errorf("failed to load provider configuration")
-	}
-
-	// Initialize provider registry
-	registry = providers.NewProviderRegistry(config, logger)
-
-	// Initialize providers
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel()
-
-	if err := registry.Initialize(ctx); err != nil {
-		return fmt.Errorf("failed to initialize providers: %w", err)
+	// The provider registry is shared with the CLI commands, so an embedded
+	// server doesn't initialize the providers a second time
+	var err error
+	registry, err = providerscmd.Registry()
+	if err != nil {
+		return err
 	}
 
 	// Create MCP HTTP server
@@ -154,6 +184,34 @@ func runMCPServer(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	timeouts, err := toolTimeouts(cmd)
+	if err != nil {
+		return err
+	}
+	mcpServer.SetToolTimeouts(timeouts)
+
+	access, err := toolAccess(cmd)
+	if err != nil {
+		return err
+	}
+	mcpServer.SetToolAccess(access)
+
+	// Every tool call is recorded unless the config turns auditing off
+	if audit := mcp.NewAuditLogFromConfig(appconfig.ProfilePath(mcp.AuditLogFile), registry.MCPConfig(), logger); audit != nil {
+		mcpServer.SetAuditLog(audit)
+		logger.Infof("Auditing tool calls to %s", audit.Path())
+	} else {
+		logger.Warn("Audit log of tool calls is disabled")
+	}
+
+	if err := startupHealth(cmd); err != nil {
+		return err
+	}
+
+	if err := connectWorkflowEngine(); err != nil {
+		return err
+	}
+
 	host, _ := cmd.Flags().GetString("host")
 	port, _ := cmd.Flags().GetInt("port")
 	_, _ = cmd.Flags().GetBool("http-only")
@@ -196,17 +254,136 @@ func runMCPServer(cmd *cobra.Command, args []string) error {
 	}
 }
 
+// connectWorkflowEngine starts a workflow engine that consumes the task events
+// the providers publish while the server runs: they reach its auto-assignment
+// and transition log. It notifies through the engine the registry already
+// uses for task watchers, so digests and failed deliveries have one owner.
+func connectWorkflowEngine() error {
+	bus := registry.EventBus()
+	if bus == nil {
+		return nil
+	}
+
+	engine, err := workflow.NewCompleteWorkflowEngineWithNotifications(nil, workflow.GetDefaultCompleteConfig(), providerscmd.Notifications(), providerscmd.WorkflowLogger{Logger: logger})
+	if err != nil {
+		return fmt.Errorf("failed to start workflow engine: %w", err)
+	}
+	return engine.ConnectProviderEvents(bus)
+}
+
+// startupHealth runs the doctor checks when the start command is given
+// --require-healthy. Failed checks stop the server from starting unless
+// --allow-degraded is set, which starts it in degraded mode instead.
+func startupHealth(cmd *cobra.Command) error {
+	if cmd.Flags().Lookup("require-healthy") == nil {
+		return nil
+	}
+	if required, _ := cmd.Flags().GetBool("require-healthy"); !required {
+		return nil
+	}
+	allowDegraded, _ := cmd.Flags().GetBool("allow-degraded")
+	timeout, _ := cmd.Flags().GetDuration("health-timeout")
+
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	failures := doctorcmd.RunChecks(ctx, timeout).Failures()
+	if len(failures) == 0 {
+		logger.Info("Startup checks passed")
+		return nil
+	}
+
+	problems := make([]string, 0, len(failures))
+	for _, check := range failures {
+		problem := fmt.Sprintf("%s: %s", check.Name, check.Message)
+		problems = append(problems, problem)
+		if check.Hint != "" {
+			logger.Errorf("Startup check failed: %s (%s)", problem, check.Hint)
+		} else {
+			logger.Errorf("Startup check failed: %s", problem)
+		}
+	}
+
+	if !allowDegraded {
+		return fmt.Errorf("%d startup checks failed, run 'ricochet doctor' for details or start with --allow-degraded", len(failures))
+	}
+	logger.Warnf("Starting in degraded mode with %d failed startup checks", len(failures))
+	mcpServer.SetDegraded(problems)
+	return nil
+}
+
+// toolAccess reads which tools to expose from the configuration and the tool
+// flags of the start command
+func toolAccess(cmd *cobra.Command) (mcp.ToolAccess, error) {
+	access := mcp.NewToolAccess(registry.MCPConfig())
+	if cmd.Flags().Lookup("enable-tools") != nil {
+		if cmd.Flags().Changed("enable-tools") {
+			access.Enabled, _ = cmd.Flags().GetStringSlice("enable-tools")
+		}
+		if cmd.Flags().Changed("disable-tools") {
+			access.Disabled, _ = cmd.Flags().GetStringSlice("disable-tools")
+		}
+	}
+
+	if err := access.Validate(mcp.ToolNames()); err != nil {
+		return access, err
+	}
+	if len(access.Enabled) > 0 || len(access.Disabled) > 0 {
+		logger.Infof("Tool access: enabled %v, disabled %v", access.Enabled, access.Disabled)
+	}
+	return access, nil
+}
+
+// toolTimeouts reads the tool timeout flags of the start command; without
+// them tools run with the default timeouts
+func toolTimeouts(cmd *cobra.Command) (mcp.ToolTimeouts, error) {
+	timeouts := mcp.DefaultToolTimeouts()
+	if cmd.Flags().Lookup("tool-timeout") == nil {
+		return timeouts, nil
+	}
+	timeouts.Default, _ = cmd.Flags().GetDuration("timeout")
+	timeouts.AI, _ = cmd.Flags().GetDuration("ai-timeout")
+
+	perTool, _ := cmd.Flags().GetStringToString("tool-timeout")
+	if len(perTool) == 0 {
+		return timeouts, nil
+	}
+	known := make(map[string]bool)
+	for _, name := range mcp.ToolNames() {
+		known[name] = true
+	}
+	timeouts.PerTool = make(map[string]time.Duration, len(perTool))
+	for name, value := range perTool {
+		if !known[name] {
+			return timeouts, providers.NewValidationError(fmt.Sprintf("--tool-timeout: unknown tool %q, see ricochet mcp tools", name), nil)
+		}
+		timeout, err := time.ParseDuration(value)
+		if err != nil || timeout < 0 {
+			return timeouts, providers.NewValidationError(fmt.Sprintf("--tool-timeout: invalid timeout %q for %s, use a duration like 90s or 5m", value, name), nil)
+		}
+		timeouts.PerTool[name] = timeout
+	}
+	return timeouts, nil
+}
+
 func runListTools(cmd *cobra.Command, args []string) error {
-	if err := initializeMCP(); err != nil {
+	// Listing reads only the tool definitions and the mcp section of the
+	// config, so no server or tool provider is set up
+	var err error
+	registry, err = providerscmd.Registry()
+	if err != nil {
 		return err
 	}
 
-	output, _ := cmd.Flags().GetString("output")
+	output := providerscmd.OutputFormat(cmd)
 	verbose, _ := cmd.Flags().GetBool("verbose")
 
-	// Get tools from MCP server
-	toolProvider := mcp.NewMCPToolProvider(registry)
-	tools := toolProvider.GetTools()
+	access := mcp.NewToolAccess(registry.MCPConfig())
+	if all, _ := cmd.Flags().GetBool("all"); all {
+		access = mcp.ToolAccess{}
+	}
+	tools := mcp.ToolDefinitions(access)
 
 	switch output {
 	case "json":
@@ -302,39 +479,16 @@ func validateAllProviders(ctx context.Context, fix bool, verbose bool) error {
 
 // Helper functions
 
-func loadMultiProviderConfig() *providers.MultiProviderConfig {
-	config := providers.DefaultMultiProviderConfig()
-
-	// Try to load from config file
-	configFile := viper.GetString("config")
-	if configFile == "" {
-		configFile = "ricochet.yaml"
-	}
-
-	if _, err := os.Stat(configFile); err == nil {
-		viper.SetConfigFile(configFile)
-		if err := viper.ReadInConfig(); err == nil {
-			viper.Unmarshal(config)
-			logger.Infof("Loaded configuration from %s", configFile)
-		} else {
-			logger.Warnf("Failed to read config file %s: %v", configFile, err)
-		}
-	} else {
-		logger.Infof("No config file found at %s, using defaults", configFile)
-	}
-
-	return config
-}
-
 func outputJSON(data interface{}) error {
-	// This would require encoding/json import
-	fmt.Printf("%+v\n", data)
-	return nil
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(data)
 }
 
 func outputYAML(data interface{}) error {
-	// For now, use JSON output since yaml package import would be needed
-	return outputJSON(data)
+	encoder := yaml.NewEncoder(os.Stdout)
+	defer encoder.Close()
+	return encoder.Encode(data)
 }
 
 func outputToolsTable(tools []mcp.ToolDefinition, verbose bool) error {
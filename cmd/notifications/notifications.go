@@ -0,0 +1,330 @@
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/user"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	providerscmd "github.com/grik-ai/ricochet-task/cmd/providers"
+	"github.com/grik-ai/ricochet-task/pkg/providers"
+	"github.com/grik-ai/ricochet-task/pkg/workflow"
+)
+
+// NotificationsCmd represents the notifications command
+var NotificationsCmd = &cobra.Command{
+	Use:   "notifications",
+	Short: "Check notification channels and retry failed deliveries",
+	Long: `Check the notification channels that deliver task watcher notifications and
+retry deliveries that failed.
+
+Channels are configured through environment variables:
+
+  email    RICOCHET_SMTP_HOST, RICOCHET_SMTP_PORT, RICOCHET_SMTP_USERNAME,
+           RICOCHET_SMTP_PASSWORD, RICOCHET_SMTP_FROM
+  slack    RICOCHET_SLACK_WEBHOOK_URL, or RICOCHET_SLACK_BOT_TOKEN and RICOCHET_SLACK_CHANNEL
+  teams    RICOCHET_TEAMS_WEBHOOK_URL
+  webhook  RICOCHET_WEBHOOK_URL, RICOCHET_WEBHOOK_SECRET, RICOCHET_WEBHOOK_TEMPLATE_FILE,
+           RICOCHET_WEBHOOK_HEADERS`,
+}
+
+var testCmd = &cobra.Command{
+	Use:   "test",
+	Short: "Send a sample notification through a channel",
+	Long: `Send a sample task watcher notification through a channel using the current
+configuration and report whether it was delivered, with the channel's error if
+it wasn't.
+
+--user is the recipient: an email address for email, a #channel or Slack ID
+for a Slack bot token, or "me" for $RICOCHET_USER or the OS user. Slack bots
+post to RICOCHET_SLACK_CHANNEL when the recipient is not a channel.
+
+Examples:
+  ricochet notifications test --channel slack
+  ricochet notifications test --channel email --user alice@example.com
+  ricochet notifications test --channel webhook --event task.status_changed`,
+	Args: cobra.NoArgs,
+	RunE: runTestNotification,
+}
+
+var previewCmd = &cobra.Command{
+	Use:   "preview",
+	Short: "Show what a notification would look like",
+	Long: `Render the notification task watchers get for an event, and with --channel
+the message that channel would send, without sending anything. Webhook
+payloads are rendered with the configured template.
+
+Examples:
+  ricochet notifications preview --event task.created
+  ricochet notifications preview --event task.assigned --channel slack
+  ricochet notifications preview --event task.updated --channel webhook --output json`,
+	Args: cobra.NoArgs,
+	RunE: runPreviewNotification,
+}
+
+var retryFailedCmd = &cobra.Command{
+	Use:   "retry-failed",
+	Short: "Retry notifications that failed to deliver",
+	Long: `Retry the notification deliveries that failed, through the channel they
+failed on. Failed deliveries are kept in failed_notifications.json of the active
+profile and retried in the background with a growing backoff per channel; this command
+retries them right away.
+
+Transient failures, like a channel being down, are retried. Permanent ones,
+like an unknown recipient, a deleted Slack channel or a channel that is not
+configured, are kept but only retried with --include-permanent, after the
+recipient or the configuration was fixed. Deliveries that keep failing become
+permanent after 10 attempts.
+
+Examples:
+  ricochet notifications retry-failed --list
+  ricochet notifications retry-failed
+  ricochet notifications retry-failed --include-permanent`,
+	Args: cobra.NoArgs,
+	RunE: runRetryFailed,
+}
+
+func init() {
+	NotificationsCmd.AddCommand(testCmd)
+	NotificationsCmd.AddCommand(previewCmd)
+	NotificationsCmd.AddCommand(retryFailedCmd)
+
+	testCmd.Flags().String("channel", "", "Channel to send through ("+strings.Join(workflow.NotificationChannelTypes, ", ")+")")
+	testCmd.Flags().String("user", "me", "Recipient of the notification")
+	testCmd.Flags().String("event", string(providers.EventTypeTaskUpdated), "Event the sample notification is about")
+	testCmd.Flags().Duration("timeout", 30*time.Second, "Timeout of the delivery")
+	testCmd.MarkFlagRequired("channel")
+
+	previewCmd.Flags().String("event", string(providers.EventTypeTaskUpdated), "Event to render ("+strings.Join(workflow.SampleEventTypes(), ", ")+")")
+	previewCmd.Flags().String("channel", "", "Also render the message of this channel")
+	previewCmd.Flags().String("user", "me", "Recipient of the notification")
+	previewCmd.Flags().StringP("output", "o", "", "Output format (table, json, yaml); defaults to $RICOCHET_OUTPUT or defaultOutputFormat from the config")
+
+	retryFailedCmd.Flags().Bool("list", false, "Only list the failed deliveries")
+	retryFailedCmd.Flags().Bool("include-permanent", false, "Also retry permanent failures")
+	retryFailedCmd.Flags().Duration("timeout", 2*time.Minute, "Timeout of all retries")
+
+	for _, command := range []*cobra.Command{testCmd, previewCmd} {
+		command.RegisterFlagCompletionFunc("channel", cobra.FixedCompletions(workflow.NotificationChannelTypes, cobra.ShellCompDirectiveNoFileComp))
+		command.RegisterFlagCompletionFunc("event", cobra.FixedCompletions(workflow.SampleEventTypes(), cobra.ShellCompDirectiveNoFileComp))
+	}
+}
+
+// channelSettings names the environment variables of each channel for hints
+var channelSettings = map[string]string{
+	"email":   "RICOCHET_SMTP_HOST",
+	"slack":   "RICOCHET_SLACK_WEBHOOK_URL or RICOCHET_SLACK_BOT_TOKEN",
+	"teams":   "RICOCHET_TEAMS_WEBHOOK_URL",
+	"webhook": "RICOCHET_WEBHOOK_URL",
+}
+
+func runTestNotification(cmd *cobra.Command, args []string) error {
+	channelType, _ := cmd.Flags().GetString("channel")
+	event, _ := cmd.Flags().GetString("event")
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+
+	recipient, err := resolveRecipient(cmd)
+	if err != nil {
+		return err
+	}
+	notification, err := workflow.SampleWatchNotification(providers.EventType(event), recipient)
+	if err != nil {
+		return err
+	}
+	notification.Title = "[Test] " + notification.Title
+
+	channel, err := workflow.NewChannelFromEnv(channelType, quietLogger{})
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(cmd.Context(), timeout)
+	defer cancel()
+
+	if err := channel.Send(ctx, notification); err != nil {
+		cmd.SilenceUsage = true
+		fmt.Printf("❌ Failed to send a sample %s notification through %s\n", event, channelType)
+		if errors.Is(err, workflow.ErrChannelNotConfigured) {
+			return providers.NewProviderError(providers.ErrorTypeConfiguration,
+				fmt.Sprintf("%s notifications are not configured, set %s", channelType, channelSettings[channelType]), err)
+		}
+		return fmt.Errorf("%s delivery failed: %w", channelType, err)
+	}
+
+	fmt.Printf("✅ Sent a sample %s notification to %s through %s\n", event, recipient, channelType)
+	return nil
+}
+
+// notificationPreview is the output of the preview command
+type notificationPreview struct {
+	Event      string                   `json:"event" yaml:"event"`
+	Title      string                   `json:"title" yaml:"title"`
+	Message    string                   `json:"message" yaml:"message"`
+	Recipients []string                 `json:"recipients" yaml:"recipients"`
+	Channel    *workflow.ChannelPreview `json:"channel,omitempty" yaml:"channel,omitempty"`
+}
+
+func runPreviewNotification(cmd *cobra.Command, args []string) error {
+	event, _ := cmd.Flags().GetString("event")
+	channelType, _ := cmd.Flags().GetString("channel")
+
+	recipient, err := resolveRecipient(cmd)
+	if err != nil {
+		return err
+	}
+	notification, err := workflow.SampleWatchNotification(providers.EventType(event), recipient)
+	if err != nil {
+		return err
+	}
+
+	preview := &notificationPreview{
+		Event:      event,
+		Title:      notification.Title,
+		Message:    notification.Message,
+		Recipients: notification.Recipients,
+	}
+	if channelType != "" {
+		channel, err := workflow.NewChannelFromEnv(channelType, quietLogger{})
+		if err != nil {
+			return err
+		}
+		if preview.Channel, err = workflow.PreviewChannel(channel, notification); err != nil {
+			return err
+		}
+	}
+
+	format := providerscmd.OutputFormat(cmd)
+	switch format {
+	case providers.OutputFormatJSON:
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(preview)
+	case providers.OutputFormatYAML:
+		encoder := yaml.NewEncoder(os.Stdout)
+		defer encoder.Close()
+		return encoder.Encode(preview)
+	}
+
+	fmt.Printf("📨 %s notification to %s (nothing was sent)\n\n", event, strings.Join(preview.Recipients, ", "))
+	fmt.Printf("Title:   %s\n", preview.Title)
+	fmt.Printf("Message:\n%s\n", indent(preview.Message))
+
+	if preview.Channel != nil {
+		target := preview.Channel.Target
+		if target == "" {
+			target = fmt.Sprintf("not configured, set %s", channelSettings[channelType])
+		}
+		payload, err := json.MarshalIndent(preview.Channel.Payload, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to render %s payload: %w", channelType, err)
+		}
+		fmt.Printf("\n%s message (to %s):\n%s\n", channelType, target, indent(string(payload)))
+	}
+	return nil
+}
+
+func runRetryFailed(cmd *cobra.Command, args []string) error {
+	listOnly, _ := cmd.Flags().GetBool("list")
+	includePermanent, _ := cmd.Flags().GetBool("include-permanent")
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+
+	// The engine registers the channels configured through the environment
+	engine := workflow.NewSmartNotificationEngine(nil, quietLogger{})
+
+	if listOnly {
+		failed := engine.FailedDeliveries()
+		if len(failed) == 0 {
+			fmt.Println("✅ No failed notifications")
+			return nil
+		}
+		for _, delivery := range failed {
+			printFailedDelivery("", delivery, true)
+		}
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(cmd.Context(), timeout)
+	defer cancel()
+
+	report, err := engine.RetryFailed(ctx, time.Now(), workflow.RetryOptions{Force: true, IncludePermanent: includePermanent})
+	if err != nil {
+		return err
+	}
+	if len(report.Delivered)+len(report.Failed)+len(report.Deferred) == 0 {
+		if report.Remaining > 0 {
+			fmt.Printf("No transient failures to retry, %d permanent left (use --include-permanent)\n", report.Remaining)
+		} else {
+			fmt.Println("✅ No failed notifications")
+		}
+		return nil
+	}
+
+	for _, delivery := range report.Delivered {
+		printFailedDelivery("✅ Delivered", delivery, false)
+	}
+	for _, delivery := range report.Failed {
+		printFailedDelivery("❌ Failed", delivery, true)
+	}
+	for _, delivery := range report.Deferred {
+		printFailedDelivery("⏸️  Deferred", delivery, true)
+	}
+	fmt.Printf("\n%d delivered, %d failed, %d deferred, %d left\n",
+		len(report.Delivered), len(report.Failed), len(report.Deferred), report.Remaining)
+
+	if len(report.Failed) > 0 {
+		cmd.SilenceUsage = true
+		return fmt.Errorf("%d notifications could not be delivered", len(report.Failed))
+	}
+	return nil
+}
+
+// printFailedDelivery prints a failed delivery on one line, and with showError its last error on the next
+func printFailedDelivery(prefix string, delivery *workflow.FailedDelivery, showError bool) {
+	kind := "transient"
+	if delivery.Permanent {
+		kind = "permanent"
+	}
+	if prefix != "" {
+		prefix += " "
+	}
+	fmt.Printf("%s%s via %s to %s (failed attempts: %d, %s)\n", prefix, delivery.Notification.Title, delivery.Channel,
+		strings.Join(delivery.Notification.Recipients, ", "), delivery.Attempts, kind)
+	if showError && delivery.LastError != "" {
+		fmt.Printf("   %s\n", delivery.LastError)
+	}
+}
+
+// resolveRecipient returns --user, with "me" standing for $RICOCHET_USER or the OS user
+func resolveRecipient(cmd *cobra.Command) (string, error) {
+	recipient, _ := cmd.Flags().GetString("user")
+	if recipient != "" && recipient != "me" {
+		return recipient, nil
+	}
+	if userID := os.Getenv("RICOCHET_USER"); userID != "" {
+		return userID, nil
+	}
+	current, err := user.Current()
+	if err != nil {
+		return "", fmt.Errorf("failed to determine current user, use --user: %w", err)
+	}
+	return current.Username, nil
+}
+
+func indent(text string) string {
+	return "  " + strings.ReplaceAll(strings.TrimRight(text, "\n"), "\n", "\n  ")
+}
+
+// quietLogger drops channel logs, the commands report the outcome themselves
+type quietLogger struct{}
+
+func (quietLogger) Info(msg string, fields ...interface{})             {}
+func (quietLogger) Error(msg string, err error, fields ...interface{}) {}
+func (quietLogger) Debug(msg string, fields ...interface{})            {}
+func (quietLogger) Warn(msg string, fields ...interface{})             {}
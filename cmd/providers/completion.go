@@ -0,0 +1,73 @@
+package providers
+
+import (
+	"context"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/grik-ai/ricochet-task/pkg/providers"
+)
+
+// CompletionTimeout bounds how long shell completion may wait for providers
+const CompletionTimeout = 5 * time.Second
+
+// CompleteProviderNames completes the names of enabled providers from the config.
+// It doesn't contact the providers, so it is safe to use for every --provider flag.
+func CompleteProviderNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return FilterCompletions(EnabledProviderNames(), toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// EnabledProviderNames returns the sorted names of the providers enabled in the config
+func EnabledProviderNames() []string {
+	var names []string
+	for name, config := range loadMultiProviderConfig().Providers {
+		if config.Enabled {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// completeProviderTypes completes the registered provider types
+func completeProviderTypes(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return FilterCompletions(registeredTypeNames(), toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// registeredTypeNames returns the provider types with a registered plugin
+func registeredTypeNames() []string {
+	var names []string
+	for _, providerType := range providers.RegisteredProviderTypes() {
+		names = append(names, string(providerType))
+	}
+	return names
+}
+
+// CompletionRegistry initializes a provider registry for shell completion.
+// Logs are discarded so they don't end up in the completion output, and
+// webhooks are not subscribed since completion never changes tasks.
+func CompletionRegistry(ctx context.Context) (*providers.ProviderRegistry, error) {
+	if registry != nil {
+		return registry, nil
+	}
+
+	quiet := logrus.New()
+	quiet.SetOutput(io.Discard)
+	return newRegistry(ctx, loadMultiProviderConfig(), quiet, false)
+}
+
+// FilterCompletions returns the candidates starting with toComplete
+func FilterCompletions(candidates []string, toComplete string) []string {
+	var matches []string
+	for _, candidate := range candidates {
+		if strings.HasPrefix(candidate, toComplete) {
+			matches = append(matches, candidate)
+		}
+	}
+	return matches
+}
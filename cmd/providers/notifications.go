@@ -0,0 +1,81 @@
+package providers
+
+import (
+	"context"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+
+	appconfig "github.com/grik-ai/ricochet-task/internal/config"
+	"github.com/grik-ai/ricochet-task/pkg/providers"
+	"github.com/grik-ai/ricochet-task/pkg/workflow"
+)
+
+var (
+	notificationsOnce sync.Once
+	notifications     *workflow.SmartNotificationEngine
+)
+
+// Notifications returns the notification engine shared by the commands and the
+// MCP server of this process. It is created on first use, because it restores
+// pending digests and failed deliveries from disk.
+func Notifications() *workflow.SmartNotificationEngine {
+	notificationsOnce.Do(func() {
+		log := logger
+		if log == nil {
+			log = logrus.New()
+		}
+		notifications = workflow.NewSmartNotificationEngine(nil, WorkflowLogger{Logger: log})
+	})
+	return notifications
+}
+
+// connectTaskWatchers delivers the task events of bus to the users watching
+// the tasks, whichever command or server changed them
+func connectTaskWatchers(bus *providers.EventBus, logger *logrus.Logger) error {
+	watchers, err := providers.NewTaskWatchRegistry(appconfig.ProfilePath(providers.WatchersFile), logger)
+	if err != nil {
+		return err
+	}
+	return workflow.ConnectTaskWatchers(bus, watchers, watchNotifier{})
+}
+
+// watchNotifier passes watcher notifications to the shared engine, so it is
+// only created when a watched task changes
+type watchNotifier struct{}
+
+func (watchNotifier) NotifyWatchers(ctx context.Context, userIDs []string, event *providers.UniversalEvent) error {
+	return Notifications().NotifyWatchers(ctx, userIDs, event)
+}
+
+// WorkflowLogger passes log messages of workflow components to a logrus logger
+type WorkflowLogger struct {
+	Logger *logrus.Logger
+}
+
+func (l WorkflowLogger) Info(msg string, args ...interface{}) {
+	l.Logger.WithFields(workflowLogFields(args)).Info(msg)
+}
+
+func (l WorkflowLogger) Error(msg string, err error, args ...interface{}) {
+	l.Logger.WithFields(workflowLogFields(args)).WithError(err).Error(msg)
+}
+
+func (l WorkflowLogger) Warn(msg string, args ...interface{}) {
+	l.Logger.WithFields(workflowLogFields(args)).Warn(msg)
+}
+
+func (l WorkflowLogger) Debug(msg string, args ...interface{}) {
+	l.Logger.WithFields(workflowLogFields(args)).Debug(msg)
+}
+
+// workflowLogFields turns key/value arguments into log fields
+func workflowLogFields(args []interface{}) logrus.Fields {
+	fields := logrus.Fields{}
+	for i := 0; i+1 < len(args); i += 2 {
+		if name, ok := args[i].(string); ok {
+			fields[name] = args[i+1]
+		}
+	}
+	return fields
+}
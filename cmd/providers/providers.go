@@ -5,7 +5,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -13,8 +15,10 @@ import (
 	"github.com/sirupsen/logrus"
 	"gopkg.in/yaml.v3"
 
+	appconfig "github.com/grik-ai/ricochet-task/internal/config"
 	"github.com/grik-ai/ricochet-task/pkg/providers"
-	"github.com/grik-ai/ricochet-task/pkg/providers/youtrack"
+	// Provider packages register their types on import
+	_ "github.com/grik-ai/ricochet-task/pkg/providers/youtrack"
 )
 
 var (
@@ -30,11 +34,19 @@ var ProvidersCmd = &cobra.Command{
 	
 Providers allow ricochet-task to integrate with various task management systems,
 enabling unified operations across multiple platforms.`,
-	PersistentPreRun: func(cmd *cobra.Command, args []string) {
-		initializeProviders()
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if CommandTimeout(cmd) < 0 {
+			return providers.NewValidationError("--timeout must not be negative", nil)
+		}
+		_, err := CommandRegistry(cmd)
+		return err
 	},
 }
 
+// defaultCommandTimeout is the deadline of provider commands and of
+// initializing the providers, used unless --timeout is given
+const defaultCommandTimeout = 30 * time.Second
+
 var listCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List all configured providers",
@@ -108,6 +120,58 @@ Examples:
 	RunE: runHealthCheck,
 }
 
+var fieldsCmd = &cobra.Command{
+	Use:   "fields [name]",
+	Short: "List custom fields and check the field mapping",
+	Long: `List the custom fields defined in a provider and the typed task fields they
+are mapped to with fieldMapping in the provider configuration. The mapping is
+checked against the discovered fields.
+
+Example configuration:
+  fieldMapping:
+    Story points: storyPoints
+    Due Date: dueDate
+
+Examples:
+  ricochet providers fields youtrack-prod
+  ricochet providers fields youtrack-prod --output json`,
+	Args:              cobra.ExactArgs(1),
+	RunE:              runListFields,
+	ValidArgsFunction: CompleteProviderNames,
+}
+
+var labelsCmd = &cobra.Command{
+	Use:   "labels [name]",
+	Short: "List the labels tasks can be tagged with",
+	Long: `List the labels a provider knows. Task create and update accept only these
+labels unless --create-labels is given, so a typo is reported instead of
+creating a near-duplicate label.
+
+Examples:
+  ricochet providers labels youtrack-prod
+  ricochet providers labels youtrack-prod --project OPS --output json`,
+	Args:              cobra.ExactArgs(1),
+	RunE:              runListLabels,
+	ValidArgsFunction: CompleteProviderNames,
+}
+
+var testRoutingCmd = &cobra.Command{
+	Use:   "test-routing",
+	Short: "Preview which provider the routing rules pick for a task",
+	Long: `Evaluate the routing rules of the configuration against a hypothetical task
+and show which provider 'tasks create --auto-route' would pick, which rules
+matched and why the others were skipped. Nothing is created.
+
+The type and priority default to those of 'tasks create'.
+
+Examples:
+  ricochet providers test-routing --type bug --project BACKEND --priority high --labels backend
+  ricochet providers test-routing --title "Fix login" --field "Team=Platform"
+  ricochet providers test-routing --type feature --output json`,
+	Args: cobra.NoArgs,
+	RunE: runTestRouting,
+}
+
 var defaultCmd = &cobra.Command{
 	Use:   "default [name]",
 	Short: "Set default provider",
@@ -120,6 +184,8 @@ Examples:
 }
 
 func init() {
+	ProvidersCmd.PersistentFlags().Duration("timeout", 0, "Deadline for the whole command (defaults to 30s, or the provider's timeout if longer)")
+
 	// Add subcommands
 	ProvidersCmd.AddCommand(listCmd)
 	ProvidersCmd.AddCommand(addCmd)
@@ -127,14 +193,17 @@ func init() {
 	ProvidersCmd.AddCommand(enableCmd)
 	ProvidersCmd.AddCommand(disableCmd)
 	ProvidersCmd.AddCommand(healthCmd)
+	ProvidersCmd.AddCommand(fieldsCmd)
+	ProvidersCmd.AddCommand(labelsCmd)
 	ProvidersCmd.AddCommand(defaultCmd)
+	ProvidersCmd.AddCommand(testRoutingCmd)
 
 	// List command flags
 	listCmd.Flags().Bool("enabled-only", false, "Show only enabled providers")
-	listCmd.Flags().StringP("output", "o", "table", "Output format: table, json, yaml")
+	listCmd.Flags().StringP("output", "o", "table", "Output format: table, json, yaml (defaults to $RICOCHET_OUTPUT or defaultOutputFormat from the config)")
 
 	// Add command flags
-	addCmd.Flags().StringP("type", "t", "", "Provider type (youtrack, jira, notion, etc.)")
+	addCmd.Flags().StringP("type", "t", "", "Provider type ("+strings.Join(registeredTypeNames(), ", ")+")")
 	addCmd.Flags().StringP("config", "c", "", "Configuration file path")
 	addCmd.Flags().String("base-url", "", "Base URL for the provider")
 	addCmd.Flags().String("token", "", "Authentication token")
@@ -142,7 +211,9 @@ func init() {
 	addCmd.Flags().String("username", "", "Username for basic auth")
 	addCmd.Flags().String("password", "", "Password for basic auth")
 	addCmd.Flags().Bool("enable", true, "Enable the provider after adding")
+	addCmd.Flags().String("default-project", "", "Project tasks commands use when --project is omitted")
 	addCmd.MarkFlagRequired("type")
+	addCmd.RegisterFlagCompletionFunc("type", completeProviderTypes)
 
 	// Remove command flags
 	removeCmd.Flags().Bool("force", false, "Force removal without confirmation")
@@ -150,33 +221,164 @@ func init() {
 	// Health command flags
 	healthCmd.Flags().Bool("watch", false, "Watch health status continuously")
 	healthCmd.Flags().Duration("interval", 30*time.Second, "Watch interval")
+	healthCmd.Flags().Duration("timeout", providers.DefaultHealthCheckTimeout, "Timeout for each provider check")
+	healthCmd.Flags().Duration("degraded-after", 0, "Latency above which a provider is reported as degraded (defaults to degradedLatency from the config or 3s)")
+
+	// Fields command flags
+	fieldsCmd.Flags().StringP("output", "o", "table", "Output format: table, json, yaml (defaults to $RICOCHET_OUTPUT or defaultOutputFormat from the config)")
+
+	// Labels command flags
+	labelsCmd.Flags().String("project", "", "Project whose labels to list, for providers with per-project labels (defaults to the provider's defaultProject)")
+	labelsCmd.Flags().StringP("output", "o", "table", "Output format: table, json, yaml (defaults to $RICOCHET_OUTPUT or defaultOutputFormat from the config)")
 
 	// Default command flags
 	defaultCmd.Flags().Bool("show", false, "Show current default provider")
+
+	// Test routing command flags
+	testRoutingCmd.Flags().String("title", "", "Task title, matched against query conditions")
+	testRoutingCmd.Flags().String("description", "", "Task description, matched against query conditions")
+	testRoutingCmd.Flags().String("project", "", "Project ID")
+	testRoutingCmd.Flags().String("type", "task", "Task type (task, bug, feature, etc.)")
+	testRoutingCmd.Flags().String("priority", "medium", "Task priority (lowest, low, medium, high, highest, critical)")
+	testRoutingCmd.Flags().String("assignee", "", "Assignee ID or username")
+	testRoutingCmd.Flags().StringSlice("labels", []string{}, "Task labels")
+	testRoutingCmd.Flags().StringToString("field", map[string]string{}, "Custom field values as Name=value")
+	testRoutingCmd.Flags().StringP("output", "o", "table", "Output format: table, json, yaml (defaults to $RICOCHET_OUTPUT or defaultOutputFormat from the config)")
+}
+
+// registryMu guards the lazy initialization of registry
+var registryMu sync.Mutex
+
+// Registry loads the provider configuration and initializes the providers on
+// the first successful call, then returns the same registry to every caller.
+// Commands and an embedded MCP server share it, so providers are never
+// initialized twice. A failed initialization is not cached: the next call
+// retries it, e.g. after the configuration has been fixed. Safe for
+// concurrent use.
+func Registry() (*providers.ProviderRegistry, error) {
+	return registryWithin(0)
+}
+
+// CommandRegistry is Registry for commands with a --timeout flag, which then
+// also bounds initializing the providers
+func CommandRegistry(cmd *cobra.Command) (*providers.ProviderRegistry, error) {
+	return registryWithin(CommandTimeout(cmd))
 }
 
-func initializeProviders() {
+// registryWithin returns the shared registry, initializing the providers
+// within timeout. Without one they get 30s, raised to the longest request
+// timeout configured for a provider.
+func registryWithin(timeout time.Duration) (*providers.ProviderRegistry, error) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if registry != nil {
+		return registry, nil
+	}
+
 	logger = logrus.New()
 	logger.SetLevel(logrus.InfoLevel)
+	if viper.GetBool("debug") {
+		logger.SetLevel(logrus.DebugLevel)
+	}
 
-	// Load configuration
 	config := loadMultiProviderConfig()
+	if timeout <= 0 {
+		timeout = defaultCommandTimeout
+		for _, provider := range config.Providers {
+			if provider != nil && provider.Timeout > timeout {
+				timeout = provider.Timeout
+			}
+		}
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
 
-	// Initialize registry
-	registry = providers.NewProviderRegistry(config, logger)
+	initialized, err := newRegistry(ctx, config, logger, true)
+	if err != nil {
+		return nil, err
+	}
+	registry = initialized
+	return registry, nil
+}
 
-	// Initialize providers
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel()
+// CommandTimeout returns the --timeout deadline of the whole command, or 0 if
+// none is given. A --timeout that only one subcommand defines, like the
+// per-check timeout of 'providers health', means something else and is
+// ignored.
+func CommandTimeout(cmd *cobra.Command) time.Duration {
+	flag := cmd.InheritedFlags().Lookup("timeout")
+	if flag == nil {
+		flag = cmd.PersistentFlags().Lookup("timeout")
+	}
+	if flag == nil {
+		return 0
+	}
+	timeout, _ := time.ParseDuration(flag.Value.String())
+	return timeout
+}
+
+// CommandContext returns a context with the deadline of a command: --timeout
+// if given, otherwise fallback raised to the request timeout configured for
+// the provider, so that one slow request is not cut short. Without a provider
+// name the default provider is assumed, and a zero fallback means no deadline.
+func CommandContext(cmd *cobra.Command, registry *providers.ProviderRegistry, providerName string, fallback time.Duration) (context.Context, context.CancelFunc) {
+	timeout := CommandTimeout(cmd)
+	if timeout <= 0 && fallback > 0 {
+		if providerName == "" {
+			providerName = registry.DefaultProviderName()
+		}
+		timeout = fallback
+		if configured := registry.ProviderTimeout(providerName); configured > timeout {
+			timeout = configured
+		}
+	}
+	if timeout <= 0 {
+		return context.WithCancel(context.Background())
+	}
+	return context.WithTimeout(context.Background(), timeout)
+}
+
+// newRegistry creates a registry for config and initializes its providers.
+// Task events are only recorded, sent to webhooks and delivered to task
+// watchers when withEvents is set.
+func newRegistry(ctx context.Context, config *providers.MultiProviderConfig, logger *logrus.Logger, withEvents bool) (*providers.ProviderRegistry, error) {
+	registry := providers.NewProviderRegistry(config, logger)
+
+	// Users, projects and statuses are cached between runs
+	if metadata, err := providers.NewMetadataCache(appconfig.ProfilePath(providers.MetadataCacheFile), logger); err != nil {
+		logger.Warnf("Metadata cache disabled: %v", err)
+	} else {
+		registry.SetMetadataCache(metadata)
+	}
+
+	// Task operations publish into an event bus that keeps the local activity
+	// log, feeds webhooks and notifies task watchers
+	if withEvents {
+		bus := providers.NewEventBus(providers.DefaultEventBufferSize, logger)
+		activity := providers.NewActivityLog(appconfig.ProfilePath(providers.ActivityLogFile))
+		if err := providers.SubscribeActivityLog(bus, activity); err != nil {
+			return nil, fmt.Errorf("failed to subscribe activity log: %w", err)
+		}
+		if err := providers.SubscribeWebhooks(bus, config.Webhooks, logger); err != nil {
+			return nil, providers.NewProviderError(providers.ErrorTypeValidation, "failed to configure webhooks", err)
+		}
+		if err := connectTaskWatchers(bus, logger); err != nil {
+			logger.Warnf("Task watchers won't be notified: %v", err)
+		}
+		registry.SetEventBus(bus)
+	}
 
 	if err := registry.Initialize(ctx); err != nil {
-		logger.Fatalf("Failed to initialize providers: %v", err)
+		return nil, providers.NewProviderError(providers.ErrorTypeConfiguration, "failed to initialize providers", err)
 	}
+
+	return registry, nil
 }
 
 func runListProviders(cmd *cobra.Command, args []string) error {
 	enabledOnly, _ := cmd.Flags().GetBool("enabled-only")
-	output, _ := cmd.Flags().GetString("output")
+	output := OutputFormat(cmd)
 
 	var providerInfos map[string]*providers.ProviderInfo
 	if enabledOnly {
@@ -219,9 +421,12 @@ func runAddProvider(cmd *cobra.Command, args []string) error {
 		// Create config from flags
 		config = createProviderConfigFromFlags(name, providerType, baseURL, token, apiKey, username, password, enable)
 	}
+	if project, _ := cmd.Flags().GetString("default-project"); project != "" {
+		config.DefaultProject = project
+	}
 
 	// Add provider
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := CommandContext(cmd, registry, name, defaultCommandTimeout)
 	defer cancel()
 
 	if err := registry.AddProvider(ctx, name, config); err != nil {
@@ -262,7 +467,7 @@ func runRemoveProvider(cmd *cobra.Command, args []string) error {
 func runEnableProvider(cmd *cobra.Command, args []string) error {
 	name := args[0]
 
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := CommandContext(cmd, registry, name, defaultCommandTimeout)
 	defer cancel()
 
 	if err := registry.EnableProvider(ctx, name); err != nil {
@@ -287,14 +492,191 @@ func runDisableProvider(cmd *cobra.Command, args []string) error {
 func runHealthCheck(cmd *cobra.Command, args []string) error {
 	watch, _ := cmd.Flags().GetBool("watch")
 	interval, _ := cmd.Flags().GetDuration("interval")
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+	degradedAfter, _ := cmd.Flags().GetDuration("degraded-after")
+	options := providers.HealthCheckOptions{Timeout: timeout, DegradedLatency: degradedAfter}
 
 	if len(args) > 0 {
 		// Check specific provider
-		return checkProviderHealth(args[0], watch, interval)
+		return checkProviderHealth(args[0], watch, interval, options)
 	}
 
 	// Check all providers
-	return checkAllProvidersHealth(watch, interval)
+	return checkAllProvidersHealth(watch, interval, options)
+}
+
+// customFieldRow is a custom field of a provider and the task field it is mapped to
+type customFieldRow struct {
+	Name      string              `json:"name" yaml:"name"`
+	Type      string              `json:"type,omitempty" yaml:"type,omitempty"`
+	MappedTo  providers.TaskField `json:"mappedTo,omitempty" yaml:"mappedTo,omitempty"`
+	Available bool                `json:"available" yaml:"available"` // false if mapped but not defined in the provider
+}
+
+func runListFields(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	provider, err := registry.GetProvider(name)
+	if err != nil {
+		return fmt.Errorf("provider not found: %w", err)
+	}
+	fieldProvider, ok := providers.ProviderAs[providers.CustomFieldProvider](provider)
+	if !ok {
+		return providers.NewUnsupportedError(name, providers.CapabilityCustomFields)
+	}
+
+	ctx, cancel := CommandContext(cmd, registry, name, defaultCommandTimeout)
+	defer cancel()
+
+	fields, err := fieldProvider.ListCustomFields(ctx)
+	if err != nil {
+		return err
+	}
+
+	mapping := registry.FieldMapping(name)
+	rows := make([]customFieldRow, 0, len(fields))
+	for _, field := range fields {
+		row := customFieldRow{Name: field.Name, Type: field.Type, Available: true}
+		for mapped, taskField := range mapping {
+			if strings.EqualFold(strings.TrimSpace(mapped), strings.TrimSpace(field.Name)) {
+				row.MappedTo = taskField
+			}
+		}
+		rows = append(rows, row)
+	}
+	for mapped, taskField := range mapping {
+		found := false
+		for _, row := range rows {
+			found = found || row.MappedTo == taskField
+		}
+		if !found {
+			rows = append(rows, customFieldRow{Name: mapped, MappedTo: taskField})
+		}
+	}
+	sort.Slice(rows, func(i, j int) bool { return rows[i].Name < rows[j].Name })
+
+	switch OutputFormat(cmd) {
+	case "json":
+		err = outputJSON(rows)
+	case "yaml":
+		err = outputYAML(rows)
+	default:
+		fmt.Printf("%-30s %-25s %s\n", "FIELD", "TYPE", "MAPPED TO")
+		for _, row := range rows {
+			mappedTo := string(row.MappedTo)
+			if !row.Available {
+				mappedTo += " (not found)"
+			}
+			fmt.Printf("%-30s %-25s %s\n", row.Name, row.Type, mappedTo)
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	cmd.SilenceUsage = true
+	return mapping.ValidateFields(fields)
+}
+
+func runListLabels(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	provider, err := registry.GetProvider(name)
+	if err != nil {
+		return fmt.Errorf("provider not found: %w", err)
+	}
+	project, _ := cmd.Flags().GetString("project")
+	if !cmd.Flags().Changed("project") {
+		project = registry.DefaultProject(name)
+	}
+
+	ctx, cancel := CommandContext(cmd, registry, name, defaultCommandTimeout)
+	defer cancel()
+
+	labels, err := providers.ListLabels(ctx, provider, project)
+	if err != nil {
+		return err
+	}
+	sort.Slice(labels, func(i, j int) bool { return strings.ToLower(labels[i].Name) < strings.ToLower(labels[j].Name) })
+
+	switch OutputFormat(cmd) {
+	case "json":
+		return outputJSON(labels)
+	case "yaml":
+		return outputYAML(labels)
+	default:
+		if len(labels) == 0 {
+			fmt.Println("No labels found")
+			return nil
+		}
+		fmt.Printf("%-30s %-15s %s\n", "LABEL", "ID", "COLOR")
+		for _, label := range labels {
+			fmt.Printf("%-30s %-15s %s\n", label.Name, label.ID, label.Color)
+		}
+		return nil
+	}
+}
+
+func runTestRouting(cmd *cobra.Command, args []string) error {
+	taskType, _ := cmd.Flags().GetString("type")
+	priority, _ := cmd.Flags().GetString("priority")
+	labels, _ := cmd.Flags().GetStringSlice("labels")
+	fields, _ := cmd.Flags().GetStringToString("field")
+
+	task := &providers.UniversalTask{
+		Type:     providers.TaskType(strings.ToLower(taskType)),
+		Priority: providers.TaskPriority(strings.ToLower(priority)),
+		Labels:   labels,
+	}
+	task.Title, _ = cmd.Flags().GetString("title")
+	task.Description, _ = cmd.Flags().GetString("description")
+	task.ProjectID, _ = cmd.Flags().GetString("project")
+	task.AssigneeID, _ = cmd.Flags().GetString("assignee")
+	if !task.Type.IsValid() {
+		return providers.NewValidationError(fmt.Sprintf("unknown task type %q", taskType), nil)
+	}
+	if !task.Priority.IsValid() {
+		return providers.NewValidationError(fmt.Sprintf("unknown priority %q", priority), nil)
+	}
+	if len(fields) > 0 {
+		task.CustomFields = make(map[string]interface{}, len(fields))
+		for name, value := range fields {
+			task.CustomFields[name] = value
+		}
+	}
+
+	decision := registry.RouteTask(task)
+	switch OutputFormat(cmd) {
+	case "json":
+		return outputJSON(decision)
+	case "yaml":
+		return outputYAML(decision)
+	}
+
+	if decision.Provider == "" {
+		fmt.Printf("❌ No provider: %s\n", decision.Reason)
+	} else {
+		fmt.Printf("➡️  %s: %s\n", decision.Provider, decision.Reason)
+	}
+	if len(decision.Rules) == 0 {
+		fmt.Println("\nNo routing rules are configured")
+		return nil
+	}
+
+	fmt.Printf("\n%-25s %-8s %-20s %s\n", "RULE", "PRIORITY", "PROVIDER", "RESULT")
+	for _, rule := range decision.Rules {
+		var result string
+		switch {
+		case rule.Selected:
+			result = "✅ selected"
+		case rule.Matched:
+			result = "⏭️  matched, skipped: " + rule.Skipped
+		case rule.Skipped != "":
+			result = "⏭️  skipped: " + rule.Skipped + "; " + strings.Join(rule.Mismatches, "; ")
+		default:
+			result = "❌ " + strings.Join(rule.Mismatches, "; ")
+		}
+		fmt.Printf("%-25s %-8d %-20s %s\n", rule.Name, rule.Priority, rule.Provider, result)
+	}
+	return nil
 }
 
 func runSetDefault(cmd *cobra.Command, args []string) error {
@@ -313,7 +695,7 @@ func runSetDefault(cmd *cobra.Command, args []string) error {
 	}
 
 	if len(args) == 0 {
-		return fmt.Errorf("provider name is required")
+		return providers.NewValidationError("provider name is required", nil)
 	}
 
 	name := args[0]
@@ -332,7 +714,7 @@ func loadMultiProviderConfig() *providers.MultiProviderConfig {
 	// Try to load from config file
 	configFile := viper.GetString("config")
 	if configFile == "" {
-		configFile = "ricochet.yaml"
+		configFile = appconfig.ProviderConfigPath()
 	}
 
 	if _, err := os.Stat(configFile); err == nil {
@@ -362,16 +744,7 @@ func loadProviderConfigFromFile(filename string) (*providers.ProviderConfig, err
 }
 
 func createProviderConfigFromFlags(name, providerType, baseURL, token, apiKey, username, password string, enable bool) *providers.ProviderConfig {
-	var config *providers.ProviderConfig
-
-	switch providers.ProviderType(providerType) {
-	case providers.ProviderTypeYouTrack:
-		config = youtrack.GetDefaultConfig()
-	default:
-		config = providers.DefaultProviderConfig()
-		config.Type = providers.ProviderType(providerType)
-	}
-
+	config := providers.NewProviderConfig(providers.ProviderType(providerType))
 	config.Name = name
 	config.Enabled = enable
 
@@ -408,8 +781,8 @@ func outputYAML(data interface{}) error {
 }
 
 func outputTable(providerInfos map[string]*providers.ProviderInfo) error {
-	fmt.Printf("%-20s %-12s %-10s %-15s %-30s\n", "NAME", "TYPE", "STATUS", "HEALTH", "CAPABILITIES")
-	fmt.Printf("%-20s %-12s %-10s %-15s %-30s\n", "----", "----", "------", "------", "------------")
+	fmt.Printf("%-20s %-12s %-10s %-15s %-22s %-30s\n", "NAME", "TYPE", "STATUS", "HEALTH", "API", "CAPABILITIES")
+	fmt.Printf("%-20s %-12s %-10s %-15s %-22s %-30s\n", "----", "----", "------", "------", "---", "------------")
 
 	for name, info := range providerInfos {
 		capabilities := strings.Join(getCapabilityNames(info.Capabilities), ", ")
@@ -417,11 +790,17 @@ func outputTable(providerInfos map[string]*providers.ProviderInfo) error {
 			capabilities = capabilities[:25] + "..."
 		}
 
-		fmt.Printf("%-20s %-12s %-10s %-15s %-30s\n",
+		apiVersion := info.APIVersion
+		if apiVersion == "" {
+			apiVersion = "-"
+		}
+
+		fmt.Printf("%-20s %-12s %-10s %-15s %-22s %-30s\n",
 			name,
-			string(getProviderType(info.Name)),
+			string(info.Type),
 			"enabled", // We'd need to track this from registry
 			string(info.HealthStatus),
+			apiVersion,
 			capabilities,
 		)
 	}
@@ -437,34 +816,17 @@ func getCapabilityNames(capabilities []providers.Capability) []string {
 	return names
 }
 
-func getProviderType(name string) providers.ProviderType {
-	// This is a simplified mapping - in practice you'd get this from the registry
-	if strings.Contains(strings.ToLower(name), "youtrack") {
-		return providers.ProviderTypeYouTrack
-	}
-	if strings.Contains(strings.ToLower(name), "jira") {
-		return providers.ProviderTypeJira
-	}
-	if strings.Contains(strings.ToLower(name), "notion") {
-		return providers.ProviderTypeNotion
-	}
-	return providers.ProviderTypeCustom
-}
-
-func checkProviderHealth(name string, watch bool, interval time.Duration) error {
+func checkProviderHealth(name string, watch bool, interval time.Duration, options providers.HealthCheckOptions) error {
 	for {
-		provider, err := registry.GetProvider(name)
-		if err != nil {
+		if _, err := registry.GetProvider(name); err != nil {
 			return fmt.Errorf("provider not found: %w", err)
 		}
 
-		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-		err = provider.HealthCheck(ctx)
-		cancel()
+		result := registry.CheckHealth(context.Background(), []string{name}, options)[0]
 
-		status := "🟢 HEALTHY"
-		if err != nil {
-			status = fmt.Sprintf("🔴 UNHEALTHY: %v", err)
+		status := fmt.Sprintf("%s %s (%s)", healthEmoji(result.Status), strings.ToUpper(string(result.Status)), formatLatency(result.Latency))
+		if result.Error != "" {
+			status += ": " + result.Error
 		}
 
 		fmt.Printf("[%s] %s: %s\n", time.Now().Format("15:04:05"), name, status)
@@ -479,21 +841,19 @@ func checkProviderHealth(name string, watch bool, interval time.Duration) error
 	return nil
 }
 
-func checkAllProvidersHealth(watch bool, interval time.Duration) error {
+func checkAllProvidersHealth(watch bool, interval time.Duration, options providers.HealthCheckOptions) error {
 	for {
-		healthStatus := registry.GetHealthStatus()
+		results := registry.CheckHealth(context.Background(), nil, options)
 
 		fmt.Printf("\n[%s] Provider Health Status:\n", time.Now().Format("15:04:05"))
-		fmt.Printf("%-20s %-15s\n", "PROVIDER", "STATUS")
-		fmt.Printf("%-20s %-15s\n", "--------", "------")
+		fmt.Printf("%-20s %-15s %-10s\n", "PROVIDER", "STATUS", "LATENCY")
+		fmt.Printf("%-20s %-15s %-10s\n", "--------", "------", "-------")
 
-		for name, status := range healthStatus {
-			emoji := "🟢"
-			if status != providers.HealthStatusHealthy {
-				emoji = "🔴"
+		for _, result := range results {
+			fmt.Printf("%-20s %s %-12s %-10s\n", result.Provider, healthEmoji(result.Status), string(result.Status), formatLatency(result.Latency))
+			if result.Error != "" {
+				fmt.Printf("%-20s   %s\n", "", result.Error)
 			}
-
-			fmt.Printf("%-20s %s %-15s\n", name, emoji, string(status))
 		}
 
 		if !watch {
@@ -504,4 +864,22 @@ func checkAllProvidersHealth(watch bool, interval time.Duration) error {
 	}
 
 	return nil
-}
\ No newline at end of file
+}
+
+func healthEmoji(status providers.ProviderHealthStatus) string {
+	switch status {
+	case providers.HealthStatusHealthy:
+		return "🟢"
+	case providers.HealthStatusDegraded:
+		return "🟡"
+	default:
+		return "🔴"
+	}
+}
+
+func formatLatency(latency time.Duration) string {
+	if latency < time.Millisecond {
+		return latency.Round(time.Microsecond).String()
+	}
+	return latency.Round(time.Millisecond).String()
+}
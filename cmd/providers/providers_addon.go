@@ -1,8 +1,71 @@
 package providers
 
-import "github.com/grik-ai/ricochet-task/pkg/providers"
+import (
+	"fmt"
+	"os"
 
-// GetRegistry возвращает текущий реестр провайдеров
+	appconfig "github.com/grik-ai/ricochet-task/internal/config"
+	"github.com/grik-ai/ricochet-task/pkg/providers"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// GetRegistry возвращает реестр провайдеров, если он уже инициализирован
+// через Registry, иначе nil
 func GetRegistry() *providers.ProviderRegistry {
+	registryMu.Lock()
+	defer registryMu.Unlock()
 	return registry
-}
\ No newline at end of file
+}
+
+// DefaultOutputFormat возвращает формат вывода по умолчанию из конфигурации,
+// загружая её, если реестр провайдеров ещё не инициализирован
+func DefaultOutputFormat() string {
+	if registry := GetRegistry(); registry != nil {
+		return registry.DefaultOutputFormat()
+	}
+	return loadMultiProviderConfig().DefaultOutputFormat
+}
+
+// OutputFormat возвращает формат вывода команды: флаг --output, если он
+// задан, иначе $RICOCHET_OUTPUT или формат по умолчанию из конфигурации.
+// О неверном значении предупреждает и выводит таблицу.
+func OutputFormat(cmd *cobra.Command) string {
+	flag, _ := cmd.Flags().GetString("output")
+	format, err := providers.ResolveOutputFormat(flag, cmd.Flags().Changed("output"), DefaultOutputFormat())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v, using %s output\n", err, format)
+	}
+	return format
+}
+
+// LoadConfigFile читает файл конфигурации провайдеров так же, как команды
+// провайдеров, но возвращает ошибку чтения вместо конфигурации по умолчанию
+func LoadConfigFile() (string, *providers.MultiProviderConfig, error) {
+	configFile := providerConfigFile()
+
+	if _, err := os.Stat(configFile); err != nil {
+		return configFile, providers.DefaultMultiProviderConfig(), err
+	}
+
+	config := providers.DefaultMultiProviderConfig()
+	v := viper.New()
+	v.SetConfigFile(configFile)
+	if err := v.ReadInConfig(); err != nil {
+		return configFile, config, err
+	}
+	if err := v.Unmarshal(config); err != nil {
+		return configFile, config, err
+	}
+
+	return configFile, config, nil
+}
+
+// providerConfigFile возвращает файл конфигурации провайдеров: --config или
+// файл активного профиля
+func providerConfigFile() string {
+	if configFile := viper.GetString("config"); configFile != "" {
+		return configFile
+	}
+	return appconfig.ProviderConfigPath()
+}
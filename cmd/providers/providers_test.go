@@ -0,0 +1,187 @@
+package providers
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	appconfig "github.com/grik-ai/ricochet-task/internal/config"
+	pkgproviders "github.com/grik-ai/ricochet-task/pkg/providers"
+	"github.com/grik-ai/ricochet-task/pkg/workflow"
+)
+
+// useRegistryConfig points Registry at configFile inside a temporary home and
+// drops the shared registry when the test ends
+func useRegistryConfig(t *testing.T, configFile string) {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	viper.Set("config", configFile)
+
+	resetRegistry := func() {
+		registryMu.Lock()
+		defer registryMu.Unlock()
+		registry = nil
+	}
+	resetRegistry()
+	t.Cleanup(func() {
+		viper.Set("config", "")
+		resetRegistry()
+	})
+}
+
+func TestRegistry(t *testing.T) {
+	t.Run("Concurrent callers share one registry", func(t *testing.T) {
+		useRegistryConfig(t, filepath.Join(t.TempDir(), "providers.yaml"))
+
+		const callers = 8
+		results := make([]*pkgproviders.ProviderRegistry, callers)
+		errs := make([]error, callers)
+
+		var wg sync.WaitGroup
+		for i := 0; i < callers; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				results[i], errs[i] = Registry()
+			}(i)
+		}
+		wg.Wait()
+
+		for i := 0; i < callers; i++ {
+			require.NoError(t, errs[i])
+			require.NotNil(t, results[i])
+			assert.Same(t, results[0], results[i])
+		}
+		assert.Same(t, results[0], GetRegistry())
+	})
+
+	t.Run("A failed initialization is retried", func(t *testing.T) {
+		configFile := filepath.Join(t.TempDir(), "providers.yaml")
+		config := "providers:\n  broken:\n    name: broken\n    type: unknown-provider-type\n    enabled: true\n"
+		require.NoError(t, os.WriteFile(configFile, []byte(config), 0644))
+		useRegistryConfig(t, configFile)
+
+		registry, err := Registry()
+		require.Error(t, err)
+		assert.Nil(t, registry)
+		assert.Nil(t, GetRegistry())
+
+		// After the configuration is fixed the next call succeeds
+		require.NoError(t, os.Remove(configFile))
+
+		registry, err = Registry()
+		require.NoError(t, err)
+		require.NotNil(t, registry)
+		assert.Same(t, registry, GetRegistry())
+	})
+}
+
+// captureChannel records the notifications sent through it
+type captureChannel struct {
+	mu   sync.Mutex
+	sent []*workflow.Notification
+}
+
+func (c *captureChannel) GetType() string { return "capture" }
+
+func (c *captureChannel) Send(ctx context.Context, notification *workflow.Notification) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.sent = append(c.sent, notification)
+	return nil
+}
+
+func TestRegistryNotifiesTaskWatchers(t *testing.T) {
+	useRegistryConfig(t, filepath.Join(t.TempDir(), "providers.yaml"))
+
+	registry, err := Registry()
+	require.NoError(t, err)
+	bus := registry.EventBus()
+	require.NotNil(t, bus)
+
+	engine := Notifications()
+	engine.SetDigestAggregator(nil)
+	engine.SetFailedQueue(nil)
+	engine.SetDeliveryConfig(&workflow.DeliveryConfig{WatchChannels: []string{"capture"}})
+	capture := &captureChannel{}
+	engine.RegisterChannel(capture)
+
+	// The watch is added after the registry started, as by another command
+	watchers, err := pkgproviders.NewTaskWatchRegistry(appconfig.ProfilePath(pkgproviders.WatchersFile), nil)
+	require.NoError(t, err)
+	require.NoError(t, watchers.WatchTask(context.Background(), "OPS-1", "alice"))
+
+	bus.Publish(&pkgproviders.UniversalEvent{
+		Type:   pkgproviders.EventTypeTaskUpdated,
+		Source: "tracker",
+		TaskID: "OPS-1",
+		Data:   map[string]interface{}{"actor": "bob"},
+	})
+	require.NoError(t, bus.Close(context.Background()))
+
+	require.Len(t, capture.sent, 1)
+	assert.Equal(t, []string{"alice"}, capture.sent[0].Recipients)
+}
+
+func TestCommandContext(t *testing.T) {
+	config := pkgproviders.DefaultMultiProviderConfig()
+	config.DefaultProvider = "slow"
+	config.Providers["slow"] = &pkgproviders.ProviderConfig{Name: "slow", Timeout: 5 * time.Minute}
+	registry := pkgproviders.NewProviderRegistry(config, nil)
+
+	deadline := func(ctx context.Context) time.Duration {
+		when, ok := ctx.Deadline()
+		if !ok {
+			return 0
+		}
+		return time.Until(when).Round(time.Minute)
+	}
+
+	t.Run("Provider timeout raises the fallback", func(t *testing.T) {
+		ctx, cancel := CommandContext(labelsCmd, registry, "", defaultCommandTimeout)
+		defer cancel()
+		assert.Equal(t, 5*time.Minute, deadline(ctx))
+	})
+
+	t.Run("Timeout flag wins", func(t *testing.T) {
+		require.NoError(t, ProvidersCmd.PersistentFlags().Set("timeout", "20m"))
+		t.Cleanup(func() { ProvidersCmd.PersistentFlags().Set("timeout", "0s") })
+
+		assert.Equal(t, 20*time.Minute, CommandTimeout(labelsCmd))
+		ctx, cancel := CommandContext(labelsCmd, registry, "slow", defaultCommandTimeout)
+		defer cancel()
+		assert.Equal(t, 20*time.Minute, deadline(ctx))
+	})
+
+	t.Run("Local timeout of health is not the command deadline", func(t *testing.T) {
+		require.NoError(t, healthCmd.Flags().Set("timeout", "1s"))
+		t.Cleanup(func() { healthCmd.Flags().Set("timeout", pkgproviders.DefaultHealthCheckTimeout.String()) })
+
+		assert.Zero(t, CommandTimeout(healthCmd))
+	})
+}
+
+func TestOutputFormat(t *testing.T) {
+	useRegistryConfig(t, filepath.Join(t.TempDir(), "providers.yaml"))
+	flags := listCmd.Flags()
+	t.Cleanup(func() {
+		flags.Set("output", "table")
+		flags.Lookup("output").Changed = false
+	})
+
+	t.Setenv(pkgproviders.OutputFormatEnv, "yaml")
+	assert.Equal(t, "yaml", OutputFormat(listCmd))
+
+	t.Setenv(pkgproviders.OutputFormatEnv, "xml")
+	assert.Equal(t, "table", OutputFormat(listCmd))
+
+	require.NoError(t, flags.Set("output", "json"))
+	assert.Equal(t, "json", OutputFormat(listCmd))
+}
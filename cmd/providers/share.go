@@ -0,0 +1,333 @@
+package providers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"github.com/grik-ai/ricochet-task/pkg/providers"
+	"github.com/grik-ai/ricochet-task/pkg/ui"
+)
+
+// providerConfigExport is the file providers export writes and providers
+// import reads: the providers section of the config file and nothing else
+type providerConfigExport struct {
+	Providers map[string]*providers.ProviderConfig `json:"providers" yaml:"providers"`
+}
+
+var exportCmd = &cobra.Command{
+	Use:   "export [name...]",
+	Short: "Export provider configs to share them",
+	Long: `Write the configs of the given providers, or of all of them, as a file that
+'ricochet providers import' merges into another config. Only provider configs
+are exported: URLs, types, projects, mappings and settings.
+
+With --redact-secrets the API keys, tokens, passwords and credential-like
+settings are left out, so the file can be committed or sent to the team;
+import asks for them. The file is YAML unless --out ends with .json; without
+--out it is printed.
+
+Examples:
+  ricochet providers export --redact-secrets --out providers.yaml
+  ricochet providers export work --redact-secrets`,
+	// Provider configs are read from the config file and don't need provider connections
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {},
+	RunE:             runExportProviders,
+}
+
+var importCmd = &cobra.Command{
+	Use:   "import [file]",
+	Short: "Merge shared provider configs into the config",
+	Long: `Merge the provider configs of a file written by 'ricochet providers export'
+into the config file. New providers are added; for existing ones the settings
+of the file replace the local ones, while credentials the file doesn't have,
+and settings it doesn't mention, are kept.
+
+Missing credentials are asked for when running in a terminal. Otherwise, or
+when left empty, the provider is imported disabled; set them in the config
+file and enable it there.
+
+Examples:
+  ricochet providers import providers.yaml
+  ricochet --profile work providers import providers.yaml`,
+	Args: cobra.ExactArgs(1),
+	// Provider configs are written to the config file and don't need provider connections
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {},
+	RunE:             runImportProviders,
+}
+
+func init() {
+	ProvidersCmd.AddCommand(exportCmd)
+	ProvidersCmd.AddCommand(importCmd)
+
+	exportCmd.Flags().Bool("redact-secrets", false, "Leave out API keys, tokens, passwords and credential-like settings")
+	exportCmd.Flags().String("out", "", "File to write, YAML or .json (defaults to stdout)")
+	exportCmd.ValidArgsFunction = CompleteProviderNames
+}
+
+func runExportProviders(cmd *cobra.Command, args []string) error {
+	redact, _ := cmd.Flags().GetBool("redact-secrets")
+	out, _ := cmd.Flags().GetString("out")
+
+	configFile, config, err := LoadConfigFile()
+	if err != nil {
+		return fmt.Errorf("failed to read provider config %s: %w", configFile, err)
+	}
+
+	names := args
+	if len(names) == 0 {
+		for name := range config.Providers {
+			names = append(names, name)
+		}
+	}
+
+	export := providerConfigExport{Providers: make(map[string]*providers.ProviderConfig, len(names))}
+	for _, name := range names {
+		providerConfig, ok := config.Providers[name]
+		if !ok {
+			return providers.NewProviderError(providers.ErrorTypeNotFound, fmt.Sprintf("provider not found: %s", name), nil)
+		}
+		if redact {
+			providerConfig = providers.RedactSecrets(providerConfig)
+		}
+		export.Providers[name] = providerConfig
+	}
+
+	if out == "" {
+		return outputYAML(export)
+	}
+
+	data, err := encodeConfig(out, export)
+	if err != nil {
+		return fmt.Errorf("failed to encode provider configs: %w", err)
+	}
+
+	// Unredacted exports carry credentials
+	mode := os.FileMode(0600)
+	if redact {
+		mode = 0644
+	}
+	if err := os.WriteFile(out, data, mode); err != nil {
+		return fmt.Errorf("failed to write %s: %w", out, err)
+	}
+
+	fmt.Printf("✅ Exported %d provider(s) to %s\n", len(export.Providers), out)
+	if !redact {
+		fmt.Println("⚠️  The file contains credentials; use --redact-secrets to share it")
+	}
+	return nil
+}
+
+func runImportProviders(cmd *cobra.Command, args []string) error {
+	imported, err := readProviderExport(args[0])
+	if err != nil {
+		return err
+	}
+
+	configFile := providerConfigFile()
+	document, err := readConfigDocument(configFile)
+	if err != nil {
+		return err
+	}
+
+	configured, _ := document["providers"].(map[string]interface{})
+	if configured == nil {
+		configured = make(map[string]interface{})
+		document["providers"] = configured
+	}
+
+	names := make([]string, 0, len(imported))
+	for name := range imported {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	interactive := ui.IsInteractive()
+	var disabled []string
+	for _, name := range names {
+		existing, exists := configured[name].(map[string]interface{})
+
+		// The file's settings replace the local ones key by key, so local
+		// credentials survive a redacted import
+		merged := make(map[string]interface{}, len(existing)+len(imported[name]))
+		for key, value := range existing {
+			merged[key] = value
+		}
+		for key, value := range imported[name] {
+			merged[key] = value
+		}
+		if _, ok := merged["name"]; !ok {
+			merged["name"] = name
+		}
+
+		providerConfig, err := decodeProviderConfig(merged)
+		if err != nil {
+			return providers.NewProviderError(providers.ErrorTypeValidation, fmt.Sprintf("invalid config of provider %s", name), err)
+		}
+
+		if missing := providerConfig.MissingSecrets(); len(missing) > 0 && providerConfig.Enabled {
+			if interactive {
+				missing, err = promptSecrets(name, missing, merged)
+				if err != nil {
+					return err
+				}
+			}
+			if len(missing) > 0 {
+				merged["enabled"] = false
+				disabled = append(disabled, fmt.Sprintf("%s (missing %s)", name, strings.Join(missing, ", ")))
+			}
+		}
+
+		configured[name] = merged
+		if exists {
+			fmt.Printf("✅ Provider '%s' updated\n", name)
+		} else {
+			fmt.Printf("✅ Provider '%s' added\n", name)
+		}
+	}
+
+	if err := writeConfigDocument(configFile, document); err != nil {
+		return err
+	}
+	fmt.Printf("\nImported %d provider(s) into %s\n", len(names), configFile)
+
+	if len(disabled) > 0 {
+		fmt.Printf("\n⚠️  Imported disabled, credentials needed:\n")
+		for _, entry := range disabled {
+			fmt.Printf("  - %s\n", entry)
+		}
+		fmt.Printf("Set them in %s and enable the providers there, or run the import again in a terminal\n", configFile)
+	}
+	return nil
+}
+
+// readProviderExport reads the providers of an export file as raw maps, so
+// that only the keys the file sets are merged
+func readProviderExport(filename string) (map[string]map[string]interface{}, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", filename, err)
+	}
+
+	// JSON is valid YAML
+	var export struct {
+		Providers map[string]map[string]interface{} `yaml:"providers"`
+	}
+	if err := yaml.Unmarshal(data, &export); err != nil {
+		return nil, providers.NewProviderError(providers.ErrorTypeValidation, fmt.Sprintf("failed to parse %s", filename), err)
+	}
+	if len(export.Providers) == 0 {
+		return nil, providers.NewValidationError(fmt.Sprintf("%s has no providers section", filename), nil)
+	}
+
+	for name, config := range export.Providers {
+		if config == nil {
+			return nil, providers.NewValidationError(fmt.Sprintf("provider %s in %s has no config", name, filename), nil)
+		}
+	}
+	return export.Providers, nil
+}
+
+// readConfigDocument reads the whole config file, keeping the sections and
+// keys the provider config doesn't know. A missing file is an empty document.
+func readConfigDocument(configFile string) (map[string]interface{}, error) {
+	document := make(map[string]interface{})
+
+	data, err := os.ReadFile(configFile)
+	if os.IsNotExist(err) {
+		return document, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read provider config %s: %w", configFile, err)
+	}
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return nil, fmt.Errorf("failed to parse provider config %s: %w", configFile, err)
+	}
+	if document == nil {
+		document = make(map[string]interface{})
+	}
+	return document, nil
+}
+
+func writeConfigDocument(configFile string, document map[string]interface{}) error {
+	data, err := encodeConfig(configFile, document)
+	if err != nil {
+		return fmt.Errorf("failed to encode provider config: %w", err)
+	}
+
+	if dir := filepath.Dir(configFile); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create config directory: %w", err)
+		}
+	}
+	// The config holds credentials; an existing file keeps its permissions
+	if err := os.WriteFile(configFile, data, 0600); err != nil {
+		return fmt.Errorf("failed to write provider config %s: %w", configFile, err)
+	}
+	return nil
+}
+
+// encodeConfig encodes data as JSON for .json files and as YAML otherwise
+func encodeConfig(filename string, data interface{}) ([]byte, error) {
+	if strings.EqualFold(filepath.Ext(filename), ".json") {
+		return json.MarshalIndent(data, "", "  ")
+	}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(data); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// decodeProviderConfig reads a raw provider config the way the config file is read
+func decodeProviderConfig(raw map[string]interface{}) (*providers.ProviderConfig, error) {
+	data, err := yaml.Marshal(raw)
+	if err != nil {
+		return nil, err
+	}
+	var config providers.ProviderConfig
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+// promptSecrets asks for the missing credentials of a provider and sets the
+// ones given in config. It returns those still missing.
+func promptSecrets(name string, missing []string, config map[string]interface{}) ([]string, error) {
+	fmt.Printf("🔑 Provider '%s' needs credentials (leave empty to import it disabled)\n", name)
+
+	var stillMissing []string
+	for _, key := range missing {
+		var value string
+		var err error
+		if key == "username" {
+			value, err = ui.InputPrompt(fmt.Sprintf("%s %s:", name, key))
+		} else {
+			value, err = ui.PasswordPrompt(fmt.Sprintf("%s %s:", name, key))
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s of provider %s: %w", key, name, err)
+		}
+
+		if value = strings.TrimSpace(value); value == "" {
+			stillMissing = append(stillMissing, key)
+			continue
+		}
+		config[key] = value
+	}
+	return stillMissing, nil
+}
@@ -1,13 +1,24 @@
 package chain
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/grik-ai/ricochet-task/internal/config"
 	"github.com/grik-ai/ricochet-task/pkg/chain"
+	"github.com/grik-ai/ricochet-task/pkg/doctor"
+	"github.com/grik-ai/ricochet-task/pkg/key"
+	"github.com/grik-ai/ricochet-task/pkg/orchestrator"
+	"github.com/grik-ai/ricochet-task/pkg/segmentation"
+	"github.com/grik-ai/ricochet-task/pkg/task"
+	"github.com/grik-ai/ricochet-task/pkg/ui"
+	"github.com/grik-ai/ricochet-task/pkg/workflow"
 	"github.com/spf13/cobra"
 )
 
@@ -24,8 +35,14 @@ func init() {
 	ChainCmd.AddCommand(listCmd)
 	ChainCmd.AddCommand(addModelCmd)
 	ChainCmd.AddCommand(runCmd)
+	ChainCmd.AddCommand(estimateCmd)
 	ChainCmd.AddCommand(statusCmd)
 	ChainCmd.AddCommand(deleteCmd)
+	ChainCmd.AddCommand(runsCmd)
+	ChainCmd.AddCommand(runStatusCmd)
+	ChainCmd.AddCommand(runResultsCmd)
+	ChainCmd.AddCommand(exportCmd)
+	ChainCmd.AddCommand(importCmd)
 }
 
 // Команда chain create
@@ -141,7 +158,11 @@ var listCmd = &cobra.Command{
 var addModelCmd = &cobra.Command{
 	Use:   "add-model",
 	Short: "Добавить модель в цепочку",
-	Long:  `Добавление новой модели в существующую цепочку с указанной ролью и параметрами.`,
+	Long: `Добавление новой модели в существующую цепочку с указанной ролью и параметрами.
+
+--fallback задает запасные модели шага: если основная модель недоступна из-за
+временного сбоя (превышение лимитов, ошибка 5xx, сетевая ошибка), шаг выполняется
+запасными моделями по порядку. Модель, выполнившая шаг, записывается в результат.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		chainID, _ := cmd.Flags().GetString("chain")
 		name, _ := cmd.Flags().GetString("name")
@@ -150,6 +171,8 @@ var addModelCmd = &cobra.Command{
 		prompt, _ := cmd.Flags().GetString("prompt")
 		temperature, _ := cmd.Flags().GetFloat64("temperature")
 		maxTokens, _ := cmd.Flags().GetInt("max-tokens")
+		fallbacks, _ := cmd.Flags().GetStringSlice("fallback")
+		outputSchemaFile, _ := cmd.Flags().GetString("output-schema")
 
 		if chainID == "" {
 			fmt.Println("Ошибка: ID цепочки не указан")
@@ -253,6 +276,30 @@ var addModelCmd = &cobra.Command{
 			Temperature: temperature,
 		}
 
+		// Проверка запасных моделей
+		for _, fallback := range fallbacks {
+			if _, err := model.WithFallback(chain.ModelName(fallback)); err != nil {
+				fmt.Printf("Ошибка: неизвестная запасная модель '%s'\n", fallback)
+				os.Exit(1)
+			}
+			model.FallbackModels = append(model.FallbackModels, chain.ModelName(fallback))
+		}
+
+		// Схема результата для шага извлечения структурированных данных
+		if outputSchemaFile != "" {
+			data, err := os.ReadFile(outputSchemaFile)
+			if err != nil {
+				fmt.Printf("Ошибка чтения схемы результата: %v\n", err)
+				os.Exit(1)
+			}
+			schema, err := chain.ParseOutputSchema(string(data))
+			if err != nil {
+				fmt.Printf("Ошибка: %v\n", err)
+				os.Exit(1)
+			}
+			model.OutputSchema = schema
+		}
+
 		// Добавление модели в цепочку
 		c.Models = append(c.Models, model)
 		c.UpdatedAt = time.Now()
@@ -272,12 +319,47 @@ var addModelCmd = &cobra.Command{
 var runCmd = &cobra.Command{
 	Use:   "run",
 	Short: "Запустить цепочку моделей",
-	Long:  `Запуск цепочки моделей с указанным входным текстом или файлом.`,
+	Long: `Запуск цепочки моделей с указанным входным текстом или файлом.
+
+Большие входные данные разбиваются на сегменты размером до --segment-tokens токенов.
+--segment-overlap добавляет к каждому сегменту, кроме первого, указанное количество
+токенов предшествующего текста как контекст: это сохраняет связность на стыках,
+но увеличивает входные токены каждой модели примерно на overlap * (сегментов - 1).
+
+Результаты сегментов объединяются по --aggregation: concatenate склеивает их по
+порядку, summarize сворачивает итоговым запросом к модели (map-reduce),
+merge объединяет результаты без повторяющихся абзацев.
+
+С флагом --stream цепочка выполняется сразу, а ответы моделей выводятся по мере
+генерации. Выход каждой модели передается на вход следующей; сегменты
+обрабатываются по очереди, их результаты склеиваются (только concatenate).
+
+Без --chain, --input и --input-file в терминале запускается пошаговый мастер:
+выбор цепочки, файла или вставленного текста, температуры и сегментации.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		chainID, _ := cmd.Flags().GetString("chain")
 		input, _ := cmd.Flags().GetString("input")
 		inputFile, _ := cmd.Flags().GetString("input-file")
 
+		options := orchestrator.DefaultProcessingOptions()
+		options.MaxTokensPerChunk, _ = cmd.Flags().GetInt("segment-tokens")
+		options.SegmentOverlap, _ = cmd.Flags().GetInt("segment-overlap")
+		options.SegmentationMethod, _ = cmd.Flags().GetString("segment-strategy")
+		options.AggregationStrategy, _ = cmd.Flags().GetString("aggregation")
+		if err := options.Validate(); err != nil {
+			fmt.Printf("Ошибка: некорректные параметры сегментации: %v\n", err)
+			os.Exit(1)
+		}
+
+		// Без параметров в терминале запускается пошаговый мастер
+		if chainID == "" && input == "" && inputFile == "" && ui.IsInteractive() {
+			if err := ui.RunChainWizard(); err != nil {
+				fmt.Printf("Ошибка: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		}
+
 		if chainID == "" {
 			fmt.Println("Ошибка: ID цепочки не указан")
 			os.Exit(1)
@@ -330,6 +412,14 @@ var runCmd = &cobra.Command{
 			os.Exit(1)
 		}
 
+		if stream, _ := cmd.Flags().GetBool("stream"); stream {
+			if err := runChainStreaming(c, input, options, cfg.ConfigDir); err != nil {
+				fmt.Printf("\nОшибка выполнения цепочки: %v\n", err)
+				os.Exit(1)
+			}
+			return
+		}
+
 		// TODO: Реализовать запуск цепочки с использованием Ricochet Service
 		// В данной реализации просто выводим информацию о запуске
 		fmt.Printf("Запущена цепочка '%s' с %d моделями.\n", c.Name, len(c.Models))
@@ -341,12 +431,182 @@ var runCmd = &cobra.Command{
 			}
 			fmt.Printf("Входные данные (превью): %s\n", preview)
 		}
+		printSegmentationPlan(input, options)
 		fmt.Println("ID запуска: " + uuid.New().String())
 		fmt.Println("Статус: обработка")
 		fmt.Println("Для проверки статуса используйте команду: ricochet chain status --chain " + chainID)
 	},
 }
 
+// printSegmentationPlan выводит, как входные данные будут разбиты на сегменты
+func printSegmentationPlan(input string, options orchestrator.ProcessingOptions) {
+	segments, err := segmentation.SegmentWithInfo(input, options.SegmentationOptions())
+	if err != nil {
+		fmt.Printf("Ошибка при сегментации входных данных: %v\n", err)
+		os.Exit(1)
+	}
+	if len(segments) <= 1 {
+		return
+	}
+
+	fmt.Printf("Сегментация: %d сегментов (метод %s, до %d токенов", len(segments), options.SegmentationMethod, options.MaxTokensPerChunk)
+	if options.SegmentOverlap > 0 {
+		fmt.Printf(", перекрытие %d токенов", options.SegmentOverlap)
+	}
+	fmt.Printf(", объединение %s)\n", options.AggregationStrategy)
+
+	if options.SegmentOverlap > 0 {
+		overlapTokens := 0
+		for _, segment := range segments {
+			overlapTokens += len(segment.Context) / 4 // Примерная оценка: 1 токен ~ 4 символа
+		}
+		fmt.Printf("Перекрытие добавит ~%d входных токенов на каждую модель цепочки\n", overlapTokens)
+	}
+}
+
+// runChainStreaming выполняет цепочку, выводя ответы моделей по мере генерации
+func runChainStreaming(c chain.Chain, input string, options orchestrator.ProcessingOptions, configDir string) error {
+	factory, err := orchestrator.NewModelFactory(configDir)
+	if err != nil {
+		return err
+	}
+
+	// Прерывание по Ctrl+C закрывает поток и останавливает цепочку
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	fmt.Printf("Запуск цепочки '%s' с %d моделями (потоковый вывод)\n", c.Name, len(c.Models))
+	_, err = orchestrator.RunChainStream(ctx, factory, c, input, options, func(event orchestrator.StreamEvent) {
+		switch event.Type {
+		case orchestrator.StreamEventModelStarted:
+			if event.Segments > 1 {
+				fmt.Printf("\n=== Сегмент %d/%d, модель %d/%d: %s ===\n", event.Segment, event.Segments, event.Step, event.Steps, event.Model.Name)
+			} else {
+				fmt.Printf("\n=== Модель %d/%d: %s ===\n", event.Step, event.Steps, event.Model.Name)
+			}
+		case orchestrator.StreamEventFallback:
+			fmt.Printf("\n=== Модель недоступна, ответ дает запасная модель %s ===\n", event.Model.Name)
+		case orchestrator.StreamEventChunk:
+			fmt.Print(event.Chunk)
+		case orchestrator.StreamEventModelFinished:
+			fmt.Println()
+		}
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Println()
+
+	return nil
+}
+
+// Команда chain estimate
+var estimateCmd = &cobra.Command{
+	Use:   "estimate <chainID>",
+	Short: "Оценить стоимость запуска цепочки",
+	Long: `Оценка токенов и стоимости запуска цепочки до ее выполнения.
+
+Входные данные разбиваются на сегменты с теми же параметрами, что и в chain run,
+затем для каждой модели оцениваются входные и выходные токены: выход модели
+становится входом следующей. Длина ответов заранее неизвестна, поэтому стоимость
+выводится диапазоном: от ответов в четверть входа до ответов длиной в max_tokens.
+Цены берутся из встроенной таблицы публичных цен провайдеров.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		input, _ := cmd.Flags().GetString("input")
+		inputFile, _ := cmd.Flags().GetString("input-file")
+
+		options := orchestrator.DefaultProcessingOptions()
+		options.MaxTokensPerChunk, _ = cmd.Flags().GetInt("segment-tokens")
+		options.SegmentOverlap, _ = cmd.Flags().GetInt("segment-overlap")
+		options.SegmentationMethod, _ = cmd.Flags().GetString("segment-strategy")
+		options.AggregationStrategy, _ = cmd.Flags().GetString("aggregation")
+
+		if input == "" && inputFile == "" {
+			fmt.Println("Ошибка: необходимо указать входной текст через --input или путь к файлу через --input-file")
+			os.Exit(1)
+		}
+
+		// Если указан файл, читаем из него
+		if inputFile != "" {
+			data, err := os.ReadFile(inputFile)
+			if err != nil {
+				fmt.Printf("Ошибка при чтении файла: %v\n", err)
+				os.Exit(1)
+			}
+			input = string(data)
+		}
+
+		// Загрузка конфигурации
+		configPath, err := config.GetConfigPath()
+		if err != nil {
+			fmt.Printf("Ошибка при получении пути конфигурации: %v\n", err)
+			os.Exit(1)
+		}
+
+		cfg, err := config.LoadConfig(configPath)
+		if err != nil {
+			fmt.Printf("Ошибка при загрузке конфигурации: %v\n", err)
+			os.Exit(1)
+		}
+
+		chainStore, err := chain.NewFileChainStore(cfg.ConfigDir)
+		if err != nil {
+			fmt.Printf("Ошибка при создании хранилища цепочек: %v\n", err)
+			os.Exit(1)
+		}
+
+		c, err := chainStore.Get(args[0])
+		if err != nil {
+			fmt.Printf("Ошибка при получении цепочки: %v\n", err)
+			os.Exit(1)
+		}
+
+		estimate, err := orchestrator.EstimateChainCost(c, input, options)
+		if err != nil {
+			fmt.Printf("Ошибка при оценке стоимости: %v\n", err)
+			os.Exit(1)
+		}
+
+		printCostEstimate(c, estimate)
+	},
+}
+
+// printCostEstimate выводит оценку стоимости по шагам цепочки
+func printCostEstimate(c chain.Chain, estimate *orchestrator.CostEstimate) {
+	fmt.Printf("Оценка стоимости цепочки '%s'\n", c.Name)
+	fmt.Printf("Входные данные: ~%d токенов\n", estimate.InputTokens)
+	fmt.Println("----------------------------------------------------")
+	for i, step := range estimate.Steps {
+		fmt.Printf("%d. %s", i+1, step.Model)
+		if step.Segments > 1 {
+			fmt.Printf(" (%d сегментов)", step.Segments)
+		}
+		fmt.Println()
+		fmt.Printf("   Вход: %s токенов, выход: %s токенов\n",
+			formatTokenRange(step.InputTokens, step.MaxInputTokens),
+			formatTokenRange(step.MinOutputTokens, step.MaxOutputTokens))
+		if step.Priced {
+			fmt.Printf("   Стоимость: $%.4f - $%.4f\n", step.MinCost, step.MaxCost)
+		} else {
+			fmt.Println("   Стоимость: неизвестна (нет цен для модели)")
+		}
+	}
+	fmt.Println("----------------------------------------------------")
+	fmt.Printf("Итого: $%.2f - $%.2f\n", estimate.MinCost, estimate.MaxCost)
+	if len(estimate.Unpriced) > 0 {
+		fmt.Printf("Внимание: стоимость моделей без цен не учтена: %v\n", estimate.Unpriced)
+	}
+}
+
+// formatTokenRange форматирует диапазон токенов, сворачивая совпадающие границы
+func formatTokenRange(low, high int) string {
+	if low == high {
+		return fmt.Sprintf("~%d", low)
+	}
+	return fmt.Sprintf("~%d-%d", low, high)
+}
+
 // Команда chain status
 var statusCmd = &cobra.Command{
 	Use:   "status",
@@ -449,6 +709,346 @@ var deleteCmd = &cobra.Command{
 	},
 }
 
+// Команда chain runs
+var runsCmd = &cobra.Command{
+	Use:   "runs",
+	Short: "История запусков цепочек",
+	Long: `Отображение сохраненных запусков цепочек с фильтрацией по цепочке, статусу и дате начала.
+По умолчанию новые запуски выводятся первыми, по 20 на страницу.
+
+Примеры:
+  ricochet chain runs --chain <chainID> --status failed
+  ricochet chain runs --since 2024-05-01 --until 2024-05-07
+  ricochet chain runs --sort duration --limit 5
+  ricochet chain runs --limit 20 --offset 20`,
+	Run: func(cmd *cobra.Command, args []string) {
+		chainID, _ := cmd.Flags().GetString("chain")
+		status, _ := cmd.Flags().GetString("status")
+		since, _ := cmd.Flags().GetString("since")
+		until, _ := cmd.Flags().GetString("until")
+		sortBy, _ := cmd.Flags().GetString("sort")
+		ascending, _ := cmd.Flags().GetBool("asc")
+		offset, _ := cmd.Flags().GetInt("offset")
+		limit, _ := cmd.Flags().GetInt("limit")
+
+		if status != "" && !isKnownRunStatus(orchestrator.RunStatus(status)) {
+			fmt.Printf("Ошибка: неизвестный статус '%s'. Допустимые значения: pending, running, processing, completed, failed, cancelled\n", status)
+			os.Exit(1)
+		}
+
+		filter := orchestrator.RunFilter{
+			ChainID:   chainID,
+			Status:    orchestrator.RunStatus(status),
+			SortBy:    orchestrator.RunSortField(sortBy),
+			Ascending: ascending,
+			Offset:    offset,
+			Limit:     limit,
+		}
+		var err error
+		if since != "" {
+			if filter.Since, err = workflow.ParseHistoryTime(since, false); err != nil {
+				fmt.Printf("Ошибка: %v\n", err)
+				os.Exit(1)
+			}
+		}
+		if until != "" {
+			if filter.Until, err = workflow.ParseHistoryTime(until, true); err != nil {
+				fmt.Printf("Ошибка: %v\n", err)
+				os.Exit(1)
+			}
+		}
+		if err := filter.Validate(); err != nil {
+			fmt.Printf("Ошибка: %v\n", err)
+			os.Exit(1)
+		}
+
+		orch := newRunOrchestrator()
+
+		runs, err := orch.FindRuns(filter)
+		if err != nil {
+			fmt.Printf("Ошибка при получении списка запусков: %v\n", err)
+			os.Exit(1)
+		}
+
+		if len(runs) == 0 {
+			fmt.Println("Запуски не найдены.")
+			return
+		}
+
+		fmt.Println("Запуски цепочек:")
+		fmt.Println("----------------------------------------------------")
+		for _, run := range runs {
+			fmt.Printf("ID: %s\n", run.ID)
+			fmt.Printf("Цепочка: %s\n", run.ChainID)
+			fmt.Printf("Статус: %s\n", run.Status)
+			if run.QueuePosition > 0 {
+				fmt.Printf("Место в очереди: %d\n", run.QueuePosition)
+			}
+			fmt.Printf("Начало: %s\n", run.StartTime.Format(time.RFC3339))
+			if !run.EndTime.IsZero() {
+				fmt.Printf("Длительность: %s\n", run.EndTime.Sub(run.StartTime).Round(time.Second))
+			}
+			fmt.Println("----------------------------------------------------")
+		}
+
+		if limit > 0 && len(runs) == limit {
+			fmt.Printf("Показаны запуски %d-%d. Следующая страница: --offset %d\n", offset+1, offset+len(runs), offset+limit)
+		}
+	},
+}
+
+// Команда chain run-status
+var runStatusCmd = &cobra.Command{
+	Use:   "run-status <runID>",
+	Short: "Статус запуска цепочки",
+	Long:  `Отображение статуса и метаданных запуска цепочки по ID запуска.`,
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		orch := newRunOrchestrator()
+
+		run, err := orch.GetRunStatus(args[0])
+		if err != nil {
+			fmt.Printf("Ошибка при получении статуса запуска: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("ID запуска: %s\n", run.ID)
+		fmt.Printf("Цепочка: %s\n", run.ChainID)
+		fmt.Printf("Статус: %s\n", run.Status)
+		if run.QueuePosition > 0 {
+			fmt.Printf("Место в очереди: %d (ждет освобождения слота)\n", run.QueuePosition)
+		}
+		fmt.Printf("Прогресс: %.0f%%\n", run.Progress*100)
+		fmt.Printf("Начало: %s\n", run.StartTime.Format(time.RFC3339))
+		if !run.EndTime.IsZero() {
+			fmt.Printf("Завершение: %s\n", run.EndTime.Format(time.RFC3339))
+			fmt.Printf("Длительность: %s\n", run.EndTime.Sub(run.StartTime).Round(time.Second))
+		}
+		if run.CurrentModel != "" {
+			fmt.Printf("Текущая модель: %s\n", run.CurrentModel)
+		}
+		fmt.Printf("Токенов использовано: %d\n", run.TotalTokens)
+		if len(run.Checkpoints) > 0 {
+			fmt.Printf("Чекпоинты: %d\n", len(run.Checkpoints))
+		}
+		if run.Error != "" {
+			fmt.Printf("Ошибка: %s\n", run.Error)
+		}
+	},
+}
+
+// Команда chain run-results
+var runResultsCmd = &cobra.Command{
+	Use:   "run-results <runID>",
+	Short: "Результаты запуска цепочки",
+	Long:  `Вывод результата завершенного запуска цепочки по ID запуска.`,
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		orch := newRunOrchestrator()
+
+		output, err := orch.GetRunResults(args[0])
+		if err != nil {
+			fmt.Printf("Ошибка при получении результатов запуска: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Println(output.Text)
+	},
+}
+
+// Команда chain export
+var exportCmd = &cobra.Command{
+	Use:   "export <chainID>",
+	Short: "Экспортировать цепочку в файл определения",
+	Long: `Экспорт цепочки в переносимый файл определения: шаги, модели, промпты и параметры
+с версией формата, без идентификаторов хранилища. Файл можно редактировать, хранить
+в репозитории и загружать командой chain import.
+
+Формат определяется расширением --out (.json - JSON, иначе YAML) или флагом --format.
+Без --out определение выводится в stdout.
+
+Примеры:
+  ricochet chain export <chainID> --out chain.yaml
+  ricochet chain export <chainID> --format json > chain.json`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		out, _ := cmd.Flags().GetString("out")
+		format, _ := cmd.Flags().GetString("format")
+
+		if format == "" {
+			format = "yaml"
+			if strings.EqualFold(filepath.Ext(out), ".json") {
+				format = "json"
+			}
+		}
+		if format != "yaml" && format != "json" {
+			fmt.Printf("Ошибка: неизвестный формат '%s'. Допустимые значения: yaml, json\n", format)
+			os.Exit(1)
+		}
+
+		chainStore, _ := openChainStore()
+
+		c, err := chainStore.Get(args[0])
+		if err != nil {
+			fmt.Printf("Ошибка при получении цепочки: %v\n", err)
+			os.Exit(1)
+		}
+
+		data, err := chain.NewDefinition(c).Marshal(format)
+		if err != nil {
+			fmt.Printf("Ошибка при экспорте цепочки: %v\n", err)
+			os.Exit(1)
+		}
+
+		if out == "" {
+			os.Stdout.Write(data)
+			return
+		}
+		if err := os.WriteFile(out, data, 0644); err != nil {
+			fmt.Printf("Ошибка при записи файла: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("Цепочка '%s' (%d шагов) экспортирована в %s\n", c.Name, len(c.Models), out)
+	},
+}
+
+// Команда chain import
+var importCmd = &cobra.Command{
+	Use:   "import <file>",
+	Short: "Импортировать цепочку из файла определения",
+	Long: `Создание цепочки по файлу определения (YAML или JSON), полученному командой
+chain export. Определение проверяется целиком до сохранения: версия формата,
+роли, модели, параметры и запасные модели.
+
+Тип модели берется из реестра моделей по ее имени. Модели вне реестра импортируются
+с указанным в файле type и предупреждением. Также выводится предупреждение, если
+для провайдера моделей цепочки не добавлен API-ключ.
+
+Примеры:
+  ricochet chain import chain.yaml
+  ricochet chain import chain.yaml --name "Release notes (копия)"`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		name, _ := cmd.Flags().GetString("name")
+
+		data, err := os.ReadFile(args[0])
+		if err != nil {
+			fmt.Printf("Ошибка при чтении файла: %v\n", err)
+			os.Exit(1)
+		}
+
+		definition, err := chain.ParseDefinition(data)
+		if err != nil {
+			fmt.Printf("Ошибка: %v\n", err)
+			os.Exit(1)
+		}
+		if name != "" {
+			definition.Name = name
+		}
+
+		c, warnings, err := definition.ToChain()
+		if err != nil {
+			fmt.Printf("Ошибка: неверное определение цепочки: %v\n", err)
+			os.Exit(1)
+		}
+
+		chainStore, configDir := openChainStore()
+
+		// Предупреждаем о провайдерах моделей без API-ключей
+		if keyStore, err := key.NewFileKeyStore(configDir); err == nil {
+			if keys, err := keyStore.List(); err == nil {
+				if check := doctor.CheckChainKeys([]chain.Chain{c}, keys); check.Status != doctor.StatusPass {
+					warnings = append(warnings, fmt.Sprintf("%s. %s", check.Message, check.Hint))
+				}
+			}
+		}
+
+		if err := chainStore.Save(c); err != nil {
+			fmt.Printf("Ошибка при сохранении цепочки: %v\n", err)
+			os.Exit(1)
+		}
+
+		for _, warning := range warnings {
+			fmt.Fprintf(os.Stderr, "Предупреждение: %s\n", warning)
+		}
+		fmt.Printf("Цепочка '%s' (%d шагов) импортирована. ID: %s\n", c.Name, len(c.Models), c.ID)
+	},
+}
+
+// openChainStore открывает хранилище цепочек из конфигурации и возвращает
+// его вместе с каталогом конфигурации
+func openChainStore() (*chain.FileChainStore, string) {
+	configPath, err := config.GetConfigPath()
+	if err != nil {
+		fmt.Printf("Ошибка при получении пути конфигурации: %v\n", err)
+		os.Exit(1)
+	}
+
+	cfg, err := config.LoadConfig(configPath)
+	if err != nil {
+		fmt.Printf("Ошибка при загрузке конфигурации: %v\n", err)
+		os.Exit(1)
+	}
+
+	chainStore, err := chain.NewFileChainStore(cfg.ConfigDir)
+	if err != nil {
+		fmt.Printf("Ошибка при создании хранилища цепочек: %v\n", err)
+		os.Exit(1)
+	}
+
+	return chainStore, cfg.ConfigDir
+}
+
+// newRunOrchestrator создает оркестратор для чтения сохраненных запусков
+func newRunOrchestrator() *orchestrator.DefaultOrchestrator {
+	// Загрузка конфигурации
+	configPath, err := config.GetConfigPath()
+	if err != nil {
+		fmt.Printf("Ошибка при получении пути конфигурации: %v\n", err)
+		os.Exit(1)
+	}
+
+	cfg, err := config.LoadConfig(configPath)
+	if err != nil {
+		fmt.Printf("Ошибка при загрузке конфигурации: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Создание хранилищ цепочек, задач и запусков
+	chainStore, err := chain.NewFileChainStore(cfg.ConfigDir)
+	if err != nil {
+		fmt.Printf("Ошибка при создании хранилища цепочек: %v\n", err)
+		os.Exit(1)
+	}
+
+	taskStore, err := task.NewFileTaskStore(cfg.ConfigDir)
+	if err != nil {
+		fmt.Printf("Ошибка при создании хранилища задач: %v\n", err)
+		os.Exit(1)
+	}
+
+	runStore, err := orchestrator.NewFileRunStore(cfg.ConfigDir)
+	if err != nil {
+		fmt.Printf("Ошибка при создании хранилища запусков: %v\n", err)
+		os.Exit(1)
+	}
+
+	return orchestrator.NewOrchestratorWithRunStore(
+		nil, nil, chainStore, nil, task.NewTaskManager(taskStore), nil, nil, runStore,
+	)
+}
+
+// isKnownRunStatus проверяет, что статус запуска существует
+func isKnownRunStatus(status orchestrator.RunStatus) bool {
+	switch status {
+	case orchestrator.StatusPending, orchestrator.StatusRunning, orchestrator.StatusProcessing,
+		orchestrator.StatusCompleted, orchestrator.StatusFailed, orchestrator.StatusCancelled:
+		return true
+	}
+	return false
+}
+
 // Инициализация флагов для команд
 func init() {
 	// Флаги для команды chain create
@@ -464,6 +1064,8 @@ func init() {
 	addModelCmd.Flags().String("prompt", "", "Системный промпт для модели")
 	addModelCmd.Flags().Float64("temperature", 0.7, "Температура (0.0-1.0)")
 	addModelCmd.Flags().Int("max-tokens", 1000, "Максимальное количество токенов")
+	addModelCmd.Flags().String("output-schema", "", "Файл с JSON-схемой результата: модель отвечает JSON по схеме, ответ проверяется")
+	addModelCmd.Flags().StringSlice("fallback", nil, "Запасные модели на случай временного сбоя основной, по порядку (например, claude-3-sonnet,gpt-4-turbo)")
 	addModelCmd.MarkFlagRequired("chain")
 	addModelCmd.MarkFlagRequired("name")
 	addModelCmd.MarkFlagRequired("type")
@@ -473,7 +1075,19 @@ func init() {
 	runCmd.Flags().String("chain", "", "ID цепочки")
 	runCmd.Flags().String("input", "", "Входной текст")
 	runCmd.Flags().String("input-file", "", "Путь к входному файлу")
-	runCmd.MarkFlagRequired("chain")
+	runCmd.Flags().Int("segment-tokens", orchestrator.DefaultProcessingOptions().MaxTokensPerChunk, "Максимальный размер сегмента в токенах")
+	runCmd.Flags().Int("segment-overlap", 0, "Перекрытие сегментов в токенах (увеличивает расход входных токенов)")
+	runCmd.Flags().String("segment-strategy", segmentation.SegmentationSentence, "Метод сегментации (sentence, paragraph, semantic, recursive)")
+	runCmd.Flags().String("aggregation", orchestrator.AggregationConcatenate, "Объединение результатов сегментов (concatenate, summarize, merge)")
+	runCmd.Flags().Bool("stream", false, "Выполнить цепочку сразу и выводить ответы моделей по мере генерации")
+
+	// Флаги для команды chain estimate
+	estimateCmd.Flags().String("input", "", "Входной текст")
+	estimateCmd.Flags().String("input-file", "", "Путь к входному файлу")
+	estimateCmd.Flags().Int("segment-tokens", orchestrator.DefaultProcessingOptions().MaxTokensPerChunk, "Максимальный размер сегмента в токенах")
+	estimateCmd.Flags().Int("segment-overlap", 0, "Перекрытие сегментов в токенах")
+	estimateCmd.Flags().String("segment-strategy", segmentation.SegmentationSentence, "Метод сегментации (sentence, paragraph, semantic, recursive)")
+	estimateCmd.Flags().String("aggregation", orchestrator.AggregationConcatenate, "Объединение результатов сегментов (concatenate, summarize, merge)")
 
 	// Флаги для команды chain status
 	statusCmd.Flags().String("chain", "", "ID цепочки")
@@ -482,4 +1096,21 @@ func init() {
 	// Флаги для команды chain delete
 	deleteCmd.Flags().String("chain", "", "ID цепочки")
 	deleteCmd.MarkFlagRequired("chain")
+
+	// Флаги для команды chain runs
+	runsCmd.Flags().String("chain", "", "Показать только запуски цепочки с указанным ID")
+	runsCmd.Flags().String("status", "", "Показать только запуски со статусом (pending, running, processing, completed, failed, cancelled)")
+	runsCmd.Flags().String("since", "", "Показать только запуски, начатые не раньше даты (RFC3339 или YYYY-MM-DD)")
+	runsCmd.Flags().String("until", "", "Показать только запуски, начатые не позже даты (RFC3339 или YYYY-MM-DD)")
+	runsCmd.Flags().String("sort", string(orchestrator.SortByStartTime), "Поле сортировки (start_time, duration, tokens)")
+	runsCmd.Flags().Bool("asc", false, "Сортировать по возрастанию (по умолчанию новые и самые большие первыми)")
+	runsCmd.Flags().Int("offset", 0, "Сколько запусков пропустить")
+	runsCmd.Flags().Int("limit", 20, "Максимальное количество запусков (0 - все)")
+
+	// Флаги для команды chain export
+	exportCmd.Flags().String("out", "", "Файл определения (по умолчанию stdout)")
+	exportCmd.Flags().String("format", "", "Формат определения (yaml, json); по умолчанию по расширению --out")
+
+	// Флаги для команды chain import
+	importCmd.Flags().String("name", "", "Имя импортированной цепочки вместо имени из файла")
 }
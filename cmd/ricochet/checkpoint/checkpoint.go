@@ -1,11 +1,14 @@
 package checkpoint
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
+	"sort"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/grik-ai/ricochet-task/pkg/chain"
 	"github.com/grik-ai/ricochet-task/pkg/checkpoint"
 	"github.com/grik-ai/ricochet-task/internal/config"
 	"github.com/spf13/cobra"
@@ -24,6 +27,8 @@ func init() {
 	CheckpointCmd.AddCommand(getCmd)
 	CheckpointCmd.AddCommand(saveCmd)
 	CheckpointCmd.AddCommand(deleteCmd)
+	CheckpointCmd.AddCommand(exportCmd)
+	CheckpointCmd.AddCommand(importCmd)
 }
 
 // Команда checkpoint list
@@ -307,6 +312,158 @@ var deleteCmd = &cobra.Command{
 	},
 }
 
+// Команда checkpoint export
+var exportCmd = &cobra.Command{
+	Use:   "export [id]",
+	Short: "Экспортировать чекпоинт в файл",
+	Long: `Экспорт чекпоинта вместе с содержимым и сведениями о цепочке (версия, модели)
+в JSON-файл, чтобы передать промежуточное состояние другому пользователю или на
+другую машину. Без --out файл выводится в stdout.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		outputFile, _ := cmd.Flags().GetString("out")
+
+		cfg, err := loadConfig()
+		if err != nil {
+			fmt.Printf("Ошибка при загрузке конфигурации: %v\n", err)
+			os.Exit(1)
+		}
+
+		checkpointStore, err := checkpoint.NewFileCheckpointStore(cfg.ConfigDir)
+		if err != nil {
+			fmt.Printf("Ошибка при создании хранилища чекпоинтов: %v\n", err)
+			os.Exit(1)
+		}
+
+		cp, err := checkpointStore.Get(args[0])
+		if err != nil {
+			fmt.Printf("Ошибка при получении чекпоинта: %v\n", err)
+			os.Exit(1)
+		}
+
+		// Сведения о цепочке нужны для проверки совместимости при импорте
+		chainInfo, err := loadChainInfo(cfg.ConfigDir, cp.ChainID)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Предупреждение: %v, сведения о цепочке не будут экспортированы\n", err)
+		}
+
+		data, err := json.MarshalIndent(checkpoint.NewExport(cp, chainInfo), "", "  ")
+		if err != nil {
+			fmt.Printf("Ошибка при сериализации чекпоинта: %v\n", err)
+			os.Exit(1)
+		}
+
+		if outputFile == "" {
+			fmt.Println(string(data))
+			return
+		}
+
+		if err := os.WriteFile(outputFile, data, 0644); err != nil {
+			fmt.Printf("Ошибка при сохранении файла: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Чекпоинт %s экспортирован в файл: %s\n", cp.ID, outputFile)
+	},
+}
+
+// Команда checkpoint import
+var importCmd = &cobra.Command{
+	Use:   "import [file]",
+	Short: "Импортировать чекпоинт из файла",
+	Long: `Импорт чекпоинта из файла, созданного командой checkpoint export. Формат файла
+проверяется; если локальная цепочка отсутствует или отличается от цепочки в файле
+(версия, модели), выводится предупреждение. Существующий чекпоинт с тем же ID
+перезаписывается только с флагом --force.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		force, _ := cmd.Flags().GetBool("force")
+
+		data, err := os.ReadFile(args[0])
+		if err != nil {
+			fmt.Printf("Ошибка при чтении файла: %v\n", err)
+			os.Exit(1)
+		}
+
+		export, err := checkpoint.ParseExport(data)
+		if err != nil {
+			fmt.Printf("Ошибка: файл не является экспортом чекпоинта: %v\n", err)
+			os.Exit(1)
+		}
+
+		cfg, err := loadConfig()
+		if err != nil {
+			fmt.Printf("Ошибка при загрузке конфигурации: %v\n", err)
+			os.Exit(1)
+		}
+
+		checkpointStore, err := checkpoint.NewFileCheckpointStore(cfg.ConfigDir)
+		if err != nil {
+			fmt.Printf("Ошибка при создании хранилища чекпоинтов: %v\n", err)
+			os.Exit(1)
+		}
+
+		cp := export.Checkpoint
+		if _, err := checkpointStore.Get(cp.ID); err == nil && !force {
+			fmt.Printf("Ошибка: чекпоинт %s уже существует, используйте --force для перезаписи\n", cp.ID)
+			os.Exit(1)
+		}
+
+		localChain, _ := loadChainInfo(cfg.ConfigDir, cp.ChainID)
+		for _, warning := range export.CompareChain(localChain) {
+			fmt.Printf("Предупреждение: %s\n", warning)
+		}
+
+		if cp.MetaData == nil {
+			cp.MetaData = make(map[string]interface{})
+		}
+		cp.MetaData["imported_from"] = args[0]
+		cp.MetaData["imported_at"] = time.Now().Format(time.RFC3339)
+
+		if err := checkpointStore.Save(cp); err != nil {
+			fmt.Printf("Ошибка при сохранении чекпоинта: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("Чекпоинт %s импортирован для цепочки %s\n", cp.ID, cp.ChainID)
+	},
+}
+
+// loadConfig загружает конфигурацию ricochet
+func loadConfig() (config.Config, error) {
+	configPath, err := config.GetConfigPath()
+	if err != nil {
+		return config.Config{}, err
+	}
+	return config.LoadConfig(configPath)
+}
+
+// loadChainInfo возвращает сведения о локальной цепочке
+func loadChainInfo(configDir, chainID string) (*checkpoint.ChainInfo, error) {
+	chainStore, err := chain.NewFileChainStore(configDir)
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := chainStore.Get(chainID)
+	if err != nil {
+		return nil, err
+	}
+
+	models := append([]chain.Model(nil), c.Models...)
+	sort.SliceStable(models, func(i, j int) bool { return models[i].Order < models[j].Order })
+
+	info := &checkpoint.ChainInfo{
+		ID:        c.ID,
+		Name:      c.Name,
+		Version:   c.Metadata.Version,
+		UpdatedAt: c.UpdatedAt,
+	}
+	for _, m := range models {
+		info.Models = append(info.Models, string(m.Name))
+	}
+	return info, nil
+}
+
 // Инициализация флагов для команд
 func init() {
 	// Флаги для команды checkpoint list
@@ -331,4 +488,10 @@ func init() {
 	deleteCmd.Flags().String("id", "", "ID чекпоинта")
 	deleteCmd.Flags().String("chain", "", "ID цепочки")
 	deleteCmd.Flags().Bool("all", false, "Удалить все чекпоинты цепочки")
+
+	// Флаги для команды checkpoint export
+	exportCmd.Flags().String("out", "", "Путь к файлу экспорта (по умолчанию stdout)")
+
+	// Флаги для команды checkpoint import
+	importCmd.Flags().Bool("force", false, "Перезаписать существующий чекпоинт с тем же ID")
 }
@@ -2,12 +2,14 @@ package main
 
 import (
 	"log"
+	"os"
 
 	"github.com/grik-ai/ricochet-task/cmd/ricochet"
 )
 
 func main() {
 	if err := ricochet.Execute(); err != nil {
-		log.Fatalf("Ошибка выполнения команды: %v", err)
+		log.Printf("Ошибка выполнения команды: %v", err)
+		os.Exit(ricochet.ExitCode(err))
 	}
 }
@@ -0,0 +1,50 @@
+package ricochet
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// completionCmd генерирует скрипт автодополнения для оболочки. Имена
+// провайдеров, статусы и метки дополняются динамически из конфигурации
+// и реестра провайдеров.
+var completionCmd = &cobra.Command{
+	Use:   "completion [bash|zsh|fish|powershell]",
+	Short: "Сгенерировать скрипт автодополнения для оболочки",
+	Long: `Генерирует скрипт автодополнения команд и флагов ricochet.
+
+Имена включенных провайдеров для --provider, статусы для --status и метки
+для --labels подставляются динамически из конфигурации и провайдеров.
+
+Bash (нужен пакет bash-completion):
+  source <(ricochet completion bash)
+  # постоянно:
+  ricochet completion bash > /etc/bash_completion.d/ricochet
+
+Zsh:
+  ricochet completion zsh > "${fpath[1]}/_ricochet"
+  # если автодополнение еще не включено:
+  echo "autoload -U compinit; compinit" >> ~/.zshrc
+
+Fish:
+  ricochet completion fish > ~/.config/fish/completions/ricochet.fish
+
+PowerShell:
+  ricochet completion powershell | Out-String | Invoke-Expression`,
+	ValidArgs:             []string{"bash", "zsh", "fish", "powershell"},
+	Args:                  cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+	DisableFlagsInUseLine: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		switch args[0] {
+		case "bash":
+			return rootCmd.GenBashCompletionV2(os.Stdout, true)
+		case "zsh":
+			return rootCmd.GenZshCompletion(os.Stdout)
+		case "fish":
+			return rootCmd.GenFishCompletion(os.Stdout, true)
+		default:
+			return rootCmd.GenPowerShellCompletionWithDesc(os.Stdout)
+		}
+	},
+}
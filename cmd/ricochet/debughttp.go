@@ -0,0 +1,52 @@
+package ricochet
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/grik-ai/ricochet-task/internal/config"
+	"github.com/grik-ai/ricochet-task/pkg/providers"
+)
+
+// httpDebugLogFile файл журнала HTTP-запросов в директории профиля
+const httpDebugLogFile = "http_debug.log"
+
+var (
+	// Флаги журнала HTTP-запросов к провайдерам
+	debugHTTP       bool
+	debugHTTPFile   string
+	debugHTTPBodies bool
+
+	httpDebugLog *providers.HTTPDebugLog
+)
+
+// applyHTTPDebugLog включает журнал HTTP-запросов к провайдерам, если задан --debug-http
+func applyHTTPDebugLog() {
+	if !debugHTTP {
+		return
+	}
+
+	path := debugHTTPFile
+	if path == "" {
+		path = config.ProfilePath(httpDebugLogFile)
+	}
+
+	log, err := providers.OpenHTTPDebugLog(path, debugHTTPBodies)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Ошибка: %v\n", err)
+		os.Exit(providers.ExitUsage)
+	}
+
+	httpDebugLog = log
+	providers.SetHTTPDebugLog(log)
+	fmt.Fprintf(os.Stderr, "🔍 HTTP-запросы к провайдерам записываются в %s\n", path)
+}
+
+// closeHTTPDebugLog закрывает файл журнала HTTP-запросов
+func closeHTTPDebugLog() {
+	if httpDebugLog == nil {
+		return
+	}
+	providers.SetHTTPDebugLog(nil)
+	httpDebugLog.Close()
+}
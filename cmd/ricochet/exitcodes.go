@@ -0,0 +1,75 @@
+package ricochet
+
+import (
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/grik-ai/ricochet-task/pkg/providers"
+)
+
+// usageError помечает ошибки неверного вызова команды: неизвестные команды и
+// флаги, неверное число аргументов, отсутствующие обязательные флаги
+type usageError struct {
+	err error
+}
+
+func (e *usageError) Error() string {
+	return e.err.Error()
+}
+
+func (e *usageError) Unwrap() error {
+	return e.err
+}
+
+// ErrorType относит ошибку к ошибкам валидации, что даёт код завершения ExitUsage
+func (e *usageError) ErrorType() providers.ErrorType {
+	return providers.ErrorTypeValidation
+}
+
+// ExitCode возвращает код завершения процесса для ошибки, которую вернул Execute.
+// Коды описаны в how-to-work/CLI_REFERENCE.md.
+func ExitCode(err error) int {
+	return providers.ExitCode(err)
+}
+
+// markUsageErrors оборачивает проверки аргументов и ошибки разбора флагов всех
+// команд, чтобы они завершались с кодом ExitUsage
+func markUsageErrors(cmd *cobra.Command) {
+	cmd.SetFlagErrorFunc(func(c *cobra.Command, err error) error {
+		return &usageError{err: err}
+	})
+
+	if validate := cmd.Args; validate != nil {
+		cmd.Args = func(c *cobra.Command, args []string) error {
+			if err := validate(c, args); err != nil {
+				return &usageError{err: err}
+			}
+			return nil
+		}
+	}
+
+	for _, child := range cmd.Commands() {
+		markUsageErrors(child)
+	}
+}
+
+// cobra не типизирует ошибки обязательных флагов и неизвестных команд,
+// поэтому распознаём их по тексту
+var cobraUsageErrorPrefixes = []string{
+	"unknown command",
+	"required flag(s)",
+	"if any flags in the group",
+}
+
+func classifyUsageError(err error) error {
+	if err == nil {
+		return nil
+	}
+	for _, prefix := range cobraUsageErrorPrefixes {
+		if strings.HasPrefix(err.Error(), prefix) {
+			return &usageError{err: err}
+		}
+	}
+	return err
+}
@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/grik-ai/ricochet-task/internal/config"
 	"github.com/grik-ai/ricochet-task/pkg/ai"
 	"github.com/grik-ai/ricochet-task/pkg/key"
 )
@@ -35,13 +36,11 @@ func runInit(cmd *cobra.Command, args []string) {
 	printWelcome()
 	
 	// Check if already initialized
-	homeDir, err := os.UserHomeDir()
+	configDir, err := config.ProfileDir(config.ActiveProfile())
 	if err != nil {
-		fmt.Printf("❌ Error getting home directory: %v\n", err)
+		fmt.Printf("❌ Error getting config directory: %v\n", err)
 		return
 	}
-	
-	configDir := filepath.Join(homeDir, ".ricochet")
 	if _, err := os.Stat(configDir); err == nil {
 		fmt.Print("🔄 Ricochet-Task is already configured. Reconfigure? (y/N): ")
 		if !askYesNo(false) {
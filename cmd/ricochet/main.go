@@ -4,14 +4,20 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/grik-ai/ricochet-task/cmd/audit"
 	"github.com/grik-ai/ricochet-task/cmd/board"
+	"github.com/grik-ai/ricochet-task/cmd/cache"
 	contextcmd "github.com/grik-ai/ricochet-task/cmd/context"
+	"github.com/grik-ai/ricochet-task/cmd/doctor"
 	mcpcmd "github.com/grik-ai/ricochet-task/cmd/mcp"
+	"github.com/grik-ai/ricochet-task/cmd/notifications"
 	"github.com/grik-ai/ricochet-task/cmd/providers"
 	"github.com/grik-ai/ricochet-task/cmd/ricochet/chain"
 	"github.com/grik-ai/ricochet-task/cmd/ricochet/checkpoint"
 	"github.com/grik-ai/ricochet-task/cmd/ricochet/key"
+	"github.com/grik-ai/ricochet-task/cmd/ricochet/models"
 	"github.com/grik-ai/ricochet-task/cmd/ricochet/ricochet_task"
+	"github.com/grik-ai/ricochet-task/cmd/ricochet/tokens"
 	"github.com/grik-ai/ricochet-task/cmd/tasks"
 	"github.com/grik-ai/ricochet-task/cmd/workflows"
 	"github.com/grik-ai/ricochet-task/pkg/ui"
@@ -45,9 +51,12 @@ var rootCmd = &cobra.Command{
 	},
 }
 
-// Execute выполняет корневую команду
+// Execute выполняет корневую команду. Код завершения для возвращённой ошибки
+// даёт ExitCode.
 func Execute() error {
-	return rootCmd.Execute()
+	markUsageErrors(rootCmd)
+	defer closeHTTPDebugLog()
+	return classifyUsageError(rootCmd.Execute())
 }
 
 func init() {
@@ -55,16 +64,36 @@ func init() {
 	rootCmd.PersistentFlags().StringP("config", "c", "", "Путь к файлу конфигурации")
 	rootCmd.PersistentFlags().BoolP("verbose", "v", false, "Включить подробный вывод")
 	rootCmd.PersistentFlags().BoolVarP(&interactiveMode, "interactive", "i", false, "Запустить в интерактивном режиме")
+	rootCmd.PersistentFlags().StringVar(&profileName, "profile", "", "Профиль конфигурации (по умолчанию $RICOCHET_PROFILE или выбранный командой profile switch)")
+	rootCmd.RegisterFlagCompletionFunc("profile", completeProfileNames)
+	rootCmd.PersistentFlags().BoolVar(&debugHTTP, "debug-http", false, "Записывать HTTP-запросы к провайдерам и ответы в журнал (заголовки с учетными данными скрываются)")
+	rootCmd.PersistentFlags().StringVar(&debugHTTPFile, "debug-http-file", "", "Файл журнала HTTP-запросов (по умолчанию http_debug.log в директории профиля)")
+	rootCmd.PersistentFlags().BoolVar(&debugHTTPBodies, "debug-http-bodies", false, "Записывать в журнал также тела запросов и ответов")
+	cobra.OnInitialize(applyProfile, applyHTTPDebugLog)
+
+	// Встроенную команду completion заменяет completionCmd
+	rootCmd.CompletionOptions.DisableDefaultCmd = true
 
 	// Подкоманды
+	rootCmd.AddCommand(tasks.AICmd)
+	rootCmd.AddCommand(audit.AuditCmd)
 	rootCmd.AddCommand(board.BoardCmd)
+	rootCmd.AddCommand(cache.CacheCmd)
+	rootCmd.AddCommand(completionCmd)
 	rootCmd.AddCommand(contextcmd.ContextCmd)
+	rootCmd.AddCommand(doctor.DoctorCmd)
 	rootCmd.AddCommand(mcpcmd.MCPCmd)
+	rootCmd.AddCommand(notifications.NotificationsCmd)
 	rootCmd.AddCommand(providers.ProvidersCmd)
 	rootCmd.AddCommand(chain.ChainCmd)
 	rootCmd.AddCommand(checkpoint.CheckpointCmd)
 	rootCmd.AddCommand(key.KeyCmd)
+	rootCmd.AddCommand(models.ModelsCmd)
+	rootCmd.AddCommand(profileCmd)
+	rootCmd.AddCommand(tasks.QueueCmd)
 	rootCmd.AddCommand(ricochet_task.TaskCmd)
+	rootCmd.AddCommand(tasks.SyncCmd)
+	rootCmd.AddCommand(tokens.TokensCmd)
 	rootCmd.AddCommand(tasks.TasksCmd)  // Подключаем полнофункциональные команды задач
 	rootCmd.AddCommand(workflows.WorkflowCmd)
 
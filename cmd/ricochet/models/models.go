@@ -0,0 +1,168 @@
+package models
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/grik-ai/ricochet-task/internal/config"
+	"github.com/grik-ai/ricochet-task/pkg/key"
+	"github.com/grik-ai/ricochet-task/pkg/models_manager"
+	"github.com/spf13/cobra"
+)
+
+// Команда models
+var ModelsCmd = &cobra.Command{
+	Use:   "models",
+	Short: "Настройка моделей",
+	Long:  `Команды для просмотра и настройки моделей, которые используются по умолчанию.`,
+}
+
+// Команда models roles
+var rolesCmd = &cobra.Command{
+	Use:   "roles",
+	Short: "Модели по умолчанию для ролей",
+	Long: `Просмотр и изменение соответствия ролей и моделей.
+
+Это соответствие используется при автоматическом выборе модели для шага цепочки:
+шаг с ролью, но без явно указанной модели, выполняется моделью, назначенной роли.`,
+}
+
+// Инициализация команд
+func init() {
+	ModelsCmd.AddCommand(rolesCmd)
+	rolesCmd.AddCommand(rolesListCmd)
+	rolesCmd.AddCommand(rolesSetCmd)
+
+	rolesSetCmd.Flags().String("model", "", "ID модели (например, gpt-4 или claude-3-opus-20240229)")
+	rolesSetCmd.Flags().String("provider", "", "Провайдер модели (openai, anthropic, deepseek, mistral); если не указан, определяется по модели")
+	rolesSetCmd.MarkFlagRequired("model")
+}
+
+// Команда models roles list
+var rolesListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Показать модели ролей",
+	Long:  `Отображение моделей, назначенных ролям, и наличия API-ключей для них.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		manager, keyStore := loadManagers()
+
+		fmt.Println("Роли моделей:")
+		fmt.Println("----------------------------------------------------")
+		for _, role := range manager.GetRoles() {
+			fmt.Printf("%s (%s)\n", role.ID, role.DisplayName)
+
+			if role.ModelID == "" {
+				// Роль не настроена: показываем, какую модель выберет автовыбор
+				auto := manager.GetModelForRole(role.ID)
+				if auto.ModelID == "" {
+					fmt.Println("   Модель: не назначена")
+				} else {
+					fmt.Printf("   Модель: %s (%s), автовыбор через роль %s\n", auto.ModelID, auto.Provider, auto.ID)
+				}
+			} else {
+				fmt.Printf("   Модель: %s (%s)\n", role.ModelID, role.Provider)
+				if !hasKey(keyStore, role.Provider) {
+					fmt.Printf("   Внимание: нет API-ключа для провайдера %s\n", role.Provider)
+				}
+			}
+		}
+		fmt.Println("----------------------------------------------------")
+		fmt.Println("Изменить модель роли: ricochet models roles set <роль> --model <модель> --provider <провайдер>")
+	},
+}
+
+// Команда models roles set
+var rolesSetCmd = &cobra.Command{
+	Use:   "set <role>",
+	Short: "Назначить модель роли",
+	Long: `Назначение модели по умолчанию для роли. Модель должна быть известна,
+а для ее провайдера должен быть добавлен API-ключ (ricochet key add).`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		roleID := strings.ToLower(args[0])
+		modelID, _ := cmd.Flags().GetString("model")
+		provider, _ := cmd.Flags().GetString("provider")
+		provider = strings.ToLower(provider)
+
+		if !models_manager.IsKnownRole(roleID) {
+			var roles []string
+			for _, role := range models_manager.DefaultRoles() {
+				roles = append(roles, role.ID)
+			}
+			fmt.Printf("Ошибка: неизвестная роль '%s'. Допустимые значения: %s\n", roleID, strings.Join(roles, ", "))
+			os.Exit(1)
+		}
+
+		manager, keyStore := loadManagers()
+
+		option, err := manager.FindModel(provider, modelID)
+		if err != nil {
+			fmt.Printf("Ошибка: %v\n", err)
+			os.Exit(1)
+		}
+
+		if !hasKey(keyStore, option.Provider) {
+			fmt.Printf("Ошибка: нет API-ключа для провайдера %s. Добавьте его командой: ricochet key add --provider %s\n",
+				option.Provider, keyProvider(option.Provider))
+			os.Exit(1)
+		}
+
+		role, err := manager.SetRoleModel(roleID, option.Provider, option.ModelID)
+		if err != nil {
+			fmt.Printf("Ошибка при назначении модели: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("Роли '%s' назначена модель %s (%s).\n", role.ID, role.ModelID, role.Provider)
+	},
+}
+
+// loadManagers загружает менеджер моделей и хранилище ключей из директории конфигурации
+func loadManagers() (*models_manager.ModelsManager, *key.FileKeyStore) {
+	configPath, err := config.GetConfigPath()
+	if err != nil {
+		fmt.Printf("Ошибка при получении пути конфигурации: %v\n", err)
+		os.Exit(1)
+	}
+
+	cfg, err := config.LoadConfig(configPath)
+	if err != nil {
+		fmt.Printf("Ошибка при загрузке конфигурации: %v\n", err)
+		os.Exit(1)
+	}
+
+	manager, err := models_manager.New(filepath.Join(cfg.ConfigDir, "models.json"))
+	if err != nil {
+		fmt.Printf("Ошибка при загрузке конфигурации моделей: %v\n", err)
+		os.Exit(1)
+	}
+
+	keyStore, err := key.NewFileKeyStore(cfg.ConfigDir)
+	if err != nil {
+		fmt.Printf("Ошибка при создании хранилища ключей: %v\n", err)
+		os.Exit(1)
+	}
+
+	return manager, keyStore
+}
+
+// keyProvider возвращает имя провайдера, под которым хранятся его API-ключи
+func keyProvider(provider string) string {
+	if provider == "anthropic" {
+		return "claude"
+	}
+	return provider
+}
+
+// hasKey проверяет, что для провайдера добавлен хотя бы один API-ключ
+func hasKey(keyStore *key.FileKeyStore, provider string) bool {
+	for _, name := range []string{keyProvider(provider), provider} {
+		keys, err := keyStore.GetByProvider(name)
+		if err == nil && len(keys) > 0 {
+			return true
+		}
+	}
+	return false
+}
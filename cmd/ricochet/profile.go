@@ -0,0 +1,127 @@
+package ricochet
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/grik-ai/ricochet-task/internal/config"
+	"github.com/grik-ai/ricochet-task/pkg/providers"
+)
+
+// profileName профиль, заданный флагом --profile
+var profileName string
+
+// profileCmd управляет именованными профилями конфигурации
+var profileCmd = &cobra.Command{
+	Use:   "profile",
+	Short: "Управление профилями конфигурации",
+	Long: `Управление именованными профилями. У каждого профиля свои провайдеры,
+API-ключи, цепочки и кэш метаданных в ~/.ricochet/profiles/<имя>/.
+
+Профиль выбирается флагом --profile, переменной RICOCHET_PROFILE или
+командой 'ricochet profile switch'. Профиль default использует ~/.ricochet
+и ricochet.yaml в текущей директории.
+
+Примеры:
+  ricochet profile create work
+  ricochet --profile work tasks list
+  ricochet profile switch work`,
+}
+
+var profileCreateCmd = &cobra.Command{
+	Use:   "create [имя]",
+	Short: "Создать профиль",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dir, err := config.CreateProfile(args[0])
+		if err != nil {
+			return providers.NewValidationError(err.Error(), nil)
+		}
+
+		fmt.Printf("✅ Профиль '%s' создан: %s\n", args[0], dir)
+		fmt.Printf("   Провайдеры настраиваются в %s\n", filepath.Join(dir, config.ProviderConfigFile))
+		fmt.Printf("   Ключи: ricochet --profile %s key add --provider openai --key <ключ>\n", args[0])
+		return nil
+	},
+}
+
+var profileListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Показать профили",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		names, err := config.ListProfiles()
+		if err != nil {
+			return err
+		}
+
+		active := config.ActiveProfile()
+		for _, name := range names {
+			marker := "  "
+			if name == active {
+				marker = "* "
+			}
+			dir, _ := config.ProfileDir(name)
+			fmt.Printf("%s%-20s %s\n", marker, name, dir)
+		}
+		return nil
+	},
+}
+
+var profileSwitchCmd = &cobra.Command{
+	Use:   "switch [имя]",
+	Short: "Сделать профиль текущим",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := config.SwitchProfile(args[0]); err != nil {
+			return providers.NewValidationError(err.Error(), nil)
+		}
+
+		fmt.Printf("✅ Текущий профиль: %s\n", args[0])
+		if env := os.Getenv(config.ProfileEnv); env != "" && env != args[0] {
+			fmt.Printf("⚠️  Переменная %s=%s переопределяет текущий профиль\n", config.ProfileEnv, env)
+		}
+		return nil
+	},
+}
+
+func init() {
+	profileCmd.AddCommand(profileCreateCmd)
+	profileCmd.AddCommand(profileListCmd)
+	profileCmd.AddCommand(profileSwitchCmd)
+
+	profileSwitchCmd.ValidArgsFunction = completeProfileNames
+}
+
+// applyProfile выбирает профиль до запуска команды: его директорию используют
+// хранилища ключей и цепочек, а его ricochet.yaml - команды провайдеров.
+// Без флага --profile профиль берется из RICOCHET_PROFILE или profile switch.
+func applyProfile() {
+	name := profileName
+	if name == "" {
+		name = os.Getenv(config.ProfileEnv)
+	}
+
+	err := config.SetActiveProfile(name)
+	if err == nil && !config.ProfileExists(config.ActiveProfile()) {
+		err = fmt.Errorf("текущий профиль %q не найден, выберите другой: ricochet --profile default profile switch default", config.ActiveProfile())
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Ошибка: %v\n", err)
+		os.Exit(providers.ExitUsage)
+	}
+}
+
+// completeProfileNames дополняет имена профилей
+func completeProfileNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	names, err := config.ListProfiles()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
@@ -0,0 +1,143 @@
+package tokens
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/grik-ai/ricochet-task/pkg/chain"
+	"github.com/grik-ai/ricochet-task/pkg/model"
+)
+
+// Команда tokens
+var TokensCmd = &cobra.Command{
+	Use:   "tokens",
+	Short: "Оценка токенов и стоимости",
+	Long:  `Команды для оценки объема текста в токенах до запуска цепочек.`,
+}
+
+// Команда tokens scan
+var scanCmd = &cobra.Command{
+	Use:   "scan <dir>",
+	Short: "Оценить токены и стоимость файлов каталога",
+	Long: `Обход файлов каталога, оценка токенов каждого файла и общей стоимости
+обработки выбранной моделью. Стоимость считается как вход модели: длина ответов
+зависит от цепочки (см. chain estimate). Файлы, которые не помещаются в контекст
+модели, придется сегментировать.
+
+Шаблон --glob: "*" и "?" не переходят через "/", "**" - любое число каталогов,
+"{md,txt}" - один из вариантов. Шаблон без "/" проверяется по имени файла.
+Скрытые каталоги и двоичные файлы пропускаются.
+
+Примеры:
+  ricochet tokens scan ./docs --model gpt-4 --glob "**/*.md"
+  ricochet tokens scan . --glob "*.{md,txt}" --top 50
+  ricochet tokens scan ./docs --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		modelName, _ := cmd.Flags().GetString("model")
+		glob, _ := cmd.Flags().GetString("glob")
+		top, _ := cmd.Flags().GetInt("top")
+		asJSON, _ := cmd.Flags().GetBool("json")
+		cmd.SilenceUsage = true
+
+		info, err := os.Stat(args[0])
+		if err != nil {
+			return fmt.Errorf("failed to read directory: %w", err)
+		}
+		if !info.IsDir() {
+			return fmt.Errorf("%s is not a directory", args[0])
+		}
+
+		scan, err := model.ScanTokens(args[0], glob, chain.ModelName(modelName))
+		if err != nil {
+			return err
+		}
+
+		if asJSON {
+			data, err := json.MarshalIndent(scan, "", "  ")
+			if err != nil {
+				return fmt.Errorf("failed to marshal scan: %w", err)
+			}
+			fmt.Println(string(data))
+			return nil
+		}
+
+		printScan(scan, top)
+		return nil
+	},
+}
+
+// Инициализация команд
+func init() {
+	TokensCmd.AddCommand(scanCmd)
+
+	scanCmd.Flags().String("model", string(chain.ModelNameGPT4), "Модель, для которой считается стоимость и размер контекста")
+	scanCmd.Flags().String("glob", "**/*", "Шаблон путей файлов относительно каталога")
+	scanCmd.Flags().Int("top", 20, "Сколько самых больших файлов показать (0 - все)")
+	scanCmd.Flags().Bool("json", false, "Вывести результат в JSON со всеми файлами")
+}
+
+// printScan выводит самые большие файлы и итоги сканирования
+func printScan(scan *model.TokenScan, top int) {
+	if len(scan.Files) == 0 {
+		fmt.Printf("Файлы по шаблону %s не найдены в %s\n", scan.Glob, scan.Root)
+		return
+	}
+
+	files := scan.Files
+	if top > 0 && len(files) > top {
+		files = files[:top]
+	}
+
+	fmt.Printf("%-60s %10s %10s %10s\n", "ФАЙЛ", "ТОКЕНЫ", "РАЗМЕР", "СТОИМОСТЬ")
+	for _, file := range files {
+		path := file.Path
+		if len(path) > 57 {
+			path = "..." + path[len(path)-54:]
+		}
+		marker := ""
+		if file.ExceedsContext {
+			marker = "  > контекста"
+		}
+		fmt.Printf("%-60s %10d %10s %10s%s\n", path, file.Tokens, formatBytes(file.Bytes), formatCost(scan, file.Cost), marker)
+	}
+	if len(files) < len(scan.Files) {
+		fmt.Printf("... и еще %d файлов (--top 0 покажет все)\n", len(scan.Files)-len(files))
+	}
+
+	fmt.Println()
+	fmt.Printf("Модель: %s (контекст %d токенов)\n", scan.Model, scan.Context)
+	fmt.Printf("Файлов: %d, %s, ~%d токенов\n", len(scan.Files), formatBytes(scan.TotalBytes), scan.TotalTokens)
+	if scan.Priced {
+		fmt.Printf("Стоимость входа: $%.2f\n", scan.Cost)
+	} else {
+		fmt.Printf("Стоимость неизвестна: нет цен для модели %s\n", scan.Model)
+	}
+	if scan.OverContext > 0 {
+		fmt.Printf("Не помещаются в контекст: %d файлов - их придется сегментировать (см. chain estimate)\n", scan.OverContext)
+	}
+	if scan.Skipped > 0 {
+		fmt.Printf("Пропущено двоичных файлов: %d\n", scan.Skipped)
+	}
+}
+
+func formatCost(scan *model.TokenScan, cost float64) string {
+	if !scan.Priced {
+		return "-"
+	}
+	return fmt.Sprintf("$%.4f", cost)
+}
+
+// formatBytes выводит размер в КБ или МБ
+func formatBytes(size int64) string {
+	switch {
+	case size >= 1<<20:
+		return fmt.Sprintf("%.1f MB", float64(size)/(1<<20))
+	case size >= 1<<10:
+		return fmt.Sprintf("%.1f KB", float64(size)/(1<<10))
+	}
+	return fmt.Sprintf("%d B", size)
+}
@@ -0,0 +1,323 @@
+package tasks
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	providerCmd "github.com/grik-ai/ricochet-task/cmd/providers"
+	"github.com/grik-ai/ricochet-task/internal/config"
+	"github.com/grik-ai/ricochet-task/pkg/ai"
+	"github.com/grik-ai/ricochet-task/pkg/key"
+	"github.com/grik-ai/ricochet-task/pkg/providers"
+	"github.com/grik-ai/ricochet-task/pkg/ui"
+)
+
+// AICmd represents the ai command
+var AICmd = &cobra.Command{
+	Use:   "ai",
+	Short: "AI workflows over the tasks of providers",
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if timeout, _ := cmd.Flags().GetDuration("timeout"); timeout < 0 {
+			return providers.NewValidationError("--timeout must not be negative", nil)
+		}
+		return initializeTasks(cmd)
+	},
+}
+
+var projectSummaryCmd = &cobra.Command{
+	Use:   "project-summary",
+	Short: "Write an AI status update of a project",
+	Long: `Aggregate the tasks of a project and have the AI chains write a status update:
+overall progress, work completed during the period, blockers, risks and next
+steps. With --post-to the update is added as a comment to that task, e.g. the
+project's epic; --dry-run only prints it.
+
+Without configured AI keys (see 'ricochet key add') the update is built from
+the task counts alone.
+
+Examples:
+  ricochet ai project-summary --project OPS
+  ricochet ai project-summary --project OPS --days 14 --post-to OPS-1
+  ricochet ai project-summary --project OPS --post-to OPS-1 --dry-run`,
+	RunE: runProjectSummary,
+}
+
+func init() {
+	AICmd.AddCommand(projectSummaryCmd)
+	AICmd.PersistentFlags().Duration("timeout", 0, "Deadline for the whole command (defaults to 2m, or the provider's timeout if longer)")
+
+	projectSummaryCmd.Flags().StringP("provider", "p", "", "Provider name (defaults to the default provider)")
+	projectSummaryCmd.Flags().String("project", "", "Project to summarize")
+	projectSummaryCmd.Flags().Int("days", 7, "Length of the reporting period in days")
+	projectSummaryCmd.Flags().Int("limit", 1000, "Maximum number of tasks to fetch")
+	projectSummaryCmd.Flags().String("post-to", "", "Task or epic to add the update to as a comment")
+	projectSummaryCmd.Flags().Bool("dry-run", false, "Print the update without posting it")
+	projectSummaryCmd.Flags().StringP("output", "o", "table", "Output format: table, json, yaml")
+	projectSummaryCmd.MarkFlagRequired("project")
+	projectSummaryCmd.RegisterFlagCompletionFunc("provider", providerCmd.CompleteProviderNames)
+}
+
+// projectSummaryResult is the structured output of project-summary
+type projectSummaryResult struct {
+	ProjectID       string                   `json:"projectId" yaml:"projectId"`
+	Since           time.Time                `json:"since" yaml:"since"`
+	PercentComplete int                      `json:"percentComplete" yaml:"percentComplete"`
+	Stats           *providers.TaskStats     `json:"stats" yaml:"stats"`
+	Summary         *ai.ProjectStatusSummary `json:"summary" yaml:"summary"`
+	PostedTo        string                   `json:"postedTo,omitempty" yaml:"postedTo,omitempty"`
+}
+
+func runProjectSummary(cmd *cobra.Command, args []string) error {
+	projectID := getStringFlag(cmd, "project")
+	postTo := getStringFlag(cmd, "post-to")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	days := getIntFlag(cmd, "days")
+	output := outputFormat(cmd)
+
+	if days <= 0 {
+		return providers.NewValidationError("--days must be positive", nil)
+	}
+
+	provider, err := selectProvider(getStringFlag(cmd, "provider"))
+	if err != nil {
+		return fmt.Errorf("failed to get provider: %w", err)
+	}
+
+	ctx, cancel := commandContext(cmd, getStringFlag(cmd, "provider"), 2*time.Minute)
+	defer cancel()
+
+	// Check where the update goes before spending an AI call on it
+	var commenter providers.CommentProvider
+	if postTo != "" && !dryRun {
+		var ok bool
+		if commenter, ok = providers.ProviderAs[providers.CommentProvider](provider); !ok {
+			return providers.NewValidationError("provider doesn't support comments, can't post the update", nil)
+		}
+		if _, err := provider.GetTask(ctx, postTo); err != nil {
+			return fmt.Errorf("failed to get task %s: %w", postTo, err)
+		}
+	}
+
+	tasks, err := provider.ListTasks(ctx, &providers.TaskFilters{ProjectID: projectID, Limit: getIntFlag(cmd, "limit")})
+	if err != nil {
+		return fmt.Errorf("failed to list tasks: %w", err)
+	}
+	if len(tasks) == 0 {
+		return providers.NewProviderError(providers.ErrorTypeNotFound, fmt.Sprintf("no tasks found in project %s", projectID), nil)
+	}
+
+	status := providers.BuildProjectStatus(projectID, tasks, time.Now().AddDate(0, 0, -days))
+	summary, err := newAIChains().SummarizeProjectStatus(status)
+	if err != nil {
+		return err
+	}
+
+	result := &projectSummaryResult{
+		ProjectID:       projectID,
+		Since:           status.Since,
+		PercentComplete: status.PercentComplete(),
+		Stats:           status.Stats,
+		Summary:         summary,
+	}
+	comment := summary.Markdown(fmt.Sprintf("Status update: %s (%s)", projectID, time.Now().Format("2006-01-02")))
+
+	if commenter != nil {
+		if err := commenter.AddComment(ctx, postTo, comment); err != nil {
+			return fmt.Errorf("failed to post the update to %s: %w", postTo, err)
+		}
+		result.PostedTo = postTo
+	}
+
+	switch output {
+	case "json":
+		return outputJSON(result)
+	case "yaml":
+		return outputYAML(result)
+	}
+
+	fmt.Println(comment)
+	switch {
+	case result.PostedTo != "":
+		fmt.Printf("💬 Posted to %s\n", result.PostedTo)
+	case postTo != "":
+		fmt.Printf("Dry run: not posted to %s\n", postTo)
+	}
+	return nil
+}
+
+// aiLogger passes log messages of AI chains to the command's logger. Their
+// progress messages are only interesting with --verbose.
+type aiLogger struct {
+	logger *logrus.Logger
+}
+
+func (l aiLogger) Info(msg string, args ...interface{}) {
+	l.logger.WithFields(aiLogFields(args)).Debug(msg)
+}
+
+func (l aiLogger) Error(msg string, err error, args ...interface{}) {
+	l.logger.WithFields(aiLogFields(args)).WithError(err).Error(msg)
+}
+
+func (l aiLogger) Warn(msg string, args ...interface{}) {
+	l.logger.WithFields(aiLogFields(args)).Warn(msg)
+}
+
+func (l aiLogger) Debug(msg string, args ...interface{}) {
+	l.logger.WithFields(aiLogFields(args)).Debug(msg)
+}
+
+// aiLogFields turns key/value arguments into log fields
+func aiLogFields(args []interface{}) logrus.Fields {
+	fields := logrus.Fields{}
+	for i := 0; i+1 < len(args); i += 2 {
+		if name, ok := args[i].(string); ok {
+			fields[name] = args[i+1]
+		}
+	}
+	return fields
+}
+
+// newAIChains creates AI chains with the API keys of the active profile,
+// added with 'ricochet key add'. Without working keys the chains fall back
+// to offline heuristics.
+func newAIChains() *ai.AIChains {
+	return ai.NewAIChains("", "", "", profileAPIKeys(), aiLogger{logger: logger})
+}
+
+// newAIScheduler paces the requests of a bulk AI operation to the aiChains
+// limits of the configuration. Offline chains make no requests, so they
+// aren't paced and nil is returned.
+func newAIScheduler(chains *ai.AIChains) *providers.AIScheduler {
+	if chains.Offline() {
+		return nil
+	}
+	scheduler := providers.NewAIScheduler(registry.AIChainConfig())
+	chains.OnUsage(scheduler.Record)
+	return scheduler
+}
+
+// aiProgress shows the progress of a bulk AI operation on one line of stderr
+// when running in a terminal
+type aiProgress struct {
+	label   string
+	enabled bool
+}
+
+func newAIProgress(label string, enabled bool) *aiProgress {
+	return &aiProgress{label: label, enabled: enabled && ui.IsInteractive()}
+}
+
+// update shows how many of the items are done
+func (p *aiProgress) update(done, total int) {
+	if p.enabled {
+		fmt.Fprintf(os.Stderr, "\r\033[K%s %d/%d", p.label, done, total)
+	}
+}
+
+// wait shows that the next request waits for a limit
+func (p *aiProgress) wait(delay time.Duration, reason string) {
+	if p.enabled {
+		fmt.Fprintf(os.Stderr, "\r\033[K⏳ Waiting %s for the %s", delay.Round(time.Second), reason)
+	}
+}
+
+// finish clears the progress line
+func (p *aiProgress) finish() {
+	if p.enabled {
+		fmt.Fprint(os.Stderr, "\r\033[K")
+	}
+}
+
+// watchPause lets Ctrl+C pause a bulk AI operation in a terminal: Enter
+// resumes it and a second Ctrl+C stops it by calling stop. The returned
+// function restores the default Ctrl+C handling.
+func watchPause(scheduler *providers.AIScheduler, stop context.CancelFunc) func() {
+	if scheduler == nil || !ui.IsInteractive() {
+		return func() {}
+	}
+
+	interrupts := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	signal.Notify(interrupts, os.Interrupt)
+
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			case <-interrupts:
+			}
+
+			if scheduler.Paused() {
+				fmt.Fprintln(os.Stderr, "\nStopping")
+				stop()
+				return
+			}
+			scheduler.Pause()
+			fmt.Fprintln(os.Stderr, "\n⏸  Paused after the current request. Press Enter to resume, Ctrl+C to stop")
+			go func() {
+				bufio.NewReader(os.Stdin).ReadString('\n')
+				if scheduler.Paused() {
+					fmt.Fprintln(os.Stderr, "▶️  Resumed")
+					scheduler.Resume()
+				}
+			}()
+		}
+	}()
+
+	return func() {
+		signal.Stop(interrupts)
+		close(done)
+	}
+}
+
+// profileAPIKeys returns the first key of each AI provider in the key store of the active profile
+func profileAPIKeys() *ai.UserAPIKeys {
+	keys := &ai.UserAPIKeys{}
+
+	dir, err := config.ProfileDir(config.ActiveProfile())
+	if err != nil {
+		return keys
+	}
+	store, err := key.NewFileKeyStore(dir)
+	if err != nil {
+		logger.Warnf("Failed to open key store: %v", err)
+		return keys
+	}
+	stored, err := store.List()
+	if err != nil {
+		logger.Warnf("Failed to read API keys: %v", err)
+		return keys
+	}
+
+	for _, k := range stored {
+		apiKey := &ai.APIKeyConfig{APIKey: k.Value, Enabled: true}
+		switch k.Provider {
+		case "openai":
+			if keys.OpenAI == nil {
+				keys.OpenAI = apiKey
+			}
+		case "anthropic":
+			if keys.Anthropic == nil {
+				keys.Anthropic = apiKey
+			}
+		case "deepseek":
+			if keys.DeepSeek == nil {
+				keys.DeepSeek = apiKey
+			}
+		case "grok":
+			if keys.Grok == nil {
+				keys.Grok = apiKey
+			}
+		}
+	}
+	return keys
+}
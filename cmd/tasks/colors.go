@@ -0,0 +1,55 @@
+package tasks
+
+import (
+	"fmt"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	"github.com/grik-ai/ricochet-task/pkg/providers"
+)
+
+var statusCategoryColors = map[providers.StatusCategory]*color.Color{
+	providers.StatusCategoryTodo:       color.New(color.FgCyan),
+	providers.StatusCategoryInProgress: color.New(color.FgYellow),
+	providers.StatusCategoryReview:     color.New(color.FgMagenta),
+	providers.StatusCategoryTesting:    color.New(color.FgMagenta),
+	providers.StatusCategoryDone:       color.New(color.FgGreen),
+	providers.StatusCategoryBlocked:    color.New(color.FgRed, color.Bold),
+	providers.StatusCategoryCancelled:  color.New(color.Faint),
+}
+
+var priorityColors = map[providers.TaskPriority]*color.Color{
+	providers.TaskPriorityCritical: color.New(color.FgRed, color.Bold),
+	providers.TaskPriorityHighest:  color.New(color.FgRed, color.Bold),
+	providers.TaskPriorityHigh:     color.New(color.FgRed),
+	providers.TaskPriorityMedium:   color.New(color.FgYellow),
+	providers.TaskPriorityLowest:   color.New(color.Faint),
+}
+
+// configureColor disables colored output when --no-color is set. The color
+// package already disables it when stdout isn't a terminal or NO_COLOR is set.
+func configureColor(cmd *cobra.Command) {
+	if noColor, _ := cmd.Flags().GetBool("no-color"); noColor {
+		color.NoColor = true
+	}
+}
+
+// colorStatus pads the status name to width and colors it by status category
+func colorStatus(status providers.TaskStatus, width int) string {
+	return colorize(statusCategoryColors[status.Category], status.Name, width)
+}
+
+// colorPriority pads the priority to width and colors it by severity
+func colorPriority(priority providers.TaskPriority, width int) string {
+	return colorize(priorityColors[priority], string(priority), width)
+}
+
+// colorize pads before coloring so escape codes don't break column alignment
+func colorize(c *color.Color, text string, width int) string {
+	padded := fmt.Sprintf("%-*s", width, text)
+	if c == nil {
+		return padded
+	}
+	return c.Sprint(padded)
+}
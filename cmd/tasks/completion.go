@@ -0,0 +1,170 @@
+package tasks
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	providerCmd "github.com/grik-ai/ricochet-task/cmd/providers"
+	"github.com/grik-ai/ricochet-task/pkg/providers"
+)
+
+// completionLabelSample is how many tasks per provider are scanned for label names
+const completionLabelSample = 200
+
+// registerCompletions adds dynamic shell completion to the flags naming
+// providers, statuses and labels
+func registerCompletions() {
+	TasksCmd.RegisterFlagCompletionFunc("provider", providerCmd.CompleteProviderNames)
+	TasksCmd.RegisterFlagCompletionFunc("providers", completeProviderList)
+	cloneCmd.RegisterFlagCompletionFunc("to-provider", providerCmd.CompleteProviderNames)
+
+	exportCmd.RegisterFlagCompletionFunc("include", completeArchiveInclude)
+	listCmd.RegisterFlagCompletionFunc("columns", completeColumns)
+	searchCmd.RegisterFlagCompletionFunc("columns", completeColumns)
+
+	for _, cmd := range []*cobra.Command{createCmd, listCmd, updateCmd, searchCmd, exportCmd} {
+		cmd.RegisterFlagCompletionFunc("status", completeStatuses)
+	}
+
+	for _, cmd := range []*cobra.Command{createCmd, listCmd, updateCmd} {
+		cmd.RegisterFlagCompletionFunc("labels", completeLabels)
+	}
+	for _, flag := range []string{"labels-all", "labels-any", "labels-none"} {
+		listCmd.RegisterFlagCompletionFunc(flag, completeLabels)
+	}
+	updateCmd.RegisterFlagCompletionFunc("add-labels", completeLabels)
+	updateCmd.RegisterFlagCompletionFunc("remove-labels", completeLabels)
+}
+
+// completeProviderList completes the comma-separated --providers flag
+func completeProviderList(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	return completeListItem(append([]string{"all"}, providerCmd.EnabledProviderNames()...), toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeArchiveInclude completes the comma-separated --include flag of export
+func completeArchiveInclude(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	items := []string{providers.ArchiveComments, providers.ArchiveAttachments, providers.ArchiveHistory, "all"}
+	return completeListItem(items, toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeColumns completes the comma-separated --columns flag
+func completeColumns(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	items := append(providers.TaskColumnNames(), providers.CustomColumnPrefix)
+	return completeListItem(items, toComplete), cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveNoSpace
+}
+
+// completeStatuses completes the status names available in the target
+// providers, restricted to --project when it is set
+func completeStatuses(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	ctx, cancel := context.WithTimeout(context.Background(), providerCmd.CompletionTimeout)
+	defer cancel()
+
+	completionRegistry, targets, err := completionTargets(ctx, cmd)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveError
+	}
+
+	projectID, _ := cmd.Flags().GetString("project")
+
+	var names []string
+	for _, name := range targets {
+		provider, err := completionRegistry.GetProvider(name)
+		if err != nil {
+			continue
+		}
+		statuses, err := provider.GetAvailableStatuses(ctx, projectID)
+		if err != nil {
+			continue
+		}
+		for _, status := range statuses {
+			names = append(names, status.Name)
+		}
+	}
+
+	return providerCmd.FilterCompletions(uniqueSorted(names), toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeLabels completes the comma-separated label flags with the labels of
+// recent tasks in the target providers
+func completeLabels(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	ctx, cancel := context.WithTimeout(context.Background(), providerCmd.CompletionTimeout)
+	defer cancel()
+
+	completionRegistry, targets, err := completionTargets(ctx, cmd)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveError
+	}
+
+	projectID, _ := cmd.Flags().GetString("project")
+	filters := &providers.TaskFilters{ProjectID: projectID, Limit: completionLabelSample}
+	result := providers.FetchTasks(ctx, targets, completionRegistry.GetProvider, filters, 0)
+
+	var labels []string
+	for _, task := range result.Tasks {
+		labels = append(labels, task.Labels...)
+	}
+
+	return completeListItem(uniqueSorted(labels), toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completionTargets initializes a registry for completion and picks the
+// providers named by --provider or --providers, or all enabled providers
+func completionTargets(ctx context.Context, cmd *cobra.Command) (*providers.ProviderRegistry, []string, error) {
+	completionRegistry, err := providerCmd.CompletionRegistry(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	providerName, _ := cmd.Flags().GetString("provider")
+	providerNames, _ := cmd.Flags().GetStringSlice("providers")
+
+	switch {
+	case providerName != "":
+		return completionRegistry, []string{providerName}, nil
+	case len(providerNames) > 0 && providerNames[0] != "all":
+		return completionRegistry, providerNames, nil
+	}
+
+	var targets []string
+	for name := range completionRegistry.ListEnabledProviders() {
+		targets = append(targets, name)
+	}
+	return completionRegistry, targets, nil
+}
+
+// completeListItem completes the last item of a comma-separated list,
+// keeping the items typed before it and skipping the ones already listed
+func completeListItem(candidates []string, toComplete string) []string {
+	prefix := ""
+	typed := make(map[string]bool)
+	if i := strings.LastIndex(toComplete, ","); i >= 0 {
+		prefix, toComplete = toComplete[:i+1], toComplete[i+1:]
+		for _, item := range strings.Split(prefix, ",") {
+			typed[item] = true
+		}
+	}
+
+	var matches []string
+	for _, candidate := range providerCmd.FilterCompletions(candidates, toComplete) {
+		if !typed[candidate] {
+			matches = append(matches, prefix+candidate)
+		}
+	}
+	return matches
+}
+
+func uniqueSorted(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	var unique []string
+	for _, value := range values {
+		if value != "" && !seen[value] {
+			seen[value] = true
+			unique = append(unique, value)
+		}
+	}
+	sort.Strings(unique)
+	return unique
+}
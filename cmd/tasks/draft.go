@@ -0,0 +1,320 @@
+package tasks
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/grik-ai/ricochet-task/internal/config"
+	"github.com/grik-ai/ricochet-task/pkg/providers"
+)
+
+var draftCmd = &cobra.Command{
+	Use:   "draft",
+	Short: "Prepare tasks locally and submit them later",
+	Long: `Keep tasks as local drafts until they are ready, e.g. to prepare a batch of
+tasks from templates and create them all at once. Drafts are stored in
+drafts.json of the active profile and get their task ID from the provider on submit.
+
+Examples:
+  ricochet tasks draft save --title "Rotate API keys" --template incident
+  ricochet tasks draft save --title "Update runbook" --labels docs --provider youtrack-prod
+  ricochet tasks draft list
+  ricochet tasks draft submit 1f0c9a2e --provider youtrack-prod
+  ricochet tasks draft submit --all`,
+}
+
+var draftSaveCmd = &cobra.Command{
+	Use:   "save",
+	Short: "Save a task as a draft",
+	Long: `Save a task as a draft. The flags are those of 'tasks create'; --provider
+records where the draft is submitted to unless 'draft submit' names another
+provider. With --draft, an existing draft is replaced.`,
+	Args: cobra.NoArgs,
+	RunE: runDraftSave,
+}
+
+var draftListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List task drafts",
+	Args:  cobra.NoArgs,
+	RunE:  runDraftList,
+}
+
+var draftSubmitCmd = &cobra.Command{
+	Use:   "submit [id...]",
+	Short: "Create the tasks of drafts in their provider",
+	Long: `Create the tasks of drafts and remove the submitted drafts. Drafts are
+referred to by the ID shown in 'draft list', or a unique prefix of it.
+
+If the provider can't be reached, the task moves into the offline queue and is
+created by 'ricochet sync flush'. A draft the provider rejects is kept.`,
+	RunE: runDraftSubmit,
+}
+
+var draftDeleteCmd = &cobra.Command{
+	Use:   "delete [id...]",
+	Short: "Delete drafts without submitting them",
+	Args:  cobra.MinimumNArgs(1),
+	RunE:  runDraftDelete,
+}
+
+func init() {
+	TasksCmd.AddCommand(draftCmd)
+	draftCmd.AddCommand(draftSaveCmd)
+	draftCmd.AddCommand(draftListCmd)
+	draftCmd.AddCommand(draftSubmitCmd)
+	draftCmd.AddCommand(draftDeleteCmd)
+
+	draftSaveCmd.Flags().StringP("title", "t", "", "Task title")
+	draftSaveCmd.Flags().StringP("description", "d", "", "Task description")
+	draftSaveCmd.Flags().String("project", "", "Project ID (defaults to the provider's defaultProject on submit)")
+	draftSaveCmd.Flags().String("type", "task", "Task type (task, bug, feature, etc.)")
+	draftSaveCmd.Flags().String("priority", "medium", "Task priority (low, medium, high, critical)")
+	draftSaveCmd.Flags().String("status", "", "Initial status")
+	draftSaveCmd.Flags().String("assignee", "", "Assignee ID or username, or \"me\" for the current user")
+	draftSaveCmd.Flags().StringSlice("labels", []string{}, "Task labels")
+	draftSaveCmd.Flags().String("due", "", "Due date: "+providers.DueDateFormats+", in the configured time zone")
+	draftSaveCmd.Flags().String("template", "", "Task template to pre-fill fields from (see 'tasks template list')")
+	draftSaveCmd.Flags().String("draft", "", "ID of a draft to replace")
+	draftSaveCmd.MarkFlagRequired("title")
+
+	draftSubmitCmd.Flags().Bool("all", false, "Submit all drafts")
+	draftSubmitCmd.Flags().Bool("create-labels", false, "Create labels that don't exist in the provider instead of rejecting them")
+}
+
+// taskDrafts opens the draft store of the active profile
+func taskDrafts() (*providers.TaskDraftStore, error) {
+	store, err := providers.NewTaskDraftStore(config.ProfilePath(providers.TaskDraftsFile), logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load drafts: %w", err)
+	}
+	return store, nil
+}
+
+func runDraftSave(cmd *cobra.Command, args []string) error {
+	priority, err := mapPriority(getStringFlag(cmd, "provider"), getStringFlag(cmd, "priority"))
+	if err != nil {
+		return err
+	}
+
+	task := &providers.UniversalTask{
+		Title:       getStringFlag(cmd, "title"),
+		Description: getStringFlag(cmd, "description"),
+		ProjectID:   getStringFlag(cmd, "project"),
+		Type:        providers.TaskType(getStringFlag(cmd, "type")),
+		Priority:    priority,
+		AssigneeID:  getStringFlag(cmd, "assignee"),
+		Labels:      getStringSliceFlag(cmd, "labels"),
+	}
+
+	templateName := getStringFlag(cmd, "template")
+	if templateName != "" {
+		templated, err := newTaskFromTemplate(cmd, templateName, task)
+		if err != nil {
+			return err
+		}
+		task = templated
+	}
+
+	if status := getStringFlag(cmd, "status"); status != "" {
+		task.Status = typedStatus(status)
+	}
+
+	due, err := dueDateFlag(cmd, "due")
+	if err != nil {
+		return err
+	}
+	if due != nil {
+		task.DueDate = due
+	}
+
+	store, err := taskDrafts()
+	if err != nil {
+		return err
+	}
+
+	draft := &providers.TaskDraft{
+		Provider: getStringFlag(cmd, "provider"),
+		Template: templateName,
+		Task:     task,
+	}
+	if id := getStringFlag(cmd, "draft"); id != "" {
+		existing, err := store.Get(id)
+		if err != nil {
+			return err
+		}
+		draft.ID = existing.ID
+	}
+
+	saved, err := store.Save(draft)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("📝 Draft saved (%s)\n", shortQueueID(saved.ID))
+	fmt.Printf("Title: %s\n", saved.Task.Title)
+	fmt.Printf("Submit it with 'ricochet tasks draft submit %s'\n", shortQueueID(saved.ID))
+	return nil
+}
+
+func runDraftList(cmd *cobra.Command, args []string) error {
+	store, err := taskDrafts()
+	if err != nil {
+		return err
+	}
+	drafts := store.List()
+
+	switch outputFormat(cmd) {
+	case "json":
+		return outputJSON(drafts)
+	case "yaml":
+		return outputYAML(drafts)
+	}
+
+	if len(drafts) == 0 {
+		fmt.Println("No drafts")
+		return nil
+	}
+
+	fmt.Printf("%-10s %-15s %-40s %-10s %-10s %s\n", "ID", "PROVIDER", "TITLE", "TYPE", "PRIORITY", "UPDATED")
+	fmt.Printf("%-10s %-15s %-40s %-10s %-10s %s\n", "--", "--------", "-----", "----", "--------", "-------")
+	for _, draft := range drafts {
+		title := draft.Task.Title
+		if len(title) > 37 {
+			title = title[:37] + "..."
+		}
+		fmt.Printf("%-10s %-15s %-40s %-10s %-10s %s\n",
+			shortQueueID(draft.ID),
+			draft.Provider,
+			title,
+			draft.Task.Type,
+			draft.Task.Priority,
+			draft.UpdatedAt.Local().Format("2006-01-02 15:04"),
+		)
+	}
+	fmt.Printf("\n%d drafts\n", len(drafts))
+	return nil
+}
+
+func runDraftSubmit(cmd *cobra.Command, args []string) error {
+	all, _ := cmd.Flags().GetBool("all")
+	if all == (len(args) > 0) {
+		return providers.NewValidationError("name the drafts to submit or use --all", nil)
+	}
+
+	store, err := taskDrafts()
+	if err != nil {
+		return err
+	}
+
+	var drafts []*providers.TaskDraft
+	if all {
+		drafts = store.List()
+	} else {
+		for _, id := range args {
+			draft, err := store.Get(id)
+			if err != nil {
+				return err
+			}
+			drafts = append(drafts, draft)
+		}
+	}
+
+	failed := 0
+	for _, draft := range drafts {
+		if err := submitDraft(cmd, draft); err != nil {
+			if len(drafts) == 1 {
+				return err
+			}
+			fmt.Printf("❌ Draft %s (%s): %v\n", shortQueueID(draft.ID), draft.Task.Title, err)
+			failed++
+			continue
+		}
+		if err := store.Delete(draft.ID); err != nil {
+			return err
+		}
+	}
+
+	if failed > 0 {
+		return providers.NewPartialFailureError(failed, len(drafts), "drafts")
+	}
+	return nil
+}
+
+// submitDraft creates the task of a draft, or queues it while the provider is
+// unreachable. The provider given with --provider wins over the draft's own.
+func submitDraft(cmd *cobra.Command, draft *providers.TaskDraft) error {
+	providerName := getStringFlag(cmd, "provider")
+	if providerName == "" {
+		providerName = draft.Provider
+	}
+	providerName, err := resolveProviderName(providerName)
+	if err != nil {
+		return err
+	}
+
+	task := draft.Task
+	if task.ProjectID == "" {
+		task.ProjectID = registry.DefaultProject(providerName)
+	}
+	task.CreatedAt = time.Now()
+	task.UpdatedAt = task.CreatedAt
+
+	createLabels, _ := cmd.Flags().GetBool("create-labels")
+	queued := &providers.QueuedOperation{Type: providers.QueuedCreate, Provider: providerName, Task: task, CreateLabels: createLabels}
+
+	provider, err := registry.GetProvider(providerName)
+	if err != nil {
+		return fmt.Errorf("failed to get provider: %w", err)
+	}
+
+	ctx, cancel := commandContext(cmd, providerName, defaultTaskTimeout)
+	defer cancel()
+
+	if task.AssigneeID, err = providers.ResolveAssignee(ctx, provider, task.AssigneeID); err != nil {
+		if providers.IsConnectivityError(err) {
+			return queueOperation(queued, err)
+		}
+		return err
+	}
+	checked, err := providers.ValidateLabels(ctx, provider, task.ProjectID, task.Labels, createLabels)
+	if err != nil {
+		if providers.IsConnectivityError(err) {
+			return queueOperation(queued, err)
+		}
+		return labelsError(err)
+	}
+	task.Labels = checked
+
+	createdTask, err := provider.CreateTask(ctx, task)
+	if err != nil {
+		if providers.IsConnectivityError(err) {
+			return queueOperation(queued, err)
+		}
+		return fmt.Errorf("failed to create task: %w", err)
+	}
+
+	fmt.Printf("✅ Draft %s submitted as %s: %s\n", shortQueueID(draft.ID), createdTask.GetDisplayID(), createdTask.Title)
+	return nil
+}
+
+func runDraftDelete(cmd *cobra.Command, args []string) error {
+	store, err := taskDrafts()
+	if err != nil {
+		return err
+	}
+
+	for _, id := range args {
+		draft, err := store.Get(id)
+		if err != nil {
+			return err
+		}
+		if err := store.Delete(draft.ID); err != nil {
+			return err
+		}
+		fmt.Printf("🗑️  Deleted draft %s (%s)\n", shortQueueID(draft.ID), draft.Task.Title)
+	}
+	return nil
+}
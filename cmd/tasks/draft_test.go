@@ -0,0 +1,30 @@
+package tasks
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grik-ai/ricochet-task/internal/config"
+	"github.com/grik-ai/ricochet-task/pkg/providers"
+)
+
+func TestTaskDraftsUseActiveProfile(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv(config.ProfileEnv, "work")
+
+	drafts, err := taskDrafts()
+	require.NoError(t, err)
+	_, err = drafts.Save(&providers.TaskDraft{Task: &providers.UniversalTask{Title: "Rotate API keys"}})
+	require.NoError(t, err)
+	assert.FileExists(t, filepath.Join(home, ".ricochet", "profiles", "work", providers.TaskDraftsFile))
+
+	// Another profile doesn't see the draft
+	t.Setenv(config.ProfileEnv, "personal")
+	drafts, err = taskDrafts()
+	require.NoError(t, err)
+	assert.Empty(t, drafts.List())
+}
@@ -0,0 +1,151 @@
+package tasks
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/grik-ai/ricochet-task/pkg/providers"
+)
+
+var createEpicCmd = &cobra.Command{
+	Use:   "create-epic",
+	Short: "Create an epic together with its child tasks",
+	Long: `Create an epic and all of its child tasks in one operation.
+
+The children are read from a JSON, YAML or CSV file in the format of
+bulk-create. Children without a parent become subtasks of the epic, all of
+them get the epic as their epic and inherit its project unless they name
+another. Children can reference each other through temporary IDs like
+"$tmp:api" in parent, blockedBy and blocks, and the epic as "$tmp:epic". The
+tasks are created in dependency order and, in providers that support links,
+linked as subtasks and dependencies.
+
+If creating or linking any task fails, the tasks created so far are deleted
+again, children first, unless --no-rollback is given. Rolling back is best
+effort: tasks that can't be deleted are reported.
+
+Examples:
+  ricochet tasks create-epic --title "Checkout" --children children.yaml --provider youtrack-prod
+  ricochet tasks create-epic --title "Checkout" --project SHOP --children children.csv --dry-run
+  ricochet tasks create-epic --title "Checkout" --children children.yaml --no-rollback -o json`,
+	Args: cobra.NoArgs,
+	RunE: runCreateEpic,
+}
+
+func init() {
+	TasksCmd.AddCommand(createEpicCmd)
+
+	createEpicCmd.Flags().StringP("title", "t", "", "Epic title")
+	createEpicCmd.Flags().StringP("description", "d", "", "Epic description")
+	createEpicCmd.Flags().String("project", "", "Project ID of the epic and its children (defaults to the provider's defaultProject)")
+	createEpicCmd.Flags().String("priority", "medium", "Epic priority (low, medium, high, critical)")
+	createEpicCmd.Flags().String("assignee", "", "Epic assignee ID or username, or \"me\" for the current user")
+	createEpicCmd.Flags().String("children", "", "File with the child tasks (JSON, YAML or CSV, as for bulk-create)")
+	createEpicCmd.Flags().StringToString("mapping", map[string]string{}, "Map CSV columns to task fields, e.g. Summary=title,Owner=assignee (- skips a column)")
+	createEpicCmd.Flags().Bool("dry-run", false, "Show what would be created without making changes")
+	createEpicCmd.Flags().Bool("no-rollback", false, "Keep the tasks created before a failure instead of deleting them")
+	createEpicCmd.MarkFlagRequired("title")
+	createEpicCmd.MarkFlagRequired("children")
+}
+
+func runCreateEpic(cmd *cobra.Command, args []string) error {
+	childrenFile := getStringFlag(cmd, "children")
+	mapping, _ := cmd.Flags().GetStringToString("mapping")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	noRollback, _ := cmd.Flags().GetBool("no-rollback")
+
+	if len(mapping) > 0 && !strings.EqualFold(filepath.Ext(childrenFile), ".csv") {
+		return providers.NewValidationError("--mapping only applies to CSV files", nil)
+	}
+
+	providerName, err := resolveProviderName(getStringFlag(cmd, "provider"))
+	if err != nil {
+		return fmt.Errorf("failed to get provider: %w", err)
+	}
+
+	priority, err := mapPriority(providerName, getStringFlag(cmd, "priority"))
+	if err != nil {
+		return err
+	}
+	epic := &providers.UniversalTask{
+		Title:       getStringFlag(cmd, "title"),
+		Description: getStringFlag(cmd, "description"),
+		ProjectID:   getStringFlag(cmd, "project"),
+		Type:        providers.TaskTypeEpic,
+		Priority:    priority,
+		AssigneeID:  getStringFlag(cmd, "assignee"),
+	}
+	if epic.ProjectID == "" && !cmd.Flags().Changed("project") {
+		epic.ProjectID = registry.DefaultProject(providerName)
+	}
+
+	children, err := readBulkTaskFile(childrenFile, mapping)
+	if err != nil {
+		return err
+	}
+	tasks, err := providers.EpicTasks(epic, children)
+	if err != nil {
+		return err
+	}
+	steps, err := planBulkTasks(tasks)
+	if err != nil {
+		return err
+	}
+
+	if dryRun {
+		fmt.Printf("Dry run - would create epic %q with %d child tasks:\n", epic.Title, len(children))
+		outputBulkPlan(steps)
+		return nil
+	}
+
+	provider, err := registry.GetProvider(providerName)
+	if err != nil {
+		return fmt.Errorf("failed to get provider: %w", err)
+	}
+
+	ctx, cancel := commandContext(cmd, providerName, 0)
+	defer cancel()
+
+	if epic.AssigneeID, err = providers.ResolveAssignee(ctx, provider, epic.AssigneeID); err != nil {
+		return err
+	}
+
+	result, err := providers.CreateEpic(ctx, provider, epic, children, !noRollback)
+	if result == nil {
+		return err
+	}
+
+	switch outputFormat(cmd) {
+	case "json":
+		if outputErr := outputJSON(result); outputErr != nil {
+			return outputErr
+		}
+		return err
+	case "yaml":
+		if outputErr := outputYAML(result); outputErr != nil {
+			return outputErr
+		}
+		return err
+	}
+
+	if err == nil {
+		fmt.Printf("✅ Epic %s created with %d child tasks\n", result.Epic.Task.GetDisplayID(), len(result.Children))
+	} else {
+		fmt.Printf("❌ Failed to create epic %q\n", epic.Title)
+	}
+	outputBulkResults(append([]*providers.BulkCreateResult{result.Epic}, result.Children...))
+
+	if len(result.RolledBack) > 0 {
+		fmt.Printf("\nRolled back: deleted %s\n", strings.Join(result.RolledBack, ", "))
+	}
+	if len(result.RollbackErrors) > 0 {
+		fmt.Println("\n⚠️  Could not delete these tasks, remove them manually:")
+		for _, rollbackErr := range result.RollbackErrors {
+			fmt.Printf("  %s\n", rollbackErr)
+		}
+	}
+	return err
+}
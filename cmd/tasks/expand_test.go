@@ -0,0 +1,162 @@
+package tasks
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grik-ai/ricochet-task/pkg/providers"
+)
+
+const expandProviderType providers.ProviderType = "tasks-expand-test"
+
+// expandProvider serves one task and records description updates
+type expandProvider struct {
+	providers.TaskProvider
+	task    *providers.UniversalTask
+	updates []*providers.TaskUpdate
+}
+
+func (p *expandProvider) GetTask(ctx context.Context, id string) (*providers.UniversalTask, error) {
+	return p.task, nil
+}
+
+func (p *expandProvider) UpdateTask(ctx context.Context, id string, updates *providers.TaskUpdate) error {
+	p.updates = append(p.updates, updates)
+	return nil
+}
+
+func (p *expandProvider) GetProviderInfo() *providers.ProviderInfo {
+	return &providers.ProviderInfo{Name: "tracker", Type: expandProviderType}
+}
+
+func (p *expandProvider) HealthCheck(ctx context.Context) error { return nil }
+func (p *expandProvider) Close() error                          { return nil }
+
+// expandPlugin hands the registry the expandProvider of the current test
+type expandPlugin struct {
+	providers.TaskManagerPlugin
+}
+
+var currentExpandProvider *expandProvider
+
+func (p *expandPlugin) Initialize(config *providers.ProviderConfig) error { return nil }
+func (p *expandPlugin) GetProvider() providers.TaskProvider               { return currentExpandProvider }
+func (p *expandPlugin) Cleanup() error                                    { return nil }
+
+func init() {
+	providers.RegisterProviderType(expandProviderType, func() providers.TaskManagerPlugin { return &expandPlugin{} })
+}
+
+// useExpandProvider makes a provider serving task the default provider of the
+// commands and uses the offline AI chains
+func useExpandProvider(t *testing.T, task *providers.UniversalTask) *expandProvider {
+	t.Helper()
+	currentExpandProvider = &expandProvider{task: task}
+	useTestProviders(t, "tracker", "tracker")
+	return currentExpandProvider
+}
+
+// useTestProviders makes the commands use a registry with the named enabled
+// providers, all served by the current expandProvider
+func useTestProviders(t *testing.T, defaultName string, names ...string) {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+
+	if currentExpandProvider == nil {
+		currentExpandProvider = &expandProvider{}
+	}
+	config := providers.DefaultMultiProviderConfig()
+	config.DefaultProvider = defaultName
+	for _, name := range names {
+		config.Providers[name] = &providers.ProviderConfig{Name: name, Type: expandProviderType, Enabled: true}
+	}
+
+	logger = logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+	registry = providers.NewProviderRegistry(config, logger)
+	require.NoError(t, registry.Initialize(context.Background()))
+	t.Cleanup(func() {
+		registry.Shutdown(context.Background())
+		registry = nil
+	})
+}
+
+// captureStdout returns what run prints to stdout
+func captureStdout(t *testing.T, run func() error) string {
+	t.Helper()
+	reader, writer, err := os.Pipe()
+	require.NoError(t, err)
+
+	stdout := os.Stdout
+	os.Stdout = writer
+	runErr := run()
+	os.Stdout = stdout
+	writer.Close()
+
+	output, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	require.NoError(t, runErr)
+	return string(output)
+}
+
+// newExpandCommand returns a command with the flags runExpandTask reads
+func newExpandCommand(apply bool) *cobra.Command {
+	cmd := &cobra.Command{}
+	cmd.Flags().String("provider", "", "")
+	cmd.Flags().Bool("apply", apply, "")
+	cmd.Flags().StringP("output", "o", "table", "")
+	cmd.Flags().Set("output", "json")
+	return cmd
+}
+
+func TestExpandTask(t *testing.T) {
+	task := &providers.UniversalTask{ID: "1", Key: "OPS-1", Title: "Rotate certificates", Description: "Certificates expire in March", Type: providers.TaskTypeTask}
+
+	t.Run("Review only", func(t *testing.T) {
+		provider := useExpandProvider(t, task)
+
+		output := captureStdout(t, func() error { return runExpandTask(newExpandCommand(false), []string{"1"}) })
+
+		var result expandResult
+		require.NoError(t, json.Unmarshal([]byte(output), &result))
+		assert.Equal(t, "OPS-1", result.TaskID)
+		assert.False(t, result.Applied)
+		assert.Contains(t, result.Description, "Certificates expire in March\n\n## Context")
+		require.NotNil(t, result.Expansion)
+		assert.NotEmpty(t, result.Expansion.AcceptanceCriteria)
+		assert.Empty(t, provider.updates, "structured output without --apply doesn't change the task")
+	})
+
+	t.Run("Apply", func(t *testing.T) {
+		provider := useExpandProvider(t, task)
+
+		output := captureStdout(t, func() error { return runExpandTask(newExpandCommand(true), []string{"1"}) })
+
+		var result expandResult
+		require.NoError(t, json.Unmarshal([]byte(output), &result))
+		assert.True(t, result.Applied)
+		require.Len(t, provider.updates, 1)
+		assert.Equal(t, result.Description, *provider.updates[0].Description)
+	})
+
+	t.Run("New task", func(t *testing.T) {
+		useExpandProvider(t, task)
+		created := &providers.UniversalTask{Title: "Fix login crash", Description: "Reported by support", Type: providers.TaskTypeBug}
+
+		// Outside a terminal the generated description is used without review
+		captureStdout(t, func() error {
+			expandNewTask(created)
+			return nil
+		})
+		assert.Contains(t, created.Description, "Reported by support\n\n## Context")
+		assert.Contains(t, created.Description, "A regression test covers the scenario")
+	})
+}
@@ -0,0 +1,128 @@
+package tasks
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/grik-ai/ricochet-task/pkg/providers"
+)
+
+var checkIntegrityCmd = &cobra.Command{
+	Use:   "check-integrity",
+	Short: "Find parent and dependency links to tasks that no longer exist",
+	Long: `Scan the tasks of a project for references to tasks that don't exist, e.g.
+children left pointing at a deleted parent: the parent, blocked-by and blocks
+links of every task. References to tasks outside the project are looked up
+before they are reported.
+
+Dangling references are only reported unless --fix is given, which removes
+the broken links. The command exits with an error while dangling references
+remain, so it can run in CI.
+
+Examples:
+  ricochet tasks check-integrity --project OPS
+  ricochet tasks check-integrity --project OPS --fix
+  ricochet tasks check-integrity --project OPS --output json`,
+	Args: cobra.NoArgs,
+	RunE: runCheckIntegrity,
+}
+
+func init() {
+	TasksCmd.AddCommand(checkIntegrityCmd)
+
+	checkIntegrityCmd.Flags().String("project", "", "Project to check (defaults to the provider's defaultProject)")
+	checkIntegrityCmd.Flags().Bool("fix", false, "Remove the links behind dangling references")
+	checkIntegrityCmd.Flags().Int("limit", 1000, "Maximum number of project tasks to check")
+}
+
+func runCheckIntegrity(cmd *cobra.Command, args []string) error {
+	providerName, _ := cmd.Flags().GetString("provider")
+	fix, _ := cmd.Flags().GetBool("fix")
+
+	name, err := resolveProviderName(providerName)
+	if err != nil {
+		return err
+	}
+	provider, err := registry.GetProvider(name)
+	if err != nil {
+		return fmt.Errorf("failed to get provider: %w", err)
+	}
+
+	project := getStringFlag(cmd, "project")
+	if project == "" {
+		project = registry.DefaultProject(name)
+	}
+	if project == "" {
+		return providers.NewValidationError("--project is required when the provider has no defaultProject", nil)
+	}
+
+	ctx, cancel := commandContext(cmd, name, defaultListTimeout)
+	defer cancel()
+
+	tasks, err := provider.ListTasks(ctx, &providers.TaskFilters{ProjectID: project, Limit: getIntFlag(cmd, "limit")})
+	if err != nil {
+		return fmt.Errorf("failed to list tasks of %s: %w", project, err)
+	}
+
+	report, err := providers.CheckIntegrity(ctx, provider, tasks)
+	if err != nil {
+		return err
+	}
+
+	var fixErr error
+	if fix {
+		fixErr = providers.RepairIntegrity(ctx, provider, report)
+		if fixErr != nil && !isPartialFailure(fixErr) {
+			return fixErr
+		}
+	}
+
+	switch outputFormat(cmd) {
+	case "json":
+		err = outputJSON(report)
+	case "yaml":
+		err = outputYAML(report)
+	default:
+		outputIntegrityReport(project, report, fix)
+	}
+	if err != nil {
+		return err
+	}
+
+	if fixErr != nil {
+		return fixErr
+	}
+	if !fix && len(report.Dangling) > 0 {
+		return fmt.Errorf("%d dangling references found, run with --fix to remove them", len(report.Dangling))
+	}
+	return nil
+}
+
+func outputIntegrityReport(project string, report *providers.IntegrityReport, fix bool) {
+	fmt.Printf("Checked %d tasks of %s, %d references\n", report.Checked, project, report.References)
+	if len(report.Dangling) == 0 {
+		fmt.Println("✅ No dangling references")
+		return
+	}
+
+	fmt.Printf("\n%-15s %-10s %-15s %s\n", "TASK", "FIELD", "MISSING", "TITLE")
+	for _, reference := range report.Dangling {
+		title := reference.Title
+		if len(title) > 47 {
+			title = title[:47] + "..."
+		}
+		line := fmt.Sprintf("%-15s %-10s %-15s %s", reference.TaskID, reference.Field, reference.TargetID, title)
+		switch {
+		case reference.Fixed:
+			line = "✓ " + line
+		case reference.Error != "":
+			line = "✗ " + line + " (" + reference.Error + ")"
+		}
+		fmt.Println(line)
+	}
+
+	if fix {
+		fmt.Printf("\n🔧 Removed %d of %d dangling references\n", report.Fixed, len(report.Dangling))
+	}
+}
@@ -0,0 +1,210 @@
+package tasks
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/grik-ai/ricochet-task/internal/config"
+	"github.com/grik-ai/ricochet-task/pkg/providers"
+)
+
+var noteCmd = &cobra.Command{
+	Use:   "note",
+	Short: "Manage local notes on tasks",
+	Long: `Keep private notes on tasks: reminders, findings, working memory for AI
+sessions. Notes are stored locally in notes.json of the active profile, keyed by provider
+and task ID, and are never sent to the provider. 'ricochet tasks get' shows them
+under "Local notes".
+
+Examples:
+  ricochet tasks note set PROJ-123 "check the migration before merging"
+  ricochet tasks note set PROJ-123 --append "bob owns the rollout"
+  git log -5 --oneline | ricochet tasks note set PROJ-123 -
+  ricochet tasks note get PROJ-123
+  ricochet tasks note clear PROJ-123`,
+}
+
+var noteSetCmd = &cobra.Command{
+	Use:   "set [id] [text]",
+	Short: "Set the note of a task, \"-\" reads the text from stdin",
+	Args:  cobra.MinimumNArgs(2),
+	RunE:  runNoteSet,
+}
+
+var noteGetCmd = &cobra.Command{
+	Use:   "get [id]",
+	Short: "Show the note of a task",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runNoteGet,
+}
+
+var noteClearCmd = &cobra.Command{
+	Use:   "clear [id]",
+	Short: "Delete the note of a task",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runNoteClear,
+}
+
+func init() {
+	TasksCmd.AddCommand(noteCmd)
+	noteCmd.AddCommand(noteSetCmd)
+	noteCmd.AddCommand(noteGetCmd)
+	noteCmd.AddCommand(noteClearCmd)
+
+	noteSetCmd.Flags().Bool("append", false, "Add the text as a new line instead of replacing the note")
+}
+
+// taskNotes opens the note store of the active profile
+func taskNotes() (*providers.TaskNoteStore, error) {
+	return providers.NewTaskNoteStore(config.ProfilePath(providers.NotesFile), logger)
+}
+
+func runNoteSet(cmd *cobra.Command, args []string) error {
+	providerName, _ := cmd.Flags().GetString("provider")
+	appendText, _ := cmd.Flags().GetBool("append")
+
+	text := strings.Join(args[1:], " ")
+	if text == "-" {
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return fmt.Errorf("failed to read note from stdin: %w", err)
+		}
+		text = string(data)
+	}
+
+	name, err := resolveProviderName(providerName)
+	if err != nil {
+		return err
+	}
+	provider, err := registry.GetProvider(name)
+	if err != nil {
+		return fmt.Errorf("failed to get provider: %w", err)
+	}
+
+	// Make sure the task exists, and key the note by the ID 'tasks get' shows
+	ctx, cancel := commandContext(cmd, name, defaultTaskTimeout)
+	defer cancel()
+
+	task, err := provider.GetTask(ctx, args[0])
+	if err != nil {
+		return fmt.Errorf("failed to get task: %w", err)
+	}
+
+	notes, err := taskNotes()
+	if err != nil {
+		return fmt.Errorf("failed to load notes: %w", err)
+	}
+
+	save := notes.Set
+	if appendText {
+		save = notes.Append
+	}
+	note, err := save(name, task.GetDisplayID(), text)
+	if err != nil {
+		return fmt.Errorf("failed to save note: %w", err)
+	}
+
+	switch outputFormat(cmd) {
+	case "json":
+		return outputJSON(note)
+	case "yaml":
+		return outputYAML(note)
+	default:
+		fmt.Printf("📝 Saved note for %s: %s\n", task.GetDisplayID(), task.Title)
+		return nil
+	}
+}
+
+func runNoteGet(cmd *cobra.Command, args []string) error {
+	providerName, _ := cmd.Flags().GetString("provider")
+
+	name, err := resolveProviderName(providerName)
+	if err != nil {
+		return err
+	}
+
+	notes, err := taskNotes()
+	if err != nil {
+		return fmt.Errorf("failed to load notes: %w", err)
+	}
+
+	taskID := noteTaskID(cmd, notes, name, args[0])
+	note, ok := notes.Get(name, taskID)
+	if !ok {
+		return providers.NewProviderError(providers.ErrorTypeNotFound, fmt.Sprintf("no note for task %s in %s", args[0], name), nil)
+	}
+
+	switch outputFormat(cmd) {
+	case "json":
+		return outputJSON(note)
+	case "yaml":
+		return outputYAML(note)
+	default:
+		fmt.Println(note.Text)
+		return nil
+	}
+}
+
+func runNoteClear(cmd *cobra.Command, args []string) error {
+	providerName, _ := cmd.Flags().GetString("provider")
+
+	name, err := resolveProviderName(providerName)
+	if err != nil {
+		return err
+	}
+
+	notes, err := taskNotes()
+	if err != nil {
+		return fmt.Errorf("failed to load notes: %w", err)
+	}
+
+	taskID := noteTaskID(cmd, notes, name, args[0])
+	if err := notes.Clear(name, taskID); err != nil {
+		return err
+	}
+
+	fmt.Printf("🗑️  Cleared note for %s\n", taskID)
+	return nil
+}
+
+// noteTaskID returns the ID a task's note is stored under. Notes are keyed by
+// display ID, so when there is no note under the given ID, for example an
+// internal one, the task is looked up to get its display ID.
+func noteTaskID(cmd *cobra.Command, notes *providers.TaskNoteStore, providerName, taskID string) string {
+	if _, ok := notes.Get(providerName, taskID); ok {
+		return taskID
+	}
+
+	provider, err := registry.GetProvider(providerName)
+	if err != nil {
+		return taskID
+	}
+	ctx, cancel := commandContext(cmd, providerName, defaultTaskTimeout)
+	defer cancel()
+
+	task, err := provider.GetTask(ctx, taskID)
+	if err != nil {
+		logger.Debugf("Failed to look up task %s: %v", taskID, err)
+		return taskID
+	}
+	return task.GetDisplayID()
+}
+
+// outputTaskNote prints the local note of a task after its details
+func outputTaskNote(providerName string, task *providers.UniversalTask) {
+	notes, err := taskNotes()
+	if err != nil {
+		logger.Warnf("Failed to load notes: %v", err)
+		return
+	}
+	note, ok := notes.Get(providerName, task.GetDisplayID())
+	if !ok {
+		return
+	}
+
+	fmt.Printf("\nLocal notes (%s):\n%s\n", note.UpdatedAt.Format("2006-01-02 15:04"), note.Text)
+}
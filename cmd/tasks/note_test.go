@@ -0,0 +1,32 @@
+package tasks
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grik-ai/ricochet-task/internal/config"
+	"github.com/grik-ai/ricochet-task/pkg/providers"
+)
+
+func TestTaskNotesUseActiveProfile(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv(config.ProfileEnv, "work")
+
+	notes, err := taskNotes()
+	require.NoError(t, err)
+	_, err = notes.Set("tracker", "OPS-1", "check the migration")
+	require.NoError(t, err)
+
+	assert.FileExists(t, filepath.Join(home, ".ricochet", "profiles", "work", providers.NotesFile))
+
+	// Another profile doesn't see the note
+	t.Setenv(config.ProfileEnv, "personal")
+	notes, err = taskNotes()
+	require.NoError(t, err)
+	_, found := notes.Get("tracker", "OPS-1")
+	assert.False(t, found)
+}
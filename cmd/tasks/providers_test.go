@@ -0,0 +1,49 @@
+package tasks
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grik-ai/ricochet-task/pkg/providers"
+)
+
+func TestChooseProviderName(t *testing.T) {
+	useTestProviders(t, "tracker", "alpha", "tracker", "zeta")
+
+	// Tests don't run in a terminal, so the default provider is used without asking
+	name, err := chooseProviderName()
+	require.NoError(t, err)
+	assert.Equal(t, "tracker", name)
+
+	targets, err := resolveTargetProviders("", nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"tracker"}, targets)
+
+	targets, err = resolveTargetProviders("zeta", nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"zeta"}, targets)
+
+	targets, err = resolveTargetProviders("", []string{"all"})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"alpha", "tracker", "zeta"}, targets)
+}
+
+func TestProviderOptions(t *testing.T) {
+	enabled := map[string]*providers.ProviderInfo{
+		"zeta":    {Name: "zeta", Type: "jira"},
+		"tracker": {Name: "tracker", Type: "youtrack"},
+		"alpha":   {Name: "alpha", Type: "notion"},
+	}
+
+	options, byOption := providerOptions(enabled, "tracker")
+	assert.Equal(t, []string{"tracker (youtrack) [default]", "alpha (notion)", "zeta (jira)"}, options,
+		"the default provider comes first, the others by name")
+	assert.Equal(t, "tracker", byOption["tracker (youtrack) [default]"])
+	assert.Equal(t, "zeta", byOption["zeta (jira)"])
+
+	// Without a default the providers are only sorted by name
+	options, _ = providerOptions(enabled, "")
+	assert.Equal(t, []string{"alpha (notion)", "tracker (youtrack)", "zeta (jira)"}, options)
+}
@@ -0,0 +1,267 @@
+package tasks
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	providerCmd "github.com/grik-ai/ricochet-task/cmd/providers"
+	"github.com/grik-ai/ricochet-task/internal/config"
+	"github.com/grik-ai/ricochet-task/pkg/providers"
+)
+
+// queueIDLength is how many characters of an operation ID are shown and
+// enough to refer to it
+const queueIDLength = 8
+
+// QueueCmd represents the queue command
+var QueueCmd = &cobra.Command{
+	Use:   "queue",
+	Short: "Manage task operations queued while offline",
+	Long: `Task creates and updates that couldn't reach their provider, or were made
+with --offline, wait in the offline queue until 'ricochet sync flush' sends them.`,
+}
+
+var queueListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List queued task operations",
+	Long: `List the queued task operations in the order they will be sent.
+
+Examples:
+  ricochet queue list
+  ricochet queue list --output json`,
+	RunE: runQueueList,
+}
+
+var queueDropCmd = &cobra.Command{
+	Use:   "drop [id...]",
+	Short: "Remove queued operations without sending them",
+	Long: `Remove operations from the offline queue, e.g. an update the provider keeps
+rejecting. Operations are referred to by the ID shown in 'queue list'.
+
+Examples:
+  ricochet queue drop 1f0c9a2e
+  ricochet queue drop --all`,
+	RunE: runQueueDrop,
+}
+
+// SyncCmd represents the sync command
+var SyncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Send locally stored changes to providers",
+}
+
+var syncFlushCmd = &cobra.Command{
+	Use:   "flush",
+	Short: "Send queued task operations to their providers",
+	Long: `Replay the offline queue in order. Sent operations leave the queue; failed
+ones stay with their error. When a provider is still unreachable, its remaining
+operations are kept for the next flush.
+
+Every operation has an idempotency key. A task create whose earlier attempt may
+have reached the provider is only sent again if no task with its title was
+created since it was queued, so flushing twice never creates duplicates.
+
+Examples:
+  ricochet sync flush
+  ricochet sync flush --provider youtrack-prod`,
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		return initializeTasks(cmd)
+	},
+	RunE: runSyncFlush,
+}
+
+func init() {
+	QueueCmd.AddCommand(queueListCmd)
+	QueueCmd.AddCommand(queueDropCmd)
+	SyncCmd.AddCommand(syncFlushCmd)
+
+	queueListCmd.Flags().StringP("output", "o", "table", "Output format: table, json, yaml")
+	queueDropCmd.Flags().Bool("all", false, "Remove all queued operations")
+
+	syncFlushCmd.Flags().StringP("provider", "p", "", "Only send operations of this provider")
+	syncFlushCmd.Flags().StringP("output", "o", "table", "Output format: table, json, yaml")
+	syncFlushCmd.RegisterFlagCompletionFunc("provider", providerCmd.CompleteProviderNames)
+}
+
+// offlineQueue returns the offline queue of the active profile
+func offlineQueue() *providers.OfflineQueue {
+	return providers.NewOfflineQueue(config.ProfilePath(providers.OfflineQueueFile))
+}
+
+// queueOperation stores an operation in the offline queue instead of failing
+// the command. cause is the connectivity error, or nil with --offline.
+func queueOperation(op *providers.QueuedOperation, cause error) error {
+	queued, err := offlineQueue().Enqueue(op)
+	if err != nil {
+		if cause != nil {
+			return fmt.Errorf("%w (and queueing failed: %v)", cause, err)
+		}
+		return fmt.Errorf("failed to queue operation: %w", err)
+	}
+
+	if cause != nil {
+		fmt.Printf("⚠️  Provider %s is unreachable: %v\n", queued.Provider, cause)
+	}
+	fmt.Printf("📥 Queued %s for %s (%s)\n", queued.Summary(), queued.Provider, shortQueueID(queued.ID))
+	fmt.Println("Run 'ricochet sync flush' to send it once the provider is reachable")
+	return nil
+}
+
+func runQueueList(cmd *cobra.Command, args []string) error {
+	ops, err := offlineQueue().List()
+	if err != nil {
+		return err
+	}
+
+	switch outputFormat(cmd) {
+	case "json":
+		return outputJSON(ops)
+	case "yaml":
+		return outputYAML(ops)
+	}
+
+	if len(ops) == 0 {
+		fmt.Println("The offline queue is empty")
+		return nil
+	}
+
+	fmt.Printf("%-10s %-15s %-40s %-17s %-9s %s\n", "ID", "PROVIDER", "OPERATION", "QUEUED", "ATTEMPTS", "LAST ERROR")
+	fmt.Printf("%-10s %-15s %-40s %-17s %-9s %s\n", "--", "--------", "---------", "------", "--------", "----------")
+	for _, op := range ops {
+		summary := op.Summary()
+		if len(summary) > 37 {
+			summary = summary[:37] + "..."
+		}
+		fmt.Printf("%-10s %-15s %-40s %-17s %-9d %s\n",
+			shortQueueID(op.ID),
+			op.Provider,
+			summary,
+			op.QueuedAt.Local().Format("2006-01-02 15:04"),
+			op.Attempts,
+			op.LastError,
+		)
+	}
+	fmt.Printf("\n%d operations queued\n", len(ops))
+	return nil
+}
+
+func runQueueDrop(cmd *cobra.Command, args []string) error {
+	all, _ := cmd.Flags().GetBool("all")
+	if all == (len(args) > 0) {
+		return providers.NewValidationError("name the operations to drop or use --all", nil)
+	}
+
+	queue := offlineQueue()
+	ops, err := queue.List()
+	if err != nil {
+		return err
+	}
+
+	var drop []*providers.QueuedOperation
+	if all {
+		drop = ops
+	} else {
+		for _, id := range args {
+			op, err := findQueuedOperation(ops, id)
+			if err != nil {
+				return err
+			}
+			drop = append(drop, op)
+		}
+	}
+
+	for _, op := range drop {
+		if err := queue.Remove(op.ID); err != nil {
+			return err
+		}
+		fmt.Printf("🗑️  Dropped %s for %s (%s)\n", op.Summary(), op.Provider, shortQueueID(op.ID))
+	}
+	return nil
+}
+
+func runSyncFlush(cmd *cobra.Command, args []string) error {
+	providerName, _ := cmd.Flags().GetString("provider")
+	output := outputFormat(cmd)
+
+	// Unlike PostRun, this also delivers the events of sent operations when others failed
+	defer flushTaskEvents(cmd)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	results, err := offlineQueue().Flush(ctx, providerName, registry.GetProvider)
+	if err != nil {
+		return err
+	}
+
+	failed := 0
+	for _, result := range results {
+		if result.Error != "" || result.Deferred {
+			failed++
+		}
+	}
+
+	switch output {
+	case "json":
+		if err := outputJSON(results); err != nil {
+			return err
+		}
+	case "yaml":
+		if err := outputYAML(results); err != nil {
+			return err
+		}
+	default:
+		if len(results) == 0 {
+			fmt.Println("Nothing to send, the offline queue is empty")
+			return nil
+		}
+		for _, result := range results {
+			op := result.Operation
+			switch {
+			case result.Deferred:
+				fmt.Printf("⏸️  %s on %s: kept, provider unreachable\n", op.Summary(), op.Provider)
+			case result.Error != "":
+				fmt.Printf("❌ %s on %s: %s\n", op.Summary(), op.Provider, result.Error)
+			case result.Duplicate:
+				fmt.Printf("✅ %s on %s: already applied as %s\n", op.Summary(), op.Provider, result.TaskID)
+			default:
+				fmt.Printf("✅ %s on %s: %s\n", op.Summary(), op.Provider, result.TaskID)
+			}
+		}
+		fmt.Printf("\nSent %d of %d queued operations\n", len(results)-failed, len(results))
+	}
+
+	if failed > 0 {
+		return providers.NewPartialFailureError(failed, len(results), "queued operations")
+	}
+	return nil
+}
+
+// findQueuedOperation finds an operation by its ID or a unique ID prefix
+func findQueuedOperation(ops []*providers.QueuedOperation, id string) (*providers.QueuedOperation, error) {
+	var found *providers.QueuedOperation
+	for _, op := range ops {
+		if !strings.HasPrefix(op.ID, id) {
+			continue
+		}
+		if found != nil {
+			return nil, providers.NewValidationError(fmt.Sprintf("operation ID %s is ambiguous", id), nil)
+		}
+		found = op
+	}
+	if found == nil {
+		return nil, providers.NewProviderError(providers.ErrorTypeNotFound, fmt.Sprintf("queued operation %s not found", id), nil)
+	}
+	return found, nil
+}
+
+func shortQueueID(id string) string {
+	if len(id) > queueIDLength {
+		return id[:queueIDLength]
+	}
+	return id
+}
@@ -0,0 +1,133 @@
+package tasks
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	"github.com/grik-ai/ricochet-task/pkg/providers"
+)
+
+var rollupCmd = &cobra.Command{
+	Use:   "rollup [epicID]",
+	Short: "Show the aggregate progress of an epic's subtasks",
+	Long: `Show how far an epic is from done: the share of its descendants that are
+completed, by count and by estimated hours, and which of them are blocked.
+
+Descendants are the epic's subtasks and their subtasks, found through the
+subtask list of each task and through the parent and epic of the tasks in the
+epic's project. --limit bounds how many project tasks are scanned.
+
+Examples:
+  ricochet tasks rollup PROJ-100
+  ricochet tasks rollup PROJ-100 --output json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runRollupTask,
+}
+
+func init() {
+	TasksCmd.AddCommand(rollupCmd)
+
+	rollupCmd.Flags().Int("limit", 1000, "Maximum number of project tasks to scan for children")
+}
+
+func runRollupTask(cmd *cobra.Command, args []string) error {
+	providerName, _ := cmd.Flags().GetString("provider")
+
+	provider, err := selectProvider(providerName)
+	if err != nil {
+		return fmt.Errorf("failed to get provider: %w", err)
+	}
+
+	ctx, cancel := commandContext(cmd, providerName, defaultListTimeout)
+	defer cancel()
+
+	epic, err := provider.GetTask(ctx, args[0])
+	if err != nil {
+		return fmt.Errorf("failed to get task: %w", err)
+	}
+
+	filters := &providers.TaskFilters{ProjectID: epic.ProjectID, Limit: getIntFlag(cmd, "limit")}
+	descendants, err := providers.FetchDescendants(ctx, provider, epic, filters)
+	if err != nil {
+		return fmt.Errorf("failed to fetch subtasks of %s: %w", epic.GetDisplayID(), err)
+	}
+	rollup := providers.ComputeRollup(epic, descendants)
+
+	switch outputFormat(cmd) {
+	case "json":
+		return outputJSON(rollup)
+	case "yaml":
+		return outputYAML(rollup)
+	default:
+		outputRollup(rollup)
+		return nil
+	}
+}
+
+func outputRollup(rollup *providers.EpicRollup) {
+	fmt.Printf("📊 %s: %s\n\n", rollup.EpicID, rollup.Title)
+	if rollup.Total == 0 {
+		fmt.Println("No subtasks found")
+		return
+	}
+
+	fmt.Printf("By count: %s %3.0f%%  %d of %d done\n", progressBar(rollup.Percent, 30), rollup.Percent, rollup.Completed, rollup.Total)
+	if rollup.EstimatedHours > 0 {
+		fmt.Printf("By hours: %s %3.0f%%  %s of %sh done",
+			progressBar(rollup.HoursPercent, 30), rollup.HoursPercent, formatHours(rollup.CompletedHours), formatHours(rollup.EstimatedHours))
+		if rollup.Unestimated > 0 {
+			fmt.Printf(", %d without estimate", rollup.Unestimated)
+		}
+		fmt.Println()
+	} else {
+		fmt.Println("By hours: no estimates")
+	}
+
+	if len(rollup.Blockers) > 0 {
+		fmt.Printf("\n⛔ Blocked (%d):\n", len(rollup.Blockers))
+		for _, child := range rollup.Blockers {
+			line := fmt.Sprintf("  %-15s %s", child.TaskID, child.Title)
+			if len(child.BlockedBy) > 0 {
+				line += " (by " + strings.Join(child.BlockedBy, ", ") + ")"
+			}
+			fmt.Println(line)
+		}
+	}
+
+	fmt.Printf("\nSubtasks:\n")
+	for _, child := range rollup.Children {
+		mark := "  "
+		switch {
+		case child.Completed:
+			mark = "✓ "
+		case child.Blocked:
+			mark = "⛔"
+		}
+		title := child.Title
+		if len(title) > 47 {
+			title = title[:47] + "..."
+		}
+		fmt.Printf("  %s %-15s %-50s %s\n", mark, child.TaskID, title, child.Status)
+	}
+}
+
+// progressBar renders percent as a bar of width cells
+func progressBar(percent float64, width int) string {
+	filled := int(math.Round(percent / 100 * float64(width)))
+	if filled > width {
+		filled = width
+	}
+	return "[" + color.GreenString(strings.Repeat("█", filled)) + strings.Repeat("░", width-filled) + "]"
+}
+
+// formatHours drops the fraction of whole hours
+func formatHours(hours float64) string {
+	if hours == math.Trunc(hours) {
+		return fmt.Sprintf("%.0f", hours)
+	}
+	return fmt.Sprintf("%.1f", hours)
+}
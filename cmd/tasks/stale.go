@@ -0,0 +1,269 @@
+package tasks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/grik-ai/ricochet-task/pkg/providers"
+	"github.com/grik-ai/ricochet-task/pkg/workflow"
+)
+
+var staleCmd = &cobra.Command{
+	Use:   "stale",
+	Short: "List open tasks nobody has updated for a while",
+	Long: `List open tasks that have not been updated for longer than --older-than,
+grouped by assignee, to surface forgotten work. Tasks whose provider reports no
+update time count from their creation. Completed tasks are never stale.
+
+--label-stale tags the stale tasks with the "stale" label, creating it in the
+provider if needed. --notify sends each assignee the list of their stale tasks
+through a notification channel (see 'ricochet notifications'); email recipients
+are looked up in the provider by the assignee's login.
+
+Examples:
+  ricochet tasks stale --older-than 30d --status open
+  ricochet tasks stale --project BACKEND --older-than 2w --output json
+  ricochet tasks stale --older-than 60d --label-stale --notify slack`,
+	RunE: runStaleTasks,
+}
+
+func init() {
+	TasksCmd.AddCommand(staleCmd)
+
+	staleCmd.Flags().String("older-than", "30d", "Minimum time without updates, in hours, days or weeks (12h, 30d, 2w)")
+	staleCmd.Flags().StringSlice("status", []string{}, "Filter by status")
+	staleCmd.Flags().String("project", "", "Filter by project")
+	staleCmd.Flags().String("assignee", "", "Filter by assignee, \"me\" for the current user")
+	staleCmd.Flags().Int("limit", 1000, "Maximum number of tasks to fetch per provider")
+	staleCmd.Flags().Bool("label-stale", false, "Add the \"stale\" label to the stale tasks")
+	staleCmd.Flags().String("notify", "", "Notify assignees of their stale tasks through a channel ("+strings.Join(workflow.NotificationChannelTypes, ", ")+")")
+	staleCmd.RegisterFlagCompletionFunc("notify", cobra.FixedCompletions(workflow.NotificationChannelTypes, cobra.ShellCompDirectiveNoFileComp))
+}
+
+func runStaleTasks(cmd *cobra.Command, args []string) error {
+	providerName, _ := cmd.Flags().GetString("provider")
+	providerNames, _ := cmd.Flags().GetStringSlice("providers")
+	output := outputFormat(cmd)
+	labelStale, _ := cmd.Flags().GetBool("label-stale")
+	channelType, _ := cmd.Flags().GetString("notify")
+
+	olderThan, err := providers.ParseAge(getStringFlag(cmd, "older-than"))
+	if err != nil {
+		return fmt.Errorf("--older-than: %w", err)
+	}
+
+	// Check the channel before fetching, so a typo doesn't cost a full fetch
+	var channel workflow.NotificationChannel
+	if channelType != "" {
+		if channel, err = workflow.NewChannelFromEnv(channelType, aiLogger{logger}); err != nil {
+			return err
+		}
+	}
+
+	filters := &providers.TaskFilters{
+		ProjectID:  getStringFlag(cmd, "project"),
+		AssigneeID: getStringFlag(cmd, "assignee"),
+		Status:     getStringSliceFlag(cmd, "status"),
+		Limit:      getIntFlag(cmd, "limit"),
+	}
+	filters.ProjectByProvider = defaultProjects(cmd)
+
+	targetProviders, err := resolveTargetProviders(providerName, providerNames)
+	if err != nil {
+		return err
+	}
+	tasks, fetchErr := collectTasks(cmd, targetProviders, filters)
+	if fetchErr != nil && !isPartialFailure(fetchErr) {
+		return fetchErr
+	}
+	groups := providers.FindStaleTasks(tasks, olderThan, time.Now())
+
+	switch output {
+	case "json":
+		err = outputJSON(groups)
+	case "yaml":
+		err = outputYAML(groups)
+	default:
+		outputStaleTasks(groups, olderThan)
+	}
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := commandContext(cmd, providerName, defaultListTimeout)
+	defer cancel()
+
+	if labelStale {
+		if err := labelStaleTasks(ctx, groups, output == "table"); err != nil {
+			return err
+		}
+	}
+	if channel != nil {
+		if err := notifyStaleAssignees(ctx, cmd, channel, groups, output == "table"); err != nil {
+			return err
+		}
+	}
+	return fetchErr
+}
+
+func outputStaleTasks(groups []providers.StaleGroup, olderThan time.Duration) {
+	if len(groups) == 0 {
+		fmt.Printf("No open tasks without updates for more than %s\n", formatAge(olderThan))
+		return
+	}
+
+	total := 0
+	for _, group := range groups {
+		total += len(group.Tasks)
+	}
+	fmt.Printf("%d stale tasks without updates for more than %s\n", total, formatAge(olderThan))
+
+	for _, group := range groups {
+		fmt.Printf("\n%s (%d)\n", group.Assignee, len(group.Tasks))
+		for _, stale := range group.Tasks {
+			title := stale.Title
+			if len(title) > 37 {
+				title = title[:37] + "..."
+			}
+			fmt.Printf("  %-15s %-40s %-15s %4dd idle (since %s)\n",
+				stale.TaskID, title, stale.Status, stale.IdleDays, stale.LastUpdated.Format("2006-01-02"))
+		}
+	}
+}
+
+// formatAge renders a threshold in whole days when it is one
+func formatAge(age time.Duration) string {
+	if age%(24*time.Hour) == 0 {
+		return fmt.Sprintf("%d days", int(age/(24*time.Hour)))
+	}
+	return age.String()
+}
+
+// labelStaleTasks adds the stale label to the tasks that don't have it yet.
+// The label is checked once per provider and project, creating it if needed.
+func labelStaleTasks(ctx context.Context, groups []providers.StaleGroup, report bool) error {
+	labels := make(map[string][]string) // provider/project -> label as spelled by the provider
+	failed, attempted := 0, 0
+	for _, group := range groups {
+		for _, stale := range group.Tasks {
+			task := stale.Task
+			if len(providers.MergeLabels(task.Labels, nil, []string{providers.StaleLabel})) < len(task.Labels) {
+				continue
+			}
+			attempted++
+
+			if err := labelStaleTask(ctx, task, labels); err != nil {
+				logger.Warnf("Failed to label %s as stale: %v", stale.TaskID, err)
+				failed++
+				continue
+			}
+			if report {
+				fmt.Printf("🏷️  %s labeled %s\n", stale.TaskID, providers.StaleLabel)
+			}
+		}
+	}
+
+	if failed > 0 {
+		return providers.NewPartialFailureError(failed, attempted, "tasks")
+	}
+	return nil
+}
+
+func labelStaleTask(ctx context.Context, task *providers.UniversalTask, labels map[string][]string) error {
+	provider, err := registry.GetProvider(task.ProviderName)
+	if err != nil {
+		return fmt.Errorf("failed to get provider: %w", err)
+	}
+
+	key := task.ProviderName + "/" + task.ProjectID
+	label, checked := labels[key]
+	if !checked {
+		if label, err = providers.ValidateLabels(ctx, provider, task.ProjectID, []string{providers.StaleLabel}, true); err != nil {
+			return err
+		}
+		labels[key] = label
+	}
+
+	return provider.UpdateTask(ctx, task.ID, &providers.TaskUpdate{Labels: providers.MergeLabels(task.Labels, label, nil)})
+}
+
+// notifyStaleAssignees sends each assignee one notification listing their
+// stale tasks. Unassigned tasks have nobody to notify.
+func notifyStaleAssignees(ctx context.Context, cmd *cobra.Command, channel workflow.NotificationChannel, groups []providers.StaleGroup, report bool) error {
+	failed, attempted := 0, 0
+	for _, group := range groups {
+		if group.Assignee == providers.UnassignedKey {
+			continue
+		}
+		attempted++
+
+		recipient := staleRecipient(ctx, channel.GetType(), group)
+		if err := channel.Send(ctx, staleNotification(group, recipient)); err != nil {
+			if errors.Is(err, workflow.ErrChannelNotConfigured) {
+				cmd.SilenceUsage = true
+				return providers.NewProviderError(providers.ErrorTypeConfiguration,
+					fmt.Sprintf("%s notifications are not configured, see 'ricochet notifications --help'", channel.GetType()), err)
+			}
+			logger.Warnf("Failed to notify %s of stale tasks: %v", group.Assignee, err)
+			failed++
+			continue
+		}
+		if report {
+			fmt.Printf("📨 Notified %s of %d stale tasks through %s\n", recipient, len(group.Tasks), channel.GetType())
+		}
+	}
+
+	if failed > 0 {
+		return providers.NewPartialFailureError(failed, attempted, "notifications")
+	}
+	return nil
+}
+
+// staleRecipient returns the address to notify the assignee of a group at:
+// the email the provider knows for email, the assignee otherwise
+func staleRecipient(ctx context.Context, channelType string, group providers.StaleGroup) string {
+	if channelType != "email" || strings.Contains(group.Assignee, "@") {
+		return group.Assignee
+	}
+
+	provider, err := registry.GetProvider(group.Tasks[0].Task.ProviderName)
+	if err != nil {
+		return group.Assignee
+	}
+	user, err := providers.ResolveUser(ctx, provider, group.Assignee)
+	if err != nil || user.Email == "" {
+		logger.Warnf("No email known for %s: %v", group.Assignee, err)
+		return group.Assignee
+	}
+	return user.Email
+}
+
+func staleNotification(group providers.StaleGroup, recipient string) *workflow.Notification {
+	var message strings.Builder
+	message.WriteString("These tasks assigned to you have not been updated for a while. Update, close or hand them over:\n\n")
+	ids := make([]string, 0, len(group.Tasks))
+	for _, stale := range group.Tasks {
+		fmt.Fprintf(&message, "• %s %s (%s, %d days without updates)\n", stale.TaskID, stale.Title, stale.Status, stale.IdleDays)
+		ids = append(ids, stale.TaskID)
+	}
+
+	now := time.Now()
+	return &workflow.Notification{
+		ID:         fmt.Sprintf("stale-%s-%d", group.Assignee, now.UnixNano()),
+		Type:       "stale_tasks",
+		Title:      fmt.Sprintf("%d stale tasks", len(group.Tasks)),
+		Message:    message.String(),
+		Priority:   "low",
+		Recipients: []string{recipient},
+		Data: map[string]interface{}{
+			"assignee": group.Assignee,
+			"tasks":    ids,
+		},
+		Timestamp: now,
+	}
+}
@@ -0,0 +1,40 @@
+package tasks
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grik-ai/ricochet-task/internal/config"
+	"github.com/grik-ai/ricochet-task/pkg/providers"
+)
+
+func TestLocalStoresUseActiveProfile(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv(config.ProfileEnv, "work")
+	profileDir := filepath.Join(home, ".ricochet", "profiles", "work")
+
+	templates, err := taskTemplates()
+	require.NoError(t, err)
+	require.NoError(t, templates.Save(&providers.TaskTemplate{Name: "oncall", Summary: "On-call handover"}))
+	assert.FileExists(t, filepath.Join(profileDir, providers.TaskTemplatesFile))
+
+	watchers, err := taskWatchers()
+	require.NoError(t, err)
+	require.NoError(t, watchers.WatchTask(context.Background(), "OPS-1", "alice"))
+	assert.FileExists(t, filepath.Join(profileDir, providers.WatchersFile))
+
+	// Another profile sees neither
+	t.Setenv(config.ProfileEnv, "personal")
+	templates, err = taskTemplates()
+	require.NoError(t, err)
+	_, err = templates.Get("oncall")
+	assert.Error(t, err)
+	watchers, err = taskWatchers()
+	require.NoError(t, err)
+	assert.Empty(t, watchers.Watchers("OPS-1"))
+}
@@ -1,18 +1,28 @@
 package tasks
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
+	"os/user"
+	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/spf13/cobra"
 	"github.com/sirupsen/logrus"
 	"gopkg.in/yaml.v3"
 
+	"github.com/grik-ai/ricochet-task/internal/config"
+	"github.com/grik-ai/ricochet-task/pkg/ai"
 	"github.com/grik-ai/ricochet-task/pkg/providers"
+	"github.com/grik-ai/ricochet-task/pkg/ui"
 	providerCmd "github.com/grik-ai/ricochet-task/cmd/providers"
 )
 
@@ -21,6 +31,13 @@ var (
 	logger   *logrus.Logger
 )
 
+// Default deadlines of task commands, used unless --timeout is given
+const (
+	defaultTaskTimeout  = 30 * time.Second // Operations on a single task
+	defaultListTimeout  = 60 * time.Second // Listing and multi-task operations
+	defaultFlushTimeout = 60 * time.Second // Delivery of queued task events
+)
+
 // TasksCmd represents the tasks command
 var TasksCmd = &cobra.Command{
 	Use:   "tasks",
@@ -29,8 +46,15 @@ var TasksCmd = &cobra.Command{
 	
 Tasks can be created in specific providers or automatically routed to the optimal provider
 based on configured routing rules.`,
-	PersistentPreRun: func(cmd *cobra.Command, args []string) {
-		initializeTasks()
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if timeout, _ := cmd.Flags().GetDuration("timeout"); timeout < 0 {
+			return providers.NewValidationError("--timeout must not be negative", nil)
+		}
+		configureColor(cmd)
+		return initializeTasks(cmd)
+	},
+	PersistentPostRun: func(cmd *cobra.Command, args []string) {
+		flushTaskEvents(cmd)
 	},
 }
 
@@ -38,11 +62,16 @@ var createCmd = &cobra.Command{
 	Use:   "create",
 	Short: "Create a new task",
 	Long: `Create a new task in the specified provider or using automatic routing.
+
+If the provider can't be reached, or with --offline, the task is stored in the
+offline queue and created later by 'ricochet sync flush'.
 	
 Examples:
   ricochet tasks create --title "Implement OAuth" --provider youtrack-prod
   ricochet tasks create --title "Fix bug" --description "Login issue" --priority high
-  ricochet tasks create --title "Research API" --type research --auto-route`,
+  ricochet tasks create --title "Research API" --type research --auto-route
+  ricochet tasks create --title "Check backups" --offline
+  ricochet tasks create --title "Login crash on prod" --type bug --check-duplicates`,
 	RunE: runCreateTask,
 }
 
@@ -50,12 +79,26 @@ var listCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List tasks",
 	Long: `List tasks from one or more providers with optional filters.
+
+Label filters can be combined: --labels-all keeps tasks with every label,
+--labels-any tasks with at least one and --labels-none drops tasks with any of
+them. --labels is the same as --labels-all.
+
+--assignee me (or @me) stands for the user whose credentials each provider
+uses, so it matches your own tasks in every provider listed.
+
+-o jsonl writes one task per line as soon as its page is fetched instead of
+one JSON array at the end, so large exports start at once and memory stays
+flat. Tasks come in the order they arrive; --limit 0 streams all of them.
 	
 Examples:
   ricochet tasks list --provider youtrack-prod
   ricochet tasks list --providers all --status open
   ricochet tasks list --assignee me --priority high
-  ricochet tasks list --project BACKEND --type bug`,
+  ricochet tasks list --project BACKEND --type bug
+  ricochet tasks list --labels-all backend,urgent --labels-none wontfix
+  ricochet tasks list --format '{{.Key}} {{.Title}} ({{.Status.Name}})'
+  ricochet tasks list --providers all --limit 0 -o jsonl | jq -r .key`,
 	RunE: runListTasks,
 }
 
@@ -63,11 +106,17 @@ var getCmd = &cobra.Command{
 	Use:   "get [id]",
 	Short: "Get a specific task",
 	Long: `Retrieve detailed information about a specific task.
+
+The description is rendered for the terminal: headings, lists, code blocks and
+tables are laid out, and Jira or YouTrack wiki markup is converted first. Use
+--raw to print it unchanged.
 	
 Examples:
   ricochet tasks get PROJ-123 --provider youtrack-prod
+  ricochet tasks get PROJ-123 --raw
   ricochet tasks get 12345 --provider jira-company
-  ricochet tasks get --search "OAuth implementation"`,
+  ricochet tasks get --search "OAuth implementation"
+  ricochet tasks get PROJ-123 --format '{{.Title}}: {{join .Labels ","}}'`,
 	RunE: runGetTask,
 }
 
@@ -75,15 +124,191 @@ var updateCmd = &cobra.Command{
 	Use:   "update [id]",
 	Short: "Update a task",
 	Long: `Update an existing task's properties.
+
+If the provider can't be reached, or with --offline, the changes are stored in
+the offline queue and applied later by 'ricochet sync flush'.
 	
 Examples:
   ricochet tasks update PROJ-123 --status "in_progress" --provider youtrack-prod
   ricochet tasks update 12345 --assignee john.doe --priority high
-  ricochet tasks update PROJ-123 --title "New title" --description "Updated description"`,
+  ricochet tasks update PROJ-123 --title "New title" --description "Updated description"
+  ricochet tasks update PROJ-123 --description "Rewritten" --confirm
+  ricochet tasks update PROJ-123 --status Done --offline`,
 	Args: cobra.ExactArgs(1),
 	RunE: runUpdateTask,
 }
 
+var closeCmd = &cobra.Command{
+	Use:   "close [id]",
+	Short: "Close a task",
+	Long: `Move a task to the done status of its project's workflow, whatever the
+provider calls it ("Done", "Closed", "Resolved", ...). If the task has a
+resolution field, it is set as well.
+
+Examples:
+  ricochet tasks close PROJ-123
+  ricochet tasks close PROJ-123 --resolution duplicate --provider youtrack-prod`,
+	Args: cobra.ExactArgs(1),
+	RunE: runCloseTask,
+}
+
+var reopenCmd = &cobra.Command{
+	Use:   "reopen [id]",
+	Short: "Reopen a task",
+	Long: `Move a task back to the initial status of its project's workflow and
+clear its resolution, if the provider has one.
+
+Examples:
+  ricochet tasks reopen PROJ-123`,
+	Args: cobra.ExactArgs(1),
+	RunE: runReopenTask,
+}
+
+var cloneCmd = &cobra.Command{
+	Use:   "clone [id]",
+	Short: "Clone a task",
+	Long: `Create a new task with the title, description, type, priority, labels and
+custom fields of an existing one. Status, comments and timestamps are not
+copied. The clone is created in the same provider unless --to-provider is set.
+
+Examples:
+  ricochet tasks clone OPS-42
+  ricochet tasks clone OPS-42 --title "Disk full on db-2"
+  ricochet tasks clone PROJ-123 --provider youtrack-prod --to-provider jira-company --project BACKEND`,
+	Args: cobra.ExactArgs(1),
+	RunE: runCloneTask,
+}
+
+var diffCmd = &cobra.Command{
+	Use:   "diff [id]",
+	Short: "Compare a task with its mirror in another provider",
+	Long: `Fetch a task and its mirror in another provider and show the fields that
+differ, with the value of --from on the left and --to on the right, and which
+side was updated last.
+
+The mirror is the task in --to that mentions the task ID in a custom field,
+label or description (or that the task mentions), otherwise the only task
+with the same title. Use --to-id to compare with a specific task instead.
+
+Examples:
+  ricochet tasks diff OPS-42 --from youtrack-prod --to jira-company
+  ricochet tasks diff OPS-42 --to jira-company --to-id BACKEND-7 --output json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runDiffTask,
+}
+
+var assignCmd = &cobra.Command{
+	Use:   "assign [id...]",
+	Short: "Assign tasks to a user or spread them across several",
+	Long: `Assign one or more tasks to a user, or with --round-robin distribute them
+across several users in turn so that everyone gets an even share. Users may be
+given by login, email, full name or ID, or as "me" for the user whose
+credentials the provider uses; all of them are resolved through the provider
+before any task is changed.
+
+Examples:
+  ricochet tasks assign OPS-42 --to alice
+  ricochet tasks assign OPS-42 --to me
+  ricochet tasks assign OPS-42 OPS-43 --to "Carol Smith" --provider youtrack-prod
+  ricochet tasks assign OPS-42 OPS-43 OPS-44 OPS-45 --round-robin alice,bob,carol`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runAssignTasks,
+}
+
+var triageCmd = &cobra.Command{
+	Use:   "triage",
+	Short: "Suggest priority, type, labels and assignee of untriaged tasks",
+	Long: `Find open tasks without a priority, assignee or labels and let the AI chains
+suggest how to classify them from their title and description. Suggestions
+use the labels the project already has and prefer team members with fewer
+open tasks. Only missing fields are filled in, and the type only replaces the
+generic task type.
+
+The suggestions are shown for confirmation before anything changes; --apply
+applies them right away. AI keys come from 'ricochet key add'; without them
+simple keyword rules are used.
+
+AI requests are paced to the aiChains rateLimit, tokenLimits and costLimits
+of the configuration: they wait for the request rate and the hourly limits,
+and triage stops once a daily or monthly limit is used up. In a terminal,
+Ctrl+C pauses the run, Enter resumes it and a second Ctrl+C stops it.
+
+Examples:
+  ricochet tasks triage --project OPS
+  ricochet tasks triage --project OPS --limit 5 --provider youtrack-prod
+  ricochet tasks triage --project OPS --apply`,
+	Args: cobra.NoArgs,
+	RunE: runTriageTasks,
+}
+
+var expandCmd = &cobra.Command{
+	Use:   "expand <id>",
+	Short: "Generate a structured description of a task from its title",
+	Long: `Let the AI chains turn a terse task into a proper description: the context
+of the work, acceptance criteria and technical notes. A description the task
+already has is kept above the generated sections.
+
+The description is shown for review before the task changes; --apply updates
+the task right away. AI keys come from 'ricochet key add'; without them a
+generic template is used. New tasks can be expanded with 'tasks create --ai-expand'.
+
+Examples:
+  ricochet tasks expand PROJ-123
+  ricochet tasks expand PROJ-123 --apply
+  ricochet tasks expand PROJ-123 --output json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runExpandTask,
+}
+
+var activityCmd = &cobra.Command{
+	Use:   "activity [id]",
+	Short: "Show the change history of a task",
+	Long: `Show who changed what in a task and when: field changes, transitions,
+assignments and comments, oldest first.
+
+The history comes from the provider's change log. For providers without one,
+the changes made through ricochet are shown instead; they are recorded locally
+with their new values only.
+
+Examples:
+  ricochet tasks activity OPS-42
+  ricochet tasks activity OPS-42 --field priority
+  ricochet tasks activity OPS-42 --output json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runTaskActivity,
+}
+
+var exportCmd = &cobra.Command{
+	Use:   "export [ids...]",
+	Short: "Export tasks to a zip archive",
+	Long: `Write tasks to a self-contained zip archive with a manifest. The named tasks
+are exported, or the tasks matching the list filters if no IDs are given.
+
+--include adds related data: comments, attachments (their contents are stored
+under attachments/ in the archive) and history (the AI execution history of
+the task). The archive can be imported into another provider with 'tasks import'.
+
+Examples:
+  ricochet tasks export OPS-42 OPS-43 --file ops.zip --include comments
+  ricochet tasks export --project BACKEND --file backend.zip --include comments,attachments,history
+  ricochet tasks export --provider youtrack-prod --status Open --file open.zip --include all`,
+	RunE: runExportTasks,
+}
+
+var importCmd = &cobra.Command{
+	Use:   "import [archive]",
+	Short: "Import tasks from an export archive",
+	Long: `Recreate the tasks of an archive written by 'tasks export' in a provider,
+together with their status, comments and attachments. Comments are added in
+their original order with a line naming the original author and date.
+
+Examples:
+  ricochet tasks import ops.zip --provider jira-company
+  ricochet tasks import backend.zip --provider youtrack-staging --project BACKEND`,
+	Args: cobra.ExactArgs(1),
+	RunE: runImportTasks,
+}
+
 var deleteCmd = &cobra.Command{
 	Use:   "delete [id]",
 	Short: "Delete a task",
@@ -100,6 +325,8 @@ var searchCmd = &cobra.Command{
 	Use:   "search [query]",
 	Short: "Search tasks across providers",
 	Long: `Search for tasks across one or more providers using a query string.
+
+-o jsonl streams the results as one task per line, like tasks list.
 	
 Examples:
   ricochet tasks search "authentication" --providers all
@@ -108,6 +335,48 @@ Examples:
 	RunE: runSearchTasks,
 }
 
+var statsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show a quick task summary",
+	Long: `Count tasks by status, priority, type, assignee and provider, and flag
+overdue and blocked tasks.
+
+Examples:
+  ricochet tasks stats --providers all --project BACKEND
+  ricochet tasks stats --provider youtrack-prod --output json`,
+	RunE: runStatsTasks,
+}
+
+var balanceCmd = &cobra.Command{
+	Use:   "balance",
+	Short: "Suggest reassignments to level assignee workload",
+	Long: `Compute open tasks and estimated remaining hours per assignee and suggest
+reassignments that level the load. Tasks that are already in progress or have
+time logged stay with their assignee.
+
+The plan is only printed unless --apply is given.
+
+Examples:
+  ricochet tasks balance --project BACKEND
+  ricochet tasks balance --project BACKEND --max-per-assignee 10 --assignees carol
+  ricochet tasks balance --project BACKEND --apply`,
+	RunE: runBalanceTasks,
+}
+
+var planOrderCmd = &cobra.Command{
+	Use:   "plan-order",
+	Short: "Order tasks by their dependencies",
+	Long: `Sort open tasks topologically by their blocked-by/blocks relations and show
+the recommended sequence. Tasks in the same step don't depend on each other and
+can be worked on in parallel. Fails with the offending chain if tasks block each
+other in a cycle.
+
+Examples:
+  ricochet tasks plan-order --project BACKEND
+  ricochet tasks plan-order --providers all --project BACKEND --output json`,
+	RunE: runPlanOrder,
+}
+
 var syncCmd = &cobra.Command{
 	Use:   "sync",
 	Short: "Sync tasks between providers",
@@ -123,12 +392,27 @@ Examples:
 var bulkCreateCmd = &cobra.Command{
 	Use:   "bulk-create",
 	Short: "Create multiple tasks from a file",
-	Long: `Create multiple tasks from a JSON or YAML file.
+	Long: `Create multiple tasks from a JSON, YAML or CSV file.
+
+CSV files need a header row naming a task field per column: id, title
+(required), description, project, type, priority, status, assignee, reporter,
+labels, parent, blockedBy, epic, dueDate, startDate and estimate. Labels and
+blockers are comma-separated within their cell, dates are YYYY-MM-DD and
+estimates durations like 4h. --mapping maps other column names to fields, or
+to "-" to skip a column. Every row is checked before any task is created.
+
+Tasks of the same file can reference each other through temporary IDs: give a
+task an id like "$tmp:epic1" and use it as another task's parent, or in its
+blockedBy or blocks lists. Tasks are created in dependency order and the
+temporary IDs are replaced by the real ones; providers that support links get
+the subtask and dependency links set. Unknown temporary IDs and reference
+cycles are reported before anything is created.
 	
 Examples:
   ricochet tasks bulk-create --file tasks.json --provider youtrack-prod
   ricochet tasks bulk-create --file tasks.yaml --auto-route
-  ricochet tasks bulk-create --file import.json --dry-run`,
+  ricochet tasks bulk-create --file import.json --dry-run
+  ricochet tasks bulk-create --file requirements.csv --mapping Summary=title,Owner=assignee,Notes=-`,
 	RunE: runBulkCreateTasks,
 }
 
@@ -137,6 +421,10 @@ var bulkUpdateCmd = &cobra.Command{
 	Short: "Update multiple tasks from a file",
 	Long: `Update multiple tasks from a JSON or YAML file with task ID to updates mapping.
 	
+The affected tasks are summarized by provider and status before anything
+changes. Afterwards an undo token is printed that reverts the updated fields
+with 'ricochet tasks undo'.
+	
 Examples:
   ricochet tasks bulk-update --file updates.json --provider youtrack-prod
   ricochet tasks bulk-update --file batch-updates.yaml --dry-run`,
@@ -147,6 +435,10 @@ var bulkDeleteCmd = &cobra.Command{
 	Use:   "bulk-delete",
 	Short: "Delete multiple tasks",
 	Long: `Delete multiple tasks by IDs from a file or command line.
+
+The tasks are summarized by provider and status before the confirmation.
+Afterwards an undo token is printed that recreates the deleted tasks with
+'ricochet tasks undo'. Recreated tasks get new IDs.
 	
 Examples:
   ricochet tasks bulk-delete --file task-ids.txt --provider youtrack-prod
@@ -155,6 +447,90 @@ Examples:
 	RunE: runBulkDeleteTasks,
 }
 
+var undoCmd = &cobra.Command{
+	Use:   "undo [token]",
+	Short: "Undo a bulk update or delete",
+	Long: `Undo a bulk operation by the token it printed: deleted tasks are recreated
+with new IDs and updated fields are set back to their previous values. Tokens
+stay valid for 7 days. Without a token, the operations that can still be
+undone are listed.
+
+Examples:
+  ricochet tasks undo
+  ricochet tasks undo 3f9a1c2e`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runUndo,
+}
+
+var watchCmd = &cobra.Command{
+	Use:   "watch [id]",
+	Short: "Watch a task for changes",
+	Long: `Subscribe to changes of a single task. Watchers are notified through their
+configured notification channels whenever the task is updated.
+
+Examples:
+  ricochet tasks watch PROJ-123
+  ricochet tasks watch PROJ-123 --user alice
+  ricochet tasks watch --list`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runWatchTask,
+}
+
+var templateCmd = &cobra.Command{
+	Use:   "template",
+	Short: "Manage task templates",
+	Long: `Manage templates used by 'ricochet tasks create --template'.
+
+Built-in templates (bug-report, incident, feature-request) are always available
+and can be overridden by creating a template with the same name.
+
+Examples:
+  ricochet tasks template list
+  ricochet tasks template show bug-report
+  ricochet tasks template create spike --type spike --labels research --description-file spike.md
+  ricochet tasks template delete spike`,
+	// Templates are stored locally and don't need provider connections
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {},
+}
+
+var templateListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List task templates",
+	RunE:  runTemplateList,
+}
+
+var templateShowCmd = &cobra.Command{
+	Use:   "show [name]",
+	Short: "Show a task template",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runTemplateShow,
+}
+
+var templateCreateCmd = &cobra.Command{
+	Use:   "create [name]",
+	Short: "Create or replace a task template",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runTemplateCreate,
+}
+
+var templateDeleteCmd = &cobra.Command{
+	Use:   "delete [name]",
+	Short: "Delete a task template",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runTemplateDelete,
+}
+
+var unwatchCmd = &cobra.Command{
+	Use:   "unwatch [id]",
+	Short: "Stop watching a task",
+	Long: `Remove your subscription to changes of a task.
+
+Examples:
+  ricochet tasks unwatch PROJ-123`,
+	Args: cobra.ExactArgs(1),
+	RunE: runUnwatchTask,
+}
+
 func init() {
 	// Add subcommands
 	TasksCmd.AddCommand(createCmd)
@@ -162,62 +538,169 @@ func init() {
 	TasksCmd.AddCommand(getCmd)
 	TasksCmd.AddCommand(updateCmd)
 	TasksCmd.AddCommand(deleteCmd)
+	TasksCmd.AddCommand(closeCmd)
+	TasksCmd.AddCommand(reopenCmd)
+	TasksCmd.AddCommand(cloneCmd)
+	TasksCmd.AddCommand(diffCmd)
+	TasksCmd.AddCommand(assignCmd)
+	TasksCmd.AddCommand(triageCmd)
+	TasksCmd.AddCommand(expandCmd)
+	TasksCmd.AddCommand(activityCmd)
+	TasksCmd.AddCommand(exportCmd)
+	TasksCmd.AddCommand(importCmd)
 	TasksCmd.AddCommand(searchCmd)
+	TasksCmd.AddCommand(statsCmd)
+	TasksCmd.AddCommand(balanceCmd)
+	TasksCmd.AddCommand(planOrderCmd)
 	TasksCmd.AddCommand(syncCmd)
 	TasksCmd.AddCommand(bulkCreateCmd)
 	TasksCmd.AddCommand(bulkUpdateCmd)
 	TasksCmd.AddCommand(bulkDeleteCmd)
+	TasksCmd.AddCommand(undoCmd)
+	TasksCmd.AddCommand(watchCmd)
+	TasksCmd.AddCommand(unwatchCmd)
+	TasksCmd.AddCommand(templateCmd)
+
+	templateCmd.AddCommand(templateListCmd)
+	templateCmd.AddCommand(templateShowCmd)
+	templateCmd.AddCommand(templateCreateCmd)
+	templateCmd.AddCommand(templateDeleteCmd)
 
 	// Global task flags
 	TasksCmd.PersistentFlags().StringP("provider", "p", "", "Target provider name")
 	TasksCmd.PersistentFlags().StringSlice("providers", []string{}, "Multiple providers (use 'all' for all enabled)")
-	TasksCmd.PersistentFlags().StringP("output", "o", "table", "Output format: table, json, yaml")
+	TasksCmd.PersistentFlags().Bool("no-color", false, "Disable colored table output (also disabled by NO_COLOR or when not writing to a terminal)")
+	TasksCmd.PersistentFlags().Duration("timeout", 0, "Deadline for the whole command (defaults to 30s for single tasks, 60s for listing and no limit for bulk operations, or the provider's timeout if longer)")
+	TasksCmd.PersistentFlags().StringP("output", "o", "table", "Output format: table, json, yaml, or summary and jsonl for list and search (defaults to $RICOCHET_OUTPUT or defaultOutputFormat from the config)")
 
 	// Create command flags
 	createCmd.Flags().StringP("title", "t", "", "Task title")
 	createCmd.Flags().StringP("description", "d", "", "Task description")
-	createCmd.Flags().String("project", "", "Project ID")
+	createCmd.Flags().String("project", "", "Project ID (defaults to the provider's defaultProject)")
 	createCmd.Flags().String("type", "task", "Task type (task, bug, feature, etc.)")
 	createCmd.Flags().String("priority", "medium", "Task priority (low, medium, high, critical)")
 	createCmd.Flags().String("status", "", "Initial status")
-	createCmd.Flags().String("assignee", "", "Assignee ID or username")
-	createCmd.Flags().StringSlice("labels", []string{}, "Task labels")
-	createCmd.Flags().Bool("auto-route", false, "Automatically route to optimal provider")
+	createCmd.Flags().String("assignee", "", "Assignee ID or username, or \"me\" for the current user")
+	createCmd.Flags().StringSlice("labels", []string{}, "Task labels, which must exist in the provider (see 'providers labels')")
+	createCmd.Flags().String("due", "", "Due date: "+providers.DueDateFormats+", in the configured time zone")
+	createCmd.Flags().Bool("create-labels", false, "Create labels that don't exist in the provider instead of rejecting them")
+	createCmd.Flags().Bool("auto-route", false, "Pick the provider with the routing rules of the config (preview with 'providers test-routing')")
+	createCmd.Flags().String("template", "", "Task template to pre-fill fields from (see 'tasks template list')")
+	createCmd.Flags().Bool("offline", false, "Queue the task without contacting the provider (send it later with 'ricochet sync flush')")
+	createCmd.Flags().Bool("check-duplicates", false, "Search for similar tasks first and offer to create the task as a duplicate of one")
+	createCmd.Flags().Bool("ai-similarity", false, "Score possible duplicates with AI instead of by common words (with --check-duplicates)")
+	createCmd.Flags().String("duplicate-of", "", "Create the task linked as a duplicate of this task")
+	createCmd.Flags().Bool("ai-expand", false, "Generate a structured description from the title with AI (asks for review in a terminal)")
 	createCmd.MarkFlagRequired("title")
 
 	// List command flags
-	listCmd.Flags().String("project", "", "Filter by project")
+	listCmd.Flags().String("project", "", "Filter by project (defaults to the provider's defaultProject, \"\" for all projects)")
 	listCmd.Flags().String("status", "", "Filter by status")
-	listCmd.Flags().String("assignee", "", "Filter by assignee")
+	listCmd.Flags().String("assignee", "", "Filter by assignee, \"me\" for the current user")
 	listCmd.Flags().String("type", "", "Filter by type")
 	listCmd.Flags().String("priority", "", "Filter by priority")
-	listCmd.Flags().StringSlice("labels", []string{}, "Filter by labels")
+	listCmd.Flags().StringSlice("labels", []string{}, "Filter by labels (same as --labels-all)")
+	listCmd.Flags().StringSlice("labels-all", []string{}, "Only tasks with all of these labels")
+	listCmd.Flags().StringSlice("labels-any", []string{}, "Only tasks with at least one of these labels")
+	listCmd.Flags().StringSlice("labels-none", []string{}, "Only tasks with none of these labels")
+	listCmd.Flags().String("due-after", "", "Only tasks due at or after this date ("+providers.DueDateFormats+")")
+	listCmd.Flags().String("due-before", "", "Only tasks due before this date ("+providers.DueDateFormats+")")
 	listCmd.Flags().Int("limit", 50, "Maximum number of tasks to return")
 	listCmd.Flags().Int("offset", 0, "Number of tasks to skip")
+	listCmd.Flags().String("format", "", "Go template for each task, e.g. '{{.Key}} {{.Title}} ({{.Status.Name}})'")
+	listCmd.Flags().StringSlice("columns", nil, columnsFlagUsage)
 
 	// Get command flags
 	getCmd.Flags().String("search", "", "Search for task by title/description")
+	getCmd.Flags().String("format", "", "Go template for the task, e.g. '{{.Key}} {{.Title}} ({{.Status.Name}})'")
+	getCmd.Flags().Bool("raw", false, "Print the description as stored by the provider, without rendering markdown")
 
 	// Update command flags
 	updateCmd.Flags().StringP("title", "t", "", "New title")
 	updateCmd.Flags().StringP("description", "d", "", "New description")
 	updateCmd.Flags().String("status", "", "New status")
 	updateCmd.Flags().String("priority", "", "New priority")
-	updateCmd.Flags().String("assignee", "", "New assignee")
-	updateCmd.Flags().StringSlice("labels", []string{}, "New labels (replaces existing)")
+	updateCmd.Flags().String("assignee", "", "New assignee, or \"me\" for the current user")
+	updateCmd.Flags().StringSlice("labels", []string{}, "New labels (replaces existing), which must exist in the provider")
+	updateCmd.Flags().String("due", "", "New due date: "+providers.DueDateFormats+", in the configured time zone")
+	updateCmd.Flags().Bool("create-labels", false, "Create labels that don't exist in the provider instead of rejecting them")
 	updateCmd.Flags().StringSlice("add-labels", []string{}, "Add labels")
 	updateCmd.Flags().StringSlice("remove-labels", []string{}, "Remove labels")
+	updateCmd.Flags().Bool("confirm", false, "Show a field-by-field diff against the current task and ask before applying")
+	updateCmd.Flags().Bool("offline", false, "Queue the changes without contacting the provider (send them later with 'ricochet sync flush')")
+
+	// Close command flags
+	closeCmd.Flags().String("resolution", "fixed", "Resolution to set if the task has a resolution field")
+
+	// Clone command flags
+	cloneCmd.Flags().String("to-provider", "", "Provider to create the clone in (defaults to the source provider)")
+	cloneCmd.Flags().StringP("title", "t", "", "Title of the clone (defaults to the source title)")
+	cloneCmd.Flags().String("project", "", "Project of the clone (defaults to the source project)")
+
+	// Diff command flags
+	diffCmd.Flags().String("from", "", "Provider of the task (defaults to the default provider)")
+	diffCmd.Flags().String("to", "", "Provider of the mirror")
+	diffCmd.Flags().String("to-id", "", "ID of the mirror, instead of looking it up")
+	diffCmd.MarkFlagRequired("to")
+
+	// Assign command flags
+	assignCmd.Flags().String("to", "", "User to assign the tasks to, or \"me\" for the current user")
+	assignCmd.Flags().StringSlice("round-robin", []string{}, "Users to distribute the tasks across in turn")
+
+	// Triage command flags
+	triageCmd.Flags().String("project", "", "Only triage tasks of this project")
+	triageCmd.Flags().Int("limit", 20, "Maximum number of tasks to triage")
+	triageCmd.Flags().Bool("apply", false, "Apply the suggestions without confirmation")
+
+	// Expand command flags
+	expandCmd.Flags().Bool("apply", false, "Update the description without confirmation")
+
+	// Activity command flags
+	activityCmd.Flags().StringSlice("field", []string{}, "Only show changes of these fields, e.g. status,priority")
+
+	// Export command flags
+	exportCmd.Flags().StringP("file", "f", "", "Archive file to write")
+	exportCmd.Flags().StringSlice("include", []string{}, "Related data to include: comments, attachments, history or all")
+	exportCmd.Flags().String("project", "", "Filter by project")
+	exportCmd.Flags().String("status", "", "Filter by status")
+	exportCmd.Flags().String("assignee", "", "Filter by assignee, \"me\" for the current user")
+	exportCmd.Flags().Int("limit", 100, "Maximum number of tasks to export")
+	exportCmd.MarkFlagRequired("file")
+
+	// Import command flags
+	importCmd.Flags().String("project", "", "Project to create the tasks in (defaults to the archived project)")
 
 	// Delete command flags
 	deleteCmd.Flags().Bool("force", false, "Force deletion without confirmation")
 
 	// Search command flags
 	searchCmd.Flags().String("query", "", "Search query")
+	searchCmd.Flags().String("project", "", "Filter by project (defaults to the provider's defaultProject)")
 	searchCmd.Flags().String("status", "", "Filter by status")
-	searchCmd.Flags().String("assignee", "", "Filter by assignee")
+	searchCmd.Flags().String("assignee", "", "Filter by assignee, \"me\" for the current user")
 	searchCmd.Flags().String("type", "", "Filter by type")
 	searchCmd.Flags().String("priority", "", "Filter by priority")
 	searchCmd.Flags().Int("limit", 100, "Maximum number of results")
+	searchCmd.Flags().StringSlice("columns", nil, columnsFlagUsage)
+
+	// Stats command flags
+	statsCmd.Flags().String("project", "", "Filter by project")
+	statsCmd.Flags().String("assignee", "", "Filter by assignee, \"me\" for the current user")
+	statsCmd.Flags().Int("limit", 1000, "Maximum number of tasks to fetch per provider")
+
+	// Balance command flags
+	balanceCmd.Flags().String("project", "", "Project to balance")
+	balanceCmd.Flags().Int("max-per-assignee", 0, "Maximum open tasks per assignee (0 for no cap)")
+	balanceCmd.Flags().StringSlice("assignees", []string{}, "Additional assignees that can take tasks")
+	balanceCmd.Flags().Int("limit", 1000, "Maximum number of tasks to fetch per provider")
+	balanceCmd.Flags().Bool("apply", false, "Apply the suggested reassignments")
+	balanceCmd.MarkFlagRequired("project")
+
+	// Plan-order command flags
+	planOrderCmd.Flags().String("project", "", "Project to plan")
+	planOrderCmd.Flags().String("assignee", "", "Filter by assignee, \"me\" for the current user")
+	planOrderCmd.Flags().Int("limit", 1000, "Maximum number of tasks to fetch per provider")
+	planOrderCmd.MarkFlagRequired("project")
 
 	// Sync command flags
 	syncCmd.Flags().String("from", "", "Source provider")
@@ -229,7 +712,8 @@ func init() {
 	syncCmd.MarkFlagRequired("to")
 
 	// Bulk create command flags
-	bulkCreateCmd.Flags().StringP("file", "f", "", "Input file (JSON or YAML)")
+	bulkCreateCmd.Flags().StringP("file", "f", "", "Input file (JSON, YAML or CSV)")
+	bulkCreateCmd.Flags().StringToString("mapping", map[string]string{}, "Map CSV columns to task fields, e.g. Summary=title,Owner=assignee (- skips a column)")
 	bulkCreateCmd.Flags().Bool("auto-route", false, "Automatically route to optimal provider")
 	bulkCreateCmd.Flags().Bool("dry-run", false, "Show what would be created without making changes")
 	bulkCreateCmd.MarkFlagRequired("file")
@@ -237,6 +721,7 @@ func init() {
 	// Bulk update command flags
 	bulkUpdateCmd.Flags().StringP("file", "f", "", "Input file (JSON or YAML)")
 	bulkUpdateCmd.Flags().Bool("dry-run", false, "Show what would be updated without making changes")
+	bulkUpdateCmd.Flags().Bool("force", false, "Update without confirmation")
 	bulkUpdateCmd.MarkFlagRequired("file")
 
 	// Bulk delete command flags
@@ -245,13 +730,61 @@ func init() {
 	bulkDeleteCmd.Flags().String("query", "", "Query to select tasks for deletion")
 	bulkDeleteCmd.Flags().Bool("dry-run", false, "Show what would be deleted without making changes")
 	bulkDeleteCmd.Flags().Bool("force", false, "Force deletion without confirmation")
+
+	// Undo command flags
+	undoCmd.Flags().Bool("list", false, "List bulk operations that can be undone")
+
+	// Watch command flags
+	watchCmd.Flags().String("user", "", "Watcher user ID (defaults to $RICOCHET_USER or the current OS user)")
+	watchCmd.Flags().Bool("list", false, "List tasks the user is watching")
+	unwatchCmd.Flags().String("user", "", "Watcher user ID (defaults to $RICOCHET_USER or the current OS user)")
+
+	// Template command flags
+	templateCreateCmd.Flags().String("summary", "", "Short description of the template")
+	templateCreateCmd.Flags().String("title-prefix", "", "Prefix added to task titles")
+	templateCreateCmd.Flags().StringP("description", "d", "", "Task description with placeholder prompts")
+	templateCreateCmd.Flags().String("description-file", "", "Read the task description from a file")
+	templateCreateCmd.Flags().String("type", "task", "Task type")
+	templateCreateCmd.Flags().String("priority", "medium", "Task priority")
+	templateCreateCmd.Flags().StringSlice("labels", []string{}, "Task labels")
+	templateCreateCmd.Flags().String("assignee", "", "Default assignee")
+
+	registerCompletions()
 }
 
-func initializeTasks() {
-	// Reuse the provider registry initialization
-	providerCmd.ProvidersCmd.PersistentPreRun(nil, nil)
-	registry = providerCmd.GetRegistry() // We'd need to expose this
+// initializeTasks takes the shared provider registry, initializing it on first
+// use within the deadline of cmd
+func initializeTasks(cmd *cobra.Command) error {
 	logger = logrus.New()
+	var err error
+	registry, err = providerCmd.CommandRegistry(cmd)
+	return err
+}
+
+// labelsError points to --create-labels when labels are unknown to the provider
+func labelsError(err error) error {
+	if providers.IsErrorType(err, providers.ErrorTypeValidation) {
+		return fmt.Errorf("%w; check 'ricochet providers labels' or pass --create-labels to create them", err)
+	}
+	return err
+}
+
+// flushTaskEvents waits for queued task events to reach webhooks before the command exits
+func flushTaskEvents(cmd *cobra.Command) {
+	if registry == nil {
+		return
+	}
+	bus := registry.EventBus()
+	if bus == nil {
+		return
+	}
+
+	ctx, cancel := commandContext(cmd, "", defaultFlushTimeout)
+	defer cancel()
+
+	if err := bus.Close(ctx); err != nil {
+		logger.Warnf("Some task events were not delivered: %v", err)
+	}
 }
 
 func runCreateTask(cmd *cobra.Command, args []string) error {
@@ -266,49 +799,130 @@ func runCreateTask(cmd *cobra.Command, args []string) error {
 	autoRoute, _ := cmd.Flags().GetBool("auto-route")
 	providerName, _ := cmd.Flags().GetString("provider")
 
+	taskPriority, err := mapPriority(providerName, priority)
+	if err != nil {
+		return err
+	}
+
 	// Create universal task
 	task := &providers.UniversalTask{
 		Title:       title,
 		Description: description,
 		ProjectID:   project,
 		Type:        providers.TaskType(taskType),
-		Priority:    mapPriority(priority),
+		Priority:    taskPriority,
 		AssigneeID:  assignee,
 		Labels:      labels,
 		CreatedAt:   time.Now(),
 		UpdatedAt:   time.Now(),
 	}
 
-	if status != "" {
-		task.Status = providers.TaskStatus{
-			ID:   strings.ToLower(strings.ReplaceAll(status, " ", "_")),
-			Name: status,
+	if templateName, _ := cmd.Flags().GetString("template"); templateName != "" {
+		templated, err := newTaskFromTemplate(cmd, templateName, task)
+		if err != nil {
+			return err
 		}
+		task = templated
 	}
 
-	// Determine target provider
-	var provider providers.TaskProvider
-	var err error
+	if status != "" {
+		task.Status = typedStatus(status)
+	}
+
+	due, err := dueDateFlag(cmd, "due")
+	if err != nil {
+		return err
+	}
+	if due != nil {
+		task.DueDate = due
+	}
 
+	// Determine target provider
 	if autoRoute {
-		// TODO: Implement smart routing based on rules
-		provider, err = registry.GetDefaultProvider()
-	} else if providerName != "" {
-		provider, err = registry.GetProvider(providerName)
-	} else {
-		provider, err = registry.GetDefaultProvider()
+		decision := registry.RouteTask(task)
+		if decision.Provider == "" {
+			return providers.NewValidationError("auto-routing found no provider: "+decision.Reason, nil)
+		}
+		providerName = decision.Provider
+		fmt.Printf("Routed to %s: %s\n", providerName, decision.Reason)
+	}
+	providerName, err = resolveProviderName(providerName)
+	if err != nil {
+		return fmt.Errorf("failed to get provider: %w", err)
+	}
+	if task.ProjectID == "" && !cmd.Flags().Changed("project") {
+		task.ProjectID = registry.DefaultProject(providerName)
+	}
+
+	createLabels, _ := cmd.Flags().GetBool("create-labels")
+	queued := &providers.QueuedOperation{Type: providers.QueuedCreate, Provider: providerName, Task: task, CreateLabels: createLabels}
+	if offline, _ := cmd.Flags().GetBool("offline"); offline {
+		return queueOperation(queued, nil)
 	}
 
+	provider, err := registry.GetProvider(providerName)
 	if err != nil {
 		return fmt.Errorf("failed to get provider: %w", err)
 	}
 
+	if aiExpand, _ := cmd.Flags().GetBool("ai-expand"); aiExpand {
+		expandNewTask(task)
+	}
+
+	duplicateOf := getStringFlag(cmd, "duplicate-of")
+	if checkDuplicates, _ := cmd.Flags().GetBool("check-duplicates"); checkDuplicates && duplicateOf == "" {
+		choice, proceed := checkForDuplicates(cmd, providerName, provider, task)
+		if !proceed {
+			fmt.Println("Task not created")
+			return nil
+		}
+		duplicateOf = choice
+	}
+
 	// Create task
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := commandContext(cmd, providerName, defaultTaskTimeout)
 	defer cancel()
 
+	if task.AssigneeID, err = providers.ResolveAssignee(ctx, provider, task.AssigneeID); err != nil {
+		if providers.IsConnectivityError(err) {
+			return queueOperation(queued, err)
+		}
+		return err
+	}
+	checked, err := providers.ValidateLabels(ctx, provider, task.ProjectID, task.Labels, createLabels)
+	if err != nil {
+		if providers.IsConnectivityError(err) {
+			return queueOperation(queued, err)
+		}
+		return labelsError(err)
+	}
+	task.Labels = checked
+	if status != "" {
+		if task.Status, err = resolveStatus(ctx, provider, task.ProjectID, status); err != nil {
+			if providers.IsConnectivityError(err) {
+				return queueOperation(queued, err)
+			}
+			return err
+		}
+	}
+
+	if duplicateOf != "" {
+		createdTask, err := providers.CreateAsDuplicate(ctx, provider, task, duplicateOf)
+		if createdTask == nil {
+			return err
+		}
+		fmt.Printf("✅ Task created as a duplicate\n")
+		fmt.Printf("ID: %s\n", createdTask.GetDisplayID())
+		fmt.Printf("Title: %s\n", createdTask.Title)
+		fmt.Printf("Duplicate of: %s\n", duplicateOf)
+		return err
+	}
+
 	createdTask, err := provider.CreateTask(ctx, task)
 	if err != nil {
+		if providers.IsConnectivityError(err) {
+			return queueOperation(queued, err)
+		}
 		return fmt.Errorf("failed to create task: %w", err)
 	}
 
@@ -320,10 +934,69 @@ func runCreateTask(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
+// checkForDuplicates searches for tasks similar to one about to be created and
+// asks what to do if there are any. It returns the task to link the new one to
+// as a duplicate, if one was picked, and whether to create the task at all.
+// A failed search is only reported, so it never blocks creating the task.
+func checkForDuplicates(cmd *cobra.Command, providerName string, provider providers.TaskProvider, task *providers.UniversalTask) (string, bool) {
+	ctx, cancel := commandContext(cmd, providerName, defaultListTimeout)
+	defer cancel()
+
+	options := providers.DuplicateOptions{}
+	if aiSimilarity, _ := cmd.Flags().GetBool("ai-similarity"); aiSimilarity {
+		options.Scorer = newAIChains().DuplicateScorer()
+	}
+
+	candidates, err := providers.FindDuplicates(ctx, provider, task, options)
+	if err != nil {
+		fmt.Printf("⚠️  Duplicate check failed: %v\n", err)
+		return "", true
+	}
+	if len(candidates) == 0 {
+		return "", true
+	}
+
+	fmt.Printf("⚠️  Found %d possible duplicates of \"%s\":\n", len(candidates), task.Title)
+	for i, candidate := range candidates {
+		fmt.Printf("  %d. %-10s %3.0f%%  %s", i+1, candidate.Task.GetDisplayID(), candidate.Score*100, candidate.Task.Title)
+		if candidate.Task.Status.Name != "" {
+			fmt.Printf(" [%s]", candidate.Task.Status.Name)
+		}
+		fmt.Println()
+	}
+
+	_, canLink := providers.ProviderAs[providers.LinkProvider](provider)
+	if canLink {
+		fmt.Printf("Create anyway (c), create as duplicate of a candidate (1-%d), or cancel (Enter)? ", len(candidates))
+	} else {
+		fmt.Print("Create anyway (c) or cancel (Enter)? ")
+	}
+	var response string
+	fmt.Scanln(&response)
+	response = strings.ToLower(strings.TrimSpace(response))
+
+	if response == "c" || response == "create" {
+		return "", true
+	}
+	if n, err := strconv.Atoi(response); err == nil && canLink && n >= 1 && n <= len(candidates) {
+		return candidates[n-1].Task.GetDisplayID(), true
+	}
+	return "", false
+}
+
 func runListTasks(cmd *cobra.Command, args []string) error {
 	providerName, _ := cmd.Flags().GetString("provider")
 	providerNames, _ := cmd.Flags().GetStringSlice("providers")
-	output, _ := cmd.Flags().GetString("output")
+	output := outputFormat(cmd)
+
+	formatter, err := taskFormatterFromFlags(cmd)
+	if err != nil {
+		return err
+	}
+	columns, err := taskColumnsFromFlags(cmd)
+	if err != nil {
+		return err
+	}
 
 	// Build filters
 	filters := &providers.TaskFilters{
@@ -333,6 +1006,7 @@ func runListTasks(cmd *cobra.Command, args []string) error {
 		Limit:      getIntFlag(cmd, "limit"),
 		Offset:     getIntFlag(cmd, "offset"),
 	}
+	filters.ProjectByProvider = defaultProjects(cmd)
 
 	if status := getStringFlag(cmd, "status"); status != "" {
 		filters.Status = []string{status}
@@ -346,8 +1020,51 @@ func runListTasks(cmd *cobra.Command, args []string) error {
 	if labels, _ := cmd.Flags().GetStringSlice("labels"); len(labels) > 0 {
 		filters.Labels = labels
 	}
+	filters.LabelsAll = getStringSliceFlag(cmd, "labels-all")
+	filters.LabelsAny = getStringSliceFlag(cmd, "labels-any")
+	filters.LabelsNone = getStringSliceFlag(cmd, "labels-none")
+	if err := filters.LabelMatch().Validate(); err != nil {
+		return err
+	}
+	if filters.DueDateAfter, err = dueDateFlag(cmd, "due-after"); err != nil {
+		return err
+	}
+	if filters.DueDateBefore, err = dueDateFlag(cmd, "due-before"); err != nil {
+		return err
+	}
 
-	// Determine target providers
+	targetProviders, err := resolveTargetProviders(providerName, providerNames)
+	if err != nil {
+		return err
+	}
+	if output == "jsonl" && formatter == nil {
+		return streamTasks(cmd, targetProviders, filters)
+	}
+
+	allTasks, err := collectTasks(cmd, targetProviders, filters)
+	if err != nil && !isPartialFailure(err) {
+		return err
+	}
+
+	// Output results
+	if outputErr := outputTasks(output, formatter, columns, allTasks); outputErr != nil {
+		return outputErr
+	}
+	return err
+}
+
+// defaultProjects returns the default projects of the providers unless
+// --project was given; --project "" lists the tasks of all projects
+func defaultProjects(cmd *cobra.Command) map[string]string {
+	if cmd.Flags().Changed("project") {
+		return nil
+	}
+	return registry.DefaultProjects()
+}
+
+// resolveTargetProviders picks the providers to query from --provider/--providers,
+// falling back to the default provider. A cancelled provider prompt is an error.
+func resolveTargetProviders(providerName string, providerNames []string) ([]string, error) {
 	var targetProviders []string
 	if len(providerNames) > 0 && providerNames[0] == "all" {
 		enabledProviders := registry.ListEnabledProviders()
@@ -359,81 +1076,343 @@ func runListTasks(cmd *cobra.Command, args []string) error {
 	} else if providerName != "" {
 		targetProviders = []string{providerName}
 	} else {
-		// Use default provider
-		if defaultProvider, err := registry.GetDefaultProvider(); err == nil {
-			info := defaultProvider.GetProviderInfo()
-			targetProviders = []string{info.Name}
-		}
-	}
-
-	// Collect tasks from all target providers
-	var allTasks []*providers.UniversalTask
-	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
-	defer cancel()
-
-	for _, providerName := range targetProviders {
-		provider, err := registry.GetProvider(providerName)
+		// Use default provider, or ask when several are enabled
+		name, err := chooseProviderName()
 		if err != nil {
-			logger.Warnf("Failed to get provider %s: %v", providerName, err)
-			continue
+			return nil, err
 		}
-
-		tasks, err := provider.ListTasks(ctx, filters)
-		if err != nil {
-			logger.Warnf("Failed to list tasks from %s: %v", providerName, err)
-			continue
+		if name != "" {
+			targetProviders = []string{name}
 		}
+	}
+	return targetProviders, nil
+}
 
-		// Set provider name for display
-		for _, task := range tasks {
-			task.ProviderName = providerName
-		}
+// selectProvider returns the named provider or, if none is named, the one chosen by chooseProviderName
+func selectProvider(providerName string) (providers.TaskProvider, error) {
+	name, err := resolveProviderName(providerName)
+	if err != nil {
+		return nil, err
+	}
+	return registry.GetProvider(name)
+}
 
-		allTasks = append(allTasks, tasks...)
+// resolveProviderName returns providerName or, if it is empty, the name chosen by chooseProviderName
+func resolveProviderName(providerName string) (string, error) {
+	if providerName != "" {
+		return providerName, nil
 	}
 
-	// Output results
-	switch output {
-	case "json":
-		return outputJSON(allTasks)
-	case "yaml":
-		return outputYAML(allTasks)
-	default:
-		return outputTaskTable(allTasks)
+	name, err := chooseProviderName()
+	if err != nil {
+		return "", err
+	}
+	if name == "" {
+		return "", providers.NewProviderError(providers.ErrorTypeConfiguration, "no default provider configured", nil)
 	}
+	return name, nil
 }
 
-func runGetTask(cmd *cobra.Command, args []string) error {
-	search, _ := cmd.Flags().GetString("search")
-	providerName, _ := cmd.Flags().GetString("provider")
-	output, _ := cmd.Flags().GetString("output")
+// chooseProviderName asks which enabled provider to use when there are several
+// and the command runs in a terminal. Otherwise it returns the default provider.
+func chooseProviderName() (string, error) {
+	defaultName := registry.DefaultProviderName()
 
-	if search != "" {
-		return runSearchTasks(cmd, []string{search})
+	enabled := registry.ListEnabledProviders()
+	if len(enabled) <= 1 || !ui.IsInteractive() {
+		return defaultName, nil
 	}
 
-	if len(args) == 0 {
-		return fmt.Errorf("task ID is required")
+	options, byOption := providerOptions(enabled, defaultName)
+	selected, err := ui.SelectPrompt("Several providers are enabled. Which one should be used?", options)
+	if err != nil {
+		return "", fmt.Errorf("provider selection cancelled: %w", err)
 	}
+	return byOption[selected], nil
+}
 
-	taskID := args[0]
+// providerOptions returns the prompt options of chooseProviderName and the
+// provider each one stands for. The default provider comes first so Enter
+// keeps the old behavior, the others follow by name.
+func providerOptions(enabled map[string]*providers.ProviderInfo, defaultName string) ([]string, map[string]string) {
+	names := make([]string, 0, len(enabled))
+	for name := range enabled {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if (names[i] == defaultName) != (names[j] == defaultName) {
+			return names[i] == defaultName
+		}
+		return names[i] < names[j]
+	})
+
+	options := make([]string, len(names))
+	byOption := make(map[string]string, len(names))
+	for i, name := range names {
+		option := fmt.Sprintf("%s (%s)", name, enabled[name].Type)
+		if name == defaultName {
+			option += " [default]"
+		}
+		options[i] = option
+		byOption[option] = name
+	}
 
-	// Get provider
-	var provider providers.TaskProvider
-	var err error
+	return options, byOption
+}
 
-	if providerName != "" {
-		provider, err = registry.GetProvider(providerName)
-	} else {
-		provider, err = registry.GetDefaultProvider()
-	}
+// collectTasks lists tasks from every target provider concurrently, skipping
+// providers that fail. If only some providers fail, the error is a
+// PartialFailureError, or an IncompleteResultsError if the timeout cut the
+// listing short, and the tasks of the others are still returned.
+func collectTasks(cmd *cobra.Command, targetProviders []string, filters *providers.TaskFilters) ([]*providers.UniversalTask, error) {
+	ctx, cancel := commandContext(cmd, "", defaultListTimeout)
+	defer cancel()
 
-	if err != nil {
-		return fmt.Errorf("failed to get provider: %w", err)
+	result := providers.FetchTasks(ctx, targetProviders, registry.GetProvider, filters, providers.DefaultFetchParallelism)
+	err := fetchError(result, targetProviders)
+	if err != nil && !isPartialFailure(err) {
+		return nil, err
 	}
+	return result.Tasks, err
+}
 
-	// Get task
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+// streamTasks writes the tasks of every target provider to stdout as JSON
+// lines while they are fetched, failing like collectTasks
+func streamTasks(cmd *cobra.Command, targetProviders []string, filters *providers.TaskFilters) error {
+	ctx, cancel := commandContext(cmd, "", defaultListTimeout)
+	defer cancel()
+
+	// One Write per task, so readers get every line as soon as it is fetched
+	encoder := json.NewEncoder(os.Stdout)
+	result, err := providers.StreamTasks(ctx, targetProviders, registry.GetProvider, filters,
+		providers.DefaultFetchParallelism, providers.DefaultStreamPageSize, func(task *providers.UniversalTask) error {
+			return encoder.Encode(task)
+		})
+	if err != nil {
+		return fmt.Errorf("failed to write tasks: %w", err)
+	}
+	return fetchError(result, targetProviders)
+}
+
+// fetchError logs the providers tasks could not be listed from and returns
+// an error if all of them failed, or a partial failure if only some did
+func fetchError(result *providers.FetchResult, targetProviders []string) error {
+	for _, failure := range result.Failures {
+		logger.Warnf("Failed to list tasks from %s: %v", failure.Provider, failure.Err)
+	}
+
+	switch failed := len(result.Failures); {
+	case failed == 0:
+		return nil
+	case failed == len(targetProviders):
+		last := result.Failures[failed-1]
+		return fmt.Errorf("failed to list tasks from %s: %w", last.Provider, last.Err)
+	case result.TimedOut:
+		return &providers.IncompleteResultsError{Fetched: len(targetProviders) - failed, Total: len(targetProviders)}
+	default:
+		return providers.NewPartialFailureError(failed, len(targetProviders), "providers")
+	}
+}
+
+// commandContext returns a context with the deadline of a task command, see
+// providerCmd.CommandContext
+func commandContext(cmd *cobra.Command, providerName string, fallback time.Duration) (context.Context, context.CancelFunc) {
+	return providerCmd.CommandContext(cmd, registry, providerName, fallback)
+}
+
+// isPartialFailure reports whether err only means that some items of an operation failed
+func isPartialFailure(err error) bool {
+	var partial *providers.PartialFailureError
+	return errors.As(err, &partial)
+}
+
+func runStatsTasks(cmd *cobra.Command, args []string) error {
+	providerName, _ := cmd.Flags().GetString("provider")
+	providerNames, _ := cmd.Flags().GetStringSlice("providers")
+	output := outputFormat(cmd)
+
+	filters := &providers.TaskFilters{
+		ProjectID:  getStringFlag(cmd, "project"),
+		AssigneeID: getStringFlag(cmd, "assignee"),
+		Limit:      getIntFlag(cmd, "limit"),
+	}
+
+	targetProviders, err := resolveTargetProviders(providerName, providerNames)
+	if err != nil {
+		return err
+	}
+	tasks, err := collectTasks(cmd, targetProviders, filters)
+	if err != nil && !isPartialFailure(err) {
+		return err
+	}
+	stats := providers.ComputeTaskStats(tasks)
+
+	var outputErr error
+	switch output {
+	case "json":
+		outputErr = outputJSON(stats)
+	case "yaml":
+		outputErr = outputYAML(stats)
+	default:
+		outputErr = outputTaskStats(stats)
+	}
+	if outputErr != nil {
+		return outputErr
+	}
+	return err
+}
+
+func runBalanceTasks(cmd *cobra.Command, args []string) error {
+	providerName, _ := cmd.Flags().GetString("provider")
+	providerNames, _ := cmd.Flags().GetStringSlice("providers")
+	output := outputFormat(cmd)
+	maxPerAssignee, _ := cmd.Flags().GetInt("max-per-assignee")
+	apply, _ := cmd.Flags().GetBool("apply")
+
+	if maxPerAssignee < 0 {
+		return providers.NewValidationError("--max-per-assignee must not be negative", nil)
+	}
+
+	filters := &providers.TaskFilters{
+		ProjectID: getStringFlag(cmd, "project"),
+		Limit:     getIntFlag(cmd, "limit"),
+	}
+
+	// A plan over some providers only would move tasks based on partial workloads
+	targetProviders, err := resolveTargetProviders(providerName, providerNames)
+	if err != nil {
+		return err
+	}
+	tasks, err := collectTasks(cmd, targetProviders, filters)
+	if err != nil {
+		return err
+	}
+	plan := providers.PlanRebalance(tasks, providers.BalanceOptions{
+		MaxPerAssignee: maxPerAssignee,
+		Assignees:      getStringSliceFlag(cmd, "assignees"),
+	})
+
+	switch output {
+	case "json":
+		if err := outputJSON(plan); err != nil {
+			return err
+		}
+	case "yaml":
+		if err := outputYAML(plan); err != nil {
+			return err
+		}
+	default:
+		outputBalancePlan(plan)
+	}
+
+	if !apply || len(plan.Reassignments) == 0 {
+		return nil
+	}
+
+	ctx, cancel := commandContext(cmd, providerName, defaultListTimeout)
+	defer cancel()
+
+	failed := 0
+	for _, move := range plan.Reassignments {
+		if err := applyReassignment(ctx, move); err != nil {
+			logger.Warnf("Failed to reassign %s to %s: %v", move.TaskID, move.To, err)
+			failed++
+			continue
+		}
+		if output == "table" {
+			fmt.Printf("✅ %s reassigned to %s\n", move.TaskID, move.To)
+		}
+	}
+
+	if failed > 0 {
+		return providers.NewPartialFailureError(failed, len(plan.Reassignments), "reassignments")
+	}
+	return nil
+}
+
+// applyReassignment changes the assignee of a task in its provider
+func applyReassignment(ctx context.Context, move providers.Reassignment) error {
+	provider, err := registry.GetProvider(move.Task.ProviderName)
+	if err != nil {
+		return fmt.Errorf("failed to get provider: %w", err)
+	}
+
+	id := move.Task.ID
+	if id == "" {
+		id = move.TaskID
+	}
+
+	assignee := move.To
+	return provider.UpdateTask(ctx, id, &providers.TaskUpdate{AssigneeID: &assignee})
+}
+
+func runPlanOrder(cmd *cobra.Command, args []string) error {
+	providerName, _ := cmd.Flags().GetString("provider")
+	providerNames, _ := cmd.Flags().GetStringSlice("providers")
+	output := outputFormat(cmd)
+
+	filters := &providers.TaskFilters{
+		ProjectID:  getStringFlag(cmd, "project"),
+		AssigneeID: getStringFlag(cmd, "assignee"),
+		Limit:      getIntFlag(cmd, "limit"),
+	}
+
+	targetProviders, err := resolveTargetProviders(providerName, providerNames)
+	if err != nil {
+		return err
+	}
+	tasks, err := collectTasks(cmd, targetProviders, filters)
+	if err != nil {
+		return err
+	}
+
+	plan, err := providers.PlanExecutionOrder(tasks)
+	if err != nil {
+		return err
+	}
+
+	switch output {
+	case "json":
+		return outputJSON(plan)
+	case "yaml":
+		return outputYAML(plan)
+	default:
+		return outputExecutionPlan(plan)
+	}
+}
+
+func runGetTask(cmd *cobra.Command, args []string) error {
+	search, _ := cmd.Flags().GetString("search")
+	providerName, _ := cmd.Flags().GetString("provider")
+	output := outputFormat(cmd)
+
+	if search != "" {
+		return runSearchTasks(cmd, []string{search})
+	}
+
+	if len(args) == 0 {
+		return providers.NewValidationError("task ID is required", nil)
+	}
+
+	formatter, err := taskFormatterFromFlags(cmd)
+	if err != nil {
+		return err
+	}
+
+	taskID := args[0]
+
+	// Get provider
+	name, err := resolveProviderName(providerName)
+	if err != nil {
+		return err
+	}
+	provider, err := registry.GetProvider(name)
+	if err != nil {
+		return fmt.Errorf("failed to get provider: %w", err)
+	}
+
+	// Get task
+	ctx, cancel := commandContext(cmd, providerName, defaultTaskTimeout)
 	defer cancel()
 
 	task, err := provider.GetTask(ctx, taskID)
@@ -442,74 +1421,892 @@ func runGetTask(cmd *cobra.Command, args []string) error {
 	}
 
 	// Output result
+	if formatter != nil {
+		return outputTaskTemplate(formatter, []*providers.UniversalTask{task})
+	}
+
 	switch output {
 	case "json":
 		return outputJSON(task)
 	case "yaml":
 		return outputYAML(task)
 	default:
-		return outputTaskDetails(task)
+		raw, _ := cmd.Flags().GetBool("raw")
+		if err := outputTaskDetails(task, raw); err != nil {
+			return err
+		}
+		outputTaskNote(name, task)
+		return nil
+	}
+}
+
+func runUpdateTask(cmd *cobra.Command, args []string) error {
+	taskID := args[0]
+	providerName, _ := cmd.Flags().GetString("provider")
+	offline, _ := cmd.Flags().GetBool("offline")
+	confirm, _ := cmd.Flags().GetBool("confirm")
+
+	if offline && confirm {
+		return providers.NewValidationError("--confirm needs the current task and can't be used with --offline", nil)
+	}
+
+	// Get provider
+	providerName, err := resolveProviderName(providerName)
+	if err != nil {
+		return fmt.Errorf("failed to get provider: %w", err)
+	}
+
+	provider, err := registry.GetProvider(providerName)
+	if err != nil {
+		return fmt.Errorf("failed to get provider: %w", err)
+	}
+
+	// Build updates
+	updates := &providers.TaskUpdate{}
+
+	if title := getStringFlag(cmd, "title"); title != "" {
+		updates.Title = &title
+	}
+	if description := getStringFlag(cmd, "description"); description != "" {
+		updates.Description = &description
+	}
+	status := getStringFlag(cmd, "status")
+	if status != "" {
+		taskStatus := typedStatus(status)
+		updates.Status = &taskStatus
+	}
+	if priority := getStringFlag(cmd, "priority"); priority != "" {
+		taskPriority, err := mapPriority(providerName, priority)
+		if err != nil {
+			return err
+		}
+		updates.Priority = &taskPriority
+	}
+	if assignee := getStringFlag(cmd, "assignee"); assignee != "" {
+		updates.AssigneeID = &assignee
+	}
+
+	// Handle labels
+	if labels, _ := cmd.Flags().GetStringSlice("labels"); len(labels) > 0 {
+		updates.Labels = labels
+	}
+	if updates.DueDate, err = dueDateFlag(cmd, "due"); err != nil {
+		return err
+	}
+
+	// TODO: Handle add-labels and remove-labels
+
+	createLabels, _ := cmd.Flags().GetBool("create-labels")
+	queued := &providers.QueuedOperation{Type: providers.QueuedUpdate, Provider: providerName, TaskID: taskID, Update: updates, CreateLabels: createLabels}
+	if offline {
+		return queueOperation(queued, nil)
+	}
+
+	// Update task
+	ctx, cancel := commandContext(cmd, providerName, defaultTaskTimeout)
+	defer cancel()
+
+	if updates.AssigneeID != nil {
+		if *updates.AssigneeID, err = providers.ResolveAssignee(ctx, provider, *updates.AssigneeID); err != nil {
+			if providers.IsConnectivityError(err) {
+				return queueOperation(queued, err)
+			}
+			return err
+		}
+	}
+	if updates.Labels != nil {
+		checked, err := providers.ValidateLabels(ctx, provider, "", updates.Labels, createLabels)
+		if err != nil {
+			if providers.IsConnectivityError(err) {
+				return queueOperation(queued, err)
+			}
+			return labelsError(err)
+		}
+		updates.Labels = checked
+	}
+
+	// The status is matched against the workflow of the task's project
+	var current *providers.UniversalTask
+	if confirm || status != "" {
+		if current, err = provider.GetTask(ctx, taskID); err != nil {
+			if providers.IsConnectivityError(err) && !confirm {
+				return queueOperation(queued, err)
+			}
+			return fmt.Errorf("failed to get task: %w", err)
+		}
+	}
+	if status != "" {
+		taskStatus, err := resolveStatus(ctx, provider, current.ProjectID, status)
+		if err != nil {
+			if providers.IsConnectivityError(err) {
+				return queueOperation(queued, err)
+			}
+			return err
+		}
+		updates.Status = &taskStatus
+	}
+
+	if confirm {
+		changes := providers.DiffTaskUpdate(current, updates)
+		if len(changes) == 0 {
+			fmt.Printf("No changes to apply to task %s\n", taskID)
+			return nil
+		}
+
+		fmt.Printf("Proposed changes to %s:\n\n%s\n", current.GetDisplayID(), providers.FormatTaskDiff(changes))
+		fmt.Print("Apply these changes? (y/N): ")
+		var response string
+		fmt.Scanln(&response)
+		if strings.ToLower(response) != "y" && strings.ToLower(response) != "yes" {
+			fmt.Println("Operation cancelled")
+			return nil
+		}
+	}
+
+	if err := provider.UpdateTask(ctx, taskID, updates); err != nil {
+		if providers.IsConnectivityError(err) {
+			return queueOperation(queued, err)
+		}
+		return fmt.Errorf("failed to update task: %w", err)
+	}
+
+	fmt.Printf("✅ Task %s updated successfully\n", taskID)
+	return nil
+}
+
+func runCloseTask(cmd *cobra.Command, args []string) error {
+	resolution, _ := cmd.Flags().GetString("resolution")
+
+	return transitionTask(cmd, args[0], "closed", func(task *providers.UniversalTask, statuses []providers.TaskStatus) (*providers.TaskUpdate, error) {
+		status, ok := providers.FinalStatus(statuses)
+		if !ok {
+			return nil, fmt.Errorf("no done status found in the workflow of project %s", task.ProjectID)
+		}
+
+		updates := &providers.TaskUpdate{Status: &status}
+
+		// Only touch the resolution when the provider has one or it was asked for explicitly
+		field, hasField := providers.ResolutionField(task)
+		if !hasField && cmd.Flags().Changed("resolution") {
+			field, hasField = providers.ResolutionFieldName, true
+		}
+		if hasField && resolution != "" {
+			updates.CustomFields = map[string]interface{}{field: resolution}
+		}
+
+		return updates, nil
+	})
+}
+
+func runReopenTask(cmd *cobra.Command, args []string) error {
+	return transitionTask(cmd, args[0], "reopened", func(task *providers.UniversalTask, statuses []providers.TaskStatus) (*providers.TaskUpdate, error) {
+		status, ok := providers.InitialStatus(statuses)
+		if !ok {
+			return nil, fmt.Errorf("no initial status found in the workflow of project %s", task.ProjectID)
+		}
+
+		updates := &providers.TaskUpdate{Status: &status}
+		if field, ok := providers.ResolutionField(task); ok {
+			updates.CustomFields = map[string]interface{}{field: nil}
+		}
+
+		return updates, nil
+	})
+}
+
+// transitionTask moves a task to the status chosen by pick from its project's workflow
+func transitionTask(cmd *cobra.Command, taskID, verb string, pick func(*providers.UniversalTask, []providers.TaskStatus) (*providers.TaskUpdate, error)) error {
+	providerName, _ := cmd.Flags().GetString("provider")
+
+	// Get provider
+	var provider providers.TaskProvider
+	var err error
+
+	provider, err = selectProvider(providerName)
+
+	if err != nil {
+		return fmt.Errorf("failed to get provider: %w", err)
+	}
+
+	ctx, cancel := commandContext(cmd, providerName, defaultTaskTimeout)
+	defer cancel()
+
+	task, err := provider.GetTask(ctx, taskID)
+	if err != nil {
+		return fmt.Errorf("failed to get task: %w", err)
+	}
+
+	statuses, err := provider.GetAvailableStatuses(ctx, task.ProjectID)
+	if err != nil {
+		return fmt.Errorf("failed to get workflow statuses: %w", err)
+	}
+
+	updates, err := pick(task, statuses)
+	if err != nil {
+		return err
+	}
+
+	if providers.SameStatus(task.Status, *updates.Status) && updates.CustomFields == nil {
+		fmt.Printf("Task %s is already %s\n", taskID, updates.Status.Name)
+		return nil
+	}
+
+	if err := provider.UpdateTask(ctx, taskID, updates); err != nil {
+		return fmt.Errorf("failed to update task: %w", err)
+	}
+
+	fmt.Printf("✅ Task %s %s (%s → %s)\n", taskID, verb, task.Status.Name, updates.Status.Name)
+	return nil
+}
+
+func runCloneTask(cmd *cobra.Command, args []string) error {
+	providerName, _ := cmd.Flags().GetString("provider")
+	targetName, _ := cmd.Flags().GetString("to-provider")
+	taskID := args[0]
+
+	source, err := selectProvider(providerName)
+	if err != nil {
+		return fmt.Errorf("failed to get provider: %w", err)
+	}
+
+	target := source
+	if targetName != "" {
+		target, err = registry.GetProvider(targetName)
+		if err != nil {
+			return fmt.Errorf("failed to get target provider: %w", err)
+		}
+	}
+
+	ctx, cancel := commandContext(cmd, providerName, defaultTaskTimeout)
+	defer cancel()
+
+	task, err := source.GetTask(ctx, taskID)
+	if err != nil {
+		return fmt.Errorf("failed to get task: %w", err)
+	}
+
+	clone := providers.CloneTask(task)
+	if title := getStringFlag(cmd, "title"); title != "" {
+		clone.Title = title
+	}
+	if project := getStringFlag(cmd, "project"); project != "" {
+		clone.ProjectID = project
+	}
+
+	createdTask, err := target.CreateTask(ctx, clone)
+	if err != nil {
+		return fmt.Errorf("failed to create clone: %w", err)
+	}
+
+	fmt.Printf("✅ Task %s cloned\n", task.GetDisplayID())
+	fmt.Printf("ID: %s\n", createdTask.GetDisplayID())
+	fmt.Printf("Title: %s\n", createdTask.Title)
+	fmt.Printf("Provider: %s\n", createdTask.ProviderName)
+
+	return nil
+}
+
+func runDiffTask(cmd *cobra.Command, args []string) error {
+	output := outputFormat(cmd)
+	fromName, err := resolveProviderName(getStringFlag(cmd, "from"))
+	if err != nil {
+		return err
+	}
+	toName := getStringFlag(cmd, "to")
+	if toName == fromName {
+		return providers.NewValidationError("--from and --to must be different providers", nil)
+	}
+
+	from, err := registry.GetProvider(fromName)
+	if err != nil {
+		return fmt.Errorf("failed to get provider: %w", err)
+	}
+	to, err := registry.GetProvider(toName)
+	if err != nil {
+		return fmt.Errorf("failed to get provider: %w", err)
+	}
+
+	ctx, cancel := commandContext(cmd, fromName, defaultTaskTimeout)
+	defer cancel()
+
+	task, err := from.GetTask(ctx, args[0])
+	if err != nil {
+		return fmt.Errorf("failed to get task from %s: %w", fromName, err)
+	}
+
+	var mirror *providers.UniversalTask
+	if toID := getStringFlag(cmd, "to-id"); toID != "" {
+		mirror, err = to.GetTask(ctx, toID)
+		if err != nil && !providers.IsNotFoundError(err) {
+			return fmt.Errorf("failed to get task from %s: %w", toName, err)
+		}
+	} else if mirror, err = findMirror(ctx, to, task); err != nil {
+		return fmt.Errorf("failed to search %s: %w", toName, err)
+	}
+
+	diff := providers.CompareMirroredTasks(task, mirror)
+	diff.FromProvider, diff.ToProvider = fromName, toName
+
+	switch output {
+	case "json":
+		err = outputJSON(diff)
+	case "yaml":
+		err = outputYAML(diff)
+	default:
+		outputMirrorDiff(diff)
+	}
+	if err != nil {
+		return err
+	}
+
+	if diff.Missing != "" {
+		cmd.SilenceUsage = true
+		return providers.NewProviderError(providers.ErrorTypeNotFound,
+			fmt.Sprintf("no mirror of %s found in %s", task.GetDisplayID(), toName), nil)
+	}
+	return nil
+}
+
+// findMirror searches provider for tasks mentioning the ID or title of task
+// and picks its mirror among them
+func findMirror(ctx context.Context, provider providers.TaskProvider, task *providers.UniversalTask) (*providers.UniversalTask, error) {
+	var candidates []*providers.UniversalTask
+	for _, query := range []string{task.GetDisplayID(), task.Title} {
+		if strings.TrimSpace(query) == "" {
+			continue
+		}
+		found, err := provider.ListTasks(ctx, &providers.TaskFilters{Query: query, Limit: 20})
+		if err != nil {
+			return nil, err
+		}
+		candidates = append(candidates, found...)
+		if mirror := providers.FindMirror(task, candidates); mirror != nil {
+			return mirror, nil
+		}
+	}
+	return nil, nil
+}
+
+func outputMirrorDiff(diff *providers.MirrorDiff) {
+	if diff.Missing != "" {
+		fmt.Printf("⚠️  No mirror of %s found in %s\n", diff.From.GetDisplayID(), diff.ToProvider)
+		fmt.Printf("Use --to-id to compare with a specific task\n")
+		return
+	}
+
+	side := map[string]string{
+		providers.MirrorSideFrom: diff.FromProvider,
+		providers.MirrorSideTo:   diff.ToProvider,
+	}
+	fmt.Printf("%s (%s) ↔ %s (%s)\n", diff.From.GetDisplayID(), diff.FromProvider, diff.To.GetDisplayID(), diff.ToProvider)
+	fmt.Printf("Updated: %s / %s", formatUpdated(diff.From.UpdatedAt), formatUpdated(diff.To.UpdatedAt))
+	if diff.Newer != "" {
+		fmt.Printf(" - %s is newer", side[diff.Newer])
+	}
+	fmt.Printf("\n\n")
+
+	if len(diff.Changes) == 0 {
+		fmt.Println("✅ The tasks match")
+		return
+	}
+	fmt.Printf("%d fields differ (%s → %s):\n\n%s", len(diff.Changes), diff.FromProvider, diff.ToProvider, providers.FormatTaskDiff(diff.Changes))
+}
+
+func formatUpdated(t time.Time) string {
+	if t.IsZero() {
+		return "unknown"
+	}
+	return t.Local().Format("2006-01-02 15:04")
+}
+
+// assignResult is the outcome of one assignment made by tasks assign
+type assignResult struct {
+	providers.Assignment
+	Error string `json:"error,omitempty"`
+}
+
+func runAssignTasks(cmd *cobra.Command, args []string) error {
+	providerName, _ := cmd.Flags().GetString("provider")
+	output := outputFormat(cmd)
+	to := getStringFlag(cmd, "to")
+	roundRobin := getStringSliceFlag(cmd, "round-robin")
+
+	if (to == "") == (len(roundRobin) == 0) {
+		return providers.NewValidationError("use either --to or --round-robin", nil)
+	}
+	names := roundRobin
+	if to != "" {
+		names = []string{to}
+	}
+
+	provider, err := selectProvider(providerName)
+	if err != nil {
+		return fmt.Errorf("failed to get provider: %w", err)
+	}
+
+	ctx, cancel := commandContext(cmd, providerName, defaultListTimeout)
+	defer cancel()
+
+	// A typo in one name shouldn't leave the tasks half assigned
+	users := make([]*providers.User, len(names))
+	for i, name := range names {
+		if users[i], err = providers.ResolveUser(ctx, provider, name); err != nil {
+			return err
+		}
+	}
+
+	var results []assignResult
+	failed := 0
+	for _, assignment := range providers.AssignRoundRobin(args, users) {
+		result := assignResult{Assignment: assignment}
+		userID := assignment.User.ID
+		if err := provider.UpdateTask(ctx, assignment.TaskID, &providers.TaskUpdate{AssigneeID: &userID}); err != nil {
+			result.Error = err.Error()
+			failed++
+		}
+		results = append(results, result)
+	}
+
+	switch output {
+	case "json":
+		if err := outputJSON(results); err != nil {
+			return err
+		}
+	case "yaml":
+		if err := outputYAML(results); err != nil {
+			return err
+		}
+	default:
+		for _, result := range results {
+			if result.Error != "" {
+				fmt.Printf("❌ %s: %s\n", result.TaskID, result.Error)
+				continue
+			}
+			fmt.Printf("✅ %-15s → %s\n", result.TaskID, result.User.DisplayName())
+		}
+		fmt.Printf("\nAssigned %d of %d tasks\n", len(results)-failed, len(results))
+	}
+
+	if failed > 0 {
+		return providers.NewPartialFailureError(failed, len(results), "assignments")
+	}
+	return nil
+}
+
+func runTriageTasks(cmd *cobra.Command, args []string) error {
+	providerName, _ := cmd.Flags().GetString("provider")
+	apply, _ := cmd.Flags().GetBool("apply")
+	output := outputFormat(cmd)
+	structured := output == "json" || output == "yaml"
+	options := providers.TriageOptions{
+		ProjectID: getStringFlag(cmd, "project"),
+		Limit:     getIntFlag(cmd, "limit"),
+	}
+
+	provider, err := selectProvider(providerName)
+	if err != nil {
+		return fmt.Errorf("failed to get provider: %w", err)
+	}
+
+	chains := newAIChains()
+	scheduler := newAIScheduler(chains)
+
+	// Paced runs take as long as the limits require
+	fallback := 5 * time.Minute
+	if scheduler.Limited() {
+		fallback = 0
+	}
+	ctx, cancel := commandContext(cmd, providerName, fallback)
+	defer cancel()
+
+	progress := newAIProgress("Triaging", !structured)
+	options.Progress = progress.update
+	scheduler.SetWaitHandler(progress.wait)
+	stopWatching := watchPause(scheduler, cancel)
+
+	results, _, err := providers.Triage(ctx, provider, options, scheduler.TriageSuggester(chains.TriageSuggester()))
+	stopWatching()
+	progress.finish()
+	if err != nil {
+		return err
+	}
+	if ctx.Err() != nil {
+		if !structured {
+			printTriageResults(results)
+		}
+		return fmt.Errorf("triage stopped after %d tasks: %w", len(results), ctx.Err())
+	}
+
+	pending := 0
+	for _, result := range results {
+		if result.Update != nil && result.Error == "" {
+			pending++
+		}
+	}
+
+	if !structured {
+		if len(results) == 0 {
+			fmt.Println("No untriaged tasks")
+			return nil
+		}
+		printTriageResults(results)
+	}
+
+	if pending > 0 && !apply && !structured {
+		apply = confirmBulk(fmt.Sprintf("\nApply the suggestions to %d tasks? (y/N): ", pending))
+	}
+
+	failed := 0
+	if apply {
+		var applied int
+		applied, failed = providers.ApplyTriage(ctx, provider, results)
+		if !structured {
+			for _, result := range results {
+				if result.Update != nil && !result.Applied && result.Error != "" {
+					fmt.Printf("❌ %s: %s\n", result.TaskID, result.Error)
+				}
+			}
+			fmt.Printf("Triaged %d of %d tasks\n", applied, pending)
+		}
+	}
+
+	switch output {
+	case "json":
+		if err := outputJSON(results); err != nil {
+			return err
+		}
+	case "yaml":
+		if err := outputYAML(results); err != nil {
+			return err
+		}
+	}
+
+	if failed > 0 {
+		return providers.NewPartialFailureError(failed, pending, "triage updates")
+	}
+	return nil
+}
+
+// printTriageResults shows the suggested changes of each task
+func printTriageResults(results []*providers.TriageResult) {
+	for _, result := range results {
+		fmt.Printf("\n%s  %s\n", result.TaskID, result.Title)
+		switch {
+		case result.Error != "":
+			fmt.Printf("  ❌ %s\n", result.Error)
+			continue
+		case len(result.Changes) == 0:
+			fmt.Println("  Nothing to change")
+		}
+		for _, change := range result.Changes {
+			if change.Old == "" {
+				fmt.Printf("  %-10s %s\n", change.Field, change.New)
+			} else {
+				fmt.Printf("  %-10s %s → %s\n", change.Field, change.Old, change.New)
+			}
+		}
+		if result.Suggestion != nil && result.Suggestion.Reason != "" {
+			fmt.Printf("  💡 %s\n", result.Suggestion.Reason)
+		}
+	}
+}
+
+// expandResult is the structured output of expand
+type expandResult struct {
+	TaskID      string            `json:"taskId" yaml:"taskId"`
+	Title       string            `json:"title" yaml:"title"`
+	Expansion   *ai.TaskExpansion `json:"expansion" yaml:"expansion"`
+	Description string            `json:"description" yaml:"description"`
+	Applied     bool              `json:"applied" yaml:"applied"`
+}
+
+func runExpandTask(cmd *cobra.Command, args []string) error {
+	providerName, _ := cmd.Flags().GetString("provider")
+	apply, _ := cmd.Flags().GetBool("apply")
+	output := outputFormat(cmd)
+	structured := output == "json" || output == "yaml"
+	taskID := args[0]
+
+	provider, err := selectProvider(providerName)
+	if err != nil {
+		return fmt.Errorf("failed to get provider: %w", err)
+	}
+
+	ctx, cancel := commandContext(cmd, providerName, 2*time.Minute)
+	defer cancel()
+
+	task, err := provider.GetTask(ctx, taskID)
+	if err != nil {
+		return fmt.Errorf("failed to get task: %w", err)
+	}
+
+	expansion, err := newAIChains().ExpandTask(task.Title, task.Description, string(task.Type))
+	if err != nil {
+		return err
+	}
+	result := &expandResult{
+		TaskID:      task.GetDisplayID(),
+		Title:       task.Title,
+		Expansion:   expansion,
+		Description: expansion.Description(task.Description),
+	}
+
+	if !structured {
+		fmt.Printf("%s  %s\n\n%s\n", result.TaskID, result.Title, result.Description)
+		if !apply {
+			apply = confirmBulk(fmt.Sprintf("Update the description of %s? (y/N): ", result.TaskID))
+		}
+	}
+
+	if apply {
+		if err := provider.UpdateTask(ctx, taskID, &providers.TaskUpdate{Description: &result.Description}); err != nil {
+			return fmt.Errorf("failed to update task: %w", err)
+		}
+		result.Applied = true
+		if !structured {
+			fmt.Printf("✅ Description of %s updated\n", result.TaskID)
+		}
+	}
+
+	switch output {
+	case "json":
+		return outputJSON(result)
+	case "yaml":
+		return outputYAML(result)
+	}
+	return nil
+}
+
+// expandNewTask replaces the description of a task about to be created with
+// one generated from its title. In a terminal the description is shown for
+// review first. A failed expansion is only reported, so the task is still created.
+func expandNewTask(task *providers.UniversalTask) {
+	expansion, err := newAIChains().ExpandTask(task.Title, task.Description, string(task.Type))
+	if err != nil {
+		fmt.Printf("⚠️  Failed to expand the description: %v\n", err)
+		return
+	}
+	description := expansion.Description(task.Description)
+
+	if ui.IsInteractive() {
+		fmt.Printf("Generated description:\n\n%s\n", description)
+		if !confirmBulk("Use the generated description? (y/N): ") {
+			return
+		}
+	}
+	task.Description = description
+}
+
+func runTaskActivity(cmd *cobra.Command, args []string) error {
+	providerName, _ := cmd.Flags().GetString("provider")
+	fields := getStringSliceFlag(cmd, "field")
+	taskID := args[0]
+
+	providerName, err := resolveProviderName(providerName)
+	if err != nil {
+		return fmt.Errorf("failed to get provider: %w", err)
+	}
+	provider, err := registry.GetProvider(providerName)
+	if err != nil {
+		return fmt.Errorf("failed to get provider: %w", err)
+	}
+
+	ctx, cancel := commandContext(cmd, providerName, defaultListTimeout)
+	defer cancel()
+
+	activityLog := providers.NewActivityLog(config.ProfilePath(providers.ActivityLogFile))
+	entries, err := providers.GetTaskActivity(ctx, provider, providerName, taskID, activityLog)
+	if err != nil {
+		return err
+	}
+
+	if len(fields) > 0 {
+		var filtered []providers.ActivityEntry
+		for _, entry := range entries {
+			for _, field := range fields {
+				if strings.EqualFold(entry.Field, field) {
+					filtered = append(filtered, entry)
+					break
+				}
+			}
+		}
+		entries = filtered
+	}
+
+	switch outputFormat(cmd) {
+	case "json":
+		return outputJSON(entries)
+	case "yaml":
+		return outputYAML(entries)
+	}
+
+	if len(entries) == 0 {
+		fmt.Printf("No activity found for %s\n", taskID)
+		return nil
+	}
+
+	fmt.Printf("%-17s %-15s %-12s %-25s %s\n", "WHEN", "ACTOR", "FIELD", "FROM", "TO")
+	fmt.Printf("%-17s %-15s %-12s %-25s %s\n", "----", "-----", "-----", "----", "--")
+	for _, entry := range entries {
+		fmt.Printf("%-17s %-15s %-12s %-25s %s\n",
+			entry.At.Local().Format("2006-01-02 15:04"),
+			activityColumn(entry.Actor, 15),
+			activityColumn(entry.Field, 12),
+			activityColumn(entry.From, 25),
+			activityColumn(entry.To, 40),
+		)
+	}
+
+	if entries[0].Source == providers.ActivitySourceLocal {
+		fmt.Printf("\n%s keeps no change log: showing changes made through ricochet\n", providerName)
+	}
+	return nil
+}
+
+// activityColumn fits a value, possibly multi-line text, into a table column
+func activityColumn(value string, width int) string {
+	value = strings.Join(strings.Fields(value), " ")
+	if len([]rune(value)) > width {
+		return string([]rune(value)[:width-3]) + "..."
+	}
+	return value
+}
+
+func runExportTasks(cmd *cobra.Command, args []string) error {
+	providerName, _ := cmd.Flags().GetString("provider")
+	filename := getStringFlag(cmd, "file")
+
+	include, err := providers.ParseArchiveInclude(getStringSliceFlag(cmd, "include"))
+	if err != nil {
+		return err
+	}
+
+	if providerName, err = resolveProviderName(providerName); err != nil {
+		return err
+	}
+	provider, err := registry.GetProvider(providerName)
+	if err != nil {
+		return fmt.Errorf("failed to get provider: %w", err)
+	}
+
+	ctx, cancel := commandContext(cmd, providerName, 10*time.Minute)
+	defer cancel()
+
+	var tasks []*providers.UniversalTask
+	if len(args) > 0 {
+		for _, taskID := range args {
+			task, err := provider.GetTask(ctx, taskID)
+			if err != nil {
+				return fmt.Errorf("failed to get task %s: %w", taskID, err)
+			}
+			tasks = append(tasks, task)
+		}
+	} else {
+		filters := &providers.TaskFilters{
+			ProjectID:  getStringFlag(cmd, "project"),
+			AssigneeID: getStringFlag(cmd, "assignee"),
+			Limit:      getIntFlag(cmd, "limit"),
+		}
+		if status := getStringFlag(cmd, "status"); status != "" {
+			filters.Status = []string{status}
+		}
+		if filters, err = providers.ResolveAssigneeFilter(ctx, provider, filters); err != nil {
+			return err
+		}
+		if tasks, err = provider.ListTasks(ctx, filters); err != nil {
+			return fmt.Errorf("failed to list tasks: %w", err)
+		}
+	}
+
+	file, err := os.Create(filename)
+	if err != nil {
+		return fmt.Errorf("failed to create archive: %w", err)
+	}
+	manifest, err := providers.ExportArchive(ctx, file, providerName, provider, tasks, include)
+	if closeErr := file.Close(); err == nil && closeErr != nil {
+		err = fmt.Errorf("failed to write archive: %w", closeErr)
+	}
+	if err != nil {
+		os.Remove(filename)
+		return err
+	}
+
+	for _, warning := range manifest.Warnings {
+		logger.Warn(warning)
+	}
+
+	fmt.Printf("✅ Exported %d tasks to %s\n", manifest.Tasks, filename)
+	if include.Comments {
+		fmt.Printf("Comments: %d\n", manifest.Comments)
+	}
+	if include.Attachments {
+		fmt.Printf("Attachments: %d\n", manifest.Attachments)
 	}
+	if len(manifest.Warnings) > 0 {
+		fmt.Printf("⚠️  %d warnings, see the archive manifest\n", len(manifest.Warnings))
+	}
+	return nil
 }
 
-func runUpdateTask(cmd *cobra.Command, args []string) error {
-	taskID := args[0]
+func runImportTasks(cmd *cobra.Command, args []string) error {
 	providerName, _ := cmd.Flags().GetString("provider")
+	output := outputFormat(cmd)
 
-	// Get provider
-	var provider providers.TaskProvider
-	var err error
-
-	if providerName != "" {
-		provider, err = registry.GetProvider(providerName)
-	} else {
-		provider, err = registry.GetDefaultProvider()
+	archive, err := providers.OpenArchive(args[0])
+	if err != nil {
+		return err
 	}
+	defer archive.Close()
 
+	if providerName, err = resolveProviderName(providerName); err != nil {
+		return err
+	}
+	target, err := registry.GetProvider(providerName)
 	if err != nil {
 		return fmt.Errorf("failed to get provider: %w", err)
 	}
 
-	// Build updates
-	updates := &providers.TaskUpdate{}
+	ctx, cancel := commandContext(cmd, providerName, 10*time.Minute)
+	defer cancel()
 
-	if title := getStringFlag(cmd, "title"); title != "" {
-		updates.Title = &title
-	}
-	if description := getStringFlag(cmd, "description"); description != "" {
-		updates.Description = &description
-	}
-	if status := getStringFlag(cmd, "status"); status != "" {
-		taskStatus := providers.TaskStatus{
-			ID:   strings.ToLower(strings.ReplaceAll(status, " ", "_")),
-			Name: status,
+	results := providers.ImportArchive(ctx, archive, target, providerName, providers.ImportOptions{
+		ProjectID: getStringFlag(cmd, "project"),
+	})
+
+	failed := 0
+	for _, result := range results {
+		if result.Error != "" {
+			failed++
 		}
-		updates.Status = &taskStatus
-	}
-	if priority := getStringFlag(cmd, "priority"); priority != "" {
-		taskPriority := mapPriority(priority)
-		updates.Priority = &taskPriority
-	}
-	if assignee := getStringFlag(cmd, "assignee"); assignee != "" {
-		updates.AssigneeID = &assignee
 	}
 
-	// Handle labels
-	if labels, _ := cmd.Flags().GetStringSlice("labels"); len(labels) > 0 {
-		updates.Labels = labels
+	switch output {
+	case "json":
+		if err := outputJSON(results); err != nil {
+			return err
+		}
+	case "yaml":
+		if err := outputYAML(results); err != nil {
+			return err
+		}
+	default:
+		for _, result := range results {
+			if result.Error != "" {
+				fmt.Printf("❌ %s: %s\n", result.Source, result.Error)
+				continue
+			}
+			fmt.Printf("✅ %s → %s (%d comments, %d attachments)\n",
+				result.Source, result.Task.GetDisplayID(), result.Comments, result.Attachments)
+			for _, warning := range result.Warnings {
+				fmt.Printf("   ⚠️  %s\n", warning)
+			}
+		}
+		fmt.Printf("\nImported %d of %d tasks into %s\n", len(results)-failed, len(results), providerName)
 	}
 
-	// TODO: Handle add-labels and remove-labels
-
-	// Update task
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel()
-
-	if err := provider.UpdateTask(ctx, taskID, updates); err != nil {
-		return fmt.Errorf("failed to update task: %w", err)
+	if failed > 0 {
+		return providers.NewPartialFailureError(failed, len(results), "tasks")
 	}
-
-	fmt.Printf("✅ Task %s updated successfully\n", taskID)
 	return nil
 }
 
@@ -522,11 +2319,7 @@ func runDeleteTask(cmd *cobra.Command, args []string) error {
 	var provider providers.TaskProvider
 	var err error
 
-	if providerName != "" {
-		provider, err = registry.GetProvider(providerName)
-	} else {
-		provider, err = registry.GetDefaultProvider()
-	}
+	provider, err = selectProvider(providerName)
 
 	if err != nil {
 		return fmt.Errorf("failed to get provider: %w", err)
@@ -544,7 +2337,7 @@ func runDeleteTask(cmd *cobra.Command, args []string) error {
 	}
 
 	// Delete task
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	ctx, cancel := commandContext(cmd, providerName, defaultTaskTimeout)
 	defer cancel()
 
 	if err := provider.DeleteTask(ctx, taskID); err != nil {
@@ -565,13 +2358,18 @@ func runSearchTasks(cmd *cobra.Command, args []string) error {
 	}
 
 	if query == "" {
-		return fmt.Errorf("search query is required")
+		return providers.NewValidationError("search query is required", nil)
 	}
 
 	providerNames, _ := cmd.Flags().GetStringSlice("providers")
-	output, _ := cmd.Flags().GetString("output")
+	output := outputFormat(cmd)
 	limit, _ := cmd.Flags().GetInt("limit")
 
+	columns, err := taskColumnsFromFlags(cmd)
+	if err != nil {
+		return err
+	}
+
 	// Build search filters
 	filters := &providers.TaskFilters{
 		Query:  query,
@@ -579,65 +2377,36 @@ func runSearchTasks(cmd *cobra.Command, args []string) error {
 		Status: getStringSliceFlag(cmd, "status"),
 		Type:   getStringSliceFlag(cmd, "type"),
 		Priority: getStringSliceFlag(cmd, "priority"),
+		ProjectID: getStringFlag(cmd, "project"),
 	}
+	filters.ProjectByProvider = defaultProjects(cmd)
 
 	if assignee := getStringFlag(cmd, "assignee"); assignee != "" {
 		filters.AssigneeID = assignee
 	}
 
 	// Determine target providers
-	var targetProviders []string
-	if len(providerNames) > 0 && providerNames[0] == "all" {
-		enabledProviders := registry.ListEnabledProviders()
-		for name := range enabledProviders {
-			targetProviders = append(targetProviders, name)
-		}
-	} else if len(providerNames) > 0 {
-		targetProviders = providerNames
-	} else {
-		// Use default provider
-		if defaultProvider, err := registry.GetDefaultProvider(); err == nil {
-			info := defaultProvider.GetProviderInfo()
-			targetProviders = []string{info.Name}
-		}
+	targetProviders, err := resolveTargetProviders("", providerNames)
+	if err != nil {
+		return err
+	}
+	if output == "jsonl" {
+		return streamTasks(cmd, targetProviders, filters)
 	}
 
 	// Search across providers
-	var allTasks []*providers.UniversalTask
-	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
-	defer cancel()
-
-	for _, providerName := range targetProviders {
-		provider, err := registry.GetProvider(providerName)
-		if err != nil {
-			logger.Warnf("Failed to get provider %s: %v", providerName, err)
-			continue
-		}
-
-		tasks, err := provider.ListTasks(ctx, filters)
-		if err != nil {
-			logger.Warnf("Failed to search tasks in %s: %v", providerName, err)
-			continue
-		}
-
-		for _, task := range tasks {
-			task.ProviderName = providerName
-		}
-
-		allTasks = append(allTasks, tasks...)
+	allTasks, err := collectTasks(cmd, targetProviders, filters)
+	if err != nil && !isPartialFailure(err) {
+		return err
 	}
 
 	fmt.Printf("Found %d tasks matching '%s'\n\n", len(allTasks), query)
 
 	// Output results
-	switch output {
-	case "json":
-		return outputJSON(allTasks)
-	case "yaml":
-		return outputYAML(allTasks)
-	default:
-		return outputTaskTable(allTasks)
+	if outputErr := outputTasks(output, nil, columns, allTasks); outputErr != nil {
+		return outputErr
 	}
+	return err
 }
 
 func runSyncTasks(cmd *cobra.Command, args []string) error {
@@ -672,11 +2441,34 @@ func runSyncTasks(cmd *cobra.Command, args []string) error {
 }
 
 // Helper functions
+// outputFormat returns the output format of cmd, see providerCmd.OutputFormat
+func outputFormat(cmd *cobra.Command) string {
+	return providerCmd.OutputFormat(cmd)
+}
+
 func getStringFlag(cmd *cobra.Command, name string) string {
 	value, _ := cmd.Flags().GetString(name)
 	return value
 }
 
+// dueDateFlag parses a due date flag in the time zone of the configuration;
+// an empty flag gives nil
+func dueDateFlag(cmd *cobra.Command, name string) (*time.Time, error) {
+	value := getStringFlag(cmd, name)
+	if value == "" {
+		return nil, nil
+	}
+	loc, err := registry.Location()
+	if err != nil {
+		return nil, err
+	}
+	due, err := providers.ParseDueDate(value, time.Now().In(loc))
+	if err != nil {
+		return nil, fmt.Errorf("--%s: %w", name, err)
+	}
+	return &due, nil
+}
+
 func getIntFlag(cmd *cobra.Command, name string) int {
 	value, _ := cmd.Flags().GetInt(name)
 	return value
@@ -690,23 +2482,48 @@ func getStringSliceFlag(cmd *cobra.Command, name string) []string {
 	return value
 }
 
-func mapPriority(priority string) providers.TaskPriority {
-	switch strings.ToLower(priority) {
-	case "lowest":
-		return providers.TaskPriorityLowest
-	case "low":
-		return providers.TaskPriorityLow
-	case "medium":
-		return providers.TaskPriorityMedium
-	case "high":
-		return providers.TaskPriorityHigh
-	case "highest":
-		return providers.TaskPriorityHighest
-	case "critical":
-		return providers.TaskPriorityCritical
-	default:
-		return providers.TaskPriorityMedium
+// mapPriority returns the priority a --priority value refers to, medium if it
+// is empty. Besides the universal levels the value may be a native priority of
+// the provider, e.g. Major for YouTrack, see providers.ResolvePriority.
+func mapPriority(providerName, priority string) (providers.TaskPriority, error) {
+	if strings.TrimSpace(priority) == "" {
+		return providers.TaskPriorityMedium, nil
+	}
+
+	mapping := providers.PriorityMappingFor("", nil)
+	if registry != nil {
+		if providerName == "" {
+			providerName = registry.DefaultProviderName()
+		}
+		mapping = registry.PriorityMapping(providerName)
+	}
+	return providers.ResolvePriority(priority, mapping)
+}
+
+// typedStatus is a status as the user typed it, for when the provider's
+// workflow can't be checked
+func typedStatus(status string) providers.TaskStatus {
+	return providers.TaskStatus{
+		ID:   strings.ToLower(strings.ReplaceAll(strings.TrimSpace(status), " ", "_")),
+		Name: strings.TrimSpace(status),
+	}
+}
+
+// resolveStatus returns the status of the project's workflow a --status value
+// refers to, see providers.ResolveStatus. Providers that can't list their
+// statuses get the status as typed.
+func resolveStatus(ctx context.Context, provider providers.TaskProvider, projectID, status string) (providers.TaskStatus, error) {
+	statuses, err := provider.GetAvailableStatuses(ctx, projectID)
+	if err != nil {
+		if providers.IsUnsupportedError(err) {
+			return typedStatus(status), nil
+		}
+		return providers.TaskStatus{}, fmt.Errorf("failed to get workflow statuses: %w", err)
+	}
+	if len(statuses) == 0 {
+		return typedStatus(status), nil
 	}
+	return providers.ResolveStatus(status, statuses)
 }
 
 func outputJSON(data interface{}) error {
@@ -721,42 +2538,255 @@ func outputYAML(data interface{}) error {
 	return encoder.Encode(data)
 }
 
-func outputTaskTable(tasks []*providers.UniversalTask) error {
-	fmt.Printf("%-15s %-12s %-40s %-12s %-10s %-15s\n", "ID", "PROVIDER", "TITLE", "STATUS", "PRIORITY", "ASSIGNEE")
-	fmt.Printf("%-15s %-12s %-40s %-12s %-10s %-15s\n", "--", "--------", "-----", "------", "--------", "--------")
+// taskFormatterFromFlags parses --format, returning nil when it isn't set
+func taskFormatterFromFlags(cmd *cobra.Command) (*providers.TaskFormatter, error) {
+	text, _ := cmd.Flags().GetString("format")
+	if text == "" {
+		return nil, nil
+	}
 
-	for _, task := range tasks {
-		title := task.Title
+	formatter, err := providers.NewTaskFormatter(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --format: %w", err)
+	}
+	return formatter, nil
+}
+
+// outputTasks prints tasks with the template formatter if given, otherwise in the output format
+func outputTasks(output string, formatter *providers.TaskFormatter, columns []providers.TaskColumn, tasks []*providers.UniversalTask) error {
+	if formatter != nil {
+		return outputTaskTemplate(formatter, tasks)
+	}
+
+	switch output {
+	case "json":
+		return outputJSON(tasks)
+	case "yaml":
+		return outputYAML(tasks)
+	case "summary":
+		fmt.Print(providers.FormatTaskSummary(tasks))
+		return nil
+	default:
+		return outputTaskTable(columns, tasks)
+	}
+}
+
+func outputTaskTemplate(formatter *providers.TaskFormatter, tasks []*providers.UniversalTask) error {
+	output, err := formatter.FormatAll(tasks)
+	if err != nil {
+		return err
+	}
+	fmt.Print(output)
+	return nil
+}
+
+var columnsFlagUsage = "Table columns in order: " + strings.Join(providers.TaskColumnNames(), ", ") +
+	", or custom:<field> for a custom field (defaults to " + strings.Join(providers.DefaultTaskColumns, ",") + ")"
+
+// taskColumnsFromFlags parses --columns, falling back to the default columns
+func taskColumnsFromFlags(cmd *cobra.Command) ([]providers.TaskColumn, error) {
+	names, _ := cmd.Flags().GetStringSlice("columns")
+	columns, err := providers.ParseTaskColumns(names)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --columns: %w", err)
+	}
+	return columns, nil
+}
+
+// outputTaskTable prints tasks as a table of columns, each as wide as its
+// longest value up to the column's limit
+func outputTaskTable(columns []providers.TaskColumn, tasks []*providers.UniversalTask) error {
+	rows := make([][]string, len(tasks))
+	widths := make([]int, len(columns))
+	for i, column := range columns {
+		widths[i] = utf8.RuneCountInString(column.Header)
+	}
+	for row, task := range tasks {
+		rows[row] = make([]string, len(columns))
+		for i, column := range columns {
+			value := truncateCell(column.Value(task), column.MaxWidth)
+			rows[row][i] = value
+			if width := utf8.RuneCountInString(value); width > widths[i] {
+				widths[i] = width
+			}
+		}
+	}
+
+	headers := make([]string, len(columns))
+	rules := make([]string, len(columns))
+	for i, column := range columns {
+		headers[i] = padCell(column.Header, widths[i])
+		rules[i] = padCell(strings.Repeat("-", utf8.RuneCountInString(column.Header)), widths[i])
+	}
+	fmt.Println(strings.TrimRight(strings.Join(headers, " "), " "))
+	fmt.Println(strings.TrimRight(strings.Join(rules, " "), " "))
+
+	for row, task := range tasks {
+		cells := make([]string, len(columns))
+		for i, column := range columns {
+			// Status and priority are colored after padding, so escape codes don't break alignment
+			switch {
+			case column.Name == "status":
+				cells[i] = colorStatus(task.Status, widths[i])
+			case column.Name == "priority":
+				cells[i] = colorPriority(task.Priority, widths[i])
+			case i == len(columns)-1:
+				cells[i] = rows[row][i]
+			default:
+				cells[i] = padCell(rows[row][i], widths[i])
+			}
+		}
+		fmt.Println(strings.TrimRight(strings.Join(cells, " "), " "))
+	}
+
+	return nil
+}
+
+// truncateCell shortens value to max characters, ending it with "..."
+func truncateCell(value string, max int) string {
+	if max <= 0 || utf8.RuneCountInString(value) <= max {
+		return value
+	}
+	runes := []rune(value)
+	return string(runes[:max-3]) + "..."
+}
+
+// padCell pads value with spaces to width characters
+func padCell(value string, width int) string {
+	if pad := width - utf8.RuneCountInString(value); pad > 0 {
+		return value + strings.Repeat(" ", pad)
+	}
+	return value
+}
+
+func outputTaskStats(stats *providers.TaskStats) error {
+	fmt.Printf("Task Summary (%d total, %d completed)\n", stats.Total, stats.Completed)
+	fmt.Printf("Overdue: %d   Blocked: %d\n", stats.Overdue, stats.Blocked)
+	if stats.StoryPoints > 0 {
+		fmt.Printf("Story points: %g (%g completed)\n", stats.StoryPoints, stats.CompletedStoryPoints)
+	}
+
+	sections := []struct {
+		title  string
+		counts map[string]int
+	}{
+		{"STATUS", stats.ByStatus},
+		{"PRIORITY", stats.ByPriority},
+		{"TYPE", stats.ByType},
+		{"ASSIGNEE", stats.ByAssignee},
+		{"PROVIDER", stats.ByProvider},
+	}
+
+	for _, section := range sections {
+		if len(section.counts) == 0 {
+			continue
+		}
+		fmt.Printf("\n%-25s %s\n", section.title, "COUNT")
+		for _, entry := range providers.SortedCounts(section.counts) {
+			key := entry.Key
+			if key == "" {
+				key = "(none)"
+			}
+			fmt.Printf("%-25s %d\n", key, entry.Count)
+		}
+	}
+
+	if len(stats.OverdueTasks) > 0 {
+		fmt.Printf("\nOverdue: %s\n", strings.Join(stats.OverdueTasks, ", "))
+	}
+	if len(stats.BlockedTasks) > 0 {
+		fmt.Printf("Blocked: %s\n", strings.Join(stats.BlockedTasks, ", "))
+	}
+
+	return nil
+}
+
+func outputBalancePlan(plan *providers.BalancePlan) {
+	before := make(map[string]providers.AssigneeLoad)
+	for _, load := range plan.Before {
+		before[load.Assignee] = load
+	}
+
+	fmt.Printf("%-20s %-16s %-16s\n", "ASSIGNEE", "OPEN TASKS", "REMAINING HOURS")
+	fmt.Printf("%-20s %-16s %-16s\n", "--------", "----------", "---------------")
+	for _, load := range plan.After {
+		was := before[load.Assignee]
+		fmt.Printf("%-20s %-16s %-16s\n",
+			load.Assignee,
+			fmt.Sprintf("%d → %d", was.OpenTasks, load.OpenTasks),
+			fmt.Sprintf("%.1f → %.1f", was.RemainingHours, load.RemainingHours),
+		)
+	}
+
+	if len(plan.Reassignments) == 0 {
+		fmt.Println("\nWorkload is already balanced, no reassignments suggested")
+		return
+	}
+
+	fmt.Printf("\nSuggested reassignments (%d):\n", len(plan.Reassignments))
+	for _, move := range plan.Reassignments {
+		title := move.Title
 		if len(title) > 37 {
 			title = title[:37] + "..."
 		}
+		fmt.Printf("  %-15s %-40s %s → %s (%.1fh)\n", move.TaskID, title, move.From, move.To, move.Hours)
+	}
+}
+
+func outputExecutionPlan(plan *providers.ExecutionPlan) error {
+	if len(plan.Groups) == 0 {
+		fmt.Println("No open tasks to plan")
+		return nil
+	}
+
+	fmt.Printf("%-6s %-15s %-40s %-12s %-10s\n", "STEP", "ID", "TITLE", "STATUS", "PRIORITY")
+	fmt.Printf("%-6s %-15s %-40s %-12s %-10s\n", "----", "--", "-----", "------", "--------")
+
+	for _, group := range plan.Groups {
+		for _, task := range group.Tasks {
+			title := task.Title
+			if len(title) > 37 {
+				title = title[:37] + "..."
+			}
+
+			fmt.Printf("%-6d %-15s %-40s %-12s %-10s\n",
+				group.Step,
+				task.GetDisplayID(),
+				title,
+				task.Status.Name,
+				string(task.Priority),
+			)
+		}
+	}
 
-		assignee := task.AssigneeID
-		if len(assignee) > 12 {
-			assignee = assignee[:12] + "..."
+	fmt.Println("\nTasks with the same step can be worked on in parallel.")
+
+	if len(plan.ExternalBlockers) > 0 {
+		ids := make([]string, 0, len(plan.ExternalBlockers))
+		for id := range plan.ExternalBlockers {
+			ids = append(ids, id)
 		}
+		sort.Strings(ids)
 
-		fmt.Printf("%-15s %-12s %-40s %-12s %-10s %-15s\n",
-			task.GetDisplayID(),
-			task.ProviderName,
-			title,
-			task.Status.Name,
-			string(task.Priority),
-			assignee,
-		)
+		fmt.Println("\nBlocked by tasks outside this plan:")
+		for _, id := range ids {
+			fmt.Printf("  %s ← %s\n", id, strings.Join(plan.ExternalBlockers[id], ", "))
+		}
 	}
 
 	return nil
 }
 
-func outputTaskDetails(task *providers.UniversalTask) error {
+// outputTaskDetails prints a task. Unless raw is set, the description is
+// converted from the provider's markup and rendered for the terminal.
+func outputTaskDetails(task *providers.UniversalTask, raw bool) error {
 	fmt.Printf("Task Details\n")
 	fmt.Printf("============\n\n")
 	fmt.Printf("ID:           %s\n", task.GetDisplayID())
 	fmt.Printf("Title:        %s\n", task.Title)
 	fmt.Printf("Provider:     %s\n", task.ProviderName)
-	fmt.Printf("Status:       %s\n", task.Status.Name)
-	fmt.Printf("Priority:     %s\n", string(task.Priority))
+	fmt.Printf("Status:       %s\n", colorStatus(task.Status, 0))
+	fmt.Printf("Priority:     %s\n", colorPriority(task.Priority, 0))
 	fmt.Printf("Type:         %s\n", string(task.Type))
 	
 	if task.AssigneeID != "" {
@@ -770,12 +2800,32 @@ func outputTaskDetails(task *providers.UniversalTask) error {
 	if len(task.Labels) > 0 {
 		fmt.Printf("Labels:       %s\n", strings.Join(task.Labels, ", "))
 	}
+
+	if task.StoryPoints != nil {
+		fmt.Printf("Story points: %g\n", *task.StoryPoints)
+	}
+
+	if task.EstimatedTime != nil {
+		fmt.Printf("Estimate:     %s\n", *task.EstimatedTime)
+	}
+
+	if task.DueDate != nil {
+		fmt.Printf("Due:          %s\n", task.DueDate.Format("2006-01-02"))
+	}
 	
 	fmt.Printf("Created:      %s\n", task.CreatedAt.Format("2006-01-02 15:04:05"))
 	fmt.Printf("Updated:      %s\n", task.UpdatedAt.Format("2006-01-02 15:04:05"))
 	
 	if task.Description != "" {
-		fmt.Printf("\nDescription:\n%s\n", task.Description)
+		description := task.Description
+		if !raw {
+			var providerType providers.ProviderType
+			if task.ProviderConfig != nil {
+				providerType = task.ProviderConfig.Type
+			}
+			description = providers.RenderMarkdown(providers.MarkupToMarkdown(description, providerType))
+		}
+		fmt.Printf("\nDescription:\n%s\n", description)
 	}
 
 	return nil
@@ -788,36 +2838,35 @@ func runBulkCreateTasks(cmd *cobra.Command, args []string) error {
 	autoRoute, _ := cmd.Flags().GetBool("auto-route")
 	dryRun, _ := cmd.Flags().GetBool("dry-run")
 	providerName, _ := cmd.Flags().GetString("provider")
+	mapping, _ := cmd.Flags().GetStringToString("mapping")
+	isCSV := strings.EqualFold(filepath.Ext(fileName), ".csv")
+
+	if len(mapping) > 0 && !isCSV {
+		return providers.NewValidationError("--mapping only applies to CSV files", nil)
+	}
 	
 	// Read and parse file
-	data, err := os.ReadFile(fileName)
+	tasks, err := readBulkTaskFile(fileName, mapping)
 	if err != nil {
-		return fmt.Errorf("failed to read file %s: %w", fileName, err)
+		return err
 	}
 	
-	var tasks []*providers.UniversalTask
-	if strings.HasSuffix(fileName, ".yaml") || strings.HasSuffix(fileName, ".yml") {
-		err = yaml.Unmarshal(data, &tasks)
-	} else {
-		err = json.Unmarshal(data, &tasks)
-	}
+	fmt.Printf("Found %d tasks to create\n", len(tasks))
+	
+	steps, err := planBulkTasks(tasks)
 	if err != nil {
-		return fmt.Errorf("failed to parse file %s: %w", fileName, err)
+		return err
 	}
 	
-	fmt.Printf("Found %d tasks to create\n", len(tasks))
-	
 	if dryRun {
 		fmt.Println("\nDry run - would create the following tasks:")
-		for i, task := range tasks {
-			fmt.Printf("%d. %s (Project: %s, Type: %s)\n", i+1, task.Title, task.ProjectID, task.Type)
-		}
+		outputBulkPlan(steps)
 		return nil
 	}
 	
 	// Determine provider
 	if !autoRoute && providerName == "" {
-		return fmt.Errorf("either --provider or --auto-route must be specified")
+		return providers.NewValidationError("either --provider or --auto-route must be specified", nil)
 	}
 	
 	var provider providers.TaskProvider
@@ -831,29 +2880,126 @@ func runBulkCreateTasks(cmd *cobra.Command, args []string) error {
 		}
 		provider = p
 	}
-	
-	// Create tasks in batches
-	ctx := context.Background()
-	createdTasks, err := provider.BulkCreateTasks(ctx, tasks)
-	if err != nil {
-		return fmt.Errorf("failed to create tasks: %w", err)
+	
+	// Create tasks in batches, referenced tasks first
+	ctx, cancel := commandContext(cmd, providerName, 0)
+	defer cancel()
+	results, err := providers.CreateWithRelations(ctx, provider, tasks)
+	
+	created := 0
+	for _, result := range results {
+		if result.Task != nil {
+			created++
+		}
+	}
+	fmt.Printf("Created %d of %d tasks\n", created, len(tasks))
+	outputBulkResults(results)
+	
+	return err
+}
+
+// readBulkTaskFile reads the tasks of a JSON, YAML or CSV bulk-create file;
+// mapping maps CSV columns to task fields
+func readBulkTaskFile(fileName string, mapping map[string]string) ([]*providers.UniversalTask, error) {
+	data, err := os.ReadFile(fileName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file %s: %w", fileName, err)
+	}
+
+	var tasks []*providers.UniversalTask
+	if strings.EqualFold(filepath.Ext(fileName), ".csv") {
+		tasks, err = providers.ParseTasksCSV(bytes.NewReader(data), mapping)
+	} else {
+		isYAML := strings.HasSuffix(fileName, ".yaml") || strings.HasSuffix(fileName, ".yml")
+		tasks, err = providers.ParseBulkTaskFile(data, isYAML)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse file %s: %w", fileName, err)
+	}
+	return tasks, nil
+}
+
+// planBulkTasks orders tasks that reference each other for creation, see
+// providers.PlanBulkCreate
+func planBulkTasks(tasks []*providers.UniversalTask) ([][]*providers.UniversalTask, error) {
+	steps, err := providers.PlanBulkCreate(tasks)
+	if err != nil {
+		var cycle *providers.DependencyCycleError
+		if errors.As(err, &cycle) {
+			return nil, providers.NewValidationError(fmt.Sprintf("tasks reference each other in a cycle: %s", strings.Join(cycle.Cycle, " → ")), nil)
+		}
+		return nil, err
+	}
+	return steps, nil
+}
+
+// outputBulkPlan prints the steps in which a dry run would create tasks
+func outputBulkPlan(steps [][]*providers.UniversalTask) {
+	i := 0
+	for step, stepTasks := range steps {
+		if len(steps) > 1 {
+			fmt.Printf("Step %d:\n", step+1)
+		}
+		for _, task := range stepTasks {
+			i++
+			fmt.Printf("%d. %s (Project: %s, Type: %s)%s\n", i, task.Title, task.ProjectID, task.Type, bulkTaskRelations(task))
+		}
+	}
+}
+
+// outputBulkResults prints the created tasks with their links and errors
+func outputBulkResults(results []*providers.BulkCreateResult) {
+	for _, result := range results {
+		ref := ""
+		if result.Ref != "" {
+			ref = fmt.Sprintf(" (%s)", result.Ref)
+		}
+		if result.Task != nil {
+			fmt.Printf("- %s: %s%s\n", result.Task.GetDisplayID(), result.Task.Title, ref)
+			for _, link := range result.Links {
+				fmt.Printf("    %s\n", link)
+			}
+		} else {
+			fmt.Printf("- %s%s: not created\n", result.Title, ref)
+		}
+		if result.Error != "" {
+			fmt.Printf("    error: %s\n", result.Error)
+		}
+	}
+}
+
+// bulkTaskRelations describes the parent and blockers of a task in a dry run
+func bulkTaskRelations(task *providers.UniversalTask) string {
+	var relations []string
+	if providers.IsTempID(task.ID) {
+		relations = append(relations, "id "+task.ID)
+	}
+	if task.ParentID != "" {
+		relations = append(relations, "parent "+task.ParentID)
+	}
+	if task.EpicID != "" && task.EpicID != task.ParentID {
+		relations = append(relations, "epic "+task.EpicID)
+	}
+	if len(task.BlockedBy) > 0 {
+		relations = append(relations, "blocked by "+strings.Join(task.BlockedBy, ", "))
+	}
+	if len(task.Blocks) > 0 {
+		relations = append(relations, "blocks "+strings.Join(task.Blocks, ", "))
 	}
-	
-	fmt.Printf("Successfully created %d tasks\n", len(createdTasks))
-	for _, task := range createdTasks {
-		fmt.Printf("- %s: %s\n", task.GetDisplayID(), task.Title)
+	if len(relations) == 0 {
+		return ""
 	}
-	
-	return nil
+	return " [" + strings.Join(relations, "; ") + "]"
 }
 
 func runBulkUpdateTasks(cmd *cobra.Command, args []string) error {
 	fileName, _ := cmd.Flags().GetString("file")
 	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	force, _ := cmd.Flags().GetBool("force")
 	providerName, _ := cmd.Flags().GetString("provider")
 	
 	if providerName == "" {
-		return fmt.Errorf("--provider must be specified")
+		return providers.NewValidationError("--provider must be specified", nil)
 	}
 	
 	// Read and parse file
@@ -874,9 +3020,26 @@ func runBulkUpdateTasks(cmd *cobra.Command, args []string) error {
 	
 	fmt.Printf("Found updates for %d tasks\n", len(updates))
 	
+	// Get provider
+	provider, err := registry.GetProvider(providerName)
+	if err != nil {
+		return fmt.Errorf("failed to get provider %s: %w", providerName, err)
+	}
+	
+	ctx, cancel := commandContext(cmd, providerName, 0)
+	defer cancel()
+	taskIDs := make([]string, 0, len(updates))
+	for taskID := range updates {
+		taskIDs = append(taskIDs, taskID)
+	}
+	sort.Strings(taskIDs)
+	before := fetchBulkTasks(ctx, provider, providerName, taskIDs)
+	printBulkSummary(before)
+	
 	if dryRun {
 		fmt.Println("\nDry run - would update the following tasks:")
-		for taskID, update := range updates {
+		for _, taskID := range taskIDs {
+			update := updates[taskID]
 			fmt.Printf("- %s: ", taskID)
 			parts := []string{}
 			if update.Title != nil {
@@ -893,15 +3056,24 @@ func runBulkUpdateTasks(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 	
-	// Get provider
-	provider, err := registry.GetProvider(providerName)
-	if err != nil {
-		return fmt.Errorf("failed to get provider %s: %w", providerName, err)
+	if !force && !confirmBulk(fmt.Sprintf("Are you sure you want to update %d tasks? (y/N): ", len(updates))) {
+		fmt.Println("Update cancelled")
+		return nil
 	}
 	
 	// Update tasks in batch
-	ctx := context.Background()
 	err = provider.BulkUpdateTasks(ctx, updates)
+	
+	// Some updates may have been applied even if the batch failed, and
+	// reverting an unapplied one changes nothing
+	var items []*providers.UndoItem
+	for _, taskID := range taskIDs {
+		if task, ok := before[taskID]; ok {
+			items = append(items, &providers.UndoItem{TaskID: taskID, Before: task, Update: updates[taskID]})
+		}
+	}
+	recordBulkUndo(providers.UndoUpdate, providerName, items)
+	
 	if err != nil {
 		return fmt.Errorf("failed to update tasks: %w", err)
 	}
@@ -920,10 +3092,19 @@ func runBulkDeleteTasks(cmd *cobra.Command, args []string) error {
 	providerName, _ := cmd.Flags().GetString("provider")
 	
 	if providerName == "" {
-		return fmt.Errorf("--provider must be specified")
+		return providers.NewValidationError("--provider must be specified", nil)
+	}
+	
+	// Get provider
+	provider, err := registry.GetProvider(providerName)
+	if err != nil {
+		return fmt.Errorf("failed to get provider %s: %w", providerName, err)
 	}
 	
+	ctx, cancel := commandContext(cmd, providerName, 0)
+	defer cancel()
 	var taskIDs []string
+	var before map[string]*providers.UniversalTask
 	
 	// Collect task IDs from different sources
 	if fileName != "" {
@@ -938,13 +3119,7 @@ func runBulkDeleteTasks(cmd *cobra.Command, args []string) error {
 			taskIDs[i] = strings.TrimSpace(id)
 		}
 	} else if query != "" {
-		// Get provider and search for tasks
-		provider, err := registry.GetProvider(providerName)
-		if err != nil {
-			return fmt.Errorf("failed to get provider %s: %w", providerName, err)
-		}
-		
-		ctx := context.Background()
+		// Search for tasks
 		filters := &providers.TaskFilters{
 			Query: query,
 		}
@@ -953,11 +3128,16 @@ func runBulkDeleteTasks(cmd *cobra.Command, args []string) error {
 			return fmt.Errorf("failed to search tasks: %w", err)
 		}
 		
+		before = make(map[string]*providers.UniversalTask, len(tasks))
 		for _, task := range tasks {
+			if task.ProviderName == "" {
+				task.ProviderName = providerName
+			}
 			taskIDs = append(taskIDs, task.GetDisplayID())
+			before[task.GetDisplayID()] = task
 		}
 	} else {
-		return fmt.Errorf("one of --file, --ids, or --query must be specified")
+		return providers.NewValidationError("one of --file, --ids, or --query must be specified", nil)
 	}
 	
 	if len(taskIDs) == 0 {
@@ -966,6 +3146,10 @@ func runBulkDeleteTasks(cmd *cobra.Command, args []string) error {
 	}
 	
 	fmt.Printf("Found %d tasks to delete\n", len(taskIDs))
+	if before == nil {
+		before = fetchBulkTasks(ctx, provider, providerName, taskIDs)
+	}
+	printBulkSummary(before)
 	
 	if dryRun {
 		fmt.Println("\nDry run - would delete the following tasks:")
@@ -976,25 +3160,14 @@ func runBulkDeleteTasks(cmd *cobra.Command, args []string) error {
 	}
 	
 	// Confirmation unless force is used
-	if !force {
-		fmt.Printf("Are you sure you want to delete %d tasks? (y/N): ", len(taskIDs))
-		var response string
-		fmt.Scanln(&response)
-		if strings.ToLower(response) != "y" && strings.ToLower(response) != "yes" {
-			fmt.Println("Deletion cancelled")
-			return nil
-		}
-	}
-	
-	// Get provider
-	provider, err := registry.GetProvider(providerName)
-	if err != nil {
-		return fmt.Errorf("failed to get provider %s: %w", providerName, err)
+	if !force && !confirmBulk(fmt.Sprintf("Are you sure you want to delete %d tasks? (y/N): ", len(taskIDs))) {
+		fmt.Println("Deletion cancelled")
+		return nil
 	}
 	
 	// Delete tasks
-	ctx := context.Background()
 	successCount := 0
+	var items []*providers.UndoItem
 	for _, taskID := range taskIDs {
 		err := provider.DeleteTask(ctx, taskID)
 		if err != nil {
@@ -1002,10 +3175,427 @@ func runBulkDeleteTasks(cmd *cobra.Command, args []string) error {
 		} else {
 			fmt.Printf("Deleted task %s\n", taskID)
 			successCount++
+			if task, ok := before[taskID]; ok {
+				items = append(items, &providers.UndoItem{TaskID: taskID, Before: task})
+			}
 		}
 	}
 	
 	fmt.Printf("Successfully deleted %d out of %d tasks\n", successCount, len(taskIDs))
+	recordBulkUndo(providers.UndoDelete, providerName, items)
 	
+	if failed := len(taskIDs) - successCount; failed > 0 {
+		return providers.NewPartialFailureError(failed, len(taskIDs), "deletions")
+	}
+	return nil
+}
+
+// fetchBulkTasks reads the current state of the tasks of a bulk operation by
+// ID. Tasks that can't be read are reported and left out, so their changes
+// can't be undone.
+func fetchBulkTasks(ctx context.Context, provider providers.TaskProvider, providerName string, taskIDs []string) map[string]*providers.UniversalTask {
+	tasks := make(map[string]*providers.UniversalTask, len(taskIDs))
+	for _, taskID := range taskIDs {
+		task, err := provider.GetTask(ctx, taskID)
+		if err != nil {
+			fmt.Printf("⚠️  Failed to read task %s, its change can't be undone: %v\n", taskID, err)
+			continue
+		}
+		if task.ProviderName == "" {
+			task.ProviderName = providerName
+		}
+		tasks[taskID] = task
+	}
+	return tasks
+}
+
+// printBulkSummary prints the tasks a bulk operation affects by provider and status
+func printBulkSummary(tasks map[string]*providers.UniversalTask) {
+	list := make([]*providers.UniversalTask, 0, len(tasks))
+	for _, task := range tasks {
+		list = append(list, task)
+	}
+	for _, line := range providers.SummarizeBulkTasks(list) {
+		fmt.Printf("  %s\n", line)
+	}
+}
+
+// confirmBulk asks the user to confirm a bulk operation
+func confirmBulk(prompt string) bool {
+	fmt.Print(prompt)
+	var response string
+	fmt.Scanln(&response)
+	response = strings.ToLower(response)
+	return response == "y" || response == "yes"
+}
+
+// recordBulkUndo stores the prior state of the tasks a bulk operation changed
+// and prints the token that undoes it. The operation has already happened,
+// so a failure to record it is only reported.
+func recordBulkUndo(operation providers.UndoOperation, providerName string, items []*providers.UndoItem) {
+	if len(items) == 0 {
+		return
+	}
+	entry, err := undoLog().Record(operation, providerName, items)
+	if err != nil {
+		fmt.Printf("⚠️  Failed to save undo information: %v\n", err)
+		return
+	}
+	fmt.Printf("↩️  Undo token: %s (valid until %s)\n", entry.Token, entry.ExpiresAt.Format("2006-01-02 15:04"))
+	fmt.Printf("   Run 'ricochet tasks undo %s' to revert\n", entry.Token)
+}
+
+// undoLog returns the undo log of the active profile
+func undoLog() *providers.UndoLog {
+	return providers.NewUndoLog(config.ProfilePath(providers.UndoLogFile))
+}
+
+// taskWatchers opens the watcher store of the active profile
+func taskWatchers() (*providers.TaskWatchRegistry, error) {
+	return providers.NewTaskWatchRegistry(config.ProfilePath(providers.WatchersFile), logger)
+}
+
+// taskTemplates opens the template store of the active profile
+func taskTemplates() (*providers.TaskTemplateStore, error) {
+	return providers.NewTaskTemplateStore(config.ProfilePath(providers.TaskTemplatesFile))
+}
+
+func runUndo(cmd *cobra.Command, args []string) error {
+	list, _ := cmd.Flags().GetBool("list")
+	format := outputFormat(cmd)
+	log := undoLog()
+
+	if list || len(args) == 0 {
+		entries, err := log.List()
+		if err != nil {
+			return err
+		}
+		switch format {
+		case "json":
+			return outputJSON(entries)
+		case "yaml":
+			return outputYAML(entries)
+		}
+		if len(entries) == 0 {
+			fmt.Println("No bulk operations to undo")
+			return nil
+		}
+		fmt.Printf("%-10s %-8s %-20s %-6s %-17s %s\n", "TOKEN", "ACTION", "PROVIDER", "TASKS", "WHEN", "EXPIRES")
+		for _, entry := range entries {
+			fmt.Printf("%-10s %-8s %-20s %-6d %-17s %s\n", entry.Token, entry.Operation, entry.Provider, len(entry.Items),
+				entry.CreatedAt.Local().Format("2006-01-02 15:04"), entry.ExpiresAt.Local().Format("2006-01-02 15:04"))
+		}
+		return nil
+	}
+
+	entry, err := log.Get(args[0])
+	if err != nil {
+		return err
+	}
+	provider, err := registry.GetProvider(entry.Provider)
+	if err != nil {
+		return fmt.Errorf("failed to get provider %s: %w", entry.Provider, err)
+	}
+
+	ctx, cancel := commandContext(cmd, entry.Provider, 0)
+	defer cancel()
+
+	results, err := log.Undo(ctx, entry, provider)
+	if err != nil {
+		return err
+	}
+
+	failed := 0
+	for _, result := range results {
+		if result.Error != "" {
+			failed++
+		}
+	}
+	switch format {
+	case "json":
+		if err := outputJSON(results); err != nil {
+			return err
+		}
+	case "yaml":
+		if err := outputYAML(results); err != nil {
+			return err
+		}
+	default:
+		for _, result := range results {
+			switch {
+			case result.Error != "":
+				fmt.Printf("❌ %s: %s\n", result.TaskID, result.Error)
+			case result.NewTaskID != "":
+				fmt.Printf("✅ %s recreated as %s\n", result.TaskID, result.NewTaskID)
+			default:
+				fmt.Printf("✅ %s reverted\n", result.TaskID)
+			}
+		}
+		if failed > 0 {
+			fmt.Printf("Run 'ricochet tasks undo %s' again to retry the failed tasks\n", entry.Token)
+		}
+	}
+
+	if failed > 0 {
+		return providers.NewPartialFailureError(failed, len(results), "undos")
+	}
+	return nil
+}
+
+func runWatchTask(cmd *cobra.Command, args []string) error {
+	providerName, _ := cmd.Flags().GetString("provider")
+	list, _ := cmd.Flags().GetBool("list")
+
+	userID, err := resolveWatcherID(cmd)
+	if err != nil {
+		return err
+	}
+
+	watchers, err := taskWatchers()
+	if err != nil {
+		return fmt.Errorf("failed to load watchers: %w", err)
+	}
+
+	if list {
+		watched := watchers.WatchedTasks(userID)
+		if len(watched) == 0 {
+			fmt.Printf("%s is not watching any tasks\n", userID)
+			return nil
+		}
+
+		fmt.Printf("👀 Tasks watched by %s:\n", userID)
+		for _, watcher := range watched {
+			fmt.Printf("  %-20s since %s\n", watcher.TaskID, watcher.WatchedAt.Format("2006-01-02 15:04"))
+		}
+		return nil
+	}
+
+	if len(args) == 0 {
+		return providers.NewValidationError("task ID is required", nil)
+	}
+	taskID := args[0]
+
+	// Get provider
+	var provider providers.TaskProvider
+	provider, err = selectProvider(providerName)
+
+	if err != nil {
+		return fmt.Errorf("failed to get provider: %w", err)
+	}
+
+	// Make sure the task exists before watching it
+	ctx, cancel := commandContext(cmd, providerName, defaultTaskTimeout)
+	defer cancel()
+
+	task, err := provider.GetTask(ctx, taskID)
+	if err != nil {
+		return fmt.Errorf("failed to get task: %w", err)
+	}
+
+	if err := watchers.WatchTask(ctx, taskID, userID); err != nil {
+		return fmt.Errorf("failed to watch task: %w", err)
+	}
+
+	fmt.Printf("👀 %s is now watching %s: %s\n", userID, task.GetDisplayID(), task.Title)
+	return nil
+}
+
+func runUnwatchTask(cmd *cobra.Command, args []string) error {
+	taskID := args[0]
+
+	userID, err := resolveWatcherID(cmd)
+	if err != nil {
+		return err
+	}
+
+	watchers, err := taskWatchers()
+	if err != nil {
+		return fmt.Errorf("failed to load watchers: %w", err)
+	}
+
+	if err := watchers.UnwatchTask(context.Background(), taskID, userID); err != nil {
+		return fmt.Errorf("failed to unwatch task: %w", err)
+	}
+
+	fmt.Printf("✅ %s stopped watching %s\n", userID, taskID)
+	return nil
+}
+
+// resolveWatcherID returns the watcher user ID from --user, $RICOCHET_USER or the OS user
+func resolveWatcherID(cmd *cobra.Command) (string, error) {
+	if userID, _ := cmd.Flags().GetString("user"); userID != "" {
+		return userID, nil
+	}
+	if userID := os.Getenv("RICOCHET_USER"); userID != "" {
+		return userID, nil
+	}
+	current, err := user.Current()
+	if err != nil {
+		return "", fmt.Errorf("failed to determine current user, use --user: %w", err)
+	}
+	return current.Username, nil
+}
+
+// newTaskFromTemplate builds a task from a template, keeping explicitly set flags from base
+func newTaskFromTemplate(cmd *cobra.Command, name string, base *providers.UniversalTask) (*providers.UniversalTask, error) {
+	store, err := taskTemplates()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load task templates: %w", err)
+	}
+
+	template, err := store.Get(name)
+	if err != nil {
+		return nil, err
+	}
+
+	task := template.NewTask(base.Title)
+	task.ProjectID = base.ProjectID
+
+	flags := cmd.Flags()
+	if flags.Changed("description") {
+		task.Description = base.Description
+	}
+	if flags.Changed("type") || task.Type == "" {
+		task.Type = base.Type
+	}
+	if flags.Changed("priority") || task.Priority == "" {
+		task.Priority = base.Priority
+	}
+	if flags.Changed("assignee") {
+		task.AssigneeID = base.AssigneeID
+	}
+	for _, label := range base.Labels {
+		if !task.HasLabel(label) {
+			task.Labels = append(task.Labels, label)
+		}
+	}
+
+	return task, nil
+}
+
+func runTemplateList(cmd *cobra.Command, args []string) error {
+	output := outputFormat(cmd)
+
+	store, err := taskTemplates()
+	if err != nil {
+		return fmt.Errorf("failed to load task templates: %w", err)
+	}
+	templates := store.List()
+
+	switch output {
+	case "json":
+		return outputJSON(templates)
+	case "yaml":
+		return outputYAML(templates)
+	}
+
+	fmt.Printf("%-20s %-10s %-10s %-9s %s\n", "NAME", "TYPE", "PRIORITY", "SOURCE", "SUMMARY")
+	fmt.Println(strings.Repeat("-", 80))
+	for _, template := range templates {
+		source := "custom"
+		if template.BuiltIn {
+			source = "built-in"
+		}
+		fmt.Printf("%-20s %-10s %-10s %-9s %s\n",
+			template.Name, template.Type, template.Priority, source, template.Summary)
+	}
+	return nil
+}
+
+func runTemplateShow(cmd *cobra.Command, args []string) error {
+	output := outputFormat(cmd)
+
+	store, err := taskTemplates()
+	if err != nil {
+		return fmt.Errorf("failed to load task templates: %w", err)
+	}
+
+	template, err := store.Get(args[0])
+	if err != nil {
+		return err
+	}
+
+	switch output {
+	case "json":
+		return outputJSON(template)
+	case "yaml":
+		return outputYAML(template)
+	}
+
+	fmt.Printf("Template: %s\n", template.Name)
+	if template.Summary != "" {
+		fmt.Printf("Summary: %s\n", template.Summary)
+	}
+	if template.TitlePrefix != "" {
+		fmt.Printf("Title Prefix: %q\n", template.TitlePrefix)
+	}
+	fmt.Printf("Type: %s\n", template.Type)
+	fmt.Printf("Priority: %s\n", template.Priority)
+	if len(template.Labels) > 0 {
+		fmt.Printf("Labels: %s\n", strings.Join(template.Labels, ", "))
+	}
+	if template.AssigneeID != "" {
+		fmt.Printf("Assignee: %s\n", template.AssigneeID)
+	}
+	if template.Description != "" {
+		fmt.Printf("\nDescription:\n%s\n", template.Description)
+	}
 	return nil
-}
\ No newline at end of file
+}
+
+func runTemplateCreate(cmd *cobra.Command, args []string) error {
+	description, _ := cmd.Flags().GetString("description")
+	descriptionFile, _ := cmd.Flags().GetString("description-file")
+	taskType, _ := cmd.Flags().GetString("type")
+	priority, _ := cmd.Flags().GetString("priority")
+
+	templatePriority, err := mapPriority("", priority)
+	if err != nil {
+		return err
+	}
+
+	if descriptionFile != "" {
+		data, err := os.ReadFile(descriptionFile)
+		if err != nil {
+			return fmt.Errorf("failed to read description file: %w", err)
+		}
+		description = string(data)
+	}
+
+	store, err := taskTemplates()
+	if err != nil {
+		return fmt.Errorf("failed to load task templates: %w", err)
+	}
+
+	template := &providers.TaskTemplate{
+		Name:        args[0],
+		Summary:     getStringFlag(cmd, "summary"),
+		TitlePrefix: getStringFlag(cmd, "title-prefix"),
+		Description: description,
+		Type:        providers.TaskType(taskType),
+		Priority:    templatePriority,
+		Labels:      getStringSliceFlag(cmd, "labels"),
+		AssigneeID:  getStringFlag(cmd, "assignee"),
+	}
+
+	if err := store.Save(template); err != nil {
+		return fmt.Errorf("failed to save template: %w", err)
+	}
+
+	fmt.Printf("✅ Template %s saved\n", template.Name)
+	return nil
+}
+
+func runTemplateDelete(cmd *cobra.Command, args []string) error {
+	store, err := taskTemplates()
+	if err != nil {
+		return fmt.Errorf("failed to load task templates: %w", err)
+	}
+
+	if err := store.Delete(args[0]); err != nil {
+		return fmt.Errorf("failed to delete template: %w", err)
+	}
+
+	fmt.Printf("✅ Template %s deleted\n", args[0])
+	return nil
+}
@@ -57,6 +57,11 @@ func init() {
 	WorkflowCmd.AddCommand(statusCmd)
 	WorkflowCmd.AddCommand(validateCmd)
 	WorkflowCmd.AddCommand(templatesCmd)
+	WorkflowCmd.AddCommand(historyCmd)
+
+	historyCmd.Flags().String("since", "", "Начало периода (RFC3339 или YYYY-MM-DD)")
+	historyCmd.Flags().String("until", "", "Конец периода (RFC3339 или YYYY-MM-DD)")
+	historyCmd.Flags().String("history-file", "", "Путь к журналу переходов (по умолчанию workflow_history.jsonl в директории профиля)")
 }
 
 // listCmd - список доступных workflow
@@ -248,6 +253,62 @@ var validateCmd = &cobra.Command{
 	},
 }
 
+// historyCmd - история переходов задачи
+var historyCmd = &cobra.Command{
+	Use:   "history [task-id]",
+	Short: "Показать историю переходов задачи между стадиями",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		taskID := args[0]
+		since, _ := cmd.Flags().GetString("since")
+		until, _ := cmd.Flags().GetString("until")
+		historyFile, _ := cmd.Flags().GetString("history-file")
+
+		filter := workflow.TransitionFilter{TaskID: taskID}
+		var err error
+		if since != "" {
+			if filter.Since, err = workflow.ParseHistoryTime(since, false); err != nil {
+				fmt.Fprintf(os.Stderr, "Ошибка: %v\n", err)
+				os.Exit(1)
+			}
+		}
+		if until != "" {
+			if filter.Until, err = workflow.ParseHistoryTime(until, true); err != nil {
+				fmt.Fprintf(os.Stderr, "Ошибка: %v\n", err)
+				os.Exit(1)
+			}
+		}
+
+		history := workflow.NewFileTransitionLog(historyFile, &SimpleLogger{})
+		records, err := history.Query(filter)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Ошибка чтения истории: %v\n", err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("📜 История переходов задачи: %s\n", taskID)
+		fmt.Println(strings.Repeat("=", 50))
+
+		if len(records) == 0 {
+			fmt.Println("Переходов не найдено")
+			return
+		}
+
+		for _, record := range records {
+			actor := record.Actor
+			if actor == "" {
+				actor = "system"
+			}
+			fmt.Printf("🕒 %s  %s → %s  (%s)\n",
+				record.Timestamp.Format("2006-01-02 15:04:05"), record.FromStage, record.ToStage, actor)
+			if record.Reason != "" {
+				fmt.Printf("   📝 %s\n", record.Reason)
+			}
+		}
+		fmt.Printf("\nВсего переходов: %d\n", len(records))
+	},
+}
+
 // templatesCmd - управление шаблонами workflow
 var templatesCmd = &cobra.Command{
 	Use:   "templates",
@@ -15,15 +15,13 @@ type Config struct {
 	APIKey     string `json:"api_key,omitempty"`
 }
 
-// DefaultConfig возвращает конфигурацию по умолчанию
+// DefaultConfig возвращает конфигурацию по умолчанию для активного профиля
 func DefaultConfig() Config {
-	homeDir, err := os.UserHomeDir()
+	configDir, err := ProfileDir(ActiveProfile())
 	if err != nil {
-		homeDir = "."
+		configDir = ".ricochet"
 	}
 
-	configDir := filepath.Join(homeDir, ".ricochet")
-
 	return Config{
 		APIGateway: "http://localhost:8080",
 		ConfigDir:  configDir,
@@ -71,12 +69,12 @@ func SaveConfig(path string, config Config) error {
 	return nil
 }
 
-// GetConfigPath возвращает путь к файлу конфигурации
+// GetConfigPath возвращает путь к файлу конфигурации активного профиля
 func GetConfigPath() (string, error) {
-	homeDir, err := os.UserHomeDir()
+	configDir, err := ProfileDir(ActiveProfile())
 	if err != nil {
-		return "", fmt.Errorf("не удалось определить домашнюю директорию: %w", err)
+		return "", err
 	}
 
-	return filepath.Join(homeDir, ".ricochet", "config.json"), nil
+	return filepath.Join(configDir, "config.json"), nil
 }
@@ -0,0 +1,209 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+const (
+	// DefaultProfile профиль по умолчанию: конфигурация в ~/.ricochet и
+	// ricochet.yaml в текущей директории, как до появления профилей
+	DefaultProfile = "default"
+
+	// ProfileEnv переменная окружения с именем профиля
+	ProfileEnv = "RICOCHET_PROFILE"
+
+	// ProviderConfigFile файл конфигурации провайдеров
+	ProviderConfigFile = "ricochet.yaml"
+
+	profilesDir        = "profiles"
+	currentProfileFile = "current_profile"
+)
+
+var profileNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]*$`)
+
+// activeProfile профиль, выбранный флагом --profile
+var activeProfile string
+
+// RootDir возвращает корневую директорию ricochet (~/.ricochet)
+func RootDir() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("не удалось определить домашнюю директорию: %w", err)
+	}
+	return filepath.Join(homeDir, ".ricochet"), nil
+}
+
+// ValidateProfileName проверяет, что имя профиля можно использовать как имя директории
+func ValidateProfileName(name string) error {
+	if !profileNamePattern.MatchString(name) {
+		return fmt.Errorf("недопустимое имя профиля %q: разрешены буквы, цифры, '-' и '_'", name)
+	}
+	return nil
+}
+
+// ProfileDir возвращает директорию профиля. Профиль по умолчанию хранится
+// прямо в ~/.ricochet, остальные - в ~/.ricochet/profiles/<имя>.
+func ProfileDir(name string) (string, error) {
+	root, err := RootDir()
+	if err != nil {
+		return "", err
+	}
+	if name == "" || name == DefaultProfile {
+		return root, nil
+	}
+	if err := ValidateProfileName(name); err != nil {
+		return "", err
+	}
+	return filepath.Join(root, profilesDir, name), nil
+}
+
+// SetActiveProfile выбирает профиль для текущего запуска (флаг --profile)
+func SetActiveProfile(name string) error {
+	if name != "" && !ProfileExists(name) {
+		return fmt.Errorf("профиль %q не найден, создайте его командой 'ricochet profile create %s'", name, name)
+	}
+	activeProfile = name
+	return nil
+}
+
+// ActiveProfile возвращает профиль текущего запуска: заданный флагом --profile,
+// затем переменной RICOCHET_PROFILE, затем командой 'profile switch'
+func ActiveProfile() string {
+	if activeProfile != "" {
+		return activeProfile
+	}
+	if name := os.Getenv(ProfileEnv); name != "" {
+		return name
+	}
+	return CurrentProfile()
+}
+
+// CurrentProfile возвращает профиль, выбранный командой 'profile switch'
+func CurrentProfile() string {
+	root, err := RootDir()
+	if err != nil {
+		return DefaultProfile
+	}
+	data, err := os.ReadFile(filepath.Join(root, currentProfileFile))
+	if err != nil {
+		return DefaultProfile
+	}
+	name := strings.TrimSpace(string(data))
+	if name == "" || ValidateProfileName(name) != nil {
+		return DefaultProfile
+	}
+	return name
+}
+
+// ProfileExists проверяет, что профиль создан. Профиль по умолчанию существует всегда.
+func ProfileExists(name string) bool {
+	if name == DefaultProfile {
+		return true
+	}
+	dir, err := ProfileDir(name)
+	if err != nil {
+		return false
+	}
+	info, err := os.Stat(dir)
+	return err == nil && info.IsDir()
+}
+
+// CreateProfile создает директорию профиля с конфигурацией, указывающей на нее
+func CreateProfile(name string) (string, error) {
+	if name == DefaultProfile {
+		return "", fmt.Errorf("профиль %q существует всегда", DefaultProfile)
+	}
+	if ProfileExists(name) {
+		return "", fmt.Errorf("профиль %q уже существует", name)
+	}
+
+	dir, err := ProfileDir(name)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", fmt.Errorf("не удалось создать директорию профиля: %w", err)
+	}
+
+	profileConfig := DefaultConfig()
+	profileConfig.ConfigDir = dir
+	if err := SaveConfig(filepath.Join(dir, "config.json"), profileConfig); err != nil {
+		return "", err
+	}
+
+	return dir, nil
+}
+
+// ListProfiles возвращает профиль по умолчанию и созданные профили
+func ListProfiles() ([]string, error) {
+	root, err := RootDir()
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(filepath.Join(root, profilesDir))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("не удалось прочитать список профилей: %w", err)
+	}
+
+	var names []string
+	for _, entry := range entries {
+		if entry.IsDir() && ValidateProfileName(entry.Name()) == nil && entry.Name() != DefaultProfile {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+
+	return append([]string{DefaultProfile}, names...), nil
+}
+
+// SwitchProfile делает профиль текущим для последующих запусков
+func SwitchProfile(name string) error {
+	if !ProfileExists(name) {
+		return fmt.Errorf("профиль %q не найден", name)
+	}
+
+	root, err := RootDir()
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(root, currentProfileFile)
+
+	if name == DefaultProfile {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("не удалось сбросить текущий профиль: %w", err)
+		}
+		return nil
+	}
+
+	if err := os.MkdirAll(root, 0755); err != nil {
+		return fmt.Errorf("не удалось создать директорию конфигурации: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(name+"\n"), 0644); err != nil {
+		return fmt.Errorf("не удалось сохранить текущий профиль: %w", err)
+	}
+	return nil
+}
+
+// ProfilePath возвращает путь к файлу в директории активного профиля
+func ProfilePath(file string) string {
+	dir, err := ProfileDir(ActiveProfile())
+	if err != nil {
+		return filepath.Join(".ricochet", file)
+	}
+	return filepath.Join(dir, file)
+}
+
+// ProviderConfigPath возвращает файл конфигурации провайдеров активного профиля.
+// Для профиля по умолчанию это ricochet.yaml в текущей директории.
+func ProviderConfigPath() string {
+	if ActiveProfile() == DefaultProfile {
+		return ProviderConfigFile
+	}
+	return ProfilePath(ProviderConfigFile)
+}
@@ -0,0 +1,64 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProfiles(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv(ProfileEnv, "")
+	t.Cleanup(func() { activeProfile = "" })
+	root := filepath.Join(home, ".ricochet")
+
+	t.Run("Default profile keeps the old layout", func(t *testing.T) {
+		assert.Equal(t, DefaultProfile, ActiveProfile())
+		assert.Equal(t, root, DefaultConfig().ConfigDir)
+		assert.Equal(t, ProviderConfigFile, ProviderConfigPath())
+
+		path, err := GetConfigPath()
+		require.NoError(t, err)
+		assert.Equal(t, filepath.Join(root, "config.json"), path)
+	})
+
+	t.Run("Create, switch and select profiles", func(t *testing.T) {
+		dir, err := CreateProfile("work")
+		require.NoError(t, err)
+		assert.Equal(t, filepath.Join(root, "profiles", "work"), dir)
+
+		_, err = CreateProfile("work")
+		assert.Error(t, err)
+		_, err = CreateProfile("../escape")
+		assert.Error(t, err)
+
+		names, err := ListProfiles()
+		require.NoError(t, err)
+		assert.Equal(t, []string{DefaultProfile, "work"}, names)
+
+		require.NoError(t, SwitchProfile("work"))
+		assert.Equal(t, "work", ActiveProfile())
+		assert.Equal(t, filepath.Join(dir, ProviderConfigFile), ProviderConfigPath())
+
+		loaded, err := LoadConfig(filepath.Join(dir, "config.json"))
+		require.NoError(t, err)
+		assert.Equal(t, dir, loaded.ConfigDir)
+
+		require.NoError(t, SetActiveProfile(DefaultProfile))
+		assert.Equal(t, DefaultProfile, ActiveProfile())
+		assert.Error(t, SetActiveProfile("missing"))
+
+		activeProfile = ""
+		require.NoError(t, SwitchProfile(DefaultProfile))
+		assert.Equal(t, DefaultProfile, CurrentProfile())
+		assert.Error(t, SwitchProfile("missing"))
+	})
+
+	t.Run("Environment overrides the current profile", func(t *testing.T) {
+		t.Setenv(ProfileEnv, "work")
+		assert.Equal(t, "work", ActiveProfile())
+	})
+}
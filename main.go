@@ -4,10 +4,13 @@ import (
 	"context"
 	"fmt"
 	"os"
-	"path/filepath"
+	"strconv"
+	"strings"
 	"log"
 
 	"github.com/grik-ai/ricochet-task/cmd/ricochet"
+	"github.com/grik-ai/ricochet-task/internal/config"
+	"github.com/grik-ai/ricochet-task/pkg/ai"
 	"github.com/grik-ai/ricochet-task/pkg/api"
 	"github.com/grik-ai/ricochet-task/pkg/chain"
 	"github.com/grik-ai/ricochet-task/pkg/checkpoint"
@@ -34,11 +37,13 @@ type Config struct {
 	MinIOSecretKey       string
 	MinIOBucket          string
 	MinIOUseSSL          bool
+	// Предел одновременных запусков цепочек, 0 - без ограничения
+	MaxConcurrentRuns    int
 }
 
 // Загрузка конфигурации
 func loadConfig() (*Config, error) {
-	return &Config{
+	cfg := &Config{
 		ConfigDir:     os.Getenv("RICOCHET_CONFIG_DIR"),
 		DefaultChain:  getEnvWithDefault("RICOCHET_DEFAULT_CHAIN", "default"),
 		WorkspacePath: getEnvWithDefault("RICOCHET_WORKSPACE_PATH", "./"),
@@ -50,7 +55,16 @@ func loadConfig() (*Config, error) {
 		MinIOSecretKey: os.Getenv("MINIO_SECRET_KEY"),
 		MinIOBucket:    getEnvWithDefault("MINIO_BUCKET", "ricochet-checkpoints"),
 		MinIOUseSSL:    os.Getenv("MINIO_USE_SSL") == "true",
-	}, nil
+		MaxConcurrentRuns: orchestrator.DefaultMaxConcurrentRuns,
+	}
+	if value := os.Getenv("RICOCHET_MAX_CONCURRENT_RUNS"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 0 {
+			return nil, fmt.Errorf("некорректное значение RICOCHET_MAX_CONCURRENT_RUNS %q: ожидается целое число не меньше 0", value)
+		}
+		cfg.MaxConcurrentRuns = limit
+	}
+	return cfg, nil
 }
 
 // getEnvWithDefault возвращает значение переменной окружения или значение по умолчанию
@@ -121,15 +135,20 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Хранилища создаются до разбора флагов, поэтому профиль из --profile
+	// выбираем заранее. Ошибку о неизвестном профиле выведет CLI.
+	if name := profileFromArgs(os.Args[1:]); name != "" {
+		_ = config.SetActiveProfile(name)
+	}
+
 	// Инициализируем хранилища
 	configDir := cfg.ConfigDir
 	if configDir == "" {
-		homeDir, err := os.UserHomeDir()
+		configDir, err = config.ProfileDir(config.ActiveProfile())
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Ошибка получения домашней директории: %v\n", err)
+			fmt.Fprintf(os.Stderr, "Ошибка получения директории конфигурации: %v\n", err)
 			os.Exit(1)
 		}
-		configDir = filepath.Join(homeDir, ".ricochet")
 	}
 
 	// Создаем директорию конфигурации, если она не существует
@@ -230,6 +249,8 @@ func main() {
 		switch k.Provider {
 		case "openai":
 			modelFactory.RegisterProvider(model.NewOpenAIProvider(k.Value, ""))
+		case "claude", "anthropic":
+			modelFactory.RegisterProvider(model.NewAnthropicProvider(k.Value, ""))
 		// Другие провайдеры будут добавлены позже
 		default:
 			fmt.Printf("Провайдер %s не поддерживается, ключ пропущен\n", k.Provider)
@@ -243,50 +264,46 @@ func main() {
 		task.DefaultExecutorConfig(),
 	)
 
-	// Инициализируем оркестратор с PostgreSQL хранилищем для метаданных запусков
-	var orchestratorImpl orchestrator.Orchestrator
+	// Инициализируем хранилище метаданных запусков (PostgreSQL или файловая система)
+	var runStore orchestrator.RunStore
 	if cfg.PostgresDSN != "" {
 		log.Printf("Инициализация PostgreSQL хранилища метаданных запусков...")
 		postgresRunStore, err := orchestrator.NewPostgresRunStore(cfg.PostgresDSN)
 		if err != nil {
-			log.Printf("Ошибка инициализации PostgreSQL хранилища метаданных: %v. Используем in-memory хранилище", err)
-			orchestratorImpl = orchestrator.NewOrchestrator(
-				apiClient,
-				keyStore,
-				chainStore,
-				checkpointStore,
-				taskManager,
-				taskExecutor,
-				modelFactory,
-			)
+			log.Printf("Ошибка инициализации PostgreSQL хранилища метаданных: %v. Используем файловое хранилище", err)
 		} else {
-			orchestratorImpl = orchestrator.NewOrchestratorWithPostgres(
-				apiClient,
-				keyStore,
-				chainStore,
-				checkpointStore,
-				taskManager,
-				taskExecutor,
-				modelFactory,
-				postgresRunStore,
-			)
+			runStore = postgresRunStore
 			log.Printf("PostgreSQL хранилище метаданных запусков инициализировано")
 		}
-	} else {
-		orchestratorImpl = orchestrator.NewOrchestrator(
-			apiClient,
-			keyStore,
-			chainStore,
-			checkpointStore,
-			taskManager,
-			taskExecutor,
-			modelFactory,
-		)
+	}
+	if runStore == nil {
+		fileRunStore, err := orchestrator.NewFileRunStore(configDir)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Ошибка инициализации файлового хранилища запусков: %v\n", err)
+			os.Exit(1)
+		}
+		runStore = fileRunStore
 	}
 
+	// Инициализируем оркестратор
+	defaultOrchestrator := orchestrator.NewOrchestratorWithRunStore(
+		apiClient,
+		keyStore,
+		chainStore,
+		checkpointStore,
+		taskManager,
+		taskExecutor,
+		modelFactory,
+		runStore,
+	)
+	// Запуски сверх предела ждут в очереди, а не стартуют все сразу
+	defaultOrchestrator.SetMaxConcurrentRuns(cfg.MaxConcurrentRuns)
+	var orchestratorImpl orchestrator.Orchestrator = defaultOrchestrator
+
 	// Устанавливаем глобальные сервисы для MCP
 	mcputils.SetOrchestratorService(orchestratorImpl)
 	mcputils.SetChainStore(chainStore)
+	mcputils.SetAIAPIKeys(aiAPIKeys(keys))
 
 	// Инициализируем интеграцию с MCP
 	mcpIntegration := mcp.NewMCPIntegration("", cfg.DefaultChain)
@@ -311,10 +328,26 @@ func main() {
 	// Запускаем CLI
 	if err := ricochet.Execute(); err != nil {
 		fmt.Fprintf(os.Stderr, "Ошибка выполнения команды: %v\n", err)
-		os.Exit(1)
+		os.Exit(ricochet.ExitCode(err))
 	}
 }
 
+// profileFromArgs возвращает значение флага --profile из аргументов командной строки
+func profileFromArgs(args []string) string {
+	for i, arg := range args {
+		if arg == "--" {
+			break
+		}
+		if value, ok := strings.CutPrefix(arg, "--profile="); ok {
+			return value
+		}
+		if arg == "--profile" && i+1 < len(args) {
+			return args[i+1]
+		}
+	}
+	return ""
+}
+
 // ModelProviderAdapter адаптер для использования фабрики провайдеров моделей с исполнителем задач
 type ModelProviderAdapter struct {
 	Factory *model.ProviderFactory
@@ -329,32 +362,51 @@ func (a *ModelProviderAdapter) Execute(ctx context.Context, model chain.Model, p
 	return provider.Execute(ctx, model, prompt, options)
 }
 
+// ExecuteStream выполняет запрос к модели с потоковой передачей ответа
+func (a *ModelProviderAdapter) ExecuteStream(ctx context.Context, model chain.Model, prompt string, options map[string]interface{}) (<-chan model.StreamChunk, error) {
+	provider, err := a.Factory.GetProviderForModel(model)
+	if err != nil {
+		return nil, fmt.Errorf("provider not found: %w", err)
+	}
+	return provider.ExecuteStream(ctx, model, prompt, options)
+}
+
 // EstimateTokens оценивает количество токенов в тексте
 func (a *ModelProviderAdapter) EstimateTokens(text string) int {
 	estimator := model.NewTokenEstimator()
 	return estimator.EstimateTokens(text, "")
 }
 
-// GetModel возвращает модель по имени
+// GetModel возвращает модель по имени у любого зарегистрированного провайдера.
+// Ошибка перечисляет доступные модели и подсказывает ближайшее имя.
 func (a *ModelProviderAdapter) GetModel(name chain.ModelName) (chain.ModelConfiguration, error) {
-	// Перебираем все типы моделей, которые мы можем поддерживать
-	modelTypes := []chain.ModelType{
-		chain.ModelTypeOpenAI,
-		chain.ModelTypeClaude,
-		chain.ModelTypeDeepSeek,
-		chain.ModelTypeGrok,
-		chain.ModelTypeLlama,
-		chain.ModelTypeMistral,
-	}
+	return a.Factory.FindModel(name)
+}
 
-	// Ищем модель в каждом типе
-	for _, modelType := range modelTypes {
-		if provider, err := a.Factory.GetProvider(modelType); err == nil {
-			if model, err := provider.GetModel(name); err == nil {
-				return model, nil
+// aiAPIKeys собирает ключи AI цепочек из хранилища ключей, по первому ключу
+// каждого провайдера
+func aiAPIKeys(keys []key.Key) *ai.UserAPIKeys {
+	userKeys := &ai.UserAPIKeys{}
+	for _, k := range keys {
+		apiKey := &ai.APIKeyConfig{APIKey: k.Value, Enabled: true}
+		switch k.Provider {
+		case "openai":
+			if userKeys.OpenAI == nil {
+				userKeys.OpenAI = apiKey
+			}
+		case "claude", "anthropic":
+			if userKeys.Anthropic == nil {
+				userKeys.Anthropic = apiKey
+			}
+		case "deepseek":
+			if userKeys.DeepSeek == nil {
+				userKeys.DeepSeek = apiKey
+			}
+		case "grok":
+			if userKeys.Grok == nil {
+				userKeys.Grok = apiKey
 			}
 		}
 	}
-
-	return chain.ModelConfiguration{}, fmt.Errorf("model not found: %s", name)
+	return userKeys
 }
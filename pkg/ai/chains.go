@@ -5,8 +5,13 @@ import (
 	"encoding/json"
 	"fmt"
 	"regexp"
+	"sort"
 	"strings"
 	"time"
+
+	"github.com/grik-ai/ricochet-task/pkg/chain"
+	"github.com/grik-ai/ricochet-task/pkg/model"
+	"github.com/grik-ai/ricochet-task/pkg/providers"
 )
 
 // ProjectAnalysis represents the result of project analysis
@@ -45,12 +50,38 @@ type ProjectPlan struct {
 	CreatedAt      time.Time        `json:"created_at"`
 }
 
+// TriageSuggestion is the suggested classification of an untriaged task
+type TriageSuggestion struct {
+	Priority string   `json:"priority"`
+	Type     string   `json:"type"`
+	Labels   []string `json:"labels"`
+	Assignee string   `json:"assignee"`
+	Reason   string   `json:"reason"`
+}
+
+// ProjectStatusSummary is a status update of a project
+type ProjectStatusSummary struct {
+	Summary   string   `json:"summary"`
+	Progress  string   `json:"progress"`
+	Blockers  []string `json:"blockers"`
+	Risks     []string `json:"risks"`
+	NextSteps []string `json:"next_steps"`
+}
+
+// TaskExpansion is a structured description generated from a terse task title
+type TaskExpansion struct {
+	Context            string   `json:"context"`
+	AcceptanceCriteria []string `json:"acceptance_criteria"`
+	TechnicalNotes     []string `json:"technical_notes"`
+}
+
 // AIChains provides AI-powered analysis and planning capabilities
 type AIChains struct {
 	hybridClient *HybridAIClient
 	mockChains   *MockAIChains
 	useMock      bool
 	logger       Logger
+	ctx          context.Context
 }
 
 // NewAIChains creates a new AI chains instance
@@ -88,6 +119,22 @@ func NewAIChains(gatewayURL, gatewayToken, userID string, userKeys *UserAPIKeys,
 	return chains
 }
 
+// WithContext returns a copy of the chains whose AI requests use ctx, so
+// cancelling it or reaching its deadline aborts requests in flight
+func (c *AIChains) WithContext(ctx context.Context) *AIChains {
+	chains := *c
+	chains.ctx = ctx
+	return &chains
+}
+
+// requestContext returns the context of AI requests, set with WithContext
+func (c *AIChains) requestContext() context.Context {
+	if c.ctx != nil {
+		return c.ctx
+	}
+	return context.Background()
+}
+
 // SetPrimaryProvider sets the primary AI provider for chains
 func (c *AIChains) SetPrimaryProvider(provider string) {
 	// This method is kept for compatibility but routing is now handled by HybridAIClient
@@ -142,7 +189,7 @@ Guidelines:
 		Strategy:    RouteUserKeyFirst,
 	}
 
-	response, err := c.hybridClient.Chat(context.Background(), request)
+	response, err := c.hybridClient.Chat(c.requestContext(), request)
 	if err != nil {
 		return nil, fmt.Errorf("failed to analyze project: %w", err)
 	}
@@ -221,7 +268,7 @@ Requirements:
 		Strategy:    RouteUserKeyFirst,
 	}
 
-	response, err := c.hybridClient.Chat(context.Background(), request)
+	response, err := c.hybridClient.Chat(c.requestContext(), request)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create project plan: %w", err)
 	}
@@ -319,7 +366,7 @@ Include specific steps, best practices, and success criteria.`, taskTitle, taskD
 		Strategy:    RouteUserKeyFirst,
 	}
 
-	response, err := c.hybridClient.Chat(context.Background(), request)
+	response, err := c.hybridClient.Chat(c.requestContext(), request)
 	if err != nil {
 		return "", fmt.Errorf("failed to execute task: %w", err)
 	}
@@ -361,7 +408,7 @@ Keep it professional and under 200 words.`, taskTitle, currentStatus, progressPe
 		Strategy:    RouteUserKeyFirst,
 	}
 
-	response, err := c.hybridClient.Chat(context.Background(), request)
+	response, err := c.hybridClient.Chat(c.requestContext(), request)
 	if err != nil {
 		return "", fmt.Errorf("failed to generate progress comment: %w", err)
 	}
@@ -373,6 +420,380 @@ Keep it professional and under 200 words.`, taskTitle, currentStatus, progressPe
 	return response.Choices[0].Message.Content, nil
 }
 
+// SuggestStepPrompt writes a prompt for a step of a model chain from the
+// chain's purpose, the role and description of the step and the steps around
+// it, each as "role: description"
+func (c *AIChains) SuggestStepPrompt(chainName, chainPurpose, role, stepDescription string, otherSteps []string) (string, error) {
+	if c.useMock {
+		return c.mockChains.SuggestStepPrompt(chainName, chainPurpose, role, stepDescription, otherSteps)
+	}
+
+	steps := "none"
+	if len(otherSteps) > 0 {
+		steps = "- " + strings.Join(otherSteps, "\n- ")
+	}
+
+	prompt := fmt.Sprintf(`Write the system prompt for one step of a chain of AI models, where every step gets the output of the previous one as its input.
+
+Chain: %s
+Purpose of the chain: %s
+Role of the step: %s
+What the step should do: %s
+Other steps of the chain:
+%s
+
+Guidelines:
+- Address the model directly and state what it receives and what it must return
+- Fit the step between the other steps, do not repeat their work
+- Name the expected format of the output so the next step can use it
+- Respond with the prompt only, without explanations or quotes`,
+		chainName, chainPurpose, role, stepDescription, steps)
+
+	request := &HybridChatRequest{
+		Model:    "gpt-4", // Default model for chain prompts
+		Messages: []Message{
+			{Role: "user", Content: prompt},
+		},
+		Temperature: 0.5,
+		MaxTokens:   600,
+		Strategy:    RouteUserKeyFirst,
+	}
+
+	response, err := c.hybridClient.Chat(c.requestContext(), request)
+	if err != nil {
+		return "", fmt.Errorf("failed to suggest step prompt: %w", err)
+	}
+
+	if len(response.Choices) == 0 {
+		return "", fmt.Errorf("no response from AI")
+	}
+
+	return strings.TrimSpace(response.Choices[0].Message.Content), nil
+}
+
+// TriageTask suggests the priority, type, labels and assignee of a task from
+// its title and description. labels are the labels the team already uses and
+// workload maps team members to their number of open tasks.
+func (c *AIChains) TriageTask(taskTitle, taskDescription string, labels []string, workload map[string]int) (*TriageSuggestion, error) {
+	if c.useMock {
+		return c.mockChains.TriageTask(taskTitle, taskDescription, labels, workload)
+	}
+
+	members := make([]string, 0, len(workload))
+	for member, open := range workload {
+		members = append(members, fmt.Sprintf("%s (%d open tasks)", member, open))
+	}
+	sort.Strings(members)
+
+	prompt := fmt.Sprintf(`Triage the following new task for a software team:
+
+Task: %s
+Description: %s
+
+Labels in use: %s
+Team members: %s
+
+Respond in the following JSON format:
+{
+  "priority": "lowest|low|medium|high|highest|critical",
+  "type": "task|bug|feature|story|improvement|chore|research",
+  "labels": ["label1", "label2"],
+  "assignee": "team member",
+  "reason": "one sentence explaining the classification"
+}
+
+Guidelines:
+- Prefer labels that are already in use, at most 3
+- Pick the assignee only from the team members, balancing their open tasks, or leave it empty
+- Reserve critical and highest for outages, data loss and security issues`,
+		taskTitle, taskDescription, strings.Join(labels, ", "), strings.Join(members, ", "))
+
+	request := &HybridChatRequest{
+		Model:    "gpt-4", // Default model for triage
+		Messages: []Message{
+			{Role: "user", Content: prompt},
+		},
+		Temperature: 0.2,
+		MaxTokens:   500,
+		Strategy:    RouteUserKeyFirst,
+	}
+
+	response, err := c.hybridClient.Chat(c.requestContext(), request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to triage task: %w", err)
+	}
+
+	if len(response.Choices) == 0 {
+		return nil, fmt.Errorf("no response from AI")
+	}
+
+	jsonContent := extractJSON(response.Choices[0].Message.Content)
+	if jsonContent == "" {
+		return nil, fmt.Errorf("failed to extract JSON from AI response")
+	}
+
+	var suggestion TriageSuggestion
+	if err := json.Unmarshal([]byte(jsonContent), &suggestion); err != nil {
+		return nil, fmt.Errorf("failed to parse AI response: %w", err)
+	}
+
+	return &suggestion, nil
+}
+
+// TriageSuggester suggests task classifications with TriageTask, checked
+// against the team context
+func (c *AIChains) TriageSuggester() providers.TriageSuggester {
+	return func(ctx context.Context, task *providers.UniversalTask, team *providers.TriageTeam) (*providers.TriageSuggestion, error) {
+		suggestion, err := c.WithContext(ctx).TriageTask(task.Title, task.Description, team.Labels, team.Workload())
+		if err != nil {
+			return nil, err
+		}
+		return providers.NewTriageSuggestion(suggestion.Priority, suggestion.Type, suggestion.Labels,
+			suggestion.Assignee, suggestion.Reason, team), nil
+	}
+}
+
+// ExpandTask writes a structured description of a task from its title and
+// any description it already has: the context of the work, acceptance
+// criteria and technical notes
+func (c *AIChains) ExpandTask(taskTitle, taskDescription, taskType string) (*TaskExpansion, error) {
+	if c.useMock {
+		return c.mockChains.ExpandTask(taskTitle, taskDescription, taskType)
+	}
+
+	prompt := fmt.Sprintf(`Expand the following terse task of a software team into a proper description:
+
+Task: %s
+Type: %s
+Current description: %s
+
+Respond in the following JSON format:
+{
+  "context": "two or three sentences on why the work is needed and what it covers",
+  "acceptance_criteria": ["verifiable condition of done"],
+  "technical_notes": ["implementation hint, dependency or risk"]
+}
+
+Guidelines:
+- Keep everything the current description says
+- Do not invent product names, people or deadlines that the task does not mention
+- Use 3 to 6 acceptance criteria and at most 5 technical notes, one sentence each`,
+		taskTitle, taskType, taskDescription)
+
+	request := &HybridChatRequest{
+		Model:    "gpt-4", // Default model for task descriptions
+		Messages: []Message{
+			{Role: "user", Content: prompt},
+		},
+		Temperature: 0.4,
+		MaxTokens:   1000,
+		Strategy:    RouteUserKeyFirst,
+	}
+
+	response, err := c.hybridClient.Chat(c.requestContext(), request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to expand task: %w", err)
+	}
+
+	if len(response.Choices) == 0 {
+		return nil, fmt.Errorf("no response from AI")
+	}
+
+	jsonContent := extractJSON(response.Choices[0].Message.Content)
+	if jsonContent == "" {
+		return nil, fmt.Errorf("failed to extract JSON from AI response")
+	}
+
+	var expansion TaskExpansion
+	if err := json.Unmarshal([]byte(jsonContent), &expansion); err != nil {
+		return nil, fmt.Errorf("failed to parse AI response: %w", err)
+	}
+
+	return &expansion, nil
+}
+
+// Description renders the expansion as a markdown task description. A
+// description the task already has is kept above the generated sections.
+func (e *TaskExpansion) Description(current string) string {
+	var b strings.Builder
+	if current = strings.TrimSpace(current); current != "" {
+		fmt.Fprintf(&b, "%s\n\n", current)
+	}
+	fmt.Fprintf(&b, "## Context\n\n%s\n", e.Context)
+	if len(e.AcceptanceCriteria) > 0 {
+		b.WriteString("\n## Acceptance criteria\n\n")
+		for _, criterion := range e.AcceptanceCriteria {
+			fmt.Fprintf(&b, "- [ ] %s\n", criterion)
+		}
+	}
+	if len(e.TechnicalNotes) > 0 {
+		b.WriteString("\n## Technical notes\n\n")
+		for _, note := range e.TechnicalNotes {
+			fmt.Fprintf(&b, "- %s\n", note)
+		}
+	}
+	return b.String()
+}
+
+// ScoreDuplicates rates how likely each candidate describes the same issue as
+// a new task, from 0 to 1
+func (c *AIChains) ScoreDuplicates(taskTitle, taskDescription string, candidates []string) ([]float64, error) {
+	var list strings.Builder
+	for i, candidate := range candidates {
+		fmt.Fprintf(&list, "%d. %s\n", i+1, candidate)
+	}
+
+	prompt := fmt.Sprintf(`A new task is about to be filed:
+
+Task: %s
+Description: %s
+
+Existing tasks:
+%s
+Rate for each existing task how likely it describes the same problem or request as the new task,
+from 0 (unrelated) to 1 (certainly a duplicate). Tasks that only touch the same area are not duplicates.
+
+Respond in the following JSON format, with one score per existing task in the same order:
+{
+  "scores": [0.0, 0.0]
+}`, taskTitle, taskDescription, list.String())
+
+	request := &HybridChatRequest{
+		Model:    "gpt-4", // Default model for duplicate detection
+		Messages: []Message{
+			{Role: "user", Content: prompt},
+		},
+		Temperature: 0.0,
+		MaxTokens:   300,
+		Strategy:    RouteUserKeyFirst,
+	}
+
+	response, err := c.hybridClient.Chat(c.requestContext(), request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to score duplicates: %w", err)
+	}
+
+	if len(response.Choices) == 0 {
+		return nil, fmt.Errorf("no response from AI")
+	}
+
+	jsonContent := extractJSON(response.Choices[0].Message.Content)
+	if jsonContent == "" {
+		return nil, fmt.Errorf("failed to extract JSON from AI response")
+	}
+
+	var result struct {
+		Scores []float64 `json:"scores"`
+	}
+	if err := json.Unmarshal([]byte(jsonContent), &result); err != nil {
+		return nil, fmt.Errorf("failed to parse AI response: %w", err)
+	}
+	if len(result.Scores) != len(candidates) {
+		return nil, fmt.Errorf("AI returned %d scores for %d tasks", len(result.Scores), len(candidates))
+	}
+
+	return result.Scores, nil
+}
+
+// DuplicateScorer scores possible duplicates with ScoreDuplicates. Without AI
+// services it returns nil, so the word similarity of the tasks is used, since
+// the mock chains can't judge similarity any better.
+func (c *AIChains) DuplicateScorer() providers.DuplicateScorer {
+	if c.useMock {
+		return nil
+	}
+	return func(ctx context.Context, task *providers.UniversalTask, candidates []*providers.UniversalTask) ([]float64, error) {
+		described := make([]string, len(candidates))
+		for i, candidate := range candidates {
+			description := candidate.Description
+			if runes := []rune(description); len(runes) > 300 {
+				description = string(runes[:300]) + "..."
+			}
+			described[i] = fmt.Sprintf("%s: %s\n   %s", candidate.GetDisplayID(), candidate.Title, strings.ReplaceAll(description, "\n", " "))
+		}
+		return c.WithContext(ctx).ScoreDuplicates(task.Title, task.Description, described)
+	}
+}
+
+// SummarizeProjectStatus writes a status update of a project: its progress,
+// blockers and risks
+func (c *AIChains) SummarizeProjectStatus(status *providers.ProjectStatus) (*ProjectStatusSummary, error) {
+	if c.useMock {
+		return c.mockChains.SummarizeProjectStatus(status)
+	}
+
+	prompt := fmt.Sprintf(`Write a status update of the following software project for its stakeholders:
+
+%s
+Respond in the following JSON format:
+{
+  "summary": "two or three sentences on the overall state of the project",
+  "progress": "what was completed in the period and what is in progress",
+  "blockers": ["blocked work and what blocks it"],
+  "risks": ["risk to the schedule or quality, e.g. overdue or unassigned important work"],
+  "next_steps": ["most important next step"]
+}
+
+Guidelines:
+- Refer to tasks by their IDs
+- Only mention blockers and risks that follow from the tasks
+- Keep every list item to one sentence, at most 5 items per list`, status.Digest())
+
+	request := &HybridChatRequest{
+		Model:    "gpt-4", // Default model for status updates
+		Messages: []Message{
+			{Role: "user", Content: prompt},
+		},
+		Temperature: 0.3,
+		MaxTokens:   1500,
+		Strategy:    RouteUserKeyFirst,
+	}
+
+	response, err := c.hybridClient.Chat(c.requestContext(), request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to summarize project: %w", err)
+	}
+
+	if len(response.Choices) == 0 {
+		return nil, fmt.Errorf("no response from AI")
+	}
+
+	jsonContent := extractJSON(response.Choices[0].Message.Content)
+	if jsonContent == "" {
+		return nil, fmt.Errorf("failed to extract JSON from AI response")
+	}
+
+	var summary ProjectStatusSummary
+	if err := json.Unmarshal([]byte(jsonContent), &summary); err != nil {
+		return nil, fmt.Errorf("failed to parse AI response: %w", err)
+	}
+
+	return &summary, nil
+}
+
+// Markdown renders the status update as a comment, e.g. on an epic
+func (s *ProjectStatusSummary) Markdown(title string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "## %s\n\n%s\n", title, s.Summary)
+	if s.Progress != "" {
+		fmt.Fprintf(&b, "\n### Progress\n\n%s\n", s.Progress)
+	}
+	writeMarkdownList(&b, "Blockers", s.Blockers)
+	writeMarkdownList(&b, "Risks", s.Risks)
+	writeMarkdownList(&b, "Next steps", s.NextSteps)
+	return b.String()
+}
+
+func writeMarkdownList(b *strings.Builder, title string, items []string) {
+	if len(items) == 0 {
+		return
+	}
+	fmt.Fprintf(b, "\n### %s\n\n", title)
+	for _, item := range items {
+		fmt.Fprintf(b, "- %s\n", item)
+	}
+}
+
 // AnalyzeCodebase performs codebase analysis for project planning
 func (c *AIChains) AnalyzeCodebase(codeFiles []string, projectDescription string) (*ProjectAnalysis, error) {
 	if c.useMock {
@@ -426,7 +847,7 @@ Consider the existing code patterns, architecture, and suggest realistic impleme
 		Strategy:    RouteUserKeyFirst,
 	}
 
-	response, err := c.hybridClient.Chat(context.Background(), request)
+	response, err := c.hybridClient.Chat(c.requestContext(), request)
 	if err != nil {
 		return nil, fmt.Errorf("failed to analyze codebase: %w", err)
 	}
@@ -490,6 +911,26 @@ func (c *AIChains) UpdateUserAPIKeys(userKeys *UserAPIKeys) {
 	}
 }
 
+// Offline reports whether the chains fall back to offline heuristics
+// because no AI service is available
+func (c *AIChains) Offline() bool {
+	return c.useMock
+}
+
+// OnUsage calls record with the tokens and cost of every AI request. Costs
+// the service doesn't report are estimated from the public model prices.
+func (c *AIChains) OnUsage(record func(providers.AIUsage)) {
+	c.hybridClient.OnUsage = func(modelName string, usage Usage, cost *float64) {
+		recorded := providers.AIUsage{Tokens: usage.TotalTokens}
+		if cost != nil {
+			recorded.Cost = *cost
+		} else if pricing, ok := model.GetModelPricing(chain.ModelName(modelName)); ok {
+			recorded.Cost = pricing.Cost(usage.PromptTokens, usage.CompletionTokens)
+		}
+		record(recorded)
+	}
+}
+
 // GetUsageStats returns usage statistics
 func (c *AIChains) GetUsageStats() *UsageStats {
 	if c.useMock {
@@ -0,0 +1,38 @@
+package ai
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTaskExpansionDescription(t *testing.T) {
+	expansion := &TaskExpansion{
+		Context:            "Certificates of the edge proxies expire soon.",
+		AcceptanceCriteria: []string{"New certificates are deployed", "Expiry alerts are configured"},
+		TechnicalNotes:     []string{"Use the ACME client"},
+	}
+
+	assert.Equal(t, `Certificates expire in March
+
+## Context
+
+Certificates of the edge proxies expire soon.
+
+## Acceptance criteria
+
+- [ ] New certificates are deployed
+- [ ] Expiry alerts are configured
+
+## Technical notes
+
+- Use the ACME client
+`, expansion.Description("  Certificates expire in March\n"), "the existing description is kept above the generated sections")
+
+	// Without a description or notes only the generated sections are rendered
+	expansion.TechnicalNotes = nil
+	description := expansion.Description("   ")
+	assert.True(t, strings.HasPrefix(description, "## Context\n\nCertificates of the edge proxies expire soon.\n"))
+	assert.NotContains(t, description, "## Technical notes")
+}
@@ -32,6 +32,10 @@ type HybridAIClient struct {
 	
 	HTTPClient   *http.Client
 	Logger       Logger
+
+	// OnUsage, если задан, вызывается после каждого успешного запроса с его
+	// моделью, использованием токенов и стоимостью, если она известна
+	OnUsage func(model string, usage Usage, cost *float64)
 }
 
 // UserAPIKeys пользовательские API ключи
@@ -155,6 +159,19 @@ func (c *HybridAIClient) initializeDirectClients() {
 
 // Chat выполняет чат запрос с интеллектуальным роутингом
 func (c *HybridAIClient) Chat(ctx context.Context, request *HybridChatRequest) (*HybridChatResponse, error) {
+	response, err := c.route(ctx, request)
+	if err == nil && c.OnUsage != nil {
+		model := response.Model
+		if model == "" {
+			model = request.Model
+		}
+		c.OnUsage(model, response.Usage, response.Cost)
+	}
+	return response, err
+}
+
+// route выбирает провайдера для запроса по стратегии роутинга
+func (c *HybridAIClient) route(ctx context.Context, request *HybridChatRequest) (*HybridChatResponse, error) {
 	// Определяем стратегию роутинга
 	strategy := request.Strategy
 	if strategy == "" {
@@ -2,7 +2,11 @@ package ai
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 	"time"
+
+	"github.com/grik-ai/ricochet-task/pkg/providers"
 )
 
 // MockAIChains provides mock AI functionality for testing when model services are not available
@@ -417,6 +421,164 @@ func (m *MockAIChains) GenerateProgressComment(taskTitle, currentStatus, progres
 	return comment, nil
 }
 
+// mockRolePrompts are the instructions of the mock prompts for the model roles
+var mockRolePrompts = map[string]string{
+	"analyzer":   "Analyze the input. List its key points, the problems you find and the open questions, each as a short bullet.",
+	"summarizer": "Summarize the input in a few sentences. Keep every decision, number and name, drop repetition.",
+	"extractor":  "Extract the facts the next step needs from the input and return them as a JSON object with one field per fact.",
+	"critic":     "Review the input critically. List its mistakes, gaps and weak arguments, explain why each matters and suggest a fix. Say plainly if it is good as is.",
+	"refiner":    "Improve the input using the review it comes with. Fix every point raised, keep what works and return the full improved version.",
+	"creator":    "Create the requested result from the input. Follow its requirements exactly and return the result only.",
+	"integrator": "Combine the inputs into one consistent result. Resolve contradictions and say which source you followed.",
+}
+
+// SuggestStepPrompt writes a mock prompt from a template for the role of the step
+func (m *MockAIChains) SuggestStepPrompt(chainName, chainPurpose, role, stepDescription string, otherSteps []string) (string, error) {
+	instruction, ok := mockRolePrompts[strings.ToLower(role)]
+	if !ok {
+		instruction = "Work on the input and return the result only."
+	}
+
+	var b strings.Builder
+	if chainPurpose != "" {
+		fmt.Fprintf(&b, "You are a step of the \"%s\" chain, which %s.\n", chainName, strings.TrimSuffix(chainPurpose, "."))
+	} else {
+		fmt.Fprintf(&b, "You are a step of the \"%s\" chain.\n", chainName)
+	}
+	if stepDescription != "" {
+		fmt.Fprintf(&b, "Your task: %s.\n", strings.TrimSuffix(stepDescription, "."))
+	}
+	b.WriteString(instruction)
+	return b.String(), nil
+}
+
+// TriageTask suggests a mock classification from keywords of the task and
+// assigns the team member with the fewest open tasks
+func (m *MockAIChains) TriageTask(taskTitle, taskDescription string, labels []string, workload map[string]int) (*TriageSuggestion, error) {
+	text := strings.ToLower(taskTitle + " " + taskDescription)
+	suggestion := &TriageSuggestion{Priority: "medium", Type: "task", Labels: []string{}}
+
+	switch {
+	case containsAny(text, []string{"bug", "error", "crash", "fail", "broken", "exception"}):
+		suggestion.Type = "bug"
+	case containsAny(text, []string{"add ", "support", "implement", "feature"}):
+		suggestion.Type = "feature"
+	case containsAny(text, []string{"investigate", "research", "spike"}):
+		suggestion.Type = "research"
+	}
+
+	switch {
+	case containsAny(text, []string{"outage", "security", "data loss", "production down", "urgent"}):
+		suggestion.Priority = "critical"
+	case containsAny(text, []string{"crash", "prod", "customer", "blocker"}):
+		suggestion.Priority = "high"
+	case containsAny(text, []string{"typo", "cosmetic", "minor", "nice to have"}):
+		suggestion.Priority = "low"
+	}
+
+	for _, label := range labels {
+		if len(suggestion.Labels) < 3 && containsAny(text, []string{strings.ToLower(label)}) {
+			suggestion.Labels = append(suggestion.Labels, label)
+		}
+	}
+
+	members := make([]string, 0, len(workload))
+	for member := range workload {
+		members = append(members, member)
+	}
+	sort.Strings(members)
+	for _, member := range members {
+		if suggestion.Assignee == "" || workload[member] < workload[suggestion.Assignee] {
+			suggestion.Assignee = member
+		}
+	}
+
+	suggestion.Reason = fmt.Sprintf("Classified as %s with %s priority from keywords of the task", suggestion.Type, suggestion.Priority)
+	return suggestion, nil
+}
+
+// ExpandTask writes a mock description from a template for the kind of task
+func (m *MockAIChains) ExpandTask(taskTitle, taskDescription, taskType string) (*TaskExpansion, error) {
+	text := strings.ToLower(taskTitle + " " + taskDescription + " " + taskType)
+	expansion := &TaskExpansion{
+		Context: fmt.Sprintf("This task covers: %s.", taskTitle),
+		AcceptanceCriteria: []string{
+			fmt.Sprintf("%s is done as described", taskTitle),
+			"The change is covered by automated tests",
+			"Documentation is updated where behavior changes",
+		},
+		TechnicalNotes: []string{"Review related code and open tasks before starting"},
+	}
+
+	switch {
+	case containsAny(text, []string{"bug", "error", "crash", "fail", "broken", "exception"}):
+		expansion.Context = fmt.Sprintf("A defect was reported: %s. It needs to be reproduced and fixed.", taskTitle)
+		expansion.AcceptanceCriteria = []string{
+			"The issue is reproduced and its root cause identified",
+			"The issue no longer occurs in the reported scenario",
+			"A regression test covers the scenario",
+		}
+		expansion.TechnicalNotes = []string{"Check logs and recent changes around the affected area"}
+	case containsAny(text, []string{"research", "investigate", "spike"}):
+		expansion.Context = fmt.Sprintf("Research is needed: %s. The outcome is a decision, not production code.", taskTitle)
+		expansion.AcceptanceCriteria = []string{
+			"Options are compared with their trade-offs",
+			"A recommendation is written up and shared with the team",
+		}
+		expansion.TechnicalNotes = []string{"Timebox the research and list open questions"}
+	}
+
+	return expansion, nil
+}
+
+// SummarizeProjectStatus writes a mock status update from the counts and
+// groups of the project's tasks
+func (m *MockAIChains) SummarizeProjectStatus(status *providers.ProjectStatus) (*ProjectStatusSummary, error) {
+	summary := &ProjectStatusSummary{
+		Summary: fmt.Sprintf("%d of %d tasks of %s are completed (%d%%). %d are in progress, %d blocked and %d overdue.",
+			status.Stats.Completed, status.Stats.Total, status.ProjectID, status.PercentComplete(),
+			len(status.InProgress), len(status.Blocked), len(status.Overdue)),
+		Progress: fmt.Sprintf("%d tasks were completed since %s; %d are in progress.",
+			len(status.Completed), status.Since.Format("2006-01-02"), len(status.InProgress)),
+		Blockers:  []string{},
+		Risks:     []string{},
+		NextSteps: []string{},
+	}
+
+	for _, task := range firstTasks(status.Blocked, 5) {
+		blocker := fmt.Sprintf("%s %s is blocked", task.GetDisplayID(), task.Title)
+		if len(task.BlockedBy) > 0 {
+			blocker += " by " + strings.Join(task.BlockedBy, ", ")
+		}
+		summary.Blockers = append(summary.Blockers, blocker)
+	}
+	for _, task := range firstTasks(status.Overdue, 5) {
+		summary.Risks = append(summary.Risks, fmt.Sprintf("%s %s was due %s", task.GetDisplayID(), task.Title, task.DueDate.Format("2006-01-02")))
+	}
+	if unassigned := status.Stats.ByAssignee[providers.UnassignedKey]; unassigned > 0 {
+		summary.Risks = append(summary.Risks, fmt.Sprintf("%d tasks have no assignee", unassigned))
+	}
+
+	if len(status.Blocked) > 0 {
+		summary.NextSteps = append(summary.NextSteps, "Resolve the blockers of the blocked tasks")
+	}
+	if len(status.Overdue) > 0 {
+		summary.NextSteps = append(summary.NextSteps, "Replan or finish the overdue tasks")
+	}
+	for _, task := range firstTasks(status.InProgress, 3) {
+		summary.NextSteps = append(summary.NextSteps, fmt.Sprintf("Finish %s %s", task.GetDisplayID(), task.Title))
+	}
+
+	return summary, nil
+}
+
+func firstTasks(tasks []*providers.UniversalTask, n int) []*providers.UniversalTask {
+	if len(tasks) > n {
+		return tasks[:n]
+	}
+	return tasks
+}
+
 // Helper functions
 
 func containsAny(text string, keywords []string) bool {
@@ -60,6 +60,30 @@ type ChatResponse struct {
 	PromptTokens int         `json:"prompt_tokens"`
 }
 
+// APIError ошибка, которую вернул API провайдера
+type APIError struct {
+	Provider   Provider
+	StatusCode int
+	Body       string
+}
+
+// Error возвращает текст ошибки
+func (e *APIError) Error() string {
+	name := string(e.Provider)
+	switch e.Provider {
+	case ProviderOpenAI:
+		name = "OpenAI"
+	case ProviderClaude:
+		name = "Claude"
+	}
+	return fmt.Sprintf("%s API error: %s", name, e.Body)
+}
+
+// HTTPStatus возвращает HTTP-статус ответа
+func (e *APIError) HTTPStatus() int {
+	return e.StatusCode
+}
+
 // Client представляет клиент для работы с API
 type Client struct {
 	httpClient  *http.Client
@@ -244,7 +268,7 @@ func (s *ChatService) sendOpenAIRequest(req *ChatRequest, apiKey string) (*ChatR
 
 	// Проверяем статус-код
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("OpenAI API error: %s", string(respBody))
+		return nil, &APIError{Provider: ProviderOpenAI, StatusCode: resp.StatusCode, Body: string(respBody)}
 	}
 
 	// Парсим ответ
@@ -351,7 +375,7 @@ func (s *ChatService) sendClaudeRequest(req *ChatRequest, apiKey string) (*ChatR
 
 	// Проверяем статус-код
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("Claude API error: %s", string(respBody))
+		return nil, &APIError{Provider: ProviderClaude, StatusCode: resp.StatusCode, Body: string(respBody)}
 	}
 
 	// Парсим ответ
@@ -96,6 +96,34 @@ type Model struct {
 	Order       int        `json:"order"`       // Порядок модели в цепочке
 	Parameters  Parameters `json:"parameters"`  // Параметры запросов к модели
 	Temperature float64    `json:"temperature"` // Температура (креативность)
+
+	// FallbackModels запасные модели шага в порядке приоритета. Используются,
+	// если основная модель недоступна из-за временного сбоя (лимиты, 5xx).
+	FallbackModels []ModelName `json:"fallback_models,omitempty"`
+
+	// OutputSchema JSON-схема результата шага. Если задана, модель получает
+	// требование ответить JSON по схеме, а ответ проверяется перед передачей
+	// следующему шагу (см. StructuredOutput).
+	OutputSchema map[string]interface{} `json:"output_schema,omitempty"`
+}
+
+// WithFallback возвращает копию шага цепочки, выполняемую запасной моделью.
+// Тип модели берется из реестра; роль, промпт и параметры шага сохраняются.
+func (m Model) WithFallback(name ModelName) (Model, error) {
+	config, err := NewModelRegistry().GetModelByName(name)
+	if err != nil {
+		return Model{}, fmt.Errorf("unknown fallback model: %w", err)
+	}
+
+	fallback := m
+	fallback.Name = config.Name
+	fallback.Type = config.Type
+	fallback.FallbackModels = nil
+	if config.MaxTokens > 0 && fallback.MaxTokens > config.MaxTokens {
+		fallback.MaxTokens = config.MaxTokens
+	}
+
+	return fallback, nil
 }
 
 // Parameters настройки запросов к модели
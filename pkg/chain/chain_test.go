@@ -73,6 +73,39 @@ func TestModel(t *testing.T) {
 	assert.ElementsMatch(t, []string{"\n\n"}, model.Parameters.Stop)
 }
 
+// TestModelWithFallback тестирует подстановку запасной модели в шаг цепочки
+func TestModelWithFallback(t *testing.T) {
+	step := chain.Model{
+		ID:             "model-1",
+		Name:           chain.ModelNameGPT4,
+		Type:           chain.ModelTypeOpenAI,
+		Role:           chain.ModelRoleAnalyzer,
+		MaxTokens:      8000,
+		Prompt:         "Analyze the text.",
+		FallbackModels: []chain.ModelName{chain.ModelNameClaude3Sonnet},
+	}
+
+	fallback, err := step.WithFallback(chain.ModelNameClaude3Sonnet)
+	require.NoError(t, err)
+
+	// Меняются модель и провайдер, настройки шага сохраняются
+	assert.Equal(t, chain.ModelNameClaude3Sonnet, fallback.Name)
+	assert.Equal(t, chain.ModelTypeClaude, fallback.Type)
+	assert.Equal(t, "model-1", fallback.ID)
+	assert.Equal(t, chain.ModelRoleAnalyzer, fallback.Role)
+	assert.Equal(t, "Analyze the text.", fallback.Prompt)
+	assert.Empty(t, fallback.FallbackModels)
+	// Лимит ответа не превышает возможности запасной модели
+	assert.Equal(t, 4096, fallback.MaxTokens)
+
+	// Исходный шаг не изменяется
+	assert.Equal(t, chain.ModelNameGPT4, step.Name)
+	assert.Len(t, step.FallbackModels, 1)
+
+	_, err = step.WithFallback("unknown-model")
+	assert.Error(t, err)
+}
+
 // TestChain тестирует функциональность структуры Chain
 func TestChain(t *testing.T) {
 	// Создаем текущее время для тестирования
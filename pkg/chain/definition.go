@@ -0,0 +1,290 @@
+package chain
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gopkg.in/yaml.v3"
+)
+
+// DefinitionSchemaVersion версия формата файла определения цепочки
+const DefinitionSchemaVersion = 1
+
+// Definition переносимое определение цепочки: шаги, модели, промпты и
+// параметры без идентификаторов и дат хранилища. Предназначено для обмена
+// цепочками и хранения в системе контроля версий.
+type Definition struct {
+	SchemaVersion int                 `json:"schema_version" yaml:"schema_version"`
+	Name          string              `json:"name" yaml:"name"`
+	Description   string              `json:"description,omitempty" yaml:"description,omitempty"`
+	Tags          []string            `json:"tags,omitempty" yaml:"tags,omitempty"`
+	Metadata      *DefinitionMetadata `json:"metadata,omitempty" yaml:"metadata,omitempty"`
+	Steps         []StepDefinition    `json:"steps" yaml:"steps"`
+}
+
+// DefinitionMetadata метаданные цепочки в определении
+type DefinitionMetadata struct {
+	Author      string                 `json:"author,omitempty" yaml:"author,omitempty"`
+	Version     string                 `json:"version,omitempty" yaml:"version,omitempty"`
+	UseCase     string                 `json:"use_case,omitempty" yaml:"use_case,omitempty"`
+	InputFormat string                 `json:"input_format,omitempty" yaml:"input_format,omitempty"`
+	Custom      map[string]interface{} `json:"custom,omitempty" yaml:"custom,omitempty"`
+}
+
+// StepDefinition шаг цепочки в определении. Type можно не указывать для
+// моделей из реестра, он берется из реестра.
+type StepDefinition struct {
+	Model       ModelName      `json:"model" yaml:"model"`
+	Type        ModelType      `json:"type,omitempty" yaml:"type,omitempty"`
+	Role        ModelRole      `json:"role" yaml:"role"`
+	Prompt      string         `json:"prompt,omitempty" yaml:"prompt,omitempty"`
+	MaxTokens   int            `json:"max_tokens,omitempty" yaml:"max_tokens,omitempty"`
+	Temperature float64        `json:"temperature" yaml:"temperature"`
+	Parameters  StepParameters `json:"parameters,omitempty" yaml:"parameters,omitempty"`
+	Fallbacks   []ModelName    `json:"fallbacks,omitempty" yaml:"fallbacks,omitempty"`
+}
+
+// StepParameters параметры запросов шага, кроме температуры
+type StepParameters struct {
+	TopP             float64  `json:"top_p,omitempty" yaml:"top_p,omitempty"`
+	FrequencyPenalty float64  `json:"frequency_penalty,omitempty" yaml:"frequency_penalty,omitempty"`
+	PresencePenalty  float64  `json:"presence_penalty,omitempty" yaml:"presence_penalty,omitempty"`
+	Stop             []string `json:"stop,omitempty" yaml:"stop,omitempty"`
+
+	// OutputSchema JSON-схема результата шага, см. Model.OutputSchema
+	OutputSchema map[string]interface{} `json:"output_schema,omitempty" yaml:"output_schema,omitempty"`
+}
+
+// knownRoles роли, которые может иметь шаг цепочки
+var knownRoles = []ModelRole{
+	ModelRoleAnalyzer, ModelRoleSummarizer, ModelRoleIntegrator,
+	ModelRoleExtractor, ModelRoleOrganizer, ModelRoleEvaluator,
+}
+
+// knownTypes типы моделей, которые может иметь шаг цепочки
+var knownTypes = []ModelType{
+	ModelTypeOpenAI, ModelTypeClaude, ModelTypeDeepSeek,
+	ModelTypeGrok, ModelTypeLlama, ModelTypeMistral,
+}
+
+// NewDefinition создает определение цепочки с шагами в порядке выполнения
+func NewDefinition(c Chain) *Definition {
+	models := append([]Model(nil), c.Models...)
+	sort.SliceStable(models, func(i, j int) bool { return models[i].Order < models[j].Order })
+
+	definition := &Definition{
+		SchemaVersion: DefinitionSchemaVersion,
+		Name:          c.Name,
+		Description:   c.Description,
+		Tags:          c.Tags,
+		Steps:         make([]StepDefinition, 0, len(models)),
+	}
+
+	meta := c.Metadata
+	if meta.Author != "" || meta.Version != "" || meta.UseCase != "" || meta.InputFormat != "" || len(meta.Custom) > 0 {
+		definition.Metadata = &DefinitionMetadata{
+			Author:      meta.Author,
+			Version:     meta.Version,
+			UseCase:     meta.UseCase,
+			InputFormat: meta.InputFormat,
+			Custom:      meta.Custom,
+		}
+	}
+
+	for _, model := range models {
+		definition.Steps = append(definition.Steps, StepDefinition{
+			Model:       model.Name,
+			Type:        model.Type,
+			Role:        model.Role,
+			Prompt:      model.Prompt,
+			MaxTokens:   model.MaxTokens,
+			Temperature: model.Temperature,
+			Parameters: StepParameters{
+				TopP:             model.Parameters.TopP,
+				FrequencyPenalty: model.Parameters.FrequencyPenalty,
+				PresencePenalty:  model.Parameters.PresencePenalty,
+				Stop:             model.Parameters.Stop,
+				OutputSchema:     model.OutputSchema,
+			},
+			Fallbacks: model.FallbackModels,
+		})
+	}
+
+	return definition
+}
+
+// Marshal сериализует определение в YAML или, при format "json", в JSON
+func (d *Definition) Marshal(format string) ([]byte, error) {
+	if format == "json" {
+		data, err := json.MarshalIndent(d, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return append(data, '\n'), nil
+	}
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(d); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// ParseDefinition разбирает определение цепочки из YAML или JSON. Неизвестные
+// поля считаются ошибкой, чтобы опечатки не терялись молча.
+func ParseDefinition(data []byte) (*Definition, error) {
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+
+	var definition Definition
+	if err := decoder.Decode(&definition); err != nil {
+		return nil, fmt.Errorf("неверный формат определения цепочки: %w", err)
+	}
+	return &definition, nil
+}
+
+// ToChain проверяет определение и создает по нему новую цепочку. Тип модели
+// берется из реестра моделей; для моделей вне реестра нужен явный type, и
+// о них возвращаются предупреждения.
+func (d *Definition) ToChain() (Chain, []string, error) {
+	switch {
+	case d.SchemaVersion == 0:
+		return Chain{}, nil, fmt.Errorf("не указана версия формата (schema_version)")
+	case d.SchemaVersion > DefinitionSchemaVersion:
+		return Chain{}, nil, fmt.Errorf("версия формата %d не поддерживается, обновите ricochet (поддерживается до %d)", d.SchemaVersion, DefinitionSchemaVersion)
+	}
+	if d.Name == "" {
+		return Chain{}, nil, fmt.Errorf("не указано имя цепочки (name)")
+	}
+	if len(d.Steps) == 0 {
+		return Chain{}, nil, fmt.Errorf("цепочка не содержит шагов (steps)")
+	}
+
+	now := time.Now()
+	c := Chain{
+		ID:          uuid.New().String(),
+		Name:        d.Name,
+		Description: d.Description,
+		Tags:        d.Tags,
+		Models:      make([]Model, 0, len(d.Steps)),
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if d.Metadata != nil {
+		c.Metadata = Metadata{
+			Author:      d.Metadata.Author,
+			Version:     d.Metadata.Version,
+			UseCase:     d.Metadata.UseCase,
+			InputFormat: d.Metadata.InputFormat,
+			Custom:      d.Metadata.Custom,
+		}
+	}
+
+	registry := NewModelRegistry()
+	var warnings []string
+	for i, step := range d.Steps {
+		number := i + 1
+		if step.Model == "" {
+			return Chain{}, nil, fmt.Errorf("шаг %d: не указана модель (model)", number)
+		}
+		if !containsRole(step.Role) {
+			return Chain{}, nil, fmt.Errorf("шаг %d: неизвестная роль '%s'. Допустимые значения: %s", number, step.Role, joinRoles())
+		}
+		if step.Temperature < 0 || step.Temperature > 2 {
+			return Chain{}, nil, fmt.Errorf("шаг %d: температура %.2f вне диапазона 0-2", number, step.Temperature)
+		}
+		if step.MaxTokens < 0 {
+			return Chain{}, nil, fmt.Errorf("шаг %d: max_tokens не может быть отрицательным", number)
+		}
+
+		modelType := step.Type
+		if config, err := registry.GetModelByName(step.Model); err == nil {
+			if modelType != "" && modelType != config.Type {
+				warnings = append(warnings, fmt.Sprintf("шаг %d: модель %s относится к %s, а не к %s; используется %s", number, step.Model, config.Type, modelType, config.Type))
+			}
+			modelType = config.Type
+		} else {
+			if modelType == "" {
+				return Chain{}, nil, fmt.Errorf("шаг %d: модель %s не найдена в реестре, укажите ее тип (type)", number, step.Model)
+			}
+			if !containsType(modelType) {
+				return Chain{}, nil, fmt.Errorf("шаг %d: неизвестный тип модели '%s'", number, modelType)
+			}
+			warnings = append(warnings, fmt.Sprintf("шаг %d: модель %s не найдена в реестре, используется как модель %s", number, step.Model, modelType))
+		}
+
+		model := Model{
+			ID:          uuid.New().String(),
+			Name:        step.Model,
+			Type:        modelType,
+			Role:        step.Role,
+			MaxTokens:   step.MaxTokens,
+			Prompt:      step.Prompt,
+			Order:       i,
+			Temperature: step.Temperature,
+			Parameters: Parameters{
+				Temperature:      step.Temperature,
+				TopP:             step.Parameters.TopP,
+				FrequencyPenalty: step.Parameters.FrequencyPenalty,
+				PresencePenalty:  step.Parameters.PresencePenalty,
+				Stop:             step.Parameters.Stop,
+			},
+		}
+		if model.Parameters.Stop == nil {
+			model.Parameters.Stop = []string{}
+		}
+		if step.Parameters.OutputSchema != nil {
+			schema, err := ParseOutputSchema(step.Parameters.OutputSchema)
+			if err != nil {
+				return Chain{}, nil, fmt.Errorf("шаг %d: %w", number, err)
+			}
+			model.OutputSchema = schema
+		}
+		for _, fallback := range step.Fallbacks {
+			if _, err := model.WithFallback(fallback); err != nil {
+				return Chain{}, nil, fmt.Errorf("шаг %d: неизвестная запасная модель '%s'", number, fallback)
+			}
+			model.FallbackModels = append(model.FallbackModels, fallback)
+		}
+
+		c.Models = append(c.Models, model)
+	}
+
+	return c, warnings, nil
+}
+
+func containsRole(role ModelRole) bool {
+	for _, known := range knownRoles {
+		if role == known {
+			return true
+		}
+	}
+	return false
+}
+
+func containsType(modelType ModelType) bool {
+	for _, known := range knownTypes {
+		if modelType == known {
+			return true
+		}
+	}
+	return false
+}
+
+func joinRoles() string {
+	roles := make([]string, len(knownRoles))
+	for i, role := range knownRoles {
+		roles[i] = string(role)
+	}
+	return strings.Join(roles, ", ")
+}
@@ -0,0 +1,151 @@
+package chain_test
+
+import (
+	"testing"
+
+	"github.com/grik-ai/ricochet-task/pkg/chain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestDefinitionRoundTrip проверяет, что цепочка переживает экспорт и импорт
+func TestDefinitionRoundTrip(t *testing.T) {
+	original := chain.Chain{
+		ID:          "chain-1",
+		Name:        "Release notes",
+		Description: "Writes and reviews release notes",
+		Tags:        []string{"docs"},
+		Models: []chain.Model{
+			{
+				ID:          "model-2",
+				Name:        chain.ModelNameClaude3Sonnet,
+				Type:        chain.ModelTypeClaude,
+				Role:        chain.ModelRoleEvaluator,
+				MaxTokens:   800,
+				Prompt:      "Review the draft.\nList every gap.",
+				Order:       1,
+				Temperature: 0.2,
+				Parameters:  chain.Parameters{Temperature: 0.2, TopP: 0.9, Stop: []string{}},
+				OutputSchema: map[string]interface{}{
+					"type":     "object",
+					"required": []interface{}{"gaps"},
+					"properties": map[string]interface{}{
+						"gaps": map[string]interface{}{"type": "array", "minItems": float64(1)},
+					},
+				},
+			},
+			{
+				ID:             "model-1",
+				Name:           chain.ModelNameGPT4,
+				Type:           chain.ModelTypeOpenAI,
+				Role:           chain.ModelRoleAnalyzer,
+				MaxTokens:      1000,
+				Prompt:         "Draft release notes from the changelog.",
+				Order:          0,
+				Temperature:    0.7,
+				Parameters:     chain.Parameters{Temperature: 0.7, TopP: 0.9, Stop: []string{"END"}},
+				FallbackModels: []chain.ModelName{chain.ModelNameGPT4Turbo},
+			},
+		},
+		Metadata: chain.Metadata{Author: "docs team", UseCase: "release"},
+	}
+
+	for _, format := range []string{"yaml", "json"} {
+		t.Run(format, func(t *testing.T) {
+			data, err := chain.NewDefinition(original).Marshal(format)
+			require.NoError(t, err)
+
+			definition, err := chain.ParseDefinition(data)
+			require.NoError(t, err)
+			assert.Equal(t, chain.DefinitionSchemaVersion, definition.SchemaVersion)
+
+			imported, warnings, err := definition.ToChain()
+			require.NoError(t, err)
+			assert.Empty(t, warnings)
+
+			assert.NotEqual(t, original.ID, imported.ID)
+			assert.Equal(t, original.Name, imported.Name)
+			assert.Equal(t, original.Description, imported.Description)
+			assert.Equal(t, original.Tags, imported.Tags)
+			assert.Equal(t, "docs team", imported.Metadata.Author)
+			require.Len(t, imported.Models, 2)
+
+			// Шаги идут в порядке выполнения
+			first, second := imported.Models[0], imported.Models[1]
+			assert.Equal(t, chain.ModelNameGPT4, first.Name)
+			assert.Equal(t, chain.ModelTypeOpenAI, first.Type)
+			assert.Equal(t, 0, first.Order)
+			assert.Equal(t, []string{"END"}, first.Parameters.Stop)
+			assert.Equal(t, []chain.ModelName{chain.ModelNameGPT4Turbo}, first.FallbackModels)
+			assert.Equal(t, chain.ModelNameClaude3Sonnet, second.Name)
+			assert.Equal(t, "Review the draft.\nList every gap.", second.Prompt)
+			assert.Equal(t, 0.2, second.Temperature)
+			assert.Equal(t, 0.2, second.Parameters.Temperature)
+			assert.Equal(t, 0.9, second.Parameters.TopP)
+			assert.Equal(t, 800, second.MaxTokens)
+			assert.Equal(t, 1, second.Order)
+			assert.Equal(t, original.Models[0].OutputSchema, second.OutputSchema)
+			assert.Nil(t, first.OutputSchema)
+		})
+	}
+}
+
+// TestDefinitionModelMapping проверяет определение типа модели по реестру
+func TestDefinitionModelMapping(t *testing.T) {
+	definition, err := chain.ParseDefinition([]byte(`
+schema_version: 1
+name: Mapping
+steps:
+  - model: claude-3-haiku
+    role: analyzer
+    temperature: 0.5
+  - model: gpt-4
+    type: claude
+    role: summarizer
+    temperature: 0.5
+  - model: my-finetune
+    type: openai
+    role: evaluator
+    temperature: 0.5
+`))
+	require.NoError(t, err)
+
+	c, warnings, err := definition.ToChain()
+	require.NoError(t, err)
+	assert.Equal(t, chain.ModelTypeClaude, c.Models[0].Type)
+	assert.Equal(t, chain.ModelTypeOpenAI, c.Models[1].Type)
+	assert.Equal(t, chain.ModelTypeOpenAI, c.Models[2].Type)
+	assert.Len(t, warnings, 2)
+}
+
+// TestDefinitionValidation проверяет ошибки в определениях
+func TestDefinitionValidation(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{"no version", "name: x\nsteps: [{model: gpt-4, role: analyzer}]", "версия"},
+		{"newer version", "schema_version: 99\nname: x\nsteps: [{model: gpt-4, role: analyzer}]", "не поддерживается"},
+		{"no name", "schema_version: 1\nsteps: [{model: gpt-4, role: analyzer}]", "имя"},
+		{"no steps", "schema_version: 1\nname: x", "шагов"},
+		{"unknown role", "schema_version: 1\nname: x\nsteps: [{model: gpt-4, role: critic}]", "роль"},
+		{"unknown model", "schema_version: 1\nname: x\nsteps: [{model: my-model, role: analyzer}]", "type"},
+		{"unknown fallback", "schema_version: 1\nname: x\nsteps: [{model: gpt-4, role: analyzer, fallbacks: [nope]}]", "запасная"},
+		{"bad output schema", "schema_version: 1\nname: x\nsteps: [{model: gpt-4, role: analyzer, parameters: {output_schema: {type: record}}}]", "схема"},
+		{"bad temperature", "schema_version: 1\nname: x\nsteps: [{model: gpt-4, role: analyzer, temperature: 3}]", "температура"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			definition, err := chain.ParseDefinition([]byte(tt.data))
+			require.NoError(t, err)
+			_, _, err = definition.ToChain()
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.want)
+		})
+	}
+
+	_, err := chain.ParseDefinition([]byte("schema_version: 1\nname: x\nstep: []"))
+	assert.Error(t, err, "неизвестные поля должны отклоняться")
+}
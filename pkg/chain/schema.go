@@ -0,0 +1,381 @@
+package chain
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// OutputSchemaParameter параметр шага конструктора цепочки с JSON-схемой
+// результата шага
+const OutputSchemaParameter = "output_schema"
+
+// schemaTypes типы значений JSON-схемы
+var schemaTypes = []string{"object", "array", "string", "number", "integer", "boolean", "null"}
+
+// SchemaError результат шага не соответствует JSON-схеме
+type SchemaError struct {
+	Problems []string // Нарушения схемы с путем к значению, например "$.items[0].title: обязательное поле"
+}
+
+func (e *SchemaError) Error() string {
+	return "результат не соответствует схеме: " + strings.Join(e.Problems, "; ")
+}
+
+// ParseOutputSchema приводит JSON-схему результата к виду, в котором ее
+// хранит шаг цепочки, и проверяет ее через CheckOutputSchema. Схема может быть
+// объектом (в том числе прочитанным из YAML) или строкой с JSON.
+func ParseOutputSchema(value interface{}) (map[string]interface{}, error) {
+	data, ok := value.(string)
+	if !ok {
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return nil, fmt.Errorf("некорректная схема результата: %w", err)
+		}
+		data = string(encoded)
+	}
+
+	var schema map[string]interface{}
+	if err := json.Unmarshal([]byte(data), &schema); err != nil {
+		return nil, fmt.Errorf("схема результата должна быть JSON-объектом: %w", err)
+	}
+	if err := CheckOutputSchema(schema); err != nil {
+		return nil, fmt.Errorf("некорректная схема результата: %w", err)
+	}
+	return schema, nil
+}
+
+// CheckOutputSchema проверяет, что схема корректна и использует только
+// поддерживаемые ключевые слова: type, properties, required,
+// additionalProperties, items, enum, minimum, maximum, minLength, maxLength,
+// minItems и maxItems. Остальные ключевые слова (title, description и т.п.)
+// не проверяются.
+func CheckOutputSchema(schema map[string]interface{}) error {
+	return checkSchema(schema, "$")
+}
+
+func checkSchema(schema map[string]interface{}, path string) error {
+	if typeValue, ok := schema["type"]; ok {
+		types, err := schemaTypeList(typeValue)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		for _, name := range types {
+			if !containsString(schemaTypes, name) {
+				return fmt.Errorf("%s: неизвестный тип '%s'. Допустимые значения: %s", path, name, strings.Join(schemaTypes, ", "))
+			}
+		}
+	}
+
+	if value, ok := schema["properties"]; ok {
+		properties, ok := value.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("%s: properties должен быть объектом", path)
+		}
+		for name, property := range properties {
+			propertySchema, ok := property.(map[string]interface{})
+			if !ok {
+				return fmt.Errorf("%s.%s: схема свойства должна быть объектом", path, name)
+			}
+			if err := checkSchema(propertySchema, path+"."+name); err != nil {
+				return err
+			}
+		}
+	}
+
+	if value, ok := schema["required"]; ok {
+		if _, err := stringList(value); err != nil {
+			return fmt.Errorf("%s: required должен быть списком строк", path)
+		}
+	}
+
+	switch value := schema["additionalProperties"].(type) {
+	case nil, bool:
+	case map[string]interface{}:
+		if err := checkSchema(value, path+".*"); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("%s: additionalProperties должен быть true, false или схемой", path)
+	}
+
+	if value, ok := schema["items"]; ok {
+		items, ok := value.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("%s: items должен быть схемой", path)
+		}
+		if err := checkSchema(items, path+"[]"); err != nil {
+			return err
+		}
+	}
+
+	if value, ok := schema["enum"]; ok {
+		if values, ok := value.([]interface{}); !ok || len(values) == 0 {
+			return fmt.Errorf("%s: enum должен быть непустым списком", path)
+		}
+	}
+
+	for _, keyword := range []string{"minimum", "maximum", "minLength", "maxLength", "minItems", "maxItems"} {
+		if value, ok := schema[keyword]; ok {
+			if _, ok := value.(float64); !ok {
+				return fmt.Errorf("%s: %s должен быть числом", path, keyword)
+			}
+		}
+	}
+
+	return nil
+}
+
+// ValidateOutput проверяет значение, разобранное из JSON, по схеме. Все
+// найденные нарушения возвращаются одной ошибкой *SchemaError.
+func ValidateOutput(schema map[string]interface{}, value interface{}) error {
+	var problems []string
+	validateValue(schema, value, "$", &problems)
+	if len(problems) > 0 {
+		return &SchemaError{Problems: problems}
+	}
+	return nil
+}
+
+func validateValue(schema map[string]interface{}, value interface{}, path string, problems *[]string) {
+	report := func(format string, args ...interface{}) {
+		*problems = append(*problems, path+": "+fmt.Sprintf(format, args...))
+	}
+
+	if typeValue, ok := schema["type"]; ok {
+		types, _ := schemaTypeList(typeValue)
+		if !matchesAnyType(value, types) {
+			report("ожидается %s, получено %s", strings.Join(types, " или "), jsonTypeName(value))
+			return
+		}
+	}
+
+	if values, ok := schema["enum"].([]interface{}); ok && !containsValue(values, value) {
+		report("значение должно быть одним из %s", formatJSON(values))
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		properties, _ := schema["properties"].(map[string]interface{})
+		required, _ := stringList(schema["required"])
+		for _, name := range required {
+			if _, ok := v[name]; !ok {
+				*problems = append(*problems, fmt.Sprintf("%s.%s: обязательное поле", path, name))
+			}
+		}
+
+		names := make([]string, 0, len(v))
+		for name := range v {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if propertySchema, ok := properties[name].(map[string]interface{}); ok {
+				validateValue(propertySchema, v[name], path+"."+name, problems)
+				continue
+			}
+			switch additional := schema["additionalProperties"].(type) {
+			case bool:
+				if !additional {
+					*problems = append(*problems, fmt.Sprintf("%s.%s: поле не предусмотрено схемой", path, name))
+				}
+			case map[string]interface{}:
+				validateValue(additional, v[name], path+"."+name, problems)
+			}
+		}
+
+	case []interface{}:
+		if minItems, ok := schema["minItems"].(float64); ok && float64(len(v)) < minItems {
+			report("нужно не меньше %v элементов, получено %d", minItems, len(v))
+		}
+		if maxItems, ok := schema["maxItems"].(float64); ok && float64(len(v)) > maxItems {
+			report("нужно не больше %v элементов, получено %d", maxItems, len(v))
+		}
+		if items, ok := schema["items"].(map[string]interface{}); ok {
+			for i, item := range v {
+				validateValue(items, item, fmt.Sprintf("%s[%d]", path, i), problems)
+			}
+		}
+
+	case string:
+		length := len([]rune(v))
+		if minLength, ok := schema["minLength"].(float64); ok && float64(length) < minLength {
+			report("строка короче %v символов", minLength)
+		}
+		if maxLength, ok := schema["maxLength"].(float64); ok && float64(length) > maxLength {
+			report("строка длиннее %v символов", maxLength)
+		}
+
+	case float64:
+		if minimum, ok := schema["minimum"].(float64); ok && v < minimum {
+			report("значение меньше %v", minimum)
+		}
+		if maximum, ok := schema["maximum"].(float64); ok && v > maximum {
+			report("значение больше %v", maximum)
+		}
+	}
+}
+
+// ParseStructuredOutput разбирает JSON из ответа модели. Ответ может быть
+// обернут в блок кода Markdown или окружен текстом: тогда берется фрагмент
+// от первой открывающей до последней закрывающей скобки.
+func ParseStructuredOutput(output string) (interface{}, error) {
+	text := strings.TrimSpace(output)
+	if strings.HasPrefix(text, "```") {
+		text = strings.TrimPrefix(text, "```")
+		if newline := strings.IndexByte(text, '\n'); newline >= 0 {
+			text = text[newline+1:] // Язык блока, например json
+		}
+		text = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(text), "```"))
+	}
+
+	var value interface{}
+	err := json.Unmarshal([]byte(text), &value)
+	if err == nil {
+		return value, nil
+	}
+
+	start := strings.IndexAny(text, "{[")
+	end := strings.LastIndexAny(text, "}]")
+	if start >= 0 && end > start {
+		if json.Unmarshal([]byte(text[start:end+1]), &value) == nil {
+			return value, nil
+		}
+	}
+
+	return nil, fmt.Errorf("ответ не является JSON: %w", err)
+}
+
+// StructuredOutput разбирает ответ модели и проверяет его по схеме.
+// Возвращает JSON без окружающего текста, готовый для следующего шага.
+func StructuredOutput(schema map[string]interface{}, output string) (string, error) {
+	value, err := ParseStructuredOutput(output)
+	if err != nil {
+		return "", err
+	}
+	if err := ValidateOutput(schema, value); err != nil {
+		return "", err
+	}
+
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("ошибка сериализации результата: %w", err)
+	}
+	return string(data), nil
+}
+
+// StructuredPrompt дополняет системный промпт шага требованием ответить JSON
+// по схеме
+func StructuredPrompt(prompt string, schema map[string]interface{}) string {
+	instructions := "Ответь только JSON-значением, соответствующим JSON-схеме ниже, " +
+		"без пояснений и без блока кода Markdown.\n\nJSON-схема:\n"
+	if data, err := json.MarshalIndent(schema, "", "  "); err == nil {
+		instructions += string(data)
+	} else {
+		instructions += formatJSON(schema)
+	}
+	if strings.TrimSpace(prompt) == "" {
+		return instructions
+	}
+	return prompt + "\n\n" + instructions
+}
+
+// RepairInput вход повторного запроса, когда ответ не прошел проверку схемы:
+// исходный вход, прежний ответ и найденные ошибки
+func RepairInput(input, output string, err error) string {
+	return "Предыдущий ответ не прошел проверку JSON-схемы: " + err.Error() + "\n\n" +
+		"Предыдущий ответ:\n" + output + "\n\n" +
+		"Исправь ответ и верни только JSON, соответствующий схеме. Исходные данные:\n" + input
+}
+
+func schemaTypeList(value interface{}) ([]string, error) {
+	if name, ok := value.(string); ok {
+		return []string{name}, nil
+	}
+	types, err := stringList(value)
+	if err != nil || len(types) == 0 {
+		return nil, fmt.Errorf("type должен быть строкой или списком строк")
+	}
+	return types, nil
+}
+
+func stringList(value interface{}) ([]string, error) {
+	if value == nil {
+		return nil, nil
+	}
+	items, ok := value.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("ожидается список строк")
+	}
+	list := make([]string, 0, len(items))
+	for _, item := range items {
+		text, ok := item.(string)
+		if !ok {
+			return nil, fmt.Errorf("ожидается список строк")
+		}
+		list = append(list, text)
+	}
+	return list, nil
+}
+
+func matchesAnyType(value interface{}, types []string) bool {
+	for _, name := range types {
+		if name == "integer" {
+			if number, ok := value.(float64); ok && number == math.Trunc(number) {
+				return true
+			}
+			continue
+		}
+		if name == jsonTypeName(value) {
+			return true
+		}
+	}
+	return false
+}
+
+func jsonTypeName(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case string:
+		return "string"
+	case float64:
+		return "number"
+	case bool:
+		return "boolean"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+func containsValue(values []interface{}, value interface{}) bool {
+	encoded := formatJSON(value)
+	for _, candidate := range values {
+		if formatJSON(candidate) == encoded {
+			return true
+		}
+	}
+	return false
+}
+
+func containsString(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
+
+func formatJSON(value interface{}) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
@@ -0,0 +1,107 @@
+package chain_test
+
+import (
+	"testing"
+
+	"github.com/grik-ai/ricochet-task/pkg/chain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ticketSchema схема результата шага извлечения задач из текста
+func ticketSchema(t *testing.T) map[string]interface{} {
+	t.Helper()
+
+	schema, err := chain.ParseOutputSchema(`{
+		"type": "object",
+		"required": ["tickets"],
+		"additionalProperties": false,
+		"properties": {
+			"tickets": {
+				"type": "array",
+				"minItems": 1,
+				"items": {
+					"type": "object",
+					"required": ["title", "priority"],
+					"properties": {
+						"title": {"type": "string", "minLength": 3},
+						"priority": {"enum": ["low", "medium", "high"]},
+						"estimate": {"type": ["integer", "null"], "minimum": 0}
+					}
+				}
+			}
+		}
+	}`)
+	require.NoError(t, err)
+	return schema
+}
+
+// TestStructuredOutput проверяет разбор и проверку ответа модели по схеме
+func TestStructuredOutput(t *testing.T) {
+	schema := ticketSchema(t)
+
+	t.Run("valid JSON in a code block", func(t *testing.T) {
+		output, err := chain.StructuredOutput(schema, "```json\n{\"tickets\": [{\"title\": \"Fix login\", \"priority\": \"high\", \"estimate\": 3}]}\n```")
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"tickets": [{"title": "Fix login", "priority": "high", "estimate": 3}]}`, output)
+	})
+
+	t.Run("JSON surrounded by prose", func(t *testing.T) {
+		output, err := chain.StructuredOutput(schema, `Конечно! {"tickets": [{"title": "Add export", "priority": "low", "estimate": null}]} Готово.`)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"tickets": [{"title": "Add export", "priority": "low", "estimate": null}]}`, output)
+	})
+
+	t.Run("prose only", func(t *testing.T) {
+		_, err := chain.StructuredOutput(schema, "Я нашел две задачи: исправить вход и добавить экспорт.")
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "не является JSON")
+	})
+
+	t.Run("schema violations are all reported", func(t *testing.T) {
+		_, err := chain.StructuredOutput(schema, `{"tickets": [{"title": "Go", "priority": "urgent", "estimate": 1.5}, {"priority": "low"}], "note": "x"}`)
+		var schemaErr *chain.SchemaError
+		require.ErrorAs(t, err, &schemaErr)
+		assert.ElementsMatch(t, []string{
+			"$.tickets[0].estimate: ожидается integer или null, получено number",
+			`$.tickets[0].priority: значение должно быть одним из ["low","medium","high"]`,
+			"$.tickets[0].title: строка короче 3 символов",
+			"$.tickets[1].title: обязательное поле",
+			"$.note: поле не предусмотрено схемой",
+		}, schemaErr.Problems)
+	})
+
+	t.Run("empty array", func(t *testing.T) {
+		_, err := chain.StructuredOutput(schema, `{"tickets": []}`)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "$.tickets: нужно не меньше 1 элементов")
+	})
+}
+
+// TestParseOutputSchema проверяет разбор и проверку самой схемы
+func TestParseOutputSchema(t *testing.T) {
+	// Числа из YAML приводятся к виду JSON
+	schema, err := chain.ParseOutputSchema(map[string]interface{}{"type": "array", "maxItems": 2})
+	require.NoError(t, err)
+	assert.Equal(t, float64(2), schema["maxItems"])
+
+	tests := []struct {
+		name   string
+		schema string
+		want   string
+	}{
+		{"not an object", `["object"]`, "JSON-объектом"},
+		{"unknown type", `{"type": "record"}`, "неизвестный тип"},
+		{"nested unknown type", `{"properties": {"id": {"type": "uuid"}}}`, "$.id"},
+		{"bad required", `{"required": "id"}`, "required"},
+		{"empty enum", `{"enum": []}`, "enum"},
+		{"bad minimum", `{"minimum": "0"}`, "minimum"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := chain.ParseOutputSchema(tt.schema)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.want)
+		})
+	}
+}
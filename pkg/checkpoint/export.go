@@ -0,0 +1,123 @@
+package checkpoint
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ExportSchemaVersion версия формата файла экспорта чекпоинта
+const ExportSchemaVersion = 1
+
+// ChainInfo сведения о цепочке, для которой создан чекпоинт. По ним при импорте
+// проверяется, что локальная цепочка совпадает с цепочкой отправителя.
+type ChainInfo struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name,omitempty"`
+	Version   string    `json:"version,omitempty"`
+	UpdatedAt time.Time `json:"updated_at,omitempty"`
+	Models    []string  `json:"models,omitempty"` // Имена моделей по порядку
+}
+
+// Export файл экспорта чекпоинта. Содержимое чекпоинта всегда хранится в самом
+// файле, даже если в хранилище оно вынесено в отдельный файл.
+type Export struct {
+	SchemaVersion int        `json:"schema_version"`
+	ExportedAt    time.Time  `json:"exported_at"`
+	Chain         *ChainInfo `json:"chain,omitempty"` // Пусто, если цепочка не найдена
+	Checkpoint    Checkpoint `json:"checkpoint"`
+}
+
+// NewExport готовит чекпоинт к экспорту. Содержимое должно быть загружено,
+// например через Store.Get.
+func NewExport(cp Checkpoint, chainInfo *ChainInfo) *Export {
+	cp.ContentPath = ""
+	cp.StorageType = ""
+	return &Export{
+		SchemaVersion: ExportSchemaVersion,
+		ExportedAt:    time.Now(),
+		Chain:         chainInfo,
+		Checkpoint:    cp,
+	}
+}
+
+// ParseExport разбирает и проверяет файл экспорта чекпоинта
+func ParseExport(data []byte) (*Export, error) {
+	var export Export
+	if err := json.Unmarshal(data, &export); err != nil {
+		return nil, fmt.Errorf("некорректный JSON: %w", err)
+	}
+	if err := export.Validate(); err != nil {
+		return nil, err
+	}
+	return &export, nil
+}
+
+// Validate проверяет версию формата и обязательные поля чекпоинта
+func (e *Export) Validate() error {
+	switch {
+	case e.SchemaVersion == 0:
+		return fmt.Errorf("не указана версия формата (schema_version)")
+	case e.SchemaVersion > ExportSchemaVersion:
+		return fmt.Errorf("версия формата %d не поддерживается, обновите ricochet (поддерживается до %d)", e.SchemaVersion, ExportSchemaVersion)
+	}
+
+	cp := e.Checkpoint
+	var missing []string
+	if cp.ID == "" {
+		missing = append(missing, "checkpoint.id")
+	}
+	if cp.ChainID == "" {
+		missing = append(missing, "checkpoint.chain_id")
+	}
+	if cp.Type == "" {
+		missing = append(missing, "checkpoint.type")
+	}
+	if cp.Content == "" {
+		missing = append(missing, "checkpoint.content")
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("не заполнены обязательные поля: %s", strings.Join(missing, ", "))
+	}
+
+	if !isKnownType(cp.Type) {
+		return fmt.Errorf("неизвестный тип чекпоинта '%s'", cp.Type)
+	}
+	if e.Chain != nil && e.Chain.ID != "" && e.Chain.ID != cp.ChainID {
+		return fmt.Errorf("ID цепочки в сведениях (%s) не совпадает с ID цепочки чекпоинта (%s)", e.Chain.ID, cp.ChainID)
+	}
+	return nil
+}
+
+// CompareChain сравнивает цепочку отправителя с локальной и возвращает
+// предупреждения о расхождениях. local равен nil, если цепочки нет локально.
+func (e *Export) CompareChain(local *ChainInfo) []string {
+	if local == nil {
+		return []string{fmt.Sprintf("цепочка %s не найдена локально, совместимость не проверена", e.Checkpoint.ChainID)}
+	}
+	if e.Chain == nil {
+		return []string{"в файле нет сведений о цепочке, совместимость не проверена"}
+	}
+
+	var warnings []string
+	if e.Chain.Version != local.Version {
+		warnings = append(warnings, fmt.Sprintf("версия цепочки отличается: в файле %q, локально %q", e.Chain.Version, local.Version))
+	}
+	if strings.Join(e.Chain.Models, ",") != strings.Join(local.Models, ",") {
+		warnings = append(warnings, fmt.Sprintf("модели цепочки отличаются: в файле [%s], локально [%s]",
+			strings.Join(e.Chain.Models, ", "), strings.Join(local.Models, ", ")))
+	} else if !e.Chain.UpdatedAt.IsZero() && local.UpdatedAt.After(e.Chain.UpdatedAt) {
+		warnings = append(warnings, fmt.Sprintf("локальная цепочка изменена после экспорта (%s)", local.UpdatedAt.Format(time.RFC3339)))
+	}
+	return warnings
+}
+
+func isKnownType(t CheckpointType) bool {
+	switch t {
+	case CheckpointTypeInput, CheckpointTypeOutput, CheckpointTypeIntermediate,
+		CheckpointTypeError, CheckpointTypeSegment, CheckpointTypeComplete:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,86 @@
+package checkpoint
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportRoundTrip(t *testing.T) {
+	store, err := NewFileCheckpointStore(t.TempDir())
+	require.NoError(t, err)
+
+	large := string(make([]byte, 20*1024))
+	require.NoError(t, store.Save(Checkpoint{ID: "cp-1", ChainID: "chain-1", Type: CheckpointTypeSegment, Content: large}))
+
+	cp, err := store.Get("cp-1")
+	require.NoError(t, err)
+	require.NotEmpty(t, cp.ContentPath)
+
+	data, err := json.Marshal(NewExport(cp, &ChainInfo{ID: "chain-1", Version: "1.0"}))
+	require.NoError(t, err)
+
+	export, err := ParseExport(data)
+	require.NoError(t, err)
+	assert.Equal(t, ExportSchemaVersion, export.SchemaVersion)
+	assert.Empty(t, export.Checkpoint.ContentPath)
+	assert.Equal(t, large, export.Checkpoint.Content)
+
+	other, err := NewFileCheckpointStore(t.TempDir())
+	require.NoError(t, err)
+	require.NoError(t, other.Save(export.Checkpoint))
+	imported, err := other.Get("cp-1")
+	require.NoError(t, err)
+	assert.Equal(t, large, imported.Content)
+}
+
+func TestParseExportValidation(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		err  string
+	}{
+		{"Invalid JSON", `{`, "некорректный JSON"},
+		{"No schema version", `{"checkpoint":{"id":"1","chain_id":"c","type":"input","content":"x"}}`, "schema_version"},
+		{"Newer schema", `{"schema_version":99,"checkpoint":{"id":"1","chain_id":"c","type":"input","content":"x"}}`, "не поддерживается"},
+		{"Missing fields", `{"schema_version":1,"checkpoint":{"id":"1","type":"input"}}`, "checkpoint.chain_id, checkpoint.content"},
+		{"Unknown type", `{"schema_version":1,"checkpoint":{"id":"1","chain_id":"c","type":"draft","content":"x"}}`, "неизвестный тип"},
+		{"Chain mismatch", `{"schema_version":1,"chain":{"id":"other"},"checkpoint":{"id":"1","chain_id":"c","type":"input","content":"x"}}`, "не совпадает"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseExport([]byte(tt.data))
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.err)
+		})
+	}
+}
+
+func TestExportCompareChain(t *testing.T) {
+	exportedAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	export := &Export{
+		Chain:      &ChainInfo{ID: "c", Version: "1.0", UpdatedAt: exportedAt, Models: []string{"gpt-4", "claude-3-opus"}},
+		Checkpoint: Checkpoint{ChainID: "c"},
+	}
+
+	assert.Empty(t, export.CompareChain(&ChainInfo{ID: "c", Version: "1.0", UpdatedAt: exportedAt, Models: []string{"gpt-4", "claude-3-opus"}}))
+
+	warnings := export.CompareChain(nil)
+	require.Len(t, warnings, 1)
+	assert.Contains(t, warnings[0], "не найдена")
+
+	warnings = export.CompareChain(&ChainInfo{ID: "c", Version: "2.0", UpdatedAt: exportedAt, Models: []string{"gpt-4"}})
+	require.Len(t, warnings, 2)
+	assert.Contains(t, warnings[0], "версия цепочки")
+	assert.Contains(t, warnings[1], "модели цепочки")
+
+	warnings = export.CompareChain(&ChainInfo{ID: "c", Version: "1.0", UpdatedAt: exportedAt.Add(time.Hour), Models: []string{"gpt-4", "claude-3-opus"}})
+	require.Len(t, warnings, 1)
+	assert.Contains(t, warnings[0], "изменена после экспорта")
+
+	export.Chain = nil
+	assert.Len(t, export.CompareChain(&ChainInfo{ID: "c"}), 1)
+}
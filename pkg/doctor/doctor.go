@@ -0,0 +1,288 @@
+package doctor
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/debug"
+	"sort"
+	"strings"
+
+	"github.com/grik-ai/ricochet-task/pkg/chain"
+	"github.com/grik-ai/ricochet-task/pkg/key"
+	"github.com/grik-ai/ricochet-task/pkg/providers"
+)
+
+// Status is the outcome of a single check
+type Status string
+
+const (
+	StatusPass Status = "pass"
+	StatusWarn Status = "warn"
+	StatusFail Status = "fail"
+)
+
+// Check is one line of the doctor checklist
+type Check struct {
+	Name    string `json:"name" yaml:"name"`
+	Status  Status `json:"status" yaml:"status"`
+	Message string `json:"message" yaml:"message"`
+	Hint    string `json:"hint,omitempty" yaml:"hint,omitempty"`
+}
+
+// Report collects the results of all checks
+type Report struct {
+	Checks []Check `json:"checks" yaml:"checks"`
+}
+
+// Add appends checks to the report
+func (r *Report) Add(checks ...Check) {
+	r.Checks = append(r.Checks, checks...)
+}
+
+// Count returns the number of checks with the given status
+func (r *Report) Count(status Status) int {
+	count := 0
+	for _, check := range r.Checks {
+		if check.Status == status {
+			count++
+		}
+	}
+	return count
+}
+
+// Failed reports whether any check failed
+func (r *Report) Failed() bool {
+	return r.Count(StatusFail) > 0
+}
+
+// Failures returns the failed checks
+func (r *Report) Failures() []Check {
+	var failed []Check
+	for _, check := range r.Checks {
+		if check.Status == StatusFail {
+			failed = append(failed, check)
+		}
+	}
+	return failed
+}
+
+// ProbeFunc checks that a provider is reachable and accepts its credentials
+type ProbeFunc func(ctx context.Context, config *providers.ProviderConfig) error
+
+// CheckConfig checks that the provider configuration file exists, parses and validates.
+// loadErr is the error returned while reading the file, if any.
+func CheckConfig(path string, config *providers.MultiProviderConfig, loadErr error) Check {
+	check := Check{Name: "Configuration file"}
+
+	switch {
+	case loadErr != nil && os.IsNotExist(loadErr):
+		check.Status = StatusWarn
+		check.Message = fmt.Sprintf("%s not found, using defaults", path)
+		check.Hint = "Run 'ricochet providers add' to configure a provider"
+	case loadErr != nil:
+		check.Status = StatusFail
+		check.Message = fmt.Sprintf("cannot read %s: %v", path, loadErr)
+		check.Hint = "Fix the YAML syntax of the file"
+	default:
+		if err := config.Validate(); err != nil {
+			check.Status = StatusFail
+			check.Message = fmt.Sprintf("%s is invalid: %v", path, err)
+			check.Hint = "Fix the reported setting, see 'ricochet providers add --help'"
+			break
+		}
+		check.Status = StatusPass
+		check.Message = fmt.Sprintf("%s is valid", path)
+	}
+
+	return check
+}
+
+// CheckProviders probes every enabled provider, one check per provider
+func CheckProviders(ctx context.Context, config *providers.MultiProviderConfig, probe ProbeFunc) []Check {
+	var names []string
+	for name, providerConfig := range config.Providers {
+		if providerConfig.Enabled {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	if len(names) == 0 {
+		return []Check{{
+			Name:    "Providers",
+			Status:  StatusWarn,
+			Message: "no enabled providers",
+			Hint:    "Run 'ricochet providers add' or 'ricochet providers enable <name>'",
+		}}
+	}
+
+	checks := make([]Check, 0, len(names))
+	for _, name := range names {
+		check := Check{Name: "Provider " + name}
+		if err := probe(ctx, config.Providers[name]); err != nil {
+			check.Status = StatusFail
+			check.Message = err.Error()
+			check.Hint = providerHint(name, err)
+		} else {
+			check.Status = StatusPass
+			check.Message = "reachable and authenticated"
+		}
+		checks = append(checks, check)
+	}
+	return checks
+}
+
+func providerHint(name string, err error) string {
+	errorType, _ := providers.ClassifyError(err)
+	switch errorType {
+	case providers.ErrorTypeUnauthorized, providers.ErrorTypeForbidden:
+		return fmt.Sprintf("Check the token of provider '%s' and its permissions", name)
+	case providers.ErrorTypeRateLimit:
+		return "The provider is rate limiting requests, try again later"
+	case providers.ErrorTypeConfiguration, providers.ErrorTypeValidation:
+		return fmt.Sprintf("Fix the configuration of provider '%s' in the config file", name)
+	default:
+		return fmt.Sprintf("Check the baseUrl of provider '%s' and your network connection", name)
+	}
+}
+
+// CheckChainKeys checks that an API key is stored for every model type used by the chains.
+// Model types without a key store entry, such as local models, are not checked.
+func CheckChainKeys(chains []chain.Chain, keys []key.Key) Check {
+	check := Check{Name: "AI keys"}
+
+	stored := make(map[string]bool)
+	for _, k := range keys {
+		stored[k.Provider] = true
+	}
+
+	required := make(map[string][]string)
+	for _, c := range chains {
+		for _, model := range c.Models {
+			provider, ok := keyProvider(model.Type)
+			if !ok || stored[provider] {
+				continue
+			}
+			if !containsString(required[provider], c.Name) {
+				required[provider] = append(required[provider], c.Name)
+			}
+		}
+	}
+
+	if len(chains) == 0 {
+		check.Status = StatusPass
+		check.Message = "no chains configured"
+		return check
+	}
+
+	if len(required) == 0 {
+		check.Status = StatusPass
+		check.Message = fmt.Sprintf("keys present for all %d chains", len(chains))
+		return check
+	}
+
+	var missing, hints []string
+	for provider := range required {
+		missing = append(missing, provider)
+	}
+	sort.Strings(missing)
+	for i, provider := range missing {
+		hints = append(hints, fmt.Sprintf("ricochet key add --provider %s --key <key>", provider))
+		missing[i] = fmt.Sprintf("%s (chains: %s)", provider, strings.Join(required[provider], ", "))
+	}
+
+	check.Status = StatusFail
+	check.Message = "missing keys for " + strings.Join(missing, "; ")
+	check.Hint = "Run " + strings.Join(hints, " and ")
+	return check
+}
+
+// keyProvider returns the key store provider holding keys for a model type
+func keyProvider(modelType chain.ModelType) (string, bool) {
+	switch modelType {
+	case chain.ModelTypeOpenAI, chain.ModelTypeClaude, chain.ModelTypeDeepSeek, chain.ModelTypeGrok:
+		return string(modelType), true
+	}
+	return "", false
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckWritable checks that dir exists or can be created and accepts new files
+func CheckWritable(name, dir string) Check {
+	check := Check{Name: name}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		check.Status = StatusFail
+		check.Message = fmt.Sprintf("cannot create %s: %v", dir, err)
+		check.Hint = "Create the directory or set config_dir in ~/.ricochet/config.json to a writable location"
+		return check
+	}
+
+	file, err := os.CreateTemp(dir, ".doctor-*")
+	if err != nil {
+		check.Status = StatusFail
+		check.Message = fmt.Sprintf("%s is not writable: %v", dir, err)
+		check.Hint = fmt.Sprintf("Fix the permissions, e.g. 'chmod u+w %s'", dir)
+		return check
+	}
+	file.Close()
+	os.Remove(file.Name())
+
+	check.Status = StatusPass
+	check.Message = fmt.Sprintf("%s is writable", dir)
+	return check
+}
+
+// CheckVersion reports the ricochet build and checks that this build ships a
+// plugin for the type of every configured provider
+func CheckVersion(config *providers.MultiProviderConfig) Check {
+	check := Check{Name: "Version"}
+
+	version := "(devel)"
+	if info, ok := debug.ReadBuildInfo(); ok && info.Main.Version != "" {
+		version = info.Main.Version
+	}
+
+	var names, plugins, unsupported []string
+	for name := range config.Providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		providerType := config.Providers[name].Type
+		pluginVersion, ok := providers.PluginVersion(providerType)
+		if !ok {
+			unsupported = append(unsupported, fmt.Sprintf("%s (%s)", name, providerType))
+			continue
+		}
+		plugin := fmt.Sprintf("%s %s", providerType, pluginVersion)
+		if !containsString(plugins, plugin) {
+			plugins = append(plugins, plugin)
+		}
+	}
+
+	check.Message = fmt.Sprintf("ricochet %s, %s", version, runtime.Version())
+	if len(plugins) > 0 {
+		check.Message += ", plugins: " + strings.Join(plugins, ", ")
+	}
+
+	if len(unsupported) > 0 {
+		check.Status = StatusFail
+		check.Message += "; no plugin for " + strings.Join(unsupported, ", ")
+		check.Hint = "Upgrade ricochet or change the provider type in the config file"
+		return check
+	}
+
+	check.Status = StatusPass
+	return check
+}
@@ -0,0 +1,113 @@
+package doctor
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grik-ai/ricochet-task/pkg/chain"
+	"github.com/grik-ai/ricochet-task/pkg/key"
+	"github.com/grik-ai/ricochet-task/pkg/providers"
+)
+
+func TestCheckConfig(t *testing.T) {
+	valid := &providers.MultiProviderConfig{
+		Providers: map[string]*providers.ProviderConfig{
+			"youtrack": {Name: "youtrack", Type: providers.ProviderTypeYouTrack, AuthType: providers.AuthTypeBearer, Token: "secret"},
+		},
+	}
+
+	assert.Equal(t, StatusPass, CheckConfig("ricochet.yaml", valid, nil).Status)
+	assert.Equal(t, StatusWarn, CheckConfig("ricochet.yaml", nil, os.ErrNotExist).Status)
+	assert.Equal(t, StatusFail, CheckConfig("ricochet.yaml", nil, errors.New("yaml: line 3")).Status)
+
+	check := CheckConfig("ricochet.yaml", &providers.MultiProviderConfig{}, nil)
+	assert.Equal(t, StatusFail, check.Status)
+	assert.NotEmpty(t, check.Hint)
+}
+
+func TestCheckProviders(t *testing.T) {
+	config := &providers.MultiProviderConfig{
+		Providers: map[string]*providers.ProviderConfig{
+			"youtrack": {Name: "youtrack", Enabled: true},
+			"jira":     {Name: "jira", Enabled: true},
+			"notion":   {Name: "notion"},
+		},
+	}
+	probe := func(ctx context.Context, config *providers.ProviderConfig) error {
+		if config.Name == "jira" {
+			return providers.NewProviderError(providers.ErrorTypeUnauthorized, "invalid token", nil)
+		}
+		return nil
+	}
+
+	checks := CheckProviders(context.Background(), config, probe)
+	require.Len(t, checks, 2)
+	assert.Equal(t, "Provider jira", checks[0].Name)
+	assert.Equal(t, StatusFail, checks[0].Status)
+	assert.Contains(t, checks[0].Hint, "token")
+	assert.Equal(t, "Provider youtrack", checks[1].Name)
+	assert.Equal(t, StatusPass, checks[1].Status)
+
+	report := &Report{}
+	report.Add(checks...)
+	assert.True(t, report.Failed())
+	assert.Equal(t, []Check{checks[0]}, report.Failures())
+
+	checks = CheckProviders(context.Background(), &providers.MultiProviderConfig{}, probe)
+	require.Len(t, checks, 1)
+	assert.Equal(t, StatusWarn, checks[0].Status)
+}
+
+func TestCheckChainKeys(t *testing.T) {
+	chains := []chain.Chain{
+		{Name: "review", Models: []chain.Model{{Type: chain.ModelTypeOpenAI}, {Type: chain.ModelTypeClaude}}},
+		{Name: "local", Models: []chain.Model{{Type: chain.ModelTypeLlama}}},
+	}
+
+	check := CheckChainKeys(chains, []key.Key{{Provider: "openai"}})
+	assert.Equal(t, StatusFail, check.Status)
+	assert.Equal(t, "missing keys for claude (chains: review)", check.Message)
+	assert.Contains(t, check.Hint, "ricochet key add --provider claude")
+
+	check = CheckChainKeys(chains, []key.Key{{Provider: "openai"}, {Provider: "claude"}})
+	assert.Equal(t, StatusPass, check.Status)
+
+	assert.Equal(t, StatusPass, CheckChainKeys(nil, nil).Status)
+}
+
+func TestCheckWritable(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "store")
+	check := CheckWritable("Config directory", dir)
+	assert.Equal(t, StatusPass, check.Status)
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+
+	if os.Geteuid() == 0 {
+		t.Skip("permissions are not enforced for root")
+	}
+	readOnly := t.TempDir()
+	require.NoError(t, os.Chmod(readOnly, 0555))
+	assert.Equal(t, StatusFail, CheckWritable("Config directory", readOnly).Status)
+}
+
+func TestCheckVersion(t *testing.T) {
+	config := &providers.MultiProviderConfig{
+		Providers: map[string]*providers.ProviderConfig{
+			"custom": {Name: "custom", Type: providers.ProviderType("unknown")},
+		},
+	}
+
+	check := CheckVersion(config)
+	assert.Equal(t, StatusFail, check.Status)
+	assert.Contains(t, check.Message, "no plugin for custom (unknown)")
+
+	assert.Equal(t, StatusPass, CheckVersion(&providers.MultiProviderConfig{}).Status)
+}
@@ -0,0 +1,75 @@
+package mcp
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/grik-ai/ricochet-task/pkg/providers"
+)
+
+// ToolAccess decides which tools the server exposes. Entries are tool names
+// or patterns like ai_*. Without Enabled all tools are exposed.
+type ToolAccess struct {
+	Enabled  []string // Only tools matching these are exposed, if set
+	Disabled []string // Tools matching these are never exposed
+}
+
+// NewToolAccess returns the tool access of the mcp configuration
+func NewToolAccess(config *providers.MCPConfig) ToolAccess {
+	if config == nil {
+		return ToolAccess{}
+	}
+	return ToolAccess{Enabled: config.EnabledTools, Disabled: config.DisabledTools}
+}
+
+// Allows reports whether a tool is exposed
+func (a ToolAccess) Allows(name string) bool {
+	if len(a.Enabled) > 0 && !matchesTool(a.Enabled, name) {
+		return false
+	}
+	return !matchesTool(a.Disabled, name)
+}
+
+// Validate checks that every entry is a valid pattern and matches one of the
+// known tools, so that a typo doesn't silently expose or hide a tool
+func (a ToolAccess) Validate(known []string) error {
+	for _, list := range []struct {
+		name    string
+		entries []string
+	}{{"enabled", a.Enabled}, {"disabled", a.Disabled}} {
+		for _, entry := range list.entries {
+			if _, err := path.Match(entry, ""); err != nil {
+				return providers.NewValidationError(fmt.Sprintf("%s tools: invalid pattern %q", list.name, entry), nil)
+			}
+			if !matchesAny(entry, known) {
+				return providers.NewValidationError(fmt.Sprintf("%s tools: %q matches no tool, see ricochet mcp tools --all", list.name, entry), nil)
+			}
+		}
+	}
+	return nil
+}
+
+// disabledToolResult is the result of calling a tool the server doesn't expose
+func disabledToolResult(name string) *ToolResult {
+	message := fmt.Sprintf("Tool %s is disabled on this server", name)
+	return &ToolResult{Error: &message, ErrorCode: ErrorCodeToolDisabled}
+}
+
+func matchesTool(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if matched, _ := path.Match(strings.TrimSpace(pattern), name); matched {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesAny(pattern string, names []string) bool {
+	for _, name := range names {
+		if matchesTool([]string{pattern}, name) {
+			return true
+		}
+	}
+	return false
+}
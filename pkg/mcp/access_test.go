@@ -0,0 +1,44 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToolAccess(t *testing.T) {
+	assert.True(t, ToolAccess{}.Allows("providers_add"), "all tools are exposed by default")
+
+	access := ToolAccess{Enabled: []string{"task_list_unified", "ai_*"}, Disabled: []string{"ai_execute_*"}}
+	assert.True(t, access.Allows("task_list_unified"))
+	assert.True(t, access.Allows("ai_analyze_project"))
+	assert.False(t, access.Allows("ai_execute_plan"), "disabled wins over enabled")
+	assert.False(t, access.Allows("providers_add"))
+
+	known := []string{"task_list_unified", "ai_analyze_project", "ai_execute_plan", "providers_add"}
+	require.NoError(t, access.Validate(known))
+	assert.Error(t, ToolAccess{Disabled: []string{"providers_remove"}}.Validate(known))
+	assert.Error(t, ToolAccess{Enabled: []string{"ai_["}}.Validate(known))
+}
+
+func TestDisabledTools(t *testing.T) {
+	provider := NewMCPToolProvider(nil)
+	provider.SetToolAccess(ToolAccess{Disabled: []string{"providers_add", "task_update_universal"}})
+
+	for _, tool := range provider.GetTools() {
+		assert.NotContains(t, []string{"providers_add", "task_update_universal"}, tool.Name)
+	}
+	assert.Len(t, provider.GetTools(), len(provider.ToolNames())-2)
+
+	result, err := provider.ExecuteTool(context.Background(), "providers_add", map[string]interface{}{"name": "prod"})
+	require.NoError(t, err)
+	require.NotNil(t, result.Error)
+	assert.Equal(t, "Tool providers_add is disabled on this server", *result.Error)
+	assert.Equal(t, ErrorCodeToolDisabled, result.ErrorCode)
+
+	result, err = provider.ExecuteTool(context.Background(), "providers_remove", nil)
+	require.NoError(t, err)
+	assert.Equal(t, ErrorCodeUnknownTool, result.ErrorCode)
+}
@@ -0,0 +1,127 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grik-ai/ricochet-task/pkg/providers"
+)
+
+const expandProviderType providers.ProviderType = "mcp-expand-test"
+
+// expandProvider serves one task and records description updates
+type expandProvider struct {
+	providers.TaskProvider
+	task    *providers.UniversalTask
+	updates []*providers.TaskUpdate
+}
+
+func (p *expandProvider) GetTask(ctx context.Context, id string) (*providers.UniversalTask, error) {
+	if id != p.task.ID {
+		return nil, providers.NewProviderError(providers.ErrorTypeNotFound, "task not found: "+id, nil)
+	}
+	return p.task, nil
+}
+
+func (p *expandProvider) UpdateTask(ctx context.Context, id string, updates *providers.TaskUpdate) error {
+	p.updates = append(p.updates, updates)
+	return nil
+}
+
+func (p *expandProvider) GetProviderInfo() *providers.ProviderInfo {
+	return &providers.ProviderInfo{Name: "tracker", Enabled: true, HealthStatus: providers.HealthStatusHealthy}
+}
+
+func (p *expandProvider) HealthCheck(ctx context.Context) error { return nil }
+func (p *expandProvider) Close() error                          { return nil }
+
+// expandPlugin hands the registry the expandProvider of the current test
+type expandPlugin struct {
+	providers.TaskManagerPlugin
+}
+
+var currentExpandProvider *expandProvider
+
+func (p *expandPlugin) Initialize(config *providers.ProviderConfig) error { return nil }
+func (p *expandPlugin) GetProvider() providers.TaskProvider               { return currentExpandProvider }
+func (p *expandPlugin) Cleanup() error                                    { return nil }
+
+func init() {
+	providers.RegisterProviderType(expandProviderType, func() providers.TaskManagerPlugin { return &expandPlugin{} })
+}
+
+// newExpandToolProvider returns a tool provider whose default provider serves task
+func newExpandToolProvider(t *testing.T, task *providers.UniversalTask) (*MCPToolProvider, *expandProvider) {
+	t.Helper()
+	return NewMCPToolProvider(newExpandRegistry(t, task)), currentExpandProvider
+}
+
+// newExpandRegistry returns a registry whose default provider "tracker"
+// serves task
+func newExpandRegistry(t *testing.T, task *providers.UniversalTask) *providers.ProviderRegistry {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+
+	currentExpandProvider = &expandProvider{task: task}
+	config := providers.DefaultMultiProviderConfig()
+	config.DefaultProvider = "tracker"
+	config.Providers["tracker"] = &providers.ProviderConfig{Name: "tracker", Type: expandProviderType, Enabled: true}
+
+	logger := logrus.New()
+	logger.SetLevel(logrus.ErrorLevel)
+	registry := providers.NewProviderRegistry(config, logger)
+	require.NoError(t, registry.Initialize(context.Background()))
+	t.Cleanup(func() { registry.Shutdown(context.Background()) })
+	return registry
+}
+
+func TestAIExpandTask(t *testing.T) {
+	task := &providers.UniversalTask{ID: "1", Key: "OPS-1", Title: "Rotate certificates", Description: "Certificates expire in March", Type: providers.TaskTypeTask}
+
+	t.Run("Review only", func(t *testing.T) {
+		toolProvider, provider := newExpandToolProvider(t, task)
+
+		result, err := toolProvider.ExecuteTool(context.Background(), "ai_expand_task", map[string]interface{}{"task_id": "1"})
+		require.NoError(t, err)
+		require.Nil(t, result.Error)
+
+		text := result.Content[0]["text"].(string)
+		assert.Contains(t, text, "Expanded description of OPS-1: Rotate certificates")
+		assert.Contains(t, text, "Certificates expire in March\n\n## Context")
+		assert.Contains(t, text, "apply=true")
+		assert.Empty(t, provider.updates, "the task is not changed without apply")
+	})
+
+	t.Run("Apply", func(t *testing.T) {
+		toolProvider, provider := newExpandToolProvider(t, task)
+
+		result, err := toolProvider.ExecuteTool(context.Background(), "ai_expand_task", map[string]interface{}{"task_id": "1", "apply": true})
+		require.NoError(t, err)
+		require.Nil(t, result.Error)
+		assert.Contains(t, result.Content[0]["text"], "Task description updated")
+
+		require.Len(t, provider.updates, 1)
+		description := *provider.updates[0].Description
+		assert.True(t, len(description) > len(task.Description))
+		assert.Contains(t, description, "Certificates expire in March\n\n## Context")
+		assert.Contains(t, description, "## Acceptance criteria")
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		toolProvider, _ := newExpandToolProvider(t, task)
+
+		result, err := toolProvider.ExecuteTool(context.Background(), "ai_expand_task", map[string]interface{}{})
+		require.NoError(t, err)
+		require.NotNil(t, result.Error)
+		assert.Equal(t, ErrorCodeInvalidArgument, result.ErrorCode)
+
+		result, err = toolProvider.ExecuteTool(context.Background(), "ai_expand_task", map[string]interface{}{"task_id": "2"})
+		require.NoError(t, err)
+		require.NotNil(t, result.Error)
+		assert.Contains(t, *result.Error, "Failed to get task")
+	})
+}
@@ -0,0 +1,347 @@
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/grik-ai/ricochet-task/pkg/providers"
+)
+
+// AuditLogFile is the file name of the MCP audit log in a config directory
+const AuditLogFile = "mcp_audit.jsonl"
+
+const (
+	// DefaultAuditMaxSize is the size at which the audit log is rotated
+	DefaultAuditMaxSize = 10 << 20
+	// DefaultAuditMaxBackups is how many rotated audit logs are kept
+	DefaultAuditMaxBackups = 5
+
+	// maxAuditValueLength bounds the length of string arguments in the log,
+	// e.g. of task descriptions
+	maxAuditValueLength = 512
+)
+
+// Outcomes of audited tool calls
+const (
+	AuditOutcomeSuccess = "success"
+	AuditOutcomeError   = "error"
+)
+
+// CallerHeader is the request header in which clients name themselves, e.g.
+// the agent that calls the tools
+const CallerHeader = "X-MCP-Client"
+
+// Caller identifies who called a tool. The server has no authentication, so
+// the name is whatever the client declares.
+type Caller struct {
+	Name      string `json:"name,omitempty"`
+	Address   string `json:"address,omitempty"` // Remote address of the request
+	UserAgent string `json:"userAgent,omitempty"`
+}
+
+// String returns the name and address of the caller
+func (c Caller) String() string {
+	switch {
+	case c.Name != "" && c.Address != "":
+		return fmt.Sprintf("%s (%s)", c.Name, c.Address)
+	case c.Name != "":
+		return c.Name
+	case c.Address != "":
+		return c.Address
+	}
+	return "unknown"
+}
+
+type callerKey struct{}
+
+// WithCaller returns a context carrying the caller of the tools run with it
+func WithCaller(ctx context.Context, caller Caller) context.Context {
+	return context.WithValue(ctx, callerKey{}, caller)
+}
+
+// CallerFrom returns the caller carried by ctx
+func CallerFrom(ctx context.Context) (Caller, bool) {
+	caller, ok := ctx.Value(callerKey{}).(Caller)
+	return caller, ok
+}
+
+// AuditEntry is one tool call in the audit log
+type AuditEntry struct {
+	Time       time.Time              `json:"time" yaml:"time"`
+	Tool       string                 `json:"tool" yaml:"tool"`
+	Arguments  map[string]interface{} `json:"arguments,omitempty" yaml:"arguments,omitempty"` // Credentials are redacted
+	Caller     Caller                 `json:"caller" yaml:"caller"`
+	Outcome    string                 `json:"outcome" yaml:"outcome"`
+	ErrorCode  ErrorCode              `json:"errorCode,omitempty" yaml:"errorCode,omitempty"`
+	Error      string                 `json:"error,omitempty" yaml:"error,omitempty"`
+	DurationMs int64                  `json:"durationMs" yaml:"durationMs"`
+}
+
+// AuditFilter selects entries of the audit log. Zero fields don't filter.
+type AuditFilter struct {
+	Since      time.Time
+	Tool       string // Tool name or pattern like ai_*
+	Caller     string // Part of the caller's name or address
+	ErrorsOnly bool
+	Limit      int // Keep only the newest entries
+}
+
+// AuditLog records every tool call in a JSON lines file, rotated once it
+// grows past its maximum size. Arguments are logged with credentials
+// redacted and long strings shortened.
+type AuditLog struct {
+	mu         sync.Mutex
+	path       string
+	maxSize    int64
+	maxBackups int
+	logger     *logrus.Logger
+}
+
+// NewAuditLog creates an audit log backed by the given file. Rotated logs are
+// kept next to it as path.1 (the newest) up to path.maxBackups.
+func NewAuditLog(path string, maxSize int64, maxBackups int, logger *logrus.Logger) *AuditLog {
+	if logger == nil {
+		logger = logrus.New()
+	}
+	if maxSize <= 0 {
+		maxSize = DefaultAuditMaxSize
+	}
+	if maxBackups < 0 {
+		maxBackups = 0
+	}
+	return &AuditLog{path: path, maxSize: maxSize, maxBackups: maxBackups, logger: logger}
+}
+
+// NewAuditLogFromConfig creates the audit log the mcp configuration asks for,
+// or returns nil if auditing is disabled
+func NewAuditLogFromConfig(path string, config *providers.MCPConfig, logger *logrus.Logger) *AuditLog {
+	maxSize, maxBackups := int64(DefaultAuditMaxSize), DefaultAuditMaxBackups
+	if config != nil && config.Audit != nil {
+		if config.Audit.Disabled {
+			return nil
+		}
+		if config.Audit.MaxSizeMB > 0 {
+			maxSize = int64(config.Audit.MaxSizeMB) << 20
+		}
+		if config.Audit.MaxBackups > 0 {
+			maxBackups = config.Audit.MaxBackups
+		}
+	}
+	return NewAuditLog(path, maxSize, maxBackups, logger)
+}
+
+// Path returns the file of the current audit log
+func (l *AuditLog) Path() string {
+	return l.path
+}
+
+// RecordCall records a finished tool call. Failures to write the log are
+// logged rather than failing the call.
+func (l *AuditLog) RecordCall(ctx context.Context, name string, arguments map[string]interface{}, started time.Time, result *ToolResult, err error) {
+	entry := AuditEntry{
+		Time:       started,
+		Tool:       name,
+		Arguments:  redactArguments(arguments),
+		Outcome:    AuditOutcomeSuccess,
+		DurationMs: time.Since(started).Milliseconds(),
+	}
+	entry.Caller, _ = CallerFrom(ctx)
+
+	switch {
+	case err != nil:
+		entry.Outcome = AuditOutcomeError
+		entry.ErrorCode = errorCode(err)
+		entry.Error = err.Error()
+	case result != nil && result.Error != nil:
+		entry.Outcome = AuditOutcomeError
+		entry.ErrorCode = result.ErrorCode
+		entry.Error = *result.Error
+	}
+
+	if err := l.Record(entry); err != nil {
+		l.logger.Errorf("Failed to write MCP audit log: %v", err)
+	}
+}
+
+// Record appends an entry to the log, rotating it first if the entry would
+// grow it past its maximum size
+func (l *AuditLog) Record(entry AuditEntry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit entry: %w", err)
+	}
+	line = append(line, '\n')
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(l.path), 0755); err != nil {
+		return fmt.Errorf("failed to create audit log directory: %w", err)
+	}
+	if info, err := os.Stat(l.path); err == nil && info.Size() > 0 && info.Size()+int64(len(line)) > l.maxSize {
+		if err := l.rotate(); err != nil {
+			return err
+		}
+	}
+
+	file, err := os.OpenFile(l.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open audit log: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(line); err != nil {
+		return fmt.Errorf("failed to write audit log: %w", err)
+	}
+	return nil
+}
+
+// rotate shifts the rotated logs by one, dropping the oldest, and moves the
+// current log to path.1. Must be called with the lock held.
+func (l *AuditLog) rotate() error {
+	if l.maxBackups == 0 {
+		if err := os.Remove(l.path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to rotate audit log: %w", err)
+		}
+		return nil
+	}
+
+	if err := os.Remove(l.backupPath(l.maxBackups)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to rotate audit log: %w", err)
+	}
+	for i := l.maxBackups - 1; i >= 1; i-- {
+		if err := os.Rename(l.backupPath(i), l.backupPath(i+1)); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to rotate audit log: %w", err)
+		}
+	}
+	if err := os.Rename(l.path, l.backupPath(1)); err != nil {
+		return fmt.Errorf("failed to rotate audit log: %w", err)
+	}
+	return nil
+}
+
+func (l *AuditLog) backupPath(n int) string {
+	return fmt.Sprintf("%s.%d", l.path, n)
+}
+
+// Entries returns the entries matching filter from the current and rotated
+// logs, oldest first
+func (l *AuditLog) Entries(filter AuditFilter) ([]AuditEntry, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// Rotated logs from the oldest to the newest, then the current one
+	var paths []string
+	for i := 1; ; i++ {
+		if _, err := os.Stat(l.backupPath(i)); err != nil {
+			break
+		}
+		paths = append([]string{l.backupPath(i)}, paths...)
+	}
+	paths = append(paths, l.path)
+
+	entries := []AuditEntry{}
+	for _, path := range paths {
+		matched, err := readAuditEntries(path, filter)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, matched...)
+	}
+
+	if filter.Limit > 0 && len(entries) > filter.Limit {
+		entries = entries[len(entries)-filter.Limit:]
+	}
+	return entries, nil
+}
+
+func readAuditEntries(path string, filter AuditFilter) ([]AuditEntry, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read audit log: %w", err)
+	}
+	defer file.Close()
+
+	var entries []AuditEntry
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var entry AuditEntry
+		// A line cut short by an interrupted write only loses that call
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			continue
+		}
+		if filter.matches(entry) {
+			entries = append(entries, entry)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read audit log: %w", err)
+	}
+	return entries, nil
+}
+
+func (f AuditFilter) matches(entry AuditEntry) bool {
+	if !f.Since.IsZero() && entry.Time.Before(f.Since) {
+		return false
+	}
+	if f.Tool != "" && !matchesTool([]string{f.Tool}, entry.Tool) {
+		return false
+	}
+	if f.Caller != "" && !strings.Contains(strings.ToLower(entry.Caller.String()), strings.ToLower(f.Caller)) {
+		return false
+	}
+	if f.ErrorsOnly && entry.Outcome != AuditOutcomeError {
+		return false
+	}
+	return true
+}
+
+// redactArguments copies tool arguments for the audit log, replacing the
+// values of arguments whose names look like credentials and shortening long
+// strings
+func redactArguments(arguments map[string]interface{}) map[string]interface{} {
+	if len(arguments) == 0 {
+		return nil
+	}
+
+	result := make(map[string]interface{}, len(arguments))
+	for name, value := range arguments {
+		if providers.IsSensitiveName(name) {
+			result[name] = "[REDACTED]"
+			continue
+		}
+		result[name] = redactValue(value)
+	}
+	return result
+}
+
+func redactValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return redactArguments(v)
+	case []interface{}:
+		values := make([]interface{}, len(v))
+		for i, item := range v {
+			values[i] = redactValue(item)
+		}
+		return values
+	case string:
+		if runes := []rune(v); len(runes) > maxAuditValueLength {
+			return fmt.Sprintf("%s... (%d characters)", string(runes[:maxAuditValueLength]), len(runes))
+		}
+	}
+	return value
+}
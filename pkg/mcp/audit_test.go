@@ -0,0 +1,83 @@
+package mcp
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditLogRecordsToolCalls(t *testing.T) {
+	log := NewAuditLog(filepath.Join(t.TempDir(), AuditLogFile), 0, 0, nil)
+	provider := NewMCPToolProvider(nil)
+	provider.SetToolAccess(ToolAccess{Disabled: []string{"providers_add"}})
+	provider.SetAuditLog(log)
+
+	ctx := WithCaller(context.Background(), Caller{Name: "triage-agent", Address: "10.0.0.7:5123"})
+	_, err := provider.ExecuteTool(ctx, "providers_add", map[string]interface{}{
+		"name":        "prod",
+		"token":       "perm:secret",
+		"description": strings.Repeat("x", 600),
+	})
+	require.NoError(t, err)
+
+	entries, err := log.Entries(AuditFilter{})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	entry := entries[0]
+	assert.Equal(t, "providers_add", entry.Tool)
+	assert.Equal(t, "triage-agent (10.0.0.7:5123)", entry.Caller.String())
+	assert.Equal(t, AuditOutcomeError, entry.Outcome)
+	assert.Equal(t, ErrorCodeToolDisabled, entry.ErrorCode)
+	assert.Equal(t, "prod", entry.Arguments["name"])
+	assert.Equal(t, "[REDACTED]", entry.Arguments["token"])
+	assert.Contains(t, entry.Arguments["description"], "... (600 characters)")
+}
+
+func TestAuditLogFilterAndRotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), AuditLogFile)
+	log := NewAuditLog(path, 300, 2, nil)
+
+	start := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	for i, tool := range []string{"task_list_unified", "task_update_universal", "ai_triage_tasks", "task_update_universal", "providers_list"} {
+		entry := AuditEntry{Time: start.Add(time.Duration(i) * time.Minute), Tool: tool, Caller: Caller{Name: "agent"}, Outcome: AuditOutcomeSuccess}
+		if i == 3 {
+			entry.Caller.Name = "rogue"
+			entry.Outcome = AuditOutcomeError
+		}
+		require.NoError(t, log.Record(entry))
+	}
+
+	_, err := os.Stat(path + ".1")
+	require.NoError(t, err, "the log is rotated once it grows past its maximum size")
+	_, err = os.Stat(path + ".3")
+	assert.True(t, os.IsNotExist(err), "only maxBackups rotated logs are kept")
+
+	entries, err := log.Entries(AuditFilter{})
+	require.NoError(t, err)
+	require.NotEmpty(t, entries)
+	assert.Equal(t, "providers_list", entries[len(entries)-1].Tool, "entries are listed oldest first across rotated logs")
+
+	entries, err = log.Entries(AuditFilter{Tool: "task_update_*", Caller: "ROGUE"})
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, start.Add(3*time.Minute), entries[0].Time.UTC())
+
+	entries, err = log.Entries(AuditFilter{ErrorsOnly: true})
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+
+	entries, err = log.Entries(AuditFilter{Since: start.Add(4 * time.Minute)})
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+
+	entries, err = log.Entries(AuditFilter{Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, "providers_list", entries[1].Tool)
+}
@@ -0,0 +1,78 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+
+	"github.com/grik-ai/ricochet-task/pkg/providers"
+)
+
+// ErrorCode classifies the error of a failed tool result, so that clients can
+// react to it without parsing the message: retry on rate_limited,
+// unavailable and timeout, re-authenticate on auth, fix the arguments on
+// invalid_argument.
+type ErrorCode string
+
+const (
+	ErrorCodeInvalidArgument ErrorCode = "invalid_argument" // missing or invalid tool arguments
+	ErrorCodeUnknownTool     ErrorCode = "unknown_tool"
+	ErrorCodeToolDisabled    ErrorCode = "tool_disabled" // the server doesn't expose the tool
+	ErrorCodeNotFound        ErrorCode = "not_found"     // task, project or other resource not found
+	ErrorCodeAuth            ErrorCode = "auth"          // the provider rejected the credentials
+	ErrorCodeForbidden       ErrorCode = "forbidden"     // the credentials lack a permission
+	ErrorCodeRateLimited     ErrorCode = "rate_limited"  // retry later
+	ErrorCodeUnavailable     ErrorCode = "unavailable"   // the provider can't be reached, retry later
+	ErrorCodeConfiguration   ErrorCode = "configuration" // unknown provider or missing settings
+	ErrorCodeUnsupported     ErrorCode = "unsupported"   // the provider can't do this
+	ErrorCodeProvider        ErrorCode = "provider_error"
+	ErrorCodeTimeout         ErrorCode = "timeout" // the tool ran out of time, retry or raise its timeout
+	ErrorCodeCancelled       ErrorCode = "cancelled"
+	ErrorCodeInternal        ErrorCode = "internal" // an error that couldn't be classified
+)
+
+// Retryable reports whether repeating the same call may succeed
+func (c ErrorCode) Retryable() bool {
+	switch c {
+	case ErrorCodeRateLimited, ErrorCodeUnavailable, ErrorCodeTimeout:
+		return true
+	}
+	return false
+}
+
+// errorCode classifies an error returned by a provider, the registry or an
+// AI chain
+func errorCode(err error) ErrorCode {
+	switch {
+	case err == nil:
+		return ""
+	case errors.Is(err, context.DeadlineExceeded):
+		return ErrorCodeTimeout
+	case errors.Is(err, context.Canceled):
+		return ErrorCodeCancelled
+	}
+
+	errorType, ok := providers.ClassifyError(err)
+	if !ok {
+		return ErrorCodeInternal
+	}
+	switch errorType {
+	case providers.ErrorTypeValidation:
+		return ErrorCodeInvalidArgument
+	case providers.ErrorTypeNotFound:
+		return ErrorCodeNotFound
+	case providers.ErrorTypeUnauthorized:
+		return ErrorCodeAuth
+	case providers.ErrorTypeForbidden:
+		return ErrorCodeForbidden
+	case providers.ErrorTypeRateLimit:
+		return ErrorCodeRateLimited
+	case providers.ErrorTypeNetwork:
+		return ErrorCodeUnavailable
+	case providers.ErrorTypeConfiguration:
+		return ErrorCodeConfiguration
+	case providers.ErrorTypeUnsupported:
+		return ErrorCodeUnsupported
+	default:
+		return ErrorCodeProvider
+	}
+}
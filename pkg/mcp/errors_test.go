@@ -0,0 +1,49 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/grik-ai/ricochet-task/pkg/providers"
+	"github.com/stretchr/testify/assert"
+)
+
+// statusError is a client error carrying an HTTP status, like YouTrackError
+type statusError int
+
+func (e statusError) Error() string { return fmt.Sprintf("status %d", int(e)) }
+
+func (e statusError) ErrorType() providers.ErrorType { return providers.ErrorTypeForStatus(int(e)) }
+
+func TestErrorCode(t *testing.T) {
+	tests := []struct {
+		err  error
+		want ErrorCode
+	}{
+		{providers.ErrTaskNotFound, ErrorCodeNotFound},
+		{fmt.Errorf("failed to get task: %w", providers.ErrTaskNotFound), ErrorCodeNotFound},
+		{providers.NewValidationError("title is required", nil), ErrorCodeInvalidArgument},
+		{providers.NewProviderError(providers.ErrorTypeConfiguration, "provider not found: jira", nil), ErrorCodeConfiguration},
+		{fmt.Errorf("failed to list tasks: %w", statusError(401)), ErrorCodeAuth},
+		{statusError(403), ErrorCodeForbidden},
+		{statusError(429), ErrorCodeRateLimited},
+		{statusError(503), ErrorCodeUnavailable},
+		{statusError(500), ErrorCodeProvider},
+		{&net.OpError{Op: "dial", Err: errors.New("connection refused")}, ErrorCodeUnavailable},
+		{fmt.Errorf("request failed: %w", context.DeadlineExceeded), ErrorCodeTimeout},
+		{context.Canceled, ErrorCodeCancelled},
+		{errors.New("something broke"), ErrorCodeInternal},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, errorCode(tt.err), tt.err.Error())
+	}
+	assert.Equal(t, ErrorCode(""), errorCode(nil))
+
+	assert.True(t, ErrorCodeRateLimited.Retryable())
+	assert.True(t, ErrorCodeUnavailable.Retryable())
+	assert.False(t, ErrorCodeAuth.Retryable())
+	assert.False(t, ErrorCodeNotFound.Retryable())
+}
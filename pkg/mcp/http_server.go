@@ -16,6 +16,7 @@ type HTTPServer struct {
 	toolProvider *MCPToolProvider
 	logger       *logrus.Logger
 	server       *http.Server
+	problems     []string // Failed startup checks the server runs degraded with
 }
 
 // NewHTTPServer creates a new HTTP server for MCP tools
@@ -30,6 +31,27 @@ func NewHTTPServer(registry *providers.ProviderRegistry, logger *logrus.Logger)
 	}
 }
 
+// SetToolTimeouts sets how long tools may run, see ToolTimeouts
+func (s *HTTPServer) SetToolTimeouts(timeouts ToolTimeouts) {
+	s.toolProvider.SetToolTimeouts(timeouts)
+}
+
+// SetToolAccess sets which tools the server exposes
+func (s *HTTPServer) SetToolAccess(access ToolAccess) {
+	s.toolProvider.SetToolAccess(access)
+}
+
+// SetAuditLog makes the server record every tool call in audit
+func (s *HTTPServer) SetAuditLog(audit *AuditLog) {
+	s.toolProvider.SetAuditLog(audit)
+}
+
+// SetDegraded marks the server as running with failed startup checks, which
+// /health reports instead of a healthy status. Call it before Start.
+func (s *HTTPServer) SetDegraded(problems []string) {
+	s.problems = problems
+}
+
 // ToolListResponse represents the response for listing tools
 type ToolListResponse struct {
 	Tools []ToolDefinition `json:"tools"`
@@ -43,9 +65,12 @@ type ToolExecuteRequest struct {
 
 // ToolExecuteResponse represents the response from tool execution
 type ToolExecuteResponse struct {
-	Content []map[string]interface{} `json:"content"`
-	IsError bool                     `json:"isError"`
-	Error   *string                  `json:"error,omitempty"`
+	Content   []map[string]interface{} `json:"content"`
+	Data      interface{}              `json:"data,omitempty"`
+	IsError   bool                     `json:"isError"`
+	Error     *string                  `json:"error,omitempty"`
+	ErrorCode ErrorCode                `json:"errorCode,omitempty"`
+	Retryable bool                     `json:"retryable,omitempty"`
 }
 
 // Start starts the HTTP server
@@ -57,7 +82,7 @@ func (s *HTTPServer) Start(addr string) error {
 		return func(w http.ResponseWriter, r *http.Request) {
 			w.Header().Set("Access-Control-Allow-Origin", "*")
 			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
-			w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+			w.Header().Set("Access-Control-Allow-Headers", "Content-Type, "+CallerHeader)
 			
 			if r.Method == "OPTIONS" {
 				w.WriteHeader(http.StatusOK)
@@ -73,11 +98,19 @@ func (s *HTTPServer) Start(addr string) error {
 	mux.HandleFunc("/tools", corsHandler(s.handleTools))
 	mux.HandleFunc("/tools/execute", corsHandler(s.handleToolExecute))
 
+	// Responses must be writable for as long as the slowest tool may run
+	writeTimeout := 30 * time.Second
+	if longest := s.toolProvider.ToolTimeouts().Max(); longest == 0 {
+		writeTimeout = 0
+	} else if longest+10*time.Second > writeTimeout {
+		writeTimeout = longest + 10*time.Second
+	}
+
 	s.server = &http.Server{
 		Addr:    addr,
 		Handler: mux,
 		ReadTimeout:  30 * time.Second,
-		WriteTimeout: 30 * time.Second,
+		WriteTimeout: writeTimeout,
 	}
 
 	s.logger.Infof("Starting MCP HTTP server on %s", addr)
@@ -105,6 +138,10 @@ func (s *HTTPServer) handleHealth(w http.ResponseWriter, r *http.Request) {
 		"version": "1.0.0",
 		"time":    time.Now().UTC().Format(time.RFC3339),
 	}
+	if len(s.problems) > 0 {
+		response["status"] = "degraded"
+		response["problems"] = s.problems
+	}
 
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(response)
@@ -144,17 +181,25 @@ func (s *HTTPServer) handleToolExecute(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-	defer cancel()
+	// The tool stops when the client disconnects or its timeout passes
+	caller := Caller{
+		Name:      r.Header.Get(CallerHeader),
+		Address:   r.RemoteAddr,
+		UserAgent: r.UserAgent(),
+	}
+	ctx := WithCaller(r.Context(), caller)
 
-	s.logger.Infof("Executing tool: %s", req.Name)
+	s.logger.Infof("Executing tool: %s (caller %s)", req.Name, caller)
 
 	result, err := s.toolProvider.ExecuteTool(ctx, req.Name, req.Arguments)
 	if err != nil {
 		s.logger.Errorf("Tool execution failed: %v", err)
+		code := errorCode(err)
 		response := ToolExecuteResponse{
-			IsError: true,
-			Error:   stringPtr(fmt.Sprintf("Tool execution failed: %v", err)),
+			IsError:   true,
+			Error:     stringPtr(fmt.Sprintf("Tool execution failed: %v", err)),
+			ErrorCode: code,
+			Retryable: code.Retryable(),
 		}
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusInternalServerError)
@@ -163,9 +208,12 @@ func (s *HTTPServer) handleToolExecute(w http.ResponseWriter, r *http.Request) {
 	}
 
 	response := ToolExecuteResponse{
-		Content: result.Content,
-		IsError: result.Error != nil,
-		Error:   result.Error,
+		Content:   result.Content,
+		Data:      result.Data,
+		IsError:   result.Error != nil,
+		Error:     result.Error,
+		ErrorCode: result.ErrorCode,
+		Retryable: result.ErrorCode.Retryable(),
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -0,0 +1,106 @@
+package mcp
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grik-ai/ricochet-task/pkg/providers"
+)
+
+// newTestHTTPServer returns a server over a registry with one provider
+func newTestHTTPServer(t *testing.T) *HTTPServer {
+	t.Helper()
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	return NewHTTPServer(newExpandRegistry(t, &providers.UniversalTask{ID: "1"}), logger)
+}
+
+func TestHTTPServerHealth(t *testing.T) {
+	server := newTestHTTPServer(t)
+
+	t.Run("Healthy", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		server.handleHealth(rec, httptest.NewRequest(http.MethodGet, "/health", nil))
+
+		require.Equal(t, http.StatusOK, rec.Code)
+		var response map[string]interface{}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		assert.Equal(t, "healthy", response["status"])
+	})
+
+	t.Run("Degraded", func(t *testing.T) {
+		server.SetDegraded([]string{"config: invalid"})
+		defer server.SetDegraded(nil)
+
+		rec := httptest.NewRecorder()
+		server.handleHealth(rec, httptest.NewRequest(http.MethodGet, "/health", nil))
+
+		var response map[string]interface{}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		assert.Equal(t, "degraded", response["status"])
+		assert.Equal(t, []interface{}{"config: invalid"}, response["problems"])
+	})
+
+	t.Run("Wrong method", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		server.handleHealth(rec, httptest.NewRequest(http.MethodPost, "/health", nil))
+		assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+	})
+}
+
+func TestHTTPServerTools(t *testing.T) {
+	server := newTestHTTPServer(t)
+	server.SetToolAccess(ToolAccess{Disabled: []string{"providers_add"}})
+
+	rec := httptest.NewRecorder()
+	server.handleTools(rec, httptest.NewRequest(http.MethodGet, "/tools", nil))
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	var response ToolListResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Len(t, response.Tools, len(ToolNames())-1)
+	for _, tool := range response.Tools {
+		assert.NotEqual(t, "providers_add", tool.Name)
+	}
+}
+
+func TestHTTPServerToolExecute(t *testing.T) {
+	server := newTestHTTPServer(t)
+
+	execute := func(body string) (*httptest.ResponseRecorder, ToolExecuteResponse) {
+		rec := httptest.NewRecorder()
+		server.handleToolExecute(rec, httptest.NewRequest(http.MethodPost, "/tools/execute", strings.NewReader(body)))
+		var response ToolExecuteResponse
+		if rec.Code != http.StatusBadRequest {
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		}
+		return rec, response
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		rec, response := execute(`{"name": "providers_list", "arguments": {"output_format": "summary"}}`)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.False(t, response.IsError)
+		assert.NotEmpty(t, response.Content)
+	})
+
+	t.Run("Unknown tool", func(t *testing.T) {
+		rec, response := execute(`{"name": "unknown_tool"}`)
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.True(t, response.IsError)
+		assert.Equal(t, ErrorCodeUnknownTool, response.ErrorCode)
+	})
+
+	t.Run("Invalid body", func(t *testing.T) {
+		rec, _ := execute(`{`)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+}
@@ -0,0 +1,83 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultToolTimeout bounds the tools that only call providers
+	DefaultToolTimeout = 30 * time.Second
+	// DefaultAIToolTimeout bounds the ai_* tools, which wait for model responses
+	DefaultAIToolTimeout = 2 * time.Minute
+)
+
+// ToolTimeouts sets how long tools may run before their work is cancelled.
+// A zero duration means no limit.
+type ToolTimeouts struct {
+	Default time.Duration            // Tools that only call providers
+	AI      time.Duration            // Tools whose names start with ai_
+	PerTool map[string]time.Duration // Overrides by tool name
+}
+
+// DefaultToolTimeouts returns the timeouts tools run with unless the server
+// configures others
+func DefaultToolTimeouts() ToolTimeouts {
+	return ToolTimeouts{Default: DefaultToolTimeout, AI: DefaultAIToolTimeout}
+}
+
+// For returns the timeout of a tool
+func (t ToolTimeouts) For(name string) time.Duration {
+	if timeout, ok := t.PerTool[name]; ok {
+		return timeout
+	}
+	if strings.HasPrefix(name, "ai_") {
+		return t.AI
+	}
+	return t.Default
+}
+
+// Max returns the longest timeout, or zero if any tool has no limit
+func (t ToolTimeouts) Max() time.Duration {
+	timeouts := []time.Duration{t.Default, t.AI}
+	for _, timeout := range t.PerTool {
+		timeouts = append(timeouts, timeout)
+	}
+
+	var longest time.Duration
+	for _, timeout := range timeouts {
+		if timeout == 0 {
+			return 0
+		}
+		if timeout > longest {
+			longest = timeout
+		}
+	}
+	return longest
+}
+
+// withToolTimeout bounds ctx by the timeout of a tool
+func (t ToolTimeouts) withToolTimeout(ctx context.Context, name string) (context.Context, context.CancelFunc) {
+	if timeout := t.For(name); timeout > 0 {
+		return context.WithTimeout(ctx, timeout)
+	}
+	return context.WithCancel(ctx)
+}
+
+// interruptedResult describes a tool whose context ended before it finished,
+// or returns nil if the context is still live
+func interruptedResult(ctx context.Context, name string, timeout time.Duration) *ToolResult {
+	var message string
+	switch err := ctx.Err(); {
+	case err == nil:
+		return nil
+	case errors.Is(err, context.DeadlineExceeded):
+		message = fmt.Sprintf("Tool %s timed out after %s", name, timeout)
+	default:
+		message = fmt.Sprintf("Tool %s was cancelled", name)
+	}
+	return &ToolResult{Error: &message, ErrorCode: errorCode(ctx.Err())}
+}
@@ -0,0 +1,45 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToolTimeouts(t *testing.T) {
+	timeouts := ToolTimeouts{
+		Default: 30 * time.Second,
+		AI:      2 * time.Minute,
+		PerTool: map[string]time.Duration{"ai_create_project_plan": 10 * time.Minute},
+	}
+
+	assert.Equal(t, 30*time.Second, timeouts.For("task_list_unified"))
+	assert.Equal(t, 2*time.Minute, timeouts.For("ai_analyze_project"))
+	assert.Equal(t, 10*time.Minute, timeouts.For("ai_create_project_plan"))
+	assert.Equal(t, 10*time.Minute, timeouts.Max())
+
+	timeouts.PerTool["task_list_unified"] = 0
+	assert.Equal(t, time.Duration(0), timeouts.Max())
+}
+
+func TestInterruptedResult(t *testing.T) {
+	assert.Nil(t, interruptedResult(context.Background(), "task_list_unified", time.Second))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	result := interruptedResult(ctx, "task_list_unified", time.Second)
+	require.NotNil(t, result)
+	assert.Equal(t, "Tool task_list_unified was cancelled", *result.Error)
+	assert.Equal(t, ErrorCodeCancelled, result.ErrorCode)
+
+	ctx, cancel = context.WithTimeout(context.Background(), time.Nanosecond)
+	defer cancel()
+	<-ctx.Done()
+	result = interruptedResult(ctx, "ai_analyze_project", 2*time.Minute)
+	require.NotNil(t, result)
+	assert.Equal(t, "Tool ai_analyze_project timed out after 2m0s", *result.Error)
+	assert.Equal(t, ErrorCodeTimeout, result.ErrorCode)
+}
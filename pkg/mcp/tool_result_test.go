@@ -0,0 +1,88 @@
+package mcp
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/grik-ai/ricochet-task/pkg/ai"
+	"github.com/grik-ai/ricochet-task/pkg/providers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToolResultData(t *testing.T) {
+	data := newTaskListData(&providers.TaskPage{
+		Tasks:      []*providers.UniversalTask{{ID: "1", Key: "OPS-1", Title: "Rotate certificates"}},
+		Failures:   []providers.ProviderFailure{{Provider: "jira", Err: errors.New("connection refused")}},
+		HasMore:    true,
+		NextCursor: "next",
+	})
+	assert.Equal(t, 1, data.Count)
+	assert.Equal(t, []ProviderFailureData{{Provider: "jira", Error: "connection refused"}}, data.Failures)
+
+	encoded, err := json.Marshal(&ToolResult{Data: data})
+	require.NoError(t, err)
+	var decoded struct {
+		Data struct {
+			Tasks []struct {
+				Key string `json:"key"`
+			} `json:"tasks"`
+			Count      int    `json:"count"`
+			HasMore    bool   `json:"has_more"`
+			NextCursor string `json:"next_cursor"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(encoded, &decoded))
+	assert.Equal(t, "OPS-1", decoded.Data.Tasks[0].Key)
+	assert.True(t, decoded.Data.HasMore)
+	assert.Equal(t, "next", decoded.Data.NextCursor)
+
+	// An empty list is still an array, a result without data has no data key
+	encoded, err = json.Marshal(&ToolResult{Data: newTaskListData(&providers.TaskPage{})})
+	require.NoError(t, err)
+	assert.Contains(t, string(encoded), `"tasks":[]`)
+
+	encoded, err = json.Marshal(&ToolResult{})
+	require.NoError(t, err)
+	assert.NotContains(t, string(encoded), "data")
+}
+
+func TestProjectAnalysisData(t *testing.T) {
+	data := newProjectAnalysisData(&ai.ProjectAnalysis{
+		Complexity:     "medium",
+		EstimatedHours: 40,
+		Tasks: []ai.TaskSuggestion{
+			{Title: "Design schema", Priority: "High", Type: "design", Hours: 8, Tags: []string{"db"}},
+			{Title: "Fix login", Priority: "urgent", Type: "bugfix", Dependencies: []string{"Design schema"}},
+			{Title: "Evaluate queues", Priority: "low", Type: "Research"},
+		},
+	})
+
+	require.Len(t, data.SuggestedTasks, 3)
+	assert.Equal(t, SuggestedTaskData{Title: "Design schema", Type: "task", Priority: "high", Labels: []string{"db"}, EstimatedHours: 8}, data.SuggestedTasks[0])
+	assert.Equal(t, "bug", data.SuggestedTasks[1].Type)
+	assert.Equal(t, "medium", data.SuggestedTasks[1].Priority, "unknown priorities fall back to medium")
+	assert.Equal(t, []string{"Design schema"}, data.SuggestedTasks[1].DependsOn)
+	assert.Equal(t, "research", data.SuggestedTasks[2].Type)
+
+	encoded, err := json.Marshal(data)
+	require.NoError(t, err)
+	assert.Contains(t, string(encoded), `"risks":[]`)
+	assert.Contains(t, string(encoded), `"estimated_hours":40`)
+}
+
+func TestExecutionSteps(t *testing.T) {
+	plan := `## Development Guide
+
+### Implementation Strategy
+1. **Setup & Environment**
+   - Configure development environment
+   1. nested detail
+2) Core development:
+3. __Integration__
+
+- Follow coding standards`
+	assert.Equal(t, []string{"Setup & Environment", "Core development", "Integration"}, executionSteps(plan))
+	assert.Equal(t, []string{}, executionSteps("No steps here"))
+}
@@ -4,9 +4,11 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"regexp"
 	"strings"
 	"time"
 
+	appconfig "github.com/grik-ai/ricochet-task/internal/config"
 	"github.com/grik-ai/ricochet-task/pkg/ai"
 	"github.com/grik-ai/ricochet-task/pkg/providers"
 )
@@ -15,6 +17,10 @@ import (
 type MCPToolProvider struct {
 	registry  *providers.ProviderRegistry
 	aiChains  *ai.AIChains
+	watchers  *providers.TaskWatchRegistry
+	timeouts  ToolTimeouts
+	access    ToolAccess
+	audit     *AuditLog
 }
 
 // NewMCPToolProvider creates a new MCP tool provider
@@ -25,10 +31,74 @@ func NewMCPToolProvider(registry *providers.ProviderRegistry) *MCPToolProvider {
 	// For now, initialize with empty values - these should be provided via config
 	aiChains := ai.NewAIChains("", "", "", nil, logger)
 	
+	// Watchers are shared with the CLI through the same store; the registry's
+	// event bus delivers task changes to them
+	watchers, err := providers.NewTaskWatchRegistry(appconfig.ProfilePath(providers.WatchersFile), nil)
+	if err != nil {
+		logger.Error("Failed to load task watchers", err)
+	}
+
+	var access ToolAccess
+	if registry != nil {
+		access = NewToolAccess(registry.MCPConfig())
+	}
+
 	return &MCPToolProvider{
 		registry: registry,
 		aiChains: aiChains,
+		watchers: watchers,
+		timeouts: DefaultToolTimeouts(),
+		access:   access,
+	}
+}
+
+// SetToolTimeouts sets how long tools may run
+func (m *MCPToolProvider) SetToolTimeouts(timeouts ToolTimeouts) {
+	m.timeouts = timeouts
+}
+
+// ToolTimeouts returns how long tools may run
+func (m *MCPToolProvider) ToolTimeouts() ToolTimeouts {
+	return m.timeouts
+}
+
+// SetToolAccess sets which tools are exposed. By default the mcp section of
+// the provider configuration decides.
+func (m *MCPToolProvider) SetToolAccess(access ToolAccess) {
+	m.access = access
+}
+
+// SetAuditLog makes every tool call recorded in audit. A nil log turns
+// auditing off.
+func (m *MCPToolProvider) SetAuditLog(audit *AuditLog) {
+	m.audit = audit
+}
+
+// ToolNames returns the names of all tools, whether exposed or not
+func (m *MCPToolProvider) ToolNames() []string {
+	return ToolNames()
+}
+
+// ToolNames returns the names of all tools, whether exposed or not. Unlike
+// NewMCPToolProvider it needs no providers, so commands can validate tool
+// names without setting up a provider.
+func ToolNames() []string {
+	tools := allTools()
+	names := make([]string, 0, len(tools))
+	for _, tool := range tools {
+		names = append(names, tool.Name)
+	}
+	return names
+}
+
+// isTool reports whether a tool of that name exists
+func (m *MCPToolProvider) isTool(name string) bool {
+	for _, tool := range allTools() {
+		if tool.Name == name {
+			return true
+		}
 	}
+	return false
 }
 
 // SimpleLogger implements the Logger interface for MCP
@@ -57,14 +127,255 @@ type ToolDefinition struct {
 	InputSchema map[string]interface{} `json:"inputSchema"`
 }
 
-// ToolResult represents the result of executing an MCP tool
+// ToolResult represents the result of executing an MCP tool. Content is the
+// rendered text for people; Data optionally carries the same result as typed
+// JSON for programmatic clients. A failed result has an Error message and an
+// ErrorCode classifying it.
 type ToolResult struct {
-	Content []map[string]interface{} `json:"content"`
-	Error   *string                  `json:"error,omitempty"`
+	Content   []map[string]interface{} `json:"content"`
+	Data      interface{}              `json:"data,omitempty"`
+	Error     *string                  `json:"error,omitempty"`
+	ErrorCode ErrorCode                `json:"error_code,omitempty"`
+}
+
+// ProvidersListData is the structured result of providers_list
+type ProvidersListData struct {
+	Providers map[string]*providers.ProviderInfo `json:"providers"`
+}
+
+// TaskListData is the structured result of task_list_unified and
+// cross_provider_search, one page of tasks
+type TaskListData struct {
+	Tasks      []*providers.UniversalTask `json:"tasks"`
+	Count      int                        `json:"count"`
+	HasMore    bool                       `json:"has_more"`
+	NextCursor string                     `json:"next_cursor,omitempty"` // Passed as cursor to get the next page
+	Failures   []ProviderFailureData      `json:"failures,omitempty"`
+}
+
+// ProviderFailureData names a provider whose tasks are missing from a list
+type ProviderFailureData struct {
+	Provider string `json:"provider"`
+	Error    string `json:"error"`
+}
+
+// TaskCreateData is the structured result of task_create_smart. Task is nil
+// when possible duplicates stopped the creation.
+type TaskCreateData struct {
+	Task        *providers.UniversalTask       `json:"task,omitempty"`
+	DuplicateOf string                         `json:"duplicateOf,omitempty"`
+	Warning     string                         `json:"warning,omitempty"`
+	Duplicates  []*providers.DuplicateCandidate `json:"duplicates,omitempty"`
+}
+
+// ProjectAnalysisData is the structured result of ai_analyze_project
+type ProjectAnalysisData struct {
+	Description    string              `json:"description"`
+	Complexity     string              `json:"complexity"`
+	EstimatedHours int                 `json:"estimated_hours"`
+	Technologies   []string            `json:"technologies"`
+	Risks          []string            `json:"risks"`
+	Dependencies   []string            `json:"dependencies"`
+	SuggestedTasks []SuggestedTaskData `json:"suggested_tasks"`
+}
+
+// SuggestedTaskData is a task suggested by an analysis. Its fields are named
+// like the arguments of task_create_smart, so it can be passed on as is.
+type SuggestedTaskData struct {
+	Title          string   `json:"title"`
+	Description    string   `json:"description,omitempty"`
+	Type           string   `json:"type"`
+	Priority       string   `json:"priority"`
+	Labels         []string `json:"labels,omitempty"`
+	EstimatedHours int      `json:"estimated_hours,omitempty"`
+	DependsOn      []string `json:"depends_on,omitempty"` // Titles of suggested tasks to do first
+}
+
+// newProjectAnalysisData converts an AI analysis, mapping the free-form
+// priorities and types of its tasks to the values task_create_smart accepts
+func newProjectAnalysisData(analysis *ai.ProjectAnalysis) *ProjectAnalysisData {
+	data := &ProjectAnalysisData{
+		Description:    analysis.Description,
+		Complexity:     analysis.Complexity,
+		EstimatedHours: analysis.EstimatedHours,
+		Technologies:   nonNilStrings(analysis.Technologies),
+		Risks:          nonNilStrings(analysis.Risks),
+		Dependencies:   nonNilStrings(analysis.Dependencies),
+		SuggestedTasks: make([]SuggestedTaskData, 0, len(analysis.Tasks)),
+	}
+	for _, task := range analysis.Tasks {
+		data.SuggestedTasks = append(data.SuggestedTasks, SuggestedTaskData{
+			Title:          task.Title,
+			Description:    task.Description,
+			Type:           suggestedTaskType(task.Type),
+			Priority:       suggestedTaskPriority(task.Priority),
+			Labels:         task.Tags,
+			EstimatedHours: task.Hours,
+			DependsOn:      task.Dependencies,
+		})
+	}
+	return data
+}
+
+// suggestedTaskType maps the work kinds AI analyses use to task types
+func suggestedTaskType(kind string) string {
+	kind = strings.ToLower(strings.TrimSpace(kind))
+	switch {
+	case kind == "bugfix" || kind == "fix":
+		return string(providers.TaskTypeBug)
+	case providers.TaskType(kind).IsValid():
+		return kind
+	default:
+		return string(providers.TaskTypeTask)
+	}
+}
+
+// suggestedTaskPriority returns the priority if task_create_smart accepts it, medium otherwise
+func suggestedTaskPriority(priority string) string {
+	priority = strings.ToLower(strings.TrimSpace(priority))
+	if !providers.TaskPriority(priority).IsValid() {
+		return string(providers.TaskPriorityMedium)
+	}
+	return priority
+}
+
+func nonNilStrings(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
+
+// TaskExecutionData is the structured result of ai_execute_task
+type TaskExecutionData struct {
+	TaskID   string              `json:"task_id"`
+	Mode     string              `json:"mode"`
+	Plan     string              `json:"plan"`
+	Steps    []string            `json:"steps"`
+	Subtasks []ExecutionStepData `json:"subtasks,omitempty"`
+	Status   string              `json:"status,omitempty"` // Status the task was moved to, if it was
+	Warnings []string            `json:"warnings,omitempty"`
+}
+
+// ExecutionStepData is a subtask created for a step of an execution plan.
+// TaskID is empty and Error set when the subtask couldn't be created.
+type ExecutionStepData struct {
+	Step   string `json:"step"`
+	TaskID string `json:"task_id,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// ProgressTrackingData is the structured result of ai_track_progress
+type ProgressTrackingData struct {
+	Tasks           []TaskProgressData `json:"tasks"`
+	OverallProgress int                `json:"overall_progress"` // Average progress of the tasks, in percent
+	DryRun          bool               `json:"dry_run"`
+	Warnings        []string           `json:"warnings,omitempty"`
+}
+
+// TaskProgressData is the progress of one task. With dry_run, NewStatus and
+// Comment are what would have been applied.
+type TaskProgressData struct {
+	TaskID            string                   `json:"task_id"`
+	Title             string                   `json:"title"`
+	Status            string                   `json:"status"`
+	Progress          int                      `json:"progress"`
+	Source            providers.ProgressSource `json:"source"`
+	Subtasks          int                      `json:"subtasks,omitempty"`
+	CompletedSubtasks int                      `json:"completed_subtasks,omitempty"`
+	NewStatus         string                   `json:"new_status,omitempty"`
+	Comment           string                   `json:"comment,omitempty"`
+	CommentAdded      bool                     `json:"comment_added,omitempty"`
+	Error             string                   `json:"error,omitempty"`
+}
+
+// executionModes maps the modes of ai_execute_task to the kinds of guidance
+// AIChains.ExecuteTask writes
+var executionModes = map[string]string{
+	"plan":      "planning",
+	"implement": "development",
+	"test":      "testing",
+	"review":    "code review",
+	"full":      "development",
+}
+
+// maxExecutionSteps caps the subtasks one execution creates
+const maxExecutionSteps = 10
+
+var executionStep = regexp.MustCompile(`^\d+[.)]\s+(.+)$`)
+
+// executionSteps returns the top-level numbered steps of an execution plan,
+// without markdown emphasis. Indented items are details of a step.
+func executionSteps(plan string) []string {
+	steps := []string{}
+	for _, line := range strings.Split(plan, "\n") {
+		match := executionStep.FindStringSubmatch(strings.TrimRight(line, " \r"))
+		if match == nil {
+			continue
+		}
+		step := strings.TrimSpace(strings.NewReplacer("**", "", "__", "").Replace(match[1]))
+		step = strings.TrimSuffix(step, ":")
+		if step != "" {
+			steps = append(steps, step)
+		}
+		if len(steps) == maxExecutionSteps {
+			break
+		}
+	}
+	return steps
+}
+
+// newTaskListData collects the tasks, position and provider failures of a page
+func newTaskListData(page *providers.TaskPage) *TaskListData {
+	data := &TaskListData{Tasks: page.Tasks, Count: len(page.Tasks), HasMore: page.HasMore, NextCursor: page.NextCursor}
+	if data.Tasks == nil {
+		data.Tasks = []*providers.UniversalTask{}
+	}
+	for _, failure := range page.Failures {
+		data.Failures = append(data.Failures, ProviderFailureData{Provider: failure.Provider, Error: failure.Err.Error()})
+	}
+	return data
+}
+
+// Paging arguments shared by the list and search tools
+var (
+	pagingOffsetSchema = map[string]interface{}{
+		"type":        "integer",
+		"description": "Number of tasks to skip on the first page; tasks are listed provider by provider, so with several providers it skips tasks of the first one",
+		"minimum":     0,
+	}
+	pagingCursorSchema = map[string]interface{}{
+		"type":        "string",
+		"description": "next_cursor of the previous page, to get the next one with the same filters and providers",
+	}
+)
+
+// pageNote tells how to get the next page in text output
+func pageNote(page *providers.TaskPage) string {
+	if !page.HasMore {
+		return ""
+	}
+	return fmt.Sprintf("\nMore tasks available, call again with cursor=%s\n", page.NextCursor)
 }
 
-// GetTools returns all available MCP tools
+// GetTools returns the tools the server exposes
 func (m *MCPToolProvider) GetTools() []ToolDefinition {
+	return ToolDefinitions(m.access)
+}
+
+// ToolDefinitions returns the tools access exposes
+func ToolDefinitions(access ToolAccess) []ToolDefinition {
+	var tools []ToolDefinition
+	for _, tool := range allTools() {
+		if access.Allows(tool.Name) {
+			tools = append(tools, tool)
+		}
+	}
+	return tools
+}
+
+// allTools returns all tools, whether exposed or not
+func allTools() []ToolDefinition {
 	return []ToolDefinition{
 		// Provider management tools
 		{
@@ -100,7 +411,7 @@ func (m *MCPToolProvider) GetTools() []ToolDefinition {
 					},
 					"include_details": map[string]interface{}{
 						"type":        "boolean",
-						"description": "Include detailed health information",
+						"description": "Include latency and error details",
 						"default":     false,
 					},
 				},
@@ -119,7 +430,7 @@ func (m *MCPToolProvider) GetTools() []ToolDefinition {
 					},
 					"type": map[string]interface{}{
 						"type":        "string",
-						"enum":        []string{"youtrack", "jira", "notion", "linear"},
+						"enum":        providers.RegisteredProviderTypes(),
 						"description": "Provider type",
 					},
 					"base_url": map[string]interface{}{
@@ -162,7 +473,7 @@ func (m *MCPToolProvider) GetTools() []ToolDefinition {
 					},
 					"project_id": map[string]interface{}{
 						"type":        "string",
-						"description": "Project ID",
+						"description": "Project ID; defaults to the defaultProject of the provider",
 					},
 					"task_type": map[string]interface{}{
 						"type":        "string",
@@ -178,12 +489,40 @@ func (m *MCPToolProvider) GetTools() []ToolDefinition {
 					},
 					"assignee": map[string]interface{}{
 						"type":        "string",
-						"description": "Assignee ID or username",
+						"description": "Assignee ID or username, or \"me\" for the current user",
 					},
 					"labels": map[string]interface{}{
 						"type":        "array",
 						"items":       map[string]interface{}{"type": "string"},
-						"description": "Task labels",
+						"description": "Task labels, which must exist in the provider unless create_labels is set",
+					},
+					"due_date": map[string]interface{}{
+						"type":        "string",
+						"description": "Due date: " + providers.DueDateFormats + ", in the configured time zone",
+					},
+					"create_labels": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Create labels that don't exist in the provider instead of rejecting them",
+						"default":     false,
+					},
+					"check_duplicates": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Search for similar tasks first; if any are found, they are returned and nothing is created",
+						"default":     false,
+					},
+					"ai_similarity": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Score possible duplicates with AI instead of by common words",
+						"default":     false,
+					},
+					"duplicate_of": map[string]interface{}{
+						"type":        "string",
+						"description": "Create the task linked as a duplicate of this task",
+					},
+					"create_anyway": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Create the task even though possible duplicates were found",
+						"default":     false,
 					},
 				},
 				"required":             []string{"title"},
@@ -207,29 +546,58 @@ func (m *MCPToolProvider) GetTools() []ToolDefinition {
 					},
 					"assignee": map[string]interface{}{
 						"type":        "string",
-						"description": "Filter by assignee",
+						"description": "Filter by assignee, \"me\" for the current user of each provider",
 					},
 					"project_id": map[string]interface{}{
 						"type":        "string",
-						"description": "Filter by project",
+						"description": "Filter by project; defaults to the defaultProject of each provider, \"\" for all projects",
 					},
 					"priority": map[string]interface{}{
 						"type":        "string",
 						"description": "Filter by priority",
 					},
+					"labels_all": map[string]interface{}{
+						"type":        "array",
+						"items":       map[string]interface{}{"type": "string"},
+						"description": "Only tasks with all of these labels",
+					},
+					"labels_any": map[string]interface{}{
+						"type":        "array",
+						"items":       map[string]interface{}{"type": "string"},
+						"description": "Only tasks with at least one of these labels",
+					},
+					"labels_none": map[string]interface{}{
+						"type":        "array",
+						"items":       map[string]interface{}{"type": "string"},
+						"description": "Only tasks with none of these labels",
+					},
+					"due_after": map[string]interface{}{
+						"type":        "string",
+						"description": "Only tasks due at or after this date (" + providers.DueDateFormats + ")",
+					},
+					"due_before": map[string]interface{}{
+						"type":        "string",
+						"description": "Only tasks due before this date (" + providers.DueDateFormats + ")",
+					},
 					"limit": map[string]interface{}{
 						"type":        "integer",
-						"description": "Maximum number of tasks to return",
+						"description": "Maximum number of tasks per page",
 						"default":     50,
 						"minimum":     1,
 						"maximum":     500,
 					},
+					"offset": pagingOffsetSchema,
+					"cursor": pagingCursorSchema,
 					"output_format": map[string]interface{}{
 						"type":        "string",
-						"enum":        []string{"table", "json", "summary"},
+						"enum":        []string{"table", "json", "summary", "template"},
 						"description": "Output format",
 						"default":     "table",
 					},
+					"template": map[string]interface{}{
+						"type":        "string",
+						"description": "Go template applied to each task when output_format is template, e.g. {{.Key}} {{.Title}} ({{.Status.Name}})",
+					},
 				},
 				"additionalProperties": false,
 			},
@@ -267,18 +635,32 @@ func (m *MCPToolProvider) GetTools() []ToolDefinition {
 					},
 					"assignee": map[string]interface{}{
 						"type":        "string",
-						"description": "New assignee",
+						"description": "New assignee, or \"me\" for the current user",
 					},
 					"add_labels": map[string]interface{}{
 						"type":        "array",
 						"items":       map[string]interface{}{"type": "string"},
-						"description": "Labels to add",
+						"description": "Labels to add, which must exist in the provider unless create_labels is set",
 					},
 					"remove_labels": map[string]interface{}{
 						"type":        "array",
 						"items":       map[string]interface{}{"type": "string"},
 						"description": "Labels to remove",
 					},
+					"create_labels": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Create added labels that don't exist in the provider instead of rejecting them",
+						"default":     false,
+					},
+					"due_date": map[string]interface{}{
+						"type":        "string",
+						"description": "New due date: " + providers.DueDateFormats + ", in the configured time zone",
+					},
+					"preview": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Return a field-by-field diff against the current task without applying it",
+						"default":     false,
+					},
 				},
 				"required":             []string{"task_id"},
 				"additionalProperties": false,
@@ -302,11 +684,13 @@ func (m *MCPToolProvider) GetTools() []ToolDefinition {
 					},
 					"limit": map[string]interface{}{
 						"type":        "integer",
-						"description": "Maximum number of results per provider",
+						"description": "Maximum number of results per page",
 						"default":     20,
 						"minimum":     1,
 						"maximum":     100,
 					},
+					"offset": pagingOffsetSchema,
+					"cursor": pagingCursorSchema,
 					"include_content": map[string]interface{}{
 						"type":        "boolean",
 						"description": "Include task descriptions in results",
@@ -321,7 +705,7 @@ func (m *MCPToolProvider) GetTools() []ToolDefinition {
 		// AI Integration tools
 		{
 			Name:        "ai_analyze_project",
-			Description: "AI-powered analysis of project status across providers with insights and recommendations",
+			Description: "AI-powered analysis of project status across providers with insights and recommendations; data.suggested_tasks can be passed to task_create_smart",
 			InputSchema: map[string]interface{}{
 				"type": "object",
 				"properties": map[string]interface{}{
@@ -329,6 +713,20 @@ func (m *MCPToolProvider) GetTools() []ToolDefinition {
 						"type":        "string",
 						"description": "Project ID to analyze",
 					},
+					"project_description": map[string]interface{}{
+						"type":        "string",
+						"description": "What the project is about; the analysis is based on it",
+					},
+					"project_type": map[string]interface{}{
+						"type":        "string",
+						"description": "Kind of project, e.g. feature, web_app or mobile_app",
+						"default":     "feature",
+					},
+					"code_files": map[string]interface{}{
+						"type":        "array",
+						"items":       map[string]interface{}{"type": "string"},
+						"description": "Source files to analyze along with the description",
+					},
 					"providers": map[string]interface{}{
 						"type":        "array",
 						"items":       map[string]interface{}{"type": "string"},
@@ -355,7 +753,7 @@ func (m *MCPToolProvider) GetTools() []ToolDefinition {
 		},
 		{
 			Name:        "ai_execute_task",
-			Description: "AI-powered task execution with automatic code generation, testing, and provider coordination",
+			Description: "Load a task from its provider and write an AI execution plan for it; optionally create subtasks for the plan's steps and move the task to in progress",
 			InputSchema: map[string]interface{}{
 				"type": "object",
 				"properties": map[string]interface{}{
@@ -370,17 +768,17 @@ func (m *MCPToolProvider) GetTools() []ToolDefinition {
 					"execution_mode": map[string]interface{}{
 						"type":        "string",
 						"enum":        []string{"plan", "implement", "test", "review", "full"},
-						"description": "Execution mode",
+						"description": "Kind of guidance to write: plan, implementation (implement, full), testing (test) or review",
 						"default":     "plan",
 					},
 					"auto_update_status": map[string]interface{}{
 						"type":        "boolean",
-						"description": "Automatically update task status based on execution progress",
+						"description": "Move the task to the in-progress status of its workflow",
 						"default":     true,
 					},
 					"create_subtasks": map[string]interface{}{
 						"type":        "boolean",
-						"description": "Create subtasks for implementation steps",
+						"description": "Create a subtask for each numbered step of the plan, at most 10",
 						"default":     false,
 					},
 				},
@@ -546,7 +944,7 @@ func (m *MCPToolProvider) GetTools() []ToolDefinition {
 		},
 		{
 			Name:        "ai_track_progress",
-			Description: "Track progress of AI-managed tasks and update statuses automatically",
+			Description: "Compute the progress of tasks from their subtasks or status, update their statuses and add AI progress comments",
 			InputSchema: map[string]interface{}{
 				"type": "object",
 				"properties": map[string]interface{}{
@@ -555,14 +953,22 @@ func (m *MCPToolProvider) GetTools() []ToolDefinition {
 						"items":       map[string]interface{}{"type": "string"},
 						"description": "Task IDs to track (leave empty for all AI-managed tasks)",
 					},
+					"provider": map[string]interface{}{
+						"type":        "string",
+						"description": "Provider of the tasks (defaults to the default provider)",
+					},
+					"project": map[string]interface{}{
+						"type":        "string",
+						"description": "Project to look for AI-managed tasks in when task_ids is empty",
+					},
 					"update_statuses": map[string]interface{}{
 						"type":        "boolean",
-						"description": "Automatically update task statuses based on progress",
+						"description": "Move tasks to in progress once some of their subtasks are done, and to done once all are",
 						"default":     true,
 					},
 					"add_progress_comments": map[string]interface{}{
 						"type":        "boolean",
-						"description": "Add progress comments to tasks",
+						"description": "Add an AI-generated progress comment to tasks with progress",
 						"default":     true,
 					},
 					"generate_report": map[string]interface{}{
@@ -570,15 +976,135 @@ func (m *MCPToolProvider) GetTools() []ToolDefinition {
 						"description": "Generate progress report",
 						"default":     false,
 					},
+					"dry_run": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Preview the status changes and comments without making them",
+						"default":     false,
+					},
+				},
+				"additionalProperties": false,
+			},
+		},
+		{
+			Name:        "task_watch",
+			Description: "Watch or unwatch a task to be notified about its changes",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"action": map[string]interface{}{
+						"type":        "string",
+						"enum":        []string{"watch", "unwatch", "list"},
+						"description": "Watch a task, stop watching it, or list watched tasks",
+						"default":     "watch",
+					},
+					"task_id": map[string]interface{}{
+						"type":        "string",
+						"description": "Task ID (required for watch and unwatch)",
+					},
+					"user_id": map[string]interface{}{
+						"type":        "string",
+						"description": "Watcher user ID",
+					},
+					"provider": map[string]interface{}{
+						"type":        "string",
+						"description": "Provider the task belongs to (leave empty for default)",
+					},
+				},
+				"required":             []string{"user_id"},
+				"additionalProperties": false,
+			},
+		},
+		{
+			Name:        "ai_triage_tasks",
+			Description: "Suggest priority, type, labels and assignee for untriaged tasks (missing priority, assignee or labels) with AI, based on their title, description and team context",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"provider": map[string]interface{}{
+						"type":        "string",
+						"description": "Provider name (leave empty for default)",
+					},
+					"project_id": map[string]interface{}{
+						"type":        "string",
+						"description": "Project to triage",
+					},
+					"limit": map[string]interface{}{
+						"type":        "integer",
+						"description": "Maximum number of untriaged tasks to suggest for",
+						"default":     20,
+					},
+					"apply": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Apply the suggestions instead of only presenting them for confirmation",
+						"default":     false,
+					},
+				},
+				"additionalProperties": false,
+			},
+		},
+		{
+			Name:        "ai_expand_task",
+			Description: "Generate a structured description of a task from its terse title with AI: context, acceptance criteria and technical notes. The existing description is kept above the generated sections",
+			InputSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"provider": map[string]interface{}{
+						"type":        "string",
+						"description": "Provider name (leave empty for default)",
+					},
+					"task_id": map[string]interface{}{
+						"type":        "string",
+						"description": "Task to expand",
+					},
+					"apply": map[string]interface{}{
+						"type":        "boolean",
+						"description": "Update the task description instead of only presenting it for review",
+						"default":     false,
+					},
 				},
+				"required":             []string{"task_id"},
 				"additionalProperties": false,
 			},
 		},
 	}
 }
 
-// ExecuteTool executes an MCP tool with the given parameters
+// ExecuteTool executes an MCP tool with the given parameters. Provider and AI
+// requests of the tool use ctx, bounded by the tool's timeout, so cancelling
+// ctx aborts the work in flight. The call is recorded in the audit log, if
+// one is set, with the caller carried by ctx.
 func (m *MCPToolProvider) ExecuteTool(ctx context.Context, name string, arguments map[string]interface{}) (*ToolResult, error) {
+	started := time.Now()
+	result, err := m.runTool(ctx, name, arguments)
+	if m.audit != nil {
+		m.audit.RecordCall(ctx, name, arguments, started, result, err)
+	}
+	return result, err
+}
+
+// runTool runs a tool within its timeout, unless the server doesn't expose it
+func (m *MCPToolProvider) runTool(ctx context.Context, name string, arguments map[string]interface{}) (*ToolResult, error) {
+	if !m.access.Allows(name) && m.isTool(name) {
+		return disabledToolResult(name), nil
+	}
+
+	ctx, cancel := m.timeouts.withToolTimeout(ctx, name)
+	defer cancel()
+
+	result, err := m.executeTool(ctx, name, arguments)
+	if err != nil || result == nil || result.Error != nil {
+		// Report the timeout or cancellation rather than the error it caused
+		if interrupted := interruptedResult(ctx, name, m.timeouts.For(name)); interrupted != nil {
+			return interrupted, nil
+		}
+	}
+	if result != nil && result.Error != nil && result.ErrorCode == "" {
+		result.ErrorCode = ErrorCodeInternal
+	}
+	return result, err
+}
+
+func (m *MCPToolProvider) executeTool(ctx context.Context, name string, arguments map[string]interface{}) (*ToolResult, error) {
 	switch name {
 	case "providers_list":
 		return m.executeProvidersList(ctx, arguments)
@@ -610,9 +1136,15 @@ func (m *MCPToolProvider) ExecuteTool(ctx context.Context, name string, argument
 		return m.executeAIExecutePlan(ctx, arguments)
 	case "ai_track_progress":
 		return m.executeAITrackProgress(ctx, arguments)
+	case "task_watch":
+		return m.executeTaskWatch(ctx, arguments)
+	case "ai_triage_tasks":
+		return m.executeAITriageTasks(ctx, arguments)
+	case "ai_expand_task":
+		return m.executeAIExpandTask(ctx, arguments)
 	default:
 		errorMsg := fmt.Sprintf("Unknown tool: %s", name)
-		return &ToolResult{Error: &errorMsg}, nil
+		return &ToolResult{Error: &errorMsg, ErrorCode: ErrorCodeUnknownTool}, nil
 	}
 }
 
@@ -632,86 +1164,66 @@ func (m *MCPToolProvider) executeProvidersList(ctx context.Context, args map[str
 		providerInfos = m.registry.ListProviders()
 	}
 
+	var content string
 	switch outputFormat {
 	case "json":
-		return &ToolResult{
-			Content: []map[string]interface{}{
-				{
-					"type": "text",
-					"text": m.formatProvidersJSON(providerInfos),
-				},
-			},
-		}, nil
+		content = m.formatProvidersJSON(providerInfos)
 	case "summary":
-		return &ToolResult{
-			Content: []map[string]interface{}{
-				{
-					"type": "text",
-					"text": m.formatProvidersSummary(providerInfos),
-				},
-			},
-		}, nil
+		content = m.formatProvidersSummary(providerInfos)
 	default: // table
-		return &ToolResult{
-			Content: []map[string]interface{}{
-				{
-					"type": "text",
-					"text": m.formatProvidersTable(providerInfos),
-				},
-			},
-		}, nil
+		content = m.formatProvidersTable(providerInfos)
 	}
+
+	return &ToolResult{
+		Content: []map[string]interface{}{
+			{
+				"type": "text",
+				"text": content,
+			},
+		},
+		Data: &ProvidersListData{Providers: providerInfos},
+	}, nil
 }
 
 func (m *MCPToolProvider) executeProviderHealth(ctx context.Context, args map[string]interface{}) (*ToolResult, error) {
 	providerName, _ := args["provider_name"].(string)
 	includeDetails, _ := args["include_details"].(bool)
 
+	var names []string
 	if providerName != "" {
 		// Check specific provider
-		provider, err := m.registry.GetProvider(providerName)
-		if err != nil {
+		if _, err := m.registry.GetProvider(providerName); err != nil {
 			errorMsg := fmt.Sprintf("Provider not found: %v", err)
-			return &ToolResult{Error: &errorMsg}, nil
-		}
-
-		err = provider.HealthCheck(ctx)
-		status := "🟢 HEALTHY"
-		details := ""
-		if err != nil {
-			status = "🔴 UNHEALTHY"
-			details = fmt.Sprintf("Error: %v", err)
-		}
-
-		result := fmt.Sprintf("Provider '%s': %s", providerName, status)
-		if includeDetails && details != "" {
-			result += "\n" + details
+			return &ToolResult{Error: &errorMsg, ErrorCode: errorCode(err)}, nil
 		}
-
-		return &ToolResult{
-			Content: []map[string]interface{}{
-				{
-					"type": "text",
-					"text": result,
-				},
-			},
-		}, nil
+		names = []string{providerName}
 	}
 
-	// Check all providers
-	healthStatus := m.registry.GetHealthStatus()
+	// Providers are checked concurrently, each bounded by the default timeout
+	results := m.registry.CheckHealth(ctx, names, providers.HealthCheckOptions{})
+
 	result := "Provider Health Status:\n"
 	result += "========================\n"
 
-	for name, status := range healthStatus {
+	for _, health := range results {
 		emoji := "🟢"
-		if status != providers.HealthStatusHealthy {
+		switch health.Status {
+		case providers.HealthStatusDegraded:
+			emoji = "🟡"
+		case providers.HealthStatusUnhealthy, providers.HealthStatusUnknown:
 			emoji = "🔴"
 		}
-		result += fmt.Sprintf("%s %s: %s\n", emoji, name, string(status))
-	}
+		result += fmt.Sprintf("%s %s: %s\n", emoji, health.Provider, string(health.Status))
 
-	return &ToolResult{
+		if includeDetails {
+			result += fmt.Sprintf("   Latency: %dms\n", health.LatencyMs)
+			if health.Error != "" {
+				result += fmt.Sprintf("   Error: %s\n", health.Error)
+			}
+		}
+	}
+
+	return &ToolResult{
 		Content: []map[string]interface{}{
 			{
 				"type": "text",
@@ -730,13 +1242,12 @@ func (m *MCPToolProvider) executeProvidersAdd(ctx context.Context, args map[stri
 
 	if name == "" || providerType == "" || baseURL == "" || token == "" {
 		errorMsg := "Missing required parameters: name, type, base_url, and token are required"
-		return &ToolResult{Error: &errorMsg}, nil
+		return &ToolResult{Error: &errorMsg, ErrorCode: ErrorCodeInvalidArgument}, nil
 	}
 
 	// Create provider config
-	config := providers.DefaultProviderConfig()
+	config := providers.NewProviderConfig(providers.ProviderType(providerType))
 	config.Name = name
-	config.Type = providers.ProviderType(providerType)
 	config.BaseURL = baseURL
 	config.Token = token
 	config.AuthType = providers.AuthTypeBearer
@@ -745,7 +1256,7 @@ func (m *MCPToolProvider) executeProvidersAdd(ctx context.Context, args map[stri
 	// Add provider
 	if err := m.registry.AddProvider(ctx, name, config); err != nil {
 		errorMsg := fmt.Sprintf("Failed to add provider: %v", err)
-		return &ToolResult{Error: &errorMsg}, nil
+		return &ToolResult{Error: &errorMsg, ErrorCode: errorCode(err)}, nil
 	}
 
 	result := fmt.Sprintf("✅ Provider '%s' added successfully", name)
@@ -772,10 +1283,14 @@ func (m *MCPToolProvider) executeTaskCreateSmart(ctx context.Context, args map[s
 	priorityStr, _ := args["priority"].(string)
 	assignee, _ := args["assignee"].(string)
 	labelsInterface, _ := args["labels"].([]interface{})
+	checkDuplicates, _ := args["check_duplicates"].(bool)
+	aiSimilarity, _ := args["ai_similarity"].(bool)
+	duplicateOf, _ := args["duplicate_of"].(string)
+	createAnyway, _ := args["create_anyway"].(bool)
 
 	if title == "" {
 		errorMsg := "Title is required"
-		return &ToolResult{Error: &errorMsg}, nil
+		return &ToolResult{Error: &errorMsg, ErrorCode: ErrorCodeInvalidArgument}, nil
 	}
 
 	// Convert labels
@@ -799,9 +1314,14 @@ func (m *MCPToolProvider) executeTaskCreateSmart(ctx context.Context, args map[s
 		UpdatedAt:   time.Now(),
 	}
 
-	// Determine target provider
 	var provider providers.TaskProvider
 	var err error
+	if task.DueDate, err = m.dueDateArg(args, "due_date"); err != nil {
+		errorMsg := err.Error()
+		return &ToolResult{Error: &errorMsg, ErrorCode: errorCode(err)}, nil
+	}
+
+	// Determine target provider
 
 	if providerName != "" {
 		provider, err = m.registry.GetProvider(providerName)
@@ -812,20 +1332,88 @@ func (m *MCPToolProvider) executeTaskCreateSmart(ctx context.Context, args map[s
 
 	if err != nil {
 		errorMsg := fmt.Sprintf("Failed to get provider: %v", err)
-		return &ToolResult{Error: &errorMsg}, nil
+		return &ToolResult{Error: &errorMsg, ErrorCode: errorCode(err)}, nil
+	}
+	if _, given := args["project_id"]; !given {
+		if providerName == "" {
+			providerName = m.registry.DefaultProviderName()
+		}
+		task.ProjectID = m.registry.DefaultProject(providerName)
+	}
+
+	if task.AssigneeID, err = providers.ResolveAssignee(ctx, provider, task.AssigneeID); err != nil {
+		errorMsg := fmt.Sprintf("Failed to resolve assignee: %v", err)
+		return &ToolResult{Error: &errorMsg, ErrorCode: errorCode(err)}, nil
+	}
+
+	createLabels, _ := args["create_labels"].(bool)
+	if task.Labels, err = providers.ValidateLabels(ctx, provider, task.ProjectID, task.Labels, createLabels); err != nil {
+		errorMsg := fmt.Sprintf("Invalid labels: %v", err)
+		return &ToolResult{Error: &errorMsg, ErrorCode: errorCode(err)}, nil
+	}
+
+	if checkDuplicates && duplicateOf == "" && !createAnyway {
+		options := providers.DuplicateOptions{}
+		if aiSimilarity {
+			options.Scorer = m.aiChains.DuplicateScorer()
+		}
+		candidates, err := providers.FindDuplicates(ctx, provider, task, options)
+		if err != nil {
+			errorMsg := fmt.Sprintf("Failed to check for duplicates: %v", err)
+			return &ToolResult{Error: &errorMsg, ErrorCode: errorCode(err)}, nil
+		}
+		if len(candidates) > 0 {
+			result := fmt.Sprintf("⚠️ Found %d possible duplicates, task not created:\n", len(candidates))
+			for _, candidate := range candidates {
+				result += fmt.Sprintf("• %s (%.0f%% similar): %s", candidate.Task.GetDisplayID(), candidate.Score*100, candidate.Task.Title)
+				if candidate.Task.Status.Name != "" {
+					result += fmt.Sprintf(" [%s]", candidate.Task.Status.Name)
+				}
+				result += "\n"
+			}
+			result += "\nCall again with duplicate_of set to one of them to create the task as its duplicate, or with create_anyway=true\n"
+			return &ToolResult{
+				Content: []map[string]interface{}{
+					{
+						"type": "text",
+						"text": result,
+					},
+				},
+				Data: &TaskCreateData{Duplicates: candidates},
+			}, nil
+		}
 	}
 
 	// Create task
-	createdTask, err := provider.CreateTask(ctx, task)
-	if err != nil {
-		errorMsg := fmt.Sprintf("Failed to create task: %v", err)
-		return &ToolResult{Error: &errorMsg}, nil
+	var createdTask *providers.UniversalTask
+	if duplicateOf != "" {
+		createdTask, err = providers.CreateAsDuplicate(ctx, provider, task, duplicateOf)
+		if createdTask == nil {
+			errorMsg := fmt.Sprintf("Failed to create task: %v", err)
+			return &ToolResult{Error: &errorMsg, ErrorCode: errorCode(err)}, nil
+		}
+	} else {
+		createdTask, err = provider.CreateTask(ctx, task)
+		if err != nil {
+			errorMsg := fmt.Sprintf("Failed to create task: %v", err)
+			return &ToolResult{Error: &errorMsg, ErrorCode: errorCode(err)}, nil
+		}
 	}
 
 	result := fmt.Sprintf("✅ Task created successfully\n")
 	result += fmt.Sprintf("ID: %s\n", createdTask.GetDisplayID())
 	result += fmt.Sprintf("Title: %s\n", createdTask.Title)
 	result += fmt.Sprintf("Provider: %s\n", createdTask.ProviderName)
+	data := &TaskCreateData{Task: createdTask}
+	if duplicateOf != "" {
+		if err != nil {
+			result += fmt.Sprintf("⚠️ %v\n", err)
+			data.Warning = err.Error()
+		} else {
+			result += fmt.Sprintf("Duplicate of: %s\n", duplicateOf)
+			data.DuplicateOf = duplicateOf
+		}
+	}
 
 	return &ToolResult{
 		Content: []map[string]interface{}{
@@ -834,6 +1422,7 @@ func (m *MCPToolProvider) executeTaskCreateSmart(ctx context.Context, args map[s
 				"text": result,
 			},
 		},
+		Data: data,
 	}, nil
 }
 
@@ -844,7 +1433,10 @@ func (m *MCPToolProvider) executeTaskListUnified(ctx context.Context, args map[s
 	projectID, _ := args["project_id"].(string)
 	priority, _ := args["priority"].(string)
 	limit, _ := args["limit"].(float64)
+	offset, _ := args["offset"].(float64)
+	cursor, _ := args["cursor"].(string)
 	outputFormat, _ := args["output_format"].(string)
+	templateText, _ := args["template"].(string)
 
 	if outputFormat == "" {
 		outputFormat = "table"
@@ -853,6 +1445,15 @@ func (m *MCPToolProvider) executeTaskListUnified(ctx context.Context, args map[s
 		limit = 50
 	}
 
+	var formatter *providers.TaskFormatter
+	if outputFormat == "template" {
+		var err error
+		if formatter, err = providers.NewTaskFormatter(templateText); err != nil {
+			errorMsg := fmt.Sprintf("Invalid template: %v", err)
+			return &ToolResult{Error: &errorMsg, ErrorCode: errorCode(err)}, nil
+		}
+	}
+
 	// Convert providers
 	var providerNames []string
 	for _, provider := range providersInterface {
@@ -872,9 +1473,9 @@ func (m *MCPToolProvider) executeTaskListUnified(ctx context.Context, args map[s
 		targetProviders = providerNames
 	} else {
 		// Use default provider
-		if defaultProvider, err := m.registry.GetDefaultProvider(); err == nil {
-			info := defaultProvider.GetProviderInfo()
-			targetProviders = []string{info.Name}
+		// The registry name, the provider info only carries the display name
+		if name := m.registry.DefaultProviderName(); name != "" {
+			targetProviders = []string{name}
 		}
 	}
 
@@ -882,7 +1483,10 @@ func (m *MCPToolProvider) executeTaskListUnified(ctx context.Context, args map[s
 	filters := &providers.TaskFilters{
 		ProjectID:  projectID,
 		AssigneeID: assignee,
-		Limit:      int(limit),
+	}
+	// Without project_id every provider lists its default project, "" lists all projects
+	if _, given := args["project_id"]; !given {
+		filters.ProjectByProvider = m.registry.DefaultProjects()
 	}
 
 	if status != "" {
@@ -891,27 +1495,30 @@ func (m *MCPToolProvider) executeTaskListUnified(ctx context.Context, args map[s
 	if priority != "" {
 		filters.Priority = []string{priority}
 	}
+	filters.LabelsAll = stringSliceArg(args, "labels_all")
+	filters.LabelsAny = stringSliceArg(args, "labels_any")
+	filters.LabelsNone = stringSliceArg(args, "labels_none")
+	if err := filters.LabelMatch().Validate(); err != nil {
+		errorMsg := err.Error()
+		return &ToolResult{Error: &errorMsg, ErrorCode: errorCode(err)}, nil
+	}
+	var err error
+	if filters.DueDateAfter, err = m.dueDateArg(args, "due_after"); err != nil {
+		errorMsg := err.Error()
+		return &ToolResult{Error: &errorMsg, ErrorCode: errorCode(err)}, nil
+	}
+	if filters.DueDateBefore, err = m.dueDateArg(args, "due_before"); err != nil {
+		errorMsg := err.Error()
+		return &ToolResult{Error: &errorMsg, ErrorCode: errorCode(err)}, nil
+	}
 
-	// Collect tasks from all target providers
-	var allTasks []*providers.UniversalTask
-	for _, providerName := range targetProviders {
-		provider, err := m.registry.GetProvider(providerName)
-		if err != nil {
-			continue
-		}
-
-		tasks, err := provider.ListTasks(ctx, filters)
-		if err != nil {
-			continue
-		}
-
-		// Set provider name for display
-		for _, task := range tasks {
-			task.ProviderName = providerName
-		}
-
-		allTasks = append(allTasks, tasks...)
+	// Collect one page of tasks from the target providers
+	page, err := providers.FetchTaskPage(ctx, targetProviders, m.registry.GetProvider, filters, int(limit), int(offset), cursor)
+	if err != nil {
+		errorMsg := err.Error()
+		return &ToolResult{Error: &errorMsg, ErrorCode: errorCode(err)}, nil
 	}
+	allTasks := page.Tasks
 
 	// Format output
 	var content string
@@ -920,9 +1527,19 @@ func (m *MCPToolProvider) executeTaskListUnified(ctx context.Context, args map[s
 		content = m.formatTasksJSON(allTasks)
 	case "summary":
 		content = m.formatTasksSummary(allTasks)
+	case "template":
+		rendered, err := formatter.FormatAll(allTasks)
+		if err != nil {
+			errorMsg := err.Error()
+			return &ToolResult{Error: &errorMsg, ErrorCode: errorCode(err)}, nil
+		}
+		content = rendered
 	default: // table
 		content = m.formatTasksTable(allTasks)
 	}
+	if outputFormat == "table" || outputFormat == "summary" {
+		content += pageNote(page)
+	}
 
 	return &ToolResult{
 		Content: []map[string]interface{}{
@@ -931,6 +1548,7 @@ func (m *MCPToolProvider) executeTaskListUnified(ctx context.Context, args map[s
 				"text": content,
 			},
 		},
+		Data: newTaskListData(page),
 	}, nil
 }
 
@@ -942,10 +1560,11 @@ func (m *MCPToolProvider) executeTaskUpdateUniversal(ctx context.Context, args m
 	status, _ := args["status"].(string)
 	priorityStr, _ := args["priority"].(string)
 	assignee, _ := args["assignee"].(string)
+	preview, _ := args["preview"].(bool)
 
 	if taskID == "" {
 		errorMsg := "Task ID is required"
-		return &ToolResult{Error: &errorMsg}, nil
+		return &ToolResult{Error: &errorMsg, ErrorCode: ErrorCodeInvalidArgument}, nil
 	}
 
 	// Get provider
@@ -960,7 +1579,7 @@ func (m *MCPToolProvider) executeTaskUpdateUniversal(ctx context.Context, args m
 
 	if err != nil {
 		errorMsg := fmt.Sprintf("Failed to get provider: %v", err)
-		return &ToolResult{Error: &errorMsg}, nil
+		return &ToolResult{Error: &errorMsg, ErrorCode: errorCode(err)}, nil
 	}
 
 	// Build updates
@@ -984,13 +1603,62 @@ func (m *MCPToolProvider) executeTaskUpdateUniversal(ctx context.Context, args m
 		updates.Priority = &taskPriority
 	}
 	if assignee != "" {
-		updates.AssigneeID = &assignee
+		resolved, err := providers.ResolveAssignee(ctx, provider, assignee)
+		if err != nil {
+			errorMsg := fmt.Sprintf("Failed to resolve assignee: %v", err)
+			return &ToolResult{Error: &errorMsg, ErrorCode: errorCode(err)}, nil
+		}
+		updates.AssigneeID = &resolved
+	}
+
+	if updates.DueDate, err = m.dueDateArg(args, "due_date"); err != nil {
+		errorMsg := err.Error()
+		return &ToolResult{Error: &errorMsg, ErrorCode: errorCode(err)}, nil
+	}
+
+	// Added and removed labels are applied to the current labels of the task
+	addLabels := stringSliceArg(args, "add_labels")
+	removeLabels := stringSliceArg(args, "remove_labels")
+	if len(addLabels) > 0 || len(removeLabels) > 0 {
+		createLabels, _ := args["create_labels"].(bool)
+		added, err := providers.ValidateLabels(ctx, provider, "", addLabels, createLabels)
+		if err != nil {
+			errorMsg := fmt.Sprintf("Invalid labels: %v", err)
+			return &ToolResult{Error: &errorMsg, ErrorCode: errorCode(err)}, nil
+		}
+		current, err := provider.GetTask(ctx, taskID)
+		if err != nil {
+			errorMsg := fmt.Sprintf("Failed to get task: %v", err)
+			return &ToolResult{Error: &errorMsg, ErrorCode: errorCode(err)}, nil
+		}
+		updates.Labels = providers.MergeLabels(current.Labels, added, removeLabels)
+	}
+
+	// Preview returns the diff without touching the task
+	if preview {
+		current, err := provider.GetTask(ctx, taskID)
+		if err != nil {
+			errorMsg := fmt.Sprintf("Failed to get task: %v", err)
+			return &ToolResult{Error: &errorMsg, ErrorCode: errorCode(err)}, nil
+		}
+
+		changes := providers.DiffTaskUpdate(current, updates)
+		result := fmt.Sprintf("🔍 Preview of changes to %s (not applied):\n\n%s", current.GetDisplayID(), providers.FormatTaskDiff(changes))
+
+		return &ToolResult{
+			Content: []map[string]interface{}{
+				{
+					"type": "text",
+					"text": result,
+				},
+			},
+		}, nil
 	}
 
 	// Update task
 	if err := provider.UpdateTask(ctx, taskID, updates); err != nil {
 		errorMsg := fmt.Sprintf("Failed to update task: %v", err)
-		return &ToolResult{Error: &errorMsg}, nil
+		return &ToolResult{Error: &errorMsg, ErrorCode: errorCode(err)}, nil
 	}
 
 	result := fmt.Sprintf("✅ Task %s updated successfully", taskID)
@@ -1009,11 +1677,13 @@ func (m *MCPToolProvider) executeCrossProviderSearch(ctx context.Context, args m
 	query, _ := args["query"].(string)
 	providersInterface, _ := args["providers"].([]interface{})
 	limit, _ := args["limit"].(float64)
+	offset, _ := args["offset"].(float64)
+	cursor, _ := args["cursor"].(string)
 	includeContent, _ := args["include_content"].(bool)
 
 	if query == "" {
 		errorMsg := "Search query is required"
-		return &ToolResult{Error: &errorMsg}, nil
+		return &ToolResult{Error: &errorMsg, ErrorCode: ErrorCodeInvalidArgument}, nil
 	}
 
 	if limit == 0 {
@@ -1028,47 +1698,33 @@ func (m *MCPToolProvider) executeCrossProviderSearch(ctx context.Context, args m
 		}
 	}
 
-	// Determine target providers
+	// Determine target providers, all enabled ones by default
 	var targetProviders []string
-	if len(providerNames) > 0 && providerNames[0] == "all" {
+	if len(providerNames) == 0 || providerNames[0] == "all" {
 		enabledProviders := m.registry.ListEnabledProviders()
 		for name := range enabledProviders {
 			targetProviders = append(targetProviders, name)
 		}
-	} else if len(providerNames) > 0 {
-		targetProviders = providerNames
 	} else {
-		targetProviders = []string{"all"}
+		targetProviders = providerNames
 	}
 
 	// Build search filters
 	filters := &providers.TaskFilters{
 		Query: query,
-		Limit: int(limit),
 	}
 
-	// Search across providers
-	var allTasks []*providers.UniversalTask
-	for _, providerName := range targetProviders {
-		provider, err := m.registry.GetProvider(providerName)
-		if err != nil {
-			continue
-		}
-
-		tasks, err := provider.ListTasks(ctx, filters)
-		if err != nil {
-			continue
-		}
-
-		for _, task := range tasks {
-			task.ProviderName = providerName
-		}
-
-		allTasks = append(allTasks, tasks...)
+	// Search one page across providers
+	page, err := providers.FetchTaskPage(ctx, targetProviders, m.registry.GetProvider, filters, int(limit), int(offset), cursor)
+	if err != nil {
+		errorMsg := err.Error()
+		return &ToolResult{Error: &errorMsg, ErrorCode: errorCode(err)}, nil
 	}
+	allTasks := page.Tasks
 
 	result := fmt.Sprintf("Found %d tasks matching '%s'\n\n", len(allTasks), query)
 	result += m.formatTasksSearchResults(allTasks, includeContent)
+	result += pageNote(page)
 
 	return &ToolResult{
 		Content: []map[string]interface{}{
@@ -1077,6 +1733,7 @@ func (m *MCPToolProvider) executeCrossProviderSearch(ctx context.Context, args m
 				"text": result,
 			},
 		},
+		Data: newTaskListData(page),
 	}, nil
 }
 
@@ -1088,7 +1745,7 @@ func (m *MCPToolProvider) executeAIAnalyzeProject(ctx context.Context, args map[
 
 	if projectDescription == "" {
 		errorMsg := "Project description is required"
-		return &ToolResult{Error: &errorMsg}, nil
+		return &ToolResult{Error: &errorMsg, ErrorCode: ErrorCodeInvalidArgument}, nil
 	}
 
 	if projectType == "" {
@@ -1110,15 +1767,15 @@ func (m *MCPToolProvider) executeAIAnalyzeProject(ctx context.Context, args map[
 				codeFileStrings[i] = fileStr
 			}
 		}
-		analysis, err = m.aiChains.AnalyzeCodebase(codeFileStrings, projectDescription)
+		analysis, err = m.aiChains.WithContext(ctx).AnalyzeCodebase(codeFileStrings, projectDescription)
 	} else {
 		// Analyze project description only
-		analysis, err = m.aiChains.AnalyzeProject(projectDescription, projectType)
+		analysis, err = m.aiChains.WithContext(ctx).AnalyzeProject(projectDescription, projectType)
 	}
 
 	if err != nil {
 		errorMsg := fmt.Sprintf("AI analysis failed: %v", err)
-		return &ToolResult{Error: &errorMsg}, nil
+		return &ToolResult{Error: &errorMsg, ErrorCode: errorCode(err)}, nil
 	}
 
 	// Format results
@@ -1154,57 +1811,105 @@ func (m *MCPToolProvider) executeAIAnalyzeProject(ctx context.Context, args map[
 				"text": result,
 			},
 		},
+		Data: newProjectAnalysisData(analysis),
 	}, nil
 }
 
 func (m *MCPToolProvider) executeAIExecuteTask(ctx context.Context, args map[string]interface{}) (*ToolResult, error) {
-	taskTitle, _ := args["task_title"].(string)
-	taskDescription, _ := args["task_description"].(string)
-	taskType, _ := args["task_type"].(string)
+	providerName, _ := args["provider"].(string)
+	taskID, _ := args["task_id"].(string)
 	executionMode, _ := args["execution_mode"].(string)
-	autoUpdateStatus, _ := args["auto_update_status"].(bool)
-
-	if taskTitle == "" {
-		errorMsg := "Task title is required"
-		return &ToolResult{Error: &errorMsg}, nil
-	}
-
-	if taskDescription == "" {
-		errorMsg := "Task description is required"
-		return &ToolResult{Error: &errorMsg}, nil
+	createSubtasks, _ := args["create_subtasks"].(bool)
+	autoUpdateStatus := true
+	if value, ok := args["auto_update_status"].(bool); ok {
+		autoUpdateStatus = value
 	}
 
-	if taskType == "" {
-		taskType = "development"
+	if taskID == "" {
+		errorMsg := "task_id is required"
+		return &ToolResult{Error: &errorMsg, ErrorCode: ErrorCodeInvalidArgument}, nil
 	}
 	if executionMode == "" {
 		executionMode = "plan"
 	}
+	guidance, ok := executionModes[executionMode]
+	if !ok {
+		errorMsg := fmt.Sprintf("Invalid execution_mode %q, use plan, implement, test, review or full", executionMode)
+		return &ToolResult{Error: &errorMsg, ErrorCode: ErrorCodeInvalidArgument}, nil
+	}
+
+	var provider providers.TaskProvider
+	var err error
+	if providerName != "" {
+		provider, err = m.registry.GetProvider(providerName)
+	} else {
+		provider, err = m.registry.GetDefaultProvider()
+	}
+	if err != nil {
+		errorMsg := fmt.Sprintf("Failed to get provider: %v", err)
+		return &ToolResult{Error: &errorMsg, ErrorCode: errorCode(err)}, nil
+	}
+
+	task, err := provider.GetTask(ctx, taskID)
+	if err != nil {
+		errorMsg := fmt.Sprintf("Failed to get task: %v", err)
+		return &ToolResult{Error: &errorMsg, ErrorCode: errorCode(err)}, nil
+	}
 
-	// Use AI chains for real task execution
-	executionResult, err := m.aiChains.ExecuteTask(taskTitle, taskDescription, taskType)
+	plan, err := m.aiChains.WithContext(ctx).ExecuteTask(task.Title, task.Description, guidance)
 	if err != nil {
 		errorMsg := fmt.Sprintf("AI task execution failed: %v", err)
-		return &ToolResult{Error: &errorMsg}, nil
+		return &ToolResult{Error: &errorMsg, ErrorCode: errorCode(err)}, nil
+	}
+
+	data := &TaskExecutionData{TaskID: task.ID, Mode: executionMode, Plan: plan, Steps: executionSteps(plan)}
+
+	result := "🤖 AI Task Execution\n"
+	result += "===================\n\n"
+	result += fmt.Sprintf("📋 Task: %s: %s\n", task.GetDisplayID(), task.Title)
+	result += fmt.Sprintf("⚙️ Mode: %s\n\n", executionMode)
+	result += "📝 AI Execution Plan:\n"
+	result += "====================\n"
+	result += plan + "\n"
+
+	if createSubtasks {
+		data.Subtasks = m.createExecutionSubtasks(ctx, provider, task, data.Steps)
+		created := 0
+		for _, subtask := range data.Subtasks {
+			if subtask.Error == "" {
+				created++
+			}
+		}
+		result += fmt.Sprintf("\n🧩 Subtasks created: %d of %d\n", created, len(data.Subtasks))
+		for _, subtask := range data.Subtasks {
+			if subtask.Error != "" {
+				result += fmt.Sprintf("• ❌ %s: %s\n", subtask.Step, subtask.Error)
+			} else {
+				result += fmt.Sprintf("• %s: %s\n", subtask.TaskID, subtask.Step)
+			}
+		}
+		if len(data.Steps) == 0 {
+			data.Warnings = append(data.Warnings, "the plan has no numbered steps to create subtasks for")
+		}
 	}
 
-	result := fmt.Sprintf("🤖 AI Task Execution\n")
-	result += fmt.Sprintf("===================\n\n")
-	result += fmt.Sprintf("📋 Task: %s\n", taskTitle)
-	result += fmt.Sprintf("🔧 Type: %s\n", taskType)
-	result += fmt.Sprintf("⚙️ Mode: %s\n", executionMode)
-	result += fmt.Sprintf("🔄 Auto-update: %t\n\n", autoUpdateStatus)
-	
-	result += fmt.Sprintf("📝 AI Execution Plan:\n")
-	result += fmt.Sprintf("====================\n")
-	result += executionResult
-	
-	// If auto-update is enabled, simulate status update
 	if autoUpdateStatus {
-		result += fmt.Sprintf("\n\n✅ Task status updated automatically\n")
-		result += fmt.Sprintf("• Status changed to: In Progress\n")
-		result += fmt.Sprintf("• AI comment added with execution plan\n")
-		result += fmt.Sprintf("• Next review scheduled\n")
+		status, warning, err := startTask(ctx, provider, task)
+		if err != nil {
+			errorMsg := fmt.Sprintf("Failed to update task status: %v", err)
+			return &ToolResult{Error: &errorMsg, ErrorCode: errorCode(err)}, nil
+		}
+		if status != "" {
+			data.Status = status
+			result += fmt.Sprintf("\n✅ Task status changed to: %s\n", status)
+		}
+		if warning != "" {
+			data.Warnings = append(data.Warnings, warning)
+		}
+	}
+
+	for _, warning := range data.Warnings {
+		result += fmt.Sprintf("\n⚠️ %s\n", warning)
 	}
 
 	return &ToolResult{
@@ -1214,11 +1919,101 @@ func (m *MCPToolProvider) executeAIExecuteTask(ctx context.Context, args map[str
 				"text": result,
 			},
 		},
+		Data: data,
 	}, nil
 }
 
+// createExecutionSubtasks creates a subtask of the task for each step and
+// links it to the task when the provider supports links. A step that fails
+// is reported and the rest are still created.
+func (m *MCPToolProvider) createExecutionSubtasks(ctx context.Context, provider providers.TaskProvider, task *providers.UniversalTask, steps []string) []ExecutionStepData {
+	linker, canLink := providers.ProviderAs[providers.LinkProvider](provider)
+
+	subtasks := make([]ExecutionStepData, 0, len(steps))
+	for i, step := range steps {
+		subtask := ExecutionStepData{Step: step}
+		created, err := provider.CreateTask(ctx, &providers.UniversalTask{
+			Title:       step,
+			Description: fmt.Sprintf("Step %d of the execution plan of %s: %s", i+1, task.GetDisplayID(), task.Title),
+			Type:        providers.TaskTypeSubtask,
+			Priority:    task.Priority,
+			ProjectID:   task.ProjectID,
+			ParentID:    task.ID,
+		})
+		if err != nil {
+			subtask.Error = err.Error()
+			subtasks = append(subtasks, subtask)
+			continue
+		}
+		subtask.TaskID = created.GetDisplayID()
+		if canLink {
+			if err := linker.LinkTasks(ctx, created.ID, task.ID, providers.TaskLinkSubtaskOf); err != nil {
+				subtask.Error = fmt.Sprintf("created, but failed to link to %s: %v", task.GetDisplayID(), err)
+			}
+		}
+		subtasks = append(subtasks, subtask)
+	}
+	return subtasks
+}
+
+// startTask moves a task to the in-progress status of its workflow and
+// returns that status. Tasks that are already in progress or closed are left
+// alone with a warning explaining why.
+func startTask(ctx context.Context, provider providers.TaskProvider, task *providers.UniversalTask) (string, string, error) {
+	if task.IsCompleted() {
+		return "", fmt.Sprintf("status not changed, the task is already %s", task.Status.Name), nil
+	}
+
+	statuses, err := provider.GetAvailableStatuses(ctx, task.ProjectID)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to get statuses: %w", err)
+	}
+	status, ok := providers.InProgressStatus(statuses)
+	if !ok {
+		return "", fmt.Sprintf("status not changed, project %s has no in-progress status", task.ProjectID), nil
+	}
+	if providers.SameStatus(task.Status, status) {
+		return "", fmt.Sprintf("status not changed, the task is already %s", task.Status.Name), nil
+	}
+
+	if err := provider.UpdateTask(ctx, task.ID, &providers.TaskUpdate{Status: &status}); err != nil {
+		return "", "", err
+	}
+	return status.Name, "", nil
+}
+
 // Helper methods for formatting and mapping
 
+// dueDateArg parses a due date argument in the configured time zone; a
+// missing argument gives nil
+func (m *MCPToolProvider) dueDateArg(args map[string]interface{}, name string) (*time.Time, error) {
+	value, _ := args[name].(string)
+	if value == "" {
+		return nil, nil
+	}
+	loc, err := m.registry.Location()
+	if err != nil {
+		return nil, err
+	}
+	due, err := providers.ParseDueDate(value, time.Now().In(loc))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return &due, nil
+}
+
+// stringSliceArg returns the strings of an array argument
+func stringSliceArg(args map[string]interface{}, name string) []string {
+	items, _ := args[name].([]interface{})
+	var values []string
+	for _, item := range items {
+		if value, ok := item.(string); ok {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
 func (m *MCPToolProvider) mapPriority(priority string) providers.TaskPriority {
 	switch priority {
 	case "lowest":
@@ -1265,8 +2060,8 @@ func (m *MCPToolProvider) formatProvidersSummary(providerList map[string]*provid
 }
 
 func (m *MCPToolProvider) formatProvidersTable(providers map[string]*providers.ProviderInfo) string {
-	result := fmt.Sprintf("%-20s %-12s %-10s %-15s\n", "NAME", "TYPE", "STATUS", "HEALTH")
-	result += fmt.Sprintf("%-20s %-12s %-10s %-15s\n", "----", "----", "------", "------")
+	result := fmt.Sprintf("%-20s %-12s %-10s %-15s %s\n", "NAME", "TYPE", "STATUS", "HEALTH", "CAPABILITIES")
+	result += fmt.Sprintf("%-20s %-12s %-10s %-15s %s\n", "----", "----", "------", "------", "------------")
 
 	for name, info := range providers {
 		status := "disabled"
@@ -1274,11 +2069,17 @@ func (m *MCPToolProvider) formatProvidersTable(providers map[string]*providers.P
 			status = "enabled"
 		}
 
-		result += fmt.Sprintf("%-20s %-12s %-10s %-15s\n",
+		capabilities := make([]string, len(info.Capabilities))
+		for i, capability := range info.Capabilities {
+			capabilities[i] = string(capability)
+		}
+
+		result += fmt.Sprintf("%-20s %-12s %-10s %-15s %s\n",
 			name,
 			string(info.Type),
 			status,
 			string(info.HealthStatus),
+			strings.Join(capabilities, ", "),
 		)
 	}
 
@@ -1291,34 +2092,7 @@ func (m *MCPToolProvider) formatTasksJSON(tasks []*providers.UniversalTask) stri
 }
 
 func (m *MCPToolProvider) formatTasksSummary(tasks []*providers.UniversalTask) string {
-	result := fmt.Sprintf("📋 Task Summary (%d total)\n\n", len(tasks))
-
-	statusCount := make(map[string]int)
-	priorityCount := make(map[providers.TaskPriority]int)
-	providerCount := make(map[string]int)
-
-	for _, task := range tasks {
-		statusCount[task.Status.Name]++
-		priorityCount[task.Priority]++
-		providerCount[task.ProviderName]++
-	}
-
-	result += "By Status:\n"
-	for status, count := range statusCount {
-		result += fmt.Sprintf("  %s: %d\n", status, count)
-	}
-
-	result += "\nBy Priority:\n"
-	for priority, count := range priorityCount {
-		result += fmt.Sprintf("  %s: %d\n", string(priority), count)
-	}
-
-	result += "\nBy Provider:\n"
-	for provider, count := range providerCount {
-		result += fmt.Sprintf("  %s: %d\n", provider, count)
-	}
-
-	return result
+	return providers.FormatTaskSummary(tasks)
 }
 
 func (m *MCPToolProvider) formatTasksTable(tasks []*providers.UniversalTask) string {
@@ -1375,7 +2149,7 @@ func (m *MCPToolProvider) executeContextSetBoard(ctx context.Context, args map[s
 
 	if boardID == "" || projectID == "" {
 		errorMsg := "board_id and project_id are required"
-		return &ToolResult{Error: &errorMsg}, nil
+		return &ToolResult{Error: &errorMsg, ErrorCode: ErrorCodeInvalidArgument}, nil
 	}
 
 	// Convert labels
@@ -1393,8 +2167,11 @@ func (m *MCPToolProvider) executeContextSetBoard(ctx context.Context, args map[s
 			providerName = info.Name
 		} else {
 			errorMsg := "Failed to get default provider"
-			return &ToolResult{Error: &errorMsg}, nil
+			return &ToolResult{Error: &errorMsg, ErrorCode: errorCode(err)}, nil
 		}
+	} else if err := m.registry.RequireCapability(providerName, providers.CapabilityBoards); providers.IsUnsupportedError(err) {
+		errorMsg := fmt.Sprintf("Cannot set board context: %v", err)
+		return &ToolResult{Error: &errorMsg, ErrorCode: errorCode(err)}, nil
 	}
 
 	result := fmt.Sprintf("✅ Board context set successfully\n")
@@ -1477,6 +2254,11 @@ func (m *MCPToolProvider) executeContextListBoards(ctx context.Context, args map
 
 	// Filter by provider if specified
 	if providerFilter != "" {
+		if err := m.registry.RequireCapability(providerFilter, providers.CapabilityBoards); providers.IsUnsupportedError(err) {
+			errorMsg := fmt.Sprintf("Boards are not available: %v", err)
+			return &ToolResult{Error: &errorMsg, ErrorCode: errorCode(err)}, nil
+		}
+
 		filteredBoards := []map[string]interface{}{}
 		for _, board := range boards {
 			if board["provider"] == providerFilter {
@@ -1486,6 +2268,9 @@ func (m *MCPToolProvider) executeContextListBoards(ctx context.Context, args map
 		boards = filteredBoards
 	}
 
+	// Skip boards of providers that don't support them
+	boards = m.boardsWithCapability(boards)
+
 	var result string
 	switch outputFormat {
 	case "json":
@@ -1515,6 +2300,34 @@ func (m *MCPToolProvider) executeContextListBoards(ctx context.Context, args map
 	}, nil
 }
 
+// boardsWithCapability drops boards whose provider doesn't advertise boards support
+func (m *MCPToolProvider) boardsWithCapability(boards []map[string]interface{}) []map[string]interface{} {
+	var names []string
+	for _, board := range boards {
+		if name, ok := board["provider"].(string); ok {
+			names = append(names, name)
+		}
+	}
+
+	_, unsupported := m.registry.SplitByCapability(names, providers.CapabilityBoards)
+	if len(unsupported) == 0 {
+		return boards
+	}
+
+	skip := make(map[string]bool, len(unsupported))
+	for _, name := range unsupported {
+		skip[name] = true
+	}
+
+	supported := []map[string]interface{}{}
+	for _, board := range boards {
+		if name, _ := board["provider"].(string); !skip[name] {
+			supported = append(supported, board)
+		}
+	}
+	return supported
+}
+
 func (m *MCPToolProvider) executeAICreateProjectPlan(ctx context.Context, args map[string]interface{}) (*ToolResult, error) {
 	description, _ := args["description"].(string)
 	projectType, _ := args["project_type"].(string)
@@ -1525,7 +2338,7 @@ func (m *MCPToolProvider) executeAICreateProjectPlan(ctx context.Context, args m
 
 	if description == "" {
 		errorMsg := "Project description is required"
-		return &ToolResult{Error: &errorMsg}, nil
+		return &ToolResult{Error: &errorMsg, ErrorCode: ErrorCodeInvalidArgument}, nil
 	}
 
 	// Set defaults
@@ -1543,10 +2356,10 @@ func (m *MCPToolProvider) executeAICreateProjectPlan(ctx context.Context, args m
 	}
 
 	// Use AI chains to create real project plan
-	plan, err := m.aiChains.CreateProjectPlan(description, projectType, complexity, int(timelineDays), priority)
+	plan, err := m.aiChains.WithContext(ctx).CreateProjectPlan(description, projectType, complexity, int(timelineDays), priority)
 	if err != nil {
 		errorMsg := fmt.Sprintf("AI project planning failed: %v", err)
-		return &ToolResult{Error: &errorMsg}, nil
+		return &ToolResult{Error: &errorMsg, ErrorCode: errorCode(err)}, nil
 	}
 
 	result := fmt.Sprintf("🤖 AI Project Plan Generated\n")
@@ -1595,7 +2408,7 @@ func (m *MCPToolProvider) executeAIExecutePlan(ctx context.Context, args map[str
 
 	if planID == "" {
 		errorMsg := "Plan ID is required"
-		return &ToolResult{Error: &errorMsg}, nil
+		return &ToolResult{Error: &errorMsg, ErrorCode: ErrorCodeInvalidArgument}, nil
 	}
 
 	result := fmt.Sprintf("🚀 Executing Plan: %s\n", planID)
@@ -1643,88 +2456,461 @@ func (m *MCPToolProvider) executeAIExecutePlan(ctx context.Context, args map[str
 }
 
 func (m *MCPToolProvider) executeAITrackProgress(ctx context.Context, args map[string]interface{}) (*ToolResult, error) {
-	updateStatuses, _ := args["update_statuses"].(bool)
-	addProgressComments, _ := args["add_progress_comments"].(bool)
+	providerName, _ := args["provider"].(string)
+	projectID, _ := args["project"].(string)
+	taskIDs := stringSliceArg(args, "task_ids")
 	generateReport, _ := args["generate_report"].(bool)
-	_, _ = args["task_ids"].([]interface{})
+	dryRun, _ := args["dry_run"].(bool)
+	updateStatuses := true
+	if value, ok := args["update_statuses"].(bool); ok {
+		updateStatuses = value
+	}
+	addProgressComments := true
+	if value, ok := args["add_progress_comments"].(bool); ok {
+		addProgressComments = value
+	}
+
+	var provider providers.TaskProvider
+	var err error
+	if providerName != "" {
+		provider, err = m.registry.GetProvider(providerName)
+	} else {
+		provider, err = m.registry.GetDefaultProvider()
+	}
+	if err != nil {
+		errorMsg := fmt.Sprintf("Failed to get provider: %v", err)
+		return &ToolResult{Error: &errorMsg, ErrorCode: errorCode(err)}, nil
+	}
+
+	tracker := &progressTracker{
+		provider:    provider,
+		projects:    make(map[string][]*providers.UniversalTask),
+		statuses:    make(map[string][]providers.TaskStatus),
+		descendants: make(map[string][]*providers.UniversalTask),
+	}
+
+	var tasks []*providers.UniversalTask
+	if len(taskIDs) > 0 {
+		for _, taskID := range taskIDs {
+			task, err := provider.GetTask(ctx, taskID)
+			if err != nil {
+				errorMsg := fmt.Sprintf("Failed to get task %s: %v", taskID, err)
+				return &ToolResult{Error: &errorMsg, ErrorCode: errorCode(err)}, nil
+			}
+			tasks = append(tasks, task)
+		}
+	} else {
+		listed, err := tracker.projectTasks(ctx, projectID)
+		if err != nil {
+			errorMsg := fmt.Sprintf("Failed to list tasks: %v", err)
+			return &ToolResult{Error: &errorMsg, ErrorCode: errorCode(err)}, nil
+		}
+		for _, task := range listed {
+			if task.IsAIManaged() {
+				tasks = append(tasks, task)
+			}
+		}
+	}
+
+	data := &ProgressTrackingData{DryRun: dryRun, Tasks: make([]TaskProgressData, 0, len(tasks))}
 
 	result := "🔍 AI Progress Tracking\n"
 	result += "=======================\n"
+	if dryRun {
+		result += "(dry run, nothing is changed)\n"
+	}
+	result += "\n"
+
+	if len(tasks) == 0 {
+		result += "No AI-managed tasks found. Pass task_ids to track specific tasks.\n"
+		return &ToolResult{
+			Content: []map[string]interface{}{{"type": "text", "text": result}},
+			Data:    data,
+		}, nil
+	}
 
-	// For demonstration, we'll track some example tasks and generate AI comments
-	exampleTasks := []struct {
-		ID       string
-		Title    string
-		Status   string
-		Progress int
-	}{
-		{"GD-11", "Setup authentication system", "Done", 100},
-		{"MG-11", "Implement user dashboard", "In Progress", 75},
-		{"MG-10", "Add notification system", "In Progress", 50},
-		{"MG-9", "Create admin panel", "Open", 25},
+	commenter, canComment := providers.ProviderAs[providers.CommentProvider](provider)
+	if addProgressComments && !canComment {
+		data.Warnings = append(data.Warnings, "the provider doesn't support comments, no progress comments were added")
+		addProgressComments = false
 	}
 
 	result += "📊 Progress Analysis:\n"
 	result += "--------------------\n"
 
 	totalProgress := 0
-	tasksCompleted := 0
-	tasksInProgress := 0
-	tasksPending := 0
+	tasksCompleted, tasksInProgress, tasksPending := 0, 0, 0
+	for _, task := range tasks {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		progress := tracker.track(ctx, task)
+		totalProgress += progress.Progress
 
-	for _, task := range exampleTasks {
 		statusIcon := "🔴"
-		if task.Progress == 100 {
+		switch {
+		case progress.Progress == 100:
 			statusIcon = "🟢"
 			tasksCompleted++
-		} else if task.Progress > 0 {
+		case progress.Progress > 0:
 			statusIcon = "🟡"
 			tasksInProgress++
-		} else {
+		default:
 			tasksPending++
 		}
 
-		result += fmt.Sprintf("%s %s: %d%% complete (%s)\n", statusIcon, task.ID, task.Progress, task.Status)
-		
-		if addProgressComments && task.Progress > 0 {
-			// Generate AI progress comment
-			comment, err := m.aiChains.GenerateProgressComment(task.Title, task.Status, fmt.Sprintf("%d", task.Progress), []string{"Implementation started", "Basic structure created"})
-			if err == nil {
-				result += fmt.Sprintf("   💬 AI Comment: %s\n", comment)
-			} else {
-				result += "   💬 Added AI progress comment\n"
+		result += fmt.Sprintf("%s %s: %d%% complete (%s)", statusIcon, progress.TaskID, progress.Progress, progress.Status)
+		if progress.Source == providers.ProgressFromSubtasks {
+			result += fmt.Sprintf(", %d of %d subtasks done", progress.CompletedSubtasks, progress.Subtasks)
+		}
+		result += "\n"
+
+		if updateStatuses && progress.Error == "" {
+			if status, ok := tracker.nextStatus(ctx, task, progress); ok {
+				progress.NewStatus = status.Name
+				if !dryRun {
+					if err := provider.UpdateTask(ctx, task.ID, &providers.TaskUpdate{Status: &status}); err != nil {
+						progress.Error = fmt.Sprintf("failed to update status: %v", err)
+						progress.NewStatus = ""
+					}
+				}
+				if progress.NewStatus != "" {
+					verb := "changed"
+					if dryRun {
+						verb = "would change"
+					}
+					result += fmt.Sprintf("   🔄 Status %s to %s\n", verb, progress.NewStatus)
+				}
 			}
 		}
 
-		if updateStatuses && task.Progress > 0 && task.Status != "Done" {
-			result += "   🔄 Status updated based on progress\n"
+		if addProgressComments && progress.Progress > 0 && progress.Error == "" {
+			comment, err := m.aiChains.WithContext(ctx).GenerateProgressComment(task.Title, task.Status.Name, fmt.Sprintf("%d", progress.Progress), tracker.completedWork(task))
+			if err != nil {
+				progress.Error = fmt.Sprintf("failed to generate progress comment: %v", err)
+			} else {
+				progress.Comment = comment
+				if !dryRun {
+					if err := commenter.AddComment(ctx, task.ID, comment); err != nil {
+						progress.Error = fmt.Sprintf("failed to add progress comment: %v", err)
+					} else {
+						progress.CommentAdded = true
+					}
+				}
+				result += fmt.Sprintf("   💬 AI Comment: %s\n", comment)
+			}
 		}
 
-		totalProgress += task.Progress
+		if progress.Error != "" {
+			result += fmt.Sprintf("   ❌ %s\n", progress.Error)
+		}
+		data.Tasks = append(data.Tasks, *progress)
 	}
 
-	averageProgress := totalProgress / len(exampleTasks)
-	result += fmt.Sprintf("\n📈 Overall Progress: %d%%\n", averageProgress)
+	data.OverallProgress = totalProgress / len(tasks)
+	result += fmt.Sprintf("\n📈 Overall Progress: %d%%\n", data.OverallProgress)
 
 	if generateReport {
-		result += "\n📄 Progress Report Generated:\n"
-		result += "----------------------------\n"
+		result += "\n📄 Progress Report:\n"
+		result += "------------------\n"
 		result += fmt.Sprintf("• %d task(s) completed\n", tasksCompleted)
 		result += fmt.Sprintf("• %d task(s) in progress\n", tasksInProgress)
 		result += fmt.Sprintf("• %d task(s) pending\n", tasksPending)
-		result += fmt.Sprintf("• Average completion: %d%%\n", averageProgress)
-		
-		if addProgressComments {
-			result += "• AI progress comments generated\n"
+		result += fmt.Sprintf("• Average completion: %d%%\n", data.OverallProgress)
+	}
+
+	for _, warning := range data.Warnings {
+		result += fmt.Sprintf("\n⚠️ %s\n", warning)
+	}
+
+	return &ToolResult{
+		Content: []map[string]interface{}{
+			{
+				"type": "text",
+				"text": result,
+			},
+		},
+		Data: data,
+	}, nil
+}
+
+// progressTracker computes task progress for ai_track_progress, listing each
+// project and loading its statuses once for all the tasks in it
+type progressTracker struct {
+	provider    providers.TaskProvider
+	projects    map[string][]*providers.UniversalTask
+	statuses    map[string][]providers.TaskStatus
+	descendants map[string][]*providers.UniversalTask // task ID -> descendants
+}
+
+// projectTasksLimit caps the tasks listed per project to find subtasks in
+const projectTasksLimit = 1000
+
+func (t *progressTracker) projectTasks(ctx context.Context, projectID string) ([]*providers.UniversalTask, error) {
+	if tasks, ok := t.projects[projectID]; ok {
+		return tasks, nil
+	}
+	tasks, err := t.provider.ListTasks(ctx, &providers.TaskFilters{ProjectID: projectID, Limit: projectTasksLimit})
+	if err != nil {
+		return nil, err
+	}
+	t.projects[projectID] = tasks
+	return tasks, nil
+}
+
+// track computes the progress of a task from its subtasks, or from its
+// status when it has none
+func (t *progressTracker) track(ctx context.Context, task *providers.UniversalTask) *TaskProgressData {
+	progress := &TaskProgressData{TaskID: task.GetDisplayID(), Title: task.Title, Status: task.Status.Name}
+
+	// Without the project listing, subtasks are still found through SubtaskIDs
+	candidates, err := t.projectTasks(ctx, task.ProjectID)
+	if err != nil {
+		candidates = nil
+	}
+	descendants, err := providers.FindDescendants(ctx, t.provider, task, candidates)
+	if err != nil {
+		progress.Error = fmt.Sprintf("failed to get subtasks: %v", err)
+	}
+	t.descendants[task.ID] = descendants
+
+	progress.Progress, progress.Source = providers.TaskProgress(task, descendants)
+	if progress.Source == providers.ProgressFromSubtasks {
+		rollup := providers.ComputeRollup(task, descendants)
+		progress.Subtasks, progress.CompletedSubtasks = rollup.Total, rollup.Completed
+	}
+	return progress
+}
+
+// nextStatus returns the status the subtasks of a task call for. Progress
+// estimated from the status itself never changes it.
+func (t *progressTracker) nextStatus(ctx context.Context, task *providers.UniversalTask, progress *TaskProgressData) (providers.TaskStatus, bool) {
+	if progress.Source != providers.ProgressFromSubtasks {
+		return providers.TaskStatus{}, false
+	}
+	statuses, ok := t.statuses[task.ProjectID]
+	if !ok {
+		var err error
+		if statuses, err = t.provider.GetAvailableStatuses(ctx, task.ProjectID); err != nil {
+			progress.Error = fmt.Sprintf("failed to get statuses: %v", err)
+			return providers.TaskStatus{}, false
 		}
-		
-		// Estimate completion time based on progress
-		remainingWork := 100 - averageProgress
-		estimatedDays := (remainingWork / 25) // Rough estimate: 25% per day
-		if estimatedDays < 1 {
-			estimatedDays = 1
+		t.statuses[task.ProjectID] = statuses
+	}
+	return providers.ProgressStatus(task, statuses, progress.Progress)
+}
+
+// completedWork lists the completed subtasks of a task for its progress comment
+func (t *progressTracker) completedWork(task *providers.UniversalTask) []string {
+	var work []string
+	for _, descendant := range t.descendants[task.ID] {
+		if descendant.IsCompleted() {
+			work = append(work, descendant.Title)
+		}
+	}
+	return work
+}
+
+func (m *MCPToolProvider) executeTaskWatch(ctx context.Context, args map[string]interface{}) (*ToolResult, error) {
+	action, _ := args["action"].(string)
+	taskID, _ := args["task_id"].(string)
+	userID, _ := args["user_id"].(string)
+	providerName, _ := args["provider"].(string)
+
+	if action == "" {
+		action = "watch"
+	}
+
+	if m.watchers == nil {
+		errorMsg := "Task watchers are not available"
+		return &ToolResult{Error: &errorMsg, ErrorCode: ErrorCodeInternal}, nil
+	}
+	if userID == "" {
+		errorMsg := "user_id is required"
+		return &ToolResult{Error: &errorMsg, ErrorCode: ErrorCodeInvalidArgument}, nil
+	}
+	if action != "list" && taskID == "" {
+		errorMsg := "task_id is required"
+		return &ToolResult{Error: &errorMsg, ErrorCode: ErrorCodeInvalidArgument}, nil
+	}
+
+	var result string
+	switch action {
+	case "watch":
+		// Make sure the task exists before watching it
+		var provider providers.TaskProvider
+		var err error
+		if providerName != "" {
+			provider, err = m.registry.GetProvider(providerName)
+		} else {
+			provider, err = m.registry.GetDefaultProvider()
+		}
+		if err != nil {
+			errorMsg := fmt.Sprintf("Failed to get provider: %v", err)
+			return &ToolResult{Error: &errorMsg, ErrorCode: errorCode(err)}, nil
+		}
+		task, err := provider.GetTask(ctx, taskID)
+		if err != nil {
+			errorMsg := fmt.Sprintf("Failed to get task: %v", err)
+			return &ToolResult{Error: &errorMsg, ErrorCode: errorCode(err)}, nil
+		}
+
+		if err := m.watchers.WatchTask(ctx, taskID, userID); err != nil {
+			errorMsg := fmt.Sprintf("Failed to watch task: %v", err)
+			return &ToolResult{Error: &errorMsg, ErrorCode: errorCode(err)}, nil
+		}
+		result = fmt.Sprintf("👀 %s is now watching %s: %s\n", userID, task.GetDisplayID(), task.Title)
+		result += fmt.Sprintf("Watchers: %s\n", strings.Join(m.watchers.Watchers(taskID), ", "))
+
+	case "unwatch":
+		if err := m.watchers.UnwatchTask(ctx, taskID, userID); err != nil {
+			errorMsg := fmt.Sprintf("Failed to unwatch task: %v", err)
+			return &ToolResult{Error: &errorMsg, ErrorCode: errorCode(err)}, nil
+		}
+		result = fmt.Sprintf("✅ %s stopped watching %s\n", userID, taskID)
+
+	case "list":
+		watched := m.watchers.WatchedTasks(userID)
+		if len(watched) == 0 {
+			result = fmt.Sprintf("%s is not watching any tasks\n", userID)
+			break
+		}
+		result = fmt.Sprintf("👀 Tasks watched by %s:\n", userID)
+		for _, watcher := range watched {
+			result += fmt.Sprintf("• %s (since %s)\n", watcher.TaskID, watcher.WatchedAt.Format("2006-01-02 15:04"))
+		}
+
+	default:
+		errorMsg := fmt.Sprintf("Unknown action: %s", action)
+		return &ToolResult{Error: &errorMsg, ErrorCode: ErrorCodeInvalidArgument}, nil
+	}
+
+	return &ToolResult{
+		Content: []map[string]interface{}{
+			{
+				"type": "text",
+				"text": result,
+			},
+		},
+	}, nil
+}
+
+func (m *MCPToolProvider) executeAITriageTasks(ctx context.Context, args map[string]interface{}) (*ToolResult, error) {
+	providerName, _ := args["provider"].(string)
+	projectID, _ := args["project_id"].(string)
+	apply, _ := args["apply"].(bool)
+	limit := 20
+	if l, ok := args["limit"].(float64); ok && l > 0 {
+		limit = int(l)
+	}
+
+	var provider providers.TaskProvider
+	var err error
+	if providerName != "" {
+		provider, err = m.registry.GetProvider(providerName)
+	} else {
+		provider, err = m.registry.GetDefaultProvider()
+	}
+	if err != nil {
+		errorMsg := fmt.Sprintf("Failed to get provider: %v", err)
+		return &ToolResult{Error: &errorMsg, ErrorCode: errorCode(err)}, nil
+	}
+
+	results, _, err := providers.Triage(ctx, provider, providers.TriageOptions{ProjectID: projectID, Limit: limit}, m.aiChains.TriageSuggester())
+	if err != nil {
+		errorMsg := fmt.Sprintf("Failed to triage tasks: %v", err)
+		return &ToolResult{Error: &errorMsg, ErrorCode: errorCode(err)}, nil
+	}
+
+	applied, failed := 0, 0
+	if apply {
+		applied, failed = providers.ApplyTriage(ctx, provider, results)
+	}
+
+	result := "🏷️ AI Task Triage\n"
+	result += "=================\n"
+	if len(results) == 0 {
+		result += "No untriaged tasks found\n"
+	}
+	for _, triage := range results {
+		result += fmt.Sprintf("\n%s: %s\n", triage.TaskID, triage.Title)
+		for _, change := range triage.Changes {
+			result += fmt.Sprintf("   • %s → %s\n", change.Field, change.New)
 		}
-		result += fmt.Sprintf("• Estimated completion: %d day(s)\n", int(estimatedDays))
+		switch {
+		case triage.Error != "":
+			result += fmt.Sprintf("   ❌ %s\n", triage.Error)
+		case len(triage.Changes) == 0:
+			result += "   Nothing to change\n"
+		case triage.Applied:
+			result += "   ✅ Applied\n"
+		}
+		if triage.Suggestion != nil && triage.Suggestion.Reason != "" {
+			result += fmt.Sprintf("   💡 %s\n", triage.Suggestion.Reason)
+		}
+	}
+
+	if apply {
+		result += fmt.Sprintf("\nApplied %d suggestions, %d failed\n", applied, failed)
+	} else if len(results) > 0 {
+		result += "\nCall again with apply=true to apply these suggestions\n"
+	}
+
+	return &ToolResult{
+		Content: []map[string]interface{}{
+			{
+				"type": "text",
+				"text": result,
+			},
+		},
+	}, nil
+}
+
+func (m *MCPToolProvider) executeAIExpandTask(ctx context.Context, args map[string]interface{}) (*ToolResult, error) {
+	providerName, _ := args["provider"].(string)
+	taskID, _ := args["task_id"].(string)
+	apply, _ := args["apply"].(bool)
+
+	if taskID == "" {
+		errorMsg := "task_id is required"
+		return &ToolResult{Error: &errorMsg, ErrorCode: ErrorCodeInvalidArgument}, nil
+	}
+
+	var provider providers.TaskProvider
+	var err error
+	if providerName != "" {
+		provider, err = m.registry.GetProvider(providerName)
+	} else {
+		provider, err = m.registry.GetDefaultProvider()
+	}
+	if err != nil {
+		errorMsg := fmt.Sprintf("Failed to get provider: %v", err)
+		return &ToolResult{Error: &errorMsg, ErrorCode: errorCode(err)}, nil
+	}
+
+	task, err := provider.GetTask(ctx, taskID)
+	if err != nil {
+		errorMsg := fmt.Sprintf("Failed to get task: %v", err)
+		return &ToolResult{Error: &errorMsg, ErrorCode: errorCode(err)}, nil
+	}
+
+	expansion, err := m.aiChains.WithContext(ctx).ExpandTask(task.Title, task.Description, string(task.Type))
+	if err != nil {
+		errorMsg := fmt.Sprintf("Failed to expand task: %v", err)
+		return &ToolResult{Error: &errorMsg, ErrorCode: errorCode(err)}, nil
+	}
+	description := expansion.Description(task.Description)
+
+	result := fmt.Sprintf("📝 Expanded description of %s: %s\n\n%s\n", task.GetDisplayID(), task.Title, description)
+	if apply {
+		if err := provider.UpdateTask(ctx, taskID, &providers.TaskUpdate{Description: &description}); err != nil {
+			errorMsg := fmt.Sprintf("Failed to update task: %v", err)
+			return &ToolResult{Error: &errorMsg, ErrorCode: errorCode(err)}, nil
+		}
+		result += "✅ Task description updated\n"
+	} else {
+		result += "Call again with apply=true to update the task description\n"
 	}
 
 	return &ToolResult{
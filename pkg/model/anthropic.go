@@ -0,0 +1,326 @@
+package model
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/grik-ai/ricochet-task/pkg/chain"
+)
+
+const (
+	defaultAnthropicAPIBaseURL = "https://api.anthropic.com/v1"
+	defaultAnthropicTimeout    = 90 * time.Second
+	anthropicAPIVersion        = "2023-06-01"
+)
+
+// AnthropicProvider провайдер для моделей Anthropic Claude
+type AnthropicProvider struct {
+	*BaseProvider
+	client       *http.Client
+	streamClient *http.Client
+}
+
+// AnthropicRequest запрос к Messages API Anthropic
+type AnthropicRequest struct {
+	Model         string             `json:"model"`
+	Messages      []AnthropicMessage `json:"messages"`
+	System        string             `json:"system,omitempty"`
+	MaxTokens     int                `json:"max_tokens"`
+	Temperature   float64            `json:"temperature,omitempty"`
+	TopP          float64            `json:"top_p,omitempty"`
+	StopSequences []string           `json:"stop_sequences,omitempty"`
+	Stream        bool               `json:"stream,omitempty"`
+}
+
+// AnthropicMessage сообщение в формате Anthropic
+type AnthropicMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// AnthropicResponse ответ Messages API Anthropic
+type AnthropicResponse struct {
+	ID      string `json:"id"`
+	Type    string `json:"type"`
+	Role    string `json:"role"`
+	Content []struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	} `json:"content"`
+	StopReason string `json:"stop_reason"`
+	Usage      struct {
+		InputTokens  int `json:"input_tokens"`
+		OutputTokens int `json:"output_tokens"`
+	} `json:"usage"`
+	Error *AnthropicError `json:"error,omitempty"`
+}
+
+// AnthropicStreamEvent событие потокового ответа Anthropic
+type AnthropicStreamEvent struct {
+	Type  string `json:"type"`
+	Delta struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	} `json:"delta"`
+	Error *AnthropicError `json:"error,omitempty"`
+}
+
+// AnthropicError ошибка API Anthropic
+type AnthropicError struct {
+	Type    string `json:"type"`
+	Message string `json:"message"`
+}
+
+// NewAnthropicProvider создает новый провайдер для Anthropic
+func NewAnthropicProvider(apiKey string, apiBaseURL string) *AnthropicProvider {
+	if apiBaseURL == "" {
+		apiBaseURL = defaultAnthropicAPIBaseURL
+	}
+
+	provider := &AnthropicProvider{
+		BaseProvider: NewBaseProvider(chain.ModelTypeClaude, apiKey, apiBaseURL),
+		client: &http.Client{
+			Timeout: defaultAnthropicTimeout,
+		},
+		streamClient: &http.Client{},
+	}
+
+	// Регистрируем поддерживаемые модели; Version - идентификатор модели в API
+	provider.RegisterModels([]chain.ModelConfiguration{
+		{
+			Name:      chain.ModelNameClaude3Haiku,
+			Type:      chain.ModelTypeClaude,
+			Context:   200000,
+			MaxTokens: 4096,
+			Version:   "claude-3-haiku-20240307",
+			Provider:  "Anthropic",
+			Endpoint:  "/messages",
+		},
+		{
+			Name:      chain.ModelNameClaude3Sonnet,
+			Type:      chain.ModelTypeClaude,
+			Context:   200000,
+			MaxTokens: 4096,
+			Version:   "claude-3-sonnet-20240229",
+			Provider:  "Anthropic",
+			Endpoint:  "/messages",
+		},
+		{
+			Name:      chain.ModelNameClaude3Opus,
+			Type:      chain.ModelTypeClaude,
+			Context:   200000,
+			MaxTokens: 4096,
+			Version:   "claude-3-opus-20240229",
+			Provider:  "Anthropic",
+			Endpoint:  "/messages",
+		},
+	})
+
+	return provider
+}
+
+// Execute выполняет запрос к модели Anthropic
+func (p *AnthropicProvider) Execute(ctx context.Context, model chain.Model, prompt string, options map[string]interface{}) (string, error) {
+	req, err := p.newRequest(ctx, model, prompt, options, false)
+	if err != nil {
+		return "", err
+	}
+
+	// Выполняем запрос
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// Читаем ответ
+	responseBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read response: %w", err)
+	}
+
+	// Проверяем статус-код
+	if resp.StatusCode != http.StatusOK {
+		return "", anthropicAPIError(resp, responseBody)
+	}
+
+	// Разбираем ответ
+	var response AnthropicResponse
+	if err := json.Unmarshal(responseBody, &response); err != nil {
+		return "", fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	var text strings.Builder
+	for _, content := range response.Content {
+		if content.Type == "text" {
+			text.WriteString(content.Text)
+		}
+	}
+	if text.Len() == 0 {
+		return "", fmt.Errorf("no response from model")
+	}
+
+	return text.String(), nil
+}
+
+// ExecuteStream выполняет запрос к модели Anthropic с потоковой передачей ответа (SSE)
+func (p *AnthropicProvider) ExecuteStream(ctx context.Context, model chain.Model, prompt string, options map[string]interface{}) (<-chan StreamChunk, error) {
+	req, err := p.newRequest(ctx, model, prompt, options, true)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+
+	// Время потокового запроса ограничивается только контекстом
+	resp, err := p.streamClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		responseBody, _ := ioutil.ReadAll(resp.Body)
+		return nil, anthropicAPIError(resp, responseBody)
+	}
+
+	return streamSSE(ctx, resp.Body, func(event string, data string) (string, bool, error) {
+		var streamEvent AnthropicStreamEvent
+		if err := json.Unmarshal([]byte(data), &streamEvent); err != nil {
+			return "", false, nil
+		}
+
+		switch streamEvent.Type {
+		case "content_block_delta":
+			if streamEvent.Delta.Type == "text_delta" {
+				return streamEvent.Delta.Text, false, nil
+			}
+		case "message_stop":
+			return "", true, nil
+		case "error":
+			return "", true, anthropicStreamError(streamEvent.Error)
+		}
+		return "", false, nil
+	}), nil
+}
+
+// anthropicStreamErrorStatus сопоставляет типы ошибок в потоке ответа
+// HTTP-статусам, с которыми API вернул бы их до начала передачи, чтобы
+// временные ошибки, например перегрузка, можно было повторить
+var anthropicStreamErrorStatus = map[string]int{
+	"invalid_request_error": http.StatusBadRequest,
+	"authentication_error":  http.StatusUnauthorized,
+	"permission_error":      http.StatusForbidden,
+	"not_found_error":       http.StatusNotFound,
+	"rate_limit_error":      http.StatusTooManyRequests,
+	"api_error":             http.StatusInternalServerError,
+	"overloaded_error":      529,
+}
+
+// anthropicStreamError преобразует событие error потока в ошибку API
+func anthropicStreamError(streamErr *AnthropicError) error {
+	if streamErr == nil {
+		return &APIError{StatusCode: http.StatusInternalServerError, Message: "stream error"}
+	}
+	status, ok := anthropicStreamErrorStatus[streamErr.Type]
+	if !ok {
+		status = http.StatusInternalServerError
+	}
+	message := streamErr.Message
+	if message == "" {
+		message = streamErr.Type
+	}
+	return &APIError{StatusCode: status, Message: message}
+}
+
+// newRequest формирует HTTP-запрос к Messages API Anthropic
+func (p *AnthropicProvider) newRequest(ctx context.Context, model chain.Model, prompt string, options map[string]interface{}, stream bool) (*http.Request, error) {
+	// Проверяем API-ключ
+	if err := p.ValidateAPIKey(); err != nil {
+		return nil, err
+	}
+
+	// Получаем конфигурацию модели
+	modelConfig, err := p.GetModel(model.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	maxTokens := model.MaxTokens
+	if maxTokens <= 0 {
+		maxTokens = modelConfig.MaxTokens / 2
+	}
+
+	modelID := modelConfig.Version
+	if modelID == "" {
+		modelID = string(model.Name)
+	}
+
+	// Формируем запрос
+	request := AnthropicRequest{
+		Model: modelID,
+		Messages: []AnthropicMessage{
+			{
+				Role:    "user",
+				Content: prompt,
+			},
+		},
+		MaxTokens:   maxTokens,
+		Temperature: model.Temperature,
+		Stream:      stream,
+	}
+
+	// Системный промпт передается отдельным полем
+	if systemPrompt, ok := options["system_prompt"].(string); ok {
+		request.System = systemPrompt
+	}
+
+	// Дополнительные параметры
+	if topP, ok := options["top_p"].(float64); ok {
+		request.TopP = topP
+	}
+
+	if stop, ok := options["stop"].([]string); ok {
+		request.StopSequences = stop
+	}
+
+	// Кодируем запрос в JSON
+	requestBody, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	// Создаем HTTP-запрос
+	endpoint := fmt.Sprintf("%s%s", p.apiBaseURL, modelConfig.Endpoint)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewBuffer(requestBody))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	// Устанавливаем заголовки
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("x-api-key", p.apiKey)
+	req.Header.Set("anthropic-version", anthropicAPIVersion)
+
+	return req, nil
+}
+
+// anthropicAPIError формирует ошибку по неуспешному ответу API
+func anthropicAPIError(resp *http.Response, responseBody []byte) error {
+	var errorResp AnthropicResponse
+	if err := json.Unmarshal(responseBody, &errorResp); err == nil && errorResp.Error != nil && errorResp.Error.Message != "" {
+		return &APIError{StatusCode: resp.StatusCode, Message: errorResp.Error.Message}
+	}
+	return &APIError{StatusCode: resp.StatusCode, Message: resp.Status}
+}
+
+// EstimateTokens переопределяет метод базового провайдера для лучшей оценки
+func (p *AnthropicProvider) EstimateTokens(text string) int {
+	estimator := NewTokenEstimator()
+	return estimator.EstimateTokens(text, "")
+}
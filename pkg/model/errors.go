@@ -0,0 +1,204 @@
+package model
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"unicode"
+
+	"github.com/grik-ai/ricochet-task/pkg/chain"
+)
+
+// APIError ошибка, которую вернул API провайдера
+type APIError struct {
+	StatusCode int    // HTTP-статус ответа
+	Message    string // Сообщение об ошибке от провайдера или текст статуса
+}
+
+// Error возвращает текст ошибки
+func (e *APIError) Error() string {
+	return fmt.Sprintf("API error: %s", e.Message)
+}
+
+// HTTPStatus возвращает HTTP-статус ответа
+func (e *APIError) HTTPStatus() int {
+	return e.StatusCode
+}
+
+// IsRetryable сообщает, что ошибка временная и запрос имеет смысл повторить,
+// в том числе другой моделью: превышение лимитов (429), перегрузка или сбой
+// провайдера (5xx), сетевые ошибки и обрыв соединения. Ошибки запроса (4xx),
+// например неверный ключ или слишком длинный промпт, и отмена контекста
+// временными не считаются.
+//
+// Статус берется у любой ошибки в цепочке, реализующей HTTPStatus() int,
+// поэтому функция подходит и для ошибок других клиентов API.
+func IsRetryable(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+
+	var statusErr interface{ HTTPStatus() int }
+	if errors.As(err, &statusErr) {
+		status := statusErr.HTTPStatus()
+		return status == http.StatusRequestTimeout ||
+			status == http.StatusTooManyRequests ||
+			status >= http.StatusInternalServerError
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+
+	return errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, context.DeadlineExceeded)
+}
+
+// ExecuteWithFallback выполняет шаг цепочки основной моделью, а если она
+// недоступна из-за временного сбоя (IsRetryable) - запасными моделями шага по
+// порядку. execute вызывается сначала с шагом, затем с копией шага для каждой
+// запасной модели (chain.Model.WithFallback). Возвращает ответ и модель,
+// которая его дала; если не ответила ни одна, ошибка содержит ошибки всех моделей.
+func ExecuteWithFallback(ctx context.Context, step chain.Model, execute func(m chain.Model) (string, error)) (string, chain.Model, error) {
+	output, err := execute(step)
+	if err == nil || len(step.FallbackModels) == 0 || !IsRetryable(err) {
+		return output, step, err
+	}
+
+	errs := []error{fmt.Errorf("%s: %w", step.Name, err)}
+	for _, name := range step.FallbackModels {
+		// Истекший или отмененный контекст не даст выполниться и запасной модели
+		if ctx.Err() != nil {
+			break
+		}
+
+		fallback, err := step.WithFallback(name)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		output, err := execute(fallback)
+		if err == nil {
+			return output, fallback, nil
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", name, err))
+	}
+
+	return "", step, errors.Join(errs...)
+}
+
+// maxSuggestionDistance ограничивает число правок, при котором имя модели
+// считается опечаткой другого
+const maxSuggestionDistance = 3
+
+// ModelNotFoundError ошибка поиска модели по имени. Содержит модели и
+// провайдеры, среди которых шел поиск, и ближайшее по написанию имя
+type ModelNotFoundError struct {
+	Name       chain.ModelName   // Запрошенное имя
+	Available  []chain.ModelName // Модели зарегистрированных провайдеров
+	Providers  []chain.ModelType // Зарегистрированные провайдеры
+	Suggestion chain.ModelName   // Ближайшее имя или пустая строка
+}
+
+// NewModelNotFoundError создает ошибку поиска модели и подбирает подсказку
+func NewModelNotFoundError(name chain.ModelName, available []chain.ModelName, providers []chain.ModelType) *ModelNotFoundError {
+	return &ModelNotFoundError{
+		Name:       name,
+		Available:  available,
+		Providers:  providers,
+		Suggestion: SuggestModelName(name, available),
+	}
+}
+
+// Error возвращает текст ошибки с подсказкой и списком доступных моделей
+func (e *ModelNotFoundError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "model not found: %s", e.Name)
+	if e.Suggestion != "" {
+		fmt.Fprintf(&b, " (did you mean %q?)", e.Suggestion)
+	}
+
+	if len(e.Providers) == 0 {
+		b.WriteString("; no model providers are registered, add an API key with 'ricochet key add'")
+		return b.String()
+	}
+
+	names := make([]string, len(e.Providers))
+	for i, provider := range e.Providers {
+		names[i] = string(provider)
+	}
+	fmt.Fprintf(&b, "; registered providers: %s", strings.Join(names, ", "))
+
+	names = make([]string, len(e.Available))
+	for i, model := range e.Available {
+		names[i] = string(model)
+	}
+	fmt.Fprintf(&b, "; available models: %s", strings.Join(names, ", "))
+	return b.String()
+}
+
+// Unwrap позволяет проверять ошибку через errors.Is(err, ErrModelNotFound)
+func (e *ModelNotFoundError) Unwrap() error {
+	return ErrModelNotFound
+}
+
+// SuggestModelName возвращает имя из available, ближайшее к name: сначала
+// совпадающее без учета регистра и разделителей ("gpt4" и "gpt-4"), затем
+// отличающееся не больше чем на несколько правок. Если похожих нет,
+// возвращает пустую строку.
+func SuggestModelName(name chain.ModelName, available []chain.ModelName) chain.ModelName {
+	normalized := normalizeModelName(string(name))
+	for _, candidate := range available {
+		if normalizeModelName(string(candidate)) == normalized {
+			return candidate
+		}
+	}
+
+	var best chain.ModelName
+	bestDistance := maxSuggestionDistance + 1
+	for _, candidate := range available {
+		distance := editDistance(normalized, normalizeModelName(string(candidate)))
+		if distance < bestDistance && distance <= len([]rune(normalized))/2 {
+			best, bestDistance = candidate, distance
+		}
+	}
+	return best
+}
+
+// normalizeModelName приводит имя к нижнему регистру без разделителей
+func normalizeModelName(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '-', '_', '.', ' ', '/', ':':
+			return -1
+		}
+		return unicode.ToLower(r)
+	}, name)
+}
+
+// editDistance считает расстояние Левенштейна между строками
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur := make([]int, len(rb)+1)
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(rb)]
+}
@@ -0,0 +1,131 @@
+package model
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/grik-ai/ricochet-task/pkg/chain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"rate limited", &APIError{StatusCode: http.StatusTooManyRequests}, true},
+		{"service unavailable", &APIError{StatusCode: http.StatusServiceUnavailable}, true},
+		{"wrapped server error", fmt.Errorf("model execution failed: %w", &APIError{StatusCode: http.StatusBadGateway}), true},
+		{"unauthorized", &APIError{StatusCode: http.StatusUnauthorized}, false},
+		{"bad request", &APIError{StatusCode: http.StatusBadRequest}, false},
+		{"network", fmt.Errorf("request failed: %w", &net.OpError{Op: "dial", Err: errors.New("connection refused")}), true},
+		{"cancelled", fmt.Errorf("request failed: %w", context.Canceled), false},
+		{"plain", errors.New("API key is required"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, IsRetryable(tt.err))
+		})
+	}
+}
+
+func TestExecuteWithFallback(t *testing.T) {
+	step := chain.Model{
+		Name:           chain.ModelNameGPT4,
+		Type:           chain.ModelTypeOpenAI,
+		FallbackModels: []chain.ModelName{"no-such-model", chain.ModelNameClaude3Sonnet},
+	}
+	unavailable := &APIError{StatusCode: http.StatusServiceUnavailable, Message: "overloaded"}
+
+	t.Run("unknown fallback is skipped", func(t *testing.T) {
+		var calls []chain.ModelName
+		output, served, err := ExecuteWithFallback(context.Background(), step, func(m chain.Model) (string, error) {
+			calls = append(calls, m.Name)
+			if m.Name == chain.ModelNameGPT4 {
+				return "", unavailable
+			}
+			return "answer from " + string(m.Name), nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "answer from claude-3-sonnet", output)
+		assert.Equal(t, chain.ModelNameClaude3Sonnet, served.Name)
+		assert.Equal(t, chain.ModelTypeClaude, served.Type)
+		assert.Equal(t, []chain.ModelName{chain.ModelNameGPT4, chain.ModelNameClaude3Sonnet}, calls)
+	})
+
+	t.Run("cancelled context stops fallbacks", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		var calls []chain.ModelName
+		_, served, err := ExecuteWithFallback(ctx, step, func(m chain.Model) (string, error) {
+			calls = append(calls, m.Name)
+			cancel()
+			return "", unavailable
+		})
+		require.Error(t, err)
+		assert.Equal(t, chain.ModelNameGPT4, served.Name)
+		assert.Equal(t, []chain.ModelName{chain.ModelNameGPT4}, calls)
+	})
+}
+
+func TestOpenAIExecuteReturnsAPIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		fmt.Fprint(w, `{"error":{"message":"The server is overloaded"}}`)
+	}))
+	defer server.Close()
+
+	provider := NewOpenAIProvider("test-key", server.URL)
+	_, err := provider.Execute(context.Background(), chain.Model{Name: chain.ModelNameGPT4}, "test", nil)
+	require.Error(t, err)
+
+	var apiErr *APIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusServiceUnavailable, apiErr.StatusCode)
+	assert.Equal(t, "API error: The server is overloaded", err.Error())
+	assert.True(t, IsRetryable(err))
+}
+
+func TestFindModel(t *testing.T) {
+	factory := NewProviderFactory()
+
+	_, err := factory.FindModel("gpt-4")
+	var notFound *ModelNotFoundError
+	require.ErrorAs(t, err, &notFound)
+	assert.Empty(t, notFound.Providers)
+	assert.Contains(t, err.Error(), "ricochet key add")
+
+	factory.RegisterProvider(NewOpenAIProvider("test-key", ""))
+	factory.RegisterProvider(NewAnthropicProvider("test-key", ""))
+
+	model, err := factory.FindModel("gpt-4")
+	require.NoError(t, err)
+	assert.Equal(t, chain.ModelName("gpt-4"), model.Name)
+
+	_, err = factory.FindModel("gpt4")
+	require.ErrorAs(t, err, &notFound)
+	assert.True(t, errors.Is(err, ErrModelNotFound))
+	assert.Equal(t, chain.ModelName("gpt-4"), notFound.Suggestion)
+	assert.Equal(t, []chain.ModelType{chain.ModelTypeClaude, chain.ModelTypeOpenAI}, notFound.Providers)
+	assert.Contains(t, notFound.Available, chain.ModelName("gpt-4"))
+	assert.Contains(t, err.Error(), `model not found: gpt4 (did you mean "gpt-4"?)`)
+	assert.Contains(t, err.Error(), "registered providers: claude, openai")
+}
+
+func TestSuggestModelName(t *testing.T) {
+	available := []chain.ModelName{"gpt-4", "gpt-4o", "claude-3-opus"}
+
+	assert.Equal(t, chain.ModelName("gpt-4"), SuggestModelName("GPT 4", available))
+	assert.Equal(t, chain.ModelName("gpt-4o"), SuggestModelName("gpt-4oo", available))
+	assert.Equal(t, chain.ModelName("claude-3-opus"), SuggestModelName("claude-3-opsu", available))
+	assert.Empty(t, SuggestModelName("llama", available))
+	assert.Empty(t, SuggestModelName("gpt", nil))
+}
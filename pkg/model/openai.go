@@ -20,7 +20,8 @@ const (
 // OpenAIProvider провайдер для моделей OpenAI
 type OpenAIProvider struct {
 	*BaseProvider
-	client *http.Client
+	client       *http.Client
+	streamClient *http.Client
 }
 
 // OpenAIRequest запрос к API OpenAI
@@ -61,6 +62,19 @@ type Choice struct {
 	FinishReason string        `json:"finish_reason"`
 }
 
+// OpenAIStreamChunk фрагмент потокового ответа API OpenAI
+type OpenAIStreamChunk struct {
+	ID      string `json:"id"`
+	Choices []struct {
+		Index int `json:"index"`
+		Delta struct {
+			Content string `json:"content"`
+		} `json:"delta"`
+		FinishReason string `json:"finish_reason"`
+	} `json:"choices"`
+	Error *OpenAIError `json:"error,omitempty"`
+}
+
 // Usage информация об использовании токенов
 type Usage struct {
 	PromptTokens     int `json:"prompt_tokens"`
@@ -87,6 +101,7 @@ func NewOpenAIProvider(apiKey string, apiBaseURL string) *OpenAIProvider {
 		client: &http.Client{
 			Timeout: defaultOpenAITimeout,
 		},
+		streamClient: &http.Client{},
 	}
 
 	// Регистрируем поддерживаемые модели
@@ -143,15 +158,95 @@ func NewOpenAIProvider(apiKey string, apiBaseURL string) *OpenAIProvider {
 
 // Execute выполняет запрос к модели OpenAI
 func (p *OpenAIProvider) Execute(ctx context.Context, model chain.Model, prompt string, options map[string]interface{}) (string, error) {
+	req, err := p.newRequest(ctx, model, prompt, options, false)
+	if err != nil {
+		return "", err
+	}
+
+	// Выполняем запрос
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// Читаем ответ
+	responseBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read response: %w", err)
+	}
+
+	// Проверяем статус-код
+	if resp.StatusCode != http.StatusOK {
+		return "", openAIAPIError(resp, responseBody)
+	}
+
+	// Разбираем ответ
+	var response OpenAIResponse
+	if err := json.Unmarshal(responseBody, &response); err != nil {
+		return "", fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	// Проверяем наличие ответа
+	if len(response.Choices) == 0 {
+		return "", fmt.Errorf("no response from model")
+	}
+
+	return response.Choices[0].Message.Content, nil
+}
+
+// ExecuteStream выполняет запрос к модели OpenAI с потоковой передачей ответа (SSE)
+func (p *OpenAIProvider) ExecuteStream(ctx context.Context, model chain.Model, prompt string, options map[string]interface{}) (<-chan StreamChunk, error) {
+	req, err := p.newRequest(ctx, model, prompt, options, true)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+
+	// Общий таймаут клиента оборвал бы длинный ответ, поэтому время
+	// потокового запроса ограничивается только контекстом
+	resp, err := p.streamClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		responseBody, _ := ioutil.ReadAll(resp.Body)
+		return nil, openAIAPIError(resp, responseBody)
+	}
+
+	return streamSSE(ctx, resp.Body, func(event string, data string) (string, bool, error) {
+		if data == "[DONE]" {
+			return "", true, nil
+		}
+
+		var chunk OpenAIStreamChunk
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			return "", false, nil
+		}
+		// Ошибка посреди ответа приходит событием с полем error
+		if chunk.Error != nil && chunk.Error.Message != "" {
+			return "", true, &APIError{StatusCode: http.StatusInternalServerError, Message: chunk.Error.Message}
+		}
+		if len(chunk.Choices) == 0 {
+			return "", false, nil
+		}
+		return chunk.Choices[0].Delta.Content, false, nil
+	}), nil
+}
+
+// newRequest формирует HTTP-запрос к API чата OpenAI
+func (p *OpenAIProvider) newRequest(ctx context.Context, model chain.Model, prompt string, options map[string]interface{}, stream bool) (*http.Request, error) {
 	// Проверяем API-ключ
 	if err := p.ValidateAPIKey(); err != nil {
-		return "", err
+		return nil, err
 	}
 
 	// Получаем конфигурацию модели
 	modelConfig, err := p.GetModel(model.Name)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	// Создаем запрос
@@ -189,6 +284,7 @@ func (p *OpenAIProvider) Execute(ctx context.Context, model chain.Model, prompt
 		Messages:    messages,
 		Temperature: temperature,
 		MaxTokens:   maxTokens,
+		Stream:      stream,
 	}
 
 	// Дополнительные параметры
@@ -211,54 +307,30 @@ func (p *OpenAIProvider) Execute(ctx context.Context, model chain.Model, prompt
 	// Кодируем запрос в JSON
 	requestBody, err := json.Marshal(request)
 	if err != nil {
-		return "", fmt.Errorf("failed to marshal request: %w", err)
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
 	}
 
 	// Создаем HTTP-запрос
 	endpoint := fmt.Sprintf("%s%s", p.apiBaseURL, modelConfig.Endpoint)
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewBuffer(requestBody))
 	if err != nil {
-		return "", fmt.Errorf("failed to create request: %w", err)
+		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	// Устанавливаем заголовки
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", p.apiKey))
 
-	// Выполняем запрос
-	resp, err := p.client.Do(req)
-	if err != nil {
-		return "", fmt.Errorf("request failed: %w", err)
-	}
-	defer resp.Body.Close()
-
-	// Читаем ответ
-	responseBody, err := ioutil.ReadAll(resp.Body)
-	if err != nil {
-		return "", fmt.Errorf("failed to read response: %w", err)
-	}
-
-	// Проверяем статус-код
-	if resp.StatusCode != http.StatusOK {
-		var errorResp OpenAIResponse
-		if err := json.Unmarshal(responseBody, &errorResp); err == nil && errorResp.Error.Message != "" {
-			return "", fmt.Errorf("API error: %s", errorResp.Error.Message)
-		}
-		return "", fmt.Errorf("API error: %s", resp.Status)
-	}
-
-	// Разбираем ответ
-	var response OpenAIResponse
-	if err := json.Unmarshal(responseBody, &response); err != nil {
-		return "", fmt.Errorf("failed to parse response: %w", err)
-	}
+	return req, nil
+}
 
-	// Проверяем наличие ответа
-	if len(response.Choices) == 0 {
-		return "", fmt.Errorf("no response from model")
+// openAIAPIError формирует ошибку по неуспешному ответу API
+func openAIAPIError(resp *http.Response, responseBody []byte) error {
+	var errorResp OpenAIResponse
+	if err := json.Unmarshal(responseBody, &errorResp); err == nil && errorResp.Error.Message != "" {
+		return &APIError{StatusCode: resp.StatusCode, Message: errorResp.Error.Message}
 	}
-
-	return response.Choices[0].Message.Content, nil
+	return &APIError{StatusCode: resp.StatusCode, Message: resp.Status}
 }
 
 // EstimateTokens переопределяет метод базового провайдера для лучшей оценки
@@ -0,0 +1,54 @@
+package model
+
+import (
+	"github.com/grik-ai/ricochet-task/pkg/chain"
+)
+
+// ModelPricing стоимость использования модели в долларах за 1000 токенов
+type ModelPricing struct {
+	InputPer1K  float64 `json:"input_per_1k"`  // Стоимость 1000 входных токенов
+	OutputPer1K float64 `json:"output_per_1k"` // Стоимость 1000 выходных токенов
+}
+
+// Cost возвращает стоимость запроса с указанным количеством токенов
+func (p ModelPricing) Cost(inputTokens, outputTokens int) float64 {
+	return float64(inputTokens)*p.InputPer1K/1000 + float64(outputTokens)*p.OutputPer1K/1000
+}
+
+// modelPricing публичные цены API провайдеров. Цены меняются, поэтому
+// рассчитанная по ним стоимость - ориентир, а не счет.
+var modelPricing = map[chain.ModelName]ModelPricing{
+	// OpenAI
+	chain.ModelNameGPT35Turbo:    {InputPer1K: 0.0005, OutputPer1K: 0.0015},
+	chain.ModelNameGPT35Turbo16k: {InputPer1K: 0.003, OutputPer1K: 0.004},
+	chain.ModelNameGPT4:          {InputPer1K: 0.03, OutputPer1K: 0.06},
+	chain.ModelNameGPT432k:       {InputPer1K: 0.06, OutputPer1K: 0.12},
+	chain.ModelNameGPT4Turbo:     {InputPer1K: 0.01, OutputPer1K: 0.03},
+	chain.ModelNameGPT4Vision:    {InputPer1K: 0.01, OutputPer1K: 0.03},
+
+	// Anthropic
+	chain.ModelNameClaude2:       {InputPer1K: 0.008, OutputPer1K: 0.024},
+	chain.ModelNameClaude2_1:     {InputPer1K: 0.008, OutputPer1K: 0.024},
+	chain.ModelNameClaude3Haiku:  {InputPer1K: 0.00025, OutputPer1K: 0.00125},
+	chain.ModelNameClaude3Sonnet: {InputPer1K: 0.003, OutputPer1K: 0.015},
+	chain.ModelNameClaude3Opus:   {InputPer1K: 0.015, OutputPer1K: 0.075},
+
+	// DeepSeek
+	chain.ModelNameDeepSeekChat:  {InputPer1K: 0.00014, OutputPer1K: 0.00028},
+	chain.ModelNameDeepSeekCoder: {InputPer1K: 0.00014, OutputPer1K: 0.00028},
+
+	// Mistral
+	chain.ModelNameMistralSmall:  {InputPer1K: 0.002, OutputPer1K: 0.006},
+	chain.ModelNameMistralMedium: {InputPer1K: 0.0027, OutputPer1K: 0.0081},
+	chain.ModelNameMistralLarge:  {InputPer1K: 0.008, OutputPer1K: 0.024},
+
+	// Локальные модели бесплатны
+	chain.ModelNameLlama2: {},
+	chain.ModelNameLlama3: {},
+}
+
+// GetModelPricing возвращает цены модели. false означает, что цены неизвестны.
+func GetModelPricing(name chain.ModelName) (ModelPricing, bool) {
+	pricing, exists := modelPricing[name]
+	return pricing, exists
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/grik-ai/ricochet-task/pkg/chain"
@@ -14,6 +15,13 @@ type Provider interface {
 	// Execute выполняет запрос к модели
 	Execute(ctx context.Context, model chain.Model, prompt string, options map[string]interface{}) (string, error)
 
+	// ExecuteStream выполняет запрос к модели и отдает ответ по мере генерации.
+	// Канал закрывается по завершении ответа. Ошибка после начала передачи
+	// приходит последним фрагментом с заполненным Err, и полученный до нее
+	// текст неполон. Провайдеры без потоковой передачи отдают весь ответ
+	// одним фрагментом.
+	ExecuteStream(ctx context.Context, model chain.Model, prompt string, options map[string]interface{}) (<-chan StreamChunk, error)
+
 	// EstimateTokens оценивает количество токенов в тексте
 	EstimateTokens(text string) int
 
@@ -53,6 +61,30 @@ func (f *ProviderFactory) GetProvider(modelType chain.ModelType) (Provider, erro
 	return provider, nil
 }
 
+// FindModel ищет модель по имени у всех зарегистрированных провайдеров.
+// Если модели нет, возвращает *ModelNotFoundError со списком доступных
+// моделей и подсказкой для опечатки.
+func (f *ProviderFactory) FindModel(name chain.ModelName) (chain.ModelConfiguration, error) {
+	types := make([]chain.ModelType, 0, len(f.providers))
+	for modelType := range f.providers {
+		types = append(types, modelType)
+	}
+	sort.Slice(types, func(i, j int) bool { return types[i] < types[j] })
+
+	var available []chain.ModelName
+	for _, modelType := range types {
+		provider := f.providers[modelType]
+		if model, err := provider.GetModel(name); err == nil {
+			return model, nil
+		}
+		for _, model := range provider.GetAvailableModels() {
+			available = append(available, model.Name)
+		}
+	}
+
+	return chain.ModelConfiguration{}, NewModelNotFoundError(name, available, types)
+}
+
 // GetProviderForModel возвращает провайдера для модели
 func (f *ProviderFactory) GetProviderForModel(model chain.Model) (Provider, error) {
 	return f.GetProvider(model.Type)
@@ -107,6 +139,11 @@ func (p *BaseProvider) Execute(ctx context.Context, model chain.Model, prompt st
 	return "", errors.New("not implemented in base provider")
 }
 
+// ExecuteStream выполняет запрос к модели с потоковой передачей ответа
+func (p *BaseProvider) ExecuteStream(ctx context.Context, model chain.Model, prompt string, options map[string]interface{}) (<-chan StreamChunk, error) {
+	return nil, errors.New("not implemented in base provider")
+}
+
 // RegisterModels регистрирует модели
 func (p *BaseProvider) RegisterModels(models []chain.ModelConfiguration) {
 	p.models = models
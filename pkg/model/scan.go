@@ -0,0 +1,216 @@
+package model
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/grik-ai/ricochet-task/pkg/chain"
+)
+
+// binarySniffLength сколько байт начала файла проверяется на двоичные данные
+const binarySniffLength = 8000
+
+// FileTokens оценка токенов одного файла
+type FileTokens struct {
+	Path           string  `json:"path"` // Относительно корня сканирования
+	Bytes          int64   `json:"bytes"`
+	Tokens         int     `json:"tokens"`
+	Cost           float64 `json:"cost"`            // Стоимость входа в долларах
+	ExceedsContext bool    `json:"exceeds_context"` // Файл не помещается в контекст модели целиком
+}
+
+// TokenScan оценка токенов и стоимости набора файлов для модели
+type TokenScan struct {
+	Root        string          `json:"root"`
+	Glob        string          `json:"glob"`
+	Model       chain.ModelName `json:"model"`
+	Context     int             `json:"context"` // Размер контекста модели в токенах
+	Files       []FileTokens    `json:"files"`   // От больших к меньшим
+	TotalBytes  int64           `json:"total_bytes"`
+	TotalTokens int             `json:"total_tokens"`
+	Cost        float64         `json:"cost"`
+	Priced      bool            `json:"priced"` // false - цены модели неизвестны, стоимость не учтена
+	OverContext int             `json:"over_context"`
+	Skipped     int             `json:"skipped"` // Двоичные файлы
+}
+
+// ScanTokens обходит файлы каталога root, подходящие под шаблон glob, и
+// оценивает их токены тем же оценщиком, что и запуск цепочек. Стоимость
+// считается как вход модели: ответы зависят от цепочки и в оценку не входят.
+// Скрытые каталоги (.git и т. п.) и двоичные файлы пропускаются.
+func ScanTokens(root, glob string, modelName chain.ModelName) (*TokenScan, error) {
+	config, err := chain.NewModelRegistry().GetModelByName(modelName)
+	if err != nil {
+		return nil, registryModelNotFound(modelName)
+	}
+
+	if glob == "" {
+		glob = "**/*"
+	}
+	matcher, err := compileGlob(glob)
+	if err != nil {
+		return nil, err
+	}
+
+	pricing, priced := GetModelPricing(modelName)
+	scan := &TokenScan{
+		Root:    root,
+		Glob:    glob,
+		Model:   modelName,
+		Context: config.Context,
+		Files:   []FileTokens{},
+		Priced:  priced,
+	}
+	estimator := NewTokenEstimator()
+
+	err = filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			if path != root && strings.HasPrefix(entry.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if !matcher(rel) {
+			return nil
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", rel, err)
+		}
+		if isBinary(data) {
+			scan.Skipped++
+			return nil
+		}
+
+		file := FileTokens{
+			Path:   rel,
+			Bytes:  int64(len(data)),
+			Tokens: estimator.EstimateTokens(string(data), ""),
+		}
+		file.Cost = pricing.Cost(file.Tokens, 0)
+		file.ExceedsContext = config.Context > 0 && file.Tokens > config.Context
+
+		scan.Files = append(scan.Files, file)
+		scan.TotalBytes += file.Bytes
+		scan.TotalTokens += file.Tokens
+		scan.Cost += file.Cost
+		if file.ExceedsContext {
+			scan.OverContext++
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(scan.Files, func(i, j int) bool {
+		if scan.Files[i].Tokens != scan.Files[j].Tokens {
+			return scan.Files[i].Tokens > scan.Files[j].Tokens
+		}
+		return scan.Files[i].Path < scan.Files[j].Path
+	})
+	return scan, nil
+}
+
+// registryModelNotFound ошибка для модели, которой нет в реестре моделей
+func registryModelNotFound(name chain.ModelName) error {
+	registry := chain.NewModelRegistry()
+	var available []chain.ModelName
+	var types []chain.ModelType
+	seen := make(map[chain.ModelType]bool)
+	for _, m := range registry.Models {
+		available = append(available, m.Name)
+		if !seen[m.Type] {
+			seen[m.Type] = true
+			types = append(types, m.Type)
+		}
+	}
+	return NewModelNotFoundError(name, available, types)
+}
+
+// isBinary считает файл двоичным, если в его начале есть нулевой байт или
+// оно не является текстом UTF-8
+func isBinary(data []byte) bool {
+	head := data
+	if len(head) > binarySniffLength {
+		head = head[:binarySniffLength]
+		// Не считаем ошибкой символ, обрезанный на границе проверки
+		for i := 0; i < utf8.UTFMax && len(head) > 0 && !utf8.Valid(head); i++ {
+			head = head[:len(head)-1]
+		}
+	}
+	return bytes.IndexByte(head, 0) >= 0 || !utf8.Valid(head)
+}
+
+// compileGlob превращает шаблон пути в функцию проверки. "*" и "?" не
+// переходят через "/", "**" соответствует любому числу каталогов, "{a,b}" -
+// одному из вариантов. Шаблон без "/" проверяется по имени файла, поэтому
+// "*.md" находит файлы во всех каталогах.
+func compileGlob(glob string) (func(string) bool, error) {
+	byName := !strings.Contains(glob, "/")
+
+	var b strings.Builder
+	b.WriteString("^")
+	inGroup := false
+	for i := 0; i < len(glob); i++ {
+		c := glob[i]
+		switch {
+		case c == '*' && strings.HasPrefix(glob[i:], "**/"):
+			b.WriteString("(?:.*/)?")
+			i += 2
+		case c == '*' && strings.HasPrefix(glob[i:], "**"):
+			b.WriteString(".*")
+			i++
+		case c == '*':
+			b.WriteString("[^/]*")
+		case c == '?':
+			b.WriteString("[^/]")
+		case c == '{' && !inGroup:
+			b.WriteString("(?:")
+			inGroup = true
+		case c == '}' && inGroup:
+			b.WriteString(")")
+			inGroup = false
+		case c == ',' && inGroup:
+			b.WriteString("|")
+		default:
+			b.WriteString(regexp.QuoteMeta(glob[i : i+1]))
+		}
+	}
+	b.WriteString("$")
+
+	if inGroup {
+		return nil, fmt.Errorf("invalid glob %q: unclosed {", glob)
+	}
+	re, err := regexp.Compile(b.String())
+	if err != nil {
+		return nil, fmt.Errorf("invalid glob %q: %w", glob, err)
+	}
+
+	return func(path string) bool {
+		if byName {
+			return re.MatchString(filepath.Base(path))
+		}
+		return re.MatchString(path)
+	}, nil
+}
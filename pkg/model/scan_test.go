@@ -0,0 +1,79 @@
+package model
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/grik-ai/ricochet-task/pkg/chain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScanTokens(t *testing.T) {
+	root := t.TempDir()
+	files := map[string]string{
+		"README.md":         strings.Repeat("word ", 100),
+		"docs/guide.md":     strings.Repeat("word ", 40000),
+		"docs/api/ref.md":   "short",
+		"docs/notes.txt":    strings.Repeat("word ", 50),
+		"docs/logo.png":     "\x89PNG\x00\x00",
+		".git/HEAD.md":      "ref: refs/heads/main",
+		"docs/api/image.md": "binary\x00data",
+	}
+	for path, content := range files {
+		full := filepath.Join(root, filepath.FromSlash(path))
+		require.NoError(t, os.MkdirAll(filepath.Dir(full), 0755))
+		require.NoError(t, os.WriteFile(full, []byte(content), 0644))
+	}
+
+	t.Run("Markdown files are sorted by size", func(t *testing.T) {
+		scan, err := ScanTokens(root, "**/*.md", chain.ModelNameGPT4)
+		require.NoError(t, err)
+
+		var paths []string
+		for _, file := range scan.Files {
+			paths = append(paths, file.Path)
+		}
+		assert.Equal(t, []string{"docs/guide.md", "README.md", "docs/api/ref.md"}, paths)
+		assert.Equal(t, 1, scan.Skipped)
+		assert.True(t, scan.Priced)
+		assert.Equal(t, 1, scan.OverContext)
+		assert.True(t, scan.Files[0].ExceedsContext)
+
+		total := 0
+		for _, file := range scan.Files {
+			total += file.Tokens
+		}
+		assert.Equal(t, total, scan.TotalTokens)
+		assert.InDelta(t, float64(scan.TotalTokens)*0.03/1000, scan.Cost, 1e-9)
+	})
+
+	t.Run("Globs", func(t *testing.T) {
+		scan, err := ScanTokens(root, "docs/*.{md,txt}", chain.ModelNameGPT4)
+		require.NoError(t, err)
+		require.Len(t, scan.Files, 2)
+		assert.Equal(t, "docs/guide.md", scan.Files[0].Path)
+		assert.Equal(t, "docs/notes.txt", scan.Files[1].Path)
+
+		scan, err = ScanTokens(root, "*.txt", chain.ModelNameGPT4)
+		require.NoError(t, err)
+		require.Len(t, scan.Files, 1)
+
+		scan, err = ScanTokens(root, "", chain.ModelNameGPT4)
+		require.NoError(t, err)
+		assert.Len(t, scan.Files, 4)
+		assert.Equal(t, 2, scan.Skipped)
+
+		_, err = ScanTokens(root, "docs/{md", chain.ModelNameGPT4)
+		assert.Error(t, err)
+	})
+
+	t.Run("Unknown model", func(t *testing.T) {
+		_, err := ScanTokens(root, "", "gpt4")
+		var notFound *ModelNotFoundError
+		require.ErrorAs(t, err, &notFound)
+		assert.Equal(t, chain.ModelNameGPT4, notFound.Suggestion)
+	})
+}
@@ -0,0 +1,123 @@
+package model
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/grik-ai/ricochet-task/pkg/chain"
+)
+
+// maxSSELineSize ограничивает размер одной строки SSE-потока
+const maxSSELineSize = 1024 * 1024
+
+// StreamChunk фрагмент потокового ответа модели. Ошибка, прервавшая ответ
+// после начала передачи, приходит последним фрагментом с заполненным Err:
+// без нее оборванный ответ нельзя было бы отличить от полного.
+type StreamChunk struct {
+	Text string
+	Err  error
+}
+
+// ExecuteAsStream выполняет обычный запрос и отдает ответ одним фрагментом.
+// Используется провайдерами, API которых не поддерживает потоковую передачу.
+func ExecuteAsStream(ctx context.Context, provider Provider, model chain.Model, prompt string, options map[string]interface{}) (<-chan StreamChunk, error) {
+	output, err := provider.Execute(ctx, model, prompt, options)
+	if err != nil {
+		return nil, err
+	}
+
+	chunks := make(chan StreamChunk, 1)
+	if output != "" {
+		chunks <- StreamChunk{Text: output}
+	}
+	close(chunks)
+
+	return chunks, nil
+}
+
+// sseEventHandler разбирает событие SSE и возвращает фрагмент текста ответа.
+// done сообщает, что ответ завершен и поток можно закрыть; err - что API
+// прервал ответ ошибкой.
+type sseEventHandler func(event string, data string) (chunk string, done bool, err error)
+
+// streamSSE читает тело ответа в формате Server-Sent Events и передает
+// фрагменты текста в канал. Канал закрывается по завершении ответа, при
+// ошибке или отмене ctx; тело ответа закрывается вместе с каналом. Ошибка
+// API, ошибка чтения и обрыв потока до конца ответа передаются последним
+// фрагментом; при отмене ctx канал просто закрывается.
+func streamSSE(ctx context.Context, body io.ReadCloser, handle sseEventHandler) <-chan StreamChunk {
+	chunks := make(chan StreamChunk)
+
+	go func() {
+		defer close(chunks)
+		defer body.Close()
+
+		fail := func(err error) {
+			if ctx.Err() != nil {
+				return
+			}
+			select {
+			case chunks <- StreamChunk{Err: err}:
+			case <-ctx.Done():
+			}
+		}
+
+		scanner := bufio.NewScanner(body)
+		scanner.Buffer(make([]byte, 0, 64*1024), maxSSELineSize)
+
+		event := ""
+		var data []string
+		for scanner.Scan() {
+			line := scanner.Text()
+
+			// Пустая строка завершает событие
+			if line == "" {
+				if len(data) == 0 {
+					event = ""
+					continue
+				}
+
+				chunk, done, err := handle(event, strings.Join(data, "\n"))
+				event = ""
+				data = data[:0]
+
+				if chunk != "" {
+					select {
+					case chunks <- StreamChunk{Text: chunk}:
+					case <-ctx.Done():
+						return
+					}
+				}
+				if err != nil {
+					fail(err)
+					return
+				}
+				if done {
+					return
+				}
+				continue
+			}
+
+			switch {
+			case strings.HasPrefix(line, ":"):
+				// Комментарий, используется серверами как keep-alive
+			case strings.HasPrefix(line, "event:"):
+				event = strings.TrimSpace(strings.TrimPrefix(line, "event:"))
+			case strings.HasPrefix(line, "data:"):
+				data = append(data, strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " "))
+			}
+		}
+
+		if err := scanner.Err(); err != nil {
+			fail(fmt.Errorf("ошибка чтения потока: %w", err))
+			return
+		}
+		// Поток закончился без признака конца ответа: соединение оборвалось
+		fail(fmt.Errorf("поток оборвался до конца ответа: %w", io.ErrUnexpectedEOF))
+	}()
+
+	return chunks
+}
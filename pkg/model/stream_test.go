@@ -0,0 +1,210 @@
+package model
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/grik-ai/ricochet-task/pkg/chain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// collectStream читает все фрагменты из канала и ошибку, прервавшую поток
+func collectStream(chunks <-chan StreamChunk) ([]string, error) {
+	var result []string
+	var err error
+	for chunk := range chunks {
+		if chunk.Err != nil {
+			err = chunk.Err
+			continue
+		}
+		result = append(result, chunk.Text)
+	}
+	return result, err
+}
+
+// collectChunks читает все фрагменты из канала потока, завершившегося без ошибки
+func collectChunks(t *testing.T, chunks <-chan StreamChunk) []string {
+	result, err := collectStream(chunks)
+	require.NoError(t, err)
+	return result
+}
+
+func TestOpenAIExecuteStream(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request OpenAIRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+		assert.True(t, request.Stream)
+		assert.Equal(t, "Bearer test-key", r.Header.Get("Authorization"))
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, token := range []string{"При", "вет", ", мир"} {
+			fmt.Fprintf(w, "data: {\"choices\":[{\"index\":0,\"delta\":{\"content\":%q}}]}\n\n", token)
+		}
+		fmt.Fprint(w, ": keep-alive\n\n")
+		fmt.Fprint(w, "data: [DONE]\n\n")
+		fmt.Fprint(w, "data: {\"choices\":[{\"index\":0,\"delta\":{\"content\":\"после DONE\"}}]}\n\n")
+	}))
+	defer server.Close()
+
+	provider := NewOpenAIProvider("test-key", server.URL)
+	chunks, err := provider.ExecuteStream(context.Background(),
+		chain.Model{Name: chain.ModelNameGPT4}, "Скажи привет", nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"При", "вет", ", мир"}, collectChunks(t, chunks))
+}
+
+func TestOpenAIExecuteStreamAPIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		fmt.Fprint(w, `{"error":{"message":"invalid api key"}}`)
+	}))
+	defer server.Close()
+
+	provider := NewOpenAIProvider("bad-key", server.URL)
+	_, err := provider.ExecuteStream(context.Background(), chain.Model{Name: chain.ModelNameGPT4}, "test", nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid api key")
+}
+
+func TestAnthropicExecuteStream(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request AnthropicRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+		assert.True(t, request.Stream)
+		assert.Equal(t, "claude-3-haiku-20240307", request.Model)
+		assert.Equal(t, "Будь краток", request.System)
+		assert.Equal(t, "test-key", r.Header.Get("x-api-key"))
+		assert.Equal(t, anthropicAPIVersion, r.Header.Get("anthropic-version"))
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "event: message_start\ndata: {\"type\":\"message_start\"}\n\n")
+		fmt.Fprint(w, "event: ping\ndata: {\"type\":\"ping\"}\n\n")
+		for _, token := range []string{"Hello", ", ", "world"} {
+			fmt.Fprintf(w, "event: content_block_delta\ndata: {\"type\":\"content_block_delta\",\"index\":0,\"delta\":{\"type\":\"text_delta\",\"text\":%q}}\n\n", token)
+		}
+		fmt.Fprint(w, "event: message_stop\ndata: {\"type\":\"message_stop\"}\n\n")
+	}))
+	defer server.Close()
+
+	provider := NewAnthropicProvider("test-key", server.URL)
+	chunks, err := provider.ExecuteStream(context.Background(),
+		chain.Model{Name: chain.ModelNameClaude3Haiku}, "Say hello",
+		map[string]interface{}{"system_prompt": "Будь краток"})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"Hello", ", ", "world"}, collectChunks(t, chunks))
+}
+
+func TestExecuteStreamMidStreamError(t *testing.T) {
+	t.Run("Anthropic error event", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/event-stream")
+			fmt.Fprint(w, "event: content_block_delta\ndata: {\"type\":\"content_block_delta\",\"delta\":{\"type\":\"text_delta\",\"text\":\"Half\"}}\n\n")
+			fmt.Fprint(w, "event: error\ndata: {\"type\":\"error\",\"error\":{\"type\":\"overloaded_error\",\"message\":\"Overloaded\"}}\n\n")
+		}))
+		defer server.Close()
+
+		provider := NewAnthropicProvider("test-key", server.URL)
+		chunks, err := provider.ExecuteStream(context.Background(), chain.Model{Name: chain.ModelNameClaude3Haiku}, "test", nil)
+		require.NoError(t, err)
+
+		text, err := collectStream(chunks)
+		assert.Equal(t, []string{"Half"}, text)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "Overloaded")
+		assert.True(t, IsRetryable(err), "an overloaded model can be retried")
+	})
+
+	t.Run("OpenAI error event", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/event-stream")
+			fmt.Fprint(w, "data: {\"choices\":[{\"delta\":{\"content\":\"Half\"}}]}\n\n")
+			fmt.Fprint(w, "data: {\"error\":{\"message\":\"The server had an error\",\"type\":\"server_error\"}}\n\n")
+		}))
+		defer server.Close()
+
+		provider := NewOpenAIProvider("test-key", server.URL)
+		chunks, err := provider.ExecuteStream(context.Background(), chain.Model{Name: chain.ModelNameGPT4}, "test", nil)
+		require.NoError(t, err)
+
+		text, err := collectStream(chunks)
+		assert.Equal(t, []string{"Half"}, text)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "The server had an error")
+	})
+
+	t.Run("Dropped connection", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "text/event-stream")
+			fmt.Fprint(w, "event: content_block_delta\ndata: {\"type\":\"content_block_delta\",\"delta\":{\"type\":\"text_delta\",\"text\":\"Half\"}}\n\n")
+			// Ответ обрывается без message_stop
+		}))
+		defer server.Close()
+
+		provider := NewAnthropicProvider("test-key", server.URL)
+		chunks, err := provider.ExecuteStream(context.Background(), chain.Model{Name: chain.ModelNameClaude3Haiku}, "test", nil)
+		require.NoError(t, err)
+
+		text, err := collectStream(chunks)
+		assert.Equal(t, []string{"Half"}, text)
+		assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+		assert.True(t, IsRetryable(err))
+	})
+}
+
+func TestExecuteStreamCancel(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "data: {\"choices\":[{\"delta\":{\"content\":\"первый\"}}]}\n\n")
+		w.(http.Flusher).Flush()
+		select {
+		case <-r.Context().Done():
+		case <-release:
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	provider := NewOpenAIProvider("test-key", server.URL)
+	chunks, err := provider.ExecuteStream(ctx, chain.Model{Name: chain.ModelNameGPT4}, "test", nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, "первый", (<-chunks).Text)
+	cancel()
+
+	// После отмены канал закрывается, не дожидаясь конца ответа
+	assert.Empty(t, collectChunks(t, chunks))
+}
+
+// staticProvider провайдер без потоковой передачи
+type staticProvider struct {
+	*BaseProvider
+	output string
+}
+
+func (p *staticProvider) Execute(ctx context.Context, model chain.Model, prompt string, options map[string]interface{}) (string, error) {
+	return p.output + strings.ToUpper(prompt), nil
+}
+
+func (p *staticProvider) ExecuteStream(ctx context.Context, model chain.Model, prompt string, options map[string]interface{}) (<-chan StreamChunk, error) {
+	return ExecuteAsStream(ctx, p, model, prompt, options)
+}
+
+func TestExecuteAsStream(t *testing.T) {
+	provider := &staticProvider{BaseProvider: NewBaseProvider(chain.ModelTypeLlama, "", ""), output: "ответ: "}
+
+	chunks, err := provider.ExecuteStream(context.Background(), chain.Model{}, "ok", nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"ответ: OK"}, collectChunks(t, chunks))
+}
@@ -88,6 +88,8 @@ const (
 	RoleCritic     = "critic"
 	RoleRefiner    = "refiner"
 	RoleCreator    = "creator"
+	RoleOrganizer  = "organizer"
+	RoleEvaluator  = "evaluator"
 )
 
 // New создает новый менеджер моделей
@@ -575,7 +577,27 @@ func DefaultRoles() []ModelRole {
 			DisplayName: "Модель-генератор",
 			Description: "Создает новый контент на основе входных данных",
 		},
+		{
+			ID:          RoleOrganizer,
+			DisplayName: "Модель-организатор",
+			Description: "Структурирует данные в категории и иерархии",
+		},
+		{
+			ID:          RoleEvaluator,
+			DisplayName: "Модель-оценщик",
+			Description: "Оценивает достоверность и обоснованность материала",
+		},
+	}
+}
+
+// IsKnownRole проверяет, что роль входит в список ролей по умолчанию
+func IsKnownRole(roleID string) bool {
+	for _, role := range DefaultRoles() {
+		if role.ID == roleID {
+			return true
+		}
 	}
+	return false
 }
 
 // GetRoleDescription возвращает описание для указанной роли
@@ -666,3 +688,91 @@ func (m *ModelsManager) GetRoles() []ModelRole {
 func (m *ModelsManager) GetAvailableRoles() []ModelRole {
 	return m.GetRoles()
 }
+
+// FindModel ищет модель провайдера в реестре менеджера, а затем в реестре
+// моделей цепочек. Пустой provider означает поиск среди всех провайдеров.
+func (m *ModelsManager) FindModel(provider, modelID string) (ModelOption, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	var matches []ModelOption
+	for providerName, models := range m.registry.Models {
+		if provider != "" && providerName != provider {
+			continue
+		}
+		for _, option := range models {
+			if option.ModelID == modelID {
+				matches = append(matches, option)
+			}
+		}
+	}
+
+	if len(matches) == 0 {
+		for _, config := range chain.NewModelRegistry().Models {
+			if string(config.Name) != modelID || (provider != "" && config.Provider != provider) {
+				continue
+			}
+			matches = append(matches, ModelOption{
+				Provider:    config.Provider,
+				ModelID:     string(config.Name),
+				DisplayName: string(config.Name),
+				MaxTokens:   config.MaxTokens,
+				ContextSize: config.Context,
+			})
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		if provider != "" {
+			return ModelOption{}, fmt.Errorf("model %s not found for provider %s", modelID, provider)
+		}
+		return ModelOption{}, fmt.Errorf("model %s not found", modelID)
+	case 1:
+		return matches[0], nil
+	default:
+		return ModelOption{}, fmt.Errorf("model %s is offered by several providers, specify the provider", modelID)
+	}
+}
+
+// SetRoleModel назначает модель роли и сохраняет конфигурацию.
+// Параметры роли (температура и т.п.) сохраняются.
+func (m *ModelsManager) SetRoleModel(roleID, provider, modelID string) (ModelRole, error) {
+	if !IsKnownRole(roleID) {
+		return ModelRole{}, fmt.Errorf("unknown role: %s", roleID)
+	}
+
+	option, err := m.FindModel(provider, modelID)
+	if err != nil {
+		return ModelRole{}, err
+	}
+
+	update := func(config ModelConfig) ModelConfig {
+		config.Provider = option.Provider
+		config.ModelID = option.ModelID
+		config.DisplayName = option.DisplayName
+		return config
+	}
+
+	m.mutex.Lock()
+	switch roleID {
+	case RoleMain:
+		m.config.Main = update(m.config.Main)
+	case RoleResearch:
+		m.config.Research = update(m.config.Research)
+	case RoleFallback:
+		m.config.Fallback = update(m.config.Fallback)
+	default:
+		if m.config.ChainRoles == nil {
+			m.config.ChainRoles = make(map[string]ModelConfig)
+		}
+		m.config.ChainRoles[roleID] = update(m.config.ChainRoles[roleID])
+	}
+	m.mutex.Unlock()
+
+	if err := m.SaveConfig(); err != nil {
+		return ModelRole{}, fmt.Errorf("failed to save models config: %w", err)
+	}
+
+	return m.GetRoleByID(roleID), nil
+}
@@ -0,0 +1,55 @@
+package models_manager
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetRoleModel(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "models.json")
+	manager, err := New(configPath)
+	require.NoError(t, err)
+
+	before := manager.GetRoleByID(RoleAnalyzer)
+
+	role, err := manager.SetRoleModel(RoleAnalyzer, "openai", "gpt-4")
+	require.NoError(t, err)
+	assert.Equal(t, "openai", role.Provider)
+	assert.Equal(t, "gpt-4", role.ModelID)
+	// Параметры роли сохраняются при смене модели
+	assert.Equal(t, before.Parameters, role.Parameters)
+
+	// Назначение переживает перезагрузку и используется автовыбором
+	reloaded, err := New(configPath)
+	require.NoError(t, err)
+	assert.Equal(t, "gpt-4", reloaded.GetModelForRole(RoleAnalyzer).ModelID)
+
+	_, err = manager.SetRoleModel(RoleMain, "anthropic", "claude-3-haiku-20240307")
+	require.NoError(t, err)
+	assert.Equal(t, "claude-3-haiku-20240307", manager.GetRoleByID(RoleMain).ModelID)
+
+	_, err = manager.SetRoleModel("unknown", "openai", "gpt-4")
+	assert.Error(t, err)
+	_, err = manager.SetRoleModel(RoleAnalyzer, "openai", "claude-3-haiku-20240307")
+	assert.Error(t, err)
+}
+
+func TestFindModel(t *testing.T) {
+	manager, err := New(filepath.Join(t.TempDir(), "models.json"))
+	require.NoError(t, err)
+
+	option, err := manager.FindModel("", "deepseek-coder")
+	require.NoError(t, err)
+	assert.Equal(t, "deepseek", option.Provider)
+
+	// Модели цепочек, которых нет в реестре менеджера, тоже доступны
+	option, err = manager.FindModel("openai", "gpt-4")
+	require.NoError(t, err)
+	assert.Equal(t, "openai", option.Provider)
+
+	_, err = manager.FindModel("openai", "no-such-model")
+	assert.Error(t, err)
+}
@@ -0,0 +1,208 @@
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/grik-ai/ricochet-task/pkg/chain"
+)
+
+// Стратегии объединения результатов сегментов
+const (
+	// AggregationConcatenate склеивает результаты сегментов по порядку
+	AggregationConcatenate = "concatenate"
+	// AggregationSummarize прогоняет результаты сегментов через модель еще раз
+	// (map-reduce): получается единое резюме вместо набора частичных
+	AggregationSummarize = "summarize"
+	// AggregationMerge объединяет результаты без повторов: абзацы, встречающиеся
+	// в нескольких сегментах, попадают в результат один раз и идут первыми
+	AggregationMerge = "merge"
+)
+
+// maxReduceDepth ограничивает число уровней свертки в AggregationSummarize
+const maxReduceDepth = 4
+
+// reducePrompt системный промпт для финальной свертки результатов сегментов
+const reducePrompt = "Вы получаете результаты обработки последовательных частей одного документа. " +
+	"Объедините их в единый связный результат: сохраните все ключевые факты и выводы, " +
+	"устраните повторы и противоречия, не упоминайте деление на части."
+
+// isKnownAggregation проверяет, что стратегия объединения поддерживается
+func isKnownAggregation(strategy string) bool {
+	switch strategy {
+	case AggregationConcatenate, AggregationSummarize, AggregationMerge:
+		return true
+	}
+	return false
+}
+
+// aggregateResults объединяет результаты сегментов по стратегии из options.
+// Для AggregationSummarize свертка выполняется моделью model.
+func (o *DefaultOrchestrator) aggregateResults(
+	ctx context.Context,
+	model chain.Model,
+	results []string,
+	runMeta *RunMetadata,
+	options ProcessingOptions,
+) (string, error) {
+	switch options.AggregationStrategy {
+	case AggregationSummarize:
+		return o.reduceResults(ctx, model, results, runMeta, options, 0)
+	case AggregationMerge:
+		return mergeUniqueResults(results), nil
+	default:
+		return mergeSegmentResults(results), nil
+	}
+}
+
+// reduceResults сворачивает результаты моделью. Если все результаты не помещаются
+// в один запрос, они сворачиваются группами, пока не останется один.
+func (o *DefaultOrchestrator) reduceResults(
+	ctx context.Context,
+	model chain.Model,
+	results []string,
+	runMeta *RunMetadata,
+	options ProcessingOptions,
+	depth int,
+) (string, error) {
+	parts := nonEmptyResults(results)
+	if len(parts) == 0 {
+		return "", nil
+	}
+	if len(parts) == 1 {
+		return parts[0], nil
+	}
+	if o.apiClient == nil || o.keyStore == nil {
+		return "", errors.New("summarize aggregation requires a configured model client")
+	}
+
+	reduceModel := model
+	reduceModel.Prompt = reducePrompt
+
+	// На последнем уровне сворачиваем все разом, чтобы не уйти в бесконечную рекурсию
+	batches := [][]string{parts}
+	if depth < maxReduceDepth {
+		batches = batchResults(parts, options.MaxTokensPerChunk)
+	}
+
+	reduced := make([]string, 0, len(batches))
+	for i, batch := range batches {
+		if len(batch) == 1 {
+			reduced = append(reduced, batch[0])
+			continue
+		}
+
+		result, err := o.processSingleChunk(ctx, reduceModel, formatReduceInput(batch), runMeta)
+		if err != nil {
+			return "", fmt.Errorf("reduce step %d of %d failed: %w", i+1, len(batches), err)
+		}
+		reduced = append(reduced, result)
+	}
+
+	if len(reduced) == 1 {
+		return strings.TrimSpace(reduced[0]), nil
+	}
+	// Если группировка ничего не сократила, следующий уровень свернет все разом
+	if len(reduced) == len(parts) {
+		depth = maxReduceDepth - 1
+	}
+	return o.reduceResults(ctx, model, reduced, runMeta, options, depth+1)
+}
+
+// batchResults группирует результаты так, чтобы каждая группа укладывалась в maxTokens
+func batchResults(parts []string, maxTokens int) [][]string {
+	var batches [][]string
+	var current []string
+	currentTokens := 0
+
+	for _, part := range parts {
+		tokens := estimateTokenCount(part)
+		if len(current) > 0 && currentTokens+tokens > maxTokens {
+			batches = append(batches, current)
+			current = nil
+			currentTokens = 0
+		}
+		current = append(current, part)
+		currentTokens += tokens
+	}
+	if len(current) > 0 {
+		batches = append(batches, current)
+	}
+
+	return batches
+}
+
+// formatReduceInput готовит результаты сегментов для свертки моделью
+func formatReduceInput(parts []string) string {
+	var b strings.Builder
+	for i, part := range parts {
+		fmt.Fprintf(&b, "=== Часть %d из %d ===\n%s\n\n", i+1, len(parts), part)
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// mergeUniqueResults объединяет абзацы результатов без повторов. Абзацы,
+// которые вернули несколько сегментов, идут первыми; остальные - в исходном порядке.
+func mergeUniqueResults(results []string) string {
+	type paragraph struct {
+		text  string
+		votes int
+	}
+
+	byKey := make(map[string]*paragraph)
+	var paragraphs []*paragraph
+	for _, result := range results {
+		seen := make(map[string]bool)
+		for _, text := range strings.Split(result, "\n\n") {
+			text = strings.TrimSpace(text)
+			if text == "" {
+				continue
+			}
+
+			key := strings.ToLower(strings.Join(strings.Fields(text), " "))
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+
+			if p, exists := byKey[key]; exists {
+				p.votes++
+				continue
+			}
+			p := &paragraph{text: text, votes: 1}
+			byKey[key] = p
+			paragraphs = append(paragraphs, p)
+		}
+	}
+
+	sort.SliceStable(paragraphs, func(i, j int) bool {
+		return paragraphs[i].votes > paragraphs[j].votes
+	})
+
+	parts := make([]string, len(paragraphs))
+	for i, p := range paragraphs {
+		parts[i] = p.text
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+// mergeSegmentResults объединяет результаты сегментов в порядке следования.
+// Перекрытие передается модели как контекст и в результаты не попадает,
+// поэтому достаточно разделить части пустой строкой.
+func mergeSegmentResults(results []string) string {
+	return strings.Join(nonEmptyResults(results), "\n\n")
+}
+
+// nonEmptyResults возвращает непустые результаты без крайних пробелов
+func nonEmptyResults(results []string) []string {
+	parts := make([]string, 0, len(results))
+	for _, result := range results {
+		if trimmed := strings.TrimSpace(result); trimmed != "" {
+			parts = append(parts, trimmed)
+		}
+	}
+	return parts
+}
@@ -0,0 +1,126 @@
+package orchestrator
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grik-ai/ricochet-task/pkg/chain"
+	"github.com/grik-ai/ricochet-task/pkg/task"
+)
+
+func TestAggregateResults(t *testing.T) {
+	o := &DefaultOrchestrator{}
+	results := []string{
+		"Компания выросла на 10%.\n\nОсновной риск - валютный.",
+		"  ",
+		"Основной риск - валютный.\n\nНовый завод запущен в марте.",
+	}
+
+	t.Run("concatenate keeps segment order", func(t *testing.T) {
+		options := DefaultProcessingOptions()
+		text, err := o.aggregateResults(context.Background(), chain.Model{}, results, &RunMetadata{}, options)
+		require.NoError(t, err)
+		assert.Equal(t, strings.TrimSpace(results[0])+"\n\n"+strings.TrimSpace(results[2]), text)
+	})
+
+	t.Run("merge drops repeats and ranks shared paragraphs first", func(t *testing.T) {
+		options := DefaultProcessingOptions()
+		options.AggregationStrategy = AggregationMerge
+		text, err := o.aggregateResults(context.Background(), chain.Model{}, results, &RunMetadata{}, options)
+		require.NoError(t, err)
+		assert.Equal(t, "Основной риск - валютный.\n\nКомпания выросла на 10%.\n\nНовый завод запущен в марте.", text)
+	})
+
+	t.Run("summarize of a single result needs no model", func(t *testing.T) {
+		options := DefaultProcessingOptions()
+		options.AggregationStrategy = AggregationSummarize
+		text, err := o.aggregateResults(context.Background(), chain.Model{}, []string{"", " только одна часть "}, &RunMetadata{}, options)
+		require.NoError(t, err)
+		assert.Equal(t, "только одна часть", text)
+	})
+
+	t.Run("summarize without model client fails", func(t *testing.T) {
+		options := DefaultProcessingOptions()
+		options.AggregationStrategy = AggregationSummarize
+		_, err := o.aggregateResults(context.Background(), chain.Model{}, results, &RunMetadata{}, options)
+		assert.Error(t, err)
+	})
+
+	t.Run("unknown strategy is rejected by validation", func(t *testing.T) {
+		options := DefaultProcessingOptions()
+		options.AggregationStrategy = "average"
+		assert.Error(t, options.Validate())
+	})
+}
+
+func TestBatchResults(t *testing.T) {
+	part := strings.Repeat("a", 400) // ~100 токенов
+	batches := batchResults([]string{part, part, part, part, part}, 250)
+	require.Len(t, batches, 3)
+	assert.Len(t, batches[0], 2)
+	assert.Len(t, batches[1], 2)
+	assert.Len(t, batches[2], 1)
+
+	// Часть больше лимита попадает в отдельную группу
+	batches = batchResults([]string{strings.Repeat("b", 2000), part}, 250)
+	require.Len(t, batches, 2)
+}
+
+func TestGetRunResultsAggregatesSegments(t *testing.T) {
+	taskStore, err := task.NewFileTaskStore(t.TempDir())
+	require.NoError(t, err)
+
+	now := time.Now()
+	model := &chain.Model{ID: "model-1", Name: chain.ModelName("gpt-3.5-turbo")}
+	for i, output := range []string{"второй", "первый"} {
+		segment := 1 - i
+		completedAt := now.Add(time.Duration(i) * time.Second)
+		require.NoError(t, taskStore.Save(task.Task{
+			ID:          "segment-" + output,
+			Type:        task.TaskTypeModelExecution,
+			Status:      task.StatusCompleted,
+			Model:       model,
+			RunID:       "run-1",
+			CompletedAt: &completedAt,
+			Input: task.TaskInput{
+				Type:     "text",
+				Segment:  segment,
+				Metadata: map[string]interface{}{"segment_count": 2},
+			},
+			Output: task.TaskOutput{Type: "text", Destination: output},
+		}))
+	}
+
+	o := NewOrchestrator(nil, nil, nil, nil, task.NewTaskManager(taskStore), nil, nil)
+	o.runs["run-1"] = &RunMetadata{
+		ID:            "run-1",
+		Status:        StatusCompleted,
+		ExtraMetadata: map[string]interface{}{"aggregation_strategy": AggregationConcatenate},
+	}
+
+	// Без сохраненного результата сегменты заново не объединяются
+	_, err = o.GetRunResults("run-1")
+	assert.Error(t, err)
+
+	require.NoError(t, o.saveRunResult(context.Background(), "run-1"))
+	output, err := o.GetRunResults("run-1")
+	require.NoError(t, err)
+	assert.Equal(t, "первый\n\nвторой", output.Text, "all segments in segment order, not just the last one")
+	assert.Equal(t, 2, output.Metadata["segments"])
+
+	// Сохраненная свертка читается без клиента модели
+	o.runs["run-2"] = &RunMetadata{
+		ID:            "run-2",
+		Status:        StatusCompleted,
+		ExtraMetadata: map[string]interface{}{"aggregation_strategy": AggregationSummarize},
+		Result:        &TaskOutput{Text: "итог", Metadata: map[string]interface{}{"aggregation_strategy": AggregationSummarize}},
+	}
+	output, err = o.GetRunResults("run-2")
+	require.NoError(t, err)
+	assert.Equal(t, "итог", output.Text)
+}
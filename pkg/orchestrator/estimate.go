@@ -0,0 +1,184 @@
+package orchestrator
+
+import (
+	"fmt"
+
+	"github.com/grik-ai/ricochet-task/pkg/chain"
+	"github.com/grik-ai/ricochet-task/pkg/model"
+	"github.com/grik-ai/ricochet-task/pkg/segmentation"
+)
+
+// defaultEstimateOutputTokens лимит ответа, если он не задан ни в модели, ни в реестре
+const defaultEstimateOutputTokens = 1000
+
+// minOutputRatio доля входа, которую модель возвращает в нижней оценке.
+// Верхняя оценка считает, что каждый запрос исчерпывает лимит ответа.
+const minOutputRatio = 0.25
+
+// StepEstimate оценка одного шага цепочки
+type StepEstimate struct {
+	Model           chain.ModelName `json:"model"`
+	Segments        int             `json:"segments"`          // запросов к модели без учета свертки
+	InputTokens     int             `json:"input_tokens"`      // включая системный промпт и перекрытие
+	MaxInputTokens  int             `json:"max_input_tokens"`  // при максимальном выходе предыдущих шагов
+	MinOutputTokens int             `json:"min_output_tokens"`
+	MaxOutputTokens int             `json:"max_output_tokens"`
+	MinCost         float64         `json:"min_cost"`
+	MaxCost         float64         `json:"max_cost"`
+	Priced          bool            `json:"priced"` // false - цены модели неизвестны, стоимость не учтена
+}
+
+// CostEstimate оценка стоимости запуска цепочки в долларах
+type CostEstimate struct {
+	InputTokens int               `json:"input_tokens"`
+	Steps       []StepEstimate    `json:"steps"`
+	MinCost     float64           `json:"min_cost"`
+	MaxCost     float64           `json:"max_cost"`
+	Unpriced    []chain.ModelName `json:"unpriced,omitempty"`
+}
+
+// EstimateChainCost оценивает стоимость запуска цепочки на входе input.
+//
+// Шаги проходятся в порядке выполнения: выход шага становится входом следующего.
+// Так как длина ответа заранее неизвестна, для каждого шага считается диапазон:
+// нижняя граница предполагает ответ в четверть входа, верхняя - ответ длиной
+// в лимит max_tokens на каждый сегмент. Сегментация, перекрытие и свертка
+// результатов (summarize) учитываются так же, как при реальном запуске.
+func EstimateChainCost(c chain.Chain, input string, options ProcessingOptions) (*CostEstimate, error) {
+	if err := options.Validate(); err != nil {
+		return nil, err
+	}
+	if len(c.Models) == 0 {
+		return nil, fmt.Errorf("chain %s has no models", c.ID)
+	}
+
+	estimator := model.NewTokenEstimator()
+	registry := chain.NewModelRegistry()
+
+	// Первый шаг сегментируем по-настоящему, чтобы учесть фактические границы и перекрытие
+	inputTokens := estimator.EstimateTokens(input, "")
+	firstSegments := []int{inputTokens}
+	if estimateTokenCount(input) > options.MaxTokensPerChunk {
+		segments, err := segmentation.SegmentWithInfo(input, options.SegmentationOptions())
+		if err != nil {
+			return nil, fmt.Errorf("failed to segment input: %w", err)
+		}
+		firstSegments = make([]int, len(segments))
+		for i, segment := range segments {
+			firstSegments[i] = estimator.EstimateTokens(segment.InputText(), "")
+		}
+	}
+
+	estimate := &CostEstimate{InputTokens: inputTokens}
+	minText, maxText := inputTokens, inputTokens
+	for i, m := range c.Models {
+		minSegments, maxSegments := firstSegments, firstSegments
+		if i > 0 {
+			minSegments = estimateSegmentTokens(minText, options)
+			maxSegments = estimateSegmentTokens(maxText, options)
+		}
+
+		step := estimateStep(m, minSegments, maxSegments, options, estimator, registry)
+		estimate.Steps = append(estimate.Steps, step)
+		estimate.MinCost += step.MinCost
+		estimate.MaxCost += step.MaxCost
+		if !step.Priced {
+			estimate.Unpriced = append(estimate.Unpriced, m.Name)
+		}
+
+		minText, maxText = step.MinOutputTokens, step.MaxOutputTokens
+	}
+
+	return estimate, nil
+}
+
+// estimateStep оценивает токены и стоимость шага для нижней и верхней оценки входа
+func estimateStep(
+	m chain.Model,
+	minSegments, maxSegments []int,
+	options ProcessingOptions,
+	estimator *model.TokenEstimator,
+	registry *chain.ModelRegistry,
+) StepEstimate {
+	systemPrompt := m.Prompt
+	if systemPrompt == "" {
+		systemPrompt = getDefaultPromptForRole(m.Role)
+	}
+	promptTokens := estimator.EstimateTokens(systemPrompt, "")
+
+	outputLimit := m.MaxTokens
+	if outputLimit <= 0 {
+		if config, err := registry.GetModelByName(m.Name); err == nil && config.MaxTokens > 0 {
+			outputLimit = config.MaxTokens / 2
+		} else {
+			outputLimit = defaultEstimateOutputTokens
+		}
+	}
+
+	minIn, minOut := estimateCalls(minSegments, promptTokens, outputLimit, false, options)
+	maxIn, maxOut := estimateCalls(maxSegments, promptTokens, outputLimit, true, options)
+
+	step := StepEstimate{
+		Model:           m.Name,
+		Segments:        len(maxSegments),
+		InputTokens:     minIn,
+		MaxInputTokens:  maxIn,
+		MinOutputTokens: minOut,
+		MaxOutputTokens: maxOut,
+	}
+
+	if pricing, ok := model.GetModelPricing(m.Name); ok {
+		step.Priced = true
+		step.MinCost = pricing.Cost(minIn, minOut)
+		step.MaxCost = pricing.Cost(maxIn, maxOut)
+	}
+
+	return step
+}
+
+// estimateCalls суммирует входные и выходные токены запросов по сегментам,
+// включая свертку результатов при AggregationSummarize
+func estimateCalls(segments []int, promptTokens, outputLimit int, upper bool, options ProcessingOptions) (int, int) {
+	output := func(input int) int {
+		if upper {
+			return outputLimit
+		}
+		return min(outputLimit, int(float64(input)*minOutputRatio))
+	}
+
+	inputTokens, outputTokens := 0, 0
+	for _, tokens := range segments {
+		inputTokens += promptTokens + tokens
+		outputTokens += output(tokens)
+	}
+
+	if len(segments) > 1 && options.AggregationStrategy == AggregationSummarize {
+		reduceInput := estimateTokenCount(reducePrompt) + outputTokens
+		inputTokens += reduceInput
+		// Итог шага - результат свертки, а не сумма ответов сегментов
+		outputTokens = output(reduceInput)
+	}
+
+	return inputTokens, outputTokens
+}
+
+// estimateSegmentTokens разбивает вход шага по размеру так же, как сегментация:
+// сегменты до MaxTokensPerChunk токенов, каждый кроме первого с перекрытием
+func estimateSegmentTokens(tokens int, options ProcessingOptions) []int {
+	if tokens <= options.MaxTokensPerChunk {
+		return []int{tokens}
+	}
+
+	count := (tokens + options.MaxTokensPerChunk - 1) / options.MaxTokensPerChunk
+	segments := make([]int, count)
+	remaining := tokens
+	for i := range segments {
+		segments[i] = min(remaining, options.MaxTokensPerChunk)
+		remaining -= segments[i]
+		if i > 0 {
+			segments[i] += options.SegmentOverlap
+		}
+	}
+
+	return segments
+}
@@ -0,0 +1,82 @@
+package orchestrator
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/grik-ai/ricochet-task/pkg/chain"
+	"github.com/grik-ai/ricochet-task/pkg/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEstimateChainCost(t *testing.T) {
+	c := chain.Chain{
+		ID: "chain-1",
+		Models: []chain.Model{
+			{Name: chain.ModelNameGPT4, Type: chain.ModelTypeOpenAI, Role: chain.ModelRoleAnalyzer, MaxTokens: 500},
+			{Name: chain.ModelNameClaude3Haiku, Type: chain.ModelTypeClaude, Role: chain.ModelRoleSummarizer, MaxTokens: 200},
+		},
+	}
+
+	t.Run("single segment", func(t *testing.T) {
+		estimate, err := EstimateChainCost(c, "Short input text for the chain.", DefaultProcessingOptions())
+		require.NoError(t, err)
+		require.Len(t, estimate.Steps, 2)
+
+		first := estimate.Steps[0]
+		assert.Equal(t, 1, first.Segments)
+		assert.True(t, first.Priced)
+		assert.Equal(t, 500, first.MaxOutputTokens)
+		assert.Less(t, first.MinOutputTokens, first.MaxOutputTokens)
+
+		// Выход первой модели становится входом второй
+		second := estimate.Steps[1]
+		assert.Greater(t, second.MaxInputTokens, second.InputTokens)
+
+		pricing, _ := model.GetModelPricing(chain.ModelNameGPT4)
+		assert.InDelta(t, pricing.Cost(first.MaxInputTokens, first.MaxOutputTokens), first.MaxCost, 1e-9)
+		assert.InDelta(t, first.MaxCost+second.MaxCost, estimate.MaxCost, 1e-9)
+		assert.LessOrEqual(t, estimate.MinCost, estimate.MaxCost)
+		assert.Empty(t, estimate.Unpriced)
+	})
+
+	t.Run("segmented input", func(t *testing.T) {
+		input := strings.Repeat("This sentence is part of a long document. ", 800)
+		options := DefaultProcessingOptions()
+		options.MaxTokensPerChunk = 1000
+
+		plain, err := EstimateChainCost(c, input, options)
+		require.NoError(t, err)
+		assert.Greater(t, plain.Steps[0].Segments, 1)
+		// Каждый сегмент может исчерпать лимит ответа
+		assert.Equal(t, plain.Steps[0].Segments*500, plain.Steps[0].MaxOutputTokens)
+
+		options.SegmentOverlap = 200
+		overlapped, err := EstimateChainCost(c, input, options)
+		require.NoError(t, err)
+		assert.Greater(t, overlapped.Steps[0].InputTokens, plain.Steps[0].InputTokens)
+
+		options.SegmentOverlap = 0
+		options.AggregationStrategy = AggregationSummarize
+		summarized, err := EstimateChainCost(c, input, options)
+		require.NoError(t, err)
+		// Свертка добавляет запрос, но сжимает выход шага до одного ответа
+		assert.Greater(t, summarized.Steps[0].MaxInputTokens, plain.Steps[0].MaxInputTokens)
+		assert.Equal(t, 500, summarized.Steps[0].MaxOutputTokens)
+	})
+
+	t.Run("unpriced model", func(t *testing.T) {
+		grok := chain.Chain{ID: "chain-2", Models: []chain.Model{{Name: chain.ModelNameGrok1, Type: chain.ModelTypeGrok}}}
+		estimate, err := EstimateChainCost(grok, "input", DefaultProcessingOptions())
+		require.NoError(t, err)
+		assert.False(t, estimate.Steps[0].Priced)
+		assert.Equal(t, []chain.ModelName{chain.ModelNameGrok1}, estimate.Unpriced)
+		assert.Zero(t, estimate.MaxCost)
+	})
+
+	t.Run("empty chain", func(t *testing.T) {
+		_, err := EstimateChainCost(chain.Chain{ID: "empty"}, "input", DefaultProcessingOptions())
+		assert.Error(t, err)
+	})
+}
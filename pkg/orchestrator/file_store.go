@@ -0,0 +1,236 @@
+package orchestrator
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+// RunStore хранилище метаданных запусков, переживающее перезапуск процесса
+type RunStore interface {
+	// SaveRunMetadata сохраняет или обновляет метаданные запуска
+	SaveRunMetadata(metadata *RunMetadata) error
+
+	// GetRunMetadata возвращает метаданные запуска по ID
+	GetRunMetadata(runID string) (*RunMetadata, error)
+
+	// ListRunsForChain возвращает запуски цепочки, новые первыми
+	ListRunsForChain(chainID string, limit int) ([]*RunMetadata, error)
+
+	// ListAllRuns возвращает все запуски, новые первыми
+	ListAllRuns(limit int) ([]*RunMetadata, error)
+
+	// DeleteRunMetadata удаляет метаданные запуска
+	DeleteRunMetadata(runID string) error
+
+	// GetRunStatistics возвращает статистику выполнения для цепочки
+	GetRunStatistics(chainID string) (*RunStatistics, error)
+}
+
+// FileRunStore хранилище метаданных запусков в JSON-файле. Файл общий для
+// CLI и MCP-сервера, поэтому изменения делаются под файловой блокировкой
+// runs.json.lock, а запись атомарна: новый файл переименовывается поверх старого.
+type FileRunStore struct {
+	path  string
+	mutex sync.Mutex
+}
+
+// NewFileRunStore создает хранилище запусков в указанной директории
+func NewFileRunStore(configDir string) (*FileRunStore, error) {
+	path := filepath.Join(configDir, "runs.json")
+
+	// Создаем директорию, если она не существует
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create runs directory: %w", err)
+	}
+
+	return &FileRunStore{path: path}, nil
+}
+
+// SaveRunMetadata сохраняет или обновляет метаданные запуска
+func (s *FileRunStore) SaveRunMetadata(metadata *RunMetadata) error {
+	return s.update(func(runs []*RunMetadata) ([]*RunMetadata, error) {
+		saved := *metadata
+		for i, run := range runs {
+			if run.ID == saved.ID {
+				runs[i] = &saved
+				return runs, nil
+			}
+		}
+		return append(runs, &saved), nil
+	})
+}
+
+// GetRunMetadata возвращает метаданные запуска по ID
+func (s *FileRunStore) GetRunMetadata(runID string) (*RunMetadata, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	runs, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, run := range runs {
+		if run.ID == runID {
+			return run, nil
+		}
+	}
+
+	return nil, ErrRunNotFound
+}
+
+// ListRunsForChain возвращает запуски цепочки, новые первыми
+func (s *FileRunStore) ListRunsForChain(chainID string, limit int) ([]*RunMetadata, error) {
+	runs, err := s.ListAllRuns(0)
+	if err != nil {
+		return nil, err
+	}
+
+	var chainRuns []*RunMetadata
+	for _, run := range runs {
+		if run.ChainID == chainID {
+			chainRuns = append(chainRuns, run)
+		}
+	}
+
+	return limitRuns(chainRuns, limit), nil
+}
+
+// ListAllRuns возвращает все запуски, новые первыми
+func (s *FileRunStore) ListAllRuns(limit int) ([]*RunMetadata, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	runs, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+
+	sortRunsByStartTime(runs)
+	return limitRuns(runs, limit), nil
+}
+
+// DeleteRunMetadata удаляет метаданные запуска
+func (s *FileRunStore) DeleteRunMetadata(runID string) error {
+	return s.update(func(runs []*RunMetadata) ([]*RunMetadata, error) {
+		for i, run := range runs {
+			if run.ID == runID {
+				return append(runs[:i], runs[i+1:]...), nil
+			}
+		}
+		return nil, ErrRunNotFound
+	})
+}
+
+// GetRunStatistics возвращает статистику выполнения для цепочки
+func (s *FileRunStore) GetRunStatistics(chainID string) (*RunStatistics, error) {
+	runs, err := s.ListRunsForChain(chainID, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	return computeRunStatistics(runs), nil
+}
+
+// update читает запуски, изменяет их через modify и записывает результат,
+// удерживая блокировку процесса и файловую блокировку, чтобы одновременные
+// изменения из CLI и MCP-сервера не терялись. Если modify возвращает ошибку,
+// файл не меняется.
+func (s *FileRunStore) update(modify func(runs []*RunMetadata) ([]*RunMetadata, error)) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	lock, err := os.OpenFile(s.path+".lock", os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open runs lock: %w", err)
+	}
+	defer lock.Close()
+	if err := lockFile(lock); err != nil {
+		return fmt.Errorf("failed to lock runs: %w", err)
+	}
+	defer unlockFile(lock)
+
+	runs, err := s.load()
+	if err != nil {
+		return err
+	}
+	runs, err = modify(runs)
+	if err != nil {
+		return err
+	}
+	return s.save(runs)
+}
+
+// load читает запуски из файла. Вызывается под блокировкой.
+func (s *FileRunStore) load() ([]*RunMetadata, error) {
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []*RunMetadata{}, nil
+		}
+		return nil, fmt.Errorf("failed to read runs: %w", err)
+	}
+
+	var runs []*RunMetadata
+	if err := json.Unmarshal(data, &runs); err != nil {
+		return nil, fmt.Errorf("failed to parse runs: %w", err)
+	}
+
+	return runs, nil
+}
+
+// save записывает запуски в файл. Вызывается под блокировкой.
+func (s *FileRunStore) save(runs []*RunMetadata) error {
+	data, err := json.MarshalIndent(runs, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal runs: %w", err)
+	}
+
+	// Пишем в отдельный временный файл и переименовываем, чтобы сбой во время
+	// записи не оставил историю запусков поврежденной
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), ".runs-*.json")
+	if err != nil {
+		return fmt.Errorf("failed to write runs: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := tmp.Chmod(0644); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write runs: %w", err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write runs: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write runs: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write runs: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("failed to replace runs file: %w", err)
+	}
+
+	return nil
+}
+
+// sortRunsByStartTime сортирует запуски от новых к старым
+func sortRunsByStartTime(runs []*RunMetadata) {
+	sort.SliceStable(runs, func(i, j int) bool {
+		return runs[i].StartTime.After(runs[j].StartTime)
+	})
+}
+
+// limitRuns обрезает список запусков до limit элементов (0 - без ограничения)
+func limitRuns(runs []*RunMetadata, limit int) []*RunMetadata {
+	if limit > 0 && len(runs) > limit {
+		return runs[:limit]
+	}
+	return runs
+}
@@ -0,0 +1,286 @@
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileRunStore(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewFileRunStore(dir)
+	require.NoError(t, err)
+
+	now := time.Now()
+	require.NoError(t, store.SaveRunMetadata(&RunMetadata{ID: "run-1", ChainID: "chain-a", Status: StatusCompleted, StartTime: now.Add(-2 * time.Hour), EndTime: now.Add(-time.Hour)}))
+	require.NoError(t, store.SaveRunMetadata(&RunMetadata{ID: "run-2", ChainID: "chain-b", Status: StatusRunning, StartTime: now.Add(-time.Hour)}))
+	require.NoError(t, store.SaveRunMetadata(&RunMetadata{ID: "run-3", ChainID: "chain-a", Status: StatusFailed, StartTime: now}))
+
+	// Обновление существующего запуска не создает дубликат
+	require.NoError(t, store.SaveRunMetadata(&RunMetadata{ID: "run-2", ChainID: "chain-b", Status: StatusCompleted, StartTime: now.Add(-time.Hour), EndTime: now}))
+
+	// Новый экземпляр хранилища видит те же данные
+	reopened, err := NewFileRunStore(dir)
+	require.NoError(t, err)
+
+	run, err := reopened.GetRunMetadata("run-2")
+	require.NoError(t, err)
+	assert.Equal(t, StatusCompleted, run.Status)
+
+	_, err = reopened.GetRunMetadata("missing")
+	assert.ErrorIs(t, err, ErrRunNotFound)
+
+	all, err := reopened.ListAllRuns(0)
+	require.NoError(t, err)
+	require.Len(t, all, 3)
+	assert.Equal(t, []string{"run-3", "run-2", "run-1"}, []string{all[0].ID, all[1].ID, all[2].ID})
+
+	chainRuns, err := reopened.ListRunsForChain("chain-a", 1)
+	require.NoError(t, err)
+	require.Len(t, chainRuns, 1)
+	assert.Equal(t, "run-3", chainRuns[0].ID)
+
+	stats, err := reopened.GetRunStatistics("chain-a")
+	require.NoError(t, err)
+	assert.Equal(t, 2, stats.TotalRuns)
+	assert.Equal(t, 1, stats.SuccessfulRuns)
+	assert.Equal(t, 1, stats.FailedRuns)
+}
+
+func TestFileRunStoreConcurrentWriters(t *testing.T) {
+	dir := t.TempDir()
+
+	// Каждое хранилище изображает отдельный процесс (CLI, MCP-сервер): общей у
+	// них остается только файловая блокировка
+	const writers = 4
+	const runsPerWriter = 10
+	var wg sync.WaitGroup
+	for w := 0; w < writers; w++ {
+		store, err := NewFileRunStore(dir)
+		require.NoError(t, err)
+
+		wg.Add(1)
+		go func(w int, store *FileRunStore) {
+			defer wg.Done()
+			for i := 0; i < runsPerWriter; i++ {
+				id := fmt.Sprintf("run-%d-%d", w, i)
+				assert.NoError(t, store.SaveRunMetadata(&RunMetadata{ID: id, ChainID: "chain", Status: StatusRunning, StartTime: time.Now()}))
+			}
+		}(w, store)
+	}
+	wg.Wait()
+
+	store, err := NewFileRunStore(dir)
+	require.NoError(t, err)
+	runs, err := store.ListAllRuns(0)
+	require.NoError(t, err)
+	assert.Len(t, runs, writers*runsPerWriter, "no update is lost")
+
+	require.NoError(t, store.DeleteRunMetadata("run-0-0"))
+	assert.ErrorIs(t, store.DeleteRunMetadata("run-0-0"), ErrRunNotFound)
+
+	// Временные файлы записи не остаются рядом с историей
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	assert.ElementsMatch(t, []string{"runs.json", "runs.json.lock"}, names)
+}
+
+func TestRunHistorySurvivesRestart(t *testing.T) {
+	o, executor, chainID := setupRunTest(t)
+	store, err := NewFileRunStore(t.TempDir())
+	require.NoError(t, err)
+	o.runStore = store
+
+	runID, err := o.RunChain(context.Background(), chainID, TaskInput{Text: "hello"}, DefaultProcessingOptions())
+	require.NoError(t, err)
+	waitStarted(t, executor)
+	close(executor.release)
+	waitRunStatus(t, o, runID, StatusCompleted)
+
+	// Сохранение выполняется после снятия блокировки, ждем записи в файл
+	require.Eventually(t, func() bool {
+		run, err := store.GetRunMetadata(runID)
+		return err == nil && run.Status == StatusCompleted
+	}, 2*time.Second, 10*time.Millisecond)
+
+	// Новый оркестратор без запусков в памяти читает историю из хранилища
+	restarted := NewOrchestratorWithRunStore(nil, nil, o.chainStore, nil, o.taskManager, nil, nil, store)
+
+	run, err := restarted.GetRunStatus(runID)
+	require.NoError(t, err)
+	assert.Equal(t, StatusCompleted, run.Status)
+	assert.Equal(t, chainID, run.ChainID)
+
+	runs, err := restarted.FindRuns(RunFilter{ChainID: chainID, Status: StatusCompleted})
+	require.NoError(t, err)
+	require.Len(t, runs, 1)
+	assert.Equal(t, runID, runs[0].ID)
+
+	runs, err = restarted.FindRuns(RunFilter{Status: StatusFailed})
+	require.NoError(t, err)
+	assert.Empty(t, runs)
+
+	runs, err = restarted.FindRuns(RunFilter{ChainID: "other-chain"})
+	require.NoError(t, err)
+	assert.Empty(t, runs)
+}
+
+func TestCleanupRuns(t *testing.T) {
+	store, err := NewFileRunStore(t.TempDir())
+	require.NoError(t, err)
+
+	now := time.Now()
+	runs := []*RunMetadata{
+		{ID: "fresh-1", ChainID: "c", Status: StatusCompleted, StartTime: now.Add(-time.Hour), EndTime: now.Add(-time.Hour)},
+		{ID: "fresh-2", ChainID: "c", Status: StatusFailed, StartTime: now.Add(-2 * time.Hour), EndTime: now.Add(-2 * time.Hour)},
+		{ID: "fresh-3", ChainID: "c", Status: StatusCancelled, StartTime: now.Add(-3 * time.Hour), EndTime: now.Add(-3 * time.Hour)},
+		{ID: "old", ChainID: "c", Status: StatusCompleted, StartTime: now.Add(-60 * 24 * time.Hour), EndTime: now.Add(-60 * 24 * time.Hour)},
+		{ID: "active", ChainID: "c", Status: StatusRunning, StartTime: now.Add(-90 * 24 * time.Hour)},
+	}
+	for _, run := range runs {
+		require.NoError(t, store.SaveRunMetadata(run))
+	}
+
+	t.Run("default retention on startup removes expired runs", func(t *testing.T) {
+		o := NewOrchestratorWithRunStore(nil, nil, nil, nil, nil, nil, nil, store)
+
+		_, err := store.GetRunMetadata("old")
+		assert.ErrorIs(t, err, ErrRunNotFound)
+		_, err = o.GetRunStatus("old")
+		assert.ErrorIs(t, err, ErrRunNotFound)
+
+		// Активные запуски не удаляются, даже если они старые
+		run, err := o.GetRunStatus("active")
+		require.NoError(t, err)
+		assert.Equal(t, StatusRunning, run.Status)
+
+		// Загруженные запуски доступны из памяти
+		o.mutex.RLock()
+		assert.Len(t, o.runs, 4)
+		o.mutex.RUnlock()
+	})
+
+	t.Run("max runs keeps the newest finished runs", func(t *testing.T) {
+		o := NewOrchestratorWithRunStore(nil, nil, nil, nil, nil, nil, nil, store)
+
+		removed, err := o.CleanupRuns(RunRetention{MaxRuns: 2})
+		require.NoError(t, err)
+		assert.Equal(t, 1, removed)
+
+		stored, err := store.ListAllRuns(0)
+		require.NoError(t, err)
+		var ids []string
+		for _, run := range stored {
+			ids = append(ids, run.ID)
+		}
+		assert.ElementsMatch(t, []string{"fresh-1", "fresh-2", "active"}, ids)
+	})
+}
+
+func TestInterruptedRunsOnRestart(t *testing.T) {
+	store, err := NewFileRunStore(t.TempDir())
+	require.NoError(t, err)
+
+	now := time.Now()
+	host, _ := os.Hostname()
+	// PID, которого нет в системе, и живой процесс, запустивший тесты
+	deadOwner := &RunOwner{PID: 1 << 30, Host: host, Instance: "dead", Heartbeat: now}
+	liveOwner := &RunOwner{PID: os.Getppid(), Host: host, Instance: "live", Heartbeat: now}
+	reusedPID := &RunOwner{PID: os.Getpid(), Host: host, Instance: "previous", Heartbeat: now}
+	staleRemote := &RunOwner{PID: 1, Host: "other-host", Instance: "stale", Heartbeat: now.Add(-time.Hour)}
+	liveRemote := &RunOwner{PID: 1, Host: "other-host", Instance: "remote", Heartbeat: now}
+	for _, run := range []*RunMetadata{
+		{ID: "queued", ChainID: "c", Status: StatusPending, StartTime: now, QueuePosition: 2, Owner: deadOwner},
+		{ID: "running", ChainID: "c", Status: StatusRunning, StartTime: now, CurrentModel: "gpt-4", Owner: reusedPID},
+		{ID: "processing", ChainID: "c", Status: StatusProcessing, StartTime: now, Owner: staleRemote},
+		{ID: "legacy", ChainID: "c", Status: StatusRunning, StartTime: now.Add(-time.Hour)},
+		{ID: "live", ChainID: "c", Status: StatusRunning, StartTime: now, Owner: liveOwner},
+		{ID: "remote", ChainID: "c", Status: StatusRunning, StartTime: now, Owner: liveRemote},
+		{ID: "legacy-fresh", ChainID: "c", Status: StatusRunning, StartTime: now},
+		{ID: "done", ChainID: "c", Status: StatusCompleted, StartTime: now, EndTime: now, Owner: deadOwner},
+	} {
+		require.NoError(t, store.SaveRunMetadata(run))
+	}
+
+	o := NewOrchestratorWithRunStore(nil, nil, nil, nil, nil, nil, nil, store)
+
+	// Загрузка истории, как в командах чтения, запуски не меняет
+	run, err := o.GetRunStatus("queued")
+	require.NoError(t, err)
+	assert.Equal(t, StatusPending, run.Status)
+	stored, err := store.GetRunMetadata("running")
+	require.NoError(t, err)
+	assert.Equal(t, StatusRunning, stored.Status)
+
+	interrupted, err := o.RecoverInterruptedRuns()
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"queued", "running", "processing", "legacy"}, interrupted)
+
+	for _, runID := range interrupted {
+		run, err := o.GetRunStatus(runID)
+		require.NoError(t, err)
+		assert.Equal(t, StatusFailed, run.Status, runID)
+		assert.Equal(t, ErrRunInterrupted.Error(), run.Error, runID)
+		assert.Zero(t, run.QueuePosition, runID)
+		assert.Empty(t, run.CurrentModel, runID)
+
+		// Новый статус сохранен, чтобы другие процессы тоже видели запуск завершенным
+		stored, err := store.GetRunMetadata(runID)
+		require.NoError(t, err)
+		assert.Equal(t, StatusFailed, stored.Status, runID)
+	}
+
+	// Запуски живых владельцев продолжают выполняться
+	for _, runID := range []string{"live", "remote", "legacy-fresh"} {
+		stored, err := store.GetRunMetadata(runID)
+		require.NoError(t, err)
+		assert.Equal(t, StatusRunning, stored.Status, runID)
+	}
+
+	run, err = o.GetRunStatus("done")
+	require.NoError(t, err)
+	assert.Equal(t, StatusCompleted, run.Status)
+	assert.Empty(t, run.Error)
+
+	// Прерванный запуск нельзя отменить: он уже не выполняется
+	assert.Error(t, o.CancelRun("running"))
+}
+
+func TestRunHeartbeat(t *testing.T) {
+	store, err := NewFileRunStore(t.TempDir())
+	require.NoError(t, err)
+	o := NewOrchestratorWithRunStore(nil, nil, nil, nil, nil, nil, nil, store)
+
+	started := time.Now().Add(-time.Hour)
+	owner := o.owner
+	owner.Heartbeat = started
+	other := RunOwner{PID: 1, Host: "other-host", Instance: "remote", Heartbeat: started}
+	o.mutex.Lock()
+	o.runs["own"] = &RunMetadata{ID: "own", ChainID: "c", Status: StatusRunning, StartTime: started, Owner: &owner}
+	o.runs["other"] = &RunMetadata{ID: "other", ChainID: "c", Status: StatusRunning, StartTime: started, Owner: &other}
+	o.mutex.Unlock()
+
+	now := time.Now()
+	o.beat(now)
+
+	stored, err := store.GetRunMetadata("own")
+	require.NoError(t, err)
+	require.NotNil(t, stored.Owner)
+	assert.Equal(t, o.owner.Instance, stored.Owner.Instance)
+	assert.WithinDuration(t, now, stored.Owner.Heartbeat, time.Second)
+
+	// Чужие запуски процесс не подтверждает
+	_, err = store.GetRunMetadata("other")
+	assert.ErrorIs(t, err, ErrRunNotFound)
+	assert.Equal(t, started, other.Heartbeat)
+}
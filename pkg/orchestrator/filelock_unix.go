@@ -0,0 +1,19 @@
+//go:build !windows
+
+package orchestrator
+
+import (
+	"os"
+	"syscall"
+)
+
+// lockFile блокирует открытый файл эксклюзивно, ожидая, пока другой процесс
+// не снимет свою блокировку
+func lockFile(file *os.File) error {
+	return syscall.Flock(int(file.Fd()), syscall.LOCK_EX)
+}
+
+// unlockFile снимает блокировку, взятую lockFile
+func unlockFile(file *os.File) error {
+	return syscall.Flock(int(file.Fd()), syscall.LOCK_UN)
+}
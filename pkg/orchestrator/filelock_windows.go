@@ -0,0 +1,22 @@
+//go:build windows
+
+package orchestrator
+
+import (
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// lockFile блокирует открытый файл эксклюзивно, ожидая, пока другой процесс
+// не снимет свою блокировку
+func lockFile(file *os.File) error {
+	overlapped := new(windows.Overlapped)
+	return windows.LockFileEx(windows.Handle(file.Fd()), windows.LOCKFILE_EXCLUSIVE_LOCK, 0, 1, 0, overlapped)
+}
+
+// unlockFile снимает блокировку, взятую lockFile
+func unlockFile(file *os.File) error {
+	overlapped := new(windows.Overlapped)
+	return windows.UnlockFileEx(windows.Handle(file.Fd()), 0, 1, 0, overlapped)
+}
@@ -3,9 +3,11 @@ package orchestrator
 import (
 	"context"
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/grik-ai/ricochet-task/pkg/checkpoint"
+	"github.com/grik-ai/ricochet-task/pkg/segmentation"
 )
 
 // Статусы выполнения задачи
@@ -31,31 +33,81 @@ type RunMetadata struct {
 	CurrentModel  string                 `json:"current_model,omitempty"`
 	TotalTokens   int                    `json:"total_tokens"`
 	Error         string                 `json:"error,omitempty"`
-	Checkpoints   []string               `json:"checkpoints"` // ID чекпоинтов
+	Checkpoints   []string               `json:"checkpoints"`              // ID чекпоинтов
+	QueuePosition int                    `json:"queue_position,omitempty"` // Место в очереди запусков, начиная с 1, пока запуск ждет слота
+	Owner         *RunOwner              `json:"owner,omitempty"`          // Процесс, выполняющий запуск
+	Result        *TaskOutput            `json:"result,omitempty"`         // Объединенный результат сегментов, сохраняется при завершении
 	ExtraMetadata map[string]interface{} `json:"extra_metadata,omitempty"`
 }
 
-// ProcessingOptions содержит опции для обработки цепочки
+// ProcessingOptions содержит опции для обработки цепочки.
+//
+// Вход длиннее MaxTokensPerChunk токенов разбивается на сегменты методом
+// SegmentationMethod. SegmentOverlap добавляет к каждому сегменту, кроме первого,
+// столько токенов предшествующего текста в качестве контекста. Это сохраняет
+// связность на стыках, но увеличивает входные токены каждой модели примерно на
+// SegmentOverlap * (число сегментов - 1).
+//
+// Результаты сегментов объединяются по AggregationStrategy: concatenate склеивает
+// их по порядку, summarize сворачивает моделью (map-reduce), merge убирает повторы.
 type ProcessingOptions struct {
-	MaxParallelChunks  int    `json:"max_parallel_chunks"`
-	MaxTokensPerChunk  int    `json:"max_tokens_per_chunk"`
-	SegmentationMethod string `json:"segmentation_method"` // simple, semantic, recursive
-	SaveCheckpoints    bool   `json:"save_checkpoints"`
-	AutoRetry          bool   `json:"auto_retry"`
-	RetryAttempts      int    `json:"retry_attempts"`
-	RetryDelay         int    `json:"retry_delay"` // в секундах
+	MaxParallelChunks   int    `json:"max_parallel_chunks"`
+	MaxTokensPerChunk   int    `json:"max_tokens_per_chunk"`
+	SegmentationMethod  string `json:"segmentation_method"`  // sentence, paragraph, semantic, simple, recursive
+	SegmentOverlap      int    `json:"segment_overlap"`      // в токенах, 0 - без перекрытия
+	AggregationStrategy string `json:"aggregation_strategy"` // concatenate, summarize, merge
+	SaveCheckpoints     bool   `json:"save_checkpoints"`
+	AutoRetry           bool   `json:"auto_retry"`
+	RetryAttempts       int    `json:"retry_attempts"`
+	RetryDelay          int    `json:"retry_delay"` // в секундах
+	RunTimeout          int    `json:"run_timeout"` // в секундах, 0 - без ограничения
 }
 
 // DefaultProcessingOptions возвращает настройки по умолчанию
 func DefaultProcessingOptions() ProcessingOptions {
 	return ProcessingOptions{
-		MaxParallelChunks:  3,
-		MaxTokensPerChunk:  2000,
-		SegmentationMethod: "simple",
-		SaveCheckpoints:    true,
-		AutoRetry:          true,
-		RetryAttempts:      3,
-		RetryDelay:         5,
+		MaxParallelChunks:   3,
+		MaxTokensPerChunk:   2000,
+		SegmentationMethod:  "simple",
+		AggregationStrategy: AggregationConcatenate,
+		SaveCheckpoints:     true,
+		AutoRetry:           true,
+		RetryAttempts:       3,
+		RetryDelay:          5,
+		RunTimeout:          3600,
+	}
+}
+
+// Validate проверяет параметры сегментации и параллельной обработки
+func (o ProcessingOptions) Validate() error {
+	if o.MaxTokensPerChunk <= 0 {
+		return fmt.Errorf("max tokens per chunk must be positive, got %d", o.MaxTokensPerChunk)
+	}
+	if o.SegmentOverlap < 0 {
+		return fmt.Errorf("segment overlap must not be negative, got %d", o.SegmentOverlap)
+	}
+	// Перекрытие не должно быть сопоставимо с сегментом, иначе почти весь вход уходит повторно
+	if o.SegmentOverlap*2 > o.MaxTokensPerChunk {
+		return fmt.Errorf("segment overlap %d must not exceed half of max tokens per chunk (%d)", o.SegmentOverlap, o.MaxTokensPerChunk)
+	}
+	if o.SegmentationMethod != "" && !segmentation.IsKnownSegmentation(o.SegmentationMethod) {
+		return fmt.Errorf("unknown segmentation method %q", o.SegmentationMethod)
+	}
+	if o.AggregationStrategy != "" && !isKnownAggregation(o.AggregationStrategy) {
+		return fmt.Errorf("unknown aggregation strategy %q", o.AggregationStrategy)
+	}
+	if o.MaxParallelChunks < 0 {
+		return fmt.Errorf("max parallel chunks must not be negative, got %d", o.MaxParallelChunks)
+	}
+	return nil
+}
+
+// SegmentationOptions возвращает опции пакета segmentation для этих настроек
+func (o ProcessingOptions) SegmentationOptions() segmentation.SegmentationOptions {
+	return segmentation.SegmentationOptions{
+		ChunkSize: o.MaxTokensPerChunk,
+		Method:    segmentation.SegmentationMethod(o.SegmentationMethod),
+		Overlap:   o.SegmentOverlap,
 	}
 }
 
@@ -122,4 +174,7 @@ var (
 	ErrRunNotFound   = errors.New("run not found")
 	ErrRunCancelled  = errors.New("run cancelled")
 	ErrInvalidInput  = errors.New("invalid input")
+
+	// ErrRunInterrupted запуск не завершился: процесс-владелец остановился
+	ErrRunInterrupted = errors.New("run interrupted: the process running it exited")
 )
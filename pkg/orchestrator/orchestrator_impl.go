@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sort"
 	"sync"
 	"time"
 
@@ -18,6 +19,26 @@ import (
 	"github.com/grik-ai/ricochet-task/pkg/task"
 )
 
+// DefaultMaxConcurrentRuns ограничивает число одновременно выполняемых
+// запусков, если оно не задано через SetMaxConcurrentRuns
+const DefaultMaxConcurrentRuns = 4
+
+// queuedRun запуск, ожидающий свободного слота
+type queuedRun struct {
+	runID   string
+	chain   chain.Chain
+	input   TaskInput
+	options ProcessingOptions
+	ctx     context.Context // Контекст вызывающего, из которого создается контекст выполнения
+}
+
+// QueueStatus описывает загрузку оркестратора: выполняемые запуски и очередь
+type QueueStatus struct {
+	Running       int `json:"running"`
+	Queued        int `json:"queued"`
+	MaxConcurrent int `json:"max_concurrent"` // 0 - без ограничения
+}
+
 // DefaultOrchestrator реализует интерфейс Orchestrator
 type DefaultOrchestrator struct {
 	apiClient       *api.Client
@@ -28,8 +49,15 @@ type DefaultOrchestrator struct {
 	taskExecutor    task.TaskExecutor
 	modelFactory    *model.ProviderFactory
 	runs            map[string]*RunMetadata
+	cancels         map[string]context.CancelFunc // Функции отмены активных запусков
 	mutex           sync.RWMutex
-	runStore        *PostgresRunStore // Опциональное PostgreSQL хранилище
+	runStore        RunStore     // Опциональное постоянное хранилище запусков
+	storeMutex      sync.Mutex   // Упорядочивает запись в runStore
+	maxConcurrent   int          // Предел одновременных запусков, 0 - без ограничения
+	activeRuns      int          // Запуски, занимающие слот
+	queue           []*queuedRun // Запуски, ожидающие слота, в порядке поступления
+	owner           RunOwner     // Текущий процесс как владелец своих запусков
+	ownerOnce       sync.Once    // Восстановление прерванных запусков и heartbeat запускаются один раз
 }
 
 // NewOrchestrator создает новый оркестратор
@@ -51,6 +79,42 @@ func NewOrchestrator(
 		taskExecutor:    taskExecutor,
 		modelFactory:    modelFactory,
 		runs:            make(map[string]*RunMetadata),
+		cancels:         make(map[string]context.CancelFunc),
+		maxConcurrent:   DefaultMaxConcurrentRuns,
+		owner:           newRunOwner(),
+	}
+}
+
+// SetMaxConcurrentRuns задает предел одновременно выполняемых запусков.
+// Запуски сверх предела ждут в очереди в статусе pending; 0 снимает
+// ограничение. Ожидающие запуски стартуют, если предел увеличился.
+func (o *DefaultOrchestrator) SetMaxConcurrentRuns(limit int) {
+	if limit < 0 {
+		limit = 0
+	}
+
+	o.mutex.Lock()
+	o.maxConcurrent = limit
+	var ready []*queuedRun
+	for len(o.queue) > 0 && o.hasFreeSlot() {
+		ready = append(ready, o.dequeueRun())
+	}
+	o.mutex.Unlock()
+
+	for _, run := range ready {
+		o.startRun(run)
+	}
+}
+
+// QueueStatus возвращает число выполняемых и ожидающих запусков
+func (o *DefaultOrchestrator) QueueStatus() QueueStatus {
+	o.mutex.RLock()
+	defer o.mutex.RUnlock()
+
+	return QueueStatus{
+		Running:       o.activeRuns,
+		Queued:        len(o.queue),
+		MaxConcurrent: o.maxConcurrent,
 	}
 }
 
@@ -65,20 +129,49 @@ func NewOrchestratorWithPostgres(
 	modelFactory *model.ProviderFactory,
 	postgresRunStore *PostgresRunStore,
 ) *DefaultOrchestrator {
-	return &DefaultOrchestrator{
-		apiClient:       apiClient,
-		keyStore:        keyStore,
-		chainStore:      chainStore,
-		checkpointStore: checkpointStore,
-		taskManager:     taskManager,
-		taskExecutor:    taskExecutor,
-		modelFactory:    modelFactory,
-		runs:            make(map[string]*RunMetadata),
-		runStore:        postgresRunStore,
-	}
+	return NewOrchestratorWithRunStore(
+		apiClient,
+		keyStore,
+		chainStore,
+		checkpointStore,
+		taskManager,
+		taskExecutor,
+		modelFactory,
+		postgresRunStore,
+	)
 }
 
-// RunChain запускает цепочку моделей с указанными входными данными
+// NewOrchestratorWithRunStore создает новый оркестратор, сохраняющий
+// метаданные запусков в runStore, чтобы история переживала перезапуск
+func NewOrchestratorWithRunStore(
+	apiClient *api.Client,
+	keyStore key.Store,
+	chainStore chain.Store,
+	checkpointStore checkpoint.Store,
+	taskManager task.TaskManager,
+	taskExecutor task.TaskExecutor,
+	modelFactory *model.ProviderFactory,
+	runStore RunStore,
+) *DefaultOrchestrator {
+	o := NewOrchestrator(
+		apiClient,
+		keyStore,
+		chainStore,
+		checkpointStore,
+		taskManager,
+		taskExecutor,
+		modelFactory,
+	)
+	o.runStore = runStore
+	o.loadRuns(DefaultRunRetention())
+	return o
+}
+
+// RunChain запускает цепочку моделей с указанными входными данными.
+// Цепочка выполняется в фоне и не прерывается при отмене ctx после возврата
+// из RunChain; для остановки используйте CancelRun или options.RunTimeout.
+// Если одновременно выполняется предельное число запусков (см.
+// SetMaxConcurrentRuns), запуск ждет в очереди в статусе pending.
 func (o *DefaultOrchestrator) RunChain(ctx context.Context, chainID string, input TaskInput, options ProcessingOptions) (string, error) {
 	// Проверяем существование цепочки
 	chainObj, err := o.chainStore.Get(chainID)
@@ -90,9 +183,17 @@ func (o *DefaultOrchestrator) RunChain(ctx context.Context, chainID string, inpu
 	if err := validateInput(input); err != nil {
 		return "", fmt.Errorf("%w: %v", ErrInvalidInput, err)
 	}
+	if err := options.Validate(); err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidInput, err)
+	}
+
+	// Перед первым запуском освобождаем историю от запусков остановленных процессов
+	o.startOwnerDuties()
 
 	// Создаем ID для запуска
 	runID := uuid.New().String()
+	owner := o.owner
+	owner.Heartbeat = time.Now()
 
 	// Создаем метаданные запуска
 	runMetadata := &RunMetadata{
@@ -102,52 +203,148 @@ func (o *DefaultOrchestrator) RunChain(ctx context.Context, chainID string, inpu
 		StartTime:   time.Now(),
 		Progress:    0.0,
 		Checkpoints: []string{},
+		Owner:       &owner,
+		ExtraMetadata: map[string]interface{}{
+			"aggregation_strategy": options.AggregationStrategy,
+		},
 	}
+	run := &queuedRun{runID: runID, chain: chainObj, input: input, options: options, ctx: ctx}
 
-	// Сохраняем метаданные запуска
+	// Сохраняем метаданные запуска и занимаем слот или встаем в очередь
 	o.mutex.Lock()
 	o.runs[runID] = runMetadata
+	start := o.hasFreeSlot()
+	if start {
+		o.activeRuns++
+	} else {
+		o.queue = append(o.queue, run)
+		o.updateQueuePositions()
+	}
 	o.mutex.Unlock()
 
-	// Также сохраняем в PostgreSQL если доступно
-	if o.runStore != nil {
-		if err := o.runStore.SaveRunMetadata(runMetadata); err != nil {
-			// Логируем ошибку, но продолжаем выполнение
-			fmt.Printf("Warning: failed to save run metadata to PostgreSQL: %v\n", err)
+	if start {
+		o.startRun(run)
+	} else {
+		o.persistRun(runID)
+	}
+
+	return runID, nil
+}
+
+// hasFreeSlot проверяет, может ли стартовать еще один запуск.
+// Вызывается под o.mutex.
+func (o *DefaultOrchestrator) hasFreeSlot() bool {
+	return o.maxConcurrent == 0 || o.activeRuns < o.maxConcurrent
+}
+
+// dequeueRun забирает первый запуск очереди и отдает ему слот.
+// Вызывается под o.mutex.
+func (o *DefaultOrchestrator) dequeueRun() *queuedRun {
+	run := o.queue[0]
+	o.queue = o.queue[1:]
+	o.activeRuns++
+	o.updateQueuePositions()
+	return run
+}
+
+// updateQueuePositions обновляет место в очереди у ожидающих запусков.
+// Вызывается под o.mutex.
+func (o *DefaultOrchestrator) updateQueuePositions() {
+	for i, run := range o.queue {
+		if metadata, ok := o.runs[run.runID]; ok {
+			metadata.QueuePosition = i + 1
 		}
 	}
+}
+
+// startRun выполняет запуск, занявший слот, в отдельной горутине. По
+// завершении слот переходит к следующему запуску очереди.
+func (o *DefaultOrchestrator) startRun(run *queuedRun) {
+	runID := run.runID
+
+	// Выполнение переживает возврат из RunChain, поэтому использует собственный
+	// контекст: отмена ctx вызывающего на него не влияет, а завершить запуск
+	// можно только через CancelRun или по истечении RunTimeout
+	runCtx, cancel := newRunContext(run.ctx, run.options)
 
-	// Обновляем статус запуска
+	// Обновляем статус запуска. Время ожидания в очереди не входит в
+	// длительность выполнения.
+	o.mutex.Lock()
+	runMetadata := o.runs[runID]
+	if runMetadata.QueuePosition > 0 {
+		runMetadata.ExtraMetadata["queue_wait_ms"] = time.Since(runMetadata.StartTime).Milliseconds()
+		runMetadata.StartTime = time.Now()
+	}
+	runMetadata.QueuePosition = 0
 	runMetadata.Status = StatusRunning
+	o.cancels[runID] = cancel
+	o.mutex.Unlock()
+
+	// Также сохраняем в постоянное хранилище, если оно доступно
+	o.persistRun(runID)
 
 	// Запускаем горутину для выполнения цепочки
 	go func() {
-		err := o.executeChain(ctx, chainObj, input, options, runID)
+		err := o.executeChain(runCtx, run.chain, run.input, run.options, runID)
 		o.mutex.Lock()
-		if err != nil {
+		switch {
+		case runMetadata.Status == StatusCancelled:
+			// Статус и время завершения уже выставлены в CancelRun
+		case err != nil:
 			runMetadata.Status = StatusFailed
 			runMetadata.Error = err.Error()
-		} else {
+			if errors.Is(runCtx.Err(), context.DeadlineExceeded) {
+				runMetadata.Error = fmt.Sprintf("run timed out after %ds: %v", run.options.RunTimeout, err)
+			}
+			runMetadata.EndTime = time.Now()
+		default:
 			runMetadata.Status = StatusCompleted
+			runMetadata.EndTime = time.Now()
+		}
+		delete(o.cancels, runID)
+
+		// Освобождаем слот или передаем его следующему запуску очереди
+		o.activeRuns--
+		var next *queuedRun
+		if len(o.queue) > 0 && o.hasFreeSlot() {
+			next = o.dequeueRun()
 		}
-		runMetadata.EndTime = time.Now()
 		o.mutex.Unlock()
-	}()
+		cancel()
 
-	return runID, nil
+		o.persistRun(runID)
+		if next != nil {
+			o.startRun(next)
+		}
+	}()
 }
 
 // GetRunStatus возвращает статус выполнения
 func (o *DefaultOrchestrator) GetRunStatus(runID string) (*RunMetadata, error) {
-	o.mutex.RLock()
-	defer o.mutex.RUnlock()
+	return o.getRun(runID)
+}
 
+// getRun ищет запуск в памяти, а затем в постоянном хранилище.
+// Возвращает копию, которую можно читать без блокировки.
+func (o *DefaultOrchestrator) getRun(runID string) (*RunMetadata, error) {
+	o.mutex.RLock()
 	metadata, exists := o.runs[runID]
-	if !exists {
-		return nil, ErrRunNotFound
+	var snapshot RunMetadata
+	if exists {
+		snapshot = *metadata
+	}
+	o.mutex.RUnlock()
+	if exists {
+		return &snapshot, nil
+	}
+
+	if o.runStore != nil {
+		if metadata, err := o.runStore.GetRunMetadata(runID); err == nil {
+			return metadata, nil
+		}
 	}
 
-	return metadata, nil
+	return nil, ErrRunNotFound
 }
 
 // CancelRun отменяет выполнение
@@ -160,6 +357,19 @@ func (o *DefaultOrchestrator) CancelRun(runID string) error {
 		return ErrRunNotFound
 	}
 
+	// Запуск из очереди еще ничего не выполнял, достаточно убрать его оттуда
+	for i, run := range o.queue {
+		if run.runID == runID {
+			o.queue = append(o.queue[:i], o.queue[i+1:]...)
+			o.updateQueuePositions()
+			metadata.QueuePosition = 0
+			metadata.Status = StatusCancelled
+			metadata.EndTime = time.Now()
+			go o.persistRun(runID)
+			return nil
+		}
+	}
+
 	if metadata.Status != StatusRunning && metadata.Status != StatusProcessing {
 		return fmt.Errorf("run is not in progress: %s", metadata.Status)
 	}
@@ -167,6 +377,14 @@ func (o *DefaultOrchestrator) CancelRun(runID string) error {
 	metadata.Status = StatusCancelled
 	metadata.EndTime = time.Now()
 
+	// Прерываем контекст выполнения цепочки
+	if cancel, ok := o.cancels[runID]; ok {
+		cancel()
+	}
+
+	// Сохраняем статус в фоне, чтобы не выполнять запись под блокировкой
+	go o.persistRun(runID)
+
 	// Отменяем выполняемые задачи
 	tasks, err := o.taskManager.ListTasks()
 	if err != nil {
@@ -187,30 +405,186 @@ func (o *DefaultOrchestrator) CancelRun(runID string) error {
 
 // ListRuns возвращает список всех выполнений
 func (o *DefaultOrchestrator) ListRuns() []*RunMetadata {
+	runs, err := o.FindRuns(RunFilter{})
+	if err != nil {
+		fmt.Printf("Warning: failed to list stored runs: %v\n", err)
+	}
+
+	return runs
+}
+
+// FindRuns возвращает запуски из памяти и постоянного хранилища, отобранные и
+// упорядоченные по фильтру (по умолчанию новые первыми).
+// При ошибке хранилища возвращаются запуски текущего процесса вместе с ошибкой.
+func (o *DefaultOrchestrator) FindRuns(filter RunFilter) ([]*RunMetadata, error) {
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+
+	byID := make(map[string]*RunMetadata)
+
+	var storeErr error
+	if o.runStore != nil {
+		var stored []*RunMetadata
+		if filter.ChainID != "" {
+			stored, storeErr = o.runStore.ListRunsForChain(filter.ChainID, 0)
+		} else {
+			stored, storeErr = o.runStore.ListAllRuns(0)
+		}
+		for _, run := range stored {
+			byID[run.ID] = run
+		}
+	}
+
+	// Запуски текущего процесса актуальнее сохраненных
 	o.mutex.RLock()
-	defer o.mutex.RUnlock()
+	for id, run := range o.runs {
+		snapshot := *run
+		byID[id] = &snapshot
+	}
+	o.mutex.RUnlock()
 
-	runs := make([]*RunMetadata, 0, len(o.runs))
-	for _, run := range o.runs {
-		runs = append(runs, run)
+	runs := make([]*RunMetadata, 0, len(byID))
+	for _, run := range byID {
+		if filter.Matches(run) {
+			runs = append(runs, run)
+		}
 	}
 
-	return runs
+	filter.sort(runs)
+	return filter.page(runs), storeErr
 }
 
-// GetRunResults возвращает результаты выполнения
-func (o *DefaultOrchestrator) GetRunResults(runID string) (TaskOutput, error) {
+// persistRun сохраняет текущее состояние запуска в постоянное хранилище, если оно задано.
+// Снимок берется под storeMutex, поэтому последней записью всегда остается
+// последнее состояние, даже если сохранения вызваны из разных горутин.
+func (o *DefaultOrchestrator) persistRun(runID string) {
+	if o.runStore == nil {
+		return
+	}
+
+	o.storeMutex.Lock()
+	defer o.storeMutex.Unlock()
+
 	o.mutex.RLock()
 	metadata, exists := o.runs[runID]
+	var snapshot RunMetadata
+	if exists {
+		snapshot = *metadata
+		snapshot.Checkpoints = append([]string(nil), metadata.Checkpoints...)
+	}
 	o.mutex.RUnlock()
-
 	if !exists {
-		return TaskOutput{}, ErrRunNotFound
+		return
+	}
+
+	if err := o.runStore.SaveRunMetadata(&snapshot); err != nil {
+		// Логируем ошибку, но продолжаем выполнение
+		fmt.Printf("Warning: failed to save run metadata: %v\n", err)
+	}
+}
+
+// RunRetention задает политику хранения завершенных запусков.
+// Активные запуски (pending, running, processing) не удаляются.
+type RunRetention struct {
+	MaxAge  time.Duration // Удалять запуски, завершенные раньше, 0 - без ограничения
+	MaxRuns int           // Хранить не больше стольких завершенных запусков, 0 - без ограничения
+}
+
+// DefaultRunRetention возвращает политику хранения по умолчанию
+func DefaultRunRetention() RunRetention {
+	return RunRetention{
+		MaxAge:  30 * 24 * time.Hour,
+		MaxRuns: 1000,
+	}
+}
+
+// loadRuns загружает историю запусков из хранилища в память,
+// предварительно удаляя устаревшие запуски. Незавершенные запуски не
+// меняются: их может выполнять другой процесс (см. RecoverInterruptedRuns).
+func (o *DefaultOrchestrator) loadRuns(retention RunRetention) {
+	if o.runStore == nil {
+		return
+	}
+
+	if _, err := o.CleanupRuns(retention); err != nil {
+		fmt.Printf("Warning: failed to clean up old runs: %v\n", err)
+	}
+
+	runs, err := o.runStore.ListAllRuns(0)
+	if err != nil {
+		fmt.Printf("Warning: failed to load stored runs: %v\n", err)
+		return
+	}
+
+	o.mutex.Lock()
+	for _, run := range runs {
+		if _, exists := o.runs[run.ID]; !exists {
+			o.runs[run.ID] = run
+		}
+	}
+	o.mutex.Unlock()
+}
+
+// CleanupRuns удаляет из хранилища и памяти завершенные запуски,
+// выходящие за рамки политики хранения. Возвращает количество удаленных запусков.
+func (o *DefaultOrchestrator) CleanupRuns(retention RunRetention) (int, error) {
+	runs, err := o.FindRuns(RunFilter{})
+	if err != nil {
+		return 0, err
+	}
+
+	var expired []string
+	finished := 0
+	for _, run := range runs {
+		if !isRunFinished(run.Status) {
+			continue
+		}
+		finished++
+
+		// Запуски отсортированы от новых к старым
+		endTime := run.EndTime
+		if endTime.IsZero() {
+			endTime = run.StartTime
+		}
+		if (retention.MaxRuns > 0 && finished > retention.MaxRuns) ||
+			(retention.MaxAge > 0 && time.Since(endTime) > retention.MaxAge) {
+			expired = append(expired, run.ID)
+		}
+	}
+
+	for i, runID := range expired {
+		if o.runStore != nil {
+			if err := o.runStore.DeleteRunMetadata(runID); err != nil {
+				return i, fmt.Errorf("failed to delete run %s: %w", runID, err)
+			}
+		}
+		o.mutex.Lock()
+		delete(o.runs, runID)
+		o.mutex.Unlock()
+	}
+
+	return len(expired), nil
+}
+
+// isRunFinished проверяет, что запуск больше не выполняется
+func isRunFinished(status RunStatus) bool {
+	return status == StatusCompleted || status == StatusFailed || status == StatusCancelled
+}
+
+// GetRunResults возвращает результаты выполнения
+func (o *DefaultOrchestrator) GetRunResults(runID string) (TaskOutput, error) {
+	metadata, err := o.getRun(runID)
+	if err != nil {
+		return TaskOutput{}, err
 	}
 
 	if metadata.Status != StatusCompleted {
 		return TaskOutput{}, fmt.Errorf("run is not completed: %s", metadata.Status)
 	}
+	if metadata.Result != nil {
+		return *metadata.Result, nil
+	}
 
 	// Ищем задачи, относящиеся к этому запуску
 	tasks, err := o.taskManager.ListTasks()
@@ -218,6 +592,12 @@ func (o *DefaultOrchestrator) GetRunResults(runID string) (TaskOutput, error) {
 		return TaskOutput{}, fmt.Errorf("failed to list tasks: %w", err)
 	}
 
+	// Результаты сегментов объединяются один раз при завершении запуска:
+	// свертка моделью при каждом чтении стоила бы нового запроса
+	if len(completedSegmentTasks(tasks, runID)) > 0 {
+		return TaskOutput{}, fmt.Errorf("aggregated result of run %s was not saved", runID)
+	}
+
 	// Находим последнюю завершенную задачу
 	var lastTask *task.Task
 	for i := range tasks {
@@ -241,6 +621,72 @@ func (o *DefaultOrchestrator) GetRunResults(runID string) (TaskOutput, error) {
 	return output, nil
 }
 
+// completedSegmentTasks возвращает завершенные задачи сегментов запуска в порядке сегментов
+func completedSegmentTasks(tasks []task.Task, runID string) []task.Task {
+	var segmentTasks []task.Task
+	for _, t := range tasks {
+		if t.RunID != runID || t.Status != task.StatusCompleted || t.Type != task.TaskTypeModelExecution {
+			continue
+		}
+		if _, ok := t.Input.Metadata["segment_count"]; ok {
+			segmentTasks = append(segmentTasks, t)
+		}
+	}
+
+	sort.SliceStable(segmentTasks, func(i, j int) bool {
+		return segmentTasks[i].Input.Segment < segmentTasks[j].Input.Segment
+	})
+	return segmentTasks
+}
+
+// saveRunResult объединяет результаты задач сегментов по стратегии запуска и
+// сохраняет их в метаданных запуска, чтобы GetRunResults возвращал готовый результат
+func (o *DefaultOrchestrator) saveRunResult(ctx context.Context, runID string) error {
+	tasks, err := o.taskManager.ListTasks()
+	if err != nil {
+		return fmt.Errorf("failed to list tasks: %w", err)
+	}
+	segmentTasks := completedSegmentTasks(tasks, runID)
+	if len(segmentTasks) == 0 {
+		return nil
+	}
+
+	o.mutex.RLock()
+	metadata := o.runs[runID]
+	options := DefaultProcessingOptions()
+	if strategy, ok := metadata.ExtraMetadata["aggregation_strategy"].(string); ok && strategy != "" {
+		options.AggregationStrategy = strategy
+	}
+	o.mutex.RUnlock()
+
+	results := make([]string, len(segmentTasks))
+	for i, t := range segmentTasks {
+		results[i] = t.Output.Destination
+	}
+
+	// Свертку выполняет модель, обработавшая сегменты
+	var model chain.Model
+	if segmentTasks[0].Model != nil {
+		model = *segmentTasks[0].Model
+	}
+
+	text, err := o.aggregateResults(ctx, model, results, metadata, options)
+	if err != nil {
+		return fmt.Errorf("failed to aggregate segment results: %w", err)
+	}
+
+	o.mutex.Lock()
+	metadata.Result = &TaskOutput{
+		Text: text,
+		Metadata: map[string]interface{}{
+			"aggregation_strategy": options.AggregationStrategy,
+			"segments":             len(segmentTasks),
+		},
+	}
+	o.mutex.Unlock()
+	return nil
+}
+
 // GetCheckpoint возвращает чекпоинт
 func (o *DefaultOrchestrator) GetCheckpoint(checkpointID string) (checkpoint.Checkpoint, error) {
 	return o.checkpointStore.Get(checkpointID)
@@ -248,12 +694,9 @@ func (o *DefaultOrchestrator) GetCheckpoint(checkpointID string) (checkpoint.Che
 
 // ListCheckpoints возвращает список чекпоинтов для указанного выполнения
 func (o *DefaultOrchestrator) ListCheckpoints(runID string) ([]checkpoint.Checkpoint, error) {
-	o.mutex.RLock()
-	metadata, exists := o.runs[runID]
-	o.mutex.RUnlock()
-
-	if !exists {
-		return nil, ErrRunNotFound
+	metadata, err := o.getRun(runID)
+	if err != nil {
+		return nil, err
 	}
 
 	checkpoints := make([]checkpoint.Checkpoint, 0, len(metadata.Checkpoints))
@@ -269,7 +712,7 @@ func (o *DefaultOrchestrator) ListCheckpoints(runID string) ([]checkpoint.Checkp
 
 // GetRunStatistics возвращает статистику выполнения для цепочки
 func (o *DefaultOrchestrator) GetRunStatistics(chainID string) (*RunStatistics, error) {
-	// Если доступно PostgreSQL хранилище, используем его
+	// Если доступно постоянное хранилище, используем его
 	if o.runStore != nil {
 		return o.runStore.GetRunStatistics(chainID)
 	}
@@ -285,12 +728,17 @@ func (o *DefaultOrchestrator) GetRunStatistics(chainID string) (*RunStatistics,
 		}
 	}
 
-	if len(chainRuns) == 0 {
-		return &RunStatistics{}, nil
+	return computeRunStatistics(chainRuns), nil
+}
+
+// computeRunStatistics считает статистику по списку запусков цепочки
+func computeRunStatistics(runs []*RunMetadata) *RunStatistics {
+	if len(runs) == 0 {
+		return &RunStatistics{}
 	}
 
 	stats := &RunStatistics{
-		TotalRuns: len(chainRuns),
+		TotalRuns: len(runs),
 	}
 
 	var totalDuration int64
@@ -298,7 +746,7 @@ func (o *DefaultOrchestrator) GetRunStatistics(chainID string) (*RunStatistics,
 	var lastRunTime time.Time
 	var lastSuccessTime time.Time
 
-	for _, run := range chainRuns {
+	for _, run := range runs {
 		if run.Status == StatusCompleted {
 			stats.SuccessfulRuns++
 			if run.EndTime.After(lastSuccessTime) {
@@ -347,7 +795,7 @@ func (o *DefaultOrchestrator) GetRunStatistics(chainID string) (*RunStatistics,
 		stats.LastSuccessfulDate = lastSuccessTime.Format(time.RFC3339)
 	}
 
-	return stats, nil
+	return stats
 }
 
 // executeChain выполняет цепочку моделей
@@ -369,7 +817,7 @@ func (o *DefaultOrchestrator) executeChain(ctx context.Context, chain chain.Chai
 	}
 
 	// Определяем, нужна ли сегментация
-	needsSegmentation := len(inputText) > options.MaxTokensPerChunk
+	needsSegmentation := estimateTokenCount(inputText) > options.MaxTokensPerChunk
 
 	// Задачи для выполнения
 	var taskIDs []string
@@ -404,12 +852,20 @@ func (o *DefaultOrchestrator) executeChain(ctx context.Context, chain chain.Chai
 		}
 		o.mutex.RUnlock()
 
+		// Проверяем, не истек ли дедлайн запуска
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("run aborted: %w", err)
+		}
+
 		// Запускаем задачу
 		if err := o.taskExecutor.ExecuteTask(ctx, taskID); err != nil {
 			return fmt.Errorf("task execution failed: %w", err)
 		}
 	}
 
+	if needsSegmentation {
+		return o.saveRunResult(ctx, runID)
+	}
 	return nil
 }
 
@@ -425,7 +881,8 @@ func (o *DefaultOrchestrator) createSegmentationTask(inputText string, options P
 			Source:    inputText,
 			ChunkSize: options.MaxTokensPerChunk,
 			Metadata: map[string]interface{}{
-				"method": options.SegmentationMethod,
+				"method":  options.SegmentationMethod,
+				"overlap": options.SegmentOverlap,
 			},
 		},
 		RunID:   runID,
@@ -460,6 +917,16 @@ func (o *DefaultOrchestrator) createModelTask(inputText string, model chain.Mode
 	return o.taskManager.CreateTask(modelTask)
 }
 
+// newRunContext создает контекст выполнения запуска, отвязанный от отмены ctx.
+// Значения ctx сохраняются, дедлайн задается options.RunTimeout.
+func newRunContext(ctx context.Context, options ProcessingOptions) (context.Context, context.CancelFunc) {
+	runCtx := context.WithoutCancel(ctx)
+	if options.RunTimeout > 0 {
+		return context.WithTimeout(runCtx, time.Duration(options.RunTimeout)*time.Second)
+	}
+	return context.WithCancel(runCtx)
+}
+
 // validateInput проверяет валидность входных данных
 func validateInput(input TaskInput) error {
 	if input.Text == "" && len(input.Files) == 0 {
@@ -480,6 +947,7 @@ func (o *DefaultOrchestrator) processChain(
 	o.mutex.Lock()
 	runMeta.Status = StatusRunning
 	o.mutex.Unlock()
+	o.persistRun(runMeta.ID)
 
 	// Выбираем текущий вход (текст для обработки)
 	currentInput := input.Text
@@ -494,6 +962,7 @@ func (o *DefaultOrchestrator) processChain(
 			runMeta.Error = "Operation cancelled"
 			runMeta.EndTime = time.Now()
 			o.mutex.Unlock()
+			o.persistRun(runMeta.ID)
 			return
 		default:
 			// Продолжаем выполнение
@@ -506,15 +975,31 @@ func (o *DefaultOrchestrator) processChain(
 		// Обновляем прогресс на основе позиции в цепочке
 		runMeta.Progress = float64(i) / float64(len(c.Models))
 		o.mutex.Unlock()
+		o.persistRun(runMeta.ID)
+
+		// Шаг со схемой результата должен ответить JSON по схеме
+		step := model
+		if len(model.OutputSchema) > 0 {
+			prompt := model.Prompt
+			if prompt == "" {
+				prompt = getDefaultPromptForRole(model.Role)
+			}
+			step.Prompt = chain.StructuredPrompt(prompt, model.OutputSchema)
+		}
 
-		// Обрабатываем текст с помощью текущей модели
-		result, err := o.processModelWithText(ctx, model, currentInput, runMeta, options)
+		// Обрабатываем текст с помощью текущей модели или ее запасных моделей
+		result, served, err := o.processStepWithFallback(ctx, step, currentInput, runMeta, options)
+		repaired := false
+		if err == nil && len(model.OutputSchema) > 0 {
+			result, served, repaired, err = o.structuredOutput(ctx, step, currentInput, result, served, runMeta, options)
+		}
 		if err != nil {
 			o.mutex.Lock()
 			runMeta.Status = StatusFailed
 			runMeta.Error = fmt.Sprintf("Error processing model '%s': %v", model.Name, err)
 			runMeta.EndTime = time.Now()
 			o.mutex.Unlock()
+			o.persistRun(runMeta.ID)
 			return
 		}
 
@@ -523,7 +1008,11 @@ func (o *DefaultOrchestrator) processChain(
 
 		// Создаем чекпоинт с промежуточным результатом
 		if options.SaveCheckpoints {
-			checkpointID, err := o.createCheckpoint(runMeta.ID, model.ID, currentInput)
+			metadata := servedByMetadata(model, served)
+			if repaired {
+				metadata["schema_repaired"] = true
+			}
+			checkpointID, err := o.createCheckpoint(runMeta.ID, model.ID, currentInput, metadata)
 			if err != nil {
 				// Логируем ошибку, но продолжаем выполнение
 				fmt.Printf("Warning: failed to create checkpoint: %v\n", err)
@@ -552,6 +1041,69 @@ func (o *DefaultOrchestrator) processChain(
 	runMeta.EndTime = time.Now()
 	runMeta.CurrentModel = ""
 	o.mutex.Unlock()
+	o.persistRun(runMeta.ID)
+}
+
+// processStepWithFallback выполняет шаг цепочки основной моделью, а если она
+// недоступна из-за временного сбоя - запасными моделями шага по порядку.
+// Возвращает результат и модель, которая его получила.
+func (o *DefaultOrchestrator) processStepWithFallback(
+	ctx context.Context,
+	step chain.Model,
+	text string,
+	runMeta *RunMetadata,
+	options ProcessingOptions,
+) (string, chain.Model, error) {
+	return model.ExecuteWithFallback(ctx, step, func(m chain.Model) (string, error) {
+		if m.Name != step.Name {
+			o.mutex.Lock()
+			runMeta.CurrentModel = string(m.Name)
+			o.mutex.Unlock()
+			o.persistRun(runMeta.ID)
+		}
+		return o.processModelWithText(ctx, m, text, runMeta, options)
+	})
+}
+
+// structuredOutput проверяет ответ шага по его схеме результата. Если ответ не
+// разбирается как JSON или нарушает схему, модели один раз отправляется
+// повторный запрос с ее ответом и найденными ошибками. Возвращает JSON без
+// окружающего текста, модель, которая его дала, и был ли нужен повторный запрос.
+func (o *DefaultOrchestrator) structuredOutput(
+	ctx context.Context,
+	step chain.Model,
+	text string,
+	output string,
+	served chain.Model,
+	runMeta *RunMetadata,
+	options ProcessingOptions,
+) (string, chain.Model, bool, error) {
+	structured, err := chain.StructuredOutput(step.OutputSchema, output)
+	if err == nil {
+		return structured, served, false, nil
+	}
+
+	repairOutput, repairedBy, execErr := o.processStepWithFallback(ctx, step, chain.RepairInput(text, output, err), runMeta, options)
+	if execErr != nil {
+		return "", served, true, fmt.Errorf("%w; repair request failed: %v", err, execErr)
+	}
+
+	structured, err = chain.StructuredOutput(step.OutputSchema, repairOutput)
+	if err != nil {
+		return "", repairedBy, true, fmt.Errorf("output does not match the step schema after a repair request: %w", err)
+	}
+	return structured, repairedBy, true, nil
+}
+
+// servedByMetadata метаданные чекпоинта шага: какая модель фактически его выполнила
+func servedByMetadata(step, served chain.Model) map[string]interface{} {
+	metadata := map[string]interface{}{
+		"served_by": string(served.Name),
+	}
+	if served.Name != step.Name {
+		metadata["fallback_from"] = string(step.Name)
+	}
+	return metadata
 }
 
 // processModelWithText обрабатывает текст с помощью модели
@@ -634,14 +1186,8 @@ func (o *DefaultOrchestrator) processLargeText(
 	runMeta *RunMetadata,
 	options ProcessingOptions,
 ) (string, error) {
-	// Создаем сегментер указанного типа
-	segmenter, err := segmentation.NewSegmenter(options.SegmentationMethod)
-	if err != nil {
-		return "", err
-	}
-
-	// Разбиваем текст на сегменты
-	segments, err := segmenter.Split(text, options.MaxTokensPerChunk)
+	// Разбиваем текст на сегменты, добавляя контекст перекрытия
+	segments, err := segmentation.SegmentWithInfo(text, options.SegmentationOptions())
 	if err != nil {
 		return "", err
 	}
@@ -658,37 +1204,41 @@ func (o *DefaultOrchestrator) processLargeText(
 
 	// Создаем канал для результатов обработки сегментов
 	type segmentResult struct {
-		segment segmentation.SegmentInfo
-		result  string
-		err     error
+		index  int
+		result string
+		err    error
 	}
 
 	resultChan := make(chan segmentResult, len(segments))
 
 	// Ограничиваем количество параллельных обработок
-	semaphore := make(chan struct{}, options.MaxParallelChunks)
+	maxParallel := options.MaxParallelChunks
+	if maxParallel <= 0 {
+		maxParallel = 1
+	}
+	semaphore := make(chan struct{}, maxParallel)
 
 	// Запускаем обработку каждого сегмента в отдельной горутине
 	var wg sync.WaitGroup
-	for _, segment := range segments {
+	for i, segment := range segments {
 		wg.Add(1)
-		go func(seg segmentation.SegmentInfo) {
+		go func(index int, seg segmentation.SegmentInfo) {
 			defer wg.Done()
 
 			// Ждем доступного слота в семафоре
 			semaphore <- struct{}{}
 			defer func() { <-semaphore }()
 
-			// Обрабатываем сегмент
-			result, err := o.processSingleChunk(ctx, model, seg.Content, runMeta)
+			// Обрабатываем сегмент вместе с контекстом перекрытия
+			result, err := o.processSingleChunk(ctx, model, seg.InputText(), runMeta)
 
 			// Отправляем результат в канал
 			resultChan <- segmentResult{
-				segment: seg,
-				result:  result,
-				err:     err,
+				index:  index,
+				result: result,
+				err:    err,
 			}
-		}(segment)
+		}(i, segment)
 	}
 
 	// Закрываем канал после завершения всех горутин
@@ -697,32 +1247,59 @@ func (o *DefaultOrchestrator) processLargeText(
 		close(resultChan)
 	}()
 
-	// Собираем результаты
-	processedSegments := make([]segmentation.SegmentInfo, len(segments))
+	// Собираем результаты в исходном порядке сегментов
+	results := make([]string, len(segments))
 	for res := range resultChan {
 		if res.err != nil {
-			return "", res.err
+			return "", fmt.Errorf("segment %d of %d: %w", res.index+1, len(segments), res.err)
 		}
+		results[res.index] = res.result
 
-		// Создаем новый сегмент с результатом обработки
-		processedSegment := res.segment
-		processedSegment.Content = res.result
-
-		// Сохраняем сегмент в массиве результатов
-		processedSegments[processedSegment.Order] = processedSegment
+		// Сохраняем результат сегмента, чтобы его не пришлось пересчитывать
+		if options.SaveCheckpoints {
+			o.saveSegmentCheckpoint(runMeta, model.ID, segments[res.index], res.index, len(segments), res.result)
+		}
 	}
 
 	// Объединяем результаты обработки сегментов
-	finalResult, err := segmenter.Merge(processedSegments)
-	if err != nil {
-		return "", err
+	return o.aggregateResults(ctx, model, results, runMeta, options)
+}
+
+// saveSegmentCheckpoint создает чекпоинт с результатом обработки одного сегмента
+func (o *DefaultOrchestrator) saveSegmentCheckpoint(runMeta *RunMetadata, modelID string, segment segmentation.SegmentInfo, index, total int, content string) {
+	if o.checkpointStore == nil {
+		return
+	}
+
+	cp := checkpoint.Checkpoint{
+		ID:        uuid.New().String(),
+		ChainID:   runMeta.ID,
+		ModelID:   modelID,
+		Type:      checkpoint.CheckpointTypeIntermediate,
+		Content:   content,
+		CreatedAt: time.Now(),
+		MetaData: map[string]interface{}{
+			"segment":        index + 1,
+			"segments_total": total,
+			"start_pos":      segment.StartPos,
+			"end_pos":        segment.EndPos,
+			"overlapping":    segment.Overlapping,
+		},
+	}
+
+	if err := o.checkpointStore.Save(cp); err != nil {
+		// Логируем ошибку, но продолжаем выполнение
+		fmt.Printf("Warning: failed to create checkpoint for segment %d: %v\n", index+1, err)
+		return
 	}
 
-	return finalResult, nil
+	o.mutex.Lock()
+	runMeta.Checkpoints = append(runMeta.Checkpoints, cp.ID)
+	o.mutex.Unlock()
 }
 
 // createCheckpoint создает чекпоинт с промежуточным результатом
-func (o *DefaultOrchestrator) createCheckpoint(runID, modelID, content string) (string, error) {
+func (o *DefaultOrchestrator) createCheckpoint(runID, modelID, content string, metadata map[string]interface{}) (string, error) {
 	checkpointID := uuid.New().String()
 
 	// Создаем чекпоинт
@@ -733,7 +1310,7 @@ func (o *DefaultOrchestrator) createCheckpoint(runID, modelID, content string) (
 		Type:      checkpoint.CheckpointTypeIntermediate,
 		Content:   content,
 		CreatedAt: time.Now(),
-		MetaData:  map[string]interface{}{},
+		MetaData:  metadata,
 	}
 
 	// Сохраняем чекпоинт
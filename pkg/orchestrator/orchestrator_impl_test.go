@@ -0,0 +1,371 @@
+package orchestrator
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grik-ai/ricochet-task/pkg/api"
+	"github.com/grik-ai/ricochet-task/pkg/chain"
+	"github.com/grik-ai/ricochet-task/pkg/checkpoint"
+	"github.com/grik-ai/ricochet-task/pkg/key"
+	"github.com/grik-ai/ricochet-task/pkg/task"
+)
+
+// blockingExecutor выполняет задачу, пока ее не отпустят или не отменят контекст
+type blockingExecutor struct {
+	started chan context.Context
+	release chan struct{}
+}
+
+func newBlockingExecutor() *blockingExecutor {
+	return &blockingExecutor{
+		started: make(chan context.Context, 1),
+		release: make(chan struct{}),
+	}
+}
+
+func (e *blockingExecutor) ExecuteTask(ctx context.Context, taskID string) error {
+	e.started <- ctx
+	select {
+	case <-e.release:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (e *blockingExecutor) CancelTask(taskID string) error {
+	return nil
+}
+
+func (e *blockingExecutor) ExecuteBatch(ctx context.Context, taskIDs []string) error {
+	return nil
+}
+
+// setupRunTest создает оркестратор с одной цепочкой из одной модели
+func setupRunTest(t *testing.T) (*DefaultOrchestrator, *blockingExecutor, string) {
+	t.Helper()
+
+	chainStore, err := chain.NewFileChainStore(t.TempDir())
+	require.NoError(t, err)
+	require.NoError(t, chainStore.Save(chain.Chain{
+		ID:   "run-test",
+		Name: "Run test",
+		Models: []chain.Model{
+			{ID: "model-1", Name: chain.ModelName("gpt-3.5-turbo"), Type: chain.ModelType("openai")},
+		},
+	}))
+
+	taskStore, err := task.NewFileTaskStore(t.TempDir())
+	require.NoError(t, err)
+
+	executor := newBlockingExecutor()
+	o := NewOrchestrator(nil, nil, chainStore, nil, task.NewTaskManager(taskStore), executor, nil)
+	return o, executor, "run-test"
+}
+
+// waitRunStatus ждет, пока запуск не перейдет в указанный статус
+func waitRunStatus(t *testing.T, o *DefaultOrchestrator, runID string, status RunStatus) *RunMetadata {
+	t.Helper()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		o.mutex.RLock()
+		metadata := *o.runs[runID]
+		o.mutex.RUnlock()
+		if metadata.Status == status {
+			return &metadata
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("run %s did not reach status %s", runID, status)
+	return nil
+}
+
+func waitStarted(t *testing.T, executor *blockingExecutor) context.Context {
+	t.Helper()
+
+	select {
+	case ctx := <-executor.started:
+		return ctx
+	case <-time.After(2 * time.Second):
+		t.Fatal("task execution did not start")
+		return nil
+	}
+}
+
+func TestRunChainContext(t *testing.T) {
+	t.Run("caller returns", func(t *testing.T) {
+		o, executor, chainID := setupRunTest(t)
+
+		type requestKey struct{}
+		callerCtx, cancelCaller := context.WithCancel(context.WithValue(context.Background(), requestKey{}, "req-1"))
+		runID, err := o.RunChain(callerCtx, chainID, TaskInput{Text: "hello"}, DefaultProcessingOptions())
+		require.NoError(t, err)
+
+		runCtx := waitStarted(t, executor)
+
+		// Контекст запроса отменяется сразу после возврата из RunChain
+		cancelCaller()
+		time.Sleep(20 * time.Millisecond)
+
+		assert.NoError(t, runCtx.Err(), "run context must not follow caller cancellation")
+		assert.Equal(t, "req-1", runCtx.Value(requestKey{}), "run context keeps caller values")
+		_, hasDeadline := runCtx.Deadline()
+		assert.True(t, hasDeadline, "run context gets a deadline from RunTimeout")
+
+		close(executor.release)
+		waitRunStatus(t, o, runID, StatusCompleted)
+
+		o.mutex.RLock()
+		assert.Empty(t, o.cancels)
+		o.mutex.RUnlock()
+	})
+
+	t.Run("explicit cancel", func(t *testing.T) {
+		o, executor, chainID := setupRunTest(t)
+
+		runID, err := o.RunChain(context.Background(), chainID, TaskInput{Text: "hello"}, DefaultProcessingOptions())
+		require.NoError(t, err)
+
+		runCtx := waitStarted(t, executor)
+		require.NoError(t, o.CancelRun(runID))
+
+		select {
+		case <-runCtx.Done():
+			assert.True(t, errors.Is(runCtx.Err(), context.Canceled))
+		case <-time.After(2 * time.Second):
+			t.Fatal("CancelRun did not cancel the run context")
+		}
+
+		metadata := waitRunStatus(t, o, runID, StatusCancelled)
+		assert.Empty(t, metadata.Error)
+		assert.False(t, metadata.EndTime.IsZero())
+	})
+
+	t.Run("run timeout", func(t *testing.T) {
+		o, executor, chainID := setupRunTest(t)
+
+		options := DefaultProcessingOptions()
+		options.RunTimeout = 1
+		runID, err := o.RunChain(context.Background(), chainID, TaskInput{Text: "hello"}, options)
+		require.NoError(t, err)
+
+		waitStarted(t, executor)
+		metadata := waitRunStatus(t, o, runID, StatusFailed)
+		assert.Contains(t, metadata.Error, "timed out after 1s")
+	})
+}
+
+func TestRunChainQueue(t *testing.T) {
+	t.Run("runs over the limit wait", func(t *testing.T) {
+		o, executor, chainID := setupRunTest(t)
+		o.SetMaxConcurrentRuns(1)
+
+		firstID, err := o.RunChain(context.Background(), chainID, TaskInput{Text: "first"}, DefaultProcessingOptions())
+		require.NoError(t, err)
+		waitStarted(t, executor)
+
+		secondID, err := o.RunChain(context.Background(), chainID, TaskInput{Text: "second"}, DefaultProcessingOptions())
+		require.NoError(t, err)
+
+		queued := waitRunStatus(t, o, secondID, StatusPending)
+		assert.Equal(t, 1, queued.QueuePosition)
+		assert.Equal(t, QueueStatus{Running: 1, Queued: 1, MaxConcurrent: 1}, o.QueueStatus())
+
+		// Второй запуск стартует только после завершения первого
+		close(executor.release)
+		waitRunStatus(t, o, firstID, StatusCompleted)
+		waitStarted(t, executor)
+		second := waitRunStatus(t, o, secondID, StatusCompleted)
+		assert.Zero(t, second.QueuePosition)
+		assert.Contains(t, second.ExtraMetadata, "queue_wait_ms")
+		assert.Equal(t, QueueStatus{MaxConcurrent: 1}, o.QueueStatus())
+	})
+
+	t.Run("cancel queued run", func(t *testing.T) {
+		o, executor, chainID := setupRunTest(t)
+		o.SetMaxConcurrentRuns(1)
+
+		firstID, err := o.RunChain(context.Background(), chainID, TaskInput{Text: "first"}, DefaultProcessingOptions())
+		require.NoError(t, err)
+		waitStarted(t, executor)
+
+		secondID, err := o.RunChain(context.Background(), chainID, TaskInput{Text: "second"}, DefaultProcessingOptions())
+		require.NoError(t, err)
+		require.NoError(t, o.CancelRun(secondID))
+
+		metadata := waitRunStatus(t, o, secondID, StatusCancelled)
+		assert.Zero(t, metadata.QueuePosition)
+		assert.Equal(t, 0, o.QueueStatus().Queued)
+
+		close(executor.release)
+		waitRunStatus(t, o, firstID, StatusCompleted)
+		select {
+		case <-executor.started:
+			t.Fatal("cancelled queued run must not start")
+		case <-time.After(50 * time.Millisecond):
+		}
+	})
+
+	t.Run("raising the limit starts waiting runs", func(t *testing.T) {
+		o, executor, chainID := setupRunTest(t)
+		o.SetMaxConcurrentRuns(1)
+
+		_, err := o.RunChain(context.Background(), chainID, TaskInput{Text: "first"}, DefaultProcessingOptions())
+		require.NoError(t, err)
+		waitStarted(t, executor)
+
+		secondID, err := o.RunChain(context.Background(), chainID, TaskInput{Text: "second"}, DefaultProcessingOptions())
+		require.NoError(t, err)
+		waitRunStatus(t, o, secondID, StatusPending)
+
+		o.SetMaxConcurrentRuns(0)
+		waitStarted(t, executor)
+		waitRunStatus(t, o, secondID, StatusRunning)
+		assert.Equal(t, QueueStatus{Running: 2}, o.QueueStatus())
+		close(executor.release)
+	})
+}
+
+// chatServer отвечает на запросы OpenAI Chat Completions ответами replies по
+// порядку и запоминает системный промпт и вход каждого запроса
+type chatServer struct {
+	replies []string
+	systems []string
+	inputs  []string
+}
+
+func (s *chatServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Messages []api.ChatMessage `json:"messages"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || len(req.Messages) != 2 {
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+	s.systems = append(s.systems, req.Messages[0].Content)
+	s.inputs = append(s.inputs, req.Messages[1].Content)
+
+	reply := s.replies[0]
+	if len(s.replies) > 1 {
+		s.replies = s.replies[1:]
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"choices": []map[string]interface{}{{"message": map[string]string{"role": "assistant", "content": reply}}},
+	})
+}
+
+// runChainWithServer выполняет цепочку из одного шага с моделью OpenAI через
+// chatServer и возвращает метаданные запуска и его итоговый результат
+func runChainWithServer(t *testing.T, server *chatServer, step chain.Model) (*RunMetadata, string) {
+	t.Helper()
+
+	httpServer := httptest.NewServer(server)
+	t.Cleanup(httpServer.Close)
+	apiClient := api.NewClient()
+	apiClient.SetBaseURL(api.ProviderOpenAI, httpServer.URL)
+
+	keyStore, err := key.NewFileKeyStore(t.TempDir())
+	require.NoError(t, err)
+	require.NoError(t, keyStore.Add(key.Key{ID: "key-1", Provider: "openai", Value: "sk-test"}))
+	checkpointStore, err := checkpoint.NewFileCheckpointStore(t.TempDir())
+	require.NoError(t, err)
+
+	o := NewOrchestrator(apiClient, keyStore, nil, checkpointStore, nil, nil, nil)
+	runMeta := &RunMetadata{ID: "run-1", Status: StatusPending}
+	o.runs[runMeta.ID] = runMeta
+
+	step.ID = "model-1"
+	step.Type = chain.ModelTypeOpenAI
+	o.processChain(context.Background(), chain.Chain{Models: []chain.Model{step}}, TaskInput{Text: "Fix the login page"}, runMeta, DefaultProcessingOptions())
+
+	if len(runMeta.Checkpoints) == 0 {
+		return runMeta, ""
+	}
+	final, err := checkpointStore.Get(runMeta.Checkpoints[len(runMeta.Checkpoints)-1])
+	require.NoError(t, err)
+	return runMeta, final.Content
+}
+
+func TestProcessChainOutputSchema(t *testing.T) {
+	schema, err := chain.ParseOutputSchema(`{
+		"type": "object",
+		"required": ["title"],
+		"properties": {"title": {"type": "string"}}
+	}`)
+	require.NoError(t, err)
+	step := chain.Model{Name: chain.ModelNameGPT4, Role: chain.ModelRoleAnalyzer, OutputSchema: schema}
+
+	t.Run("valid output is normalized", func(t *testing.T) {
+		server := &chatServer{replies: []string{"```json\n{\"title\": \"Fix login\"}\n```"}}
+
+		runMeta, output := runChainWithServer(t, server, step)
+		require.Equal(t, StatusCompleted, runMeta.Status, runMeta.Error)
+
+		require.Len(t, server.systems, 1)
+		assert.Contains(t, server.systems[0], getDefaultPromptForRole(chain.ModelRoleAnalyzer), "the role prompt is kept")
+		assert.Contains(t, server.systems[0], `"required"`)
+		assert.JSONEq(t, `{"title": "Fix login"}`, output)
+	})
+
+	t.Run("invalid output is repaired once", func(t *testing.T) {
+		server := &chatServer{replies: []string{"The task is to fix login.", `{"title": "Fix login"}`}}
+
+		runMeta, output := runChainWithServer(t, server, step)
+		require.Equal(t, StatusCompleted, runMeta.Status, runMeta.Error)
+
+		require.Len(t, server.inputs, 2)
+		assert.Contains(t, server.inputs[1], "The task is to fix login.")
+		assert.Contains(t, server.inputs[1], "Fix the login page", "the repair request repeats the step input")
+		assert.JSONEq(t, `{"title": "Fix login"}`, output)
+	})
+
+	t.Run("output still invalid after repair fails the run", func(t *testing.T) {
+		server := &chatServer{replies: []string{`{"name": "Fix login"}`}}
+
+		runMeta, _ := runChainWithServer(t, server, step)
+		assert.Equal(t, StatusFailed, runMeta.Status)
+		assert.Len(t, server.inputs, 2)
+		assert.Contains(t, runMeta.Error, "$.title")
+	})
+}
+
+func TestProcessingOptionsValidate(t *testing.T) {
+	options := DefaultProcessingOptions()
+	require.NoError(t, options.Validate())
+
+	options.SegmentOverlap = 200
+	options.SegmentationMethod = "paragraph"
+	assert.NoError(t, options.Validate())
+
+	options.SegmentOverlap = options.MaxTokensPerChunk
+	assert.Error(t, options.Validate(), "overlap larger than half a segment")
+
+	options = DefaultProcessingOptions()
+	options.SegmentationMethod = "words"
+	assert.Error(t, options.Validate())
+
+	options = DefaultProcessingOptions()
+	options.MaxTokensPerChunk = 0
+	assert.Error(t, options.Validate())
+
+	segOptions := DefaultProcessingOptions()
+	segOptions.SegmentOverlap = 100
+	converted := segOptions.SegmentationOptions()
+	assert.Equal(t, 100, converted.Overlap)
+	assert.Equal(t, segOptions.MaxTokensPerChunk, converted.ChunkSize)
+}
+
+func TestMergeSegmentResults(t *testing.T) {
+	merged := mergeSegmentResults([]string{"  first part\n", "", "second part", "third part  "})
+	assert.Equal(t, "first part\n\nsecond part\n\nthird part", merged)
+}
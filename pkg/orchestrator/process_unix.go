@@ -0,0 +1,19 @@
+//go:build !windows
+
+package orchestrator
+
+import (
+	"errors"
+	"syscall"
+)
+
+// processAlive сообщает, что на этом хосте есть процесс с указанным PID
+func processAlive(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	// Сигнал 0 только проверяет существование процесса; EPERM значит, что
+	// процесс есть, но принадлежит другому пользователю
+	err := syscall.Kill(pid, 0)
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
@@ -0,0 +1,31 @@
+//go:build windows
+
+package orchestrator
+
+import (
+	"errors"
+
+	"golang.org/x/sys/windows"
+)
+
+// stillActive код завершения процесса, который еще выполняется
+const stillActive = 259
+
+// processAlive сообщает, что на этом хосте есть процесс с указанным PID
+func processAlive(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	handle, err := windows.OpenProcess(windows.PROCESS_QUERY_LIMITED_INFORMATION, false, uint32(pid))
+	if err != nil {
+		// Процесс есть, но принадлежит другому пользователю
+		return errors.Is(err, windows.ERROR_ACCESS_DENIED)
+	}
+	defer windows.CloseHandle(handle)
+
+	var code uint32
+	if err := windows.GetExitCodeProcess(handle, &code); err != nil {
+		return true
+	}
+	return code == stillActive
+}
@@ -0,0 +1,114 @@
+package orchestrator
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// RunSortField поле, по которому упорядочиваются запуски
+type RunSortField string
+
+const (
+	SortByStartTime RunSortField = "start_time"
+	SortByDuration  RunSortField = "duration"
+	SortByTokens    RunSortField = "tokens"
+)
+
+// RunSortFields допустимые поля сортировки запусков
+var RunSortFields = []RunSortField{SortByStartTime, SortByDuration, SortByTokens}
+
+// RunFilter задает условия отбора, порядок и страницу запусков.
+// Пустые поля не ограничивают выборку.
+type RunFilter struct {
+	ChainID   string       // Только запуски цепочки
+	Status    RunStatus    // Только запуски с указанным статусом
+	Since     time.Time    // Только запуски, начатые не раньше
+	Until     time.Time    // Только запуски, начатые не позже
+	SortBy    RunSortField // Поле сортировки, по умолчанию время начала
+	Ascending bool         // По возрастанию; по умолчанию новые и самые большие первыми
+	Offset    int          // Сколько запусков пропустить
+	Limit     int          // Максимальное количество, 0 - без ограничения
+}
+
+// Validate проверяет поле сортировки и границы страницы
+func (f RunFilter) Validate() error {
+	if f.SortBy != "" && !isRunSortField(f.SortBy) {
+		return fmt.Errorf("unknown sort field %q: expected one of %v", f.SortBy, RunSortFields)
+	}
+	if f.Offset < 0 || f.Limit < 0 {
+		return fmt.Errorf("offset and limit must not be negative")
+	}
+	if !f.Since.IsZero() && !f.Until.IsZero() && f.Until.Before(f.Since) {
+		return fmt.Errorf("until %s is before since %s", f.Until.Format(time.RFC3339), f.Since.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// Matches проверяет соответствие запуска фильтру
+func (f RunFilter) Matches(run *RunMetadata) bool {
+	if f.ChainID != "" && run.ChainID != f.ChainID {
+		return false
+	}
+	if f.Status != "" && run.Status != f.Status {
+		return false
+	}
+	if !f.Since.IsZero() && run.StartTime.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && run.StartTime.After(f.Until) {
+		return false
+	}
+	return true
+}
+
+// sort упорядочивает запуски по полю фильтра. Запуски с равным значением
+// поля остаются упорядоченными по времени начала, новые первыми.
+func (f RunFilter) sort(runs []*RunMetadata) {
+	sortRunsByStartTime(runs)
+
+	var less func(a, b *RunMetadata) bool
+	switch f.SortBy {
+	case SortByDuration:
+		less = func(a, b *RunMetadata) bool { return runDuration(a) < runDuration(b) }
+	case SortByTokens:
+		less = func(a, b *RunMetadata) bool { return a.TotalTokens < b.TotalTokens }
+	default:
+		if f.Ascending {
+			sort.SliceStable(runs, func(i, j int) bool { return runs[i].StartTime.Before(runs[j].StartTime) })
+		}
+		return
+	}
+
+	sort.SliceStable(runs, func(i, j int) bool {
+		if f.Ascending {
+			return less(runs[i], runs[j])
+		}
+		return less(runs[j], runs[i])
+	})
+}
+
+// page возвращает страницу запусков согласно Offset и Limit
+func (f RunFilter) page(runs []*RunMetadata) []*RunMetadata {
+	if f.Offset >= len(runs) {
+		return []*RunMetadata{}
+	}
+	return limitRuns(runs[f.Offset:], f.Limit)
+}
+
+// runDuration длительность запуска; для незавершенного - время с начала
+func runDuration(run *RunMetadata) time.Duration {
+	if run.EndTime.IsZero() {
+		return time.Since(run.StartTime)
+	}
+	return run.EndTime.Sub(run.StartTime)
+}
+
+func isRunSortField(field RunSortField) bool {
+	for _, known := range RunSortFields {
+		if field == known {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,66 @@
+package orchestrator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindRunsFilterAndPage(t *testing.T) {
+	store, err := NewFileRunStore(t.TempDir())
+	require.NoError(t, err)
+
+	start := time.Now().Add(-time.Hour)
+	for _, run := range []*RunMetadata{
+		{ID: "old", ChainID: "a", Status: StatusCompleted, StartTime: start.AddDate(0, 0, -3), EndTime: start.AddDate(0, 0, -3).Add(time.Minute), TotalTokens: 500},
+		{ID: "slow", ChainID: "a", Status: StatusCompleted, StartTime: start.AddDate(0, 0, -2), EndTime: start.AddDate(0, 0, -2).Add(time.Hour), TotalTokens: 100},
+		{ID: "failed", ChainID: "b", Status: StatusFailed, StartTime: start.AddDate(0, 0, -1), EndTime: start.AddDate(0, 0, -1).Add(time.Second), TotalTokens: 10},
+		{ID: "new", ChainID: "a", Status: StatusCompleted, StartTime: start, EndTime: start.Add(10 * time.Minute), TotalTokens: 300},
+	} {
+		require.NoError(t, store.SaveRunMetadata(run))
+	}
+	o := NewOrchestratorWithRunStore(nil, nil, nil, nil, nil, nil, nil, store)
+
+	ids := func(filter RunFilter) []string {
+		runs, err := o.FindRuns(filter)
+		require.NoError(t, err)
+		result := []string{}
+		for _, run := range runs {
+			result = append(result, run.ID)
+		}
+		return result
+	}
+
+	t.Run("Newest first by default", func(t *testing.T) {
+		assert.Equal(t, []string{"new", "failed", "slow", "old"}, ids(RunFilter{}))
+		assert.Equal(t, []string{"old", "slow", "failed", "new"}, ids(RunFilter{Ascending: true}))
+	})
+
+	t.Run("Chain, status and date range", func(t *testing.T) {
+		assert.Equal(t, []string{"new", "slow", "old"}, ids(RunFilter{ChainID: "a"}))
+		assert.Equal(t, []string{"failed"}, ids(RunFilter{Status: StatusFailed}))
+		assert.Equal(t, []string{"failed", "slow"}, ids(RunFilter{Since: start.AddDate(0, 0, -2), Until: start.AddDate(0, 0, -1)}))
+	})
+
+	t.Run("Sort by duration and tokens", func(t *testing.T) {
+		assert.Equal(t, []string{"slow", "new", "old", "failed"}, ids(RunFilter{SortBy: SortByDuration}))
+		assert.Equal(t, []string{"failed", "slow", "new", "old"}, ids(RunFilter{SortBy: SortByTokens, Ascending: true}))
+	})
+
+	t.Run("Pages", func(t *testing.T) {
+		assert.Equal(t, []string{"new", "failed"}, ids(RunFilter{Limit: 2}))
+		assert.Equal(t, []string{"slow", "old"}, ids(RunFilter{Offset: 2, Limit: 2}))
+		assert.Empty(t, ids(RunFilter{Offset: 10}))
+	})
+
+	t.Run("Invalid filters", func(t *testing.T) {
+		_, err := o.FindRuns(RunFilter{SortBy: "name"})
+		assert.Error(t, err)
+		_, err = o.FindRuns(RunFilter{Offset: -1})
+		assert.Error(t, err)
+		_, err = o.FindRuns(RunFilter{Since: start, Until: start.AddDate(0, 0, -1)})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,138 @@
+package orchestrator
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// RunHeartbeatInterval как часто процесс обновляет heartbeat своих
+	// незавершенных запусков
+	RunHeartbeatInterval = 30 * time.Second
+
+	// RunOwnerTimeout сколько запуск может не обновлять heartbeat, прежде чем
+	// его владелец с другого хоста считается остановленным
+	RunOwnerTimeout = 10 * time.Minute
+)
+
+// RunOwner процесс, который выполняет запуск. Историю запусков читают и CLI,
+// и MCP-сервер, поэтому прерванным запуск считается, только когда его
+// владелец точно остановился.
+type RunOwner struct {
+	PID       int       `json:"pid"`
+	Host      string    `json:"host"`
+	Instance  string    `json:"instance"`  // Уникален для каждого старта процесса, отличает повторно выданный PID
+	Heartbeat time.Time `json:"heartbeat"` // Последнее подтверждение, что владелец жив
+}
+
+// newRunOwner описывает текущий процесс
+func newRunOwner() RunOwner {
+	host, _ := os.Hostname()
+	return RunOwner{PID: os.Getpid(), Host: host, Instance: uuid.New().String()}
+}
+
+// ownerStopped сообщает, что владелец запуска больше не может его выполнять.
+// На этом хосте это проверяется по PID; владелец с другого хоста считается
+// остановленным, если не обновлял heartbeat дольше RunOwnerTimeout. Запуски
+// без владельца записаны версиями без heartbeat, для них отсчет идет от старта.
+func (o *DefaultOrchestrator) ownerStopped(run *RunMetadata, now time.Time) bool {
+	owner := run.Owner
+	switch {
+	case owner == nil:
+		return now.Sub(run.StartTime) > RunOwnerTimeout
+	case owner.Instance == o.owner.Instance:
+		return false
+	case owner.Host == o.owner.Host:
+		// PID текущего процесса, выданный повторно, значит прежний владелец завершился
+		return owner.PID == o.owner.PID || !processAlive(owner.PID)
+	default:
+		return now.Sub(owner.Heartbeat) > RunOwnerTimeout
+	}
+}
+
+// RecoverInterruptedRuns помечает незавершенные запуски, владельцы которых
+// остановились, как failed с ошибкой ErrRunInterrupted и возвращает их ID.
+// Оркестратор вызывает его сам перед первым запуском цепочки; команды,
+// которые только читают историю, его не вызывают.
+func (o *DefaultOrchestrator) RecoverInterruptedRuns() ([]string, error) {
+	if o.runStore == nil {
+		return nil, nil
+	}
+
+	// Читаем хранилище заново: загруженные при старте данные могли устареть
+	runs, err := o.runStore.ListAllRuns(0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load stored runs: %w", err)
+	}
+
+	now := time.Now()
+	var interrupted []string
+	o.mutex.Lock()
+	for _, run := range runs {
+		if isRunFinished(run.Status) || o.cancels[run.ID] != nil || !o.ownerStopped(run, now) {
+			continue
+		}
+		// Время завершения неизвестно, поэтому EndTime остается пустым
+		run.Status = StatusFailed
+		run.Error = ErrRunInterrupted.Error()
+		run.QueuePosition = 0
+		run.CurrentModel = ""
+		o.runs[run.ID] = run
+		interrupted = append(interrupted, run.ID)
+	}
+	o.mutex.Unlock()
+
+	for _, runID := range interrupted {
+		o.persistRun(runID)
+	}
+	return interrupted, nil
+}
+
+// startOwnerDuties один раз за время жизни процесса восстанавливает прерванные
+// запуски и начинает обновлять heartbeat запусков этого процесса
+func (o *DefaultOrchestrator) startOwnerDuties() {
+	o.ownerOnce.Do(func() {
+		if o.runStore == nil {
+			return
+		}
+		if _, err := o.RecoverInterruptedRuns(); err != nil {
+			fmt.Printf("Warning: failed to recover interrupted runs: %v\n", err)
+		}
+		go o.heartbeatLoop(RunHeartbeatInterval)
+	})
+}
+
+// heartbeatLoop периодически подтверждает, что процесс жив и выполняет свои запуски
+func (o *DefaultOrchestrator) heartbeatLoop(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		o.beat(time.Now())
+	}
+}
+
+// beat обновляет heartbeat незавершенных запусков этого процесса и сохраняет их
+func (o *DefaultOrchestrator) beat(now time.Time) {
+	var owned []string
+	o.mutex.Lock()
+	for runID, run := range o.runs {
+		if run.Owner == nil || run.Owner.Instance != o.owner.Instance || isRunFinished(run.Status) {
+			continue
+		}
+		// Сохраненные копии метаданных ссылаются на прежнего владельца,
+		// поэтому он заменяется, а не изменяется на месте
+		owner := *run.Owner
+		owner.Heartbeat = now
+		run.Owner = &owner
+		owned = append(owned, runID)
+	}
+	o.mutex.Unlock()
+
+	for _, runID := range owned {
+		o.persistRun(runID)
+	}
+}
@@ -0,0 +1,199 @@
+package orchestrator
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/grik-ai/ricochet-task/pkg/chain"
+	"github.com/grik-ai/ricochet-task/pkg/key"
+	"github.com/grik-ai/ricochet-task/pkg/model"
+	"github.com/grik-ai/ricochet-task/pkg/segmentation"
+)
+
+// StreamEventType тип события потокового запуска цепочки
+type StreamEventType string
+
+const (
+	StreamEventModelStarted  StreamEventType = "model_started"  // Модель начала обработку сегмента
+	StreamEventChunk         StreamEventType = "chunk"          // Фрагмент ответа модели
+	StreamEventModelFinished StreamEventType = "model_finished" // Модель закончила обработку сегмента
+	StreamEventFallback      StreamEventType = "fallback"       // Шаг продолжает запасная модель (Model), ответ начинается заново
+)
+
+// StreamEvent событие потокового запуска цепочки. Номера сегмента и модели
+// начинаются с 1.
+type StreamEvent struct {
+	Type     StreamEventType
+	Segment  int
+	Segments int
+	Step     int // Номер модели в цепочке
+	Steps    int
+	Model    chain.Model
+	Chunk    string // Только для StreamEventChunk
+}
+
+// StreamHandler получает события потокового запуска по порядку
+type StreamHandler func(event StreamEvent)
+
+// RunChainStream выполняет цепочку, передавая ответы моделей обработчику по
+// мере генерации. Выход каждой модели передается на вход следующей; сегменты
+// обрабатываются по очереди, их результаты склеиваются, поэтому поддерживается
+// только объединение concatenate. Если модель шага недоступна из-за временного
+// сбоя, шаг выполняют его запасные модели, о чем сообщает StreamEventFallback.
+// Возвращает итоговый результат цепочки.
+func RunChainStream(ctx context.Context, factory *model.ProviderFactory, c chain.Chain, input string, options ProcessingOptions, handle StreamHandler) (string, error) {
+	segments, err := segmentation.SegmentWithInfo(input, options.SegmentationOptions())
+	if err != nil {
+		return "", fmt.Errorf("failed to segment input: %w", err)
+	}
+	if len(segments) > 1 && options.AggregationStrategy != AggregationConcatenate {
+		return "", fmt.Errorf("%w: streamed runs of %d segments support only the %s aggregation strategy, got %s", ErrInvalidInput, len(segments), AggregationConcatenate, options.AggregationStrategy)
+	}
+	if handle == nil {
+		handle = func(StreamEvent) {}
+	}
+
+	models := append([]chain.Model(nil), c.Models...)
+	sort.SliceStable(models, func(i, j int) bool { return models[i].Order < models[j].Order })
+
+	results := make([]string, 0, len(segments))
+	for i, segment := range segments {
+		text := segment.InputText()
+		for j, m := range models {
+			event := StreamEvent{Segment: i + 1, Segments: len(segments), Step: j + 1, Steps: len(models), Model: m}
+
+			event.Type = StreamEventModelStarted
+			handle(event)
+
+			onChunk := func(chunk string) {
+				chunkEvent := event
+				chunkEvent.Type = StreamEventChunk
+				chunkEvent.Chunk = chunk
+				handle(chunkEvent)
+			}
+
+			// Шаг со схемой результата должен ответить JSON по схеме
+			step := m
+			if len(m.OutputSchema) > 0 {
+				step.Prompt = chain.StructuredPrompt(m.Prompt, m.OutputSchema)
+			}
+
+			// При временном сбое модели шаг выполняют его запасные модели
+			streamStep := func(input string) (string, error) {
+				output, _, err := model.ExecuteWithFallback(ctx, step, func(served chain.Model) (string, error) {
+					event.Model = served
+					if served.Name != step.Name {
+						fallbackEvent := event
+						fallbackEvent.Type = StreamEventFallback
+						handle(fallbackEvent)
+					}
+					return streamModel(ctx, factory, served, input, onChunk)
+				})
+				return output, err
+			}
+
+			stepInput := text
+			text, err = streamStep(stepInput)
+			if err == nil && len(m.OutputSchema) > 0 {
+				text, err = streamStructuredOutput(step, stepInput, text, streamStep, onChunk)
+			}
+			if err != nil {
+				return "", fmt.Errorf("model %s: %w", m.Name, err)
+			}
+
+			event.Type = StreamEventModelFinished
+			handle(event)
+		}
+		results = append(results, text)
+	}
+
+	return strings.Join(results, "\n\n"), nil
+}
+
+// streamStructuredOutput проверяет ответ шага по его схеме результата. Если
+// ответ не прошел проверку, модели один раз отправляется повторный запрос
+// через streamStep с ее ответом и найденными ошибками; его ответ тоже
+// передается в onChunk.
+func streamStructuredOutput(m chain.Model, input, output string, streamStep func(input string) (string, error), onChunk func(string)) (string, error) {
+	structured, err := chain.StructuredOutput(m.OutputSchema, output)
+	if err == nil {
+		return structured, nil
+	}
+
+	onChunk("\n")
+	repaired, streamErr := streamStep(chain.RepairInput(input, output, err))
+	if streamErr != nil {
+		return "", fmt.Errorf("%w; repair request failed: %v", err, streamErr)
+	}
+
+	structured, err = chain.StructuredOutput(m.OutputSchema, repaired)
+	if err != nil {
+		return "", fmt.Errorf("output does not match the step schema after a repair request: %w", err)
+	}
+	return structured, nil
+}
+
+// streamModel выполняет запрос к модели, передавая фрагменты ответа в onChunk
+func streamModel(ctx context.Context, factory *model.ProviderFactory, m chain.Model, input string, onChunk func(string)) (string, error) {
+	provider, err := factory.GetProviderForModel(m)
+	if err != nil {
+		return "", fmt.Errorf("no API key found for provider %s: %w", m.Type, err)
+	}
+
+	options := map[string]interface{}{
+		"system_prompt": m.Prompt,
+	}
+
+	chunks, err := provider.ExecuteStream(ctx, m, input, options)
+	if err != nil {
+		return "", err
+	}
+
+	var output strings.Builder
+	var streamErr error
+	for chunk := range chunks {
+		if chunk.Err != nil {
+			streamErr = chunk.Err
+			continue
+		}
+		onChunk(chunk.Text)
+		output.WriteString(chunk.Text)
+	}
+
+	if ctx.Err() != nil {
+		return "", fmt.Errorf("run aborted: %w", ctx.Err())
+	}
+	// Оборванный ответ не передается следующей модели как полный
+	if streamErr != nil {
+		return "", fmt.Errorf("response stream interrupted: %w", streamErr)
+	}
+
+	return output.String(), nil
+}
+
+// NewModelFactory регистрирует провайдеров моделей для сохраненных API-ключей
+func NewModelFactory(configDir string) (*model.ProviderFactory, error) {
+	keyStore, err := key.NewFileKeyStore(configDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create key store: %w", err)
+	}
+
+	keys, err := keyStore.List()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list API keys: %w", err)
+	}
+
+	factory := model.NewProviderFactory()
+	for _, k := range keys {
+		switch k.Provider {
+		case "openai":
+			factory.RegisterProvider(model.NewOpenAIProvider(k.Value, ""))
+		case "claude", "anthropic":
+			factory.RegisterProvider(model.NewAnthropicProvider(k.Value, ""))
+		}
+	}
+
+	return factory, nil
+}
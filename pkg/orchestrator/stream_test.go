@@ -0,0 +1,141 @@
+package orchestrator
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/grik-ai/ricochet-task/pkg/chain"
+	"github.com/grik-ai/ricochet-task/pkg/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// upperStreamProvider отвечает входом модели в верхнем регистре, по словам
+type upperStreamProvider struct {
+	*model.BaseProvider
+}
+
+func (p *upperStreamProvider) ExecuteStream(ctx context.Context, m chain.Model, prompt string, options map[string]interface{}) (<-chan model.StreamChunk, error) {
+	words := strings.SplitAfter(strings.ToUpper(prompt), " ")
+	chunks := make(chan model.StreamChunk, len(words))
+	for _, word := range words {
+		chunks <- model.StreamChunk{Text: word}
+	}
+	close(chunks)
+	return chunks, nil
+}
+
+// cutStreamProvider обрывает ответ ошибкой после первого фрагмента
+type cutStreamProvider struct {
+	*model.BaseProvider
+}
+
+func (p *cutStreamProvider) ExecuteStream(ctx context.Context, m chain.Model, prompt string, options map[string]interface{}) (<-chan model.StreamChunk, error) {
+	chunks := make(chan model.StreamChunk, 2)
+	chunks <- model.StreamChunk{Text: "HALF"}
+	chunks <- model.StreamChunk{Err: &model.APIError{StatusCode: 529, Message: "Overloaded"}}
+	close(chunks)
+	return chunks, nil
+}
+
+func TestRunChainStream(t *testing.T) {
+	factory := model.NewProviderFactory()
+	factory.RegisterProvider(&upperStreamProvider{model.NewBaseProvider(chain.ModelTypeOpenAI, "", "")})
+
+	c := chain.Chain{Name: "test", Models: []chain.Model{
+		{Name: "second", Type: chain.ModelTypeOpenAI, Order: 2},
+		{Name: "first", Type: chain.ModelTypeOpenAI, Order: 1},
+	}}
+
+	var events []StreamEvent
+	var streamed strings.Builder
+	result, err := RunChainStream(context.Background(), factory, c, "hello stream world", DefaultProcessingOptions(), func(event StreamEvent) {
+		events = append(events, event)
+		if event.Type == StreamEventChunk {
+			streamed.WriteString(event.Chunk)
+		}
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "HELLO STREAM WORLD", result)
+	assert.Equal(t, "HELLO STREAM WORLDHELLO STREAM WORLD", streamed.String())
+
+	require.Len(t, events, 10)
+	assert.Equal(t, StreamEventModelStarted, events[0].Type)
+	assert.Equal(t, chain.ModelName("first"), events[0].Model.Name)
+	assert.Equal(t, 1, events[0].Step)
+	assert.Equal(t, 2, events[0].Steps)
+	assert.Equal(t, 1, events[0].Segments)
+	assert.Equal(t, StreamEventModelFinished, events[4].Type)
+	assert.Equal(t, chain.ModelName("second"), events[5].Model.Name)
+	assert.Equal(t, StreamEventModelFinished, events[9].Type)
+
+	t.Run("Missing provider", func(t *testing.T) {
+		c := chain.Chain{Models: []chain.Model{{Name: "claude-3", Type: chain.ModelTypeClaude}}}
+		_, err := RunChainStream(context.Background(), factory, c, "hello", DefaultProcessingOptions(), nil)
+		assert.ErrorContains(t, err, "no API key found for provider claude")
+	})
+
+	t.Run("A mid-stream error stops the chain", func(t *testing.T) {
+		factory := model.NewProviderFactory()
+		factory.RegisterProvider(&upperStreamProvider{model.NewBaseProvider(chain.ModelTypeOpenAI, "", "")})
+		factory.RegisterProvider(&cutStreamProvider{model.NewBaseProvider(chain.ModelTypeClaude, "", "")})
+
+		c := chain.Chain{Models: []chain.Model{
+			{Name: "cut", Type: chain.ModelTypeClaude, Order: 1},
+			{Name: "next", Type: chain.ModelTypeOpenAI, Order: 2},
+		}}
+		var started []chain.ModelName
+		result, err := RunChainStream(context.Background(), factory, c, "hello", DefaultProcessingOptions(), func(event StreamEvent) {
+			if event.Type == StreamEventModelStarted {
+				started = append(started, event.Model.Name)
+			}
+		})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "Overloaded")
+		assert.Empty(t, result)
+		assert.Equal(t, []chain.ModelName{"cut"}, started, "the truncated answer isn't passed on")
+	})
+
+	t.Run("A transient failure streams the answer of a fallback model", func(t *testing.T) {
+		factory := model.NewProviderFactory()
+		factory.RegisterProvider(&upperStreamProvider{model.NewBaseProvider(chain.ModelTypeOpenAI, "", "")})
+		factory.RegisterProvider(&cutStreamProvider{model.NewBaseProvider(chain.ModelTypeClaude, "", "")})
+
+		c := chain.Chain{Models: []chain.Model{
+			{Name: chain.ModelNameClaude3Sonnet, Type: chain.ModelTypeClaude, Order: 1, FallbackModels: []chain.ModelName{chain.ModelNameGPT4Turbo}},
+		}}
+		var events []StreamEvent
+		var streamed strings.Builder
+		result, err := RunChainStream(context.Background(), factory, c, "hello", DefaultProcessingOptions(), func(event StreamEvent) {
+			events = append(events, event)
+			if event.Type == StreamEventChunk {
+				streamed.WriteString(event.Chunk)
+			}
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "HELLO", result, "only the fallback's answer is passed on")
+		assert.Equal(t, "HALFHELLO", streamed.String())
+
+		var fallbacks []chain.ModelName
+		for _, event := range events {
+			if event.Type == StreamEventFallback {
+				fallbacks = append(fallbacks, event.Model.Name)
+			}
+		}
+		assert.Equal(t, []chain.ModelName{chain.ModelNameGPT4Turbo}, fallbacks)
+		last := events[len(events)-1]
+		assert.Equal(t, StreamEventModelFinished, last.Type)
+		assert.Equal(t, chain.ModelNameGPT4Turbo, last.Model.Name)
+	})
+
+	t.Run("Only concatenate for several segments", func(t *testing.T) {
+		options := DefaultProcessingOptions()
+		options.MaxTokensPerChunk = 5
+		options.AggregationStrategy = AggregationSummarize
+		_, err := RunChainStream(context.Background(), factory, c, strings.Repeat("many words here. ", 50), options, nil)
+		assert.ErrorIs(t, err, ErrInvalidInput)
+		assert.ErrorContains(t, err, "streamed runs of")
+		assert.ErrorContains(t, err, AggregationConcatenate)
+	})
+}
@@ -0,0 +1,226 @@
+package providers
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ActivityLogFile is the file name of the local task activity log in a config directory
+const ActivityLogFile = "task_activity.jsonl"
+
+// Sources of activity entries
+const (
+	ActivitySourceProvider = "provider" // The provider's change log
+	ActivitySourceLocal    = "local"    // Changes made through ricochet, from the local activity log
+)
+
+// Activity fields with the same meaning across providers. Other fields keep
+// the provider's name.
+const (
+	ActivityFieldCreated     = "created"
+	ActivityFieldUpdated     = "updated" // A change the entry doesn't describe in detail
+	ActivityFieldDeleted     = "deleted"
+	ActivityFieldTitle       = "title"
+	ActivityFieldDescription = "description"
+	ActivityFieldStatus      = "status"
+	ActivityFieldPriority    = "priority"
+	ActivityFieldAssignee    = "assignee"
+	ActivityFieldType        = "type"
+	ActivityFieldLabels      = "labels"
+	ActivityFieldComment     = "comment"
+	ActivityFieldAttachment  = "attachment"
+	ActivityFieldLinks       = "links"
+)
+
+// ActivityEntry is one change in the history of a task
+type ActivityEntry struct {
+	Actor  string    `json:"actor,omitempty"`
+	Field  string    `json:"field"`
+	From   string    `json:"from,omitempty"`
+	To     string    `json:"to,omitempty"`
+	At     time.Time `json:"at"`
+	Source string    `json:"source,omitempty"`
+}
+
+// ActivityProvider is implemented by providers that keep a change log of tasks
+type ActivityProvider interface {
+	GetActivity(ctx context.Context, taskID string) ([]ActivityEntry, error)
+}
+
+// GetTaskActivity returns the history of a task, oldest change first. Providers
+// without a change log get the changes made through ricochet from log instead.
+func GetTaskActivity(ctx context.Context, provider TaskProvider, providerName, taskID string, log *ActivityLog) ([]ActivityEntry, error) {
+	if activity, ok := ProviderAs[ActivityProvider](provider); ok {
+		entries, err := activity.GetActivity(ctx, taskID)
+		if err == nil {
+			for i := range entries {
+				entries[i].Source = ActivitySourceProvider
+			}
+			sortActivity(entries)
+			return entries, nil
+		}
+		if !IsUnsupportedError(err) {
+			return nil, fmt.Errorf("failed to get activity of task %s: %w", taskID, err)
+		}
+	}
+
+	if log == nil {
+		return nil, NewUnsupportedError(providerName, CapabilityActivity)
+	}
+	return log.Entries(providerName, taskID)
+}
+
+func sortActivity(entries []ActivityEntry) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].At.Before(entries[j].At)
+	})
+}
+
+// activityRecord is a line of the local activity log
+type activityRecord struct {
+	Provider string `json:"provider"`
+	TaskID   string `json:"taskId"`
+	ActivityEntry
+}
+
+// ActivityLog records task changes made through ricochet in an append-only
+// JSON lines file, so that tasks of providers without a change log still have
+// a history. Only the new values are known, since events don't carry the old
+// ones.
+type ActivityLog struct {
+	mu    sync.Mutex
+	path  string
+	actor string // Recorded for events that don't name their actor
+}
+
+// NewActivityLog creates an activity log backed by the given file. Changes
+// without an actor are attributed to the local user.
+func NewActivityLog(path string) *ActivityLog {
+	log := &ActivityLog{path: path}
+	if current, err := user.Current(); err == nil {
+		log.actor = current.Username
+	}
+	return log
+}
+
+// SubscribeActivityLog records the task events published to bus in log
+func SubscribeActivityLog(bus *EventBus, log *ActivityLog) error {
+	return bus.Subscribe("activity-log", log.HandleEvent,
+		EventTypeTaskCreated, EventTypeTaskUpdated, EventTypeTaskDeleted)
+}
+
+// HandleEvent records a task event. It is an EventCallback.
+func (l *ActivityLog) HandleEvent(event *UniversalEvent) error {
+	entries := activityFromEvent(event)
+	if len(entries) == 0 {
+		return nil
+	}
+
+	var data []byte
+	for _, entry := range entries {
+		if entry.Actor == "" {
+			entry.Actor = l.actor
+		}
+		line, err := json.Marshal(activityRecord{Provider: event.Source, TaskID: event.TaskID, ActivityEntry: entry})
+		if err != nil {
+			return fmt.Errorf("failed to marshal activity: %w", err)
+		}
+		data = append(append(data, line...), '\n')
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(l.path), 0755); err != nil {
+		return fmt.Errorf("failed to create activity log directory: %w", err)
+	}
+	file, err := os.OpenFile(l.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open activity log: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(data); err != nil {
+		return fmt.Errorf("failed to write activity log: %w", err)
+	}
+	return nil
+}
+
+// activityFromEvent turns a task event into an entry per changed field
+func activityFromEvent(event *UniversalEvent) []ActivityEntry {
+	if event == nil || event.TaskID == "" {
+		return nil
+	}
+
+	actor, _ := event.Data["actor"].(string)
+	entry := func(field string, value interface{}) ActivityEntry {
+		to := ""
+		if value != nil {
+			to = fmt.Sprint(value)
+		}
+		return ActivityEntry{Actor: actor, Field: field, To: to, At: event.Timestamp, Source: ActivitySourceLocal}
+	}
+
+	switch event.Type {
+	case EventTypeTaskCreated:
+		return []ActivityEntry{entry(ActivityFieldCreated, event.Data["title"])}
+	case EventTypeTaskDeleted:
+		return []ActivityEntry{entry(ActivityFieldDeleted, nil)}
+	case EventTypeTaskUpdated:
+		var entries []ActivityEntry
+		for _, field := range []string{ActivityFieldTitle, ActivityFieldStatus, ActivityFieldPriority, ActivityFieldAssignee} {
+			if value, ok := event.Data[field]; ok {
+				entries = append(entries, entry(field, value))
+			}
+		}
+		if len(entries) == 0 {
+			// Fields the event doesn't describe, e.g. the description
+			entries = append(entries, entry(ActivityFieldUpdated, nil))
+		}
+		return entries
+	}
+	return nil
+}
+
+// Entries returns the recorded changes of a task, oldest first
+func (l *ActivityLog) Entries(providerName, taskID string) ([]ActivityEntry, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	file, err := os.Open(l.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []ActivityEntry{}, nil
+		}
+		return nil, fmt.Errorf("failed to read activity log: %w", err)
+	}
+	defer file.Close()
+
+	entries := []ActivityEntry{}
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var record activityRecord
+		// A line cut short by an interrupted write only loses that change
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			continue
+		}
+		if record.Provider == providerName && record.TaskID == taskID {
+			entries = append(entries, record.ActivityEntry)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read activity log: %w", err)
+	}
+
+	sortActivity(entries)
+	return entries, nil
+}
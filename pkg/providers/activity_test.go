@@ -0,0 +1,102 @@
+package providers
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// activityProvider has a change log, or reports it unsupported
+type activityProvider struct {
+	TaskProvider
+	entries     []ActivityEntry
+	unsupported bool
+}
+
+func (p *activityProvider) GetActivity(ctx context.Context, taskID string) ([]ActivityEntry, error) {
+	if p.unsupported {
+		return nil, NewUnsupportedError("plain", CapabilityActivity)
+	}
+	return p.entries, nil
+}
+
+func TestActivityLog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ActivityLogFile)
+	log := NewActivityLog(path)
+	log.actor = "alice"
+
+	bus := NewEventBus(10, nil)
+	require.NoError(t, SubscribeActivityLog(bus, log))
+
+	start := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+	bus.Publish(&UniversalEvent{Type: EventTypeTaskCreated, Source: "yt", TaskID: "OPS-1", Timestamp: start,
+		Data: map[string]interface{}{"title": "Rotate certificates", "status": "Open"}})
+	bus.Publish(&UniversalEvent{Type: EventTypeTaskUpdated, Source: "yt", TaskID: "OPS-1", Timestamp: start.Add(time.Hour),
+		Data: map[string]interface{}{"priority": "high", "status": "In Progress", "actor": "bob"}})
+	bus.Publish(&UniversalEvent{Type: EventTypeTaskStatusChanged, Source: "yt", TaskID: "OPS-1", Timestamp: start.Add(time.Hour),
+		Data: map[string]interface{}{"status": "In Progress"}})
+	bus.Publish(&UniversalEvent{Type: EventTypeTaskUpdated, Source: "yt", TaskID: "OPS-2", Timestamp: start.Add(2 * time.Hour)})
+	bus.Publish(&UniversalEvent{Type: EventTypeTaskDeleted, Source: "jira", TaskID: "OPS-1", Timestamp: start.Add(3 * time.Hour)})
+	require.NoError(t, bus.Close(context.Background()))
+
+	t.Run("Entries of one task", func(t *testing.T) {
+		entries, err := NewActivityLog(path).Entries("yt", "OPS-1")
+		require.NoError(t, err)
+		require.Len(t, entries, 3)
+
+		assert.Equal(t, ActivityEntry{Actor: "alice", Field: ActivityFieldCreated, To: "Rotate certificates", At: start, Source: ActivitySourceLocal}, entries[0])
+		assert.Equal(t, ActivityFieldStatus, entries[1].Field)
+		assert.Equal(t, "In Progress", entries[1].To)
+		assert.Equal(t, ActivityFieldPriority, entries[2].Field)
+		assert.Equal(t, "bob", entries[2].Actor)
+
+		entries, err = log.Entries("yt", "OPS-2")
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		assert.Equal(t, ActivityFieldUpdated, entries[0].Field)
+	})
+
+	t.Run("Damaged lines are skipped", func(t *testing.T) {
+		file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0600)
+		require.NoError(t, err)
+		_, err = file.WriteString(`{"provider":"yt","taskId":"OPS-1","fie`)
+		require.NoError(t, err)
+		require.NoError(t, file.Close())
+
+		entries, err := log.Entries("yt", "OPS-1")
+		require.NoError(t, err)
+		assert.Len(t, entries, 3)
+
+		entries, err = NewActivityLog(filepath.Join(t.TempDir(), ActivityLogFile)).Entries("yt", "OPS-1")
+		require.NoError(t, err)
+		assert.Empty(t, entries)
+	})
+
+	t.Run("Provider change log wins", func(t *testing.T) {
+		provider := &activityProvider{entries: []ActivityEntry{
+			{Actor: "carol", Field: ActivityFieldPriority, From: "normal", To: "high", At: start.Add(time.Hour)},
+			{Actor: "carol", Field: ActivityFieldCreated, At: start},
+		}}
+		entries, err := GetTaskActivity(context.Background(), &wrappedProvider{provider}, "yt", "OPS-1", log)
+		require.NoError(t, err)
+		require.Len(t, entries, 2)
+		assert.Equal(t, ActivityFieldCreated, entries[0].Field)
+		assert.Equal(t, ActivitySourceProvider, entries[1].Source)
+	})
+
+	t.Run("Falls back to the local log", func(t *testing.T) {
+		for _, provider := range []TaskProvider{&tasksOnlyProvider{}, &activityProvider{unsupported: true}} {
+			entries, err := GetTaskActivity(context.Background(), provider, "yt", "OPS-1", log)
+			require.NoError(t, err)
+			assert.Len(t, entries, 3)
+		}
+
+		_, err := GetTaskActivity(context.Background(), &tasksOnlyProvider{}, "yt", "OPS-1", nil)
+		assert.True(t, IsUnsupportedError(err))
+	})
+}
@@ -0,0 +1,321 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrAIBudgetExceeded is returned by AIScheduler.Wait once a daily or monthly
+// request, token or cost limit is used up
+var ErrAIBudgetExceeded = errors.New("AI budget exceeded")
+
+const (
+	aiBudgetDay   = 24 * time.Hour
+	aiBudgetMonth = 30 * aiBudgetDay
+)
+
+// AIUsage is the token count and cost of one AI request
+type AIUsage struct {
+	Tokens int
+	Cost   float64
+}
+
+type aiUsageRecord struct {
+	at    time.Time
+	usage AIUsage
+}
+
+// AIScheduler paces the AI requests of bulk operations, such as triaging many
+// tasks, so they stay within the limits of the aiChains configuration: the
+// request rate of RateLimit and the TokenLimits and CostLimits. Requests wait
+// for the rate and the hourly limits; daily and monthly limits can't be waited
+// out, so Wait fails with ErrAIBudgetExceeded once they are used up. Limits
+// apply to the requests made through the scheduler. A nil scheduler doesn't
+// limit anything.
+type AIScheduler struct {
+	rateLimit *RateLimitConfig
+	tokens    *TokenLimits
+	cost      *CostLimits
+
+	now   func() time.Time
+	sleep func(ctx context.Context, d time.Duration) error
+
+	mu       sync.Mutex
+	requests []time.Time
+	usage    []aiUsageRecord
+	paused   bool
+	resumed  chan struct{}
+	onWait   func(delay time.Duration, reason string)
+}
+
+// NewAIScheduler creates a scheduler for the limits of an aiChains
+// configuration, which may be nil
+func NewAIScheduler(config *AIChainConfig) *AIScheduler {
+	s := &AIScheduler{now: time.Now, sleep: sleepContext}
+	if config != nil {
+		s.rateLimit = config.RateLimit
+		s.tokens = config.TokenLimits
+		s.cost = config.CostLimits
+	}
+	return s
+}
+
+// Limited reports whether any limit is configured
+func (s *AIScheduler) Limited() bool {
+	return s != nil && (s.rateLimit != nil || s.tokens != nil || s.cost != nil)
+}
+
+// SetWaitHandler sets a function that is told whenever a request has to wait
+// for a limit, e.g. to show it in a progress display
+func (s *AIScheduler) SetWaitHandler(handler func(delay time.Duration, reason string)) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.onWait = handler
+}
+
+// Pause holds back the requests that haven't started yet until Resume
+func (s *AIScheduler) Pause() {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.paused {
+		s.paused = true
+		s.resumed = make(chan struct{})
+	}
+}
+
+// Resume lets paused requests continue
+func (s *AIScheduler) Resume() {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.paused {
+		s.paused = false
+		close(s.resumed)
+	}
+}
+
+// Paused reports whether the scheduler is paused
+func (s *AIScheduler) Paused() bool {
+	if s == nil {
+		return false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.paused
+}
+
+// Wait blocks until the next request may start without exceeding the rate
+// and hourly limits, or while the scheduler is paused. It fails with
+// ErrAIBudgetExceeded if a daily or monthly limit is used up, and with the
+// context's error if it is done first.
+func (s *AIScheduler) Wait(ctx context.Context) error {
+	if s == nil {
+		return ctx.Err()
+	}
+
+	for {
+		s.mu.Lock()
+		if s.paused {
+			resumed := s.resumed
+			s.mu.Unlock()
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-resumed:
+			}
+			continue
+		}
+
+		now := s.now()
+		s.prune(now)
+		if err := s.checkBudget(now); err != nil {
+			s.mu.Unlock()
+			return err
+		}
+		delay, reason := s.delay(now)
+		if delay <= 0 {
+			s.requests = append(s.requests, now)
+			s.mu.Unlock()
+			return ctx.Err()
+		}
+		onWait := s.onWait
+		s.mu.Unlock()
+
+		if onWait != nil {
+			onWait(delay, reason)
+		}
+		if err := s.sleep(ctx, delay); err != nil {
+			return err
+		}
+	}
+}
+
+// Record adds the usage of a finished request to the token and cost limits
+func (s *AIScheduler) Record(usage AIUsage) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.usage = append(s.usage, aiUsageRecord{at: s.now(), usage: usage})
+}
+
+// TriageSuggester paces the requests of a triage suggester
+func (s *AIScheduler) TriageSuggester(suggest TriageSuggester) TriageSuggester {
+	return func(ctx context.Context, task *UniversalTask, team *TriageTeam) (*TriageSuggestion, error) {
+		if err := s.Wait(ctx); err != nil {
+			return nil, err
+		}
+		return suggest(ctx, task, team)
+	}
+}
+
+// delay returns how long the next request has to wait and for which limit
+func (s *AIScheduler) delay(now time.Time) (time.Duration, string) {
+	var delay time.Duration
+	reason := ""
+	wait := func(d time.Duration, why string) {
+		if d > delay {
+			delay, reason = d, why
+		}
+	}
+
+	if interval := s.requestInterval(); interval > 0 && len(s.requests) > 0 {
+		wait(s.requests[len(s.requests)-1].Add(interval).Sub(now), "request rate limit")
+	}
+	if s.tokens != nil && s.tokens.PerHour > 0 {
+		wait(s.windowDelay(now, float64(s.tokens.PerHour), func(u AIUsage) float64 { return float64(u.Tokens) }), "hourly token limit")
+	}
+	if s.cost != nil && s.cost.PerHour > 0 {
+		wait(s.windowDelay(now, s.cost.PerHour, func(u AIUsage) float64 { return u.Cost }), "hourly cost limit")
+	}
+	return delay, reason
+}
+
+// requestInterval is the shortest time between two requests that keeps to
+// the request rate limits
+func (s *AIScheduler) requestInterval() time.Duration {
+	if s.rateLimit == nil {
+		return 0
+	}
+	var interval time.Duration
+	limit := func(d time.Duration) {
+		if d > interval {
+			interval = d
+		}
+	}
+	if s.rateLimit.RequestsPerSecond > 0 {
+		limit(time.Duration(float64(time.Second) / s.rateLimit.RequestsPerSecond))
+	}
+	if s.rateLimit.RequestsPerMinute > 0 {
+		limit(time.Minute / time.Duration(s.rateLimit.RequestsPerMinute))
+	}
+	if s.rateLimit.RequestsPerHour > 0 {
+		limit(time.Hour / time.Duration(s.rateLimit.RequestsPerHour))
+	}
+	return interval
+}
+
+// windowDelay returns how long until the usage of the last hour drops below limit
+func (s *AIScheduler) windowDelay(now time.Time, limit float64, value func(AIUsage) float64) time.Duration {
+	used := s.used(now, time.Hour, value)
+	for _, record := range s.usage {
+		if used < limit {
+			break
+		}
+		if record.at.After(now.Add(-time.Hour)) {
+			used -= value(record.usage)
+			if used < limit {
+				return record.at.Add(time.Hour).Sub(now)
+			}
+		}
+	}
+	return 0
+}
+
+// checkBudget fails once a daily or monthly limit is used up
+func (s *AIScheduler) checkBudget(now time.Time) error {
+	tokens := func(u AIUsage) float64 { return float64(u.Tokens) }
+	cost := func(u AIUsage) float64 { return u.Cost }
+
+	if s.rateLimit != nil && s.rateLimit.RequestsPerDay > 0 {
+		count := 0
+		for _, at := range s.requests {
+			if at.After(now.Add(-aiBudgetDay)) {
+				count++
+			}
+		}
+		if count >= s.rateLimit.RequestsPerDay {
+			return fmt.Errorf("%w: %d of %d requests per day made", ErrAIBudgetExceeded, count, s.rateLimit.RequestsPerDay)
+		}
+	}
+	if s.tokens != nil {
+		if used := s.used(now, aiBudgetDay, tokens); s.tokens.PerDay > 0 && used >= float64(s.tokens.PerDay) {
+			return fmt.Errorf("%w: %.0f of %d tokens per day used", ErrAIBudgetExceeded, used, s.tokens.PerDay)
+		}
+		if used := s.used(now, aiBudgetMonth, tokens); s.tokens.PerMonth > 0 && used >= float64(s.tokens.PerMonth) {
+			return fmt.Errorf("%w: %.0f of %d tokens per month used", ErrAIBudgetExceeded, used, s.tokens.PerMonth)
+		}
+	}
+	if s.cost != nil {
+		if used := s.used(now, aiBudgetDay, cost); s.cost.PerDay > 0 && used >= s.cost.PerDay {
+			return fmt.Errorf("%w: %.2f of %.2f %s per day spent", ErrAIBudgetExceeded, used, s.cost.PerDay, s.currency())
+		}
+		if used := s.used(now, aiBudgetMonth, cost); s.cost.PerMonth > 0 && used >= s.cost.PerMonth {
+			return fmt.Errorf("%w: %.2f of %.2f %s per month spent", ErrAIBudgetExceeded, used, s.cost.PerMonth, s.currency())
+		}
+	}
+	return nil
+}
+
+// used sums the usage recorded within window before now
+func (s *AIScheduler) used(now time.Time, window time.Duration, value func(AIUsage) float64) float64 {
+	total := 0.0
+	for _, record := range s.usage {
+		if record.at.After(now.Add(-window)) {
+			total += value(record.usage)
+		}
+	}
+	return total
+}
+
+// prune forgets requests and usage older than the longest limit window
+func (s *AIScheduler) prune(now time.Time) {
+	cutoff := now.Add(-aiBudgetMonth)
+	for len(s.requests) > 0 && !s.requests[0].After(cutoff) {
+		s.requests = s.requests[1:]
+	}
+	for len(s.usage) > 0 && !s.usage[0].at.After(cutoff) {
+		s.usage = s.usage[1:]
+	}
+}
+
+func (s *AIScheduler) currency() string {
+	if s.cost.Currency != "" {
+		return s.cost.Currency
+	}
+	return "USD"
+}
+
+// sleepContext waits for d or until ctx is done
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
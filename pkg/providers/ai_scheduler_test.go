@@ -0,0 +1,164 @@
+package providers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeAIClock drives an AIScheduler without real waiting
+type fakeAIClock struct {
+	now    time.Time
+	waited []time.Duration
+}
+
+func newTestAIScheduler(config *AIChainConfig) (*AIScheduler, *fakeAIClock) {
+	clock := &fakeAIClock{now: time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)}
+	s := NewAIScheduler(config)
+	s.now = func() time.Time { return clock.now }
+	s.sleep = func(ctx context.Context, d time.Duration) error {
+		clock.waited = append(clock.waited, d)
+		clock.now = clock.now.Add(d)
+		return ctx.Err()
+	}
+	return s, clock
+}
+
+func TestAIScheduler(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("No limits", func(t *testing.T) {
+		s, clock := newTestAIScheduler(nil)
+		assert.False(t, s.Limited())
+		for i := 0; i < 5; i++ {
+			require.NoError(t, s.Wait(ctx))
+		}
+		assert.Empty(t, clock.waited)
+
+		var nilScheduler *AIScheduler
+		assert.False(t, nilScheduler.Limited())
+		assert.NoError(t, nilScheduler.Wait(ctx))
+	})
+
+	t.Run("Request rate", func(t *testing.T) {
+		s, clock := newTestAIScheduler(&AIChainConfig{RateLimit: &RateLimitConfig{RequestsPerMinute: 20, RequestsPerSecond: 1}})
+		var reasons []string
+		s.SetWaitHandler(func(delay time.Duration, reason string) { reasons = append(reasons, reason) })
+
+		for i := 0; i < 3; i++ {
+			require.NoError(t, s.Wait(ctx))
+		}
+		assert.Equal(t, []time.Duration{3 * time.Second, 3 * time.Second}, clock.waited)
+		assert.Equal(t, []string{"request rate limit", "request rate limit"}, reasons)
+	})
+
+	t.Run("Hourly token limit", func(t *testing.T) {
+		s, clock := newTestAIScheduler(&AIChainConfig{TokenLimits: &TokenLimits{PerHour: 1000}})
+		start := clock.now
+
+		require.NoError(t, s.Wait(ctx))
+		s.Record(AIUsage{Tokens: 600})
+		clock.now = clock.now.Add(10 * time.Minute)
+		require.NoError(t, s.Wait(ctx))
+		s.Record(AIUsage{Tokens: 600})
+		assert.Empty(t, clock.waited)
+
+		// 1200 tokens in the last hour, the first 600 age out an hour after they were used
+		require.NoError(t, s.Wait(ctx))
+		require.Len(t, clock.waited, 1)
+		assert.Equal(t, start.Add(time.Hour), clock.now)
+	})
+
+	t.Run("Hourly cost limit", func(t *testing.T) {
+		s, clock := newTestAIScheduler(&AIChainConfig{CostLimits: &CostLimits{PerHour: 1}})
+		require.NoError(t, s.Wait(ctx))
+		s.Record(AIUsage{Cost: 1.5})
+
+		var reason string
+		s.SetWaitHandler(func(delay time.Duration, why string) { reason = why })
+		require.NoError(t, s.Wait(ctx))
+		assert.Equal(t, []time.Duration{time.Hour}, clock.waited)
+		assert.Equal(t, "hourly cost limit", reason)
+	})
+
+	t.Run("Daily and monthly budgets stop", func(t *testing.T) {
+		s, _ := newTestAIScheduler(&AIChainConfig{TokenLimits: &TokenLimits{PerDay: 1000}})
+		require.NoError(t, s.Wait(ctx))
+		s.Record(AIUsage{Tokens: 1000})
+		err := s.Wait(ctx)
+		require.ErrorIs(t, err, ErrAIBudgetExceeded)
+		assert.Contains(t, err.Error(), "1000 of 1000 tokens per day")
+
+		s, clock := newTestAIScheduler(&AIChainConfig{CostLimits: &CostLimits{PerMonth: 10, Currency: "EUR"}})
+		require.NoError(t, s.Wait(ctx))
+		s.Record(AIUsage{Cost: 10})
+		clock.now = clock.now.Add(7 * 24 * time.Hour)
+		err = s.Wait(ctx)
+		require.ErrorIs(t, err, ErrAIBudgetExceeded)
+		assert.Contains(t, err.Error(), "10.00 of 10.00 EUR per month")
+
+		s, _ = newTestAIScheduler(&AIChainConfig{RateLimit: &RateLimitConfig{RequestsPerDay: 2}})
+		require.NoError(t, s.Wait(ctx))
+		require.NoError(t, s.Wait(ctx))
+		assert.ErrorIs(t, s.Wait(ctx), ErrAIBudgetExceeded)
+	})
+
+	t.Run("Pause and resume", func(t *testing.T) {
+		s := NewAIScheduler(nil)
+		s.Pause()
+		assert.True(t, s.Paused())
+
+		done := make(chan error, 1)
+		go func() { done <- s.Wait(ctx) }()
+		select {
+		case <-done:
+			t.Fatal("Wait returned while paused")
+		case <-time.After(20 * time.Millisecond):
+		}
+
+		s.Resume()
+		assert.False(t, s.Paused())
+		select {
+		case err := <-done:
+			assert.NoError(t, err)
+		case <-time.After(time.Second):
+			t.Fatal("Wait didn't return after Resume")
+		}
+
+		s.Pause()
+		cancelled, cancel := context.WithCancel(ctx)
+		cancel()
+		assert.ErrorIs(t, s.Wait(cancelled), context.Canceled)
+	})
+}
+
+func TestTriageStopsWhenBudgetExceeded(t *testing.T) {
+	provider := &triageProvider{flakyProvider: flakyProvider{tasks: []*UniversalTask{
+		{ID: "OPS-1", Title: "First"},
+		{ID: "OPS-2", Title: "Second"},
+		{ID: "OPS-3", Title: "Third"},
+		{ID: "OPS-4", Title: "Fourth"},
+	}}}
+
+	s, _ := newTestAIScheduler(&AIChainConfig{RateLimit: &RateLimitConfig{RequestsPerDay: 2}})
+	calls := 0
+	suggest := func(ctx context.Context, task *UniversalTask, team *TriageTeam) (*TriageSuggestion, error) {
+		calls++
+		return &TriageSuggestion{Priority: TaskPriorityHigh}, nil
+	}
+
+	var progress [][2]int
+	options := TriageOptions{Progress: func(done, total int) { progress = append(progress, [2]int{done, total}) }}
+	results, _, err := Triage(context.Background(), provider, options, s.TriageSuggester(suggest))
+	require.NoError(t, err)
+
+	// The fourth task is left out once the budget is used up
+	assert.Equal(t, 2, calls)
+	require.Len(t, results, 3)
+	assert.Empty(t, results[1].Error)
+	assert.Contains(t, results[2].Error, ErrAIBudgetExceeded.Error())
+	assert.Equal(t, [][2]int{{0, 4}, {1, 4}, {2, 4}, {3, 4}}, progress)
+}
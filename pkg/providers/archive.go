@@ -0,0 +1,450 @@
+package providers
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ArchiveVersion is the version of the task archive format written by ExportArchive
+const ArchiveVersion = 1
+
+const (
+	archiveManifestFile   = "manifest.json"
+	archiveTasksFile      = "tasks.json"
+	archiveAttachmentsDir = "attachments"
+)
+
+// Related data that can be included in a task archive
+const (
+	ArchiveComments    = "comments"
+	ArchiveAttachments = "attachments"
+	ArchiveHistory     = "history"
+)
+
+// ArchiveInclude selects the related data exported beside the task fields
+type ArchiveInclude struct {
+	Comments    bool
+	Attachments bool
+	History     bool // AI execution history from the task's ricochet metadata
+}
+
+// ParseArchiveInclude parses a list such as "comments,attachments". "all"
+// selects everything.
+func ParseArchiveInclude(items []string) (ArchiveInclude, error) {
+	var include ArchiveInclude
+	for _, item := range items {
+		switch strings.ToLower(strings.TrimSpace(item)) {
+		case "":
+		case ArchiveComments:
+			include.Comments = true
+		case ArchiveAttachments:
+			include.Attachments = true
+		case ArchiveHistory:
+			include.History = true
+		case "all":
+			include = ArchiveInclude{Comments: true, Attachments: true, History: true}
+		default:
+			return include, NewValidationError(fmt.Sprintf("unknown export item %q: expected %s, %s, %s or all",
+				item, ArchiveComments, ArchiveAttachments, ArchiveHistory), nil)
+		}
+	}
+	return include, nil
+}
+
+// Items returns the names of the selected data
+func (i ArchiveInclude) Items() []string {
+	items := []string{}
+	if i.Comments {
+		items = append(items, ArchiveComments)
+	}
+	if i.Attachments {
+		items = append(items, ArchiveAttachments)
+	}
+	if i.History {
+		items = append(items, ArchiveHistory)
+	}
+	return items
+}
+
+// ArchiveManifest describes the contents of a task archive
+type ArchiveManifest struct {
+	Version     int       `json:"version"`
+	CreatedAt   time.Time `json:"createdAt"`
+	Provider    string    `json:"provider"`
+	Include     []string  `json:"include"`
+	Tasks       int       `json:"tasks"`
+	Comments    int       `json:"comments"`
+	Attachments int       `json:"attachments"`
+	Warnings    []string  `json:"warnings,omitempty"`
+}
+
+// ArchivedTask is a task together with its related data
+type ArchivedTask struct {
+	Task        *UniversalTask        `json:"task"`
+	Comments    []*Comment            `json:"comments,omitempty"`
+	Attachments []*ArchivedAttachment `json:"attachments,omitempty"`
+}
+
+// ArchivedAttachment is an attachment with the path of its content in the
+// archive. The path is empty if the attachment could not be downloaded.
+type ArchivedAttachment struct {
+	*Attachment
+	Path string `json:"path,omitempty"`
+}
+
+// ExportArchive writes tasks of a provider with the selected related data to w
+// as a zip archive: a manifest, the tasks with their comments and attachment
+// metadata, and the attachment contents under attachments/. Comments and
+// attachments require the provider to implement CommentProvider and
+// AttachmentProvider. Failures to fetch the data of a single task are recorded
+// as manifest warnings instead of aborting the export.
+func ExportArchive(ctx context.Context, w io.Writer, providerName string, provider TaskProvider, tasks []*UniversalTask, include ArchiveInclude) (*ArchiveManifest, error) {
+	commenter, hasComments := ProviderAs[CommentProvider](provider)
+	if include.Comments && !hasComments {
+		return nil, NewUnsupportedError(providerName, CapabilityComments)
+	}
+	attachments, hasAttachments := ProviderAs[AttachmentProvider](provider)
+	if include.Attachments && !hasAttachments {
+		return nil, NewUnsupportedError(providerName, CapabilityAttachments)
+	}
+
+	manifest := &ArchiveManifest{
+		Version:   ArchiveVersion,
+		CreatedAt: time.Now(),
+		Provider:  providerName,
+		Include:   include.Items(),
+	}
+	warn := func(format string, args ...interface{}) {
+		manifest.Warnings = append(manifest.Warnings, fmt.Sprintf(format, args...))
+	}
+
+	archive := zip.NewWriter(w)
+	archived := make([]*ArchivedTask, 0, len(tasks))
+
+	for _, task := range tasks {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		id := task.GetDisplayID()
+
+		// Listings may leave out attachments, the task itself has them
+		if include.Attachments && task.Attachments == nil {
+			if full, err := provider.GetTask(ctx, task.ID); err != nil {
+				warn("%s: failed to get attachments: %v", id, err)
+			} else {
+				task = full
+			}
+		}
+
+		entry := &ArchivedTask{Task: archivedTaskFields(task, include)}
+
+		if include.Comments {
+			comments, err := commenter.GetComments(ctx, task.ID)
+			if err != nil {
+				warn("%s: failed to get comments: %v", id, err)
+			}
+			entry.Comments = comments
+			manifest.Comments += len(comments)
+		}
+
+		if include.Attachments {
+			for i, attachment := range task.Attachments {
+				saved := &ArchivedAttachment{Attachment: attachment}
+				filePath := path.Join(archiveAttachmentsDir, archiveName(id), fmt.Sprintf("%d-%s", i+1, archiveName(attachment.Filename)))
+				if err := writeArchiveAttachment(ctx, archive, filePath, attachments, attachment); err != nil {
+					warn("%s: failed to download attachment %s: %v", id, attachment.Filename, err)
+				} else {
+					saved.Path = filePath
+					manifest.Attachments++
+				}
+				entry.Attachments = append(entry.Attachments, saved)
+			}
+		}
+
+		archived = append(archived, entry)
+	}
+	manifest.Tasks = len(archived)
+
+	if err := writeArchiveJSON(archive, archiveTasksFile, archived); err != nil {
+		return nil, err
+	}
+	if err := writeArchiveJSON(archive, archiveManifestFile, manifest); err != nil {
+		return nil, err
+	}
+	if err := archive.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write archive: %w", err)
+	}
+
+	return manifest, nil
+}
+
+// archivedTaskFields returns a copy of task without the data stored beside it
+// in the archive, and without AI execution history unless it is included
+func archivedTaskFields(task *UniversalTask, include ArchiveInclude) *UniversalTask {
+	fields := *task
+	fields.Comments = nil
+	fields.Attachments = nil
+	if task.RicochetMetadata != nil && !include.History {
+		metadata := *task.RicochetMetadata
+		metadata.AIExecutionHistory = nil
+		fields.RicochetMetadata = &metadata
+	}
+	return &fields
+}
+
+func writeArchiveAttachment(ctx context.Context, archive *zip.Writer, filePath string, provider AttachmentProvider, attachment *Attachment) error {
+	content, err := provider.DownloadAttachment(ctx, attachment)
+	if err != nil {
+		return err
+	}
+	defer content.Close()
+
+	file, err := archive.Create(filePath)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(file, content)
+	return err
+}
+
+func writeArchiveJSON(archive *zip.Writer, name string, value interface{}) error {
+	file, err := archive.Create(name)
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(value); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	return nil
+}
+
+// archiveName makes a task key or file name safe to use as a path element
+func archiveName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		switch r {
+		case '/', '\\', ':', '*', '?', '"', '<', '>', '|':
+			return '_'
+		}
+		return r
+	}, name)
+	if name == "" || name == "." || name == ".." {
+		return "_"
+	}
+	return name
+}
+
+// Archive is a task archive opened for reading
+type Archive struct {
+	Manifest ArchiveManifest
+	Tasks    []*ArchivedTask
+
+	files  map[string]*zip.File
+	closer io.Closer
+}
+
+// OpenArchive opens a task archive written by ExportArchive
+func OpenArchive(filename string) (*Archive, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open archive: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to open archive: %w", err)
+	}
+
+	archive, err := ReadArchive(file, info.Size())
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	archive.closer = file
+	return archive, nil
+}
+
+// ReadArchive reads a task archive of the given size from r
+func ReadArchive(r io.ReaderAt, size int64) (*Archive, error) {
+	reader, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("not a task archive: %w", err)
+	}
+
+	archive := &Archive{files: make(map[string]*zip.File, len(reader.File))}
+	for _, file := range reader.File {
+		archive.files[file.Name] = file
+	}
+
+	if err := archive.readJSON(archiveManifestFile, &archive.Manifest); err != nil {
+		return nil, err
+	}
+	if archive.Manifest.Version > ArchiveVersion {
+		return nil, fmt.Errorf("archive version %d is newer than the supported version %d", archive.Manifest.Version, ArchiveVersion)
+	}
+	if err := archive.readJSON(archiveTasksFile, &archive.Tasks); err != nil {
+		return nil, err
+	}
+
+	return archive, nil
+}
+
+// OpenFile opens a file stored in the archive, such as an attachment
+func (a *Archive) OpenFile(name string) (io.ReadCloser, error) {
+	file, exists := a.files[name]
+	if !exists {
+		return nil, fmt.Errorf("%s is missing from the archive", name)
+	}
+	return file.Open()
+}
+
+// Close closes the archive file
+func (a *Archive) Close() error {
+	if a.closer == nil {
+		return nil
+	}
+	return a.closer.Close()
+}
+
+func (a *Archive) readJSON(name string, value interface{}) error {
+	content, err := a.OpenFile(name)
+	if err != nil {
+		return fmt.Errorf("not a task archive: %w", err)
+	}
+	defer content.Close()
+
+	if err := json.NewDecoder(content).Decode(value); err != nil {
+		return fmt.Errorf("failed to read %s: %w", name, err)
+	}
+	return nil
+}
+
+// ImportOptions configures how archived tasks are recreated
+type ImportOptions struct {
+	ProjectID string // Project to create the tasks in, defaults to the archived project
+}
+
+// ImportResult is the outcome of importing one archived task
+type ImportResult struct {
+	Source      string         `json:"source"`
+	Task        *UniversalTask `json:"task,omitempty"`
+	Comments    int            `json:"comments"`
+	Attachments int            `json:"attachments"`
+	Warnings    []string       `json:"warnings,omitempty"`
+	Error       string         `json:"error,omitempty"`
+}
+
+// ImportArchive recreates the archived tasks in target with their comments
+// and attachments. Comments are added in their original order with a header
+// naming the original author and date, since providers set both themselves.
+// Attachments are uploaded when target implements AttachmentProvider.
+func ImportArchive(ctx context.Context, archive *Archive, target TaskProvider, targetName string, options ImportOptions) []*ImportResult {
+	commenter, hasComments := ProviderAs[CommentProvider](target)
+	uploader, hasAttachments := ProviderAs[AttachmentProvider](target)
+
+	results := make([]*ImportResult, 0, len(archive.Tasks))
+	for _, entry := range archive.Tasks {
+		if entry.Task == nil {
+			continue
+		}
+		result := &ImportResult{Source: entry.Task.GetDisplayID()}
+		results = append(results, result)
+		warn := func(format string, args ...interface{}) {
+			result.Warnings = append(result.Warnings, fmt.Sprintf(format, args...))
+		}
+
+		task := importedTask(entry.Task)
+		if options.ProjectID != "" {
+			task.ProjectID = options.ProjectID
+		}
+
+		created, err := target.CreateTask(ctx, task)
+		if err != nil {
+			result.Error = err.Error()
+			continue
+		}
+		result.Task = created
+
+		if entry.Task.Status.Name != "" {
+			if err := target.UpdateStatus(ctx, created.ID, entry.Task.Status); err != nil {
+				warn("failed to set status %s: %v", entry.Task.Status.Name, err)
+			}
+		}
+
+		if len(entry.Comments) > 0 {
+			if !hasComments {
+				warn("%d comments skipped: %v", len(entry.Comments), NewUnsupportedError(targetName, CapabilityComments))
+			} else {
+				comments := append([]*Comment(nil), entry.Comments...)
+				sort.SliceStable(comments, func(i, j int) bool { return comments[i].CreatedAt.Before(comments[j].CreatedAt) })
+				for _, comment := range comments {
+					if err := commenter.AddComment(ctx, created.ID, importedComment(comment)); err != nil {
+						warn("failed to add comment %s: %v", comment.ID, err)
+						continue
+					}
+					result.Comments++
+				}
+			}
+		}
+
+		for _, attachment := range entry.Attachments {
+			if attachment.Path == "" {
+				warn("attachment %s was not downloaded during export", attachment.Filename)
+				continue
+			}
+			if !hasAttachments {
+				warn("attachment %s skipped: %v", attachment.Filename, NewUnsupportedError(targetName, CapabilityAttachments))
+				continue
+			}
+			if err := importAttachment(ctx, archive, uploader, created.ID, attachment); err != nil {
+				warn("failed to upload attachment %s: %v", attachment.Filename, err)
+				continue
+			}
+			result.Attachments++
+		}
+	}
+
+	return results
+}
+
+// importedTask builds the task to create from an archived one. Besides the
+// fields CloneTask copies it keeps the assignee, dates, estimates and ricochet
+// metadata, which belong to the task rather than to the provider.
+func importedTask(source *UniversalTask) *UniversalTask {
+	task := CloneTask(source)
+	task.AssigneeID = source.AssigneeID
+	task.Tags = append([]string(nil), source.Tags...)
+	task.DueDate = source.DueDate
+	task.StartDate = source.StartDate
+	task.EstimatedTime = source.EstimatedTime
+	task.RicochetMetadata = source.RicochetMetadata
+	return task
+}
+
+func importedComment(comment *Comment) string {
+	author := comment.AuthorID
+	if author == "" {
+		author = "unknown"
+	}
+	return fmt.Sprintf("_Originally posted by %s on %s_\n\n%s", author, comment.CreatedAt.Format("2006-01-02 15:04"), comment.Content)
+}
+
+func importAttachment(ctx context.Context, archive *Archive, uploader AttachmentProvider, taskID string, attachment *ArchivedAttachment) error {
+	content, err := archive.OpenFile(attachment.Path)
+	if err != nil {
+		return err
+	}
+	defer content.Close()
+
+	return uploader.UploadAttachment(ctx, taskID, attachment.Filename, content)
+}
@@ -0,0 +1,213 @@
+package providers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// archiveProvider keeps tasks, comments and attachment contents in memory
+type archiveProvider struct {
+	TaskProvider
+	tasks    map[string]*UniversalTask
+	comments map[string][]*Comment
+	files    map[string]string
+	statuses map[string]string
+}
+
+func newArchiveProvider() *archiveProvider {
+	return &archiveProvider{
+		tasks:    make(map[string]*UniversalTask),
+		comments: make(map[string][]*Comment),
+		files:    make(map[string]string),
+		statuses: make(map[string]string),
+	}
+}
+
+func (p *archiveProvider) GetTask(ctx context.Context, id string) (*UniversalTask, error) {
+	task, exists := p.tasks[id]
+	if !exists {
+		return nil, ErrTaskNotFound
+	}
+	return task, nil
+}
+
+func (p *archiveProvider) CreateTask(ctx context.Context, task *UniversalTask) (*UniversalTask, error) {
+	if task.Title == "fail" {
+		return nil, errors.New("project is archived")
+	}
+	created := *task
+	created.ID = fmt.Sprintf("NEW-%d", len(p.tasks)+1)
+	p.tasks[created.ID] = &created
+	return &created, nil
+}
+
+func (p *archiveProvider) UpdateStatus(ctx context.Context, taskID string, status TaskStatus) error {
+	p.statuses[taskID] = status.Name
+	return nil
+}
+
+func (p *archiveProvider) GetComments(ctx context.Context, taskID string) ([]*Comment, error) {
+	return p.comments[taskID], nil
+}
+
+func (p *archiveProvider) AddComment(ctx context.Context, taskID string, comment string) error {
+	p.comments[taskID] = append(p.comments[taskID], &Comment{Content: comment})
+	return nil
+}
+
+func (p *archiveProvider) DownloadAttachment(ctx context.Context, attachment *Attachment) (io.ReadCloser, error) {
+	content, exists := p.files[attachment.URL]
+	if !exists {
+		return nil, errors.New("404 Not Found")
+	}
+	return io.NopCloser(strings.NewReader(content)), nil
+}
+
+func (p *archiveProvider) UploadAttachment(ctx context.Context, taskID, filename string, content io.Reader) error {
+	data, err := io.ReadAll(content)
+	if err != nil {
+		return err
+	}
+	p.files[taskID+"/"+filename] = string(data)
+	return nil
+}
+
+// wrappedProvider hides the optional interfaces like the registry wrappers do
+type wrappedProvider struct {
+	TaskProvider
+}
+
+func (w *wrappedProvider) Unwrap() TaskProvider {
+	return w.TaskProvider
+}
+
+// tasksOnlyProvider supports neither comments nor attachments
+type tasksOnlyProvider struct {
+	TaskProvider
+	created []*UniversalTask
+}
+
+func (p *tasksOnlyProvider) CreateTask(ctx context.Context, task *UniversalTask) (*UniversalTask, error) {
+	p.created = append(p.created, task)
+	return task, nil
+}
+
+func (p *tasksOnlyProvider) UpdateStatus(ctx context.Context, taskID string, status TaskStatus) error {
+	return nil
+}
+
+func TestTaskArchive(t *testing.T) {
+	created := time.Date(2024, 3, 1, 9, 30, 0, 0, time.UTC)
+
+	source := newArchiveProvider()
+	source.tasks["1"] = &UniversalTask{
+		ID: "1", Key: "OPS/1", Title: "Disk full", Status: TaskStatus{Name: "In Progress"},
+		Attachments: []*Attachment{
+			{ID: "a", Filename: "df.txt", URL: "/files/a"},
+			{ID: "b", Filename: "gone.log", URL: "/files/b"},
+		},
+		RicochetMetadata: &RicochetTaskMetadata{AIExecutionHistory: []*AIExecutionRecord{{}}},
+	}
+	source.tasks["2"] = &UniversalTask{ID: "2", Key: "OPS-2", Title: "fail"}
+	source.comments["1"] = []*Comment{
+		{ID: "c2", Content: "Cleaned /var/log", AuthorID: "bob", CreatedAt: created.Add(time.Hour)},
+		{ID: "c1", Content: "Looking into it", AuthorID: "alice", CreatedAt: created},
+	}
+	source.files["/files/a"] = "/dev/sda1 100%"
+
+	tasks := []*UniversalTask{{ID: "1", Key: "OPS/1", Title: "Disk full"}, source.tasks["2"]}
+
+	var buf bytes.Buffer
+	include := ArchiveInclude{Comments: true, Attachments: true}
+	manifest, err := ExportArchive(context.Background(), &buf, "youtrack", &wrappedProvider{source}, tasks, include)
+	require.NoError(t, err)
+
+	t.Run("Export writes a manifest, tasks and attachments", func(t *testing.T) {
+		assert.Equal(t, ArchiveVersion, manifest.Version)
+		assert.Equal(t, []string{ArchiveComments, ArchiveAttachments}, manifest.Include)
+		assert.Equal(t, 2, manifest.Tasks)
+		assert.Equal(t, 2, manifest.Comments)
+		assert.Equal(t, 1, manifest.Attachments)
+		require.Len(t, manifest.Warnings, 1)
+		assert.Contains(t, manifest.Warnings[0], "gone.log")
+
+		archive, err := ReadArchive(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+		require.NoError(t, err)
+		assert.True(t, manifest.CreatedAt.Equal(archive.Manifest.CreatedAt))
+		assert.Equal(t, manifest.Warnings, archive.Manifest.Warnings)
+		require.Len(t, archive.Tasks, 2)
+
+		entry := archive.Tasks[0]
+		assert.Nil(t, entry.Task.Comments)
+		assert.Empty(t, entry.Task.RicochetMetadata.AIExecutionHistory)
+		require.Len(t, entry.Attachments, 2)
+		assert.Equal(t, "attachments/OPS_1/1-df.txt", entry.Attachments[0].Path)
+		assert.Empty(t, entry.Attachments[1].Path)
+
+		content, err := archive.OpenFile(entry.Attachments[0].Path)
+		require.NoError(t, err)
+		data, _ := io.ReadAll(content)
+		assert.Equal(t, "/dev/sda1 100%", string(data))
+	})
+
+	t.Run("Import recreates tasks with comments and attachments", func(t *testing.T) {
+		archive, err := ReadArchive(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+		require.NoError(t, err)
+
+		target := newArchiveProvider()
+		results := ImportArchive(context.Background(), archive, target, "jira", ImportOptions{ProjectID: "NEW"})
+		require.Len(t, results, 2)
+
+		result := results[0]
+		assert.Equal(t, "OPS/1", result.Source)
+		require.NotNil(t, result.Task)
+		assert.Equal(t, "NEW", result.Task.ProjectID)
+		assert.Equal(t, "In Progress", target.statuses[result.Task.ID])
+		assert.Equal(t, 2, result.Comments)
+		assert.Equal(t, 1, result.Attachments)
+		assert.Len(t, result.Warnings, 1)
+
+		comments := target.comments[result.Task.ID]
+		require.Len(t, comments, 2)
+		assert.Contains(t, comments[0].Content, "Originally posted by alice on 2024-03-01 09:30")
+		assert.Contains(t, comments[1].Content, "Cleaned /var/log")
+		assert.Equal(t, "/dev/sda1 100%", target.files[result.Task.ID+"/df.txt"])
+
+		assert.Equal(t, "project is archived", results[1].Error)
+	})
+
+	t.Run("Missing support", func(t *testing.T) {
+		_, err := ExportArchive(context.Background(), io.Discard, "plain", &tasksOnlyProvider{}, tasks, include)
+		assert.True(t, IsUnsupportedError(err))
+
+		archive, err := ReadArchive(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+		require.NoError(t, err)
+		target := &tasksOnlyProvider{}
+		results := ImportArchive(context.Background(), archive, target, "plain", ImportOptions{})
+		assert.Len(t, target.created, 2)
+		assert.Equal(t, 0, results[0].Comments)
+		assert.Len(t, results[0].Warnings, 3)
+	})
+
+	t.Run("Include list", func(t *testing.T) {
+		parsed, err := ParseArchiveInclude([]string{"comments", " History"})
+		require.NoError(t, err)
+		assert.Equal(t, ArchiveInclude{Comments: true, History: true}, parsed)
+
+		parsed, err = ParseArchiveInclude([]string{"all"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{ArchiveComments, ArchiveAttachments, ArchiveHistory}, parsed.Items())
+
+		_, err = ParseArchiveInclude([]string{"watchers"})
+		assert.Error(t, err)
+	})
+}
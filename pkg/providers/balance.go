@@ -0,0 +1,211 @@
+package providers
+
+import (
+	"math"
+	"sort"
+)
+
+// AssigneeLoad is the open work of a single assignee
+type AssigneeLoad struct {
+	Assignee       string  `json:"assignee"`
+	OpenTasks      int     `json:"openTasks"`
+	RemainingHours float64 `json:"remainingHours"`
+}
+
+// Reassignment moves a task from one assignee to another
+type Reassignment struct {
+	Task   *UniversalTask `json:"-"`
+	TaskID string         `json:"taskId"`
+	Title  string         `json:"title"`
+	From   string         `json:"from"`
+	To     string         `json:"to"`
+	Hours  float64        `json:"hours"`
+}
+
+// BalanceOptions controls workload rebalancing
+type BalanceOptions struct {
+	// MaxPerAssignee caps the open tasks of an assignee; 0 means no cap
+	MaxPerAssignee int
+	// Assignees are extra candidates that may have no open tasks yet
+	Assignees []string
+}
+
+// BalancePlan is a set of reassignments with the load before and after applying them
+type BalancePlan struct {
+	Before        []AssigneeLoad `json:"before"`
+	After         []AssigneeLoad `json:"after"`
+	Reassignments []Reassignment `json:"reassignments"`
+}
+
+// RemainingHours estimates the work left on a task from its time-tracking fields:
+// the remaining time if set, otherwise the estimate minus the time spent
+func RemainingHours(task *UniversalTask) float64 {
+	if task.RemainingTime != nil {
+		return task.RemainingTime.Hours()
+	}
+	if task.EstimatedTime == nil {
+		return 0
+	}
+
+	remaining := *task.EstimatedTime
+	if task.TimeSpent != nil {
+		remaining -= *task.TimeSpent
+	}
+	if remaining < 0 {
+		return 0
+	}
+	return remaining.Hours()
+}
+
+// isMovable reports whether a task can be handed to someone else.
+// Work that has already started stays with its assignee.
+func isMovable(task *UniversalTask) bool {
+	if task.TimeSpent != nil && *task.TimeSpent > 0 {
+		return false
+	}
+	switch task.Status.Category {
+	case StatusCategoryInProgress, StatusCategoryReview, StatusCategoryTesting:
+		return false
+	}
+	return true
+}
+
+// PlanRebalance suggests reassignments that level the number of open tasks per
+// assignee, preferring moves that also level the remaining hours. Unassigned and
+// completed tasks are ignored. Assignees above MaxPerAssignee give away tasks to
+// anyone below the cap, even if that leaves the counts uneven.
+func PlanRebalance(tasks []*UniversalTask, opts BalanceOptions) *BalancePlan {
+	loads := make(map[string]*AssigneeLoad)
+	owned := make(map[string][]*UniversalTask)
+
+	addAssignee := func(assignee string) *AssigneeLoad {
+		load, ok := loads[assignee]
+		if !ok {
+			load = &AssigneeLoad{Assignee: assignee}
+			loads[assignee] = load
+		}
+		return load
+	}
+
+	for _, assignee := range opts.Assignees {
+		if assignee != "" {
+			addAssignee(assignee)
+		}
+	}
+
+	for _, task := range tasks {
+		if task == nil || task.AssigneeID == "" || task.IsCompleted() {
+			continue
+		}
+		load := addAssignee(task.AssigneeID)
+		load.OpenTasks++
+		load.RemainingHours += RemainingHours(task)
+		if isMovable(task) {
+			owned[task.AssigneeID] = append(owned[task.AssigneeID], task)
+		}
+	}
+
+	plan := &BalancePlan{Before: snapshotLoads(loads)}
+
+	// Assignees that have nothing left to give away
+	exhausted := make(map[string]bool)
+
+	for {
+		source := pickLoad(loads, func(l *AssigneeLoad) bool { return !exhausted[l.Assignee] }, true)
+		if source == nil {
+			break
+		}
+		target := pickLoad(loads, func(l *AssigneeLoad) bool {
+			return l.Assignee != source.Assignee &&
+				(opts.MaxPerAssignee <= 0 || l.OpenTasks < opts.MaxPerAssignee)
+		}, false)
+		if target == nil {
+			break
+		}
+
+		overCap := opts.MaxPerAssignee > 0 && source.OpenTasks > opts.MaxPerAssignee
+		if source.OpenTasks-target.OpenTasks <= 1 && !overCap {
+			break
+		}
+
+		index := pickTask(owned[source.Assignee], (source.RemainingHours-target.RemainingHours)/2)
+		if index < 0 {
+			exhausted[source.Assignee] = true
+			continue
+		}
+
+		task := owned[source.Assignee][index]
+		owned[source.Assignee] = append(owned[source.Assignee][:index], owned[source.Assignee][index+1:]...)
+
+		hours := RemainingHours(task)
+		source.OpenTasks--
+		source.RemainingHours -= hours
+		target.OpenTasks++
+		target.RemainingHours += hours
+
+		plan.Reassignments = append(plan.Reassignments, Reassignment{
+			Task:   task,
+			TaskID: task.GetDisplayID(),
+			Title:  task.Title,
+			From:   source.Assignee,
+			To:     target.Assignee,
+			Hours:  hours,
+		})
+	}
+
+	plan.After = snapshotLoads(loads)
+	return plan
+}
+
+// pickLoad returns the most (or least) loaded assignee matching the filter.
+// Load is compared by open tasks, then remaining hours, then name.
+func pickLoad(loads map[string]*AssigneeLoad, filter func(*AssigneeLoad) bool, most bool) *AssigneeLoad {
+	var best *AssigneeLoad
+	for _, load := range loads {
+		if !filter(load) {
+			continue
+		}
+		if best == nil || heavier(load, best) == most {
+			best = load
+		}
+	}
+	return best
+}
+
+// heavier reports whether a carries more load than b
+func heavier(a, b *AssigneeLoad) bool {
+	if a.OpenTasks != b.OpenTasks {
+		return a.OpenTasks > b.OpenTasks
+	}
+	if a.RemainingHours != b.RemainingHours {
+		return a.RemainingHours > b.RemainingHours
+	}
+	return a.Assignee < b.Assignee
+}
+
+// pickTask returns the index of the task whose remaining hours are closest to
+// the given amount, or -1 if there are no tasks
+func pickTask(tasks []*UniversalTask, hours float64) int {
+	best := -1
+	bestDistance := math.Inf(1)
+	for i, task := range tasks {
+		distance := math.Abs(RemainingHours(task) - hours)
+		if distance < bestDistance {
+			best = i
+			bestDistance = distance
+		}
+	}
+	return best
+}
+
+// snapshotLoads copies the loads ordered from the most to the least loaded
+func snapshotLoads(loads map[string]*AssigneeLoad) []AssigneeLoad {
+	result := make([]AssigneeLoad, 0, len(loads))
+	for _, load := range loads {
+		result = append(result, *load)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return heavier(&result[i], &result[j])
+	})
+	return result
+}
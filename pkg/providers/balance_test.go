@@ -0,0 +1,114 @@
+package providers
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func hours(h float64) *time.Duration {
+	d := time.Duration(h * float64(time.Hour))
+	return &d
+}
+
+func openTasks(assignee string, count int, estimate float64) []*UniversalTask {
+	var tasks []*UniversalTask
+	for i := 0; i < count; i++ {
+		tasks = append(tasks, &UniversalTask{
+			Key:           fmt.Sprintf("%s-%d", assignee, i+1),
+			AssigneeID:    assignee,
+			Status:        TaskStatus{Name: "Open", Category: StatusCategoryTodo},
+			EstimatedTime: hours(estimate),
+		})
+	}
+	return tasks
+}
+
+func loadOf(loads []AssigneeLoad, assignee string) AssigneeLoad {
+	for _, load := range loads {
+		if load.Assignee == assignee {
+			return load
+		}
+	}
+	return AssigneeLoad{}
+}
+
+func TestRemainingHours(t *testing.T) {
+	assert.Equal(t, 0.0, RemainingHours(&UniversalTask{}))
+	assert.Equal(t, 3.0, RemainingHours(&UniversalTask{EstimatedTime: hours(5), TimeSpent: hours(2)}))
+	assert.Equal(t, 0.0, RemainingHours(&UniversalTask{EstimatedTime: hours(1), TimeSpent: hours(2)}))
+	// Explicit remaining time wins over the estimate
+	assert.Equal(t, 4.0, RemainingHours(&UniversalTask{EstimatedTime: hours(5), TimeSpent: hours(2), RemainingTime: hours(4)}))
+}
+
+func TestPlanRebalance(t *testing.T) {
+	t.Run("Levels open task counts", func(t *testing.T) {
+		tasks := append(openTasks("alice", 8, 2), openTasks("bob", 2, 2)...)
+
+		plan := PlanRebalance(tasks, BalanceOptions{})
+
+		require.Len(t, plan.Reassignments, 3)
+		for _, move := range plan.Reassignments {
+			assert.Equal(t, "alice", move.From)
+			assert.Equal(t, "bob", move.To)
+		}
+		assert.Equal(t, AssigneeLoad{Assignee: "alice", OpenTasks: 8, RemainingHours: 16}, loadOf(plan.Before, "alice"))
+		assert.Equal(t, AssigneeLoad{Assignee: "alice", OpenTasks: 5, RemainingHours: 10}, loadOf(plan.After, "alice"))
+		assert.Equal(t, AssigneeLoad{Assignee: "bob", OpenTasks: 5, RemainingHours: 10}, loadOf(plan.After, "bob"))
+	})
+
+	t.Run("Extra assignees receive work", func(t *testing.T) {
+		plan := PlanRebalance(openTasks("alice", 4, 1), BalanceOptions{Assignees: []string{"carol"}})
+
+		assert.Len(t, plan.Reassignments, 2)
+		assert.Equal(t, 2, loadOf(plan.After, "carol").OpenTasks)
+	})
+
+	t.Run("Started and completed tasks stay put", func(t *testing.T) {
+		tasks := openTasks("alice", 4, 1)
+		tasks[0].Status = TaskStatus{Name: "In Progress", Category: StatusCategoryInProgress}
+		tasks[1].TimeSpent = hours(0.5)
+		tasks[2].Status = TaskStatus{Name: "Done", Category: StatusCategoryDone}
+		tasks = append(tasks, &UniversalTask{Key: "unassigned", Status: TaskStatus{Category: StatusCategoryTodo}})
+
+		plan := PlanRebalance(tasks, BalanceOptions{Assignees: []string{"bob"}})
+
+		require.Len(t, plan.Reassignments, 1)
+		assert.Equal(t, "alice-4", plan.Reassignments[0].TaskID)
+		assert.Equal(t, 3, loadOf(plan.Before, "alice").OpenTasks)
+	})
+
+	t.Run("Prefers tasks that level remaining hours", func(t *testing.T) {
+		tasks := openTasks("alice", 3, 1)
+		tasks[1].EstimatedTime = hours(8)
+
+		plan := PlanRebalance(tasks, BalanceOptions{Assignees: []string{"bob"}})
+
+		require.Len(t, plan.Reassignments, 1)
+		assert.Equal(t, "alice-2", plan.Reassignments[0].TaskID)
+		assert.Equal(t, 8.0, plan.Reassignments[0].Hours)
+	})
+
+	t.Run("Cap moves work off overloaded assignees", func(t *testing.T) {
+		tasks := append(openTasks("alice", 6, 1), openTasks("bob", 5, 1)...)
+
+		plan := PlanRebalance(tasks, BalanceOptions{MaxPerAssignee: 3, Assignees: []string{"carol", "dave"}})
+
+		for _, load := range plan.After {
+			assert.LessOrEqual(t, load.OpenTasks, 3, load.Assignee)
+		}
+		assert.Len(t, plan.Reassignments, 5)
+	})
+
+	t.Run("Cap without spare capacity", func(t *testing.T) {
+		tasks := append(openTasks("alice", 5, 1), openTasks("bob", 2, 1)...)
+
+		plan := PlanRebalance(tasks, BalanceOptions{MaxPerAssignee: 2})
+
+		assert.Empty(t, plan.Reassignments)
+		assert.Equal(t, 5, loadOf(plan.After, "alice").OpenTasks)
+	})
+}
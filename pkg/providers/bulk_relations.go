@@ -0,0 +1,386 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// TempIDPrefix marks the temporary IDs tasks of a bulk-create file get with
+// "id" so other tasks of the file can reference them, e.g. "$tmp:epic1"
+const TempIDPrefix = "$tmp:"
+
+// IsTempID reports whether id is a temporary ID of a bulk-create file
+func IsTempID(id string) bool {
+	return strings.HasPrefix(id, TempIDPrefix)
+}
+
+// bulkRelationKeys are relationship keys of bulk-create files besides the
+// task fields: "parent" and, in YAML, the camel case field names
+type bulkRelationKeys struct {
+	Parent    string   `json:"parent" yaml:"parent"`
+	ParentID  string   `json:"-" yaml:"parentId"`
+	BlockedBy []string `json:"-" yaml:"blockedBy"`
+	Blocks    []string `json:"-" yaml:"blocks"`
+}
+
+// ParseBulkTaskFile reads the tasks of a JSON or YAML bulk-create file.
+// Relationships can be given as parent (or parentId), blockedBy and blocks,
+// with temporary IDs for tasks of the same file.
+func ParseBulkTaskFile(data []byte, isYAML bool) ([]*UniversalTask, error) {
+	var tasks []*UniversalTask
+	var keys []bulkRelationKeys
+	unmarshal := json.Unmarshal
+	if isYAML {
+		unmarshal = yaml.Unmarshal
+	}
+	if err := unmarshal(data, &tasks); err != nil {
+		return nil, err
+	}
+	if err := unmarshal(data, &keys); err != nil {
+		return nil, err
+	}
+
+	for i, task := range tasks {
+		if task == nil || i >= len(keys) {
+			continue
+		}
+		for _, parent := range []string{keys[i].Parent, keys[i].ParentID} {
+			if task.ParentID == "" {
+				task.ParentID = strings.TrimSpace(parent)
+			}
+		}
+		task.BlockedBy = mergeIDs(task.BlockedBy, keys[i].BlockedBy)
+		task.Blocks = mergeIDs(task.Blocks, keys[i].Blocks)
+	}
+	return tasks, nil
+}
+
+func mergeIDs(ids, more []string) []string {
+	for _, id := range more {
+		if id = strings.TrimSpace(id); id != "" && !containsString(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// BulkCreateResult is the outcome of creating one task of a bulk-create file
+type BulkCreateResult struct {
+	Ref   string         `json:"ref,omitempty"` // Temporary ID from the file
+	Title string         `json:"title"`
+	Task  *UniversalTask `json:"task,omitempty"`
+	// Links lists the relationships set after creation, e.g. "subtask of OPS-1"
+	Links []string `json:"links,omitempty"`
+	Error string   `json:"error,omitempty"`
+}
+
+// bulkNode is a task of a bulk-create file with its relationships split into
+// references to other tasks of the file and to existing tasks
+type bulkNode struct {
+	task      *UniversalTask
+	ref       string
+	parent    string
+	epic      string
+	blockedBy []string // Includes the tasks of the file that list this one in blocks
+	blocks    []string // Existing tasks only
+	deps      []*bulkNode
+}
+
+// PlanBulkCreate orders the tasks of a bulk-create file so every task comes
+// after the tasks of the file it references as parent, epic or blocker.
+// Tasks in the same step don't reference each other. Temporary IDs must be
+// unique and every temporary reference must be defined; a cycle of
+// references yields a *DependencyCycleError.
+func PlanBulkCreate(tasks []*UniversalTask) ([][]*UniversalTask, error) {
+	levels, err := planBulkNodes(tasks)
+	if err != nil {
+		return nil, err
+	}
+	steps := make([][]*UniversalTask, len(levels))
+	for i, level := range levels {
+		for _, node := range level {
+			steps[i] = append(steps[i], node.task)
+		}
+	}
+	return steps, nil
+}
+
+func planBulkNodes(tasks []*UniversalTask) ([][]*bulkNode, error) {
+	nodes := make([]*bulkNode, 0, len(tasks))
+	byRef := make(map[string]*bulkNode)
+	for i, task := range tasks {
+		if task == nil {
+			return nil, NewValidationError(fmt.Sprintf("task %d is empty", i+1), nil)
+		}
+		node := &bulkNode{task: task, parent: task.ParentID, epic: task.EpicID}
+		if IsTempID(task.ID) {
+			node.ref = task.ID
+			if _, exists := byRef[node.ref]; exists {
+				return nil, NewValidationError(fmt.Sprintf("temporary ID %s is used by more than one task", node.ref), nil)
+			}
+			byRef[node.ref] = node
+		}
+		nodes = append(nodes, node)
+	}
+
+	lookup := func(node *bulkNode, ref string) (*bulkNode, error) {
+		target, ok := byRef[ref]
+		if !ok {
+			return nil, NewValidationError(fmt.Sprintf("task %q references unknown temporary ID %s", node.task.Title, ref), nil)
+		}
+		return target, nil
+	}
+
+	for _, node := range nodes {
+		node.blockedBy = append(node.blockedBy, node.task.BlockedBy...)
+		for _, id := range node.task.Blocks {
+			if !IsTempID(id) {
+				node.blocks = append(node.blocks, id)
+				continue
+			}
+			target, err := lookup(node, id)
+			if err != nil {
+				return nil, err
+			}
+			if node.ref == "" {
+				// The blocked task needs a way to reference its blocker
+				return nil, NewValidationError(fmt.Sprintf("task %q blocks %s but has no temporary ID", node.task.Title, id), nil)
+			}
+			if !containsString(target.blockedBy, node.ref) && !containsString(target.task.BlockedBy, node.ref) {
+				target.blockedBy = append(target.blockedBy, node.ref)
+			}
+		}
+	}
+
+	for _, node := range nodes {
+		refs := node.blockedBy
+		if node.epic != "" && node.epic != node.parent {
+			refs = append([]string{node.epic}, refs...)
+		}
+		if node.parent != "" {
+			refs = append([]string{node.parent}, refs...)
+		}
+		for _, ref := range refs {
+			if !IsTempID(ref) {
+				continue
+			}
+			target, err := lookup(node, ref)
+			if err != nil {
+				return nil, err
+			}
+			node.deps = append(node.deps, target)
+		}
+	}
+
+	// Kahn's algorithm, one level at a time, in file order within a level
+	planned := make(map[*bulkNode]bool, len(nodes))
+	var levels [][]*bulkNode
+	for len(planned) < len(nodes) {
+		var level []*bulkNode
+		for _, node := range nodes {
+			if planned[node] {
+				continue
+			}
+			ready := true
+			for _, dep := range node.deps {
+				ready = ready && planned[dep]
+			}
+			if ready {
+				level = append(level, node)
+			}
+		}
+		if len(level) == 0 {
+			return nil, &DependencyCycleError{Cycle: findBulkCycle(nodes, planned)}
+		}
+		for _, node := range level {
+			planned[node] = true
+		}
+		levels = append(levels, level)
+	}
+	return levels, nil
+}
+
+// findBulkCycle follows references from an unplanned task until one repeats
+func findBulkCycle(nodes []*bulkNode, planned map[*bulkNode]bool) []string {
+	var current *bulkNode
+	for _, node := range nodes {
+		if !planned[node] {
+			current = node
+			break
+		}
+	}
+
+	position := make(map[*bulkNode]int)
+	var path []*bulkNode
+	for {
+		if start, seen := position[current]; seen {
+			var cycle []string
+			for i := len(path) - 1; i >= start; i-- {
+				cycle = append(cycle, path[i].ref)
+			}
+			return append(cycle, cycle[0])
+		}
+		position[current] = len(path)
+		path = append(path, current)
+		for _, dep := range current.deps {
+			if !planned[dep] {
+				current = dep
+				break
+			}
+		}
+	}
+}
+
+// CreateWithRelations creates the tasks of a bulk-create file in the order of
+// PlanBulkCreate, one batch per step. Temporary IDs are replaced by the IDs of
+// the created tasks before their dependents are created, so ParentID, EpicID
+// and BlockedBy hold real IDs. Providers that can link tasks also get the
+// relationships linked after creation. After a batch fails the remaining
+// tasks are not created; the tasks the provider created before the failure
+// are in the results. Results are in file order.
+func CreateWithRelations(ctx context.Context, provider TaskProvider, tasks []*UniversalTask) ([]*BulkCreateResult, error) {
+	levels, err := planBulkNodes(tasks)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make(map[*UniversalTask]*BulkCreateResult, len(tasks))
+	for _, task := range tasks {
+		result := &BulkCreateResult{Title: task.Title}
+		if IsTempID(task.ID) {
+			result.Ref = task.ID
+		}
+		results[task] = result
+	}
+	ordered := func() []*BulkCreateResult {
+		list := make([]*BulkCreateResult, len(tasks))
+		for i, task := range tasks {
+			list[i] = results[task]
+		}
+		return list
+	}
+
+	linker, canLink := ProviderAs[LinkProvider](provider)
+	created := make(map[string]string) // Temporary ID to the display ID of the created task
+	resolve := func(id string) string {
+		if real, ok := created[id]; ok {
+			return real
+		}
+		return id
+	}
+
+	var failed error
+	for _, level := range levels {
+		if failed != nil {
+			for _, node := range level {
+				results[node.task].Error = "not created because an earlier batch failed"
+			}
+			continue
+		}
+
+		batch := make([]*UniversalTask, len(level))
+		for i, node := range level {
+			task := *node.task
+			if node.ref != "" {
+				task.ID = ""
+			}
+			task.ParentID = resolve(node.parent)
+			task.EpicID = resolve(node.epic)
+			task.BlockedBy = make([]string, len(node.blockedBy))
+			for j, id := range node.blockedBy {
+				task.BlockedBy[j] = resolve(id)
+			}
+			task.Blocks = node.blocks
+			batch[i] = &task
+		}
+
+		createdTasks, err := provider.BulkCreateTasks(ctx, batch)
+		if err == nil && len(createdTasks) != len(batch) {
+			err = fmt.Errorf("provider created %d of %d tasks", len(createdTasks), len(batch))
+		}
+		if len(createdTasks) > len(batch) {
+			createdTasks = createdTasks[:len(batch)]
+		}
+		if err != nil {
+			// The tasks created before the failure are kept in the results,
+			// so that they can be reported and rolled back
+			failed = fmt.Errorf("failed to create tasks: %w", err)
+			for _, node := range level[len(createdTasks):] {
+				results[node.task].Error = err.Error()
+			}
+		}
+
+		for i, node := range level[:len(createdTasks)] {
+			task := createdTasks[i]
+			result := results[node.task]
+			result.Task = task
+			if node.ref != "" {
+				created[node.ref] = task.GetDisplayID()
+			}
+			if canLink {
+				linkBulkTask(ctx, linker, task.GetDisplayID(), batch[i], result)
+			}
+		}
+	}
+
+	if failed != nil {
+		return ordered(), failed
+	}
+	linkFailures := 0
+	for _, result := range results {
+		if result.Error != "" {
+			linkFailures++
+		}
+	}
+	if linkFailures > 0 {
+		return ordered(), NewPartialFailureError(linkFailures, len(tasks), "tasks")
+	}
+	return ordered(), nil
+}
+
+// linkBulkTask links a created task to its parent and blockers, and the tasks
+// it blocks to it. Failures are reported in the result.
+func linkBulkTask(ctx context.Context, linker LinkProvider, id string, task *UniversalTask, result *BulkCreateResult) {
+	type link struct {
+		source, target string
+		linkType       TaskLinkType
+		description    string
+	}
+	var links []link
+	if task.ParentID != "" {
+		links = append(links, link{id, task.ParentID, TaskLinkSubtaskOf, "subtask of " + task.ParentID})
+	}
+	for _, blocker := range task.BlockedBy {
+		links = append(links, link{id, blocker, TaskLinkDependsOn, "depends on " + blocker})
+	}
+	for _, blocked := range task.Blocks {
+		links = append(links, link{blocked, id, TaskLinkDependsOn, "blocks " + blocked})
+	}
+
+	var errs []string
+	for _, l := range links {
+		if err := linker.LinkTasks(ctx, l.source, l.target, l.linkType); err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", l.description, err))
+			continue
+		}
+		result.Links = append(result.Links, l.description)
+	}
+	if len(errs) > 0 {
+		sort.Strings(errs)
+		result.Error = "created, but failed to link " + strings.Join(errs, "; ")
+	}
+}
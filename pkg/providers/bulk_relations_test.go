@@ -0,0 +1,187 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// bulkProvider records the batches it creates and the links it sets
+type bulkProvider struct {
+	flakyProvider
+	batches [][]*UniversalTask
+	links   []string
+	failAt  int // Batch number that fails, from 1
+	// failAtTask is the task number, counted over all batches from 1, that
+	// fails after the tasks before it in its batch were created
+	failAtTask int
+}
+
+func (p *bulkProvider) BulkCreateTasks(ctx context.Context, tasks []*UniversalTask) ([]*UniversalTask, error) {
+	p.batches = append(p.batches, tasks)
+	if len(p.batches) == p.failAt {
+		return nil, errConnectionRefused
+	}
+	var created []*UniversalTask
+	for _, task := range tasks {
+		if len(p.tasks)+1 == p.failAtTask {
+			return created, errConnectionRefused
+		}
+		task, err := p.CreateTask(ctx, task)
+		if err != nil {
+			return nil, err
+		}
+		created = append(created, task)
+	}
+	return created, nil
+}
+
+func (p *bulkProvider) LinkTasks(ctx context.Context, sourceID, targetID string, linkType TaskLinkType) error {
+	if sourceID == "OPS-404" || targetID == "OPS-404" {
+		return ErrTaskNotFound
+	}
+	p.links = append(p.links, sourceID+" "+string(linkType)+" "+targetID)
+	return nil
+}
+
+func TestParseBulkTaskFile(t *testing.T) {
+	data := []byte(`
+- id: $tmp:epic
+  title: Checkout
+  type: epic
+- title: Payment form
+  parent: $tmp:epic
+  blockedBy: [$tmp:api]
+- id: $tmp:api
+  title: Payment API
+  parentId: $tmp:epic
+  blocks: [OPS-7]
+`)
+	tasks, err := ParseBulkTaskFile(data, true)
+	require.NoError(t, err)
+	require.Len(t, tasks, 3)
+	assert.Equal(t, "$tmp:epic", tasks[0].ID)
+	assert.Equal(t, "$tmp:epic", tasks[1].ParentID)
+	assert.Equal(t, []string{"$tmp:api"}, tasks[1].BlockedBy)
+	assert.Equal(t, "$tmp:epic", tasks[2].ParentID)
+	assert.Equal(t, []string{"OPS-7"}, tasks[2].Blocks)
+
+	tasks, err = ParseBulkTaskFile([]byte(`[{"id": "$tmp:a", "title": "A"}, {"title": "B", "parent": "$tmp:a", "blockedBy": ["OPS-1"]}]`), false)
+	require.NoError(t, err)
+	assert.Equal(t, "$tmp:a", tasks[1].ParentID)
+	assert.Equal(t, []string{"OPS-1"}, tasks[1].BlockedBy)
+}
+
+func TestPlanBulkCreate(t *testing.T) {
+	t.Run("Referenced tasks come first", func(t *testing.T) {
+		tasks := []*UniversalTask{
+			{Title: "Form", ParentID: "$tmp:epic", BlockedBy: []string{"$tmp:api"}},
+			{ID: "$tmp:api", Title: "API", ParentID: "$tmp:epic"},
+			{ID: "$tmp:epic", Title: "Epic"},
+			{Title: "Unrelated", ParentID: "OPS-1"},
+		}
+		steps, err := PlanBulkCreate(tasks)
+		require.NoError(t, err)
+
+		titles := make([][]string, len(steps))
+		for i, step := range steps {
+			for _, task := range step {
+				titles[i] = append(titles[i], task.Title)
+			}
+		}
+		assert.Equal(t, [][]string{{"Epic", "Unrelated"}, {"API"}, {"Form"}}, titles)
+	})
+
+	t.Run("Blocks orders the blocked task after its blocker", func(t *testing.T) {
+		steps, err := PlanBulkCreate([]*UniversalTask{
+			{ID: "$tmp:b", Title: "B"},
+			{ID: "$tmp:a", Title: "A", Blocks: []string{"$tmp:b"}},
+		})
+		require.NoError(t, err)
+		require.Len(t, steps, 2)
+		assert.Equal(t, "A", steps[0][0].Title)
+	})
+
+	t.Run("Invalid references", func(t *testing.T) {
+		_, err := PlanBulkCreate([]*UniversalTask{{Title: "A", ParentID: "$tmp:missing"}})
+		assert.True(t, IsErrorType(err, ErrorTypeValidation))
+		assert.Contains(t, err.Error(), "unknown temporary ID $tmp:missing")
+
+		_, err = PlanBulkCreate([]*UniversalTask{{ID: "$tmp:a", Title: "A"}, {ID: "$tmp:a", Title: "B"}})
+		assert.Contains(t, err.Error(), "$tmp:a is used by more than one task")
+
+		_, err = PlanBulkCreate([]*UniversalTask{{ID: "$tmp:b", Title: "B"}, {Title: "A", Blocks: []string{"$tmp:b"}}})
+		assert.Contains(t, err.Error(), "has no temporary ID")
+	})
+
+	t.Run("Cycles", func(t *testing.T) {
+		_, err := PlanBulkCreate([]*UniversalTask{
+			{ID: "$tmp:a", Title: "A", ParentID: "$tmp:c"},
+			{ID: "$tmp:b", Title: "B", BlockedBy: []string{"$tmp:a"}},
+			{ID: "$tmp:c", Title: "C", BlockedBy: []string{"$tmp:b"}},
+			{ID: "$tmp:d", Title: "D"},
+		})
+		var cycle *DependencyCycleError
+		require.True(t, errors.As(err, &cycle))
+		assert.Equal(t, []string{"$tmp:b", "$tmp:c", "$tmp:a", "$tmp:b"}, cycle.Cycle)
+
+		_, err = PlanBulkCreate([]*UniversalTask{{ID: "$tmp:a", Title: "A", ParentID: "$tmp:a"}})
+		require.True(t, errors.As(err, &cycle))
+		assert.Equal(t, []string{"$tmp:a", "$tmp:a"}, cycle.Cycle)
+	})
+}
+
+func TestCreateWithRelations(t *testing.T) {
+	tasks := []*UniversalTask{
+		{Title: "Form", ParentID: "$tmp:epic", BlockedBy: []string{"$tmp:api"}},
+		{ID: "$tmp:api", Title: "API", ParentID: "$tmp:epic", Blocks: []string{"OPS-404"}},
+		{ID: "$tmp:epic", Title: "Epic"},
+	}
+
+	t.Run("Temporary IDs are resolved and linked", func(t *testing.T) {
+		tasks := []*UniversalTask{tasks[0], tasks[2], {ID: "$tmp:api", Title: "API", ParentID: "$tmp:epic"}}
+		provider := &bulkProvider{}
+		results, err := CreateWithRelations(context.Background(), provider, tasks)
+		require.NoError(t, err)
+
+		require.Len(t, provider.batches, 3)
+		assert.Empty(t, provider.batches[0][0].ID)
+		assert.Equal(t, "OPS-1", provider.batches[1][0].ParentID)
+		assert.Equal(t, []string{"OPS-2"}, provider.batches[2][0].BlockedBy)
+
+		require.Len(t, results, 3)
+		assert.Equal(t, "OPS-3", results[0].Task.GetDisplayID())
+		assert.Equal(t, "$tmp:epic", results[1].Ref)
+		assert.Equal(t, "OPS-1", results[1].Task.GetDisplayID())
+		assert.Equal(t, []string{"subtask of OPS-1", "depends on OPS-2"}, results[0].Links)
+		assert.Equal(t, []string{"OPS-2 subtask_of OPS-1", "OPS-3 subtask_of OPS-1", "OPS-3 depends_on OPS-2"}, provider.links)
+
+		// The file's tasks keep their temporary IDs
+		assert.Equal(t, "$tmp:epic", tasks[0].ParentID)
+	})
+
+	t.Run("Link failures are partial failures", func(t *testing.T) {
+		provider := &bulkProvider{}
+		results, err := CreateWithRelations(context.Background(), provider, []*UniversalTask{{Title: "A", Blocks: []string{"OPS-404"}}, {Title: "B"}})
+		var partial *PartialFailureError
+		require.True(t, errors.As(err, &partial))
+		assert.Equal(t, 1, partial.Failed)
+		require.NotNil(t, results[0].Task)
+		assert.Contains(t, results[0].Error, "failed to link blocks OPS-404")
+		assert.Empty(t, results[1].Error)
+	})
+
+	t.Run("A failed batch stops its dependents", func(t *testing.T) {
+		provider := &bulkProvider{failAt: 2}
+		results, err := CreateWithRelations(context.Background(), provider, tasks)
+		require.Error(t, err)
+		assert.Len(t, provider.batches, 2)
+		assert.NotNil(t, results[2].Task)
+		assert.Nil(t, results[1].Task)
+		assert.Contains(t, results[1].Error, "connection refused")
+		assert.Equal(t, "not created because an earlier batch failed", results[0].Error)
+	})
+}
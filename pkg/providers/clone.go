@@ -0,0 +1,34 @@
+package providers
+
+import "time"
+
+// CloneTask builds a new task from source, copying its content but not its
+// identity or history: status, resolution, comments, attachments, links and
+// timestamps are left for the target provider to set
+func CloneTask(source *UniversalTask) *UniversalTask {
+	now := time.Now()
+	clone := &UniversalTask{
+		Title:       source.Title,
+		Description: source.Description,
+		Type:        source.Type,
+		Priority:    source.Priority,
+		ProjectID:   source.ProjectID,
+		Labels:      append([]string(nil), source.Labels...),
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	if len(source.CustomFields) > 0 {
+		resolution, _ := ResolutionField(source)
+		clone.CustomFields = make(map[string]interface{}, len(source.CustomFields))
+		for name, value := range source.CustomFields {
+			// The resolution belongs to the source task's status
+			if name == resolution {
+				continue
+			}
+			clone.CustomFields[name] = value
+		}
+	}
+
+	return clone
+}
@@ -0,0 +1,54 @@
+package providers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCloneTask(t *testing.T) {
+	created := time.Now().Add(-48 * time.Hour)
+	source := &UniversalTask{
+		ID:           "42",
+		Key:          "OPS-42",
+		Title:        "Disk full on db-1",
+		Description:  "## Impact\nWrites fail",
+		Type:         TaskTypeBug,
+		Priority:     TaskPriorityCritical,
+		Status:       TaskStatus{Name: "Fixed", Category: StatusCategoryDone},
+		ProjectID:    "OPS",
+		AssigneeID:   "alice",
+		Labels:       []string{"incident"},
+		CustomFields: map[string]interface{}{"Severity": "S1", "Resolution": "Fixed"},
+		Comments:     []*Comment{{Content: "Rotated logs"}},
+		BlockedBy:    []string{"OPS-1"},
+		CreatedAt:    created,
+		ProviderName: "youtrack",
+	}
+
+	clone := CloneTask(source)
+
+	assert.Equal(t, source.Title, clone.Title)
+	assert.Equal(t, source.Description, clone.Description)
+	assert.Equal(t, TaskTypeBug, clone.Type)
+	assert.Equal(t, TaskPriorityCritical, clone.Priority)
+	assert.Equal(t, "OPS", clone.ProjectID)
+	assert.Equal(t, []string{"incident"}, clone.Labels)
+	assert.Equal(t, map[string]interface{}{"Severity": "S1"}, clone.CustomFields)
+
+	assert.Empty(t, clone.ID)
+	assert.Empty(t, clone.Key)
+	assert.Empty(t, clone.Status.Name)
+	assert.Empty(t, clone.AssigneeID)
+	assert.Empty(t, clone.Comments)
+	assert.Empty(t, clone.BlockedBy)
+	assert.Empty(t, clone.ProviderName)
+	assert.True(t, clone.CreatedAt.After(created))
+
+	// The clone doesn't share slices or maps with the source
+	clone.Labels[0] = "template"
+	clone.CustomFields["Severity"] = "S3"
+	assert.Equal(t, "incident", source.Labels[0])
+	assert.Equal(t, "S1", source.CustomFields["Severity"])
+}
@@ -0,0 +1,215 @@
+package providers
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CustomColumnPrefix selects a custom field as a table column, e.g. custom:Sprint
+const CustomColumnPrefix = "custom:"
+
+// DefaultTaskColumns are the columns of task tables when none are chosen
+var DefaultTaskColumns = []string{"id", "provider", "title", "status", "priority", "assignee"}
+
+// TaskColumn is a column of a task table
+type TaskColumn struct {
+	Name        string // e.g. "due" or "custom:Story points"
+	Header      string
+	MaxWidth    int    // Longer values are truncated; 0 means no limit
+	CustomField string // Set for custom field columns
+
+	value func(*UniversalTask) string
+}
+
+// Value returns the text of the column for task
+func (c TaskColumn) Value(task *UniversalTask) string {
+	if c.CustomField != "" {
+		value, _ := LookupCustomField(task, c.CustomField)
+		return FormatFieldValue(value)
+	}
+	return c.value(task)
+}
+
+type taskColumnSpec struct {
+	header   string
+	maxWidth int
+	value    func(*UniversalTask) string
+}
+
+var taskColumns = map[string]taskColumnSpec{
+	"id":       {"ID", 0, func(t *UniversalTask) string { return t.GetDisplayID() }},
+	"provider": {"PROVIDER", 0, func(t *UniversalTask) string { return t.ProviderName }},
+	"title":    {"TITLE", 40, func(t *UniversalTask) string { return t.Title }},
+	"status":   {"STATUS", 0, func(t *UniversalTask) string { return t.Status.Name }},
+	"priority": {"PRIORITY", 0, func(t *UniversalTask) string { return string(t.Priority) }},
+	"type":     {"TYPE", 0, func(t *UniversalTask) string { return string(t.Type) }},
+	"project":  {"PROJECT", 0, func(t *UniversalTask) string { return firstNonEmpty(t.ProjectKey, t.ProjectID) }},
+	"assignee": {"ASSIGNEE", 15, func(t *UniversalTask) string { return t.AssigneeID }},
+	"reporter": {"REPORTER", 15, func(t *UniversalTask) string { return t.ReporterID }},
+	"labels":   {"LABELS", 30, func(t *UniversalTask) string { return strings.Join(t.Labels, ",") }},
+	"parent":   {"PARENT", 0, func(t *UniversalTask) string { return t.ParentID }},
+	"epic":     {"EPIC", 0, func(t *UniversalTask) string { return t.EpicID }},
+	"sprint":   {"SPRINT", 0, func(t *UniversalTask) string { return t.SprintID }},
+	"blocked":  {"BLOCKED BY", 30, func(t *UniversalTask) string { return strings.Join(t.BlockedBy, ",") }},
+	"points": {"POINTS", 0, func(t *UniversalTask) string {
+		if t.StoryPoints == nil {
+			return ""
+		}
+		return strconv.FormatFloat(*t.StoryPoints, 'f', -1, 64)
+	}},
+	"estimate": {"ESTIMATE", 0, func(t *UniversalTask) string { return formatOptionalDuration(t.EstimatedTime) }},
+	"spent":    {"SPENT", 0, func(t *UniversalTask) string { return formatOptionalDuration(t.TimeSpent) }},
+	"due":      {"DUE", 0, func(t *UniversalTask) string { return formatOptionalDate(t.DueDate) }},
+	"created":  {"CREATED", 0, func(t *UniversalTask) string { return formatOptionalDate(&t.CreatedAt) }},
+	"updated":  {"UPDATED", 0, func(t *UniversalTask) string { return formatOptionalDate(&t.UpdatedAt) }},
+	"resolved": {"RESOLVED", 0, func(t *UniversalTask) string { return formatOptionalDate(t.ResolvedAt) }},
+}
+
+// TaskColumnNames returns the names of the built-in task table columns, sorted
+func TaskColumnNames() []string {
+	names := make([]string, 0, len(taskColumns))
+	for name := range taskColumns {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ParseTaskColumns resolves column names, in the given order, into table
+// columns. Names are built-in fields like due or labels, or custom:<field>
+// for a custom field. No names means DefaultTaskColumns.
+func ParseTaskColumns(names []string) ([]TaskColumn, error) {
+	if len(names) == 0 {
+		names = DefaultTaskColumns
+	}
+
+	columns := make([]TaskColumn, 0, len(names))
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+
+		if len(name) > len(CustomColumnPrefix) && strings.EqualFold(name[:len(CustomColumnPrefix)], CustomColumnPrefix) {
+			field := strings.TrimSpace(name[len(CustomColumnPrefix):])
+			columns = append(columns, TaskColumn{Name: name, Header: strings.ToUpper(field), MaxWidth: 30, CustomField: field})
+			continue
+		}
+
+		spec, ok := taskColumns[strings.ToLower(name)]
+		if !ok {
+			return nil, NewValidationError(fmt.Sprintf("unknown column %q (expected one of %s, or %s<field>)",
+				name, strings.Join(TaskColumnNames(), ", "), CustomColumnPrefix), nil)
+		}
+		columns = append(columns, TaskColumn{Name: strings.ToLower(name), Header: spec.header, MaxWidth: spec.maxWidth, value: spec.value})
+	}
+
+	if len(columns) == 0 {
+		return nil, NewValidationError("no columns given", nil)
+	}
+	return columns, nil
+}
+
+// LookupCustomField finds a custom field of task by name. Names match
+// exactly first, then ignoring case, spaces, dashes and underscores, so
+// StoryPoints finds "Story points".
+func LookupCustomField(task *UniversalTask, name string) (interface{}, bool) {
+	if value, ok := task.CustomFields[name]; ok {
+		return value, true
+	}
+
+	wanted := normalizeFieldName(name)
+	for field, value := range task.CustomFields {
+		if normalizeFieldName(field) == wanted {
+			return value, true
+		}
+	}
+	return nil, false
+}
+
+func normalizeFieldName(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '-', '_':
+			return -1
+		}
+		return r
+	}, strings.ToLower(name))
+}
+
+// FormatFieldValue renders a custom field value for a table cell: enum and
+// user values by their name, periods as durations, lists joined by commas
+func FormatFieldValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	case []interface{}:
+		parts := make([]string, 0, len(v))
+		for _, item := range v {
+			if text := FormatFieldValue(item); text != "" {
+				parts = append(parts, text)
+			}
+		}
+		return strings.Join(parts, ",")
+	case map[string]interface{}:
+		for _, key := range []string{"name", "presentation", "fullName", "login", "text"} {
+			if text, ok := v[key].(string); ok && text != "" {
+				return text
+			}
+		}
+		if minutes, ok := v["minutes"].(float64); ok {
+			return formatDuration(time.Duration(minutes) * time.Minute)
+		}
+	}
+
+	if data, err := json.Marshal(value); err == nil {
+		return string(data)
+	}
+	return fmt.Sprint(value)
+}
+
+func formatOptionalDuration(d *time.Duration) string {
+	if d == nil {
+		return ""
+	}
+	return formatDuration(*d)
+}
+
+// formatDuration renders whole hours and minutes, e.g. 1h30m
+func formatDuration(d time.Duration) string {
+	d = d.Round(time.Minute)
+	if d == 0 {
+		return "0m"
+	}
+	text := strings.TrimSuffix(d.String(), "0s")
+	if strings.HasSuffix(text, "h0m") {
+		text = strings.TrimSuffix(text, "0m")
+	}
+	return text
+}
+
+func formatOptionalDate(t *time.Time) string {
+	if t == nil || t.IsZero() {
+		return ""
+	}
+	return t.Format("2006-01-02")
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
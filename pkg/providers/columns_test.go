@@ -0,0 +1,55 @@
+package providers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTaskColumns(t *testing.T) {
+	columns, err := ParseTaskColumns(nil)
+	require.NoError(t, err)
+	assert.Len(t, columns, len(DefaultTaskColumns))
+	assert.Equal(t, "ID", columns[0].Header)
+
+	columns, err = ParseTaskColumns([]string{"Due", " labels ", "custom:StoryPoints"})
+	require.NoError(t, err)
+	require.Len(t, columns, 3)
+	assert.Equal(t, "due", columns[0].Name)
+	assert.Equal(t, "StoryPoints", columns[2].CustomField)
+	assert.Equal(t, "STORYPOINTS", columns[2].Header)
+
+	_, err = ParseTaskColumns([]string{"id", "bogus"})
+	assert.True(t, IsErrorType(err, ErrorTypeValidation))
+	_, err = ParseTaskColumns([]string{"custom:"})
+	assert.Error(t, err)
+}
+
+func TestTaskColumnValues(t *testing.T) {
+	due := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	estimate := 90 * time.Minute
+	task := &UniversalTask{
+		Key:           "OPS-1",
+		Labels:        []string{"backend", "urgent"},
+		DueDate:       &due,
+		EstimatedTime: &estimate,
+		CustomFields: map[string]interface{}{
+			"Story points": float64(5),
+			"Sprint":       map[string]interface{}{"name": "Sprint 12"},
+			"Estimation":   map[string]interface{}{"minutes": float64(120)},
+			"Fix versions": []interface{}{map[string]interface{}{"name": "1.0"}, map[string]interface{}{"name": "1.1"}},
+		},
+	}
+
+	columns, err := ParseTaskColumns([]string{"id", "labels", "due", "estimate", "custom:StoryPoints", "custom:sprint",
+		"custom:Estimation", "custom:fix_versions", "custom:Missing", "assignee"})
+	require.NoError(t, err)
+
+	var values []string
+	for _, column := range columns {
+		values = append(values, column.Value(task))
+	}
+	assert.Equal(t, []string{"OPS-1", "backend,urgent", "2026-03-01", "1h30m", "5", "Sprint 12", "2h", "1.0,1.1", "", ""}, values)
+}
@@ -12,6 +12,9 @@ type ProviderConfig struct {
 	Enabled     bool         `json:"enabled" yaml:"enabled"`
 	Description string       `json:"description,omitempty" yaml:"description,omitempty"`
 
+	// Project that commands use when no project is given
+	DefaultProject string `json:"defaultProject,omitempty" yaml:"defaultProject,omitempty"`
+
 	// Connection settings
 	BaseURL     string `json:"baseUrl,omitempty" yaml:"baseUrl,omitempty"`
 	APIVersion  string `json:"apiVersion,omitempty" yaml:"apiVersion,omitempty"`
@@ -28,6 +31,15 @@ type ProviderConfig struct {
 	// Provider-specific settings
 	Settings map[string]interface{} `json:"settings,omitempty" yaml:"settings,omitempty"`
 
+	// Status categories of custom workflow states, checked before the built-in heuristics
+	StatusMapping StatusMapping `json:"statusMapping,omitempty" yaml:"statusMapping,omitempty"`
+
+	// Custom fields promoted to typed task fields, e.g. "Story points": storyPoints
+	FieldMapping FieldMapping `json:"fieldMapping,omitempty" yaml:"fieldMapping,omitempty"`
+
+	// Native priority values of universal levels, e.g. critical: Blocker, on top of the built-in scheme
+	PriorityMapping PriorityMapping `json:"priorityMapping,omitempty" yaml:"priorityMapping,omitempty"`
+
 	// Performance tuning
 	RateLimit   *RateLimitConfig `json:"rateLimit,omitempty" yaml:"rateLimit,omitempty"`
 	Timeout     time.Duration    `json:"timeout" yaml:"timeout"`
@@ -62,10 +74,22 @@ type MultiProviderConfig struct {
 	// Quality gates
 	QualityGates *QualityGatesConfig `json:"qualityGates,omitempty" yaml:"qualityGates,omitempty"`
 
+	// Outbound webhooks notified about task changes made through ricochet
+	Webhooks     []*OutboundWebhookConfig `json:"webhooks,omitempty" yaml:"webhooks,omitempty"`
+
+	// MCP server settings
+	MCP          *MCPConfig `json:"mcp,omitempty" yaml:"mcp,omitempty"`
+
 	// Global settings
 	LogLevel     string        `json:"logLevel" yaml:"logLevel"`
+	// Output format used by CLI commands when -o/--output is not given
+	DefaultOutputFormat string `json:"defaultOutputFormat,omitempty" yaml:"defaultOutputFormat,omitempty"`
+	// IANA time zone due dates are given in, e.g. Europe/Berlin; the local zone if empty
+	Timezone     string        `json:"timezone,omitempty" yaml:"timezone,omitempty"`
 	MetricsPort  int           `json:"metricsPort,omitempty" yaml:"metricsPort,omitempty"`
 	HealthCheck  time.Duration `json:"healthCheck" yaml:"healthCheck"`
+	// Health check latency above which a provider is reported as degraded
+	DegradedLatency time.Duration `json:"degradedLatency,omitempty" yaml:"degradedLatency,omitempty"`
 }
 
 // RateLimitConfig defines rate limiting settings
@@ -89,6 +113,22 @@ type RetryConfig struct {
 	RetryableErrors []string     `json:"retryableErrors,omitempty" yaml:"retryableErrors,omitempty"`
 }
 
+// OutboundWebhookConfig defines a URL that receives task events as signed JSON POSTs
+type OutboundWebhookConfig struct {
+	Name    string            `json:"name,omitempty" yaml:"name,omitempty"`
+	URL     string            `json:"url" yaml:"url"`
+	Secret  string            `json:"secret,omitempty" yaml:"secret,omitempty"` // HMAC-SHA256 key for X-Ricochet-Signature
+	Headers map[string]string `json:"headers,omitempty" yaml:"headers,omitempty"`
+
+	// Event filtering: no events means all of them. Statuses limits
+	// task.status_changed events to the listed status names.
+	Events   []EventType `json:"events,omitempty" yaml:"events,omitempty"`
+	Statuses []string    `json:"statuses,omitempty" yaml:"statuses,omitempty"`
+
+	Timeout     time.Duration `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+	RetryConfig *RetryConfig  `json:"retryConfig,omitempty" yaml:"retryConfig,omitempty"`
+}
+
 // CacheConfig defines caching behavior
 type CacheConfig struct {
 	Enabled       bool          `json:"enabled" yaml:"enabled"`
@@ -100,6 +140,7 @@ type CacheConfig struct {
 	TasksTTL      time.Duration `json:"tasksTtl,omitempty" yaml:"tasksTtl,omitempty"`
 	BoardsTTL     time.Duration `json:"boardsTtl,omitempty" yaml:"boardsTtl,omitempty"`
 	ProjectsTTL   time.Duration `json:"projectsTtl,omitempty" yaml:"projectsTtl,omitempty"`
+	MetadataTTL   time.Duration `json:"metadataTtl,omitempty" yaml:"metadataTtl,omitempty"` // users, projects and statuses
 	
 	// External cache
 	Redis         *RedisConfig  `json:"redis,omitempty" yaml:"redis,omitempty"`
@@ -239,6 +280,8 @@ type AIChainConfig struct {
 	DefaultModels *AIModelConfig `json:"defaultModels,omitempty" yaml:"defaultModels,omitempty"`
 	TokenLimits   *TokenLimits   `json:"tokenLimits,omitempty" yaml:"tokenLimits,omitempty"`
 	CostLimits    *CostLimits    `json:"costLimits,omitempty" yaml:"costLimits,omitempty"`
+	// Request rate of the model API that bulk AI operations are paced to
+	RateLimit     *RateLimitConfig `json:"rateLimit,omitempty" yaml:"rateLimit,omitempty"`
 }
 
 // ChainConfig defines a specific AI chain configuration
@@ -287,6 +330,24 @@ type CostLimits struct {
 	Currency   string  `json:"currency,omitempty" yaml:"currency,omitempty"`
 }
 
+// MCPConfig defines settings of the MCP server
+type MCPConfig struct {
+	// Tools the server exposes: tool names or patterns like ai_*. If set, only
+	// matching tools are exposed; all tools are exposed otherwise.
+	EnabledTools  []string `json:"enabledTools,omitempty" yaml:"enabledTools,omitempty"`
+	// Tools the server hides even if EnabledTools matches them
+	DisabledTools []string `json:"disabledTools,omitempty" yaml:"disabledTools,omitempty"`
+	// Audit log of tool calls, which is on unless disabled
+	Audit         *MCPAuditConfig `json:"audit,omitempty" yaml:"audit,omitempty"`
+}
+
+// MCPAuditConfig defines the audit log of MCP tool calls
+type MCPAuditConfig struct {
+	Disabled   bool `json:"disabled,omitempty" yaml:"disabled,omitempty"`
+	MaxSizeMB  int  `json:"maxSizeMB,omitempty" yaml:"maxSizeMB,omitempty"`   // Size at which the log is rotated, 10 by default
+	MaxBackups int  `json:"maxBackups,omitempty" yaml:"maxBackups,omitempty"` // Rotated logs to keep, 5 by default
+}
+
 // QualityGatesConfig defines quality gate configurations
 type QualityGatesConfig struct {
 	Enabled bool                        `json:"enabled" yaml:"enabled"`
@@ -387,6 +448,18 @@ func (c *ProviderConfig) Validate() error {
 		}
 	}
 	
+	if err := c.StatusMapping.Validate(); err != nil {
+		return err
+	}
+
+	if err := c.FieldMapping.Validate(); err != nil {
+		return err
+	}
+
+	if err := c.PriorityMapping.Validate(); err != nil {
+		return err
+	}
+	
 	return nil
 }
 
@@ -402,6 +475,16 @@ func (c *MultiProviderConfig) Validate() error {
 		}
 	}
 	
+	if c.DefaultOutputFormat != "" {
+		if err := ValidateOutputFormat(c.DefaultOutputFormat); err != nil {
+			return NewProviderError(ErrorTypeValidation, "invalid default output format", err)
+		}
+	}
+
+	if _, err := LoadTimezone(c.Timezone); err != nil {
+		return NewProviderError(ErrorTypeValidation, "invalid time zone", err)
+	}
+	
 	// Validate each provider
 	for name, provider := range c.Providers {
 		if err := provider.Validate(); err != nil {
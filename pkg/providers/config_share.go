@@ -0,0 +1,74 @@
+package providers
+
+// RedactSecrets returns a copy of config without credentials, for sharing a
+// provider setup: the API key, token and password, the webhook secret and
+// Redis password, and the auth config and settings entries whose names look
+// like credentials. The username is kept since the secret it needs is not.
+func RedactSecrets(config *ProviderConfig) *ProviderConfig {
+	redacted := *config
+	redacted.APIKey = ""
+	redacted.Token = ""
+	redacted.Password = ""
+	redacted.AuthConfig = redactSettings(config.AuthConfig)
+	redacted.Settings = redactSettings(config.Settings)
+
+	if config.SyncConfig != nil {
+		syncConfig := *config.SyncConfig
+		syncConfig.WebhookSecret = ""
+		redacted.SyncConfig = &syncConfig
+	}
+	if config.CacheConfig != nil && config.CacheConfig.Redis != nil {
+		cacheConfig := *config.CacheConfig
+		redis := *config.CacheConfig.Redis
+		redis.Password = ""
+		cacheConfig.Redis = &redis
+		redacted.CacheConfig = &cacheConfig
+	}
+
+	return &redacted
+}
+
+// redactSettings copies settings without the entries whose names look like
+// credentials, descending into nested maps
+func redactSettings(settings map[string]interface{}) map[string]interface{} {
+	if settings == nil {
+		return nil
+	}
+
+	result := make(map[string]interface{}, len(settings))
+	for name, value := range settings {
+		if IsSensitiveName(name) {
+			continue
+		}
+		if nested, ok := value.(map[string]interface{}); ok {
+			value = redactSettings(nested)
+		}
+		result[name] = value
+	}
+	return result
+}
+
+// MissingSecrets returns the config keys of the credentials the
+// authentication type of the provider needs but that are not set, e.g.
+// "token" for bearer authentication
+func (c *ProviderConfig) MissingSecrets() []string {
+	var missing []string
+	switch c.AuthType {
+	case AuthTypeAPIKey:
+		if c.APIKey == "" {
+			missing = append(missing, "apiKey")
+		}
+	case AuthTypeBearer:
+		if c.Token == "" {
+			missing = append(missing, "token")
+		}
+	case AuthTypeBasic:
+		if c.Username == "" {
+			missing = append(missing, "username")
+		}
+		if c.Password == "" {
+			missing = append(missing, "password")
+		}
+	}
+	return missing
+}
@@ -0,0 +1,48 @@
+package providers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedactSecrets(t *testing.T) {
+	config := &ProviderConfig{
+		Name:     "work",
+		Type:     ProviderTypeYouTrack,
+		BaseURL:  "https://work.youtrack.cloud",
+		AuthType: AuthTypeBasic,
+		Username: "ci",
+		Password: "hunter2",
+		Token:    "perm-token",
+		Settings: map[string]interface{}{
+			"useshortnames": true,
+			"webhooktoken":  "abc",
+			"mappings":      map[string]interface{}{"sprint": "Sprint", "client_secret": "xyz"},
+		},
+		SyncConfig: &SyncConfig{Enabled: true, WebhookSecret: "shh"},
+	}
+
+	redacted := RedactSecrets(config)
+	assert.Equal(t, "https://work.youtrack.cloud", redacted.BaseURL)
+	assert.Equal(t, "ci", redacted.Username)
+	assert.Empty(t, redacted.Password)
+	assert.Empty(t, redacted.Token)
+	assert.Equal(t, map[string]interface{}{
+		"useshortnames": true,
+		"mappings":      map[string]interface{}{"sprint": "Sprint"},
+	}, redacted.Settings)
+	assert.True(t, redacted.SyncConfig.Enabled)
+	assert.Empty(t, redacted.SyncConfig.WebhookSecret)
+
+	assert.Equal(t, "hunter2", config.Password, "the original is not changed")
+	assert.Equal(t, "shh", config.SyncConfig.WebhookSecret)
+	assert.Equal(t, "abc", config.Settings["webhooktoken"])
+}
+
+func TestMissingSecrets(t *testing.T) {
+	assert.Equal(t, []string{"token"}, (&ProviderConfig{AuthType: AuthTypeBearer}).MissingSecrets())
+	assert.Empty(t, (&ProviderConfig{AuthType: AuthTypeBearer, Token: "x"}).MissingSecrets())
+	assert.Equal(t, []string{"password"}, (&ProviderConfig{AuthType: AuthTypeBasic, Username: "ci"}).MissingSecrets())
+	assert.Equal(t, []string{"apiKey"}, RedactSecrets(&ProviderConfig{AuthType: AuthTypeAPIKey, APIKey: "k"}).MissingSecrets())
+}
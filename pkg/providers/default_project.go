@@ -0,0 +1,27 @@
+package providers
+
+// DefaultProjectID returns the project commands use when no project is given:
+// DefaultProject, or the defaultProject setting that YouTrack providers were
+// configured with before it existed
+func (c *ProviderConfig) DefaultProjectID() string {
+	if c == nil {
+		return ""
+	}
+	if c.DefaultProject != "" {
+		return c.DefaultProject
+	}
+	project, _ := c.Settings["defaultProject"].(string)
+	return project
+}
+
+// ForProvider returns the filters to list the tasks of the named provider
+// with: the filters themselves, or a copy with the provider's project from
+// ProjectByProvider if ProjectID is empty
+func (f *TaskFilters) ForProvider(name string) *TaskFilters {
+	if f == nil || f.ProjectID != "" || f.ProjectByProvider[name] == "" {
+		return f
+	}
+	filters := *f
+	filters.ProjectID = f.ProjectByProvider[name]
+	return &filters
+}
@@ -0,0 +1,49 @@
+package providers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// projectListProvider records the project it was asked to list
+type projectListProvider struct {
+	TaskProvider
+	project string
+}
+
+func (p *projectListProvider) ListTasks(ctx context.Context, filters *TaskFilters) ([]*UniversalTask, error) {
+	p.project = filters.ProjectID
+	return nil, nil
+}
+
+func TestDefaultProject(t *testing.T) {
+	config := DefaultMultiProviderConfig()
+	config.Providers = map[string]*ProviderConfig{
+		"youtrack": {DefaultProject: "BACKEND", Settings: map[string]interface{}{"defaultProject": "OLD"}},
+		"legacy":   {Settings: map[string]interface{}{"defaultProject": "OPS"}},
+		"jira":     {},
+	}
+	registry := NewProviderRegistry(config, nil)
+
+	assert.Equal(t, "BACKEND", registry.DefaultProject("youtrack"))
+	assert.Equal(t, "OPS", registry.DefaultProject("legacy"), "the YouTrack setting is still honored")
+	assert.Equal(t, "", registry.DefaultProject("jira"))
+	assert.Equal(t, "", registry.DefaultProject("missing"))
+	assert.Equal(t, map[string]string{"youtrack": "BACKEND", "legacy": "OPS"}, registry.DefaultProjects())
+}
+
+func TestFiltersForProvider(t *testing.T) {
+	youtrack, jira := &projectListProvider{}, &projectListProvider{}
+	lookup := providerLookup(map[string]TaskProvider{"youtrack": youtrack, "jira": jira})
+	defaults := map[string]string{"youtrack": "BACKEND"}
+
+	FetchTasks(context.Background(), []string{"youtrack", "jira"}, lookup, &TaskFilters{ProjectByProvider: defaults}, 0)
+	assert.Equal(t, "BACKEND", youtrack.project)
+	assert.Equal(t, "", jira.project, "providers without a default project list all projects")
+
+	_, err := FetchTaskPage(context.Background(), []string{"youtrack"}, lookup, &TaskFilters{ProjectID: "OPS", ProjectByProvider: defaults}, 10, 0, "")
+	assert.NoError(t, err)
+	assert.Equal(t, "OPS", youtrack.project, "an explicit project wins")
+}
@@ -0,0 +1,169 @@
+package providers
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// FieldChange is a single field that a TaskUpdate would change
+type FieldChange struct {
+	Field string `json:"field"`
+	Old   string `json:"old"`
+	New   string `json:"new"`
+}
+
+// Multiline reports whether the change is better shown as a line diff
+func (c FieldChange) Multiline() bool {
+	return strings.Contains(c.Old, "\n") || strings.Contains(c.New, "\n")
+}
+
+// DiffTaskUpdate compares proposed updates with the current task.
+// Fields the update leaves unchanged are omitted.
+func DiffTaskUpdate(current *UniversalTask, updates *TaskUpdate) []FieldChange {
+	if current == nil || updates == nil {
+		return nil
+	}
+
+	var changes []FieldChange
+	add := func(field, oldValue, newValue string) {
+		if oldValue != newValue {
+			changes = append(changes, FieldChange{Field: field, Old: oldValue, New: newValue})
+		}
+	}
+
+	if updates.Title != nil {
+		add("title", current.Title, *updates.Title)
+	}
+	if updates.Description != nil {
+		add("description", current.Description, *updates.Description)
+	}
+	if updates.Status != nil {
+		add("status", current.Status.Name, updates.Status.Name)
+	}
+	if updates.Priority != nil {
+		add("priority", string(current.Priority), string(*updates.Priority))
+	}
+	if updates.Type != nil {
+		add("type", string(current.Type), string(*updates.Type))
+	}
+	if updates.AssigneeID != nil {
+		add("assignee", current.AssigneeID, *updates.AssigneeID)
+	}
+	if updates.DueDate != nil {
+		add("dueDate", formatDiffTime(current.DueDate), formatDiffTime(updates.DueDate))
+	}
+	if updates.Labels != nil {
+		add("labels", strings.Join(current.Labels, ", "), strings.Join(updates.Labels, ", "))
+	}
+	if updates.EstimatedTime != nil {
+		add("estimatedTime", formatDiffDuration(current.EstimatedTime), formatDiffDuration(updates.EstimatedTime))
+	}
+
+	keys := make([]string, 0, len(updates.CustomFields))
+	for key := range updates.CustomFields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		oldValue := ""
+		if value, exists := current.CustomFields[key]; exists && value != nil {
+			oldValue = fmt.Sprintf("%v", value)
+		}
+		newValue := ""
+		if value := updates.CustomFields[key]; value != nil {
+			newValue = fmt.Sprintf("%v", value)
+		}
+		add("customFields."+key, oldValue, newValue)
+	}
+
+	return changes
+}
+
+// FormatTaskDiff renders changes as text: "field: old → new" for short values
+// and a line diff for multiline values such as descriptions.
+func FormatTaskDiff(changes []FieldChange) string {
+	if len(changes) == 0 {
+		return "No changes\n"
+	}
+
+	var b strings.Builder
+	for _, change := range changes {
+		if !change.Multiline() {
+			fmt.Fprintf(&b, "%s: %s → %s\n", change.Field, displayDiffValue(change.Old), displayDiffValue(change.New))
+			continue
+		}
+
+		fmt.Fprintf(&b, "%s:\n", change.Field)
+		for _, line := range diffLines(strings.Split(change.Old, "\n"), strings.Split(change.New, "\n")) {
+			fmt.Fprintf(&b, "  %s\n", line)
+		}
+	}
+	return b.String()
+}
+
+// diffLines returns a line diff of a and b with "- ", "+ " and "  " prefixes
+func diffLines(a, b []string) []string {
+	// Longest common subsequence table
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var lines []string
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			lines = append(lines, "  "+a[i])
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			lines = append(lines, "- "+a[i])
+			i++
+		default:
+			lines = append(lines, "+ "+b[j])
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		lines = append(lines, "- "+a[i])
+	}
+	for ; j < len(b); j++ {
+		lines = append(lines, "+ "+b[j])
+	}
+	return lines
+}
+
+func displayDiffValue(value string) string {
+	if value == "" {
+		return "(empty)"
+	}
+	return fmt.Sprintf("%q", value)
+}
+
+func formatDiffTime(t *time.Time) string {
+	if t == nil || t.IsZero() {
+		return ""
+	}
+	return t.Format("2006-01-02")
+}
+
+func formatDiffDuration(d *time.Duration) string {
+	if d == nil {
+		return ""
+	}
+	return d.String()
+}
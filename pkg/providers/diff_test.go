@@ -0,0 +1,44 @@
+package providers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffTaskUpdate(t *testing.T) {
+	current := &UniversalTask{
+		Title:       "Fix login",
+		Description: "## Steps\n1. Open page\n2. Click login",
+		Status:      TaskStatus{ID: "open", Name: "Open"},
+		Priority:    TaskPriorityMedium,
+		Labels:      []string{"bug"},
+	}
+
+	t.Run("Only changed fields", func(t *testing.T) {
+		title := "Fix login"
+		priority := TaskPriorityHigh
+		status := TaskStatus{ID: "in_progress", Name: "In Progress"}
+
+		changes := DiffTaskUpdate(current, &TaskUpdate{Title: &title, Priority: &priority, Status: &status})
+
+		assert.Equal(t, []FieldChange{
+			{Field: "status", Old: "Open", New: "In Progress"},
+			{Field: "priority", Old: "medium", New: "high"},
+		}, changes)
+		assert.Equal(t, "status: \"Open\" → \"In Progress\"\npriority: \"medium\" → \"high\"\n", FormatTaskDiff(changes))
+	})
+
+	t.Run("Multiline description", func(t *testing.T) {
+		description := "## Steps\n1. Open page\n2. Click sign in"
+		changes := DiffTaskUpdate(current, &TaskUpdate{Description: &description})
+
+		assert.Len(t, changes, 1)
+		assert.Equal(t, "description:\n    ## Steps\n    1. Open page\n  - 2. Click login\n  + 2. Click sign in\n", FormatTaskDiff(changes))
+	})
+
+	t.Run("No changes", func(t *testing.T) {
+		assert.Empty(t, DiffTaskUpdate(current, &TaskUpdate{Labels: []string{"bug"}}))
+		assert.Equal(t, "No changes\n", FormatTaskDiff(nil))
+	})
+}
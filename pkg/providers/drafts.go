@@ -0,0 +1,215 @@
+package providers
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// TaskDraft is a task prepared locally and not yet submitted to a provider.
+// The task has no ID until it is created.
+type TaskDraft struct {
+	ID        string         `json:"id"`
+	Provider  string         `json:"provider,omitempty"` // Provider to submit to, if chosen when saving
+	Template  string         `json:"template,omitempty"` // Template the task was filled from
+	Task      *UniversalTask `json:"task"`
+	CreatedAt time.Time      `json:"createdAt"`
+	UpdatedAt time.Time      `json:"updatedAt"`
+}
+
+// TaskDraftStore keeps task drafts until they are submitted
+type TaskDraftStore struct {
+	mu     sync.RWMutex
+	path   string
+	drafts map[string]*TaskDraft // ID -> draft
+	logger *logrus.Logger
+}
+
+// TaskDraftsFile is the file name of the draft store in a config directory
+const TaskDraftsFile = "drafts.json"
+
+// NewTaskDraftStore creates a draft store backed by the given file.
+// An empty path keeps drafts in memory only.
+func NewTaskDraftStore(path string, logger *logrus.Logger) (*TaskDraftStore, error) {
+	if logger == nil {
+		logger = logrus.New()
+	}
+
+	store := &TaskDraftStore{
+		path:   path,
+		drafts: make(map[string]*TaskDraft),
+		logger: logger,
+	}
+
+	if err := store.load(); err != nil {
+		return nil, err
+	}
+
+	return store, nil
+}
+
+// Save stores a draft. A draft without an ID is added with a new one; a
+// draft with the ID of a stored one replaces it.
+func (s *TaskDraftStore) Save(draft *TaskDraft) (*TaskDraft, error) {
+	if draft.Task == nil || strings.TrimSpace(draft.Task.Title) == "" {
+		return nil, NewValidationError("draft needs a task with a title", nil)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	saved := *draft
+	task := *draft.Task
+	task.ID = "" // Assigned by the provider on submit
+	task.Key = ""
+	saved.Task = &task
+
+	now := time.Now()
+	if saved.ID == "" {
+		saved.ID = uuid.New().String()
+		saved.CreatedAt = now
+	} else if existing, exists := s.drafts[saved.ID]; exists {
+		saved.CreatedAt = existing.CreatedAt
+	} else {
+		return nil, NewProviderError(ErrorTypeNotFound, fmt.Sprintf("draft %s not found", saved.ID), nil)
+	}
+	saved.UpdatedAt = now
+	s.drafts[saved.ID] = &saved
+
+	s.logger.Debugf("Saved draft %s", saved.ID)
+	copied := copyTaskDraft(&saved)
+	return copied, s.save()
+}
+
+// Get returns a draft by its ID or a unique prefix of it
+func (s *TaskDraftStore) Get(id string) (*TaskDraft, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if id == "" {
+		return nil, NewValidationError("draft ID is required", nil)
+	}
+
+	var found *TaskDraft
+	for draftID, draft := range s.drafts {
+		if !strings.HasPrefix(draftID, id) {
+			continue
+		}
+		if found != nil {
+			return nil, NewValidationError(fmt.Sprintf("draft ID %s is ambiguous", id), nil)
+		}
+		found = draft
+	}
+	if found == nil {
+		return nil, NewProviderError(ErrorTypeNotFound, fmt.Sprintf("draft %s not found", id), nil)
+	}
+	return copyTaskDraft(found), nil
+}
+
+// List returns the drafts, oldest first
+func (s *TaskDraftStore) List() []*TaskDraft {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	drafts := make([]*TaskDraft, 0, len(s.drafts))
+	for _, draft := range s.drafts {
+		drafts = append(drafts, copyTaskDraft(draft))
+	}
+	sortTaskDrafts(drafts)
+	return drafts
+}
+
+// Delete removes a draft, e.g. once it was submitted
+func (s *TaskDraftStore) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.drafts[id]; !exists {
+		return NewProviderError(ErrorTypeNotFound, fmt.Sprintf("draft %s not found", id), nil)
+	}
+	delete(s.drafts, id)
+
+	s.logger.Debugf("Deleted draft %s", id)
+	return s.save()
+}
+
+func sortTaskDrafts(drafts []*TaskDraft) {
+	sort.Slice(drafts, func(i, j int) bool {
+		if !drafts[i].CreatedAt.Equal(drafts[j].CreatedAt) {
+			return drafts[i].CreatedAt.Before(drafts[j].CreatedAt)
+		}
+		return drafts[i].ID < drafts[j].ID
+	})
+}
+
+func copyTaskDraft(draft *TaskDraft) *TaskDraft {
+	copied := *draft
+	task := *draft.Task
+	task.Labels = append([]string(nil), draft.Task.Labels...)
+	copied.Task = &task
+	return &copied
+}
+
+// save persists drafts to disk. Must be called with the lock held.
+func (s *TaskDraftStore) save() error {
+	if s.path == "" {
+		return nil
+	}
+
+	all := make([]*TaskDraft, 0, len(s.drafts))
+	for _, draft := range s.drafts {
+		all = append(all, draft)
+	}
+	sortTaskDrafts(all)
+
+	data, err := json.MarshalIndent(all, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal drafts: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return fmt.Errorf("failed to create drafts directory: %w", err)
+	}
+	if err := os.WriteFile(s.path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write drafts: %w", err)
+	}
+
+	return nil
+}
+
+// load restores drafts from disk
+func (s *TaskDraftStore) load() error {
+	if s.path == "" {
+		return nil
+	}
+
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to read drafts: %w", err)
+	}
+
+	var all []*TaskDraft
+	if err := json.Unmarshal(data, &all); err != nil {
+		return fmt.Errorf("failed to parse drafts: %w", err)
+	}
+
+	for _, draft := range all {
+		if draft.Task == nil {
+			continue
+		}
+		s.drafts[draft.ID] = draft
+	}
+
+	return nil
+}
@@ -0,0 +1,70 @@
+package providers
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskDraftStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "drafts.json")
+
+	store, err := NewTaskDraftStore(path, nil)
+	require.NoError(t, err)
+
+	first, err := store.Save(&TaskDraft{Provider: "youtrack", Task: &UniversalTask{ID: "1-1", Key: "OPS-1", Title: "Rotate keys", Labels: []string{"security"}}})
+	require.NoError(t, err)
+	assert.NotEmpty(t, first.ID)
+	assert.Empty(t, first.Task.ID, "drafts get their ID from the provider on submit")
+	assert.Empty(t, first.Task.Key)
+
+	second, err := store.Save(&TaskDraft{Task: &UniversalTask{Title: "Update runbook"}})
+	require.NoError(t, err)
+
+	t.Run("Validation", func(t *testing.T) {
+		_, err := store.Save(&TaskDraft{Task: &UniversalTask{Title: "  "}})
+		assert.True(t, IsErrorType(err, ErrorTypeValidation))
+		_, err = store.Save(&TaskDraft{ID: "missing", Task: &UniversalTask{Title: "Edit"}})
+		assert.True(t, IsNotFoundError(err))
+	})
+
+	t.Run("Get by prefix", func(t *testing.T) {
+		draft, err := store.Get(first.ID[:8])
+		require.NoError(t, err)
+		assert.Equal(t, "Rotate keys", draft.Task.Title)
+
+		draft.Task.Labels[0] = "changed"
+		draft, err = store.Get(first.ID)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"security"}, draft.Task.Labels, "returned drafts are copies")
+
+		_, err = store.Get("zzz")
+		assert.True(t, IsNotFoundError(err))
+	})
+
+	t.Run("Update keeps creation time", func(t *testing.T) {
+		first.Task.Title = "Rotate API keys"
+		updated, err := store.Save(first)
+		require.NoError(t, err)
+		assert.Equal(t, first.CreatedAt, updated.CreatedAt)
+		assert.Len(t, store.List(), 2)
+	})
+
+	t.Run("Persistence", func(t *testing.T) {
+		restored, err := NewTaskDraftStore(path, nil)
+		require.NoError(t, err)
+		drafts := restored.List()
+		require.Len(t, drafts, 2)
+		assert.Equal(t, "Rotate API keys", drafts[0].Task.Title)
+		assert.Equal(t, "youtrack", drafts[0].Provider)
+		assert.Equal(t, second.ID, drafts[1].ID)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		require.NoError(t, store.Delete(second.ID))
+		assert.Len(t, store.List(), 1)
+		assert.True(t, IsNotFoundError(store.Delete(second.ID)))
+	})
+}
@@ -0,0 +1,119 @@
+package providers
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DueDateFormats describes the forms ParseDueDate accepts, for help texts
+const DueDateFormats = "YYYY-MM-DD, YYYY-MM-DDTHH:MM, RFC3339, today, tomorrow, +3d, +2w, +4h, eod or eow"
+
+var (
+	relativeDueDate  = regexp.MustCompile(`^\+(\d+)([hdw])$`)
+	ambiguousDueDate = regexp.MustCompile(`^\d{1,2}[./-]\d{1,2}[./-]\d{2,4}$`)
+)
+
+// ParseDueDate parses a due date given on the command line or to a tool.
+// Dates and times without a zone are taken in the zone of now, which should
+// be the configured time zone (see MultiProviderConfig.Timezone).
+//
+// Absolute forms are YYYY-MM-DD (the start of that day, like the date fields
+// of providers), YYYY-MM-DDTHH:MM and RFC3339. Relative forms are today,
+// tomorrow, +Nd and +Nw (the start of the day N days or weeks from today),
+// +Nh (N hours from now), eod (the end of today) and eow (the end of Sunday
+// of the current week). Dates like 01/06/2024, whose day and month order
+// depends on the region, are rejected instead of guessed.
+func ParseDueDate(value string, now time.Time) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	loc := now.Location()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+
+	switch strings.ToLower(value) {
+	case "":
+		return time.Time{}, NewValidationError("due date is empty, use "+DueDateFormats, nil)
+	case "today":
+		return today, nil
+	case "tomorrow":
+		return today.AddDate(0, 0, 1), nil
+	case "eod":
+		return endOfDay(today), nil
+	case "eow":
+		// Weeks run from Monday to Sunday
+		daysToSunday := (7 - int(today.Weekday())) % 7
+		return endOfDay(today.AddDate(0, 0, daysToSunday)), nil
+	}
+
+	if match := relativeDueDate.FindStringSubmatch(strings.ToLower(value)); match != nil {
+		n, err := strconv.Atoi(match[1])
+		if err != nil {
+			return time.Time{}, NewValidationError(fmt.Sprintf("invalid due date %q, the offset is too large", value), nil)
+		}
+		switch match[2] {
+		case "h":
+			return now.Add(time.Duration(n) * time.Hour), nil
+		case "d":
+			return today.AddDate(0, 0, n), nil
+		default:
+			return today.AddDate(0, 0, 7*n), nil
+		}
+	}
+
+	if date, err := time.Parse(time.RFC3339, value); err == nil {
+		return date.In(loc), nil
+	}
+	for _, layout := range []string{"2006-01-02", "2006-01-02T15:04", "2006-01-02 15:04"} {
+		if date, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return date, nil
+		}
+	}
+
+	if ambiguousDueDate.MatchString(value) {
+		return time.Time{}, NewValidationError(fmt.Sprintf("ambiguous due date %q, day and month order differs between regions; use YYYY-MM-DD", value), nil)
+	}
+	return time.Time{}, NewValidationError(fmt.Sprintf("invalid due date %q, use %s", value, DueDateFormats), nil)
+}
+
+// LoadTimezone returns the location of an IANA time zone name such as
+// Europe/Berlin. An empty name is the local time zone of the machine.
+func LoadTimezone(name string) (*time.Location, error) {
+	if name == "" {
+		return time.Local, nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, NewValidationError(fmt.Sprintf("unknown time zone %q, use an IANA name like Europe/Berlin", name), nil)
+	}
+	return loc, nil
+}
+
+// FilterByDueDate keeps the tasks whose due date lies within the due date
+// bounds of the filters: DueDateAfter is inclusive and DueDateBefore
+// exclusive, so a week is given by its Monday and the next Monday. Tasks
+// without a due date never match a bound.
+func (f *TaskFilters) FilterByDueDate(tasks []*UniversalTask) []*UniversalTask {
+	if f == nil || (f.DueDateAfter == nil && f.DueDateBefore == nil) {
+		return tasks
+	}
+
+	filtered := tasks[:0]
+	for _, task := range tasks {
+		if task.DueDate == nil {
+			continue
+		}
+		if f.DueDateAfter != nil && task.DueDate.Before(*f.DueDateAfter) {
+			continue
+		}
+		if f.DueDateBefore != nil && !task.DueDate.Before(*f.DueDateBefore) {
+			continue
+		}
+		filtered = append(filtered, task)
+	}
+	return filtered
+}
+
+func endOfDay(day time.Time) time.Time {
+	return day.AddDate(0, 0, 1).Add(-time.Second)
+}
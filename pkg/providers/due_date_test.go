@@ -0,0 +1,111 @@
+package providers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDueDate(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+	// Wednesday afternoon
+	now := time.Date(2024, 5, 29, 15, 30, 0, 0, berlin)
+
+	tests := []struct {
+		value string
+		want  time.Time
+	}{
+		{"2024-06-01", time.Date(2024, 6, 1, 0, 0, 0, 0, berlin)},
+		{"2024-06-01T17:00", time.Date(2024, 6, 1, 17, 0, 0, 0, berlin)},
+		{"2024-06-01 17:00", time.Date(2024, 6, 1, 17, 0, 0, 0, berlin)},
+		{"2024-06-01T12:00:00Z", time.Date(2024, 6, 1, 14, 0, 0, 0, berlin)},
+		{"today", time.Date(2024, 5, 29, 0, 0, 0, 0, berlin)},
+		{"Tomorrow", time.Date(2024, 5, 30, 0, 0, 0, 0, berlin)},
+		{"+3d", time.Date(2024, 6, 1, 0, 0, 0, 0, berlin)},
+		{"+2w", time.Date(2024, 6, 12, 0, 0, 0, 0, berlin)},
+		{"+4h", time.Date(2024, 5, 29, 19, 30, 0, 0, berlin)},
+		{"eod", time.Date(2024, 5, 29, 23, 59, 59, 0, berlin)},
+		{"EOW", time.Date(2024, 6, 2, 23, 59, 59, 0, berlin)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseDueDate(tt.value, now)
+			require.NoError(t, err)
+			assert.True(t, tt.want.Equal(got), "got %s, want %s", got, tt.want)
+			assert.Equal(t, berlin, got.Location())
+		})
+	}
+
+	t.Run("End of week on Sunday", func(t *testing.T) {
+		sunday := time.Date(2024, 6, 2, 10, 0, 0, 0, berlin)
+		got, err := ParseDueDate("eow", sunday)
+		require.NoError(t, err)
+		assert.True(t, time.Date(2024, 6, 2, 23, 59, 59, 0, berlin).Equal(got))
+	})
+
+	t.Run("Across a daylight saving change", func(t *testing.T) {
+		saturday := time.Date(2024, 3, 30, 12, 0, 0, 0, berlin)
+		got, err := ParseDueDate("+1d", saturday)
+		require.NoError(t, err)
+		assert.True(t, time.Date(2024, 3, 31, 0, 0, 0, 0, berlin).Equal(got))
+	})
+
+	t.Run("Ambiguous and invalid dates", func(t *testing.T) {
+		for _, value := range []string{"01/06/2024", "1.6.2024", "06-01-24"} {
+			_, err := ParseDueDate(value, now)
+			require.Error(t, err, value)
+			assert.Contains(t, err.Error(), "ambiguous", value)
+			assert.True(t, IsErrorType(err, ErrorTypeValidation), value)
+		}
+		for _, value := range []string{"", "next friday", "+3m", "2024-13-01"} {
+			_, err := ParseDueDate(value, now)
+			require.Error(t, err, value)
+			assert.NotContains(t, err.Error(), "ambiguous", value)
+		}
+	})
+}
+
+func TestLoadTimezone(t *testing.T) {
+	loc, err := LoadTimezone("")
+	require.NoError(t, err)
+	assert.Equal(t, time.Local, loc)
+
+	loc, err = LoadTimezone("America/New_York")
+	require.NoError(t, err)
+	assert.Equal(t, "America/New_York", loc.String())
+
+	_, err = LoadTimezone("Mars/Olympus")
+	assert.True(t, IsErrorType(err, ErrorTypeValidation))
+}
+
+func TestFilterByDueDate(t *testing.T) {
+	day := func(d int) *time.Time {
+		date := time.Date(2024, 6, d, 0, 0, 0, 0, time.UTC)
+		return &date
+	}
+	tasks := []*UniversalTask{
+		{ID: "a", DueDate: day(1)},
+		{ID: "b", DueDate: day(5)},
+		{ID: "c", DueDate: day(8)},
+		{ID: "d"},
+	}
+
+	ids := func(tasks []*UniversalTask) []string {
+		var ids []string
+		for _, task := range tasks {
+			ids = append(ids, task.ID)
+		}
+		return ids
+	}
+
+	week := &TaskFilters{DueDateAfter: day(1), DueDateBefore: day(8)}
+	assert.Equal(t, []string{"a", "b"}, ids(week.FilterByDueDate(append([]*UniversalTask{}, tasks...))))
+
+	after := &TaskFilters{DueDateAfter: day(5)}
+	assert.Equal(t, []string{"b", "c"}, ids(after.FilterByDueDate(append([]*UniversalTask{}, tasks...))))
+
+	assert.Len(t, (&TaskFilters{}).FilterByDueDate(append([]*UniversalTask{}, tasks...)), 4)
+}
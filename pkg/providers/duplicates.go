@@ -0,0 +1,221 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+const (
+	// DefaultDuplicateThreshold is the similarity from which a task is reported as a possible duplicate
+	DefaultDuplicateThreshold = 0.5
+
+	// maxDuplicateCandidates bounds how many possible duplicates are reported
+	maxDuplicateCandidates = 5
+
+	// duplicateSearchKeywords bounds how many title keywords are searched for
+	duplicateSearchKeywords = 3
+
+	// duplicateSearchLimit bounds the results of each keyword search
+	duplicateSearchLimit = 20
+)
+
+// TaskLinkType is the kind of a link between two tasks
+type TaskLinkType string
+
+const (
+	TaskLinkDuplicateOf TaskLinkType = "duplicate_of"
+	TaskLinkSubtaskOf   TaskLinkType = "subtask_of"
+	TaskLinkDependsOn   TaskLinkType = "depends_on"
+	TaskLinkBlocks      TaskLinkType = "blocks"
+)
+
+// LinkProvider is implemented by providers that can link tasks to each other
+type LinkProvider interface {
+	// LinkTasks links the source task to the target, e.g. marks source as a duplicate of target
+	LinkTasks(ctx context.Context, sourceID, targetID string, linkType TaskLinkType) error
+}
+
+// UnlinkProvider is implemented by providers that can remove links between tasks
+type UnlinkProvider interface {
+	// UnlinkTasks removes the link of the given type from the source task to the target
+	UnlinkTasks(ctx context.Context, sourceID, targetID string, linkType TaskLinkType) error
+}
+
+// DuplicateCandidate is an existing task that may describe the same thing as a new one
+type DuplicateCandidate struct {
+	Task  *UniversalTask `json:"task"`
+	Score float64        `json:"score"` // Similarity from 0 to 1
+}
+
+// DuplicateScorer scores how similar existing tasks are to a new one, from 0
+// to 1, e.g. with an AI chain. It returns one score per candidate.
+type DuplicateScorer func(ctx context.Context, task *UniversalTask, candidates []*UniversalTask) ([]float64, error)
+
+// DuplicateOptions tunes the duplicate search
+type DuplicateOptions struct {
+	Threshold float64         // Minimum similarity, DefaultDuplicateThreshold if 0
+	Scorer    DuplicateScorer // Optional, replaces the word similarity of candidates
+}
+
+// FindDuplicates searches the provider for tasks similar to a task that is
+// about to be created. The most significant words of its title are searched
+// for, and the results are ranked by the similarity of their titles and
+// descriptions, or by the scorer if one is given. A scorer that fails falls
+// back to the word similarity.
+func FindDuplicates(ctx context.Context, provider TaskProvider, task *UniversalTask, options DuplicateOptions) ([]*DuplicateCandidate, error) {
+	threshold := options.Threshold
+	if threshold <= 0 {
+		threshold = DefaultDuplicateThreshold
+	}
+
+	keywords := significantWords(task.Title)
+	if len(keywords) == 0 {
+		return nil, nil
+	}
+	// Longer words tell tasks apart better than short ones
+	sort.SliceStable(keywords, func(i, j int) bool {
+		return len(keywords[i]) > len(keywords[j])
+	})
+	if len(keywords) > duplicateSearchKeywords {
+		keywords = keywords[:duplicateSearchKeywords]
+	}
+
+	seen := make(map[string]bool)
+	var found []*UniversalTask
+	for _, keyword := range keywords {
+		tasks, err := provider.ListTasks(ctx, &TaskFilters{ProjectID: task.ProjectID, Query: keyword, Limit: duplicateSearchLimit})
+		if err != nil {
+			return nil, fmt.Errorf("failed to search for duplicates: %w", err)
+		}
+		for _, candidate := range tasks {
+			if id := candidate.GetDisplayID(); !seen[id] {
+				seen[id] = true
+				found = append(found, candidate)
+			}
+		}
+	}
+	if len(found) == 0 {
+		return nil, nil
+	}
+
+	scores := make([]float64, len(found))
+	for i, candidate := range found {
+		scores[i] = TaskSimilarity(task, candidate)
+	}
+	if options.Scorer != nil {
+		if scored, err := options.Scorer(ctx, task, found); err == nil && len(scored) == len(found) {
+			scores = scored
+		}
+	}
+
+	var candidates []*DuplicateCandidate
+	for i, candidate := range found {
+		if scores[i] >= threshold {
+			candidates = append(candidates, &DuplicateCandidate{Task: candidate, Score: scores[i]})
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Score > candidates[j].Score
+	})
+	if len(candidates) > maxDuplicateCandidates {
+		candidates = candidates[:maxDuplicateCandidates]
+	}
+	return candidates, nil
+}
+
+// TaskSimilarity compares the words of two tasks, from 0 for nothing in common
+// to 1 for the same words. Titles count three times as much as descriptions.
+func TaskSimilarity(a, b *UniversalTask) float64 {
+	titles := wordSimilarity(significantWords(a.Title), significantWords(b.Title))
+	if a.Description == "" || b.Description == "" {
+		return titles
+	}
+	descriptions := wordSimilarity(significantWords(a.Description), significantWords(b.Description))
+	return (3*titles + descriptions) / 4
+}
+
+// wordSimilarity is the share of the shorter word list found in the longer one,
+// so a short title is similar to a longer one that repeats it
+func wordSimilarity(a, b []string) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	if len(a) > len(b) {
+		a, b = b, a
+	}
+	words := make(map[string]bool, len(b))
+	for _, word := range b {
+		words[stemWord(word)] = true
+	}
+	common := 0
+	for _, word := range a {
+		if words[stemWord(word)] {
+			common++
+		}
+	}
+	return float64(common) / float64(len(a))
+}
+
+// stopWords are too common to tell tasks apart
+var stopWords = map[string]bool{
+	"the": true, "and": true, "for": true, "with": true, "from": true, "into": true,
+	"when": true, "not": true, "does": true, "doesn": true, "are": true, "was": true,
+	"that": true, "this": true, "after": true, "before": true, "should": true, "can": true,
+}
+
+// significantWords returns the distinct lowercase words of a text without stop
+// words and words shorter than three letters
+func significantWords(text string) []string {
+	seen := make(map[string]bool)
+	var words []string
+	for _, word := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if len(word) < 3 || stopWords[word] || seen[word] {
+			continue
+		}
+		seen[word] = true
+		words = append(words, word)
+	}
+	return words
+}
+
+// stemWord drops plural endings, so "crashes" and "crash" match
+func stemWord(word string) string {
+	for _, suffix := range []string{"ches", "shes", "sses", "xes"} {
+		if strings.HasSuffix(word, suffix) {
+			return strings.TrimSuffix(word, "es")
+		}
+	}
+	if strings.HasSuffix(word, "s") && !strings.HasSuffix(word, "ss") && len(word) > 3 {
+		return strings.TrimSuffix(word, "s")
+	}
+	return word
+}
+
+// CreateAsDuplicate creates a task and links it as a duplicate of an existing
+// one, which trackers like YouTrack resolve as a duplicate. The provider must
+// be able to link tasks; this is checked before anything is created. If only
+// the link fails, the created task is returned with the error.
+func CreateAsDuplicate(ctx context.Context, provider TaskProvider, task *UniversalTask, duplicateOf string) (*UniversalTask, error) {
+	linker, ok := ProviderAs[LinkProvider](provider)
+	if !ok {
+		return nil, NewValidationError("provider can't link tasks as duplicates", nil)
+	}
+	if _, err := provider.GetTask(ctx, duplicateOf); err != nil {
+		return nil, fmt.Errorf("failed to get task %s: %w", duplicateOf, err)
+	}
+
+	created, err := provider.CreateTask(ctx, task)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create task: %w", err)
+	}
+	if err := linker.LinkTasks(ctx, created.GetDisplayID(), duplicateOf, TaskLinkDuplicateOf); err != nil {
+		return created, fmt.Errorf("created %s but failed to link it as a duplicate of %s: %w", created.GetDisplayID(), duplicateOf, err)
+	}
+	created.DuplicateOf = duplicateOf
+	return created, nil
+}
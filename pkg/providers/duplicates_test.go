@@ -0,0 +1,107 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// searchProvider finds its tasks by the words of their titles and records links
+type searchProvider struct {
+	flakyProvider
+	queries []string
+	links   map[string]string
+}
+
+func (p *searchProvider) ListTasks(ctx context.Context, filters *TaskFilters) ([]*UniversalTask, error) {
+	p.queries = append(p.queries, filters.Query)
+	var found []*UniversalTask
+	for _, task := range p.tasks {
+		if strings.Contains(strings.ToLower(task.Title), filters.Query) {
+			found = append(found, task)
+		}
+	}
+	return found, nil
+}
+
+func (p *searchProvider) GetTask(ctx context.Context, id string) (*UniversalTask, error) {
+	for _, task := range p.tasks {
+		if task.ID == id {
+			return task, nil
+		}
+	}
+	return nil, ErrTaskNotFound
+}
+
+func (p *searchProvider) LinkTasks(ctx context.Context, sourceID, targetID string, linkType TaskLinkType) error {
+	if p.links == nil {
+		p.links = make(map[string]string)
+	}
+	p.links[sourceID] = targetID
+	return nil
+}
+
+func TestFindDuplicates(t *testing.T) {
+	provider := &searchProvider{flakyProvider: flakyProvider{tasks: []*UniversalTask{
+		{ID: "OPS-1", Title: "Login crashes on prod"},
+		{ID: "OPS-2", Title: "Login page is slow"},
+		{ID: "OPS-3", Title: "Rotate certificates"},
+	}}}
+	task := &UniversalTask{Title: "Login crash in production", ProjectID: "OPS"}
+
+	t.Run("Similar titles are candidates", func(t *testing.T) {
+		candidates, err := FindDuplicates(context.Background(), provider, task, DuplicateOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"production", "login", "crash"}, provider.queries)
+		require.Len(t, candidates, 1)
+		assert.Equal(t, "OPS-1", candidates[0].Task.ID)
+		assert.InDelta(t, 2.0/3, candidates[0].Score, 0.01)
+	})
+
+	t.Run("Scorer replaces word similarity", func(t *testing.T) {
+		scorer := func(ctx context.Context, task *UniversalTask, candidates []*UniversalTask) ([]float64, error) {
+			scores := make([]float64, len(candidates))
+			for i, candidate := range candidates {
+				if candidate.ID == "OPS-2" {
+					scores[i] = 0.9
+				}
+			}
+			return scores, nil
+		}
+		candidates, err := FindDuplicates(context.Background(), provider, task, DuplicateOptions{Scorer: scorer})
+		require.NoError(t, err)
+		require.Len(t, candidates, 1)
+		assert.Equal(t, "OPS-2", candidates[0].Task.ID)
+
+		failing := func(ctx context.Context, task *UniversalTask, candidates []*UniversalTask) ([]float64, error) {
+			return nil, errors.New("model unavailable")
+		}
+		candidates, err = FindDuplicates(context.Background(), provider, task, DuplicateOptions{Scorer: failing})
+		require.NoError(t, err)
+		require.Len(t, candidates, 1)
+		assert.Equal(t, "OPS-1", candidates[0].Task.ID)
+	})
+
+	t.Run("Descriptions count less than titles", func(t *testing.T) {
+		a := &UniversalTask{Title: "Login crashes", Description: "Stack trace attached"}
+		b := &UniversalTask{Title: "Login crash", Description: "Happens on Safari"}
+		assert.InDelta(t, 0.75, TaskSimilarity(a, b), 0.01)
+	})
+
+	t.Run("Created tasks are linked as duplicates", func(t *testing.T) {
+		created, err := CreateAsDuplicate(context.Background(), provider, task, "OPS-1")
+		require.NoError(t, err)
+		assert.Equal(t, "OPS-1", created.DuplicateOf)
+		assert.Equal(t, "OPS-1", provider.links[created.ID])
+
+		_, err = CreateAsDuplicate(context.Background(), provider, task, "OPS-404")
+		assert.True(t, IsErrorType(err, ErrorTypeNotFound))
+
+		_, err = CreateAsDuplicate(context.Background(), &flakyProvider{}, task, "OPS-1")
+		assert.True(t, IsErrorType(err, ErrorTypeValidation))
+	})
+}
@@ -0,0 +1,112 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// EpicTempID is the temporary ID children passed to CreateEpic can use to
+// reference the epic, e.g. in blockedBy
+const EpicTempID = TempIDPrefix + "epic"
+
+// epicRollbackTimeout bounds rolling back a failed CreateEpic
+const epicRollbackTimeout = 30 * time.Second
+
+// EpicCreateResult is the outcome of CreateEpic
+type EpicCreateResult struct {
+	Epic     *BulkCreateResult   `json:"epic"`
+	Children []*BulkCreateResult `json:"children"`
+	// RolledBack lists the tasks deleted again after a failure
+	RolledBack []string `json:"rolledBack,omitempty"`
+	// RollbackErrors lists the tasks that could not be deleted
+	RollbackErrors []string `json:"rollbackErrors,omitempty"`
+}
+
+// EpicTasks returns the epic followed by its children as CreateEpic creates
+// them, ready for PlanBulkCreate: the epic gets EpicTempID unless it already
+// has a temporary ID, children without a parent become its subtasks and all
+// of them get it as EpicID and inherit its project. The given tasks are not
+// changed.
+func EpicTasks(epic *UniversalTask, children []*UniversalTask) ([]*UniversalTask, error) {
+	if epic == nil || strings.TrimSpace(epic.Title) == "" {
+		return nil, NewValidationError("epic title is required", nil)
+	}
+	if len(children) == 0 {
+		return nil, NewValidationError("the epic has no child tasks", nil)
+	}
+
+	parent := *epic
+	if !IsTempID(parent.ID) {
+		parent.ID = EpicTempID
+	}
+	if parent.Type == "" {
+		parent.Type = TaskTypeEpic
+	}
+
+	tasks := []*UniversalTask{&parent}
+	for i, child := range children {
+		if child == nil {
+			return nil, NewValidationError(fmt.Sprintf("child task %d is empty", i+1), nil)
+		}
+		task := *child
+		if task.ProjectID == "" {
+			task.ProjectID = parent.ProjectID
+		}
+		if task.ParentID == "" {
+			task.ParentID = parent.ID
+		}
+		if task.EpicID == "" {
+			task.EpicID = parent.ID
+		}
+		tasks = append(tasks, &task)
+	}
+	return tasks, nil
+}
+
+// CreateEpic creates an epic and its child tasks in one operation. Children
+// can reference each other with temporary IDs as in a bulk-create file and
+// the epic with EpicTempID, see EpicTasks; they are created in dependency
+// order and linked by CreateWithRelations. If creating or linking any task
+// fails and rollback is set, the tasks created so far are deleted again,
+// children first, as far as the provider allows.
+func CreateEpic(ctx context.Context, provider TaskProvider, epic *UniversalTask, children []*UniversalTask, rollback bool) (*EpicCreateResult, error) {
+	tasks, err := EpicTasks(epic, children)
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := CreateWithRelations(ctx, provider, tasks)
+	if results == nil {
+		// Nothing was created, e.g. a reference to an unknown temporary ID
+		return nil, err
+	}
+
+	result := &EpicCreateResult{Epic: results[0], Children: results[1:]}
+	if err != nil && rollback {
+		result.rollBack(ctx, provider)
+	}
+	return result, err
+}
+
+// rollBack deletes the created tasks, the epic last so that no child is left
+// without it. It doesn't use the context of the creation, which is often the
+// reason of the failure, e.g. an exceeded --timeout, but its own deadline.
+func (r *EpicCreateResult) rollBack(ctx context.Context, provider TaskProvider) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), epicRollbackTimeout)
+	defer cancel()
+
+	results := append([]*BulkCreateResult{r.Epic}, r.Children...)
+	for i := len(results) - 1; i >= 0; i-- {
+		if results[i].Task == nil {
+			continue
+		}
+		id := results[i].Task.GetDisplayID()
+		if err := provider.DeleteTask(ctx, id); err != nil {
+			r.RollbackErrors = append(r.RollbackErrors, fmt.Sprintf("%s: %v", id, err))
+			continue
+		}
+		r.RolledBack = append(r.RolledBack, id)
+	}
+}
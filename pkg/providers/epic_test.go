@@ -0,0 +1,143 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// epicProvider records the tasks deleted by a rollback
+type epicProvider struct {
+	bulkProvider
+	deleted     []string
+	undeletable string
+}
+
+func (p *epicProvider) DeleteTask(ctx context.Context, id string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if _, ok := ctx.Deadline(); !ok {
+		return errors.New("rollback without deadline")
+	}
+	if id == p.undeletable {
+		return ErrTaskNotFound
+	}
+	p.deleted = append(p.deleted, id)
+	return nil
+}
+
+func TestEpicTasks(t *testing.T) {
+	children := []*UniversalTask{
+		{ID: "$tmp:api", Title: "API"},
+		{Title: "Form", BlockedBy: []string{"$tmp:api"}, ProjectID: "WEB"},
+		{Title: "Receipt", ParentID: "$tmp:api"},
+	}
+	tasks, err := EpicTasks(&UniversalTask{Title: "Checkout", ProjectID: "SHOP"}, children)
+	require.NoError(t, err)
+	require.Len(t, tasks, 4)
+
+	assert.Equal(t, EpicTempID, tasks[0].ID)
+	assert.Equal(t, TaskTypeEpic, tasks[0].Type)
+	assert.Equal(t, EpicTempID, tasks[1].ParentID)
+	assert.Equal(t, "SHOP", tasks[1].ProjectID)
+	assert.Equal(t, "WEB", tasks[2].ProjectID, "a child keeps its own project")
+	assert.Equal(t, "$tmp:api", tasks[3].ParentID, "a child keeps its own parent")
+	assert.Equal(t, EpicTempID, tasks[3].EpicID)
+	assert.Empty(t, children[0].ParentID, "the given tasks are not changed")
+
+	_, err = EpicTasks(&UniversalTask{Title: " "}, children)
+	assert.True(t, IsErrorType(err, ErrorTypeValidation))
+	_, err = EpicTasks(&UniversalTask{Title: "Checkout"}, nil)
+	assert.True(t, IsErrorType(err, ErrorTypeValidation))
+}
+
+func TestCreateEpic(t *testing.T) {
+	epic := &UniversalTask{Title: "Checkout", ProjectID: "OPS"}
+	children := []*UniversalTask{
+		{ID: "$tmp:api", Title: "API"},
+		{Title: "Form", BlockedBy: []string{"$tmp:api"}},
+	}
+
+	t.Run("Children are wired to the epic", func(t *testing.T) {
+		provider := &epicProvider{}
+		result, err := CreateEpic(context.Background(), provider, epic, children, true)
+		require.NoError(t, err)
+
+		assert.Equal(t, "OPS-1", result.Epic.Task.GetDisplayID())
+		require.Len(t, result.Children, 2)
+		form := provider.batches[2][0]
+		assert.Equal(t, "OPS-1", form.ParentID)
+		assert.Equal(t, "OPS-1", form.EpicID)
+		assert.Equal(t, []string{"OPS-2"}, form.BlockedBy)
+		assert.Equal(t, []string{"OPS-2 subtask_of OPS-1", "OPS-3 subtask_of OPS-1", "OPS-3 depends_on OPS-2"}, provider.links)
+		assert.Empty(t, result.RolledBack)
+	})
+
+	t.Run("A failed step rolls back the created tasks", func(t *testing.T) {
+		provider := &epicProvider{bulkProvider: bulkProvider{failAt: 3}, undeletable: "OPS-2"}
+		result, err := CreateEpic(context.Background(), provider, epic, children, true)
+		require.Error(t, err)
+		assert.Contains(t, result.Children[1].Error, "connection refused")
+
+		// Children first, the epic last; OPS-2 can't be deleted
+		assert.Equal(t, []string{"OPS-1"}, result.RolledBack)
+		assert.Equal(t, []string{"OPS-1"}, provider.deleted)
+		require.Len(t, result.RollbackErrors, 1)
+		assert.Contains(t, result.RollbackErrors[0], "OPS-2")
+	})
+
+	t.Run("A failure midway through a batch rolls back its created tasks", func(t *testing.T) {
+		// The epic is OPS-1, the children are one batch of which the
+		// second fails after the first was created as OPS-2
+		provider := &epicProvider{bulkProvider: bulkProvider{failAtTask: 3}}
+		siblings := []*UniversalTask{{Title: "API"}, {Title: "Form"}, {Title: "Receipt"}}
+		result, err := CreateEpic(context.Background(), provider, epic, siblings, true)
+		require.Error(t, err)
+
+		require.NotNil(t, result.Children[0].Task)
+		assert.Equal(t, "OPS-2", result.Children[0].Task.GetDisplayID())
+		assert.Contains(t, result.Children[1].Error, "connection refused")
+		assert.Contains(t, result.Children[2].Error, "connection refused")
+		assert.Equal(t, []string{"OPS-2", "OPS-1"}, provider.deleted, "no child is left without its epic")
+	})
+
+	t.Run("Rollback outlives the cancelled context of the creation", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		provider := &epicProvider{bulkProvider: bulkProvider{failAt: 3}}
+		result, err := CreateEpic(ctx, provider, epic, children, true)
+		require.Error(t, err)
+		assert.Equal(t, []string{"OPS-2", "OPS-1"}, provider.deleted)
+		assert.Empty(t, result.RollbackErrors)
+	})
+
+	t.Run("A failed link rolls back too", func(t *testing.T) {
+		provider := &epicProvider{}
+		result, err := CreateEpic(context.Background(), provider, epic, []*UniversalTask{{Title: "Form", Blocks: []string{"OPS-404"}}}, true)
+		var partial *PartialFailureError
+		require.True(t, errors.As(err, &partial))
+		assert.Equal(t, []string{"OPS-2", "OPS-1"}, provider.deleted)
+		assert.Equal(t, provider.deleted, result.RolledBack)
+	})
+
+	t.Run("Without rollback the created tasks stay", func(t *testing.T) {
+		provider := &epicProvider{bulkProvider: bulkProvider{failAt: 2}}
+		result, err := CreateEpic(context.Background(), provider, epic, children, false)
+		require.Error(t, err)
+		assert.NotNil(t, result.Epic.Task)
+		assert.Empty(t, provider.deleted)
+	})
+
+	t.Run("Invalid references create nothing", func(t *testing.T) {
+		provider := &epicProvider{}
+		result, err := CreateEpic(context.Background(), provider, epic, []*UniversalTask{{Title: "Form", ParentID: "$tmp:missing"}}, true)
+		require.Error(t, err)
+		assert.Nil(t, result)
+		assert.Empty(t, provider.batches)
+	})
+}
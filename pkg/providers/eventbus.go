@@ -0,0 +1,184 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// DefaultEventBufferSize is the per-subscriber queue length used when none is given
+const DefaultEventBufferSize = 256
+
+// EventBus is an in-process publish/subscribe hub for task events.
+// Every subscriber gets its own buffered queue and worker, so a slow
+// subscriber never blocks publishers or other subscribers.
+type EventBus struct {
+	mu            sync.RWMutex
+	subscriptions map[string]*eventSubscription
+	bufferSize    int
+	closed        bool
+	published     int64
+	dropped       int64
+	logger        *logrus.Logger
+	wg            sync.WaitGroup
+}
+
+type eventSubscription struct {
+	name     string
+	types    map[EventType]bool // empty means all events
+	callback EventCallback
+	queue    chan *UniversalEvent
+}
+
+// NewEventBus creates an event bus with the given per-subscriber buffer size
+func NewEventBus(bufferSize int, logger *logrus.Logger) *EventBus {
+	if bufferSize <= 0 {
+		bufferSize = DefaultEventBufferSize
+	}
+	if logger == nil {
+		logger = logrus.New()
+	}
+
+	return &EventBus{
+		subscriptions: make(map[string]*eventSubscription),
+		bufferSize:    bufferSize,
+		logger:        logger,
+	}
+}
+
+// Subscribe registers a named callback for the given event types.
+// With no types the subscriber receives every event.
+func (b *EventBus) Subscribe(name string, callback EventCallback, types ...EventType) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return fmt.Errorf("event bus is closed")
+	}
+	if _, exists := b.subscriptions[name]; exists {
+		return fmt.Errorf("subscriber already registered: %s", name)
+	}
+
+	sub := &eventSubscription{
+		name:     name,
+		types:    make(map[EventType]bool, len(types)),
+		callback: callback,
+		queue:    make(chan *UniversalEvent, b.bufferSize),
+	}
+	for _, eventType := range types {
+		sub.types[eventType] = true
+	}
+
+	b.subscriptions[name] = sub
+	b.wg.Add(1)
+	go b.dispatch(sub)
+
+	b.logger.Debugf("Event subscriber %s registered", name)
+	return nil
+}
+
+// Unsubscribe removes a subscriber. Events already queued for it are still delivered.
+func (b *EventBus) Unsubscribe(name string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if sub, exists := b.subscriptions[name]; exists {
+		delete(b.subscriptions, name)
+		close(sub.queue)
+	}
+}
+
+// Publish queues an event for every matching subscriber without blocking.
+// If a subscriber's queue is full the event is dropped for that subscriber.
+func (b *EventBus) Publish(event *UniversalEvent) {
+	if event == nil {
+		return
+	}
+	if event.ID == "" {
+		event.ID = fmt.Sprintf("evt-%d", time.Now().UnixNano())
+	}
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now()
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if b.closed {
+		return
+	}
+	atomic.AddInt64(&b.published, 1)
+
+	for _, sub := range b.subscriptions {
+		if len(sub.types) > 0 && !sub.types[event.Type] {
+			continue
+		}
+
+		select {
+		case sub.queue <- event:
+		default:
+			atomic.AddInt64(&b.dropped, 1)
+			b.logger.Warnf("Event queue of subscriber %s is full, dropping %s event for task %s",
+				sub.name, event.Type, event.TaskID)
+		}
+	}
+}
+
+// Stats returns the number of published and dropped events
+func (b *EventBus) Stats() (published, dropped int64) {
+	return atomic.LoadInt64(&b.published), atomic.LoadInt64(&b.dropped)
+}
+
+// Close stops accepting events and waits until queued events are delivered
+// or the context is done.
+func (b *EventBus) Close(ctx context.Context) error {
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return nil
+	}
+	b.closed = true
+	for name, sub := range b.subscriptions {
+		delete(b.subscriptions, name)
+		close(sub.queue)
+	}
+	b.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		b.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("event bus did not drain: %w", ctx.Err())
+	}
+}
+
+// dispatch delivers queued events to a single subscriber
+func (b *EventBus) dispatch(sub *eventSubscription) {
+	defer b.wg.Done()
+
+	for event := range sub.queue {
+		b.deliver(sub, event)
+	}
+}
+
+func (b *EventBus) deliver(sub *eventSubscription, event *UniversalEvent) {
+	defer func() {
+		if r := recover(); r != nil {
+			b.logger.Errorf("Event subscriber %s panicked on %s: %v", sub.name, event.Type, r)
+		}
+	}()
+
+	if err := sub.callback(event); err != nil {
+		b.logger.Errorf("Event subscriber %s failed on %s: %v", sub.name, event.Type, err)
+	}
+}
@@ -0,0 +1,132 @@
+package providers
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventBus(t *testing.T) {
+	t.Run("Delivers matching events", func(t *testing.T) {
+		bus := NewEventBus(8, nil)
+
+		var mu sync.Mutex
+		var all, statusOnly []EventType
+		require.NoError(t, bus.Subscribe("all", func(event *UniversalEvent) error {
+			mu.Lock()
+			defer mu.Unlock()
+			all = append(all, event.Type)
+			return nil
+		}))
+		require.NoError(t, bus.Subscribe("status", func(event *UniversalEvent) error {
+			mu.Lock()
+			defer mu.Unlock()
+			statusOnly = append(statusOnly, event.Type)
+			return nil
+		}, EventTypeTaskStatusChanged))
+
+		bus.Publish(&UniversalEvent{Type: EventTypeTaskCreated, TaskID: "T-1"})
+		bus.Publish(&UniversalEvent{Type: EventTypeTaskStatusChanged, TaskID: "T-1"})
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		require.NoError(t, bus.Close(ctx))
+
+		assert.Equal(t, []EventType{EventTypeTaskCreated, EventTypeTaskStatusChanged}, all)
+		assert.Equal(t, []EventType{EventTypeTaskStatusChanged}, statusOnly)
+	})
+
+	t.Run("Duplicate subscriber name", func(t *testing.T) {
+		bus := NewEventBus(1, nil)
+		noop := func(event *UniversalEvent) error { return nil }
+		require.NoError(t, bus.Subscribe("dup", noop))
+		assert.Error(t, bus.Subscribe("dup", noop))
+	})
+
+	t.Run("Slow subscriber does not block publishers", func(t *testing.T) {
+		bus := NewEventBus(1, nil)
+		release := make(chan struct{})
+		require.NoError(t, bus.Subscribe("slow", func(event *UniversalEvent) error {
+			<-release
+			return nil
+		}))
+
+		done := make(chan struct{})
+		go func() {
+			for i := 0; i < 10; i++ {
+				bus.Publish(&UniversalEvent{Type: EventTypeTaskUpdated, TaskID: "T-2"})
+			}
+			close(done)
+		}()
+
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("Publish blocked on a slow subscriber")
+		}
+
+		published, dropped := bus.Stats()
+		assert.Equal(t, int64(10), published)
+		assert.True(t, dropped > 0)
+
+		close(release)
+		require.NoError(t, bus.Close(context.Background()))
+	})
+}
+
+// stubTaskProvider implements only the operations exercised by the tests
+type stubTaskProvider struct {
+	TaskProvider
+	updated []string
+}
+
+func (s *stubTaskProvider) UpdateTask(ctx context.Context, id string, updates *TaskUpdate) error {
+	s.updated = append(s.updated, id)
+	return nil
+}
+
+func (s *stubTaskProvider) DeleteTask(ctx context.Context, id string) error {
+	return ErrTaskNotFound
+}
+
+func TestPublishingProvider(t *testing.T) {
+	stub := &stubTaskProvider{}
+	bus := NewEventBus(8, nil)
+
+	var mu sync.Mutex
+	var events []*UniversalEvent
+	require.NoError(t, bus.Subscribe("recorder", func(event *UniversalEvent) error {
+		mu.Lock()
+		defer mu.Unlock()
+		events = append(events, event)
+		return nil
+	}))
+
+	provider := NewPublishingProvider(stub, "youtrack-prod", bus)
+	ctx := WithActor(context.Background(), "alice")
+
+	status := TaskStatus{ID: "in_progress", Name: "In Progress"}
+	assignee := "bob"
+	updates := &TaskUpdate{Status: &status, AssigneeID: &assignee}
+
+	require.NoError(t, provider.UpdateTask(ctx, "PROJ-1", updates))
+	assert.Error(t, provider.DeleteTask(ctx, "PROJ-2"))
+
+	require.NoError(t, bus.Close(context.Background()))
+	assert.Equal(t, []string{"PROJ-1"}, stub.updated)
+
+	require.Len(t, events, 3)
+	assert.Equal(t, EventTypeTaskUpdated, events[0].Type)
+	assert.Equal(t, EventTypeTaskStatusChanged, events[1].Type)
+	assert.Equal(t, EventTypeTaskAssigned, events[2].Type)
+	for _, event := range events {
+		assert.Equal(t, "youtrack-prod", event.Source)
+		assert.Equal(t, "PROJ-1", event.TaskID)
+		assert.Equal(t, "alice", event.Data["actor"])
+	}
+	assert.Equal(t, "In Progress", events[1].Data["status"])
+}
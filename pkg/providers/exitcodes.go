@@ -0,0 +1,114 @@
+package providers
+
+import (
+	"errors"
+	"fmt"
+	"net"
+)
+
+// Exit codes of the CLI. Scripts can rely on them to tell failure classes apart.
+const (
+	ExitOK             = 0 // success
+	ExitFailure        = 1 // unclassified failure
+	ExitUsage          = 2 // invalid arguments, flags or input
+	ExitProviderError  = 3 // provider, authentication, network or configuration failure
+	ExitNotFound       = 4 // task, project or other resource not found
+	ExitPartialFailure = 5 // some items of a multi-item operation failed
+)
+
+// ErrorTyper is implemented by provider client errors that can be classified
+// into an ErrorType, e.g. errors carrying an HTTP status code
+type ErrorTyper interface {
+	ErrorType() ErrorType
+}
+
+// PartialFailureError reports a multi-item operation where only some items failed
+type PartialFailureError struct {
+	Failed int
+	Total  int
+	What   string
+}
+
+// NewPartialFailureError creates a PartialFailureError, e.g. "2 of 5 deletions failed"
+func NewPartialFailureError(failed, total int, what string) *PartialFailureError {
+	return &PartialFailureError{Failed: failed, Total: total, What: what}
+}
+
+func (e *PartialFailureError) Error() string {
+	return fmt.Sprintf("%d of %d %s failed", e.Failed, e.Total, e.What)
+}
+
+// ErrorTypeForStatus classifies an HTTP status code returned by a provider API
+func ErrorTypeForStatus(statusCode int) ErrorType {
+	switch {
+	case statusCode == 401:
+		return ErrorTypeUnauthorized
+	case statusCode == 403:
+		return ErrorTypeForbidden
+	case statusCode == 404:
+		return ErrorTypeNotFound
+	case statusCode == 429:
+		return ErrorTypeRateLimit
+	case statusCode == 502 || statusCode == 503 || statusCode == 504:
+		// Gateway errors and maintenance pages mean the provider is unreachable
+		return ErrorTypeNetwork
+	case statusCode >= 400 && statusCode < 500:
+		return ErrorTypeValidation
+	default:
+		return ErrorTypeInternal
+	}
+}
+
+// ExitCode maps an error returned by a command to the CLI exit code
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitOK
+	}
+
+	var partial *PartialFailureError
+	if errors.As(err, &partial) {
+		return ExitPartialFailure
+	}
+
+	if errorType, ok := ClassifyError(err); ok {
+		return exitCodeForType(errorType)
+	}
+
+	return ExitFailure
+}
+
+// ClassifyError returns the ErrorType of a provider error, an error
+// implementing ErrorTyper or a network error
+func ClassifyError(err error) (ErrorType, bool) {
+	var providerErr *ProviderError
+	if errors.As(err, &providerErr) {
+		return providerErr.Type, true
+	}
+
+	var typed ErrorTyper
+	if errors.As(err, &typed) {
+		return typed.ErrorType(), true
+	}
+
+	// Timeouts and connection failures are transient provider failures
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return ErrorTypeNetwork, true
+	}
+
+	return "", false
+}
+
+func exitCodeForType(errorType ErrorType) int {
+	switch errorType {
+	case ErrorTypeValidation, ErrorTypeUnsupported:
+		return ExitUsage
+	case ErrorTypeNotFound:
+		return ExitNotFound
+	case ErrorTypeUnauthorized, ErrorTypeForbidden, ErrorTypeRateLimit,
+		ErrorTypeNetwork, ErrorTypeInternal, ErrorTypeConfiguration:
+		return ExitProviderError
+	default:
+		return ExitFailure
+	}
+}
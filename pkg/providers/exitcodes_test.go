@@ -0,0 +1,47 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type statusError struct{ status int }
+
+func (e *statusError) Error() string        { return fmt.Sprintf("API error %d", e.status) }
+func (e *statusError) ErrorType() ErrorType { return ErrorTypeForStatus(e.status) }
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"Success", nil, ExitOK},
+		{"Unclassified", errors.New("boom"), ExitFailure},
+		{"Validation", NewValidationError("title is required", nil), ExitUsage},
+		{"Wrapped not found", fmt.Errorf("failed to get task: %w", ErrTaskNotFound), ExitNotFound},
+		{"Unauthorized", ErrUnauthorized, ExitProviderError},
+		{"Rate limited", ErrRateLimited, ExitProviderError},
+		{"Configuration", NewProviderError(ErrorTypeConfiguration, "provider not found: yt", nil), ExitProviderError},
+		{"Client 401", fmt.Errorf("failed to list: %w", &statusError{401}), ExitProviderError},
+		{"Client 404", &statusError{404}, ExitNotFound},
+		{"Client 400", &statusError{400}, ExitUsage},
+		{"Client 503", &statusError{503}, ExitProviderError},
+		{"Network", &url.Error{Op: "Get", URL: "https://yt.example.com", Err: errors.New("connection refused")}, ExitProviderError},
+		{"Timeout", fmt.Errorf("failed to get task: %w", context.DeadlineExceeded), ExitProviderError},
+		{"Partial", fmt.Errorf("sync: %w", NewPartialFailureError(2, 5, "deletions")), ExitPartialFailure},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ExitCode(tt.err))
+		})
+	}
+
+	assert.Equal(t, "2 of 5 deletions failed", NewPartialFailureError(2, 5, "deletions").Error())
+}
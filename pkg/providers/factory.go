@@ -0,0 +1,95 @@
+package providers
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// PluginFactory is a function that creates a new plugin instance
+type PluginFactory func() TaskManagerPlugin
+
+// DefaultConfigProvider is implemented by plugins that provide type-specific
+// defaults for new provider configurations
+type DefaultConfigProvider interface {
+	DefaultConfig() *ProviderConfig
+}
+
+var (
+	pluginFactoriesMu     sync.RWMutex
+	globalPluginFactories = make(map[ProviderType]PluginFactory)
+)
+
+// RegisterProviderType registers the plugin constructor for a provider type.
+// Provider packages call it from init; registering a type twice panics.
+func RegisterProviderType(providerType ProviderType, factory PluginFactory) {
+	pluginFactoriesMu.Lock()
+	defer pluginFactoriesMu.Unlock()
+
+	if factory == nil {
+		panic("providers: RegisterProviderType factory is nil for " + string(providerType))
+	}
+	if _, exists := globalPluginFactories[providerType]; exists {
+		panic("providers: RegisterProviderType called twice for " + string(providerType))
+	}
+	globalPluginFactories[providerType] = factory
+}
+
+// RegisteredProviderTypes returns the registered provider types, sorted
+func RegisteredProviderTypes() []ProviderType {
+	pluginFactoriesMu.RLock()
+	defer pluginFactoriesMu.RUnlock()
+
+	types := make([]ProviderType, 0, len(globalPluginFactories))
+	for providerType := range globalPluginFactories {
+		types = append(types, providerType)
+	}
+	sort.Slice(types, func(i, j int) bool { return types[i] < types[j] })
+	return types
+}
+
+// IsProviderTypeRegistered reports whether a plugin is registered for a provider type
+func IsProviderTypeRegistered(providerType ProviderType) bool {
+	_, exists := pluginFactory(providerType)
+	return exists
+}
+
+// NewProviderConfig returns the default configuration for a provider type,
+// using the plugin's defaults when it provides them
+func NewProviderConfig(providerType ProviderType) *ProviderConfig {
+	if factory, exists := pluginFactory(providerType); exists {
+		if defaults, ok := factory().(DefaultConfigProvider); ok {
+			if config := defaults.DefaultConfig(); config != nil {
+				config.Type = providerType
+				return config
+			}
+		}
+	}
+
+	config := DefaultProviderConfig()
+	config.Type = providerType
+	return config
+}
+
+// newPlugin creates a plugin instance for a provider type
+func newPlugin(providerType ProviderType) (TaskManagerPlugin, error) {
+	factory, exists := pluginFactory(providerType)
+	if !exists {
+		return nil, unknownProviderTypeError(ErrorTypeConfiguration, providerType)
+	}
+	return factory(), nil
+}
+
+func pluginFactory(providerType ProviderType) (PluginFactory, bool) {
+	pluginFactoriesMu.RLock()
+	defer pluginFactoriesMu.RUnlock()
+
+	factory, exists := globalPluginFactories[providerType]
+	return factory, exists
+}
+
+// unknownProviderTypeError reports a provider type without a registered plugin
+func unknownProviderTypeError(errorType ErrorType, providerType ProviderType) error {
+	return NewProviderError(errorType,
+		fmt.Sprintf("no plugin registered for provider type %q (available: %v)", providerType, RegisteredProviderTypes()), nil)
+}
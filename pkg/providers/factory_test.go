@@ -0,0 +1,76 @@
+package providers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubPlugin is a plugin with type-specific defaults
+type stubPlugin struct {
+	TaskManagerPlugin
+}
+
+func (p *stubPlugin) DefaultConfig() *ProviderConfig {
+	config := DefaultProviderConfig()
+	config.AuthType = AuthTypeAPIKey
+	config.Settings = map[string]interface{}{"workspace": "main"}
+	return config
+}
+
+func TestRegisterProviderType(t *testing.T) {
+	const stubType ProviderType = "stub-factory"
+	RegisterProviderType(stubType, func() TaskManagerPlugin { return &stubPlugin{} })
+	t.Cleanup(func() {
+		pluginFactoriesMu.Lock()
+		delete(globalPluginFactories, stubType)
+		pluginFactoriesMu.Unlock()
+	})
+
+	t.Run("Registered types are listed", func(t *testing.T) {
+		assert.Contains(t, RegisteredProviderTypes(), stubType)
+		assert.True(t, IsProviderTypeRegistered(stubType))
+		assert.False(t, IsProviderTypeRegistered("unregistered"))
+	})
+
+	t.Run("Registering a type twice panics", func(t *testing.T) {
+		assert.Panics(t, func() {
+			RegisterProviderType(stubType, func() TaskManagerPlugin { return &stubPlugin{} })
+		})
+		assert.Panics(t, func() { RegisterProviderType("nil-factory", nil) })
+	})
+
+	t.Run("Default config comes from the plugin", func(t *testing.T) {
+		config := NewProviderConfig(stubType)
+		assert.Equal(t, stubType, config.Type)
+		assert.Equal(t, AuthTypeAPIKey, config.AuthType)
+		assert.Equal(t, "main", config.Settings["workspace"])
+
+		fallback := NewProviderConfig("unregistered")
+		assert.Equal(t, ProviderType("unregistered"), fallback.Type)
+		assert.True(t, fallback.Enabled)
+	})
+
+	t.Run("Unknown types are rejected", func(t *testing.T) {
+		quiet := logrus.New()
+		quiet.SetLevel(logrus.PanicLevel)
+		registry := NewProviderRegistry(&MultiProviderConfig{Providers: map[string]*ProviderConfig{}}, quiet)
+
+		config := NewProviderConfig("unregistered")
+		config.Name = "other"
+		config.BaseURL = "https://example.com"
+		config.AuthType = AuthTypeBearer
+		config.Token = "token"
+		config.Enabled = false
+
+		err := registry.AddProvider(context.Background(), "other", config)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), string(stubType))
+		assert.Equal(t, ExitUsage, ExitCode(err))
+
+		assert.Error(t, ProbeProvider(context.Background(), config))
+	})
+}
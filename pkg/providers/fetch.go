@@ -0,0 +1,152 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefaultFetchParallelism bounds how many providers are queried at once
+const DefaultFetchParallelism = 8
+
+// ProviderLookup resolves a provider by name, e.g. ProviderRegistry.GetProvider
+type ProviderLookup func(name string) (TaskProvider, error)
+
+// ProviderFailure is a provider that tasks could not be fetched from
+type ProviderFailure struct {
+	Provider string
+	Err      error
+}
+
+// FetchResult holds the tasks fetched from several providers
+type FetchResult struct {
+	Tasks    []*UniversalTask
+	Failures []ProviderFailure
+	TimedOut bool // The deadline of ctx fired before every provider answered
+}
+
+// IncompleteResultsError reports an aggregated fetch cut short by its deadline.
+// It unwraps to a PartialFailureError, so the tasks fetched in time are still
+// returned along with it.
+type IncompleteResultsError struct {
+	Fetched int
+	Total   int
+}
+
+func (e *IncompleteResultsError) Error() string {
+	return fmt.Sprintf("results incomplete due to timeout (fetched %d of %d providers)", e.Fetched, e.Total)
+}
+
+func (e *IncompleteResultsError) Unwrap() error {
+	return NewPartialFailureError(e.Total-e.Fetched, e.Total, "providers")
+}
+
+// FetchTasks lists tasks from the target providers concurrently, querying at
+// most parallelism providers at a time. All queries share ctx and its deadline.
+// Providers that fail are reported in Failures; the tasks of the others are
+// tagged with their provider name and sorted by provider, then key.
+func FetchTasks(ctx context.Context, targets []string, lookup ProviderLookup, filters *TaskFilters, parallelism int) *FetchResult {
+	if parallelism <= 0 {
+		parallelism = DefaultFetchParallelism
+	}
+
+	var (
+		mu     sync.Mutex
+		wg     sync.WaitGroup
+		result = &FetchResult{}
+		slots  = make(chan struct{}, parallelism)
+	)
+
+	for _, name := range targets {
+		wg.Add(1)
+		go func(name string) {
+			defer wg.Done()
+
+			select {
+			case slots <- struct{}{}:
+				defer func() { <-slots }()
+			case <-ctx.Done():
+				mu.Lock()
+				result.Failures = append(result.Failures, ProviderFailure{Provider: name, Err: ctx.Err()})
+				mu.Unlock()
+				return
+			}
+
+			tasks, err := fetchProviderTasks(ctx, name, lookup, filters)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				result.Failures = append(result.Failures, ProviderFailure{Provider: name, Err: err})
+				return
+			}
+			result.Tasks = append(result.Tasks, tasks...)
+		}(name)
+	}
+	wg.Wait()
+
+	result.TimedOut = len(result.Failures) > 0 && errors.Is(ctx.Err(), context.DeadlineExceeded)
+	SortTasksByProvider(result.Tasks)
+	sort.Slice(result.Failures, func(i, j int) bool {
+		return result.Failures[i].Provider < result.Failures[j].Provider
+	})
+
+	return result
+}
+
+func fetchProviderTasks(ctx context.Context, name string, lookup ProviderLookup, filters *TaskFilters) ([]*UniversalTask, error) {
+	provider, err := lookup(name)
+	if err != nil {
+		return nil, err
+	}
+
+	// "me" is a different user in every provider
+	providerFilters, err := ResolveAssigneeFilter(ctx, provider, filters.ForProvider(name))
+	if err != nil {
+		return nil, err
+	}
+
+	tasks, err := provider.ListTasks(ctx, providerFilters)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, task := range tasks {
+		task.ProviderName = name
+	}
+	return filters.FilterByDueDate(filters.FilterByLabels(tasks)), nil
+}
+
+// SortTasksByProvider sorts tasks by provider name, then by key, comparing the
+// numeric part of keys like PROJ-9 and PROJ-10 as numbers
+func SortTasksByProvider(tasks []*UniversalTask) {
+	sort.SliceStable(tasks, func(i, j int) bool {
+		if tasks[i].ProviderName != tasks[j].ProviderName {
+			return tasks[i].ProviderName < tasks[j].ProviderName
+		}
+		return lessTaskKey(tasks[i].GetDisplayID(), tasks[j].GetDisplayID())
+	})
+}
+
+func lessTaskKey(a, b string) bool {
+	prefixA, numA, okA := splitTaskKey(a)
+	prefixB, numB, okB := splitTaskKey(b)
+	if okA && okB && prefixA == prefixB && numA != numB {
+		return numA < numB
+	}
+	return a < b
+}
+
+// splitTaskKey splits a key like PROJ-123 into its prefix and number
+func splitTaskKey(key string) (string, int, bool) {
+	i := strings.LastIndexAny(key, "-#")
+	number, err := strconv.Atoi(key[i+1:])
+	if err != nil {
+		return "", 0, false
+	}
+	return key[:i+1], number, true
+}
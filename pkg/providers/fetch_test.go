@@ -0,0 +1,109 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// slowListProvider answers ListTasks after a fixed latency
+type slowListProvider struct {
+	TaskProvider
+	latency time.Duration
+	keys    []string
+	err     error
+}
+
+func (p *slowListProvider) ListTasks(ctx context.Context, filters *TaskFilters) ([]*UniversalTask, error) {
+	select {
+	case <-time.After(p.latency):
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	if p.err != nil {
+		return nil, p.err
+	}
+
+	tasks := make([]*UniversalTask, 0, len(p.keys))
+	for _, key := range p.keys {
+		tasks = append(tasks, &UniversalTask{Key: key})
+	}
+	return tasks, nil
+}
+
+func providerLookup(providers map[string]TaskProvider) ProviderLookup {
+	return func(name string) (TaskProvider, error) {
+		provider, ok := providers[name]
+		if !ok {
+			return nil, fmt.Errorf("provider not found: %s", name)
+		}
+		return provider, nil
+	}
+}
+
+func TestFetchTasks(t *testing.T) {
+	lookup := providerLookup(map[string]TaskProvider{
+		"youtrack": &slowListProvider{latency: 30 * time.Millisecond, keys: []string{"YT-10", "YT-9", "YT-100"}},
+		"jira":     &slowListProvider{latency: 10 * time.Millisecond, keys: []string{"JR-2", "JR-1"}},
+		"notion":   &slowListProvider{latency: 20 * time.Millisecond, err: errors.New("unauthorized")},
+	})
+
+	t.Run("Merges in deterministic order", func(t *testing.T) {
+		result := FetchTasks(context.Background(), []string{"youtrack", "notion", "jira", "missing"}, lookup, &TaskFilters{}, 0)
+
+		var keys []string
+		for _, task := range result.Tasks {
+			keys = append(keys, task.ProviderName+"/"+task.Key)
+		}
+		assert.Equal(t, []string{"jira/JR-1", "jira/JR-2", "youtrack/YT-9", "youtrack/YT-10", "youtrack/YT-100"}, keys)
+
+		require.Len(t, result.Failures, 2)
+		assert.False(t, result.TimedOut, "failures without a deadline are not timeouts")
+		assert.Equal(t, "missing", result.Failures[0].Provider)
+		assert.Equal(t, "notion", result.Failures[1].Provider)
+	})
+
+	t.Run("Shares the deadline", func(t *testing.T) {
+		lookup := providerLookup(map[string]TaskProvider{
+			"fast": &slowListProvider{latency: time.Millisecond, keys: []string{"F-1"}},
+			"slow": &slowListProvider{latency: time.Minute, keys: []string{"S-1"}},
+		})
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		result := FetchTasks(ctx, []string{"slow", "fast"}, lookup, &TaskFilters{}, 2)
+		assert.Len(t, result.Tasks, 1)
+		require.Len(t, result.Failures, 1)
+		assert.Equal(t, "slow", result.Failures[0].Provider)
+		assert.ErrorIs(t, result.Failures[0].Err, context.DeadlineExceeded)
+		assert.True(t, result.TimedOut)
+
+		err := &IncompleteResultsError{Fetched: 1, Total: 2}
+		assert.EqualError(t, err, "results incomplete due to timeout (fetched 1 of 2 providers)")
+		assert.Equal(t, ExitPartialFailure, ExitCode(err))
+	})
+}
+
+func BenchmarkFetchTasks(b *testing.B) {
+	all := make(map[string]TaskProvider)
+	var targets []string
+	for i := 0; i < 5; i++ {
+		name := fmt.Sprintf("provider-%d", i)
+		all[name] = &slowListProvider{latency: 5 * time.Millisecond, keys: []string{"P-1", "P-2"}}
+		targets = append(targets, name)
+	}
+	lookup := providerLookup(all)
+
+	for _, parallelism := range []int{1, DefaultFetchParallelism} {
+		b.Run(fmt.Sprintf("parallelism=%d", parallelism), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				FetchTasks(context.Background(), targets, lookup, &TaskFilters{}, parallelism)
+			}
+		})
+	}
+}
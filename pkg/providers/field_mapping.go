@@ -0,0 +1,331 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TaskField is a typed UniversalTask field that a provider custom field can
+// be promoted to
+type TaskField string
+
+const (
+	TaskFieldStoryPoints   TaskField = "storyPoints"   // Number, e.g. YouTrack "Story points"
+	TaskFieldEstimatedTime TaskField = "estimatedTime" // Duration
+	TaskFieldDueDate       TaskField = "dueDate"
+	TaskFieldStartDate     TaskField = "startDate"
+)
+
+// TaskFields lists the task fields custom fields can be mapped to
+var TaskFields = []TaskField{
+	TaskFieldStoryPoints,
+	TaskFieldEstimatedTime,
+	TaskFieldDueDate,
+	TaskFieldStartDate,
+}
+
+// IsValid reports whether the field is one of TaskFields
+func (f TaskField) IsValid() bool {
+	for _, field := range TaskFields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+// CustomFieldInfo describes a custom field defined in a provider
+type CustomFieldInfo struct {
+	Name string `json:"name" yaml:"name"`
+	Type string `json:"type,omitempty" yaml:"type,omitempty"` // Provider-specific, e.g. "integer" or "period"
+}
+
+// CustomFieldProvider is implemented by providers that can list the custom
+// fields they define, so field mappings can be checked against them
+type CustomFieldProvider interface {
+	ListCustomFields(ctx context.Context) ([]*CustomFieldInfo, error)
+}
+
+// FieldMapping maps provider custom field names to typed task fields, e.g.
+// "Story points" to storyPoints. Mapped fields are promoted out of
+// CustomFields when tasks are read and written back to the custom field when
+// tasks are created or updated. Field names are compared case-insensitively.
+type FieldMapping map[string]TaskField
+
+// Validate checks that every custom field is mapped to a known task field and
+// that no task field is mapped twice
+func (m FieldMapping) Validate() error {
+	mappedTo := make(map[TaskField]string)
+	for _, name := range m.names() {
+		field := m[name]
+		if strings.TrimSpace(name) == "" {
+			return NewValidationError("field mapping contains an empty field name", nil)
+		}
+		if !field.IsValid() {
+			known := make([]string, len(TaskFields))
+			for i, f := range TaskFields {
+				known[i] = string(f)
+			}
+			return NewValidationError(fmt.Sprintf("field %q is mapped to unknown task field %q, use one of %s", name, field, strings.Join(known, ", ")), nil)
+		}
+		if other, exists := mappedTo[field]; exists {
+			return NewValidationError(fmt.Sprintf("fields %q and %q are both mapped to %s", other, name, field), nil)
+		}
+		mappedTo[field] = name
+	}
+	return nil
+}
+
+// ValidateFields checks that every mapped custom field exists among the
+// fields discovered in the provider
+func (m FieldMapping) ValidateFields(discovered []*CustomFieldInfo) error {
+	var unknown []string
+	for _, name := range m.names() {
+		if findCustomField(discovered, name) == nil {
+			unknown = append(unknown, fmt.Sprintf("%q", name))
+		}
+	}
+	if len(unknown) == 0 {
+		return nil
+	}
+
+	available := make([]string, len(discovered))
+	for i, field := range discovered {
+		available[i] = field.Name
+	}
+	sort.Strings(available)
+	return NewValidationError(fmt.Sprintf("mapped fields not found in the provider: %s (available: %s)",
+		strings.Join(unknown, ", "), strings.Join(available, ", ")), nil)
+}
+
+// Promote moves the values of mapped custom fields into their typed task
+// fields. Values that can't be converted stay in CustomFields.
+func (m FieldMapping) Promote(task *UniversalTask) {
+	if len(m) == 0 || len(task.CustomFields) == 0 {
+		return
+	}
+
+	for _, name := range m.names() {
+		key, value, ok := lookupCustomField(task.CustomFields, name)
+		if !ok {
+			continue
+		}
+		if value == nil || setTaskField(task, m[name], value) {
+			delete(task.CustomFields, key)
+		}
+	}
+}
+
+// Demote moves the typed task fields that are mapped back into CustomFields,
+// as float64, time.Duration or time.Time values for the provider to encode
+func (m FieldMapping) Demote(task *UniversalTask) {
+	for _, name := range m.names() {
+		value, ok := taskFieldValue(task, m[name])
+		if !ok {
+			continue
+		}
+		if task.CustomFields == nil {
+			task.CustomFields = make(map[string]interface{})
+		}
+		task.CustomFields[name] = value
+		clearTaskField(task, m[name])
+	}
+}
+
+// DemoteUpdate moves the typed fields of an update that are mapped into its
+// CustomFields, like Demote
+func (m FieldMapping) DemoteUpdate(update *TaskUpdate) {
+	for _, name := range m.names() {
+		var value interface{}
+		switch m[name] {
+		case TaskFieldStoryPoints:
+			if update.StoryPoints != nil {
+				value = *update.StoryPoints
+				update.StoryPoints = nil
+			}
+		case TaskFieldEstimatedTime:
+			if update.EstimatedTime != nil {
+				value = *update.EstimatedTime
+				update.EstimatedTime = nil
+			}
+		case TaskFieldDueDate:
+			if update.DueDate != nil {
+				value = *update.DueDate
+				update.DueDate = nil
+			}
+		case TaskFieldStartDate:
+			if update.StartDate != nil {
+				value = *update.StartDate
+				update.StartDate = nil
+			}
+		}
+		if value == nil {
+			continue
+		}
+		if update.CustomFields == nil {
+			update.CustomFields = make(map[string]interface{})
+		}
+		update.CustomFields[name] = value
+	}
+}
+
+// names returns the mapped field names in a stable order
+func (m FieldMapping) names() []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func findCustomField(fields []*CustomFieldInfo, name string) *CustomFieldInfo {
+	for _, field := range fields {
+		if strings.EqualFold(strings.TrimSpace(field.Name), strings.TrimSpace(name)) {
+			return field
+		}
+	}
+	return nil
+}
+
+func lookupCustomField(fields map[string]interface{}, name string) (string, interface{}, bool) {
+	if value, ok := fields[name]; ok {
+		return name, value, true
+	}
+	for key, value := range fields {
+		if strings.EqualFold(strings.TrimSpace(key), strings.TrimSpace(name)) {
+			return key, value, true
+		}
+	}
+	return "", nil, false
+}
+
+// setTaskField converts a raw custom field value and stores it in a typed
+// task field. It reports whether the value could be converted.
+func setTaskField(task *UniversalTask, field TaskField, value interface{}) bool {
+	switch field {
+	case TaskFieldStoryPoints:
+		if points, ok := customFieldNumber(value); ok {
+			task.StoryPoints = &points
+			return true
+		}
+	case TaskFieldEstimatedTime:
+		if duration, ok := customFieldDuration(value); ok {
+			task.EstimatedTime = &duration
+			return true
+		}
+	case TaskFieldDueDate, TaskFieldStartDate:
+		date, ok := customFieldTime(value)
+		if !ok {
+			return false
+		}
+		if field == TaskFieldDueDate {
+			task.DueDate = &date
+		} else {
+			task.StartDate = &date
+		}
+		return true
+	}
+	return false
+}
+
+func taskFieldValue(task *UniversalTask, field TaskField) (interface{}, bool) {
+	switch field {
+	case TaskFieldStoryPoints:
+		if task.StoryPoints != nil {
+			return *task.StoryPoints, true
+		}
+	case TaskFieldEstimatedTime:
+		if task.EstimatedTime != nil {
+			return *task.EstimatedTime, true
+		}
+	case TaskFieldDueDate:
+		if task.DueDate != nil {
+			return *task.DueDate, true
+		}
+	case TaskFieldStartDate:
+		if task.StartDate != nil {
+			return *task.StartDate, true
+		}
+	}
+	return nil, false
+}
+
+func clearTaskField(task *UniversalTask, field TaskField) {
+	switch field {
+	case TaskFieldStoryPoints:
+		task.StoryPoints = nil
+	case TaskFieldEstimatedTime:
+		task.EstimatedTime = nil
+	case TaskFieldDueDate:
+		task.DueDate = nil
+	case TaskFieldStartDate:
+		task.StartDate = nil
+	}
+}
+
+// customFieldNumber reads numbers, numeric strings and values like
+// {"name": "5"} of enumeration fields
+func customFieldNumber(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case string:
+		number, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		return number, err == nil
+	case map[string]interface{}:
+		if name, ok := v["name"]; ok {
+			return customFieldNumber(name)
+		}
+	}
+	return 0, false
+}
+
+// customFieldDuration reads durations, minutes and period values like
+// {"minutes": 90}
+func customFieldDuration(value interface{}) (time.Duration, bool) {
+	switch v := value.(type) {
+	case time.Duration:
+		return v, true
+	case string:
+		duration, err := time.ParseDuration(strings.TrimSpace(v))
+		return duration, err == nil
+	case map[string]interface{}:
+		if minutes, ok := customFieldNumber(v["minutes"]); ok {
+			return time.Duration(minutes) * time.Minute, true
+		}
+	default:
+		if minutes, ok := customFieldNumber(value); ok {
+			return time.Duration(minutes) * time.Minute, true
+		}
+	}
+	return 0, false
+}
+
+// customFieldTime reads times, Unix timestamps in milliseconds and RFC 3339
+// or YYYY-MM-DD strings
+func customFieldTime(value interface{}) (time.Time, bool) {
+	switch v := value.(type) {
+	case time.Time:
+		return v, true
+	case string:
+		for _, layout := range []string{time.RFC3339, "2006-01-02"} {
+			if t, err := time.Parse(layout, strings.TrimSpace(v)); err == nil {
+				return t, true
+			}
+		}
+		return time.Time{}, false
+	}
+	if millis, ok := customFieldNumber(value); ok {
+		return time.UnixMilli(int64(millis)).UTC(), true
+	}
+	return time.Time{}, false
+}
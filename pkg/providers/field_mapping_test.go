@@ -0,0 +1,91 @@
+package providers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFieldMappingValidate(t *testing.T) {
+	assert.NoError(t, FieldMapping{"Story points": TaskFieldStoryPoints, "Estimation": TaskFieldEstimatedTime}.Validate())
+	assert.NoError(t, FieldMapping(nil).Validate())
+
+	err := FieldMapping{"Story points": "points"}.Validate()
+	require.Error(t, err)
+	assert.True(t, IsErrorType(err, ErrorTypeValidation))
+	assert.Contains(t, err.Error(), `unknown task field "points"`)
+
+	err = FieldMapping{"Story points": TaskFieldStoryPoints, "Points": TaskFieldStoryPoints}.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `fields "Points" and "Story points" are both mapped to storyPoints`)
+
+	assert.Error(t, FieldMapping{" ": TaskFieldDueDate}.Validate())
+}
+
+func TestFieldMappingValidateFields(t *testing.T) {
+	discovered := []*CustomFieldInfo{{Name: "Story points", Type: "integer"}, {Name: "Due Date", Type: "date"}}
+
+	assert.NoError(t, FieldMapping{"story points": TaskFieldStoryPoints, "Due Date": TaskFieldDueDate}.ValidateFields(discovered))
+
+	err := FieldMapping{"Story Points": TaskFieldStoryPoints, "Estimate": TaskFieldEstimatedTime}.ValidateFields(discovered)
+	require.Error(t, err)
+	assert.True(t, IsErrorType(err, ErrorTypeValidation))
+	assert.Contains(t, err.Error(), `"Estimate"`)
+	assert.Contains(t, err.Error(), "available: Due Date, Story points")
+}
+
+func TestFieldMappingPromote(t *testing.T) {
+	due := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	mapping := FieldMapping{
+		"Story points": TaskFieldStoryPoints,
+		"Estimation":   TaskFieldEstimatedTime,
+		"Due Date":     TaskFieldDueDate,
+		"Start":        TaskFieldStartDate,
+	}
+
+	task := &UniversalTask{CustomFields: map[string]interface{}{
+		"story points": 5.0,
+		"Estimation":   map[string]interface{}{"minutes": 90.0, "presentation": "1h 30m"},
+		"Due Date":     float64(due.UnixMilli()),
+		"Start":        "not a date",
+		"Team":         "Platform",
+	}}
+	mapping.Promote(task)
+
+	require.NotNil(t, task.StoryPoints)
+	assert.Equal(t, 5.0, *task.StoryPoints)
+	require.NotNil(t, task.EstimatedTime)
+	assert.Equal(t, 90*time.Minute, *task.EstimatedTime)
+	require.NotNil(t, task.DueDate)
+	assert.True(t, due.Equal(*task.DueDate))
+	assert.Nil(t, task.StartDate)
+	// Unconvertible and unmapped values stay in place
+	assert.Equal(t, map[string]interface{}{"Start": "not a date", "Team": "Platform"}, task.CustomFields)
+
+	enum := &UniversalTask{CustomFields: map[string]interface{}{"Story points": map[string]interface{}{"name": "8"}}}
+	mapping.Promote(enum)
+	require.NotNil(t, enum.StoryPoints)
+	assert.Equal(t, 8.0, *enum.StoryPoints)
+}
+
+func TestFieldMappingDemote(t *testing.T) {
+	mapping := FieldMapping{"Story points": TaskFieldStoryPoints, "Estimation": TaskFieldEstimatedTime}
+	points := 3.0
+	estimate := 2 * time.Hour
+	due := time.Now()
+
+	task := &UniversalTask{StoryPoints: &points, EstimatedTime: &estimate, DueDate: &due}
+	mapping.Demote(task)
+
+	assert.Equal(t, map[string]interface{}{"Story points": 3.0, "Estimation": 2 * time.Hour}, task.CustomFields)
+	assert.Nil(t, task.StoryPoints)
+	assert.Nil(t, task.EstimatedTime)
+	assert.NotNil(t, task.DueDate, "unmapped fields are left alone")
+
+	update := &TaskUpdate{StoryPoints: &points}
+	mapping.DemoteUpdate(update)
+	assert.Nil(t, update.StoryPoints)
+	assert.Equal(t, map[string]interface{}{"Story points": 3.0}, update.CustomFields)
+}
@@ -0,0 +1,76 @@
+package providers
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// TaskFormatter renders tasks with a user-supplied Go template.
+// The template receives a *UniversalTask, e.g. "{{.Key}} {{.Title}} ({{.Status.Name}})".
+type TaskFormatter struct {
+	tmpl *template.Template
+}
+
+var taskTemplateFuncs = template.FuncMap{
+	"join":  strings.Join,
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+	"date": func(t interface{}) string {
+		switch v := t.(type) {
+		case time.Time:
+			if v.IsZero() {
+				return ""
+			}
+			return v.Format("2006-01-02")
+		case *time.Time:
+			if v == nil || v.IsZero() {
+				return ""
+			}
+			return v.Format("2006-01-02")
+		}
+		return ""
+	},
+}
+
+// NewTaskFormatter parses a task output template
+func NewTaskFormatter(text string) (*TaskFormatter, error) {
+	if strings.TrimSpace(text) == "" {
+		return nil, NewProviderError(ErrorTypeValidation, "output template is empty", nil)
+	}
+
+	tmpl, err := template.New("task").Funcs(taskTemplateFuncs).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, NewProviderError(ErrorTypeValidation, "invalid output template", err)
+	}
+
+	return &TaskFormatter{tmpl: tmpl}, nil
+}
+
+// Format renders a single task
+func (f *TaskFormatter) Format(task *UniversalTask) (string, error) {
+	var buf bytes.Buffer
+	if err := f.tmpl.Execute(&buf, task); err != nil {
+		return "", NewProviderError(ErrorTypeValidation,
+			fmt.Sprintf("failed to render task %s with output template", task.GetDisplayID()), err)
+	}
+	return buf.String(), nil
+}
+
+// FormatAll renders tasks one per line
+func (f *TaskFormatter) FormatAll(tasks []*UniversalTask) (string, error) {
+	var b strings.Builder
+	for _, task := range tasks {
+		line, err := f.Format(task)
+		if err != nil {
+			return "", err
+		}
+		b.WriteString(line)
+		if !strings.HasSuffix(line, "\n") {
+			b.WriteString("\n")
+		}
+	}
+	return b.String(), nil
+}
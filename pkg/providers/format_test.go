@@ -0,0 +1,50 @@
+package providers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskFormatter(t *testing.T) {
+	tasks := []*UniversalTask{
+		{Key: "PROJ-1", Title: "Fix login", Status: TaskStatus{Name: "Open"}, Labels: []string{"bug", "auth"}},
+		{Key: "PROJ-2", Title: "Add SSO", Status: TaskStatus{Name: "Done"}},
+	}
+
+	t.Run("One line per task", func(t *testing.T) {
+		formatter, err := NewTaskFormatter("{{.Key}} {{.Title}} ({{.Status.Name}})")
+		require.NoError(t, err)
+
+		output, err := formatter.FormatAll(tasks)
+		require.NoError(t, err)
+		assert.Equal(t, "PROJ-1 Fix login (Open)\nPROJ-2 Add SSO (Done)\n", output)
+	})
+
+	t.Run("Template functions", func(t *testing.T) {
+		formatter, err := NewTaskFormatter(`{{upper .Key}}: {{join .Labels ","}}`)
+		require.NoError(t, err)
+
+		output, err := formatter.Format(tasks[0])
+		require.NoError(t, err)
+		assert.Equal(t, "PROJ-1: bug,auth", output)
+	})
+
+	t.Run("Invalid template", func(t *testing.T) {
+		_, err := NewTaskFormatter("{{.Key")
+		assert.True(t, IsErrorType(err, ErrorTypeValidation))
+
+		_, err = NewTaskFormatter("  ")
+		assert.Error(t, err)
+	})
+
+	t.Run("Unknown field", func(t *testing.T) {
+		formatter, err := NewTaskFormatter("{{.Nope}}")
+		require.NoError(t, err)
+
+		_, err = formatter.FormatAll(tasks)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "PROJ-1")
+	})
+}
@@ -0,0 +1,129 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultHealthCheckTimeout bounds a single provider health check
+	DefaultHealthCheckTimeout = 10 * time.Second
+
+	// DefaultDegradedLatency is the health check latency above which a
+	// provider that answered is still reported as degraded
+	DefaultDegradedLatency = 3 * time.Second
+)
+
+// HealthCheckOptions configures a batch of health checks
+type HealthCheckOptions struct {
+	// Timeout for each provider, DefaultHealthCheckTimeout when zero
+	Timeout time.Duration
+	// Latency threshold for HealthStatusDegraded, DefaultDegradedLatency when zero
+	DegradedLatency time.Duration
+}
+
+// HealthResult is the outcome of one provider health check
+type HealthResult struct {
+	Provider  string               `json:"provider" yaml:"provider"`
+	Status    ProviderHealthStatus `json:"status" yaml:"status"`
+	Latency   time.Duration        `json:"-" yaml:"-"`
+	LatencyMs int64                `json:"latencyMs" yaml:"latencyMs"`
+	Error     string               `json:"error,omitempty" yaml:"error,omitempty"`
+	CheckedAt time.Time            `json:"checkedAt" yaml:"checkedAt"`
+}
+
+// CheckProviderHealth runs the health check of a provider and measures its
+// round-trip latency. Answers slower than the degraded threshold are reported
+// as degraded, errors and timeouts as unhealthy.
+func CheckProviderHealth(ctx context.Context, name string, provider TaskProvider, options HealthCheckOptions) HealthResult {
+	options = options.withDefaults()
+
+	checkCtx, cancel := context.WithTimeout(ctx, options.Timeout)
+	defer cancel()
+
+	result := HealthResult{Provider: name, CheckedAt: time.Now()}
+	err := provider.HealthCheck(checkCtx)
+	result.Latency = time.Since(result.CheckedAt)
+	result.LatencyMs = result.Latency.Milliseconds()
+
+	switch {
+	case err != nil && errors.Is(checkCtx.Err(), context.DeadlineExceeded):
+		result.Status = HealthStatusUnhealthy
+		result.Error = fmt.Sprintf("health check timed out after %s", options.Timeout)
+	case err != nil:
+		result.Status = HealthStatusUnhealthy
+		result.Error = err.Error()
+	default:
+		result.Status = healthStatusForLatency(result.Latency, options.DegradedLatency)
+	}
+	return result
+}
+
+// CheckHealth checks the named providers concurrently, or all initialized
+// providers when no names are given. Results are sorted by provider name;
+// unknown names are reported as unhealthy.
+func (r *ProviderRegistry) CheckHealth(ctx context.Context, names []string, options HealthCheckOptions) []HealthResult {
+	if options.DegradedLatency == 0 && r.config != nil {
+		options.DegradedLatency = r.config.DegradedLatency
+	}
+
+	r.mu.RLock()
+	if len(names) == 0 {
+		for name := range r.providers {
+			names = append(names, name)
+		}
+	}
+	targets := make(map[string]TaskProvider, len(names))
+	for _, name := range names {
+		if provider, exists := r.providers[name]; exists {
+			targets[name] = provider
+		}
+	}
+	r.mu.RUnlock()
+
+	results := make([]HealthResult, len(names))
+	var wg sync.WaitGroup
+	for i, name := range names {
+		provider, exists := targets[name]
+		if !exists {
+			results[i] = HealthResult{
+				Provider:  name,
+				Status:    HealthStatusUnhealthy,
+				Error:     fmt.Sprintf("provider not found: %s", name),
+				CheckedAt: time.Now(),
+			}
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int, name string, provider TaskProvider) {
+			defer wg.Done()
+			results[i] = CheckProviderHealth(ctx, name, provider, options)
+		}(i, name, provider)
+	}
+	wg.Wait()
+
+	sort.Slice(results, func(i, j int) bool { return results[i].Provider < results[j].Provider })
+	return results
+}
+
+func (o HealthCheckOptions) withDefaults() HealthCheckOptions {
+	if o.Timeout <= 0 {
+		o.Timeout = DefaultHealthCheckTimeout
+	}
+	if o.DegradedLatency <= 0 {
+		o.DegradedLatency = DefaultDegradedLatency
+	}
+	return o
+}
+
+func healthStatusForLatency(latency, degradedLatency time.Duration) ProviderHealthStatus {
+	if latency > degradedLatency {
+		return HealthStatusDegraded
+	}
+	return HealthStatusHealthy
+}
@@ -0,0 +1,70 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// latencyProvider answers its health check after a fixed delay
+type latencyProvider struct {
+	TaskProvider
+	latency time.Duration
+	err     error
+}
+
+func (p *latencyProvider) HealthCheck(ctx context.Context) error {
+	select {
+	case <-time.After(p.latency):
+		return p.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func TestCheckHealth(t *testing.T) {
+	registry := NewProviderRegistry(&MultiProviderConfig{Providers: map[string]*ProviderConfig{}}, nil)
+	registry.providers["fast"] = &latencyProvider{latency: time.Millisecond}
+	registry.providers["slow"] = &latencyProvider{latency: 60 * time.Millisecond}
+	registry.providers["hanging"] = &latencyProvider{latency: time.Hour}
+	registry.providers["broken"] = &latencyProvider{err: errors.New("401 Unauthorized")}
+
+	options := HealthCheckOptions{Timeout: 150 * time.Millisecond, DegradedLatency: 40 * time.Millisecond}
+
+	t.Run("Checks all providers concurrently", func(t *testing.T) {
+		start := time.Now()
+		results := registry.CheckHealth(context.Background(), nil, options)
+		assert.Less(t, time.Since(start), time.Second)
+
+		require.Len(t, results, 4)
+		byName := make(map[string]HealthResult)
+		for _, result := range results {
+			byName[result.Provider] = result
+		}
+		assert.Equal(t, "broken", results[0].Provider)
+
+		assert.Equal(t, HealthStatusHealthy, byName["fast"].Status)
+		assert.Equal(t, HealthStatusDegraded, byName["slow"].Status)
+		assert.GreaterOrEqual(t, byName["slow"].LatencyMs, int64(60))
+
+		assert.Equal(t, HealthStatusUnhealthy, byName["hanging"].Status)
+		assert.Contains(t, byName["hanging"].Error, "timed out")
+
+		assert.Equal(t, HealthStatusUnhealthy, byName["broken"].Status)
+		assert.Equal(t, "401 Unauthorized", byName["broken"].Error)
+	})
+
+	t.Run("Named providers and the configured threshold", func(t *testing.T) {
+		registry.config.DegradedLatency = 100 * time.Millisecond
+
+		results := registry.CheckHealth(context.Background(), []string{"slow", "missing"}, HealthCheckOptions{})
+		require.Len(t, results, 2)
+		assert.Equal(t, "missing", results[0].Provider)
+		assert.Equal(t, HealthStatusUnhealthy, results[0].Status)
+		assert.Equal(t, HealthStatusHealthy, results[1].Status)
+	})
+}
@@ -0,0 +1,226 @@
+package providers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MaxDebugBodySize bounds how much of each request and response body is logged
+const MaxDebugBodySize = 64 * 1024
+
+const redacted = "[REDACTED]"
+
+// HTTPDebugEntry is one provider call in the HTTP debug log
+type HTTPDebugEntry struct {
+	Time            time.Time           `json:"time"`
+	Provider        string              `json:"provider"`
+	Method          string              `json:"method"`
+	URL             string              `json:"url"`
+	Status          int                 `json:"status,omitempty"`
+	DurationMs      int64               `json:"durationMs"`
+	RequestHeaders  map[string][]string `json:"requestHeaders,omitempty"`
+	ResponseHeaders map[string][]string `json:"responseHeaders,omitempty"`
+	RequestBody     string              `json:"requestBody,omitempty"`
+	ResponseBody    string              `json:"responseBody,omitempty"`
+	Error           string              `json:"error,omitempty"`
+}
+
+// HTTPDebugLog writes provider HTTP exchanges as JSON lines, one per call.
+// Credentials in headers and query parameters are redacted; bodies are only
+// logged when enabled since they may contain personal data.
+type HTTPDebugLog struct {
+	mu     sync.Mutex
+	w      io.Writer
+	closer io.Closer
+	bodies bool
+}
+
+var (
+	httpDebugMu  sync.RWMutex
+	httpDebugLog *HTTPDebugLog
+)
+
+// NewHTTPDebugLog creates a debug log writing to w
+func NewHTTPDebugLog(w io.Writer, bodies bool) *HTTPDebugLog {
+	return &HTTPDebugLog{w: w, bodies: bodies}
+}
+
+// OpenHTTPDebugLog creates a debug log appending to the file at path
+func OpenHTTPDebugLog(path string, bodies bool) (*HTTPDebugLog, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open HTTP debug log: %w", err)
+	}
+
+	log := NewHTTPDebugLog(file, bodies)
+	log.closer = file
+	return log, nil
+}
+
+// Close closes the underlying file, if the log owns one
+func (l *HTTPDebugLog) Close() error {
+	if l.closer == nil {
+		return nil
+	}
+	return l.closer.Close()
+}
+
+// SetHTTPDebugLog makes provider clients created afterwards log their HTTP
+// exchanges to log. A nil log turns logging off.
+func SetHTTPDebugLog(log *HTTPDebugLog) {
+	httpDebugMu.Lock()
+	defer httpDebugMu.Unlock()
+
+	httpDebugLog = log
+}
+
+// DebugTransport wraps the transport of a provider client so its calls are
+// written to the HTTP debug log. It returns base unchanged when logging is off.
+func DebugTransport(provider string, base http.RoundTripper) http.RoundTripper {
+	httpDebugMu.RLock()
+	log := httpDebugLog
+	httpDebugMu.RUnlock()
+
+	if log == nil {
+		return base
+	}
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &debugTransport{provider: provider, base: base, log: log}
+}
+
+type debugTransport struct {
+	provider string
+	base     http.RoundTripper
+	log      *HTTPDebugLog
+}
+
+func (t *debugTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	entry := &HTTPDebugEntry{
+		Time:           time.Now(),
+		Provider:       t.provider,
+		Method:         req.Method,
+		URL:            redactURL(req.URL),
+		RequestHeaders: redactHeaders(req.Header),
+	}
+
+	if t.log.bodies && req.Body != nil {
+		body, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		entry.RequestBody = truncateBody(body)
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	entry.DurationMs = time.Since(entry.Time).Milliseconds()
+
+	if err != nil {
+		entry.Error = err.Error()
+		t.log.write(entry)
+		return nil, err
+	}
+
+	entry.Status = resp.StatusCode
+	entry.ResponseHeaders = redactHeaders(resp.Header)
+
+	if t.log.bodies && resp.Body != nil {
+		body, readErr := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+		entry.ResponseBody = truncateBody(body)
+		if readErr != nil {
+			entry.Error = readErr.Error()
+		}
+	}
+
+	t.log.write(entry)
+	return resp, nil
+}
+
+// CloseIdleConnections closes idle connections of the wrapped transport
+func (t *debugTransport) CloseIdleConnections() {
+	if closer, ok := t.base.(interface{ CloseIdleConnections() }); ok {
+		closer.CloseIdleConnections()
+	}
+}
+
+func (l *HTTPDebugLog) write(entry *HTTPDebugEntry) {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.w.Write(append(data, '\n'))
+}
+
+func truncateBody(body []byte) string {
+	if len(body) > MaxDebugBodySize {
+		return string(body[:MaxDebugBodySize]) + fmt.Sprintf("... (%d bytes truncated)", len(body)-MaxDebugBodySize)
+	}
+	return string(body)
+}
+
+// IsSensitiveName reports whether a header, query parameter or argument may
+// carry credentials, judging by its name
+func IsSensitiveName(name string) bool {
+	name = strings.ToLower(name)
+	switch name {
+	case "authorization", "proxy-authorization", "cookie", "set-cookie":
+		return true
+	}
+	for _, marker := range []string{"token", "secret", "password", "api-key", "apikey", "api_key", "signature"} {
+		if strings.Contains(name, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+func redactHeaders(header http.Header) map[string][]string {
+	if len(header) == 0 {
+		return nil
+	}
+
+	result := make(map[string][]string, len(header))
+	for name, values := range header {
+		if IsSensitiveName(name) {
+			result[name] = []string{redacted}
+			continue
+		}
+		result[name] = append([]string(nil), values...)
+	}
+	return result
+}
+
+func redactURL(u *url.URL) string {
+	redactedURL := *u
+	redactedURL.User = nil
+
+	query := redactedURL.Query()
+	changed := false
+	for name := range query {
+		if IsSensitiveName(name) {
+			query.Set(name, redacted)
+			changed = true
+		}
+	}
+	if changed {
+		redactedURL.RawQuery = query.Encode()
+	}
+	return redactedURL.String()
+}
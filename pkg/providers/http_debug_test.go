@@ -0,0 +1,79 @@
+package providers
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDebugTransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Set-Cookie", "session=abc")
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error":"bad field","echo":` + string(body) + `}`))
+	}))
+	defer server.Close()
+
+	send := func(log *HTTPDebugLog) *http.Response {
+		SetHTTPDebugLog(log)
+		defer SetHTTPDebugLog(nil)
+
+		client := &http.Client{Transport: DebugTransport("youtrack-prod", nil)}
+		req, err := http.NewRequest("POST", server.URL+"/api/issues?fields=id&token=secret", strings.NewReader(`{"summary":"Bug"}`))
+		require.NoError(t, err)
+		req.Header.Set("Authorization", "Bearer perm:secret")
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := client.Do(req)
+		require.NoError(t, err)
+		return resp
+	}
+
+	t.Run("Logs one redacted entry per call", func(t *testing.T) {
+		var buf bytes.Buffer
+		resp := send(NewHTTPDebugLog(&buf, true))
+
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		assert.Equal(t, `{"error":"bad field","echo":{"summary":"Bug"}}`, string(body))
+
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		require.Len(t, lines, 1)
+		assert.NotContains(t, lines[0], "secret")
+		assert.NotContains(t, lines[0], "session=abc")
+
+		var entry HTTPDebugEntry
+		require.NoError(t, json.Unmarshal([]byte(lines[0]), &entry))
+		assert.Equal(t, "youtrack-prod", entry.Provider)
+		assert.Equal(t, "POST", entry.Method)
+		assert.Equal(t, http.StatusBadRequest, entry.Status)
+		assert.Contains(t, entry.URL, "fields=id")
+		assert.Equal(t, []string{redacted}, entry.RequestHeaders["Authorization"])
+		assert.Equal(t, []string{"application/json"}, entry.RequestHeaders["Content-Type"])
+		assert.Equal(t, `{"summary":"Bug"}`, entry.RequestBody)
+		assert.Equal(t, string(body), entry.ResponseBody)
+	})
+
+	t.Run("Bodies are opt-in", func(t *testing.T) {
+		var buf bytes.Buffer
+		send(NewHTTPDebugLog(&buf, false))
+
+		var entry HTTPDebugEntry
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+		assert.Empty(t, entry.RequestBody)
+		assert.Empty(t, entry.ResponseBody)
+	})
+
+	t.Run("Off by default", func(t *testing.T) {
+		base := &http.Transport{}
+		assert.Same(t, base, DebugTransport("youtrack-prod", base))
+	})
+}
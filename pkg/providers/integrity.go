@@ -0,0 +1,131 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+)
+
+// ReferenceField names the field of a task that refers to another task
+type ReferenceField string
+
+const (
+	ReferenceParent    ReferenceField = "parent"
+	ReferenceBlockedBy ReferenceField = "blockedBy"
+	ReferenceBlocks    ReferenceField = "blocks"
+)
+
+// referenceLinks are the links that hold each reference, removed to repair it
+var referenceLinks = map[ReferenceField]TaskLinkType{
+	ReferenceParent:    TaskLinkSubtaskOf,
+	ReferenceBlockedBy: TaskLinkDependsOn,
+	ReferenceBlocks:    TaskLinkBlocks,
+}
+
+// DanglingReference is a reference from a task to a task that doesn't exist
+type DanglingReference struct {
+	TaskID   string         `json:"taskId"`
+	Title    string         `json:"title"`
+	Field    ReferenceField `json:"field"`
+	TargetID string         `json:"targetId"`
+	Fixed    bool           `json:"fixed,omitempty"`
+	Error    string         `json:"error,omitempty"` // Why the repair failed
+}
+
+// IntegrityReport is the result of checking the references between tasks
+type IntegrityReport struct {
+	Checked    int                  `json:"checked"`    // Tasks checked
+	References int                  `json:"references"` // References followed
+	Dangling   []*DanglingReference `json:"dangling"`
+	Fixed      int                  `json:"fixed"`
+}
+
+// CheckIntegrity finds the parent and dependency references of tasks that
+// point at tasks that don't exist. References to one of tasks are known to be
+// fine; others are looked up with GetTask, once per target, so references
+// into other projects are only reported when the target is really gone.
+func CheckIntegrity(ctx context.Context, provider TaskProvider, tasks []*UniversalTask) (*IntegrityReport, error) {
+	exists := make(map[string]bool)
+	for _, task := range tasks {
+		for _, alias := range taskAliases(task) {
+			exists[alias] = true
+		}
+	}
+
+	report := &IntegrityReport{Checked: len(tasks), Dangling: []*DanglingReference{}}
+	for _, task := range tasks {
+		references := []struct {
+			field   ReferenceField
+			targets []string
+		}{
+			{ReferenceParent, []string{task.ParentID}},
+			{ReferenceBlockedBy, task.BlockedBy},
+			{ReferenceBlocks, task.Blocks},
+		}
+
+		for _, reference := range references {
+			for _, target := range reference.targets {
+				if target == "" {
+					continue
+				}
+				report.References++
+
+				found, known := exists[target]
+				if !known {
+					_, err := provider.GetTask(ctx, target)
+					switch {
+					case err == nil:
+						found = true
+					case IsNotFoundError(err):
+						found = false
+					default:
+						return nil, fmt.Errorf("failed to look up %s referenced by %s: %w", target, task.GetDisplayID(), err)
+					}
+					exists[target] = found
+				}
+
+				if !found {
+					report.Dangling = append(report.Dangling, &DanglingReference{
+						TaskID:   task.GetDisplayID(),
+						Title:    task.Title,
+						Field:    reference.field,
+						TargetID: target,
+					})
+				}
+			}
+		}
+	}
+
+	return report, nil
+}
+
+// RepairIntegrity removes the links behind the dangling references of
+// report. A link that can't be removed is recorded in the Error of its
+// reference and the others are still repaired; the error returned is a
+// PartialFailureError then, or an unsupported error if the provider can't
+// remove links at all.
+func RepairIntegrity(ctx context.Context, provider TaskProvider, report *IntegrityReport) error {
+	if len(report.Dangling) == 0 {
+		return nil
+	}
+
+	unlinker, ok := ProviderAs[UnlinkProvider](provider)
+	if !ok {
+		return NewProviderError(ErrorTypeUnsupported, "provider does not support removing task links", nil)
+	}
+
+	failed := 0
+	for _, reference := range report.Dangling {
+		if err := unlinker.UnlinkTasks(ctx, reference.TaskID, reference.TargetID, referenceLinks[reference.Field]); err != nil {
+			reference.Error = err.Error()
+			failed++
+			continue
+		}
+		reference.Fixed = true
+		report.Fixed++
+	}
+
+	if failed > 0 {
+		return NewPartialFailureError(failed, len(report.Dangling), "references")
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// unlinkingProvider serves GetTask from hierarchyProvider and records unlinks
+type unlinkingProvider struct {
+	hierarchyProvider
+	unlinked []string
+	fail     map[string]bool
+}
+
+func (p *unlinkingProvider) UnlinkTasks(ctx context.Context, sourceID, targetID string, linkType TaskLinkType) error {
+	if p.fail[targetID] {
+		return errors.New("link not found")
+	}
+	p.unlinked = append(p.unlinked, sourceID+" "+string(linkType)+" "+targetID)
+	return nil
+}
+
+func TestCheckIntegrity(t *testing.T) {
+	ctx := context.Background()
+	tasks := []*UniversalTask{
+		{ID: "1-1", Key: "OPS-1", Title: "Epic"},
+		{ID: "1-2", Key: "OPS-2", ParentID: "1-1"},                                 // fine
+		{ID: "1-3", Key: "OPS-3", ParentID: "1-404", BlockedBy: []string{"OPS-2"}}, // deleted parent
+		{ID: "1-4", Key: "OPS-4", BlockedBy: []string{"WEB-7", "OPS-99"}, Blocks: []string{"OPS-99"}},
+	}
+	provider := &unlinkingProvider{hierarchyProvider: hierarchyProvider{byID: map[string]*UniversalTask{
+		"WEB-7": {Key: "WEB-7"}, // lives in another project
+	}}}
+
+	report, err := CheckIntegrity(ctx, provider, tasks)
+	require.NoError(t, err)
+	assert.Equal(t, 4, report.Checked)
+	assert.Equal(t, 6, report.References)
+	require.Len(t, report.Dangling, 3)
+	assert.Equal(t, DanglingReference{TaskID: "OPS-3", Field: ReferenceParent, TargetID: "1-404"}, *report.Dangling[0])
+	assert.Equal(t, ReferenceBlockedBy, report.Dangling[1].Field)
+	assert.Equal(t, ReferenceBlocks, report.Dangling[2].Field)
+	assert.Equal(t, []string{"1-404", "WEB-7", "OPS-99"}, provider.fetched, "each missing target is looked up once")
+
+	provider.fail = map[string]bool{"1-404": true}
+	err = RepairIntegrity(ctx, provider, report)
+	var partial *PartialFailureError
+	require.True(t, errors.As(err, &partial))
+	assert.Equal(t, 2, report.Fixed)
+	assert.Equal(t, []string{"OPS-4 depends_on OPS-99", "OPS-4 blocks OPS-99"}, provider.unlinked)
+	assert.False(t, report.Dangling[0].Fixed)
+	assert.Equal(t, "link not found", report.Dangling[0].Error)
+
+	err = RepairIntegrity(ctx, &provider.hierarchyProvider, report)
+	assert.True(t, IsUnsupportedError(err))
+}
@@ -3,6 +3,8 @@ package providers
 import (
 	"context"
 	"errors"
+	"fmt"
+	"io"
 	"time"
 )
 
@@ -19,7 +21,8 @@ type TaskProvider interface {
 	UpdateStatus(ctx context.Context, taskID string, status TaskStatus) error
 	GetAvailableStatuses(ctx context.Context, projectID string) ([]TaskStatus, error)
 
-	// Bulk operations
+	// Bulk operations. When creating fails midway, BulkCreateTasks returns
+	// the tasks created so far, in order, together with the error.
 	BulkCreateTasks(ctx context.Context, tasks []*UniversalTask) ([]*UniversalTask, error)
 	BulkUpdateTasks(ctx context.Context, updates map[string]*TaskUpdate) error
 
@@ -86,6 +89,38 @@ type AnalyticsProvider interface {
 	ExportData(ctx context.Context, format ExportFormat, filters *ExportFilters) ([]byte, error)
 }
 
+// CommentProvider is implemented by providers that can read and add task comments
+type CommentProvider interface {
+	GetComments(ctx context.Context, taskID string) ([]*Comment, error)
+	AddComment(ctx context.Context, taskID string, comment string) error
+}
+
+// AttachmentProvider is implemented by providers that can download and upload
+// task attachments
+type AttachmentProvider interface {
+	DownloadAttachment(ctx context.Context, attachment *Attachment) (io.ReadCloser, error)
+	UploadAttachment(ctx context.Context, taskID, filename string, content io.Reader) error
+}
+
+// ProviderAs finds the first provider in the chain of wrappers around provider
+// that implements T, so optional interfaces stay reachable through the
+// caching and publishing wrappers
+func ProviderAs[T any](provider TaskProvider) (T, bool) {
+	for provider != nil {
+		if target, ok := provider.(T); ok {
+			return target, true
+		}
+		wrapper, ok := provider.(interface{ Unwrap() TaskProvider })
+		if !ok {
+			break
+		}
+		provider = wrapper.Unwrap()
+	}
+
+	var zero T
+	return zero, false
+}
+
 // TaskManagerPlugin defines the plugin interface for dynamic loading
 type TaskManagerPlugin interface {
 	// Plugin metadata
@@ -126,6 +161,11 @@ const (
 	CapabilityAPI               Capability = "api"
 	CapabilityDocuments         Capability = "documents"
 	CapabilityTemplates         Capability = "templates"
+	CapabilitySprints           Capability = "sprints"
+	CapabilityAttachments       Capability = "attachments"
+	CapabilityComments          Capability = "comments"
+	CapabilityUsers             Capability = "users"
+	CapabilityActivity          Capability = "activity"
 )
 
 // ProviderInfo contains metadata about a provider
@@ -133,6 +173,9 @@ type ProviderInfo struct {
 	Name            string                 `json:"name"`
 	Type            ProviderType           `json:"type"`
 	Version         string                 `json:"version"`
+	// APIVersion is the version of the provider's API detected on the
+	// server, empty when the provider doesn't report it
+	APIVersion      string                 `json:"apiVersion,omitempty"`
 	Description     string                 `json:"description,omitempty"`
 	Enabled         bool                   `json:"enabled"`
 	Capabilities    []Capability           `json:"capabilities"`
@@ -208,6 +251,7 @@ const (
 	ErrorTypeNetwork        ErrorType = "network"
 	ErrorTypeInternal       ErrorType = "internal"
 	ErrorTypeConfiguration ErrorType = "configuration"
+	ErrorTypeUnsupported    ErrorType = "unsupported"
 )
 
 // Common errors
@@ -273,6 +317,19 @@ func IsRateLimitError(err error) bool {
 	return IsErrorType(err, ErrorTypeRateLimit)
 }
 
+// IsUnsupportedError checks if an error is an "unsupported" error
+func IsUnsupportedError(err error) bool {
+	return IsErrorType(err, ErrorTypeUnsupported)
+}
+
+// NewUnsupportedError reports that a provider lacks a capability an operation needs
+func NewUnsupportedError(provider string, capability Capability) *ProviderError {
+	err := NewProviderError(ErrorTypeUnsupported, fmt.Sprintf("provider %s does not support %s", provider, capability), nil)
+	err.Context["provider"] = provider
+	err.Context["capability"] = string(capability)
+	return err
+}
+
 // NewValidationError creates a new validation error
 func NewValidationError(message string, context map[string]interface{}) *ProviderError {
 	return &ProviderError{
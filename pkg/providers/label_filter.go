@@ -0,0 +1,108 @@
+package providers
+
+import (
+	"fmt"
+	"strings"
+)
+
+// LabelMatch is the label condition of task filters. Labels are compared
+// case-insensitively, like providers compare tags.
+type LabelMatch struct {
+	All  []string // Tasks must have every label
+	Any  []string // Tasks must have at least one label
+	None []string // Tasks must have none of the labels
+}
+
+// LabelMatch returns the label condition of the filters. Labels counts as
+// LabelsAll; duplicates are dropped.
+func (f *TaskFilters) LabelMatch() LabelMatch {
+	if f == nil {
+		return LabelMatch{}
+	}
+	return LabelMatch{
+		All:  uniqueLabels(append(append([]string{}, f.Labels...), f.LabelsAll...)),
+		Any:  uniqueLabels(f.LabelsAny),
+		None: uniqueLabels(f.LabelsNone),
+	}
+}
+
+// IsEmpty reports whether the condition matches every task
+func (m LabelMatch) IsEmpty() bool {
+	return len(m.All) == 0 && len(m.Any) == 0 && len(m.None) == 0
+}
+
+// Validate rejects conditions no task can match: a label both required and excluded
+func (m LabelMatch) Validate() error {
+	excluded := labelSet(m.None)
+	for _, label := range append(append([]string{}, m.All...), m.Any...) {
+		if excluded[strings.ToLower(label)] {
+			return NewValidationError(fmt.Sprintf("label %q is both required and excluded", label), nil)
+		}
+	}
+	return nil
+}
+
+// Matches reports whether a task with the given labels satisfies the condition
+func (m LabelMatch) Matches(labels []string) bool {
+	has := labelSet(labels)
+	for _, label := range m.All {
+		if !has[strings.ToLower(label)] {
+			return false
+		}
+	}
+	for _, label := range m.None {
+		if has[strings.ToLower(label)] {
+			return false
+		}
+	}
+	if len(m.Any) == 0 {
+		return true
+	}
+	for _, label := range m.Any {
+		if has[strings.ToLower(label)] {
+			return true
+		}
+	}
+	return false
+}
+
+// FilterByLabels keeps the tasks that satisfy the label condition of the
+// filters. Providers translate the condition into their queries; this makes
+// sure the result is the same for providers whose queries can't express it.
+func (f *TaskFilters) FilterByLabels(tasks []*UniversalTask) []*UniversalTask {
+	match := f.LabelMatch()
+	if match.IsEmpty() {
+		return tasks
+	}
+
+	filtered := tasks[:0]
+	for _, task := range tasks {
+		if match.Matches(task.Labels) {
+			filtered = append(filtered, task)
+		}
+	}
+	return filtered
+}
+
+func labelSet(labels []string) map[string]bool {
+	set := make(map[string]bool, len(labels))
+	for _, label := range labels {
+		set[strings.ToLower(label)] = true
+	}
+	return set
+}
+
+// uniqueLabels trims labels and drops empty and duplicate ones
+func uniqueLabels(labels []string) []string {
+	var unique []string
+	seen := make(map[string]bool, len(labels))
+	for _, label := range labels {
+		label = strings.TrimSpace(label)
+		if label == "" || seen[strings.ToLower(label)] {
+			continue
+		}
+		seen[strings.ToLower(label)] = true
+		unique = append(unique, label)
+	}
+	return unique
+}
@@ -0,0 +1,48 @@
+package providers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLabelMatch(t *testing.T) {
+	tasks := []*UniversalTask{
+		{ID: "1", Labels: []string{"backend", "urgent"}},
+		{ID: "2", Labels: []string{"Backend"}},
+		{ID: "3", Labels: []string{"frontend", "urgent"}},
+		{ID: "4"},
+	}
+	ids := func(filters *TaskFilters) []string {
+		var ids []string
+		for _, task := range filters.FilterByLabels(append([]*UniversalTask{}, tasks...)) {
+			ids = append(ids, task.ID)
+		}
+		return ids
+	}
+
+	t.Run("All", func(t *testing.T) {
+		assert.Equal(t, []string{"1"}, ids(&TaskFilters{LabelsAll: []string{"backend", "urgent"}}))
+		assert.Equal(t, []string{"1"}, ids(&TaskFilters{Labels: []string{"backend", "urgent"}}))
+		assert.Equal(t, []string{"1", "2"}, ids(&TaskFilters{LabelsAll: []string{"BACKEND"}}))
+	})
+
+	t.Run("Any", func(t *testing.T) {
+		assert.Equal(t, []string{"1", "2", "3"}, ids(&TaskFilters{LabelsAny: []string{"backend", "urgent"}}))
+	})
+
+	t.Run("None", func(t *testing.T) {
+		assert.Equal(t, []string{"2", "4"}, ids(&TaskFilters{LabelsNone: []string{"urgent"}}))
+	})
+
+	t.Run("Combined", func(t *testing.T) {
+		assert.Equal(t, []string{"3"}, ids(&TaskFilters{LabelsAll: []string{"urgent"}, LabelsAny: []string{"frontend", "mobile"}, LabelsNone: []string{"backend"}}))
+		assert.Len(t, ids(&TaskFilters{}), 4)
+	})
+
+	t.Run("Contradictions are rejected", func(t *testing.T) {
+		filters := &TaskFilters{LabelsAll: []string{"backend"}, LabelsNone: []string{"Backend"}}
+		assert.True(t, IsErrorType(filters.LabelMatch().Validate(), ErrorTypeValidation))
+		assert.NoError(t, (&TaskFilters{LabelsAny: []string{"a"}, LabelsNone: []string{"b"}}).LabelMatch().Validate())
+	})
+}
@@ -0,0 +1,159 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Label is a label tasks can be tagged with
+type Label struct {
+	ID    string `json:"id" yaml:"id"`
+	Name  string `json:"name" yaml:"name"`
+	Color string `json:"color,omitempty" yaml:"color,omitempty"`
+}
+
+// LabelProvider is implemented by providers that can list the labels tasks
+// can be tagged with. An empty projectID lists the labels of all projects;
+// providers whose labels aren't per project ignore it.
+type LabelProvider interface {
+	ListLabels(ctx context.Context, projectID string) ([]*Label, error)
+}
+
+// LabelCreator is implemented by providers that can create labels
+type LabelCreator interface {
+	CreateLabel(ctx context.Context, projectID, name string) (*Label, error)
+}
+
+// ListLabels returns the labels the provider knows for the project
+func ListLabels(ctx context.Context, provider TaskProvider, projectID string) ([]*Label, error) {
+	lister, ok := ProviderAs[LabelProvider](provider)
+	if !ok {
+		return nil, NewProviderError(ErrorTypeUnsupported, "the provider can't list labels", nil)
+	}
+	labels, err := lister.ListLabels(ctx, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list labels: %w", err)
+	}
+	return labels, nil
+}
+
+// ValidateLabels checks labels against the labels the provider knows for the
+// project and returns them spelled the way the provider does, without
+// duplicates. Unknown labels are rejected with the closest known label as a
+// suggestion, or created when create is set, so a typo never turns into a
+// new label by accident. Providers that can't list labels get the labels
+// back unchanged.
+func ValidateLabels(ctx context.Context, provider TaskProvider, projectID string, labels []string, create bool) ([]string, error) {
+	labels = uniqueLabels(labels)
+	if len(labels) == 0 {
+		return labels, nil
+	}
+
+	lister, ok := ProviderAs[LabelProvider](provider)
+	if !ok {
+		return labels, nil
+	}
+	known, err := lister.ListLabels(ctx, projectID)
+	if err != nil {
+		if IsUnsupportedError(err) {
+			return labels, nil
+		}
+		return nil, fmt.Errorf("failed to list labels: %w", err)
+	}
+
+	byName := make(map[string]string, len(known))
+	names := make([]string, 0, len(known))
+	for _, label := range known {
+		byName[strings.ToLower(label.Name)] = label.Name
+		names = append(names, label.Name)
+	}
+
+	validated := make([]string, 0, len(labels))
+	var unknown []string
+	for _, label := range labels {
+		if name, exists := byName[strings.ToLower(label)]; exists {
+			validated = append(validated, name)
+			continue
+		}
+		unknown = append(unknown, label)
+		validated = append(validated, label)
+	}
+	if len(unknown) == 0 {
+		return validated, nil
+	}
+
+	if !create {
+		described := make([]string, len(unknown))
+		for i, label := range unknown {
+			described[i] = fmt.Sprintf("%q", label)
+			if suggestion := suggestLabel(label, names); suggestion != "" {
+				described[i] += fmt.Sprintf(" (did you mean %q?)", suggestion)
+			}
+		}
+		return nil, NewValidationError(fmt.Sprintf("unknown labels: %s", strings.Join(described, ", ")), nil)
+	}
+
+	creator, ok := ProviderAs[LabelCreator](provider)
+	if !ok {
+		return nil, NewProviderError(ErrorTypeUnsupported,
+			fmt.Sprintf("the provider can't create labels, add %s in the provider first", strings.Join(unknown, ", ")), nil)
+	}
+	for _, label := range unknown {
+		if _, err := creator.CreateLabel(ctx, projectID, label); err != nil {
+			return nil, fmt.Errorf("failed to create label %q: %w", label, err)
+		}
+	}
+	return validated, nil
+}
+
+// MergeLabels returns current with add appended and remove dropped. Labels
+// are compared case-insensitively and duplicates are dropped.
+func MergeLabels(current, add, remove []string) []string {
+	removed := labelSet(remove)
+	merged := []string{}
+	for _, label := range uniqueLabels(append(append([]string{}, current...), add...)) {
+		if !removed[strings.ToLower(label)] {
+			merged = append(merged, label)
+		}
+	}
+	return merged
+}
+
+// suggestLabel returns the known label closest to label, or "" if none is
+// close enough to be a likely typo
+func suggestLabel(label string, known []string) string {
+	label = strings.ToLower(label)
+	best, bestDistance := "", len([]rune(label))/2+1
+	if bestDistance > 3 {
+		bestDistance = 3
+	}
+	for _, name := range known {
+		if distance := editDistance(label, strings.ToLower(name)); distance < bestDistance {
+			best, bestDistance = name, distance
+		}
+	}
+	return best
+}
+
+// editDistance returns the Levenshtein distance between a and b
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	previous := make([]int, len(rb)+1)
+	current := make([]int, len(rb)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		current[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(rb)]
+}
@@ -0,0 +1,96 @@
+package providers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// labelProvider knows a fixed set of labels and records the labels it creates
+type labelProvider struct {
+	TaskProvider
+	labels  []*Label
+	created []string
+}
+
+func (p *labelProvider) ListLabels(ctx context.Context, projectID string) ([]*Label, error) {
+	return p.labels, nil
+}
+
+func (p *labelProvider) CreateLabel(ctx context.Context, projectID, name string) (*Label, error) {
+	p.created = append(p.created, name)
+	label := &Label{ID: "new-" + name, Name: name}
+	p.labels = append(p.labels, label)
+	return label, nil
+}
+
+// labelListProvider lists labels but can't create them
+type labelListProvider struct {
+	TaskProvider
+	labels []*Label
+}
+
+func (p *labelListProvider) ListLabels(ctx context.Context, projectID string) ([]*Label, error) {
+	return p.labels, nil
+}
+
+func TestValidateLabels(t *testing.T) {
+	ctx := context.Background()
+	known := []*Label{{ID: "6-1", Name: "backend"}, {ID: "6-2", Name: "Frontend"}, {ID: "6-3", Name: "ops"}}
+
+	t.Run("Known labels", func(t *testing.T) {
+		provider := &labelProvider{labels: known}
+		labels, err := ValidateLabels(ctx, provider, "OPS", []string{"Backend", " frontend ", "backend"}, false)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"backend", "Frontend"}, labels, "spelled like the provider, without duplicates")
+		assert.Empty(t, provider.created)
+	})
+
+	t.Run("Unknown labels are rejected", func(t *testing.T) {
+		provider := &labelProvider{labels: known}
+		_, err := ValidateLabels(ctx, provider, "OPS", []string{"backnd", "design"}, false)
+		require.Error(t, err)
+		assert.True(t, IsErrorType(err, ErrorTypeValidation))
+		assert.Contains(t, err.Error(), `"backnd" (did you mean "backend"?)`)
+		assert.Contains(t, err.Error(), `"design"`)
+		assert.NotContains(t, err.Error(), `"design" (did you mean`)
+		assert.Empty(t, provider.created)
+	})
+
+	t.Run("Unknown labels are created on request", func(t *testing.T) {
+		provider := &labelProvider{labels: known}
+		labels, err := ValidateLabels(ctx, provider, "OPS", []string{"ops", "design"}, true)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"ops", "design"}, labels)
+		assert.Equal(t, []string{"design"}, provider.created)
+	})
+
+	t.Run("Provider can't create labels", func(t *testing.T) {
+		_, err := ValidateLabels(ctx, &labelListProvider{labels: known}, "", []string{"design"}, true)
+		assert.True(t, IsUnsupportedError(err))
+	})
+
+	t.Run("Provider can't list labels", func(t *testing.T) {
+		labels, err := ValidateLabels(ctx, &tasksOnlyProvider{}, "", []string{"anything"}, false)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"anything"}, labels)
+
+		_, err = ListLabels(ctx, &tasksOnlyProvider{}, "")
+		assert.True(t, IsUnsupportedError(err))
+	})
+}
+
+func TestMergeLabels(t *testing.T) {
+	assert.Equal(t, []string{"backend", "urgent"}, MergeLabels([]string{"backend", "ops"}, []string{"Backend", "urgent"}, []string{"OPS"}))
+	assert.Equal(t, []string{}, MergeLabels([]string{"ops"}, nil, []string{"ops"}))
+}
+
+func TestSuggestLabel(t *testing.T) {
+	known := []string{"backend", "frontend", "ops"}
+	assert.Equal(t, "backend", suggestLabel("backnd", known))
+	assert.Equal(t, "frontend", suggestLabel("FrontEnd2", known))
+	assert.Equal(t, "", suggestLabel("design", known))
+	assert.Equal(t, "", suggestLabel("op", []string{"qa"}))
+}
@@ -0,0 +1,439 @@
+package providers
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/fatih/color"
+)
+
+// Wiki markup of Jira and legacy YouTrack descriptions
+var (
+	jiraHeadingPattern     = regexp.MustCompile(`^h([1-6])\.\s+(.*)$`)
+	jiraListPattern        = regexp.MustCompile(`^([*#-]+)\s+(.*)$`)
+	jiraCodePattern        = regexp.MustCompile(`^\{(code|noformat)(?::([^}|]*))?[^}]*\}(.*)$`)
+	jiraBlockEndPattern    = regexp.MustCompile(`^(.*?)\{(code|noformat)\}\s*$`)
+	jiraBoldPattern        = regexp.MustCompile(`(^|[^\w*])\*(\S(?:[^*]*\S)?)\*([^\w*]|$)`)
+	jiraItalicPattern      = regexp.MustCompile(`(^|[^\w_])_(\S(?:[^_]*\S)?)_([^\w_]|$)`)
+	jiraStrikePattern      = regexp.MustCompile(`(^|\s)-(\S(?:[^-]*\S)?)-(\s|$)`)
+	jiraMonospacePattern   = regexp.MustCompile(`\{\{(.+?)\}\}`)
+	jiraLinkPattern        = regexp.MustCompile(`\[([^\]|]+)\|([^\]]+)\]`)
+	jiraBareLinkPattern    = regexp.MustCompile(`\[((?:https?|mailto):[^\]\s]+)\]`)
+	youTrackHeadingPattern = regexp.MustCompile(`^(={1,6})\s*(.+?)\s*=*\s*$`)
+	youTrackCodePattern    = regexp.MustCompile(`^\{(code|noformat|monospace)(?:[ :]([^}]*))?\}\s*$`)
+	youTrackLinkPattern    = regexp.MustCompile(`\[((?:https?|mailto):\S+)\s+([^\]]+)\](?:[^(]|$)`)
+)
+
+// MarkupToMarkdown converts the wiki markup of a provider's task descriptions
+// to markdown. Jira descriptions are always wiki markup. YouTrack uses
+// markdown unless an instance still has wiki formatting enabled, so only
+// constructs that can't be markdown are converted there. Other providers
+// already use markdown.
+func MarkupToMarkdown(text string, providerType ProviderType) string {
+	switch providerType {
+	case ProviderTypeJira:
+		return jiraToMarkdown(text)
+	case ProviderTypeYouTrack:
+		return youTrackToMarkdown(text)
+	default:
+		return text
+	}
+}
+
+func jiraToMarkdown(text string) string {
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	out := make([]string, 0, len(lines))
+	inCode, inQuote := false, false
+	numbers := map[int]int{}
+
+	for _, line := range lines {
+		if inCode {
+			if m := jiraBlockEndPattern.FindStringSubmatch(line); m != nil {
+				if m[1] != "" {
+					out = append(out, m[1])
+				}
+				out = append(out, "```")
+				inCode = false
+				continue
+			}
+			out = append(out, line)
+			continue
+		}
+
+		trimmed := strings.TrimSpace(line)
+		if m := jiraCodePattern.FindStringSubmatch(trimmed); m != nil {
+			out = append(out, "```"+strings.TrimSpace(m[2]))
+			rest := m[3]
+			if end := jiraBlockEndPattern.FindStringSubmatch(rest); end != nil {
+				if end[1] != "" {
+					out = append(out, end[1])
+				}
+				out = append(out, "```")
+				continue
+			}
+			if rest != "" {
+				out = append(out, rest)
+			}
+			inCode = true
+			continue
+		}
+
+		if trimmed == "{quote}" {
+			inQuote = !inQuote
+			continue
+		}
+
+		var converted string
+		switch {
+		case strings.HasPrefix(trimmed, "||") || strings.HasPrefix(trimmed, "|"):
+			converted = jiraTableRow(trimmed)
+		case strings.HasPrefix(trimmed, "bq. "):
+			converted = "> " + jiraInline(strings.TrimPrefix(trimmed, "bq. "))
+		case trimmed == "----":
+			converted = "---"
+		default:
+			if m := jiraHeadingPattern.FindStringSubmatch(trimmed); m != nil {
+				level, _ := strconv.Atoi(m[1])
+				converted = strings.Repeat("#", level) + " " + jiraInline(m[2])
+			} else if m := jiraListPattern.FindStringSubmatch(trimmed); m != nil && !(len(m[1]) > 1 && strings.Trim(m[1], "-") == "") {
+				depth := len(m[1]) - 1
+				indent := strings.Repeat("  ", depth)
+				if strings.HasSuffix(m[1], "#") {
+					numbers[depth]++
+					converted = fmt.Sprintf("%s%d. %s", indent, numbers[depth], jiraInline(m[2]))
+				} else {
+					converted = indent + "- " + jiraInline(m[2])
+				}
+				for level := range numbers {
+					if level > depth {
+						delete(numbers, level)
+					}
+				}
+			} else {
+				converted = jiraInline(line)
+			}
+		}
+
+		if jiraListPattern.FindStringSubmatch(trimmed) == nil {
+			numbers = map[int]int{}
+		}
+		if inQuote {
+			converted = "> " + converted
+		}
+		out = append(out, strings.Split(converted, "\n")...)
+	}
+
+	return strings.Join(out, "\n")
+}
+
+// jiraTableRow converts "||a||b||" header rows and "|a|b|" rows. Header rows
+// get the separator row markdown needs.
+func jiraTableRow(line string) string {
+	header := strings.HasPrefix(line, "||")
+	separator := "|"
+	if header {
+		separator = "||"
+	}
+
+	cells := strings.Split(strings.Trim(line, "|"), separator)
+	for i, cell := range cells {
+		cells[i] = jiraInline(strings.Trim(strings.TrimSpace(cell), "|"))
+	}
+
+	row := "| " + strings.Join(cells, " | ") + " |"
+	if header {
+		row += "\n|" + strings.Repeat(" --- |", len(cells))
+	}
+	return row
+}
+
+func jiraInline(text string) string {
+	text = strings.ReplaceAll(text, `\\`, "\n")
+	text = jiraMonospacePattern.ReplaceAllString(text, "`$1`")
+	text = jiraLinkPattern.ReplaceAllString(text, "[$1]($2)")
+	text = jiraBareLinkPattern.ReplaceAllString(text, "$1")
+	text = jiraBoldPattern.ReplaceAllString(text, "$1**$2**$3")
+	text = jiraItalicPattern.ReplaceAllString(text, "$1*$2*$3")
+	text = jiraStrikePattern.ReplaceAllString(text, "$1~~$2~~$3")
+	return text
+}
+
+func youTrackToMarkdown(text string) string {
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	out := make([]string, 0, len(lines))
+	inCode, inFence := false, false
+
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") {
+			inFence = !inFence
+		}
+		if inFence || strings.HasPrefix(trimmed, "```") {
+			out = append(out, line)
+			continue
+		}
+
+		if m := youTrackCodePattern.FindStringSubmatch(trimmed); m != nil {
+			if inCode {
+				out = append(out, "```")
+			} else {
+				out = append(out, "```"+strings.TrimSpace(m[2]))
+			}
+			inCode = !inCode
+			continue
+		}
+		if inCode {
+			out = append(out, line)
+			continue
+		}
+
+		// "= Heading =", but not a "===" underline of a markdown heading
+		if m := youTrackHeadingPattern.FindStringSubmatch(trimmed); m != nil && strings.HasSuffix(trimmed, "=") && strings.Trim(m[2], "= ") != "" {
+			out = append(out, strings.Repeat("#", len(m[1]))+" "+m[2])
+			continue
+		}
+
+		line = jiraMonospacePattern.ReplaceAllString(line, "`$1`")
+		line = youTrackLinkPattern.ReplaceAllStringFunc(line, func(match string) string {
+			m := youTrackLinkPattern.FindStringSubmatch(match)
+			suffix := strings.TrimPrefix(match, "["+m[1]+" "+m[2]+"]")
+			return fmt.Sprintf("[%s](%s)%s", m[2], m[1], suffix)
+		})
+		out = append(out, line)
+	}
+
+	return strings.Join(out, "\n")
+}
+
+// Markdown constructs recognized by RenderMarkdown
+var (
+	markdownHeadingPattern = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*\s*$`)
+	markdownBulletPattern  = regexp.MustCompile(`^(\s*)[-*+]\s+(.*)$`)
+	markdownNumberPattern  = regexp.MustCompile(`^(\s*)(\d+)[.)]\s+(.*)$`)
+	markdownRulePattern    = regexp.MustCompile(`^\s*([-*_])(\s*[-*_]){2,}\s*$`)
+	markdownTableSeparator = regexp.MustCompile(`^\|?\s*:?-+:?\s*(\|\s*:?-+:?\s*)*\|?$`)
+	markdownImagePattern   = regexp.MustCompile(`!\[([^\]]*)\]\(([^)\s]+)[^)]*\)`)
+	markdownLinkPattern    = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)[^)]*\)`)
+	markdownAutoLink       = regexp.MustCompile(`<((?:https?|mailto):[^>\s]+)>`)
+	markdownBoldPattern    = regexp.MustCompile(`\*\*(.+?)\*\*|__(.+?)__`)
+	markdownItalicPattern  = regexp.MustCompile(`(^|[^\w*])\*(\S(?:[^*]*\S)?)\*|(^|[^\w_])_(\S(?:[^_]*\S)?)_`)
+	markdownStrikePattern  = regexp.MustCompile(`~~(.+?)~~`)
+	ansiPattern            = regexp.MustCompile(`\x1b\[[0-9;]*m`)
+)
+
+var (
+	markdownHeadingStyle = color.New(color.Bold, color.Underline)
+	markdownBoldStyle    = color.New(color.Bold)
+	markdownItalicStyle  = color.New(color.Italic)
+	markdownStrikeStyle  = color.New(color.CrossedOut)
+	markdownCodeStyle    = color.New(color.FgCyan)
+	markdownFaintStyle   = color.New(color.Faint)
+)
+
+// RenderMarkdown formats markdown for reading in a terminal: headings, lists,
+// quotes, rules, code blocks and tables are laid out and emphasis is styled.
+// Without color, headings are still underlined with "=" and "-".
+func RenderMarkdown(text string) string {
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	var out []string
+
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+
+		// Fenced code block
+		if fence := codeFence(trimmed); fence != "" {
+			i++
+			for ; i < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[i]), fence); i++ {
+				out = append(out, "    "+markdownCodeStyle.Sprint(strings.TrimRight(lines[i], " \t")))
+			}
+			continue
+		}
+
+		// Table: a row followed by a separator row
+		if strings.HasPrefix(trimmed, "|") && i+1 < len(lines) && markdownTableSeparator.MatchString(strings.TrimSpace(lines[i+1])) {
+			rows := [][]string{splitTableRow(trimmed)}
+			i += 2
+			for ; i < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[i]), "|"); i++ {
+				rows = append(rows, splitTableRow(strings.TrimSpace(lines[i])))
+			}
+			i--
+			out = append(out, renderTable(rows)...)
+			continue
+		}
+
+		switch {
+		case trimmed == "":
+			out = append(out, "")
+		case markdownRulePattern.MatchString(trimmed):
+			out = append(out, markdownFaintStyle.Sprint(strings.Repeat("─", 40)))
+		case strings.HasPrefix(trimmed, ">"):
+			quote := strings.TrimSpace(strings.TrimLeft(trimmed, "> "))
+			out = append(out, markdownFaintStyle.Sprint("│ ")+renderInline(quote))
+		default:
+			if m := markdownHeadingPattern.FindStringSubmatch(trimmed); m != nil {
+				out = append(out, renderHeading(len(m[1]), m[2])...)
+			} else if m := markdownBulletPattern.FindStringSubmatch(line); m != nil {
+				bullet, item := "•", m[2]
+				switch {
+				case strings.HasPrefix(item, "[ ] "):
+					bullet, item = "☐", item[4:]
+				case strings.HasPrefix(item, "[x] "), strings.HasPrefix(item, "[X] "):
+					bullet, item = "☑", item[4:]
+				}
+				out = append(out, listIndent(m[1])+bullet+" "+renderInline(item))
+			} else if m := markdownNumberPattern.FindStringSubmatch(line); m != nil {
+				out = append(out, listIndent(m[1])+m[2]+". "+renderInline(m[3]))
+			} else {
+				out = append(out, renderInline(trimmed))
+			}
+		}
+	}
+
+	// Collapse runs of blank lines left by the markup
+	var rendered []string
+	for i, line := range out {
+		if line == "" && (i == 0 || out[i-1] == "") {
+			continue
+		}
+		rendered = append(rendered, line)
+	}
+	return strings.TrimRight(strings.Join(rendered, "\n"), "\n")
+}
+
+// codeFence returns the fence that opens a code block on the line, if any
+func codeFence(line string) string {
+	for _, fence := range []string{"```", "~~~"} {
+		if strings.HasPrefix(line, fence) {
+			return fence
+		}
+	}
+	return ""
+}
+
+func renderHeading(level int, text string) []string {
+	heading := renderInline(text)
+	switch level {
+	case 1, 2:
+		underline := "="
+		if level == 2 {
+			underline = "-"
+		}
+		return []string{"", markdownHeadingStyle.Sprint(heading), strings.Repeat(underline, visibleWidth(heading))}
+	default:
+		return []string{"", markdownBoldStyle.Sprint(heading)}
+	}
+}
+
+// listIndent turns the indentation of a nested list item into two spaces
+// per level
+func listIndent(indent string) string {
+	width := len(strings.ReplaceAll(indent, "\t", "    "))
+	return strings.Repeat("  ", width/2)
+}
+
+func splitTableRow(line string) []string {
+	line = strings.TrimSuffix(strings.TrimPrefix(line, "|"), "|")
+	cells := strings.Split(line, "|")
+	for i, cell := range cells {
+		cells[i] = renderInline(strings.TrimSpace(cell))
+	}
+	return cells
+}
+
+// renderTable aligns table columns. Widths are measured without escape codes.
+func renderTable(rows [][]string) []string {
+	var widths []int
+	for _, row := range rows {
+		for i, cell := range row {
+			if i >= len(widths) {
+				widths = append(widths, 0)
+			}
+			if w := visibleWidth(cell); w > widths[i] {
+				widths[i] = w
+			}
+		}
+	}
+
+	format := func(row []string, header bool) string {
+		cells := make([]string, len(widths))
+		for i, width := range widths {
+			cell := ""
+			if i < len(row) {
+				cell = row[i]
+			}
+			if header {
+				cell = markdownBoldStyle.Sprint(cell)
+			}
+			cells[i] = cell + strings.Repeat(" ", width-visibleWidth(cell))
+		}
+		return strings.TrimRight(strings.Join(cells, " │ "), " ")
+	}
+
+	separators := make([]string, len(widths))
+	for i, width := range widths {
+		separators[i] = strings.Repeat("─", width)
+	}
+
+	lines := []string{format(rows[0], true), strings.Join(separators, "─┼─")}
+	for _, row := range rows[1:] {
+		lines = append(lines, format(row, false))
+	}
+	return lines
+}
+
+// renderInline styles emphasis, code spans and links. Code spans are kept
+// verbatim.
+func renderInline(text string) string {
+	parts := strings.Split(text, "`")
+	for i, part := range parts {
+		if i%2 == 1 && i < len(parts)-1 {
+			parts[i] = markdownCodeStyle.Sprint(part)
+			continue
+		}
+		if i%2 == 1 {
+			// An unmatched backtick is literal
+			part = "`" + part
+		}
+		parts[i] = renderEmphasis(part)
+	}
+	return strings.Join(parts, "")
+}
+
+func renderEmphasis(text string) string {
+	text = markdownImagePattern.ReplaceAllStringFunc(text, func(match string) string {
+		m := markdownImagePattern.FindStringSubmatch(match)
+		return fmt.Sprintf("[%s] %s", strings.TrimSpace("image "+m[1]), markdownFaintStyle.Sprint("("+m[2]+")"))
+	})
+	text = markdownLinkPattern.ReplaceAllStringFunc(text, func(match string) string {
+		m := markdownLinkPattern.FindStringSubmatch(match)
+		if m[1] == m[2] {
+			return markdownCodeStyle.Sprint(m[2])
+		}
+		return m[1] + " " + markdownFaintStyle.Sprint("("+m[2]+")")
+	})
+	text = markdownAutoLink.ReplaceAllString(text, "$1")
+	text = markdownBoldPattern.ReplaceAllStringFunc(text, func(match string) string {
+		m := markdownBoldPattern.FindStringSubmatch(match)
+		return markdownBoldStyle.Sprint(m[1] + m[2])
+	})
+	text = markdownItalicPattern.ReplaceAllStringFunc(text, func(match string) string {
+		m := markdownItalicPattern.FindStringSubmatch(match)
+		if m[2] != "" {
+			return m[1] + markdownItalicStyle.Sprint(m[2])
+		}
+		return m[3] + markdownItalicStyle.Sprint(m[4])
+	})
+	text = markdownStrikePattern.ReplaceAllStringFunc(text, func(match string) string {
+		return markdownStrikeStyle.Sprint(markdownStrikePattern.FindStringSubmatch(match)[1])
+	})
+	return text
+}
+
+// visibleWidth counts the characters of text that take up terminal columns
+func visibleWidth(text string) int {
+	return utf8.RuneCountInString(ansiPattern.ReplaceAllString(text, ""))
+}
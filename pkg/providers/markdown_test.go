@@ -0,0 +1,87 @@
+package providers
+
+import (
+	"testing"
+
+	"github.com/fatih/color"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarkupToMarkdown(t *testing.T) {
+	t.Run("Jira", func(t *testing.T) {
+		jira := "h2. Steps\n" +
+			"# Open {{/login}}\n" +
+			"## Enter *valid* and _wrong_ passwords\n" +
+			"# See [the docs|https://docs.example.com]\n" +
+			"* -old- behavior\n" +
+			"{code:go}\nfmt.Println(\"*x*\")\n{code}\n" +
+			"||Field||Value||\n" +
+			"|name|{{alice}}|\n" +
+			"bq. Quoted"
+
+		assert.Equal(t, "## Steps\n"+
+			"1. Open `/login`\n"+
+			"  1. Enter **valid** and *wrong* passwords\n"+
+			"2. See [the docs](https://docs.example.com)\n"+
+			"- ~~old~~ behavior\n"+
+			"```go\nfmt.Println(\"*x*\")\n```\n"+
+			"| Field | Value |\n| --- | --- |\n"+
+			"| name | `alice` |\n"+
+			"> Quoted", MarkupToMarkdown(jira, ProviderTypeJira))
+	})
+
+	t.Run("YouTrack wiki", func(t *testing.T) {
+		wiki := "= Summary =\n{code java}\nint x = 1;\n{code}\nSee [https://example.com the spec] and {{x}}"
+		assert.Equal(t, "# Summary\n```java\nint x = 1;\n```\nSee [the spec](https://example.com) and `x`",
+			MarkupToMarkdown(wiki, ProviderTypeYouTrack))
+	})
+
+	t.Run("Markdown is unchanged", func(t *testing.T) {
+		markdown := "Title\n===\n\n- *item* with [link](https://example.com)\n```\n= not a heading =\n```"
+		assert.Equal(t, markdown, MarkupToMarkdown(markdown, ProviderTypeYouTrack))
+		assert.Equal(t, "*a* {{b}}", MarkupToMarkdown("*a* {{b}}", ProviderTypeNotion))
+	})
+}
+
+func TestRenderMarkdown(t *testing.T) {
+	noColor := color.NoColor
+	color.NoColor = true
+	defer func() { color.NoColor = noColor }()
+
+	t.Run("Blocks", func(t *testing.T) {
+		markdown := "# Login fails\n\n\n" +
+			"Users **can't** log in, see [issue](https://example.com/1).\n" +
+			"## Steps\n" +
+			"1. Open `/login`\n" +
+			"   - [x] check *cookies*\n" +
+			"> Reported by support\n" +
+			"---\n" +
+			"```sh\ncurl -X POST **not bold**\n```"
+
+		assert.Equal(t, "Login fails\n"+
+			"===========\n"+
+			"\n"+
+			"Users can't log in, see issue (https://example.com/1).\n"+
+			"\n"+
+			"Steps\n"+
+			"-----\n"+
+			"1. Open /login\n"+
+			"  ☑ check cookies\n"+
+			"│ Reported by support\n"+
+			"────────────────────────────────────────\n"+
+			"    curl -X POST **not bold**", RenderMarkdown(markdown))
+	})
+
+	t.Run("Tables are aligned", func(t *testing.T) {
+		markdown := "| Field | Value |\n|:--|--:|\n| name | `alice` |\n| role | |"
+		assert.Equal(t, "Field │ Value\n"+
+			"──────┼──────\n"+
+			"name  │ alice\n"+
+			"role  │", RenderMarkdown(markdown))
+	})
+
+	t.Run("Plain text is kept", func(t *testing.T) {
+		assert.Equal(t, "snake_case_name and 2 * 3 * 4", RenderMarkdown("snake_case_name and 2 * 3 * 4"))
+		assert.Equal(t, "a `b", RenderMarkdown("a `b"))
+	})
+}
@@ -0,0 +1,312 @@
+package providers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// DefaultMetadataTTL is how long users, projects and statuses stay cached.
+// They change far less often than tasks, so the TTL is much longer.
+const DefaultMetadataTTL = 1 * time.Hour
+
+// MetadataKind is a kind of provider metadata kept in the cache
+type MetadataKind string
+
+const (
+	MetadataUsers    MetadataKind = "users"
+	MetadataProjects MetadataKind = "projects"
+	MetadataStatuses MetadataKind = "statuses"
+)
+
+// MetadataKey identifies a cached lookup, e.g. the statuses of one project
+type MetadataKey struct {
+	Provider string       `json:"provider"`
+	Kind     MetadataKind `json:"kind"`
+	Key      string       `json:"key"`
+}
+
+type metadataEntry struct {
+	MetadataKey
+	Fingerprint string          `json:"fingerprint"`
+	FetchedAt   time.Time       `json:"fetchedAt"`
+	Value       json.RawMessage `json:"value"`
+}
+
+// MetadataCache is a file-backed cache of provider metadata lookups shared
+// between CLI invocations. Entries are dropped when they expire or when the
+// configuration of their provider changes.
+type MetadataCache struct {
+	mu      sync.Mutex
+	path    string
+	entries map[MetadataKey]*metadataEntry
+	logger  *logrus.Logger
+	now     func() time.Time
+}
+
+// MetadataCacheFile is the file name of the metadata cache in a config directory
+const MetadataCacheFile = "metadata_cache.json"
+
+// DefaultMetadataCachePath returns the default location of the metadata cache
+func DefaultMetadataCachePath() string {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join(".ricochet", MetadataCacheFile)
+	}
+	return filepath.Join(homeDir, ".ricochet", MetadataCacheFile)
+}
+
+// NewMetadataCache creates a metadata cache backed by the given file.
+// An empty path keeps entries in memory only.
+func NewMetadataCache(path string, logger *logrus.Logger) (*MetadataCache, error) {
+	if logger == nil {
+		logger = logrus.New()
+	}
+
+	cache := &MetadataCache{
+		path:    path,
+		entries: make(map[MetadataKey]*metadataEntry),
+		logger:  logger,
+		now:     time.Now,
+	}
+
+	if err := cache.load(); err != nil {
+		return nil, err
+	}
+
+	return cache, nil
+}
+
+// ConfigFingerprint hashes a provider configuration so cached metadata can be
+// invalidated when the configuration changes
+func ConfigFingerprint(config *ProviderConfig) string {
+	if config == nil {
+		return ""
+	}
+	data, err := json.Marshal(config)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:8])
+}
+
+// Lookup decodes the cached value for key into dest. If there is no fresh
+// entry for the given provider fingerprint, fetch is called and its result is
+// cached. Failing to persist the cache is logged, not returned.
+func (c *MetadataCache) Lookup(key MetadataKey, fingerprint string, ttl time.Duration, dest interface{}, fetch func() (interface{}, error)) error {
+	if ttl <= 0 {
+		ttl = DefaultMetadataTTL
+	}
+
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	if ok && entry.Fingerprint == fingerprint && c.now().Sub(entry.FetchedAt) < ttl {
+		value := entry.Value
+		c.mu.Unlock()
+		return json.Unmarshal(value, dest)
+	}
+	c.mu.Unlock()
+
+	result, err := fetch()
+	if err != nil {
+		return err
+	}
+
+	value, err := json.Marshal(result)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s for cache: %w", key.Kind, err)
+	}
+
+	c.mu.Lock()
+	// Entries cached under an older configuration of the provider are stale
+	for cachedKey, cached := range c.entries {
+		if cachedKey.Provider == key.Provider && cached.Fingerprint != fingerprint {
+			delete(c.entries, cachedKey)
+		}
+	}
+	c.entries[key] = &metadataEntry{
+		MetadataKey: key,
+		Fingerprint: fingerprint,
+		FetchedAt:   c.now(),
+		Value:       value,
+	}
+	if err := c.save(); err != nil {
+		c.logger.Warnf("Failed to save metadata cache: %v", err)
+	}
+	c.mu.Unlock()
+
+	return json.Unmarshal(value, dest)
+}
+
+// InvalidateProvider drops all entries of a provider and returns how many were removed
+func (c *MetadataCache) InvalidateProvider(provider string) (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	removed := 0
+	for key := range c.entries {
+		if key.Provider == provider {
+			delete(c.entries, key)
+			removed++
+		}
+	}
+	if removed == 0 {
+		return 0, nil
+	}
+	return removed, c.save()
+}
+
+// Clear drops every entry and returns how many were removed
+func (c *MetadataCache) Clear() (int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	removed := len(c.entries)
+	c.entries = make(map[MetadataKey]*metadataEntry)
+	return removed, c.save()
+}
+
+// Len returns the number of cached entries
+func (c *MetadataCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return len(c.entries)
+}
+
+// save persists entries to disk. Must be called with the lock held.
+func (c *MetadataCache) save() error {
+	if c.path == "" {
+		return nil
+	}
+
+	all := make([]*metadataEntry, 0, len(c.entries))
+	for _, entry := range c.entries {
+		all = append(all, entry)
+	}
+	sort.Slice(all, func(i, j int) bool {
+		a, b := all[i].MetadataKey, all[j].MetadataKey
+		if a.Provider != b.Provider {
+			return a.Provider < b.Provider
+		}
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		return a.Key < b.Key
+	})
+
+	data, err := json.MarshalIndent(all, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal metadata cache: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(c.path), 0755); err != nil {
+		return fmt.Errorf("failed to create metadata cache directory: %w", err)
+	}
+	if err := os.WriteFile(c.path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write metadata cache: %w", err)
+	}
+
+	return nil
+}
+
+// load restores entries from disk
+func (c *MetadataCache) load() error {
+	if c.path == "" {
+		return nil
+	}
+
+	data, err := os.ReadFile(c.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to read metadata cache: %w", err)
+	}
+
+	var all []*metadataEntry
+	if err := json.Unmarshal(data, &all); err != nil {
+		// A corrupt cache is only a cache: start over instead of failing every command
+		c.logger.Warnf("Ignoring unreadable metadata cache %s: %v", c.path, err)
+		return nil
+	}
+
+	for _, entry := range all {
+		c.entries[entry.MetadataKey] = entry
+	}
+
+	return nil
+}
+
+// CachingProvider wraps a TaskProvider and serves metadata lookups from a MetadataCache
+type CachingProvider struct {
+	TaskProvider
+	name        string
+	fingerprint string
+	ttl         time.Duration
+	cache       *MetadataCache
+}
+
+// NewCachingProvider wraps provider so its metadata lookups go through cache.
+// The TTL comes from the provider's cache config, defaulting to DefaultMetadataTTL.
+func NewCachingProvider(provider TaskProvider, name string, config *ProviderConfig, cache *MetadataCache) *CachingProvider {
+	ttl := DefaultMetadataTTL
+	if config != nil && config.CacheConfig != nil && config.CacheConfig.MetadataTTL > 0 {
+		ttl = config.CacheConfig.MetadataTTL
+	}
+
+	return &CachingProvider{
+		TaskProvider: provider,
+		name:         name,
+		fingerprint:  ConfigFingerprint(config),
+		ttl:          ttl,
+		cache:        cache,
+	}
+}
+
+// Unwrap returns the underlying provider
+func (p *CachingProvider) Unwrap() TaskProvider {
+	return p.TaskProvider
+}
+
+// GetAvailableStatuses returns the project's statuses, fetching them at most once per TTL
+func (p *CachingProvider) GetAvailableStatuses(ctx context.Context, projectID string) ([]TaskStatus, error) {
+	var statuses []TaskStatus
+	key := MetadataKey{Provider: p.name, Kind: MetadataStatuses, Key: projectID}
+	err := p.cache.Lookup(key, p.fingerprint, p.ttl, &statuses, func() (interface{}, error) {
+		return p.TaskProvider.GetAvailableStatuses(ctx, projectID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return statuses, nil
+}
+
+// ListUsers returns the provider's users, fetching them at most once per TTL.
+// Providers that can't list users report an unsupported error.
+func (p *CachingProvider) ListUsers(ctx context.Context) ([]*User, error) {
+	lister, ok := ProviderAs[UserProvider](p.TaskProvider)
+	if !ok {
+		return nil, NewUnsupportedError(p.name, CapabilityUsers)
+	}
+
+	var users []*User
+	key := MetadataKey{Provider: p.name, Kind: MetadataUsers}
+	err := p.cache.Lookup(key, p.fingerprint, p.ttl, &users, func() (interface{}, error) {
+		return lister.ListUsers(ctx)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return users, nil
+}
@@ -0,0 +1,93 @@
+package providers
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// statusProvider counts GetAvailableStatuses calls
+type statusProvider struct {
+	TaskProvider
+	calls int
+}
+
+func (p *statusProvider) GetAvailableStatuses(ctx context.Context, projectID string) ([]TaskStatus, error) {
+	p.calls++
+	return []TaskStatus{{ID: "open", Name: "Open"}, {ID: "done", Name: "Done", IsFinal: true}}, nil
+}
+
+func TestMetadataCache(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "metadata_cache.json")
+	config := &ProviderConfig{Name: "yt", Type: ProviderTypeYouTrack, BaseURL: "https://a.example.com"}
+
+	cache, err := NewMetadataCache(path, nil)
+	require.NoError(t, err)
+	now := time.Now()
+	cache.now = func() time.Time { return now }
+
+	inner := &statusProvider{}
+	provider := NewCachingProvider(inner, "yt", config, cache)
+
+	t.Run("Fetches once per TTL", func(t *testing.T) {
+		statuses, err := provider.GetAvailableStatuses(context.Background(), "PROJ")
+		require.NoError(t, err)
+		assert.Len(t, statuses, 2)
+
+		statuses, err = provider.GetAvailableStatuses(context.Background(), "PROJ")
+		require.NoError(t, err)
+		assert.True(t, statuses[1].IsFinal)
+		assert.Equal(t, 1, inner.calls)
+
+		_, err = provider.GetAvailableStatuses(context.Background(), "OTHER")
+		require.NoError(t, err)
+		assert.Equal(t, 2, inner.calls)
+	})
+
+	t.Run("Shared between runs", func(t *testing.T) {
+		reloaded, err := NewMetadataCache(path, nil)
+		require.NoError(t, err)
+		reloaded.now = cache.now
+
+		_, err = NewCachingProvider(inner, "yt", config, reloaded).GetAvailableStatuses(context.Background(), "PROJ")
+		require.NoError(t, err)
+		assert.Equal(t, 2, inner.calls)
+	})
+
+	t.Run("Expires after TTL", func(t *testing.T) {
+		now = now.Add(DefaultMetadataTTL + time.Minute)
+
+		_, err := provider.GetAvailableStatuses(context.Background(), "PROJ")
+		require.NoError(t, err)
+		assert.Equal(t, 3, inner.calls)
+	})
+
+	t.Run("Config change invalidates", func(t *testing.T) {
+		changed := *config
+		changed.BaseURL = "https://b.example.com"
+
+		_, err := NewCachingProvider(inner, "yt", &changed, cache).GetAvailableStatuses(context.Background(), "PROJ")
+		require.NoError(t, err)
+		assert.Equal(t, 4, inner.calls)
+		// Entries cached under the old configuration are dropped
+		assert.Equal(t, 1, cache.Len())
+	})
+
+	t.Run("Clear", func(t *testing.T) {
+		removed, err := cache.InvalidateProvider("other")
+		require.NoError(t, err)
+		assert.Equal(t, 0, removed)
+
+		removed, err = cache.Clear()
+		require.NoError(t, err)
+		assert.Equal(t, 1, removed)
+
+		reloaded, err := NewMetadataCache(path, nil)
+		require.NoError(t, err)
+		assert.Equal(t, 0, reloaded.Len())
+	})
+}
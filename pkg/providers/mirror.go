@@ -0,0 +1,166 @@
+package providers
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Sides of a MirrorDiff
+const (
+	MirrorSideFrom = "from"
+	MirrorSideTo   = "to"
+)
+
+// MirrorDiff compares a task with its mirror in another provider
+type MirrorDiff struct {
+	FromProvider string         `json:"fromProvider"`
+	ToProvider   string         `json:"toProvider"`
+	From         *UniversalTask `json:"from,omitempty"`
+	To           *UniversalTask `json:"to,omitempty"`
+	// Missing is the side the task could not be found on, if any
+	Missing string `json:"missing,omitempty"`
+	// Newer is the side that was updated last, empty if unknown or equal
+	Newer string `json:"newer,omitempty"`
+	// Changes lists the differing fields, Old holds the from side and New the to side
+	Changes []FieldChange `json:"changes"`
+}
+
+// FindMirror picks the mirror of source among candidates from another
+// provider: a task that references the source ID in its custom fields,
+// labels or description, or that references one in source, wins; otherwise
+// the only task with the same title. It returns nil if there is no match.
+func FindMirror(source *UniversalTask, candidates []*UniversalTask) *UniversalTask {
+	var sameTitle []*UniversalTask
+	for _, candidate := range candidates {
+		if candidate == nil {
+			continue
+		}
+		if referencesTask(candidate, source) || referencesTask(source, candidate) {
+			return candidate
+		}
+		if strings.EqualFold(strings.TrimSpace(candidate.Title), strings.TrimSpace(source.Title)) {
+			sameTitle = append(sameTitle, candidate)
+		}
+	}
+	if len(sameTitle) == 1 {
+		return sameTitle[0]
+	}
+	return nil
+}
+
+// CompareMirroredTasks diffs the fields of a task and its mirror. Descriptions
+// are compared as markdown so differing markup alone is not a change, and
+// only custom fields present on both sides are compared.
+func CompareMirroredTasks(from, to *UniversalTask) *MirrorDiff {
+	diff := &MirrorDiff{From: from, To: to, Changes: []FieldChange{}}
+	if from != nil {
+		diff.FromProvider = from.ProviderName
+	}
+	if to != nil {
+		diff.ToProvider = to.ProviderName
+	}
+	switch {
+	case from == nil:
+		diff.Missing = MirrorSideFrom
+		return diff
+	case to == nil:
+		diff.Missing = MirrorSideTo
+		return diff
+	}
+
+	add := func(field, fromValue, toValue string) {
+		if fromValue != toValue {
+			diff.Changes = append(diff.Changes, FieldChange{Field: field, Old: fromValue, New: toValue})
+		}
+	}
+
+	add("title", from.Title, to.Title)
+	add("description", strings.TrimSpace(mirrorDescription(from)), strings.TrimSpace(mirrorDescription(to)))
+	add("status", from.Status.Name, to.Status.Name)
+	add("statusCategory", string(from.Status.Category), string(to.Status.Category))
+	add("priority", string(from.Priority), string(to.Priority))
+	add("type", string(from.Type), string(to.Type))
+	add("assignee", from.AssigneeID, to.AssigneeID)
+	add("dueDate", formatDiffTime(from.DueDate), formatDiffTime(to.DueDate))
+	add("labels", strings.Join(sortedLabels(from.Labels), ", "), strings.Join(sortedLabels(to.Labels), ", "))
+	add("estimatedTime", formatDiffDuration(from.EstimatedTime), formatDiffDuration(to.EstimatedTime))
+	add("storyPoints", formatDiffNumber(from.StoryPoints), formatDiffNumber(to.StoryPoints))
+
+	var keys []string
+	for key := range from.CustomFields {
+		if _, exists := to.CustomFields[key]; exists {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		add("customFields."+key, formatDiffValue(from.CustomFields[key]), formatDiffValue(to.CustomFields[key]))
+	}
+
+	switch {
+	case from.UpdatedAt.IsZero() || to.UpdatedAt.IsZero() || from.UpdatedAt.Equal(to.UpdatedAt):
+	case from.UpdatedAt.After(to.UpdatedAt):
+		diff.Newer = MirrorSideFrom
+	default:
+		diff.Newer = MirrorSideTo
+	}
+	return diff
+}
+
+// referencesTask reports whether task mentions the display ID of other
+func referencesTask(task, other *UniversalTask) bool {
+	id := other.GetDisplayID()
+	if id == "" {
+		return false
+	}
+
+	var texts []string
+	for _, value := range task.CustomFields {
+		if s, ok := value.(string); ok {
+			texts = append(texts, s)
+		}
+	}
+	texts = append(texts, task.Labels...)
+	texts = append(texts, task.Description)
+
+	pattern := regexp.MustCompile(`(^|[^\w-])` + regexp.QuoteMeta(id) + `($|[^\w-])`)
+	for _, text := range texts {
+		if pattern.MatchString(text) {
+			return true
+		}
+	}
+	return false
+}
+
+func mirrorDescription(task *UniversalTask) string {
+	if task.ProviderConfig == nil {
+		return task.Description
+	}
+	return MarkupToMarkdown(task.Description, task.ProviderConfig.Type)
+}
+
+func sortedLabels(labels []string) []string {
+	sorted := append([]string(nil), labels...)
+	sort.Strings(sorted)
+	return sorted
+}
+
+func formatDiffNumber(n *float64) string {
+	if n == nil {
+		return ""
+	}
+	return fmt.Sprintf("%g", *n)
+}
+
+func formatDiffValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case time.Time:
+		return v.Format("2006-01-02")
+	}
+	return fmt.Sprintf("%v", value)
+}
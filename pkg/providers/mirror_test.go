@@ -0,0 +1,80 @@
+package providers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindMirror(t *testing.T) {
+	source := &UniversalTask{Key: "OPS-1", Title: "Fix login"}
+
+	t.Run("Reference wins over title", func(t *testing.T) {
+		mirror := FindMirror(source, []*UniversalTask{
+			{Key: "EXT-1", Title: "Fix login"},
+			{Key: "EXT-2", Title: "Login bug", CustomFields: map[string]interface{}{"Source": "OPS-1"}},
+		})
+		require.NotNil(t, mirror)
+		assert.Equal(t, "EXT-2", mirror.Key)
+	})
+
+	t.Run("Source references the mirror", func(t *testing.T) {
+		linked := &UniversalTask{Key: "OPS-1", Title: "Fix login", Description: "Mirrored as EXT-3."}
+		mirror := FindMirror(linked, []*UniversalTask{{Key: "EXT-30"}, {Key: "EXT-3"}})
+		require.NotNil(t, mirror)
+		assert.Equal(t, "EXT-3", mirror.Key)
+	})
+
+	t.Run("IDs match whole words only", func(t *testing.T) {
+		assert.Nil(t, FindMirror(source, []*UniversalTask{{Key: "EXT-4", Labels: []string{"OPS-12"}}}))
+	})
+
+	t.Run("Unique title", func(t *testing.T) {
+		mirror := FindMirror(source, []*UniversalTask{{Key: "EXT-5", Title: " fix LOGIN"}})
+		require.NotNil(t, mirror)
+		assert.Equal(t, "EXT-5", mirror.Key)
+
+		assert.Nil(t, FindMirror(source, []*UniversalTask{{Key: "EXT-5", Title: "Fix login"}, {Key: "EXT-6", Title: "Fix login"}}))
+	})
+}
+
+func TestCompareMirroredTasks(t *testing.T) {
+	earlier := time.Now().Add(-time.Hour)
+	points := 3.0
+	from := &UniversalTask{
+		Key: "OPS-1", Title: "Fix login", Description: "Steps\n",
+		Status:       TaskStatus{Name: "In Progress", Category: StatusCategoryInProgress},
+		Priority:     TaskPriorityHigh,
+		Labels:       []string{"bug", "auth"},
+		StoryPoints:  &points,
+		CustomFields: map[string]interface{}{"Team": "Platform", "Sprint": "12"},
+		UpdatedAt:    earlier,
+	}
+	to := &UniversalTask{
+		Key: "EXT-1", Title: "Fix login", Description: "Steps",
+		Status:       TaskStatus{Name: "Done", Category: StatusCategoryDone},
+		Priority:     TaskPriorityHigh,
+		Labels:       []string{"auth", "bug"},
+		CustomFields: map[string]interface{}{"Team": "Core"},
+		UpdatedAt:    time.Now(),
+	}
+
+	diff := CompareMirroredTasks(from, to)
+	assert.Empty(t, diff.Missing)
+	assert.Equal(t, MirrorSideTo, diff.Newer)
+	assert.Equal(t, []FieldChange{
+		{Field: "status", Old: "In Progress", New: "Done"},
+		{Field: "statusCategory", Old: "in_progress", New: "done"},
+		{Field: "storyPoints", Old: "3", New: ""},
+		{Field: "customFields.Team", Old: "Platform", New: "Core"},
+	}, diff.Changes)
+
+	assert.Equal(t, MirrorSideFrom, CompareMirroredTasks(to, from).Newer)
+	assert.Empty(t, CompareMirroredTasks(from, from).Changes)
+
+	missing := CompareMirroredTasks(from, nil)
+	assert.Equal(t, MirrorSideTo, missing.Missing)
+	assert.Empty(t, missing.Changes)
+}
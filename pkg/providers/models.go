@@ -50,6 +50,7 @@ type UniversalTask struct {
 	Comments      []*Comment             `json:"comments,omitempty"`
 
 	// Time tracking
+	StoryPoints     *float64       `json:"storyPoints,omitempty"` // From a custom field, see FieldMapping
 	EstimatedTime   *time.Duration `json:"estimatedTime,omitempty"`
 	TimeSpent       *time.Duration `json:"timeSpent,omitempty"`
 	RemainingTime   *time.Duration `json:"remainingTime,omitempty"`
@@ -109,6 +110,15 @@ const (
 	TaskPriorityCritical TaskPriority = "critical"
 )
 
+// IsValid reports whether the priority is one of the known priorities
+func (p TaskPriority) IsValid() bool {
+	switch p {
+	case TaskPriorityLowest, TaskPriorityLow, TaskPriorityMedium, TaskPriorityHigh, TaskPriorityHighest, TaskPriorityCritical:
+		return true
+	}
+	return false
+}
+
 // TaskType represents different types of tasks
 type TaskType string
 
@@ -125,6 +135,16 @@ const (
 	TaskTypeChore       TaskType = "chore"
 )
 
+// IsValid reports whether the type is one of the known task types
+func (t TaskType) IsValid() bool {
+	switch t {
+	case TaskTypeTask, TaskTypeStory, TaskTypeBug, TaskTypeEpic, TaskTypeSubtask, TaskTypeFeature,
+		TaskTypeImprovement, TaskTypeSpike, TaskTypeResearch, TaskTypeChore:
+		return true
+	}
+	return false
+}
+
 // UniversalBoard represents a board/project across providers
 type UniversalBoard struct {
 	// Core identifiers
@@ -347,11 +367,14 @@ type TaskUpdate struct {
 	Description   *string                `json:"description,omitempty"`
 	Status        *TaskStatus            `json:"status,omitempty"`
 	Priority      *TaskPriority          `json:"priority,omitempty"`
+	Type          *TaskType              `json:"type,omitempty"`
 	AssigneeID    *string                `json:"assigneeId,omitempty"`
 	DueDate       *time.Time             `json:"dueDate,omitempty"`
+	StartDate     *time.Time             `json:"startDate,omitempty"`
 	Labels        []string               `json:"labels,omitempty"`
 	CustomFields  map[string]interface{} `json:"customFields,omitempty"`
 	EstimatedTime *time.Duration         `json:"estimatedTime,omitempty"`
+	StoryPoints   *float64               `json:"storyPoints,omitempty"`
 }
 
 type TaskFilters struct {
@@ -362,7 +385,10 @@ type TaskFilters struct {
 	Status       []string     `json:"status,omitempty"`
 	Priority     []string     `json:"priority,omitempty"`
 	Type         []string     `json:"type,omitempty"`
-	Labels       []string     `json:"labels,omitempty"`
+	Labels       []string     `json:"labels,omitempty"` // Same as LabelsAll
+	LabelsAll    []string     `json:"labelsAll,omitempty"`  // Tasks must have every label
+	LabelsAny    []string     `json:"labelsAny,omitempty"`  // Tasks must have at least one label
+	LabelsNone   []string     `json:"labelsNone,omitempty"` // Tasks must have none of the labels
 	CreatedAfter *time.Time   `json:"createdAfter,omitempty"`
 	CreatedBefore *time.Time  `json:"createdBefore,omitempty"`
 	UpdatedAfter *time.Time   `json:"updatedAfter,omitempty"`
@@ -372,6 +398,10 @@ type TaskFilters struct {
 	Query        string       `json:"query,omitempty"`
 	Limit        int          `json:"limit,omitempty"`
 	Offset       int          `json:"offset,omitempty"`
+
+	// Project of each provider, by provider name, for providers listed
+	// without ProjectID, e.g. their default projects; see ForProvider
+	ProjectByProvider map[string]string `json:"-"`
 }
 
 type BoardUpdate struct {
@@ -514,6 +544,18 @@ func (t *UniversalTask) IsBlocked() bool {
 	return t.Status.Category == StatusCategoryBlocked || len(t.BlockedBy) > 0
 }
 
+// IsAIManaged reports whether Ricochet's AI has worked on the task: it is
+// linked to a chain or Ricochet task, or has an AI execution on record
+func (t *UniversalTask) IsAIManaged() bool {
+	metadata := t.RicochetMetadata
+	if metadata == nil {
+		return false
+	}
+	return metadata.ChainID != "" || metadata.RicochetTaskID != "" || metadata.AutoExecution ||
+		len(metadata.AIExecutionHistory) > 0 ||
+		(metadata.AIExecutionState != "" && metadata.AIExecutionState != AIExecutionStateIdle)
+}
+
 func (t *UniversalTask) HasSubtasks() bool {
 	return len(t.SubtaskIDs) > 0
 }
@@ -0,0 +1,206 @@
+package providers
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// TaskNote is a local note about a task. Notes are personal reminders and
+// working memory; they are never sent to the provider.
+type TaskNote struct {
+	Provider  string    `json:"provider"`
+	TaskID    string    `json:"taskId"`
+	Text      string    `json:"text"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// TaskNoteStore keeps local task notes keyed by provider and task ID
+type TaskNoteStore struct {
+	mu     sync.RWMutex
+	path   string
+	notes  map[string]*TaskNote // provider/taskID -> note
+	logger *logrus.Logger
+}
+
+// NotesFile is the file name of the note store in a config directory
+const NotesFile = "notes.json"
+
+// NewTaskNoteStore creates a note store backed by the given file.
+// An empty path keeps notes in memory only.
+func NewTaskNoteStore(path string, logger *logrus.Logger) (*TaskNoteStore, error) {
+	if logger == nil {
+		logger = logrus.New()
+	}
+
+	store := &TaskNoteStore{
+		path:   path,
+		notes:  make(map[string]*TaskNote),
+		logger: logger,
+	}
+
+	if err := store.load(); err != nil {
+		return nil, err
+	}
+
+	return store, nil
+}
+
+func noteKey(provider, taskID string) string {
+	return provider + "/" + taskID
+}
+
+// Set replaces the note of a task
+func (s *TaskNoteStore) Set(provider, taskID, text string) (*TaskNote, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.set(provider, taskID, text)
+}
+
+// Append adds a line to the note of a task, creating the note if needed
+func (s *TaskNoteStore) Append(provider, taskID, text string) (*TaskNote, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if existing, exists := s.notes[noteKey(provider, taskID)]; exists && strings.TrimSpace(text) != "" {
+		text = existing.Text + "\n" + strings.TrimSpace(text)
+	}
+	return s.set(provider, taskID, text)
+}
+
+// set stores a note. Must be called with the lock held.
+func (s *TaskNoteStore) set(provider, taskID, text string) (*TaskNote, error) {
+	text = strings.TrimSpace(text)
+	if provider == "" || taskID == "" {
+		return nil, NewProviderError(ErrorTypeValidation, "provider and task ID are required", nil)
+	}
+	if text == "" {
+		return nil, NewProviderError(ErrorTypeValidation, "note text is required", nil)
+	}
+
+	note := &TaskNote{
+		Provider:  provider,
+		TaskID:    taskID,
+		Text:      text,
+		UpdatedAt: time.Now(),
+	}
+	s.notes[noteKey(provider, taskID)] = note
+
+	s.logger.Debugf("Saved note for %s in %s", taskID, provider)
+	copied := *note
+	return &copied, s.save()
+}
+
+// Get returns the note of a task
+func (s *TaskNoteStore) Get(provider, taskID string) (*TaskNote, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	note, exists := s.notes[noteKey(provider, taskID)]
+	if !exists {
+		return nil, false
+	}
+	copied := *note
+	return &copied, true
+}
+
+// Clear removes the note of a task
+func (s *TaskNoteStore) Clear(provider, taskID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := noteKey(provider, taskID)
+	if _, exists := s.notes[key]; !exists {
+		return NewProviderError(ErrorTypeNotFound, fmt.Sprintf("no note for task %s in %s", taskID, provider), nil)
+	}
+	delete(s.notes, key)
+
+	s.logger.Debugf("Cleared note for %s in %s", taskID, provider)
+	return s.save()
+}
+
+// List returns the notes of a provider sorted by task ID, or all notes when
+// provider is empty
+func (s *TaskNoteStore) List(provider string) []*TaskNote {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var notes []*TaskNote
+	for _, note := range s.notes {
+		if provider == "" || note.Provider == provider {
+			copied := *note
+			notes = append(notes, &copied)
+		}
+	}
+	sort.Slice(notes, func(i, j int) bool {
+		if notes[i].Provider != notes[j].Provider {
+			return notes[i].Provider < notes[j].Provider
+		}
+		return notes[i].TaskID < notes[j].TaskID
+	})
+	return notes
+}
+
+// save persists notes to disk. Must be called with the lock held.
+func (s *TaskNoteStore) save() error {
+	if s.path == "" {
+		return nil
+	}
+
+	all := make([]*TaskNote, 0, len(s.notes))
+	for _, note := range s.notes {
+		all = append(all, note)
+	}
+	sort.Slice(all, func(i, j int) bool {
+		return noteKey(all[i].Provider, all[i].TaskID) < noteKey(all[j].Provider, all[j].TaskID)
+	})
+
+	data, err := json.MarshalIndent(all, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal notes: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return fmt.Errorf("failed to create notes directory: %w", err)
+	}
+	// Notes are personal, keep them private
+	if err := os.WriteFile(s.path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write notes: %w", err)
+	}
+
+	return nil
+}
+
+// load restores notes from disk
+func (s *TaskNoteStore) load() error {
+	if s.path == "" {
+		return nil
+	}
+
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to read notes: %w", err)
+	}
+
+	var all []*TaskNote
+	if err := json.Unmarshal(data, &all); err != nil {
+		return fmt.Errorf("failed to parse notes: %w", err)
+	}
+
+	for _, note := range all {
+		s.notes[noteKey(note.Provider, note.TaskID)] = note
+	}
+
+	return nil
+}
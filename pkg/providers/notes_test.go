@@ -0,0 +1,61 @@
+package providers
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskNoteStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "notes.json")
+
+	store, err := NewTaskNoteStore(path, nil)
+	require.NoError(t, err)
+
+	t.Run("Set, append and get", func(t *testing.T) {
+		_, err := store.Set("youtrack", "PROJ-1", "  check the migration first ")
+		require.NoError(t, err)
+		_, err = store.Set("jira", "PROJ-1", "same key, other provider")
+		require.NoError(t, err)
+
+		note, err := store.Append("youtrack", "PROJ-1", "ask bob about the rollout")
+		require.NoError(t, err)
+		assert.Equal(t, "check the migration first\nask bob about the rollout", note.Text)
+
+		got, ok := store.Get("youtrack", "PROJ-1")
+		require.True(t, ok)
+		assert.Equal(t, note.Text, got.Text)
+		assert.False(t, got.UpdatedAt.IsZero())
+
+		_, ok = store.Get("youtrack", "PROJ-2")
+		assert.False(t, ok)
+		assert.Len(t, store.List(""), 2)
+		assert.Len(t, store.List("jira"), 1)
+	})
+
+	t.Run("Validation", func(t *testing.T) {
+		_, err := store.Set("youtrack", "PROJ-1", "   ")
+		assert.True(t, IsErrorType(err, ErrorTypeValidation))
+		_, err = store.Set("", "PROJ-1", "text")
+		assert.True(t, IsErrorType(err, ErrorTypeValidation))
+	})
+
+	t.Run("Persistence", func(t *testing.T) {
+		restored, err := NewTaskNoteStore(path, nil)
+		require.NoError(t, err)
+		note, ok := restored.Get("jira", "PROJ-1")
+		require.True(t, ok)
+		assert.Equal(t, "same key, other provider", note.Text)
+	})
+
+	t.Run("Clear", func(t *testing.T) {
+		require.NoError(t, store.Clear("jira", "PROJ-1"))
+		_, ok := store.Get("jira", "PROJ-1")
+		assert.False(t, ok)
+
+		err := store.Clear("jira", "PROJ-1")
+		assert.True(t, IsNotFoundError(err))
+	})
+}
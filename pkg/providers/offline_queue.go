@@ -0,0 +1,342 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OfflineQueueFile is the file name of the offline operation queue in a config directory
+const OfflineQueueFile = "offline_queue.json"
+
+// QueuedOperationType is the kind of a queued task operation
+type QueuedOperationType string
+
+const (
+	QueuedCreate QueuedOperationType = "create"
+	QueuedUpdate QueuedOperationType = "update"
+)
+
+// QueuedOperation is a task create or update stored while its provider is
+// unreachable. The ID is the idempotency key of the operation: it stays the
+// same across replays so that an operation is applied at most once.
+type QueuedOperation struct {
+	ID           string              `json:"id"`
+	Type         QueuedOperationType `json:"type"`
+	Provider     string              `json:"provider"`
+	TaskID       string              `json:"taskId,omitempty"`
+	Task         *UniversalTask      `json:"task,omitempty"`
+	Update       *TaskUpdate         `json:"update,omitempty"`
+	CreateLabels bool                `json:"createLabels,omitempty"` // Create unknown labels instead of rejecting them
+	QueuedAt     time.Time           `json:"queuedAt"`
+	Attempts     int                 `json:"attempts"`
+	LastError    string              `json:"lastError,omitempty"`
+}
+
+// Summary describes the operation in one line
+func (op *QueuedOperation) Summary() string {
+	switch op.Type {
+	case QueuedCreate:
+		if op.Task != nil {
+			return fmt.Sprintf("create %q", op.Task.Title)
+		}
+	case QueuedUpdate:
+		return fmt.Sprintf("update %s", op.TaskID)
+	}
+	return string(op.Type)
+}
+
+// FlushResult is the outcome of replaying one queued operation
+type FlushResult struct {
+	Operation *QueuedOperation `json:"operation"`
+	TaskID    string           `json:"taskId,omitempty"`
+	Duplicate bool             `json:"duplicate,omitempty"` // An earlier replay had already applied the operation
+	Deferred  bool             `json:"deferred,omitempty"`  // Not attempted because the provider is unreachable
+	Error     string           `json:"error,omitempty"`
+}
+
+// OfflineQueue stores task operations in a file until they can be replayed.
+// Every change re-reads the file, so operations queued by concurrent
+// commands are kept.
+type OfflineQueue struct {
+	mu   sync.Mutex
+	path string
+	now  func() time.Time
+}
+
+// NewOfflineQueue creates a queue backed by the given file
+func NewOfflineQueue(path string) *OfflineQueue {
+	return &OfflineQueue{path: path, now: time.Now}
+}
+
+// IsConnectivityError reports whether err means that the provider could not
+// be reached, as opposed to the provider rejecting the request
+func IsConnectivityError(err error) bool {
+	errorType, ok := ClassifyError(err)
+	return ok && errorType == ErrorTypeNetwork
+}
+
+// Enqueue validates an operation, assigns its idempotency key and stores it
+func (q *OfflineQueue) Enqueue(op *QueuedOperation) (*QueuedOperation, error) {
+	if op.Provider == "" {
+		return nil, NewValidationError("queued operation needs a provider", nil)
+	}
+	switch op.Type {
+	case QueuedCreate:
+		if op.Task == nil || op.Task.Title == "" {
+			return nil, NewValidationError("queued create needs a task with a title", nil)
+		}
+	case QueuedUpdate:
+		if op.TaskID == "" || op.Update == nil {
+			return nil, NewValidationError("queued update needs a task ID and changes", nil)
+		}
+	default:
+		return nil, NewValidationError(fmt.Sprintf("unknown operation type %q", op.Type), nil)
+	}
+
+	queued := *op
+	queued.ID = uuid.New().String()
+	queued.QueuedAt = q.now()
+	queued.Attempts = 0
+	queued.LastError = ""
+
+	err := q.modify(func(ops []*QueuedOperation) ([]*QueuedOperation, error) {
+		return append(ops, &queued), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &queued, nil
+}
+
+// List returns the queued operations in the order they will be replayed
+func (q *OfflineQueue) List() ([]*QueuedOperation, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	return q.load()
+}
+
+// Remove drops a queued operation without replaying it
+func (q *OfflineQueue) Remove(id string) error {
+	return q.modify(func(ops []*QueuedOperation) ([]*QueuedOperation, error) {
+		for i, op := range ops {
+			if op.ID == id {
+				return append(ops[:i], ops[i+1:]...), nil
+			}
+		}
+		return nil, NewProviderError(ErrorTypeNotFound, fmt.Sprintf("queued operation %s not found", id), nil)
+	})
+}
+
+// Flush replays queued operations in order, limited to one provider if
+// providerName is set. Applied operations leave the queue; failed ones stay
+// with their error. Once a provider turns out to be unreachable, its remaining
+// operations are deferred so that they keep their order.
+//
+// A create is counted as an attempt before it is sent. If an earlier attempt
+// may have reached the provider, the provider is first searched for a task
+// with the same title created since the operation was queued, so that a lost
+// response doesn't create the task twice.
+func (q *OfflineQueue) Flush(ctx context.Context, providerName string, getProvider func(name string) (TaskProvider, error)) ([]*FlushResult, error) {
+	ops, err := q.List()
+	if err != nil {
+		return nil, err
+	}
+
+	var results []*FlushResult
+	unreachable := make(map[string]bool)
+	for _, op := range ops {
+		if providerName != "" && op.Provider != providerName {
+			continue
+		}
+
+		result := &FlushResult{Operation: op}
+		results = append(results, result)
+		if unreachable[op.Provider] {
+			result.Deferred = true
+			continue
+		}
+
+		provider, err := getProvider(op.Provider)
+		if err != nil {
+			result.Error = err.Error()
+			continue
+		}
+
+		if op.Type == QueuedCreate && op.Attempts > 0 {
+			if existing, err := findReplayedTask(ctx, provider, op); err == nil && existing != nil {
+				result.TaskID = existing.GetDisplayID()
+				result.Duplicate = true
+				if err := q.Remove(op.ID); err != nil {
+					return results, err
+				}
+				continue
+			}
+		}
+
+		op.Attempts++
+		if err := q.save(op); err != nil {
+			return results, err
+		}
+
+		taskID, err := replayOperation(ctx, provider, op)
+		if err != nil {
+			result.Error = err.Error()
+			if IsConnectivityError(err) {
+				unreachable[op.Provider] = true
+			}
+			op.LastError = err.Error()
+			if err := q.save(op); err != nil {
+				return results, err
+			}
+			continue
+		}
+
+		result.TaskID = taskID
+		if err := q.Remove(op.ID); err != nil {
+			return results, err
+		}
+	}
+
+	return results, nil
+}
+
+// replayOperation applies a queued operation and returns the affected task ID
+func replayOperation(ctx context.Context, provider TaskProvider, op *QueuedOperation) (string, error) {
+	switch op.Type {
+	case QueuedCreate:
+		task := *op.Task
+		assignee, err := ResolveAssignee(ctx, provider, task.AssigneeID)
+		if err != nil {
+			return "", err
+		}
+		task.AssigneeID = assignee
+		if task.Labels, err = ValidateLabels(ctx, provider, task.ProjectID, task.Labels, op.CreateLabels); err != nil {
+			return "", err
+		}
+		created, err := provider.CreateTask(ctx, &task)
+		if err != nil {
+			return "", err
+		}
+		return created.GetDisplayID(), nil
+	case QueuedUpdate:
+		update := op.Update
+		if update.AssigneeID != nil && IsCurrentUser(*update.AssigneeID) {
+			assignee, err := ResolveAssignee(ctx, provider, *update.AssigneeID)
+			if err != nil {
+				return "", err
+			}
+			resolved := *update
+			resolved.AssigneeID = &assignee
+			update = &resolved
+		}
+		if update.Labels != nil {
+			labels, err := ValidateLabels(ctx, provider, "", update.Labels, op.CreateLabels)
+			if err != nil {
+				return "", err
+			}
+			resolved := *update
+			resolved.Labels = labels
+			update = &resolved
+		}
+		if err := provider.UpdateTask(ctx, op.TaskID, update); err != nil {
+			return "", err
+		}
+		return op.TaskID, nil
+	default:
+		return "", NewValidationError(fmt.Sprintf("unknown operation type %q", op.Type), nil)
+	}
+}
+
+// findReplayedTask looks for a task created by an earlier attempt of a queued
+// create. Titles are compared here rather than in a provider query, since
+// query syntax differs between providers.
+func findReplayedTask(ctx context.Context, provider TaskProvider, op *QueuedOperation) (*UniversalTask, error) {
+	since := op.QueuedAt
+	tasks, err := provider.ListTasks(ctx, &TaskFilters{
+		ProjectID:    op.Task.ProjectID,
+		CreatedAfter: &since,
+		Limit:        100,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, task := range tasks {
+		if task.Title == op.Task.Title && !task.CreatedAt.Before(since) {
+			return task, nil
+		}
+	}
+	return nil, nil
+}
+
+// save replaces a queued operation with its updated copy
+func (q *OfflineQueue) save(op *QueuedOperation) error {
+	return q.modify(func(ops []*QueuedOperation) ([]*QueuedOperation, error) {
+		for i, queued := range ops {
+			if queued.ID == op.ID {
+				copied := *op
+				ops[i] = &copied
+			}
+		}
+		return ops, nil
+	})
+}
+
+// modify applies change to the operations read from disk and writes the result
+func (q *OfflineQueue) modify(change func([]*QueuedOperation) ([]*QueuedOperation, error)) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	ops, err := q.load()
+	if err != nil {
+		return err
+	}
+	if ops, err = change(ops); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(ops, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal offline queue: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(q.path), 0755); err != nil {
+		return fmt.Errorf("failed to create offline queue directory: %w", err)
+	}
+
+	// Write through a temporary file so that an interrupted write can't lose queued operations
+	tmp := q.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("failed to write offline queue: %w", err)
+	}
+	if err := os.Rename(tmp, q.path); err != nil {
+		return fmt.Errorf("failed to write offline queue: %w", err)
+	}
+	return nil
+}
+
+// load reads the queued operations. Must be called with the lock held.
+func (q *OfflineQueue) load() ([]*QueuedOperation, error) {
+	data, err := os.ReadFile(q.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []*QueuedOperation{}, nil
+		}
+		return nil, fmt.Errorf("failed to read offline queue: %w", err)
+	}
+
+	// Unlike a cache, the queue holds the only copy of the operations: don't discard it
+	var ops []*QueuedOperation
+	if err := json.Unmarshal(data, &ops); err != nil {
+		return nil, fmt.Errorf("failed to parse offline queue %s: %w", q.path, err)
+	}
+	return ops, nil
+}
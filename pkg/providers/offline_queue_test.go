@@ -0,0 +1,162 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// flakyProvider fails with connection errors while down. With lostResponse it
+// creates the task but fails as if the response never arrived.
+type flakyProvider struct {
+	TaskProvider
+	down         bool
+	lostResponse bool
+	tasks        []*UniversalTask
+	updates      map[string]*TaskUpdate
+}
+
+var errConnectionRefused = &url.Error{Op: "Post", URL: "https://yt.example.com", Err: errors.New("connection refused")}
+
+func (p *flakyProvider) CreateTask(ctx context.Context, task *UniversalTask) (*UniversalTask, error) {
+	if p.down {
+		return nil, errConnectionRefused
+	}
+	created := *task
+	created.ID = fmt.Sprintf("OPS-%d", len(p.tasks)+1)
+	created.CreatedAt = time.Now()
+	p.tasks = append(p.tasks, &created)
+	if p.lostResponse {
+		return nil, fmt.Errorf("failed to create task: %w", context.DeadlineExceeded)
+	}
+	return &created, nil
+}
+
+func (p *flakyProvider) UpdateTask(ctx context.Context, id string, updates *TaskUpdate) error {
+	if p.down {
+		return errConnectionRefused
+	}
+	if id == "OPS-404" {
+		return ErrTaskNotFound
+	}
+	if p.updates == nil {
+		p.updates = make(map[string]*TaskUpdate)
+	}
+	p.updates[id] = updates
+	return nil
+}
+
+func (p *flakyProvider) ListTasks(ctx context.Context, filters *TaskFilters) ([]*UniversalTask, error) {
+	if p.down {
+		return nil, errConnectionRefused
+	}
+	return p.tasks, nil
+}
+
+func TestOfflineQueue(t *testing.T) {
+	path := filepath.Join(t.TempDir(), OfflineQueueFile)
+	queue := NewOfflineQueue(path)
+
+	yt := &flakyProvider{down: true}
+	jira := &flakyProvider{}
+	getProvider := func(name string) (TaskProvider, error) {
+		switch name {
+		case "youtrack":
+			return yt, nil
+		case "jira":
+			return jira, nil
+		}
+		return nil, ErrInvalidConfig
+	}
+
+	title := "Rotate certificates"
+	status := TaskStatus{Name: "Done"}
+	create, err := queue.Enqueue(&QueuedOperation{Type: QueuedCreate, Provider: "youtrack", Task: &UniversalTask{Title: title}})
+	require.NoError(t, err)
+	_, err = queue.Enqueue(&QueuedOperation{Type: QueuedUpdate, Provider: "youtrack", TaskID: "OPS-7", Update: &TaskUpdate{Status: &status}})
+	require.NoError(t, err)
+	_, err = queue.Enqueue(&QueuedOperation{Type: QueuedUpdate, Provider: "jira", TaskID: "OPS-404", Update: &TaskUpdate{Status: &status}})
+	require.NoError(t, err)
+
+	t.Run("Enqueue validates and persists", func(t *testing.T) {
+		_, err := queue.Enqueue(&QueuedOperation{Type: QueuedCreate, Provider: "youtrack", Task: &UniversalTask{}})
+		assert.True(t, IsErrorType(err, ErrorTypeValidation))
+
+		ops, err := NewOfflineQueue(path).List()
+		require.NoError(t, err)
+		require.Len(t, ops, 3)
+		assert.Equal(t, create.ID, ops[0].ID)
+		assert.Equal(t, `create "Rotate certificates"`, ops[0].Summary())
+		assert.Equal(t, "update OPS-7", ops[1].Summary())
+	})
+
+	t.Run("Unreachable provider defers the rest of its operations", func(t *testing.T) {
+		results, err := queue.Flush(context.Background(), "", getProvider)
+		require.NoError(t, err)
+		require.Len(t, results, 3)
+
+		assert.Contains(t, results[0].Error, "connection refused")
+		assert.True(t, results[1].Deferred)
+		assert.Contains(t, results[2].Error, "not found")
+
+		ops, err := queue.List()
+		require.NoError(t, err)
+		require.Len(t, ops, 3)
+		assert.Equal(t, 1, ops[0].Attempts)
+		assert.Equal(t, 0, ops[1].Attempts)
+		assert.Contains(t, ops[0].LastError, "connection refused")
+	})
+
+	t.Run("A lost response doesn't create the task twice", func(t *testing.T) {
+		yt.down = false
+		yt.lostResponse = true
+		results, err := queue.Flush(context.Background(), "youtrack", getProvider)
+		require.NoError(t, err)
+		require.Len(t, results, 2)
+		assert.NotEmpty(t, results[0].Error)
+		assert.True(t, results[1].Deferred)
+		require.Len(t, yt.tasks, 1)
+
+		yt.lostResponse = false
+		results, err = queue.Flush(context.Background(), "youtrack", getProvider)
+		require.NoError(t, err)
+		require.Len(t, results, 2)
+		assert.True(t, results[0].Duplicate)
+		assert.Equal(t, "OPS-1", results[0].TaskID)
+		assert.Equal(t, "OPS-7", results[1].TaskID)
+		assert.Len(t, yt.tasks, 1)
+		assert.Equal(t, "Done", yt.updates["OPS-7"].Status.Name)
+
+		ops, err := queue.List()
+		require.NoError(t, err)
+		require.Len(t, ops, 1)
+		assert.Equal(t, "jira", ops[0].Provider)
+	})
+
+	t.Run("Remove", func(t *testing.T) {
+		ops, err := queue.List()
+		require.NoError(t, err)
+		require.NoError(t, queue.Remove(ops[0].ID))
+		assert.True(t, IsNotFoundError(queue.Remove(ops[0].ID)))
+
+		ops, err = queue.List()
+		require.NoError(t, err)
+		assert.Empty(t, ops)
+	})
+}
+
+func TestIsConnectivityError(t *testing.T) {
+	assert.True(t, IsConnectivityError(fmt.Errorf("request failed: %w", errConnectionRefused)))
+	assert.True(t, IsConnectivityError(context.DeadlineExceeded))
+	assert.True(t, IsConnectivityError(&statusError{503}))
+	assert.False(t, IsConnectivityError(&statusError{500}))
+	assert.False(t, IsConnectivityError(NewValidationError("title is required", nil)))
+	assert.False(t, IsConnectivityError(errors.New("boom")))
+}
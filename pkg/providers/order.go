@@ -0,0 +1,192 @@
+package providers
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ExecutionGroup is a set of tasks whose dependencies are all satisfied by
+// earlier groups, so they can be worked on in parallel
+type ExecutionGroup struct {
+	Step  int              `json:"step"`
+	Tasks []*UniversalTask `json:"tasks"`
+}
+
+// ExecutionPlan is a dependency-respecting order of open tasks
+type ExecutionPlan struct {
+	Groups []ExecutionGroup `json:"groups"`
+	// ExternalBlockers maps tasks to blockers outside the planned set,
+	// which the plan cannot order
+	ExternalBlockers map[string][]string `json:"externalBlockers,omitempty"`
+}
+
+// Order returns the tasks of all groups in recommended sequence
+func (p *ExecutionPlan) Order() []*UniversalTask {
+	var order []*UniversalTask
+	for _, group := range p.Groups {
+		order = append(order, group.Tasks...)
+	}
+	return order
+}
+
+// DependencyCycleError reports tasks that block each other
+type DependencyCycleError struct {
+	Cycle []string // display IDs; the first task is repeated at the end
+}
+
+func (e *DependencyCycleError) Error() string {
+	return fmt.Sprintf("dependency cycle: %s", strings.Join(e.Cycle, " → "))
+}
+
+// priorityRank orders priorities from the most to the least urgent
+var priorityRank = map[TaskPriority]int{
+	TaskPriorityCritical: 0,
+	TaskPriorityHighest:  1,
+	TaskPriorityHigh:     2,
+	TaskPriorityMedium:   3,
+	TaskPriorityLow:      4,
+	TaskPriorityLowest:   5,
+}
+
+func rankOf(priority TaskPriority) int {
+	if rank, ok := priorityRank[priority]; ok {
+		return rank
+	}
+	return priorityRank[TaskPriorityMedium]
+}
+
+// PlanExecutionOrder sorts open tasks topologically by their BlockedBy/Blocks
+// relations. Tasks in the same group have no dependencies on each other and
+// are ordered by priority. Completed tasks count as satisfied dependencies and
+// are left out of the plan. A dependency cycle yields a *DependencyCycleError.
+func PlanExecutionOrder(tasks []*UniversalTask) (*ExecutionPlan, error) {
+	// Relations may reference a task by ID, key or external ID
+	index := make(map[string]*UniversalTask)
+	var open []*UniversalTask
+	for _, task := range tasks {
+		if task == nil {
+			continue
+		}
+		for _, id := range []string{task.ID, task.Key, task.ExternalID} {
+			if id != "" {
+				index[id] = task
+			}
+		}
+		if !task.IsCompleted() {
+			open = append(open, task)
+		}
+	}
+
+	plan := &ExecutionPlan{}
+	blockers := make(map[*UniversalTask]map[*UniversalTask]bool, len(open))
+	dependents := make(map[*UniversalTask][]*UniversalTask, len(open))
+	for _, task := range open {
+		blockers[task] = make(map[*UniversalTask]bool)
+	}
+
+	addEdge := func(blocker, blocked *UniversalTask) {
+		if blocker == blocked || blocker.IsCompleted() || blocked.IsCompleted() || blockers[blocked][blocker] {
+			return
+		}
+		blockers[blocked][blocker] = true
+		dependents[blocker] = append(dependents[blocker], blocked)
+	}
+
+	for _, task := range open {
+		for _, id := range task.BlockedBy {
+			blocker, ok := index[id]
+			if !ok {
+				if plan.ExternalBlockers == nil {
+					plan.ExternalBlockers = make(map[string][]string)
+				}
+				plan.ExternalBlockers[task.GetDisplayID()] = append(plan.ExternalBlockers[task.GetDisplayID()], id)
+				continue
+			}
+			addEdge(blocker, task)
+		}
+		for _, id := range task.Blocks {
+			if blocked, ok := index[id]; ok {
+				addEdge(task, blocked)
+			}
+		}
+	}
+
+	// Kahn's algorithm, one level at a time
+	remaining := make(map[*UniversalTask]int, len(open))
+	var ready []*UniversalTask
+	for _, task := range open {
+		remaining[task] = len(blockers[task])
+		if remaining[task] == 0 {
+			ready = append(ready, task)
+		}
+	}
+
+	planned := 0
+	for len(ready) > 0 {
+		sortByPriority(ready)
+		plan.Groups = append(plan.Groups, ExecutionGroup{Step: len(plan.Groups) + 1, Tasks: ready})
+		planned += len(ready)
+
+		var next []*UniversalTask
+		for _, task := range ready {
+			for _, dependent := range dependents[task] {
+				remaining[dependent]--
+				if remaining[dependent] == 0 {
+					next = append(next, dependent)
+				}
+			}
+		}
+		ready = next
+	}
+
+	if planned < len(open) {
+		return nil, &DependencyCycleError{Cycle: findCycle(open, remaining, blockers)}
+	}
+	return plan, nil
+}
+
+func sortByPriority(tasks []*UniversalTask) {
+	sort.SliceStable(tasks, func(i, j int) bool {
+		if rankOf(tasks[i].Priority) != rankOf(tasks[j].Priority) {
+			return rankOf(tasks[i].Priority) < rankOf(tasks[j].Priority)
+		}
+		return tasks[i].GetDisplayID() < tasks[j].GetDisplayID()
+	})
+}
+
+// findCycle walks blockers from a task left unplanned by the topological sort.
+// Every such task has an unplanned blocker, so the walk must revisit a task.
+func findCycle(open []*UniversalTask, remaining map[*UniversalTask]int, blockers map[*UniversalTask]map[*UniversalTask]bool) []string {
+	var stuck []*UniversalTask
+	for _, task := range open {
+		if remaining[task] > 0 {
+			stuck = append(stuck, task)
+		}
+	}
+	sortByPriority(stuck)
+
+	position := make(map[*UniversalTask]int)
+	var path []*UniversalTask
+	for current := stuck[0]; ; {
+		if start, seen := position[current]; seen {
+			cycle := make([]string, 0, len(path)-start+1)
+			// Walking blockers goes against the dependency direction, so reverse it
+			for i := len(path) - 1; i >= start; i-- {
+				cycle = append(cycle, path[i].GetDisplayID())
+			}
+			return append(cycle, cycle[0])
+		}
+		position[current] = len(path)
+		path = append(path, current)
+
+		var candidates []*UniversalTask
+		for blocker := range blockers[current] {
+			if remaining[blocker] > 0 {
+				candidates = append(candidates, blocker)
+			}
+		}
+		sortByPriority(candidates)
+		current = candidates[0]
+	}
+}
@@ -0,0 +1,81 @@
+package providers
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func groupKeys(plan *ExecutionPlan) [][]string {
+	var groups [][]string
+	for _, group := range plan.Groups {
+		var keys []string
+		for _, task := range group.Tasks {
+			keys = append(keys, task.GetDisplayID())
+		}
+		groups = append(groups, keys)
+	}
+	return groups
+}
+
+func TestPlanExecutionOrder(t *testing.T) {
+	open := TaskStatus{Name: "Open", Category: StatusCategoryTodo}
+	done := TaskStatus{Name: "Done", Category: StatusCategoryDone}
+
+	t.Run("Groups by dependency depth", func(t *testing.T) {
+		tasks := []*UniversalTask{
+			{ID: "4", Key: "P-4", Status: open, BlockedBy: []string{"P-2", "P-3"}},
+			{ID: "2", Key: "P-2", Status: open, BlockedBy: []string{"P-1"}},
+			{ID: "3", Key: "P-3", Status: open, Priority: TaskPriorityHigh},
+			{ID: "1", Key: "P-1", Status: open, Priority: TaskPriorityLow, Blocks: []string{"3"}},
+			{ID: "5", Key: "P-5", Status: open, Priority: TaskPriorityCritical},
+		}
+
+		plan, err := PlanExecutionOrder(tasks)
+		require.NoError(t, err)
+
+		assert.Equal(t, [][]string{{"P-5", "P-1"}, {"P-3", "P-2"}, {"P-4"}}, groupKeys(plan))
+		assert.Len(t, plan.Order(), 5)
+		assert.Equal(t, 2, plan.Groups[1].Step)
+	})
+
+	t.Run("Completed and external blockers", func(t *testing.T) {
+		tasks := []*UniversalTask{
+			{Key: "P-1", Status: done},
+			{Key: "P-2", Status: open, BlockedBy: []string{"P-1"}},
+			{Key: "P-3", Status: open, BlockedBy: []string{"OTHER-9"}},
+		}
+
+		plan, err := PlanExecutionOrder(tasks)
+		require.NoError(t, err)
+
+		assert.Equal(t, [][]string{{"P-2", "P-3"}}, groupKeys(plan))
+		assert.Equal(t, map[string][]string{"P-3": {"OTHER-9"}}, plan.ExternalBlockers)
+	})
+
+	t.Run("Reports the cycle", func(t *testing.T) {
+		tasks := []*UniversalTask{
+			{Key: "P-1", Status: open},
+			{Key: "P-2", Status: open, BlockedBy: []string{"P-1", "P-4"}},
+			{Key: "P-3", Status: open, BlockedBy: []string{"P-2"}},
+			{Key: "P-4", Status: open, BlockedBy: []string{"P-3"}},
+			{Key: "P-5", Status: open, BlockedBy: []string{"P-4"}},
+		}
+
+		_, err := PlanExecutionOrder(tasks)
+		require.Error(t, err)
+
+		var cycleErr *DependencyCycleError
+		require.True(t, errors.As(err, &cycleErr))
+		assert.Equal(t, []string{"P-3", "P-4", "P-2", "P-3"}, cycleErr.Cycle)
+		assert.Equal(t, "dependency cycle: P-3 → P-4 → P-2 → P-3", err.Error())
+	})
+
+	t.Run("Self reference is ignored", func(t *testing.T) {
+		plan, err := PlanExecutionOrder([]*UniversalTask{{Key: "P-1", Status: open, BlockedBy: []string{"P-1"}}})
+		require.NoError(t, err)
+		assert.Equal(t, [][]string{{"P-1"}}, groupKeys(plan))
+	})
+}
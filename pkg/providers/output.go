@@ -0,0 +1,55 @@
+package providers
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// OutputFormatEnv overrides the configured default output format
+const OutputFormatEnv = "RICOCHET_OUTPUT"
+
+// Output formats supported by CLI commands
+const (
+	OutputFormatTable = "table"
+	OutputFormatJSON  = "json"
+	OutputFormatYAML  = "yaml"
+)
+
+// ValidateOutputFormat checks that format is a supported output format
+func ValidateOutputFormat(format string) error {
+	switch format {
+	case OutputFormatTable, OutputFormatJSON, OutputFormatYAML:
+		return nil
+	}
+	return fmt.Errorf("unknown output format %q (expected %s, %s or %s)", format, OutputFormatTable, OutputFormatJSON, OutputFormatYAML)
+}
+
+// ResolveOutputFormat picks the output format of a command. An explicit flag
+// wins over $RICOCHET_OUTPUT, which wins over the configured default.
+func ResolveOutputFormat(flag string, flagSet bool, configured string) (string, error) {
+	if flagSet {
+		return flag, nil
+	}
+
+	if env := strings.TrimSpace(os.Getenv(OutputFormatEnv)); env != "" {
+		format := strings.ToLower(env)
+		if err := ValidateOutputFormat(format); err != nil {
+			return OutputFormatTable, fmt.Errorf("invalid $%s: %w", OutputFormatEnv, err)
+		}
+		return format, nil
+	}
+
+	if configured != "" {
+		format := strings.ToLower(configured)
+		if err := ValidateOutputFormat(format); err != nil {
+			return OutputFormatTable, fmt.Errorf("invalid defaultOutputFormat: %w", err)
+		}
+		return format, nil
+	}
+
+	if flag != "" {
+		return flag, nil
+	}
+	return OutputFormatTable, nil
+}
@@ -0,0 +1,44 @@
+package providers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveOutputFormat(t *testing.T) {
+	t.Run("Flag default without preferences", func(t *testing.T) {
+		t.Setenv(OutputFormatEnv, "")
+		format, err := ResolveOutputFormat("table", false, "")
+		assert.NoError(t, err)
+		assert.Equal(t, "table", format)
+	})
+
+	t.Run("Config default", func(t *testing.T) {
+		t.Setenv(OutputFormatEnv, "")
+		format, err := ResolveOutputFormat("table", false, "JSON")
+		assert.NoError(t, err)
+		assert.Equal(t, "json", format)
+	})
+
+	t.Run("Environment wins over config", func(t *testing.T) {
+		t.Setenv(OutputFormatEnv, "yaml")
+		format, err := ResolveOutputFormat("table", false, "json")
+		assert.NoError(t, err)
+		assert.Equal(t, "yaml", format)
+	})
+
+	t.Run("Explicit flag wins", func(t *testing.T) {
+		t.Setenv(OutputFormatEnv, "yaml")
+		format, err := ResolveOutputFormat("table", true, "json")
+		assert.NoError(t, err)
+		assert.Equal(t, "table", format)
+	})
+
+	t.Run("Invalid preference falls back to table", func(t *testing.T) {
+		t.Setenv(OutputFormatEnv, "")
+		format, err := ResolveOutputFormat("table", false, "xml")
+		assert.Error(t, err)
+		assert.Equal(t, "table", format)
+	})
+}
@@ -0,0 +1,139 @@
+package providers
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// TaskPage is one page of tasks listed from several providers
+type TaskPage struct {
+	Tasks      []*UniversalTask
+	Failures   []ProviderFailure
+	HasMore    bool
+	NextCursor string // Passed to FetchTaskPage to get the next page, empty on the last one
+}
+
+// pageCursor is the position of a page: the provider it starts at and the
+// number of that provider's tasks already listed
+type pageCursor struct {
+	Provider string `json:"p"`
+	Offset   int    `json:"o"`
+}
+
+// encodePageCursor turns a position into the opaque cursor given to clients
+func encodePageCursor(cursor pageCursor) string {
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodePageCursor(value string) (pageCursor, error) {
+	var cursor pageCursor
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err == nil {
+		err = json.Unmarshal(data, &cursor)
+	}
+	if err != nil || cursor.Provider == "" || cursor.Offset < 0 {
+		return cursor, NewValidationError(fmt.Sprintf("invalid cursor %q, pass the next_cursor of the previous page", value), nil)
+	}
+	return cursor, nil
+}
+
+// FetchTaskPage lists up to limit tasks from the target providers, walking
+// them one after another in name order so that pages never overlap. The
+// first page starts at offset within the first provider, later pages at the
+// cursor returned with the previous one. Providers that fail are reported in
+// Failures and skipped. Label filters apply after a provider answers and a
+// full page does not know whether the next provider has tasks, so a page may
+// hold fewer tasks than limit, and the last page may be empty.
+func FetchTaskPage(ctx context.Context, targets []string, lookup ProviderLookup, filters *TaskFilters, limit, offset int, cursor string) (*TaskPage, error) {
+	if limit <= 0 {
+		return nil, NewValidationError("limit must be positive", nil)
+	}
+	if offset < 0 {
+		return nil, NewValidationError("offset must not be negative", nil)
+	}
+	if offset > 0 && cursor != "" {
+		return nil, NewValidationError("offset and cursor cannot be combined, the cursor already holds the position", nil)
+	}
+
+	if filters == nil {
+		filters = &TaskFilters{}
+	}
+
+	names := append([]string(nil), targets...)
+	sort.Strings(names)
+
+	position := pageCursor{Offset: offset}
+	if len(names) > 0 {
+		position.Provider = names[0]
+	}
+	if cursor != "" {
+		var err error
+		if position, err = decodePageCursor(cursor); err != nil {
+			return nil, err
+		}
+	}
+
+	start := sort.SearchStrings(names, position.Provider)
+	if position.Provider != "" && (start == len(names) || names[start] != position.Provider) {
+		return nil, NewValidationError(fmt.Sprintf("cursor points at provider %s, which is not listed; keep the providers of the first page", position.Provider), nil)
+	}
+
+	page := &TaskPage{Tasks: []*UniversalTask{}}
+	remaining := limit
+	for i := start; i < len(names); i++ {
+		name := names[i]
+		skip := 0
+		if i == start {
+			skip = position.Offset
+		}
+
+		if remaining == 0 {
+			page.HasMore = true
+			page.NextCursor = encodePageCursor(pageCursor{Provider: name, Offset: skip})
+			break
+		}
+		if err := ctx.Err(); err != nil {
+			page.Failures = append(page.Failures, ProviderFailure{Provider: name, Err: err})
+			continue
+		}
+
+		// One task more than needed tells whether the provider has another page
+		providerFilters := *filters.ForProvider(name)
+		providerFilters.Limit = remaining + 1
+		providerFilters.Offset = skip
+		provider, err := lookup(name)
+		var tasks []*UniversalTask
+		if err == nil {
+			var resolved *TaskFilters
+			if resolved, err = ResolveAssigneeFilter(ctx, provider, &providerFilters); err == nil {
+				tasks, err = provider.ListTasks(ctx, resolved)
+			}
+		}
+		if err != nil {
+			page.Failures = append(page.Failures, ProviderFailure{Provider: name, Err: err})
+			continue
+		}
+
+		more := len(tasks) > remaining
+		if more {
+			tasks = tasks[:remaining]
+		}
+		for _, task := range tasks {
+			task.ProviderName = name
+		}
+		page.Tasks = append(page.Tasks, filters.FilterByDueDate(filters.FilterByLabels(tasks))...)
+		remaining -= len(tasks)
+
+		if more {
+			page.HasMore = true
+			page.NextCursor = encodePageCursor(pageCursor{Provider: name, Offset: skip + len(tasks)})
+			break
+		}
+	}
+
+	return page, nil
+}
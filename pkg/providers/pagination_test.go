@@ -0,0 +1,86 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// pagedProvider lists its tasks honouring the limit and offset of the filters
+type pagedProvider struct {
+	TaskProvider
+	keys []string
+}
+
+func (p *pagedProvider) ListTasks(ctx context.Context, filters *TaskFilters) ([]*UniversalTask, error) {
+	var tasks []*UniversalTask
+	for i := filters.Offset; i < len(p.keys) && len(tasks) < filters.Limit; i++ {
+		tasks = append(tasks, &UniversalTask{Key: p.keys[i]})
+	}
+	return tasks, nil
+}
+
+func pageKeys(page *TaskPage) []string {
+	var keys []string
+	for _, task := range page.Tasks {
+		keys = append(keys, task.ProviderName+"/"+task.Key)
+	}
+	return keys
+}
+
+func TestFetchTaskPage(t *testing.T) {
+	lookup := providerLookup(map[string]TaskProvider{
+		"youtrack": &pagedProvider{keys: []string{"YT-1", "YT-2", "YT-3"}},
+		"jira":     &pagedProvider{keys: []string{"JR-1", "JR-2"}},
+		"notion":   &slowListProvider{err: errors.New("unauthorized")},
+	})
+	targets := []string{"youtrack", "notion", "jira"}
+
+	t.Run("Pages walk the providers in order", func(t *testing.T) {
+		var pages [][]string
+		cursor := ""
+		for {
+			page, err := FetchTaskPage(context.Background(), targets, lookup, &TaskFilters{}, 2, 0, cursor)
+			require.NoError(t, err)
+			pages = append(pages, pageKeys(page))
+			if !page.HasMore {
+				assert.Empty(t, page.NextCursor)
+				break
+			}
+			require.NotEmpty(t, page.NextCursor)
+			cursor = page.NextCursor
+		}
+
+		assert.Equal(t, [][]string{
+			{"jira/JR-1", "jira/JR-2"},
+			{"youtrack/YT-1", "youtrack/YT-2"},
+			{"youtrack/YT-3"},
+		}, pages)
+	})
+
+	t.Run("Failed providers are skipped", func(t *testing.T) {
+		page, err := FetchTaskPage(context.Background(), targets, lookup, &TaskFilters{}, 3, 1, "")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"jira/JR-2", "youtrack/YT-1", "youtrack/YT-2"}, pageKeys(page))
+		require.Len(t, page.Failures, 1)
+		assert.Equal(t, "notion", page.Failures[0].Provider)
+		assert.True(t, page.HasMore)
+	})
+
+	t.Run("Invalid positions", func(t *testing.T) {
+		_, err := FetchTaskPage(context.Background(), targets, lookup, &TaskFilters{}, 2, 0, "garbage")
+		assert.True(t, IsErrorType(err, ErrorTypeValidation))
+		assert.Contains(t, err.Error(), "invalid cursor")
+
+		page, err := FetchTaskPage(context.Background(), targets, lookup, &TaskFilters{}, 2, 0, "")
+		require.NoError(t, err)
+		_, err = FetchTaskPage(context.Background(), []string{"youtrack"}, lookup, &TaskFilters{}, 2, 0, page.NextCursor)
+		assert.Contains(t, err.Error(), "not listed")
+
+		_, err = FetchTaskPage(context.Background(), targets, lookup, &TaskFilters{}, 2, 1, page.NextCursor)
+		assert.Contains(t, err.Error(), "cannot be combined")
+	})
+}
@@ -0,0 +1,138 @@
+package providers
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// PriorityMapping maps universal priority levels to the native priority values
+// of a provider, e.g. critical: Blocker. Providers with fewer levels map
+// several universal levels to the same value.
+type PriorityMapping map[TaskPriority]string
+
+// DefaultPriorityMappings are the built-in priority schemes of provider types
+var DefaultPriorityMappings = map[ProviderType]PriorityMapping{
+	ProviderTypeYouTrack: {
+		TaskPriorityCritical: "Show-stopper",
+		TaskPriorityHighest:  "Critical",
+		TaskPriorityHigh:     "Major",
+		TaskPriorityMedium:   "Normal",
+		TaskPriorityLow:      "Minor",
+		TaskPriorityLowest:   "Cosmetic",
+	},
+	ProviderTypeJira: {
+		TaskPriorityCritical: "Highest",
+		TaskPriorityHighest:  "Highest",
+		TaskPriorityHigh:     "High",
+		TaskPriorityMedium:   "Medium",
+		TaskPriorityLow:      "Low",
+		TaskPriorityLowest:   "Lowest",
+	},
+	// Linear priorities are numbers, 0 means no priority
+	ProviderTypeLinear: {
+		TaskPriorityCritical: "1",
+		TaskPriorityHighest:  "1",
+		TaskPriorityHigh:     "2",
+		TaskPriorityMedium:   "3",
+		TaskPriorityLow:      "4",
+		TaskPriorityLowest:   "4",
+	},
+}
+
+// genericPriorityMapping is used for provider types without a built-in scheme
+var genericPriorityMapping = PriorityMapping{
+	TaskPriorityCritical: "Critical",
+	TaskPriorityHighest:  "Highest",
+	TaskPriorityHigh:     "High",
+	TaskPriorityMedium:   "Medium",
+	TaskPriorityLow:      "Low",
+	TaskPriorityLowest:   "Lowest",
+}
+
+// PriorityMappingFor returns the priority mapping of a provider: the built-in
+// scheme of its type with the configured entries taking precedence
+func PriorityMappingFor(providerType ProviderType, configured PriorityMapping) PriorityMapping {
+	defaults, ok := DefaultPriorityMappings[providerType]
+	if !ok {
+		defaults = genericPriorityMapping
+	}
+	mapping := make(PriorityMapping, len(defaults)+len(configured))
+	for priority, value := range defaults {
+		mapping[priority] = value
+	}
+	for priority, value := range configured {
+		mapping[priority] = value
+	}
+	return mapping
+}
+
+// Native returns the provider value of a universal priority. A level without
+// a value of its own gets the value of the nearest mapped level, the more
+// urgent one on a tie, so no priority is silently dropped.
+func (m PriorityMapping) Native(priority TaskPriority) (string, bool) {
+	if value, ok := m[priority]; ok {
+		return value, true
+	}
+	if !priority.IsValid() || len(m) == 0 {
+		return "", false
+	}
+
+	rank := rankOf(priority)
+	best, bestDistance := "", len(priorityRank)
+	for mapped, value := range m {
+		distance := rankOf(mapped) - rank
+		if distance < 0 {
+			distance = -distance
+		}
+		if distance < bestDistance || (distance == bestDistance && rankOf(mapped) < rank) {
+			best, bestDistance = value, distance
+		}
+	}
+	return best, true
+}
+
+// Universal returns the universal priority of a provider value, compared
+// case-insensitively. A value several levels share reads back as the level
+// nearest to medium, e.g. Jira's Highest as highest rather than critical.
+func (m PriorityMapping) Universal(value string) (TaskPriority, bool) {
+	value = strings.TrimSpace(value)
+	var found TaskPriority
+	for priority, native := range m {
+		if !strings.EqualFold(strings.TrimSpace(native), value) {
+			continue
+		}
+		if found == "" || distanceFromMedium(priority) < distanceFromMedium(found) ||
+			(distanceFromMedium(priority) == distanceFromMedium(found) && rankOf(priority) < rankOf(found)) {
+			found = priority
+		}
+	}
+	return found, found != ""
+}
+
+func distanceFromMedium(priority TaskPriority) int {
+	distance := rankOf(priority) - rankOf(TaskPriorityMedium)
+	if distance < 0 {
+		return -distance
+	}
+	return distance
+}
+
+// Validate checks that only known priorities are mapped, each to a value
+func (m PriorityMapping) Validate() error {
+	priorities := make([]string, 0, len(m))
+	for priority := range m {
+		priorities = append(priorities, string(priority))
+	}
+	sort.Strings(priorities)
+
+	for _, priority := range priorities {
+		if !TaskPriority(priority).IsValid() {
+			return NewValidationError(fmt.Sprintf("priority mapping contains unknown priority %q, use lowest, low, medium, high, highest or critical", priority), nil)
+		}
+		if strings.TrimSpace(m[TaskPriority(priority)]) == "" {
+			return NewValidationError(fmt.Sprintf("priority %q is mapped to an empty value", priority), nil)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package providers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPriorityMapping(t *testing.T) {
+	t.Run("Built-in schemes", func(t *testing.T) {
+		jira := PriorityMappingFor(ProviderTypeJira, nil)
+		value, ok := jira.Native(TaskPriorityCritical)
+		assert.True(t, ok)
+		assert.Equal(t, "Highest", value)
+
+		// A value several levels share reads back as the level nearest to medium
+		priority, ok := jira.Universal("highest")
+		assert.True(t, ok)
+		assert.Equal(t, TaskPriorityHighest, priority)
+		priority, _ = PriorityMappingFor(ProviderTypeLinear, nil).Universal("4")
+		assert.Equal(t, TaskPriorityLow, priority)
+
+		value, _ = PriorityMappingFor(ProviderTypeCustom, nil).Native(TaskPriorityLow)
+		assert.Equal(t, "Low", value)
+	})
+
+	t.Run("Configured values take precedence", func(t *testing.T) {
+		mapping := PriorityMappingFor(ProviderTypeJira, PriorityMapping{TaskPriorityCritical: "Blocker"})
+		value, _ := mapping.Native(TaskPriorityCritical)
+		assert.Equal(t, "Blocker", value)
+		value, _ = mapping.Native(TaskPriorityLow)
+		assert.Equal(t, "Low", value)
+		priority, _ := mapping.Universal("Blocker")
+		assert.Equal(t, TaskPriorityCritical, priority)
+	})
+
+	t.Run("Unmapped levels use the nearest mapped level", func(t *testing.T) {
+		mapping := PriorityMapping{TaskPriorityHigh: "P1", TaskPriorityMedium: "P2", TaskPriorityLowest: "P4"}
+		for priority, expected := range map[TaskPriority]string{
+			TaskPriorityCritical: "P1",
+			TaskPriorityHighest:  "P1",
+			TaskPriorityLow:      "P2", // Tie between P2 and P4, the more urgent wins
+		} {
+			value, ok := mapping.Native(priority)
+			assert.True(t, ok)
+			assert.Equal(t, expected, value, priority)
+		}
+
+		_, ok := mapping.Native("urgent")
+		assert.False(t, ok)
+		_, ok = mapping.Universal("P3")
+		assert.False(t, ok)
+	})
+
+	t.Run("Validate", func(t *testing.T) {
+		assert.NoError(t, PriorityMapping{TaskPriorityCritical: "Blocker"}.Validate())
+
+		err := PriorityMapping{"urgent": "P0"}.Validate()
+		assert.True(t, IsErrorType(err, ErrorTypeValidation))
+		assert.Contains(t, err.Error(), `unknown priority "urgent"`)
+
+		assert.Contains(t, PriorityMapping{TaskPriorityLow: " "}.Validate().Error(), "empty value")
+	})
+}
@@ -0,0 +1,58 @@
+package providers
+
+// ProgressSource tells what a task's progress was computed from
+type ProgressSource string
+
+const (
+	ProgressFromSubtasks ProgressSource = "subtasks" // share of completed descendants
+	ProgressFromStatus   ProgressSource = "status"   // estimated from the status category
+)
+
+// statusProgress estimates how far along a task without subtasks is from
+// the category of its status
+var statusProgress = map[StatusCategory]int{
+	StatusCategoryTodo:       0,
+	StatusCategoryBlocked:    0,
+	StatusCategoryInProgress: 50,
+	StatusCategoryReview:     75,
+	StatusCategoryTesting:    75,
+	StatusCategoryDone:       100,
+	StatusCategoryCancelled:  100,
+}
+
+// TaskProgress returns the completion percentage of a task: the share of its
+// completed descendants when it has any, an estimate from its status
+// otherwise
+func TaskProgress(task *UniversalTask, descendants []*UniversalTask) (int, ProgressSource) {
+	if len(descendants) > 0 {
+		rollup := ComputeRollup(task, descendants)
+		return rollup.Completed * 100 / rollup.Total, ProgressFromSubtasks
+	}
+	if task.IsCompleted() {
+		return 100, ProgressFromStatus
+	}
+	return statusProgress[task.Status.Category], ProgressFromStatus
+}
+
+// ProgressStatus picks the status a task should move to given the progress
+// of its subtasks: in progress once some are done while the task is still to
+// do, done once all of them are. Completed tasks are never reopened. It
+// returns false when the task's status already fits.
+func ProgressStatus(task *UniversalTask, statuses []TaskStatus, progress int) (TaskStatus, bool) {
+	if task.IsCompleted() {
+		return TaskStatus{}, false
+	}
+
+	var status TaskStatus
+	var ok bool
+	switch {
+	case progress >= 100:
+		status, ok = FinalStatus(statuses)
+	case progress > 0 && (task.Status.Category == StatusCategoryTodo || task.Status.Category == ""):
+		status, ok = InProgressStatus(statuses)
+	}
+	if !ok || SameStatus(task.Status, status) {
+		return TaskStatus{}, false
+	}
+	return status, true
+}
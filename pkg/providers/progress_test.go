@@ -0,0 +1,57 @@
+package providers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTaskProgress(t *testing.T) {
+	open := TaskStatus{Name: "Open", Category: StatusCategoryTodo}
+	done := TaskStatus{Name: "Done", Category: StatusCategoryDone}
+
+	progress, source := TaskProgress(&UniversalTask{Status: open}, []*UniversalTask{
+		{Status: done}, {Status: open}, {Status: done}, {Status: open},
+	})
+	assert.Equal(t, 50, progress)
+	assert.Equal(t, ProgressFromSubtasks, source)
+
+	progress, source = TaskProgress(&UniversalTask{Status: TaskStatus{Name: "Review", Category: StatusCategoryReview}}, nil)
+	assert.Equal(t, 75, progress)
+	assert.Equal(t, ProgressFromStatus, source)
+
+	progress, _ = TaskProgress(&UniversalTask{Status: TaskStatus{Name: "Closed", IsFinal: true}}, nil)
+	assert.Equal(t, 100, progress)
+}
+
+func TestProgressStatus(t *testing.T) {
+	workflow := []TaskStatus{
+		{ID: "open", Name: "Open", Category: StatusCategoryTodo, Order: 1},
+		{ID: "in_progress", Name: "In Progress", Category: StatusCategoryInProgress, Order: 2},
+		{ID: "resolved", Name: "Resolved", Category: StatusCategoryDone, IsFinal: true, Order: 3},
+	}
+	open := &UniversalTask{Status: workflow[0]}
+	started := &UniversalTask{Status: workflow[1]}
+
+	status, ok := ProgressStatus(open, workflow, 40)
+	assert.True(t, ok)
+	assert.Equal(t, "In Progress", status.Name)
+
+	status, ok = ProgressStatus(started, workflow, 100)
+	assert.True(t, ok)
+	assert.Equal(t, "Resolved", status.Name)
+
+	_, ok = ProgressStatus(open, workflow, 0)
+	assert.False(t, ok, "nothing done yet")
+	_, ok = ProgressStatus(started, workflow, 40)
+	assert.False(t, ok, "already in progress")
+	_, ok = ProgressStatus(&UniversalTask{Status: workflow[2]}, workflow, 40)
+	assert.False(t, ok, "completed tasks are not reopened")
+}
+
+func TestIsAIManaged(t *testing.T) {
+	assert.False(t, (&UniversalTask{}).IsAIManaged())
+	assert.False(t, (&UniversalTask{RicochetMetadata: &RicochetTaskMetadata{SyncStatus: SyncStatusSynced}}).IsAIManaged())
+	assert.True(t, (&UniversalTask{RicochetMetadata: &RicochetTaskMetadata{ChainID: "chain-1"}}).IsAIManaged())
+	assert.True(t, (&UniversalTask{RicochetMetadata: &RicochetTaskMetadata{AIExecutionState: AIExecutionStateRunning}}).IsAIManaged())
+}
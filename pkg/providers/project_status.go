@@ -0,0 +1,121 @@
+package providers
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// statusDigestItems bounds how many tasks of each group a status digest lists
+const statusDigestItems = 15
+
+// ProjectStatus aggregates the tasks of a project for a status update
+type ProjectStatus struct {
+	ProjectID  string           `json:"projectId"`
+	Since      time.Time        `json:"since"` // Start of the reporting period
+	Stats      *TaskStats       `json:"stats"`
+	Completed  []*UniversalTask `json:"completed"` // Completed during the period
+	InProgress []*UniversalTask `json:"inProgress"`
+	Blocked    []*UniversalTask `json:"blocked"`
+	Overdue    []*UniversalTask `json:"overdue"`
+}
+
+// BuildProjectStatus groups the tasks of a project for a status update.
+// Tasks completed before since are only counted in the stats.
+func BuildProjectStatus(projectID string, tasks []*UniversalTask, since time.Time) *ProjectStatus {
+	status := &ProjectStatus{
+		ProjectID:  projectID,
+		Since:      since,
+		Stats:      ComputeTaskStats(tasks),
+		Completed:  []*UniversalTask{},
+		InProgress: []*UniversalTask{},
+		Blocked:    []*UniversalTask{},
+		Overdue:    []*UniversalTask{},
+	}
+
+	for _, task := range tasks {
+		switch {
+		case task.IsCompleted():
+			if !task.UpdatedAt.Before(since) {
+				status.Completed = append(status.Completed, task)
+			}
+			continue
+		case task.IsBlocked():
+			status.Blocked = append(status.Blocked, task)
+		case task.Status.Category == StatusCategoryInProgress ||
+			task.Status.Category == StatusCategoryReview ||
+			task.Status.Category == StatusCategoryTesting:
+			status.InProgress = append(status.InProgress, task)
+		}
+		if task.IsOverdue() {
+			status.Overdue = append(status.Overdue, task)
+		}
+	}
+
+	// Most important and most recently changed first
+	for _, group := range [][]*UniversalTask{status.Completed, status.InProgress, status.Blocked, status.Overdue} {
+		sort.SliceStable(group, func(i, j int) bool {
+			if ri, rj := rankOf(group[i].Priority), rankOf(group[j].Priority); ri != rj {
+				return ri < rj
+			}
+			return group[i].UpdatedAt.After(group[j].UpdatedAt)
+		})
+	}
+	return status
+}
+
+// PercentComplete is the share of completed tasks, from 0 to 100
+func (s *ProjectStatus) PercentComplete() int {
+	if s.Stats.Total == 0 {
+		return 0
+	}
+	return s.Stats.Completed * 100 / s.Stats.Total
+}
+
+// Digest describes the status as plain text, e.g. for an AI prompt. Each
+// group lists its most important tasks only.
+func (s *ProjectStatus) Digest() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Project: %s\n", s.ProjectID)
+	fmt.Fprintf(&b, "Period: since %s\n", s.Since.Format("2006-01-02"))
+	fmt.Fprintf(&b, "Tasks: %d total, %d completed (%d%%), %d in progress, %d blocked, %d overdue\n",
+		s.Stats.Total, s.Stats.Completed, s.PercentComplete(), len(s.InProgress), len(s.Blocked), len(s.Overdue))
+
+	writeDigestGroup(&b, "Completed in period", s.Completed)
+	writeDigestGroup(&b, "In progress", s.InProgress)
+	writeDigestGroup(&b, "Blocked", s.Blocked)
+	writeDigestGroup(&b, "Overdue", s.Overdue)
+	return b.String()
+}
+
+func writeDigestGroup(b *strings.Builder, title string, tasks []*UniversalTask) {
+	if len(tasks) == 0 {
+		return
+	}
+	fmt.Fprintf(b, "\n%s (%d):\n", title, len(tasks))
+	for i, task := range tasks {
+		if i == statusDigestItems {
+			fmt.Fprintf(b, "- ... and %d more\n", len(tasks)-i)
+			break
+		}
+		fmt.Fprintf(b, "- %s %s", task.GetDisplayID(), task.Title)
+		var details []string
+		if task.Priority != "" {
+			details = append(details, string(task.Priority))
+		}
+		if task.AssigneeID != "" {
+			details = append(details, "assignee "+task.AssigneeID)
+		}
+		if len(task.BlockedBy) > 0 {
+			details = append(details, "blocked by "+strings.Join(task.BlockedBy, ", "))
+		}
+		if task.DueDate != nil {
+			details = append(details, "due "+task.DueDate.Format("2006-01-02"))
+		}
+		if len(details) > 0 {
+			fmt.Fprintf(b, " (%s)", strings.Join(details, "; "))
+		}
+		b.WriteString("\n")
+	}
+}
@@ -0,0 +1,46 @@
+package providers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildProjectStatus(t *testing.T) {
+	now := time.Now()
+	since := now.AddDate(0, 0, -7)
+	yesterday := now.AddDate(0, 0, -1)
+	done := TaskStatus{Name: "Done", Category: StatusCategoryDone}
+	inProgress := TaskStatus{Name: "In Progress", Category: StatusCategoryInProgress}
+
+	status := BuildProjectStatus("OPS", []*UniversalTask{
+		{ID: "OPS-1", Title: "Old release", Status: done, UpdatedAt: now.AddDate(0, -1, 0)},
+		{ID: "OPS-2", Title: "Rotate certificates", Status: done, UpdatedAt: yesterday},
+		{ID: "OPS-3", Title: "Migrate database", Status: inProgress, Priority: TaskPriorityLow, UpdatedAt: now},
+		{ID: "OPS-4", Title: "Fix login crash", Status: inProgress, Priority: TaskPriorityCritical, AssigneeID: "alice", UpdatedAt: yesterday},
+		{ID: "OPS-5", Title: "Upgrade Go", BlockedBy: []string{"OPS-3"}, DueDate: &yesterday},
+		{ID: "OPS-6", Title: "Write runbook"},
+	}, since)
+
+	assert.Equal(t, 6, status.Stats.Total)
+	assert.Equal(t, 33, status.PercentComplete())
+	require.Len(t, status.Completed, 1)
+	assert.Equal(t, "OPS-2", status.Completed[0].ID)
+	require.Len(t, status.InProgress, 2)
+	assert.Equal(t, "OPS-4", status.InProgress[0].ID, "critical tasks come first")
+	require.Len(t, status.Blocked, 1)
+	require.Len(t, status.Overdue, 1)
+	assert.Equal(t, "OPS-5", status.Overdue[0].ID)
+
+	digest := status.Digest()
+	assert.Contains(t, digest, "Tasks: 6 total, 2 completed (33%), 2 in progress, 1 blocked, 1 overdue")
+	assert.Contains(t, digest, "- OPS-4 Fix login crash (critical; assignee alice)")
+	assert.Contains(t, digest, "- OPS-5 Upgrade Go (blocked by OPS-3; due "+yesterday.Format("2006-01-02")+")")
+	assert.NotContains(t, digest, "Old release")
+	assert.NotContains(t, digest, "Write runbook")
+
+	empty := BuildProjectStatus("OPS", nil, since)
+	assert.Equal(t, 0, empty.PercentComplete())
+}
@@ -0,0 +1,192 @@
+package providers
+
+import (
+	"context"
+)
+
+type actorContextKey struct{}
+
+// WithActor attaches the ID of the user performing task operations to ctx.
+// Published events carry it so consumers can tell who made the change.
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorContextKey{}, actor)
+}
+
+// ActorFromContext returns the actor set by WithActor, if any
+func ActorFromContext(ctx context.Context) string {
+	actor, _ := ctx.Value(actorContextKey{}).(string)
+	return actor
+}
+
+// PublishingProvider wraps a TaskProvider and publishes an event to the bus
+// after every successful task mutation.
+type PublishingProvider struct {
+	TaskProvider
+	name string
+	bus  *EventBus
+}
+
+// NewPublishingProvider wraps provider so its task operations publish into bus
+func NewPublishingProvider(provider TaskProvider, name string, bus *EventBus) *PublishingProvider {
+	return &PublishingProvider{
+		TaskProvider: provider,
+		name:         name,
+		bus:          bus,
+	}
+}
+
+// Unwrap returns the underlying provider
+func (p *PublishingProvider) Unwrap() TaskProvider {
+	return p.TaskProvider
+}
+
+// CreateTask creates the task and publishes task.created (and task.assigned)
+func (p *PublishingProvider) CreateTask(ctx context.Context, task *UniversalTask) (*UniversalTask, error) {
+	created, err := p.TaskProvider.CreateTask(ctx, task)
+	if err != nil {
+		return nil, err
+	}
+
+	p.publishTaskCreated(ctx, created)
+	return created, nil
+}
+
+// UpdateTask updates the task and publishes task.updated plus status and assignment events
+func (p *PublishingProvider) UpdateTask(ctx context.Context, id string, updates *TaskUpdate) error {
+	if err := p.TaskProvider.UpdateTask(ctx, id, updates); err != nil {
+		return err
+	}
+
+	p.publishTaskUpdated(ctx, id, updates)
+	return nil
+}
+
+// DeleteTask deletes the task and publishes task.deleted
+func (p *PublishingProvider) DeleteTask(ctx context.Context, id string) error {
+	if err := p.TaskProvider.DeleteTask(ctx, id); err != nil {
+		return err
+	}
+
+	p.publish(ctx, EventTypeTaskDeleted, id, nil)
+	return nil
+}
+
+// UpdateStatus changes the status and publishes task.status_changed
+func (p *PublishingProvider) UpdateStatus(ctx context.Context, taskID string, status TaskStatus) error {
+	if err := p.TaskProvider.UpdateStatus(ctx, taskID, status); err != nil {
+		return err
+	}
+
+	p.publish(ctx, EventTypeTaskStatusChanged, taskID, map[string]interface{}{
+		"status":    status.Name,
+		"status_id": status.ID,
+	})
+	return nil
+}
+
+// BulkCreateTasks creates the tasks and publishes an event per created task,
+// also for those created before a failure
+func (p *PublishingProvider) BulkCreateTasks(ctx context.Context, tasks []*UniversalTask) ([]*UniversalTask, error) {
+	created, err := p.TaskProvider.BulkCreateTasks(ctx, tasks)
+
+	// Tasks created before a failure exist too
+	for _, task := range created {
+		p.publishTaskCreated(ctx, task)
+	}
+	return created, err
+}
+
+// BulkUpdateTasks updates the tasks and publishes events per updated task
+func (p *PublishingProvider) BulkUpdateTasks(ctx context.Context, updates map[string]*TaskUpdate) error {
+	if err := p.TaskProvider.BulkUpdateTasks(ctx, updates); err != nil {
+		return err
+	}
+
+	for id, update := range updates {
+		p.publishTaskUpdated(ctx, id, update)
+	}
+	return nil
+}
+
+func (p *PublishingProvider) publishTaskCreated(ctx context.Context, task *UniversalTask) {
+	if task == nil {
+		return
+	}
+
+	data := map[string]interface{}{
+		"title":      task.Title,
+		"priority":   string(task.Priority),
+		"type":       string(task.Type),
+		"project_id": task.ProjectID,
+		"status":     task.Status.Name,
+	}
+	if task.AssigneeID != "" {
+		data["assignee"] = task.AssigneeID
+	}
+	p.publishWithBoard(ctx, EventTypeTaskCreated, task.GetDisplayID(), task.BoardID, data)
+
+	if task.AssigneeID != "" {
+		p.publishWithBoard(ctx, EventTypeTaskAssigned, task.GetDisplayID(), task.BoardID, map[string]interface{}{
+			"title":    task.Title,
+			"assignee": task.AssigneeID,
+		})
+	}
+}
+
+func (p *PublishingProvider) publishTaskUpdated(ctx context.Context, id string, updates *TaskUpdate) {
+	data := map[string]interface{}{}
+	if updates != nil {
+		if updates.Title != nil {
+			data["title"] = *updates.Title
+		}
+		if updates.Priority != nil {
+			data["priority"] = string(*updates.Priority)
+		}
+		if updates.Status != nil {
+			data["status"] = updates.Status.Name
+		}
+		if updates.AssigneeID != nil {
+			data["assignee"] = *updates.AssigneeID
+		}
+	}
+	p.publish(ctx, EventTypeTaskUpdated, id, data)
+
+	if updates == nil {
+		return
+	}
+	if updates.Status != nil {
+		p.publish(ctx, EventTypeTaskStatusChanged, id, map[string]interface{}{
+			"status":    updates.Status.Name,
+			"status_id": updates.Status.ID,
+		})
+	}
+	if updates.AssigneeID != nil && *updates.AssigneeID != "" {
+		p.publish(ctx, EventTypeTaskAssigned, id, map[string]interface{}{
+			"assignee": *updates.AssigneeID,
+		})
+	}
+}
+
+func (p *PublishingProvider) publish(ctx context.Context, eventType EventType, taskID string, data map[string]interface{}) {
+	p.publishWithBoard(ctx, eventType, taskID, "", data)
+}
+
+func (p *PublishingProvider) publishWithBoard(ctx context.Context, eventType EventType, taskID, boardID string, data map[string]interface{}) {
+	if p.bus == nil {
+		return
+	}
+	if data == nil {
+		data = make(map[string]interface{})
+	}
+	if actor := ActorFromContext(ctx); actor != "" {
+		data["actor"] = actor
+	}
+
+	p.bus.Publish(&UniversalEvent{
+		Type:    eventType,
+		Source:  p.name,
+		TaskID:  taskID,
+		BoardID: boardID,
+		Data:    data,
+	})
+}
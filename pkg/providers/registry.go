@@ -18,13 +18,10 @@ type ProviderRegistry struct {
 	healthCheckers   map[string]*HealthChecker
 	logger           *logrus.Logger
 	defaultProvider  string
+	eventBus         *EventBus
+	metadataCache    *MetadataCache
 }
 
-// PluginFactory is a function that creates a new plugin instance
-type PluginFactory func() TaskManagerPlugin
-
-var globalPluginFactories = make(map[string]PluginFactory)
-
 // NewProviderRegistry creates a new provider registry
 func NewProviderRegistry(config *MultiProviderConfig, logger *logrus.Logger) *ProviderRegistry {
 	if logger == nil {
@@ -43,11 +40,6 @@ func NewProviderRegistry(config *MultiProviderConfig, logger *logrus.Logger) *Pr
 	return registry
 }
 
-// RegisterPluginFactory registers a plugin factory globally
-func RegisterPluginFactory(providerType string, factory PluginFactory) {
-	globalPluginFactories[providerType] = factory
-}
-
 // Initialize initializes all configured providers
 func (r *ProviderRegistry) Initialize(ctx context.Context) error {
 	r.mu.Lock()
@@ -79,15 +71,12 @@ func (r *ProviderRegistry) Initialize(ctx context.Context) error {
 
 // initializeProvider initializes a single provider
 func (r *ProviderRegistry) initializeProvider(ctx context.Context, name string, config *ProviderConfig) error {
-	// Get plugin factory
-	factory, exists := globalPluginFactories[string(config.Type)]
-	if !exists {
-		return fmt.Errorf("no plugin factory registered for provider type: %s", config.Type)
+	// Create plugin instance
+	plugin, err := newPlugin(config.Type)
+	if err != nil {
+		return err
 	}
 
-	// Create plugin instance
-	plugin := factory()
-	
 	// Initialize plugin
 	if err := plugin.Initialize(config); err != nil {
 		return fmt.Errorf("failed to initialize plugin: %w", err)
@@ -114,6 +103,37 @@ func (r *ProviderRegistry) initializeProvider(ctx context.Context, name string,
 	return nil
 }
 
+// ProbeProvider creates a throwaway instance of a configured provider and runs
+// its health check without registering it. Failures to create the provider
+// are configuration errors.
+func ProbeProvider(ctx context.Context, config *ProviderConfig) error {
+	plugin, err := newPlugin(config.Type)
+	if err != nil {
+		return err
+	}
+
+	if err := plugin.Initialize(config); err != nil {
+		return NewProviderError(ErrorTypeConfiguration, "failed to initialize plugin", err)
+	}
+	defer plugin.Cleanup()
+
+	provider := plugin.GetProvider()
+	if provider == nil {
+		return NewProviderError(ErrorTypeConfiguration, "plugin returned nil provider", nil)
+	}
+
+	return provider.HealthCheck(ctx)
+}
+
+// PluginVersion returns the version of the plugin registered for a provider type
+func PluginVersion(providerType ProviderType) (string, bool) {
+	factory, exists := pluginFactory(providerType)
+	if !exists {
+		return "", false
+	}
+	return factory().Version(), true
+}
+
 // GetProvider returns a provider by name
 func (r *ProviderRegistry) GetProvider(name string) (TaskProvider, error) {
 	r.mu.RLock()
@@ -121,21 +141,200 @@ func (r *ProviderRegistry) GetProvider(name string) (TaskProvider, error) {
 
 	provider, exists := r.providers[name]
 	if !exists {
-		return nil, fmt.Errorf("provider not found: %s", name)
+		return nil, NewProviderError(ErrorTypeConfiguration, fmt.Sprintf("provider not found: %s", name), nil)
+	}
+
+	return r.wrapProvider(name, provider), nil
+}
+
+// SetEventBus makes task operations on providers from this registry publish into bus
+func (r *ProviderRegistry) SetEventBus(bus *EventBus) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.eventBus = bus
+}
+
+// EventBus returns the event bus task operations publish into, or nil
+func (r *ProviderRegistry) EventBus() *EventBus {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.eventBus
+}
+
+// DefaultOutputFormat returns the configured default output format of CLI commands
+func (r *ProviderRegistry) DefaultOutputFormat() string {
+	if r.config == nil {
+		return ""
+	}
+	return r.config.DefaultOutputFormat
+}
+
+// Location returns the configured time zone of due dates, or the local time
+// zone if none is configured
+func (r *ProviderRegistry) Location() (*time.Location, error) {
+	if r.config == nil {
+		return time.Local, nil
+	}
+	return LoadTimezone(r.config.Timezone)
+}
+
+// ProviderTimeout returns the configured request timeout of a provider, or 0 if
+// it has none
+func (r *ProviderRegistry) ProviderTimeout(name string) time.Duration {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.config == nil || r.config.Providers[name] == nil {
+		return 0
+	}
+	return r.config.Providers[name].Timeout
+}
+
+// DefaultProject returns the project commands use for a provider when no
+// project is given, or an empty string if it has none
+func (r *ProviderRegistry) DefaultProject(name string) string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.config == nil || r.config.Providers[name] == nil {
+		return ""
+	}
+	return r.config.Providers[name].DefaultProjectID()
+}
+
+// DefaultProjects returns the default project of every provider that has one,
+// for TaskFilters.ProjectByProvider
+func (r *ProviderRegistry) DefaultProjects() map[string]string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	projects := make(map[string]string)
+	if r.config == nil {
+		return projects
+	}
+	for name, config := range r.config.Providers {
+		if project := config.DefaultProjectID(); project != "" {
+			projects[name] = project
+		}
+	}
+	return projects
+}
+
+// FieldMapping returns the custom field mapping configured for a provider
+func (r *ProviderRegistry) FieldMapping(name string) FieldMapping {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.config == nil || r.config.Providers[name] == nil {
+		return nil
+	}
+	return r.config.Providers[name].FieldMapping
+}
+
+// PriorityMapping returns the priority mapping of a provider: the built-in
+// scheme of its type with the configured entries on top
+func (r *ProviderRegistry) PriorityMapping(name string) PriorityMapping {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.config == nil || r.config.Providers[name] == nil {
+		return PriorityMappingFor("", nil)
 	}
+	config := r.config.Providers[name]
+	return PriorityMappingFor(config.Type, config.PriorityMapping)
+}
+
+// AIChainConfig returns the aiChains configuration, or nil if there is none
+func (r *ProviderRegistry) AIChainConfig() *AIChainConfig {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
 
-	return provider, nil
+	if r.config == nil {
+		return nil
+	}
+	return r.config.AIChains
+}
+
+// MCPConfig returns the mcp configuration, or nil if there is none
+func (r *ProviderRegistry) MCPConfig() *MCPConfig {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.config == nil {
+		return nil
+	}
+	return r.config.MCP
+}
+
+// RouteTask decides which enabled provider a task goes to under the routing
+// rules of the configuration, see EvaluateRouting
+func (r *ProviderRegistry) RouteTask(task *UniversalTask) *RoutingDecision {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var available []string
+	var routing *RoutingConfig
+	if r.config != nil {
+		routing = r.config.Routing
+		for name := range r.providers {
+			if config := r.config.Providers[name]; config != nil && config.Enabled {
+				available = append(available, name)
+			}
+		}
+	}
+	return EvaluateRouting(routing, task, available, r.defaultProvider)
+}
+
+// SetMetadataCache makes metadata lookups on providers from this registry go through cache
+func (r *ProviderRegistry) SetMetadataCache(cache *MetadataCache) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.metadataCache = cache
+}
+
+// MetadataCache returns the cache metadata lookups go through, or nil
+func (r *ProviderRegistry) MetadataCache() *MetadataCache {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.metadataCache
+}
+
+// wrapProvider wraps a provider so it caches metadata lookups and publishes task events.
+// Must be called with the lock held.
+func (r *ProviderRegistry) wrapProvider(name string, provider TaskProvider) TaskProvider {
+	if r.metadataCache != nil {
+		config := r.config.Providers[name]
+		if config == nil || config.CacheConfig == nil || config.CacheConfig.Enabled {
+			provider = NewCachingProvider(provider, name, config, r.metadataCache)
+		}
+	}
+	if r.eventBus != nil {
+		provider = NewPublishingProvider(provider, name, r.eventBus)
+	}
+	return provider
 }
 
 // GetDefaultProvider returns the default provider
 func (r *ProviderRegistry) GetDefaultProvider() (TaskProvider, error) {
 	if r.defaultProvider == "" {
-		return nil, fmt.Errorf("no default provider configured")
+		return nil, NewProviderError(ErrorTypeConfiguration, "no default provider configured", nil)
 	}
 
 	return r.GetProvider(r.defaultProvider)
 }
 
+// DefaultProviderName returns the name of the default provider, or an empty string
+func (r *ProviderRegistry) DefaultProviderName() string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.defaultProvider
+}
+
 // ListProviders returns all available providers
 func (r *ProviderRegistry) ListProviders() map[string]*ProviderInfo {
 	r.mu.RLock()
@@ -143,7 +342,7 @@ func (r *ProviderRegistry) ListProviders() map[string]*ProviderInfo {
 
 	info := make(map[string]*ProviderInfo)
 	for name, provider := range r.providers {
-		info[name] = provider.GetProviderInfo()
+		info[name] = r.providerInfo(name, provider)
 	}
 
 	return info
@@ -158,13 +357,28 @@ func (r *ProviderRegistry) ListEnabledProviders() map[string]*ProviderInfo {
 	for name, provider := range r.providers {
 		config := r.config.Providers[name]
 		if config != nil && config.Enabled {
-			info[name] = provider.GetProviderInfo()
+			info[name] = r.providerInfo(name, provider)
 		}
 	}
 
 	return info
 }
 
+// providerInfo returns the info of a provider with its type taken from the
+// configuration when the plugin leaves it empty
+func (r *ProviderRegistry) providerInfo(name string, provider TaskProvider) *ProviderInfo {
+	info := provider.GetProviderInfo()
+	if info == nil || info.Type != "" {
+		return info
+	}
+
+	withType := *info
+	if config := r.config.Providers[name]; config != nil {
+		withType.Type = config.Type
+	}
+	return &withType
+}
+
 // HasCapability checks if any provider has a specific capability
 func (r *ProviderRegistry) HasCapability(capability Capability) bool {
 	r.mu.RLock()
@@ -201,6 +415,39 @@ func (r *ProviderRegistry) GetProvidersWithCapability(capability Capability) []s
 	return providers
 }
 
+// RequireCapability returns an unsupported error if the named provider doesn't
+// advertise capability
+func (r *ProviderRegistry) RequireCapability(name string, capability Capability) error {
+	provider, err := r.GetProvider(name)
+	if err != nil {
+		return err
+	}
+
+	if !provider.GetProviderInfo().HasCapability(capability) {
+		return NewUnsupportedError(name, capability)
+	}
+	return nil
+}
+
+// SplitByCapability splits provider names into those that advertise capability
+// and those that don't, so aggregate operations can skip the latter. Unknown
+// providers count as supported so that looking them up reports the real error.
+func (r *ProviderRegistry) SplitByCapability(names []string, capability Capability) (supported, unsupported []string) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, name := range names {
+		provider, exists := r.providers[name]
+		if exists && !provider.GetProviderInfo().HasCapability(capability) {
+			unsupported = append(unsupported, name)
+			continue
+		}
+		supported = append(supported, name)
+	}
+
+	return supported, unsupported
+}
+
 // EnableProvider enables a provider
 func (r *ProviderRegistry) EnableProvider(ctx context.Context, name string) error {
 	r.mu.Lock()
@@ -276,6 +523,9 @@ func (r *ProviderRegistry) AddProvider(ctx context.Context, name string, config
 	if err := config.Validate(); err != nil {
 		return fmt.Errorf("invalid provider config: %w", err)
 	}
+	if !IsProviderTypeRegistered(config.Type) {
+		return unknownProviderTypeError(ErrorTypeValidation, config.Type)
+	}
 
 	// Check if already exists
 	if _, exists := r.config.Providers[name]; exists {
@@ -310,6 +560,12 @@ func (r *ProviderRegistry) RemoveProvider(name string) error {
 	// Remove from config
 	delete(r.config.Providers, name)
 
+	if r.metadataCache != nil {
+		if _, err := r.metadataCache.InvalidateProvider(name); err != nil {
+			r.logger.Warnf("Failed to clear cached metadata of provider %s: %v", name, err)
+		}
+	}
+
 	// Update default provider if this was it
 	if r.defaultProvider == name {
 		r.defaultProvider = ""
@@ -460,20 +716,16 @@ func (h *HealthChecker) Stop() {
 
 // performHealthCheck performs a single health check
 func (h *HealthChecker) performHealthCheck(ctx context.Context) {
-	checkCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
-	defer cancel()
-
 	providerInfo := h.provider.GetProviderInfo()
 	oldStatus := providerInfo.HealthStatus
 
-	err := h.provider.HealthCheck(checkCtx)
-	
-	var newStatus ProviderHealthStatus
-	if err != nil {
-		newStatus = HealthStatusUnhealthy
-		h.logger.Warnf("Health check failed for provider %s: %v", providerInfo.Name, err)
-	} else {
-		newStatus = HealthStatusHealthy
+	result := CheckProviderHealth(ctx, providerInfo.Name, h.provider, HealthCheckOptions{Timeout: 30 * time.Second})
+	newStatus := result.Status
+	switch newStatus {
+	case HealthStatusUnhealthy:
+		h.logger.Warnf("Health check failed for provider %s: %s", providerInfo.Name, result.Error)
+	case HealthStatusDegraded:
+		h.logger.Warnf("Provider %s is slow: health check took %s", providerInfo.Name, result.Latency)
 	}
 
 	// Update provider info (this would need to be implemented in the provider)
@@ -499,7 +751,7 @@ func (r *ProviderRegistry) ExecuteOnAllProviders(operation func(string, TaskProv
 
 	results := make(map[string]error)
 	for name, provider := range r.providers {
-		results[name] = operation(name, provider)
+		results[name] = operation(name, r.wrapProvider(name, provider))
 	}
 
 	return results
@@ -513,7 +765,7 @@ func (r *ProviderRegistry) ExecuteOnEnabledProviders(operation func(string, Task
 	for name, provider := range r.providers {
 		config := r.config.Providers[name]
 		if config != nil && config.Enabled {
-			results[name] = operation(name, provider)
+			results[name] = operation(name, r.wrapProvider(name, provider))
 		}
 	}
 
@@ -0,0 +1,43 @@
+package providers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// capabilityProvider advertises a fixed set of capabilities
+type capabilityProvider struct {
+	TaskProvider
+	capabilities []Capability
+}
+
+func (p *capabilityProvider) GetProviderInfo() *ProviderInfo {
+	return &ProviderInfo{Capabilities: p.capabilities}
+}
+
+func TestRegistryCapabilities(t *testing.T) {
+	registry := NewProviderRegistry(DefaultMultiProviderConfig(), nil)
+	registry.providers["youtrack"] = &capabilityProvider{capabilities: []Capability{CapabilityTasks, CapabilityBoards, CapabilitySprints}}
+	registry.providers["notion"] = &capabilityProvider{capabilities: []Capability{CapabilityTasks, CapabilityDocuments}}
+
+	t.Run("Split by capability", func(t *testing.T) {
+		supported, unsupported := registry.SplitByCapability([]string{"notion", "youtrack", "missing"}, CapabilitySprints)
+		assert.Equal(t, []string{"youtrack", "missing"}, supported)
+		assert.Equal(t, []string{"notion"}, unsupported)
+	})
+
+	t.Run("Require capability", func(t *testing.T) {
+		assert.NoError(t, registry.RequireCapability("youtrack", CapabilitySprints))
+
+		err := registry.RequireCapability("notion", CapabilitySprints)
+		require.Error(t, err)
+		assert.True(t, IsUnsupportedError(err))
+		assert.Equal(t, "provider notion does not support sprints", err.Error())
+		assert.Equal(t, ExitUsage, ExitCode(err))
+
+		err = registry.RequireCapability("missing", CapabilitySprints)
+		assert.False(t, IsUnsupportedError(err))
+	})
+}
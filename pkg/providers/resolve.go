@@ -0,0 +1,238 @@
+package providers
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// statusAliases are shorthands users type for workflow states. They resolve to
+// the status of the category when the workflow has exactly one.
+var statusAliases = map[string]StatusCategory{
+	"todo":      StatusCategoryTodo,
+	"new":       StatusCategoryTodo,
+	"wip":       StatusCategoryInProgress,
+	"doing":     StatusCategoryInProgress,
+	"started":   StatusCategoryInProgress,
+	"active":    StatusCategoryInProgress,
+	"done":      StatusCategoryDone,
+	"closed":    StatusCategoryDone,
+	"finished":  StatusCategoryDone,
+	"completed": StatusCategoryDone,
+	"canceled":  StatusCategoryCancelled,
+	"cancelled": StatusCategoryCancelled,
+	"blocked":   StatusCategoryBlocked,
+	"review":    StatusCategoryReview,
+	"testing":   StatusCategoryTesting,
+	"qa":        StatusCategoryTesting,
+}
+
+// priorityAliases are priority names of other trackers that aren't levels of
+// the universal scheme
+var priorityAliases = map[string]TaskPriority{
+	"urgent":  TaskPriorityCritical,
+	"blocker": TaskPriorityCritical,
+	"normal":  TaskPriorityMedium,
+	"trivial": TaskPriorityLowest,
+}
+
+// priorityLevels are the universal priorities from the most to the least urgent
+var priorityLevels = []TaskPriority{
+	TaskPriorityCritical,
+	TaskPriorityHighest,
+	TaskPriorityHigh,
+	TaskPriorityMedium,
+	TaskPriorityLow,
+	TaskPriorityLowest,
+}
+
+// ResolveStatus finds the workflow status a user's input refers to, so that
+// "in progress", "inprogress" or "WIP" all find "In Progress". It tries, in
+// order: the status ID or name ignoring case, the name ignoring whitespace
+// and punctuation, a shorthand like wip or done when the workflow has a
+// single status of that category, and finally names containing the input or
+// within a few typos of it. Input matching several statuses at the first
+// step that matches anything is rejected with the candidates as suggestions.
+func ResolveStatus(input string, statuses []TaskStatus) (TaskStatus, error) {
+	input = strings.TrimSpace(input)
+	if input == "" {
+		return TaskStatus{}, NewValidationError("status is required", nil)
+	}
+
+	names := make([]string, len(statuses))
+	for i, status := range statuses {
+		if status.ID == input || strings.EqualFold(status.Name, input) {
+			return status, nil
+		}
+		names[i] = status.Name
+	}
+
+	matches := matchNormalized(input, names)
+	if len(matches) == 0 {
+		if category, ok := statusAliases[normalizeName(input)]; ok {
+			for i, status := range statuses {
+				if status.Category == category {
+					matches = append(matches, i)
+				}
+			}
+		}
+	}
+	if len(matches) == 0 {
+		matches = closeMatches(input, names)
+	}
+
+	switch len(matches) {
+	case 0:
+		return TaskStatus{}, NewValidationError(fmt.Sprintf("unknown status %q, available statuses: %s", input, strings.Join(quoteNames(names), ", ")), nil)
+	case 1:
+		return statuses[matches[0]], nil
+	default:
+		candidates := make([]string, len(matches))
+		for i, match := range matches {
+			candidates[i] = names[match]
+		}
+		return TaskStatus{}, NewValidationError(fmt.Sprintf("status %q is ambiguous, did you mean %s?", input, strings.Join(quoteNames(candidates), " or ")), nil)
+	}
+}
+
+// ResolvePriority finds the universal priority a user's input refers to. The
+// input may be a universal level or a native value of the provider's
+// mapping, e.g. Major for YouTrack, matched the same way as statuses by
+// ResolveStatus, or a common name like urgent or normal.
+func ResolvePriority(input string, mapping PriorityMapping) (TaskPriority, error) {
+	input = strings.TrimSpace(input)
+	if input == "" {
+		return "", NewValidationError("priority is required", nil)
+	}
+
+	// Universal levels first, so that a native value spelled like a level,
+	// e.g. Jira's Highest, reads as that level
+	var names []string
+	var priorities []TaskPriority
+	for _, priority := range priorityLevels {
+		names = append(names, string(priority))
+		priorities = append(priorities, priority)
+	}
+	for _, priority := range priorityLevels {
+		native, ok := mapping[priority]
+		if !ok {
+			continue
+		}
+		if universal, ok := mapping.Universal(native); ok && universal == priority {
+			names = append(names, native)
+			priorities = append(priorities, priority)
+		}
+	}
+
+	for i, name := range names {
+		if strings.EqualFold(name, input) {
+			return priorities[i], nil
+		}
+	}
+
+	var matched []TaskPriority
+	if alias, ok := priorityAliases[normalizeName(input)]; ok {
+		matched = []TaskPriority{alias}
+	}
+	for _, match := range matchNormalized(input, names) {
+		matched = appendPriority(matched, priorities[match])
+	}
+	if len(matched) == 0 {
+		for _, match := range closeMatches(input, names) {
+			matched = appendPriority(matched, priorities[match])
+		}
+	}
+
+	switch len(matched) {
+	case 0:
+		return "", NewValidationError(fmt.Sprintf("unknown priority %q, use %s", input, strings.Join(quoteNames(names), ", ")), nil)
+	case 1:
+		return matched[0], nil
+	default:
+		candidates := make([]string, len(matched))
+		for i, priority := range matched {
+			candidates[i] = string(priority)
+		}
+		return "", NewValidationError(fmt.Sprintf("priority %q is ambiguous, did you mean %s?", input, strings.Join(quoteNames(candidates), " or ")), nil)
+	}
+}
+
+func appendPriority(priorities []TaskPriority, priority TaskPriority) []TaskPriority {
+	for _, existing := range priorities {
+		if existing == priority {
+			return priorities
+		}
+	}
+	return append(priorities, priority)
+}
+
+// normalizeName lowercases a name and drops everything but letters and
+// digits, so that "In Progress", "in-progress" and "inprogress" compare equal
+func normalizeName(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// matchNormalized returns the indexes of the names equal to input once both
+// are normalized
+func matchNormalized(input string, names []string) []int {
+	key := normalizeName(input)
+	var matches []int
+	for i, name := range names {
+		if key != "" && normalizeName(name) == key {
+			matches = append(matches, i)
+		}
+	}
+	return matches
+}
+
+// closeMatches returns the indexes of the names containing input or, if none
+// does, of the names closest to it within a few typos
+func closeMatches(input string, names []string) []int {
+	key := normalizeName(input)
+	if key == "" {
+		return nil
+	}
+
+	var matches []int
+	if len([]rune(key)) >= 3 {
+		for i, name := range names {
+			if strings.Contains(normalizeName(name), key) {
+				matches = append(matches, i)
+			}
+		}
+		if len(matches) > 0 {
+			return matches
+		}
+	}
+
+	// A typo is only taken for a name when few letters differ, fewer in
+	// short names, where a couple of letters make a different word
+	bestDistance := 2
+	if len([]rune(key)) >= 5 {
+		bestDistance = 3
+	}
+	for i, name := range names {
+		distance := editDistance(key, normalizeName(name))
+		switch {
+		case distance < bestDistance:
+			matches, bestDistance = []int{i}, distance
+		case distance == bestDistance && len(matches) > 0:
+			matches = append(matches, i)
+		}
+	}
+	return matches
+}
+
+func quoteNames(names []string) []string {
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = fmt.Sprintf("%q", name)
+	}
+	return quoted
+}
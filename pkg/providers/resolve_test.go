@@ -0,0 +1,108 @@
+package providers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveStatus(t *testing.T) {
+	workflow := []TaskStatus{
+		{ID: "open", Name: "Open", Category: StatusCategoryTodo},
+		{ID: "in_progress", Name: "In Progress", Category: StatusCategoryInProgress},
+		{ID: "code_review", Name: "Code Review", Category: StatusCategoryReview},
+		{ID: "fixed", Name: "Fixed", Category: StatusCategoryDone, IsFinal: true},
+		{ID: "verified", Name: "Verified", Category: StatusCategoryDone, IsFinal: true},
+	}
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"In Progress", "In Progress"},
+		{"in progress", "In Progress"},
+		{"  IN PROGRESS ", "In Progress"},
+		{"inprogress", "In Progress"},
+		{"in-progress", "In Progress"},
+		{"in_progress", "In Progress"},
+		{"WIP", "In Progress"},
+		{"todo", "Open"},
+		{"review", "Code Review"},
+		{"progress", "In Progress"},
+		{"verifed", "Verified"},
+		{"Fxed", "Fixed"},
+	}
+	for _, test := range tests {
+		t.Run(test.input, func(t *testing.T) {
+			status, err := ResolveStatus(test.input, workflow)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, status.Name)
+		})
+	}
+
+	t.Run("ambiguous", func(t *testing.T) {
+		// Two done statuses, so the shorthand can't pick one
+		_, err := ResolveStatus("done", workflow)
+		require.Error(t, err)
+		assert.True(t, IsErrorType(err, ErrorTypeValidation))
+		assert.Contains(t, err.Error(), `"Fixed" or "Verified"`)
+	})
+
+	t.Run("unknown", func(t *testing.T) {
+		// Too many letters differ in a short name to take it for a typo
+		_, err := ResolveStatus("Opne", workflow)
+		require.Error(t, err)
+
+		_, err = ResolveStatus("deployed", workflow)
+		require.Error(t, err)
+		assert.True(t, IsErrorType(err, ErrorTypeValidation))
+		assert.Contains(t, err.Error(), `available statuses: "Open", "In Progress"`)
+	})
+}
+
+func TestResolvePriority(t *testing.T) {
+	youtrack := PriorityMappingFor(ProviderTypeYouTrack, nil)
+
+	tests := []struct {
+		input    string
+		mapping  PriorityMapping
+		expected TaskPriority
+	}{
+		{"high", youtrack, TaskPriorityHigh},
+		{"HIGHEST", youtrack, TaskPriorityHighest},
+		{"Major", youtrack, TaskPriorityHigh},
+		{"show stopper", youtrack, TaskPriorityCritical},
+		{"showstopper", youtrack, TaskPriorityCritical},
+		{"urgent", youtrack, TaskPriorityCritical},
+		{"normal", PriorityMappingFor(ProviderTypeJira, nil), TaskPriorityMedium},
+		{"Highest", PriorityMappingFor(ProviderTypeJira, nil), TaskPriorityHighest},
+		{"meduim", youtrack, TaskPriorityMedium},
+		{"crit", PriorityMappingFor(ProviderTypeJira, nil), TaskPriorityCritical},
+	}
+	for _, test := range tests {
+		t.Run(test.input, func(t *testing.T) {
+			priority, err := ResolvePriority(test.input, test.mapping)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, priority)
+		})
+	}
+
+	t.Run("ambiguous", func(t *testing.T) {
+		_, err := ResolvePriority("hig", youtrack)
+		require.Error(t, err)
+		assert.True(t, IsErrorType(err, ErrorTypeValidation))
+		assert.Contains(t, err.Error(), `"highest" or "high"`)
+
+		// YouTrack's Critical is the highest level, not critical
+		_, err = ResolvePriority("crit", youtrack)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `"critical" or "highest"`)
+	})
+
+	t.Run("unknown", func(t *testing.T) {
+		_, err := ResolvePriority("whenever", youtrack)
+		require.Error(t, err)
+		assert.True(t, IsErrorType(err, ErrorTypeValidation))
+	})
+}
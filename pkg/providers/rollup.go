@@ -0,0 +1,200 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+)
+
+// RollupChild is a descendant of an epic in its rollup
+type RollupChild struct {
+	TaskID         string   `json:"taskId"`
+	Title          string   `json:"title"`
+	Status         string   `json:"status"`
+	ParentID       string   `json:"parentId,omitempty"`
+	Completed      bool     `json:"completed"`
+	Blocked        bool     `json:"blocked"`
+	BlockedBy      []string `json:"blockedBy,omitempty"`
+	EstimatedHours float64  `json:"estimatedHours,omitempty"`
+}
+
+// EpicRollup is the aggregate progress of an epic's descendants
+type EpicRollup struct {
+	EpicID    string `json:"epicId"`
+	Title     string `json:"title"`
+	Total     int    `json:"total"`
+	Completed int    `json:"completed"`
+
+	// Percent is the share of completed descendants, by count
+	Percent float64 `json:"percent"`
+
+	// EstimatedHours sums the estimates of the descendants, CompletedHours
+	// those of the completed ones. Descendants without an estimate are
+	// counted in Unestimated and left out of HoursPercent.
+	EstimatedHours float64 `json:"estimatedHours"`
+	CompletedHours float64 `json:"completedHours"`
+	HoursPercent   float64 `json:"hoursPercent"`
+	Unestimated    int     `json:"unestimated"`
+
+	Blockers []RollupChild `json:"blockers,omitempty"`
+	Children []RollupChild `json:"children"`
+}
+
+// taskAliases returns the IDs a task may be referred to by from ParentID,
+// EpicID or SubtaskIDs of other tasks
+func taskAliases(task *UniversalTask) []string {
+	var aliases []string
+	for _, id := range []string{task.ID, task.Key, task.ExternalID} {
+		if id != "" {
+			aliases = append(aliases, id)
+		}
+	}
+	return aliases
+}
+
+// FetchDescendants returns the subtasks of an epic and their subtasks,
+// breadth first. Children are found through the SubtaskIDs of each task and
+// through the ParentID and EpicID of the tasks listed with filters, so
+// providers that only fill one side of the relation are covered. A nil
+// filters skips the listing and follows SubtaskIDs only.
+func FetchDescendants(ctx context.Context, provider TaskProvider, epic *UniversalTask, filters *TaskFilters) ([]*UniversalTask, error) {
+	var candidates []*UniversalTask
+	if filters != nil {
+		listed, err := provider.ListTasks(ctx, filters)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list tasks: %w", err)
+		}
+		candidates = listed
+	}
+	return FindDescendants(ctx, provider, epic, candidates)
+}
+
+// FindDescendants is FetchDescendants over tasks already listed, so that
+// several tasks of a project can share one listing. Subtasks missing from
+// candidates are fetched from the provider.
+func FindDescendants(ctx context.Context, provider TaskProvider, epic *UniversalTask, candidates []*UniversalTask) ([]*UniversalTask, error) {
+	// Candidates by every ID they are known by, and their children by parent ID
+	byID := make(map[string]*UniversalTask)
+	children := make(map[string][]*UniversalTask)
+	for _, task := range candidates {
+		if task == nil {
+			continue
+		}
+		for _, alias := range taskAliases(task) {
+			byID[alias] = task
+		}
+		if task.ParentID != "" {
+			children[task.ParentID] = append(children[task.ParentID], task)
+		}
+		if task.EpicID != "" && task.EpicID != task.ParentID {
+			children[task.EpicID] = append(children[task.EpicID], task)
+		}
+	}
+
+	seen := make(map[string]bool)
+	for _, alias := range taskAliases(epic) {
+		seen[alias] = true
+	}
+	markSeen := func(task *UniversalTask) bool {
+		aliases := taskAliases(task)
+		for _, alias := range aliases {
+			if seen[alias] {
+				return false
+			}
+		}
+		for _, alias := range aliases {
+			seen[alias] = true
+		}
+		return true
+	}
+
+	var descendants []*UniversalTask
+	queue := []*UniversalTask{epic}
+	for len(queue) > 0 {
+		if err := ctx.Err(); err != nil {
+			return descendants, err
+		}
+		parent := queue[0]
+		queue = queue[1:]
+
+		var next []*UniversalTask
+		for _, alias := range taskAliases(parent) {
+			next = append(next, children[alias]...)
+		}
+		for _, id := range parent.SubtaskIDs {
+			if seen[id] {
+				continue
+			}
+			child, known := byID[id]
+			if !known {
+				fetched, err := provider.GetTask(ctx, id)
+				if err != nil {
+					return descendants, fmt.Errorf("failed to get subtask %s: %w", id, err)
+				}
+				child = fetched
+			}
+			next = append(next, child)
+		}
+
+		for _, child := range next {
+			if !markSeen(child) {
+				continue
+			}
+			descendants = append(descendants, child)
+			queue = append(queue, child)
+		}
+	}
+
+	return descendants, nil
+}
+
+// ComputeRollup aggregates the progress of an epic's descendants by count and
+// by estimated hours and picks out the blocked ones
+func ComputeRollup(epic *UniversalTask, descendants []*UniversalTask) *EpicRollup {
+	rollup := &EpicRollup{
+		EpicID:   epic.GetDisplayID(),
+		Title:    epic.Title,
+		Children: make([]RollupChild, 0, len(descendants)),
+	}
+
+	for _, task := range descendants {
+		if task == nil {
+			continue
+		}
+
+		child := RollupChild{
+			TaskID:    task.GetDisplayID(),
+			Title:     task.Title,
+			Status:    task.Status.Name,
+			ParentID:  task.ParentID,
+			Completed: task.IsCompleted(),
+			Blocked:   task.IsBlocked() && !task.IsCompleted(),
+			BlockedBy: task.BlockedBy,
+		}
+
+		rollup.Total++
+		if child.Completed {
+			rollup.Completed++
+		}
+		if task.EstimatedTime != nil && *task.EstimatedTime > 0 {
+			child.EstimatedHours = task.EstimatedTime.Hours()
+			rollup.EstimatedHours += child.EstimatedHours
+			if child.Completed {
+				rollup.CompletedHours += child.EstimatedHours
+			}
+		} else {
+			rollup.Unestimated++
+		}
+		if child.Blocked {
+			rollup.Blockers = append(rollup.Blockers, child)
+		}
+		rollup.Children = append(rollup.Children, child)
+	}
+
+	if rollup.Total > 0 {
+		rollup.Percent = 100 * float64(rollup.Completed) / float64(rollup.Total)
+	}
+	if rollup.EstimatedHours > 0 {
+		rollup.HoursPercent = 100 * rollup.CompletedHours / rollup.EstimatedHours
+	}
+	return rollup
+}
@@ -0,0 +1,89 @@
+package providers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// hierarchyProvider lists a fixed set of tasks and serves GetTask from another
+type hierarchyProvider struct {
+	TaskProvider
+	listed  []*UniversalTask
+	byID    map[string]*UniversalTask
+	fetched []string
+}
+
+func (p *hierarchyProvider) ListTasks(ctx context.Context, filters *TaskFilters) ([]*UniversalTask, error) {
+	return p.listed, nil
+}
+
+func (p *hierarchyProvider) GetTask(ctx context.Context, id string) (*UniversalTask, error) {
+	p.fetched = append(p.fetched, id)
+	if task, ok := p.byID[id]; ok {
+		return task, nil
+	}
+	return nil, NewProviderError(ErrorTypeNotFound, "task not found", nil)
+}
+
+func TestFetchDescendants(t *testing.T) {
+	ctx := context.Background()
+	epic := &UniversalTask{ID: "1-1", Key: "OPS-1", SubtaskIDs: []string{"1-2", "1-9"}}
+
+	listed := []*UniversalTask{
+		{ID: "1-2", Key: "OPS-2", ParentID: "1-1"},  // known from both sides
+		{ID: "1-3", Key: "OPS-3", EpicID: "OPS-1"},  // epic link by key
+		{ID: "1-4", Key: "OPS-4", ParentID: "1-3"},  // grandchild
+		{ID: "1-5", Key: "OPS-5", ParentID: "1-4"},  // great-grandchild
+		{ID: "1-6", Key: "OPS-6", ParentID: "1-77"}, // someone else's
+		{ID: "1-1", Key: "OPS-1"},                   // the epic itself
+	}
+	outside := &UniversalTask{ID: "1-9", Key: "OPS-9"} // not in the listed project
+
+	provider := &hierarchyProvider{listed: listed, byID: map[string]*UniversalTask{"1-9": outside}}
+
+	descendants, err := FetchDescendants(ctx, provider, epic, &TaskFilters{ProjectID: "OPS"})
+	require.NoError(t, err)
+
+	var ids []string
+	for _, task := range descendants {
+		ids = append(ids, task.Key)
+	}
+	assert.Equal(t, []string{"OPS-2", "OPS-3", "OPS-9", "OPS-4", "OPS-5"}, ids)
+	assert.Equal(t, []string{"1-9"}, provider.fetched, "listed subtasks are not fetched again")
+
+	_, err = FetchDescendants(ctx, provider, &UniversalTask{ID: "1-1", SubtaskIDs: []string{"1-404"}}, nil)
+	assert.True(t, IsNotFoundError(err))
+}
+
+func TestComputeRollup(t *testing.T) {
+	hours := func(h int) *time.Duration { d := time.Duration(h) * time.Hour; return &d }
+	done := TaskStatus{Name: "Done", Category: StatusCategoryDone}
+
+	epic := &UniversalTask{Key: "OPS-1", Title: "Billing"}
+	rollup := ComputeRollup(epic, []*UniversalTask{
+		{Key: "OPS-2", Status: done, EstimatedTime: hours(6)},
+		{Key: "OPS-3", EstimatedTime: hours(2), BlockedBy: []string{"OPS-9"}},
+		{Key: "OPS-4", Status: TaskStatus{Name: "Blocked", Category: StatusCategoryBlocked}},
+		{Key: "OPS-5", Status: done, BlockedBy: []string{"OPS-9"}},
+	})
+
+	assert.Equal(t, "OPS-1", rollup.EpicID)
+	assert.Equal(t, 4, rollup.Total)
+	assert.Equal(t, 2, rollup.Completed)
+	assert.InDelta(t, 50, rollup.Percent, 0.001)
+	assert.InDelta(t, 8, rollup.EstimatedHours, 0.001)
+	assert.InDelta(t, 6, rollup.CompletedHours, 0.001)
+	assert.InDelta(t, 75, rollup.HoursPercent, 0.001)
+	assert.Equal(t, 2, rollup.Unestimated)
+	require.Len(t, rollup.Blockers, 2, "completed tasks don't block")
+	assert.Equal(t, "OPS-3", rollup.Blockers[0].TaskID)
+	assert.Equal(t, "OPS-4", rollup.Blockers[1].TaskID)
+
+	empty := ComputeRollup(epic, nil)
+	assert.Zero(t, empty.Percent)
+	assert.NotNil(t, empty.Children)
+}
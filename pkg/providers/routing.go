@@ -0,0 +1,153 @@
+package providers
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// RuleEvaluation is how one routing rule fared against a task
+type RuleEvaluation struct {
+	Name     string `json:"name"`
+	Provider string `json:"provider"`
+	Priority int    `json:"priority"`
+	Matched  bool   `json:"matched"`
+	Selected bool   `json:"selected,omitempty"`
+	// Skipped is why the rule wasn't considered, e.g. it is disabled
+	Skipped string `json:"skipped,omitempty"`
+	// Mismatches lists the conditions the task doesn't meet
+	Mismatches []string `json:"mismatches,omitempty"`
+}
+
+// RoutingDecision is the provider a task is routed to and why
+type RoutingDecision struct {
+	Provider string `json:"provider"`
+	// Rule is the rule that chose the provider, empty if it is the fallback
+	Rule   string           `json:"rule,omitempty"`
+	Reason string           `json:"reason"`
+	Rules  []RuleEvaluation `json:"rules"`
+}
+
+// EvaluateRouting decides which provider a task goes to under the routing
+// rules of config. Enabled rules are tried by descending priority, in
+// configuration order for equal priorities, and the first one that matches
+// and whose provider is among available wins. Without a match the task goes
+// to the routing default provider, or to fallback. Every rule is evaluated
+// so the decision explains which matched and which were skipped.
+func EvaluateRouting(config *RoutingConfig, task *UniversalTask, available []string, fallback string) *RoutingDecision {
+	decision := &RoutingDecision{Rules: []RuleEvaluation{}}
+	isAvailable := func(name string) bool {
+		for _, provider := range available {
+			if provider == name {
+				return true
+			}
+		}
+		return false
+	}
+
+	var rules []RoutingRule
+	if config != nil {
+		for i, rule := range config.Rules {
+			if rule.Name == "" {
+				rule.Name = fmt.Sprintf("rule %d", i+1)
+			}
+			rules = append(rules, rule)
+		}
+		if config.Strategy != "" && config.Strategy != RoutingStrategyRules {
+			decision.Reason = fmt.Sprintf("strategy %s is not supported, rules were evaluated instead; ", config.Strategy)
+		}
+	}
+	sort.SliceStable(rules, func(i, j int) bool { return rules[i].Priority > rules[j].Priority })
+
+	for _, rule := range rules {
+		evaluation := RuleEvaluation{Name: rule.Name, Provider: rule.Provider, Priority: rule.Priority}
+		evaluation.Mismatches = rule.Condition.Mismatches(task)
+		evaluation.Matched = len(evaluation.Mismatches) == 0
+		switch {
+		case !rule.Enabled:
+			evaluation.Skipped = "disabled"
+		case !isAvailable(rule.Provider):
+			evaluation.Skipped = fmt.Sprintf("provider %q is not configured or not enabled", rule.Provider)
+		case evaluation.Matched && decision.Rule == "":
+			evaluation.Selected = true
+			decision.Provider = rule.Provider
+			decision.Rule = evaluation.Name
+			decision.Reason += fmt.Sprintf("matched rule %q", evaluation.Name)
+		case evaluation.Matched:
+			evaluation.Skipped = fmt.Sprintf("rule %q was matched first", decision.Rule)
+		}
+		decision.Rules = append(decision.Rules, evaluation)
+	}
+
+	if decision.Rule != "" {
+		return decision
+	}
+	switch {
+	case config != nil && config.DefaultProvider != "" && isAvailable(config.DefaultProvider):
+		decision.Provider = config.DefaultProvider
+		decision.Reason += "no rule matched, using the routing default provider"
+	case fallback != "":
+		decision.Provider = fallback
+		decision.Reason += "no rule matched, using the default provider"
+	default:
+		decision.Reason += "no rule matched and there is no default provider"
+	}
+	return decision
+}
+
+// Mismatches describes the conditions a task doesn't meet. Text comparisons
+// ignore case; every label of the condition must be on the task, the custom
+// field condition has the form "Name=value" and the query must occur in the
+// title or description.
+func (c RoutingCondition) Mismatches(task *UniversalTask) []string {
+	var mismatches []string
+	mismatch := func(field, want, got string) {
+		if got == "" {
+			got = "none"
+		}
+		mismatches = append(mismatches, fmt.Sprintf("%s is %s, not %s", field, got, want))
+	}
+
+	if c.ProjectID != "" && !strings.EqualFold(c.ProjectID, task.ProjectID) {
+		mismatch("project", c.ProjectID, task.ProjectID)
+	}
+	if c.TaskType != "" && !strings.EqualFold(string(c.TaskType), string(task.Type)) {
+		mismatch("type", string(c.TaskType), string(task.Type))
+	}
+	if c.Priority != "" && !strings.EqualFold(string(c.Priority), string(task.Priority)) {
+		mismatch("priority", string(c.Priority), string(task.Priority))
+	}
+	if c.Assignee != "" && !strings.EqualFold(c.Assignee, task.AssigneeID) {
+		mismatch("assignee", c.Assignee, task.AssigneeID)
+	}
+
+	var missing []string
+	taskLabels := labelSet(task.Labels)
+	for _, label := range c.Labels {
+		if !taskLabels[strings.ToLower(label)] {
+			missing = append(missing, label)
+		}
+	}
+	if len(missing) > 0 {
+		mismatches = append(mismatches, fmt.Sprintf("labels %s are missing", strings.Join(missing, ", ")))
+	}
+
+	if c.CustomField != "" {
+		name, want, _ := strings.Cut(c.CustomField, "=")
+		name, want = strings.TrimSpace(name), strings.TrimSpace(want)
+		_, value, ok := lookupCustomField(task.CustomFields, name)
+		switch {
+		case !ok:
+			mismatches = append(mismatches, fmt.Sprintf("custom field %s is not set", name))
+		case want != "" && !strings.EqualFold(formatDiffValue(value), want):
+			mismatch("custom field "+name, want, formatDiffValue(value))
+		}
+	}
+
+	if query := strings.ToLower(strings.TrimSpace(c.Query)); query != "" &&
+		!strings.Contains(strings.ToLower(task.Title), query) && !strings.Contains(strings.ToLower(task.Description), query) {
+		mismatches = append(mismatches, fmt.Sprintf("title and description don't contain %q", c.Query))
+	}
+
+	return mismatches
+}
@@ -0,0 +1,76 @@
+package providers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvaluateRouting(t *testing.T) {
+	config := &RoutingConfig{
+		Strategy:        RoutingStrategyRules,
+		DefaultProvider: "youtrack",
+		Rules: []RoutingRule{
+			{Name: "docs", Provider: "notion", Priority: 1, Enabled: true, Condition: RoutingCondition{Labels: []string{"docs"}}},
+			{Name: "backend-bugs", Provider: "youtrack", Priority: 10, Enabled: true, Condition: RoutingCondition{TaskType: TaskTypeBug, Labels: []string{"Backend"}}},
+			{Name: "incidents", Provider: "jira", Priority: 20, Enabled: true, Condition: RoutingCondition{Priority: TaskPriorityCritical}},
+			{Name: "legacy", Provider: "youtrack", Priority: 30, Enabled: false},
+			{Provider: "notion", Priority: 10, Enabled: true, Condition: RoutingCondition{ProjectID: "OPS", Query: "runbook"}},
+		},
+	}
+	available := []string{"youtrack", "notion"}
+
+	t.Run("Highest priority matching rule wins", func(t *testing.T) {
+		task := &UniversalTask{Type: TaskTypeBug, Priority: TaskPriorityCritical, Labels: []string{"backend", "docs"}}
+		decision := EvaluateRouting(config, task, available, "")
+
+		assert.Equal(t, "youtrack", decision.Provider)
+		assert.Equal(t, "backend-bugs", decision.Rule)
+		require.Len(t, decision.Rules, 5)
+
+		names := make([]string, len(decision.Rules))
+		for i, rule := range decision.Rules {
+			names[i] = rule.Name
+		}
+		assert.Equal(t, []string{"legacy", "incidents", "backend-bugs", "rule 5", "docs"}, names)
+
+		assert.Equal(t, "disabled", decision.Rules[0].Skipped)
+		assert.True(t, decision.Rules[1].Matched)
+		assert.Contains(t, decision.Rules[1].Skipped, `"jira" is not configured`)
+		assert.True(t, decision.Rules[2].Selected)
+		assert.Equal(t, []string{"project is none, not OPS", `title and description don't contain "runbook"`}, decision.Rules[3].Mismatches)
+		assert.True(t, decision.Rules[4].Matched)
+		assert.Equal(t, `rule "backend-bugs" was matched first`, decision.Rules[4].Skipped)
+	})
+
+	t.Run("Conditions", func(t *testing.T) {
+		task := &UniversalTask{ProjectID: "ops", Title: "Write the RUNBOOK", Type: TaskTypeTask}
+		decision := EvaluateRouting(config, task, available, "")
+		assert.Equal(t, "notion", decision.Provider)
+		assert.Equal(t, "rule 5", decision.Rule)
+
+		condition := RoutingCondition{Assignee: "alice", CustomField: "Team=Platform"}
+		assert.Equal(t, []string{"assignee is none, not alice", "custom field Team is not set"}, condition.Mismatches(&UniversalTask{}))
+		assert.Empty(t, condition.Mismatches(&UniversalTask{AssigneeID: "Alice", CustomFields: map[string]interface{}{"team": "platform"}}))
+		assert.Equal(t, []string{"custom field Team is Mobile, not Platform"},
+			condition.Mismatches(&UniversalTask{AssigneeID: "alice", CustomFields: map[string]interface{}{"Team": "Mobile"}}))
+	})
+
+	t.Run("Fallbacks", func(t *testing.T) {
+		task := &UniversalTask{Type: TaskTypeFeature}
+		decision := EvaluateRouting(config, task, available, "notion")
+		assert.Equal(t, "youtrack", decision.Provider)
+		assert.Empty(t, decision.Rule)
+		assert.Equal(t, "no rule matched, using the routing default provider", decision.Reason)
+
+		decision = EvaluateRouting(&RoutingConfig{DefaultProvider: "jira", Strategy: RoutingStrategyRoundRobin}, task, available, "notion")
+		assert.Equal(t, "notion", decision.Provider)
+		assert.Contains(t, decision.Reason, "strategy round_robin is not supported")
+		assert.Empty(t, decision.Rules)
+
+		decision = EvaluateRouting(nil, task, nil, "")
+		assert.Empty(t, decision.Provider)
+		assert.Equal(t, "no rule matched and there is no default provider", decision.Reason)
+	})
+}
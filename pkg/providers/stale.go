@@ -0,0 +1,108 @@
+package providers
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// StaleLabel is the label stale tasks are tagged with
+const StaleLabel = "stale"
+
+var ageValue = regexp.MustCompile(`^(\d+)([hdw])$`)
+
+// ParseAge parses a task age such as 30d, 2w or 12h
+func ParseAge(value string) (time.Duration, error) {
+	match := ageValue.FindStringSubmatch(strings.ToLower(strings.TrimSpace(value)))
+	if match == nil {
+		return 0, NewValidationError(fmt.Sprintf("invalid age %q, use hours, days or weeks like 12h, 30d or 2w", value), nil)
+	}
+	n, err := strconv.Atoi(match[1])
+	if err != nil || n <= 0 {
+		return 0, NewValidationError(fmt.Sprintf("invalid age %q, use a positive number of hours, days or weeks", value), nil)
+	}
+
+	unit := time.Hour
+	switch match[2] {
+	case "d":
+		unit = 24 * time.Hour
+	case "w":
+		unit = 7 * 24 * time.Hour
+	}
+	return time.Duration(n) * unit, nil
+}
+
+// StaleTask is an open task nobody has touched for a while
+type StaleTask struct {
+	Task        *UniversalTask `json:"-"`
+	TaskID      string         `json:"taskId"`
+	Title       string         `json:"title"`
+	Status      string         `json:"status"`
+	LastUpdated time.Time      `json:"lastUpdated"`
+	IdleDays    int            `json:"idleDays"`
+}
+
+// StaleGroup holds the stale tasks of one assignee
+type StaleGroup struct {
+	Assignee string      `json:"assignee"`
+	Tasks    []StaleTask `json:"tasks"`
+}
+
+// IdleTime returns how long a task has gone without an update. Tasks whose
+// provider reports no update time count from their creation, like GetAge.
+func IdleTime(task *UniversalTask, now time.Time) time.Duration {
+	last := task.UpdatedAt
+	if last.IsZero() || last.Before(task.CreatedAt) {
+		last = task.CreatedAt
+	}
+	if last.IsZero() {
+		return 0
+	}
+	return now.Sub(last)
+}
+
+// FindStaleTasks returns the open tasks not updated for longer than olderThan,
+// grouped by assignee. Assignees with the most stale tasks come first, and
+// within a group the tasks idle the longest. Tasks without an update or
+// creation time are skipped, as their age is unknown.
+func FindStaleTasks(tasks []*UniversalTask, olderThan time.Duration, now time.Time) []StaleGroup {
+	groups := make(map[string][]StaleTask)
+	for _, task := range tasks {
+		if task == nil || task.IsCompleted() {
+			continue
+		}
+		idle := IdleTime(task, now)
+		if idle <= olderThan {
+			continue
+		}
+
+		assignee := task.AssigneeID
+		if assignee == "" {
+			assignee = UnassignedKey
+		}
+		groups[assignee] = append(groups[assignee], StaleTask{
+			Task:        task,
+			TaskID:      task.GetDisplayID(),
+			Title:       task.Title,
+			Status:      task.Status.Name,
+			LastUpdated: now.Add(-idle),
+			IdleDays:    int(idle / (24 * time.Hour)),
+		})
+	}
+
+	result := make([]StaleGroup, 0, len(groups))
+	for assignee, stale := range groups {
+		sort.SliceStable(stale, func(i, j int) bool { return stale[i].LastUpdated.Before(stale[j].LastUpdated) })
+		result = append(result, StaleGroup{Assignee: assignee, Tasks: stale})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if len(result[i].Tasks) != len(result[j].Tasks) {
+			return len(result[i].Tasks) > len(result[j].Tasks)
+		}
+		return result[i].Assignee < result[j].Assignee
+	})
+	return result
+}
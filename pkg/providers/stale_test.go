@@ -0,0 +1,58 @@
+package providers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAge(t *testing.T) {
+	tests := map[string]time.Duration{
+		"12h": 12 * time.Hour,
+		"30d": 30 * 24 * time.Hour,
+		"2W":  14 * 24 * time.Hour,
+	}
+	for value, want := range tests {
+		got, err := ParseAge(value)
+		require.NoError(t, err, value)
+		assert.Equal(t, want, got, value)
+	}
+
+	for _, value := range []string{"", "30", "0d", "1m", "-3d"} {
+		_, err := ParseAge(value)
+		assert.True(t, IsErrorType(err, ErrorTypeValidation), value)
+	}
+}
+
+func TestFindStaleTasks(t *testing.T) {
+	now := time.Date(2024, 6, 30, 12, 0, 0, 0, time.UTC)
+	daysAgo := func(days int) time.Time { return now.AddDate(0, 0, -days) }
+
+	tasks := []*UniversalTask{
+		{Key: "OPS-1", AssigneeID: "alice", CreatedAt: daysAgo(90), UpdatedAt: daysAgo(40)},
+		{Key: "OPS-2", AssigneeID: "alice", CreatedAt: daysAgo(90), UpdatedAt: daysAgo(60)},
+		{Key: "OPS-3", AssigneeID: "alice", CreatedAt: daysAgo(90), UpdatedAt: daysAgo(5)},
+		{Key: "OPS-4", AssigneeID: "bob", CreatedAt: daysAgo(45)},
+		{Key: "OPS-5", CreatedAt: daysAgo(100), UpdatedAt: daysAgo(31)},
+		{Key: "OPS-6", AssigneeID: "bob", CreatedAt: daysAgo(90), UpdatedAt: daysAgo(80), Status: TaskStatus{Name: "Done", Category: StatusCategoryDone}},
+		{Key: "OPS-7", AssigneeID: "bob"},
+		nil,
+	}
+
+	groups := FindStaleTasks(tasks, 30*24*time.Hour, now)
+	require.Len(t, groups, 3)
+
+	assert.Equal(t, "alice", groups[0].Assignee)
+	require.Len(t, groups[0].Tasks, 2)
+	assert.Equal(t, "OPS-2", groups[0].Tasks[0].TaskID, "idle the longest first")
+	assert.Equal(t, 60, groups[0].Tasks[0].IdleDays)
+	assert.True(t, daysAgo(60).Equal(groups[0].Tasks[0].LastUpdated))
+
+	assert.Equal(t, "bob", groups[1].Assignee)
+	assert.Equal(t, "OPS-4", groups[1].Tasks[0].TaskID, "counts from creation without updates")
+	assert.Equal(t, UnassignedKey, groups[2].Assignee)
+
+	assert.Empty(t, FindStaleTasks(tasks, 365*24*time.Hour, now))
+}
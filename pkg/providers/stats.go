@@ -0,0 +1,135 @@
+package providers
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// UnassignedKey is the assignee bucket used for tasks without an assignee
+const UnassignedKey = "unassigned"
+
+// TaskStats is a quick summary of a set of tasks
+type TaskStats struct {
+	Total      int            `json:"total"`
+	Completed  int            `json:"completed"`
+	Overdue    int            `json:"overdue"`
+	Blocked    int            `json:"blocked"`
+	ByStatus   map[string]int `json:"byStatus"`
+	ByPriority map[string]int `json:"byPriority"`
+	ByType     map[string]int `json:"byType"`
+	ByAssignee map[string]int `json:"byAssignee"`
+	ByProvider map[string]int `json:"byProvider"`
+
+	// StoryPoints sums the story points of estimated tasks, see FieldMapping
+	StoryPoints          float64 `json:"storyPoints,omitempty"`
+	CompletedStoryPoints float64 `json:"completedStoryPoints,omitempty"`
+
+	// OverdueTasks and BlockedTasks hold display IDs of the flagged tasks
+	OverdueTasks []string `json:"overdueTasks,omitempty"`
+	BlockedTasks []string `json:"blockedTasks,omitempty"`
+}
+
+// CountEntry is a single key/count pair of a TaskStats breakdown
+type CountEntry struct {
+	Key   string
+	Count int
+}
+
+// ComputeTaskStats counts tasks by status, priority, type, assignee and provider
+// and flags overdue and blocked tasks
+func ComputeTaskStats(tasks []*UniversalTask) *TaskStats {
+	stats := &TaskStats{
+		ByStatus:   make(map[string]int),
+		ByPriority: make(map[string]int),
+		ByType:     make(map[string]int),
+		ByAssignee: make(map[string]int),
+		ByProvider: make(map[string]int),
+	}
+
+	for _, task := range tasks {
+		if task == nil {
+			continue
+		}
+
+		stats.Total++
+		stats.ByStatus[task.Status.Name]++
+		stats.ByPriority[string(task.Priority)]++
+		stats.ByType[string(task.Type)]++
+		if task.ProviderName != "" {
+			stats.ByProvider[task.ProviderName]++
+		}
+
+		assignee := task.AssigneeID
+		if assignee == "" {
+			assignee = UnassignedKey
+		}
+		stats.ByAssignee[assignee]++
+
+		if task.IsCompleted() {
+			stats.Completed++
+		}
+		if task.StoryPoints != nil {
+			stats.StoryPoints += *task.StoryPoints
+			if task.IsCompleted() {
+				stats.CompletedStoryPoints += *task.StoryPoints
+			}
+		}
+		if task.IsOverdue() {
+			stats.Overdue++
+			stats.OverdueTasks = append(stats.OverdueTasks, task.GetDisplayID())
+		}
+		if task.IsBlocked() {
+			stats.Blocked++
+			stats.BlockedTasks = append(stats.BlockedTasks, task.GetDisplayID())
+		}
+	}
+
+	return stats
+}
+
+// SortedCounts returns the entries of a breakdown ordered by count, then key
+func SortedCounts(counts map[string]int) []CountEntry {
+	entries := make([]CountEntry, 0, len(counts))
+	for key, count := range counts {
+		entries = append(entries, CountEntry{Key: key, Count: count})
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Count != entries[j].Count {
+			return entries[i].Count > entries[j].Count
+		}
+		return entries[i].Key < entries[j].Key
+	})
+	return entries
+}
+
+// FormatTaskSummary renders the status, priority and provider breakdowns of
+// tasks, the summary output of task lists in the CLI and the MCP tools
+func FormatTaskSummary(tasks []*UniversalTask) string {
+	stats := ComputeTaskStats(tasks)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "📋 Task Summary (%d total)\n", stats.Total)
+
+	sections := []struct {
+		title  string
+		counts map[string]int
+	}{
+		{"By Status", stats.ByStatus},
+		{"By Priority", stats.ByPriority},
+		{"By Provider", stats.ByProvider},
+	}
+	for _, section := range sections {
+		fmt.Fprintf(&b, "\n%s:\n", section.title)
+		for _, entry := range SortedCounts(section.counts) {
+			key := entry.Key
+			if key == "" {
+				key = "(none)"
+			}
+			fmt.Fprintf(&b, "  %s: %d\n", key, entry.Count)
+		}
+	}
+
+	return b.String()
+}
@@ -0,0 +1,81 @@
+package providers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestComputeTaskStats(t *testing.T) {
+	yesterday := time.Now().Add(-24 * time.Hour)
+	tomorrow := time.Now().Add(24 * time.Hour)
+
+	tasks := []*UniversalTask{
+		{Key: "PROJ-1", Type: TaskTypeBug, Priority: TaskPriorityHigh, AssigneeID: "alice", ProviderName: "youtrack",
+			Status: TaskStatus{Name: "Open", Category: StatusCategoryTodo}, DueDate: &yesterday},
+		{Key: "PROJ-2", Type: TaskTypeTask, Priority: TaskPriorityMedium, AssigneeID: "alice", ProviderName: "youtrack",
+			Status: TaskStatus{Name: "Done", Category: StatusCategoryDone}, DueDate: &yesterday},
+		{Key: "PROJ-3", Type: TaskTypeTask, Priority: TaskPriorityMedium, ProviderName: "youtrack",
+			Status: TaskStatus{Name: "Open", Category: StatusCategoryTodo}, BlockedBy: []string{"PROJ-1"}, DueDate: &tomorrow},
+		{Key: "PROJ-4", Type: TaskTypeFeature, Priority: TaskPriorityLow, AssigneeID: "bob", ProviderName: "jira",
+			Status: TaskStatus{Name: "Blocked", Category: StatusCategoryBlocked}},
+		nil,
+	}
+
+	stats := ComputeTaskStats(tasks)
+
+	assert.Equal(t, 4, stats.Total)
+	assert.Equal(t, 1, stats.Completed)
+	assert.Equal(t, map[string]int{"Open": 2, "Done": 1, "Blocked": 1}, stats.ByStatus)
+	assert.Equal(t, map[string]int{"high": 1, "medium": 2, "low": 1}, stats.ByPriority)
+	assert.Equal(t, map[string]int{"bug": 1, "task": 2, "feature": 1}, stats.ByType)
+	assert.Equal(t, map[string]int{"alice": 2, "bob": 1, UnassignedKey: 1}, stats.ByAssignee)
+	assert.Equal(t, map[string]int{"youtrack": 3, "jira": 1}, stats.ByProvider)
+
+	// Completed tasks past their due date are not overdue
+	assert.Equal(t, 1, stats.Overdue)
+	assert.Equal(t, []string{"PROJ-1"}, stats.OverdueTasks)
+	assert.Equal(t, 2, stats.Blocked)
+	assert.Equal(t, []string{"PROJ-3", "PROJ-4"}, stats.BlockedTasks)
+	assert.Zero(t, stats.StoryPoints)
+
+	three, five := 3.0, 5.0
+	tasks[0].StoryPoints = &three
+	tasks[1].StoryPoints = &five
+	stats = ComputeTaskStats(tasks)
+	assert.Equal(t, 8.0, stats.StoryPoints)
+	assert.Equal(t, 5.0, stats.CompletedStoryPoints)
+}
+
+func TestSortedCounts(t *testing.T) {
+	entries := SortedCounts(map[string]int{"b": 1, "a": 1, "c": 3})
+
+	assert.Equal(t, []CountEntry{{Key: "c", Count: 3}, {Key: "a", Count: 1}, {Key: "b", Count: 1}}, entries)
+}
+
+func TestFormatTaskSummary(t *testing.T) {
+	tasks := []*UniversalTask{
+		{Status: TaskStatus{Name: "Open"}, Priority: TaskPriorityHigh, ProviderName: "youtrack"},
+		{Status: TaskStatus{Name: "Open"}, Priority: TaskPriorityLow, ProviderName: "jira"},
+		{Status: TaskStatus{Name: "Done"}, Priority: TaskPriorityHigh, ProviderName: "youtrack"},
+		{ProviderName: "jira"},
+	}
+
+	assert.Equal(t, `📋 Task Summary (4 total)
+
+By Status:
+  Open: 2
+  (none): 1
+  Done: 1
+
+By Priority:
+  high: 2
+  (none): 1
+  low: 1
+
+By Provider:
+  jira: 2
+  youtrack: 2
+`, FormatTaskSummary(tasks))
+}
@@ -0,0 +1,79 @@
+package providers
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// StatusCategories lists the known status categories
+var StatusCategories = []StatusCategory{
+	StatusCategoryTodo,
+	StatusCategoryInProgress,
+	StatusCategoryReview,
+	StatusCategoryTesting,
+	StatusCategoryBlocked,
+	StatusCategoryDone,
+	StatusCategoryCancelled,
+}
+
+// IsValid reports whether the category is one of the known status categories
+func (c StatusCategory) IsValid() bool {
+	for _, category := range StatusCategories {
+		if c == category {
+			return true
+		}
+	}
+	return false
+}
+
+// IsFinal reports whether tasks in this category are finished
+func (c StatusCategory) IsFinal() bool {
+	return c == StatusCategoryDone || c == StatusCategoryCancelled
+}
+
+// StatusMapping maps raw provider status names to status categories, for
+// custom workflow states the built-in heuristics can't categorize. Status names
+// are compared case-insensitively.
+type StatusMapping map[string]StatusCategory
+
+// Lookup returns the category configured for a raw status name
+func (m StatusMapping) Lookup(statusName string) (StatusCategory, bool) {
+	if len(m) == 0 {
+		return "", false
+	}
+	if category, ok := m[statusName]; ok {
+		return category, true
+	}
+
+	statusName = strings.TrimSpace(statusName)
+	for name, category := range m {
+		if strings.EqualFold(strings.TrimSpace(name), statusName) {
+			return category, true
+		}
+	}
+	return "", false
+}
+
+// Validate checks that every status is mapped to a known category
+func (m StatusMapping) Validate() error {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if strings.TrimSpace(name) == "" {
+			return NewValidationError("status mapping contains an empty status name", nil)
+		}
+		if category := m[name]; !category.IsValid() {
+			known := make([]string, len(StatusCategories))
+			for i, c := range StatusCategories {
+				known[i] = string(c)
+			}
+			return NewValidationError(fmt.Sprintf("status %q is mapped to unknown category %q, use one of %s", name, category, strings.Join(known, ", ")), nil)
+		}
+	}
+	return nil
+}
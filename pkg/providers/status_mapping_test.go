@@ -0,0 +1,46 @@
+package providers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatusMapping(t *testing.T) {
+	mapping := StatusMapping{
+		"QA Sign-off": StatusCategoryReview,
+		"staged":      StatusCategoryDone,
+	}
+
+	t.Run("Lookup ignores case and whitespace", func(t *testing.T) {
+		category, ok := mapping.Lookup("QA Sign-off")
+		assert.True(t, ok)
+		assert.Equal(t, StatusCategoryReview, category)
+
+		category, ok = mapping.Lookup(" Staged ")
+		assert.True(t, ok)
+		assert.Equal(t, StatusCategoryDone, category)
+
+		_, ok = mapping.Lookup("Open")
+		assert.False(t, ok)
+
+		_, ok = StatusMapping(nil).Lookup("Open")
+		assert.False(t, ok)
+	})
+
+	t.Run("Validate", func(t *testing.T) {
+		assert.NoError(t, mapping.Validate())
+		assert.NoError(t, StatusMapping(nil).Validate())
+
+		err := StatusMapping{"QA Sign-off": "qa"}.Validate()
+		assert.True(t, IsErrorType(err, ErrorTypeValidation))
+		assert.Contains(t, err.Error(), `unknown category "qa"`)
+
+		config := &ProviderConfig{Name: "yt", Type: ProviderTypeYouTrack, AuthType: AuthTypeBearer, Token: "x"}
+		config.StatusMapping = StatusMapping{" ": StatusCategoryDone}
+		assert.Error(t, config.Validate())
+	})
+
+	assert.True(t, StatusCategoryCancelled.IsFinal())
+	assert.False(t, StatusCategoryReview.IsFinal())
+}
@@ -0,0 +1,146 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+)
+
+// DefaultStreamPageSize is how many tasks StreamTasks asks a provider for at once
+const DefaultStreamPageSize = 100
+
+// StreamTasks lists tasks from the target providers like FetchTasks, but
+// hands every task to emit as soon as its page arrives instead of collecting
+// them, so memory stays flat however many tasks there are. Each provider is
+// paged through pageSize tasks at a time until it runs out or filters.Limit
+// of its tasks were listed; a zero limit lists all of them. Tasks come in
+// arrival order, not sorted. emit is never called concurrently. If it fails,
+// e.g. because the reader went away, streaming stops and its error is
+// returned; FetchResult.Tasks is always empty.
+func StreamTasks(ctx context.Context, targets []string, lookup ProviderLookup, filters *TaskFilters, parallelism, pageSize int, emit func(*UniversalTask) error) (*FetchResult, error) {
+	if parallelism <= 0 {
+		parallelism = DefaultFetchParallelism
+	}
+	if pageSize <= 0 {
+		pageSize = DefaultStreamPageSize
+	}
+	if filters == nil {
+		filters = &TaskFilters{}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		result  = &FetchResult{}
+		emitErr error
+		slots   = make(chan struct{}, parallelism)
+	)
+
+	// send emits a page of tasks, reporting false once streaming has stopped
+	send := func(tasks []*UniversalTask) bool {
+		mu.Lock()
+		defer mu.Unlock()
+		for _, task := range tasks {
+			if emitErr != nil {
+				return false
+			}
+			if err := emit(task); err != nil {
+				emitErr = err
+				cancel()
+				return false
+			}
+		}
+		return emitErr == nil
+	}
+
+	for _, name := range targets {
+		wg.Add(1)
+		go func(name string) {
+			defer wg.Done()
+
+			select {
+			case slots <- struct{}{}:
+				defer func() { <-slots }()
+			case <-ctx.Done():
+				mu.Lock()
+				result.Failures = append(result.Failures, ProviderFailure{Provider: name, Err: ctx.Err()})
+				mu.Unlock()
+				return
+			}
+
+			if err := streamProviderTasks(ctx, name, lookup, filters, pageSize, send); err != nil {
+				mu.Lock()
+				result.Failures = append(result.Failures, ProviderFailure{Provider: name, Err: err})
+				mu.Unlock()
+			}
+		}(name)
+	}
+	wg.Wait()
+
+	if emitErr != nil {
+		return result, emitErr
+	}
+
+	result.TimedOut = len(result.Failures) > 0 && errors.Is(ctx.Err(), context.DeadlineExceeded)
+	sort.Slice(result.Failures, func(i, j int) bool {
+		return result.Failures[i].Provider < result.Failures[j].Provider
+	})
+	return result, nil
+}
+
+func streamProviderTasks(ctx context.Context, name string, lookup ProviderLookup, filters *TaskFilters, pageSize int, send func([]*UniversalTask) bool) error {
+	provider, err := lookup(name)
+	if err != nil {
+		return err
+	}
+
+	// "me" is a different user in every provider
+	providerFilters, err := ResolveAssigneeFilter(ctx, provider, filters.ForProvider(name))
+	if err != nil {
+		return err
+	}
+
+	limit := filters.Limit
+	listed := 0
+	firstID := ""
+	for limit <= 0 || listed < limit {
+		page := *providerFilters
+		page.Offset = filters.Offset + listed
+		page.Limit = pageSize
+		if limit > 0 && limit-listed < pageSize {
+			page.Limit = limit - listed
+		}
+
+		tasks, err := provider.ListTasks(ctx, &page)
+		if err != nil {
+			return err
+		}
+		if len(tasks) == 0 {
+			return nil
+		}
+		// A provider that ignores the offset would return its first page forever
+		if listed > 0 && tasks[0].GetDisplayID() == firstID {
+			return nil
+		}
+		if listed == 0 {
+			firstID = tasks[0].GetDisplayID()
+		}
+
+		for _, task := range tasks {
+			task.ProviderName = name
+		}
+		if !send(filters.FilterByDueDate(filters.FilterByLabels(tasks))) {
+			return nil
+		}
+
+		listed += len(tasks)
+		if len(tasks) < page.Limit {
+			return nil
+		}
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingPagedProvider records the limit and offset of each page it lists
+type countingPagedProvider struct {
+	pagedProvider
+	pages []string
+}
+
+func (p *countingPagedProvider) ListTasks(ctx context.Context, filters *TaskFilters) ([]*UniversalTask, error) {
+	p.pages = append(p.pages, fmt.Sprintf("%d+%d", filters.Offset, filters.Limit))
+	return p.pagedProvider.ListTasks(ctx, filters)
+}
+
+func TestStreamTasks(t *testing.T) {
+	keys := func(prefix string, n int) []string {
+		var keys []string
+		for i := 1; i <= n; i++ {
+			keys = append(keys, fmt.Sprintf("%s-%d", prefix, i))
+		}
+		return keys
+	}
+
+	t.Run("Pages through every provider", func(t *testing.T) {
+		youtrack := &countingPagedProvider{pagedProvider: pagedProvider{keys: keys("YT", 250)}}
+		lookup := providerLookup(map[string]TaskProvider{
+			"youtrack": youtrack,
+			"jira":     &pagedProvider{keys: keys("JR", 3)},
+			"notion":   &slowListProvider{err: errors.New("unauthorized")},
+		})
+
+		var streamed []string
+		result, err := StreamTasks(context.Background(), []string{"youtrack", "jira", "notion"}, lookup, &TaskFilters{}, 2, 100, func(task *UniversalTask) error {
+			streamed = append(streamed, task.ProviderName+"/"+task.Key)
+			return nil
+		})
+		require.NoError(t, err)
+
+		assert.Len(t, streamed, 253)
+		assert.Empty(t, result.Tasks)
+		assert.Equal(t, []string{"0+100", "100+100", "200+100"}, youtrack.pages)
+		require.Len(t, result.Failures, 1)
+		assert.Equal(t, "notion", result.Failures[0].Provider)
+
+		sort.Strings(streamed)
+		assert.Equal(t, "jira/JR-1", streamed[0])
+	})
+
+	t.Run("Limit applies per provider", func(t *testing.T) {
+		youtrack := &countingPagedProvider{pagedProvider: pagedProvider{keys: keys("YT", 250)}}
+		lookup := providerLookup(map[string]TaskProvider{"youtrack": youtrack})
+
+		count := 0
+		_, err := StreamTasks(context.Background(), []string{"youtrack"}, lookup, &TaskFilters{Limit: 150}, 0, 100, func(task *UniversalTask) error {
+			count++
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 150, count)
+		assert.Equal(t, []string{"0+100", "100+50"}, youtrack.pages)
+	})
+
+	t.Run("Emit errors stop streaming", func(t *testing.T) {
+		youtrack := &countingPagedProvider{pagedProvider: pagedProvider{keys: keys("YT", 250)}}
+		lookup := providerLookup(map[string]TaskProvider{"youtrack": youtrack})
+
+		broken := errors.New("broken pipe")
+		count := 0
+		_, err := StreamTasks(context.Background(), []string{"youtrack"}, lookup, &TaskFilters{}, 0, 100, func(task *UniversalTask) error {
+			count++
+			if count == 5 {
+				return broken
+			}
+			return nil
+		})
+		assert.ErrorIs(t, err, broken)
+		assert.Equal(t, 5, count)
+		assert.Len(t, youtrack.pages, 1)
+	})
+
+	t.Run("Providers ignoring the offset are read once", func(t *testing.T) {
+		lookup := providerLookup(map[string]TaskProvider{
+			"static": &slowListProvider{keys: keys("ST", 100)},
+		})
+
+		count := 0
+		_, err := StreamTasks(context.Background(), []string{"static"}, lookup, &TaskFilters{}, 0, 100, func(task *UniversalTask) error {
+			count++
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, 100, count)
+	})
+}
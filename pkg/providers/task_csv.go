@@ -0,0 +1,225 @@
+package providers
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// CSVIgnoreColumn is the mapping target of CSV columns that should be skipped
+const CSVIgnoreColumn = "-"
+
+// csvTaskFields sets a task field from a CSV cell. Cells are never empty.
+var csvTaskFields = map[string]func(task *UniversalTask, value string) error{
+	"id":          func(task *UniversalTask, value string) error { task.ID = value; return nil },
+	"title":       func(task *UniversalTask, value string) error { task.Title = value; return nil },
+	"description": func(task *UniversalTask, value string) error { task.Description = value; return nil },
+	"project":     func(task *UniversalTask, value string) error { task.ProjectID = value; return nil },
+	"assignee":    func(task *UniversalTask, value string) error { task.AssigneeID = value; return nil },
+	"reporter":    func(task *UniversalTask, value string) error { task.ReporterID = value; return nil },
+	"parent":      func(task *UniversalTask, value string) error { task.ParentID = value; return nil },
+	"epic":        func(task *UniversalTask, value string) error { task.EpicID = value; return nil },
+	"status": func(task *UniversalTask, value string) error {
+		task.Status = TaskStatus{ID: strings.ToLower(strings.ReplaceAll(value, " ", "_")), Name: value}
+		return nil
+	},
+	"priority": func(task *UniversalTask, value string) error {
+		priority := TaskPriority(strings.ToLower(value))
+		if !priority.IsValid() {
+			return fmt.Errorf("unknown priority %q, use lowest, low, medium, high, highest or critical", value)
+		}
+		task.Priority = priority
+		return nil
+	},
+	"type": func(task *UniversalTask, value string) error {
+		taskType := TaskType(strings.ToLower(value))
+		if !taskType.IsValid() {
+			return fmt.Errorf("unknown type %q, use task, story, bug, epic, subtask, feature, improvement, spike, research or chore", value)
+		}
+		task.Type = taskType
+		return nil
+	},
+	"labels": func(task *UniversalTask, value string) error {
+		for _, label := range strings.Split(value, ",") {
+			if label = strings.TrimSpace(label); label != "" {
+				task.Labels = append(task.Labels, label)
+			}
+		}
+		return nil
+	},
+	"blockedBy": func(task *UniversalTask, value string) error {
+		task.BlockedBy = mergeIDs(task.BlockedBy, strings.Split(value, ","))
+		return nil
+	},
+	"dueDate": func(task *UniversalTask, value string) error {
+		date, err := parseCSVDate(value)
+		task.DueDate = date
+		return err
+	},
+	"startDate": func(task *UniversalTask, value string) error {
+		date, err := parseCSVDate(value)
+		task.StartDate = date
+		return err
+	},
+	"estimate": func(task *UniversalTask, value string) error {
+		estimate, err := time.ParseDuration(value)
+		if err != nil || estimate < 0 {
+			return fmt.Errorf("invalid estimate %q, use a duration like 4h or 90m", value)
+		}
+		task.EstimatedTime = &estimate
+		return nil
+	},
+}
+
+// CSVTaskFields lists the task fields CSV columns can be mapped to
+func CSVTaskFields() []string {
+	return []string{"id", "title", "description", "project", "type", "priority", "status", "assignee",
+		"reporter", "labels", "parent", "blockedBy", "epic", "dueDate", "startDate", "estimate"}
+}
+
+func parseCSVDate(value string) (*time.Time, error) {
+	for _, layout := range []string{"2006-01-02", time.RFC3339} {
+		if date, err := time.Parse(layout, value); err == nil {
+			return &date, nil
+		}
+	}
+	return nil, fmt.Errorf("invalid date %q, use YYYY-MM-DD", value)
+}
+
+// ParseTasksCSV reads tasks from CSV with a header row. Columns are named
+// after task fields (see CSVTaskFields, compared case-insensitively); mapping
+// maps other column names to fields, or to "-" to skip a column. Labels are
+// comma-separated within their cell. Separators are detected from the header,
+// so semicolon-separated spreadsheet exports work too. All rows are checked
+// and every invalid cell is reported.
+func ParseTasksCSV(r io.Reader, mapping map[string]string) ([]*UniversalTask, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV: %w", err)
+	}
+	text := strings.TrimPrefix(string(data), "\ufeff") // Byte order mark of spreadsheet exports
+
+	reader := csv.NewReader(strings.NewReader(text))
+	reader.Comma = detectCSVSeparator(text)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return []*UniversalTask{}, nil
+	}
+	if err != nil {
+		return nil, NewValidationError(fmt.Sprintf("invalid CSV: %v", err), nil)
+	}
+
+	fields, err := csvColumnFields(header, mapping)
+	if err != nil {
+		return nil, err
+	}
+
+	tasks := []*UniversalTask{}
+	var problems []string
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) && errors.Is(parseErr.Err, csv.ErrFieldCount) {
+				problems = append(problems, fmt.Sprintf("line %d: expected %d columns, got %d", parseErr.StartLine, len(header), len(record)))
+				continue
+			}
+			return nil, NewValidationError(fmt.Sprintf("invalid CSV: %v", err), nil)
+		}
+
+		line, _ := reader.FieldPos(0)
+		task := &UniversalTask{}
+		for i, value := range record {
+			value = strings.TrimSpace(value)
+			if fields[i] == "" || value == "" {
+				continue
+			}
+			if err := csvTaskFields[fields[i]](task, value); err != nil {
+				problems = append(problems, fmt.Sprintf("line %d, column %q: %v", line, header[i], err))
+			}
+		}
+		if task.Title == "" {
+			problems = append(problems, fmt.Sprintf("line %d: title is empty", line))
+			continue
+		}
+		tasks = append(tasks, task)
+	}
+
+	if len(problems) > 0 {
+		return nil, NewValidationError("invalid CSV rows:\n  "+strings.Join(problems, "\n  "), nil)
+	}
+	return tasks, nil
+}
+
+// csvColumnFields returns the task field of each column, or "" for skipped columns
+func csvColumnFields(header []string, mapping map[string]string) ([]string, error) {
+	known := make(map[string]string)
+	for _, field := range CSVTaskFields() {
+		known[strings.ToLower(field)] = field
+	}
+
+	columnMapping := make(map[string]string, len(mapping))
+	for column, field := range mapping {
+		if _, ok := known[strings.ToLower(field)]; !ok && field != CSVIgnoreColumn {
+			return nil, NewValidationError(fmt.Sprintf("column %q is mapped to unknown field %q, use one of %s or %s to skip it",
+				column, field, strings.Join(CSVTaskFields(), ", "), CSVIgnoreColumn), nil)
+		}
+		columnMapping[strings.ToLower(strings.TrimSpace(column))] = field
+	}
+
+	fields := make([]string, len(header))
+	seen := make(map[string]string)
+	hasTitle := false
+	for i, column := range header {
+		name := strings.TrimSpace(column)
+		target, mapped := columnMapping[strings.ToLower(name)]
+		if !mapped {
+			target = name
+		}
+		if target == CSVIgnoreColumn {
+			continue
+		}
+
+		field, ok := known[strings.ToLower(target)]
+		if !ok {
+			return nil, NewValidationError(fmt.Sprintf("unknown CSV column %q, map it to one of %s or to %s to skip it",
+				name, strings.Join(CSVTaskFields(), ", "), CSVIgnoreColumn), nil)
+		}
+		if previous, ok := seen[field]; ok {
+			return nil, NewValidationError(fmt.Sprintf("CSV columns %q and %q both set %s", previous, name, field), nil)
+		}
+		seen[field] = name
+		fields[i] = field
+		hasTitle = hasTitle || field == "title"
+	}
+
+	if !hasTitle {
+		return nil, NewValidationError("CSV has no title column", nil)
+	}
+	return fields, nil
+}
+
+// detectCSVSeparator picks the separator that splits the header line into
+// the most columns
+func detectCSVSeparator(text string) rune {
+	header := text
+	if i := strings.IndexByte(text, '\n'); i >= 0 {
+		header = text[:i]
+	}
+
+	separator, most := ',', strings.Count(header, ",")
+	for _, candidate := range []rune{';', '\t'} {
+		if count := strings.Count(header, string(candidate)); count > most {
+			separator, most = candidate, count
+		}
+	}
+	return separator
+}
@@ -0,0 +1,103 @@
+package providers
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTasksCSV(t *testing.T) {
+	t.Run("Header names task fields", func(t *testing.T) {
+		csv := "Title,Description,Project,Type,Priority,Status,Assignee,Labels,DueDate,Estimate\n" +
+			`Rotate certificates,"Before they expire, see wiki",OPS,chore,High,In Progress,alice,"security, infra",2026-11-01,4h` + "\n" +
+			"Check backups,,,,,,,,,\n"
+
+		tasks, err := ParseTasksCSV(strings.NewReader(csv), nil)
+		require.NoError(t, err)
+		require.Len(t, tasks, 2)
+
+		due := time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)
+		estimate := 4 * time.Hour
+		assert.Equal(t, &UniversalTask{
+			Title:         "Rotate certificates",
+			Description:   "Before they expire, see wiki",
+			ProjectID:     "OPS",
+			Type:          TaskTypeChore,
+			Priority:      TaskPriorityHigh,
+			Status:        TaskStatus{ID: "in_progress", Name: "In Progress"},
+			AssigneeID:    "alice",
+			Labels:        []string{"security", "infra"},
+			DueDate:       &due,
+			EstimatedTime: &estimate,
+		}, tasks[0])
+		assert.Equal(t, &UniversalTask{Title: "Check backups"}, tasks[1])
+	})
+
+	t.Run("Mapping and semicolons", func(t *testing.T) {
+		csv := "\ufeffSummary;Owner;Notes\nRotate certificates;bob;ignored\n"
+
+		tasks, err := ParseTasksCSV(strings.NewReader(csv), map[string]string{"summary": "title", "Owner": "assignee", "Notes": "-"})
+		require.NoError(t, err)
+		require.Len(t, tasks, 1)
+		assert.Equal(t, "Rotate certificates", tasks[0].Title)
+		assert.Equal(t, "bob", tasks[0].AssigneeID)
+	})
+
+	t.Run("Temporary IDs and blockers", func(t *testing.T) {
+		csv := "id,title,parent,blockedBy\n$tmp:epic,Checkout,,\n,Payment form,$tmp:epic,\"$tmp:epic, OPS-3\"\n"
+
+		tasks, err := ParseTasksCSV(strings.NewReader(csv), nil)
+		require.NoError(t, err)
+		require.Len(t, tasks, 2)
+		assert.Equal(t, "$tmp:epic", tasks[0].ID)
+		assert.Equal(t, "$tmp:epic", tasks[1].ParentID)
+		assert.Equal(t, []string{"$tmp:epic", "OPS-3"}, tasks[1].BlockedBy)
+	})
+
+	t.Run("Invalid columns", func(t *testing.T) {
+		_, err := ParseTasksCSV(strings.NewReader("Summary,Owner\n"), nil)
+		assert.True(t, IsErrorType(err, ErrorTypeValidation))
+		assert.Contains(t, err.Error(), `unknown CSV column "Summary"`)
+
+		_, err = ParseTasksCSV(strings.NewReader("Title\n"), map[string]string{"Title": "name"})
+		assert.Contains(t, err.Error(), `unknown field "name"`)
+
+		_, err = ParseTasksCSV(strings.NewReader("Title,Summary\n"), map[string]string{"Summary": "title"})
+		assert.Contains(t, err.Error(), "both set title")
+
+		_, err = ParseTasksCSV(strings.NewReader("Assignee\nalice\n"), nil)
+		assert.Contains(t, err.Error(), "no title column")
+	})
+
+	t.Run("Every invalid cell is reported", func(t *testing.T) {
+		csv := "title,priority,type,dueDate,estimate\n" +
+			"A,urgent,task,,\n" +
+			"B,low,ticket,01/11/2026,\n" +
+			",low,,,\n" +
+			"C,low,task,,soon\n" +
+			"D,low\n"
+
+		_, err := ParseTasksCSV(strings.NewReader(csv), nil)
+		require.Error(t, err)
+		assert.True(t, IsErrorType(err, ErrorTypeValidation))
+		for _, problem := range []string{
+			`line 2, column "priority": unknown priority "urgent"`,
+			`line 3, column "type": unknown type "ticket"`,
+			`line 3, column "dueDate": invalid date "01/11/2026"`,
+			"line 4: title is empty",
+			`line 5, column "estimate": invalid estimate "soon"`,
+			"line 6: expected 5 columns, got 2",
+		} {
+			assert.Contains(t, err.Error(), problem)
+		}
+	})
+
+	t.Run("Empty file", func(t *testing.T) {
+		tasks, err := ParseTasksCSV(strings.NewReader(""), nil)
+		require.NoError(t, err)
+		assert.Empty(t, tasks)
+	})
+}
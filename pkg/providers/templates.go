@@ -0,0 +1,270 @@
+package providers
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+)
+
+// TaskTemplate pre-fills fields of new tasks
+type TaskTemplate struct {
+	Name        string       `json:"name" yaml:"name"`
+	Summary     string       `json:"summary,omitempty" yaml:"summary,omitempty"`
+	TitlePrefix string       `json:"titlePrefix,omitempty" yaml:"titlePrefix,omitempty"`
+	Description string       `json:"description,omitempty" yaml:"description,omitempty"`
+	Type        TaskType     `json:"type,omitempty" yaml:"type,omitempty"`
+	Priority    TaskPriority `json:"priority,omitempty" yaml:"priority,omitempty"`
+	Labels      []string     `json:"labels,omitempty" yaml:"labels,omitempty"`
+	AssigneeID  string       `json:"assigneeId,omitempty" yaml:"assigneeId,omitempty"`
+	BuiltIn     bool         `json:"-" yaml:"-"`
+	UpdatedAt   time.Time    `json:"updatedAt" yaml:"updatedAt"`
+}
+
+// NewTask builds a task from the template
+func (t *TaskTemplate) NewTask(title string) *UniversalTask {
+	now := time.Now()
+	return &UniversalTask{
+		Title:       t.TitlePrefix + title,
+		Description: t.Description,
+		Type:        t.Type,
+		Priority:    t.Priority,
+		Labels:      append([]string(nil), t.Labels...),
+		AssigneeID:  t.AssigneeID,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+}
+
+var templateNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// builtinTaskTemplates are available even when no templates were saved
+var builtinTaskTemplates = []*TaskTemplate{
+	{
+		Name:        "bug-report",
+		Summary:     "Bug report with reproduction steps",
+		TitlePrefix: "[Bug] ",
+		Description: `## Summary
+<what is broken, in one sentence>
+
+## Steps to Reproduce
+1. <first step>
+2. <second step>
+
+## Expected Behavior
+<what should happen>
+
+## Actual Behavior
+<what happens instead, with error messages or screenshots>
+
+## Environment
+<version, OS, browser, configuration>`,
+		Type:     TaskTypeBug,
+		Priority: TaskPriorityHigh,
+		Labels:   []string{"bug"},
+	},
+	{
+		Name:        "incident",
+		Summary:     "Production incident with timeline",
+		TitlePrefix: "[Incident] ",
+		Description: `## Impact
+<who and what is affected>
+
+## Timeline
+- <HH:MM> <detected how>
+- <HH:MM> <actions taken>
+
+## Root Cause
+<fill in once known>
+
+## Follow-ups
+- [ ] <preventive action>`,
+		Type:     TaskTypeBug,
+		Priority: TaskPriorityCritical,
+		Labels:   []string{"incident"},
+	},
+	{
+		Name:        "feature-request",
+		Summary:     "Feature request with acceptance criteria",
+		Description: `## Problem
+<what user need does this address>
+
+## Proposal
+<what should be built>
+
+## Acceptance Criteria
+- [ ] <criterion>`,
+		Type:     TaskTypeFeature,
+		Priority: TaskPriorityMedium,
+		Labels:   []string{"feature"},
+	},
+}
+
+// TaskTemplateStore keeps task templates in a JSON file.
+// Built-in templates are always available and can be overridden by saving
+// a template with the same name.
+type TaskTemplateStore struct {
+	mu        sync.RWMutex
+	path      string
+	templates map[string]*TaskTemplate // saved templates only
+}
+
+// TaskTemplatesFile is the file name of the template store in a config directory
+const TaskTemplatesFile = "task_templates.json"
+
+// NewTaskTemplateStore creates a template store backed by the given file.
+// An empty path keeps saved templates in memory only.
+func NewTaskTemplateStore(path string) (*TaskTemplateStore, error) {
+	store := &TaskTemplateStore{
+		path:      path,
+		templates: make(map[string]*TaskTemplate),
+	}
+
+	if err := store.load(); err != nil {
+		return nil, err
+	}
+
+	return store, nil
+}
+
+// Get returns a template by name
+func (s *TaskTemplateStore) Get(name string) (*TaskTemplate, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if template, exists := s.templates[name]; exists {
+		return copyTaskTemplate(template), nil
+	}
+	if template := findBuiltinTaskTemplate(name); template != nil {
+		return template, nil
+	}
+
+	return nil, NewProviderError(ErrorTypeNotFound, fmt.Sprintf("task template not found: %s", name), nil)
+}
+
+// List returns all templates sorted by name
+func (s *TaskTemplateStore) List() []*TaskTemplate {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	var templates []*TaskTemplate
+	for _, template := range s.templates {
+		templates = append(templates, copyTaskTemplate(template))
+	}
+	for _, builtin := range builtinTaskTemplates {
+		if _, overridden := s.templates[builtin.Name]; !overridden {
+			templates = append(templates, findBuiltinTaskTemplate(builtin.Name))
+		}
+	}
+
+	sort.Slice(templates, func(i, j int) bool { return templates[i].Name < templates[j].Name })
+	return templates
+}
+
+// Save creates or replaces a template
+func (s *TaskTemplateStore) Save(template *TaskTemplate) error {
+	if !templateNamePattern.MatchString(template.Name) {
+		return NewProviderError(ErrorTypeValidation,
+			fmt.Sprintf("invalid template name %q: use lowercase letters, digits, '-' and '_'", template.Name), nil)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	saved := copyTaskTemplate(template)
+	saved.BuiltIn = false
+	saved.UpdatedAt = time.Now()
+	s.templates[saved.Name] = saved
+
+	return s.save()
+}
+
+// Delete removes a saved template. Deleting an overridden built-in restores the default.
+func (s *TaskTemplateStore) Delete(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.templates[name]; !exists {
+		if findBuiltinTaskTemplate(name) != nil {
+			return NewProviderError(ErrorTypeValidation, fmt.Sprintf("built-in template %s cannot be deleted", name), nil)
+		}
+		return NewProviderError(ErrorTypeNotFound, fmt.Sprintf("task template not found: %s", name), nil)
+	}
+
+	delete(s.templates, name)
+	return s.save()
+}
+
+// save persists saved templates. Must be called with the lock held.
+func (s *TaskTemplateStore) save() error {
+	if s.path == "" {
+		return nil
+	}
+
+	templates := make([]*TaskTemplate, 0, len(s.templates))
+	for _, template := range s.templates {
+		templates = append(templates, template)
+	}
+	sort.Slice(templates, func(i, j int) bool { return templates[i].Name < templates[j].Name })
+
+	data, err := json.MarshalIndent(templates, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal task templates: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0755); err != nil {
+		return fmt.Errorf("failed to create templates directory: %w", err)
+	}
+	if err := os.WriteFile(s.path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write task templates: %w", err)
+	}
+
+	return nil
+}
+
+// load reads saved templates from disk
+func (s *TaskTemplateStore) load() error {
+	if s.path == "" {
+		return nil
+	}
+
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to read task templates: %w", err)
+	}
+
+	var templates []*TaskTemplate
+	if err := json.Unmarshal(data, &templates); err != nil {
+		return fmt.Errorf("failed to parse task templates: %w", err)
+	}
+
+	for _, template := range templates {
+		s.templates[template.Name] = template
+	}
+
+	return nil
+}
+
+func findBuiltinTaskTemplate(name string) *TaskTemplate {
+	for _, builtin := range builtinTaskTemplates {
+		if builtin.Name == name {
+			template := copyTaskTemplate(builtin)
+			template.BuiltIn = true
+			return template
+		}
+	}
+	return nil
+}
+
+func copyTaskTemplate(template *TaskTemplate) *TaskTemplate {
+	copied := *template
+	copied.Labels = append([]string(nil), template.Labels...)
+	return &copied
+}
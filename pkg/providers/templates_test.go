@@ -0,0 +1,69 @@
+package providers
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskTemplateStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "templates.json")
+	store, err := NewTaskTemplateStore(path)
+	require.NoError(t, err)
+
+	t.Run("Built-in templates", func(t *testing.T) {
+		template, err := store.Get("bug-report")
+		require.NoError(t, err)
+		assert.True(t, template.BuiltIn)
+		assert.Equal(t, TaskTypeBug, template.Type)
+		assert.Contains(t, template.Description, "## Steps to Reproduce")
+
+		task := template.NewTask("Login fails")
+		assert.Equal(t, "[Bug] Login fails", task.Title)
+		assert.Equal(t, []string{"bug"}, task.Labels)
+	})
+
+	t.Run("Unknown template", func(t *testing.T) {
+		_, err := store.Get("missing")
+		assert.True(t, IsNotFoundError(err))
+	})
+
+	t.Run("Save, override and persist", func(t *testing.T) {
+		require.NoError(t, store.Save(&TaskTemplate{Name: "spike", Type: TaskTypeSpike, Labels: []string{"research"}}))
+		require.NoError(t, store.Save(&TaskTemplate{Name: "bug-report", Type: TaskTypeBug, Priority: TaskPriorityLow}))
+
+		restored, err := NewTaskTemplateStore(path)
+		require.NoError(t, err)
+
+		spike, err := restored.Get("spike")
+		require.NoError(t, err)
+		assert.False(t, spike.BuiltIn)
+		assert.Equal(t, []string{"research"}, spike.Labels)
+
+		bug, err := restored.Get("bug-report")
+		require.NoError(t, err)
+		assert.Equal(t, TaskPriorityLow, bug.Priority)
+
+		names := []string{}
+		for _, template := range restored.List() {
+			names = append(names, template.Name)
+		}
+		assert.Equal(t, []string{"bug-report", "feature-request", "incident", "spike"}, names)
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		require.NoError(t, store.Delete("bug-report"))
+		bug, err := store.Get("bug-report")
+		require.NoError(t, err)
+		assert.True(t, bug.BuiltIn, "deleting an override restores the built-in")
+
+		assert.True(t, IsErrorType(store.Delete("incident"), ErrorTypeValidation))
+		assert.True(t, IsNotFoundError(store.Delete("missing")))
+	})
+
+	t.Run("Invalid name", func(t *testing.T) {
+		assert.True(t, IsErrorType(store.Save(&TaskTemplate{Name: "Bad Name"}), ErrorTypeValidation))
+	})
+}
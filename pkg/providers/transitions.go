@@ -0,0 +1,86 @@
+package providers
+
+import (
+	"sort"
+	"strings"
+)
+
+// ResolutionFieldName is the custom field providers use for a task's resolution
+const ResolutionFieldName = "resolution"
+
+// FinalStatus picks the status a task moves to when it is closed: the first
+// done status of the workflow, falling back to any final status that isn't a
+// cancellation
+func FinalStatus(statuses []TaskStatus) (TaskStatus, bool) {
+	ordered := orderedStatuses(statuses)
+
+	for _, status := range ordered {
+		if status.Category == StatusCategoryDone {
+			return status, true
+		}
+	}
+	for _, status := range ordered {
+		if status.IsFinal && status.Category != StatusCategoryCancelled {
+			return status, true
+		}
+	}
+	return TaskStatus{}, false
+}
+
+// InitialStatus picks the status a task moves to when it is reopened: the
+// first to-do status of the workflow, falling back to the first non-final one
+func InitialStatus(statuses []TaskStatus) (TaskStatus, bool) {
+	ordered := orderedStatuses(statuses)
+
+	for _, status := range ordered {
+		if status.Category == StatusCategoryTodo && !status.IsFinal {
+			return status, true
+		}
+	}
+	for _, status := range ordered {
+		if !status.IsFinal && status.Category != StatusCategoryDone && status.Category != StatusCategoryCancelled {
+			return status, true
+		}
+	}
+	return TaskStatus{}, false
+}
+
+// InProgressStatus picks the status a task moves to when work on it starts:
+// the first in-progress status of the workflow
+func InProgressStatus(statuses []TaskStatus) (TaskStatus, bool) {
+	for _, status := range orderedStatuses(statuses) {
+		if status.Category == StatusCategoryInProgress && !status.IsFinal {
+			return status, true
+		}
+	}
+	return TaskStatus{}, false
+}
+
+// SameStatus reports whether two statuses are the same workflow state
+func SameStatus(a, b TaskStatus) bool {
+	if a.ID != "" && a.ID == b.ID {
+		return true
+	}
+	return a.Name != "" && strings.EqualFold(a.Name, b.Name)
+}
+
+// ResolutionField returns the name of the task's resolution custom field, if it has one
+func ResolutionField(task *UniversalTask) (string, bool) {
+	for name := range task.CustomFields {
+		if strings.EqualFold(name, ResolutionFieldName) {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// orderedStatuses sorts statuses by workflow order, keeping the provider's
+// order for statuses without one
+func orderedStatuses(statuses []TaskStatus) []TaskStatus {
+	ordered := make([]TaskStatus, len(statuses))
+	copy(ordered, statuses)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return ordered[i].Order < ordered[j].Order
+	})
+	return ordered
+}
@@ -0,0 +1,72 @@
+package providers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFinalAndInitialStatus(t *testing.T) {
+	workflow := []TaskStatus{
+		{ID: "wont_fix", Name: "Won't fix", Category: StatusCategoryCancelled, IsFinal: true, Order: 5},
+		{ID: "resolved", Name: "Resolved", Category: StatusCategoryDone, IsFinal: true, Order: 4},
+		{ID: "in_progress", Name: "In Progress", Category: StatusCategoryInProgress, Order: 2},
+		{ID: "open", Name: "Open", Category: StatusCategoryTodo, Order: 1},
+		{ID: "submitted", Name: "Submitted", Category: StatusCategoryTodo, Order: 0},
+	}
+
+	final, ok := FinalStatus(workflow)
+	assert.True(t, ok)
+	assert.Equal(t, "Resolved", final.Name)
+
+	initial, ok := InitialStatus(workflow)
+	assert.True(t, ok)
+	assert.Equal(t, "Submitted", initial.Name)
+
+	started, ok := InProgressStatus(workflow)
+	assert.True(t, ok)
+	assert.Equal(t, "In Progress", started.Name)
+
+	t.Run("Final status without done category", func(t *testing.T) {
+		final, ok := FinalStatus([]TaskStatus{
+			{Name: "Obsolete", Category: StatusCategoryCancelled, IsFinal: true},
+			{Name: "Open", Category: StatusCategoryTodo},
+			{Name: "Verified", Category: StatusCategoryReview, IsFinal: true},
+		})
+		assert.True(t, ok)
+		assert.Equal(t, "Verified", final.Name)
+	})
+
+	t.Run("Initial status without todo category", func(t *testing.T) {
+		initial, ok := InitialStatus([]TaskStatus{
+			{Name: "Done", Category: StatusCategoryDone, IsFinal: true},
+			{Name: "In Progress", Category: StatusCategoryInProgress},
+		})
+		assert.True(t, ok)
+		assert.Equal(t, "In Progress", initial.Name)
+	})
+
+	t.Run("No matching status", func(t *testing.T) {
+		_, ok := FinalStatus([]TaskStatus{{Name: "Open", Category: StatusCategoryTodo}})
+		assert.False(t, ok)
+		_, ok = InitialStatus(nil)
+		assert.False(t, ok)
+		_, ok = InProgressStatus([]TaskStatus{{Name: "Open", Category: StatusCategoryTodo}})
+		assert.False(t, ok)
+	})
+}
+
+func TestSameStatus(t *testing.T) {
+	assert.True(t, SameStatus(TaskStatus{ID: "done"}, TaskStatus{ID: "done", Name: "Done"}))
+	assert.True(t, SameStatus(TaskStatus{Name: "done"}, TaskStatus{ID: "closed", Name: "Done"}))
+	assert.False(t, SameStatus(TaskStatus{}, TaskStatus{}))
+}
+
+func TestResolutionField(t *testing.T) {
+	name, ok := ResolutionField(&UniversalTask{CustomFields: map[string]interface{}{"Resolution": "Fixed"}})
+	assert.True(t, ok)
+	assert.Equal(t, "Resolution", name)
+
+	_, ok = ResolutionField(&UniversalTask{})
+	assert.False(t, ok)
+}
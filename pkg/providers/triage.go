@@ -0,0 +1,272 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// triageScanLimit bounds how many tasks are read to find untriaged ones and
+// to learn the labels and workload of the team
+const triageScanLimit = 200
+
+// TriageTeam is the team context triage suggestions are based on
+type TriageTeam struct {
+	Labels  []string      `json:"labels"` // Labels in use, most used first
+	Members []*TeamMember `json:"members"`
+}
+
+// TeamMember is a possible assignee and their number of open tasks
+type TeamMember struct {
+	ID        string `json:"id"`
+	Name      string `json:"name,omitempty"`
+	OpenTasks int    `json:"openTasks"`
+}
+
+// Workload maps the members' names to their open task counts
+func (t *TriageTeam) Workload() map[string]int {
+	workload := make(map[string]int, len(t.Members))
+	for _, member := range t.Members {
+		workload[member.displayName()] = member.OpenTasks
+	}
+	return workload
+}
+
+// findMember returns the member a name refers to by ID or name, compared case-insensitively
+func (t *TriageTeam) findMember(name string) *TeamMember {
+	for _, member := range t.Members {
+		if strings.EqualFold(member.ID, name) || strings.EqualFold(member.Name, name) {
+			return member
+		}
+	}
+	return nil
+}
+
+func (m *TeamMember) displayName() string {
+	if m.Name != "" {
+		return m.Name
+	}
+	return m.ID
+}
+
+// TriageSuggestion is a suggested classification of a task
+type TriageSuggestion struct {
+	Priority   TaskPriority `json:"priority,omitempty"`
+	Type       TaskType     `json:"type,omitempty"`
+	Labels     []string     `json:"labels,omitempty"`
+	AssigneeID string       `json:"assigneeId,omitempty"`
+	Reason     string       `json:"reason,omitempty"`
+}
+
+// TriageSuggester suggests the classification of an untriaged task, e.g. with an AI chain
+type TriageSuggester func(ctx context.Context, task *UniversalTask, team *TriageTeam) (*TriageSuggestion, error)
+
+// TriageOptions selects the tasks to triage
+type TriageOptions struct {
+	ProjectID string
+	Limit     int // Most untriaged tasks to suggest for, 0 for all found
+	// Progress, if set, is called before each suggestion and once at the end
+	// with the number of tasks done so far and the number of untriaged tasks
+	Progress func(done, total int)
+}
+
+// TriageResult is the suggestion for one task and the update that applies it
+type TriageResult struct {
+	TaskID     string            `json:"taskId"`
+	Title      string            `json:"title"`
+	Suggestion *TriageSuggestion `json:"suggestion,omitempty"`
+	Changes    []FieldChange     `json:"changes,omitempty"`
+	Update     *TaskUpdate       `json:"-"`
+	Applied    bool              `json:"applied,omitempty"`
+	Error      string            `json:"error,omitempty"`
+}
+
+// IsUntriaged reports whether an open task is missing its priority, assignee or labels
+func IsUntriaged(task *UniversalTask) bool {
+	if task.IsCompleted() {
+		return false
+	}
+	return task.Priority == "" || task.AssigneeID == "" || len(task.Labels) == 0
+}
+
+// BuildTriageTeam learns the labels and workload of a team from its tasks.
+// users are the provider's users, if it can list them; otherwise the
+// assignees of the tasks make up the team.
+func BuildTriageTeam(tasks []*UniversalTask, users []*User) *TriageTeam {
+	labelCounts := make(map[string]int)
+	open := make(map[string]int)
+	for _, task := range tasks {
+		for _, label := range task.Labels {
+			labelCounts[label]++
+		}
+		if task.AssigneeID != "" && !task.IsCompleted() {
+			open[task.AssigneeID]++
+		}
+	}
+
+	team := &TriageTeam{Labels: []string{}, Members: []*TeamMember{}}
+	for _, entry := range SortedCounts(labelCounts) {
+		team.Labels = append(team.Labels, entry.Key)
+	}
+
+	if len(users) > 0 {
+		for _, user := range users {
+			team.Members = append(team.Members, &TeamMember{ID: user.ID, Name: user.Login, OpenTasks: open[user.ID] + open[user.Login]})
+		}
+		return team
+	}
+	for _, entry := range SortedCounts(open) {
+		team.Members = append(team.Members, &TeamMember{ID: entry.Key, OpenTasks: entry.Count})
+	}
+	return team
+}
+
+// NewTriageSuggestion checks suggested values, e.g. from an AI response.
+// Unknown priorities and types are dropped, and so are labels the team
+// doesn't use yet, if it uses any, and assignees that aren't team members.
+func NewTriageSuggestion(priority, taskType string, labels []string, assignee, reason string, team *TriageTeam) *TriageSuggestion {
+	suggestion := &TriageSuggestion{Reason: reason}
+
+	if p := TaskPriority(strings.ToLower(strings.TrimSpace(priority))); p.IsValid() {
+		suggestion.Priority = p
+	}
+	if t := TaskType(strings.ToLower(strings.TrimSpace(taskType))); t.IsValid() {
+		suggestion.Type = t
+	}
+
+	known := labelSet(team.Labels)
+	for _, label := range uniqueLabels(labels) {
+		if len(team.Labels) == 0 || known[strings.ToLower(label)] {
+			suggestion.Labels = append(suggestion.Labels, canonicalLabel(team.Labels, label))
+		}
+	}
+
+	if member := team.findMember(strings.TrimSpace(assignee)); member != nil {
+		suggestion.AssigneeID = member.ID
+	}
+	return suggestion
+}
+
+// canonicalLabel returns the spelling of label that the team uses
+func canonicalLabel(labels []string, label string) string {
+	for _, known := range labels {
+		if strings.EqualFold(known, label) {
+			return known
+		}
+	}
+	return label
+}
+
+// Update returns the changes that apply the suggestion to a task, or nil if
+// there are none. Only missing fields are filled in: a priority, assignee or
+// labels someone already set are kept. The type is only changed from the
+// generic task type to a more specific one.
+func (s *TriageSuggestion) Update(task *UniversalTask) *TaskUpdate {
+	update := &TaskUpdate{}
+	changed := false
+
+	if task.Priority == "" && s.Priority != "" {
+		update.Priority = &s.Priority
+		changed = true
+	}
+	if (task.Type == "" || task.Type == TaskTypeTask) && s.Type != "" && s.Type != TaskTypeTask {
+		update.Type = &s.Type
+		changed = true
+	}
+	if len(task.Labels) == 0 && len(s.Labels) > 0 {
+		update.Labels = append([]string{}, s.Labels...)
+		changed = true
+	}
+	if task.AssigneeID == "" && s.AssigneeID != "" {
+		update.AssigneeID = &s.AssigneeID
+		changed = true
+	}
+
+	if !changed {
+		return nil
+	}
+	return update
+}
+
+// Triage finds untriaged open tasks of a provider and asks suggest for their
+// classification. Nothing is changed; see ApplyTriage. A suggestion that
+// fails is reported in its result. Triage stops at the first suggestion that
+// fails because the AI budget is used up or ctx is done, leaving the rest of
+// the tasks out of the results.
+func Triage(ctx context.Context, provider TaskProvider, options TriageOptions, suggest TriageSuggester) ([]*TriageResult, *TriageTeam, error) {
+	tasks, err := provider.ListTasks(ctx, &TaskFilters{ProjectID: options.ProjectID, Limit: triageScanLimit})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list tasks: %w", err)
+	}
+
+	var users []*User
+	if userProvider, ok := ProviderAs[UserProvider](provider); ok {
+		// Without users the assignees of the tasks make up the team
+		users, _ = userProvider.ListUsers(ctx)
+	}
+	team := BuildTriageTeam(tasks, users)
+
+	var untriaged []*UniversalTask
+	for _, task := range tasks {
+		if !IsUntriaged(task) {
+			continue
+		}
+		if options.Limit > 0 && len(untriaged) >= options.Limit {
+			break
+		}
+		untriaged = append(untriaged, task)
+	}
+
+	results := []*TriageResult{}
+	for i, task := range untriaged {
+		if options.Progress != nil {
+			options.Progress(i, len(untriaged))
+		}
+
+		result := &TriageResult{TaskID: task.GetDisplayID(), Title: task.Title}
+		results = append(results, result)
+
+		suggestion, err := suggest(ctx, task, team)
+		if err != nil {
+			result.Error = err.Error()
+			if errors.Is(err, ErrAIBudgetExceeded) || ctx.Err() != nil {
+				break
+			}
+			continue
+		}
+		result.Suggestion = suggestion
+		if result.Update = suggestion.Update(task); result.Update != nil {
+			result.Changes = DiffTaskUpdate(task, result.Update)
+			for i, change := range result.Changes {
+				// Show who the assignee is rather than their ID
+				if member := team.findMember(change.New); change.Field == "assignee" && member != nil {
+					result.Changes[i].New = member.displayName()
+				}
+			}
+		}
+	}
+
+	if options.Progress != nil {
+		options.Progress(len(results), len(untriaged))
+	}
+
+	return results, team, nil
+}
+
+// ApplyTriage applies the updates of triage results that have one
+func ApplyTriage(ctx context.Context, provider TaskProvider, results []*TriageResult) (applied, failed int) {
+	for _, result := range results {
+		if result.Update == nil || result.Error != "" {
+			continue
+		}
+		if err := provider.UpdateTask(ctx, result.TaskID, result.Update); err != nil {
+			result.Error = err.Error()
+			failed++
+			continue
+		}
+		result.Applied = true
+		applied++
+	}
+	return applied, failed
+}
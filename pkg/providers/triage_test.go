@@ -0,0 +1,107 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// triageProvider lists fixed tasks and users and records updates
+type triageProvider struct {
+	flakyProvider
+	users []*User
+}
+
+func (p *triageProvider) ListUsers(ctx context.Context) ([]*User, error) {
+	return p.users, nil
+}
+
+func TestTriage(t *testing.T) {
+	tasks := []*UniversalTask{
+		{ID: "OPS-1", Title: "Login crashes", Type: TaskTypeTask},
+		{ID: "OPS-2", Title: "Rotate certificates", Priority: TaskPriorityHigh, Type: TaskTypeChore, AssigneeID: "alice", Labels: []string{"infra"}},
+		{ID: "OPS-3", Title: "Old bug", Status: TaskStatus{Category: StatusCategoryDone}},
+		{ID: "OPS-4", Title: "Update docs", Priority: TaskPriorityLow, Type: TaskTypeStory, Labels: []string{"docs"}},
+		{ID: "OPS-5", Title: "Fix flaky test", AssigneeID: "bob", Labels: []string{"Backend", "infra"}},
+	}
+
+	t.Run("Team context", func(t *testing.T) {
+		team := BuildTriageTeam(tasks, nil)
+		assert.Equal(t, []string{"infra", "Backend", "docs"}, team.Labels)
+		assert.Equal(t, map[string]int{"alice": 1, "bob": 1}, team.Workload())
+
+		team = BuildTriageTeam(tasks, []*User{{ID: "1-1", Login: "alice"}, {ID: "1-3", Login: "carol"}})
+		assert.Equal(t, map[string]int{"alice": 1, "carol": 0}, team.Workload())
+	})
+
+	t.Run("Suggestions are checked against the team", func(t *testing.T) {
+		team := BuildTriageTeam(tasks, []*User{{ID: "1-1", Login: "alice"}, {ID: "1-3", Login: "carol"}})
+		suggestion := NewTriageSuggestion("High", "bug", []string{"backend", "made-up", "backend"}, "Carol", "crash on login", team)
+		assert.Equal(t, &TriageSuggestion{
+			Priority:   TaskPriorityHigh,
+			Type:       TaskTypeBug,
+			Labels:     []string{"Backend"},
+			AssigneeID: "1-3",
+			Reason:     "crash on login",
+		}, suggestion)
+
+		suggestion = NewTriageSuggestion("urgent", "ticket", nil, "dave", "", team)
+		assert.Empty(t, suggestion.Priority)
+		assert.Empty(t, suggestion.Type)
+		assert.Empty(t, suggestion.AssigneeID)
+	})
+
+	t.Run("Only missing fields are filled in", func(t *testing.T) {
+		suggestion := &TriageSuggestion{Priority: TaskPriorityHigh, Type: TaskTypeBug, Labels: []string{"backend"}, AssigneeID: "1-3"}
+
+		update := suggestion.Update(tasks[0])
+		require.NotNil(t, update)
+		assert.Equal(t, TaskPriorityHigh, *update.Priority)
+		assert.Equal(t, TaskTypeBug, *update.Type)
+		assert.Equal(t, []string{"backend"}, update.Labels)
+		assert.Equal(t, "1-3", *update.AssigneeID)
+
+		update = suggestion.Update(tasks[3])
+		require.NotNil(t, update)
+		assert.Nil(t, update.Priority)
+		assert.Nil(t, update.Type)
+		assert.Nil(t, update.Labels)
+		assert.Equal(t, "1-3", *update.AssigneeID)
+
+		assert.Nil(t, suggestion.Update(tasks[1]))
+	})
+
+	t.Run("Untriaged open tasks get suggestions", func(t *testing.T) {
+		provider := &triageProvider{flakyProvider: flakyProvider{tasks: tasks}}
+		var asked []string
+		suggest := func(ctx context.Context, task *UniversalTask, team *TriageTeam) (*TriageSuggestion, error) {
+			asked = append(asked, task.ID)
+			if task.ID == "OPS-5" {
+				return nil, errors.New("model unavailable")
+			}
+			return &TriageSuggestion{Priority: TaskPriorityMedium, AssigneeID: "bob"}, nil
+		}
+
+		results, team, err := Triage(context.Background(), provider, TriageOptions{}, suggest)
+		require.NoError(t, err)
+		assert.NotNil(t, team)
+		assert.Equal(t, []string{"OPS-1", "OPS-4", "OPS-5"}, asked)
+		require.Len(t, results, 3)
+		assert.Equal(t, []FieldChange{{Field: "priority", New: "medium"}, {Field: "assignee", New: "bob"}}, results[0].Changes)
+		assert.Equal(t, "model unavailable", results[2].Error)
+
+		applied, failed := ApplyTriage(context.Background(), provider, results)
+		assert.Equal(t, 2, applied)
+		assert.Equal(t, 0, failed)
+		assert.True(t, results[1].Applied)
+		assert.Equal(t, "bob", *provider.updates["OPS-4"].AssigneeID)
+		assert.NotContains(t, provider.updates, "OPS-5")
+
+		results, _, err = Triage(context.Background(), provider, TriageOptions{Limit: 1}, suggest)
+		require.NoError(t, err)
+		assert.Len(t, results, 1)
+	})
+}
@@ -0,0 +1,319 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UndoLogFile is the file name of the undo log in a config directory
+const UndoLogFile = "undo.json"
+
+// DefaultUndoRetention is how long bulk operations can be undone
+const DefaultUndoRetention = 7 * 24 * time.Hour
+
+// UndoOperation is the kind of a bulk operation that can be undone
+type UndoOperation string
+
+const (
+	UndoDelete UndoOperation = "delete"
+	UndoUpdate UndoOperation = "update"
+)
+
+// UndoItem is the state of one task before a bulk operation changed it
+type UndoItem struct {
+	TaskID string         `json:"taskId"`
+	Before *UniversalTask `json:"before"`
+	Update *TaskUpdate    `json:"update,omitempty"` // The applied changes, for updates
+}
+
+// UndoEntry records what a bulk operation deleted or changed, so that it
+// can be undone within the retention window
+type UndoEntry struct {
+	Token     string        `json:"token"`
+	Operation UndoOperation `json:"operation"`
+	Provider  string        `json:"provider"`
+	Items     []*UndoItem   `json:"items"`
+	CreatedAt time.Time     `json:"createdAt"`
+	ExpiresAt time.Time     `json:"expiresAt"`
+}
+
+// UndoResult is the outcome of undoing the operation on one task
+type UndoResult struct {
+	TaskID    string `json:"taskId"`
+	NewTaskID string `json:"newTaskId,omitempty"` // The recreated task, for deletions
+	Error     string `json:"error,omitempty"`
+}
+
+// UndoLog stores undo entries of bulk operations in a file. Expired entries
+// are dropped whenever the log changes.
+type UndoLog struct {
+	mu        sync.Mutex
+	path      string
+	retention time.Duration
+	now       func() time.Time
+}
+
+// NewUndoLog creates an undo log backed by the given file
+func NewUndoLog(path string) *UndoLog {
+	return &UndoLog{path: path, retention: DefaultUndoRetention, now: time.Now}
+}
+
+// SummarizeBulkTasks describes the tasks a bulk operation is about to change,
+// counted by provider and status, e.g. "yt: 3 (Open: 2, In Progress: 1)"
+func SummarizeBulkTasks(tasks []*UniversalTask) []string {
+	byProvider := make(map[string][]*UniversalTask)
+	for _, task := range tasks {
+		byProvider[task.ProviderName] = append(byProvider[task.ProviderName], task)
+	}
+
+	var lines []string
+	for _, entry := range SortedCounts(ComputeTaskStats(tasks).ByProvider) {
+		lines = append(lines, summarizeStatuses(entry.Key, byProvider[entry.Key]))
+	}
+	if unnamed := byProvider[""]; len(unnamed) > 0 {
+		lines = append(lines, summarizeStatuses("tasks", unnamed))
+	}
+	return lines
+}
+
+func summarizeStatuses(name string, tasks []*UniversalTask) string {
+	var statuses []string
+	for _, entry := range SortedCounts(ComputeTaskStats(tasks).ByStatus) {
+		status := entry.Key
+		if status == "" {
+			status = "no status"
+		}
+		statuses = append(statuses, fmt.Sprintf("%s: %d", status, entry.Count))
+	}
+	return fmt.Sprintf("%s: %d (%s)", name, len(tasks), strings.Join(statuses, ", "))
+}
+
+// Record stores the prior state of the tasks of a bulk operation and returns
+// the entry with its undo token
+func (l *UndoLog) Record(operation UndoOperation, providerName string, items []*UndoItem) (*UndoEntry, error) {
+	if len(items) == 0 {
+		return nil, NewValidationError("nothing to undo", nil)
+	}
+	if operation == UndoUpdate {
+		for _, item := range items {
+			if item.Update == nil {
+				return nil, NewValidationError(fmt.Sprintf("undo of update needs the changes of task %s", item.TaskID), nil)
+			}
+		}
+	}
+
+	now := l.now()
+	entry := &UndoEntry{
+		Token:     uuid.New().String()[:8],
+		Operation: operation,
+		Provider:  providerName,
+		Items:     items,
+		CreatedAt: now,
+		ExpiresAt: now.Add(l.retention),
+	}
+
+	err := l.modify(func(entries []*UndoEntry) ([]*UndoEntry, error) {
+		return append(entries, entry), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// List returns the entries that can still be undone, newest first
+func (l *UndoLog) List() ([]*UndoEntry, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	entries, err := l.load()
+	if err != nil {
+		return nil, err
+	}
+	entries = l.unexpired(entries)
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].CreatedAt.After(entries[j].CreatedAt)
+	})
+	return entries, nil
+}
+
+// Get returns the entry of an undo token
+func (l *UndoLog) Get(token string) (*UndoEntry, error) {
+	entries, err := l.List()
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		if entry.Token == token {
+			return entry, nil
+		}
+	}
+	return nil, NewProviderError(ErrorTypeNotFound, fmt.Sprintf("undo token %s not found or expired", token), nil)
+}
+
+// Undo recreates the deleted tasks or reverts the updated fields of an entry.
+// Recreated tasks get new IDs. Items that were undone leave the entry, and
+// the entry leaves the log once every item is undone, so a failed undo can
+// be retried.
+func (l *UndoLog) Undo(ctx context.Context, entry *UndoEntry, provider TaskProvider) ([]*UndoResult, error) {
+	var results []*UndoResult
+	var remaining []*UndoItem
+	for _, item := range entry.Items {
+		result := &UndoResult{TaskID: item.TaskID}
+		results = append(results, result)
+
+		var err error
+		switch entry.Operation {
+		case UndoDelete:
+			var created *UniversalTask
+			if created, err = provider.CreateTask(ctx, restoredTask(item.Before)); err == nil {
+				result.NewTaskID = created.GetDisplayID()
+			}
+		case UndoUpdate:
+			err = provider.UpdateTask(ctx, item.TaskID, RevertUpdate(item.Before, item.Update))
+		default:
+			err = NewValidationError(fmt.Sprintf("unknown undo operation %q", entry.Operation), nil)
+		}
+		if err != nil {
+			result.Error = err.Error()
+			remaining = append(remaining, item)
+		}
+	}
+
+	err := l.modify(func(entries []*UndoEntry) ([]*UndoEntry, error) {
+		for i, stored := range entries {
+			if stored.Token != entry.Token {
+				continue
+			}
+			if len(remaining) == 0 {
+				return append(entries[:i], entries[i+1:]...), nil
+			}
+			stored.Items = remaining
+		}
+		return entries, nil
+	})
+	return results, err
+}
+
+// restoredTask builds the task that replaces a deleted one. Unlike a clone
+// it keeps the status, since the task is meant to come back as it was.
+func restoredTask(before *UniversalTask) *UniversalTask {
+	task := importedTask(before)
+	task.Status = before.Status
+	task.ReporterID = before.ReporterID
+	task.ParentID = before.ParentID
+	task.EpicID = before.EpicID
+	return task
+}
+
+// RevertUpdate returns the update that sets the fields changed by applied
+// back to their values in before
+func RevertUpdate(before *UniversalTask, applied *TaskUpdate) *TaskUpdate {
+	revert := &TaskUpdate{}
+	if applied.Title != nil {
+		revert.Title = &before.Title
+	}
+	if applied.Description != nil {
+		revert.Description = &before.Description
+	}
+	if applied.Status != nil {
+		status := before.Status
+		revert.Status = &status
+	}
+	if applied.Priority != nil {
+		revert.Priority = &before.Priority
+	}
+	if applied.Type != nil {
+		revert.Type = &before.Type
+	}
+	if applied.AssigneeID != nil {
+		revert.AssigneeID = &before.AssigneeID
+	}
+	if applied.DueDate != nil {
+		revert.DueDate = before.DueDate
+	}
+	if applied.Labels != nil {
+		revert.Labels = append([]string{}, before.Labels...)
+	}
+	if applied.EstimatedTime != nil {
+		revert.EstimatedTime = before.EstimatedTime
+	}
+	if len(applied.CustomFields) > 0 {
+		revert.CustomFields = make(map[string]interface{}, len(applied.CustomFields))
+		for name := range applied.CustomFields {
+			revert.CustomFields[name] = before.CustomFields[name]
+		}
+	}
+	return revert
+}
+
+// unexpired drops entries past their retention window
+func (l *UndoLog) unexpired(entries []*UndoEntry) []*UndoEntry {
+	now := l.now()
+	kept := entries[:0]
+	for _, entry := range entries {
+		if now.Before(entry.ExpiresAt) {
+			kept = append(kept, entry)
+		}
+	}
+	return kept
+}
+
+// modify applies change to the unexpired entries read from disk and writes the result
+func (l *UndoLog) modify(change func([]*UndoEntry) ([]*UndoEntry, error)) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	entries, err := l.load()
+	if err != nil {
+		return err
+	}
+	if entries, err = change(l.unexpired(entries)); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal undo log: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(l.path), 0755); err != nil {
+		return fmt.Errorf("failed to create undo log directory: %w", err)
+	}
+
+	// Write through a temporary file so that an interrupted write can't lose the prior state of tasks
+	tmp := l.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("failed to write undo log: %w", err)
+	}
+	if err := os.Rename(tmp, l.path); err != nil {
+		return fmt.Errorf("failed to write undo log: %w", err)
+	}
+	return nil
+}
+
+// load reads the stored entries. Must be called with the lock held.
+func (l *UndoLog) load() ([]*UndoEntry, error) {
+	data, err := os.ReadFile(l.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []*UndoEntry{}, nil
+		}
+		return nil, fmt.Errorf("failed to read undo log: %w", err)
+	}
+
+	var entries []*UndoEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse undo log %s: %w", l.path, err)
+	}
+	return entries, nil
+}
@@ -0,0 +1,107 @@
+package providers
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUndoLog(t *testing.T) {
+	now := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+	newLog := func(path string) *UndoLog {
+		log := NewUndoLog(path)
+		log.now = func() time.Time { return now }
+		return log
+	}
+
+	t.Run("Summary counts by provider and status", func(t *testing.T) {
+		lines := SummarizeBulkTasks([]*UniversalTask{
+			{ProviderName: "yt", Status: TaskStatus{Name: "Open"}},
+			{ProviderName: "yt", Status: TaskStatus{Name: "Open"}},
+			{ProviderName: "yt", Status: TaskStatus{Name: "Done"}},
+			{ProviderName: "jira"},
+		})
+		assert.Equal(t, []string{"yt: 3 (Open: 2, Done: 1)", "jira: 1 (no status: 1)"}, lines)
+	})
+
+	t.Run("Deleted tasks are recreated", func(t *testing.T) {
+		log := newLog(filepath.Join(t.TempDir(), UndoLogFile))
+		entry, err := log.Record(UndoDelete, "yt", []*UndoItem{
+			{TaskID: "OPS-7", Before: &UniversalTask{ID: "OPS-7", Title: "Rotate certificates", Status: TaskStatus{Name: "Open"}, AssigneeID: "alice"}},
+		})
+		require.NoError(t, err)
+		assert.Len(t, entry.Token, 8)
+		assert.Equal(t, now.Add(DefaultUndoRetention), entry.ExpiresAt)
+
+		stored, err := newLog(log.path).Get(entry.Token)
+		require.NoError(t, err)
+
+		provider := &flakyProvider{}
+		results, err := log.Undo(context.Background(), stored, provider)
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		assert.Equal(t, "OPS-1", results[0].NewTaskID)
+		require.Len(t, provider.tasks, 1)
+		assert.Equal(t, "Rotate certificates", provider.tasks[0].Title)
+		assert.Equal(t, "Open", provider.tasks[0].Status.Name)
+		assert.Equal(t, "alice", provider.tasks[0].AssigneeID)
+
+		_, err = log.Get(entry.Token)
+		assert.True(t, IsErrorType(err, ErrorTypeNotFound))
+	})
+
+	t.Run("Updates are reverted and failures kept", func(t *testing.T) {
+		log := newLog(filepath.Join(t.TempDir(), UndoLogFile))
+		title, priority := "New title", TaskPriorityHigh
+		entry, err := log.Record(UndoUpdate, "yt", []*UndoItem{
+			{TaskID: "OPS-1", Before: &UniversalTask{Title: "Old title", Priority: TaskPriorityLow, Description: "kept"}, Update: &TaskUpdate{Title: &title, Priority: &priority}},
+			{TaskID: "OPS-404", Before: &UniversalTask{Title: "Gone"}, Update: &TaskUpdate{Title: &title}},
+		})
+		require.NoError(t, err)
+
+		provider := &flakyProvider{}
+		results, err := log.Undo(context.Background(), entry, provider)
+		require.NoError(t, err)
+		require.Len(t, results, 2)
+		assert.Empty(t, results[0].Error)
+		assert.NotEmpty(t, results[1].Error)
+
+		revert := provider.updates["OPS-1"]
+		require.NotNil(t, revert)
+		assert.Equal(t, "Old title", *revert.Title)
+		assert.Equal(t, TaskPriorityLow, *revert.Priority)
+		assert.Nil(t, revert.Description)
+
+		stored, err := log.Get(entry.Token)
+		require.NoError(t, err)
+		require.Len(t, stored.Items, 1)
+		assert.Equal(t, "OPS-404", stored.Items[0].TaskID)
+	})
+
+	t.Run("Expired entries are dropped", func(t *testing.T) {
+		log := newLog(filepath.Join(t.TempDir(), UndoLogFile))
+		old, err := log.Record(UndoDelete, "yt", []*UndoItem{{TaskID: "OPS-1", Before: &UniversalTask{Title: "Old"}}})
+		require.NoError(t, err)
+
+		now = now.Add(DefaultUndoRetention + time.Minute)
+		recent, err := log.Record(UndoDelete, "yt", []*UndoItem{{TaskID: "OPS-2", Before: &UniversalTask{Title: "Recent"}}})
+		require.NoError(t, err)
+
+		entries, err := log.List()
+		require.NoError(t, err)
+		require.Len(t, entries, 1)
+		assert.Equal(t, recent.Token, entries[0].Token)
+
+		_, err = log.Get(old.Token)
+		assert.True(t, IsErrorType(err, ErrorTypeNotFound))
+	})
+
+	t.Run("Updates need their changes", func(t *testing.T) {
+		_, err := newLog(filepath.Join(t.TempDir(), UndoLogFile)).Record(UndoUpdate, "yt", []*UndoItem{{TaskID: "OPS-1", Before: &UniversalTask{}}})
+		assert.True(t, IsErrorType(err, ErrorTypeValidation))
+	})
+}
@@ -0,0 +1,168 @@
+package providers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// User is a user account of a provider
+type User struct {
+	ID    string `json:"id"`
+	Login string `json:"login,omitempty"`
+	Name  string `json:"name,omitempty"`
+	Email string `json:"email,omitempty"`
+}
+
+// DisplayName returns the user's full name, login or ID, whichever is set first
+func (u *User) DisplayName() string {
+	switch {
+	case u.Name != "":
+		return u.Name
+	case u.Login != "":
+		return u.Login
+	default:
+		return u.ID
+	}
+}
+
+// UserProvider is implemented by providers that can list their users
+type UserProvider interface {
+	ListUsers(ctx context.Context) ([]*User, error)
+}
+
+// CurrentUserProvider is implemented by providers that can tell which user
+// their credentials belong to
+type CurrentUserProvider interface {
+	GetCurrentUser(ctx context.Context) (*User, error)
+}
+
+// IsCurrentUser reports whether name is "me" or "@me", which stand for the
+// user the provider's credentials belong to
+func IsCurrentUser(name string) bool {
+	name = strings.TrimSpace(name)
+	return strings.EqualFold(name, "me") || strings.EqualFold(name, "@me")
+}
+
+// CurrentUser returns the user the provider's credentials belong to
+func CurrentUser(ctx context.Context, provider TaskProvider) (*User, error) {
+	unsupported := NewProviderError(ErrorTypeUnsupported,
+		`the provider can't tell the current user, pass your login instead of "me"`, nil)
+
+	identifier, ok := ProviderAs[CurrentUserProvider](provider)
+	if !ok {
+		return nil, unsupported
+	}
+	user, err := identifier.GetCurrentUser(ctx)
+	if err != nil {
+		if IsUnsupportedError(err) {
+			return nil, unsupported
+		}
+		return nil, fmt.Errorf("failed to get current user: %w", err)
+	}
+	return user, nil
+}
+
+// ResolveAssignee returns the user ID to assign a task to: the current user's
+// for "me" and "@me", the name itself otherwise
+func ResolveAssignee(ctx context.Context, provider TaskProvider, name string) (string, error) {
+	if !IsCurrentUser(name) {
+		return name, nil
+	}
+	user, err := CurrentUser(ctx, provider)
+	if err != nil {
+		return "", err
+	}
+	return user.ID, nil
+}
+
+// ResolveAssigneeFilter returns filters whose "me" or "@me" assignee is
+// replaced with the current user's login, which providers search by, or ID.
+// Other filters are returned as they are.
+func ResolveAssigneeFilter(ctx context.Context, provider TaskProvider, filters *TaskFilters) (*TaskFilters, error) {
+	if filters == nil || !IsCurrentUser(filters.AssigneeID) {
+		return filters, nil
+	}
+	user, err := CurrentUser(ctx, provider)
+	if err != nil {
+		return nil, err
+	}
+
+	resolved := *filters
+	resolved.AssigneeID = user.Login
+	if resolved.AssigneeID == "" {
+		resolved.AssigneeID = user.ID
+	}
+	return &resolved, nil
+}
+
+// ResolveUser finds the user a name refers to. The name may be a user ID,
+// login, email or full name, compared case-insensitively, or "me" and "@me"
+// for the current user. Providers that can't list users get the name back as
+// the user ID unchanged.
+func ResolveUser(ctx context.Context, provider TaskProvider, name string) (*User, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, NewValidationError("user name is required", nil)
+	}
+	if IsCurrentUser(name) {
+		return CurrentUser(ctx, provider)
+	}
+
+	lister, ok := ProviderAs[UserProvider](provider)
+	if !ok {
+		return &User{ID: name}, nil
+	}
+
+	users, err := lister.ListUsers(ctx)
+	if err != nil {
+		if IsUnsupportedError(err) {
+			return &User{ID: name}, nil
+		}
+		return nil, fmt.Errorf("failed to list users: %w", err)
+	}
+
+	// An exact ID or login wins over a name several users share
+	var matches []*User
+	for _, user := range users {
+		if user.ID == name || strings.EqualFold(user.Login, name) {
+			return user, nil
+		}
+		if strings.EqualFold(user.Email, name) || strings.EqualFold(user.Name, name) {
+			matches = append(matches, user)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, NewProviderError(ErrorTypeNotFound, fmt.Sprintf("user not found: %s", name), nil)
+	case 1:
+		return matches[0], nil
+	default:
+		logins := make([]string, len(matches))
+		for i, user := range matches {
+			logins[i] = user.Login
+		}
+		return nil, NewValidationError(fmt.Sprintf("user %q is ambiguous, use one of the logins %s", name, strings.Join(logins, ", ")), nil)
+	}
+}
+
+// Assignment is a task and the user it is assigned to
+type Assignment struct {
+	TaskID string `json:"taskId"`
+	User   *User  `json:"user"`
+}
+
+// AssignRoundRobin distributes tasks across users in turn, so that no user
+// gets more than one task more than another
+func AssignRoundRobin(taskIDs []string, users []*User) []Assignment {
+	if len(users) == 0 {
+		return nil
+	}
+
+	assignments := make([]Assignment, len(taskIDs))
+	for i, taskID := range taskIDs {
+		assignments[i] = Assignment{TaskID: taskID, User: users[i%len(users)]}
+	}
+	return assignments
+}
@@ -0,0 +1,157 @@
+package providers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// userListProvider lists a fixed set of users and counts the calls
+type userListProvider struct {
+	TaskProvider
+	users []*User
+	calls int
+}
+
+func (p *userListProvider) ListUsers(ctx context.Context) ([]*User, error) {
+	p.calls++
+	return p.users, nil
+}
+
+func TestResolveUser(t *testing.T) {
+	provider := &userListProvider{users: []*User{
+		{ID: "1-1", Login: "alice", Name: "Alice Smith", Email: "alice@example.com"},
+		{ID: "1-2", Login: "bob", Name: "Bob Jones", Email: "bob@example.com"},
+		{ID: "1-3", Login: "bob.j", Name: "Bob Jones"},
+	}}
+	ctx := context.Background()
+
+	t.Run("By ID, login, email or name", func(t *testing.T) {
+		for _, name := range []string{"1-1", "Alice", "alice@EXAMPLE.com", "alice smith"} {
+			user, err := ResolveUser(ctx, provider, name)
+			require.NoError(t, err, name)
+			assert.Equal(t, "1-1", user.ID, name)
+		}
+	})
+
+	t.Run("Ambiguous and unknown names", func(t *testing.T) {
+		_, err := ResolveUser(ctx, provider, "Bob Jones")
+		assert.True(t, IsErrorType(err, ErrorTypeValidation))
+		assert.Contains(t, err.Error(), "bob, bob.j")
+
+		_, err = ResolveUser(ctx, provider, "carol")
+		assert.True(t, IsNotFoundError(err))
+	})
+
+	t.Run("Providers without users keep the name", func(t *testing.T) {
+		user, err := ResolveUser(ctx, &tasksOnlyProvider{}, "carol")
+		require.NoError(t, err)
+		assert.Equal(t, &User{ID: "carol"}, user)
+	})
+
+	t.Run("Cached", func(t *testing.T) {
+		cache, err := NewMetadataCache("", nil)
+		require.NoError(t, err)
+		caching := NewCachingProvider(provider, "yt", &ProviderConfig{}, cache)
+		provider.calls = 0
+
+		for _, name := range []string{"alice", "bob"} {
+			_, err := ResolveUser(ctx, caching, name)
+			require.NoError(t, err)
+		}
+		assert.Equal(t, 1, provider.calls)
+
+		uncached := NewCachingProvider(&tasksOnlyProvider{}, "plain", &ProviderConfig{}, cache)
+		user, err := ResolveUser(ctx, uncached, "carol")
+		require.NoError(t, err)
+		assert.Equal(t, "carol", user.ID)
+	})
+}
+
+// currentUserProvider knows the user its credentials belong to
+type currentUserProvider struct {
+	TaskProvider
+	user *User
+	err  error
+}
+
+func (p *currentUserProvider) GetCurrentUser(ctx context.Context) (*User, error) {
+	return p.user, p.err
+}
+
+func TestCurrentUser(t *testing.T) {
+	ctx := context.Background()
+	provider := &currentUserProvider{user: &User{ID: "1-2", Login: "bob", Name: "Bob Jones"}}
+
+	t.Run("Me tokens", func(t *testing.T) {
+		for _, name := range []string{"me", "@me", " ME "} {
+			assert.True(t, IsCurrentUser(name), name)
+		}
+		for _, name := range []string{"", "meg", "alice"} {
+			assert.False(t, IsCurrentUser(name), name)
+		}
+	})
+
+	t.Run("Assignee", func(t *testing.T) {
+		assignee, err := ResolveAssignee(ctx, provider, "@me")
+		require.NoError(t, err)
+		assert.Equal(t, "1-2", assignee)
+
+		assignee, err = ResolveAssignee(ctx, &tasksOnlyProvider{}, "alice")
+		require.NoError(t, err)
+		assert.Equal(t, "alice", assignee)
+
+		user, err := ResolveUser(ctx, provider, "me")
+		require.NoError(t, err)
+		assert.Equal(t, "bob", user.Login)
+	})
+
+	t.Run("Filter by login", func(t *testing.T) {
+		filters := &TaskFilters{AssigneeID: "me", ProjectID: "OPS"}
+		resolved, err := ResolveAssigneeFilter(ctx, provider, filters)
+		require.NoError(t, err)
+		assert.Equal(t, "bob", resolved.AssigneeID)
+		assert.Equal(t, "OPS", resolved.ProjectID)
+		assert.Equal(t, "me", filters.AssigneeID, "the filters of other providers stay unresolved")
+
+		other := &TaskFilters{AssigneeID: "alice"}
+		resolved, err = ResolveAssigneeFilter(ctx, &tasksOnlyProvider{}, other)
+		require.NoError(t, err)
+		assert.Same(t, other, resolved)
+	})
+
+	t.Run("Through wrappers", func(t *testing.T) {
+		cache, err := NewMetadataCache("", nil)
+		require.NoError(t, err)
+		caching := NewCachingProvider(provider, "yt", &ProviderConfig{}, cache)
+
+		user, err := CurrentUser(ctx, caching)
+		require.NoError(t, err)
+		assert.Equal(t, "1-2", user.ID)
+	})
+
+	t.Run("Unsupported", func(t *testing.T) {
+		_, err := ResolveAssignee(ctx, &tasksOnlyProvider{}, "me")
+		assert.True(t, IsUnsupportedError(err))
+
+		_, err = CurrentUser(ctx, &currentUserProvider{err: NewProviderError(ErrorTypeUnsupported, "no", nil)})
+		assert.True(t, IsUnsupportedError(err))
+	})
+}
+
+func TestAssignRoundRobin(t *testing.T) {
+	alice, bob := &User{ID: "alice"}, &User{ID: "bob"}
+
+	assignments := AssignRoundRobin([]string{"OPS-1", "OPS-2", "OPS-3"}, []*User{alice, bob})
+	assert.Equal(t, []Assignment{
+		{TaskID: "OPS-1", User: alice},
+		{TaskID: "OPS-2", User: bob},
+		{TaskID: "OPS-3", User: alice},
+	}, assignments)
+
+	assert.Nil(t, AssignRoundRobin([]string{"OPS-1"}, nil))
+	assert.Equal(t, "alice", alice.DisplayName())
+	assert.Equal(t, "Bob", (&User{ID: "1-2", Login: "bob", Name: "Bob"}).DisplayName())
+}
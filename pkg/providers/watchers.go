@@ -0,0 +1,264 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// TaskWatcher is a user subscribed to changes of a single task
+type TaskWatcher struct {
+	TaskID    string    `json:"taskId"`
+	UserID    string    `json:"userId"`
+	WatchedAt time.Time `json:"watchedAt"`
+}
+
+// WatchNotifier delivers a task event to the users watching that task
+type WatchNotifier interface {
+	NotifyWatchers(ctx context.Context, userIDs []string, event *UniversalEvent) error
+}
+
+// TaskWatchRegistry stores per-task watchers and fans task events out to them
+type TaskWatchRegistry struct {
+	mu       sync.RWMutex
+	path     string
+	watchers map[string]map[string]*TaskWatcher // taskID -> userID -> watcher
+	notifier WatchNotifier
+	logger   *logrus.Logger
+}
+
+// WatchersFile is the file name of the watcher store in a config directory
+const WatchersFile = "watchers.json"
+
+// NewTaskWatchRegistry creates a watcher registry backed by the given file.
+// An empty path keeps watchers in memory only.
+func NewTaskWatchRegistry(path string, logger *logrus.Logger) (*TaskWatchRegistry, error) {
+	if logger == nil {
+		logger = logrus.New()
+	}
+
+	registry := &TaskWatchRegistry{
+		path:     path,
+		watchers: make(map[string]map[string]*TaskWatcher),
+		logger:   logger,
+	}
+
+	if err := registry.load(); err != nil {
+		return nil, err
+	}
+
+	return registry, nil
+}
+
+// SetNotifier sets the notifier used to deliver events to watchers
+func (r *TaskWatchRegistry) SetNotifier(notifier WatchNotifier) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.notifier = notifier
+}
+
+// WatchTask subscribes a user to changes of a task
+func (r *TaskWatchRegistry) WatchTask(ctx context.Context, taskID, userID string) error {
+	if taskID == "" || userID == "" {
+		return NewProviderError(ErrorTypeValidation, "task ID and user ID are required", nil)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Other processes share the store, so changes start from its current state
+	if err := r.load(); err != nil {
+		return err
+	}
+
+	if _, exists := r.watchers[taskID]; !exists {
+		r.watchers[taskID] = make(map[string]*TaskWatcher)
+	}
+	if _, exists := r.watchers[taskID][userID]; exists {
+		return nil
+	}
+
+	r.watchers[taskID][userID] = &TaskWatcher{
+		TaskID:    taskID,
+		UserID:    userID,
+		WatchedAt: time.Now(),
+	}
+
+	r.logger.Debugf("User %s is now watching task %s", userID, taskID)
+	return r.save()
+}
+
+// UnwatchTask removes a user's subscription to a task
+func (r *TaskWatchRegistry) UnwatchTask(ctx context.Context, taskID, userID string) error {
+	if taskID == "" || userID == "" {
+		return NewProviderError(ErrorTypeValidation, "task ID and user ID are required", nil)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := r.load(); err != nil {
+		return err
+	}
+
+	if _, exists := r.watchers[taskID][userID]; !exists {
+		return NewProviderError(ErrorTypeNotFound, fmt.Sprintf("user %s is not watching task %s", userID, taskID), nil)
+	}
+
+	delete(r.watchers[taskID], userID)
+	if len(r.watchers[taskID]) == 0 {
+		delete(r.watchers, taskID)
+	}
+
+	r.logger.Debugf("User %s stopped watching task %s", userID, taskID)
+	return r.save()
+}
+
+// Watchers returns the IDs of users watching a task, sorted
+func (r *TaskWatchRegistry) Watchers(taskID string) []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.refresh()
+
+	userIDs := make([]string, 0, len(r.watchers[taskID]))
+	for userID := range r.watchers[taskID] {
+		userIDs = append(userIDs, userID)
+	}
+	sort.Strings(userIDs)
+	return userIDs
+}
+
+// WatchedTasks returns the tasks a user is watching, sorted by task ID
+func (r *TaskWatchRegistry) WatchedTasks(userID string) []*TaskWatcher {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.refresh()
+
+	var watched []*TaskWatcher
+	for _, taskWatchers := range r.watchers {
+		if watcher, exists := taskWatchers[userID]; exists {
+			copied := *watcher
+			watched = append(watched, &copied)
+		}
+	}
+	sort.Slice(watched, func(i, j int) bool { return watched[i].TaskID < watched[j].TaskID })
+	return watched
+}
+
+// HandleEvent notifies the watchers of the event's task.
+// It matches EventCallback so it can be passed to SubscribeToEvents directly.
+func (r *TaskWatchRegistry) HandleEvent(event *UniversalEvent) error {
+	if event == nil || event.TaskID == "" {
+		return nil
+	}
+
+	r.mu.RLock()
+	notifier := r.notifier
+	r.mu.RUnlock()
+
+	if notifier == nil {
+		return nil
+	}
+
+	// The user who made the change doesn't need to hear about it
+	actor, _ := event.Data["actor"].(string)
+
+	var recipients []string
+	for _, userID := range r.Watchers(event.TaskID) {
+		if userID != actor {
+			recipients = append(recipients, userID)
+		}
+	}
+	if len(recipients) == 0 {
+		return nil
+	}
+
+	if err := notifier.NotifyWatchers(context.Background(), recipients, event); err != nil {
+		return fmt.Errorf("failed to notify watchers of task %s: %w", event.TaskID, err)
+	}
+	return nil
+}
+
+// save persists watchers to disk. Must be called with the lock held.
+func (r *TaskWatchRegistry) save() error {
+	if r.path == "" {
+		return nil
+	}
+
+	var all []*TaskWatcher
+	for _, taskWatchers := range r.watchers {
+		for _, watcher := range taskWatchers {
+			all = append(all, watcher)
+		}
+	}
+	sort.Slice(all, func(i, j int) bool {
+		if all[i].TaskID != all[j].TaskID {
+			return all[i].TaskID < all[j].TaskID
+		}
+		return all[i].UserID < all[j].UserID
+	})
+
+	data, err := json.MarshalIndent(all, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal watchers: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(r.path), 0755); err != nil {
+		return fmt.Errorf("failed to create watchers directory: %w", err)
+	}
+	if err := os.WriteFile(r.path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write watchers: %w", err)
+	}
+
+	return nil
+}
+
+// refresh re-reads watchers from disk, so watches added by other processes,
+// e.g. the CLI while the MCP server runs, are seen. Must be called with the
+// lock held.
+func (r *TaskWatchRegistry) refresh() {
+	if err := r.load(); err != nil {
+		r.logger.Warnf("Using cached task watchers: %v", err)
+	}
+}
+
+// load replaces the watchers in memory with the ones on disk
+func (r *TaskWatchRegistry) load() error {
+	if r.path == "" {
+		return nil
+	}
+
+	data, err := os.ReadFile(r.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to read watchers: %w", err)
+	}
+
+	var all []*TaskWatcher
+	if err := json.Unmarshal(data, &all); err != nil {
+		return fmt.Errorf("failed to parse watchers: %w", err)
+	}
+
+	watchers := make(map[string]map[string]*TaskWatcher)
+	for _, watcher := range all {
+		if _, exists := watchers[watcher.TaskID]; !exists {
+			watchers[watcher.TaskID] = make(map[string]*TaskWatcher)
+		}
+		watchers[watcher.TaskID][watcher.UserID] = watcher
+	}
+	r.watchers = watchers
+
+	return nil
+}
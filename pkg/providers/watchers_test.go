@@ -0,0 +1,91 @@
+package providers
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingNotifier struct {
+	calls [][]string
+}
+
+func (n *recordingNotifier) NotifyWatchers(ctx context.Context, userIDs []string, event *UniversalEvent) error {
+	n.calls = append(n.calls, userIDs)
+	return nil
+}
+
+func TestTaskWatchRegistry(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "watchers.json")
+
+	registry, err := NewTaskWatchRegistry(path, nil)
+	require.NoError(t, err)
+
+	t.Run("Watch and unwatch", func(t *testing.T) {
+		require.NoError(t, registry.WatchTask(ctx, "PROJ-1", "bob"))
+		require.NoError(t, registry.WatchTask(ctx, "PROJ-1", "alice"))
+		require.NoError(t, registry.WatchTask(ctx, "PROJ-1", "alice")) // idempotent
+		require.NoError(t, registry.WatchTask(ctx, "PROJ-2", "alice"))
+
+		assert.Equal(t, []string{"alice", "bob"}, registry.Watchers("PROJ-1"))
+		assert.Len(t, registry.WatchedTasks("alice"), 2)
+
+		require.NoError(t, registry.UnwatchTask(ctx, "PROJ-2", "alice"))
+		assert.Empty(t, registry.Watchers("PROJ-2"))
+
+		err := registry.UnwatchTask(ctx, "PROJ-2", "alice")
+		assert.True(t, IsNotFoundError(err))
+	})
+
+	t.Run("Validation", func(t *testing.T) {
+		err := registry.WatchTask(ctx, "", "alice")
+		assert.True(t, IsErrorType(err, ErrorTypeValidation))
+	})
+
+	t.Run("Persistence", func(t *testing.T) {
+		restored, err := NewTaskWatchRegistry(path, nil)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"alice", "bob"}, restored.Watchers("PROJ-1"))
+	})
+
+	t.Run("HandleEvent notifies watchers except the actor", func(t *testing.T) {
+		notifier := &recordingNotifier{}
+		registry.SetNotifier(notifier)
+
+		require.NoError(t, registry.HandleEvent(&UniversalEvent{
+			Type:   EventTypeTaskStatusChanged,
+			TaskID: "PROJ-1",
+			Data:   map[string]interface{}{"actor": "bob"},
+		}))
+		require.NoError(t, registry.HandleEvent(&UniversalEvent{
+			Type:   EventTypeTaskUpdated,
+			TaskID: "PROJ-404",
+		}))
+
+		require.Len(t, notifier.calls, 1)
+		assert.Equal(t, []string{"alice"}, notifier.calls[0])
+	})
+
+	t.Run("Watches of other processes are seen", func(t *testing.T) {
+		// Another process, e.g. the CLI next to the MCP server, shares the store
+		other, err := NewTaskWatchRegistry(path, nil)
+		require.NoError(t, err)
+		require.NoError(t, other.WatchTask(ctx, "PROJ-3", "carol"))
+
+		notifier := &recordingNotifier{}
+		registry.SetNotifier(notifier)
+		require.NoError(t, registry.HandleEvent(&UniversalEvent{Type: EventTypeTaskUpdated, TaskID: "PROJ-3"}))
+		require.Len(t, notifier.calls, 1)
+		assert.Equal(t, []string{"carol"}, notifier.calls[0])
+
+		// A change of this registry keeps the watches of the other one
+		require.NoError(t, registry.WatchTask(ctx, "PROJ-4", "alice"))
+		require.NoError(t, other.UnwatchTask(ctx, "PROJ-4", "alice"))
+		assert.Equal(t, []string{"carol"}, registry.Watchers("PROJ-3"))
+		assert.Empty(t, registry.Watchers("PROJ-4"))
+	})
+}
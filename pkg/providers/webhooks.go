@@ -0,0 +1,240 @@
+package providers
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	defaultWebhookTimeout = 10 * time.Second
+
+	// WebhookSignatureHeader carries the HMAC-SHA256 signature of the request body
+	WebhookSignatureHeader = "X-Ricochet-Signature"
+	// WebhookEventHeader carries the event type
+	WebhookEventHeader = "X-Ricochet-Event"
+	// WebhookDeliveryHeader carries the event ID, stable across retries
+	WebhookDeliveryHeader = "X-Ricochet-Delivery"
+)
+
+// WebhookEmitter POSTs task events to a single outbound webhook
+type WebhookEmitter struct {
+	name     string
+	config   *OutboundWebhookConfig
+	retry    RetryConfig
+	statuses map[string]bool
+	client   *http.Client
+	logger   *logrus.Logger
+}
+
+// NewWebhookEmitter validates the webhook configuration and creates its emitter
+func NewWebhookEmitter(config *OutboundWebhookConfig, logger *logrus.Logger) (*WebhookEmitter, error) {
+	if config == nil {
+		return nil, fmt.Errorf("webhook configuration is required")
+	}
+
+	parsed, err := url.Parse(config.URL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return nil, fmt.Errorf("invalid webhook URL %q: expected an http(s) URL", config.URL)
+	}
+	if logger == nil {
+		logger = logrus.New()
+	}
+
+	name := config.Name
+	if name == "" {
+		name = parsed.Host
+	}
+
+	timeout := config.Timeout
+	if timeout <= 0 {
+		timeout = defaultWebhookTimeout
+	}
+
+	retry := RetryConfig{
+		MaxRetries:    3,
+		InitialDelay:  1 * time.Second,
+		MaxDelay:      30 * time.Second,
+		BackoffFactor: 2.0,
+	}
+	if config.RetryConfig != nil {
+		if config.RetryConfig.MaxRetries < 0 {
+			return nil, fmt.Errorf("webhook %s: maxRetries must not be negative", name)
+		}
+		retry.MaxRetries = config.RetryConfig.MaxRetries
+		retry.Jitter = config.RetryConfig.Jitter
+		if config.RetryConfig.InitialDelay > 0 {
+			retry.InitialDelay = config.RetryConfig.InitialDelay
+		}
+		if config.RetryConfig.MaxDelay > 0 {
+			retry.MaxDelay = config.RetryConfig.MaxDelay
+		}
+		if config.RetryConfig.BackoffFactor >= 1 {
+			retry.BackoffFactor = config.RetryConfig.BackoffFactor
+		}
+	}
+
+	statuses := make(map[string]bool, len(config.Statuses))
+	for _, status := range config.Statuses {
+		statuses[strings.ToLower(status)] = true
+	}
+
+	return &WebhookEmitter{
+		name:     name,
+		config:   config,
+		retry:    retry,
+		statuses: statuses,
+		client:   &http.Client{Timeout: timeout},
+		logger:   logger,
+	}, nil
+}
+
+// Name returns the webhook name, defaulting to the URL host
+func (e *WebhookEmitter) Name() string {
+	return e.name
+}
+
+// Matches reports whether the webhook wants the event
+func (e *WebhookEmitter) Matches(event *UniversalEvent) bool {
+	if len(e.config.Events) > 0 {
+		wanted := false
+		for _, eventType := range e.config.Events {
+			if eventType == event.Type {
+				wanted = true
+				break
+			}
+		}
+		if !wanted {
+			return false
+		}
+	}
+
+	if len(e.statuses) > 0 && event.Type == EventTypeTaskStatusChanged {
+		status, _ := event.Data["status"].(string)
+		return e.statuses[strings.ToLower(status)]
+	}
+	return true
+}
+
+// HandleEvent delivers matching events. It matches EventCallback so the
+// emitter can subscribe to an EventBus directly.
+func (e *WebhookEmitter) HandleEvent(event *UniversalEvent) error {
+	if event == nil || !e.Matches(event) {
+		return nil
+	}
+	return e.Deliver(context.Background(), event)
+}
+
+// Deliver POSTs the event, retrying with exponential backoff on network
+// errors, 429 and 5xx responses
+func (e *WebhookEmitter) Deliver(ctx context.Context, event *UniversalEvent) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("webhook %s: failed to marshal event: %w", e.name, err)
+	}
+
+	var lastErr error
+	for attempt := 0; attempt <= e.retry.MaxRetries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return fmt.Errorf("webhook %s: %w (last error: %v)", e.name, ctx.Err(), lastErr)
+			case <-time.After(e.backoff(attempt)):
+			}
+		}
+
+		retry, err := e.send(ctx, event, body)
+		if err == nil {
+			return nil
+		}
+		lastErr = err
+		if !retry {
+			break
+		}
+
+		e.logger.Warnf("Webhook %s delivery of %s failed (attempt %d): %v", e.name, event.Type, attempt+1, err)
+	}
+
+	return fmt.Errorf("webhook %s: %w", e.name, lastErr)
+}
+
+// backoff returns the delay before the given retry attempt
+func (e *WebhookEmitter) backoff(attempt int) time.Duration {
+	delay := float64(e.retry.InitialDelay) * math.Pow(e.retry.BackoffFactor, float64(attempt-1))
+	if max := float64(e.retry.MaxDelay); delay > max {
+		delay = max
+	}
+	if e.retry.Jitter {
+		delay = delay/2 + rand.Float64()*delay/2
+	}
+	return time.Duration(delay)
+}
+
+// send makes a single attempt. It returns true if the error is worth retrying.
+func (e *WebhookEmitter) send(ctx context.Context, event *UniversalEvent, body []byte) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.config.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "RicochetTask/1.0")
+	for name, value := range e.config.Headers {
+		req.Header.Set(name, value)
+	}
+	req.Header.Set(WebhookEventHeader, string(event.Type))
+	req.Header.Set(WebhookDeliveryHeader, event.ID)
+	if e.config.Secret != "" {
+		req.Header.Set(WebhookSignatureHeader, SignWebhookPayload(e.config.Secret, body))
+	}
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return ctx.Err() == nil, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 {
+		return true, fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return false, fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return false, nil
+}
+
+// SignWebhookPayload returns the HMAC-SHA256 signature of body as sha256=<hex>
+func SignWebhookPayload(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// SubscribeWebhooks creates an emitter per configured webhook and subscribes it to bus.
+// Each webhook gets its own queue, so a slow endpoint does not delay the others.
+func SubscribeWebhooks(bus *EventBus, configs []*OutboundWebhookConfig, logger *logrus.Logger) error {
+	for _, config := range configs {
+		emitter, err := NewWebhookEmitter(config, logger)
+		if err != nil {
+			return err
+		}
+		if err := bus.Subscribe("webhook:"+emitter.Name(), emitter.HandleEvent, config.Events...); err != nil {
+			return fmt.Errorf("failed to subscribe webhook %s: %w", emitter.Name(), err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+package providers
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func fastRetry(maxRetries int) *RetryConfig {
+	return &RetryConfig{MaxRetries: maxRetries, InitialDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond}
+}
+
+func TestWebhookEmitter(t *testing.T) {
+	event := &UniversalEvent{
+		ID:     "evt-1",
+		Type:   EventTypeTaskStatusChanged,
+		Source: "youtrack",
+		TaskID: "PROJ-1",
+		Data:   map[string]interface{}{"status": "Ready for Deploy"},
+	}
+
+	t.Run("Signed delivery", func(t *testing.T) {
+		var body []byte
+		var headers http.Header
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ = io.ReadAll(r.Body)
+			headers = r.Header.Clone()
+		}))
+		defer server.Close()
+
+		emitter, err := NewWebhookEmitter(&OutboundWebhookConfig{
+			URL:     server.URL,
+			Secret:  "s3cret",
+			Headers: map[string]string{"Authorization": "Bearer token"},
+		}, nil)
+		require.NoError(t, err)
+		require.NoError(t, emitter.HandleEvent(event))
+
+		var received UniversalEvent
+		require.NoError(t, json.Unmarshal(body, &received))
+		assert.Equal(t, "PROJ-1", received.TaskID)
+		assert.Equal(t, SignWebhookPayload("s3cret", body), headers.Get(WebhookSignatureHeader))
+		assert.Equal(t, "task.status_changed", headers.Get(WebhookEventHeader))
+		assert.Equal(t, "evt-1", headers.Get(WebhookDeliveryHeader))
+		assert.Equal(t, "Bearer token", headers.Get("Authorization"))
+	})
+
+	t.Run("Retries transient failures", func(t *testing.T) {
+		var calls int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if atomic.AddInt32(&calls, 1) < 3 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+			}
+		}))
+		defer server.Close()
+
+		emitter, err := NewWebhookEmitter(&OutboundWebhookConfig{URL: server.URL, RetryConfig: fastRetry(3)}, nil)
+		require.NoError(t, err)
+		require.NoError(t, emitter.Deliver(context.Background(), event))
+		assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+	})
+
+	t.Run("Does not retry client errors", func(t *testing.T) {
+		var calls int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&calls, 1)
+			w.WriteHeader(http.StatusBadRequest)
+		}))
+		defer server.Close()
+
+		emitter, err := NewWebhookEmitter(&OutboundWebhookConfig{URL: server.URL, RetryConfig: fastRetry(3)}, nil)
+		require.NoError(t, err)
+		assert.Error(t, emitter.Deliver(context.Background(), event))
+		assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+	})
+
+	t.Run("Gives up after max retries", func(t *testing.T) {
+		var calls int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&calls, 1)
+			w.WriteHeader(http.StatusTooManyRequests)
+		}))
+		defer server.Close()
+
+		emitter, err := NewWebhookEmitter(&OutboundWebhookConfig{URL: server.URL, RetryConfig: fastRetry(2)}, nil)
+		require.NoError(t, err)
+		assert.Error(t, emitter.Deliver(context.Background(), event))
+		assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+	})
+
+	t.Run("Invalid URL", func(t *testing.T) {
+		_, err := NewWebhookEmitter(&OutboundWebhookConfig{URL: "ftp://example.com"}, nil)
+		assert.Error(t, err)
+	})
+}
+
+func TestWebhookEmitterMatches(t *testing.T) {
+	emitter, err := NewWebhookEmitter(&OutboundWebhookConfig{
+		URL:      "https://ci.example.com/hook",
+		Events:   []EventType{EventTypeTaskStatusChanged, EventTypeTaskCreated},
+		Statuses: []string{"Ready for Deploy"},
+	}, nil)
+	require.NoError(t, err)
+
+	status := func(name string) *UniversalEvent {
+		return &UniversalEvent{Type: EventTypeTaskStatusChanged, Data: map[string]interface{}{"status": name}}
+	}
+
+	assert.True(t, emitter.Matches(status("ready for deploy")))
+	assert.False(t, emitter.Matches(status("In Progress")))
+	assert.True(t, emitter.Matches(&UniversalEvent{Type: EventTypeTaskCreated}))
+	assert.False(t, emitter.Matches(&UniversalEvent{Type: EventTypeTaskDeleted}))
+}
+
+func TestSubscribeWebhooks(t *testing.T) {
+	var mu sync.Mutex
+	var received []EventType
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		received = append(received, EventType(r.Header.Get(WebhookEventHeader)))
+	}))
+	defer server.Close()
+
+	bus := NewEventBus(8, nil)
+	require.NoError(t, SubscribeWebhooks(bus, []*OutboundWebhookConfig{
+		{Name: "ci", URL: server.URL, Events: []EventType{EventTypeTaskDeleted}},
+	}, nil))
+
+	bus.Publish(&UniversalEvent{Type: EventTypeTaskCreated, TaskID: "PROJ-1"})
+	bus.Publish(&UniversalEvent{Type: EventTypeTaskDeleted, TaskID: "PROJ-1"})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(t, bus.Close(ctx))
+
+	assert.Equal(t, []EventType{EventTypeTaskDeleted}, received)
+
+	assert.Error(t, SubscribeWebhooks(NewEventBus(1, nil), []*OutboundWebhookConfig{{URL: "not a url"}}, nil))
+}
@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"mime/multipart"
 	"net/http"
 	"net/url"
 	"strconv"
@@ -16,6 +17,33 @@ import (
 	"github.com/grik-ai/ricochet-task/pkg/providers"
 )
 
+// usersPageSize is how many users ListUsers requests at a time
+const usersPageSize = 500
+
+// issueCustomFields requests the custom fields of issues with the values
+// translations read: names of enum, state and user values, periods and texts.
+// They are the fallback when the top-level state, priority or type of an
+// issue is missing from the response.
+const issueCustomFields = "customFields(id,name,value(id,name,login,isResolved,minutes,presentation,text))"
+
+// issueFields are the fields of a single issue GetIssue and CreateIssue return
+const issueFields = "id,idReadable,summary,description,project(id,name),state(id,name),assignee(id,name),reporter(id,name),priority(id,name),type(id,name),created,updated,resolved,tags(id,name)," + issueCustomFields + ",comments(id,text,author(id,name),created),attachments(id,name,url,size)"
+
+// activitiesPageSize is how many activities GetIssueActivities requests at a time
+const activitiesPageSize = 200
+
+// activityCategories are the kinds of issue changes GetIssueActivities returns
+var activityCategories = []string{
+	"IssueCreatedCategory",
+	"SummaryCategory",
+	"DescriptionCategory",
+	"CustomFieldCategory",
+	"TagsCategory",
+	"LinksCategory",
+	"CommentsCategory",
+	"AttachmentsCategory",
+}
+
 // YouTrackClient handles HTTP communication with YouTrack API
 type YouTrackClient struct {
 	baseURL     string
@@ -39,6 +67,11 @@ func (e *YouTrackError) Error() string {
 	return fmt.Sprintf("YouTrack API error %d: %s", e.StatusCode, e.Message)
 }
 
+// ErrorType classifies the error by its HTTP status code
+func (e *YouTrackError) ErrorType() providers.ErrorType {
+	return providers.ErrorTypeForStatus(e.StatusCode)
+}
+
 // NewYouTrackClient creates a new YouTrack client
 func NewYouTrackClient(config *providers.ProviderConfig) (*YouTrackClient, error) {
 	if config.BaseURL == "" {
@@ -61,14 +94,14 @@ func NewYouTrackClient(config *providers.ProviderConfig) (*YouTrackClient, error
 		rateLimiter = rate.NewLimiter(rate.Limit(10), 20)
 	}
 
-	// Setup HTTP client
+	// Setup HTTP client; calls are written to the HTTP debug log when it is enabled
 	httpClient := &http.Client{
 		Timeout: config.Timeout,
-		Transport: &http.Transport{
+		Transport: providers.DebugTransport(config.Name, &http.Transport{
 			MaxIdleConns:       100,
 			IdleConnTimeout:    90 * time.Second,
 			DisableCompression: true,
-		},
+		}),
 	}
 
 	client := &YouTrackClient{
@@ -89,7 +122,12 @@ func (c *YouTrackClient) CreateIssue(ctx context.Context, issue *YouTrackIssue)
 		return nil, fmt.Errorf("failed to marshal issue: %w", err)
 	}
 
-	resp, err := c.makeRequest(ctx, "POST", "/api/issues", body)
+	// Without fields YouTrack returns only the ID of the created issue
+	params := url.Values{
+		"fields": {issueFields},
+	}
+
+	resp, err := c.makeRequest(ctx, "POST", "/api/issues?"+params.Encode(), body)
 	if err != nil {
 		return nil, err
 	}
@@ -111,7 +149,7 @@ func (c *YouTrackClient) CreateIssue(ctx context.Context, issue *YouTrackIssue)
 func (c *YouTrackClient) GetIssue(ctx context.Context, id string) (*YouTrackIssue, error) {
 	path := fmt.Sprintf("/api/issues/%s", url.PathEscape(id))
 	params := url.Values{
-		"fields": {"id,idReadable,summary,description,project(id,name),state(id,name),assignee(id,name),reporter(id,name),priority(id,name),type(id,name),created,updated,resolved,customFields(id,name,value),comments(id,text,author(id,name),created),attachments(id,name,url,size)"},
+		"fields": {issueFields},
 	}
 
 	resp, err := c.makeRequest(ctx, "GET", path+"?"+params.Encode(), nil)
@@ -197,10 +235,38 @@ func (c *YouTrackClient) DeleteIssue(ctx context.Context, id string) error {
 	return nil
 }
 
+// ApplyCommand applies a YouTrack command, such as "duplicates PROJ-1", to issues
+func (c *YouTrackClient) ApplyCommand(ctx context.Context, command string, issueIDs ...string) error {
+	request := &YouTrackCommand{Query: command}
+	for _, id := range issueIDs {
+		request.Issues = append(request.Issues, &YouTrackCommandIssue{IDReadable: id})
+	}
+	body, err := json.Marshal(request)
+	if err != nil {
+		return fmt.Errorf("failed to marshal command: %w", err)
+	}
+
+	resp, err := c.makeRequest(ctx, "POST", "/api/commands", body)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return &YouTrackError{StatusCode: 404, Message: "Issue not found"}
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return c.handleErrorResponse(resp)
+	}
+
+	return nil
+}
+
 // ListIssues lists issues with filters
 func (c *YouTrackClient) ListIssues(ctx context.Context, filters *YouTrackIssueFilters) ([]*YouTrackIssue, error) {
 	params := url.Values{
-		"fields": {"id,idReadable,summary,description,project(id,name),state(id,name),assignee(id,name),reporter(id,name),priority(id,name),type(id,name),created,updated,resolved"},
+		"fields": {"id,idReadable,summary,description,project(id,name),state(id,name),assignee(id,name),reporter(id,name),priority(id,name),type(id,name),created,updated,resolved,tags(id,name),"+issueCustomFields},
 	}
 
 	// Build query string from filters
@@ -260,18 +326,19 @@ func (c *YouTrackClient) GetProjectStatuses(ctx context.Context, projectID strin
 	return states, nil
 }
 
-// BulkCreateIssues creates multiple issues in YouTrack
+// BulkCreateIssues creates multiple issues in YouTrack. If creating an issue
+// fails, the issues created before it are returned with the error.
 func (c *YouTrackClient) BulkCreateIssues(ctx context.Context, issues []*YouTrackIssue) ([]*YouTrackIssue, error) {
 	// YouTrack doesn't have native bulk create, so we create issues one by one
 	// In a production implementation, you might want to implement concurrent creation with rate limiting
-	createdIssues := make([]*YouTrackIssue, len(issues))
+	createdIssues := make([]*YouTrackIssue, 0, len(issues))
 
 	for i, issue := range issues {
 		createdIssue, err := c.CreateIssue(ctx, issue)
 		if err != nil {
-			return nil, fmt.Errorf("failed to create issue %d: %w", i, err)
+			return createdIssues, fmt.Errorf("failed to create issue %d: %w", i, err)
 		}
-		createdIssues[i] = createdIssue
+		createdIssues = append(createdIssues, createdIssue)
 	}
 
 	return createdIssues, nil
@@ -314,6 +381,67 @@ func (c *YouTrackClient) AddComment(ctx context.Context, issueID string, comment
 	return nil
 }
 
+// DownloadFile streams a file served by YouTrack, such as an attachment.
+// Attachment URLs are relative to the instance; absolute URLs must point to it
+// so the token is never sent elsewhere.
+func (c *YouTrackClient) DownloadFile(ctx context.Context, fileURL string) (io.ReadCloser, error) {
+	path := fileURL
+	if strings.HasPrefix(fileURL, "http://") || strings.HasPrefix(fileURL, "https://") {
+		if !strings.HasPrefix(fileURL, c.baseURL+"/") {
+			return nil, fmt.Errorf("file URL %s is outside the YouTrack instance", fileURL)
+		}
+		path = strings.TrimPrefix(fileURL, c.baseURL)
+	}
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+
+	resp, err := c.doRequest(ctx, "GET", path, nil, "")
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		return nil, c.handleErrorResponse(resp)
+	}
+
+	return resp.Body, nil
+}
+
+// AddAttachment uploads a file as an attachment of an issue
+func (c *YouTrackClient) AddAttachment(ctx context.Context, issueID, filename string, content io.Reader) error {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile("file", filename)
+	if err != nil {
+		return fmt.Errorf("failed to create attachment form: %w", err)
+	}
+	if _, err := io.Copy(part, content); err != nil {
+		return fmt.Errorf("failed to read attachment: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("failed to create attachment form: %w", err)
+	}
+
+	path := fmt.Sprintf("/api/issues/%s/attachments", url.PathEscape(issueID))
+	resp, err := c.doRequest(ctx, "POST", path, &body, writer.FormDataContentType())
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return &YouTrackError{StatusCode: 404, Message: "Issue not found"}
+	}
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		return c.handleErrorResponse(resp)
+	}
+
+	return nil
+}
+
 // GetComments gets comments for an issue
 func (c *YouTrackClient) GetComments(ctx context.Context, issueID string) ([]*YouTrackComment, error) {
 	path := fmt.Sprintf("/api/issues/%s/comments", url.PathEscape(issueID))
@@ -343,6 +471,190 @@ func (c *YouTrackClient) GetComments(ctx context.Context, issueID string) ([]*Yo
 	return comments, nil
 }
 
+// ListUsers gets all users of the instance, one page at a time
+func (c *YouTrackClient) ListUsers(ctx context.Context) ([]*YouTrackUser, error) {
+	var users []*YouTrackUser
+	for skip := 0; ; skip += usersPageSize {
+		params := url.Values{
+			"fields": {"id,login,name,fullName,email"},
+			"$top":   {strconv.Itoa(usersPageSize)},
+			"$skip":  {strconv.Itoa(skip)},
+		}
+
+		page, err := c.listUsersPage(ctx, params)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, page...)
+
+		if len(page) < usersPageSize {
+			return users, nil
+		}
+	}
+}
+
+// GetCurrentUser gets the user the client's token belongs to
+func (c *YouTrackClient) GetCurrentUser(ctx context.Context) (*YouTrackUser, error) {
+	resp, err := c.makeRequest(ctx, "GET", "/api/users/me?fields=id,login,name,fullName,email", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.handleErrorResponse(resp)
+	}
+
+	var user YouTrackUser
+	if err := json.NewDecoder(resp.Body).Decode(&user); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return &user, nil
+}
+
+func (c *YouTrackClient) listUsersPage(ctx context.Context, params url.Values) ([]*YouTrackUser, error) {
+	resp, err := c.makeRequest(ctx, "GET", "/api/users?"+params.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.handleErrorResponse(resp)
+	}
+
+	var users []*YouTrackUser
+	if err := json.NewDecoder(resp.Body).Decode(&users); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return users, nil
+}
+
+// ListCustomFields returns the custom fields defined in YouTrack
+func (c *YouTrackClient) ListCustomFields(ctx context.Context) ([]*YouTrackCustomFieldDefinition, error) {
+	params := url.Values{
+		"fields": {"id,name,localizedName,fieldType(id,presentation)"},
+		"$top":   {"-1"},
+	}
+
+	resp, err := c.makeRequest(ctx, "GET", "/api/admin/customFieldSettings/customFields?"+params.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.handleErrorResponse(resp)
+	}
+
+	var fields []*YouTrackCustomFieldDefinition
+	if err := json.NewDecoder(resp.Body).Decode(&fields); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return fields, nil
+}
+
+// ListTags returns the tags visible to the client's user
+func (c *YouTrackClient) ListTags(ctx context.Context) ([]*YouTrackTag, error) {
+	params := url.Values{
+		"fields": {"id,name,color(id,background)"},
+		"$top":   {"-1"},
+	}
+
+	resp, err := c.makeRequest(ctx, "GET", "/api/tags?"+params.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.handleErrorResponse(resp)
+	}
+
+	var tags []*YouTrackTag
+	if err := json.NewDecoder(resp.Body).Decode(&tags); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return tags, nil
+}
+
+// CreateTag creates a tag owned by the client's user
+func (c *YouTrackClient) CreateTag(ctx context.Context, name string) (*YouTrackTag, error) {
+	body, err := json.Marshal(&YouTrackTag{Name: name})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal tag: %w", err)
+	}
+
+	resp, err := c.makeRequest(ctx, "POST", "/api/tags?fields=id,name,color(id,background)", body)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		return nil, c.handleErrorResponse(resp)
+	}
+
+	var tag YouTrackTag
+	if err := json.NewDecoder(resp.Body).Decode(&tag); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return &tag, nil
+}
+
+// GetIssueActivities returns the change history of an issue, oldest first
+func (c *YouTrackClient) GetIssueActivities(ctx context.Context, id string) ([]*YouTrackActivity, error) {
+	path := fmt.Sprintf("/api/issues/%s/activities", url.PathEscape(id))
+
+	var activities []*YouTrackActivity
+	for skip := 0; ; skip += activitiesPageSize {
+		params := url.Values{
+			"fields":     {"id,timestamp,author(id,login,name,fullName),category(id),field(id,name),added(id,name,login,fullName,text,idReadable,presentation),removed(id,name,login,fullName,text,idReadable,presentation)"},
+			"categories": {strings.Join(activityCategories, ",")},
+			"$top":       {strconv.Itoa(activitiesPageSize)},
+			"$skip":      {strconv.Itoa(skip)},
+		}
+
+		page, err := c.getActivitiesPage(ctx, path+"?"+params.Encode())
+		if err != nil {
+			return nil, err
+		}
+		activities = append(activities, page...)
+
+		if len(page) < activitiesPageSize {
+			return activities, nil
+		}
+	}
+}
+
+func (c *YouTrackClient) getActivitiesPage(ctx context.Context, path string) ([]*YouTrackActivity, error) {
+	resp, err := c.makeRequest(ctx, "GET", path, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, &YouTrackError{StatusCode: 404, Message: "Issue not found"}
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.handleErrorResponse(resp)
+	}
+
+	var activities []*YouTrackActivity
+	if err := json.NewDecoder(resp.Body).Decode(&activities); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return activities, nil
+}
+
 // HealthCheck performs a health check by getting server configuration
 func (c *YouTrackClient) HealthCheck(ctx context.Context) error {
 	resp, err := c.makeRequest(ctx, "GET", "/api/config", nil)
@@ -358,17 +670,57 @@ func (c *YouTrackClient) HealthCheck(ctx context.Context) error {
 	return nil
 }
 
+// GetServerVersion returns the version and build of the YouTrack server.
+// Fields missing from the response are left empty.
+func (c *YouTrackClient) GetServerVersion(ctx context.Context) (*YouTrackServerVersion, error) {
+	params := url.Values{
+		"fields": {"version,build"},
+	}
+
+	resp, err := c.makeRequest(ctx, "GET", "/api/config?"+params.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, c.handleErrorResponse(resp)
+	}
+
+	// Decoded loosely, servers have reported the build both as a string and
+	// as a number
+	var config map[string]interface{}
+	if err := json.NewDecoder(resp.Body).Decode(&config); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	version := &YouTrackServerVersion{}
+	if value, ok := config["version"]; ok && value != nil {
+		version.Version = fmt.Sprint(value)
+	}
+	if value, ok := config["build"]; ok && value != nil {
+		version.Build = fmt.Sprint(value)
+	}
+	return version, nil
+}
+
 // Close closes the client and cleans up resources
 func (c *YouTrackClient) Close() error {
 	// Close HTTP client connections
-	if transport, ok := c.httpClient.Transport.(*http.Transport); ok {
-		transport.CloseIdleConnections()
-	}
+	c.httpClient.CloseIdleConnections()
 	return nil
 }
 
 // makeRequest makes an HTTP request to YouTrack API
 func (c *YouTrackClient) makeRequest(ctx context.Context, method, path string, body []byte) (*http.Response, error) {
+	if body == nil {
+		return c.doRequest(ctx, method, path, nil, "")
+	}
+	return c.doRequest(ctx, method, path, bytes.NewReader(body), "application/json")
+}
+
+// doRequest makes an authenticated request with a body of the given content type
+func (c *YouTrackClient) doRequest(ctx context.Context, method, path string, body io.Reader, contentType string) (*http.Response, error) {
 	// Rate limiting
 	if err := c.rateLimiter.Wait(ctx); err != nil {
 		return nil, fmt.Errorf("rate limiter error: %w", err)
@@ -376,12 +728,7 @@ func (c *YouTrackClient) makeRequest(ctx context.Context, method, path string, b
 
 	// Create request
 	url := c.baseURL + path
-	var bodyReader io.Reader
-	if body != nil {
-		bodyReader = bytes.NewReader(body)
-	}
-
-	req, err := http.NewRequestWithContext(ctx, method, url, bodyReader)
+	req, err := http.NewRequestWithContext(ctx, method, url, body)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
@@ -390,9 +737,9 @@ func (c *YouTrackClient) makeRequest(ctx context.Context, method, path string, b
 	req.Header.Set("Authorization", "Bearer "+c.token)
 	req.Header.Set("User-Agent", c.userAgent)
 	req.Header.Set("Accept", "application/json")
-	
-	if body != nil {
-		req.Header.Set("Content-Type", "application/json")
+
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
 	}
 
 	// Make request
@@ -3,8 +3,11 @@ package youtrack
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
@@ -303,6 +306,126 @@ func TestDeleteIssue(t *testing.T) {
 	})
 }
 
+// TestAttachmentFiles tests attachment download and upload
+func TestAttachmentFiles(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer test-token", r.Header.Get("Authorization"))
+
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/api/files/1-2":
+			w.Write([]byte("log contents"))
+
+		case r.Method == "POST" && r.URL.Path == "/api/issues/PROJ-1/attachments":
+			file, header, err := r.FormFile("file")
+			require.NoError(t, err)
+			data, _ := io.ReadAll(file)
+			assert.Equal(t, "build.log", header.Filename)
+			assert.Equal(t, "log contents", string(data))
+			w.WriteHeader(http.StatusOK)
+
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	config := &providers.ProviderConfig{
+		BaseURL: server.URL,
+		Token:   "test-token",
+	}
+
+	client, err := NewYouTrackClient(config)
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	t.Run("Download relative and absolute URLs", func(t *testing.T) {
+		for _, fileURL := range []string{"/api/files/1-2", server.URL + "/api/files/1-2"} {
+			body, err := client.DownloadFile(ctx, fileURL)
+			require.NoError(t, err)
+			data, _ := io.ReadAll(body)
+			body.Close()
+			assert.Equal(t, "log contents", string(data))
+		}
+	})
+
+	t.Run("Refuse URLs of other hosts", func(t *testing.T) {
+		_, err := client.DownloadFile(ctx, "https://example.com/api/files/1-2")
+		assert.Error(t, err)
+	})
+
+	t.Run("Upload", func(t *testing.T) {
+		err := client.AddAttachment(ctx, "PROJ-1", "build.log", strings.NewReader("log contents"))
+		assert.NoError(t, err)
+	})
+}
+
+// TestListUsers tests listing users page by page
+func TestListUsers(t *testing.T) {
+	var skips []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/users", r.URL.Path)
+		skips = append(skips, r.URL.Query().Get("$skip"))
+
+		count := usersPageSize
+		if r.URL.Query().Get("$skip") != "0" {
+			count = 2
+		}
+		users := make([]*YouTrackUser, count)
+		for i := range users {
+			users[i] = &YouTrackUser{ID: fmt.Sprintf("1-%d", i), Login: fmt.Sprintf("user%d", i)}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(users)
+	}))
+	defer server.Close()
+
+	config := &providers.ProviderConfig{
+		BaseURL: server.URL,
+		Token:   "test-token",
+	}
+
+	client, err := NewYouTrackClient(config)
+	require.NoError(t, err)
+
+	users, err := client.ListUsers(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, users, usersPageSize+2)
+	assert.Equal(t, []string{"0", strconv.Itoa(usersPageSize)}, skips)
+}
+
+// TestGetIssueActivities tests reading and converting the change history of an issue
+func TestGetIssueActivities(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/issues/OPS-1/activities", r.URL.Path)
+		assert.Contains(t, r.URL.Query().Get("categories"), "CustomFieldCategory")
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`[
+			{"timestamp": 1759309200000, "author": {"login": "alice"}, "category": {"id": "IssueCreatedCategory"}, "added": [{"idReadable": "OPS-1"}]},
+			{"timestamp": 1759312800000, "author": {"login": "bob"}, "category": {"id": "CustomFieldCategory"}, "field": {"name": "Priority"},
+			 "added": [{"name": "Critical"}], "removed": [{"name": "Normal"}]},
+			{"timestamp": 1759316400000, "author": {"login": "bob"}, "category": {"id": "SummaryCategory"}, "field": {"name": "summary"},
+			 "added": "Rotate all certificates", "removed": "Rotate certificates"},
+			{"timestamp": 1759320000000, "author": {"name": "Carol"}, "category": {"id": "CustomFieldCategory"}, "field": {"name": "Story points"},
+			 "added": 5, "removed": null}
+		]`))
+	}))
+	defer server.Close()
+
+	provider, err := createTestProvider(server.URL, "test-token")
+	require.NoError(t, err)
+
+	entries, err := provider.GetActivity(context.Background(), "OPS-1")
+	require.NoError(t, err)
+	require.Len(t, entries, 4)
+
+	assert.Equal(t, providers.ActivityEntry{Actor: "alice", Field: providers.ActivityFieldCreated, At: time.UnixMilli(1759309200000)}, entries[0])
+	assert.Equal(t, providers.ActivityEntry{Actor: "bob", Field: providers.ActivityFieldPriority, From: "Normal", To: "Critical", At: time.UnixMilli(1759312800000)}, entries[1])
+	assert.Equal(t, providers.ActivityFieldTitle, entries[2].Field)
+	assert.Equal(t, "Rotate certificates", entries[2].From)
+	assert.Equal(t, providers.ActivityEntry{Actor: "Carol", Field: "Story points", To: "5", At: time.UnixMilli(1759320000000)}, entries[3])
+}
+
 // TestBulkOperations tests bulk operations
 func TestBulkOperations(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -1,7 +1,9 @@
 package youtrack
 
 import (
+	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 )
 
@@ -98,6 +100,14 @@ type YouTrackPriority struct {
 	Color       *YouTrackColor `json:"color,omitempty"`
 }
 
+// GetName returns the name of the priority, empty when there is none
+func (p *YouTrackPriority) GetName() string {
+	if p == nil {
+		return ""
+	}
+	return p.Name
+}
+
 // YouTrackIssueType represents an issue type
 type YouTrackIssueType struct {
 	ID          string `json:"id,omitempty"`
@@ -106,12 +116,38 @@ type YouTrackIssueType struct {
 	AutoAttached bool  `json:"autoAttached,omitempty"`
 }
 
+// GetName returns the name of the type, empty when there is none
+func (t *YouTrackIssueType) GetName() string {
+	if t == nil {
+		return ""
+	}
+	return t.Name
+}
+
 // YouTrackDuration represents time duration in YouTrack
 type YouTrackDuration struct {
 	Minutes     int    `json:"minutes,omitempty"`
 	Presentation string `json:"presentation,omitempty"`
 }
 
+// YouTrackServerVersion is the version of a YouTrack server as reported by
+// /api/config
+type YouTrackServerVersion struct {
+	Version string `json:"version,omitempty"`
+	Build   string `json:"build,omitempty"`
+}
+
+// String returns the version with its build, e.g. "2024.3 (build 52635)"
+func (v *YouTrackServerVersion) String() string {
+	if v == nil || v.Version == "" {
+		return ""
+	}
+	if v.Build == "" {
+		return v.Version
+	}
+	return fmt.Sprintf("%s (build %s)", v.Version, v.Build)
+}
+
 // YouTrackCustomField represents a custom field value
 type YouTrackCustomField struct {
 	ID    string      `json:"id,omitempty"`
@@ -122,7 +158,8 @@ type YouTrackCustomField struct {
 
 // YouTrackCustomFieldUpdate represents custom field update
 type YouTrackCustomFieldUpdate struct {
-	ID    string      `json:"id"`
+	ID    string      `json:"id,omitempty"`
+	Name  string      `json:"name,omitempty"`
 	Value interface{} `json:"value"`
 }
 
@@ -159,6 +196,29 @@ type YouTrackComment struct {
 	PermittedGroup *YouTrackUserGroup `json:"permittedGroup,omitempty"`
 }
 
+// YouTrackActivity represents an entry of an issue's change history. Added and
+// Removed hold a string for text fields and a list of entities otherwise.
+type YouTrackActivity struct {
+	ID        string                    `json:"id,omitempty"`
+	Timestamp int64                     `json:"timestamp,omitempty"`
+	Author    *YouTrackUser             `json:"author,omitempty"`
+	Category  *YouTrackActivityCategory `json:"category,omitempty"`
+	Field     *YouTrackActivityField    `json:"field,omitempty"`
+	Added     json.RawMessage           `json:"added,omitempty"`
+	Removed   json.RawMessage           `json:"removed,omitempty"`
+}
+
+// YouTrackActivityCategory is the kind of change of an activity
+type YouTrackActivityCategory struct {
+	ID string `json:"id"`
+}
+
+// YouTrackActivityField is the issue field an activity changed
+type YouTrackActivityField struct {
+	ID   string `json:"id,omitempty"`
+	Name string `json:"name"`
+}
+
 // YouTrackAttachment represents a file attachment
 type YouTrackAttachment struct {
 	ID          string        `json:"id,omitempty"`
@@ -190,6 +250,17 @@ type YouTrackIssueLinkType struct {
 	Directed    bool   `json:"directed,omitempty"`
 }
 
+// YouTrackCommand is a command applied to issues, such as "duplicates PROJ-1"
+type YouTrackCommand struct {
+	Query  string                `json:"query"`
+	Issues []*YouTrackCommandIssue `json:"issues"`
+}
+
+// YouTrackCommandIssue identifies an issue a command applies to
+type YouTrackCommandIssue struct {
+	IDReadable string `json:"idReadable"`
+}
+
 // YouTrackTag represents a tag
 type YouTrackTag struct {
 	ID    string `json:"id,omitempty"`
@@ -330,6 +401,13 @@ func (a *YouTrackAttachment) GetCreatedTime() time.Time {
 	return time.Unix(a.Created/1000, 0)
 }
 
+func (a *YouTrackActivity) GetTime() time.Time {
+	if a.Timestamp == 0 {
+		return time.Time{}
+	}
+	return time.UnixMilli(a.Timestamp)
+}
+
 func (w *YouTrackWorkItem) GetDateTime() time.Time {
 	if w.Date == 0 {
 		return time.Time{}
@@ -428,6 +506,44 @@ func (i *YouTrackIssue) GetCustomFieldStringValue(fieldName string) string {
 	return ""
 }
 
+// GetCustomFieldEnumValue returns the name of the value of the first of the
+// given custom fields an issue has, e.g. of a State or Priority field, and
+// whether the value is a resolved state. Values are read defensively: an
+// object with a name, a plain string or the first of several values.
+func (i *YouTrackIssue) GetCustomFieldEnumValue(fieldNames ...string) (name string, isResolved bool, ok bool) {
+	for _, fieldName := range fieldNames {
+		for _, field := range i.CustomFields {
+			if field == nil || !strings.EqualFold(field.Name, fieldName) {
+				continue
+			}
+			if name, isResolved, ok = enumValueName(field.Value); ok {
+				return name, isResolved, true
+			}
+		}
+	}
+	return "", false, false
+}
+
+// enumValueName reads the name of a custom field value
+func enumValueName(value interface{}) (string, bool, bool) {
+	switch v := value.(type) {
+	case string:
+		return v, false, v != ""
+	case map[string]interface{}:
+		for _, key := range []string{"name", "localizedName", "presentation"} {
+			if name, ok := v[key].(string); ok && name != "" {
+				isResolved, _ := v["isResolved"].(bool)
+				return name, isResolved, true
+			}
+		}
+	case []interface{}:
+		if len(v) > 0 {
+			return enumValueName(v[0])
+		}
+	}
+	return "", false, false
+}
+
 // Helper methods for issue hierarchy
 func (i *YouTrackIssue) IsSubtask() bool {
 	return i.Parent != nil
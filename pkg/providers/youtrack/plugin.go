@@ -89,6 +89,11 @@ func (p *YouTrackPlugin) GetAnalyticsProvider() providers.AnalyticsProvider {
 	return nil
 }
 
+// DefaultConfig returns the defaults for new YouTrack provider configurations
+func (p *YouTrackPlugin) DefaultConfig() *providers.ProviderConfig {
+	return GetDefaultConfig()
+}
+
 // Cleanup cleans up plugin resources
 func (p *YouTrackPlugin) Cleanup() error {
 	if p.provider != nil {
@@ -256,6 +261,9 @@ func GetCapabilities() []providers.Capability {
 		providers.CapabilityReporting,
 		providers.CapabilityAdvancedSearch,
 		providers.CapabilityWebhooks,
+		providers.CapabilitySprints,
+		providers.CapabilityAttachments,
+		providers.CapabilityComments,
 	}
 }
 
@@ -288,5 +296,5 @@ func GetSupportedFeatures() map[string]bool {
 // Plugin factory function for registration
 func init() {
 	// Register the plugin factory
-	providers.RegisterPluginFactory(string(providers.ProviderTypeYouTrack), NewYouTrackPlugin)
+	providers.RegisterProviderType(providers.ProviderTypeYouTrack, NewYouTrackPlugin)
 }
\ No newline at end of file
@@ -3,6 +3,8 @@ package youtrack
 import (
 	"context"
 	"fmt"
+	"io"
+	"sync"
 	"time"
 
 	"github.com/sirupsen/logrus"
@@ -15,6 +17,11 @@ type YouTrackProvider struct {
 	config     *providers.ProviderConfig
 	translator *YouTrackTranslator
 	logger     *logrus.Entry
+
+	// apiVersion is the server version detected by the first successful
+	// health check
+	apiVersion   string
+	apiVersionMu sync.RWMutex
 }
 
 // NewYouTrackProvider creates a new YouTrack provider
@@ -33,10 +40,16 @@ func NewYouTrackProvider(config *providers.ProviderConfig) (*YouTrackProvider, e
 		"instance": config.Name,
 	})
 
+	translator := NewYouTrackTranslator()
+	translator.SetStatusCategories(config.StatusMapping)
+	translator.SetFieldMapping(config.FieldMapping)
+	translator.SetPriorityMapping(config.PriorityMapping)
+	translator.SetLogger(logger)
+
 	return &YouTrackProvider{
 		client:     client,
 		config:     config,
-		translator: NewYouTrackTranslator(),
+		translator: translator,
 		logger:     logger,
 	}, nil
 }
@@ -220,10 +233,7 @@ func (p *YouTrackProvider) BulkCreateTasks(ctx context.Context, tasks []*provide
 	}
 
 	// Create in YouTrack (batch operation if supported)
-	createdIssues, err := p.client.BulkCreateIssues(ctx, ytIssues)
-	if err != nil {
-		return nil, fmt.Errorf("failed to bulk create issues in YouTrack: %w", err)
-	}
+	createdIssues, bulkErr := p.client.BulkCreateIssues(ctx, ytIssues)
 
 	// Convert back to universal format
 	universalTasks := make([]*providers.UniversalTask, len(createdIssues))
@@ -237,6 +247,12 @@ func (p *YouTrackProvider) BulkCreateTasks(ctx context.Context, tasks []*provide
 		universalTasks[i].ProviderConfig = p.config
 	}
 
+	// The issues created before a failure are returned, so that callers can
+	// undo or report them
+	if bulkErr != nil {
+		return universalTasks, fmt.Errorf("failed to bulk create issues in YouTrack: %w", bulkErr)
+	}
+
 	p.logger.WithField("count", len(universalTasks)).Info("Tasks bulk created successfully in YouTrack")
 	return universalTasks, nil
 }
@@ -269,6 +285,7 @@ func (p *YouTrackProvider) GetProviderInfo() *providers.ProviderInfo {
 	return &providers.ProviderInfo{
 		Name:        "YouTrack",
 		Version:     "1.0.0",
+		APIVersion:  p.APIVersion(),
 		Description: "JetBrains YouTrack integration for ricochet-task",
 		Capabilities: []providers.Capability{
 			providers.CapabilityTasks,
@@ -281,6 +298,11 @@ func (p *YouTrackProvider) GetProviderInfo() *providers.ProviderInfo {
 			providers.CapabilityReporting,
 			providers.CapabilityAdvancedSearch,
 			providers.CapabilityWebhooks,
+			providers.CapabilitySprints,
+			providers.CapabilityAttachments,
+			providers.CapabilityComments,
+			providers.CapabilityUsers,
+			providers.CapabilityActivity,
 		},
 		SupportedFeatures: map[string]bool{
 			"hierarchical_tasks": true,
@@ -314,9 +336,40 @@ func (p *YouTrackProvider) HealthCheck(ctx context.Context) error {
 	}
 
 	p.logger.Debug("YouTrack health check passed")
+
+	if p.APIVersion() == "" {
+		p.detectAPIVersion(ctx)
+	}
 	return nil
 }
 
+// APIVersion returns the version of the YouTrack server, empty until a
+// health check detected it
+func (p *YouTrackProvider) APIVersion() string {
+	p.apiVersionMu.RLock()
+	defer p.apiVersionMu.RUnlock()
+	return p.apiVersion
+}
+
+// detectAPIVersion records the version of the server. Servers that don't
+// report it are only logged: the provider works without knowing it.
+func (p *YouTrackProvider) detectAPIVersion(ctx context.Context) {
+	version, err := p.client.GetServerVersion(ctx)
+	if err != nil {
+		p.logger.WithError(err).Debug("Failed to detect the YouTrack version")
+		return
+	}
+	if version.String() == "" {
+		p.logger.Warn("YouTrack didn't report its version, the response may have changed")
+		return
+	}
+
+	p.apiVersionMu.Lock()
+	p.apiVersion = version.String()
+	p.apiVersionMu.Unlock()
+	p.logger.WithField("api_version", p.apiVersion).Debug("Detected YouTrack version")
+}
+
 // Close closes the provider and cleans up resources
 func (p *YouTrackProvider) Close() error {
 	p.logger.Info("Closing YouTrack provider")
@@ -398,6 +451,129 @@ func (p *YouTrackProvider) GetComments(ctx context.Context, taskID string) ([]*p
 	return comments, nil
 }
 
+// ListUsers returns the users of the YouTrack instance
+func (p *YouTrackProvider) ListUsers(ctx context.Context) ([]*providers.User, error) {
+	ytUsers, err := p.client.ListUsers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list users from YouTrack: %w", err)
+	}
+
+	users := make([]*providers.User, len(ytUsers))
+	for i, ytUser := range ytUsers {
+		users[i] = universalUser(ytUser)
+	}
+
+	return users, nil
+}
+
+// GetCurrentUser returns the YouTrack user the token belongs to
+func (p *YouTrackProvider) GetCurrentUser(ctx context.Context) (*providers.User, error) {
+	ytUser, err := p.client.GetCurrentUser(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current user from YouTrack: %w", err)
+	}
+	return universalUser(ytUser), nil
+}
+
+func universalUser(ytUser *YouTrackUser) *providers.User {
+	name := ytUser.FullName
+	if name == "" {
+		name = ytUser.Name
+	}
+	return &providers.User{
+		ID:    ytUser.ID,
+		Login: ytUser.Login,
+		Name:  name,
+		Email: ytUser.Email,
+	}
+}
+
+// ListCustomFields returns the custom fields defined in YouTrack
+func (p *YouTrackProvider) ListCustomFields(ctx context.Context) ([]*providers.CustomFieldInfo, error) {
+	ytFields, err := p.client.ListCustomFields(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list custom fields from YouTrack: %w", err)
+	}
+
+	fields := make([]*providers.CustomFieldInfo, len(ytFields))
+	for i, ytField := range ytFields {
+		fields[i] = &providers.CustomFieldInfo{Name: ytField.Name}
+		if ytField.FieldType != nil {
+			fields[i].Type = ytField.FieldType.ID
+		}
+	}
+
+	return fields, nil
+}
+
+// ListLabels returns the YouTrack tags. Tags aren't per project in YouTrack,
+// so projectID is ignored.
+func (p *YouTrackProvider) ListLabels(ctx context.Context, projectID string) ([]*providers.Label, error) {
+	tags, err := p.client.ListTags(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tags from YouTrack: %w", err)
+	}
+
+	labels := make([]*providers.Label, len(tags))
+	for i, tag := range tags {
+		labels[i] = universalLabel(tag)
+	}
+	return labels, nil
+}
+
+// CreateLabel creates a YouTrack tag
+func (p *YouTrackProvider) CreateLabel(ctx context.Context, projectID, name string) (*providers.Label, error) {
+	tag, err := p.client.CreateTag(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create tag in YouTrack: %w", err)
+	}
+	return universalLabel(tag), nil
+}
+
+func universalLabel(tag *YouTrackTag) *providers.Label {
+	label := &providers.Label{ID: tag.ID, Name: tag.Name}
+	if tag.Color != nil {
+		label.Color = tag.Color.Background
+	}
+	return label
+}
+
+// GetActivity returns the change history of an issue
+func (p *YouTrackProvider) GetActivity(ctx context.Context, taskID string) ([]providers.ActivityEntry, error) {
+	activities, err := p.client.GetIssueActivities(ctx, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get activity from YouTrack: %w", err)
+	}
+
+	entries := make([]providers.ActivityEntry, len(activities))
+	for i, activity := range activities {
+		entries[i] = p.translator.YouTrackActivityToUniversal(activity)
+	}
+
+	return entries, nil
+}
+
+func (p *YouTrackProvider) DownloadAttachment(ctx context.Context, attachment *providers.Attachment) (io.ReadCloser, error) {
+	content, err := p.client.DownloadFile(ctx, attachment.URL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download attachment %s from YouTrack: %w", attachment.Filename, err)
+	}
+
+	return content, nil
+}
+
+func (p *YouTrackProvider) UploadAttachment(ctx context.Context, taskID, filename string, content io.Reader) error {
+	err := p.client.AddAttachment(ctx, taskID, filename, content)
+	if err != nil {
+		if IsNotFoundError(err) {
+			return providers.ErrTaskNotFound
+		}
+		return fmt.Errorf("failed to upload attachment to YouTrack: %w", err)
+	}
+
+	return nil
+}
+
 // IsNotFoundError checks if an error is a "not found" error from YouTrack
 func IsNotFoundError(err error) bool {
 	if ytErr, ok := err.(*YouTrackError); ok {
@@ -422,6 +598,54 @@ func IsUnauthorizedError(err error) bool {
 	return false
 }
 
+// LinkTasks links two issues with the YouTrack command for the link type
+func (p *YouTrackProvider) LinkTasks(ctx context.Context, sourceID, targetID string, linkType providers.TaskLinkType) error {
+	command, err := linkCommand(linkType, targetID)
+	if err != nil {
+		return err
+	}
+
+	if err := p.client.ApplyCommand(ctx, command, sourceID); err != nil {
+		if IsNotFoundError(err) {
+			return providers.ErrTaskNotFound
+		}
+		return fmt.Errorf("failed to link issues in YouTrack: %w", err)
+	}
+	return nil
+}
+
+// UnlinkTasks removes a link between two issues with the "remove" form of
+// the link command
+func (p *YouTrackProvider) UnlinkTasks(ctx context.Context, sourceID, targetID string, linkType providers.TaskLinkType) error {
+	command, err := linkCommand(linkType, targetID)
+	if err != nil {
+		return err
+	}
+
+	if err := p.client.ApplyCommand(ctx, "remove "+command, sourceID); err != nil {
+		if IsNotFoundError(err) {
+			return providers.ErrTaskNotFound
+		}
+		return fmt.Errorf("failed to unlink issues in YouTrack: %w", err)
+	}
+	return nil
+}
+
+// linkCommand returns the YouTrack command that links an issue to targetID
+func linkCommand(linkType providers.TaskLinkType, targetID string) (string, error) {
+	switch linkType {
+	case providers.TaskLinkDuplicateOf:
+		return "duplicates " + targetID, nil
+	case providers.TaskLinkSubtaskOf:
+		return "subtask of " + targetID, nil
+	case providers.TaskLinkDependsOn:
+		return "depends on " + targetID, nil
+	case providers.TaskLinkBlocks:
+		return "is required for " + targetID, nil
+	}
+	return "", providers.NewValidationError(fmt.Sprintf("unsupported link type %q", linkType), nil)
+}
+
 // SearchTasks searches for tasks with a query string
 func (p *YouTrackProvider) SearchTasks(ctx context.Context, query string, filters *providers.TaskFilters) ([]*providers.UniversalTask, error) {
 	if query == "" {
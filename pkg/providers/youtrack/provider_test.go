@@ -10,6 +10,8 @@ import (
 	"testing"
 	"time"
 
+	"github.com/sirupsen/logrus"
+	logrustest "github.com/sirupsen/logrus/hooks/test"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -468,6 +470,93 @@ func TestProviderInfo(t *testing.T) {
 	}
 }
 
+// TestStatusCategories tests configured status categories of custom states
+func TestStatusCategories(t *testing.T) {
+	config := &providers.ProviderConfig{
+		Name:     "test-youtrack",
+		Type:     providers.ProviderTypeYouTrack,
+		BaseURL:  "https://youtrack.example.com",
+		Token:    "test-token",
+		AuthType: providers.AuthTypeBearer,
+		Timeout:  30 * time.Second,
+		StatusMapping: providers.StatusMapping{
+			"qa sign-off": providers.StatusCategoryReview,
+			"Fixed":       providers.StatusCategoryTesting,
+		},
+	}
+	provider, err := NewYouTrackProvider(config)
+	require.NoError(t, err)
+	translator := provider.translator
+
+	status := translator.YouTrackStatusToUniversal(&YouTrackState{Name: "QA Sign-off", IsResolved: true})
+	assert.Equal(t, "qa_sign-off", status.ID)
+	assert.Equal(t, providers.StatusCategoryReview, status.Category)
+	assert.False(t, status.IsFinal)
+
+	task := translator.YouTrackToUniversal(&YouTrackIssue{ID: "2-1", State: &YouTrackState{Name: "Fixed", IsResolved: true}})
+	assert.Equal(t, "fixed", task.Status.ID)
+	assert.Equal(t, providers.StatusCategoryTesting, task.Status.Category)
+
+	// Unmapped states keep the built-in mapping and heuristics
+	assert.Equal(t, providers.StatusCategoryDone, translator.YouTrackStatusToUniversal(&YouTrackState{Name: "Verified"}).Category)
+	assert.Equal(t, providers.StatusCategoryInProgress, translator.YouTrackStatusToUniversal(&YouTrackState{Name: "In Development"}).Category)
+}
+
+// TestFieldMapping tests promoting mapped custom fields to typed task fields and back
+func TestFieldMapping(t *testing.T) {
+	translator := NewYouTrackTranslator()
+	translator.SetFieldMapping(providers.FieldMapping{
+		"Story points": providers.TaskFieldStoryPoints,
+		"Estimation":   providers.TaskFieldEstimatedTime,
+	})
+
+	task := translator.YouTrackToUniversal(&YouTrackIssue{ID: "2-1", CustomFields: []*YouTrackCustomField{
+		{Name: "Story points", Value: 5.0},
+		{Name: "Estimation", Value: map[string]interface{}{"minutes": 120.0, "presentation": "2h"}},
+		{Name: "Team", Value: "Platform"},
+	}})
+	require.NotNil(t, task.StoryPoints)
+	assert.Equal(t, 5.0, *task.StoryPoints)
+	require.NotNil(t, task.EstimatedTime)
+	assert.Equal(t, 2*time.Hour, *task.EstimatedTime)
+	assert.Equal(t, map[string]interface{}{"Team": "Platform"}, task.CustomFields)
+
+	points := 8.0
+	update := &providers.TaskUpdate{StoryPoints: &points}
+	ytUpdate := translator.UniversalUpdatesToYouTrack(update)
+	require.Len(t, ytUpdate.CustomFields, 1)
+	assert.Equal(t, "Story points", ytUpdate.CustomFields[0].Name)
+	assert.Equal(t, 8.0, ytUpdate.CustomFields[0].Value)
+	assert.NotNil(t, update.StoryPoints, "the caller's update is not modified")
+
+	estimate := 90 * time.Minute
+	issue := translator.UniversalToYouTrack(&providers.UniversalTask{Title: "Estimated", EstimatedTime: &estimate})
+	require.Len(t, issue.CustomFields, 1)
+	assert.Equal(t, "Estimation", issue.CustomFields[0].Name)
+	assert.Equal(t, 90, issue.CustomFields[0].Value.(*YouTrackDuration).Minutes)
+}
+
+// TestPriorityMapping tests configured priority values in both directions
+func TestPriorityMapping(t *testing.T) {
+	translator := NewYouTrackTranslator()
+	assert.Equal(t, "Show-stopper", translator.UniversalToYouTrack(&providers.UniversalTask{Priority: providers.TaskPriorityCritical}).Priority.Name)
+
+	translator.SetPriorityMapping(providers.PriorityMapping{
+		providers.TaskPriorityCritical: "Blocker",
+		providers.TaskPriorityHighest:  "Blocker",
+	})
+	assert.Equal(t, "Blocker", translator.UniversalToYouTrack(&providers.UniversalTask{Priority: providers.TaskPriorityCritical}).Priority.Name)
+	assert.Equal(t, "Major", translator.UniversalToYouTrack(&providers.UniversalTask{Priority: providers.TaskPriorityHigh}).Priority.Name)
+
+	task := translator.YouTrackToUniversal(&YouTrackIssue{ID: "2-1", Priority: &YouTrackPriority{Name: "blocker"}})
+	assert.Equal(t, providers.TaskPriorityHighest, task.Priority)
+	task = translator.YouTrackToUniversal(&YouTrackIssue{ID: "2-1", Priority: &YouTrackPriority{Name: "Urgent"}})
+	assert.Equal(t, providers.TaskPriorityMedium, task.Priority)
+
+	filters := translator.UniversalFiltersToYouTrack(&providers.TaskFilters{Priority: []string{"critical", "highest"}})
+	assert.Equal(t, "Blocker", filters.Priority)
+}
+
 // TestClose tests provider cleanup
 func TestClose(t *testing.T) {
 	server := createMockServer()
@@ -631,3 +720,178 @@ func BenchmarkListTasks(b *testing.B) {
 		}
 	}
 }
+
+// TestLabelFilters tests the translation of label conditions into tag queries
+func TestLabelFilters(t *testing.T) {
+	translator := NewYouTrackTranslator()
+
+	tests := []struct {
+		name     string
+		filters  *providers.TaskFilters
+		expected string
+	}{
+		{"All", &providers.TaskFilters{LabelsAll: []string{"backend", "urgent"}}, "tag: {backend} and tag: {urgent}"},
+		{"Labels means all", &providers.TaskFilters{Labels: []string{"backend"}, LabelsAll: []string{"Backend", "urgent"}}, "tag: {backend} and tag: {urgent}"},
+		{"Any", &providers.TaskFilters{LabelsAny: []string{"backend", "frontend"}}, "(tag: {backend} or tag: {frontend})"},
+		{"None", &providers.TaskFilters{LabelsNone: []string{"wontfix", "duplicate"}}, "tag: -{wontfix} and tag: -{duplicate}"},
+		{"Combined with a query", &providers.TaskFilters{Query: "#Unresolved", LabelsAll: []string{"backend"}, LabelsAny: []string{"p1"}, LabelsNone: []string{"blocked"}},
+			"(#Unresolved) and (tag: {backend} and tag: {p1} and tag: -{blocked})"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, translator.UniversalFiltersToYouTrack(tt.filters).Query)
+		})
+	}
+}
+
+// TestLinkTasks tests marking an issue as a duplicate with a YouTrack command
+func TestLinkTasks(t *testing.T) {
+	var received YouTrackCommand
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, "/api/commands", r.URL.Path)
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	provider, err := createTestProvider(server.URL, "test-token")
+	require.NoError(t, err)
+
+	require.NoError(t, provider.LinkTasks(context.Background(), "PROJ-9", "PROJ-1", providers.TaskLinkDuplicateOf))
+	assert.Equal(t, "duplicates PROJ-1", received.Query)
+	require.Len(t, received.Issues, 1)
+	assert.Equal(t, "PROJ-9", received.Issues[0].IDReadable)
+
+	require.NoError(t, provider.LinkTasks(context.Background(), "PROJ-9", "PROJ-1", providers.TaskLinkBlocks))
+	assert.Equal(t, "is required for PROJ-1", received.Query)
+
+	err = provider.LinkTasks(context.Background(), "PROJ-9", "PROJ-1", providers.TaskLinkType("relates_to"))
+	assert.True(t, providers.IsErrorType(err, providers.ErrorTypeValidation))
+}
+
+// TestUnlinkTasks tests removing links with the remove form of the command
+func TestUnlinkTasks(t *testing.T) {
+	var received YouTrackCommand
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, "/api/commands", r.URL.Path)
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		if received.Issues[0].IDReadable == "PROJ-404" {
+			w.WriteHeader(http.StatusNotFound)
+			json.NewEncoder(w).Encode(map[string]string{"error": "Issue not found"})
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	provider, err := createTestProvider(server.URL, "test-token")
+	require.NoError(t, err)
+
+	require.NoError(t, provider.UnlinkTasks(context.Background(), "PROJ-9", "PROJ-1", providers.TaskLinkDependsOn))
+	assert.Equal(t, "remove depends on PROJ-1", received.Query)
+	require.Len(t, received.Issues, 1)
+	assert.Equal(t, "PROJ-9", received.Issues[0].IDReadable)
+
+	require.NoError(t, provider.UnlinkTasks(context.Background(), "PROJ-9", "PROJ-1", providers.TaskLinkBlocks))
+	assert.Equal(t, "remove is required for PROJ-1", received.Query)
+
+	err = provider.UnlinkTasks(context.Background(), "PROJ-404", "PROJ-1", providers.TaskLinkSubtaskOf)
+	assert.ErrorIs(t, err, providers.ErrTaskNotFound)
+
+	err = provider.UnlinkTasks(context.Background(), "PROJ-9", "PROJ-1", providers.TaskLinkType("relates_to"))
+	assert.True(t, providers.IsErrorType(err, providers.ErrorTypeValidation))
+}
+
+// TestMissingFields tests reading renamed or missing fields of issues
+func TestMissingFields(t *testing.T) {
+	logger, hook := logrustest.NewNullLogger()
+	translator := NewYouTrackTranslator()
+	translator.SetLogger(logrus.NewEntry(logger))
+
+	t.Run("Custom fields stand in for missing fields", func(t *testing.T) {
+		task := translator.YouTrackToUniversal(&YouTrackIssue{ID: "2-1", CustomFields: []*YouTrackCustomField{
+			{Name: "Stage", Value: map[string]interface{}{"name": "Verified", "isResolved": true}},
+			{Name: "Priority", Value: []interface{}{map[string]interface{}{"name": "Major"}}},
+			{Name: "Type", Value: "Bug"},
+		}})
+		assert.Equal(t, "verified", task.Status.ID)
+		assert.True(t, task.Status.IsFinal)
+		assert.Equal(t, providers.TaskPriorityHigh, task.Priority)
+		assert.Equal(t, providers.TaskTypeBug, task.Type)
+		assert.Empty(t, hook.AllEntries())
+	})
+
+	t.Run("Top-level fields take precedence", func(t *testing.T) {
+		task := translator.YouTrackToUniversal(&YouTrackIssue{
+			ID:           "2-2",
+			State:        &YouTrackState{Name: "Open"},
+			Priority:     &YouTrackPriority{Name: "Minor"},
+			Type:         &YouTrackIssueType{Name: "Feature"},
+			CustomFields: []*YouTrackCustomField{{Name: "State", Value: map[string]interface{}{"name": "Fixed"}}},
+		})
+		assert.Equal(t, "open", task.Status.ID)
+		assert.Equal(t, providers.TaskPriorityLow, task.Priority)
+		assert.Equal(t, providers.TaskTypeFeature, task.Type)
+	})
+
+	t.Run("Missing fields are logged once", func(t *testing.T) {
+		issue := &YouTrackIssue{ID: "2-3", IDReadable: "PROJ-3", CustomFields: []*YouTrackCustomField{
+			{Name: "State", Value: map[string]interface{}{"$type": "StateBundleElement"}},
+			{Name: "Priority", Value: nil},
+			{Name: "Type", Value: 42.0},
+		}}
+		task := translator.YouTrackToUniversal(issue)
+		translator.YouTrackToUniversal(issue)
+
+		assert.Empty(t, task.Status.ID)
+		assert.Empty(t, task.Priority)
+		assert.Empty(t, task.Type)
+
+		require.Len(t, hook.AllEntries(), 3)
+		entry := hook.AllEntries()[0]
+		assert.Equal(t, logrus.WarnLevel, entry.Level)
+		assert.Equal(t, "state", entry.Data["field"])
+		assert.Equal(t, "PROJ-3", entry.Data["issue"])
+		assert.Contains(t, entry.Message, "State, Status, Stage")
+	})
+}
+
+// TestAPIVersion tests detecting the version of the server
+func TestAPIVersion(t *testing.T) {
+	newServer := func(config string) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/api/config" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, config)
+		}))
+	}
+
+	t.Run("Detected by the health check", func(t *testing.T) {
+		server := newServer(`{"version":"2024.3","build":52635,"$type":"AppConfig"}`)
+		defer server.Close()
+
+		provider, err := createTestProvider(server.URL, "test-token")
+		require.NoError(t, err)
+		assert.Empty(t, provider.GetProviderInfo().APIVersion)
+
+		require.NoError(t, provider.HealthCheck(context.Background()))
+		assert.Equal(t, "2024.3 (build 52635)", provider.GetProviderInfo().APIVersion)
+	})
+
+	t.Run("A server without version stays healthy", func(t *testing.T) {
+		server := newServer(`{"$type":"AppConfig"}`)
+		defer server.Close()
+
+		provider, err := createTestProvider(server.URL, "test-token")
+		require.NoError(t, err)
+
+		require.NoError(t, provider.HealthCheck(context.Background()))
+		assert.Empty(t, provider.GetProviderInfo().APIVersion)
+	})
+}
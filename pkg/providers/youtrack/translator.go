@@ -1,18 +1,36 @@
 package youtrack
 
 import (
+	"encoding/json"
 	"strings"
+	"sync"
+	"time"
 
+	"github.com/sirupsen/logrus"
 	"github.com/grik-ai/ricochet-task/pkg/providers"
 )
 
 // YouTrackTranslator handles conversion between YouTrack and Universal formats
 type YouTrackTranslator struct {
-	statusMapping   map[string]providers.TaskStatus
-	priorityMapping map[string]providers.TaskPriority
-	typeMapping     map[string]providers.TaskType
+	statusMapping    map[string]providers.TaskStatus
+	statusCategories providers.StatusMapping
+	fieldMapping     providers.FieldMapping
+	priorityMapping  providers.PriorityMapping
+	typeMapping      map[string]providers.TaskType
+	logger           *logrus.Entry
+	// missingFields records the fields already warned about as missing
+	missingFields    sync.Map
 }
 
+// Custom fields an issue's state, priority and type are read from when the
+// response lacks the top-level field, e.g. after it was renamed in the API
+// or when the project names its state field differently
+var (
+	stateFieldNames    = []string{"State", "Status", "Stage"}
+	priorityFieldNames = []string{"Priority"}
+	typeFieldNames     = []string{"Type", "Issue Type"}
+)
+
 // NewYouTrackTranslator creates a new translator
 func NewYouTrackTranslator() *YouTrackTranslator {
 	return &YouTrackTranslator{
@@ -31,14 +49,7 @@ func NewYouTrackTranslator() *YouTrackTranslator {
 			"Obsolete":    {ID: "obsolete", Name: "Obsolete", Category: providers.StatusCategoryCancelled, IsFinal: true},
 			"Blocked":     {ID: "blocked", Name: "Blocked", Category: providers.StatusCategoryBlocked},
 		},
-		priorityMapping: map[string]providers.TaskPriority{
-			"Show-stopper": providers.TaskPriorityCritical,
-			"Critical":     providers.TaskPriorityHighest,
-			"Major":        providers.TaskPriorityHigh,
-			"Normal":       providers.TaskPriorityMedium,
-			"Minor":        providers.TaskPriorityLow,
-			"Cosmetic":     providers.TaskPriorityLowest,
-		},
+		priorityMapping: providers.PriorityMappingFor(providers.ProviderTypeYouTrack, nil),
 		typeMapping: map[string]providers.TaskType{
 			"Feature":      providers.TaskTypeFeature,
 			"Bug":          providers.TaskTypeBug,
@@ -52,8 +63,43 @@ func NewYouTrackTranslator() *YouTrackTranslator {
 	}
 }
 
+// SetStatusCategories sets the configured categories of custom states. They
+// take precedence over the built-in mapping and the inferred categories.
+func (t *YouTrackTranslator) SetStatusCategories(mapping providers.StatusMapping) {
+	t.statusCategories = mapping
+}
+
+// SetLogger sets the logger missing fields of issues are reported to
+func (t *YouTrackTranslator) SetLogger(logger *logrus.Entry) {
+	t.logger = logger
+}
+
+// SetFieldMapping sets the custom fields that are promoted to typed task
+// fields, e.g. "Story points" to storyPoints
+func (t *YouTrackTranslator) SetFieldMapping(mapping providers.FieldMapping) {
+	t.fieldMapping = mapping
+}
+
+// SetPriorityMapping sets the configured priority values, e.g. for a project
+// with its own priority scheme. Levels that aren't configured keep the
+// built-in YouTrack values.
+func (t *YouTrackTranslator) SetPriorityMapping(mapping providers.PriorityMapping) {
+	t.priorityMapping = providers.PriorityMappingFor(providers.ProviderTypeYouTrack, mapping)
+}
+
 // UniversalToYouTrack converts a Universal task to YouTrack issue
 func (t *YouTrackTranslator) UniversalToYouTrack(task *providers.UniversalTask) *YouTrackIssue {
+	if len(t.fieldMapping) > 0 {
+		// Write mapped typed fields to their custom fields without changing the caller's task
+		mapped := *task
+		mapped.CustomFields = make(map[string]interface{}, len(task.CustomFields))
+		for name, value := range task.CustomFields {
+			mapped.CustomFields[name] = value
+		}
+		t.fieldMapping.Demote(&mapped)
+		task = &mapped
+	}
+
 	issue := &YouTrackIssue{
 		Summary:     task.Title,
 		Description: task.Description,
@@ -163,23 +209,13 @@ func (t *YouTrackTranslator) YouTrackToUniversal(issue *YouTrackIssue) *provider
 	}
 
 	// Convert status
-	if issue.State != nil {
-		if status, exists := t.statusMapping[issue.State.Name]; exists {
-			task.Status = status
-		} else {
-			// Create a dynamic status mapping
-			task.Status = providers.TaskStatus{
-				ID:   strings.ToLower(strings.ReplaceAll(issue.State.Name, " ", "_")),
-				Name: issue.State.Name,
-				Category: t.inferStatusCategory(issue.State.Name, issue.State.IsResolved),
-				IsFinal: issue.State.IsResolved,
-			}
-		}
+	if state := t.issueState(issue); state != nil {
+		task.Status = t.YouTrackStatusToUniversal(state)
 	}
 
 	// Convert priority
-	if issue.Priority != nil {
-		if priority, exists := t.priorityMapping[issue.Priority.Name]; exists {
+	if name, ok := t.issueEnumField(issue, "priority", issue.Priority.GetName(), priorityFieldNames); ok {
+		if priority, exists := t.priorityMapping.Universal(name); exists {
 			task.Priority = priority
 		} else {
 			// Default to medium if unknown
@@ -188,8 +224,8 @@ func (t *YouTrackTranslator) YouTrackToUniversal(issue *YouTrackIssue) *provider
 	}
 
 	// Convert type
-	if issue.Type != nil {
-		if taskType, exists := t.typeMapping[issue.Type.Name]; exists {
+	if name, ok := t.issueEnumField(issue, "type", issue.Type.GetName(), typeFieldNames); ok {
+		if taskType, exists := t.typeMapping[name]; exists {
 			task.Type = taskType
 		} else {
 			// Default to task if unknown
@@ -222,6 +258,7 @@ func (t *YouTrackTranslator) YouTrackToUniversal(issue *YouTrackIssue) *provider
 	// Convert custom fields
 	if issue.CustomFields != nil {
 		task.CustomFields = t.convertCustomFieldsFromYouTrack(issue.CustomFields)
+		t.fieldMapping.Promote(task)
 	}
 
 	// Convert tags to labels
@@ -272,6 +309,16 @@ func (t *YouTrackTranslator) YouTrackToUniversal(issue *YouTrackIssue) *provider
 func (t *YouTrackTranslator) UniversalUpdatesToYouTrack(updates *providers.TaskUpdate) *YouTrackIssueUpdate {
 	ytUpdates := &YouTrackIssueUpdate{}
 
+	if len(t.fieldMapping) > 0 {
+		mapped := *updates
+		mapped.CustomFields = make(map[string]interface{}, len(updates.CustomFields))
+		for name, value := range updates.CustomFields {
+			mapped.CustomFields[name] = value
+		}
+		t.fieldMapping.DemoteUpdate(&mapped)
+		updates = &mapped
+	}
+
 	if updates.Title != nil {
 		ytUpdates.Summary = updates.Title
 	}
@@ -296,6 +343,14 @@ func (t *YouTrackTranslator) UniversalUpdatesToYouTrack(updates *providers.TaskU
 		}
 	}
 
+	if updates.Type != nil {
+		if ytType := t.findYouTrackTypeByTaskType(*updates.Type); ytType != "" {
+			ytUpdates.Type = &YouTrackIssueType{
+				Name: ytType,
+			}
+		}
+	}
+
 	if updates.AssigneeID != nil {
 		ytUpdates.Assignee = &YouTrackUser{
 			ID: *updates.AssigneeID,
@@ -353,8 +408,11 @@ func (t *YouTrackTranslator) UniversalFiltersToYouTrack(filters *providers.TaskF
 	// Convert priority filters
 	if len(filters.Priority) > 0 {
 		ytPriorities := make([]string, 0, len(filters.Priority))
+		seen := make(map[string]bool)
 		for _, priority := range filters.Priority {
-			if ytPriority := t.findYouTrackPriorityByTaskPriority(providers.TaskPriority(priority)); ytPriority != "" {
+			// Several levels can share a YouTrack priority
+			if ytPriority := t.findYouTrackPriorityByTaskPriority(providers.TaskPriority(priority)); ytPriority != "" && !seen[ytPriority] {
+				seen[ytPriority] = true
 				ytPriorities = append(ytPriorities, ytPriority)
 			}
 		}
@@ -380,6 +438,11 @@ func (t *YouTrackTranslator) UniversalFiltersToYouTrack(filters *providers.TaskF
 		}
 	}
 
+	// Convert label filters
+	if match := filters.LabelMatch(); !match.IsEmpty() {
+		ytFilters.Query = t.combineQueries(ytFilters.Query, t.buildTagQuery(match))
+	}
+
 	// Set time filters
 	ytFilters.CreatedAfter = filters.CreatedAfter
 	ytFilters.CreatedBefore = filters.CreatedBefore
@@ -394,22 +457,163 @@ func (t *YouTrackTranslator) UniversalStatusToYouTrack(status providers.TaskStat
 	return t.findYouTrackStatus(status)
 }
 
+// issueState returns the state of an issue, read from its custom fields when
+// the response has no top-level state
+func (t *YouTrackTranslator) issueState(issue *YouTrackIssue) *YouTrackState {
+	if issue.State != nil && issue.State.Name != "" {
+		return issue.State
+	}
+	name, isResolved, ok := issue.GetCustomFieldEnumValue(stateFieldNames...)
+	if !ok {
+		t.warnMissingField(issue, "state", stateFieldNames)
+		return nil
+	}
+	return &YouTrackState{Name: name, IsResolved: isResolved}
+}
+
+// issueEnumField returns the name of a field of an issue, the top-level one
+// if the response has it or else the first of the custom fields
+func (t *YouTrackTranslator) issueEnumField(issue *YouTrackIssue, field, name string, customFieldNames []string) (string, bool) {
+	if name != "" {
+		return name, true
+	}
+	if name, _, ok := issue.GetCustomFieldEnumValue(customFieldNames...); ok {
+		return name, true
+	}
+	t.warnMissingField(issue, field, customFieldNames)
+	return "", false
+}
+
+// warnMissingField logs once per field that issues come without it, so that a
+// change of the API shows up instead of tasks silently losing the field
+func (t *YouTrackTranslator) warnMissingField(issue *YouTrackIssue, field string, customFieldNames []string) {
+	if t.logger == nil {
+		return
+	}
+	if _, warned := t.missingFields.LoadOrStore(field, true); warned {
+		return
+	}
+	issueID := issue.IDReadable
+	if issueID == "" {
+		issueID = issue.ID
+	}
+	t.logger.WithFields(logrus.Fields{
+		"issue": issueID,
+		"field": field,
+	}).Warnf("YouTrack issue has no %s, neither as a field nor as one of the custom fields %s; tasks are returned without it. The YouTrack API may have changed or the project names the field differently", field, strings.Join(customFieldNames, ", "))
+}
+
 func (t *YouTrackTranslator) YouTrackStatusToUniversal(status *YouTrackState) providers.TaskStatus {
 	if status == nil {
 		return providers.TaskStatus{}
 	}
 
-	if universalStatus, exists := t.statusMapping[status.Name]; exists {
-		return universalStatus
+	universalStatus, exists := t.statusMapping[status.Name]
+	if !exists {
+		// Create dynamic mapping
+		universalStatus = providers.TaskStatus{
+			ID:       strings.ToLower(strings.ReplaceAll(status.Name, " ", "_")),
+			Name:     status.Name,
+			Category: t.inferStatusCategory(status.Name, status.IsResolved),
+			IsFinal:  status.IsResolved,
+		}
+	}
+
+	if category, ok := t.statusCategories.Lookup(status.Name); ok {
+		universalStatus.Category = category
+		universalStatus.IsFinal = category.IsFinal()
+	}
+
+	return universalStatus
+}
+
+// activityFieldNames maps YouTrack field names of activities to universal ones
+var activityFieldNames = map[string]string{
+	"summary":     providers.ActivityFieldTitle,
+	"description": providers.ActivityFieldDescription,
+	"state":       providers.ActivityFieldStatus,
+	"priority":    providers.ActivityFieldPriority,
+	"assignee":    providers.ActivityFieldAssignee,
+	"type":        providers.ActivityFieldType,
+	"tag":         providers.ActivityFieldLabels,
+	"tags":        providers.ActivityFieldLabels,
+	"comments":    providers.ActivityFieldComment,
+	"links":       providers.ActivityFieldLinks,
+	"attachments": providers.ActivityFieldAttachment,
+}
+
+// YouTrackActivityToUniversal converts an issue activity to an activity entry
+func (t *YouTrackTranslator) YouTrackActivityToUniversal(activity *YouTrackActivity) providers.ActivityEntry {
+	entry := providers.ActivityEntry{
+		From: activityValue(activity.Removed),
+		To:   activityValue(activity.Added),
+		At:   activity.GetTime(),
+	}
+
+	if activity.Author != nil {
+		entry.Actor = activity.Author.Login
+		if entry.Actor == "" {
+			entry.Actor = activity.Author.Name
+		}
 	}
 
-	// Create dynamic mapping
-	return providers.TaskStatus{
-		ID:       strings.ToLower(strings.ReplaceAll(status.Name, " ", "_")),
-		Name:     status.Name,
-		Category: t.inferStatusCategory(status.Name, status.IsResolved),
-		IsFinal:  status.IsResolved,
+	switch {
+	case activity.Category != nil && activity.Category.ID == "IssueCreatedCategory":
+		entry.Field = providers.ActivityFieldCreated
+		entry.From, entry.To = "", ""
+	case activity.Field != nil:
+		entry.Field = activity.Field.Name
+		if field, ok := activityFieldNames[strings.ToLower(activity.Field.Name)]; ok {
+			entry.Field = field
+		}
+	case activity.Category != nil:
+		entry.Field = activity.Category.ID
 	}
+
+	return entry
+}
+
+// activityValue describes the added or removed value of an activity: text as
+// is, entities by their most readable name
+func activityValue(raw json.RawMessage) string {
+	if len(raw) == 0 || string(raw) == "null" {
+		return ""
+	}
+
+	var text string
+	if err := json.Unmarshal(raw, &text); err == nil {
+		return text
+	}
+
+	type entity struct {
+		IDReadable   string `json:"idReadable"`
+		Presentation string `json:"presentation"`
+		FullName     string `json:"fullName"`
+		Login        string `json:"login"`
+		Name         string `json:"name"`
+		Text         string `json:"text"`
+		ID           string `json:"id"`
+	}
+	var entities []entity
+	if err := json.Unmarshal(raw, &entities); err != nil {
+		var single entity
+		if err := json.Unmarshal(raw, &single); err != nil {
+			// Numbers and other plain values
+			return strings.Trim(string(raw), `"`)
+		}
+		entities = []entity{single}
+	}
+
+	names := make([]string, 0, len(entities))
+	for _, e := range entities {
+		for _, name := range []string{e.IDReadable, e.Presentation, e.Login, e.Name, e.FullName, e.Text, e.ID} {
+			if name != "" {
+				names = append(names, name)
+				break
+			}
+		}
+	}
+	return strings.Join(names, ", ")
 }
 
 // Comment conversion
@@ -454,7 +658,7 @@ func (t *YouTrackTranslator) convertCustomFieldsToYouTrack(fields map[string]int
 	for name, value := range fields {
 		ytField := &YouTrackCustomField{
 			Name:  name,
-			Value: value,
+			Value: youTrackCustomFieldValue(value),
 		}
 		ytFields = append(ytFields, ytField)
 	}
@@ -477,8 +681,8 @@ func (t *YouTrackTranslator) convertCustomFieldUpdatesToYouTrack(fields map[stri
 
 	for name, value := range fields {
 		ytUpdate := &YouTrackCustomFieldUpdate{
-			ID:    name, // In practice, you'd need to map field names to IDs
-			Value: value,
+			Name:  name,
+			Value: youTrackCustomFieldValue(value),
 		}
 		ytUpdates = append(ytUpdates, ytUpdate)
 	}
@@ -486,6 +690,18 @@ func (t *YouTrackTranslator) convertCustomFieldUpdatesToYouTrack(fields map[stri
 	return ytUpdates
 }
 
+// youTrackCustomFieldValue encodes the typed values of mapped task fields the
+// way YouTrack expects them: periods in minutes and dates in milliseconds
+func youTrackCustomFieldValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case time.Duration:
+		return DurationToYouTrackDuration(v)
+	case time.Time:
+		return v.UnixMilli()
+	}
+	return value
+}
+
 // Helper methods for finding mappings
 func (t *YouTrackTranslator) findYouTrackStatus(status providers.TaskStatus) string {
 	for ytStatus, universalStatus := range t.statusMapping {
@@ -506,12 +722,11 @@ func (t *YouTrackTranslator) findYouTrackStatusByID(statusID string) string {
 }
 
 func (t *YouTrackTranslator) findYouTrackPriority(priority providers.TaskPriority) string {
-	for ytPriority, universalPriority := range t.priorityMapping {
-		if universalPriority == priority {
-			return ytPriority
-		}
+	if ytPriority, ok := t.priorityMapping.Native(priority); ok {
+		return ytPriority
 	}
-	return "Normal" // Default fallback
+	ytPriority, _ := t.priorityMapping.Native(providers.TaskPriorityMedium) // Default fallback
+	return ytPriority
 }
 
 func (t *YouTrackTranslator) findYouTrackPriorityByTaskPriority(priority providers.TaskPriority) string {
@@ -576,6 +791,25 @@ func (t *YouTrackTranslator) buildMultiTypeQuery(types []string, existingQuery s
 	return t.combineQueries(existingQuery, typeQuery)
 }
 
+// buildTagQuery expresses a label condition with YouTrack tags: required tags
+// are joined with and, alternatives with or, and excluded tags use the minus
+// operator
+func (t *YouTrackTranslator) buildTagQuery(match providers.LabelMatch) string {
+	var parts []string
+	for _, label := range match.All {
+		parts = append(parts, "tag: {"+label+"}")
+	}
+	if len(match.Any) == 1 {
+		parts = append(parts, "tag: {"+match.Any[0]+"}")
+	} else if len(match.Any) > 1 {
+		parts = append(parts, "(tag: {"+strings.Join(match.Any, "} or tag: {")+"})")
+	}
+	for _, label := range match.None {
+		parts = append(parts, "tag: -{"+label+"}")
+	}
+	return strings.Join(parts, " and ")
+}
+
 func (t *YouTrackTranslator) combineQueries(existing, new string) string {
 	if existing == "" {
 		return new
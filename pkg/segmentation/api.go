@@ -7,6 +7,8 @@ const (
 	MethodSimple    SegmentationMethod = "simple"    // Простая сегментация по размеру
 	MethodSemantic  SegmentationMethod = "semantic"  // Сегментация по смысловым блокам
 	MethodRecursive SegmentationMethod = "recursive" // Рекурсивная сегментация
+	MethodSentence  SegmentationMethod = "sentence"  // По границам предложений
+	MethodParagraph SegmentationMethod = "paragraph" // По параграфам
 )
 
 // SegmentationOptions опции для сегментации
@@ -14,7 +16,7 @@ type SegmentationOptions struct {
 	ChunkSize   int                // Максимальный размер чанка в токенах
 	MaxSegments int                // Максимальное количество сегментов (0 - без ограничений)
 	Method      SegmentationMethod // Метод сегментации
-	Overlap     int                // Размер перекрытия в токенах (для MethodSimple и MethodSemantic)
+	Overlap     int                // Размер перекрытия в токенах, см. AddOverlap
 	MinSegSize  int                // Минимальный размер сегмента в токенах
 }
 
@@ -29,8 +31,26 @@ func DefaultSegmentationOptions() SegmentationOptions {
 	}
 }
 
-// Segment сегментирует текст и возвращает список сегментов
+// Segment сегментирует текст и возвращает список сегментов.
+// При Overlap > 0 каждый сегмент, кроме первого, начинается с контекста
+// перекрытия (см. SegmentInfo.InputText).
 func Segment(text string, options SegmentationOptions) ([]string, error) {
+	segments, err := SegmentWithInfo(text, options)
+	if err != nil {
+		return nil, err
+	}
+
+	// Преобразуем SegmentInfo в строки
+	result := make([]string, len(segments))
+	for i, segment := range segments {
+		result[i] = segment.InputText()
+	}
+
+	return result, nil
+}
+
+// SegmentWithInfo сегментирует текст и возвращает сегменты с позициями и контекстом перекрытия
+func SegmentWithInfo(text string, options SegmentationOptions) ([]SegmentInfo, error) {
 	// Создаем сегментер
 	segmenter, err := NewSegmenter(string(options.Method))
 	if err != nil {
@@ -53,11 +73,5 @@ func Segment(text string, options SegmentationOptions) ([]string, error) {
 		segments = segments[:options.MaxSegments]
 	}
 
-	// Преобразуем SegmentInfo в строки
-	result := make([]string, len(segments))
-	for i, segment := range segments {
-		result[i] = segment.Content
-	}
-
-	return result, nil
+	return AddOverlap(segments, text, options.Overlap), nil
 }
@@ -0,0 +1,67 @@
+package segmentation
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// Маркеры, которыми контекст перекрытия отделяется от содержимого сегмента
+const (
+	overlapContextHeader = "[Контекст из предыдущей части, только для связности - не обрабатывать]"
+	overlapContentHeader = "[Часть для обработки]"
+)
+
+// AddOverlap добавляет каждому сегменту контекст: около overlapTokens токенов
+// исходного текста, непосредственно предшествующих сегменту.
+//
+// Контекст хранится отдельно от Content, поэтому модель видит стык частей,
+// а результаты сегментов объединяются без дублирования. Цена перекрытия -
+// дополнительные входные токены: примерно overlapTokens * (число сегментов - 1)
+// на каждую модель цепочки.
+func AddOverlap(segments []SegmentInfo, text string, overlapTokens int) []SegmentInfo {
+	if overlapTokens <= 0 {
+		return segments
+	}
+
+	// Примерная оценка: 1 токен ~ 4 символа
+	overlapChars := overlapTokens * 4
+
+	for i := range segments {
+		start := segments[i].StartPos
+		if start <= 0 || start > len(text) {
+			continue
+		}
+
+		from := start - overlapChars
+		if from < 0 {
+			from = 0
+		}
+		// Не разрезаем символы UTF-8 и по возможности начинаем с целого слова
+		for from < start && !utf8.RuneStart(text[from]) {
+			from++
+		}
+		if from > 0 {
+			if idx := strings.IndexAny(text[from:start], " \n\t"); idx >= 0 && from+idx+1 < start {
+				from += idx + 1
+			}
+		}
+
+		context := strings.TrimSpace(text[from:start])
+		if context == "" {
+			continue
+		}
+		segments[i].Context = context
+		segments[i].Overlapping = true
+	}
+
+	return segments
+}
+
+// InputText возвращает текст, который отправляется модели: контекст
+// перекрытия (если есть), а затем содержимое сегмента
+func (s SegmentInfo) InputText() string {
+	if s.Context == "" {
+		return s.Content
+	}
+	return overlapContextHeader + "\n" + s.Context + "\n\n" + overlapContentHeader + "\n" + s.Content
+}
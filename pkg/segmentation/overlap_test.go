@@ -0,0 +1,65 @@
+package segmentation
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func longText(sentences int) string {
+	var b strings.Builder
+	for i := 0; i < sentences; i++ {
+		b.WriteString("Это предложение номер ")
+		b.WriteString(strings.Repeat("x", i%7+1))
+		b.WriteString(" для проверки сегментации. ")
+	}
+	return b.String()
+}
+
+func TestSegmentWithInfoOverlap(t *testing.T) {
+	text := longText(200)
+
+	t.Run("no overlap", func(t *testing.T) {
+		segments, err := SegmentWithInfo(text, SegmentationOptions{ChunkSize: 200, Method: MethodSentence})
+		require.NoError(t, err)
+		require.Greater(t, len(segments), 2)
+
+		for _, segment := range segments {
+			assert.Empty(t, segment.Context)
+			assert.Equal(t, segment.Content, segment.InputText())
+		}
+	})
+
+	t.Run("overlap adds preceding text as context", func(t *testing.T) {
+		segments, err := SegmentWithInfo(text, SegmentationOptions{ChunkSize: 200, Method: MethodSentence, Overlap: 20})
+		require.NoError(t, err)
+		require.Greater(t, len(segments), 2)
+
+		assert.Empty(t, segments[0].Context, "first segment has nothing before it")
+		for _, segment := range segments[1:] {
+			require.NotEmpty(t, segment.Context)
+			assert.True(t, segment.Overlapping)
+			assert.LessOrEqual(t, len(segment.Context), 20*4)
+			assert.True(t, strings.HasSuffix(strings.TrimSpace(text[:segment.StartPos]), segment.Context),
+				"context must be the text right before the segment")
+			assert.Contains(t, segment.InputText(), segment.Content)
+		}
+
+		// Содержимое сегментов не дублируется, поэтому склеивается обратно в исходный текст
+		merged, err := (&SimpleSegmenter{}).Merge(segments)
+		require.NoError(t, err)
+		assert.Equal(t, text, merged)
+	})
+
+	t.Run("strategy aliases", func(t *testing.T) {
+		assert.True(t, IsKnownSegmentation(SegmentationSentence))
+		assert.True(t, IsKnownSegmentation(SegmentationParagraph))
+		assert.False(t, IsKnownSegmentation("words"))
+
+		segmenter, err := NewSegmenter(SegmentationParagraph)
+		require.NoError(t, err)
+		assert.Equal(t, SegmentationSemantic, segmenter.GetType())
+	})
+}
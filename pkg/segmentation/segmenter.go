@@ -14,6 +14,8 @@ const (
 	SegmentationSimple    = "simple"    // По размеру
 	SegmentationSemantic  = "semantic"  // По смысловым блокам
 	SegmentationRecursive = "recursive" // Рекурсивное разбиение
+	SegmentationSentence  = "sentence"  // По границам предложений (то же, что simple)
+	SegmentationParagraph = "paragraph" // По параграфам (то же, что semantic)
 )
 
 // SegmentInfo содержит информацию о сегменте текста
@@ -26,6 +28,7 @@ type SegmentInfo struct {
 	TokenCount  int               `json:"token_count"` // Примерное количество токенов
 	Metadata    map[string]string `json:"metadata"`    // Метаданные сегмента
 	Overlapping bool              `json:"overlapping"` // Флаг перекрытия с другими сегментами
+	Context     string            `json:"context"`     // Перекрытие: конец предыдущего фрагмента текста
 }
 
 // Segmenter определяет интерфейс для сегментации текста
@@ -43,9 +46,9 @@ type Segmenter interface {
 // NewSegmenter создает новый сегментер указанного типа
 func NewSegmenter(segmentationType string) (Segmenter, error) {
 	switch segmentationType {
-	case SegmentationSimple:
+	case SegmentationSimple, SegmentationSentence:
 		return &SimpleSegmenter{}, nil
-	case SegmentationSemantic:
+	case SegmentationSemantic, SegmentationParagraph:
 		return &SemanticSegmenter{}, nil
 	case SegmentationRecursive:
 		return &RecursiveSegmenter{}, nil
@@ -54,6 +57,16 @@ func NewSegmenter(segmentationType string) (Segmenter, error) {
 	}
 }
 
+// IsKnownSegmentation проверяет, что тип сегментации поддерживается.
+// NewSegmenter для неизвестного типа молча использует простую сегментацию.
+func IsKnownSegmentation(segmentationType string) bool {
+	switch segmentationType {
+	case SegmentationSimple, SegmentationSentence, SegmentationSemantic, SegmentationParagraph, SegmentationRecursive:
+		return true
+	}
+	return false
+}
+
 // SimpleSegmenter реализует простую сегментацию по размеру
 type SimpleSegmenter struct{}
 
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sort"
 	"sync"
 	"time"
 
@@ -419,7 +420,7 @@ func (s *RicochetService) CancelRun(runID string) error {
 	return nil
 }
 
-// ListRuns возвращает список всех запусков
+// ListRuns возвращает список всех запусков, новые первыми
 func (s *RicochetService) ListRuns() []*RunMetadata {
 	s.mutex.RLock()
 	defer s.mutex.RUnlock()
@@ -429,6 +430,9 @@ func (s *RicochetService) ListRuns() []*RunMetadata {
 		runs = append(runs, run)
 	}
 
+	sort.Slice(runs, func(i, j int) bool {
+		return runs[i].StartTime.After(runs[j].StartTime)
+	})
 	return runs
 }
 
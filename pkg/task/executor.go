@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/grik-ai/ricochet-task/pkg/chain"
+	"github.com/grik-ai/ricochet-task/pkg/model"
 	"github.com/grik-ai/ricochet-task/pkg/segmentation"
 )
 
@@ -121,7 +122,7 @@ func (e *DefaultTaskExecutor) ExecuteTask(ctx context.Context, taskID string) er
 
 	switch task.Type {
 	case TaskTypeModelExecution:
-		executeErr = e.executeModelTask(taskCtx, task)
+		executeErr = e.executeModelTask(taskCtx, &task)
 	case TaskTypeSegmentation:
 		executeErr = e.executeSegmentationTask(taskCtx, task)
 	case TaskTypeIntegration:
@@ -132,8 +133,11 @@ func (e *DefaultTaskExecutor) ExecuteTask(ctx context.Context, taskID string) er
 		executeErr = fmt.Errorf("unsupported task type: %s", task.Type)
 	}
 
-	// Обновляем метрики задачи
+	// Обновляем метрики задачи и сохраняем результат
 	task.Metrics.DurationMs = time.Since(startTime).Milliseconds()
+	if err := e.taskManager.UpdateTaskOutput(taskID, task.Output, task.Metrics); err != nil {
+		return fmt.Errorf("failed to save task output: %w", err)
+	}
 
 	// Обновляем статус задачи в зависимости от результата
 	var finalStatus TaskStatus
@@ -220,7 +224,7 @@ func (e *DefaultTaskExecutor) ExecuteBatch(ctx context.Context, taskIDs []string
 }
 
 // executeModelTask выполняет задачу типа TaskTypeModelExecution
-func (e *DefaultTaskExecutor) executeModelTask(ctx context.Context, task Task) error {
+func (e *DefaultTaskExecutor) executeModelTask(ctx context.Context, task *Task) error {
 	if task.Model == nil {
 		return errors.New("model is not specified for model execution task")
 	}
@@ -250,21 +254,45 @@ func (e *DefaultTaskExecutor) executeModelTask(ctx context.Context, task Task) e
 		options["system_prompt"] = task.Model.Prompt
 	}
 
+	// Шаг со схемой результата должен ответить JSON по схеме
+	schema := task.Model.OutputSchema
+	if len(schema) > 0 {
+		options["system_prompt"] = chain.StructuredPrompt(task.Model.Prompt, schema)
+	}
+
 	// Оцениваем количество входных токенов
 	task.Metrics.TokensInput = e.modelProvider.EstimateTokens(inputText)
 
-	// Выполняем запрос к модели
-	output, err := e.modelProvider.Execute(ctx, *task.Model, inputText, options)
+	// Выполняем запрос к модели, при временном сбое - запасными моделями шага
+	output, served, err := e.executeWithFallback(ctx, *task.Model, inputText, options)
 	if err != nil {
 		return fmt.Errorf("model execution failed: %w", err)
 	}
 
+	repaired := false
+	if len(schema) > 0 {
+		output, served, repaired, err = e.structuredOutput(ctx, *task.Model, inputText, options, output, served)
+		if err != nil {
+			return err
+		}
+	}
+
 	// Оцениваем количество выходных токенов
 	task.Metrics.TokensOutput = e.modelProvider.EstimateTokens(output)
 
-	// Сохраняем результат
+	// Сохраняем результат и модель, которая его получила
 	task.Output.Type = "text"
 	task.Output.Destination = output
+	if task.Output.Metadata == nil {
+		task.Output.Metadata = make(map[string]interface{})
+	}
+	task.Output.Metadata["served_by"] = string(served.Name)
+	if served.Name != task.Model.Name {
+		task.Output.Metadata["fallback_from"] = string(task.Model.Name)
+	}
+	if repaired {
+		task.Output.Metadata["schema_repaired"] = true
+	}
 
 	// TODO: Рассчитать стоимость выполнения запроса
 	// task.Metrics.Cost = ...
@@ -272,6 +300,49 @@ func (e *DefaultTaskExecutor) executeModelTask(ctx context.Context, task Task) e
 	return nil
 }
 
+// executeWithFallback выполняет запрос основной моделью шага, а если она недоступна
+// из-за временного сбоя - запасными моделями шага по порядку.
+// Возвращает ответ и модель, которая его дала.
+func (e *DefaultTaskExecutor) executeWithFallback(
+	ctx context.Context,
+	step chain.Model,
+	input string,
+	options map[string]interface{},
+) (string, chain.Model, error) {
+	return model.ExecuteWithFallback(ctx, step, func(m chain.Model) (string, error) {
+		return e.modelProvider.Execute(ctx, m, input, options)
+	})
+}
+
+// structuredOutput проверяет ответ шага по его схеме результата. Если ответ не
+// разбирается как JSON или нарушает схему, модели один раз отправляется
+// повторный запрос с ее ответом и найденными ошибками. Возвращает JSON без
+// окружающего текста, модель, которая его дала, и был ли нужен повторный запрос.
+func (e *DefaultTaskExecutor) structuredOutput(
+	ctx context.Context,
+	step chain.Model,
+	input string,
+	options map[string]interface{},
+	output string,
+	served chain.Model,
+) (string, chain.Model, bool, error) {
+	structured, err := chain.StructuredOutput(step.OutputSchema, output)
+	if err == nil {
+		return structured, served, false, nil
+	}
+
+	repairOutput, repairedBy, execErr := e.executeWithFallback(ctx, step, chain.RepairInput(input, output, err), options)
+	if execErr != nil {
+		return "", served, true, fmt.Errorf("%w; repair request failed: %v", err, execErr)
+	}
+
+	structured, err = chain.StructuredOutput(step.OutputSchema, repairOutput)
+	if err != nil {
+		return "", repairedBy, true, fmt.Errorf("output does not match the step schema after a repair request: %w", err)
+	}
+	return structured, repairedBy, true, nil
+}
+
 // executeSegmentationTask выполняет задачу типа TaskTypeSegmentation
 func (e *DefaultTaskExecutor) executeSegmentationTask(_ context.Context, task Task) error {
 	// Получаем входные данные
@@ -301,11 +372,21 @@ func (e *DefaultTaskExecutor) executeSegmentationTask(_ context.Context, task Ta
 		method = segmentation.SegmentationMethod(methodStr)
 	}
 
+	// Определяем перекрытие сегментов в токенах (после JSON число приходит как float64)
+	overlap := 0
+	switch v := task.Input.Metadata["overlap"].(type) {
+	case int:
+		overlap = v
+	case float64:
+		overlap = int(v)
+	}
+
 	// Создаем опции сегментации
 	options := segmentation.SegmentationOptions{
 		ChunkSize:   chunkSize,
 		MaxSegments: 0, // Без ограничений
 		Method:      method,
+		Overlap:     overlap,
 	}
 
 	// Сегментируем текст
@@ -316,6 +397,13 @@ func (e *DefaultTaskExecutor) executeSegmentationTask(_ context.Context, task Ta
 
 	// Создаем подзадачи для каждого сегмента
 	for i, segment := range segments {
+		// Номер сегмента и их общее число нужны для объединения результатов
+		metadata := make(map[string]interface{}, len(task.Input.Metadata)+1)
+		for key, value := range task.Input.Metadata {
+			metadata[key] = value
+		}
+		metadata["segment_count"] = len(segments)
+
 		// Создаем новую задачу для обработки сегмента
 		segmentTask := Task{
 			Type:         TaskTypeModelExecution, // По умолчанию используем модель
@@ -327,7 +415,7 @@ func (e *DefaultTaskExecutor) executeSegmentationTask(_ context.Context, task Ta
 				Type:     "text",
 				Source:   segment,
 				Segment:  i,
-				Metadata: task.Input.Metadata,
+				Metadata: metadata,
 			},
 			RunID:   task.RunID,
 			ChainID: task.ChainID,
@@ -0,0 +1,187 @@
+package task
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/grik-ai/ricochet-task/pkg/chain"
+	"github.com/grik-ai/ricochet-task/pkg/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// scriptedProvider возвращает заданные ошибки для моделей и записывает порядок вызовов
+type scriptedProvider struct {
+	errs  map[chain.ModelName]error
+	calls []chain.ModelName
+}
+
+func (p *scriptedProvider) Execute(ctx context.Context, m chain.Model, prompt string, options map[string]interface{}) (string, error) {
+	p.calls = append(p.calls, m.Name)
+	if err := p.errs[m.Name]; err != nil {
+		return "", err
+	}
+	return "answer from " + string(m.Name), nil
+}
+
+func (p *scriptedProvider) EstimateTokens(text string) int {
+	return len(text) / 4
+}
+
+func (p *scriptedProvider) GetModel(name chain.ModelName) (chain.ModelConfiguration, error) {
+	return chain.NewModelRegistry().GetModelByName(name)
+}
+
+// runModelTask создает задачу шага и выполняет ее
+func runModelTask(t *testing.T, provider ModelProvider, step chain.Model) (Task, error) {
+	t.Helper()
+
+	store, err := NewFileTaskStore(t.TempDir())
+	require.NoError(t, err)
+	manager := NewTaskManager(store)
+
+	_, err = manager.CreateTask(Task{
+		ID:    "task-1",
+		Type:  TaskTypeModelExecution,
+		Model: &step,
+		Input: TaskInput{Type: "text", Source: "input"},
+	})
+	require.NoError(t, err)
+
+	executor := NewTaskExecutor(manager, provider, DefaultExecutorConfig())
+	execErr := executor.ExecuteTask(context.Background(), "task-1")
+
+	saved, err := manager.GetTask("task-1")
+	require.NoError(t, err)
+	return saved, execErr
+}
+
+func TestExecuteModelTaskFallback(t *testing.T) {
+	step := chain.Model{
+		Name:           chain.ModelNameGPT4,
+		Type:           chain.ModelTypeOpenAI,
+		FallbackModels: []chain.ModelName{chain.ModelNameGPT4Turbo, chain.ModelNameClaude3Sonnet},
+	}
+	unavailable := &model.APIError{StatusCode: http.StatusServiceUnavailable, Message: "overloaded"}
+
+	t.Run("transient failure uses fallbacks in order", func(t *testing.T) {
+		provider := &scriptedProvider{errs: map[chain.ModelName]error{
+			chain.ModelNameGPT4:      unavailable,
+			chain.ModelNameGPT4Turbo: unavailable,
+		}}
+
+		saved, err := runModelTask(t, provider, step)
+		require.NoError(t, err)
+
+		assert.Equal(t, []chain.ModelName{chain.ModelNameGPT4, chain.ModelNameGPT4Turbo, chain.ModelNameClaude3Sonnet}, provider.calls)
+		assert.Equal(t, StatusCompleted, saved.Status)
+		assert.Equal(t, "answer from claude-3-sonnet", saved.Output.Destination)
+		assert.Equal(t, "claude-3-sonnet", saved.Output.Metadata["served_by"])
+		assert.Equal(t, "gpt-4", saved.Output.Metadata["fallback_from"])
+	})
+
+	t.Run("primary success records the primary model", func(t *testing.T) {
+		provider := &scriptedProvider{}
+
+		saved, err := runModelTask(t, provider, step)
+		require.NoError(t, err)
+
+		assert.Equal(t, []chain.ModelName{chain.ModelNameGPT4}, provider.calls)
+		assert.Equal(t, "gpt-4", saved.Output.Metadata["served_by"])
+		assert.NotContains(t, saved.Output.Metadata, "fallback_from")
+	})
+
+	t.Run("permanent failure does not fall back", func(t *testing.T) {
+		provider := &scriptedProvider{errs: map[chain.ModelName]error{
+			chain.ModelNameGPT4: &model.APIError{StatusCode: http.StatusUnauthorized, Message: "invalid api key"},
+		}}
+
+		saved, err := runModelTask(t, provider, step)
+		require.Error(t, err)
+
+		assert.Equal(t, []chain.ModelName{chain.ModelNameGPT4}, provider.calls)
+		assert.Equal(t, StatusFailed, saved.Status)
+	})
+
+	t.Run("all models failing reports every error", func(t *testing.T) {
+		provider := &scriptedProvider{errs: map[chain.ModelName]error{
+			chain.ModelNameGPT4:          unavailable,
+			chain.ModelNameGPT4Turbo:     unavailable,
+			chain.ModelNameClaude3Sonnet: unavailable,
+		}}
+
+		_, err := runModelTask(t, provider, step)
+		require.Error(t, err)
+
+		assert.Len(t, provider.calls, 3)
+		assert.Contains(t, err.Error(), "gpt-4-turbo: API error: overloaded")
+		assert.Contains(t, err.Error(), "claude-3-sonnet: API error: overloaded")
+	})
+}
+
+// replyingProvider отвечает заданными ответами по порядку и записывает запросы
+type replyingProvider struct {
+	scriptedProvider
+	replies []string
+	inputs  []string
+	prompts []string
+}
+
+func (p *replyingProvider) Execute(ctx context.Context, m chain.Model, prompt string, options map[string]interface{}) (string, error) {
+	p.calls = append(p.calls, m.Name)
+	p.inputs = append(p.inputs, prompt)
+	systemPrompt, _ := options["system_prompt"].(string)
+	p.prompts = append(p.prompts, systemPrompt)
+
+	reply := p.replies[0]
+	p.replies = p.replies[1:]
+	return reply, nil
+}
+
+func TestExecuteModelTaskOutputSchema(t *testing.T) {
+	schema, err := chain.ParseOutputSchema(`{
+		"type": "object",
+		"required": ["title"],
+		"properties": {"title": {"type": "string"}}
+	}`)
+	require.NoError(t, err)
+	step := chain.Model{Name: chain.ModelNameGPT4, Type: chain.ModelTypeOpenAI, Prompt: "Extract the task.", OutputSchema: schema}
+
+	t.Run("valid output is normalized", func(t *testing.T) {
+		provider := &replyingProvider{replies: []string{"```json\n{\"title\": \"Fix login\"}\n```"}}
+
+		saved, err := runModelTask(t, provider, step)
+		require.NoError(t, err)
+
+		assert.Len(t, provider.calls, 1)
+		assert.Contains(t, provider.prompts[0], "Extract the task.")
+		assert.Contains(t, provider.prompts[0], `"required"`)
+		assert.JSONEq(t, `{"title": "Fix login"}`, saved.Output.Destination)
+		assert.NotContains(t, saved.Output.Metadata, "schema_repaired")
+	})
+
+	t.Run("invalid output is repaired once", func(t *testing.T) {
+		provider := &replyingProvider{replies: []string{"The task is to fix login.", `{"title": "Fix login"}`}}
+
+		saved, err := runModelTask(t, provider, step)
+		require.NoError(t, err)
+
+		require.Len(t, provider.calls, 2)
+		assert.Contains(t, provider.inputs[1], "The task is to fix login.")
+		assert.Contains(t, provider.inputs[1], "input", "the repair request repeats the step input")
+		assert.JSONEq(t, `{"title": "Fix login"}`, saved.Output.Destination)
+		assert.Equal(t, true, saved.Output.Metadata["schema_repaired"])
+	})
+
+	t.Run("output still invalid after repair fails the step", func(t *testing.T) {
+		provider := &replyingProvider{replies: []string{`{"name": "Fix login"}`, `{"name": "Fix login"}`}}
+
+		saved, err := runModelTask(t, provider, step)
+		require.Error(t, err)
+
+		assert.Len(t, provider.calls, 2)
+		assert.Contains(t, err.Error(), "$.title")
+		assert.Equal(t, StatusFailed, saved.Status)
+	})
+}
@@ -308,6 +308,9 @@ type TaskManager interface {
 	// UpdateTaskStatus обновляет статус задачи
 	UpdateTaskStatus(taskID string, status TaskStatus) error
 
+	// UpdateTaskOutput сохраняет результат и метрики выполнения задачи
+	UpdateTaskOutput(taskID string, output TaskOutput, metrics TaskMetrics) error
+
 	// GetTask возвращает задачу по ID
 	GetTask(taskID string) (Task, error)
 
@@ -387,6 +390,19 @@ func (m *DefaultTaskManager) UpdateTaskStatus(taskID string, status TaskStatus)
 	return m.taskStore.Save(task)
 }
 
+// UpdateTaskOutput сохраняет результат и метрики выполнения задачи
+func (m *DefaultTaskManager) UpdateTaskOutput(taskID string, output TaskOutput, metrics TaskMetrics) error {
+	task, err := m.taskStore.Get(taskID)
+	if err != nil {
+		return err
+	}
+
+	task.Output = output
+	task.Metrics = metrics
+
+	return m.taskStore.Save(task)
+}
+
 // GetTask возвращает задачу по ID
 func (m *DefaultTaskManager) GetTask(taskID string) (Task, error) {
 	return m.taskStore.Get(taskID)
@@ -0,0 +1,357 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/briandowns/spinner"
+	"github.com/grik-ai/ricochet-task/internal/config"
+	"github.com/grik-ai/ricochet-task/pkg/chain"
+	"github.com/grik-ai/ricochet-task/pkg/model"
+	"github.com/grik-ai/ricochet-task/pkg/orchestrator"
+	"github.com/grik-ai/ricochet-task/pkg/segmentation"
+)
+
+const (
+	inputFromFile  = "📄 Файл"
+	inputFromPaste = "✍️  Вставить текст"
+)
+
+// chainRunSettings параметры запуска, выбранные в мастере
+type chainRunSettings struct {
+	chain       chain.Chain
+	input       string
+	inputSource string // Путь к файлу или описание вставленного текста
+	options     orchestrator.ProcessingOptions
+	temperature *float64 // Переопределение температуры всех моделей цепочки
+}
+
+// RunChainWizard пошагово запускает цепочку: выбор цепочки, входных данных и
+// параметров, затем выполнение с выводом ответов моделей по мере генерации
+func RunChainWizard() error {
+	if !IsInteractive() {
+		return fmt.Errorf("мастер запуска цепочки работает только в терминале, используйте ricochet chain run --chain <ID> --stream")
+	}
+
+	configPath, err := config.GetConfigPath()
+	if err != nil {
+		return fmt.Errorf("не удалось получить путь к конфигурации: %w", err)
+	}
+	cfg, err := config.LoadConfig(configPath)
+	if err != nil {
+		return fmt.Errorf("не удалось загрузить конфигурацию: %w", err)
+	}
+	store, err := chain.NewFileChainStore(cfg.ConfigDir)
+	if err != nil {
+		return fmt.Errorf("ошибка при создании хранилища цепочек: %w", err)
+	}
+
+	DrawBox("Запуск цепочки моделей", "Выберите цепочку, входные данные и параметры.\nОтветы моделей выводятся по мере генерации, Ctrl+C прерывает запуск.", 60)
+
+	settings := &chainRunSettings{options: orchestrator.DefaultProcessingOptions()}
+	if settings.chain, err = askChain(store); err != nil {
+		return err
+	}
+	if len(settings.chain.Models) == 0 {
+		PrintWarning(fmt.Sprintf("Цепочка '%s' не содержит моделей", settings.chain.Name))
+		return nil
+	}
+	if err := askInput(settings); err != nil {
+		return err
+	}
+	if err := askRunOptions(settings); err != nil {
+		return err
+	}
+
+	segments, err := segmentation.SegmentWithInfo(settings.input, settings.options.SegmentationOptions())
+	if err != nil {
+		return fmt.Errorf("ошибка сегментации входных данных: %w", err)
+	}
+	DrawBox("Параметры запуска", describeChainRun(settings, len(segments)), 60)
+	if !ConfirmPrompt("Запустить цепочку?") {
+		PrintInfo("Запуск отменен")
+		return nil
+	}
+
+	factory, err := orchestrator.NewModelFactory(cfg.ConfigDir)
+	if err != nil {
+		return err
+	}
+	result, err := runChainWithProgress(factory, settings)
+	if err != nil {
+		return err
+	}
+
+	return offerSaveResult(result)
+}
+
+// askChain предлагает выбрать одну из сохраненных цепочек
+func askChain(store chain.Store) (chain.Chain, error) {
+	chains, err := store.List()
+	if err != nil {
+		return chain.Chain{}, fmt.Errorf("ошибка получения списка цепочек: %w", err)
+	}
+	if len(chains) == 0 {
+		return chain.Chain{}, fmt.Errorf("нет сохраненных цепочек, создайте цепочку командой ricochet chain create")
+	}
+	sort.SliceStable(chains, func(i, j int) bool { return chains[i].Name < chains[j].Name })
+
+	options := make([]string, len(chains))
+	byOption := make(map[string]chain.Chain, len(chains))
+	for i, c := range chains {
+		options[i] = fmt.Sprintf("%s (моделей: %d) - %s", c.Name, len(c.Models), c.ID)
+		byOption[options[i]] = c
+	}
+
+	selected, err := SelectPrompt("Выберите цепочку:", options)
+	if err != nil {
+		return chain.Chain{}, err
+	}
+	return byOption[selected], nil
+}
+
+// askInput запрашивает входные данные: путь к файлу или вставленный текст
+func askInput(settings *chainRunSettings) error {
+	source, err := SelectPrompt("Откуда взять входные данные?", []string{inputFromFile, inputFromPaste})
+	if err != nil {
+		return err
+	}
+
+	if source == inputFromPaste {
+		var text string
+		prompt := &survey.Multiline{Message: "Вставьте текст (пустая строка завершает ввод):"}
+		if err := survey.AskOne(prompt, &text, survey.WithValidator(survey.Required)); err != nil {
+			return err
+		}
+		settings.input = text
+		settings.inputSource = fmt.Sprintf("вставленный текст, %d символов", len([]rune(text)))
+		return nil
+	}
+
+	var path string
+	prompt := &survey.Input{
+		Message: "Путь к файлу:",
+		Suggest: suggestPaths,
+	}
+	validate := func(answer interface{}) error {
+		info, err := os.Stat(strings.TrimSpace(answer.(string)))
+		if err != nil {
+			return fmt.Errorf("файл недоступен: %w", err)
+		}
+		if info.IsDir() {
+			return fmt.Errorf("укажите файл, а не директорию")
+		}
+		return nil
+	}
+	if err := survey.AskOne(prompt, &path, survey.WithValidator(validate)); err != nil {
+		return err
+	}
+
+	path = strings.TrimSpace(path)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("ошибка при чтении файла: %w", err)
+	}
+	settings.input = string(data)
+	settings.inputSource = path
+	return nil
+}
+
+// suggestPaths дополняет путь к файлу при нажатии Tab
+func suggestPaths(toComplete string) []string {
+	matches, _ := filepath.Glob(toComplete + "*")
+	for i, match := range matches {
+		if info, err := os.Stat(match); err == nil && info.IsDir() {
+			matches[i] = match + string(filepath.Separator)
+		}
+	}
+	return matches
+}
+
+// askRunOptions предлагает переопределить температуру и параметры сегментации.
+// Сегменты при потоковом запуске склеиваются по порядку.
+func askRunOptions(settings *chainRunSettings) error {
+	if !ConfirmPrompt("Изменить параметры запуска (температура, сегментация)?") {
+		return nil
+	}
+
+	var temperature string
+	err := survey.AskOne(&survey.Input{
+		Message: "Температура для всех моделей (пусто - как в цепочке):",
+	}, &temperature, survey.WithValidator(func(answer interface{}) error {
+		value := strings.TrimSpace(answer.(string))
+		if value == "" {
+			return nil
+		}
+		t, err := strconv.ParseFloat(value, 64)
+		if err != nil || t <= 0 || t > 2 {
+			return fmt.Errorf("температура - число больше 0 и не больше 2")
+		}
+		return nil
+	}))
+	if err != nil {
+		return err
+	}
+	if value := strings.TrimSpace(temperature); value != "" {
+		t, _ := strconv.ParseFloat(value, 64)
+		settings.temperature = &t
+	}
+
+	options := &settings.options
+	method, err := SelectPrompt("Метод сегментации:", []string{
+		segmentation.SegmentationSentence,
+		segmentation.SegmentationParagraph,
+		segmentation.SegmentationSemantic,
+		segmentation.SegmentationRecursive,
+	})
+	if err != nil {
+		return err
+	}
+	options.SegmentationMethod = method
+
+	if options.MaxTokensPerChunk, err = askPositiveInt("Максимальный размер сегмента в токенах:", options.MaxTokensPerChunk, 1); err != nil {
+		return err
+	}
+	if options.SegmentOverlap, err = askPositiveInt("Перекрытие сегментов в токенах:", options.SegmentOverlap, 0); err != nil {
+		return err
+	}
+
+	if err := options.Validate(); err != nil {
+		PrintWarning(fmt.Sprintf("Некорректные параметры сегментации: %v", err))
+		return askRunOptions(settings)
+	}
+	return nil
+}
+
+// askPositiveInt запрашивает целое число не меньше min
+func askPositiveInt(message string, defaultValue, min int) (int, error) {
+	var answer string
+	err := survey.AskOne(&survey.Input{
+		Message: message,
+		Default: strconv.Itoa(defaultValue),
+	}, &answer, survey.WithValidator(func(answer interface{}) error {
+		n, err := strconv.Atoi(strings.TrimSpace(answer.(string)))
+		if err != nil || n < min {
+			return fmt.Errorf("введите целое число не меньше %d", min)
+		}
+		return nil
+	}))
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(strings.TrimSpace(answer))
+}
+
+// describeChainRun описывает выбранные параметры запуска
+func describeChainRun(settings *chainRunSettings, segments int) string {
+	tokens := model.NewTokenEstimator().EstimateTokens(settings.input, "")
+
+	lines := []string{
+		fmt.Sprintf("Цепочка: %s (моделей: %d)", settings.chain.Name, len(settings.chain.Models)),
+		fmt.Sprintf("Вход: %s", settings.inputSource),
+		fmt.Sprintf("Объем: ~%d токенов, сегментов: %d", tokens, segments),
+	}
+	if segments > 1 {
+		lines = append(lines, fmt.Sprintf("Сегментация: %s, до %d токенов, перекрытие %d",
+			settings.options.SegmentationMethod, settings.options.MaxTokensPerChunk, settings.options.SegmentOverlap))
+	}
+	if settings.temperature != nil {
+		lines = append(lines, fmt.Sprintf("Температура: %g", *settings.temperature))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// runChainWithProgress выполняет цепочку, показывая шаг и ответ каждой модели
+func runChainWithProgress(factory *model.ProviderFactory, settings *chainRunSettings) (string, error) {
+	c := settings.chain
+	if settings.temperature != nil {
+		c.Models = append([]chain.Model(nil), c.Models...)
+		for i := range c.Models {
+			c.Models[i].Temperature = *settings.temperature
+		}
+	}
+
+	// Прерывание по Ctrl+C закрывает поток и останавливает цепочку
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	started := time.Now()
+	var stepStarted time.Time
+	var waiting *spinner.Spinner
+	stopWaiting := func() {
+		if waiting != nil {
+			waiting.Stop()
+			waiting = nil
+		}
+	}
+	defer stopWaiting()
+
+	result, err := orchestrator.RunChainStream(ctx, factory, c, settings.input, settings.options, func(event orchestrator.StreamEvent) {
+		switch event.Type {
+		case orchestrator.StreamEventModelStarted:
+			stepStarted = time.Now()
+			current := (event.Segment-1)*event.Steps + event.Step
+			header := fmt.Sprintf("[%d/%d] Модель %d/%d: %s", current, event.Segments*event.Steps, event.Step, event.Steps, event.Model.Name)
+			if event.Segments > 1 {
+				header = fmt.Sprintf("[%d/%d] Сегмент %d/%d, модель %d/%d: %s", current, event.Segments*event.Steps,
+					event.Segment, event.Segments, event.Step, event.Steps, event.Model.Name)
+			}
+			fmt.Printf("\n%s\n", BoldColor(header))
+			waiting = CreateSpinner("Ожидание ответа модели...")
+			waiting.Start()
+		case orchestrator.StreamEventFallback:
+			stopWaiting()
+			fmt.Printf("\n%s\n", WarningColor(fmt.Sprintf("Модель недоступна, ответ дает запасная модель %s", event.Model.Name)))
+			waiting = CreateSpinner("Ожидание ответа модели...")
+			waiting.Start()
+		case orchestrator.StreamEventChunk:
+			stopWaiting()
+			fmt.Print(event.Chunk)
+		case orchestrator.StreamEventModelFinished:
+			stopWaiting()
+			fmt.Printf("\n%s\n", InfoColor(fmt.Sprintf("(%s)", time.Since(stepStarted).Round(100*time.Millisecond))))
+		}
+	})
+	stopWaiting()
+	if err != nil {
+		if errors.Is(err, context.Canceled) {
+			return "", fmt.Errorf("запуск прерван")
+		}
+		return "", err
+	}
+
+	fmt.Println()
+	PrintSuccess(fmt.Sprintf("Цепочка '%s' выполнена за %s", c.Name, time.Since(started).Round(time.Second)))
+	return result, nil
+}
+
+// offerSaveResult предлагает сохранить результат цепочки в файл
+func offerSaveResult(result string) error {
+	var path string
+	if err := survey.AskOne(&survey.Input{
+		Message: "Сохранить результат в файл (пусто - не сохранять):",
+		Suggest: suggestPaths,
+	}, &path); err != nil {
+		return err
+	}
+
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return nil
+	}
+	if err := os.WriteFile(path, []byte(result), 0644); err != nil {
+		PrintError(fmt.Sprintf("Не удалось сохранить результат: %v", err))
+		return nil
+	}
+	PrintSuccess("Результат сохранен в " + path)
+	return nil
+}
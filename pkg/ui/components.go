@@ -2,8 +2,10 @@ package ui
 
 import (
 	"fmt"
+	"os"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/AlecAivazis/survey/v2"
 	"github.com/briandowns/spinner"
@@ -65,8 +67,9 @@ func DrawBox(title string, content string, boxWidth int) {
 	// Верхняя граница с заголовком
 	topBorder := "╔"
 	titleWithSpaces := " " + title + " "
-	sideLength := (boxWidth - len(titleWithSpaces)) / 2
-	topBorder += strings.Repeat("═", sideLength) + titleWithSpaces + strings.Repeat("═", boxWidth-sideLength-len(titleWithSpaces))
+	titleWidth := utf8.RuneCountInString(titleWithSpaces)
+	sideLength := (boxWidth - titleWidth) / 2
+	topBorder += strings.Repeat("═", sideLength) + titleWithSpaces + strings.Repeat("═", boxWidth-sideLength-titleWidth)
 	topBorder += "╗"
 
 	// Разбиваем содержимое на строки
@@ -77,11 +80,12 @@ func DrawBox(title string, content string, boxWidth int) {
 
 	// Выводим содержимое
 	for _, line := range lines {
+		// Ширина считается в символах, а не байтах, чтобы не разрезать кириллицу
 		paddedLine := line
-		if len(line) < boxWidth {
-			paddedLine += strings.Repeat(" ", boxWidth-len(line))
-		} else if len(line) > boxWidth {
-			paddedLine = line[:boxWidth-3] + "..."
+		if width := utf8.RuneCountInString(line); width < boxWidth {
+			paddedLine += strings.Repeat(" ", boxWidth-width)
+		} else if width > boxWidth {
+			paddedLine = string([]rune(line)[:boxWidth-3]) + "..."
 		}
 		color.New(color.FgCyan).Print("║ ")
 		fmt.Print(paddedLine)
@@ -122,6 +126,18 @@ func SelectPrompt(message string, options []string) (string, error) {
 	return selected, err
 }
 
+// IsInteractive сообщает, что ввод и вывод подключены к терминалу
+// и пользователю можно задавать вопросы
+func IsInteractive() bool {
+	for _, file := range []*os.File{os.Stdin, os.Stdout} {
+		info, err := file.Stat()
+		if err != nil || info.Mode()&os.ModeCharDevice == 0 {
+			return false
+		}
+	}
+	return true
+}
+
 // InputPrompt запрашивает текстовый ввод
 func InputPrompt(message string) (string, error) {
 	var input string
@@ -132,6 +148,16 @@ func InputPrompt(message string) (string, error) {
 	return input, err
 }
 
+// PasswordPrompt запрашивает секрет, не показывая вводимые символы
+func PasswordPrompt(message string) (string, error) {
+	var input string
+	prompt := &survey.Password{
+		Message: message,
+	}
+	err := survey.AskOne(prompt, &input)
+	return input, err
+}
+
 // PrintInfo выводит информационное сообщение
 func PrintInfo(message string) {
 	fmt.Println(InfoEmoji + InfoColor(message))
@@ -370,8 +370,9 @@ func handleDeleteChain() error {
 }
 
 func handleRunChain() error {
-	PrintInfo("Запуск цепочки моделей")
-	// TODO: Реализовать запуск цепочки
+	if err := RunChainWizard(); err != nil {
+		PrintError(err.Error())
+	}
 	return ShowChainManagementMenu()
 }
 
@@ -180,8 +180,12 @@ func (eb *EventBus) publishToHandlers(ctx context.Context, event Event) error {
 	
 	eventType := event.GetType()
 	
-	// Отправляем обработчикам
-	if handlers, exists := eb.handlers[eventType]; exists {
+	// Отправляем обработчикам, включая подписанных на все события ("*")
+	handlers := eb.handlers[eventType]
+	if eventType != "*" {
+		handlers = append(handlers[:len(handlers):len(handlers)], eb.handlers["*"]...)
+	}
+	if len(handlers) > 0 {
 		for _, handler := range handlers {
 			if handler.CanHandle(eventType) {
 				handlerStart := time.Now()
@@ -4,6 +4,7 @@ import (
 	"context"
 	"net/http"
 	"net/http/httptest"
+	"os"
 	"strings"
 	"testing"
 	"time"
@@ -17,9 +18,24 @@ func (ml *MockLogger) Info(msg string, args ...interface{})  {}
 func (ml *MockLogger) Warn(msg string, args ...interface{})  {}
 func (ml *MockLogger) Error(msg string, err error, args ...interface{}) {}
 
+// useTempWorkDir переносит рабочий каталог теста во временный, чтобы прогресс
+// задач сохранялся в нём, а не в data/progress репозитория
+func useTempWorkDir(tb testing.TB) {
+	tb.Helper()
+	wd, err := os.Getwd()
+	if err != nil {
+		tb.Fatalf("Failed to get working directory: %v", err)
+	}
+	if err := os.Chdir(tb.TempDir()); err != nil {
+		tb.Fatalf("Failed to change working directory: %v", err)
+	}
+	tb.Cleanup(func() { os.Chdir(wd) })
+}
+
 // TestGitProgressTrackerIntegration тестирует полную интеграцию Git tracking
 func TestGitProgressTrackerIntegration(t *testing.T) {
 	logger := &MockLogger{}
+	useTempWorkDir(t)
 	tracker := NewGitProgressTracker(nil, logger)
 	
 	// Тестируем обработку push события
@@ -229,6 +245,7 @@ func TestBranchPatterns(t *testing.T) {
 // TestWebhookHandling тестирует обработку webhook
 func TestWebhookHandling(t *testing.T) {
 	logger := &MockLogger{}
+	useTempWorkDir(t)
 	tracker := NewGitProgressTracker(nil, logger)
 	handler := tracker.GetWebhookHandler()
 	
@@ -307,6 +324,7 @@ func TestWebhookHandling(t *testing.T) {
 // TestProgressEngine тестирует движок прогресса
 func TestProgressEngine(t *testing.T) {
 	logger := &MockLogger{}
+	useTempWorkDir(t)
 	tracker := NewGitProgressTracker(nil, logger)
 	engine := tracker.progressEngine
 	
@@ -454,6 +472,7 @@ func BenchmarkPatternMatching(b *testing.B) {
 // BenchmarkProgressUpdate бенчмарк для обновления прогресса
 func BenchmarkProgressUpdate(b *testing.B) {
 	logger := &MockLogger{}
+	useTempWorkDir(b)
 	tracker := NewGitProgressTracker(nil, logger)
 	
 	gitEvent := &GitProgressEvent{
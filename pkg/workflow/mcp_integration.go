@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sort"
 	"sync"
 	"time"
 
@@ -13,7 +14,7 @@ import (
 // MCPIntegration интеграция с Model Context Protocol
 type MCPIntegration struct {
 	tools           map[string]MCPTool
-	workflows       *WorkflowEngine
+	workflows       *CompleteWorkflowEngine
 	aiChains        *ai.AIChains
 	logger          Logger
 	eventBus        *EventBus
@@ -113,9 +114,17 @@ type MCPResource struct {
 	Content     interface{}            `json:"content"`
 	Metadata    map[string]interface{} `json:"metadata"`
 	AccessLevel string                 `json:"access_level"`
+	Owner       string                 `json:"owner,omitempty"`
 	TTL         time.Duration          `json:"ttl,omitempty"`
 }
 
+// Уровни доступа к ресурсам MCP
+const (
+	MCPAccessPrivate = "private" // только владелец
+	MCPAccessShared  = "shared"  // владелец и пользователи с выданным доступом
+	MCPAccessPublic  = "public"  // любой аутентифицированный пользователь
+)
+
 // MCPResourceManager менеджер ресурсов MCP
 type MCPResourceManager struct {
 	resources map[string]*MCPResource
@@ -125,7 +134,7 @@ type MCPResourceManager struct {
 }
 
 // NewMCPIntegration создает новую MCP интеграцию
-func NewMCPIntegration(workflows *WorkflowEngine, aiChains *ai.AIChains, eventBus *EventBus, config *MCPConfig, logger Logger) *MCPIntegration {
+func NewMCPIntegration(workflows *CompleteWorkflowEngine, aiChains *ai.AIChains, eventBus *EventBus, config *MCPConfig, logger Logger) *MCPIntegration {
 	if config == nil {
 		config = &MCPConfig{
 			MaxConcurrentOps: 10,
@@ -176,6 +185,9 @@ func (mcp *MCPIntegration) registerBuiltinTools() {
 	
 	// Workflow Control Tool
 	mcp.RegisterTool(NewWorkflowControlTool(mcp.workflows, mcp.logger))
+
+	// Workflow History Tool
+	mcp.RegisterTool(NewWorkflowHistoryTool(mcp.workflows, mcp.logger))
 	
 	// Resource Management Tool
 	mcp.RegisterTool(NewResourceManagementTool(mcp.resourceManager, mcp.logger))
@@ -566,7 +578,31 @@ func (rm *MCPResourceManager) AddResource(uri string, resource *MCPResource) {
 	rm.logger.Debug("MCP resource added", "uri", uri, "type", resource.Type)
 }
 
+// CreateResource добавляет ресурс от имени его владельца. Существующий ресурс
+// может перезаписать только его владелец, иначе чужой пользователь забрал бы
+// ресурс вместе с правом владения.
+func (rm *MCPResourceManager) CreateResource(uri string, resource *MCPResource) error {
+	if resource.Owner == "" {
+		return fmt.Errorf("authentication required to create resource %s", uri)
+	}
+
+	rm.mutex.Lock()
+	defer rm.mutex.Unlock()
+
+	if existing, exists := rm.resources[uri]; exists && existing.Owner != resource.Owner {
+		return fmt.Errorf("resource %s already exists and belongs to another user", uri)
+	}
+
+	rm.resources[uri] = resource
+	rm.logger.Debug("MCP resource created", "uri", uri, "owner", resource.Owner)
+	return nil
+}
+
 func (rm *MCPResourceManager) GetResource(uri string, userID string) (*MCPResource, error) {
+	if userID == "" {
+		return nil, fmt.Errorf("authentication required to access resource %s", uri)
+	}
+
 	rm.mutex.RLock()
 	defer rm.mutex.RUnlock()
 
@@ -576,10 +612,8 @@ func (rm *MCPResourceManager) GetResource(uri string, userID string) (*MCPResour
 	}
 
 	// Проверяем права доступа
-	if allowedUsers, exists := rm.access[uri]; exists {
-		if !mcpContains(allowedUsers, userID) {
-			return nil, fmt.Errorf("access denied to resource %s", uri)
-		}
+	if !rm.canAccess(uri, resource, userID) {
+		return nil, fmt.Errorf("access denied to resource %s", uri)
 	}
 
 	return resource, nil
@@ -598,20 +632,71 @@ func (rm *MCPResourceManager) GrantAccess(uri string, userID string) {
 	}
 }
 
+// GrantAccessBy выдает доступ к ресурсу от имени владельца.
+// Доступ к private ресурсам выдать нельзя - их видит только владелец.
+func (rm *MCPResourceManager) GrantAccessBy(uri string, grantorID string, userID string) error {
+	if grantorID == "" {
+		return fmt.Errorf("authentication required to grant access to resource %s", uri)
+	}
+
+	rm.mutex.RLock()
+	resource, exists := rm.resources[uri]
+	rm.mutex.RUnlock()
+
+	if !exists {
+		return fmt.Errorf("resource %s not found", uri)
+	}
+	if resource.Owner != grantorID {
+		return fmt.Errorf("only the owner can grant access to resource %s", uri)
+	}
+	if resource.AccessLevel == MCPAccessPrivate {
+		return fmt.Errorf("resource %s is private and cannot be shared", uri)
+	}
+
+	rm.GrantAccess(uri, userID)
+	return nil
+}
+
 func (rm *MCPResourceManager) ListResources(userID string) []string {
+	if userID == "" {
+		return nil
+	}
+
 	rm.mutex.RLock()
 	defer rm.mutex.RUnlock()
 
 	var accessible []string
-	for uri, allowedUsers := range rm.access {
-		if mcpContains(allowedUsers, userID) {
+	for uri, resource := range rm.resources {
+		if rm.canAccess(uri, resource, userID) {
 			accessible = append(accessible, uri)
 		}
 	}
 
+	sort.Strings(accessible)
 	return accessible
 }
 
+// canAccess проверяет может ли пользователь читать ресурс.
+// Вызывается под блокировкой rm.mutex.
+func (rm *MCPResourceManager) canAccess(uri string, resource *MCPResource, userID string) bool {
+	if userID == "" {
+		return false
+	}
+	if resource.Owner != "" && resource.Owner == userID {
+		return true
+	}
+
+	switch resource.AccessLevel {
+	case MCPAccessPublic:
+		return true
+	case MCPAccessPrivate:
+		return false
+	default:
+		// shared и ресурсы без явного уровня доступны только по выданному доступу
+		return mcpContains(rm.access[uri], userID)
+	}
+}
+
 // MCPEventHandler адаптер для событий MCP
 type MCPEventHandler struct {
 	handler func(event Event)
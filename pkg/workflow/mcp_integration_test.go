@@ -2,7 +2,9 @@ package workflow
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"path/filepath"
 	"testing"
 	"time"
 )
@@ -216,7 +218,29 @@ func TestAIAnalysisTool(t *testing.T) {
 // TestWorkflowControlTool тестирует инструмент управления workflow
 func TestWorkflowControlTool(t *testing.T) {
 	logger := &MockLogger{}
-	tool := NewWorkflowControlTool(nil, logger)
+	config := GetDefaultCompleteConfig()
+	config.AuditLogPath = filepath.Join(t.TempDir(), "history.jsonl")
+	engine, err := NewCompleteWorkflowEngine(nil, config, logger)
+	if err != nil {
+		t.Fatalf("Failed to create workflow engine: %v", err)
+	}
+	tool := NewWorkflowControlTool(engine, logger)
+
+	instance, err := engine.CreateWorkflow(context.Background(), &WorkflowDefinition{
+		Name: "Control Test Workflow",
+		Stages: map[string]*StageDefinition{
+			"development": {Name: "development"},
+			"testing":     {Name: "testing"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create workflow: %v", err)
+	}
+	instance.Tasks["task-123"] = &TaskInstance{
+		ID:      "task-123",
+		Status:  "in_progress",
+		Context: map[string]interface{}{"stage_name": "development"},
+	}
 
 	t.Run("GetSchema", func(t *testing.T) {
 		schema := tool.GetSchema()
@@ -249,11 +273,15 @@ func TestWorkflowControlTool(t *testing.T) {
 		// Проверяем структуру результата
 		result, ok := output.Result.([]map[string]interface{})
 		if !ok {
-			t.Error("Unexpected result format")
+			t.Fatal("Unexpected result format")
+		}
+
+		if len(result) != 1 {
+			t.Fatalf("Expected 1 workflow, got %d", len(result))
 		}
 
-		if len(result) == 0 {
-			t.Error("No workflows returned")
+		if result[0]["id"] != instance.ID || result[0]["name"] != "Control Test Workflow" {
+			t.Errorf("Unexpected workflow in list: %v", result[0])
 		}
 	})
 
@@ -261,7 +289,7 @@ func TestWorkflowControlTool(t *testing.T) {
 		input := &MCPToolInput{
 			Parameters: map[string]interface{}{
 				"action":      "status",
-				"workflow_id": "test-workflow-1",
+				"workflow_id": instance.ID,
 			},
 		}
 
@@ -273,6 +301,31 @@ func TestWorkflowControlTool(t *testing.T) {
 		if !output.Success {
 			t.Error("Get status failed")
 		}
+
+		status := output.Result.(map[string]interface{})
+		if status["status"] != "created" {
+			t.Errorf("Expected status 'created', got %v", status["status"])
+		}
+		if status["progress"] != 0.0 {
+			t.Errorf("Expected progress 0, got %v", status["progress"])
+		}
+	})
+
+	t.Run("GetStatusUnknownWorkflow", func(t *testing.T) {
+		input := &MCPToolInput{
+			Parameters: map[string]interface{}{
+				"action":      "status",
+				"workflow_id": "missing-workflow",
+			},
+		}
+
+		output, err := tool.Execute(context.Background(), input)
+		if !errors.Is(err, ErrWorkflowNotFound) {
+			t.Errorf("Expected ErrWorkflowNotFound, got %v", err)
+		}
+		if output.Success {
+			t.Error("Expected failure for unknown workflow")
+		}
 	})
 
 	t.Run("TransitionTask", func(t *testing.T) {
@@ -303,9 +356,179 @@ func TestWorkflowControlTool(t *testing.T) {
 			t.Error("Task ID not preserved")
 		}
 
+		if result["old_stage"] != "development" {
+			t.Errorf("Expected old stage 'development', got %v", result["old_stage"])
+		}
+
 		if result["new_stage"] != "testing" {
 			t.Error("New stage not set correctly")
 		}
+
+		if instance.Tasks["task-123"].Context["stage_name"] != "testing" {
+			t.Error("Task stage not updated in engine")
+		}
+	})
+
+	t.Run("TransitionValidation", func(t *testing.T) {
+		input := &MCPToolInput{
+			Parameters: map[string]interface{}{
+				"action":    "transition",
+				"task_id":   "task-123",
+				"new_stage": "deployment",
+			},
+		}
+		if _, err := tool.Execute(context.Background(), input); !errors.Is(err, ErrInvalidStage) {
+			t.Errorf("Expected ErrInvalidStage, got %v", err)
+		}
+
+		input.Parameters["task_id"] = "missing-task"
+		input.Parameters["new_stage"] = "testing"
+		if _, err := tool.Execute(context.Background(), input); !errors.Is(err, ErrTaskNotFound) {
+			t.Errorf("Expected ErrTaskNotFound, got %v", err)
+		}
+	})
+
+	t.Run("ControlWorkflow", func(t *testing.T) {
+		control := func(action string) (*MCPToolOutput, error) {
+			return tool.Execute(context.Background(), &MCPToolInput{
+				Parameters: map[string]interface{}{
+					"action":      action,
+					"workflow_id": instance.ID,
+				},
+			})
+		}
+
+		// Нельзя возобновить workflow, который не на паузе
+		if _, err := control("resume"); !errors.Is(err, ErrInvalidWorkflowState) {
+			t.Errorf("Expected ErrInvalidWorkflowState, got %v", err)
+		}
+
+		output, err := control("stop")
+		if err != nil {
+			t.Fatalf("Failed to stop workflow: %v", err)
+		}
+		if output.Result.(map[string]interface{})["status"] != "stopped" {
+			t.Errorf("Expected stopped status, got %v", output.Result)
+		}
+
+		if _, err := control("start"); !errors.Is(err, ErrInvalidWorkflowState) {
+			t.Errorf("Expected stopped workflow to refuse start, got %v", err)
+		}
+	})
+}
+
+// TestWorkflowHistoryTool тестирует журнал переходов задач
+func TestWorkflowHistoryTool(t *testing.T) {
+	logger := &MockLogger{}
+	config := GetDefaultCompleteConfig()
+	config.AuditLogPath = filepath.Join(t.TempDir(), "history.jsonl")
+	engine, err := NewCompleteWorkflowEngine(nil, config, logger)
+	if err != nil {
+		t.Fatalf("Failed to create workflow engine: %v", err)
+	}
+
+	instance, err := engine.CreateWorkflow(context.Background(), &WorkflowDefinition{
+		Name: "History Test Workflow",
+		Stages: map[string]*StageDefinition{
+			"development": {Name: "development"},
+			"review":      {Name: "review"},
+			"done":        {Name: "done"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create workflow: %v", err)
+	}
+	instance.Tasks["task-1"] = &TaskInstance{
+		ID:      "task-1",
+		Context: map[string]interface{}{"stage_name": "development"},
+	}
+
+	control := NewWorkflowControlTool(engine, logger)
+	for _, stage := range []string{"review", "done"} {
+		_, err := control.Execute(context.Background(), &MCPToolInput{
+			Parameters: map[string]interface{}{
+				"action":    "transition",
+				"task_id":   "task-1",
+				"new_stage": stage,
+				"reason":    "moved to " + stage,
+			},
+			Context: &MCPExecutionContext{UserID: "alice"},
+		})
+		if err != nil {
+			t.Fatalf("Failed to transition task: %v", err)
+		}
+	}
+
+	tool := NewWorkflowHistoryTool(engine, logger)
+
+	t.Run("FullHistory", func(t *testing.T) {
+		output, err := tool.Execute(context.Background(), &MCPToolInput{
+			Parameters: map[string]interface{}{"task_id": "task-1"},
+		})
+		if err != nil || !output.Success {
+			t.Fatalf("Failed to get history: %v %s", err, output.Error)
+		}
+
+		records := output.Result.([]*TransitionRecord)
+		if len(records) != 2 {
+			t.Fatalf("Expected 2 transitions, got %d", len(records))
+		}
+
+		first := records[0]
+		if first.FromStage != "development" || first.ToStage != "review" {
+			t.Errorf("Unexpected first transition: %s -> %s", first.FromStage, first.ToStage)
+		}
+		if first.Actor != "alice" || first.Reason != "moved to review" {
+			t.Errorf("Actor or reason not recorded: %+v", first)
+		}
+		if records[1].FromStage != "review" {
+			t.Errorf("Expected second transition from review, got %s", records[1].FromStage)
+		}
+	})
+
+	t.Run("DateRange", func(t *testing.T) {
+		output, _ := tool.Execute(context.Background(), &MCPToolInput{
+			Parameters: map[string]interface{}{
+				"task_id": "task-1",
+				"until":   "2000-01-01",
+			},
+		})
+		if records := output.Result.([]*TransitionRecord); len(records) != 0 {
+			t.Errorf("Expected no transitions before 2000, got %d", len(records))
+		}
+
+		output, _ = tool.Execute(context.Background(), &MCPToolInput{
+			Parameters: map[string]interface{}{
+				"task_id": "task-1",
+				"since":   time.Now().Add(-time.Hour).Format(time.RFC3339),
+			},
+		})
+		if records := output.Result.([]*TransitionRecord); len(records) != 2 {
+			t.Errorf("Expected 2 recent transitions, got %d", len(records))
+		}
+	})
+
+	t.Run("InvalidDate", func(t *testing.T) {
+		output, _ := tool.Execute(context.Background(), &MCPToolInput{
+			Parameters: map[string]interface{}{
+				"task_id": "task-1",
+				"since":   "yesterday",
+			},
+		})
+		if output.Success {
+			t.Error("Expected invalid date to be rejected")
+		}
+	})
+
+	t.Run("PersistedLog", func(t *testing.T) {
+		// Журнал читается независимо от движка, как в CLI
+		records, err := NewFileTransitionLog(config.AuditLogPath, logger).Query(TransitionFilter{TaskID: "task-1"})
+		if err != nil {
+			t.Fatalf("Failed to read log: %v", err)
+		}
+		if len(records) != 2 {
+			t.Errorf("Expected 2 persisted transitions, got %d", len(records))
+		}
 	})
 }
 
@@ -320,11 +543,13 @@ func TestResourceManagementTool(t *testing.T) {
 			Parameters: map[string]interface{}{
 				"action":       "create",
 				"resource_uri": "test://resource1",
+				"access_level": MCPAccessShared,
 				"resource_data": map[string]interface{}{
 					"title": "Test Resource",
 					"data":  "Some test data",
 				},
 			},
+			Context: &MCPExecutionContext{UserID: "owner1"},
 		}
 
 		output, err := tool.Execute(context.Background(), input)
@@ -345,6 +570,7 @@ func TestResourceManagementTool(t *testing.T) {
 				"resource_uri": "test://resource1",
 				"user_id":      "user123",
 			},
+			Context: &MCPExecutionContext{UserID: "owner1"},
 		}
 
 		output, err := tool.Execute(context.Background(), grantInput)
@@ -359,9 +585,9 @@ func TestResourceManagementTool(t *testing.T) {
 		// Теперь проверяем список ресурсов для пользователя
 		listInput := &MCPToolInput{
 			Parameters: map[string]interface{}{
-				"action":  "list",
-				"user_id": "user123",
+				"action": "list",
 			},
+			Context: &MCPExecutionContext{UserID: "user123"},
 		}
 
 		listOutput, err := tool.Execute(context.Background(), listInput)
@@ -406,8 +632,8 @@ func TestResourceManagementTool(t *testing.T) {
 			Parameters: map[string]interface{}{
 				"action":       "read",
 				"resource_uri": "test://resource1",
-				"user_id":      "user123",
 			},
+			Context: &MCPExecutionContext{UserID: "user123"},
 		}
 
 		output, err := tool.Execute(context.Background(), input)
@@ -429,6 +655,163 @@ func TestResourceManagementTool(t *testing.T) {
 			t.Error("Wrong resource URI")
 		}
 	})
+
+	t.Run("RejectAnonymous", func(t *testing.T) {
+		for _, action := range []string{"list", "read"} {
+			input := &MCPToolInput{
+				Parameters: map[string]interface{}{
+					"action":       action,
+					"resource_uri": "test://resource1",
+				},
+			}
+
+			output, _ := tool.Execute(context.Background(), input)
+			if output.Success {
+				t.Errorf("Expected %s without user to be rejected", action)
+			}
+		}
+	})
+
+	t.Run("PrivateResourceIsolation", func(t *testing.T) {
+		createInput := &MCPToolInput{
+			Parameters: map[string]interface{}{
+				"action":        "create",
+				"resource_uri":  "test://private1",
+				"resource_data": map[string]interface{}{"secret": "value"},
+			},
+			Context: &MCPExecutionContext{UserID: "alice"},
+		}
+		if output, err := tool.Execute(context.Background(), createInput); err != nil || !output.Success {
+			t.Fatalf("Failed to create private resource: %v", err)
+		}
+
+		// Чужой пользователь не может прочитать private ресурс
+		readInput := &MCPToolInput{
+			Parameters: map[string]interface{}{
+				"action":       "read",
+				"resource_uri": "test://private1",
+			},
+			Context: &MCPExecutionContext{UserID: "mallory"},
+		}
+		output, err := tool.Execute(context.Background(), readInput)
+		if err == nil || output.Success {
+			t.Error("Expected access to another user's private resource to be denied")
+		}
+
+		// И не видит его в списке
+		listInput := &MCPToolInput{
+			Parameters: map[string]interface{}{
+				"action": "list",
+			},
+			Context: &MCPExecutionContext{UserID: "mallory"},
+		}
+		listOutput, err := tool.Execute(context.Background(), listInput)
+		if err != nil || !listOutput.Success {
+			t.Fatalf("Failed to list resources: %v", err)
+		}
+		resources := listOutput.Result.(map[string]interface{})["resources"].([]string)
+		if len(resources) != 0 {
+			t.Errorf("Expected no visible resources, got %v", resources)
+		}
+
+		// Private ресурс нельзя расшарить
+		grantInput := &MCPToolInput{
+			Parameters: map[string]interface{}{
+				"action":       "grant_access",
+				"resource_uri": "test://private1",
+				"user_id":      "mallory",
+			},
+			Context: &MCPExecutionContext{UserID: "alice"},
+		}
+		if output, _ := tool.Execute(context.Background(), grantInput); output.Success {
+			t.Error("Expected grant on private resource to be rejected")
+		}
+
+		// Параметр user_id не подменяет пользователя контекста
+		readInput.Parameters["user_id"] = "alice"
+		if output, err := tool.Execute(context.Background(), readInput); err == nil || output.Success {
+			t.Error("Expected user_id parameter not to grant the owner's identity")
+		}
+
+		// Без контекста user_id не аутентифицирует вовсе
+		spoofInput := &MCPToolInput{
+			Parameters: map[string]interface{}{
+				"action":       "read",
+				"resource_uri": "test://private1",
+				"user_id":      "alice",
+			},
+		}
+		if output, _ := tool.Execute(context.Background(), spoofInput); output.Success {
+			t.Error("Expected user_id without execution context to be rejected")
+		}
+
+		// Владелец читает свой ресурс
+		readInput.Context = &MCPExecutionContext{UserID: "alice"}
+		if output, err := tool.Execute(context.Background(), readInput); err != nil || !output.Success {
+			t.Errorf("Owner should be able to read own resource: %v", err)
+		}
+	})
+
+	t.Run("CreateRequiresOwner", func(t *testing.T) {
+		takeover := &MCPToolInput{
+			Parameters: map[string]interface{}{
+				"action":        "create",
+				"resource_uri":  "test://resource1",
+				"access_level":  MCPAccessPublic,
+				"resource_data": map[string]interface{}{"title": "Hijacked"},
+			},
+			Context: &MCPExecutionContext{UserID: "mallory"},
+		}
+		if output, _ := tool.Execute(context.Background(), takeover); output.Success {
+			t.Error("Expected create over another user's resource to be rejected")
+		}
+
+		resource, err := resourceManager.GetResource("test://resource1", "owner1")
+		if err != nil {
+			t.Fatalf("Failed to read resource: %v", err)
+		}
+		if resource.Owner != "owner1" || resource.AccessLevel != MCPAccessShared {
+			t.Errorf("Resource was taken over: owner %s, access %s", resource.Owner, resource.AccessLevel)
+		}
+
+		// Владелец может перезаписать свой ресурс
+		takeover.Context = &MCPExecutionContext{UserID: "owner1"}
+		takeover.Parameters["access_level"] = MCPAccessShared
+		if output, _ := tool.Execute(context.Background(), takeover); !output.Success {
+			t.Errorf("Owner should be able to recreate own resource: %s", output.Error)
+		}
+	})
+
+	t.Run("GrantRequiresOwner", func(t *testing.T) {
+		input := &MCPToolInput{
+			Parameters: map[string]interface{}{
+				"action":       "grant_access",
+				"resource_uri": "test://resource1",
+				"user_id":      "mallory",
+			},
+			Context: &MCPExecutionContext{UserID: "user123"},
+		}
+
+		output, _ := tool.Execute(context.Background(), input)
+		if output.Success {
+			t.Error("Expected grant by non-owner to be rejected")
+		}
+	})
+
+	t.Run("PublicResource", func(t *testing.T) {
+		resourceManager.AddResource("test://public1", &MCPResource{
+			URI:         "test://public1",
+			AccessLevel: MCPAccessPublic,
+			Owner:       "alice",
+		})
+
+		if _, err := resourceManager.GetResource("test://public1", "bob"); err != nil {
+			t.Errorf("Public resource should be readable: %v", err)
+		}
+		if _, err := resourceManager.GetResource("test://public1", ""); err == nil {
+			t.Error("Public resource should still require authentication")
+		}
+	})
 }
 
 // TestCodeAnalysisTool тестирует инструмент анализа кода
@@ -144,11 +144,11 @@ func (tool *AIAnalysisTool) GetCapabilities() []string {
 
 // Workflow Control Tool
 type WorkflowControlTool struct {
-	workflows *WorkflowEngine
+	workflows *CompleteWorkflowEngine
 	logger    Logger
 }
 
-func NewWorkflowControlTool(workflows *WorkflowEngine, logger Logger) *WorkflowControlTool {
+func NewWorkflowControlTool(workflows *CompleteWorkflowEngine, logger Logger) *WorkflowControlTool {
 	return &WorkflowControlTool{
 		workflows: workflows,
 		logger:    logger,
@@ -193,6 +193,12 @@ func (tool *WorkflowControlTool) GetSchema() *MCPToolSchema {
 				Description: "New stage for transition",
 				Required:    false,
 			},
+			{
+				Name:        "reason",
+				Type:        "string",
+				Description: "Reason for transition, stored in the audit log",
+				Required:    false,
+			},
 		},
 		Capabilities: []string{"workflow_management", "state_control", "transition_handling"},
 		Version:      "1.0.0",
@@ -218,15 +224,22 @@ func (tool *WorkflowControlTool) ValidateInput(input *MCPToolInput) error {
 func (tool *WorkflowControlTool) Execute(ctx context.Context, input *MCPToolInput) (*MCPToolOutput, error) {
 	action := input.Parameters["action"].(string)
 
+	if tool.workflows == nil {
+		return &MCPToolOutput{
+			Success: false,
+			Error:   "workflow engine is not configured",
+		}, nil
+	}
+
 	switch action {
 	case "list":
 		return tool.listWorkflows()
 	case "status":
 		return tool.getWorkflowStatus(input)
 	case "transition":
-		return tool.transitionTask(input)
+		return tool.transitionTask(ctx, input)
 	case "start", "stop", "pause", "resume":
-		return tool.controlWorkflow(action, input)
+		return tool.controlWorkflow(ctx, action, input)
 	default:
 		return &MCPToolOutput{
 			Success: false,
@@ -236,21 +249,18 @@ func (tool *WorkflowControlTool) Execute(ctx context.Context, input *MCPToolInpu
 }
 
 func (tool *WorkflowControlTool) listWorkflows() (*MCPToolOutput, error) {
-	// Здесь должна быть логика получения списка workflow
-	// Пока возвращаем мок данные
-	workflows := []map[string]interface{}{
-		{
-			"id":     "wf-1",
-			"name":   "Development Workflow",
-			"status": "active",
-			"tasks":  5,
-		},
-		{
-			"id":     "wf-2", 
-			"name":   "Review Workflow",
-			"status": "paused",
-			"tasks":  3,
-		},
+	instances := tool.workflows.ListWorkflows()
+
+	workflows := make([]map[string]interface{}, 0, len(instances))
+	for _, instance := range instances {
+		workflows = append(workflows, map[string]interface{}{
+			"id":            instance.ID,
+			"name":          instance.Definition.Name,
+			"status":        instance.Status,
+			"current_stage": instance.CurrentStage,
+			"progress":      tool.workflows.WorkflowProgress(instance) * 100,
+			"tasks":         len(instance.Tasks),
+		})
 	}
 
 	return &MCPToolOutput{
@@ -271,26 +281,38 @@ func (tool *WorkflowControlTool) getWorkflowStatus(input *MCPToolInput) (*MCPToo
 		}, nil
 	}
 
-	// Мок статуса workflow
+	instance, err := tool.workflows.GetWorkflowStatus(workflowID)
+	if err != nil {
+		return &MCPToolOutput{
+			Success: false,
+			Error:   err.Error(),
+		}, err
+	}
+
+	tasks := make([]map[string]interface{}, 0, len(instance.Tasks))
+	for _, task := range instance.Tasks {
+		tasks = append(tasks, map[string]interface{}{
+			"id":       task.ID,
+			"status":   task.Status,
+			"stage":    task.Context["stage_name"],
+			"assignee": task.AssignedTo,
+		})
+	}
+
 	status := map[string]interface{}{
-		"id":            workflowID,
-		"status":        "running",
-		"current_stage": "development",
-		"progress":      75.5,
-		"tasks": []map[string]interface{}{
-			{
-				"id":     "task-1",
-				"title":  "Implement feature",
-				"status": "in_progress",
-				"assignee": "user1",
-			},
-			{
-				"id":     "task-2",
-				"title":  "Write tests",
-				"status": "pending",
-				"assignee": "user2",
-			},
-		},
+		"id":            instance.ID,
+		"name":          instance.Definition.Name,
+		"status":        instance.Status,
+		"current_stage": instance.CurrentStage,
+		"progress":      tool.workflows.WorkflowProgress(instance) * 100,
+		"created_at":    instance.CreatedAt,
+		"tasks":         tasks,
+	}
+	if instance.StartedAt != nil {
+		status["started_at"] = *instance.StartedAt
+	}
+	if instance.CompletedAt != nil {
+		status["completed_at"] = *instance.CompletedAt
 	}
 
 	return &MCPToolOutput{
@@ -299,7 +321,7 @@ func (tool *WorkflowControlTool) getWorkflowStatus(input *MCPToolInput) (*MCPToo
 	}, nil
 }
 
-func (tool *WorkflowControlTool) transitionTask(input *MCPToolInput) (*MCPToolOutput, error) {
+func (tool *WorkflowControlTool) transitionTask(ctx context.Context, input *MCPToolInput) (*MCPToolOutput, error) {
 	taskID, ok := input.Parameters["task_id"].(string)
 	if !ok {
 		return &MCPToolOutput{
@@ -318,12 +340,26 @@ func (tool *WorkflowControlTool) transitionTask(input *MCPToolInput) (*MCPToolOu
 
 	tool.logger.Info("Task transition requested", "task_id", taskID, "new_stage", newStage)
 
+	var actor string
+	if input.Context != nil {
+		actor = input.Context.UserID
+	}
+	reason, _ := input.Parameters["reason"].(string)
+
+	oldStage, err := tool.workflows.TransitionTask(ctx, taskID, newStage, actor, reason)
+	if err != nil {
+		return &MCPToolOutput{
+			Success: false,
+			Error:   err.Error(),
+		}, err
+	}
+
 	result := map[string]interface{}{
-		"task_id":     taskID,
-		"old_stage":   "in_progress",
-		"new_stage":   newStage,
+		"task_id":         taskID,
+		"old_stage":       oldStage,
+		"new_stage":       newStage,
 		"transitioned_at": time.Now(),
-		"success":     true,
+		"success":         true,
 	}
 
 	return &MCPToolOutput{
@@ -332,7 +368,7 @@ func (tool *WorkflowControlTool) transitionTask(input *MCPToolInput) (*MCPToolOu
 	}, nil
 }
 
-func (tool *WorkflowControlTool) controlWorkflow(action string, input *MCPToolInput) (*MCPToolOutput, error) {
+func (tool *WorkflowControlTool) controlWorkflow(ctx context.Context, action string, input *MCPToolInput) (*MCPToolOutput, error) {
 	workflowID, ok := input.Parameters["workflow_id"].(string)
 	if !ok {
 		return &MCPToolOutput{
@@ -343,10 +379,29 @@ func (tool *WorkflowControlTool) controlWorkflow(action string, input *MCPToolIn
 
 	tool.logger.Info("Workflow control action", "action", action, "workflow_id", workflowID)
 
+	var instance *WorkflowInstance
+	var err error
+	switch action {
+	case "start":
+		instance, err = tool.workflows.StartWorkflow(ctx, workflowID)
+	case "stop":
+		instance, err = tool.workflows.StopWorkflow(ctx, workflowID)
+	case "pause":
+		instance, err = tool.workflows.PauseWorkflow(ctx, workflowID)
+	case "resume":
+		instance, err = tool.workflows.ResumeWorkflow(ctx, workflowID)
+	}
+	if err != nil {
+		return &MCPToolOutput{
+			Success: false,
+			Error:   err.Error(),
+		}, err
+	}
+
 	result := map[string]interface{}{
 		"workflow_id": workflowID,
 		"action":      action,
-		"status":      "completed",
+		"status":      tool.workflows.instanceStatus(instance),
 		"timestamp":   time.Now(),
 	}
 
@@ -360,6 +415,125 @@ func (tool *WorkflowControlTool) GetCapabilities() []string {
 	return []string{"workflow_management", "state_control", "transition_handling"}
 }
 
+// Workflow History Tool
+type WorkflowHistoryTool struct {
+	workflows *CompleteWorkflowEngine
+	logger    Logger
+}
+
+func NewWorkflowHistoryTool(workflows *CompleteWorkflowEngine, logger Logger) *WorkflowHistoryTool {
+	return &WorkflowHistoryTool{
+		workflows: workflows,
+		logger:    logger,
+	}
+}
+
+func (tool *WorkflowHistoryTool) GetName() string {
+	return "workflow_history"
+}
+
+func (tool *WorkflowHistoryTool) GetDescription() string {
+	return "Returns the audit log of task stage transitions"
+}
+
+func (tool *WorkflowHistoryTool) GetSchema() *MCPToolSchema {
+	return &MCPToolSchema{
+		Name:        "workflow_history",
+		Description: "Task transition history",
+		Parameters: []MCPParameter{
+			{
+				Name:        "task_id",
+				Type:        "string",
+				Description: "Task identifier",
+				Required:    true,
+			},
+			{
+				Name:        "since",
+				Type:        "string",
+				Description: "Start of date range (RFC3339 or YYYY-MM-DD)",
+				Required:    false,
+			},
+			{
+				Name:        "until",
+				Type:        "string",
+				Description: "End of date range (RFC3339 or YYYY-MM-DD)",
+				Required:    false,
+			},
+		},
+		Capabilities: []string{"workflow_management", "audit_log"},
+		Version:      "1.0.0",
+	}
+}
+
+func (tool *WorkflowHistoryTool) ValidateInput(input *MCPToolInput) error {
+	if taskID, ok := input.Parameters["task_id"].(string); !ok || taskID == "" {
+		return fmt.Errorf("task_id parameter is required")
+	}
+	return nil
+}
+
+func (tool *WorkflowHistoryTool) Execute(ctx context.Context, input *MCPToolInput) (*MCPToolOutput, error) {
+	if tool.workflows == nil {
+		return &MCPToolOutput{
+			Success: false,
+			Error:   "workflow engine is not configured",
+		}, nil
+	}
+
+	filter := TransitionFilter{}
+	filter.TaskID, _ = input.Parameters["task_id"].(string)
+
+	var err error
+	if since, ok := input.Parameters["since"].(string); ok && since != "" {
+		if filter.Since, err = ParseHistoryTime(since, false); err != nil {
+			return &MCPToolOutput{Success: false, Error: err.Error()}, nil
+		}
+	}
+	if until, ok := input.Parameters["until"].(string); ok && until != "" {
+		if filter.Until, err = ParseHistoryTime(until, true); err != nil {
+			return &MCPToolOutput{Success: false, Error: err.Error()}, nil
+		}
+	}
+
+	records, err := tool.workflows.GetTransitionHistory(filter)
+	if err != nil {
+		return &MCPToolOutput{
+			Success: false,
+			Error:   err.Error(),
+		}, nil
+	}
+
+	return &MCPToolOutput{
+		Success: true,
+		Result:  records,
+		Metadata: map[string]interface{}{
+			"task_id":     filter.TaskID,
+			"transitions": len(records),
+		},
+	}, nil
+}
+
+func (tool *WorkflowHistoryTool) GetCapabilities() []string {
+	return []string{"workflow_management", "audit_log"}
+}
+
+// ParseHistoryTime разбирает границу диапазона дат в формате RFC3339 или YYYY-MM-DD.
+// Для верхней границы дата без времени означает конец дня.
+func ParseHistoryTime(value string, endOfDay bool) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+
+	t, err := time.ParseInLocation("2006-01-02", value, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q: expected RFC3339 or YYYY-MM-DD", value)
+	}
+	if endOfDay {
+		t = t.Add(24*time.Hour - time.Nanosecond)
+	}
+	return t, nil
+}
+
 // Resource Management Tool
 type ResourceManagementTool struct {
 	resourceManager *MCPResourceManager
@@ -402,8 +576,15 @@ func (tool *ResourceManagementTool) GetSchema() *MCPToolSchema {
 			{
 				Name:        "user_id",
 				Type:        "string",
-				Description: "User ID for access control",
+				Description: "Target user for grant_access; the caller is identified by the execution context",
+				Required:    false,
+			},
+			{
+				Name:        "access_level",
+				Type:        "string",
+				Description: "Access level for create operation",
 				Required:    false,
+				Enum:        []string{MCPAccessPrivate, MCPAccessShared, MCPAccessPublic},
 			},
 			{
 				Name:        "resource_data",
@@ -453,14 +634,30 @@ func (tool *ResourceManagementTool) Execute(ctx context.Context, input *MCPToolI
 	}
 }
 
+// authenticatedUser возвращает пользователя, от имени которого выполняется вызов.
+// Личность берется только из контекста выполнения: параметр user_id задает
+// вызывающий, поэтому он может лишь указывать, кому выдается доступ.
+func (tool *ResourceManagementTool) authenticatedUser(input *MCPToolInput) string {
+	if input.Context == nil {
+		return ""
+	}
+	return input.Context.UserID
+}
+
 func (tool *ResourceManagementTool) listResources(input *MCPToolInput) (*MCPToolOutput, error) {
-	userID, ok := input.Parameters["user_id"].(string)
-	if !ok {
-		userID = "anonymous"
+	userID := tool.authenticatedUser(input)
+	if userID == "" {
+		return &MCPToolOutput{
+			Success: false,
+			Error:   "authenticated user is required for list action",
+		}, nil
 	}
 
 	resources := tool.resourceManager.ListResources(userID)
-	
+	if resources == nil {
+		resources = []string{}
+	}
+
 	return &MCPToolOutput{
 		Success: true,
 		Result: map[string]interface{}{
@@ -480,9 +677,12 @@ func (tool *ResourceManagementTool) readResource(input *MCPToolInput) (*MCPToolO
 		}, nil
 	}
 
-	userID, ok := input.Parameters["user_id"].(string)
-	if !ok {
-		userID = "anonymous"
+	userID := tool.authenticatedUser(input)
+	if userID == "" {
+		return &MCPToolOutput{
+			Success: false,
+			Error:   "authenticated user is required for read action",
+		}, nil
 	}
 
 	resource, err := tool.resourceManager.GetResource(uri, userID)
@@ -516,22 +716,49 @@ func (tool *ResourceManagementTool) createResource(input *MCPToolInput) (*MCPToo
 		}, nil
 	}
 
+	owner := tool.authenticatedUser(input)
+	if owner == "" {
+		return &MCPToolOutput{
+			Success: false,
+			Error:   "authenticated user is required for create action",
+		}, nil
+	}
+
+	accessLevel := MCPAccessPrivate
+	if level, ok := input.Parameters["access_level"].(string); ok && level != "" {
+		if level != MCPAccessPrivate && level != MCPAccessShared && level != MCPAccessPublic {
+			return &MCPToolOutput{
+				Success: false,
+				Error:   fmt.Sprintf("invalid access_level: %s", level),
+			}, nil
+		}
+		accessLevel = level
+	}
+
 	resource := &MCPResource{
 		URI:         uri,
 		Type:        "data",
 		Content:     resourceData,
 		Metadata:    make(map[string]interface{}),
-		AccessLevel: "private",
+		AccessLevel: accessLevel,
+		Owner:       owner,
 	}
 
-	tool.resourceManager.AddResource(uri, resource)
+	if err := tool.resourceManager.CreateResource(uri, resource); err != nil {
+		return &MCPToolOutput{
+			Success: false,
+			Error:   err.Error(),
+		}, nil
+	}
 
 	return &MCPToolOutput{
 		Success: true,
 		Result: map[string]interface{}{
-			"uri":        uri,
-			"created_at": time.Now(),
-			"status":     "created",
+			"uri":          uri,
+			"owner":        owner,
+			"access_level": accessLevel,
+			"created_at":   time.Now(),
+			"status":       "created",
 		},
 	}, nil
 }
@@ -553,13 +780,22 @@ func (tool *ResourceManagementTool) grantAccess(input *MCPToolInput) (*MCPToolOu
 		}, nil
 	}
 
-	tool.resourceManager.GrantAccess(uri, userID)
+	// Выдавать доступ может только владелец, идентифицированный контекстом выполнения
+	grantorID := tool.authenticatedUser(input)
+
+	if err := tool.resourceManager.GrantAccessBy(uri, grantorID, userID); err != nil {
+		return &MCPToolOutput{
+			Success: false,
+			Error:   err.Error(),
+		}, nil
+	}
 
 	return &MCPToolOutput{
 		Success: true,
 		Result: map[string]interface{}{
 			"uri":        uri,
 			"user_id":    userID,
+			"granted_by": grantorID,
 			"granted_at": time.Now(),
 			"status":     "access_granted",
 		},
@@ -21,6 +21,8 @@ type NotificationMetrics struct {
 	TotalSent      int64                 `json:"total_sent"`
 	TotalDelivered int64                 `json:"total_delivered"`
 	TotalFailed    int64                 `json:"total_failed"`
+	TotalRetries   int64                 `json:"total_retries"`
+	RetryDelivered int64                 `json:"retry_delivered"`
 	ByChannel      map[string]int64      `json:"by_channel"`
 	ByPriority     map[string]int64      `json:"by_priority"`
 	ByType         map[string]int64      `json:"by_type"`
@@ -122,6 +124,9 @@ func (na *NotificationAnalytics) RecordNotification(notification *SmartNotificat
 		PersonalizedAI: notification.AIAnalysis != nil,
 		Metadata:       make(map[string]interface{}),
 	}
+	if len(notification.DeliveredVia) > 0 {
+		record.Metadata["delivered_via"] = notification.DeliveredVia
+	}
 	
 	// Записываем по каналам
 	for _, channel := range notification.OptimalChannels {
@@ -171,6 +176,38 @@ func (na *NotificationAnalytics) RecordDelivery(notificationID, channel string,
 	}
 }
 
+// RecordRetry записывает повтор доставки по каналу; retry - номер повтора
+func (na *NotificationAnalytics) RecordRetry(notificationID, channel string, retry int, success bool) {
+	na.mutex.Lock()
+	defer na.mutex.Unlock()
+
+	na.metrics.TotalRetries++
+	if success {
+		na.metrics.RetryDelivered++
+	}
+
+	for _, record := range na.history {
+		if record.ID == notificationID && record.Channel == channel {
+			if record.Metadata == nil {
+				record.Metadata = make(map[string]interface{})
+			}
+			record.Metadata["retries"] = retry
+			if success && record.Failed {
+				record.Failed = false
+				na.metrics.TotalFailed--
+				na.metrics.TotalDelivered++
+			}
+			break
+		}
+	}
+	na.metrics.LastUpdated = time.Now()
+
+	na.logger.Debug("Notification retry recorded",
+		"id", notificationID,
+		"channel", channel,
+		"success", success)
+}
+
 // RecordOpen записывает открытие уведомления
 func (na *NotificationAnalytics) RecordOpen(notificationID, channel string) {
 	na.mutex.Lock()
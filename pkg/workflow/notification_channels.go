@@ -3,13 +3,105 @@ package workflow
 import (
 	"bytes"
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"html"
+	"io"
+	"mime"
+	"net"
 	"net/http"
 	"net/smtp"
+	"net/textproto"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
 	"time"
 )
 
+// ErrChannelNotConfigured канал не настроен и не может доставить уведомление
+var ErrChannelNotConfigured = errors.New("notification channel is not configured")
+
+// notificationHTTPTimeout таймаут HTTP доставки уведомлений
+const notificationHTTPTimeout = 10 * time.Second
+
+// slackIDPattern ID канала или пользователя Slack (C0123ABCD, U0123ABCD)
+var slackIDPattern = regexp.MustCompile(`^[CGDU][A-Z0-9]{8,}$`)
+
+// permanentSlackErrors ошибки Slack API из-за получателя, которые повтор не исправит
+var permanentSlackErrors = map[string]bool{
+	"channel_not_found": true,
+	"not_in_channel":    true,
+	"is_archived":       true,
+	"user_not_found":    true,
+	"cannot_dm_bot":     true,
+}
+
+// personalizedContentOf возвращает персонализированный контент, добавленный prepareForChannel
+func personalizedContentOf(notification *Notification) *PersonalizedContent {
+	if notification.Data == nil {
+		return nil
+	}
+	content, _ := notification.Data["personalized_content"].(*PersonalizedContent)
+	return content
+}
+
+// postJSON отправляет JSON и возвращает ошибку для не-2xx ответов
+func postJSON(ctx context.Context, client *http.Client, url string, payload interface{}, headers map[string]string) ([]byte, error) {
+	jsonData, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return body, statusError(resp.StatusCode, fmt.Errorf("status %d: %s", resp.StatusCode, strings.TrimSpace(string(body))))
+	}
+
+	return body, nil
+}
+
+// EmailChannelConfig настройки SMTP
+type EmailChannelConfig struct {
+	Host     string `json:"host"`
+	Port     string `json:"port"`
+	Username string `json:"username"`
+	Password string `json:"password"`
+	From     string `json:"from"`
+}
+
+// EmailChannelConfigFromEnv читает настройки SMTP из RICOCHET_SMTP_* переменных
+func EmailChannelConfigFromEnv() *EmailChannelConfig {
+	return &EmailChannelConfig{
+		Host:     os.Getenv("RICOCHET_SMTP_HOST"),
+		Port:     os.Getenv("RICOCHET_SMTP_PORT"),
+		Username: os.Getenv("RICOCHET_SMTP_USERNAME"),
+		Password: os.Getenv("RICOCHET_SMTP_PASSWORD"),
+		From:     os.Getenv("RICOCHET_SMTP_FROM"),
+	}
+}
+
 // EmailChannel канал email уведомлений
 type EmailChannel struct {
 	smtpHost     string
@@ -20,126 +112,330 @@ type EmailChannel struct {
 	logger       Logger
 }
 
-// NewEmailChannel создает новый email канал
+// NewEmailChannel создает email канал с настройками из окружения
 func NewEmailChannel(logger Logger) *EmailChannel {
-	return &EmailChannel{
-		smtpHost:     "smtp.gmail.com", // Конфигурировать из настроек
-		smtpPort:     "587",
-		smtpUsername: "",
-		smtpPassword: "",
-		fromEmail:    "notifications@ricochet-task.com",
-		logger:       logger,
+	return NewEmailChannelWithConfig(EmailChannelConfigFromEnv(), logger)
+}
+
+// NewEmailChannelWithConfig создает email канал с явными настройками SMTP
+func NewEmailChannelWithConfig(config *EmailChannelConfig, logger Logger) *EmailChannel {
+	channel := &EmailChannel{
+		smtpPort:  "587",
+		fromEmail: "notifications@ricochet-task.com",
+		logger:    logger,
+	}
+	if config == nil {
+		return channel
+	}
+
+	channel.smtpHost = config.Host
+	channel.smtpUsername = config.Username
+	channel.smtpPassword = config.Password
+	if config.Port != "" {
+		channel.smtpPort = config.Port
+	}
+	if config.From != "" {
+		channel.fromEmail = config.From
 	}
+	return channel
 }
 
 func (ec *EmailChannel) GetType() string {
 	return "email"
 }
 
+// Configured сообщает, задан ли SMTP сервер
+func (ec *EmailChannel) Configured() bool {
+	return ec.smtpHost != ""
+}
+
 func (ec *EmailChannel) Send(ctx context.Context, notification *Notification) error {
+	if ec.smtpHost == "" {
+		return fmt.Errorf("email: %w (set SMTP host)", ErrChannelNotConfigured)
+	}
 	if len(notification.Recipients) == 0 {
-		return fmt.Errorf("no recipients specified")
+		return NewPermanentDeliveryError(fmt.Errorf("no recipients specified"))
 	}
-	
+
 	// Формируем email
 	subject := notification.Title
 	body := ec.formatEmailBody(notification)
-	
-	// Отправляем каждому получателю
+
+	// Отправляем каждому получателю отдельно, чтобы не раскрывать адреса
 	for _, recipient := range notification.Recipients {
-		if err := ec.sendEmail(recipient, subject, body); err != nil {
+		if err := ec.sendEmail(ctx, recipient, subject, body); err != nil {
 			ec.logger.Error("Failed to send email", err, "recipient", recipient)
-			return err
+			return fmt.Errorf("email to %s: %w", recipient, err)
 		}
 	}
-	
-	ec.logger.Info("Email notification sent", 
+
+	ec.logger.Info("Email notification sent",
 		"recipients", len(notification.Recipients),
 		"notification_id", notification.ID)
-	
+
 	return nil
 }
 
-func (ec *EmailChannel) sendEmail(to, subject, body string) error {
-	// Простая реализация отправки email
-	if ec.smtpUsername == "" {
-		// В тестовом режиме просто логируем
-		ec.logger.Info("Email would be sent", "to", to, "subject", subject)
-		return nil
+func (ec *EmailChannel) sendEmail(ctx context.Context, to, subject, body string) error {
+	addr := net.JoinHostPort(ec.smtpHost, ec.smtpPort)
+
+	dialer := &net.Dialer{Timeout: notificationHTTPTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to connect to SMTP server: %w", err)
 	}
-	
-	// Реальная отправка через SMTP
-	auth := smtp.PlainAuth("", ec.smtpUsername, ec.smtpPassword, ec.smtpHost)
-	
-	msg := []byte("To: " + to + "\r\n" +
-		"Subject: " + subject + "\r\n" +
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	} else {
+		conn.SetDeadline(time.Now().Add(30 * time.Second))
+	}
+
+	client, err := smtp.NewClient(conn, ec.smtpHost)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to start SMTP session: %w", err)
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: ec.smtpHost}); err != nil {
+			return fmt.Errorf("STARTTLS failed: %w", err)
+		}
+	}
+
+	if ec.smtpUsername != "" {
+		auth := smtp.PlainAuth("", ec.smtpUsername, ec.smtpPassword, ec.smtpHost)
+		if err := client.Auth(auth); err != nil {
+			return fmt.Errorf("SMTP authentication failed: %w", err)
+		}
+	}
+
+	if err := client.Mail(ec.fromEmail); err != nil {
+		return fmt.Errorf("MAIL FROM rejected: %w", err)
+	}
+	if err := client.Rcpt(to); err != nil {
+		// 5xx - сервер не примет письмо для этого адреса и при повторе
+		var smtpErr *textproto.Error
+		if errors.As(err, &smtpErr) && smtpErr.Code >= 500 {
+			return NewPermanentDeliveryError(fmt.Errorf("RCPT TO rejected: %w", err))
+		}
+		return fmt.Errorf("RCPT TO rejected: %w", err)
+	}
+
+	writer, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("DATA rejected: %w", err)
+	}
+
+	msg := "From: " + ec.fromEmail + "\r\n" +
+		"To: " + to + "\r\n" +
+		"Subject: " + mime.QEncoding.Encode("utf-8", subject) + "\r\n" +
+		"Date: " + time.Now().Format(time.RFC1123Z) + "\r\n" +
 		"MIME-version: 1.0\r\n" +
 		"Content-Type: text/html; charset=\"UTF-8\"\r\n" +
-		"\r\n" + body)
-	
-	err := smtp.SendMail(ec.smtpHost+":"+ec.smtpPort, auth, ec.fromEmail, []string{to}, msg)
-	return err
+		"\r\n" + body
+	if _, err := writer.Write([]byte(msg)); err != nil {
+		writer.Close()
+		return fmt.Errorf("failed to write message: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("message rejected: %w", err)
+	}
+
+	return client.Quit()
 }
 
 func (ec *EmailChannel) formatEmailBody(notification *Notification) string {
+	var extra strings.Builder
+	if content := personalizedContentOf(notification); content != nil {
+		if content.Summary != "" {
+			fmt.Fprintf(&extra, "<p><strong>%s</strong></p>\n", html.EscapeString(content.Summary))
+		}
+		if len(content.ActionItems) > 0 {
+			extra.WriteString("<ul>\n")
+			for _, item := range content.ActionItems {
+				fmt.Fprintf(&extra, "<li>%s</li>\n", html.EscapeString(item))
+			}
+			extra.WriteString("</ul>\n")
+		}
+	}
+
 	return fmt.Sprintf(`
 <html>
 <body>
 <h2>%s</h2>
 <p>%s</p>
-<hr>
+%s<hr>
 <p><small>Sent at: %s</small></p>
 <p><small>Notification ID: %s</small></p>
 </body>
 </html>
-`, notification.Title, notification.Message, notification.Timestamp.Format(time.RFC3339), notification.ID)
+`, html.EscapeString(notification.Title), html.EscapeString(notification.Message), extra.String(),
+		notification.Timestamp.Format(time.RFC3339), notification.ID)
+}
+
+// SlackChannelConfig настройки Slack: incoming webhook или bot token
+type SlackChannelConfig struct {
+	WebhookURL     string `json:"webhook_url"`
+	BotToken       string `json:"bot_token"`
+	DefaultChannel string `json:"default_channel"`
+	APIURL         string `json:"api_url,omitempty"` // для тестов, по умолчанию https://slack.com/api
+}
+
+// SlackChannelConfigFromEnv читает настройки Slack из RICOCHET_SLACK_* переменных
+func SlackChannelConfigFromEnv() *SlackChannelConfig {
+	return &SlackChannelConfig{
+		WebhookURL:     os.Getenv("RICOCHET_SLACK_WEBHOOK_URL"),
+		BotToken:       os.Getenv("RICOCHET_SLACK_BOT_TOKEN"),
+		DefaultChannel: os.Getenv("RICOCHET_SLACK_CHANNEL"),
+	}
 }
 
 // SlackChannel канал Slack уведомлений
 type SlackChannel struct {
-	webhookURL string
-	botToken   string
-	logger     Logger
+	webhookURL     string
+	botToken       string
+	defaultChannel string
+	apiURL         string
+	client         *http.Client
+	logger         Logger
 }
 
-// NewSlackChannel создает новый Slack канал
+// NewSlackChannel создает Slack канал с настройками из окружения
 func NewSlackChannel(logger Logger) *SlackChannel {
-	return &SlackChannel{
-		webhookURL: "", // Конфигурировать из настроек
-		botToken:   "", // Конфигурировать из настроек
-		logger:     logger,
+	return NewSlackChannelWithConfig(SlackChannelConfigFromEnv(), logger)
+}
+
+// NewSlackChannelWithConfig создает Slack канал с явными настройками
+func NewSlackChannelWithConfig(config *SlackChannelConfig, logger Logger) *SlackChannel {
+	channel := &SlackChannel{
+		apiURL: "https://slack.com/api",
+		client: &http.Client{Timeout: notificationHTTPTimeout},
+		logger: logger,
+	}
+	if config == nil {
+		return channel
+	}
+
+	channel.webhookURL = config.WebhookURL
+	channel.botToken = config.BotToken
+	channel.defaultChannel = config.DefaultChannel
+	if config.APIURL != "" {
+		channel.apiURL = strings.TrimSuffix(config.APIURL, "/")
 	}
+	return channel
 }
 
 func (sc *SlackChannel) GetType() string {
 	return "slack"
 }
 
+// Configured сообщает, задан ли webhook или токен бота
+func (sc *SlackChannel) Configured() bool {
+	return sc.webhookURL != "" || sc.botToken != ""
+}
+
 func (sc *SlackChannel) Send(ctx context.Context, notification *Notification) error {
-	if sc.webhookURL == "" {
-		// В тестовом режиме просто логируем
-		sc.logger.Info("Slack notification would be sent", 
-			"notification_id", notification.ID,
-			"title", notification.Title)
-		return nil
-	}
-	
 	// Формируем Slack сообщение
 	slackMsg := sc.formatSlackMessage(notification)
-	
-	// Отправляем через webhook
-	return sc.sendSlackWebhook(slackMsg)
+
+	switch {
+	case sc.webhookURL != "":
+		// Incoming webhook привязан к одному каналу
+		if _, err := postJSON(ctx, sc.client, sc.webhookURL, slackMsg, nil); err != nil {
+			return fmt.Errorf("slack webhook: %w", err)
+		}
+	case sc.botToken != "":
+		channels := sc.targetChannels(notification)
+		if len(channels) == 0 {
+			return NewPermanentDeliveryError(fmt.Errorf("slack: no target channel for bot token delivery"))
+		}
+		for _, channel := range channels {
+			if err := sc.postMessage(ctx, channel, slackMsg); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("slack: %w (set webhook URL or bot token)", ErrChannelNotConfigured)
+	}
+
+	sc.logger.Info("Slack notification sent", "notification_id", notification.ID)
+	return nil
+}
+
+// targetChannels определяет каналы для chat.postMessage
+func (sc *SlackChannel) targetChannels(notification *Notification) []string {
+	if channel, ok := notification.Data["slack_channel"].(string); ok && channel != "" {
+		return []string{channel}
+	}
+
+	var channels []string
+	for _, recipient := range notification.Recipients {
+		// Каналы (#name) и Slack ID принимаются как есть
+		if strings.HasPrefix(recipient, "#") || slackIDPattern.MatchString(recipient) {
+			channels = append(channels, recipient)
+		}
+	}
+	if len(channels) == 0 && sc.defaultChannel != "" {
+		channels = append(channels, sc.defaultChannel)
+	}
+	return channels
+}
+
+func (sc *SlackChannel) postMessage(ctx context.Context, channel string, message map[string]interface{}) error {
+	payload := make(map[string]interface{}, len(message)+1)
+	for key, value := range message {
+		payload[key] = value
+	}
+	payload["channel"] = channel
+
+	body, err := postJSON(ctx, sc.client, sc.apiURL+"/chat.postMessage", payload, map[string]string{
+		"Authorization": "Bearer " + sc.botToken,
+	})
+	if err != nil {
+		return fmt.Errorf("slack chat.postMessage: %w", err)
+	}
+
+	// Slack API возвращает 200 даже при ошибках, результат в поле ok
+	var result struct {
+		OK    bool   `json:"ok"`
+		Error string `json:"error"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return fmt.Errorf("slack chat.postMessage: invalid response: %w", err)
+	}
+	if !result.OK {
+		err := fmt.Errorf("slack chat.postMessage to %s: %s", channel, result.Error)
+		if permanentSlackErrors[result.Error] {
+			return NewPermanentDeliveryError(err)
+		}
+		return err
+	}
+	return nil
 }
 
 func (sc *SlackChannel) formatSlackMessage(notification *Notification) map[string]interface{} {
 	color := sc.getColorByPriority(notification.Priority)
-	
+
+	text := notification.Message
+	if content := personalizedContentOf(notification); content != nil {
+		if content.Summary != "" {
+			text = "*" + content.Summary + "*\n" + text
+		}
+		if len(content.ActionItems) > 0 {
+			text += "\n"
+			for _, item := range content.ActionItems {
+				text += "\n• " + item
+			}
+		}
+	}
+
 	attachment := map[string]interface{}{
-		"color":    color,
-		"title":    notification.Title,
-		"text":     notification.Message,
-		"ts":       notification.Timestamp.Unix(),
-		"footer":   "Ricochet Task",
+		"color":  color,
+		"title":  notification.Title,
+		"text":   text,
+		"ts":     notification.Timestamp.Unix(),
+		"footer": "Ricochet Task",
 		"fields": []map[string]interface{}{
 			{
 				"title": "Priority",
@@ -153,8 +449,9 @@ func (sc *SlackChannel) formatSlackMessage(notification *Notification) map[strin
 			},
 		},
 	}
-	
+
 	return map[string]interface{}{
+		"text":        notification.Title,
 		"attachments": []interface{}{attachment},
 	}
 }
@@ -170,35 +467,23 @@ func (sc *SlackChannel) getColorByPriority(priority string) string {
 	}
 }
 
-func (sc *SlackChannel) sendSlackWebhook(message map[string]interface{}) error {
-	jsonData, err := json.Marshal(message)
-	if err != nil {
-		return err
-	}
-	
-	resp, err := http.Post(sc.webhookURL, "application/json", bytes.NewBuffer(jsonData))
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-	
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("slack webhook returned status %d", resp.StatusCode)
-	}
-	
-	return nil
-}
-
 // TeamsChannel канал Microsoft Teams уведомлений
 type TeamsChannel struct {
 	webhookURL string
+	client     *http.Client
 	logger     Logger
 }
 
-// NewTeamsChannel создает новый Teams канал
+// NewTeamsChannel создает Teams канал с webhook из RICOCHET_TEAMS_WEBHOOK_URL
 func NewTeamsChannel(logger Logger) *TeamsChannel {
+	return NewTeamsChannelWithWebhook(os.Getenv("RICOCHET_TEAMS_WEBHOOK_URL"), logger)
+}
+
+// NewTeamsChannelWithWebhook создает Teams канал для connector webhook
+func NewTeamsChannelWithWebhook(webhookURL string, logger Logger) *TeamsChannel {
 	return &TeamsChannel{
-		webhookURL: "", // Конфигурировать из настроек
+		webhookURL: webhookURL,
+		client:     &http.Client{Timeout: notificationHTTPTimeout},
 		logger:     logger,
 	}
 }
@@ -207,47 +492,68 @@ func (tc *TeamsChannel) GetType() string {
 	return "teams"
 }
 
+// Configured сообщает, задан ли connector webhook
+func (tc *TeamsChannel) Configured() bool {
+	return tc.webhookURL != ""
+}
+
 func (tc *TeamsChannel) Send(ctx context.Context, notification *Notification) error {
 	if tc.webhookURL == "" {
-		// В тестовом режиме просто логируем
-		tc.logger.Info("Teams notification would be sent", 
-			"notification_id", notification.ID,
-			"title", notification.Title)
-		return nil
+		return fmt.Errorf("teams: %w (set connector webhook URL)", ErrChannelNotConfigured)
 	}
-	
+
 	// Формируем Teams сообщение
 	teamsMsg := tc.formatTeamsMessage(notification)
-	
+
 	// Отправляем через webhook
-	return tc.sendTeamsWebhook(teamsMsg)
+	if _, err := postJSON(ctx, tc.client, tc.webhookURL, teamsMsg, nil); err != nil {
+		return fmt.Errorf("teams webhook: %w", err)
+	}
+
+	tc.logger.Info("Teams notification sent", "notification_id", notification.ID)
+	return nil
 }
 
 func (tc *TeamsChannel) formatTeamsMessage(notification *Notification) map[string]interface{} {
 	themeColor := tc.getThemeColorByPriority(notification.Priority)
-	
+
+	sections := []map[string]interface{}{
+		{
+			"activityTitle":    notification.Title,
+			"activitySubtitle": notification.Type,
+			"text":             notification.Message,
+			"facts": []map[string]interface{}{
+				{
+					"name":  "Priority",
+					"value": notification.Priority,
+				},
+				{
+					"name":  "Time",
+					"value": notification.Timestamp.Format("2006-01-02 15:04:05"),
+				},
+			},
+		},
+	}
+
+	summary := notification.Title
+	if content := personalizedContentOf(notification); content != nil {
+		if content.Summary != "" {
+			summary = content.Summary
+		}
+		if len(content.ActionItems) > 0 {
+			sections = append(sections, map[string]interface{}{
+				"title": "Action items",
+				"text":  "- " + strings.Join(content.ActionItems, "\n- "),
+			})
+		}
+	}
+
 	return map[string]interface{}{
 		"@type":      "MessageCard",
 		"@context":   "http://schema.org/extensions",
 		"themeColor": themeColor,
-		"summary":    notification.Title,
-		"sections": []map[string]interface{}{
-			{
-				"activityTitle":    notification.Title,
-				"activitySubtitle": notification.Type,
-				"text":             notification.Message,
-				"facts": []map[string]interface{}{
-					{
-						"name":  "Priority",
-						"value": notification.Priority,
-					},
-					{
-						"name":  "Time",
-						"value": notification.Timestamp.Format("2006-01-02 15:04:05"),
-					},
-				},
-			},
-		},
+		"summary":    summary,
+		"sections":   sections,
 	}
 }
 
@@ -262,36 +568,184 @@ func (tc *TeamsChannel) getThemeColorByPriority(priority string) string {
 	}
 }
 
-func (tc *TeamsChannel) sendTeamsWebhook(message map[string]interface{}) error {
-	jsonData, err := json.Marshal(message)
-	if err != nil {
-		return err
+// WebhookChannelConfig настройки webhook канала
+type WebhookChannelConfig struct {
+	URL             string            `json:"url" yaml:"url"`
+	PayloadTemplate string            `json:"payload_template" yaml:"payload_template"` // Go template над полями SmartNotification
+	ContentType     string            `json:"content_type" yaml:"content_type"`
+	Headers         map[string]string `json:"headers" yaml:"headers"`
+	Secret          string            `json:"secret" yaml:"secret"` // ключ HMAC-SHA256 подписи тела запроса
+	SignatureHeader string            `json:"signature_header" yaml:"signature_header"`
+	MaxRetries      int               `json:"max_retries" yaml:"max_retries"`
+	RetryBackoff    time.Duration     `json:"retry_backoff" yaml:"retry_backoff"`
+}
+
+// DefaultWebhookMaxRetries - сколько раз webhook из окружения повторяет
+// запрос после временной ошибки (сеть, 5xx)
+const DefaultWebhookMaxRetries = 3
+
+// WebhookChannelConfigFromEnv читает настройки из RICOCHET_WEBHOOK_* переменных.
+// RICOCHET_WEBHOOK_TEMPLATE_FILE указывает на файл с шаблоном payload,
+// RICOCHET_WEBHOOK_HEADERS задает заголовки в виде "Name=value,Other=value",
+// RICOCHET_WEBHOOK_MAX_RETRIES - число повторов (по умолчанию
+// DefaultWebhookMaxRetries, 0 отключает повторы), RICOCHET_WEBHOOK_RETRY_BACKOFF -
+// паузу перед первым повтором, например "2s"; каждая следующая пауза вдвое длиннее.
+func WebhookChannelConfigFromEnv() (*WebhookChannelConfig, error) {
+	config := &WebhookChannelConfig{
+		URL:        os.Getenv("RICOCHET_WEBHOOK_URL"),
+		Secret:     os.Getenv("RICOCHET_WEBHOOK_SECRET"),
+		MaxRetries: DefaultWebhookMaxRetries,
 	}
-	
-	resp, err := http.Post(tc.webhookURL, "application/json", bytes.NewBuffer(jsonData))
-	if err != nil {
-		return err
+
+	if value := os.Getenv("RICOCHET_WEBHOOK_MAX_RETRIES"); value != "" {
+		retries, err := strconv.Atoi(value)
+		if err != nil || retries < 0 {
+			return nil, fmt.Errorf("invalid RICOCHET_WEBHOOK_MAX_RETRIES %q: expected a non-negative number", value)
+		}
+		config.MaxRetries = retries
 	}
-	defer resp.Body.Close()
-	
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("teams webhook returned status %d", resp.StatusCode)
+
+	if value := os.Getenv("RICOCHET_WEBHOOK_RETRY_BACKOFF"); value != "" {
+		backoff, err := time.ParseDuration(value)
+		if err != nil || backoff <= 0 {
+			return nil, fmt.Errorf("invalid RICOCHET_WEBHOOK_RETRY_BACKOFF %q: expected a positive duration like 2s", value)
+		}
+		config.RetryBackoff = backoff
 	}
-	
-	return nil
+
+	if path := os.Getenv("RICOCHET_WEBHOOK_TEMPLATE_FILE"); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read webhook template: %w", err)
+		}
+		config.PayloadTemplate = string(data)
+	}
+
+	if headers := os.Getenv("RICOCHET_WEBHOOK_HEADERS"); headers != "" {
+		config.Headers = make(map[string]string)
+		for _, pair := range strings.Split(headers, ",") {
+			name, value, ok := strings.Cut(pair, "=")
+			if !ok {
+				return nil, fmt.Errorf("invalid webhook header %q: expected Name=value", pair)
+			}
+			config.Headers[strings.TrimSpace(name)] = strings.TrimSpace(value)
+		}
+	}
+
+	return config, nil
+}
+
+// webhookTemplateFuncs функции, доступные в шаблоне payload
+var webhookTemplateFuncs = template.FuncMap{
+	"json": func(v interface{}) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+	"upper":      strings.ToUpper,
+	"lower":      strings.ToLower,
+	"join":       strings.Join,
+	"truncate":   truncate,
+	"formatTime": formatTime,
 }
 
 // WebhookChannel универсальный webhook канал
 type WebhookChannel struct {
-	defaultURL string
-	logger     Logger
+	defaultURL      string
+	payload         *template.Template
+	contentType     string
+	headers         map[string]string
+	secret          string
+	signatureHeader string
+	maxRetries      int
+	retryBackoff    time.Duration
+	client          *http.Client
+	configErr       error
+	logger          Logger
 }
 
-// NewWebhookChannel создает новый webhook канал
+// NewWebhookChannel создает webhook канал с настройками из окружения
 func NewWebhookChannel(logger Logger) *WebhookChannel {
-	return &WebhookChannel{
-		defaultURL: "", // Конфигурировать из настроек
-		logger:     logger,
+	config, err := WebhookChannelConfigFromEnv()
+	if err == nil {
+		var channel *WebhookChannel
+		if channel, err = NewWebhookChannelWithConfig(config, logger); err == nil {
+			return channel
+		}
+	}
+
+	// Некорректная конфигурация не должна молча превращаться в "успешную" отправку
+	logger.Error("Invalid webhook channel configuration", err)
+	channel, _ := NewWebhookChannelWithConfig(nil, logger)
+	channel.configErr = err
+	return channel
+}
+
+// NewWebhookChannelWithConfig создает webhook канал и проверяет шаблон payload
+func NewWebhookChannelWithConfig(config *WebhookChannelConfig, logger Logger) (*WebhookChannel, error) {
+	channel := &WebhookChannel{
+		contentType:     "application/json",
+		signatureHeader: "X-Ricochet-Signature",
+		retryBackoff:    time.Second,
+		client:          &http.Client{Timeout: notificationHTTPTimeout},
+		logger:          logger,
+	}
+	if config == nil {
+		return channel, nil
+	}
+
+	channel.defaultURL = config.URL
+	channel.headers = config.Headers
+	channel.secret = config.Secret
+	channel.maxRetries = config.MaxRetries
+	if config.ContentType != "" {
+		channel.contentType = config.ContentType
+	}
+	if config.SignatureHeader != "" {
+		channel.signatureHeader = config.SignatureHeader
+	}
+	if config.RetryBackoff > 0 {
+		channel.retryBackoff = config.RetryBackoff
+	}
+	if channel.maxRetries < 0 {
+		return nil, fmt.Errorf("max_retries must not be negative")
+	}
+
+	if config.PayloadTemplate != "" {
+		tmpl, err := template.New("webhook_payload").Funcs(webhookTemplateFuncs).Parse(config.PayloadTemplate)
+		if err != nil {
+			return nil, fmt.Errorf("invalid webhook payload template: %w", err)
+		}
+
+		// Пробный рендер ловит обращения к несуществующим полям
+		if err := tmpl.Execute(io.Discard, sampleSmartNotification()); err != nil {
+			return nil, fmt.Errorf("invalid webhook payload template: %w", err)
+		}
+		channel.payload = tmpl
+	}
+
+	return channel, nil
+}
+
+// sampleSmartNotification уведомление с заполненными вложенными структурами для проверки шаблонов
+func sampleSmartNotification() *SmartNotification {
+	return &SmartNotification{
+		Notification: &Notification{
+			ID:         "sample",
+			Type:       "task.failed",
+			Title:      "Sample",
+			Priority:   "critical",
+			Recipients: []string{"user"},
+			Data:       map[string]interface{}{},
+			Timestamp:  time.Now(),
+		},
+		Priority:            "critical",
+		Urgency:             "high",
+		PersonalizedContent: &PersonalizedContent{Context: map[string]string{}},
+		OptimalTiming:       &OptimalTiming{DeliverAt: time.Now()},
+		AIAnalysis:          &AINotificationAnalysis{Insights: map[string]interface{}{}},
+		Context: &NotificationContext{
+			TimeContext: &TimeContext{CurrentTime: time.Now()},
+		},
 	}
 }
 
@@ -299,20 +753,29 @@ func (wc *WebhookChannel) GetType() string {
 	return "webhook"
 }
 
+// Configured сообщает, задан ли корректный URL по умолчанию
+func (wc *WebhookChannel) Configured() bool {
+	return wc.configErr == nil && wc.defaultURL != ""
+}
+
 func (wc *WebhookChannel) Send(ctx context.Context, notification *Notification) error {
+	if wc.configErr != nil {
+		return fmt.Errorf("webhook: %w", wc.configErr)
+	}
+
 	webhookURL := wc.getWebhookURL(notification)
 	if webhookURL == "" {
-		wc.logger.Info("Webhook notification would be sent", 
-			"notification_id", notification.ID,
-			"title", notification.Title)
-		return nil
+		return fmt.Errorf("webhook: %w (set webhook URL)", ErrChannelNotConfigured)
 	}
 	
 	// Формируем webhook payload
-	payload := wc.formatWebhookPayload(notification)
+	body, err := wc.renderPayload(notification)
+	if err != nil {
+		return fmt.Errorf("webhook: %w", err)
+	}
 	
 	// Отправляем
-	return wc.sendWebhook(webhookURL, payload)
+	return wc.sendWebhook(ctx, webhookURL, body)
 }
 
 func (wc *WebhookChannel) getWebhookURL(notification *Notification) string {
@@ -323,7 +786,39 @@ func (wc *WebhookChannel) getWebhookURL(notification *Notification) string {
 	return wc.defaultURL
 }
 
+// renderPayload рендерит тело запроса: шаблон, если задан, иначе стандартный JSON
+func (wc *WebhookChannel) renderPayload(notification *Notification) ([]byte, error) {
+	if wc.payload == nil {
+		return json.Marshal(wc.formatWebhookPayload(notification))
+	}
+
+	smart, ok := notification.Data["smart_notification"].(*SmartNotification)
+	if !ok {
+		smart = &SmartNotification{Notification: notification, Priority: notification.Priority}
+	} else {
+		// Заголовок и текст уже адаптированы под канал
+		adapted := *smart
+		adapted.Notification = notification
+		smart = &adapted
+	}
+
+	var buf bytes.Buffer
+	if err := wc.payload.Execute(&buf, smart); err != nil {
+		return nil, fmt.Errorf("failed to render payload template: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
 func (wc *WebhookChannel) formatWebhookPayload(notification *Notification) map[string]interface{} {
+	// Служебные ключи prepareForChannel не отправляем наружу
+	data := make(map[string]interface{}, len(notification.Data))
+	for key, value := range notification.Data {
+		if key == "smart_notification" || key == "personalized_content" {
+			continue
+		}
+		data[key] = value
+	}
+
 	return map[string]interface{}{
 		"id":         notification.ID,
 		"type":       notification.Type,
@@ -331,38 +826,78 @@ func (wc *WebhookChannel) formatWebhookPayload(notification *Notification) map[s
 		"message":    notification.Message,
 		"priority":   notification.Priority,
 		"recipients": notification.Recipients,
-		"data":       notification.Data,
+		"data":       data,
 		"timestamp":  notification.Timestamp.Unix(),
 		"source":     "ricochet-task",
 	}
 }
 
-func (wc *WebhookChannel) sendWebhook(url string, payload map[string]interface{}) error {
-	jsonData, err := json.Marshal(payload)
-	if err != nil {
-		return err
+// sign возвращает HMAC-SHA256 подпись тела в формате sha256=<hex>
+func (wc *WebhookChannel) sign(body []byte) string {
+	mac := hmac.New(sha256.New, []byte(wc.secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// sendWebhook отправляет запрос, повторяя попытки при 5xx и сетевых ошибках
+func (wc *WebhookChannel) sendWebhook(ctx context.Context, url string, body []byte) error {
+	var lastErr error
+	for attempt := 0; attempt <= wc.maxRetries; attempt++ {
+		if attempt > 0 {
+			backoff := wc.retryBackoff * time.Duration(1<<(attempt-1))
+			select {
+			case <-ctx.Done():
+				return fmt.Errorf("webhook: %w (last error: %v)", ctx.Err(), lastErr)
+			case <-time.After(backoff):
+			}
+		}
+
+		retry, err := wc.doRequest(ctx, url, body)
+		if err == nil {
+			return nil
+		}
+		lastErr = err
+		if !retry {
+			break
+		}
+
+		wc.logger.Warn("Webhook delivery failed, retrying", "url", url, "attempt", attempt+1, "error", err.Error())
 	}
-	
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonData))
+
+	return lastErr
+}
+
+// doRequest выполняет одну попытку. Возвращает true, если ошибку имеет смысл повторить.
+func (wc *WebhookChannel) doRequest(ctx context.Context, url string, body []byte) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(body))
 	if err != nil {
-		return err
+		return false, err
 	}
 	
-	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Content-Type", wc.contentType)
 	req.Header.Set("User-Agent", "RicochetTask/1.0")
+	for name, value := range wc.headers {
+		req.Header.Set(name, value)
+	}
+	if wc.secret != "" {
+		req.Header.Set(wc.signatureHeader, wc.sign(body))
+	}
 	
-	client := &http.Client{Timeout: 10 * time.Second}
-	resp, err := client.Do(req)
+	resp, err := wc.client.Do(req)
 	if err != nil {
-		return err
+		return ctx.Err() == nil, err
 	}
 	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
 	
+	if resp.StatusCode >= 500 {
+		return true, fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+		return false, statusError(resp.StatusCode, fmt.Errorf("webhook returned status %d", resp.StatusCode))
 	}
 	
-	return nil
+	return false, nil
 }
 
 // SMSChannel канал SMS уведомлений  
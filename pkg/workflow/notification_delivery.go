@@ -0,0 +1,185 @@
+package workflow
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultDeliveryConcurrency - сколько каналов уведомления отправляются одновременно
+const DefaultDeliveryConcurrency = 4
+
+// DeliveryConfig настройки доставки уведомления по каналам
+type DeliveryConfig struct {
+	// Concurrency ограничивает число одновременных отправок; 0 - DefaultDeliveryConcurrency
+	Concurrency int `json:"concurrency"`
+
+	// Fallbacks задает по приоритету уведомления цепочку каналов: следующий
+	// канал используется, только если предыдущие не доставили. Например,
+	// {"critical": ["slack", "sms"]} отправляет SMS, только если Slack не
+	// ответил. Каналы цепочки, которых нет среди каналов уведомления,
+	// пропускаются; остальные каналы отправляются параллельно, как обычно.
+	Fallbacks map[string][]string `json:"fallbacks,omitempty"`
+
+	// WatchChannels каналы, по которым наблюдатели задачи без собственной
+	// подписки получают уведомления об ее изменениях; пусто - не получают
+	WatchChannels []string `json:"watch_channels,omitempty"`
+}
+
+// DeliveryConfigFromEnv читает настройки доставки из окружения:
+// RICOCHET_NOTIFICATION_CONCURRENCY, RICOCHET_NOTIFICATION_FALLBACK_<ПРИОРИТЕТ>
+// со списком каналов через запятую, например RICOCHET_NOTIFICATION_FALLBACK_CRITICAL=slack,sms,
+// и RICOCHET_WATCH_CHANNELS с каналами для наблюдателей задач
+func DeliveryConfigFromEnv() *DeliveryConfig {
+	config := &DeliveryConfig{Fallbacks: make(map[string][]string)}
+
+	if value := os.Getenv("RICOCHET_NOTIFICATION_CONCURRENCY"); value != "" {
+		if concurrency, err := strconv.Atoi(value); err == nil && concurrency > 0 {
+			config.Concurrency = concurrency
+		}
+	}
+
+	for _, priority := range []string{"low", "medium", "high", "critical"} {
+		chain := parseChannelList(os.Getenv("RICOCHET_NOTIFICATION_FALLBACK_" + strings.ToUpper(priority)))
+		if len(chain) > 0 {
+			config.Fallbacks[priority] = chain
+		}
+	}
+
+	config.WatchChannels = parseChannelList(os.Getenv("RICOCHET_WATCH_CHANNELS"))
+
+	return config
+}
+
+// parseChannelList разбирает список каналов через запятую, пропуская пустые
+func parseChannelList(value string) []string {
+	var channels []string
+	for _, channel := range strings.Split(value, ",") {
+		if channel = strings.TrimSpace(channel); channel != "" {
+			channels = append(channels, channel)
+		}
+	}
+	return channels
+}
+
+// deliveryRoutes разбивает каналы уведомления на маршруты: каждый канал вне
+// цепочки резервирования - отдельный маршрут, цепочка - один маршрут из
+// своих каналов в заданном порядке
+func (config *DeliveryConfig) deliveryRoutes(priority string, channels []string) [][]string {
+	var chain []string
+	if config != nil {
+		for _, channel := range config.Fallbacks[priority] {
+			if contains(channels, channel) && !contains(chain, channel) {
+				chain = append(chain, channel)
+			}
+		}
+	}
+
+	var routes [][]string
+	chainAdded := false
+	for _, channel := range channels {
+		if contains(chain, channel) {
+			// Цепочка встает на место первого своего канала среди каналов уведомления
+			if !chainAdded {
+				routes = append(routes, chain)
+				chainAdded = true
+			}
+			continue
+		}
+		routes = append(routes, []string{channel})
+	}
+	return routes
+}
+
+func (config *DeliveryConfig) concurrency() int {
+	if config == nil || config.Concurrency <= 0 {
+		return DefaultDeliveryConcurrency
+	}
+	return config.Concurrency
+}
+
+// routeResult итог доставки по одному маршруту
+type routeResult struct {
+	deliveredVia string   // канал, который доставил; пусто, если ни один
+	errors       []string // ошибки каналов, если маршрут не доставил
+}
+
+// deliver отправляет уведомление по маршрутам параллельно, не больше
+// concurrency одновременно. Каналы одного маршрута пробуются по очереди до
+// первой успешной доставки.
+func (sne *SmartNotificationEngine) deliver(ctx context.Context, notification *SmartNotification) []routeResult {
+	sne.mutex.RLock()
+	config := sne.delivery
+	channels := make(map[string]NotificationChannel, len(sne.channels))
+	for channelType, channel := range sne.channels {
+		channels[channelType] = channel
+	}
+	sne.mutex.RUnlock()
+
+	routes := config.deliveryRoutes(notification.Priority, notification.OptimalChannels)
+	results := make([]routeResult, len(routes))
+
+	semaphore := make(chan struct{}, config.concurrency())
+	var wg sync.WaitGroup
+	for i, route := range routes {
+		wg.Add(1)
+		go func(i int, route []string) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			results[i] = sne.deliverRoute(ctx, notification, route, channels)
+		}(i, route)
+	}
+	wg.Wait()
+
+	return results
+}
+
+// deliverRoute пробует каналы маршрута по очереди. Если ни один не доставил,
+// в очередь повторов попадает первый отказавший канал маршрута, чтобы повтор
+// не рассылал уведомление по всем резервным каналам.
+func (sne *SmartNotificationEngine) deliverRoute(ctx context.Context, notification *SmartNotification, route []string, channels map[string]NotificationChannel) routeResult {
+	var result routeResult
+	var failedChannel string
+	var failedErr error
+	for _, channelType := range route {
+		channel, exists := channels[channelType]
+		if !exists {
+			result.errors = append(result.errors, fmt.Sprintf("channel %s not found", channelType))
+			continue
+		}
+
+		err := channel.Send(ctx, sne.prepareForChannel(notification, channelType))
+		if err == nil {
+			sne.logger.Info("Notification sent successfully",
+				"channel", channelType,
+				"notification_id", notification.ID,
+				"user_id", notification.Recipients[0])
+			// Резервный канал доставил - отказы предыдущих уже не ошибка
+			return routeResult{deliveredVia: channelType}
+		}
+
+		result.errors = append(result.errors, fmt.Sprintf("%s: %v", channelType, err))
+		if failedErr == nil {
+			failedChannel, failedErr = channelType, err
+		}
+		if len(route) > 1 {
+			sne.logger.Warn("Notification channel failed, trying the next one",
+				"channel", channelType, "notification_id", notification.ID, "error", err.Error())
+		}
+	}
+
+	// Сохраняем для повтора, чтобы сбой канала не терял уведомление
+	if sne.failed != nil && failedErr != nil {
+		if queueErr := sne.failed.Add(notification, failedChannel, failedErr, time.Now()); queueErr != nil {
+			sne.logger.Error("Failed to queue notification for retry", queueErr,
+				"channel", failedChannel, "notification_id", notification.ID)
+		}
+	}
+	return result
+}
@@ -0,0 +1,372 @@
+package workflow
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	appconfig "github.com/grik-ai/ricochet-task/internal/config"
+)
+
+// Частоты доставки, при которых уведомления собираются в дайджест
+const (
+	FrequencyImmediate = "immediate"
+	FrequencyBatched   = "batched"
+	FrequencyDaily     = "daily"
+	FrequencyWeekly    = "weekly"
+)
+
+// DigestItem элемент дайджеста. Похожие уведомления схлопываются в один элемент.
+type DigestItem struct {
+	GroupKey  string    `json:"group_key"`
+	Type      string    `json:"type"`
+	Title     string    `json:"title"`
+	Message   string    `json:"message"`
+	Priority  string    `json:"priority"`
+	TaskID    string    `json:"task_id,omitempty"`
+	ProjectID string    `json:"project_id,omitempty"`
+	Count     int       `json:"count"`
+	FirstAt   time.Time `json:"first_at"`
+	LastAt    time.Time `json:"last_at"`
+}
+
+// NotificationDigest накопленные уведомления пользователя до границы окна
+type NotificationDigest struct {
+	UserID      string        `json:"user_id"`
+	Frequency   string        `json:"frequency"`
+	Channels    []string      `json:"channels"`
+	WindowStart time.Time     `json:"window_start"`
+	DeliverAt   time.Time     `json:"deliver_at"`
+	Items       []*DigestItem `json:"items"`
+}
+
+// Total возвращает общее число уведомлений в дайджесте
+func (d *NotificationDigest) Total() int {
+	total := 0
+	for _, item := range d.Items {
+		total += item.Count
+	}
+	return total
+}
+
+// DigestConfig настройки агрегатора дайджестов
+type DigestConfig struct {
+	BatchWindow  time.Duration `json:"batch_window"`  // окно для frequency=batched
+	DeliveryHour int           `json:"delivery_hour"` // час отправки daily/weekly дайджестов
+	StoragePath  string        `json:"storage_path"`  // пусто - только в памяти
+}
+
+// DefaultDigestStoragePath возвращает путь хранения дайджестов в директории активного профиля
+func DefaultDigestStoragePath() string {
+	return appconfig.ProfilePath("notification_digests.json")
+}
+
+// DigestAggregator копит уведомления подписчиков с отложенной доставкой
+type DigestAggregator struct {
+	config  *DigestConfig
+	pending map[string]*NotificationDigest // user_id -> дайджест
+	logger  Logger
+	mutex   sync.Mutex
+}
+
+// NewDigestAggregator создает агрегатор и восстанавливает сохраненные дайджесты
+func NewDigestAggregator(config *DigestConfig, logger Logger) *DigestAggregator {
+	if config == nil {
+		config = &DigestConfig{}
+	}
+	if config.BatchWindow <= 0 {
+		config.BatchWindow = time.Hour
+	}
+	if config.DeliveryHour <= 0 || config.DeliveryHour > 23 {
+		config.DeliveryHour = 9
+	}
+
+	da := &DigestAggregator{
+		config:  config,
+		pending: make(map[string]*NotificationDigest),
+		logger:  logger,
+	}
+
+	if err := da.load(); err != nil {
+		logger.Error("Failed to load pending digests", err, "path", config.StoragePath)
+	}
+
+	return da
+}
+
+// ShouldBatch проверяет, нужно ли отложить уведомление в дайджест.
+// Критичные уведомления всегда доставляются немедленно.
+func (da *DigestAggregator) ShouldBatch(subscriber *NotificationSubscriber, notification *SmartNotification) bool {
+	if subscriber == nil || subscriber.Preferences == nil {
+		return false
+	}
+
+	switch subscriber.Preferences.Frequency {
+	case FrequencyBatched, FrequencyDaily, FrequencyWeekly:
+	default:
+		return false
+	}
+
+	if notification.Urgency == "critical" || notification.Notification.Priority == "critical" ||
+		dataString(notification.Data, "priority") == "critical" {
+		return false
+	}
+
+	return true
+}
+
+// Add добавляет уведомление в дайджест подписчика
+func (da *DigestAggregator) Add(subscriber *NotificationSubscriber, notification *SmartNotification) error {
+	da.mutex.Lock()
+	defer da.mutex.Unlock()
+
+	now := time.Now()
+	digest, exists := da.pending[subscriber.UserID]
+	if !exists {
+		digest = &NotificationDigest{
+			UserID:      subscriber.UserID,
+			Frequency:   subscriber.Preferences.Frequency,
+			WindowStart: now,
+			DeliverAt:   da.nextDelivery(subscriber.Preferences.Frequency, now),
+		}
+		da.pending[subscriber.UserID] = digest
+	}
+	digest.Channels = notification.OptimalChannels
+
+	title, message := notification.Title, notification.Message
+	if notification.PersonalizedContent != nil {
+		title = notification.PersonalizedContent.Subject
+		message = notification.PersonalizedContent.Body
+	}
+
+	item := &DigestItem{
+		Type:      notification.Type,
+		Title:     title,
+		Message:   message,
+		Priority:  notification.Notification.Priority,
+		TaskID:    dataString(notification.Data, "task_id"),
+		ProjectID: dataString(notification.Data, "project_id", "project"),
+		Count:     1,
+		FirstAt:   now,
+		LastAt:    now,
+	}
+	item.GroupKey = strings.Join([]string{item.Type, item.TaskID, item.ProjectID}, "|")
+
+	grouped := false
+	if subscriber.Preferences.GroupSimilar {
+		for _, existing := range digest.Items {
+			if existing.GroupKey == item.GroupKey {
+				existing.Count++
+				existing.LastAt = now
+				existing.Title = item.Title
+				existing.Message = item.Message
+				grouped = true
+				break
+			}
+		}
+	}
+	if !grouped {
+		digest.Items = append(digest.Items, item)
+	}
+
+	da.logger.Debug("Notification added to digest",
+		"user_id", subscriber.UserID,
+		"items", len(digest.Items),
+		"deliver_at", digest.DeliverAt.Format(time.RFC3339))
+
+	return da.save()
+}
+
+// TakeDue извлекает дайджесты, время доставки которых наступило
+func (da *DigestAggregator) TakeDue(now time.Time) ([]*NotificationDigest, error) {
+	da.mutex.Lock()
+	defer da.mutex.Unlock()
+
+	var due []*NotificationDigest
+	for userID, digest := range da.pending {
+		if !digest.DeliverAt.After(now) {
+			due = append(due, digest)
+			delete(da.pending, userID)
+		}
+	}
+	if len(due) == 0 {
+		return nil, nil
+	}
+
+	sort.Slice(due, func(i, j int) bool { return due[i].UserID < due[j].UserID })
+	return due, da.save()
+}
+
+// Requeue возвращает дайджест в очередь после неудачной отправки
+func (da *DigestAggregator) Requeue(digest *NotificationDigest, retryAt time.Time) error {
+	da.mutex.Lock()
+	defer da.mutex.Unlock()
+
+	if existing, ok := da.pending[digest.UserID]; ok {
+		// За время отправки пришли новые уведомления - объединяем
+		existing.Items = append(digest.Items, existing.Items...)
+		existing.WindowStart = digest.WindowStart
+		existing.DeliverAt = retryAt
+	} else {
+		digest.DeliverAt = retryAt
+		da.pending[digest.UserID] = digest
+	}
+	return da.save()
+}
+
+// Pending возвращает копию текущего дайджеста пользователя
+func (da *DigestAggregator) Pending(userID string) *NotificationDigest {
+	da.mutex.Lock()
+	defer da.mutex.Unlock()
+
+	digest, ok := da.pending[userID]
+	if !ok {
+		return nil
+	}
+	copied := *digest
+	copied.Items = append([]*DigestItem(nil), digest.Items...)
+	return &copied
+}
+
+// nextDelivery вычисляет границу окна для частоты
+func (da *DigestAggregator) nextDelivery(frequency string, now time.Time) time.Time {
+	switch frequency {
+	case FrequencyDaily:
+		next := time.Date(now.Year(), now.Month(), now.Day(), da.config.DeliveryHour, 0, 0, 0, now.Location())
+		if !next.After(now) {
+			next = next.AddDate(0, 0, 1)
+		}
+		return next
+	case FrequencyWeekly:
+		// Еженедельный дайджест уходит в понедельник
+		next := time.Date(now.Year(), now.Month(), now.Day(), da.config.DeliveryHour, 0, 0, 0, now.Location())
+		daysUntilMonday := (int(time.Monday) - int(now.Weekday()) + 7) % 7
+		next = next.AddDate(0, 0, daysUntilMonday)
+		if !next.After(now) {
+			next = next.AddDate(0, 0, 7)
+		}
+		return next
+	default:
+		return now.Add(da.config.BatchWindow)
+	}
+}
+
+// save сохраняет дайджесты на диск. Вызывается под блокировкой.
+func (da *DigestAggregator) save() error {
+	if da.config.StoragePath == "" {
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(da.config.StoragePath), 0755); err != nil {
+		return fmt.Errorf("failed to create digest directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(da.pending, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal digests: %w", err)
+	}
+
+	// Пишем через временный файл, чтобы не потерять дайджесты при сбое
+	tmpPath := da.config.StoragePath + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write digests: %w", err)
+	}
+	if err := os.Rename(tmpPath, da.config.StoragePath); err != nil {
+		return fmt.Errorf("failed to write digests: %w", err)
+	}
+
+	return nil
+}
+
+// load восстанавливает дайджесты с диска
+func (da *DigestAggregator) load() error {
+	if da.config.StoragePath == "" {
+		return nil
+	}
+
+	data, err := os.ReadFile(da.config.StoragePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to read digests: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &da.pending); err != nil {
+		return fmt.Errorf("failed to unmarshal digests: %w", err)
+	}
+	if da.pending == nil {
+		da.pending = make(map[string]*NotificationDigest)
+	}
+
+	return nil
+}
+
+// buildDigestNotification собирает одно уведомление из дайджеста
+func buildDigestNotification(digest *NotificationDigest) *SmartNotification {
+	total := digest.Total()
+	title := fmt.Sprintf("Digest: %d %s", total, pluralize(total, "notification", "notifications"))
+
+	var body strings.Builder
+	actionItems := make([]string, 0, len(digest.Items))
+	for _, item := range digest.Items {
+		line := item.Title
+		if item.Count > 1 {
+			line = fmt.Sprintf("%s (x%d)", item.Title, item.Count)
+		}
+		fmt.Fprintf(&body, "• %s\n", line)
+		if item.Message != "" {
+			fmt.Fprintf(&body, "  %s\n", item.Message)
+		}
+		actionItems = append(actionItems, line)
+	}
+
+	now := time.Now()
+	return &SmartNotification{
+		Notification: &Notification{
+			ID:         fmt.Sprintf("digest-%d", now.UnixNano()),
+			Type:       "digest",
+			Title:      title,
+			Message:    body.String(),
+			Priority:   "low",
+			Recipients: []string{digest.UserID},
+			Data: map[string]interface{}{
+				"frequency":    digest.Frequency,
+				"window_start": digest.WindowStart,
+				"items":        len(digest.Items),
+			},
+			Timestamp: now,
+		},
+		Priority: "low",
+		Urgency:  "low",
+		PersonalizedContent: &PersonalizedContent{
+			Subject:     title,
+			Body:        body.String(),
+			Summary:     fmt.Sprintf("%d updates since %s", total, digest.WindowStart.Format("2006-01-02 15:04")),
+			ActionItems: actionItems,
+			Context:     make(map[string]string),
+		},
+		OptimalChannels: digest.Channels,
+		OptimalTiming: &OptimalTiming{
+			DeliverAt: now,
+			Reasoning: "Digest window boundary reached",
+		},
+	}
+}
+
+// dataString возвращает первое непустое строковое значение из data по ключам
+func dataString(data map[string]interface{}, keys ...string) string {
+	for _, key := range keys {
+		if value, ok := data[key]; ok && value != nil {
+			if str := fmt.Sprintf("%v", value); str != "" {
+				return str
+			}
+		}
+	}
+	return ""
+}
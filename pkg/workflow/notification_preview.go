@@ -0,0 +1,144 @@
+package workflow
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/grik-ai/ricochet-task/pkg/providers"
+)
+
+// NotificationChannelTypes каналы, которые настраиваются из окружения
+var NotificationChannelTypes = []string{"email", "slack", "teams", "webhook"}
+
+// sampleEventData данные примеров событий задачи, о которых уведомляются наблюдатели
+var sampleEventData = map[providers.EventType]map[string]interface{}{
+	providers.EventTypeTaskCreated: {
+		"title": "Rotate TLS certificates", "priority": "high", "type": "chore",
+		"project_id": "OPS", "status": "Open", "assignee": "alice", "actor": "bob",
+	},
+	providers.EventTypeTaskUpdated:       {"title": "Rotate TLS certificates", "status": "In Progress", "actor": "bob"},
+	providers.EventTypeTaskDeleted:       {"title": "Rotate TLS certificates", "actor": "bob"},
+	providers.EventTypeTaskAssigned:      {"title": "Rotate TLS certificates", "assignee": "alice"},
+	providers.EventTypeTaskStatusChanged: {"title": "Rotate TLS certificates", "status": "Done", "actor": "bob"},
+	providers.EventTypeCommentAdded:      {"title": "Rotate TLS certificates", "actor": "bob"},
+}
+
+// SampleEventTypes возвращает события, для которых есть пример уведомления
+func SampleEventTypes() []string {
+	types := make([]string, 0, len(sampleEventData))
+	for eventType := range sampleEventData {
+		types = append(types, string(eventType))
+	}
+	sort.Strings(types)
+	return types
+}
+
+// SampleWatchNotification собирает уведомление наблюдателя о примере события,
+// как его отрисует NotifyWatchers, без AI персонализации
+func SampleWatchNotification(eventType providers.EventType, recipient string) (*Notification, error) {
+	sample, ok := sampleEventData[eventType]
+	if !ok {
+		return nil, providers.NewValidationError(fmt.Sprintf("unknown event %q, use one of %s", eventType, strings.Join(SampleEventTypes(), ", ")), nil)
+	}
+
+	data := make(map[string]interface{}, len(sample))
+	for key, value := range sample {
+		data[key] = value
+	}
+	event := &providers.UniversalEvent{
+		Type:      eventType,
+		Source:    "sample",
+		TaskID:    "OPS-123",
+		Data:      data,
+		Timestamp: time.Now(),
+	}
+
+	templates := NewNotificationTemplates()
+	data = watchEventData(event)
+	return &Notification{
+		ID:         fmt.Sprintf("sample-%d", time.Now().UnixNano()),
+		Type:       "task_watch",
+		Title:      templates.RenderTemplate("task_watch_title", data),
+		Message:    templates.RenderTemplate("task_watch_body", data),
+		Priority:   "medium",
+		Recipients: []string{recipient},
+		Data:       data,
+		Template:   "task_watch",
+		Timestamp:  event.Timestamp,
+	}, nil
+}
+
+// NewChannelFromEnv создает канал с настройками из RICOCHET_* переменных, как
+// его регистрирует движок уведомлений
+func NewChannelFromEnv(channelType string, logger Logger) (NotificationChannel, error) {
+	switch channelType {
+	case "email":
+		return NewEmailChannel(logger), nil
+	case "slack":
+		return NewSlackChannel(logger), nil
+	case "teams":
+		return NewTeamsChannel(logger), nil
+	case "webhook":
+		config, err := WebhookChannelConfigFromEnv()
+		if err != nil {
+			return nil, err
+		}
+		return NewWebhookChannelWithConfig(config, logger)
+	}
+	return nil, providers.NewValidationError(fmt.Sprintf("unknown notification channel %q, use one of %s", channelType, strings.Join(NotificationChannelTypes, ", ")), nil)
+}
+
+// ChannelPreview то, что канал отправил бы: адрес доставки и тело запроса
+type ChannelPreview struct {
+	Channel string      `json:"channel" yaml:"channel"`
+	Target  string      `json:"target,omitempty" yaml:"target,omitempty"`
+	Payload interface{} `json:"payload" yaml:"payload"`
+}
+
+// PreviewChannel отрисовывает уведомление так, как его отправит канал, ничего
+// не отправляя. Target пуст, если канал не настроен.
+func PreviewChannel(channel NotificationChannel, notification *Notification) (*ChannelPreview, error) {
+	preview := &ChannelPreview{Channel: channel.GetType()}
+
+	switch ch := channel.(type) {
+	case *EmailChannel:
+		preview.Target = strings.Join(notification.Recipients, ", ")
+		preview.Payload = map[string]string{"subject": notification.Title, "body": ch.formatEmailBody(notification)}
+	case *SlackChannel:
+		switch {
+		case ch.webhookURL != "":
+			preview.Target = "incoming webhook"
+		case ch.botToken != "":
+			preview.Target = strings.Join(ch.targetChannels(notification), ", ")
+		}
+		preview.Payload = ch.formatSlackMessage(notification)
+	case *TeamsChannel:
+		if ch.webhookURL != "" {
+			preview.Target = "connector webhook"
+		}
+		preview.Payload = ch.formatTeamsMessage(notification)
+	case *WebhookChannel:
+		if ch.configErr != nil {
+			return nil, fmt.Errorf("webhook: %w", ch.configErr)
+		}
+		preview.Target = ch.getWebhookURL(notification)
+		body, err := ch.renderPayload(notification)
+		if err != nil {
+			return nil, fmt.Errorf("webhook: %w", err)
+		}
+		// JSON показываем структурой, остальные форматы как есть
+		var payload interface{}
+		if json.Unmarshal(body, &payload) == nil {
+			preview.Payload = payload
+		} else {
+			preview.Payload = string(body)
+		}
+	default:
+		return nil, fmt.Errorf("preview is not supported for %s notifications", channel.GetType())
+	}
+
+	return preview, nil
+}
@@ -0,0 +1,363 @@
+package workflow
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	appconfig "github.com/grik-ai/ricochet-task/internal/config"
+)
+
+// PermanentDeliveryError ошибка доставки, которую повтор не исправит:
+// неверный получатель, удаленный канал или отклоненный запрос
+type PermanentDeliveryError struct {
+	Err error
+}
+
+// NewPermanentDeliveryError помечает ошибку доставки как постоянную
+func NewPermanentDeliveryError(err error) error {
+	return &PermanentDeliveryError{Err: err}
+}
+
+func (e *PermanentDeliveryError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *PermanentDeliveryError) Unwrap() error {
+	return e.Err
+}
+
+// IsPermanentDeliveryError проверяет, что повтор доставки не поможет.
+// Ненастроенный канал тоже постоянная ошибка, пока не изменятся настройки.
+func IsPermanentDeliveryError(err error) bool {
+	var permanent *PermanentDeliveryError
+	return errors.As(err, &permanent) || errors.Is(err, ErrChannelNotConfigured)
+}
+
+// statusError помечает HTTP ошибку как постоянную для 4xx ответов, кроме
+// таймаута запроса и превышения лимита
+func statusError(status int, err error) error {
+	if status >= 400 && status < 500 && status != http.StatusRequestTimeout && status != http.StatusTooManyRequests {
+		return NewPermanentDeliveryError(err)
+	}
+	return err
+}
+
+// FailedDelivery неудачная доставка уведомления по одному каналу
+type FailedDelivery struct {
+	ID            string             `json:"id"`
+	Channel       string             `json:"channel"`
+	Notification  *SmartNotification `json:"notification"`
+	Attempts      int                `json:"attempts"`
+	LastError     string             `json:"last_error"`
+	Permanent     bool               `json:"permanent"`
+	FailedAt      time.Time          `json:"failed_at"`
+	NextAttemptAt time.Time          `json:"next_attempt_at"`
+}
+
+// RetryConfig настройки повторной доставки
+type RetryConfig struct {
+	InitialBackoff time.Duration `json:"initial_backoff"` // пауза перед первым повтором, дальше удваивается
+	MaxBackoff     time.Duration `json:"max_backoff"`
+	MaxAttempts    int           `json:"max_attempts"` // после стольких попыток доставка считается постоянно неудачной
+	StoragePath    string        `json:"storage_path"` // пусто - только в памяти
+}
+
+// DefaultFailedNotificationsPath возвращает путь хранения неудачных доставок в директории активного профиля
+func DefaultFailedNotificationsPath() string {
+	return appconfig.ProfilePath("failed_notifications.json")
+}
+
+// FailedNotificationQueue хранит неудачные доставки до повтора. У каждого
+// канала уведомления своя запись и своя пауза, поэтому сбой Slack не
+// задерживает повтор по email.
+type FailedNotificationQueue struct {
+	config  *RetryConfig
+	entries map[string]*FailedDelivery // id -> доставка
+	logger  Logger
+	mutex   sync.Mutex
+}
+
+// NewFailedNotificationQueue создает очередь и восстанавливает сохраненные доставки
+func NewFailedNotificationQueue(config *RetryConfig, logger Logger) *FailedNotificationQueue {
+	if config == nil {
+		config = &RetryConfig{}
+	}
+	if config.InitialBackoff <= 0 {
+		config.InitialBackoff = time.Minute
+	}
+	if config.MaxBackoff <= 0 {
+		config.MaxBackoff = time.Hour
+	}
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = 10
+	}
+
+	fq := &FailedNotificationQueue{
+		config:  config,
+		entries: make(map[string]*FailedDelivery),
+		logger:  logger,
+	}
+
+	if err := fq.load(); err != nil {
+		logger.Error("Failed to load failed notifications", err, "path", config.StoragePath)
+	}
+
+	return fq
+}
+
+// Add записывает неудачную попытку доставки и назначает следующую
+func (fq *FailedNotificationQueue) Add(notification *SmartNotification, channel string, sendErr error, now time.Time) error {
+	fq.mutex.Lock()
+	defer fq.mutex.Unlock()
+
+	id := notification.ID + ":" + channel
+	entry, exists := fq.entries[id]
+	if !exists {
+		// Контекст содержит событие-интерфейс и не нужен каналам для повтора
+		stored := *notification
+		stored.Context = nil
+		entry = &FailedDelivery{
+			ID:           id,
+			Channel:      channel,
+			Notification: &stored,
+			FailedAt:     now,
+		}
+		fq.entries[id] = entry
+	}
+	fq.recordAttempt(entry, sendErr, now)
+	return fq.save()
+}
+
+// Fail возвращает в очередь доставку, повтор которой не удался
+func (fq *FailedNotificationQueue) Fail(entry *FailedDelivery, sendErr error, now time.Time) error {
+	fq.mutex.Lock()
+	defer fq.mutex.Unlock()
+
+	fq.entries[entry.ID] = entry
+	fq.recordAttempt(entry, sendErr, now)
+	return fq.save()
+}
+
+// Requeue возвращает доставку в очередь без новой попытки
+func (fq *FailedNotificationQueue) Requeue(entry *FailedDelivery) error {
+	fq.mutex.Lock()
+	defer fq.mutex.Unlock()
+
+	fq.entries[entry.ID] = entry
+	return fq.save()
+}
+
+// recordAttempt учитывает попытку. Вызывается под блокировкой.
+func (fq *FailedNotificationQueue) recordAttempt(entry *FailedDelivery, sendErr error, now time.Time) {
+	entry.Attempts++
+	entry.LastError = sendErr.Error()
+	entry.Permanent = IsPermanentDeliveryError(sendErr) || entry.Attempts >= fq.config.MaxAttempts
+	entry.NextAttemptAt = now.Add(fq.backoff(entry.Attempts))
+}
+
+// backoff возвращает паузу после попытки с номером attempt
+func (fq *FailedNotificationQueue) backoff(attempt int) time.Duration {
+	delay := fq.config.InitialBackoff
+	for i := 1; i < attempt && delay < fq.config.MaxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > fq.config.MaxBackoff {
+		delay = fq.config.MaxBackoff
+	}
+	return delay
+}
+
+// TakeDue извлекает доставки для повтора: временные с наступившим временем
+// попытки, все временные при force и постоянные при includePermanent
+func (fq *FailedNotificationQueue) TakeDue(now time.Time, force, includePermanent bool) ([]*FailedDelivery, error) {
+	fq.mutex.Lock()
+	defer fq.mutex.Unlock()
+
+	var due []*FailedDelivery
+	for id, entry := range fq.entries {
+		if entry.Permanent && !includePermanent {
+			continue
+		}
+		if !entry.Permanent && !force && entry.NextAttemptAt.After(now) {
+			continue
+		}
+		due = append(due, entry)
+		delete(fq.entries, id)
+	}
+	if len(due) == 0 {
+		return nil, nil
+	}
+
+	sort.Slice(due, func(i, j int) bool { return due[i].FailedAt.Before(due[j].FailedAt) })
+	return due, fq.save()
+}
+
+// List возвращает копии ожидающих доставок, старые первыми
+func (fq *FailedNotificationQueue) List() []*FailedDelivery {
+	fq.mutex.Lock()
+	defer fq.mutex.Unlock()
+
+	list := make([]*FailedDelivery, 0, len(fq.entries))
+	for _, entry := range fq.entries {
+		copied := *entry
+		list = append(list, &copied)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].FailedAt.Before(list[j].FailedAt) })
+	return list
+}
+
+// save сохраняет очередь на диск. Вызывается под блокировкой.
+func (fq *FailedNotificationQueue) save() error {
+	if fq.config.StoragePath == "" {
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(fq.config.StoragePath), 0755); err != nil {
+		return fmt.Errorf("failed to create failed notifications directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(fq.entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal failed notifications: %w", err)
+	}
+
+	// Пишем через временный файл, чтобы не потерять очередь при сбое
+	tmpPath := fq.config.StoragePath + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write failed notifications: %w", err)
+	}
+	if err := os.Rename(tmpPath, fq.config.StoragePath); err != nil {
+		return fmt.Errorf("failed to write failed notifications: %w", err)
+	}
+
+	return nil
+}
+
+// load восстанавливает очередь с диска
+func (fq *FailedNotificationQueue) load() error {
+	if fq.config.StoragePath == "" {
+		return nil
+	}
+
+	data, err := os.ReadFile(fq.config.StoragePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to read failed notifications: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &fq.entries); err != nil {
+		return fmt.Errorf("failed to unmarshal failed notifications: %w", err)
+	}
+	if fq.entries == nil {
+		fq.entries = make(map[string]*FailedDelivery)
+	}
+
+	return nil
+}
+
+// RetryOptions выбор доставок для RetryFailed
+type RetryOptions struct {
+	Force            bool // не ждать окончания паузы
+	IncludePermanent bool // повторить и постоянные ошибки, например после исправления получателя
+}
+
+// RetryReport итог повтора
+type RetryReport struct {
+	Delivered []*FailedDelivery `json:"delivered"`
+	Failed    []*FailedDelivery `json:"failed"`
+	Deferred  []*FailedDelivery `json:"deferred"` // канал недоступен, повтор отложен без попытки
+	Remaining int               `json:"remaining"`
+}
+
+// RetryFailed повторяет неудачные доставки. После временной ошибки канала
+// остальные его доставки откладываются до следующего запуска, чтобы не
+// нагружать канал во время сбоя.
+func (sne *SmartNotificationEngine) RetryFailed(ctx context.Context, now time.Time, options RetryOptions) (*RetryReport, error) {
+	report := &RetryReport{}
+	if sne.failed == nil {
+		return report, nil
+	}
+
+	due, err := sne.failed.TakeDue(now, options.Force, options.IncludePermanent)
+	if err != nil {
+		sne.logger.Error("Failed to persist failed notifications", err)
+	}
+
+	down := make(map[string]bool)
+	for _, entry := range due {
+		if down[entry.Channel] {
+			report.Deferred = append(report.Deferred, entry)
+			if err := sne.failed.Requeue(entry); err != nil {
+				return report, err
+			}
+			continue
+		}
+
+		sne.mutex.RLock()
+		channel, exists := sne.channels[entry.Channel]
+		sne.mutex.RUnlock()
+
+		var sendErr error
+		if !exists {
+			sendErr = NewPermanentDeliveryError(fmt.Errorf("channel %s not found", entry.Channel))
+		} else {
+			sendErr = channel.Send(ctx, sne.prepareForChannel(entry.Notification, entry.Channel))
+		}
+
+		sne.analytics.RecordRetry(entry.Notification.ID, entry.Channel, entry.Attempts, sendErr == nil)
+		if sendErr == nil {
+			sne.logger.Info("Notification delivered on retry",
+				"channel", entry.Channel,
+				"notification_id", entry.Notification.ID,
+				"attempts", entry.Attempts+1)
+			report.Delivered = append(report.Delivered, entry)
+			continue
+		}
+
+		if err := sne.failed.Fail(entry, sendErr, now); err != nil {
+			return report, err
+		}
+		if !entry.Permanent {
+			down[entry.Channel] = true
+		}
+		report.Failed = append(report.Failed, entry)
+	}
+
+	report.Remaining = len(sne.failed.List())
+	return report, nil
+}
+
+// RunRetryScheduler периодически повторяет неудачные доставки до отмены контекста
+func (sne *SmartNotificationEngine) RunRetryScheduler(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			report, err := sne.RetryFailed(ctx, now, RetryOptions{})
+			if err != nil {
+				sne.logger.Error("Notification retry failed", err)
+				continue
+			}
+			if len(report.Delivered)+len(report.Failed) > 0 {
+				sne.logger.Info("Retried failed notifications",
+					"delivered", len(report.Delivered),
+					"failed", len(report.Failed),
+					"remaining", report.Remaining)
+			}
+		}
+	}
+}
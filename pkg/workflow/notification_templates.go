@@ -149,6 +149,16 @@ Impact: {{.impact | titleCase}}
 {{range .recommendations}}• {{.}}
 {{end}}{{end}}`)
 
+	// Шаблоны для наблюдателей задачи
+	nt.RegisterTemplate("task_watch_title", "Watched task {{.task_id}}: {{.change}}")
+	nt.RegisterTemplate("task_watch_body", `Task {{.task_id}} you are watching has changed ({{.change}}).
+{{if .title}}
+Title: {{.title}}{{end}}{{if .status}}
+Status: {{.status}}{{end}}{{if .assignee}}
+Assignee: {{.assignee}}{{end}}{{if .actor}}
+Changed by: {{.actor}}{{end}}
+Provider: {{.provider}}`)
+
 	// Шаблоны для экстренных уведомлений
 	nt.RegisterTemplate("critical_alert_title", "🚨 CRITICAL: {{.alert_type | titleCase}}")
 	nt.RegisterTemplate("critical_alert_body", `CRITICAL ALERT: {{.alert_type | titleCase}}
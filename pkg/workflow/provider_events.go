@@ -0,0 +1,121 @@
+package workflow
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/grik-ai/ricochet-task/pkg/providers"
+)
+
+// Типы событий workflow, в которые транслируются события провайдеров
+const (
+	EventProviderTaskCreated       = "task_created"
+	EventProviderTaskUpdated       = "task_updated"
+	EventProviderTaskDeleted       = "task_deleted"
+	EventProviderTaskAssigned      = "task_assigned"
+	EventProviderTaskStatusChanged = "task_status_changed"
+)
+
+// providerEventsSubscriber имя подписчика движка на шине провайдеров
+const providerEventsSubscriber = "workflow-engine"
+
+// taskWatchersSubscriber имя подписчика наблюдателей задач на шине провайдеров
+const taskWatchersSubscriber = "task-watchers"
+
+// ProviderEventType переводит тип события провайдера в тип события workflow:
+// task.status_changed -> task_status_changed
+func ProviderEventType(eventType providers.EventType) string {
+	return strings.ReplaceAll(string(eventType), ".", "_")
+}
+
+// NewProviderEvent оборачивает событие провайдера в событие workflow
+func NewProviderEvent(event *providers.UniversalEvent) *WorkflowEvent {
+	data := make(map[string]interface{}, len(event.Data)+4)
+	for key, value := range event.Data {
+		data[key] = value
+	}
+	data["event_id"] = event.ID
+	data["task_id"] = event.TaskID
+	data["provider"] = event.Source
+	if event.BoardID != "" {
+		data["board_id"] = event.BoardID
+	}
+
+	return &WorkflowEvent{
+		Type:      ProviderEventType(event.Type),
+		Timestamp: event.Timestamp,
+		Source:    event.Source,
+		Data:      data,
+	}
+}
+
+// ConnectProviderEvents подписывает движок на шину событий провайдеров.
+// События попадают во внутреннюю шину, откуда их получают уведомления,
+// автоназначение и журнал аудита.
+func (cwe *CompleteWorkflowEngine) ConnectProviderEvents(bus *providers.EventBus) error {
+	err := bus.Subscribe(providerEventsSubscriber, func(event *providers.UniversalEvent) error {
+		return cwe.eventBus.Publish(context.Background(), NewProviderEvent(event))
+	})
+	if err != nil {
+		return fmt.Errorf("failed to subscribe to provider events: %w", err)
+	}
+
+	cwe.logger.Info("Connected to provider event bus")
+	return nil
+}
+
+// ConnectTaskWatchers подписывает наблюдателей задач на шину событий
+// провайдеров: об изменении задачи ее наблюдателям сообщает notifier
+func ConnectTaskWatchers(bus *providers.EventBus, watchers *providers.TaskWatchRegistry, notifier providers.WatchNotifier) error {
+	watchers.SetNotifier(notifier)
+
+	if err := bus.Subscribe(taskWatchersSubscriber, watchers.HandleEvent); err != nil {
+		return fmt.Errorf("failed to subscribe task watchers to provider events: %w", err)
+	}
+	return nil
+}
+
+// registerProviderEventHandlers регистрирует обработчики событий провайдеров
+func (cwe *CompleteWorkflowEngine) registerProviderEventHandlers() {
+	notify := &WorkflowEventHandler{
+		handler: func(ctx context.Context, event Event) error {
+			return cwe.notifications.ProcessEvent(ctx, event)
+		},
+	}
+	for _, eventType := range []string{
+		EventProviderTaskCreated,
+		EventProviderTaskUpdated,
+		EventProviderTaskDeleted,
+		EventProviderTaskAssigned,
+		EventProviderTaskStatusChanged,
+	} {
+		cwe.eventBus.Subscribe(eventType, notify)
+	}
+
+	// Новые задачи без исполнителя - кандидаты на автоназначение
+	cwe.eventBus.Subscribe(EventProviderTaskCreated, &WorkflowEventHandler{
+		handler: func(ctx context.Context, event Event) error {
+			return cwe.autoAssignment.ProcessEvent(ctx, event)
+		},
+	})
+
+	// Смена статуса в провайдере попадает в журнал переходов
+	if cwe.history != nil {
+		cwe.eventBus.Subscribe(EventProviderTaskStatusChanged, &WorkflowEventHandler{
+			handler: cwe.recordProviderTransition,
+		})
+	}
+}
+
+// recordProviderTransition записывает смену статуса задачи провайдера в журнал
+func (cwe *CompleteWorkflowEngine) recordProviderTransition(ctx context.Context, event Event) error {
+	data := event.GetData()
+	return cwe.history.Record(&TransitionRecord{
+		TaskID:    dataString(data, "task_id"),
+		ToStage:   dataString(data, "status"),
+		Actor:     dataString(data, "actor"),
+		Reason:    fmt.Sprintf("status changed in %s", event.GetSource()),
+		Timestamp: event.GetTimestamp(),
+	})
+}
@@ -3,11 +3,13 @@ package workflow
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/grik-ai/ricochet-task/pkg/ai"
+	"github.com/grik-ai/ricochet-task/pkg/providers"
 )
 
 // SmartNotificationEngine интеллектная система уведомлений
@@ -21,6 +23,9 @@ type SmartNotificationEngine struct {
 	analytics       *NotificationAnalytics
 	rateLimiter     *NotificationRateLimiter
 	contextAnalyzer *NotificationContextAnalyzer
+	digests         *DigestAggregator
+	failed          *FailedNotificationQueue
+	delivery        *DeliveryConfig
 	mutex           sync.RWMutex
 }
 
@@ -106,6 +111,9 @@ type SmartNotification struct {
 	OptimalTiming     *OptimalTiming         `json:"optimal_timing"`
 	Context           *NotificationContext   `json:"context"`
 	AIAnalysis        *AINotificationAnalysis `json:"ai_analysis"`
+
+	// DeliveredVia - каналы, которые доставили уведомление при последней отправке
+	DeliveredVia []string `json:"delivered_via,omitempty"`
 }
 
 // PersonalizedContent персонализированный контент
@@ -148,6 +156,9 @@ func NewSmartNotificationEngine(aiChains *ai.AIChains, logger Logger) *SmartNoti
 		analytics:       NewNotificationAnalytics(logger),
 		rateLimiter:     NewNotificationRateLimiter(logger),
 		contextAnalyzer: NewNotificationContextAnalyzer(aiChains, logger),
+		digests:         NewDigestAggregator(&DigestConfig{StoragePath: DefaultDigestStoragePath()}, logger),
+		failed:          NewFailedNotificationQueue(&RetryConfig{StoragePath: DefaultFailedNotificationsPath()}, logger),
+		delivery:        DeliveryConfigFromEnv(),
 	}
 	
 	// Регистрируем стандартные каналы
@@ -168,6 +179,38 @@ func (sne *SmartNotificationEngine) RegisterChannel(channel NotificationChannel)
 	sne.logger.Info("Registered notification channel", "type", channel.GetType())
 }
 
+// SetDigestAggregator заменяет агрегатор дайджестов (например, с другим хранилищем)
+func (sne *SmartNotificationEngine) SetDigestAggregator(digests *DigestAggregator) {
+	sne.mutex.Lock()
+	defer sne.mutex.Unlock()
+
+	sne.digests = digests
+}
+
+// SetFailedQueue заменяет очередь неудачных доставок; nil отключает повторы
+func (sne *SmartNotificationEngine) SetFailedQueue(failed *FailedNotificationQueue) {
+	sne.mutex.Lock()
+	defer sne.mutex.Unlock()
+
+	sne.failed = failed
+}
+
+// SetDeliveryConfig заменяет настройки параллельной и резервной доставки по каналам
+func (sne *SmartNotificationEngine) SetDeliveryConfig(config *DeliveryConfig) {
+	sne.mutex.Lock()
+	defer sne.mutex.Unlock()
+
+	sne.delivery = config
+}
+
+// FailedDeliveries возвращает доставки, ожидающие повтора
+func (sne *SmartNotificationEngine) FailedDeliveries() []*FailedDelivery {
+	if sne.failed == nil {
+		return nil
+	}
+	return sne.failed.List()
+}
+
 // Subscribe подписывает пользователя на уведомления
 func (sne *SmartNotificationEngine) Subscribe(ctx context.Context, subscriber *NotificationSubscriber) error {
 	sne.mutex.Lock()
@@ -208,33 +251,177 @@ func (sne *SmartNotificationEngine) ProcessEvent(ctx context.Context, event Even
 		subscribers := sne.findRelevantSubscribers(event, rule)
 		
 		for _, subscriber := range subscribers {
-			// Создаем умное уведомление
-			smartNotification, err := sne.createSmartNotification(ctx, event, subscriber, rule)
-			if err != nil {
-				sne.logger.Error("Failed to create smart notification", err,
-					"user_id", subscriber.UserID, "event_type", event.GetType())
-				continue
-			}
-			
-			// Проверяем, нужно ли отправлять уведомление
-			if !sne.shouldSendNotification(ctx, smartNotification) {
-				sne.logger.Debug("Notification filtered out", 
-					"user_id", subscriber.UserID, 
-					"reason", "filtering_rules")
+			sne.deliverToSubscriber(ctx, event, subscriber, rule)
+		}
+	}
+	
+	return nil
+}
+
+// NotifyWatchers уведомляет наблюдателей задачи об изменении в провайдере.
+// Пользователи без подписки на уведомления получают их по каналам
+// DeliveryConfig.WatchChannels, а если они не заданы - по всем настроенным
+// каналам. Если не настроен ни один канал, наблюдатели пропускаются.
+func (sne *SmartNotificationEngine) NotifyWatchers(ctx context.Context, userIDs []string, event *providers.UniversalEvent) error {
+	watchEvent := &WorkflowEvent{
+		Type:      "task_watch",
+		Timestamp: event.Timestamp,
+		Source:    event.Source,
+		Data:      watchEventData(event),
+	}
+	if watchEvent.Timestamp.IsZero() {
+		watchEvent.Timestamp = time.Now()
+	}
+	rule := &NotificationRule{Event: "task_watch", Template: "task_watch"}
+
+	for _, userID := range userIDs {
+		sne.mutex.RLock()
+		subscribers := sne.subscribers[userID]
+		var watchChannels []string
+		if sne.delivery != nil {
+			watchChannels = sne.delivery.WatchChannels
+		}
+		if len(watchChannels) == 0 {
+			watchChannels = sne.configuredChannels()
+		}
+		sne.mutex.RUnlock()
+
+		if len(subscribers) == 0 {
+			if len(watchChannels) == 0 {
+				sne.logger.Debug("Watcher has no notification subscription", "user_id", userID, "task_id", event.TaskID)
 				continue
 			}
-			
-			// Отправляем уведомление
-			if err := sne.sendSmartNotification(ctx, smartNotification); err != nil {
-				sne.logger.Error("Failed to send notification", err,
-					"user_id", subscriber.UserID, "notification_id", smartNotification.ID)
-			}
+			subscribers = []*NotificationSubscriber{watchSubscriber(userID, watchChannels)}
+		}
+
+		// Наблюдатель получает одно уведомление, даже если подписок несколько
+		sne.deliverToSubscriber(ctx, watchEvent, subscribers[0], rule)
+	}
+
+	return nil
+}
+
+// configuredChannels возвращает типы настроенных каналов по порядку.
+// Вызывается под sne.mutex.
+func (sne *SmartNotificationEngine) configuredChannels() []string {
+	var channels []string
+	for channelType, channel := range sne.channels {
+		if configurable, ok := channel.(ConfigurableChannel); ok && !configurable.Configured() {
+			continue
+		}
+		channels = append(channels, channelType)
+	}
+	sort.Strings(channels)
+	return channels
+}
+
+// watchSubscriber подписка по умолчанию для наблюдателя без собственной подписки
+func watchSubscriber(userID string, channels []string) *NotificationSubscriber {
+	return &NotificationSubscriber{
+		ID:          "watch-" + userID,
+		UserID:      userID,
+		Preferences: &NotificationPrefs{Channels: channels, Frequency: FrequencyImmediate},
+	}
+}
+
+// watchEventData данные шаблона task_watch для события провайдера
+func watchEventData(event *providers.UniversalEvent) map[string]interface{} {
+	data := make(map[string]interface{}, len(event.Data)+3)
+	for key, value := range event.Data {
+		data[key] = value
+	}
+	data["task_id"] = event.TaskID
+	data["change"] = string(event.Type)
+	data["provider"] = event.Source
+	return data
+}
+
+// deliverToSubscriber создает уведомление по правилу и доставляет его подписчику
+func (sne *SmartNotificationEngine) deliverToSubscriber(ctx context.Context, event Event, subscriber *NotificationSubscriber, rule *NotificationRule) {
+	// Создаем умное уведомление
+	smartNotification, err := sne.createSmartNotification(ctx, event, subscriber, rule)
+	if err != nil {
+		sne.logger.Error("Failed to create smart notification", err,
+			"user_id", subscriber.UserID, "event_type", event.GetType())
+		return
+	}
+	
+	// Подписчики с отложенной доставкой получают дайджест
+	if sne.digests != nil && sne.digests.ShouldBatch(subscriber, smartNotification) {
+		if err := sne.digests.Add(subscriber, smartNotification); err != nil {
+			sne.logger.Error("Failed to add notification to digest", err,
+				"user_id", subscriber.UserID, "notification_id", smartNotification.ID)
 		}
+		return
 	}
 	
+	// Проверяем, нужно ли отправлять уведомление
+	if !sne.shouldSendNotification(ctx, smartNotification) {
+		sne.logger.Debug("Notification filtered out", 
+			"user_id", subscriber.UserID, 
+			"reason", "filtering_rules")
+		return
+	}
+	
+	// Отправляем уведомление
+	if err := sne.sendSmartNotification(ctx, smartNotification); err != nil {
+		sne.logger.Error("Failed to send notification", err,
+			"user_id", subscriber.UserID, "notification_id", smartNotification.ID)
+	}
+}
+
+// FlushDigests отправляет дайджесты, у которых наступила граница окна
+func (sne *SmartNotificationEngine) FlushDigests(ctx context.Context, now time.Time) error {
+	if sne.digests == nil {
+		return nil
+	}
+
+	due, err := sne.digests.TakeDue(now)
+	if err != nil {
+		sne.logger.Error("Failed to persist digests", err)
+	}
+
+	var errors []string
+	for _, digest := range due {
+		notification := buildDigestNotification(digest)
+		if err := sne.sendSmartNotification(ctx, notification); err != nil {
+			errors = append(errors, fmt.Sprintf("%s: %v", digest.UserID, err))
+			// Повторим отправку в следующем окне, чтобы дайджест не потерялся
+			if requeueErr := sne.digests.Requeue(digest, now.Add(sne.digests.config.BatchWindow)); requeueErr != nil {
+				sne.logger.Error("Failed to requeue digest", requeueErr, "user_id", digest.UserID)
+			}
+			continue
+		}
+
+		sne.logger.Info("Digest sent",
+			"user_id", digest.UserID,
+			"items", len(digest.Items),
+			"notifications", digest.Total())
+	}
+
+	if len(errors) > 0 {
+		return fmt.Errorf("failed to send digests: %s", strings.Join(errors, "; "))
+	}
 	return nil
 }
 
+// RunDigestScheduler периодически отправляет готовые дайджесты до отмены контекста
+func (sne *SmartNotificationEngine) RunDigestScheduler(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if err := sne.FlushDigests(ctx, now); err != nil {
+				sne.logger.Error("Digest delivery failed", err)
+			}
+		}
+	}
+}
+
 // createSmartNotification создает умное уведомление
 func (sne *SmartNotificationEngine) createSmartNotification(ctx context.Context, event Event, subscriber *NotificationSubscriber, rule *NotificationRule) (*SmartNotification, error) {
 	// Анализируем контекст
@@ -401,24 +588,11 @@ func (sne *SmartNotificationEngine) sendSmartNotification(ctx context.Context, n
 	
 	// Отправляем немедленно по оптимальным каналам
 	var errors []string
-	for _, channelType := range notification.OptimalChannels {
-		channel, exists := sne.channels[channelType]
-		if !exists {
-			errors = append(errors, fmt.Sprintf("channel %s not found", channelType))
-			continue
-		}
-		
-		// Подготавливаем уведомление для канала
-		channelNotification := sne.prepareForChannel(notification, channelType)
-		
-		// Отправляем
-		if err := channel.Send(ctx, channelNotification); err != nil {
-			errors = append(errors, fmt.Sprintf("%s: %v", channelType, err))
-		} else {
-			sne.logger.Info("Notification sent successfully", 
-				"channel", channelType, 
-				"notification_id", notification.ID,
-				"user_id", notification.Recipients[0])
+	notification.DeliveredVia = nil
+	for _, result := range sne.deliver(ctx, notification) {
+		errors = append(errors, result.errors...)
+		if result.deliveredVia != "" {
+			notification.DeliveredVia = append(notification.DeliveredVia, result.deliveredVia)
 		}
 	}
 	
@@ -665,10 +839,18 @@ func (sne *SmartNotificationEngine) scheduleNotification(ctx context.Context, no
 func (sne *SmartNotificationEngine) prepareForChannel(notification *SmartNotification, channelType string) *Notification {
 	// Адаптируем уведомление для конкретного канала
 	adapted := *notification.Notification
+
+	// Копируем Data, чтобы каналы имели доступ к исходному умному уведомлению
+	adapted.Data = make(map[string]interface{}, len(notification.Data)+2)
+	for key, value := range notification.Data {
+		adapted.Data[key] = value
+	}
+	adapted.Data["smart_notification"] = notification
 	
 	if notification.PersonalizedContent != nil {
 		adapted.Title = notification.PersonalizedContent.Subject
 		adapted.Message = notification.PersonalizedContent.Body
+		adapted.Data["personalized_content"] = notification.PersonalizedContent
 	}
 	
 	return &adapted
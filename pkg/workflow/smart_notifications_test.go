@@ -2,8 +2,23 @@ package workflow
 
 import (
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
+
+	"github.com/grik-ai/ricochet-task/pkg/providers"
 )
 
 // TestSmartNotificationEngine тестирует основной движок уведомлений
@@ -178,7 +193,7 @@ func TestNotificationChannels(t *testing.T) {
 	logger := &MockLogger{}
 	
 	t.Run("EmailChannel", func(t *testing.T) {
-		emailChannel := NewEmailChannel(logger)
+		emailChannel := NewEmailChannelWithConfig(nil, logger)
 		
 		if emailChannel.GetType() != "email" {
 			t.Error("Email channel type incorrect")
@@ -195,15 +210,15 @@ func TestNotificationChannels(t *testing.T) {
 			Timestamp:  time.Now(),
 		}
 		
-		// В тестовом режиме это должно пройти без ошибок
+		// Без SMTP настроек отправка не должна считаться успешной
 		err := emailChannel.Send(context.Background(), notification)
-		if err != nil {
-			t.Fatalf("Failed to send email: %v", err)
+		if !errors.Is(err, ErrChannelNotConfigured) {
+			t.Fatalf("Expected ErrChannelNotConfigured, got %v", err)
 		}
 	})
 	
 	t.Run("SlackChannel", func(t *testing.T) {
-		slackChannel := NewSlackChannel(logger)
+		slackChannel := NewSlackChannelWithConfig(nil, logger)
 		
 		if slackChannel.GetType() != "slack" {
 			t.Error("Slack channel type incorrect")
@@ -221,9 +236,102 @@ func TestNotificationChannels(t *testing.T) {
 		}
 		
 		err := slackChannel.Send(context.Background(), notification)
-		if err != nil {
+		if !errors.Is(err, ErrChannelNotConfigured) {
+			t.Fatalf("Expected ErrChannelNotConfigured, got %v", err)
+		}
+
+		var received map[string]interface{}
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			json.NewDecoder(r.Body).Decode(&received)
+			w.Write([]byte("ok"))
+		}))
+		defer server.Close()
+
+		slackChannel = NewSlackChannelWithConfig(&SlackChannelConfig{WebhookURL: server.URL}, logger)
+		notification.Data["personalized_content"] = &PersonalizedContent{
+			Summary:     "Short summary",
+			ActionItems: []string{"Review PR"},
+		}
+		if err := slackChannel.Send(context.Background(), notification); err != nil {
 			t.Fatalf("Failed to send Slack message: %v", err)
 		}
+
+		attachment := received["attachments"].([]interface{})[0].(map[string]interface{})
+		text := attachment["text"].(string)
+		if !strings.Contains(text, "Short summary") || !strings.Contains(text, "Review PR") {
+			t.Errorf("Personalized content not formatted: %q", text)
+		}
+	})
+
+	t.Run("SlackBotToken", func(t *testing.T) {
+		var channel, auth string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var payload map[string]interface{}
+			json.NewDecoder(r.Body).Decode(&payload)
+			channel, _ = payload["channel"].(string)
+			auth = r.Header.Get("Authorization")
+			if channel == "#missing" {
+				w.Write([]byte(`{"ok":false,"error":"channel_not_found"}`))
+				return
+			}
+			w.Write([]byte(`{"ok":true}`))
+		}))
+		defer server.Close()
+
+		slackChannel := NewSlackChannelWithConfig(&SlackChannelConfig{
+			BotToken: "xoxb-test",
+			APIURL:   server.URL,
+		}, logger)
+
+		notification := &Notification{
+			ID:         "notif-bot",
+			Title:      "Bot message",
+			Recipients: []string{"#dev"},
+			Data:       map[string]interface{}{},
+			Timestamp:  time.Now(),
+		}
+		if err := slackChannel.Send(context.Background(), notification); err != nil {
+			t.Fatalf("Failed to send via bot token: %v", err)
+		}
+		if channel != "#dev" || auth != "Bearer xoxb-test" {
+			t.Errorf("Unexpected request: channel=%q auth=%q", channel, auth)
+		}
+
+		// Slack отвечает 200 с ok=false - это должно быть ошибкой
+		notification.Recipients = []string{"#missing"}
+		if err := slackChannel.Send(context.Background(), notification); err == nil {
+			t.Error("Expected error for ok=false response")
+		}
+	})
+
+	t.Run("TeamsChannel", func(t *testing.T) {
+		status := http.StatusOK
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(status)
+		}))
+		defer server.Close()
+
+		notification := &Notification{
+			ID:        "notif-teams",
+			Title:     "Teams message",
+			Priority:  "low",
+			Data:      map[string]interface{}{},
+			Timestamp: time.Now(),
+		}
+
+		if err := NewTeamsChannelWithWebhook("", logger).Send(context.Background(), notification); !errors.Is(err, ErrChannelNotConfigured) {
+			t.Errorf("Expected ErrChannelNotConfigured, got %v", err)
+		}
+
+		teamsChannel := NewTeamsChannelWithWebhook(server.URL, logger)
+		if err := teamsChannel.Send(context.Background(), notification); err != nil {
+			t.Fatalf("Failed to send Teams message: %v", err)
+		}
+
+		status = http.StatusBadRequest
+		if err := teamsChannel.Send(context.Background(), notification); err == nil {
+			t.Error("Expected error for failed delivery")
+		}
 	})
 	
 	t.Run("WebhookChannel", func(t *testing.T) {
@@ -232,6 +340,11 @@ func TestNotificationChannels(t *testing.T) {
 		if webhookChannel.GetType() != "webhook" {
 			t.Error("Webhook channel type incorrect")
 		}
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer server.Close()
 		
 		notification := &Notification{
 			ID:        "notif3",
@@ -241,7 +354,7 @@ func TestNotificationChannels(t *testing.T) {
 			Priority:  "low",
 			Recipients: []string{"webhook-service"},
 			Data:      map[string]interface{}{
-				"webhook_url": "https://example.com/webhook",
+				"webhook_url": server.URL,
 			},
 			Timestamp: time.Now(),
 		}
@@ -253,6 +366,670 @@ func TestNotificationChannels(t *testing.T) {
 	})
 }
 
+// TestWebhookChannelConfig тестирует шаблоны, подпись и повторы webhook канала
+func TestWebhookChannelConfig(t *testing.T) {
+	logger := &MockLogger{}
+
+	t.Run("InvalidTemplate", func(t *testing.T) {
+		for _, tmpl := range []string{`{"summary": {{.Title}`, `{"summary": "{{.NoSuchField}}"}`} {
+			_, err := NewWebhookChannelWithConfig(&WebhookChannelConfig{
+				URL:             "http://localhost",
+				PayloadTemplate: tmpl,
+			}, logger)
+			if err == nil {
+				t.Errorf("Expected template %q to be rejected", tmpl)
+			}
+		}
+	})
+
+	t.Run("TemplateHeadersAndSignature", func(t *testing.T) {
+		var body []byte
+		var headers http.Header
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ = io.ReadAll(r.Body)
+			headers = r.Header
+			w.WriteHeader(http.StatusAccepted)
+		}))
+		defer server.Close()
+
+		channel, err := NewWebhookChannelWithConfig(&WebhookChannelConfig{
+			URL:             server.URL,
+			PayloadTemplate: `{"routing_key":"abc","event_action":"trigger","payload":{"summary":{{json .Title}},"severity":"{{.Priority}}","urgency":"{{.Urgency}}"}}`,
+			Headers:         map[string]string{"X-Routing": "oncall"},
+			Secret:          "s3cret",
+		}, logger)
+		if err != nil {
+			t.Fatalf("Failed to configure webhook: %v", err)
+		}
+
+		smart := &SmartNotification{
+			Notification: &Notification{
+				ID:        "n1",
+				Title:     `Task "build" failed`,
+				Priority:  "critical",
+				Data:      map[string]interface{}{},
+				Timestamp: time.Now(),
+			},
+			Priority: "critical",
+			Urgency:  "high",
+		}
+		engine := NewSmartNotificationEngine(nil, logger)
+		if err := channel.Send(context.Background(), engine.prepareForChannel(smart, "webhook")); err != nil {
+			t.Fatalf("Failed to send webhook: %v", err)
+		}
+
+		var payload map[string]interface{}
+		if err := json.Unmarshal(body, &payload); err != nil {
+			t.Fatalf("Rendered payload is not valid JSON: %v (%s)", err, body)
+		}
+		inner := payload["payload"].(map[string]interface{})
+		if inner["summary"] != `Task "build" failed` || inner["urgency"] != "high" {
+			t.Errorf("Unexpected rendered payload: %s", body)
+		}
+
+		if headers.Get("X-Routing") != "oncall" {
+			t.Error("Custom header not sent")
+		}
+
+		mac := hmac.New(sha256.New, []byte("s3cret"))
+		mac.Write(body)
+		expected := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+		if headers.Get("X-Ricochet-Signature") != expected {
+			t.Errorf("Invalid signature: %s", headers.Get("X-Ricochet-Signature"))
+		}
+	})
+
+	t.Run("RetryOn5xx", func(t *testing.T) {
+		attempts := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			attempts++
+			if attempts < 3 {
+				w.WriteHeader(http.StatusBadGateway)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+
+		channel, _ := NewWebhookChannelWithConfig(&WebhookChannelConfig{
+			URL:          server.URL,
+			MaxRetries:   3,
+			RetryBackoff: time.Millisecond,
+		}, logger)
+
+		notification := &Notification{ID: "n2", Data: map[string]interface{}{}, Timestamp: time.Now()}
+		if err := channel.Send(context.Background(), notification); err != nil {
+			t.Fatalf("Expected delivery after retries, got %v", err)
+		}
+		if attempts != 3 {
+			t.Errorf("Expected 3 attempts, got %d", attempts)
+		}
+	})
+
+	t.Run("RetriesFromEnv", func(t *testing.T) {
+		attempts := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			attempts++
+			if attempts < 3 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+		t.Setenv("RICOCHET_WEBHOOK_URL", server.URL)
+		t.Setenv("RICOCHET_WEBHOOK_RETRY_BACKOFF", "1ms")
+
+		// Без RICOCHET_WEBHOOK_MAX_RETRIES канал повторяет запрос по умолчанию
+		channel, err := NewChannelFromEnv("webhook", logger)
+		if err != nil {
+			t.Fatalf("Failed to configure webhook from env: %v", err)
+		}
+		notification := &Notification{ID: "n4", Data: map[string]interface{}{}, Timestamp: time.Now()}
+		if err := channel.Send(context.Background(), notification); err != nil {
+			t.Fatalf("Expected delivery after retries, got %v", err)
+		}
+		if attempts != 3 {
+			t.Errorf("Expected 3 attempts, got %d", attempts)
+		}
+
+		attempts = 0
+		t.Setenv("RICOCHET_WEBHOOK_MAX_RETRIES", "0")
+		channel, err = NewChannelFromEnv("webhook", logger)
+		if err != nil {
+			t.Fatalf("Failed to configure webhook from env: %v", err)
+		}
+		if err := channel.Send(context.Background(), notification); err == nil {
+			t.Error("Expected error without retries")
+		}
+		if attempts != 1 {
+			t.Errorf("Expected a single attempt with retries disabled, got %d", attempts)
+		}
+
+		for name, value := range map[string]string{
+			"RICOCHET_WEBHOOK_MAX_RETRIES":   "-1",
+			"RICOCHET_WEBHOOK_RETRY_BACKOFF": "soon",
+		} {
+			t.Run(name, func(t *testing.T) {
+				t.Setenv(name, value)
+				if _, err := WebhookChannelConfigFromEnv(); err == nil || !strings.Contains(err.Error(), name) {
+					t.Errorf("Expected %s=%q to be rejected, got %v", name, value, err)
+				}
+			})
+		}
+	})
+
+	t.Run("NoRetryOn4xx", func(t *testing.T) {
+		attempts := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			attempts++
+			w.WriteHeader(http.StatusBadRequest)
+		}))
+		defer server.Close()
+
+		channel, _ := NewWebhookChannelWithConfig(&WebhookChannelConfig{
+			URL:          server.URL,
+			MaxRetries:   3,
+			RetryBackoff: time.Millisecond,
+		}, logger)
+
+		notification := &Notification{ID: "n3", Data: map[string]interface{}{}, Timestamp: time.Now()}
+		if err := channel.Send(context.Background(), notification); err == nil {
+			t.Error("Expected error for 400 response")
+		}
+		if attempts != 1 {
+			t.Errorf("Expected a single attempt, got %d", attempts)
+		}
+	})
+}
+
+// captureChannel канал, запоминающий отправленные уведомления
+type captureChannel struct {
+	sent []*Notification
+}
+
+func (c *captureChannel) GetType() string { return "capture" }
+
+func (c *captureChannel) Send(ctx context.Context, notification *Notification) error {
+	c.sent = append(c.sent, notification)
+	return nil
+}
+
+// TestNotificationDigest тестирует группировку уведомлений в дайджест
+func TestNotificationDigest(t *testing.T) {
+	logger := &MockLogger{}
+	storagePath := filepath.Join(t.TempDir(), "digests.json")
+
+	engine := NewSmartNotificationEngine(nil, logger)
+	engine.SetDigestAggregator(NewDigestAggregator(&DigestConfig{StoragePath: storagePath}, logger))
+	capture := &captureChannel{}
+	engine.RegisterChannel(capture)
+	engine.rules = append(engine.rules, &NotificationRule{Event: "task_updated"})
+
+	err := engine.Subscribe(context.Background(), &NotificationSubscriber{
+		ID:     "sub-digest",
+		UserID: "digest-user",
+		Preferences: &NotificationPrefs{
+			Channels:     []string{"capture"},
+			Frequency:    FrequencyBatched,
+			GroupSimilar: true,
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to subscribe: %v", err)
+	}
+
+	event := func(taskID, priority string) *WorkflowEvent {
+		return &WorkflowEvent{
+			Type:      "task_updated",
+			Timestamp: time.Now(),
+			Data:      map[string]interface{}{"task_id": taskID, "priority": priority},
+		}
+	}
+
+	t.Run("BatchAndGroup", func(t *testing.T) {
+		for i := 0; i < 3; i++ {
+			engine.ProcessEvent(context.Background(), event("TASK-1", "medium"))
+		}
+		engine.ProcessEvent(context.Background(), event("TASK-2", "medium"))
+
+		if len(capture.sent) != 0 {
+			t.Fatalf("Batched notifications must not be sent immediately, got %d", len(capture.sent))
+		}
+
+		digest := engine.digests.Pending("digest-user")
+		if digest == nil {
+			t.Fatal("Digest not created")
+		}
+		if len(digest.Items) != 2 || digest.Total() != 4 {
+			t.Errorf("Expected 2 groups with 4 notifications, got %d groups, %d total", len(digest.Items), digest.Total())
+		}
+	})
+
+	t.Run("CriticalBypassesBatching", func(t *testing.T) {
+		engine.ProcessEvent(context.Background(), event("TASK-3", "critical"))
+		if len(capture.sent) != 1 {
+			t.Errorf("Critical notification should be sent immediately, sent %d", len(capture.sent))
+		}
+		capture.sent = nil
+	})
+
+	t.Run("PersistAcrossRestarts", func(t *testing.T) {
+		restored := NewDigestAggregator(&DigestConfig{StoragePath: storagePath}, logger)
+		digest := restored.Pending("digest-user")
+		if digest == nil || digest.Total() != 4 {
+			t.Fatalf("Pending digest not restored: %+v", digest)
+		}
+	})
+
+	t.Run("FlushAtWindowBoundary", func(t *testing.T) {
+		if err := engine.FlushDigests(context.Background(), time.Now()); err != nil {
+			t.Fatalf("Flush failed: %v", err)
+		}
+		if len(capture.sent) != 0 {
+			t.Fatal("Digest sent before window boundary")
+		}
+
+		if err := engine.FlushDigests(context.Background(), time.Now().Add(2*time.Hour)); err != nil {
+			t.Fatalf("Flush failed: %v", err)
+		}
+		if len(capture.sent) != 1 {
+			t.Fatalf("Expected one combined digest, got %d", len(capture.sent))
+		}
+		if !strings.Contains(capture.sent[0].Title, "4 notifications") || !strings.Contains(capture.sent[0].Message, "(x3)") {
+			t.Errorf("Unexpected digest content: %q / %q", capture.sent[0].Title, capture.sent[0].Message)
+		}
+		if engine.digests.Pending("digest-user") != nil {
+			t.Error("Digest should be cleared after delivery")
+		}
+	})
+
+	t.Run("DailyWindow", func(t *testing.T) {
+		da := NewDigestAggregator(nil, logger)
+		now := time.Date(2024, 3, 6, 15, 0, 0, 0, time.UTC) // среда
+		if next := da.nextDelivery(FrequencyDaily, now); !next.Equal(time.Date(2024, 3, 7, 9, 0, 0, 0, time.UTC)) {
+			t.Errorf("Unexpected daily delivery: %s", next)
+		}
+		if next := da.nextDelivery(FrequencyWeekly, now); !next.Equal(time.Date(2024, 3, 11, 9, 0, 0, 0, time.UTC)) {
+			t.Errorf("Unexpected weekly delivery: %s", next)
+		}
+	})
+}
+
+// flakyChannel канал, который возвращает err, пока он задан
+type flakyChannel struct {
+	err  error
+	sent []*Notification
+}
+
+func (c *flakyChannel) GetType() string { return "flaky" }
+
+func (c *flakyChannel) Send(ctx context.Context, notification *Notification) error {
+	if c.err != nil {
+		return c.err
+	}
+	c.sent = append(c.sent, notification)
+	return nil
+}
+
+// TestFailedNotificationRetry тестирует повтор неудачных доставок
+func TestFailedNotificationRetry(t *testing.T) {
+	logger := &MockLogger{}
+	storagePath := filepath.Join(t.TempDir(), "failed.json")
+	config := &RetryConfig{InitialBackoff: time.Minute, MaxBackoff: 4 * time.Minute, MaxAttempts: 5, StoragePath: storagePath}
+
+	engine := NewSmartNotificationEngine(nil, logger)
+	engine.SetDigestAggregator(nil)
+	engine.SetFailedQueue(NewFailedNotificationQueue(config, logger))
+	flaky := &flakyChannel{err: errors.New("connection refused")}
+	engine.RegisterChannel(flaky)
+
+	notification := &SmartNotification{
+		Notification: &Notification{ID: "n1", Title: "Task updated", Recipients: []string{"alice"}, Data: map[string]interface{}{}},
+		OptimalChannels: []string{"flaky"},
+		OptimalTiming:   &OptimalTiming{},
+		Context:         &NotificationContext{Event: &WorkflowEvent{Type: "task_updated"}},
+	}
+	if err := engine.sendSmartNotification(context.Background(), notification); err == nil {
+		t.Fatal("Expected delivery error")
+	}
+
+	failed := engine.FailedDeliveries()
+	if len(failed) != 1 || failed[0].Channel != "flaky" || failed[0].Permanent {
+		t.Fatalf("Expected one transient failure, got %+v", failed)
+	}
+
+	t.Run("PersistAcrossRestarts", func(t *testing.T) {
+		restored := NewFailedNotificationQueue(config, logger).List()
+		if len(restored) != 1 || restored[0].Notification.Title != "Task updated" {
+			t.Fatalf("Failed delivery not restored: %+v", restored)
+		}
+	})
+
+	t.Run("Backoff", func(t *testing.T) {
+		now := time.Now()
+		report, err := engine.RetryFailed(context.Background(), now, RetryOptions{})
+		if err != nil || len(report.Failed)+len(report.Delivered) != 0 {
+			t.Fatalf("Retried before the backoff ended: %+v, %v", report, err)
+		}
+
+		report, err = engine.RetryFailed(context.Background(), now.Add(2*time.Minute), RetryOptions{})
+		if err != nil || len(report.Failed) != 1 {
+			t.Fatalf("Expected a failed retry: %+v, %v", report, err)
+		}
+		if next := report.Failed[0].NextAttemptAt.Sub(now.Add(2 * time.Minute)); next != 2*time.Minute {
+			t.Errorf("Expected the backoff to double, got %s", next)
+		}
+		if metrics := engine.analytics.GetMetrics(); metrics.TotalRetries != 1 || metrics.RetryDelivered != 0 {
+			t.Errorf("Unexpected retry metrics: %+v", metrics)
+		}
+	})
+
+	t.Run("DeliverOnRetry", func(t *testing.T) {
+		flaky.err = nil
+		report, err := engine.RetryFailed(context.Background(), time.Now(), RetryOptions{Force: true})
+		if err != nil || len(report.Delivered) != 1 || report.Remaining != 0 {
+			t.Fatalf("Expected delivery on retry: %+v, %v", report, err)
+		}
+		if len(flaky.sent) != 1 || flaky.sent[0].ID != "n1" {
+			t.Errorf("Unexpected sent notifications: %+v", flaky.sent)
+		}
+		if metrics := engine.analytics.GetMetrics(); metrics.RetryDelivered != 1 {
+			t.Errorf("Retry delivery not recorded: %+v", metrics)
+		}
+	})
+
+	t.Run("PermanentFailure", func(t *testing.T) {
+		flaky.err = NewPermanentDeliveryError(errors.New("channel_not_found"))
+		notification.ID = "n2"
+		engine.sendSmartNotification(context.Background(), notification)
+
+		report, err := engine.RetryFailed(context.Background(), time.Now().Add(time.Hour), RetryOptions{Force: true})
+		if err != nil || len(report.Failed)+len(report.Delivered) != 0 || report.Remaining != 1 {
+			t.Fatalf("Permanent failures must not be retried: %+v, %v", report, err)
+		}
+
+		flaky.err = nil
+		report, err = engine.RetryFailed(context.Background(), time.Now(), RetryOptions{IncludePermanent: true})
+		if err != nil || len(report.Delivered) != 1 || report.Remaining != 0 {
+			t.Fatalf("Expected permanent failure to be retried on request: %+v, %v", report, err)
+		}
+	})
+
+	t.Run("ClassifyErrors", func(t *testing.T) {
+		for _, err := range []error{
+			fmt.Errorf("slack: %w", ErrChannelNotConfigured),
+			statusError(http.StatusNotFound, errors.New("status 404")),
+			fmt.Errorf("email: %w", NewPermanentDeliveryError(errors.New("RCPT TO rejected"))),
+		} {
+			if !IsPermanentDeliveryError(err) {
+				t.Errorf("Expected a permanent error: %v", err)
+			}
+		}
+		for _, err := range []error{
+			errors.New("connection refused"),
+			statusError(http.StatusTooManyRequests, errors.New("status 429")),
+			statusError(http.StatusBadGateway, errors.New("status 502")),
+		} {
+			if IsPermanentDeliveryError(err) {
+				t.Errorf("Expected a transient error: %v", err)
+			}
+		}
+	})
+}
+
+// TestNotifyWatchers тестирует уведомление наблюдателей задачи
+func TestNotifyWatchers(t *testing.T) {
+	logger := &MockLogger{}
+	engine := NewSmartNotificationEngine(nil, logger)
+	engine.SetDigestAggregator(nil)
+	capture := &captureChannel{}
+	engine.RegisterChannel(capture)
+
+	err := engine.Subscribe(context.Background(), &NotificationSubscriber{
+		ID:          "sub-watcher",
+		UserID:      "watcher",
+		Preferences: &NotificationPrefs{Channels: []string{"capture"}},
+	})
+	if err != nil {
+		t.Fatalf("Failed to subscribe: %v", err)
+	}
+
+	event := &providers.UniversalEvent{
+		Type:   providers.EventTypeTaskStatusChanged,
+		Source: "youtrack",
+		TaskID: "PROJ-42",
+		Data:   map[string]interface{}{"status": "Blocked", "actor": "alice"},
+	}
+
+	if err := engine.NotifyWatchers(context.Background(), []string{"watcher", "unsubscribed"}, event); err != nil {
+		t.Fatalf("NotifyWatchers failed: %v", err)
+	}
+
+	if len(capture.sent) != 1 {
+		t.Fatalf("Expected 1 notification, got %d", len(capture.sent))
+	}
+	if !strings.Contains(capture.sent[0].Title, "PROJ-42") {
+		t.Errorf("Title should mention the task: %q", capture.sent[0].Title)
+	}
+	if !strings.Contains(capture.sent[0].Message, "Blocked") {
+		t.Errorf("Message should include the new status: %q", capture.sent[0].Message)
+	}
+}
+
+// TestConnectTaskWatchers тестирует доставку событий шины провайдеров наблюдателям задачи
+func TestConnectTaskWatchers(t *testing.T) {
+	t.Run("WatchChannelsFromEnv", func(t *testing.T) {
+		t.Setenv("RICOCHET_WATCH_CHANNELS", " slack, ,webhook")
+
+		config := DeliveryConfigFromEnv()
+		if fmt.Sprint(config.WatchChannels) != "[slack webhook]" {
+			t.Errorf("Expected watch channels [slack webhook], got %v", config.WatchChannels)
+		}
+	})
+
+	t.Run("DeliversThroughEventBus", func(t *testing.T) {
+		engine := NewSmartNotificationEngine(nil, &MockLogger{})
+		engine.SetDigestAggregator(nil)
+		engine.SetFailedQueue(nil)
+		engine.SetDeliveryConfig(&DeliveryConfig{WatchChannels: []string{"capture"}})
+		capture := &captureChannel{}
+		engine.RegisterChannel(capture)
+
+		watchers, err := providers.NewTaskWatchRegistry("", nil)
+		if err != nil {
+			t.Fatalf("Failed to create watcher registry: %v", err)
+		}
+		for _, userID := range []string{"alice", "bob"} {
+			if err := watchers.WatchTask(context.Background(), "PROJ-7", userID); err != nil {
+				t.Fatalf("Failed to watch task: %v", err)
+			}
+		}
+
+		bus := providers.NewEventBus(0, nil)
+		if err := ConnectTaskWatchers(bus, watchers, engine); err != nil {
+			t.Fatalf("ConnectTaskWatchers failed: %v", err)
+		}
+
+		bus.Publish(&providers.UniversalEvent{
+			Type:   providers.EventTypeTaskStatusChanged,
+			Source: "youtrack",
+			TaskID: "PROJ-7",
+			Data:   map[string]interface{}{"status": "Done", "actor": "alice"},
+		})
+		// Close дожидается доставки событий из очереди
+		if err := bus.Close(context.Background()); err != nil {
+			t.Fatalf("Failed to close event bus: %v", err)
+		}
+
+		if len(capture.sent) != 1 {
+			t.Fatalf("Expected 1 notification, got %d", len(capture.sent))
+		}
+		if recipients := capture.sent[0].Recipients; len(recipients) != 1 || recipients[0] != "bob" {
+			t.Errorf("Only the watcher who didn't make the change should be notified: %v", recipients)
+		}
+		if !strings.Contains(capture.sent[0].Title, "PROJ-7") {
+			t.Errorf("Title should mention the task: %q", capture.sent[0].Title)
+		}
+	})
+	t.Run("DefaultsToConfiguredChannels", func(t *testing.T) {
+		engine := NewSmartNotificationEngine(nil, &MockLogger{})
+		engine.SetDigestAggregator(nil)
+		engine.SetFailedQueue(NewFailedNotificationQueue(&RetryConfig{}, &MockLogger{}))
+		engine.SetDeliveryConfig(&DeliveryConfig{})
+		// Стандартные каналы без настроек пропускаются
+		engine.RegisterChannel(NewSlackChannelWithConfig(nil, &MockLogger{}))
+		engine.RegisterChannel(NewTeamsChannelWithWebhook("", &MockLogger{}))
+		capture := &captureChannel{}
+		engine.RegisterChannel(capture)
+
+		err := engine.NotifyWatchers(context.Background(), []string{"bob"}, &providers.UniversalEvent{
+			Type:   providers.EventTypeTaskUpdated,
+			Source: "youtrack",
+			TaskID: "PROJ-8",
+		})
+		if err != nil {
+			t.Fatalf("NotifyWatchers failed: %v", err)
+		}
+
+		if len(capture.sent) != 1 {
+			t.Fatalf("Expected 1 notification through the configured channel, got %d", len(capture.sent))
+		}
+		if failed := engine.FailedDeliveries(); len(failed) != 0 {
+			t.Errorf("Unconfigured channels should not be tried: %v", failed)
+		}
+	})
+}
+
+// deliveryChannel канал с заданным типом, который считает одновременные отправки
+type deliveryChannel struct {
+	channelType string
+	err         error
+	delay       time.Duration
+	inFlight    *int32
+	maxInFlight *int32
+	mutex       sync.Mutex
+	sent        int
+}
+
+func (c *deliveryChannel) GetType() string { return c.channelType }
+
+func (c *deliveryChannel) Send(ctx context.Context, notification *Notification) error {
+	if c.inFlight != nil {
+		current := atomic.AddInt32(c.inFlight, 1)
+		defer atomic.AddInt32(c.inFlight, -1)
+		for {
+			max := atomic.LoadInt32(c.maxInFlight)
+			if current <= max || atomic.CompareAndSwapInt32(c.maxInFlight, max, current) {
+				break
+			}
+		}
+	}
+	time.Sleep(c.delay)
+	if c.err != nil {
+		return c.err
+	}
+	c.mutex.Lock()
+	c.sent++
+	c.mutex.Unlock()
+	return nil
+}
+
+// TestNotificationDelivery тестирует параллельную и резервную доставку по каналам
+func TestNotificationDelivery(t *testing.T) {
+	newEngine := func(config *DeliveryConfig, channels ...NotificationChannel) *SmartNotificationEngine {
+		engine := NewSmartNotificationEngine(nil, &MockLogger{})
+		engine.SetDigestAggregator(nil)
+		engine.SetFailedQueue(NewFailedNotificationQueue(&RetryConfig{}, &MockLogger{}))
+		engine.SetDeliveryConfig(config)
+		for _, channel := range channels {
+			engine.RegisterChannel(channel)
+		}
+		return engine
+	}
+	newNotification := func(priority string, channels ...string) *SmartNotification {
+		return &SmartNotification{
+			Notification:    &Notification{ID: "n1", Title: "Build failed", Recipients: []string{"alice"}, Data: map[string]interface{}{}},
+			Priority:        priority,
+			OptimalChannels: channels,
+			OptimalTiming:   &OptimalTiming{},
+		}
+	}
+
+	t.Run("Routes", func(t *testing.T) {
+		config := &DeliveryConfig{Fallbacks: map[string][]string{"critical": {"slack", "sms", "pager"}}}
+		routes := config.deliveryRoutes("critical", []string{"email", "sms", "slack"})
+		want := [][]string{{"email"}, {"slack", "sms"}}
+		if fmt.Sprint(routes) != fmt.Sprint(want) {
+			t.Errorf("Expected routes %v, got %v", want, routes)
+		}
+		if routes := config.deliveryRoutes("low", []string{"email", "sms"}); len(routes) != 2 {
+			t.Errorf("Channels without a fallback chain are separate routes: %v", routes)
+		}
+	})
+
+	t.Run("FallbackOnlyOnFailure", func(t *testing.T) {
+		slack := &deliveryChannel{channelType: "slack"}
+		sms := &deliveryChannel{channelType: "sms"}
+		engine := newEngine(&DeliveryConfig{Fallbacks: map[string][]string{"critical": {"slack", "sms"}}}, slack, sms)
+
+		notification := newNotification("critical", "slack", "sms")
+		if err := engine.sendSmartNotification(context.Background(), notification); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if slack.sent != 1 || sms.sent != 0 {
+			t.Errorf("SMS must not be used when Slack delivers: slack=%d sms=%d", slack.sent, sms.sent)
+		}
+		if fmt.Sprint(notification.DeliveredVia) != "[slack]" {
+			t.Errorf("Expected delivery via slack, got %v", notification.DeliveredVia)
+		}
+
+		slack.err = errors.New("connection refused")
+		if err := engine.sendSmartNotification(context.Background(), notification); err != nil {
+			t.Fatalf("A delivered fallback is not an error: %v", err)
+		}
+		if sms.sent != 1 || fmt.Sprint(notification.DeliveredVia) != "[sms]" {
+			t.Errorf("Expected delivery via sms, got %v", notification.DeliveredVia)
+		}
+		if failed := engine.FailedDeliveries(); len(failed) != 0 {
+			t.Errorf("A delivered fallback must not be retried: %+v", failed)
+		}
+
+		sms.err = errors.New("gateway timeout")
+		if err := engine.sendSmartNotification(context.Background(), notification); err == nil {
+			t.Fatal("Expected an error when the whole chain fails")
+		}
+		if failed := engine.FailedDeliveries(); len(failed) != 1 || failed[0].Channel != "slack" {
+			t.Errorf("Expected the first channel of the chain to be retried: %+v", failed)
+		}
+	})
+
+	t.Run("BoundedConcurrency", func(t *testing.T) {
+		for _, concurrency := range []int{1, 3} {
+			var inFlight, maxInFlight int32
+			var channels []NotificationChannel
+			var types []string
+			for i := 0; i < 5; i++ {
+				channelType := fmt.Sprintf("channel-%d", i)
+				channels = append(channels, &deliveryChannel{channelType: channelType, delay: 20 * time.Millisecond, inFlight: &inFlight, maxInFlight: &maxInFlight})
+				types = append(types, channelType)
+			}
+			engine := newEngine(&DeliveryConfig{Concurrency: concurrency}, channels...)
+
+			notification := newNotification("high", types...)
+			if err := engine.sendSmartNotification(context.Background(), notification); err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if len(notification.DeliveredVia) != 5 {
+				t.Errorf("Expected delivery via all channels, got %v", notification.DeliveredVia)
+			}
+			if got := atomic.LoadInt32(&maxInFlight); got > int32(concurrency) || (concurrency > 1 && got < 2) {
+				t.Errorf("Concurrency %d: %d sends in flight", concurrency, got)
+			}
+		}
+	})
+}
+
 // TestNotificationAnalytics тестирует аналитику уведомлений
 func TestNotificationAnalytics(t *testing.T) {
 	logger := &MockLogger{}
@@ -602,4 +1379,56 @@ func BenchmarkTemplateRendering(b *testing.B) {
 	for i := 0; i < b.N; i++ {
 		templates.RenderTemplate("task_assigned_body", data)
 	}
-}
\ No newline at end of file
+}
+// TestNotificationPreview тестирует примеры уведомлений и их отрисовку каналами
+func TestNotificationPreview(t *testing.T) {
+	logger := &MockLogger{}
+
+	notification, err := SampleWatchNotification(providers.EventTypeTaskCreated, "alice")
+	if err != nil {
+		t.Fatalf("Failed to build sample notification: %v", err)
+	}
+	if !strings.Contains(notification.Title, "OPS-123") || !strings.Contains(notification.Message, "Rotate TLS certificates") {
+		t.Errorf("Sample notification not rendered: %q / %q", notification.Title, notification.Message)
+	}
+
+	if _, err := SampleWatchNotification("task.exploded", "alice"); !providers.IsErrorType(err, providers.ErrorTypeValidation) {
+		t.Errorf("Expected validation error for unknown event, got %v", err)
+	}
+	if _, err := NewChannelFromEnv("pigeon", logger); !providers.IsErrorType(err, providers.ErrorTypeValidation) {
+		t.Errorf("Expected validation error for unknown channel, got %v", err)
+	}
+
+	slack := NewSlackChannelWithConfig(&SlackChannelConfig{BotToken: "xoxb", DefaultChannel: "#ops"}, logger)
+	preview, err := PreviewChannel(slack, notification)
+	if err != nil {
+		t.Fatalf("Failed to preview Slack message: %v", err)
+	}
+	if preview.Target != "#ops" {
+		t.Errorf("Expected default channel as target, got %q", preview.Target)
+	}
+	if payload := preview.Payload.(map[string]interface{}); payload["text"] != notification.Title {
+		t.Errorf("Slack payload should carry the title: %v", payload)
+	}
+
+	webhook, err := NewWebhookChannelWithConfig(&WebhookChannelConfig{
+		URL:             "https://hooks.example.com",
+		PayloadTemplate: `{"text": {{json .Title}}}`,
+	}, logger)
+	if err != nil {
+		t.Fatalf("Failed to create webhook channel: %v", err)
+	}
+	preview, err = PreviewChannel(webhook, notification)
+	if err != nil {
+		t.Fatalf("Failed to preview webhook payload: %v", err)
+	}
+	if payload := preview.Payload.(map[string]interface{}); payload["text"] != notification.Title {
+		t.Errorf("Webhook payload not rendered from template: %v", payload)
+	}
+
+	// Без настроек канал показывает сообщение, но не адрес доставки
+	preview, err = PreviewChannel(NewTeamsChannelWithWebhook("", logger), notification)
+	if err != nil || preview.Target != "" {
+		t.Errorf("Expected preview without target, got %+v, %v", preview, err)
+	}
+}
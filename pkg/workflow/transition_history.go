@@ -0,0 +1,149 @@
+package workflow
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	appconfig "github.com/grik-ai/ricochet-task/internal/config"
+)
+
+// TransitionRecord запись о переходе задачи между стадиями
+type TransitionRecord struct {
+	WorkflowID string    `json:"wf"`
+	TaskID     string    `json:"task"`
+	FromStage  string    `json:"from"`
+	ToStage    string    `json:"to"`
+	Actor      string    `json:"by,omitempty"`
+	Reason     string    `json:"reason,omitempty"`
+	Timestamp  time.Time `json:"at"`
+}
+
+// TransitionFilter фильтр для выборки истории переходов.
+// Пустые поля не ограничивают выборку.
+type TransitionFilter struct {
+	TaskID string
+	Since  time.Time
+	Until  time.Time
+}
+
+// Matches проверяет соответствие записи фильтру
+func (f TransitionFilter) Matches(record *TransitionRecord) bool {
+	if f.TaskID != "" && record.TaskID != f.TaskID {
+		return false
+	}
+	if !f.Since.IsZero() && record.Timestamp.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && record.Timestamp.After(f.Until) {
+		return false
+	}
+	return true
+}
+
+// TransitionLog хранилище истории переходов
+type TransitionLog interface {
+	Record(record *TransitionRecord) error
+	Query(filter TransitionFilter) ([]*TransitionRecord, error)
+}
+
+// FileTransitionLog журнал переходов в формате JSON Lines.
+// Записи только дописываются в конец файла, одна строка на переход.
+type FileTransitionLog struct {
+	path   string
+	logger Logger
+	mutex  sync.RWMutex
+}
+
+// DefaultTransitionLogPath возвращает путь к журналу переходов в директории активного профиля
+func DefaultTransitionLogPath() string {
+	return appconfig.ProfilePath("workflow_history.jsonl")
+}
+
+// NewFileTransitionLog создает файловый журнал переходов
+func NewFileTransitionLog(path string, logger Logger) *FileTransitionLog {
+	if path == "" {
+		path = DefaultTransitionLogPath()
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		logger.Error("Failed to create transition log directory", err, "path", path)
+	}
+
+	return &FileTransitionLog{
+		path:   path,
+		logger: logger,
+	}
+}
+
+// Record дописывает запись в журнал
+func (l *FileTransitionLog) Record(record *TransitionRecord) error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal transition record: %w", err)
+	}
+
+	file, err := os.OpenFile(l.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open transition log: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write transition record: %w", err)
+	}
+
+	return nil
+}
+
+// Query возвращает записи, подходящие под фильтр, в хронологическом порядке
+func (l *FileTransitionLog) Query(filter TransitionFilter) ([]*TransitionRecord, error) {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+
+	file, err := os.Open(l.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []*TransitionRecord{}, nil
+		}
+		return nil, fmt.Errorf("failed to open transition log: %w", err)
+	}
+	defer file.Close()
+
+	records := []*TransitionRecord{}
+	scanner := bufio.NewScanner(file)
+	line := 0
+	for scanner.Scan() {
+		line++
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		var record TransitionRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			l.logger.Warn("Skipping malformed transition record", "path", l.path, "line", line)
+			continue
+		}
+
+		if filter.Matches(&record) {
+			records = append(records, &record)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read transition log: %w", err)
+	}
+
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].Timestamp.Before(records[j].Timestamp)
+	})
+
+	return records, nil
+}
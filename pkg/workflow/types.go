@@ -280,6 +280,12 @@ type NotificationChannel interface {
 	GetType() string
 }
 
+// ConfigurableChannel канал, который может быть не настроен. Каналы без этого
+// метода считаются настроенными.
+type ConfigurableChannel interface {
+	Configured() bool
+}
+
 // Notification уведомление
 type Notification struct {
 	ID        string                 `json:"id"`
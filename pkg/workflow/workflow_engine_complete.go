@@ -2,13 +2,23 @@ package workflow
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sort"
 	"sync"
 	"time"
 
 	"github.com/grik-ai/ricochet-task/pkg/ai"
 )
 
+// Ошибки управления workflow
+var (
+	ErrWorkflowNotFound     = errors.New("workflow not found")
+	ErrTaskNotFound         = errors.New("task not found")
+	ErrInvalidStage         = errors.New("invalid stage")
+	ErrInvalidWorkflowState = errors.New("invalid workflow state")
+)
+
 // CompleteWorkflowEngine полная реализация движка workflow с всеми компонентами
 type CompleteWorkflowEngine struct {
 	// Основные компоненты
@@ -18,10 +28,12 @@ type CompleteWorkflowEngine struct {
 	autoAssignment   *AutoAssignment
 	notifications    *SmartNotificationEngine
 	mcpIntegration   *MCPIntegration
+	history          TransitionLog
 
 	// Системы
 	workflows        map[string]*WorkflowDefinition
 	runningWorkflows map[string]*WorkflowInstance
+	cancels          map[string]context.CancelFunc
 	logger           Logger
 	aiChains         *ai.AIChains
 	config           *CompleteEngineConfig
@@ -41,6 +53,7 @@ type CompleteEngineConfig struct {
 	DefaultTimeout      time.Duration          `json:"default_timeout"`
 	EnableMetrics       bool                   `json:"enable_metrics"`
 	EnableAuditLog      bool                   `json:"enable_audit_log"`
+	AuditLogPath        string                 `json:"audit_log_path"` // пусто - workflow_history.jsonl в директории профиля
 }
 
 // WorkflowMetrics метрики workflow
@@ -68,6 +81,14 @@ type TaskMetrics struct {
 
 // NewCompleteWorkflowEngine создает полный движок workflow
 func NewCompleteWorkflowEngine(aiChains *ai.AIChains, config *CompleteEngineConfig, logger Logger) (*CompleteWorkflowEngine, error) {
+	return NewCompleteWorkflowEngineWithNotifications(aiChains, config, nil, logger)
+}
+
+// NewCompleteWorkflowEngineWithNotifications создает полный движок workflow,
+// который доставляет уведомления через notifications. Так движок делит
+// дайджесты и очередь неудачных доставок с другими частями процесса; nil -
+// движок создает собственный.
+func NewCompleteWorkflowEngineWithNotifications(aiChains *ai.AIChains, config *CompleteEngineConfig, notifications *SmartNotificationEngine, logger Logger) (*CompleteWorkflowEngine, error) {
 	if config == nil {
 		config = GetDefaultCompleteConfig()
 	}
@@ -75,9 +96,11 @@ func NewCompleteWorkflowEngine(aiChains *ai.AIChains, config *CompleteEngineConf
 	engine := &CompleteWorkflowEngine{
 		workflows:        make(map[string]*WorkflowDefinition),
 		runningWorkflows: make(map[string]*WorkflowInstance),
+		cancels:          make(map[string]context.CancelFunc),
 		logger:           logger,
 		aiChains:         aiChains,
 		config:           config,
+		notifications:    notifications,
 	}
 
 	// Инициализируем компоненты
@@ -114,10 +137,17 @@ func (cwe *CompleteWorkflowEngine) initializeComponents() error {
 	cwe.autoAssignment = NewAutoAssignment(cwe.aiChains, cwe.eventBus, cwe.config.AutoAssignmentConfig, cwe.logger)
 
 	// Smart Notifications
-	cwe.notifications = NewSmartNotificationEngine(cwe.aiChains, cwe.logger)
+	if cwe.notifications == nil {
+		cwe.notifications = NewSmartNotificationEngine(cwe.aiChains, cwe.logger)
+	}
+
+	// Журнал переходов задач
+	if cwe.config.EnableAuditLog {
+		cwe.history = NewFileTransitionLog(cwe.config.AuditLogPath, cwe.logger)
+	}
 
 	// MCP Integration
-	cwe.mcpIntegration = NewMCPIntegration(nil, cwe.aiChains, cwe.eventBus, cwe.config.MCPConfig, cwe.logger)
+	cwe.mcpIntegration = NewMCPIntegration(cwe, cwe.aiChains, cwe.eventBus, cwe.config.MCPConfig, cwe.logger)
 
 	// Регистрируем event handlers
 	cwe.registerEventHandlers()
@@ -148,6 +178,9 @@ func (cwe *CompleteWorkflowEngine) registerEventHandlers() {
 		},
 	})
 
+	// События провайдеров задач
+	cwe.registerProviderEventHandlers()
+
 	// Метрики и аудит
 	if cwe.config.EnableMetrics || cwe.config.EnableAuditLog {
 		cwe.eventBus.Subscribe("*", &WorkflowEventHandler{
@@ -176,6 +209,12 @@ func (cwe *CompleteWorkflowEngine) startBackgroundSystems() {
 	
 	// Запускаем мониторинг workflow
 	go cwe.runWorkflowMonitoring()
+
+	// Запускаем отправку дайджестов уведомлений
+	go cwe.notifications.RunDigestScheduler(context.Background(), time.Minute)
+
+	// Запускаем повтор неудачных доставок уведомлений
+	go cwe.notifications.RunRetryScheduler(context.Background(), time.Minute)
 	
 	// Запускаем сборщик метрик
 	if cwe.config.EnableMetrics {
@@ -243,12 +282,24 @@ func (cwe *CompleteWorkflowEngine) ExecuteWorkflow(ctx context.Context, workflow
 	cwe.mutex.RUnlock()
 
 	if !exists {
-		return fmt.Errorf("workflow %s not found", workflowID)
+		return fmt.Errorf("%w: %s", ErrWorkflowNotFound, workflowID)
 	}
 
+	execCtx, cancel := context.WithCancel(ctx)
+	cwe.mutex.Lock()
+	cwe.cancels[workflowID] = cancel
+	cwe.mutex.Unlock()
+
 	// Запускаем выполнение в отдельной горутине
 	go func() {
-		if err := cwe.executeWorkflowInternal(ctx, instance); err != nil {
+		defer func() {
+			cwe.mutex.Lock()
+			delete(cwe.cancels, workflowID)
+			cwe.mutex.Unlock()
+			cancel()
+		}()
+
+		if err := cwe.executeWorkflowInternal(execCtx, instance); err != nil {
 			cwe.logger.Error("Workflow execution failed", err, "workflow_id", workflowID)
 			
 			// Публикуем событие ошибки
@@ -270,15 +321,19 @@ func (cwe *CompleteWorkflowEngine) ExecuteWorkflow(ctx context.Context, workflow
 
 // executeWorkflowInternal внутренняя логика выполнения workflow
 func (cwe *CompleteWorkflowEngine) executeWorkflowInternal(ctx context.Context, instance *WorkflowInstance) error {
+	cwe.mutex.Lock()
 	instance.Status = "running"
 	instance.StartedAt = &[]time.Time{time.Now()}[0]
+	cwe.mutex.Unlock()
 
 	defer func() {
+		cwe.mutex.Lock()
 		if instance.Status == "running" {
 			instance.Status = "completed"
 			completedAt := time.Now()
 			instance.CompletedAt = &completedAt
 		}
+		cwe.mutex.Unlock()
 		
 		// Публикуем событие завершения
 		event := &WorkflowEvent{
@@ -296,7 +351,14 @@ func (cwe *CompleteWorkflowEngine) executeWorkflowInternal(ctx context.Context,
 
 	// Выполняем стадии
 	for stageName, stage := range instance.Definition.Stages {
+		if err := cwe.waitWhilePaused(ctx, instance); err != nil {
+			return nil
+		}
+
 		if err := cwe.executeStage(ctx, instance, stageName, stage); err != nil {
+			if cwe.instanceStatus(instance) == "stopped" {
+				return nil
+			}
 			instance.Status = "failed"
 			return fmt.Errorf("stage %s failed: %w", stageName, err)
 		}
@@ -430,12 +492,248 @@ func (cwe *CompleteWorkflowEngine) GetWorkflowStatus(workflowID string) (*Workfl
 
 	instance, exists := cwe.runningWorkflows[workflowID]
 	if !exists {
-		return nil, fmt.Errorf("workflow %s not found", workflowID)
+		return nil, fmt.Errorf("%w: %s", ErrWorkflowNotFound, workflowID)
 	}
 
 	return instance, nil
 }
 
+// ListWorkflows возвращает все экземпляры workflow, отсортированные по времени создания
+func (cwe *CompleteWorkflowEngine) ListWorkflows() []*WorkflowInstance {
+	cwe.mutex.RLock()
+	defer cwe.mutex.RUnlock()
+
+	instances := make([]*WorkflowInstance, 0, len(cwe.runningWorkflows))
+	for _, instance := range cwe.runningWorkflows {
+		instances = append(instances, instance)
+	}
+
+	sort.Slice(instances, func(i, j int) bool {
+		return instances[i].CreatedAt.Before(instances[j].CreatedAt)
+	})
+
+	return instances
+}
+
+// WorkflowProgress вычисляет прогресс workflow (0.0 - 1.0) по завершенным задачам
+func (cwe *CompleteWorkflowEngine) WorkflowProgress(instance *WorkflowInstance) float64 {
+	cwe.mutex.RLock()
+	defer cwe.mutex.RUnlock()
+
+	if instance.Status == "completed" {
+		return 1.0
+	}
+	if len(instance.Tasks) == 0 {
+		return instance.Progress
+	}
+
+	completed := 0
+	for _, task := range instance.Tasks {
+		if task.Status == "completed" || task.Status == "skipped" {
+			completed++
+		}
+	}
+
+	return float64(completed) / float64(len(instance.Tasks))
+}
+
+// FindTask находит задачу и workflow, которому она принадлежит
+func (cwe *CompleteWorkflowEngine) FindTask(taskID string) (*WorkflowInstance, *TaskInstance, error) {
+	cwe.mutex.RLock()
+	defer cwe.mutex.RUnlock()
+
+	for _, instance := range cwe.runningWorkflows {
+		if task, ok := instance.Tasks[taskID]; ok {
+			return instance, task, nil
+		}
+	}
+
+	return nil, nil, fmt.Errorf("%w: %s", ErrTaskNotFound, taskID)
+}
+
+// TransitionTask переводит задачу в другую стадию workflow.
+// Целевая стадия должна существовать в определении workflow.
+// Переход записывается в журнал, если аудит включен.
+func (cwe *CompleteWorkflowEngine) TransitionTask(ctx context.Context, taskID, newStage, actor, reason string) (string, error) {
+	instance, task, err := cwe.FindTask(taskID)
+	if err != nil {
+		return "", err
+	}
+
+	cwe.mutex.Lock()
+	if _, ok := instance.Definition.Stages[newStage]; !ok {
+		cwe.mutex.Unlock()
+		return "", fmt.Errorf("%w: %s is not defined in workflow %s", ErrInvalidStage, newStage, instance.Definition.Name)
+	}
+
+	oldStage, _ := task.Context["stage_name"].(string)
+	if oldStage == "" {
+		oldStage = instance.CurrentStage
+	}
+	task.Context["stage_name"] = newStage
+	cwe.mutex.Unlock()
+
+	if cwe.history != nil {
+		record := &TransitionRecord{
+			WorkflowID: instance.ID,
+			TaskID:     taskID,
+			FromStage:  oldStage,
+			ToStage:    newStage,
+			Actor:      actor,
+			Reason:     reason,
+			Timestamp:  time.Now(),
+		}
+		if err := cwe.history.Record(record); err != nil {
+			cwe.logger.Error("Failed to record task transition", err, "task_id", taskID)
+		}
+	}
+
+	cwe.eventBus.Publish(ctx, &WorkflowEvent{
+		Type:       "workflow.task.transitioned",
+		Timestamp:  time.Now(),
+		Source:     "complete_engine",
+		WorkflowID: instance.ID,
+		StageID:    newStage,
+		Data: map[string]interface{}{
+			"task_id":   taskID,
+			"old_stage": oldStage,
+			"new_stage": newStage,
+			"actor":     actor,
+			"reason":    reason,
+		},
+	})
+
+	cwe.logger.Info("Task transitioned",
+		"workflow_id", instance.ID,
+		"task_id", taskID,
+		"old_stage", oldStage,
+		"new_stage", newStage)
+
+	return oldStage, nil
+}
+
+// GetTransitionHistory возвращает историю переходов задач из журнала аудита
+func (cwe *CompleteWorkflowEngine) GetTransitionHistory(filter TransitionFilter) ([]*TransitionRecord, error) {
+	if cwe.history == nil {
+		return nil, fmt.Errorf("transition history is disabled: enable audit log in engine config")
+	}
+	return cwe.history.Query(filter)
+}
+
+// StartWorkflow запускает созданный workflow
+func (cwe *CompleteWorkflowEngine) StartWorkflow(ctx context.Context, workflowID string) (*WorkflowInstance, error) {
+	instance, err := cwe.GetWorkflowStatus(workflowID)
+	if err != nil {
+		return nil, err
+	}
+
+	if status := cwe.instanceStatus(instance); status != "created" {
+		return nil, fmt.Errorf("%w: cannot start workflow %s in status %s", ErrInvalidWorkflowState, workflowID, status)
+	}
+
+	// Выполнение не должно зависеть от контекста вызывающего запроса
+	if err := cwe.ExecuteWorkflow(context.Background(), workflowID); err != nil {
+		return nil, err
+	}
+
+	return instance, nil
+}
+
+// StopWorkflow останавливает выполнение workflow
+func (cwe *CompleteWorkflowEngine) StopWorkflow(ctx context.Context, workflowID string) (*WorkflowInstance, error) {
+	return cwe.changeWorkflowStatus(ctx, workflowID, "stopped", "created", "running", "paused")
+}
+
+// PauseWorkflow приостанавливает выполнение workflow
+func (cwe *CompleteWorkflowEngine) PauseWorkflow(ctx context.Context, workflowID string) (*WorkflowInstance, error) {
+	return cwe.changeWorkflowStatus(ctx, workflowID, "paused", "running")
+}
+
+// ResumeWorkflow возобновляет приостановленный workflow
+func (cwe *CompleteWorkflowEngine) ResumeWorkflow(ctx context.Context, workflowID string) (*WorkflowInstance, error) {
+	return cwe.changeWorkflowStatus(ctx, workflowID, "running", "paused")
+}
+
+// changeWorkflowStatus меняет статус workflow, если текущий статус входит в allowed
+func (cwe *CompleteWorkflowEngine) changeWorkflowStatus(ctx context.Context, workflowID, newStatus string, allowed ...string) (*WorkflowInstance, error) {
+	cwe.mutex.Lock()
+	instance, exists := cwe.runningWorkflows[workflowID]
+	if !exists {
+		cwe.mutex.Unlock()
+		return nil, fmt.Errorf("%w: %s", ErrWorkflowNotFound, workflowID)
+	}
+
+	oldStatus := instance.Status
+	permitted := false
+	for _, status := range allowed {
+		if oldStatus == status {
+			permitted = true
+			break
+		}
+	}
+	if !permitted {
+		cwe.mutex.Unlock()
+		return nil, fmt.Errorf("%w: cannot move workflow %s from %s to %s", ErrInvalidWorkflowState, workflowID, oldStatus, newStatus)
+	}
+
+	instance.Status = newStatus
+	if newStatus == "stopped" {
+		completedAt := time.Now()
+		instance.CompletedAt = &completedAt
+		if cancel, ok := cwe.cancels[workflowID]; ok {
+			cancel()
+		}
+	}
+	cwe.mutex.Unlock()
+
+	cwe.eventBus.Publish(ctx, &WorkflowEvent{
+		Type:       "workflow.status.changed",
+		Timestamp:  time.Now(),
+		Source:     "complete_engine",
+		WorkflowID: workflowID,
+		Data: map[string]interface{}{
+			"old_status": oldStatus,
+			"new_status": newStatus,
+		},
+	})
+
+	cwe.logger.Info("Workflow status changed",
+		"workflow_id", workflowID,
+		"old_status", oldStatus,
+		"new_status", newStatus)
+
+	return instance, nil
+}
+
+// instanceStatus возвращает статус workflow под блокировкой
+func (cwe *CompleteWorkflowEngine) instanceStatus(instance *WorkflowInstance) string {
+	cwe.mutex.RLock()
+	defer cwe.mutex.RUnlock()
+	return instance.Status
+}
+
+// waitWhilePaused блокирует выполнение пока workflow на паузе
+func (cwe *CompleteWorkflowEngine) waitWhilePaused(ctx context.Context, instance *WorkflowInstance) error {
+	ticker := time.NewTicker(500 * time.Millisecond)
+	defer ticker.Stop()
+
+	for {
+		switch cwe.instanceStatus(instance) {
+		case "paused":
+		case "stopped":
+			return context.Canceled
+		default:
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
 // GetMetrics возвращает метрики движка
 func (cwe *CompleteWorkflowEngine) GetMetrics() *WorkflowMetrics {
 	cwe.mutex.RLock()
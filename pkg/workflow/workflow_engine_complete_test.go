@@ -2,8 +2,11 @@ package workflow
 
 import (
 	"context"
+	"path/filepath"
 	"testing"
 	"time"
+
+	"github.com/grik-ai/ricochet-task/pkg/providers"
 )
 
 // TestCompleteWorkflowEngine тестирует полный движок workflow
@@ -477,4 +480,75 @@ func TestWorkflowEngineResilience(t *testing.T) {
 			t.Logf("Context cancellation handled: %v", err)
 		}
 	})
-}
\ No newline at end of file
+}
+
+// TestProviderEventBridge тестирует доставку событий провайдеров в движок
+func TestProviderEventBridge(t *testing.T) {
+	logger := &MockLogger{}
+
+	config := GetDefaultCompleteConfig()
+	config.AuditLogPath = filepath.Join(t.TempDir(), "history.jsonl")
+	engine, err := NewCompleteWorkflowEngine(nil, config, logger)
+	if err != nil {
+		t.Fatalf("Failed to create complete workflow engine: %v", err)
+	}
+	engine.notifications.SetDigestAggregator(nil)
+
+	bus := providers.NewEventBus(16, nil)
+	if err := engine.ConnectProviderEvents(bus); err != nil {
+		t.Fatalf("Failed to connect provider events: %v", err)
+	}
+
+	bus.Publish(&providers.UniversalEvent{
+		Type:   providers.EventTypeTaskStatusChanged,
+		Source: "youtrack",
+		TaskID: "PROJ-7",
+		Data:   map[string]interface{}{"status": "In Review", "actor": "alice"},
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := bus.Close(ctx); err != nil {
+		t.Fatalf("Bus did not drain: %v", err)
+	}
+
+	records, err := engine.GetTransitionHistory(TransitionFilter{TaskID: "PROJ-7"})
+	if err != nil {
+		t.Fatalf("Failed to query history: %v", err)
+	}
+	if len(records) != 1 {
+		t.Fatalf("Expected 1 audit record, got %d", len(records))
+	}
+	if records[0].ToStage != "In Review" || records[0].Actor != "alice" {
+		t.Errorf("Unexpected audit record: %+v", records[0])
+	}
+
+	if ProviderEventType(providers.EventTypeTaskStatusChanged) != EventProviderTaskStatusChanged {
+		t.Errorf("Unexpected event type mapping")
+	}
+}
+
+// TestCompleteWorkflowEngineSharedNotifications проверяет, что движок
+// уведомляет через переданный ему движок уведомлений
+func TestCompleteWorkflowEngineSharedNotifications(t *testing.T) {
+	logger := &MockLogger{}
+	shared := NewSmartNotificationEngine(nil, logger)
+
+	config := GetDefaultCompleteConfig()
+	config.AuditLogPath = filepath.Join(t.TempDir(), "history.jsonl")
+	engine, err := NewCompleteWorkflowEngineWithNotifications(nil, config, shared, logger)
+	if err != nil {
+		t.Fatalf("Failed to create complete workflow engine: %v", err)
+	}
+	if engine.notifications != shared {
+		t.Errorf("Expected the engine to use the shared notification engine")
+	}
+
+	own, err := NewCompleteWorkflowEngine(nil, config, logger)
+	if err != nil {
+		t.Fatalf("Failed to create complete workflow engine: %v", err)
+	}
+	if own.notifications == nil || own.notifications == shared {
+		t.Errorf("Expected the engine to create its own notification engine")
+	}
+}